@@ -290,6 +290,14 @@ func (g *Generator) addAccount(acc *account) string {
 	return id
 }
 
+// RemoveAccount removes a single account from memory.
+func (g *Generator) RemoveAccount(accountID string) {
+	g.Lock()
+	defer g.Unlock()
+
+	delete(g.accounts, accountID)
+}
+
 // Reset resets the accounts map removing all the accounts from memory.
 func (g *Generator) Reset() {
 	g.Lock()
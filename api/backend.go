@@ -30,9 +30,12 @@ type StatusBackend interface {
 	SaveAccountAndStartNodeWithKey(acc multiaccounts.Account, password string, settings accounts.Settings, conf *params.NodeConfig, subaccs []accounts.Account, keyHex string) error
 	Recover(rpcParams personal.RecoverParams) (types.Address, error)
 	Logout() error
+	SwitchAccount(acc multiaccounts.Account, password string) error
+	DeleteMultiaccount(keyUID string, password string) error
 
 	CallPrivateRPC(inputJSON string) (string, error)
 	CallRPC(inputJSON string) (string, error)
+	CallDappRPC(origin string, inputJSON string) (string, error)
 	GetNodesFromContract(rpcEndpoint string, contractAddress string) ([]string, error)
 	HashTransaction(sendArgs transactions.SendTxArgs) (transactions.SendTxArgs, types.Hash, error)
 	HashTypedData(typed typeddata.TypedData) (types.Hash, error)
@@ -614,3 +614,74 @@ func TestLoginWithKey(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, crypto.PubkeyToAddress(chatKey.PublicKey), extkey.Address)
 }
+
+func TestSwitchAndDeleteMultiaccount(t *testing.T) {
+	utils.Init()
+
+	password := "test-pass"
+	b := NewGethStatusBackend()
+	tmpdir, err := ioutil.TempDir("", "switch-account-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	conf, err := params.NewNodeConfig(tmpdir, 1777)
+	require.NoError(t, err)
+	require.NoError(t, b.AccountManager().InitKeystore(conf.KeyStoreDir))
+	b.UpdateRootDataDir(conf.DataDir)
+	require.NoError(t, b.OpenAccounts())
+
+	newAccount := func() (multiaccounts.Account, types.Address, []accounts.Account) {
+		key, err := gethcrypto.GenerateKey()
+		require.NoError(t, err)
+		address, err := b.AccountManager().ImportAccount(key, password)
+		require.NoError(t, err)
+		keyUID := sha256.Sum256(gethcrypto.FromECDSAPub(&key.PublicKey))
+		return multiaccounts.Account{KeyUID: types.EncodeHex(keyUID[:])}, address, []accounts.Account{
+			{Address: address, Wallet: true, Chat: true},
+			{Address: types.Address{1}, Type: accountTypeWatch},
+		}
+	}
+	first, firstAddress, firstAccounts := newAccount()
+	second, secondAddress, secondAccounts := newAccount()
+	withAddress := func(address types.Address) accounts.Settings {
+		s := settings
+		s.Address = address
+		return s
+	}
+
+	require.NoError(t, b.StartNodeWithAccountAndConfig(first, password, withAddress(firstAddress), conf, firstAccounts))
+	require.NoError(t, b.Logout())
+	require.NoError(t, b.StopNode())
+	require.NoError(t, b.StartNodeWithAccountAndConfig(second, password, withAddress(secondAddress), conf, secondAccounts))
+	defer func() {
+		assert.NoError(t, b.Logout())
+		assert.NoError(t, b.StopNode())
+	}()
+
+	require.Equal(t, ErrAnotherAccountLoggedIn, b.ensureAppDBOpened(first, password))
+	require.Equal(t, ErrAccountLoggedIn, b.DeleteMultiaccount(second.KeyUID, password))
+
+	require.NoError(t, b.SwitchAccount(first, password))
+	extkey, err := b.accountManager.SelectedChatAccount()
+	require.NoError(t, err)
+	require.Equal(t, firstAddress, extkey.Address)
+
+	// nothing is deleted if the password is wrong
+	require.Error(t, b.DeleteMultiaccount(second.KeyUID, "wrong-pass"))
+	accs, err := b.GetAccounts()
+	require.NoError(t, err)
+	require.Len(t, accs, 2)
+	_, _, err = b.AccountManager().AddressToDecryptedAccount(secondAddress.Hex(), password)
+	require.NoError(t, err)
+
+	require.NoError(t, b.DeleteMultiaccount(second.KeyUID, password))
+	accs, err = b.GetAccounts()
+	require.NoError(t, err)
+	require.Len(t, accs, 1)
+	require.Equal(t, first.KeyUID, accs[0].KeyUID)
+	_, err = os.Stat(b.appDBPath(second.KeyUID))
+	require.True(t, os.IsNotExist(err))
+	_, _, err = b.AccountManager().AddressToDecryptedAccount(secondAddress.Hex(), password)
+	require.Error(t, err)
+	_, _, err = b.AccountManager().AddressToDecryptedAccount(firstAddress.Hex(), password)
+	require.NoError(t, err)
+}
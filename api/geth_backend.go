@@ -4,14 +4,17 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"database/sql"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	gethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
@@ -34,6 +37,7 @@ import (
 	"github.com/status-im/status-go/rpc"
 	accountssvc "github.com/status-im/status-go/services/accounts"
 	"github.com/status-im/status-go/services/browsers"
+	"github.com/status-im/status-go/services/localsettings"
 	"github.com/status-im/status-go/services/mailservers"
 	"github.com/status-im/status-go/services/permissions"
 	"github.com/status-im/status-go/services/personal"
@@ -47,6 +51,8 @@ import (
 
 const (
 	contractQueryTimeout = 1000 * time.Millisecond
+	// accountTypeWatch is a type of watch-only wallet accounts.
+	accountTypeWatch = "watch"
 )
 
 var (
@@ -59,6 +65,11 @@ var (
 	// ErrRPCClientUnavailable is returned if an RPC client can't be retrieved.
 	// This is a normal situation when a node is stopped.
 	ErrRPCClientUnavailable = errors.New("JSON-RPC client is unavailable")
+	// ErrAccountLoggedIn is returned if a logged in account is deleted.
+	ErrAccountLoggedIn = errors.New("account is logged in")
+	// ErrAnotherAccountLoggedIn is returned if an account is logged in while another one wasn't logged out.
+	// A single account is logged in at a time, SwitchAccount logs out and restarts the node to change it.
+	ErrAnotherAccountLoggedIn = errors.New("another account is logged in, log out or switch the account")
 )
 
 var _ StatusBackend = (*GethStatusBackend)(nil)
@@ -69,6 +80,8 @@ type GethStatusBackend struct {
 	// rootDataDir is the same for all networks.
 	rootDataDir             string
 	appDB                   *sql.DB
+	account                 *multiaccounts.Account // account whose database is opened as appDB
+	localSettings           *localsettings.Store   // settings in appDB shared by services
 	statusNode              *node.StatusNode
 	personalAPI             *personal.PublicAPI
 	rpcFilters              *rpcfilters.Service
@@ -179,16 +192,130 @@ func (b *GethStatusBackend) ensureAppDBOpened(account multiaccounts.Account, pas
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if b.appDB != nil {
+		if b.account.KeyUID != account.KeyUID {
+			return ErrAnotherAccountLoggedIn
+		}
 		return nil
 	}
 	if len(b.rootDataDir) == 0 {
 		return errors.New("root datadir wasn't provided")
 	}
-	path := filepath.Join(b.rootDataDir, fmt.Sprintf("app-%x.sql", account.KeyUID))
-	b.appDB, err = appdatabase.InitializeDB(path, password)
+	b.appDB, err = appdatabase.InitializeDB(b.appDBPath(account.KeyUID), password)
 	if err != nil {
 		return err
 	}
+	b.account = &account
+	b.localSettings = localsettings.NewStore(b.appDB)
+	return nil
+}
+
+func (b *GethStatusBackend) appDBPath(keyUID string) string {
+	return filepath.Join(b.rootDataDir, fmt.Sprintf("app-%x.sql", keyUID))
+}
+
+// SwitchAccount logs out the current account, stops the node and starts it with another account.
+// It is a restart, not a switch between profiles loaded in the same node: only one account is
+// open at a time and services are created anew with the database of the account, so that no
+// filters, mailserver requests or wallet data of the previous account are kept.
+func (b *GethStatusBackend) SwitchAccount(acc multiaccounts.Account, password string) error {
+	if b.IsNodeRunning() {
+		if err := b.Logout(); err != nil {
+			return err
+		}
+		if err := b.StopNode(); err != nil {
+			return err
+		}
+	}
+	return b.StartNodeWithAccount(acc, password)
+}
+
+// DeleteMultiaccount deletes the account together with its keys and its database.
+// The account must not be logged in. The password is required to decrypt the database
+// with addresses of the keys. The account is removed from the list of accounts before
+// its keys and database, so that a listed account always has its keys.
+func (b *GethStatusBackend) DeleteMultiaccount(keyUID string, password string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.multiaccountsDB == nil {
+		return errors.New("accounts db wasn't initialized")
+	}
+	if b.account != nil && b.account.KeyUID == keyUID {
+		return ErrAccountLoggedIn
+	}
+	if len(b.rootDataDir) == 0 {
+		return errors.New("root datadir wasn't provided")
+	}
+	path := b.appDBPath(keyUID)
+	_, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exists := err == nil
+
+	var addresses []types.Address
+	if exists {
+		// the password is verified before anything is deleted
+		addresses, err = accountKeyAddresses(path, password)
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.multiaccountsDB.DeleteAccount(keyUID); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	if err := b.deleteKeys(addresses, password); err != nil {
+		return err
+	}
+	// sqlite keeps the write-ahead log and the shared memory index next to the database
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountKeyAddresses returns addresses of the master account and of derived accounts
+// stored in the database of the account. Watch-only accounts and keycard accounts don't have keys.
+func accountKeyAddresses(path, password string) ([]types.Address, error) {
+	db, err := appdatabase.InitializeDB(path, password)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	accdb := accounts.NewDB(db)
+	settings, err := accdb.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	accs, err := accdb.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	addresses := []types.Address{settings.Address}
+	for _, acc := range accs {
+		if acc.Type != accountTypeWatch {
+			addresses = append(addresses, acc.Address)
+		}
+	}
+	return addresses, nil
+}
+
+// deleteKeys deletes keys of the addresses from the keystore.
+func (b *GethStatusBackend) deleteKeys(addresses []types.Address, password string) error {
+	keyStore := b.accountManager.GetKeystore()
+	if keyStore == nil {
+		return account.ErrAccountKeyStoreMissing
+	}
+	for _, address := range addresses {
+		err := keyStore.Delete(types.Account{Address: address}, password)
+		if err != nil && err != gethkeystore.ErrNoMatch {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -421,9 +548,15 @@ func (b *GethStatusBackend) mailserversService() gethnode.ServiceConstructor {
 	}
 }
 
-func (b *GethStatusBackend) walletService(network uint64, accountsFeed *event.Feed) gethnode.ServiceConstructor {
+func (b *GethStatusBackend) localSettingsService() gethnode.ServiceConstructor {
 	return func(*gethnode.ServiceContext) (gethnode.Service, error) {
-		return wallet.NewService(wallet.NewDB(b.appDB, network), accountsFeed), nil
+		return localsettings.NewService(b.localSettings), nil
+	}
+}
+
+func (b *GethStatusBackend) walletService(network uint64, accountsFeed *event.Feed, config params.WalletConfig) gethnode.ServiceConstructor {
+	return func(*gethnode.ServiceContext) (gethnode.Service, error) {
+		return wallet.NewService(wallet.NewDB(b.appDB, network), accountsFeed, config, b.transactor, accounts.NewDB(b.appDB), b.accountManager.AccountsGenerator(), walletKeys{b}), nil
 	}
 }
 
@@ -443,10 +576,11 @@ func (b *GethStatusBackend) startNode(config *params.NodeConfig) (err error) {
 	services = appendIf(config.UpstreamConfig.Enabled, services, b.rpcFiltersService())
 	services = append(services, b.subscriptionService())
 	services = appendIf(b.appDB != nil && b.multiaccountsDB != nil, services, b.accountsService(accountsFeed))
+	services = appendIf(b.localSettings != nil, services, b.localSettingsService())
 	services = appendIf(config.BrowsersConfig.Enabled, services, b.browsersService())
 	services = appendIf(config.PermissionsConfig.Enabled, services, b.permissionsService())
 	services = appendIf(config.MailserversConfig.Enabled, services, b.mailserversService())
-	services = appendIf(config.WalletConfig.Enabled, services, b.walletService(config.NetworkID, accountsFeed))
+	services = appendIf(config.WalletConfig.Enabled, services, b.walletService(config.NetworkID, accountsFeed, config.WalletConfig))
 
 	manager := b.accountManager.GetManager()
 	if manager == nil {
@@ -558,6 +692,20 @@ func (b *GethStatusBackend) CallRPC(inputJSON string) (string, error) {
 	return client.CallRaw(inputJSON), nil
 }
 
+// CallDappRPC executes public RPC requests of a dapp with the given origin on node's in-proc RPC server.
+// Requests are executed only if the dapp was granted permissions required by requested methods.
+func (b *GethStatusBackend) CallDappRPC(origin string, inputJSON string) (string, error) {
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return "", ErrRPCClientUnavailable
+	}
+	service, err := b.statusNode.PermissionsService()
+	if err != nil {
+		return "", err
+	}
+	return client.CallRawFromOrigin(origin, service, inputJSON), nil
+}
+
 // GetNodesFromContract returns a list of nodes from the contract
 func (b *GethStatusBackend) GetNodesFromContract(rpcEndpoint string, contractAddress string) ([]string, error) {
 	var response []string
@@ -701,6 +849,19 @@ func (b *GethStatusBackend) getVerifiedWalletAccount(address, password string) (
 	}, nil
 }
 
+// walletKeys unlocks wallet accounts for the wallet service.
+type walletKeys struct {
+	backend *GethStatusBackend
+}
+
+func (k walletKeys) WalletAccountKey(address common.Address, password string) (*ecdsa.PrivateKey, error) {
+	account, err := k.backend.getVerifiedWalletAccount(address.Hex(), password)
+	if err != nil {
+		return nil, err
+	}
+	return account.AccountKey.PrivateKey, nil
+}
+
 // registerHandlers attaches Status callback handlers to running node
 func (b *GethStatusBackend) registerHandlers() error {
 	var clients []*rpc.Client
@@ -807,6 +968,14 @@ func (b *GethStatusBackend) cleanupServices() error {
 			return fmt.Errorf("%s: %v", ErrWhisperClearIdentitiesFailure, err)
 		}
 		b.selectedAccountShhKeyID = ""
+		// filters of the account must be removed before its database is closed
+		st, err := b.statusNode.ShhExtService()
+		if err != nil {
+			return err
+		}
+		if err := st.StopMessenger(); err != nil {
+			return err
+		}
 	default:
 		return err
 	}
@@ -833,6 +1002,8 @@ func (b *GethStatusBackend) closeAppDB() error {
 			return err
 		}
 		b.appDB = nil
+		b.account = nil
+		b.localSettings = nil
 		return nil
 	}
 	return nil
@@ -930,6 +1101,7 @@ func (b *GethStatusBackend) startWallet() error {
 
 	return wallet.StartReactor(
 		b.statusNode.RPCClient().Ethclient(),
+		b.statusNode.RPCClient(),
 		allAddresses,
 		new(big.Int).SetUint64(b.statusNode.Config().NetworkID))
 }
@@ -416,9 +416,9 @@ func (b *nimbusStatusBackend) accountsService(accountsFeed *event.Feed) nimbussv
 // 	}
 // }
 
-// func (b *nimbusStatusBackend) walletService(network uint64, accountsFeed *event.Feed) nimbussvc.ServiceConstructor {
+// func (b *nimbusStatusBackend) walletService(network uint64, accountsFeed *event.Feed, config params.WalletConfig) nimbussvc.ServiceConstructor {
 // 	return func(*nimbussvc.ServiceContext) (nimbussvc.Service, error) {
-// 		return wallet.NewService(wallet.NewDB(b.appDB, network), accountsFeed), nil
+// 		return wallet.NewService(wallet.NewDB(b.appDB, network), accountsFeed, config, b.transactor, accounts.NewDB(b.appDB), b.accountManager.AccountsGenerator(), walletKeys{b}), nil
 // 	}
 // }
 
@@ -442,7 +442,7 @@ func (b *nimbusStatusBackend) startNode(config *params.NodeConfig) (err error) {
 	// services = appendIf(config.BrowsersConfig.Enabled, services, b.browsersService())
 	// services = appendIf(config.PermissionsConfig.Enabled, services, b.permissionsService())
 	// services = appendIf(config.MailserversConfig.Enabled, services, b.mailserversService())
-	// services = appendIf(config.WalletConfig.Enabled, services, b.walletService(config.NetworkID, accountsFeed))
+	// services = appendIf(config.WalletConfig.Enabled, services, b.walletService(config.NetworkID, accountsFeed, config.WalletConfig))
 
 	// manager := b.accountManager.GetManager()
 	// if manager == nil {
@@ -555,6 +555,11 @@ func (b *nimbusStatusBackend) CallRPC(inputJSON string) (string, error) {
 	return client.CallRaw(inputJSON), nil
 }
 
+// CallDappRPC executes public RPC requests of a dapp with the given origin on node's in-proc RPC server.
+func (b *nimbusStatusBackend) CallDappRPC(origin string, inputJSON string) (string, error) {
+	panic("CallDappRPC")
+}
+
 // GetNodesFromContract returns a list of nodes from the contract
 func (b *nimbusStatusBackend) GetNodesFromContract(rpcEndpoint string, contractAddress string) ([]string, error) {
 	panic("GetNodesFromContract")
@@ -923,6 +928,7 @@ func (b *nimbusStatusBackend) injectAccountIntoServices() error {
 // 	copy(allAddresses[1:], watchAddresses)
 // 	return wallet.StartReactor(
 // 		b.statusNode.RPCClient().Ethclient(),
+// 		b.statusNode.RPCClient(),
 // 		allAddresses,
 // 		new(big.Int).SetUint64(b.statusNode.Config().NetworkID),
 //	)
@@ -8,6 +8,44 @@
 // 0003_settings.up.sql (1.311kB)
 // 0004_pending_stickers.down.sql (0)
 // 0004_pending_stickers.up.sql (61B)
+// 0005_transfers_tx_hash.down.sql (34B)
+// 0005_transfers_tx_hash.up.sql (177B)
+// 0006_token_metadata.down.sql (27B)
+// 0006_token_metadata.up.sql (247B)
+// 0007_historical_prices.down.sql (30B)
+// 0007_historical_prices.up.sql (213B)
+// 0008_contract_abis.down.sql (26B)
+// 0008_contract_abis.up.sql (184B)
+// 0009_hardware_accounts.down.sql (30B)
+// 0009_hardware_accounts.up.sql (155B)
+// 0010_spending_limits.down.sql (28B)
+// 0010_spending_limits.up.sql (233B)
+// 0011_address_book.down.sql (25B)
+// 0011_address_book.up.sql (261B)
+// 0012_owned_tokens.down.sql (25B)
+// 0012_owned_tokens.up.sql (262B)
+// 0013_crypto_on_ramps.down.sql (28B)
+// 0013_crypto_on_ramps.up.sql (199B)
+// 0014_transfers_l2.down.sql (46B)
+// 0014_transfers_l2.up.sql (191B)
+// 0015_method_signatures.down.sql (30B)
+// 0015_method_signatures.up.sql (156B)
+// 0016_allowances.down.sql (54B)
+// 0016_allowances.up.sql (477B)
+// 0017_transfers_chart.down.sql (32B)
+// 0017_transfers_chart.up.sql (227B)
+// 0018_share_tokens.down.sql (25B)
+// 0018_share_tokens.up.sql (187B)
+// 0019_transfers_withdrawal.down.sql (0B)
+// 0019_transfers_withdrawal.up.sql (50B)
+// 0020_transfer_notes.down.sql (53B)
+// 0020_transfer_notes.up.sql (453B)
+// 0021_nft_metadata.down.sql (25B)
+// 0021_nft_metadata.up.sql (388B)
+// 0022_local_settings.down.sql (27B)
+// 0022_local_settings.up.sql (206B)
+// 0023_keycard_pairings.down.sql (58B)
+// 0023_keycard_pairings.up.sql (411B)
 // doc.go (74B)
 
 package migrations
@@ -77,7 +115,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __0001_appDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcd\xcb\xaa\xc2\x40\x0c\xc6\xf1\x7d\x9f\xa2\xef\xd1\xd5\x39\xb4\x0b\x41\x54\xc4\x85\xbb\x21\x4e\x63\x1b\x6c\x27\x63\x92\x7a\x79\x7b\x41\xf0\x32\xea\x6c\x7f\xf9\xf8\xa7\x5e\x2f\x57\xe5\xe6\xef\x7f\xde\x94\x8a\x66\x14\x3a\xad\x8a\x37\x04\xef\x79\x0a\x96\xe2\x4e\xf8\xac\x28\xbf\xd1\xf5\xa4\xc6\x72\x4d\x8e\x2d\xc4\x98\xce\x23\xca\x48\xaa\xc4\x21\x75\x13\x08\xba\xff\x8a\x0f\xec\x0f\x29\x8d\x40\x83\xa2\x9c\x3e\xa7\x2f\x77\x82\xc7\x09\xd5\x5c\x07\xcf\xe7\xb3\x45\xdd\x6c\x73\x1b\xe7\x7b\x30\x47\xad\xa3\xf6\x92\x6b\x1a\x47\xf2\xd9\x8f\xf7\xc0\x23\x29\x10\x3a\xd4\xaa\xb8\x05\x00\x00\xff\xff\xf6\xca\x86\xce\x64\x01\x00\x00")
+var __0001_appDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcd\xcd\xaa\xc2\x40\x0c\x86\xe1\x7d\xaf\xa2\xf7\xd1\xd5\x39\xb4\x0b\x41\x54\xc4\x85\xbb\x21\x4e\xe3\x34\xd8\x4e\xc6\x24\xf5\xe7\xee\x05\x41\x71\xd0\xd9\x3e\x09\xef\xd7\x6e\xd7\x9b\x7a\xf7\xf7\xbf\xec\x6a\x45\x33\x8a\x41\x9b\xea\x03\xc1\x7b\x9e\xa3\xe5\x78\x10\xbe\x2a\xca\x6f\x74\x03\xa9\xb1\xdc\xb3\x63\x0f\x29\xe5\xef\x09\x65\x22\x55\xe2\x98\xbb\x09\x44\x3d\x7e\xc5\x47\xf6\xa7\x9c\x26\xa0\x51\x51\x2e\x28\x25\x77\x82\xe7\x19\xd5\x5c\x80\xf7\xf8\x62\xd5\x76\xfb\xd2\x8f\xf3\x03\x98\xa3\xde\x51\x7f\x2b\x35\x8d\x13\xf9\xe2\xe2\x33\xf0\x4a\x0a\xc4\x80\xda\x54\x8f\x01\x00\xf6\xca\x86\xce\x64\x01\x00\x00")
 
 func _0001_appDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -92,12 +130,12 @@ func _0001_appDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0001_app.down.sql", size: 356, mode: os.FileMode(0644), modTime: time.Unix(1580303056, 0)}
+	info := bindataFileInfo{name: "0001_app.down.sql", size: 356, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb5, 0x25, 0xa0, 0xf8, 0x7d, 0x2d, 0xd, 0xcf, 0x18, 0xe4, 0x73, 0xc3, 0x95, 0xf5, 0x24, 0x20, 0xa9, 0xe6, 0x9e, 0x1d, 0x93, 0xe5, 0xc5, 0xad, 0x93, 0x8f, 0x5e, 0x40, 0xb5, 0x30, 0xaa, 0x25}}
 	return a, nil
 }
 
-var __0001_appUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x56\x4f\x73\xa2\x30\x14\xbf\xf3\x29\x72\xd4\x19\x2e\x7b\xee\x09\x35\x5a\x66\x29\xec\x22\x6e\xdb\x53\x26\x42\xc4\x8c\x40\xd2\x24\xd4\xfa\xed\x77\x02\x04\x50\x41\xeb\xce\xde\x4c\xde\xcb\xe3\xf7\xe7\xe5\xc5\x79\x08\x9d\x08\x82\xc8\x99\x79\x10\xb8\x4b\xe0\x07\x11\x80\x6f\xee\x3a\x5a\x03\x49\x94\xa2\x45\x2a\xc1\xc4\x52\x27\x4e\xc0\x1f\x27\x9c\x3f\x3b\x21\xf8\x15\xba\x2f\x4e\xf8\x0e\x7e\xc2\x77\xdb\xfa\xc4\x59\x49\xc0\xcc\x0b\x66\xd6\x14\xbc\xba\xd1\x73\xb0\x89\x40\x18\xbc\xba\x8b\x27\xcb\xba\x51\x1c\xc7\x31\x2b\x0b\xa5\x8b\xe3\x24\x11\x44\xca\xe1\xfa\x47\x9c\x65\x44\x81\x59\x10\x78\xd0\xf1\x6d\x2b\xde\xe3\xde\xaa\xc2\x15\xc1\xb7\xc8\xb6\xa4\x62\x02\xa7\x66\xc5\xcb\xed\x81\x9c\x2a\x5c\xb6\xc5\xb1\xda\x37\xfb\x05\xce\x4d\x4a\xcc\x32\x26\xcc\x6f\x41\xb0\x22\x09\xc2\x0a\x2c\x9c\x08\x46\xee\x0b\xac\xc0\xfa\x1b\xcf\xb3\xad\x92\x27\xa3\xd1\x71\xd6\x1b\xdf\xfd\xbd\x81\xc0\xf5\x17\xf0\x0d\x94\x05\xfd\x28\x09\xaa\xd9\x20\xc3\x38\xf0\x7b\x3a\xd4\xb1\x29\x78\x7d\x86\x21\x6c\x97\x4f\xb7\xca\x69\x31\x86\x8b\xe9\x48\x5b\xaa\x5a\xb4\x85\xea\x0a\x1d\x63\xd4\x9c\xba\x28\xd0\xc6\xbb\x32\xdd\xd6\x6d\x6f\xb7\x82\x1d\x25\x11\xda\x5b\x9a\x54\x0a\x9f\x7b\xda\x9a\xd0\xd3\x58\xd1\x9c\x48\x85\x73\x0e\x36\xeb\x95\xbb\xf2\xe1\x02\xcc\xdc\x95\xeb\x47\xb6\x95\x60\xce\x8d\xe5\x60\x01\x97\xce\xc6\x8b\xc0\x0e\x67\x92\xd8\xd6\x9e\x6a\xdf\x4f\x6e\x91\x90\x2f\xb0\xf1\xd7\xf5\x49\xd7\x8f\x1e\xeb\x46\x83\x18\x35\xf5\xc0\xc4\x6a\xb6\x90\x61\xd0\x41\x35\x39\x75\xeb\x2c\x83\x10\xba\x2b\x5f\x33\x9b\x74\x67\xa6\x20\x84\x4b\x18\x42\x7f\x0e\xbb\xea\x13\xbd\x1f\x68\x0e\x1e\x8c\x20\x98\x3b\xeb\xb9\xb3\x80\xd6\x1d\x35\x35\x7d\x2d\x65\xa7\x5a\x4f\xcc\xc7\x68\x72\x22\x72\x2a\x25\x65\x85\x2e\xa8\x0b\xa3\x21\x2f\xba\xb4\xcb\x48\x9f\x6c\x7b\xfc\x8c\x6b\x85\x76\x52\x6f\x0f\x53\xbd\x05\x50\x09\x5c\xc8\x5d\xdd\x3a\x05\x51\x47\x26\x0e\xda\x80\xd6\xd8\xba\x25\xfa\x5e\x60\xb9\x6f\x07\x47\xb7\x7d\x39\x52\xba\xc8\x36\x3b\xa0\x91\x43\xea\xab\x99\x17\x92\x14\x09\x11\x26\xc3\xb6\x04\x89\x09\xe5\xaa\x89\x66\x2c\x6d\x7e\x9d\x4d\xc5\xf3\x4f\x14\x65\xbe\x25\xe2\x1a\x6f\xaf\xcd\x47\x39\x65\x0c\x27\x24\xa9\x3a\xbe\x6d\xf7\x1f\xe7\xda\x77\xda\xd8\x0d\x55\xdb\x10\x3b\xef\xbc\x8c\xc5\x07\x79\x3b\xfd\xca\x25\xdb\x9a\x07\xfe\x3a\x0a\x1d\x0d\xab\x99\x34\xc6\x18\xc4\x89\x30\x13\xa7\xfa\xdd\x94\x36\xe3\x69\xa2\x6b\xb6\x1f\xe9\xbe\x3b\xbd\xd7\xe5\x35\xd2\xef\xda\x7e\xdb\xdf\x31\xf1\x5b\xef\xbf\x25\xf9\xd2\xf1\xd6\x83\x5a\xe4\x98\x73\x5a\xa4\x68\xc7\x84\x99\x9d\x48\x31\x54\x31\x18\xd4\xe4\x52\xf3\xc7\x75\x41\x02\x17\x29\xf9\x4f\xf2\xec\x04\xcb\x87\xc5\x51\xec\x72\xff\x1e\xbc\x1c\xd3\x4c\x12\xf1\x59\x5f\x59\x00\x00\xa0\xc9\xf0\x43\xae\x63\xd5\xb0\xb9\x06\xa5\x43\xe3\x90\x75\x94\x63\x29\x8f\x4c\x24\xdd\x9d\xd4\xbb\xbb\x8c\x10\x75\x75\xe2\xb1\x91\xd8\x11\x40\x82\x7c\x94\x44\x2a\x94\x62\x6e\xc8\xa4\x98\xd7\x72\xf5\x9f\x16\xb8\x82\x97\xf8\x74\x9e\x62\xf7\xb2\x06\x1f\x43\x1d\xa8\xde\xf1\xcb\x87\x66\x9c\x47\xfd\x82\x8f\x20\x47\x4d\x31\x44\x93\x2f\x7d\xb7\x47\x09\x36\x79\xdf\x36\x18\x29\xc6\x69\x6c\x94\xa9\x16\xe3\x4e\x37\xc5\xe5\xb9\x61\x19\x96\xca\xa0\x68\x35\xea\x8d\x38\x9d\x93\x50\x19\xb3\x4f\x22\x4e\x57\x4f\x7e\x73\x21\xab\x46\x22\x29\x53\x54\xff\x1b\x19\xce\xfa\xe7\x1e\xa8\x70\x1b\x9d\xda\x4b\xd7\xf7\x68\x94\x72\xc6\x8e\xa4\xa3\x57\xb7\x4d\xc3\xb1\x4e\xd8\xd3\x74\xdf\xcf\x50\xcc\xc4\xaf\xe1\xfe\x0d\x00\x00\xff\xff\xe8\x42\x77\x9b\x97\x0b\x00\x00")
+var __0001_appUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x56\x4d\x93\xea\x28\x14\xdd\xf3\x2b\x58\xda\x55\xd9\xcc\xfa\xad\xa2\xa2\x9d\x1a\x5f\x32\x13\x71\xba\xdf\x8a\xa2\x13\x8c\x54\xc7\xc0\x03\x6c\xbb\xff\xfd\x14\x09\x90\xf8\x11\x6d\xa7\x66\x27\xdc\xcb\xe1\x9c\x73\x6f\x2e\xce\x72\x14\x63\x04\x71\x3c\x5d\x21\x98\x2c\x60\x9a\x61\x88\x5e\x93\x35\x5e\x43\xcd\x8c\xe1\x4d\xa5\xe1\x04\x98\x2f\xc9\xe0\x3f\x71\x3e\x7b\x8e\x73\xf8\x57\x9e\xfc\x8c\xf3\x5f\xf0\x4f\xf4\x2b\x02\x1f\xb4\x3e\x30\x38\x5d\x65\x53\xf0\x04\x5f\x12\xfc\x9c\x6d\x30\xcc\xb3\x97\x64\xfe\x03\x80\x1b\xe0\xb4\x28\xc4\xa1\x31\x16\x9c\x96\xa5\x62\x5a\x5f\xc7\x3f\xd2\xba\x66\x06\x4e\xb3\x6c\x85\xe2\x34\x02\xc5\x8e\x0e\x56\x2d\x2f\x8c\x5e\x71\x04\xb4\x11\x8a\x56\x7e\x25\x0f\x6f\xef\xec\xab\xe5\x15\x01\x49\xcd\xce\xed\x37\x74\xef\x53\x0a\x51\x0b\xe5\x7f\x2b\x46\x0d\x2b\x09\x35\x70\x1e\x63\x84\x93\x9f\xa8\x75\x22\xdd\xac\x56\x11\x38\xc8\x72\x34\x3a\xae\x7a\x93\x26\x7f\x6f\x10\x4c\xd2\x39\x7a\x85\x87\x86\xff\x3e\x30\xd2\xa9\x21\x5e\x71\x96\x0e\x7c\xe8\x62\x4f\xf0\xe5\x19\xe5\x28\x2c\x7f\xdc\x82\x2b\x76\x74\x04\xcc\x46\x02\x54\xbb\x08\x40\x1d\xa1\x5e\x31\x71\xa7\xce\x00\x42\xbc\x87\xe9\xb7\x6e\xd7\xf6\x4d\x89\xa3\x66\xca\xd6\x96\x97\xad\xc3\xa7\x35\x0d\x45\x18\x78\x6c\xf8\x9e\x69\x43\xf7\x12\x6e\xd6\xcb\x64\x99\xa2\x39\x9c\x26\xcb\x24\xc5\x11\x28\xa9\x94\xbe\xe4\x70\x8e\x16\xf1\x66\x85\xe1\x96\xd6\x9a\x45\x60\xc7\x6d\xdd\xbf\x92\xa6\x64\x9f\x70\x93\xae\xbb\x93\x49\x8a\x1f\xeb\x46\xcf\x98\x38\x3c\x38\x01\x6e\x8b\xf0\xf2\x9c\xaa\xcf\xb1\xc2\x22\xb0\xc8\x72\x94\x2c\x53\xab\x6c\xd2\x9f\x79\x82\x39\x5a\xa0\x1c\xa5\x33\xd4\xa3\x4f\xec\x7e\x96\xc2\x39\x5a\x21\x8c\xe0\x2c\x5e\xcf\xe2\x39\x02\x77\xdc\xb4\xf2\xad\x95\xbd\x6b\x03\x33\x1f\x93\x29\x99\xda\x73\xad\xb9\x68\x2c\xa0\x05\x26\xd7\x6a\xd1\xa7\x9d\x47\x86\x62\xc3\xf1\x13\xad\x76\x57\x4f\x2c\xea\x98\xd4\x5b\x04\x8d\xa2\x8d\xde\x76\xad\xd3\x30\x73\x14\xea\xdd\x16\x20\x14\xb6\x6b\x89\x01\xa1\x1d\xd5\xbb\x30\x38\xfa\xed\xf3\x91\xd2\x47\xde\xea\x77\x32\x72\xc8\x7c\xba\x79\xa1\x59\x53\x32\xe5\x33\x22\xa0\x58\xc1\xb8\x34\x2e\x5a\x8b\xca\xfd\x3a\x99\x8a\xa7\x57\x34\x87\xfd\x1b\x53\xae\x85\xaf\xb7\xf9\xa8\xa6\x5a\xd0\x92\x95\x6d\xc7\x87\x76\xff\xe3\xd4\xfb\xde\x9b\xc8\x49\x8d\xbc\xb0\xd3\xce\xab\x45\xf1\xae\x6f\xa7\x5f\x54\x29\x02\xb3\x2c\x5d\xe3\x3c\xb6\xd4\xdd\xa4\xf1\x85\x21\x92\x29\x3f\x71\xda\xdf\x0e\xda\x8f\xa7\x89\xc5\x0c\x97\xf4\xf7\x3e\xdd\xeb\xf2\x8e\xe9\x77\xcb\xee\x2e\x78\xd0\x7c\xaf\xf9\x7b\x96\x2f\xe2\xd5\xfa\xaa\x17\x7b\x2a\x25\x6f\x2a\xb2\x15\xca\xcf\x4e\x62\x04\x69\x15\x5c\xf5\xe4\xdc\xf3\xc7\x7d\x21\x8a\x36\x15\xfb\x9f\xec\xd9\x2a\xb1\xbf\x3c\x63\xc9\x19\x71\xbe\x7f\x8f\xde\x9e\xf2\x5a\x33\xf5\xd1\x7d\xb2\x10\x42\xc8\xcb\x70\xed\xc9\xd0\xb7\x31\x3b\x16\xae\x90\xb2\xa1\x71\xca\x36\x2a\xa9\xd6\x47\xa1\x02\x74\xb7\xbb\xad\x19\x33\x17\x27\x1e\x1b\x89\xbd\x00\xa2\xd8\xef\x03\xd3\x86\x54\x54\x7a\x31\x15\x95\x9d\x5d\xc1\xeb\x24\xc5\x68\x89\xce\xf9\xd9\x3c\x23\xee\x65\x5d\x7d\x0c\x6d\xc0\x3e\xd0\x17\x0f\xcd\xb8\x8e\xee\x05\x1f\x61\x4e\x1c\x18\xe1\xe5\xa7\x9d\xc0\xa3\x02\x5d\xde\xb7\x0b\x4c\x8c\x90\xbc\xf0\xce\xb4\x8b\xf1\x4a\x3b\xf0\x50\xcf\x6e\xb7\xa6\xda\x78\x16\xc1\xa3\xc1\x88\xb3\x39\x25\xd7\x85\xf8\x60\xea\xeb\xe2\xc9\x77\x1f\xa4\x4d\x6a\x58\x25\x0c\xb7\xff\x46\xae\x67\xfd\xe7\x1e\x68\x79\x7b\x9f\xc2\x47\x37\xac\xd1\xa8\xe4\x5a\x1c\x59\x2f\xaf\x6b\x1b\xa7\xb1\x53\xbf\xe3\xd5\x6e\x98\x61\x84\x8f\x5f\xd2\xfd\x77\x00\xe8\x42\x77\x9b\x97\x0b\x00\x00")
 
 func _0001_appUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -112,12 +150,12 @@ func _0001_appUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0001_app.up.sql", size: 2967, mode: os.FileMode(0644), modTime: time.Unix(1580303056, 0)}
+	info := bindataFileInfo{name: "0001_app.up.sql", size: 2967, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf7, 0x3a, 0xa7, 0xf2, 0x8f, 0xfa, 0x82, 0x7c, 0xc5, 0x49, 0xac, 0xac, 0xf, 0xc, 0x77, 0xe2, 0xba, 0xe8, 0x4d, 0xe, 0x6f, 0x5d, 0x2c, 0x2c, 0x18, 0x80, 0xc2, 0x1d, 0xe, 0x25, 0xe, 0x18}}
 	return a, nil
 }
 
-var __0002_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\xc9\xcf\x4e\xcd\x2b\xb6\xe6\x02\x04\x00\x00\xff\xff\xf0\xdb\x32\xa7\x13\x00\x00\x00")
+var __0002_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x13\x00\xec\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x74\x6f\x6b\x65\x6e\x73\x3b\x0a\x03\x00\xf0\xdb\x32\xa7\x13\x00\x00\x00")
 
 func _0002_tokensDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -132,12 +170,12 @@ func _0002_tokensDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0002_tokens.down.sql", size: 19, mode: os.FileMode(0644), modTime: time.Unix(1580303056, 0)}
+	info := bindataFileInfo{name: "0002_tokens.down.sql", size: 19, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd1, 0x31, 0x2, 0xcc, 0x2f, 0x38, 0x90, 0xf7, 0x58, 0x37, 0x47, 0xf4, 0x18, 0xf7, 0x72, 0x74, 0x67, 0x14, 0x7e, 0xf3, 0xb1, 0xd6, 0x5f, 0xb0, 0xd5, 0xe7, 0x91, 0xf4, 0x26, 0x77, 0x8e, 0x68}}
 	return a, nil
 }
 
-var __0002_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xcd\x6a\x85\x30\x10\x46\xf7\x79\x8a\x6f\x79\x05\xdf\xa0\xab\xa8\xa9\x0e\xb5\xb1\xc4\xb1\xea\xaa\x58\x93\x85\xf8\x13\x30\x42\xe9\xdb\x17\x4b\x4b\x2b\xdc\xed\x37\x67\x0e\x27\x35\x4a\xb2\x02\xcb\xa4\x54\xa0\x47\xe8\x8a\xa1\x3a\xaa\xb9\xc6\xe1\x67\xb7\x05\xdc\x04\x30\x58\xbb\xbb\x10\xf0\x2a\x4d\x5a\x48\xf3\x4d\xe9\xa6\x2c\x63\x01\x6c\xee\xf8\xf0\xfb\xfc\x36\x59\x34\xba\xa6\x5c\xab\x0c\x09\xe5\xa4\xf9\x8a\x0d\xab\x03\xab\xee\xba\x86\xcf\xf5\xdd\x2f\x77\xbd\xd6\x8d\xd3\x3a\x2c\xe1\xcf\x4a\x9a\xcf\xc3\xe8\x17\xbf\xff\xbe\x9c\xc3\x8b\xa1\x67\x69\x7a\x3c\xa9\x1e\xb7\x9f\xd4\xf8\x5f\x57\x24\x22\xb4\xc4\x45\xd5\x30\x4c\xd5\x52\xf6\x20\xc4\x57\x00\x00\x00\xff\xff\x73\xf3\x87\xe5\xf8\x00\x00\x00")
+var __0002_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\x4d\x6a\x85\x30\x18\x45\xe7\x59\xc5\x1d\x3e\xc1\x1d\x74\x14\x35\xd5\x8f\xda\x58\xe2\x67\xd5\x51\xb1\x26\x03\xf1\x27\x60\x84\xd2\xdd\x17\x4b\x4b\x2b\xbc\xe9\xe5\x9e\xc3\x49\x8d\x92\xac\xc0\x32\x29\x15\xe8\x11\xba\x62\xa8\x8e\x6a\xae\x71\xf8\xd9\x6d\x01\x37\x01\x0c\xd6\xee\x2e\x04\xbc\x4a\x93\x16\xd2\x7c\xbf\x74\x53\x96\xb1\x00\x36\x77\x7c\xf8\x7d\x7e\x9b\x2c\x1a\x5d\x53\xae\x55\x86\x84\x72\xd2\x7c\xbd\x0d\xab\x03\xab\xee\xba\x86\xcf\xf5\xdd\x2f\x77\xbd\xd6\x8d\xd3\x3a\x2c\xe1\xcf\x4a\x9a\x4f\x66\xf4\x8b\xdf\x7f\x91\x73\x78\x31\xf4\x2c\x4d\x8f\x27\xd5\xe3\xf6\x93\x1a\xff\xeb\x8a\x44\x84\x96\xb8\xa8\x1a\x86\xa9\x5a\xca\x1e\x84\xf8\x1a\x00\x73\xf3\x87\xe5\xf8\x00\x00\x00")
 
 func _0002_tokensUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -152,12 +190,12 @@ func _0002_tokensUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0002_tokens.up.sql", size: 248, mode: os.FileMode(0644), modTime: time.Unix(1580303056, 0)}
+	info := bindataFileInfo{name: "0002_tokens.up.sql", size: 248, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcc, 0xd6, 0xde, 0xd3, 0x7b, 0xee, 0x92, 0x11, 0x38, 0xa4, 0xeb, 0x84, 0xca, 0xcb, 0x37, 0x75, 0x5, 0x77, 0x7f, 0x14, 0x39, 0xee, 0xa1, 0x8b, 0xd4, 0x5c, 0x6e, 0x55, 0x6, 0x50, 0x16, 0xd4}}
 	return a, nil
 }
 
-var __0003_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\xca\xb1\x0a\xc2\x40\x0c\x06\xe0\x3d\x4f\xf1\x8f\x0a\xbe\x41\xa7\x5c\x1b\x69\xb0\x9a\x92\x46\x6b\x47\x87\x43\x04\x11\xe1\x4e\xc1\xb7\x77\x11\xd7\x8f\xaf\x73\x1b\x11\x9c\x06\x41\xc9\xb5\xde\x1e\xd7\xd2\x50\xeb\xc2\x21\x3f\xd6\x2d\x0e\x16\x90\xb3\x4e\x31\xfd\x13\x56\x04\xd4\xcf\x33\xe3\xc4\xde\xf6\xec\x18\x5d\xf7\xec\x0b\x76\xb2\x6c\x08\x78\x5f\xee\xaf\x8c\x34\x58\xa2\x35\x66\x8d\xde\x8e\x01\xb7\x59\xbb\x86\xe8\x1b\x00\x00\xff\xff\x49\x2e\x16\x6c\x76\x00\x00\x00")
+var __0003_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x76\x00\x89\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x3b\x0a\x43\x52\x45\x41\x54\x45\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x20\x28\x0a\x20\x20\x74\x79\x70\x65\x20\x56\x41\x52\x43\x48\x41\x52\x20\x50\x52\x49\x4d\x41\x52\x59\x20\x4b\x45\x59\x2c\x0a\x20\x20\x76\x61\x6c\x75\x65\x20\x42\x4c\x4f\x42\x0a\x29\x20\x57\x49\x54\x48\x4f\x55\x54\x20\x52\x4f\x57\x49\x44\x3b\x0a\x0a\x03\x00\x49\x2e\x16\x6c\x76\x00\x00\x00")
 
 func _0003_settingsDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -172,12 +210,12 @@ func _0003_settingsDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0003_settings.down.sql", size: 118, mode: os.FileMode(0644), modTime: time.Unix(1580303056, 0)}
+	info := bindataFileInfo{name: "0003_settings.down.sql", size: 118, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe5, 0xa6, 0xf5, 0xc0, 0x60, 0x64, 0x77, 0xe2, 0xe7, 0x3c, 0x9b, 0xb1, 0x52, 0xa9, 0x95, 0x16, 0xf8, 0x60, 0x2f, 0xa5, 0xeb, 0x46, 0xb9, 0xb9, 0x8f, 0x4c, 0xf4, 0xfd, 0xbb, 0xe7, 0xe5, 0xe5}}
 	return a, nil
 }
 
-var __0003_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x93\xbd\x6e\xdb\x30\x10\xc7\x77\x3f\x05\xb7\xb4\x40\x87\x66\x28\x50\x20\x93\x1c\xab\x89\x50\x57\x0a\x54\xb9\x41\xa6\x03\x4d\x9e\xad\x83\x29\x92\xe0\x51\x0e\xf4\xf6\x85\x1c\x45\x56\x53\xd9\x23\x79\xbf\xfb\xbe\xff\xaa\x2c\x9e\x44\x95\x2c\xd7\xa9\x60\x8c\x91\xec\x9e\xef\x16\xf7\x65\x9a\x54\xe9\x87\x6f\xf1\x69\x21\x84\xd4\x3a\x20\xb3\xf8\x93\x94\xf7\x8f\x49\x29\xf2\xa2\x12\xf9\x66\xbd\xfe\xb2\x10\x42\xd5\xd2\x31\x34\x4e\xa3\x58\x16\xc5\x3a\x4d\x72\xb1\x4a\x7f\x24\x9b\x75\x25\x76\xd2\x30\x9e\x98\x36\x04\xb4\xaa\x1b\x03\xbc\x13\x37\x2d\xeb\x9b\x33\x11\xc1\x62\x7c\x75\xe1\x30\x9f\xa9\xe5\xe8\x1a\xd8\x3a\x17\xad\xd3\xc8\x62\xb9\x2e\x96\x73\x06\x40\x2b\xb7\x06\xf5\x08\x68\xe9\x3d\xc3\xb5\x2e\x90\xfc\xed\xb7\xef\xb7\x1f\x99\xde\xb4\x33\x88\x71\xfa\x51\x93\x46\xa8\x5d\x83\x10\x9d\x33\x91\xfc\xe5\xc6\xc9\x72\x94\xc6\xc8\x48\xce\x02\xe9\xd9\xd4\x07\xec\xa0\xbd\x6c\x53\x32\x68\x38\xc5\xb1\x0a\xa7\xe0\xd4\xee\x25\x05\xd4\xe0\xac\xd8\xe4\xbf\xb3\x87\x3c\x5d\x89\x65\xf6\x90\xe5\xd5\x47\x88\xec\x7e\xea\x6f\x24\x47\x68\xbd\x96\x11\xf5\x9c\xab\x91\x11\x39\x82\xc6\x40\x47\xec\x23\xc4\xfa\x8c\x65\x79\x35\x76\xfc\xf5\x44\xbb\x3d\x18\x3c\xa2\x99\xa6\x68\x2c\x36\xce\x92\x9a\xfe\x59\xd9\xe0\x6c\xbf\xc3\xfa\xdf\x56\xfb\xaf\xc5\x69\x04\xe5\xec\x8e\xf6\xe3\x5a\xad\x8b\xb4\x23\x75\x9a\xee\x64\xe9\x97\x96\xe1\x6b\x17\xdd\x5b\x0f\xff\x85\xf7\x64\x2d\x6a\x68\x24\x19\xc6\x70\xc4\x70\xbe\x2e\x1f\x70\x87\xa1\x1f\xef\xb4\xec\xc1\x72\x24\x7c\x05\x1f\xe8\x28\x55\x77\x25\x73\xbb\x35\xa4\xe0\x80\xdd\x6c\xd7\x01\x1b\x6c\xb6\x18\x80\x3b\xab\xc8\xee\x41\xd5\x8e\xd4\x15\x3d\x31\xed\x6d\xcf\xf9\x3a\x48\x9e\x9f\x24\x47\x52\x07\x0c\x0c\x5e\xaa\x03\xc3\x70\x88\x13\x4d\x8c\x40\x40\xd5\x2b\xef\xfd\x7d\x06\x86\x62\x9c\x85\xc6\x6d\xc9\xe0\x28\xce\xcb\x75\x75\x36\xd6\x18\x49\x4d\x6f\x7d\xd4\x3a\xe9\x1b\xf1\x54\x66\xbf\x92\xf2\x45\xfc\x4c\x5f\x7a\x87\x96\x31\xf4\x53\x3d\x67\x7d\xed\xab\x8c\x10\x9c\x8b\x57\x05\x3b\x70\x8c\xfd\xfd\x82\x97\xcc\xd7\x56\x3f\xd0\x47\x62\xda\x9a\x5e\xb7\x07\xb4\x63\xdc\xc5\x67\xf1\x9c\x55\x8f\xc5\xa6\x12\x65\xf1\x9c\xad\xee\x16\x7f\x03\x00\x00\xff\xff\xa5\xa1\x7b\x78\x1f\x05\x00\x00")
+var __0003_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x93\xbf\x6e\xdb\x30\x10\xc6\x77\x3f\x05\x37\xb7\x40\x87\x66\x28\x50\x20\x93\x1c\xab\x89\x50\x57\x0a\x54\xb9\x41\xa6\x03\x4d\x9e\xad\x83\x29\x92\xe0\x51\x0e\xfc\xf6\x85\x1c\x47\x52\x5d\xd9\xa3\x78\x3f\xdd\xbf\xef\xbe\x65\x59\x3c\x8b\x2a\x59\xac\x52\xc1\x18\x23\xd9\x1d\xdf\xcf\x1e\xca\x34\xa9\xd2\x8b\x67\xf1\x69\x26\x84\xd4\x3a\x20\xb3\xf8\x93\x94\x0f\x4f\x49\x29\xf2\xa2\x12\xf9\x7a\xb5\xfa\x32\x13\x42\xd5\xd2\x31\x34\x4e\xa3\x58\x14\xc5\x2a\x4d\x72\xb1\x4c\x7f\x24\xeb\x55\x25\xb6\xd2\x30\x9e\x98\x36\x04\xb4\xea\xd8\x27\xf8\x20\xe6\x2d\xeb\xf9\x40\x44\xb0\x18\xdf\x5c\xd8\x4f\x57\x6a\x39\xba\x06\x36\xce\x45\xeb\x34\xb2\x58\xac\x8a\xc5\x54\x00\xd0\xca\x8d\x41\xdd\x03\x5a\x7a\xcf\x70\x6b\x0a\x24\x7f\xf7\xed\xfb\xdd\x25\xd3\x85\xb6\x06\x31\x8e\x1f\x6a\xd2\x08\xb5\x6b\x10\xa2\x73\x26\x92\xbf\x3e\x38\x59\x8e\xd2\x18\x19\xc9\x59\x20\x3d\x59\x7a\x8f\x47\x68\xaf\xc7\x94\x0c\x1a\x4e\x79\xac\xc2\x31\x38\x8e\x7b\x49\x01\x35\x38\x2b\xd6\xf9\xef\xec\x31\x4f\x97\x62\x91\x3d\x66\x79\x75\x09\x91\xdd\x8d\xff\x37\x92\x23\xb4\x5e\xcb\x88\x7a\xea\x57\x23\x23\x72\x04\x8d\x81\x0e\xd8\x65\x88\xf5\x80\x65\x79\xd5\x4f\xfc\xf5\x44\xbb\x1d\x18\x3c\xa0\x19\x97\x68\x2c\x36\xce\x92\x1a\xbf\x59\xd9\xe0\xe4\xbc\x67\xf9\xdf\xa5\xfd\x37\xe2\x34\x82\x72\x76\x4b\xbb\x5e\x56\xeb\x22\x6d\x49\x9d\xb6\x3b\x12\xfd\x9a\x18\xbe\x76\xd1\xbd\xcf\xf0\x5f\x7a\x4f\xd6\xa2\x86\x46\x92\x61\x0c\x07\x0c\xc3\x75\xf9\x80\x5b\x0c\xdd\x7a\xc7\x6d\x9f\x23\x07\xc2\x37\xf0\x81\x0e\x52\x1d\x6f\x54\x6e\x37\x86\x14\xec\x71\x70\xc0\xb8\x78\xc0\x06\x9b\x0d\x06\xe0\xa3\x55\x64\x77\xa0\x6a\x47\xea\x86\x9f\x98\x76\xb6\xe3\x7c\x1d\x24\x4f\x6f\x92\x23\xa9\x3d\x06\x06\x2f\xd5\x9e\xe1\x7c\x88\x23\x4f\xf4\x40\x40\xd5\x39\xef\xe3\x7b\x00\xce\xcd\x38\x0b\x8d\xdb\x90\xc1\xde\x9c\xd7\xfb\x3a\xda\x58\x63\x24\x35\xbe\xf5\x0f\x6a\x4e\x7a\x2e\x9e\xcb\xec\x57\x52\xbe\x8a\x9f\xe9\x6b\xd7\x65\xcb\x18\xba\xad\x0e\x55\xdf\xa4\x31\x18\x21\x38\x17\x6f\x1a\xf6\xcc\x31\x76\xf7\x0b\x5e\x32\xdf\x92\xfe\x4c\x1f\x88\x69\x63\x3a\xdf\xee\xd1\xf6\x79\x67\x9f\xc5\x4b\x56\x3d\x15\xeb\x4a\x94\xc5\x4b\xb6\xbc\x9f\xfd\x1d\x00\xa5\xa1\x7b\x78\x1f\x05\x00\x00")
 
 func _0003_settingsUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -192,12 +230,12 @@ func _0003_settingsUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0003_settings.up.sql", size: 1311, mode: os.FileMode(0644), modTime: time.Unix(1580381202, 0)}
+	info := bindataFileInfo{name: "0003_settings.up.sql", size: 1311, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xea, 0x35, 0x0, 0xeb, 0xe2, 0x33, 0x68, 0xb9, 0xf4, 0xf6, 0x8e, 0x9e, 0x10, 0xe9, 0x58, 0x68, 0x28, 0xb, 0xcd, 0xec, 0x74, 0x71, 0xa7, 0x9a, 0x5a, 0x77, 0x59, 0xb1, 0x13, 0x1c, 0xa1, 0x5b}}
 	return a, nil
 }
 
-var __0004_pending_stickersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x01\x00\x00\xff\xff\x00\x00\x00\x00\x00\x00\x00\x00")
+var __0004_pending_stickersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
 
 func _0004_pending_stickersDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -212,12 +250,12 @@ func _0004_pending_stickersDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0004_pending_stickers.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(1580381133, 0)}
+	info := bindataFileInfo{name: "0004_pending_stickers.down.sql", size: 0, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24, 0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55}}
 	return a, nil
 }
 
-var __0004_pending_stickersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\x2d\x29\xc9\xcc\x4b\x2f\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x2e\xc9\x4c\xce\x4e\x2d\x2a\x8e\x2f\x48\x4c\xce\x2e\x8e\x2f\x48\xcd\x4b\xc9\xcc\x4b\x57\x70\xf2\xf1\x77\xb2\xe6\x02\x04\x00\x00\xff\xff\xc9\xc1\xc2\xc6\x3d\x00\x00\x00")
+var __0004_pending_stickersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3d\x00\xc2\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x65\x74\x74\x69\x6e\x67\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x73\x74\x69\x63\x6b\x65\x72\x73\x5f\x70\x61\x63\x6b\x73\x5f\x70\x65\x6e\x64\x69\x6e\x67\x20\x42\x4c\x4f\x42\x3b\x0a\x03\x00\xc9\xc1\xc2\xc6\x3d\x00\x00\x00")
 
 func _0004_pending_stickersUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -232,12 +270,772 @@ func _0004_pending_stickersUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0004_pending_stickers.up.sql", size: 61, mode: os.FileMode(0644), modTime: time.Unix(1580381236, 0)}
+	info := bindataFileInfo{name: "0004_pending_stickers.up.sql", size: 61, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3c, 0xed, 0x25, 0xdf, 0x75, 0x2, 0x6c, 0xf0, 0xa2, 0xa8, 0x37, 0x62, 0x65, 0xad, 0xfd, 0x98, 0xa0, 0x9d, 0x63, 0x94, 0xdf, 0x6b, 0x46, 0xe0, 0x68, 0xec, 0x9c, 0x7f, 0x77, 0xdd, 0xb3, 0x6}}
 	return a, nil
 }
 
-var _docGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\xc9\xb1\x0d\xc4\x20\x0c\x05\xd0\x9e\x29\xfe\x02\xd8\xfd\x6d\xe3\x4b\xac\x2f\x44\x82\x09\x78\x7f\xa5\x49\xfd\xa6\x1d\xdd\xe8\xd8\xcf\x55\x8a\x2a\xe3\x47\x1f\xbe\x2c\x1d\x8c\xfa\x6f\xe3\xb4\x34\xd4\xd9\x89\xbb\x71\x59\xb6\x18\x1b\x35\x20\xa2\x9f\x0a\x03\xa2\xe5\x0d\x00\x00\xff\xff\x60\xcd\x06\xbe\x4a\x00\x00\x00")
+var __0005_transfers_tx_hashDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x22\x00\xdd\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x69\x64\x78\x5f\x74\x72\x61\x6e\x73\x66\x65\x72\x73\x5f\x74\x78\x5f\x68\x61\x73\x68\x3b\x0a\x03\x00\x7a\xf3\x2c\x8e\x22\x00\x00\x00")
+
+func _0005_transfers_tx_hashDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0005_transfers_tx_hashDownSql,
+		"0005_transfers_tx_hash.down.sql",
+	)
+}
+
+func _0005_transfers_tx_hashDownSql() (*asset, error) {
+	bytes, err := _0005_transfers_tx_hashDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0005_transfers_tx_hash.down.sql", size: 34, mode: os.FileMode(0644), modTime: time.Unix(1792055662, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb1, 0x72, 0xbd, 0xb7, 0x4e, 0x12, 0x73, 0xd9, 0xb, 0xe4, 0xe, 0x71, 0xcc, 0xfc, 0xfa, 0x74, 0x1d, 0x2, 0x5a, 0xd, 0xad, 0xe1, 0x9a, 0xc4, 0x2, 0x1, 0xb2, 0x4e, 0xf8, 0xdd, 0xec, 0xc9}}
+	return a, nil
+}
+
+var __0005_transfers_tx_hashUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x29\x4a\xcc\x2b\x4e\x4b\x2d\x2a\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xa9\x88\xcf\x48\x2c\xce\x50\x08\x73\x0c\x72\xf6\x70\x0c\xb2\xe6\x0a\x0d\x70\x71\x0c\x41\x56\x1c\xec\x1a\x02\x57\x65\xab\x00\xa6\xc2\x3d\x5c\x83\x5c\x15\x4a\x2a\x0b\x52\x15\x6c\x15\xd4\x53\x4b\x32\xd4\xad\xb9\x9c\x83\x5c\x41\xfa\x3c\xfd\x5c\x5c\x23\x14\x32\x53\x2a\xe2\xe1\x26\xc4\xc3\x74\xfb\xfb\x21\x19\xab\x01\x15\xd5\x51\xc8\x4b\x2d\x29\xcf\x2f\xca\x8e\xcf\x4c\xd1\xb4\xe6\x02\x0c\x00\x9f\x2f\x90\x26\xb1\x00\x00\x00")
+
+func _0005_transfers_tx_hashUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0005_transfers_tx_hashUpSql,
+		"0005_transfers_tx_hash.up.sql",
+	)
+}
+
+func _0005_transfers_tx_hashUpSql() (*asset, error) {
+	bytes, err := _0005_transfers_tx_hashUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0005_transfers_tx_hash.up.sql", size: 177, mode: os.FileMode(0644), modTime: time.Unix(1792055662, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x64, 0x1, 0x33, 0xca, 0xdd, 0xed, 0x8e, 0x20, 0xd8, 0x73, 0xab, 0xf0, 0x6f, 0x27, 0x90, 0x4b, 0xa5, 0xe7, 0x97, 0x5f, 0xf, 0x1f, 0xca, 0xd9, 0xff, 0xe1, 0x5, 0xaf, 0x5a, 0xd1, 0x3c, 0xa2}}
+	return a, nil
+}
+
+var __0006_token_metadataDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1b\x00\xe4\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x74\x6f\x6b\x65\x6e\x5f\x6d\x65\x74\x61\x64\x61\x74\x61\x3b\x0a\x03\x00\xa6\x87\xad\x40\x1b\x00\x00\x00")
+
+func _0006_token_metadataDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0006_token_metadataDownSql,
+		"0006_token_metadata.down.sql",
+	)
+}
+
+func _0006_token_metadataDownSql() (*asset, error) {
+	bytes, err := _0006_token_metadataDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0006_token_metadata.down.sql", size: 27, mode: os.FileMode(0644), modTime: time.Unix(1792056466, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0x95, 0x1e, 0x5e, 0x3b, 0x99, 0x29, 0x47, 0x13, 0xe3, 0x57, 0x8a, 0x70, 0x6a, 0xa5, 0x4d, 0xbf, 0xf6, 0xfd, 0xc0, 0x1d, 0xb5, 0xbb, 0xd0, 0x7b, 0x23, 0xdb, 0xab, 0xd9, 0x61, 0x3b, 0x68}}
+	return a, nil
+}
+
+var __0006_token_metadataUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xce\xc1\x6a\x84\x30\x14\x85\xe1\x7d\x9e\xe2\x2c\x67\x60\xde\xa0\xab\xa8\xa9\x5e\x6a\x63\x89\xd7\xaa\x2b\x49\x9b\x2c\x44\xa3\x60\x02\xa5\x6f\x5f\x28\x85\xd6\x32\xdb\xc3\xe1\xe3\xcf\x8d\x92\xac\xc0\x32\xab\x15\xe8\x11\xba\x61\xa8\x81\x5a\x6e\x91\xf6\xc5\x6f\x53\xf0\xc9\x3a\x9b\x2c\x2e\x02\xb0\xce\x1d\x3e\x46\xbc\x4a\x93\x57\xd2\x7c\xbf\x75\x57\xd7\x37\x01\x6c\x3e\x7d\xec\xc7\x32\xcd\x0e\x9d\x6e\xa9\xd4\xaa\x40\x46\x25\x69\x3e\xdf\x6c\xf0\x60\x35\x9c\xd7\xf8\x19\xde\xf6\xf5\xae\xeb\xfc\xfb\x1c\xec\x1a\x7f\xd5\xff\xe4\x8b\xa1\x67\x69\x46\x3c\xa9\x11\x97\x9f\xc4\xdb\x9f\x9e\xab\xb8\xa2\x27\xae\x9a\x8e\x61\x9a\x9e\x8a\x07\xf1\x35\x00\xce\x19\x05\x5c\xf7\x00\x00\x00")
+
+func _0006_token_metadataUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0006_token_metadataUpSql,
+		"0006_token_metadata.up.sql",
+	)
+}
+
+func _0006_token_metadataUpSql() (*asset, error) {
+	bytes, err := _0006_token_metadataUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0006_token_metadata.up.sql", size: 247, mode: os.FileMode(0644), modTime: time.Unix(1792056466, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x51, 0x14, 0xc3, 0x12, 0xff, 0xf9, 0x2e, 0x30, 0x87, 0xa4, 0xd3, 0x5b, 0x3, 0x1d, 0xc8, 0x5a, 0x9, 0x23, 0xfa, 0xeb, 0xa4, 0x3d, 0x4c, 0xb2, 0xf5, 0xd2, 0x12, 0x56, 0x31, 0xa5, 0x77, 0x53}}
+	return a, nil
+}
+
+var __0007_historical_pricesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1e\x00\xe1\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x68\x69\x73\x74\x6f\x72\x69\x63\x61\x6c\x5f\x70\x72\x69\x63\x65\x73\x3b\x0a\x03\x00\xe4\x53\x56\x0e\x1e\x00\x00\x00")
+
+func _0007_historical_pricesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0007_historical_pricesDownSql,
+		"0007_historical_prices.down.sql",
+	)
+}
+
+func _0007_historical_pricesDownSql() (*asset, error) {
+	bytes, err := _0007_historical_pricesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0007_historical_prices.down.sql", size: 30, mode: os.FileMode(0644), modTime: time.Unix(1792057682, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x12, 0xae, 0xbd, 0x79, 0x9, 0xaf, 0xf, 0x7d, 0x69, 0xec, 0xc6, 0xa2, 0xc7, 0xc4, 0x96, 0xd8, 0xfb, 0xb2, 0x53, 0x0, 0x36, 0x31, 0xfe, 0xa7, 0x1e, 0xd, 0x5d, 0x6b, 0x53, 0x3b, 0x4b, 0x4c}}
+	return a, nil
+}
+
+var __0007_historical_pricesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xcd\xc1\x8a\x83\x30\x14\x85\xe1\x7d\x9e\xe2\x2c\x15\x7c\x83\x59\x45\xcd\xe8\x65\x32\x71\x88\xd7\xb1\xae\x8a\x8d\x42\x05\x5b\x4b\x6c\x17\x79\xfb\x82\x85\x82\xd0\xed\xf9\x0f\x7c\x99\x55\x92\x15\x58\xa6\x5a\x81\xbe\x61\x2a\x86\x3a\x50\xcd\x35\xce\xd3\x7a\x5f\xfc\xe4\xfa\xf9\x78\xf3\x93\x1b\x57\x44\x02\x58\xc3\xe5\xb4\xcc\xf8\x97\x36\x2b\xa5\xdd\xfe\xa6\xd1\x3a\x11\x80\x7b\x78\x3f\x5e\x5d\xf8\x18\x87\x3e\xa0\x31\x35\x15\x46\xe5\x48\xa9\x20\xc3\xbb\xbe\x11\xb0\x4a\xea\xdd\xfc\x67\xe9\x57\xda\x0e\x3f\xaa\x43\xf4\xb2\x93\x37\x94\x60\xe8\x43\x2c\x62\xb4\xc4\x65\xd5\x30\x6c\xd5\x52\xfe\x25\x9e\x03\x00\x2f\x28\x5c\x1c\xd5\x00\x00\x00")
+
+func _0007_historical_pricesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0007_historical_pricesUpSql,
+		"0007_historical_prices.up.sql",
+	)
+}
+
+func _0007_historical_pricesUpSql() (*asset, error) {
+	bytes, err := _0007_historical_pricesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0007_historical_prices.up.sql", size: 213, mode: os.FileMode(0644), modTime: time.Unix(1792057682, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x83, 0x72, 0x37, 0x4b, 0x1d, 0xa3, 0x6c, 0x6e, 0x3b, 0xff, 0xc3, 0x2a, 0x68, 0xcb, 0xe8, 0x4c, 0xdd, 0x9f, 0xb5, 0x13, 0xd3, 0xc, 0x89, 0xe0, 0xb5, 0x0, 0xa5, 0xf8, 0x91, 0x3, 0x75, 0x5c}}
+	return a, nil
+}
+
+var __0008_contract_abisDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1a\x00\xe5\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x6e\x74\x72\x61\x63\x74\x5f\x61\x62\x69\x73\x3b\x0a\x03\x00\xfb\x2f\x99\x11\x1a\x00\x00\x00")
+
+func _0008_contract_abisDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0008_contract_abisDownSql,
+		"0008_contract_abis.down.sql",
+	)
+}
+
+func _0008_contract_abisDownSql() (*asset, error) {
+	bytes, err := _0008_contract_abisDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0008_contract_abis.down.sql", size: 26, mode: os.FileMode(0644), modTime: time.Unix(1792058046, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x47, 0xc0, 0xae, 0x3e, 0xdd, 0x7f, 0x91, 0xbf, 0xfe, 0x90, 0x64, 0x30, 0x8, 0xc7, 0x5d, 0xa7, 0x1b, 0x38, 0x1, 0xd7, 0x57, 0x3, 0xfa, 0x1a, 0x4, 0x5c, 0xb7, 0x3b, 0x6f, 0x7b, 0x1b, 0xd3}}
+	return a, nil
+}
+
+var __0008_contract_abisUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcd\xbd\x0a\x83\x30\x14\xc5\xf1\x3d\x4f\x71\x46\x05\xdf\xa0\x53\xd4\x5b\xbd\xd4\xc6\x12\xaf\x55\x27\x89\x1f\x83\x14\x14\x34\xd0\xd7\x2f\x94\x0e\x75\x3d\xfc\x38\xff\xc4\x92\x16\x82\xe8\xb8\x20\xf0\x15\xa6\x14\x50\xcb\x95\x54\x18\xb7\xd5\xef\x6e\xf4\xbd\x1b\x96\x03\x81\x02\xdc\x34\xed\xf3\x71\xe0\xa9\x6d\x92\x6b\xfb\xc5\xa6\x2e\x8a\x48\x01\xeb\xec\xdf\xdb\xfe\xea\x97\x09\xb5\xa9\x38\x33\x94\x22\xe6\x8c\x8d\x9c\x98\x1b\x16\x08\xb5\xe7\xf1\x61\xf9\xae\x6d\x87\x1b\x75\x08\x7e\x91\xe8\xef\x31\x54\x21\x1a\x96\xbc\xac\x05\xb6\x6c\x38\xbd\xa8\xcf\x00\xa9\xdb\x08\x36\xb8\x00\x00\x00")
+
+func _0008_contract_abisUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0008_contract_abisUpSql,
+		"0008_contract_abis.up.sql",
+	)
+}
+
+func _0008_contract_abisUpSql() (*asset, error) {
+	bytes, err := _0008_contract_abisUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0008_contract_abis.up.sql", size: 184, mode: os.FileMode(0644), modTime: time.Unix(1792058046, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x50, 0xc3, 0x87, 0xc3, 0x5c, 0x9f, 0x99, 0x46, 0xbb, 0x2, 0x63, 0xd6, 0x2e, 0xbe, 0x5d, 0x59, 0xdb, 0x3c, 0x47, 0x4f, 0x52, 0x4e, 0x13, 0x1, 0x6e, 0x83, 0xec, 0xd8, 0x82, 0xc4, 0x11, 0x68}}
+	return a, nil
+}
+
+var __0009_hardware_accountsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1e\x00\xe1\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x68\x61\x72\x64\x77\x61\x72\x65\x5f\x61\x63\x63\x6f\x75\x6e\x74\x73\x3b\x0a\x03\x00\xd2\xdb\xb6\x82\x1e\x00\x00\x00")
+
+func _0009_hardware_accountsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0009_hardware_accountsDownSql,
+		"0009_hardware_accounts.down.sql",
+	)
+}
+
+func _0009_hardware_accountsDownSql() (*asset, error) {
+	bytes, err := _0009_hardware_accountsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0009_hardware_accounts.down.sql", size: 30, mode: os.FileMode(0644), modTime: time.Unix(1792059281, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf8, 0xdd, 0x32, 0x22, 0x22, 0x18, 0x87, 0xcb, 0x7, 0x75, 0x85, 0x79, 0x0, 0xe9, 0xba, 0x90, 0xef, 0x6f, 0xc0, 0x97, 0x54, 0x91, 0x6d, 0x45, 0x6f, 0xd7, 0x70, 0xdc, 0x83, 0x51, 0x10, 0xa7}}
+	return a, nil
+}
+
+var __0009_hardware_accountsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\xcb\xb1\x0a\xc2\x30\x14\x46\xe1\x3d\x4f\xf1\x8f\x0a\xbe\x81\x53\xac\x91\x06\x63\x23\xb7\xb7\xd6\x4e\x72\x69\x02\x1d\x8a\x4a\x12\xe9\xeb\x0b\x0e\x22\x38\x7f\xe7\x54\x64\x34\x1b\xb0\xde\x39\x03\x7b\x40\xe3\x19\xe6\x6a\x5b\x6e\x31\x49\x0a\x8b\xa4\x78\x93\x71\x7c\xbc\xee\x25\x63\xa5\x00\x09\x21\xc5\x9c\x71\xd1\x54\xd5\x9a\x70\x26\x7b\xd2\x34\xe0\x68\x86\xcf\xdc\x74\xce\x6d\x14\xb0\xc8\x3c\xc7\xf2\xcd\x7e\xe9\x29\x65\xfa\x03\xb5\x46\x6f\xb9\xf6\x1d\x83\x7c\x6f\xf7\x5b\xf5\x1e\x00\x12\xf7\xb9\xcd\x9b\x00\x00\x00")
+
+func _0009_hardware_accountsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0009_hardware_accountsUpSql,
+		"0009_hardware_accounts.up.sql",
+	)
+}
+
+func _0009_hardware_accountsUpSql() (*asset, error) {
+	bytes, err := _0009_hardware_accountsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0009_hardware_accounts.up.sql", size: 155, mode: os.FileMode(0644), modTime: time.Unix(1792059281, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3c, 0xe9, 0xeb, 0x1, 0x1d, 0x77, 0x29, 0x88, 0xff, 0xc2, 0xc3, 0x43, 0x0, 0x93, 0x59, 0xce, 0x1e, 0xbb, 0x92, 0x78, 0x77, 0xfc, 0xdd, 0x92, 0x91, 0xf, 0xe, 0xab, 0xfb, 0x41, 0xb0, 0x7c}}
+	return a, nil
+}
+
+var __0010_spending_limitsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1c\x00\xe3\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x73\x70\x65\x6e\x64\x69\x6e\x67\x5f\x6c\x69\x6d\x69\x74\x73\x3b\x0a\x03\x00\x00\x6f\xbd\x71\x1c\x00\x00\x00")
+
+func _0010_spending_limitsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0010_spending_limitsDownSql,
+		"0010_spending_limits.down.sql",
+	)
+}
+
+func _0010_spending_limitsDownSql() (*asset, error) {
+	bytes, err := _0010_spending_limitsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0010_spending_limits.down.sql", size: 28, mode: os.FileMode(0644), modTime: time.Unix(1792059744, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2, 0x10, 0x74, 0x89, 0x3, 0x3c, 0x66, 0xb8, 0xea, 0x59, 0xdc, 0xb3, 0x68, 0x2, 0x4b, 0x9f, 0xf2, 0xd3, 0x92, 0x4a, 0x87, 0x22, 0x88, 0xd1, 0xa3, 0x6f, 0xd3, 0x3f, 0xd1, 0x10, 0x3e, 0xb8}}
+	return a, nil
+}
+
+var __0010_spending_limitsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xc1\x6a\x84\x30\x18\x84\xef\x79\x8a\x39\x2a\xf8\x06\x3d\x45\x4d\xf5\xa7\x36\x96\x18\x6b\x3d\x89\x90\x50\x82\x36\x16\x23\xc8\xbe\xfd\xb2\xbb\xc8\x22\xec\x75\x3e\xe6\x9b\xc9\x94\xe0\x5a\x40\xf3\xb4\x12\xa0\x77\xc8\x5a\x43\xfc\x50\xa3\x1b\x84\x7f\xeb\x8d\xf3\xbf\xc3\xec\xfe\xdc\x16\x10\x31\xc0\xdb\x6d\x5f\xd6\x69\x70\x06\xad\x6c\xa8\x90\x22\x47\x4a\x05\x49\x7d\x6f\xca\xb6\xaa\x12\x06\x8c\xc6\xac\x36\x04\x7c\x73\x95\x95\x5c\x9d\xd8\xb6\x4c\xd6\xbf\x24\x66\x74\xf3\xe5\x20\x37\xcd\x6e\xed\x74\x4e\xbe\x14\x7d\x72\xd5\xe3\x43\xf4\x88\x9e\x67\x92\x63\x31\x79\xe8\x63\x16\xa3\x23\x5d\xd6\xad\x86\xaa\x3b\xca\xdf\xd8\x75\x00\xd7\xfd\x2e\x1a\xe9\x00\x00\x00")
+
+func _0010_spending_limitsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0010_spending_limitsUpSql,
+		"0010_spending_limits.up.sql",
+	)
+}
+
+func _0010_spending_limitsUpSql() (*asset, error) {
+	bytes, err := _0010_spending_limitsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0010_spending_limits.up.sql", size: 233, mode: os.FileMode(0644), modTime: time.Unix(1792059744, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x58, 0x41, 0x83, 0xbd, 0x85, 0x9, 0xad, 0x68, 0xb2, 0x71, 0xdd, 0x46, 0x70, 0xc5, 0x19, 0xd7, 0xdc, 0x2e, 0x71, 0x77, 0xa1, 0xbc, 0x2b, 0x56, 0x46, 0xeb, 0xa5, 0xca, 0xf2, 0x53, 0xf3, 0x5c}}
+	return a, nil
+}
+
+var __0011_address_bookDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x19\x00\xe6\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x61\x64\x64\x72\x65\x73\x73\x5f\x62\x6f\x6f\x6b\x3b\x0a\x03\x00\x66\x41\x6a\xfa\x19\x00\x00\x00")
+
+func _0011_address_bookDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0011_address_bookDownSql,
+		"0011_address_book.down.sql",
+	)
+}
+
+func _0011_address_bookDownSql() (*asset, error) {
+	bytes, err := _0011_address_bookDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0011_address_book.down.sql", size: 25, mode: os.FileMode(0644), modTime: time.Unix(1792060875, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x32, 0xff, 0xfd, 0x25, 0x2d, 0x1, 0xd8, 0x49, 0x25, 0xa0, 0x8, 0xfd, 0x8b, 0x59, 0xc, 0x54, 0x15, 0x11, 0xcd, 0x37, 0x69, 0x61, 0x66, 0x80, 0x57, 0xfe, 0xb3, 0x51, 0x47, 0xe, 0xe4, 0xf4}}
+	return a, nil
+}
+
+var __0011_address_bookUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xc1\x8a\x83\x30\x14\x45\xf7\xf9\x8a\xbb\x54\xf0\x0f\x66\x15\x35\xa3\x8f\x71\xe2\x10\xe3\x58\x57\xc1\x92\x2c\x8a\xad\x01\x23\xed\xef\x17\x0b\xd2\x56\xba\xbd\x87\x73\x39\x99\x12\x5c\x0b\x68\x9e\x56\x02\xf4\x0d\x59\x6b\x88\x03\x35\xba\xc1\x60\xed\xec\x42\x30\x47\xef\x47\x44\x0c\x98\xdc\x72\xf3\xf3\x68\x4e\x16\xad\x6c\xa8\x90\x22\x47\x4a\x05\x49\xfd\xd0\x64\x5b\x55\x09\xc3\xe6\xe1\x9f\xab\xac\xe4\xea\x8d\x4d\xc3\xc5\x7d\x04\x6e\x0a\xe6\x15\x6e\xdb\xee\x6c\x9d\x67\x17\xfc\xf9\xea\xac\x19\x96\x7d\xc7\x8a\xff\x14\xfd\x72\xd5\xe3\x47\xf4\x88\x9e\xc9\xc9\xd6\x15\xb3\x18\x1d\xe9\xb2\x6e\x35\x54\xdd\x51\xfe\xc5\xee\x03\x00\x11\xf1\xfa\x22\x05\x01\x00\x00")
+
+func _0011_address_bookUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0011_address_bookUpSql,
+		"0011_address_book.up.sql",
+	)
+}
+
+func _0011_address_bookUpSql() (*asset, error) {
+	bytes, err := _0011_address_bookUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0011_address_book.up.sql", size: 261, mode: os.FileMode(0644), modTime: time.Unix(1792060875, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x69, 0x12, 0xb9, 0x6f, 0xd3, 0x51, 0x5b, 0x25, 0x6b, 0xef, 0xad, 0x0, 0x80, 0x49, 0xdb, 0x21, 0xb9, 0x4c, 0xe0, 0x21, 0x8b, 0x56, 0xf4, 0xdd, 0x1b, 0x50, 0x1f, 0x36, 0xfc, 0x2d, 0xb, 0xd9}}
+	return a, nil
+}
+
+var __0012_owned_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x19\x00\xe6\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x6f\x77\x6e\x65\x64\x5f\x74\x6f\x6b\x65\x6e\x73\x3b\x0a\x03\x00\xdd\x8d\x51\xbb\x19\x00\x00\x00")
+
+func _0012_owned_tokensDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0012_owned_tokensDownSql,
+		"0012_owned_tokens.down.sql",
+	)
+}
+
+func _0012_owned_tokensDownSql() (*asset, error) {
+	bytes, err := _0012_owned_tokensDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0012_owned_tokens.down.sql", size: 25, mode: os.FileMode(0644), modTime: time.Unix(1792061420, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7, 0xbf, 0xf3, 0x61, 0x95, 0x30, 0x8c, 0x70, 0x4f, 0x1f, 0x36, 0x8c, 0x40, 0xca, 0x7c, 0x22, 0xa2, 0xa3, 0x71, 0x42, 0x71, 0x13, 0x5e, 0x11, 0x64, 0x5b, 0xf8, 0xa3, 0xa9, 0xd9, 0xd5, 0x12}}
+	return a, nil
+}
+
+var __0012_owned_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\x41\xca\x83\x30\x18\x44\xf7\x39\xc5\x2c\x15\xbc\xc1\xbf\x8a\x9a\x5f\x3f\x6a\x63\x89\xb1\xd6\x95\xa4\x26\xd0\x62\x89\xa0\x82\xd7\x2f\x58\x4a\x29\x48\xd7\x8f\x99\xf7\x12\x25\xb8\x16\xd0\x3c\x2e\x04\xe8\x1f\xb2\xd4\x10\x17\xaa\x74\x85\x71\xf5\xce\x76\xcb\x38\x38\x3f\x23\x60\x80\x77\xcb\x3a\x4e\x43\x77\xb7\xa8\x65\x45\x99\x14\x29\x62\xca\x48\xea\x6d\x26\xeb\xa2\x88\x18\x60\xac\x9d\xdc\x3c\xe3\xcc\x55\x92\x73\xf5\xc5\xb6\xb7\x5d\x72\x35\x0f\xe3\x7b\xb7\xcb\xfa\x9b\xeb\x07\x67\x3b\xb3\xfc\x14\x9f\x14\x1d\xb9\x6a\x71\x10\x2d\x82\x4f\x6c\xf4\x2e\x8a\x5e\xfa\x90\x85\x68\x48\xe7\x65\xad\xa1\xca\x86\xd2\x3f\xf6\x1c\x00\xdd\x39\x21\x60\x06\x01\x00\x00")
+
+func _0012_owned_tokensUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0012_owned_tokensUpSql,
+		"0012_owned_tokens.up.sql",
+	)
+}
+
+func _0012_owned_tokensUpSql() (*asset, error) {
+	bytes, err := _0012_owned_tokensUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0012_owned_tokens.up.sql", size: 262, mode: os.FileMode(0644), modTime: time.Unix(1792061420, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd1, 0x36, 0x32, 0xa7, 0x99, 0xb5, 0xb7, 0x89, 0xef, 0x57, 0xd0, 0x48, 0xc5, 0xb7, 0xbd, 0x35, 0xb8, 0x73, 0xd6, 0xf3, 0xff, 0x3e, 0x85, 0xbf, 0x95, 0xf7, 0x74, 0x32, 0x7d, 0xca, 0x3e, 0xc0}}
+	return a, nil
+}
+
+var __0013_crypto_on_rampsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1c\x00\xe3\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x72\x79\x70\x74\x6f\x5f\x6f\x6e\x5f\x72\x61\x6d\x70\x73\x3b\x0a\x03\x00\x9c\xe2\x0a\xb6\x1c\x00\x00\x00")
+
+func _0013_crypto_on_rampsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0013_crypto_on_rampsDownSql,
+		"0013_crypto_on_ramps.down.sql",
+	)
+}
+
+func _0013_crypto_on_rampsDownSql() (*asset, error) {
+	bytes, err := _0013_crypto_on_rampsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0013_crypto_on_ramps.down.sql", size: 28, mode: os.FileMode(0644), modTime: time.Unix(1792062528, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8c, 0x23, 0x57, 0x42, 0x74, 0x30, 0x2b, 0x3c, 0x34, 0x4f, 0x5a, 0x61, 0x30, 0xe4, 0x96, 0xc9, 0xe, 0x31, 0x4, 0xdd, 0x58, 0x3f, 0x49, 0x31, 0x89, 0xc, 0x6b, 0x48, 0x2, 0xd5, 0xa7, 0xb2}}
+	return a, nil
+}
+
+var __0013_crypto_on_rampsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcc\xbd\x6e\x83\x30\x18\x85\xe1\xdd\x57\x71\xc6\x56\xea\x1d\x74\xb2\xc1\x85\x4f\x75\xed\xca\x98\x10\x26\x84\xc0\x51\x18\xc0\x96\xf1\x92\xbb\x8f\x92\x21\x3f\xf3\x79\xce\x5b\x58\xc9\x9d\x84\xe3\x42\x49\xd0\x0f\xb4\x71\x90\x47\x6a\x5c\x83\x29\x5d\x62\x0e\x43\xd8\x86\x34\xae\x71\xc7\x07\x03\xb6\x71\xf5\x38\x70\x5b\xd4\xdc\xde\xad\x6e\x95\xc2\xbf\xa5\x3f\x6e\x7b\xfc\xca\xfe\x8b\x01\x31\xec\x4b\x5e\xc2\x86\x56\x37\x54\x69\x59\x82\xb4\x7b\xe8\x9b\x98\xfd\x3e\xa5\x25\xe6\x90\x20\x94\x11\x6f\xdb\xc9\xe7\xe9\xec\xe7\x61\xcc\xcf\xbf\xa0\xea\x35\xc1\x3e\xd1\x91\xab\x4d\xeb\x60\x4d\x47\xe5\x37\xbb\x0e\x00\xb2\xb5\x51\xc5\xc7\x00\x00\x00")
+
+func _0013_crypto_on_rampsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0013_crypto_on_rampsUpSql,
+		"0013_crypto_on_ramps.up.sql",
+	)
+}
+
+func _0013_crypto_on_rampsUpSql() (*asset, error) {
+	bytes, err := _0013_crypto_on_rampsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0013_crypto_on_ramps.up.sql", size: 199, mode: os.FileMode(0644), modTime: time.Unix(1792062528, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcf, 0x1f, 0x8d, 0x44, 0xfd, 0x9b, 0x5, 0x19, 0x3f, 0xa7, 0x11, 0xf, 0xc, 0x97, 0x9a, 0x6, 0x89, 0xaa, 0xab, 0xe2, 0x6c, 0x6f, 0xac, 0x7a, 0x2b, 0x93, 0xbb, 0x1, 0x81, 0x19, 0x74, 0x54}}
+	return a, nil
+}
+
+var __0014_transfers_l2DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x2e\x00\xd1\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x69\x64\x78\x5f\x74\x72\x61\x6e\x73\x66\x65\x72\x73\x5f\x62\x72\x69\x64\x67\x65\x5f\x6d\x65\x73\x73\x61\x67\x65\x5f\x68\x61\x73\x68\x3b\x0a\x03\x00\xac\x4c\x47\x6f\x2e\x00\x00\x00")
+
+func _0014_transfers_l2DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0014_transfers_l2DownSql,
+		"0014_transfers_l2.down.sql",
+	)
+}
+
+func _0014_transfers_l2DownSql() (*asset, error) {
+	bytes, err := _0014_transfers_l2DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0014_transfers_l2.down.sql", size: 46, mode: os.FileMode(0644), modTime: time.Unix(1792063423, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x31, 0xf8, 0xfc, 0xee, 0x5e, 0x8b, 0x3f, 0x43, 0x65, 0x50, 0xea, 0x60, 0x20, 0x91, 0xbc, 0xd4, 0xf5, 0x27, 0xc4, 0x41, 0x76, 0xcb, 0xc, 0xfe, 0x6c, 0x76, 0xdf, 0xf1, 0x54, 0xbc, 0x4, 0xbf}}
+	return a, nil
+}
+
+var __0014_transfers_l2UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x29\x4a\xcc\x2b\x4e\x4b\x2d\x2a\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\xc8\x31\x8a\x4f\x4b\x4d\x55\x70\xf2\xf1\x77\xb2\xe6\x22\xa8\x3a\xa9\x28\x33\x25\x3d\x35\x3e\x37\xb5\xb8\x38\x31\x3d\x35\x3e\x23\xb1\x38\x43\x21\xcc\x31\xc8\xd9\xc3\x31\xc8\x9a\xcb\x39\xc8\xd5\x31\xc4\x55\xc1\xd3\xcf\xc5\x35\x42\x21\x33\xa5\x22\x1e\x6e\x44\x3c\x36\x7d\xfe\x7e\x48\x76\x68\x60\x51\xa1\x69\xcd\x05\x18\x00\xad\xf8\xde\x60\xbf\x00\x00\x00")
+
+func _0014_transfers_l2UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0014_transfers_l2UpSql,
+		"0014_transfers_l2.up.sql",
+	)
+}
+
+func _0014_transfers_l2UpSql() (*asset, error) {
+	bytes, err := _0014_transfers_l2UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0014_transfers_l2.up.sql", size: 191, mode: os.FileMode(0644), modTime: time.Unix(1792063423, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x59, 0xfb, 0x99, 0xbc, 0xf9, 0xe6, 0x70, 0xed, 0xdd, 0x89, 0x41, 0x8b, 0x89, 0xbb, 0x4a, 0xac, 0x1c, 0x42, 0x32, 0x2b, 0x91, 0xe5, 0xf1, 0x20, 0xc9, 0x66, 0xd4, 0xb3, 0x51, 0x12, 0x84, 0xcf}}
+	return a, nil
+}
+
+var __0015_method_signaturesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1e\x00\xe1\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x6d\x65\x74\x68\x6f\x64\x5f\x73\x69\x67\x6e\x61\x74\x75\x72\x65\x73\x3b\x0a\x03\x00\xe3\x28\x7a\xf9\x1e\x00\x00\x00")
+
+func _0015_method_signaturesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0015_method_signaturesDownSql,
+		"0015_method_signatures.down.sql",
+	)
+}
+
+func _0015_method_signaturesDownSql() (*asset, error) {
+	bytes, err := _0015_method_signaturesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0015_method_signatures.down.sql", size: 30, mode: os.FileMode(0644), modTime: time.Unix(1792063931, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6a, 0x11, 0x16, 0x39, 0xbd, 0x46, 0xd7, 0xf1, 0x3e, 0x61, 0x5c, 0x7c, 0xc8, 0xc4, 0xd, 0x61, 0x9a, 0xaf, 0xf6, 0x1c, 0x3c, 0x31, 0xdb, 0x24, 0xf5, 0x53, 0x33, 0xf5, 0xaa, 0xe7, 0x2b, 0x61}}
+	return a, nil
+}
+
+var __0015_method_signaturesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcc\xc1\x0a\x82\x40\x14\x85\xe1\xfd\x3c\xc5\x59\x2a\xf8\x06\xad\x26\xbb\xe1\xa5\xc9\x89\xeb\x35\x75\x15\x51\x43\x05\x95\xe0\x4c\xef\x1f\x05\x12\xad\xff\xf3\x9d\x52\xc8\x2a\x41\xed\xd2\x11\x78\x8d\xda\x2b\xa8\xe7\x46\x1b\x3c\x42\xba\x8e\xe7\x43\xbc\x5d\x9e\xc7\xf4\x9a\x42\x44\x66\x80\x18\xee\xe1\x94\xc6\x09\x7b\x2b\x65\x65\xe5\x2b\xea\xd6\xb9\xe2\x13\xe7\x2d\x94\x7a\xfd\x4b\x3b\xe1\xad\x95\x01\x1b\x1a\x90\xcd\x27\xc5\x4f\xe4\x26\x47\xc7\x5a\xf9\x56\x21\xbe\xe3\xd5\xc2\xbc\x07\x00\x23\xff\x3e\x06\x9c\x00\x00\x00")
+
+func _0015_method_signaturesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0015_method_signaturesUpSql,
+		"0015_method_signatures.up.sql",
+	)
+}
+
+func _0015_method_signaturesUpSql() (*asset, error) {
+	bytes, err := _0015_method_signaturesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0015_method_signatures.up.sql", size: 156, mode: os.FileMode(0644), modTime: time.Unix(1792063931, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0x8e, 0x61, 0x33, 0xaf, 0xf0, 0xe0, 0xde, 0xcb, 0x80, 0x43, 0xf5, 0x9e, 0x2f, 0xce, 0x33, 0x7, 0x34, 0x89, 0x71, 0xd5, 0xad, 0x30, 0x13, 0x89, 0x52, 0x2a, 0x47, 0x3d, 0x8a, 0x41, 0x75}}
+	return a, nil
+}
+
+var __0016_allowancesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x48\xcc\xc9\xc9\x2f\x4f\xcc\x4b\x4e\x2d\xb6\xe6\x72\xc1\x26\x1c\x5f\x9c\x9c\x98\x97\x97\x9a\x62\xcd\x05\x00\xfd\x93\xa5\xdf\x36\x00\x00\x00")
+
+func _0016_allowancesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0016_allowancesDownSql,
+		"0016_allowances.down.sql",
+	)
+}
+
+func _0016_allowancesDownSql() (*asset, error) {
+	bytes, err := _0016_allowancesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0016_allowances.down.sql", size: 54, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x26, 0xb0, 0xd6, 0xc2, 0xb0, 0xbc, 0x1a, 0xfe, 0xc7, 0x8d, 0x31, 0xfc, 0x2b, 0x6f, 0xcb, 0x7a, 0xb, 0xa9, 0x3d, 0xb8, 0x39, 0x41, 0x6, 0x6e, 0xd3, 0x2a, 0xd1, 0xe6, 0xbf, 0x73, 0xa5, 0x6f}}
+	return a, nil
+}
+
+var __0016_allowancesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x90\xcb\x0e\x82\x30\x10\x45\xf7\xfd\x8a\x59\x42\xc2\x1f\xb8\x2a\x50\xa1\x11\x8b\x29\x45\x64\x45\x78\x74\x61\xa8\xc5\xf0\x90\xdf\x97\x48\x8c\x31\xa9\x89\x89\xae\xcf\xdc\xcc\xb9\xd7\xe3\x04\x0b\x02\x02\xbb\x11\x01\xba\x05\x16\x0b\x20\x27\x9a\x88\x04\x4a\xa5\xba\xb9\xd4\xb5\x1c\xc0\x42\x00\x5a\x8e\x73\xd7\xb7\xc5\xb9\x81\x94\x25\x34\x60\xc4\x07\x97\x06\x94\x89\x47\x88\xa5\x51\xe4\x2c\x67\xdd\xac\x65\x0f\x47\xcc\xbd\x10\xf3\x37\x32\x76\xad\xd4\x46\x32\x5c\xa5\x6e\x3e\xa4\x6e\xa5\x9a\xa4\x91\x54\xaa\x2d\xf4\x74\xa9\x96\xa0\xc1\xe3\xc0\xe9\x1e\xf3\x1c\x76\x24\x07\xeb\xe5\xee\xac\x82\xce\x6a\xe3\x3c\x5f\xdb\xc8\x86\x8c\x8a\x30\x4e\x05\xf0\x38\xa3\xfe\x06\x21\xef\x9b\x69\x8a\xa1\x2e\xb5\x96\xcd\x7f\x26\xfa\xa5\x92\xa1\xc2\x1d\x07\xa6\x79\xdc\xdd\x01\x00\x00")
+
+func _0016_allowancesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0016_allowancesUpSql,
+		"0016_allowances.up.sql",
+	)
+}
+
+func _0016_allowancesUpSql() (*asset, error) {
+	bytes, err := _0016_allowancesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0016_allowances.up.sql", size: 477, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6b, 0x5f, 0x3f, 0x10, 0x6c, 0xf1, 0x95, 0x24, 0xdf, 0xac, 0x16, 0x49, 0x7d, 0xe, 0xb0, 0xc7, 0xc9, 0xd0, 0x3e, 0xb6, 0xe2, 0x40, 0xc4, 0x1b, 0xe9, 0x49, 0xa7, 0x1f, 0xe3, 0x2a, 0x4e, 0xe8}}
+	return a, nil
+}
+
+var __0017_transfers_chartDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xc8\x4c\xa9\x88\x2f\x29\x4a\xcc\x2b\x4e\x4b\x2d\x2a\x8e\x4f\xce\x48\x2c\x2a\xb1\xe6\x02\x00\xbd\xdb\x05\xaa\x20\x00\x00\x00")
+
+func _0017_transfers_chartDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0017_transfers_chartDownSql,
+		"0017_transfers_chart.down.sql",
+	)
+}
+
+func _0017_transfers_chartDownSql() (*asset, error) {
+	bytes, err := _0017_transfers_chartDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0017_transfers_chart.down.sql", size: 32, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xad, 0x5f, 0xb2, 0x71, 0x20, 0x90, 0x58, 0x61, 0x5f, 0xc2, 0x28, 0x95, 0xf, 0x86, 0xb5, 0x23, 0x1, 0xd5, 0x70, 0xfe, 0xf6, 0x10, 0xa3, 0x6a, 0x5a, 0x5d, 0x1b, 0x60, 0x7, 0x4b, 0x8e, 0x2}}
+	return a, nil
+}
+
+var __0017_transfers_chartUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xcc\xb1\x0a\xc2\x30\x14\x85\xe1\xbd\x4f\x71\x46\x85\xbe\x41\xa7\x6b\x1b\x50\x88\x29\x84\x28\x6e\x21\x98\x88\xa1\x34\x91\xe4\x16\x7d\x7c\x1d\x44\xdc\x74\x3a\xcb\xff\x1d\x92\x46\x68\x18\xda\x48\x01\x2e\x2e\xd5\x4b\x28\x15\x34\x0c\xe8\x47\x79\xd8\x2b\x70\x9e\x42\xc2\x91\x74\xbf\x25\xdd\x35\xf4\xab\x77\x73\x5e\x12\xdb\x98\xa0\x05\xc9\xff\x41\x5e\xf8\x2d\xfa\xd7\x18\x81\x9d\x1a\xc4\x09\xd1\x3f\xec\x87\xd9\xf3\xd5\x15\xc6\xa8\xbe\x9e\x56\x29\xf0\x3d\x97\xc9\x46\xdf\xc2\x79\x5f\x42\xad\x2d\x38\xce\xa1\xb2\x9b\x6f\xeb\xae\x79\x02\xcc\x56\xa3\x03\xe3\x00\x00\x00")
+
+func _0017_transfers_chartUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0017_transfers_chartUpSql,
+		"0017_transfers_chart.up.sql",
+	)
+}
+
+func _0017_transfers_chartUpSql() (*asset, error) {
+	bytes, err := _0017_transfers_chartUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0017_transfers_chart.up.sql", size: 227, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x31, 0xad, 0x8a, 0x3d, 0x37, 0x7c, 0x3c, 0x2a, 0x5d, 0x29, 0xfa, 0x47, 0xb2, 0x85, 0xc0, 0xf5, 0xa7, 0x82, 0x70, 0x22, 0x76, 0x92, 0xdc, 0x95, 0x28, 0xae, 0x9, 0x90, 0x4a, 0xf8, 0x1a, 0x2c}}
+	return a, nil
+}
+
+var __0018_share_tokensDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\xce\x48\x2c\x4a\x8d\x2f\xc9\xcf\x4e\xcd\x2b\xb6\xe6\x02\x00\x74\x76\xfa\x7e\x19\x00\x00\x00")
+
+func _0018_share_tokensDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0018_share_tokensDownSql,
+		"0018_share_tokens.down.sql",
+	)
+}
+
+func _0018_share_tokensDownSql() (*asset, error) {
+	bytes, err := _0018_share_tokensDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0018_share_tokens.down.sql", size: 25, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0x6a, 0x50, 0x61, 0x97, 0x19, 0xd3, 0x1e, 0xe6, 0x45, 0x22, 0x20, 0xf8, 0x3a, 0xa8, 0x1e, 0xdc, 0x85, 0xb0, 0xa5, 0x41, 0x8f, 0x14, 0x4, 0x21, 0x6f, 0x9, 0x60, 0xee, 0x40, 0x28, 0xcf}}
+	return a, nil
+}
+
+var __0018_share_tokensUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6d\x8d\x41\x0a\x83\x30\x14\x05\xf7\x9e\xe2\x2d\x15\xbc\x41\x57\x51\x53\xfd\xd4\x26\x25\x7e\x6b\x5d\x89\x90\x40\x25\xa0\x90\x08\xbd\x7e\x4b\xe9\xa2\x85\xae\x67\xe6\xbd\xd2\x48\xc1\x12\x2c\x8a\x56\x82\x8e\x50\x9a\x21\x6f\xd4\x71\x87\x78\x9f\x83\x9b\xf6\xcd\xbb\x35\x22\x4d\x80\xd9\xda\xe0\x62\xc4\x55\x98\xb2\x11\xe6\xed\xaa\xbe\x6d\xf3\x17\x5b\xdd\xfe\xd8\x82\x9f\x16\x8b\x5e\x75\x54\x2b\x59\xa1\xa0\x9a\x14\xff\x68\x7e\x59\xed\xdf\xfe\x62\xe8\x2c\xcc\x88\x93\x1c\x91\x7e\x8e\xf2\xaf\xd5\x2c\xc9\x30\x10\x37\xba\x67\x18\x3d\x50\x75\x48\x9e\xb5\x8c\x97\xa7\xbb\x00\x00\x00")
+
+func _0018_share_tokensUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0018_share_tokensUpSql,
+		"0018_share_tokens.up.sql",
+	)
+}
+
+func _0018_share_tokensUpSql() (*asset, error) {
+	bytes, err := _0018_share_tokensUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0018_share_tokens.up.sql", size: 187, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5a, 0x41, 0xc0, 0x68, 0xc0, 0x28, 0xac, 0x76, 0x58, 0xa9, 0xd2, 0x6d, 0x9e, 0xd9, 0xfa, 0x1d, 0x88, 0x16, 0xee, 0x9d, 0xe8, 0xf9, 0x39, 0x46, 0xa4, 0x87, 0x15, 0x18, 0x55, 0x4b, 0xe7, 0xa2}}
+	return a, nil
+}
+
+var __0019_transfers_withdrawalDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func _0019_transfers_withdrawalDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0019_transfers_withdrawalDownSql,
+		"0019_transfers_withdrawal.down.sql",
+	)
+}
+
+func _0019_transfers_withdrawalDownSql() (*asset, error) {
+	bytes, err := _0019_transfers_withdrawalDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0019_transfers_withdrawal.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24, 0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55}}
+	return a, nil
+}
+
+var __0019_transfers_withdrawalUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x29\x4a\xcc\x2b\x4e\x4b\x2d\x2a\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xcf\x2c\xc9\x48\x29\x4a\x2c\x4f\xcc\x51\x70\xf2\xf1\x77\xb2\xe6\x02\x00\xa8\x6c\xf1\x3b\x32\x00\x00\x00")
+
+func _0019_transfers_withdrawalUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0019_transfers_withdrawalUpSql,
+		"0019_transfers_withdrawal.up.sql",
+	)
+}
+
+func _0019_transfers_withdrawalUpSql() (*asset, error) {
+	bytes, err := _0019_transfers_withdrawalUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0019_transfers_withdrawal.up.sql", size: 50, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xaa, 0x84, 0x4c, 0x45, 0xd1, 0xa2, 0x1a, 0xe8, 0x4a, 0x7d, 0x4, 0xa6, 0xa1, 0x63, 0x2c, 0x84, 0x13, 0x10, 0x3e, 0x79, 0xc4, 0xf, 0x9d, 0x7e, 0xaa, 0xaa, 0x9c, 0x15, 0x46, 0x37, 0x2e, 0xef}}
+	return a, nil
+}
+
+var __0020_transfer_notesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\x29\x4a\xcc\x2b\x4e\x4b\x2d\x8a\x2f\x49\x4c\x2f\xb6\xe6\x72\xc1\x22\x93\x97\x5f\x92\x0a\x94\x02\x00\xe7\xa5\xda\xf3\x35\x00\x00\x00")
+
+func _0020_transfer_notesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0020_transfer_notesDownSql,
+		"0020_transfer_notes.down.sql",
+	)
+}
+
+func _0020_transfer_notesDownSql() (*asset, error) {
+	bytes, err := _0020_transfer_notesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0020_transfer_notes.down.sql", size: 53, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x98, 0x54, 0xac, 0x68, 0xd3, 0x5, 0x4d, 0x3a, 0xd0, 0x11, 0xb, 0xa9, 0x7c, 0x58, 0x47, 0xf5, 0xba, 0x64, 0x66, 0xe8, 0x49, 0xe5, 0x60, 0x47, 0xd4, 0x5, 0xfa, 0x5f, 0x2f, 0x36, 0x98, 0xa}}
+	return a, nil
+}
+
+var __0020_transfer_notesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x8f\xcb\x0a\x82\x40\x14\x86\xf7\xf3\x14\xff\x52\xc1\x37\x70\x35\xea\xa4\x87\x6c\x8c\x71\x2c\x5d\x89\xa0\x5d\x08\x14\x74\xa0\x1e\x3f\x75\x53\x4a\x44\x8b\x36\x67\x71\xce\x7f\xf9\x8e\xaf\x04\xd7\x02\x9a\x7b\xb1\x00\x6d\x20\x13\x0d\x91\x53\xaa\x53\x98\xbe\x6a\x87\x53\xd3\x97\x6d\x67\x9a\x01\x16\x03\xda\xc6\xdc\xbb\xfe\x56\x5e\x6b\x64\x32\xa5\x50\x8a\x00\x1e\x85\x24\xf5\x6c\x94\x59\x1c\x3b\xa3\xec\x52\x0d\x17\x1c\xb8\xf2\x23\xae\x16\x87\x29\x09\x5a\xe4\x4b\xf9\x5e\xd1\x8e\xab\x02\x5b\x51\xc0\x7a\x55\x38\x73\x8e\xcd\x6c\x1c\x49\x47\x49\xa6\xa1\x92\x23\x05\x2e\x63\xfe\x0f\xd0\xa6\x3a\xff\x87\x79\x0c\xfa\xb8\xff\x4a\xed\x4c\xb6\x2f\xe8\x24\x03\x91\xaf\xd0\xaf\xf5\xa3\x5c\xe0\x4f\x03\x89\x5c\xff\xf4\xde\x35\xb5\xb8\xec\x09\x32\x4a\xc6\x3e\xc5\x01\x00\x00")
+
+func _0020_transfer_notesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0020_transfer_notesUpSql,
+		"0020_transfer_notes.up.sql",
+	)
+}
+
+func _0020_transfer_notesUpSql() (*asset, error) {
+	bytes, err := _0020_transfer_notesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0020_transfer_notes.up.sql", size: 453, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe5, 0x66, 0xb3, 0xd9, 0x91, 0x7a, 0xc3, 0x79, 0xa0, 0x41, 0xad, 0x2e, 0xf, 0x19, 0x16, 0x64, 0xb3, 0x8d, 0x3a, 0x40, 0xa3, 0x82, 0xb8, 0xc1, 0xdc, 0x7b, 0xc0, 0x69, 0x3b, 0xc9, 0x51, 0x2e}}
+	return a, nil
+}
+
+var __0021_nft_metadataDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xc8\x4b\x2b\x89\xcf\x4d\x2d\x49\x4c\x49\x2c\x49\xb4\xe6\x02\x00\xda\xcb\xc0\x50\x19\x00\x00\x00")
+
+func _0021_nft_metadataDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0021_nft_metadataDownSql,
+		"0021_nft_metadata.down.sql",
+	)
+}
+
+func _0021_nft_metadataDownSql() (*asset, error) {
+	bytes, err := _0021_nft_metadataDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0021_nft_metadata.down.sql", size: 25, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x51, 0x6b, 0x16, 0x7d, 0xd4, 0xde, 0x64, 0x13, 0xe0, 0x76, 0x45, 0xa1, 0x7b, 0x2e, 0xbb, 0x17, 0xbb, 0x55, 0xcb, 0xea, 0x68, 0x92, 0x3c, 0x4d, 0x8c, 0x7, 0x5, 0x5f, 0x60, 0xe8, 0xa6, 0x65}}
+	return a, nil
+}
+
+var __0021_nft_metadataUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x90\xcb\x0e\x82\x30\x10\x45\xf7\x7c\xc5\xdd\xa9\x09\x0b\xf7\xae\x8a\x54\x6d\xac\xc5\x94\xe2\x63\x45\x1a\xa8\xd1\x18\xc1\x94\x31\xfe\xbe\xe2\x02\x35\x3e\xb6\x73\xce\x4c\xe6\xde\xb1\xe6\xcc\x70\x18\x16\x49\x0e\x31\x81\x4a\x0c\xf8\x46\xa4\x26\x45\xb5\xa3\xfc\xe4\xc8\x96\x96\x2c\xfa\x01\x50\x39\xba\xd6\xfe\x98\x1f\x4a\x64\x2a\x15\x53\xc5\x63\x44\x62\x2a\x94\x79\xac\xa9\x4c\xca\xf0\xae\x15\x75\x45\xde\x16\x84\x15\xd3\xe3\x19\xd3\x6f\x90\xea\xa3\xab\xda\x0b\xbf\xe1\xc5\x1f\xbe\xd2\x86\x2c\x5d\x9a\xaf\xa8\x7b\x33\x92\x49\xd4\x0e\x9c\xf7\xb5\xff\x50\x11\xf3\x09\xcb\xa4\x41\xaf\xd7\x4a\x96\xc8\x9d\xce\xd4\xe0\x35\x41\xe7\x0c\x5b\x65\xe7\xa8\xd8\xbb\x32\xb7\xf4\x37\xf2\x52\x8b\x05\xd3\x5b\xcc\xf9\x16\xfd\x67\x4d\x61\xd7\x45\xd8\x05\x1f\x04\x03\xac\x85\x99\x25\x99\x81\x4e\xd6\x22\x1e\x05\x37\xd1\x83\x6e\x5e\x84\x01\x00\x00")
+
+func _0021_nft_metadataUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0021_nft_metadataUpSql,
+		"0021_nft_metadata.up.sql",
+	)
+}
+
+func _0021_nft_metadataUpSql() (*asset, error) {
+	bytes, err := _0021_nft_metadataUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0021_nft_metadata.up.sql", size: 388, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x17, 0xcc, 0xd7, 0x81, 0xf1, 0xa8, 0xce, 0x11, 0x8c, 0x9f, 0xd9, 0xce, 0x6, 0xb1, 0x13, 0x27, 0x8, 0xf7, 0x96, 0x44, 0x43, 0xec, 0x4b, 0xfc, 0x5f, 0xfa, 0x84, 0xa3, 0x5e, 0xa7, 0x91, 0xfa}}
+	return a, nil
+}
+
+var __0022_local_settingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xc8\xc9\x4f\x4e\xcc\x89\x2f\x4e\x2d\x29\xc9\xcc\x4b\x2f\xb6\xe6\x02\x00\x1a\xc5\x03\xa5\x1b\x00\x00\x00")
+
+func _0022_local_settingsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0022_local_settingsDownSql,
+		"0022_local_settings.down.sql",
+	)
+}
+
+func _0022_local_settingsDownSql() (*asset, error) {
+	bytes, err := _0022_local_settingsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0022_local_settings.down.sql", size: 27, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x11, 0x59, 0x72, 0xd2, 0x28, 0x64, 0xe7, 0x4d, 0xca, 0x20, 0xce, 0xd2, 0x8a, 0x1e, 0x9f, 0xad, 0x1, 0x25, 0x38, 0xf3, 0xc6, 0x5f, 0x91, 0x32, 0xf3, 0xae, 0x6b, 0x3a, 0x35, 0xa3, 0x7b, 0x75}}
+	return a, nil
+}
+
+var __0022_local_settingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6d\x8d\x41\x0b\x82\x30\x1c\x47\xef\x7e\x8a\xdf\x51\xc1\x6f\xd0\x69\xd3\xa5\x7f\x5a\x33\xe6\xcc\x3c\xc9\xd0\x11\x82\x59\xe4\xf4\xf3\x17\xd2\x45\xe8\xfa\xde\x83\x97\x68\xc1\x8c\x80\x61\x5c\x0a\xd0\x11\xaa\x30\x10\x37\x2a\x4d\x89\xf1\xd9\xd9\xb1\x9d\x9d\xf7\xc3\x74\x9f\x11\x06\xc0\xec\xde\xeb\xd0\x39\x5c\x99\x4e\x72\xa6\xb7\x5a\x55\x52\xc6\x5f\x37\xd9\xc7\x7f\xb1\xda\x71\x71\xe0\xb2\xe0\x3b\xbc\xbc\x7a\xeb\x5d\xdf\x5a\x8f\x4a\x95\x94\x29\x91\x82\x53\x46\xca\xec\xb2\x8b\xa6\x33\xd3\x0d\x4e\xa2\x41\xf8\xfb\xc7\xdb\x2c\x0a\x22\xd4\x64\xf2\xa2\x32\xd0\x45\x4d\xe9\x21\xf8\x00\x9d\x0b\xfb\xff\xce\x00\x00\x00")
+
+func _0022_local_settingsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0022_local_settingsUpSql,
+		"0022_local_settings.up.sql",
+	)
+}
+
+func _0022_local_settingsUpSql() (*asset, error) {
+	bytes, err := _0022_local_settingsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0022_local_settings.up.sql", size: 206, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x10, 0xfc, 0x98, 0x42, 0x23, 0x9b, 0xb, 0x92, 0xe9, 0xf6, 0x63, 0x58, 0xfa, 0x6e, 0xee, 0xb2, 0x3a, 0x2a, 0x9d, 0x7c, 0x21, 0x6a, 0x36, 0x6b, 0xf9, 0x40, 0x8e, 0x43, 0x54, 0x48, 0x3d, 0xd1}}
+	return a, nil
+}
+
+var __0023_keycard_pairingsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xc8\x4e\xad\x4c\x4e\x2c\x4a\x89\x4f\x4c\x4e\xce\x2f\xcd\x2b\x29\xb6\xe6\x72\xc1\x94\x2c\x48\xcc\x2c\xca\xcc\x4b\x07\x4a\x02\x00\xce\xd3\xf9\xe5\x3a\x00\x00\x00")
+
+func _0023_keycard_pairingsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0023_keycard_pairingsDownSql,
+		"0023_keycard_pairings.down.sql",
+	)
+}
+
+func _0023_keycard_pairingsDownSql() (*asset, error) {
+	bytes, err := _0023_keycard_pairingsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0023_keycard_pairings.down.sql", size: 58, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4a, 0x62, 0xd0, 0xbc, 0x47, 0xa1, 0x82, 0xe8, 0xed, 0x5a, 0x6e, 0x18, 0x75, 0x13, 0x55, 0x81, 0xf2, 0x4a, 0x5d, 0xc9, 0x2, 0x67, 0x84, 0xe1, 0x23, 0xb9, 0xaa, 0x24, 0xb4, 0xc6, 0xd5, 0x80}}
+	return a, nil
+}
+
+var __0023_keycard_pairingsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x90\x41\x0a\xc2\x30\x10\x45\xf7\x3d\xc5\x2c\x15\xbc\x81\xab\xb4\x8d\x1a\xac\xa9\xa4\xa9\xb5\xab\x10\x92\xa0\x41\x88\x92\xa4\xa0\xb7\xb7\xa8\xa0\xd2\xa2\xeb\xff\xe6\xf1\xff\x64\x0c\x23\x8e\x81\xa3\xb4\xc0\x40\x16\x40\x4b\x0e\x78\x4f\x2a\x5e\xc1\xc9\xdc\x94\xf4\x5a\x5c\xa4\xf5\xd6\x1d\x02\x4c\x12\x00\xeb\x42\x94\x4e\x19\xd1\x59\x0d\x3b\xc4\xb2\x15\x62\xb0\x65\x64\x83\x58\x0b\x6b\xdc\x3e\x04\xb4\x2e\x8a\x59\x0f\xbf\x2e\x45\x6f\x82\xb4\x28\xd3\xd1\xd0\x3a\x6d\xae\x50\xd3\x8a\x2c\x29\xce\x81\x50\xfe\x8d\x59\x27\xbc\x89\xde\x9a\xf0\x03\xea\x5d\x46\x0b\x19\xdf\x48\x4a\x96\x9f\x54\x32\x85\x86\xf0\x55\x59\x73\x60\x65\x43\xf2\x79\x92\x64\xff\xa7\x4b\xa5\xce\x9d\x8b\xcf\xe9\x52\x6b\x6f\x42\xf8\xbb\x7a\xf4\x45\xdf\x6d\xe3\x71\x10\x0c\x0b\xde\x01\x44\xef\xa9\x3c\x9b\x01\x00\x00")
+
+func _0023_keycard_pairingsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0023_keycard_pairingsUpSql,
+		"0023_keycard_pairings.up.sql",
+	)
+}
+
+func _0023_keycard_pairingsUpSql() (*asset, error) {
+	bytes, err := _0023_keycard_pairingsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0023_keycard_pairings.up.sql", size: 411, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0x40, 0x93, 0xfa, 0xe5, 0x9b, 0x1b, 0x31, 0xf2, 0x76, 0x61, 0xe6, 0x8c, 0xf0, 0x3a, 0xcd, 0x74, 0x8b, 0xb9, 0x19, 0xa9, 0xab, 0x2a, 0x95, 0x94, 0x9b, 0x6, 0x6c, 0x47, 0x21, 0x3, 0x4d}}
+	return a, nil
+}
+
+var _docGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4a\x00\xb5\xff\x70\x61\x63\x6b\x61\x67\x65\x20\x73\x71\x6c\x0a\x0a\x2f\x2f\x67\x6f\x3a\x67\x65\x6e\x65\x72\x61\x74\x65\x20\x67\x6f\x2d\x62\x69\x6e\x64\x61\x74\x61\x20\x2d\x70\x6b\x67\x20\x6d\x69\x67\x72\x61\x74\x69\x6f\x6e\x73\x20\x2d\x6f\x20\x2e\x2e\x2f\x62\x69\x6e\x64\x61\x74\x61\x2e\x67\x6f\x20\x2e\x2f\x0a\x03\x00\x60\xcd\x06\xbe\x4a\x00\x00\x00")
 
 func docGoBytes() ([]byte, error) {
 	return bindataRead(
@@ -252,7 +1050,7 @@ func docGo() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "doc.go", size: 74, mode: os.FileMode(0644), modTime: time.Unix(1574771268, 0)}
+	info := bindataFileInfo{name: "doc.go", size: 74, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xde, 0x7c, 0x28, 0xcd, 0x47, 0xf2, 0xfa, 0x7c, 0x51, 0x2d, 0xd8, 0x38, 0xb, 0xb0, 0x34, 0x9d, 0x4c, 0x62, 0xa, 0x9e, 0x28, 0xc3, 0x31, 0x23, 0xd9, 0xbb, 0x89, 0x9f, 0xa0, 0x89, 0x1f, 0xe8}}
 	return a, nil
 }
@@ -348,26 +1146,112 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"0001_app.down.sql":              _0001_appDownSql,
-	"0001_app.up.sql":                _0001_appUpSql,
-	"0002_tokens.down.sql":           _0002_tokensDownSql,
-	"0002_tokens.up.sql":             _0002_tokensUpSql,
-	"0003_settings.down.sql":         _0003_settingsDownSql,
-	"0003_settings.up.sql":           _0003_settingsUpSql,
+	"0001_app.down.sql": _0001_appDownSql,
+
+	"0001_app.up.sql": _0001_appUpSql,
+
+	"0002_tokens.down.sql": _0002_tokensDownSql,
+
+	"0002_tokens.up.sql": _0002_tokensUpSql,
+
+	"0003_settings.down.sql": _0003_settingsDownSql,
+
+	"0003_settings.up.sql": _0003_settingsUpSql,
+
 	"0004_pending_stickers.down.sql": _0004_pending_stickersDownSql,
-	"0004_pending_stickers.up.sql":   _0004_pending_stickersUpSql,
-	"doc.go":                         docGo,
+
+	"0004_pending_stickers.up.sql": _0004_pending_stickersUpSql,
+
+	"0005_transfers_tx_hash.down.sql": _0005_transfers_tx_hashDownSql,
+
+	"0005_transfers_tx_hash.up.sql": _0005_transfers_tx_hashUpSql,
+
+	"0006_token_metadata.down.sql": _0006_token_metadataDownSql,
+
+	"0006_token_metadata.up.sql": _0006_token_metadataUpSql,
+
+	"0007_historical_prices.down.sql": _0007_historical_pricesDownSql,
+
+	"0007_historical_prices.up.sql": _0007_historical_pricesUpSql,
+
+	"0008_contract_abis.down.sql": _0008_contract_abisDownSql,
+
+	"0008_contract_abis.up.sql": _0008_contract_abisUpSql,
+
+	"0009_hardware_accounts.down.sql": _0009_hardware_accountsDownSql,
+
+	"0009_hardware_accounts.up.sql": _0009_hardware_accountsUpSql,
+
+	"0010_spending_limits.down.sql": _0010_spending_limitsDownSql,
+
+	"0010_spending_limits.up.sql": _0010_spending_limitsUpSql,
+
+	"0011_address_book.down.sql": _0011_address_bookDownSql,
+
+	"0011_address_book.up.sql": _0011_address_bookUpSql,
+
+	"0012_owned_tokens.down.sql": _0012_owned_tokensDownSql,
+
+	"0012_owned_tokens.up.sql": _0012_owned_tokensUpSql,
+
+	"0013_crypto_on_ramps.down.sql": _0013_crypto_on_rampsDownSql,
+
+	"0013_crypto_on_ramps.up.sql": _0013_crypto_on_rampsUpSql,
+
+	"0014_transfers_l2.down.sql": _0014_transfers_l2DownSql,
+
+	"0014_transfers_l2.up.sql": _0014_transfers_l2UpSql,
+
+	"0015_method_signatures.down.sql": _0015_method_signaturesDownSql,
+
+	"0015_method_signatures.up.sql": _0015_method_signaturesUpSql,
+
+	"0016_allowances.down.sql": _0016_allowancesDownSql,
+
+	"0016_allowances.up.sql": _0016_allowancesUpSql,
+
+	"0017_transfers_chart.down.sql": _0017_transfers_chartDownSql,
+
+	"0017_transfers_chart.up.sql": _0017_transfers_chartUpSql,
+
+	"0018_share_tokens.down.sql": _0018_share_tokensDownSql,
+
+	"0018_share_tokens.up.sql": _0018_share_tokensUpSql,
+
+	"0019_transfers_withdrawal.down.sql": _0019_transfers_withdrawalDownSql,
+
+	"0019_transfers_withdrawal.up.sql": _0019_transfers_withdrawalUpSql,
+
+	"0020_transfer_notes.down.sql": _0020_transfer_notesDownSql,
+
+	"0020_transfer_notes.up.sql": _0020_transfer_notesUpSql,
+
+	"0021_nft_metadata.down.sql": _0021_nft_metadataDownSql,
+
+	"0021_nft_metadata.up.sql": _0021_nft_metadataUpSql,
+
+	"0022_local_settings.down.sql": _0022_local_settingsDownSql,
+
+	"0022_local_settings.up.sql": _0022_local_settingsUpSql,
+
+	"0023_keycard_pairings.down.sql": _0023_keycard_pairingsDownSql,
+
+	"0023_keycard_pairings.up.sql": _0023_keycard_pairingsUpSql,
+
+	"doc.go": docGo,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -400,15 +1284,53 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"0001_app.down.sql":              &bintree{_0001_appDownSql, map[string]*bintree{}},
-	"0001_app.up.sql":                &bintree{_0001_appUpSql, map[string]*bintree{}},
-	"0002_tokens.down.sql":           &bintree{_0002_tokensDownSql, map[string]*bintree{}},
-	"0002_tokens.up.sql":             &bintree{_0002_tokensUpSql, map[string]*bintree{}},
-	"0003_settings.down.sql":         &bintree{_0003_settingsDownSql, map[string]*bintree{}},
-	"0003_settings.up.sql":           &bintree{_0003_settingsUpSql, map[string]*bintree{}},
-	"0004_pending_stickers.down.sql": &bintree{_0004_pending_stickersDownSql, map[string]*bintree{}},
-	"0004_pending_stickers.up.sql":   &bintree{_0004_pending_stickersUpSql, map[string]*bintree{}},
-	"doc.go":                         &bintree{docGo, map[string]*bintree{}},
+	"0001_app.down.sql":                  &bintree{_0001_appDownSql, map[string]*bintree{}},
+	"0001_app.up.sql":                    &bintree{_0001_appUpSql, map[string]*bintree{}},
+	"0002_tokens.down.sql":               &bintree{_0002_tokensDownSql, map[string]*bintree{}},
+	"0002_tokens.up.sql":                 &bintree{_0002_tokensUpSql, map[string]*bintree{}},
+	"0003_settings.down.sql":             &bintree{_0003_settingsDownSql, map[string]*bintree{}},
+	"0003_settings.up.sql":               &bintree{_0003_settingsUpSql, map[string]*bintree{}},
+	"0004_pending_stickers.down.sql":     &bintree{_0004_pending_stickersDownSql, map[string]*bintree{}},
+	"0004_pending_stickers.up.sql":       &bintree{_0004_pending_stickersUpSql, map[string]*bintree{}},
+	"0005_transfers_tx_hash.down.sql":    &bintree{_0005_transfers_tx_hashDownSql, map[string]*bintree{}},
+	"0005_transfers_tx_hash.up.sql":      &bintree{_0005_transfers_tx_hashUpSql, map[string]*bintree{}},
+	"0006_token_metadata.down.sql":       &bintree{_0006_token_metadataDownSql, map[string]*bintree{}},
+	"0006_token_metadata.up.sql":         &bintree{_0006_token_metadataUpSql, map[string]*bintree{}},
+	"0007_historical_prices.down.sql":    &bintree{_0007_historical_pricesDownSql, map[string]*bintree{}},
+	"0007_historical_prices.up.sql":      &bintree{_0007_historical_pricesUpSql, map[string]*bintree{}},
+	"0008_contract_abis.down.sql":        &bintree{_0008_contract_abisDownSql, map[string]*bintree{}},
+	"0008_contract_abis.up.sql":          &bintree{_0008_contract_abisUpSql, map[string]*bintree{}},
+	"0009_hardware_accounts.down.sql":    &bintree{_0009_hardware_accountsDownSql, map[string]*bintree{}},
+	"0009_hardware_accounts.up.sql":      &bintree{_0009_hardware_accountsUpSql, map[string]*bintree{}},
+	"0010_spending_limits.down.sql":      &bintree{_0010_spending_limitsDownSql, map[string]*bintree{}},
+	"0010_spending_limits.up.sql":        &bintree{_0010_spending_limitsUpSql, map[string]*bintree{}},
+	"0011_address_book.down.sql":         &bintree{_0011_address_bookDownSql, map[string]*bintree{}},
+	"0011_address_book.up.sql":           &bintree{_0011_address_bookUpSql, map[string]*bintree{}},
+	"0012_owned_tokens.down.sql":         &bintree{_0012_owned_tokensDownSql, map[string]*bintree{}},
+	"0012_owned_tokens.up.sql":           &bintree{_0012_owned_tokensUpSql, map[string]*bintree{}},
+	"0013_crypto_on_ramps.down.sql":      &bintree{_0013_crypto_on_rampsDownSql, map[string]*bintree{}},
+	"0013_crypto_on_ramps.up.sql":        &bintree{_0013_crypto_on_rampsUpSql, map[string]*bintree{}},
+	"0014_transfers_l2.down.sql":         &bintree{_0014_transfers_l2DownSql, map[string]*bintree{}},
+	"0014_transfers_l2.up.sql":           &bintree{_0014_transfers_l2UpSql, map[string]*bintree{}},
+	"0015_method_signatures.down.sql":    &bintree{_0015_method_signaturesDownSql, map[string]*bintree{}},
+	"0015_method_signatures.up.sql":      &bintree{_0015_method_signaturesUpSql, map[string]*bintree{}},
+	"0016_allowances.down.sql":           &bintree{_0016_allowancesDownSql, map[string]*bintree{}},
+	"0016_allowances.up.sql":             &bintree{_0016_allowancesUpSql, map[string]*bintree{}},
+	"0017_transfers_chart.down.sql":      &bintree{_0017_transfers_chartDownSql, map[string]*bintree{}},
+	"0017_transfers_chart.up.sql":        &bintree{_0017_transfers_chartUpSql, map[string]*bintree{}},
+	"0018_share_tokens.down.sql":         &bintree{_0018_share_tokensDownSql, map[string]*bintree{}},
+	"0018_share_tokens.up.sql":           &bintree{_0018_share_tokensUpSql, map[string]*bintree{}},
+	"0019_transfers_withdrawal.down.sql": &bintree{_0019_transfers_withdrawalDownSql, map[string]*bintree{}},
+	"0019_transfers_withdrawal.up.sql":   &bintree{_0019_transfers_withdrawalUpSql, map[string]*bintree{}},
+	"0020_transfer_notes.down.sql":       &bintree{_0020_transfer_notesDownSql, map[string]*bintree{}},
+	"0020_transfer_notes.up.sql":         &bintree{_0020_transfer_notesUpSql, map[string]*bintree{}},
+	"0021_nft_metadata.down.sql":         &bintree{_0021_nft_metadataDownSql, map[string]*bintree{}},
+	"0021_nft_metadata.up.sql":           &bintree{_0021_nft_metadataUpSql, map[string]*bintree{}},
+	"0022_local_settings.down.sql":       &bintree{_0022_local_settingsDownSql, map[string]*bintree{}},
+	"0022_local_settings.up.sql":         &bintree{_0022_local_settingsUpSql, map[string]*bintree{}},
+	"0023_keycard_pairings.down.sql":     &bintree{_0023_keycard_pairingsDownSql, map[string]*bintree{}},
+	"0023_keycard_pairings.up.sql":       &bintree{_0023_keycard_pairingsUpSql, map[string]*bintree{}},
+	"doc.go":                             &bintree{docGo, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
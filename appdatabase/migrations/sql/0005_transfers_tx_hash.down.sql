@@ -0,0 +1 @@
+DROP INDEX idx_transfers_tx_hash;
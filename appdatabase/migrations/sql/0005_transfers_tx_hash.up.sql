@@ -0,0 +1,3 @@
+ALTER TABLE transfers ADD COLUMN tx_hash VARCHAR;
+UPDATE transfers SET tx_hash = hash WHERE type = 'eth';
+CREATE INDEX idx_transfers_tx_hash ON transfers (tx_hash, network_id);
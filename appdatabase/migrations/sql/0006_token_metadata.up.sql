@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS token_metadata (
+  address VARCHAR NOT NULL,
+  network_id UNSIGNED BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  symbol VARCHAR NOT NULL,
+  decimals UNSIGNED INT NOT NULL,
+  PRIMARY KEY (address, network_id)
+) WITHOUT ROWID;
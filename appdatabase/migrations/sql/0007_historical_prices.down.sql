@@ -0,0 +1 @@
+DROP TABLE historical_prices;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS historical_prices (
+  symbol VARCHAR NOT NULL,
+  currency VARCHAR NOT NULL,
+  day UNSIGNED BIGINT NOT NULL,
+  price REAL NOT NULL,
+  PRIMARY KEY (symbol, currency, day)
+) WITHOUT ROWID;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS contract_abis (
+  address VARCHAR NOT NULL,
+  network_id UNSIGNED BIGINT NOT NULL,
+  abi TEXT NOT NULL,
+  PRIMARY KEY (address, network_id)
+) WITHOUT ROWID;
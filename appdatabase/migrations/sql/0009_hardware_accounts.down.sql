@@ -0,0 +1 @@
+DROP TABLE hardware_accounts;
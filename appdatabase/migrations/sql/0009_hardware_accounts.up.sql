@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS hardware_accounts (
+  address VARCHAR PRIMARY KEY NOT NULL,
+  wallet VARCHAR NOT NULL,
+  path VARCHAR NOT NULL
+) WITHOUT ROWID;
@@ -0,0 +1 @@
+DROP TABLE spending_limits;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS spending_limits (
+  network_id UNSIGNED BIGINT NOT NULL,
+  address VARCHAR NOT NULL,
+  token VARCHAR NOT NULL,
+  daily VARCHAR,
+  weekly VARCHAR,
+  PRIMARY KEY (network_id, address, token)
+) WITHOUT ROWID;
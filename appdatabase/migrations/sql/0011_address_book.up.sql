@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS address_book (
+  network_id UNSIGNED BIGINT NOT NULL,
+  address VARCHAR NOT NULL,
+  name VARCHAR NOT NULL,
+  ens_name VARCHAR,
+  ens_address VARCHAR,
+  resolved_at UNSIGNED BIGINT,
+  PRIMARY KEY (network_id, address)
+) WITHOUT ROWID;
@@ -0,0 +1 @@
+DROP TABLE owned_tokens;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS owned_tokens (
+  network_id UNSIGNED BIGINT NOT NULL,
+  address VARCHAR NOT NULL,
+  token VARCHAR NOT NULL,
+  balance VARCHAR NOT NULL,
+  checked_at UNSIGNED BIGINT NOT NULL,
+  PRIMARY KEY (network_id, address, token)
+) WITHOUT ROWID;
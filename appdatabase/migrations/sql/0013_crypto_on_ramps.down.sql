@@ -0,0 +1 @@
+DROP TABLE crypto_on_ramps;
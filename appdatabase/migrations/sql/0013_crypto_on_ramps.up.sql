@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS crypto_on_ramps (
+  name VARCHAR NOT NULL PRIMARY KEY,
+  position UNSIGNED INT NOT NULL,
+  descriptor BLOB NOT NULL,
+  fetched_at UNSIGNED BIGINT NOT NULL
+) WITHOUT ROWID;
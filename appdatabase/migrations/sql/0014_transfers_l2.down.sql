@@ -0,0 +1 @@
+DROP INDEX idx_transfers_bridge_message_hash;
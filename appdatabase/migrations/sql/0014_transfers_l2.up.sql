@@ -0,0 +1,3 @@
+ALTER TABLE transfers ADD COLUMN l2_fee BLOB;
+ALTER TABLE transfers ADD COLUMN bridge_message_hash VARCHAR;
+CREATE INDEX idx_transfers_bridge_message_hash ON transfers (bridge_message_hash);
@@ -0,0 +1 @@
+DROP TABLE method_signatures;
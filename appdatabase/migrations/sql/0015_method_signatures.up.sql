@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS method_signatures (
+  selector VARCHAR NOT NULL,
+  signature TEXT NOT NULL,
+  PRIMARY KEY (selector, signature)
+) WITHOUT ROWID;
@@ -0,0 +1,2 @@
+DROP TABLE allowances;
+DROP TABLE allowances_scanned;
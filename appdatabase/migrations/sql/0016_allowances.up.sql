@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS allowances (
+  network_id UNSIGNED BIGINT NOT NULL,
+  owner VARCHAR NOT NULL,
+  token VARCHAR NOT NULL,
+  spender VARCHAR NOT NULL,
+  value VARCHAR NOT NULL,
+  blk_number BIGINT NOT NULL,
+  PRIMARY KEY (network_id, owner, token, spender)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS allowances_scanned (
+  network_id UNSIGNED BIGINT NOT NULL,
+  owner VARCHAR NOT NULL,
+  blk_number BIGINT NOT NULL,
+  PRIMARY KEY (network_id, owner)
+) WITHOUT ROWID;
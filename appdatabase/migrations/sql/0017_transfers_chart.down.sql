@@ -0,0 +1 @@
+DROP INDEX idx_transfers_chart;
@@ -0,0 +1,4 @@
+ALTER TABLE transfers ADD COLUMN token VARCHAR;
+ALTER TABLE transfers ADD COLUMN amount_in REAL;
+ALTER TABLE transfers ADD COLUMN amount_out REAL;
+CREATE INDEX idx_transfers_chart ON transfers (network_id, address, timestamp);
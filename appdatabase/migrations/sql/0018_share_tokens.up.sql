@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS share_tokens (
+  address VARCHAR NOT NULL,
+  network_id UNSIGNED BIGINT NOT NULL,
+  kind VARCHAR NOT NULL,
+  PRIMARY KEY (address, network_id)
+) WITHOUT ROWID;
@@ -0,0 +1 @@
+ALTER TABLE transfers ADD COLUMN withdrawal BLOB;
@@ -0,0 +1,2 @@
+DROP TABLE transfer_tags;
+DROP TABLE transfer_notes;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS transfer_notes (
+  network_id UNSIGNED BIGINT NOT NULL,
+  hash VARCHAR NOT NULL,
+  note TEXT NOT NULL,
+  PRIMARY KEY (network_id, hash)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS transfer_tags (
+  network_id UNSIGNED BIGINT NOT NULL,
+  hash VARCHAR NOT NULL,
+  tag VARCHAR NOT NULL,
+  PRIMARY KEY (network_id, hash, tag)
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS idx_transfer_tags_tag ON transfer_tags (network_id, tag);
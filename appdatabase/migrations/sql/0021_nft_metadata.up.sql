@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS nft_metadata (
+  network_id UNSIGNED BIGINT NOT NULL,
+  contract VARCHAR NOT NULL,
+  token_id VARCHAR NOT NULL,
+  token_uri VARCHAR NOT NULL,
+  status VARCHAR NOT NULL,
+  metadata BLOB,
+  error VARCHAR NOT NULL DEFAULT '',
+  attempts INT NOT NULL DEFAULT 0,
+  fetched_at UNSIGNED BIGINT NOT NULL,
+  PRIMARY KEY (network_id, contract, token_id)
+) WITHOUT ROWID;
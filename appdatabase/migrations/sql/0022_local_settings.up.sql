@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS local_settings (
+  service VARCHAR NOT NULL,
+  name VARCHAR NOT NULL,
+  value BLOB NOT NULL,
+  updated_at UNSIGNED BIGINT NOT NULL,
+  PRIMARY KEY (service, name)
+) WITHOUT ROWID;
@@ -0,0 +1,2 @@
+DROP TABLE keycard_accounts;
+DROP TABLE keycard_pairings;
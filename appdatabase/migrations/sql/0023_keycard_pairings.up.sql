@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS keycard_pairings (
+  instance_uid VARCHAR PRIMARY KEY NOT NULL,
+  pairing_key BLOB NOT NULL,
+  pairing_index UNSIGNED INT NOT NULL,
+  pin_retries UNSIGNED INT NOT NULL,
+  paired_at UNSIGNED BIGINT NOT NULL
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS keycard_accounts (
+  address VARCHAR PRIMARY KEY NOT NULL,
+  instance_uid VARCHAR NOT NULL,
+  path VARCHAR NOT NULL
+) WITHOUT ROWID;
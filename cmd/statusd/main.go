@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/okzk/sdnotify"
@@ -92,12 +93,15 @@ func main() {
 		opts = append(opts, params.WithMailserver())
 	}
 
-	config, err := params.NewNodeConfigWithDefaultsAndFiles(
-		*dataDir,
-		uint64(*networkID),
-		opts,
-		configFiles,
-	)
+	loadConfig := func() (*params.NodeConfig, error) {
+		return params.NewNodeConfigWithDefaultsAndFiles(
+			*dataDir,
+			uint64(*networkID),
+			opts,
+			configFiles,
+		)
+	}
+	config, err := loadConfig()
 	if err != nil {
 		printUsage()
 		logger.Error(err.Error())
@@ -152,6 +156,11 @@ func main() {
 	// handle interrupt signals
 	interruptCh := haltOnInterruptSignal(backend.StatusNode())
 
+	// reload mailserver config on SIGHUP
+	if config.WhisperConfig.EnableMailServer || config.WakuConfig.EnableMailServer {
+		go reloadMailServerOnHangupSignal(interruptCh, backend.StatusNode(), loadConfig)
+	}
+
 	// Start collecting metrics. Metrics can be enabled by providing `-metrics` flag
 	// or setting `gethmetrics.Enabled` to true during compilation time:
 	// https://github.com/status-im/go-ethereum/pull/76.
@@ -326,3 +335,35 @@ func haltOnInterruptSignal(statusNode *node.StatusNode) <-chan struct{} {
 	}()
 	return interruptCh
 }
+
+// reloadMailServerOnHangupSignal reads configuration files again on every hangup signal (SIGHUP)
+// and applies the mailserver section. Changes that require a restart are logged and ignored.
+func reloadMailServerOnHangupSignal(interruptCh <-chan struct{}, statusNode *node.StatusNode, loadConfig func() (*params.NodeConfig, error)) {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGHUP)
+	defer signal.Stop(signalCh)
+	for {
+		select {
+		case <-interruptCh:
+			return
+		case <-signalCh:
+		}
+		logger.Info("Got hangup, reloading mailserver config...")
+		config, err := loadConfig()
+		if err != nil {
+			logger.Error("Failed to load config", "error", err)
+			continue
+		}
+		service, err := statusNode.MailServerService()
+		if err != nil {
+			logger.Error("Failed to get mailserver service", "error", err)
+			continue
+		}
+		changes, err := service.Reload(config)
+		if err != nil {
+			logger.Error("Failed to reload mailserver config", "error", err)
+			continue
+		}
+		logger.Info("Mailserver config reloaded", "changes", changes)
+	}
+}
@@ -27,6 +27,8 @@ const (
 	TopicHistoryBucket
 	// HistoryRequestBucket isolated bucket for storing list of pending requests.
 	HistoryRequestBucket
+	// ManagedPeers is a list of static peers and trusted mailservers added at runtime.
+	ManagedPeers
 )
 
 // NewMemoryDB returns leveldb with memory backend prefixed with a bucket.
@@ -160,3 +162,8 @@ func (iter NamespaceIterator) Prev() bool {
 func (iter NamespaceIterator) Next() bool {
 	return iter.iter.Next()
 }
+
+// Release releases resources of the iterator.
+func (iter NamespaceIterator) Release() {
+	iter.iter.Release()
+}
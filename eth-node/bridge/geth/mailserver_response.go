@@ -16,6 +16,7 @@ func NewWhisperMailServerResponseWrapper(mailServerResponse *whisper.MailServerR
 		LastEnvelopeHash: types.Hash(mailServerResponse.LastEnvelopeHash),
 		Cursor:           mailServerResponse.Cursor,
 		Error:            mailServerResponse.Error,
+		Digest:           mailServerResponse.Digest,
 	}
 }
 
@@ -29,5 +30,6 @@ func NewWakuMailServerResponseWrapper(mailServerResponse *waku.MailServerRespons
 		LastEnvelopeHash: types.Hash(mailServerResponse.LastEnvelopeHash),
 		Cursor:           mailServerResponse.Cursor,
 		Error:            mailServerResponse.Error,
+		Digest:           mailServerResponse.Digest,
 	}
 }
@@ -169,6 +169,12 @@ func (w *gethWakuWrapper) RequestHistoricMessagesWithTimeout(peerID []byte, enve
 	return w.waku.RequestHistoricMessagesWithTimeout(peerID, envelope.Unwrap().(*waku.Envelope), timeout)
 }
 
+// RequestAcknowledgedHistoricMessagesWithTimeout sends a request for batches of envelopes
+// that are acknowledged until the request is completed or expired.
+func (w *gethWakuWrapper) RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope types.Envelope, timeout time.Duration) error {
+	return w.waku.RequestAcknowledgedHistoricMessagesWithTimeout(peerID, envelope.Unwrap().(*waku.Envelope), timeout)
+}
+
 type wakuFilterWrapper struct {
 	filter *waku.Filter
 	id     string
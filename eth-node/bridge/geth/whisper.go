@@ -174,6 +174,12 @@ func (w *gethWhisperWrapper) RequestHistoricMessagesWithTimeout(peerID []byte, e
 	return w.whisper.RequestHistoricMessagesWithTimeout(peerID, envelope.Unwrap().(*whisper.Envelope), timeout)
 }
 
+// RequestAcknowledgedHistoricMessagesWithTimeout sends a request for batches of envelopes
+// that are acknowledged until the request is completed or expired.
+func (w *gethWhisperWrapper) RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope types.Envelope, timeout time.Duration) error {
+	return w.whisper.RequestAcknowledgedHistoricMessagesWithTimeout(peerID, envelope.Unwrap().(*whisper.Envelope), timeout)
+}
+
 // SyncMessages can be sent between two Mail Servers and syncs envelopes between them.
 func (w *gethWhisperWrapper) SyncMessages(peerID []byte, req types.SyncMailRequest) error {
 	return w.whisper.SyncMessages(peerID, *GetGethSyncMailRequestFrom(&req))
@@ -425,6 +425,10 @@ func (w *nimbusWhisperWrapper) RequestHistoricMessagesWithTimeout(peerID []byte,
 	return errors.New("not implemented")
 }
 
+func (w *nimbusWhisperWrapper) RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope types.Envelope, timeout time.Duration) error {
+	return errors.New("not implemented")
+}
+
 // SyncMessages can be sent between two Mail Servers and syncs envelopes between them.
 func (w *nimbusWhisperWrapper) SyncMessages(peerID []byte, req types.SyncMailRequest) error {
 	return errors.New("not implemented")
@@ -50,6 +50,8 @@ type MailServerResponse struct {
 	LastEnvelopeHash Hash
 	Cursor           []byte
 	Error            error
+	// Digest contains keys of envelopes returned for a digest request.
+	Digest [][]byte
 }
 
 // SyncMailRequest contains details which envelopes should be synced
@@ -47,6 +47,10 @@ type Waku interface {
 	// The whisper protocol is agnostic of the format and contents of envelope.
 	// A timeout of 0 never expires.
 	RequestHistoricMessagesWithTimeout(peerID []byte, envelope Envelope, timeout time.Duration) error
+	// RequestAcknowledgedHistoricMessagesWithTimeout sends a request that asks the mail server
+	// to deliver batches of envelopes that are acknowledged. Batches are acknowledged
+	// until the request is completed or expired.
+	RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope Envelope, timeout time.Duration) error
 	// SendMessagesRequest sends a MessagesRequest. This is an equivalent to RequestHistoricMessages
 	// in terms of the functionality.
 	SendMessagesRequest(peerID []byte, request MessagesRequest) error
@@ -184,6 +184,16 @@ func CallRPC(inputJSON *C.char) *C.char {
 	return C.CString(outputJSON)
 }
 
+//CallDappRPC calls public APIs via RPC on behalf of a dapp with the given origin
+//export CallDappRPC
+func CallDappRPC(origin *C.char, inputJSON *C.char) *C.char {
+	outputJSON, err := statusBackend.CallDappRPC(C.GoString(origin), C.GoString(inputJSON))
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	return C.CString(outputJSON)
+}
+
 //CallPrivateRPC calls both public and private APIs via RPC
 //export CallPrivateRPC
 func CallPrivateRPC(inputJSON *C.char) *C.char {
@@ -408,6 +418,26 @@ func Logout() *C.char {
 	return makeJSONResponse(statusBackend.StopNode())
 }
 
+// SwitchAccount logs out the current account and logs in another one, the node is restarted.
+// Only one account is open at a time.
+//export SwitchAccount
+func SwitchAccount(accountData, password *C.char) *C.char {
+	data, pass := C.GoString(accountData), C.GoString(password)
+	var account multiaccounts.Account
+	err := json.Unmarshal([]byte(data), &account)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	api.RunAsync(func() error { return statusBackend.SwitchAccount(account, pass) })
+	return makeJSONResponse(nil)
+}
+
+// DeleteMultiaccount deletes an account that is not logged in, its keys and its database.
+//export DeleteMultiaccount
+func DeleteMultiaccount(keyUID, password *C.char) *C.char {
+	return makeJSONResponse(statusBackend.DeleteMultiaccount(C.GoString(keyUID), C.GoString(password)))
+}
+
 // SignMessage unmarshals rpc params {data, address, password} and passes
 // them onto backend.SignMessage
 //export SignMessage
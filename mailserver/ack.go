@@ -0,0 +1,164 @@
+package mailserver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+const (
+	// defaultAckBatchSize is a number of envelopes in a batch sent to clients that acknowledge batches.
+	defaultAckBatchSize = 100
+	// defaultAckTimeout is how long an acknowledgement of a batch is awaited.
+	defaultAckTimeout = 30 * time.Second
+)
+
+var errAckTimeout = errors.New("batch was not acknowledged in time")
+
+type ackKey struct {
+	peer  types.Hash
+	batch types.Hash
+}
+
+// ackTracker notifies deliveries about batches acknowledged by peers.
+type ackTracker struct {
+	mu      sync.Mutex
+	waiting map[ackKey]chan struct{}
+}
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{waiting: make(map[ackKey]chan struct{})}
+}
+
+// Expect returns a channel that is closed when the peer acknowledges the batch.
+// It must be called before the batch is sent.
+func (t *ackTracker) Expect(peer, batch types.Hash) <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := ackKey{peer, batch}
+	ch, exist := t.waiting[key]
+	if !exist {
+		ch = make(chan struct{})
+		t.waiting[key] = ch
+	}
+	return ch
+}
+
+// Acknowledged is called when a peer acknowledges a batch. Acknowledgements
+// of batches that are not expected are ignored.
+func (t *ackTracker) Acknowledged(peer, batch types.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := ackKey{peer, batch}
+	if ch, exist := t.waiting[key]; exist {
+		close(ch)
+		delete(t.waiting, key)
+	}
+}
+
+// Forget stops waiting for an acknowledgement of the batch.
+func (t *ackTracker) Forget(peer, batch types.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.waiting, ackKey{peer, batch})
+}
+
+type ackBatch struct {
+	cursor           []byte
+	lastEnvelopeHash types.Hash
+	hash             types.Hash
+	ack              <-chan struct{}
+}
+
+// ackWindow limits the number of batches of a request that are sent to a peer
+// before they are acknowledged. It keeps the cursor of the last acknowledged batch,
+// so that the request can be resumed after it if the peer stops acknowledging batches.
+type ackWindow struct {
+	tracker   *ackTracker
+	quit      <-chan struct{}
+	peerID    types.Hash
+	size      int
+	batchSize int
+	timeout   time.Duration
+
+	// queued are batches queued for sending, in order
+	mu     sync.Mutex
+	queued []ackBatch
+
+	// pending are batches sent and not acknowledged yet, used only by the sender
+	pending []ackBatch
+	// cursor and lastEnvelopeHash of the last acknowledged batch
+	cursor           []byte
+	lastEnvelopeHash types.Hash
+}
+
+func newAckWindow(tracker *ackTracker, quit <-chan struct{}, peerID types.Hash, size, batchSize int, timeout time.Duration) *ackWindow {
+	if batchSize <= 0 {
+		batchSize = defaultAckBatchSize
+	}
+	if timeout <= 0 {
+		timeout = defaultAckTimeout
+	}
+	return &ackWindow{
+		tracker:   tracker,
+		quit:      quit,
+		peerID:    peerID,
+		size:      size,
+		batchSize: batchSize,
+		timeout:   timeout,
+	}
+}
+
+// Queued records the cursor and the hash of the last envelope of a batch queued for sending.
+func (w *ackWindow) Queued(cursor []byte, lastEnvelopeHash types.Hash) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queued = append(w.queued, ackBatch{
+		cursor:           append([]byte(nil), cursor...),
+		lastEnvelopeHash: lastEnvelopeHash,
+	})
+}
+
+// Expect starts waiting for an acknowledgement of the next queued batch, hash is the hash
+// of the message the batch is sent in.
+func (w *ackWindow) Expect(hash types.Hash) {
+	w.mu.Lock()
+	batch := w.queued[0]
+	w.queued = w.queued[1:]
+	w.mu.Unlock()
+	batch.hash = hash
+	batch.ack = w.tracker.Expect(w.peerID, hash)
+	w.pending = append(w.pending, batch)
+}
+
+// Wait blocks until at most max sent batches are not acknowledged.
+// It returns errAckTimeout if the oldest batch is not acknowledged in time
+// and errDeliveryCanceled if quit is closed.
+func (w *ackWindow) Wait(max int) error {
+	for len(w.pending) > max {
+		batch := w.pending[0]
+		select {
+		case <-batch.ack:
+		case <-time.After(w.timeout):
+			ackTimeoutsCounter.Inc()
+			return errAckTimeout
+		case <-w.quit:
+			return errDeliveryCanceled
+		}
+		acknowledgedBatchesCounter.Inc()
+		w.cursor = batch.cursor
+		w.lastEnvelopeHash = batch.lastEnvelopeHash
+		w.pending = w.pending[1:]
+	}
+	return nil
+}
+
+// Close stops waiting for acknowledgements of pending batches.
+func (w *ackWindow) Close() {
+	for _, batch := range w.pending {
+		w.tracker.Forget(w.peerID, batch.hash)
+	}
+	w.pending = nil
+}
@@ -0,0 +1,233 @@
+package mailserver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/params"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+// ackTestService acknowledges the first acked batches it receives.
+type ackTestService struct {
+	digestTestService
+
+	ms      *mailServer
+	acked   int
+	batches int
+}
+
+func (s *ackTestService) SendRawP2PDirect(peerID []byte, envelopes ...rlp.RawValue) error {
+	hash, err := s.ms.adapter.P2PBatchHash(envelopes)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.batches++
+	ack := s.batches <= s.acked
+	s.mu.Unlock()
+	if ack {
+		s.ms.acks.Acknowledged(types.BytesToHash(peerID), hash)
+	}
+	return s.digestTestService.SendRawP2PDirect(peerID, envelopes...)
+}
+
+func (s *ackTestService) envelopeHashes(t *testing.T) []types.Hash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var hashes []types.Hash
+	for _, raw := range s.envelopes {
+		var env whisper.Envelope
+		require.NoError(t, rlp.DecodeBytes(raw, &env))
+		hashes = append(hashes, types.Hash(env.Hash()))
+	}
+	s.envelopes = nil
+	return hashes
+}
+
+func TestAckTracker(t *testing.T) {
+	tracker := newAckTracker()
+	peer, batch := types.Hash{1}, types.Hash{2}
+
+	// not expected acknowledgements are ignored
+	tracker.Acknowledged(peer, batch)
+	ack := tracker.Expect(peer, batch)
+	tracker.Acknowledged(types.Hash{3}, batch)
+	select {
+	case <-ack:
+		require.FailNow(t, "acknowledged by another peer")
+	default:
+	}
+	tracker.Acknowledged(peer, batch)
+	<-ack
+	require.Empty(t, tracker.waiting)
+
+	tracker.Expect(peer, batch)
+	tracker.Forget(peer, batch)
+	require.Empty(t, tracker.waiting)
+}
+
+func TestDeliverMailWithAcknowledgedBatches(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	server.ms.acks = newAckTracker()
+	server.ms.ackBatchSize = 2
+	server.ms.ackTimeout = int64(50 * time.Millisecond)
+	service := &ackTestService{ms: server.ms, acked: 100}
+	server.ms.service = service
+
+	now := time.Now()
+	archived := make(map[types.Hash]bool)
+	for i := 5; i > 0; i-- {
+		env := archiveEnvelope(t, now.Add(-time.Duration(i)*time.Second), server)
+		archived[types.Hash(env.Hash())] = true
+	}
+
+	req := MessagesRequestPayload{
+		Lower:     uint32(now.Add(-time.Minute).Unix()),
+		Upper:     uint32(now.Add(time.Minute).Unix()),
+		Bloom:     types.MakeFullNodeBloom(),
+		Limit:     10,
+		Batch:     true,
+		AckWindow: 1,
+	}
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, req)
+	server.ms.deliveries.Wait()
+	resp := service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.Nil(t, resp.Cursor)
+	require.Equal(t, 3, service.batches)
+	require.Len(t, service.envelopeHashes(t), 5)
+
+	// only the first batch is acknowledged, the request is resumed after it
+	service.acked, service.batches = 1, 0
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{3}, req)
+	server.ms.deliveries.Wait()
+	resp = service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.NotNil(t, resp.Cursor)
+	first := service.envelopeHashes(t)
+	require.Equal(t, first[1], types.Hash(resp.LastEnvelopeHash))
+
+	service.acked, service.batches = 100, 0
+	req.Cursor = resp.Cursor
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{4}, req)
+	server.ms.deliveries.Wait()
+	resp = service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.Nil(t, resp.Cursor)
+	rest := service.envelopeHashes(t)
+	require.Len(t, rest, 3)
+	for _, hash := range append(first[:2], rest...) {
+		require.True(t, archived[hash])
+		delete(archived, hash)
+	}
+	require.Empty(t, archived)
+
+	// no batch is acknowledged
+	service.acked = 0
+	req.Cursor = nil
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{5}, req)
+	server.ms.deliveries.Wait()
+	resp = service.lastResponse(t)
+	require.Error(t, resp.Error)
+	require.Empty(t, server.ms.acks.waiting)
+}
+
+// TestAcknowledgedBatchesOverPeerConnection delivers acknowledged batches to a whisper client
+// connected to the mail server, acknowledgements are read by the same loop that reads requests.
+func TestAcknowledgedBatchesOverPeerConnection(t *testing.T) {
+	const password = "password_for_this_test"
+	dataDir, err := ioutil.TempDir("", "mailserver-ack-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	shh := whisper.New(&whisper.DefaultConfig)
+	server := &WhisperMailServer{}
+	shh.RegisterMailServer(server)
+	require.NoError(t, server.Init(shh, &params.WhisperConfig{
+		DataDir:                dataDir,
+		MailServerPassword:     password,
+		MailServerAckTimeout:   5,
+		MailServerAckBatchSize: 2,
+	}))
+	defer server.Close()
+
+	client := whisper.New(&whisper.DefaultConfig)
+	require.NoError(t, client.Start(nil))
+	defer func() { require.NoError(t, client.Stop()) }()
+
+	serverID, clientID := enode.ID{1}, enode.ID{2}
+	serverRW, clientRW := p2p.MsgPipe()
+	defer func() {
+		serverRW.Close()
+		clientRW.Close()
+	}()
+	go func() {
+		_ = shh.HandlePeer(p2p.NewPeer(clientID, "client", []p2p.Cap{{Name: "shh", Version: 6}}), serverRW)
+	}()
+	go func() {
+		_ = client.HandlePeer(p2p.NewPeer(serverID, "server", []p2p.Cap{{Name: "shh", Version: 6}}), clientRW)
+	}()
+
+	now := time.Now()
+	for i := 5; i > 0; i-- {
+		env, err := generateEnvelope(now.Add(-time.Duration(i) * time.Second))
+		require.NoError(t, err)
+		server.Archive(env)
+	}
+
+	payload, err := rlp.EncodeToBytes(MessagesRequestPayload{
+		Lower:     uint32(now.Add(-time.Minute).Unix()),
+		Upper:     uint32(now.Add(time.Minute).Unix()),
+		Bloom:     types.MakeFullNodeBloom(),
+		Limit:     10,
+		Batch:     true,
+		AckWindow: 1,
+	})
+	require.NoError(t, err)
+	keyID, err := client.AddSymKeyFromPassword(password)
+	require.NoError(t, err)
+	key, err := client.GetSymKey(keyID)
+	require.NoError(t, err)
+	src, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	messageParams := &whisper.MessageParams{KeySym: key, Topic: whisper.TopicType{1}, Payload: payload, Src: src, WorkTime: 1}
+	message, err := whisper.NewSentMessage(messageParams)
+	require.NoError(t, err)
+	request, err := message.Wrap(messageParams, now)
+	require.NoError(t, err)
+
+	events := make(chan whisper.EnvelopeEvent, 100)
+	sub := client.SubscribeEnvelopeEvents(events)
+	defer sub.Unsubscribe()
+	require.Eventually(t, func() bool {
+		return client.RequestAcknowledgedHistoricMessagesWithTimeout(serverID[:], request, time.Minute) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	timeout := time.After(4 * time.Second)
+	for {
+		select {
+		case ev := <-events:
+			if ev.Event != whisper.EventMailServerRequestCompleted || ev.Hash != request.Hash() {
+				continue
+			}
+			resp := ev.Data.(*whisper.MailServerResponse)
+			require.NoError(t, resp.Error)
+			require.Nil(t, resp.Cursor)
+			return
+		case <-timeout:
+			require.FailNow(t, "request was not completed before batches timed out")
+		}
+	}
+}
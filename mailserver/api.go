@@ -0,0 +1,251 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrMailServerNotInitialized is returned when the mailserver is not initialized.
+	ErrMailServerNotInitialized = errors.New("mailserver is not initialized")
+	// ErrInvalidTopicsNumber is returned when the number of requested topics is not positive.
+	ErrInvalidTopicsNumber = errors.New("number of topics must be positive")
+	// ErrInvalidSourcesNumber is returned when the number of requested sources is not positive.
+	ErrInvalidSourcesNumber = errors.New("number of sources must be positive")
+	// ErrSoftDeleteDisabled is returned when restoring pruned envelopes without a soft delete window.
+	ErrSoftDeleteDisabled = errors.New("soft delete of pruned envelopes is disabled")
+	// ErrDiskUsageUnsupported is returned when the size on disk is requested from a database other than LevelDB.
+	ErrDiskUsageUnsupported = errors.New("disk usage is reported only by LevelDB archives")
+	// ErrInvalidDiskUsageBuckets is returned when the bucket length is not positive or there are too many buckets.
+	ErrInvalidDiskUsageBuckets = errors.New("invalid number of disk usage buckets")
+	// ErrInvalidCoverageRange is returned when the start of the requested range is after its end.
+	ErrInvalidCoverageRange = errors.New("from must not be after to")
+)
+
+// maxDiskUsageBuckets limits the number of buckets returned by DiskUsage.
+const maxDiskUsageBuckets = 1000
+
+// PublicAPI is an operator API of the mailserver.
+type PublicAPI struct {
+	provider serverProvider
+}
+
+// NewPublicAPI returns instance of the mailserver API.
+func NewPublicAPI(provider serverProvider) *PublicAPI {
+	return &PublicAPI{provider: provider}
+}
+
+func (api *PublicAPI) server() (*mailServer, error) {
+	return serverFrom(api.provider)
+}
+
+func serverFrom(provider serverProvider) (*mailServer, error) {
+	s := provider.server()
+	if s == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	return s, nil
+}
+
+// GetTopTopics returns n topics with the highest number of envelopes
+// received during the window specified in seconds.
+func (api *PublicAPI) GetTopTopics(ctx context.Context, n int, window uint32) ([]TopicStats, error) {
+	if n <= 0 {
+		return nil, ErrInvalidTopicsNumber
+	}
+	s, err := api.server()
+	if err != nil {
+		return nil, err
+	}
+	if s.topicStats == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	return s.topicStats.TopTopics(n, time.Now().Add(-time.Duration(window)*time.Second))
+}
+
+// GetCoverageGaps returns time ranges, overlapping the range between two unix timestamps,
+// during which envelopes were not archived because the mailserver was down or the database
+// was unavailable. An ongoing gap is returned too.
+func (api *PublicAPI) GetCoverageGaps(ctx context.Context, from, to uint32) ([]CoverageGap, error) {
+	if from > to {
+		return nil, ErrInvalidCoverageRange
+	}
+	s, err := api.server()
+	if err != nil {
+		return nil, err
+	}
+	if s.coverage == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	return s.coverage.Gaps(from, to)
+}
+
+// AdminAPI is a privileged operator API of the mailserver, it is not exposed publicly.
+type AdminAPI struct {
+	provider serverProvider
+}
+
+// NewAdminAPI returns instance of the mailserver admin API.
+func NewAdminAPI(provider serverProvider) *AdminAPI {
+	return &AdminAPI{provider: provider}
+}
+
+// PruneEstimate returns the number and the total size of envelopes
+// that would be removed by pruning envelopes older than a given timestamp.
+func (api *AdminAPI) PruneEstimate(ctx context.Context, timestamp uint32) (PruneStats, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return PruneStats{}, err
+	}
+	if s.db == nil {
+		return PruneStats{}, ErrMailServerNotInitialized
+	}
+	return s.db.PruneEstimate(time.Unix(int64(timestamp), 0))
+}
+
+// ArchiveStats returns the number and the total size of archived envelopes together with
+// keys of the oldest and the newest ones. Unlike metrics the stats are kept across restarts.
+func (api *AdminAPI) ArchiveStats(ctx context.Context) (ArchiveStats, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return ArchiveStats{}, err
+	}
+	if s.db == nil {
+		return ArchiveStats{}, ErrMailServerNotInitialized
+	}
+	return s.db.ArchiveStats()
+}
+
+// DiskUsage returns the approximate size on disk of envelopes sent between two timestamps
+// in buckets with the length specified in seconds. Only LevelDB archives are supported.
+func (api *AdminAPI) DiskUsage(ctx context.Context, from, to, bucket uint32) ([]BucketDiskUsage, error) {
+	if bucket == 0 || to <= from || (to-from)/bucket >= maxDiskUsageBuckets {
+		return nil, ErrInvalidDiskUsageBuckets
+	}
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return nil, err
+	}
+	if s.db == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	db, ok := s.db.(compactor)
+	if !ok {
+		return nil, ErrDiskUsageUnsupported
+	}
+	return db.DiskUsage(time.Unix(int64(from), 0), time.Unix(int64(to), 0), time.Duration(bucket)*time.Second)
+}
+
+// GetTopSources returns n peers that delivered the highest number of archived envelopes
+// sent during the window specified in seconds.
+func (api *AdminAPI) GetTopSources(ctx context.Context, n int, window uint32) ([]EnvelopeSource, error) {
+	if n <= 0 {
+		return nil, ErrInvalidSourcesNumber
+	}
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return nil, err
+	}
+	if s.envelopeSources == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	return s.envelopeSources.TopSources(n, time.Now().Add(-time.Duration(window)*time.Second))
+}
+
+// CompressionStats returns sizes of compressed responses sent to recently served peers
+// before and after the compression. Peers that saved the most bytes are first.
+func (api *AdminAPI) CompressionStats(ctx context.Context) ([]PeerCompressionStats, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return nil, err
+	}
+	if s.compression == nil {
+		return nil, ErrMailServerNotInitialized
+	}
+	return s.compression.Stats(), nil
+}
+
+// IndexBuildProgress returns states of background index builds. The list is empty
+// if building indexes is disabled.
+func (api *AdminAPI) IndexBuildProgress(ctx context.Context) ([]IndexBuildProgress, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return nil, err
+	}
+	if s.indexBuilder == nil {
+		return []IndexBuildProgress{}, nil
+	}
+	return s.indexBuilder.Progress(), nil
+}
+
+// ReadOnlyStatus is the state of the read-only mode.
+type ReadOnlyStatus struct {
+	ReadOnly bool `json:"readOnly"`
+	// Spooled is the number of envelopes in the spill queue, zero if the queue is disabled.
+	Spooled int `json:"spooled"`
+}
+
+// SetReadOnly enables or disables the read-only mode for a maintenance of the database.
+// Requests are served in the mode, but envelopes are not archived and not pruned.
+// Envelopes are queued if the spill queue is enabled and moved to the database once
+// the mode is disabled, otherwise they are dropped. The mode is kept until it's changed
+// again, also when a config without changes of MailServerReadOnly is reloaded.
+func (api *AdminAPI) SetReadOnly(ctx context.Context, readOnly bool) (ReadOnlyStatus, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return ReadOnlyStatus{}, err
+	}
+	s.setReadOnly(readOnly)
+	return s.readOnlyStatus(), nil
+}
+
+// GetReadOnlyStatus returns the state of the read-only mode.
+func (api *AdminAPI) GetReadOnlyStatus(ctx context.Context) (ReadOnlyStatus, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return ReadOnlyStatus{}, err
+	}
+	return s.readOnlyStatus(), nil
+}
+
+// RestorePruned restores envelopes pruned at or after a given timestamp and returns
+// how many have been restored. Only envelopes pruned within the soft delete window can be restored.
+func (api *AdminAPI) RestorePruned(ctx context.Context, timestamp uint32) (int, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return 0, err
+	}
+	if s.softDeleteWindow() == 0 {
+		return 0, ErrSoftDeleteDisabled
+	}
+	restored, err := s.db.Restore(time.Unix(int64(timestamp), 0))
+	if s.queryCache != nil && restored > 0 {
+		s.queryCache.Restored()
+	}
+	return restored, err
+}
+
+// SchemaVersion returns the version of the database schema and the latest version supported
+// by the mailserver. The schema is newer than expected if the database was migrated by a newer
+// version of the mailserver sharing it.
+func (api *AdminAPI) SchemaVersion(ctx context.Context) (SchemaVersion, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return SchemaVersion{}, err
+	}
+	return s.db.SchemaVersion()
+}
+
+// DBHealth returns health statuses of postgres connection pools, one for every shard.
+// The list is empty if envelopes are stored in LevelDB.
+func (api *AdminAPI) DBHealth(ctx context.Context) ([]PostgresHealth, error) {
+	s, err := serverFrom(api.provider)
+	if err != nil {
+		return nil, err
+	}
+	if s.postgresHealth == nil {
+		return []PostgresHealth{}, nil
+	}
+	return s.postgresHealth.PostgresHealth(), nil
+}
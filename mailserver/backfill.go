@@ -0,0 +1,200 @@
+package mailserver
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	backfillCheckPeriod = time.Minute
+	// backfillSyncTimeout is how long the backfiller waits for a sync response page.
+	backfillSyncTimeout = time.Minute
+	// backfillOverlap is subtracted from the start of a gap to request envelopes
+	// with timestamps slightly before the gap that were received during it.
+	backfillOverlap = whisperTTLSafeThreshold
+)
+
+// backfiller requests envelopes sent during coverage gaps from peer mailservers.
+// Envelopes received in sync responses are archived by whisper and validated like any
+// other archived envelope. A gap is marked as healed once a peer sent all pages of
+// the response without an error. Peers are tried in turn, a peer that is not connected,
+// fails or times out is skipped until the next gap.
+type backfiller struct {
+	mu sync.Mutex
+
+	syncer    primaryPeer
+	peers     []types.Hash
+	coverage  *coverageTracker
+	paused    func() bool
+	retention time.Duration
+	now       func() time.Time
+
+	// next is the index of the peer that is tried first
+	next      int
+	syncing   bool
+	peer      types.Hash
+	gap       CoverageGap
+	request   whisper.SyncMailRequest
+	requested time.Time
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newBackfiller(syncer primaryPeer, peers []types.Hash, coverage *coverageTracker, paused func() bool, retention time.Duration) *backfiller {
+	return &backfiller{
+		syncer:    syncer,
+		peers:     peers,
+		coverage:  coverage,
+		paused:    paused,
+		retention: retention,
+		now:       time.Now,
+		period:    backfillCheckPeriod,
+	}
+}
+
+// Start starts a loop that backfills gaps one by one.
+func (b *backfiller) Start() {
+	b.cancel = make(chan struct{})
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		t := time.NewTicker(b.period)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				b.Check()
+			case <-b.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop.
+func (b *backfiller) Stop() {
+	if b.cancel == nil {
+		return
+	}
+	close(b.cancel)
+	b.wg.Wait()
+	b.cancel = nil
+}
+
+// Check requests the oldest gap that is not healed from a connected peer, unless a request is in progress.
+// Nothing is requested in the read-only mode, envelopes wouldn't be archived.
+func (b *backfiller) Check() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.paused != nil && b.paused() {
+		return
+	}
+	now := b.now()
+	if b.syncing {
+		if now.Sub(b.requested) < backfillSyncTimeout {
+			return
+		}
+		log.Warn("backfill from peer mailserver timed out", "peer", b.peer, "from", b.gap.From, "to", b.gap.To)
+		b.syncing = false
+		b.skipPeer()
+	}
+
+	var from uint32
+	if b.retention > 0 {
+		from = uint32(now.Add(-b.retention).Unix())
+	}
+	gaps, err := b.coverage.Gaps(from, uint32(now.Unix()))
+	if err != nil {
+		log.Error("failed to read coverage gaps", "err", err)
+		return
+	}
+	for _, gap := range gaps {
+		if gap.HealedAt != 0 || gap.Ongoing {
+			continue
+		}
+		peer, ok := b.connectedPeer()
+		if !ok {
+			log.Debug("no peer mailservers connected to backfill gaps")
+			return
+		}
+		lower := gap.From
+		if lower > backfillOverlap {
+			lower -= backfillOverlap
+		}
+		b.peer = peer
+		b.gap = gap
+		b.request = whisper.SyncMailRequest{
+			Lower: lower,
+			Upper: gap.To,
+			Bloom: types.MakeFullNodeBloom(),
+			Limit: whisper.MaxLimitInSyncMailRequest,
+		}
+		b.sendRequest()
+		return
+	}
+}
+
+// connectedPeer returns the first connected peer starting from the next one and marks it as trusted,
+// so that its sync responses are archived.
+func (b *backfiller) connectedPeer() (types.Hash, bool) {
+	for i := 0; i < len(b.peers); i++ {
+		peer := b.peers[(b.next+i)%len(b.peers)]
+		if err := b.syncer.AllowP2PMessagesFromPeer(peer.Bytes()); err == nil {
+			return peer, true
+		}
+	}
+	return types.Hash{}, false
+}
+
+func (b *backfiller) skipPeer() {
+	if len(b.peers) > 0 {
+		b.next = (b.next + 1) % len(b.peers)
+	}
+}
+
+// SyncFinished handles the last response to a sync request. If the response has a cursor,
+// the next page is requested, otherwise the gap is healed.
+func (b *backfiller) SyncFinished(peer types.Hash, cursor []byte, errMsg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.syncing || peer != b.peer {
+		return
+	}
+	b.syncing = false
+	if errMsg != "" {
+		log.Warn("backfill from peer mailserver failed", "peer", b.peer, "from", b.gap.From, "to", b.gap.To, "err", errMsg)
+		b.skipPeer()
+		return
+	}
+	if len(cursor) > 0 && !bytes.Equal(cursor, b.request.Cursor) {
+		b.request.Cursor = cursor
+		b.sendRequest()
+		return
+	}
+	if err := b.coverage.Healed(b.gap); err != nil {
+		log.Error("failed to mark coverage gap as healed", "from", b.gap.From, "to", b.gap.To, "err", err)
+		return
+	}
+	log.Info("backfilled coverage gap", "peer", b.peer, "from", b.gap.From, "to", b.gap.To)
+}
+
+func (b *backfiller) sendRequest() {
+	log.Info("backfilling from peer mailserver", "peer", b.peer, "lower", b.request.Lower, "upper", b.request.Upper)
+	if err := b.syncer.SyncMessages(b.peer.Bytes(), b.request); err != nil {
+		log.Warn("failed to send backfill request to peer mailserver", "peer", b.peer, "err", err)
+		b.skipPeer()
+		return
+	}
+	b.syncing = true
+	b.requested = b.now()
+}
@@ -0,0 +1,124 @@
+package mailserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+type backfillRequest struct {
+	peer    types.Hash
+	request whisper.SyncMailRequest
+}
+
+type backfillTestPeers struct {
+	connected map[types.Hash]bool
+	requests  []backfillRequest
+}
+
+func (p *backfillTestPeers) AllowP2PMessagesFromPeer(peerID []byte) error {
+	if !p.connected[types.BytesToHash(peerID)] {
+		return errors.New("peer not found")
+	}
+	return nil
+}
+
+func (p *backfillTestPeers) SyncMessages(peerID []byte, req whisper.SyncMailRequest) error {
+	p.requests = append(p.requests, backfillRequest{peer: types.BytesToHash(peerID), request: req})
+	return nil
+}
+
+func setupBackfiller(t *testing.T, peers *backfillTestPeers, ids []types.Hash) (*backfiller, *coverageTracker, *time.Time) {
+	server := setupTestServer(t)
+	now := time.Unix(1590000000, 0)
+	tracker := newCoverageTracker(server.ms.db)
+	tracker.now = func() time.Time { return now }
+	b := newBackfiller(peers, ids, tracker, nil, 24*time.Hour)
+	b.now = tracker.now
+	return b, tracker, &now
+}
+
+func TestBackfillerHealsGaps(t *testing.T) {
+	first, second := types.Hash{1}, types.Hash{2}
+	peers := &backfillTestPeers{connected: map[types.Hash]bool{second: true}}
+	b, tracker, now := setupBackfiller(t, peers, []types.Hash{first, second})
+
+	// no gaps
+	b.Check()
+	require.Empty(t, peers.requests)
+
+	old := CoverageGap{From: uint32(now.Add(-48 * time.Hour).Unix()), To: uint32(now.Add(-47 * time.Hour).Unix()), Reason: coverageGapDown}
+	gap := CoverageGap{From: uint32(now.Add(-2 * time.Hour).Unix()), To: uint32(now.Add(-time.Hour).Unix()), Reason: coverageGapDown}
+	require.NoError(t, tracker.db.SaveCoverageGap(old))
+	require.NoError(t, tracker.db.SaveCoverageGap(gap))
+
+	// gaps older than the retention are not requested, disconnected peers are skipped
+	b.Check()
+	require.Len(t, peers.requests, 1)
+	require.Equal(t, second, peers.requests[0].peer)
+	require.Equal(t, gap.From-backfillOverlap, peers.requests[0].request.Lower)
+	require.Equal(t, gap.To, peers.requests[0].request.Upper)
+
+	// request is in flight
+	b.Check()
+	require.Len(t, peers.requests, 1)
+
+	// next page is requested
+	b.SyncFinished(second, []byte{1}, "")
+	require.Len(t, peers.requests, 2)
+	require.Equal(t, []byte{1}, peers.requests[1].request.Cursor)
+
+	// responses from other peers are ignored
+	b.SyncFinished(first, nil, "")
+	b.SyncFinished(second, nil, "")
+	gaps, err := tracker.Gaps(gap.From, gap.To)
+	require.NoError(t, err)
+	require.Len(t, gaps, 1)
+	require.Equal(t, uint32(now.Unix()), gaps[0].HealedAt)
+
+	b.Check()
+	require.Len(t, peers.requests, 2)
+}
+
+func TestBackfillerSkipsFailedPeers(t *testing.T) {
+	first, second := types.Hash{1}, types.Hash{2}
+	peers := &backfillTestPeers{connected: map[types.Hash]bool{first: true, second: true}}
+	b, tracker, now := setupBackfiller(t, peers, []types.Hash{first, second})
+	gap := CoverageGap{From: uint32(now.Add(-2 * time.Hour).Unix()), To: uint32(now.Add(-time.Hour).Unix()), Reason: coverageGapUnavailable}
+	require.NoError(t, tracker.db.SaveCoverageGap(gap))
+
+	b.Check()
+	require.Len(t, peers.requests, 1)
+	require.Equal(t, first, peers.requests[0].peer)
+	b.SyncFinished(first, nil, "requests per seconds limit exceeded")
+
+	b.Check()
+	require.Len(t, peers.requests, 2)
+	require.Equal(t, second, peers.requests[1].peer)
+
+	// the request times out
+	*now = now.Add(backfillSyncTimeout)
+	b.Check()
+	require.Len(t, peers.requests, 3)
+	require.Equal(t, first, peers.requests[2].peer)
+
+	gaps, err := tracker.Gaps(gap.From, gap.To)
+	require.NoError(t, err)
+	require.Zero(t, gaps[0].HealedAt)
+}
+
+func TestBackfillerPausedInReadOnlyMode(t *testing.T) {
+	peer := types.Hash{1}
+	peers := &backfillTestPeers{connected: map[types.Hash]bool{peer: true}}
+	b, tracker, now := setupBackfiller(t, peers, []types.Hash{peer})
+	b.paused = func() bool { return true }
+	require.NoError(t, tracker.db.SaveCoverageGap(CoverageGap{From: uint32(now.Add(-time.Hour).Unix()), To: uint32(now.Unix()), Reason: coverageGapDown}))
+
+	b.Check()
+	require.Empty(t, peers.requests)
+}
@@ -0,0 +1,456 @@
+package mailserver
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// bloomIndexRecentBuckets is a number of recent buckets which blooms are kept in memory.
+const bloomIndexRecentBuckets = 24
+
+// bloomRange is a range of envelope timestamps, inclusive.
+type bloomRange struct {
+	lower, upper uint32
+}
+
+// bloomIndex maintains blooms aggregated from blooms of envelopes archived in hourly buckets
+// of envelope timestamps. A bloom filter of a request is tested against buckets first and
+// only ranges of buckets with possible matches are read from the database. An envelope
+// can match the request only if its bloom shares bits with the request bloom, so buckets
+// which aggregated bloom doesn't intersect the request bloom are skipped.
+//
+// The index is complete starting from its first bucket, older buckets are always read.
+type bloomIndex struct {
+	mu sync.RWMutex
+
+	db DB
+	// start is the first bucket the index is complete for
+	start uint32
+	// recent holds aggregated blooms of recent buckets
+	recent map[uint32][]byte
+	// pending holds bits of buckets that are not stored yet
+	pending map[uint32][]byte
+	// backfilling is a bucket before start that is being indexed by Backfill,
+	// envelopes archived in the meantime are indexed as if it was already in the index
+	backfilling   uint32
+	isBackfilling bool
+}
+
+// newBloomIndex reads the first bucket of the index. If the index is empty,
+// it is complete starting from the next bucket.
+func newBloomIndex(db DB, now time.Time) (*bloomIndex, error) {
+	start, err := db.BloomIndexStart()
+	if err != nil {
+		return nil, err
+	}
+	if start == 0 {
+		start = topicIndexBucketFor(uint32(now.Unix())) + topicIndexBucket
+	}
+	return &bloomIndex{
+		db:      db,
+		start:   start,
+		recent:  make(map[uint32][]byte),
+		pending: make(map[uint32][]byte),
+	}, nil
+}
+
+// Add aggregates the bloom of an archived envelope. Bits are stored only if they are new in the bucket.
+// If they can't be stored, they are kept in memory and stored with the next envelope.
+func (i *bloomIndex) Add(env types.Envelope) {
+	i.add(env.Expiry()-env.TTL(), types.TopicToBloom(env.Topic()))
+}
+
+func (i *bloomIndex) add(timestamp uint32, bloom []byte) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	bucket := topicIndexBucketFor(timestamp)
+	if bucket < i.start && !(i.isBackfilling && bucket == i.backfilling) {
+		return
+	}
+	aggregated, ok := i.recent[bucket]
+	if !ok {
+		aggregated = make([]byte, types.BloomFilterSize)
+		i.recent[bucket] = aggregated
+	}
+	if !types.BloomFilterMatch(aggregated, bloom) {
+		pending, ok := i.pending[bucket]
+		if !ok {
+			pending = make([]byte, types.BloomFilterSize)
+			i.pending[bucket] = pending
+		}
+		for j := range bloom {
+			aggregated[j] |= bloom[j]
+			pending[j] |= bloom[j]
+		}
+	}
+	if len(i.pending) == 0 {
+		return
+	}
+	if err := i.flush(); err != nil {
+		log.Error("failed to store bloom index", "err", err)
+	}
+	i.trim(bucket)
+}
+
+// flush stores pending bits. Must be called with the lock held.
+func (i *bloomIndex) flush() error {
+	for bucket, bloom := range i.pending {
+		if err := i.db.SaveBucketBloom(bucket, bloom); err != nil {
+			return err
+		}
+		delete(i.pending, bucket)
+	}
+	return nil
+}
+
+// trim forgets stored buckets that are older than the recent ones.
+// Must be called with the lock held.
+func (i *bloomIndex) trim(latest uint32) {
+	if len(i.recent) <= bloomIndexRecentBuckets {
+		return
+	}
+	for bucket := range i.recent {
+		if bucket+bloomIndexRecentBuckets*topicIndexBucket > latest {
+			continue
+		}
+		if _, pending := i.pending[bucket]; !pending {
+			delete(i.recent, bucket)
+		}
+	}
+}
+
+// Ranges returns ranges between lower and upper timestamps that can contain envelopes matching
+// the bloom filter, ordered by time. Adjacent buckets with possible matches are merged into
+// a single range. False is returned if the bloom filter matches every envelope.
+func (i *bloomIndex) Ranges(lower, upper uint32, bloom []byte) ([]bloomRange, bool, error) {
+	if len(bloom) == 0 || isFullBloom(bloom) || lower > upper {
+		return nil, false, nil
+	}
+	i.mu.RLock()
+	start := i.start
+	i.mu.RUnlock()
+
+	from, to := topicIndexBucketFor(lower), topicIndexBucketFor(upper)
+	if to < start {
+		return []bloomRange{{lower, upper}}, true, nil
+	}
+	indexedFrom := from
+	if indexedFrom < start {
+		indexedFrom = start
+	}
+	blooms, err := i.db.BucketBlooms(indexedFrom, to)
+	if err != nil {
+		return nil, false, err
+	}
+	i.mu.RLock()
+	for bucket, aggregated := range i.recent {
+		if bucket < indexedFrom || bucket > to {
+			continue
+		}
+		stored, ok := blooms[bucket]
+		if !ok {
+			stored = make([]byte, types.BloomFilterSize)
+			blooms[bucket] = stored
+		}
+		for j := range aggregated {
+			stored[j] |= aggregated[j]
+		}
+	}
+	i.mu.RUnlock()
+
+	var rst []bloomRange
+	for bucket := from; ; bucket += topicIndexBucket {
+		if bucket < start || bloomsIntersect(bloom, blooms[bucket]) {
+			r := bloomRange{lower: bucket, upper: bucket + topicIndexBucket - 1}
+			if r.lower < lower {
+				r.lower = lower
+			}
+			if r.upper > upper {
+				r.upper = upper
+			}
+			if n := len(rst); n > 0 && rst[n-1].upper+1 == r.lower {
+				rst[n-1].upper = r.upper
+			} else {
+				rst = append(rst, r)
+			}
+		}
+		if bucket >= to || bucket > math.MaxUint32-topicIndexBucket {
+			break
+		}
+	}
+	return rst, true, nil
+}
+
+// Pruned removes buckets of envelopes older than a given time. The bucket
+// of the time itself is kept as it may still contain envelopes.
+func (i *bloomIndex) Pruned(t time.Time) {
+	bucket := topicIndexBucketFor(uint32(t.Unix()))
+	i.mu.Lock()
+	if bucket > i.start {
+		i.start = bucket
+	}
+	for b := range i.recent {
+		if b < bucket {
+			delete(i.recent, b)
+			delete(i.pending, b)
+		}
+	}
+	i.mu.Unlock()
+	if err := i.db.PruneBloomIndex(bucket); err != nil {
+		log.Error("failed to prune bloom index", "err", err)
+	}
+}
+
+// Backfill aggregates blooms of envelopes archived before the first bucket of the index, from
+// the newest bucket to the bucket of the oldest archived envelope. The first bucket is moved back
+// after every aggregated bucket, so an interrupted backfill continues where it stopped. A bucket
+// without envelopes is stored with an empty bloom to keep the progress.
+func (i *bloomIndex) Backfill(ctx context.Context, progress func(float64)) error {
+	stats, err := i.db.ArchiveStats()
+	if err != nil {
+		return err
+	}
+	if len(stats.Oldest) < timestampLength {
+		return nil
+	}
+	oldest := topicIndexBucketFor(binary.BigEndian.Uint32(stats.Oldest))
+
+	i.mu.RLock()
+	start := i.start
+	i.mu.RUnlock()
+	if start <= oldest {
+		return nil
+	}
+	total := float64((start - oldest) / topicIndexBucket)
+	defer func() {
+		i.mu.Lock()
+		i.isBackfilling = false
+		i.mu.Unlock()
+	}()
+	for bucket := start - topicIndexBucket; bucket >= oldest; bucket -= topicIndexBucket {
+		if !i.startBackfilling(bucket) {
+			// the index was pruned in the meantime
+			return nil
+		}
+		bloom, err := i.bucketBloom(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if ok, err := i.extend(bucket, bloom); err != nil || !ok {
+			return err
+		}
+		progress(float64((start-bucket)/topicIndexBucket) / total)
+		if bucket < topicIndexBucket {
+			break
+		}
+	}
+	return nil
+}
+
+// bucketBloom aggregates blooms of envelopes archived in the bucket.
+func (i *bloomIndex) bucketBloom(ctx context.Context, bucket uint32) ([]byte, error) {
+	iter, err := i.db.BuildIterator(ctx, CursorQuery{
+		start: NewDBKey(bucket, types.TopicType{}, types.Hash{}).Bytes(),
+		end:   NewDBKey(bucket+topicIndexBucket, types.TopicType{}, types.Hash{}).Bytes(),
+		bloom: types.MakeFullNodeBloom(),
+		limit: math.MaxUint32,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = iter.Release() }()
+
+	rst := make([]byte, types.BloomFilterSize)
+	for iter.Next() {
+		key, err := iter.DBKey()
+		if err != nil {
+			return nil, err
+		}
+		bloom := types.TopicToBloom(key.Topic())
+		for j := range bloom {
+			rst[j] |= bloom[j]
+		}
+	}
+	return rst, iter.Error()
+}
+
+// startBackfilling marks the bucket right before the first one as being indexed.
+// False is returned if the first bucket was moved by pruning.
+func (i *bloomIndex) startBackfilling(bucket uint32) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if bucket+topicIndexBucket != i.start {
+		return false
+	}
+	i.backfilling = bucket
+	i.isBackfilling = true
+	return true
+}
+
+// extend stores the bloom of the backfilled bucket and makes it the first bucket.
+// False is returned if the first bucket was moved by pruning in the meantime.
+func (i *bloomIndex) extend(bucket uint32, bloom []byte) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if bucket+topicIndexBucket != i.start {
+		return false, nil
+	}
+	if err := i.db.SaveBucketBloom(bucket, bloom); err != nil {
+		return false, err
+	}
+	i.start = bucket
+	i.isBackfilling = false
+	return true, nil
+}
+
+// rangesFromCursor drops ranges before the cursor of a paginated request, or after it
+// if envelopes are returned in a descending order. OrderDefault must be resolved
+// to the order of the database first, otherwise it is handled as ascending.
+func rangesFromCursor(ranges []bloomRange, cursor []byte, order Order) []bloomRange {
+	if len(cursor) != CursorLength {
+		return ranges
+	}
+	timestamp := binary.BigEndian.Uint32(cursor)
+	if order == OrderDescending {
+		for len(ranges) > 0 && ranges[len(ranges)-1].lower > timestamp {
+			ranges = ranges[:len(ranges)-1]
+		}
+		return ranges
+	}
+	for len(ranges) > 0 && ranges[0].upper < timestamp {
+		ranges = ranges[1:]
+	}
+	return ranges
+}
+
+// skippedBuckets returns a number of buckets between lower and upper timestamps not covered by ranges.
+func skippedBuckets(lower, upper uint32, ranges []bloomRange) uint32 {
+	if lower > upper {
+		return 0
+	}
+	rst := (topicIndexBucketFor(upper)-topicIndexBucketFor(lower))/topicIndexBucket + 1
+	for _, r := range ranges {
+		rst -= (topicIndexBucketFor(r.upper)-topicIndexBucketFor(r.lower))/topicIndexBucket + 1
+	}
+	return rst
+}
+
+// rangesIterator iterates over envelopes in ranges one after another, an iterator of a range
+// is built once the previous one is exhausted. The cursor applies to the range it belongs to.
+// Ranges are read from the newest one if envelopes are returned in a descending order.
+type rangesIterator struct {
+	ctx     context.Context
+	db      DB
+	query   CursorQuery
+	ranges  []bloomRange
+	current Iterator
+	err     error
+}
+
+func newRangesIterator(ctx context.Context, db DB, query CursorQuery, ranges []bloomRange) *rangesIterator {
+	if query.order == OrderDescending {
+		reversed := make([]bloomRange, len(ranges))
+		for i, r := range ranges {
+			reversed[len(ranges)-1-i] = r
+		}
+		ranges = reversed
+	}
+	return &rangesIterator{ctx: ctx, db: db, query: query, ranges: ranges}
+}
+
+func (i *rangesIterator) Next() bool {
+	for {
+		if i.err != nil {
+			return false
+		}
+		if i.current != nil {
+			if i.current.Next() {
+				return true
+			}
+			i.err = i.current.Error()
+			_ = i.current.Release()
+			i.current = nil
+			continue
+		}
+		if len(i.ranges) == 0 {
+			return false
+		}
+		r := i.ranges[0]
+		i.ranges = i.ranges[1:]
+
+		query := i.query
+		query.start = NewDBKey(r.lower, types.TopicType{}, types.Hash{}).Bytes()
+		if r.upper < math.MaxUint32 {
+			query.end = NewDBKey(r.upper+1, types.TopicType{}, types.Hash{}).Bytes()
+		}
+		if len(query.cursor) == CursorLength {
+			timestamp := binary.BigEndian.Uint32(query.cursor)
+			if (query.order == OrderDescending && timestamp > r.upper) || (query.order != OrderDescending && timestamp < r.lower) {
+				query.cursor = nil
+			}
+		}
+		// the cursor is passed only to the first range
+		i.query.cursor = nil
+		i.current, i.err = i.db.BuildIterator(i.ctx, query)
+	}
+}
+
+func (i *rangesIterator) DBKey() (*DBKey, error) {
+	if i.current == nil {
+		return nil, errEmptyIterator
+	}
+	return i.current.DBKey()
+}
+
+func (i *rangesIterator) GetEnvelope(bloom []byte) ([]byte, error) {
+	if i.current == nil {
+		return nil, errEmptyIterator
+	}
+	return i.current.GetEnvelope(bloom)
+}
+
+func (i *rangesIterator) Release() error {
+	if i.current == nil {
+		return nil
+	}
+	err := i.current.Release()
+	i.current = nil
+	return err
+}
+
+func (i *rangesIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+	if i.current != nil {
+		return i.current.Error()
+	}
+	return nil
+}
+
+// bloomsIntersect returns true if two bloom filters share at least one bit.
+func bloomsIntersect(a, b []byte) bool {
+	for j := 0; j < len(a) && j < len(b); j++ {
+		if a[j]&b[j] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func isFullBloom(bloom []byte) bool {
+	for _, b := range bloom {
+		if b != 0xff {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+package mailserver
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func TestBloomIndexRanges(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	index, err := newBloomIndex(db, now)
+	require.NoError(t, err)
+	start := topicIndexBucketFor(uint32(now.Unix())) + topicIndexBucket
+	require.Equal(t, start, index.start)
+
+	first := types.TopicToBloom(types.TopicType{1, 1, 1, 1})
+	second := types.TopicToBloom(types.TopicType{2, 2, 2, 2})
+	index.add(start+10, first)
+	index.add(start+topicIndexBucket+10, first)
+	index.add(start+topicIndexBucket*3+10, second)
+
+	// full blooms match every bucket
+	_, ok, err := index.Ranges(start, start+topicIndexBucket*4, types.MakeFullNodeBloom())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// adjacent buckets are merged and buckets before the start are always read
+	ranges, ok, err := index.Ranges(start-10, start+topicIndexBucket*4-1, first)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []bloomRange{{start - 10, start + topicIndexBucket*2 - 1}}, ranges)
+	require.Equal(t, uint32(2), skippedBuckets(start-10, start+topicIndexBucket*4-1, ranges))
+
+	ranges, ok, err = index.Ranges(start+5, start+topicIndexBucket*4-1, second)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []bloomRange{{start + topicIndexBucket*3, start + topicIndexBucket*4 - 1}}, ranges)
+
+	// no buckets match the bloom filter
+	ranges, ok, err = index.Ranges(start+topicIndexBucket*2, start+topicIndexBucket*3-1, first)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, ranges)
+
+	// blooms are read from the database by a new instance
+	index, err = newBloomIndex(db, now.Add(10*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, start, index.start)
+	ranges, ok, err = index.Ranges(start, start+topicIndexBucket*4-1, second)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []bloomRange{{start + topicIndexBucket*3, start + topicIndexBucket*4 - 1}}, ranges)
+}
+
+func TestBloomIndexPruned(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	index, err := newBloomIndex(db, now)
+	require.NoError(t, err)
+	start := index.start
+	bloom := types.TopicToBloom(types.TopicType{1, 1, 1, 1})
+	index.add(start+10, bloom)
+	index.add(start+topicIndexBucket+10, bloom)
+
+	index.Pruned(time.Unix(int64(start+topicIndexBucket+5), 0))
+	require.Equal(t, start+topicIndexBucket, index.start)
+	first, err := db.BloomIndexStart()
+	require.NoError(t, err)
+	require.Equal(t, start+topicIndexBucket, first)
+
+	blooms, err := db.BucketBlooms(0, math.MaxUint32)
+	require.NoError(t, err)
+	require.Len(t, blooms, 1)
+}
+
+func TestBloomIndexBackfill(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	for _, sent := range []time.Time{now.Add(-5 * time.Hour), now.Add(-3 * time.Hour)} {
+		env, err := generateEnvelope(sent)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	}
+	bloom := types.TopicToBloom(types.TopicType{0x1F, 0x7E, 0xA1, 0x7F})
+
+	index, err := newBloomIndex(db, now)
+	require.NoError(t, err)
+	oldest := topicIndexBucketFor(uint32(now.Add(-5 * time.Hour).Unix()))
+
+	var progress []float64
+	require.NoError(t, index.Backfill(context.Background(), func(p float64) {
+		progress = append(progress, p)
+	}))
+	require.Equal(t, oldest, index.start)
+	require.Len(t, progress, 6)
+	require.Equal(t, float64(1), progress[len(progress)-1])
+
+	ranges, ok, err := index.Ranges(oldest, oldest+topicIndexBucket*6-1, bloom)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []bloomRange{
+		{oldest, oldest + topicIndexBucket - 1},
+		{oldest + topicIndexBucket*2, oldest + topicIndexBucket*3 - 1},
+	}, ranges)
+
+	// the start is stored, a new instance doesn't backfill again
+	index, err = newBloomIndex(db, now)
+	require.NoError(t, err)
+	require.Equal(t, oldest, index.start)
+	progress = nil
+	require.NoError(t, index.Backfill(context.Background(), func(p float64) {
+		progress = append(progress, p)
+	}))
+	require.Empty(t, progress)
+}
+
+func TestRangesIterator(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	var keys [][]byte
+	for _, sent := range []time.Time{now.Add(-5 * time.Hour), now.Add(-3 * time.Hour), now.Add(-3*time.Hour + time.Minute), now.Add(-time.Hour)} {
+		env, err := generateEnvelope(sent)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+		keys = append(keys, NewDBKey(env.Expiry-env.TTL, types.TopicType(env.Topic), types.Hash(env.Hash())).Bytes())
+	}
+	lower := uint32(now.Add(-6 * time.Hour).Unix())
+	ranges := []bloomRange{
+		{lower, uint32(now.Add(-4 * time.Hour).Unix())},
+		{uint32(now.Add(-4*time.Hour).Unix()) + 1, uint32(now.Add(-2 * time.Hour).Unix())},
+	}
+	query := CursorQuery{bloom: types.MakeFullNodeBloom(), limit: 10}
+
+	collect := func(ranges []bloomRange, cursor []byte) [][]byte {
+		query.cursor = cursor
+		iter := newRangesIterator(context.Background(), db, query, rangesFromCursor(ranges, cursor, query.order))
+		defer func() { require.NoError(t, iter.Release()) }()
+		var rst [][]byte
+		for iter.Next() {
+			key, err := iter.DBKey()
+			require.NoError(t, err)
+			rst = append(rst, append([]byte{}, key.Bytes()...))
+		}
+		require.NoError(t, iter.Error())
+		return rst
+	}
+
+	// the envelope outside of ranges is skipped
+	require.Equal(t, keys[:3], collect(ranges, nil))
+	// ranges before the cursor are skipped and the cursor is exclusive
+	require.Equal(t, keys[2:3], collect(ranges, keys[1][:CursorLength]))
+	require.Empty(t, collect(ranges[:1], keys[1][:CursorLength]))
+
+	query.order = OrderDescending
+	require.Equal(t, [][]byte{keys[2], keys[1], keys[0]}, collect(ranges, nil))
+	// ranges after the cursor are skipped and the cursor is exclusive
+	require.Equal(t, [][]byte{keys[1], keys[0]}, collect(ranges, keys[2][:CursorLength]))
+	require.Equal(t, [][]byte{keys[0]}, collect(ranges, keys[1][:CursorLength]))
+	require.Empty(t, collect(ranges[:1], keys[0][:CursorLength]))
+}
+
+func TestBloomIndexPaginationInDefaultOrder(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	index, err := newBloomIndex(db, time.Now())
+	require.NoError(t, err)
+	index.start = 0
+
+	now := time.Now()
+	var keys [][]byte
+	for _, sent := range []time.Time{now.Add(-5 * time.Hour), now.Add(-3 * time.Hour), now.Add(-time.Hour)} {
+		env, err := generateEnvelope(sent)
+		require.NoError(t, err)
+		envelope := gethbridge.NewWhisperEnvelope(env)
+		require.NoError(t, db.SaveEnvelope(envelope))
+		index.Add(envelope)
+		keys = append(keys, NewDBKey(env.Expiry-env.TTL, types.TopicType(env.Topic), types.Hash(env.Hash())).Bytes())
+	}
+
+	paginate := func(ms *mailServer) [][]byte {
+		req := MessagesRequestPayload{
+			Lower: uint32(now.Add(-6 * time.Hour).Unix()),
+			Upper: uint32(now.Unix()),
+			Bloom: types.TopicToBloom(types.TopicType{0x1F, 0x7E, 0xA1, 0x7F}),
+			Limit: 1,
+		}
+		var rst [][]byte
+		for page := 0; page <= len(keys); page++ {
+			iter, err := ms.createIterator(context.Background(), req)
+			require.NoError(t, err)
+			if !iter.Next() {
+				require.NoError(t, iter.Release())
+				break
+			}
+			key, err := iter.DBKey()
+			require.NoError(t, err)
+			rst = append(rst, append([]byte{}, key.Bytes()...))
+			req.Cursor = key.Cursor()
+			require.NoError(t, iter.Release())
+		}
+		return rst
+	}
+
+	require.Equal(t, keys, paginate(&mailServer{db: db, bloomIndex: index, defaultOrder: OrderAscending}))
+	// the default order of a database returning the newest envelopes first, like Postgres
+	require.Equal(t, [][]byte{keys[2], keys[1], keys[0]}, paginate(&mailServer{db: db, bloomIndex: index, defaultOrder: OrderDescending}))
+}
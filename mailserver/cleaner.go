@@ -19,6 +19,16 @@ type dbCleaner struct {
 	db        DB
 	batchSize int
 	retention time.Duration
+	// softDeleteWindow is how long pruned envelopes can be restored.
+	// Envelopes are removed immediately if it is zero.
+	softDeleteWindow time.Duration
+	// pruned is called after envelopes older than a given time are removed
+	pruned func(time.Time)
+	// paused returns true if envelopes must not be pruned, e.g. in the read-only mode
+	paused func() bool
+	// compactAfter is a number of removed envelopes after which pruned ranges
+	// are compacted, if the db supports it
+	compactAfter int
 
 	period time.Duration
 	cancel chan struct{}
@@ -30,8 +40,9 @@ func newDBCleaner(db DB, retention time.Duration) *dbCleaner {
 		db:        db,
 		retention: retention,
 
-		batchSize: dbCleanerBatchSize,
-		period:    dbCleanerPeriod,
+		batchSize:    dbCleanerBatchSize,
+		compactAfter: dbCompactionThreshold,
+		period:       dbCleanerPeriod,
 	}
 }
 
@@ -67,6 +78,10 @@ func (c *dbCleaner) schedule(period time.Duration, cancel <-chan struct{}) {
 	for {
 		select {
 		case <-t.C:
+			if c.paused != nil && c.paused() {
+				log.Info("Skipping pruning in the read-only mode")
+				continue
+			}
 			count, err := c.PruneEntriesOlderThan(time.Now().Add(-c.retention))
 			if err != nil {
 				log.Error("failed to prune data", "err", err)
@@ -79,7 +94,36 @@ func (c *dbCleaner) schedule(period time.Duration, cancel <-chan struct{}) {
 }
 
 // PruneEntriesOlderThan removes messages sent between lower and upper timestamps
-// and returns how many have been removed.
+// and returns how many have been removed. If soft delete is enabled messages are
+// marked as deleted and the ones deleted before the window are vacuumed.
+// Pruned ranges are compacted if many envelopes have been removed.
 func (c *dbCleaner) PruneEntriesOlderThan(t time.Time) (int, error) {
-	return c.db.Prune(t, c.batchSize)
+	var (
+		count    int
+		vacuumed int
+		err      error
+	)
+	if c.softDeleteWindow > 0 {
+		now := time.Now()
+		count, err = c.db.SoftPrune(t, now, c.batchSize)
+		if err == nil {
+			vacuumed, err = c.db.Vacuum(now.Add(-c.softDeleteWindow))
+			log.Debug("vacuumed soft deleted envelopes", "count", vacuumed)
+		}
+	} else {
+		count, err = c.db.Prune(t, c.batchSize)
+	}
+	if c.pruned != nil && count > 0 {
+		c.pruned(t)
+	}
+	if err != nil {
+		return count, err
+	}
+	if err := c.db.PruneEnvelopeSources(t); err != nil {
+		return count, err
+	}
+	if db, ok := c.db.(compactor); ok && c.compactAfter > 0 && count+vacuumed >= c.compactAfter {
+		return count, compact("pruned", func() error { return db.CompactPruned(t) })
+	}
+	return count, nil
 }
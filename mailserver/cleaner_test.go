@@ -1,6 +1,7 @@
 package mailserver
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -53,6 +54,67 @@ func TestCleanerSchedule(t *testing.T) {
 	testMessagesCount(t, 1, server)
 }
 
+func TestPruneEstimate(t *testing.T) {
+	now := time.Now()
+	server := setupTestServer(t)
+	defer server.Close()
+
+	first := archiveEnvelope(t, now.Add(-10*time.Second), server)
+	second := archiveEnvelope(t, now.Add(-3*time.Second), server)
+	archiveEnvelope(t, now.Add(-1*time.Second), server)
+
+	expectedSize := 0
+	for _, env := range []*whisper.Envelope{first, second} {
+		data, err := rlp.EncodeToBytes(env)
+		require.NoError(t, err)
+		expectedSize += len(data)
+	}
+
+	stats, err := NewAdminAPI(server).PruneEstimate(context.Background(), uint32(now.Add(-2*time.Second).Unix()))
+	require.NoError(t, err)
+	require.Equal(t, PruneStats{Rows: 2, Size: int64(expectedSize)}, stats)
+
+	// nothing is removed
+	testMessagesCount(t, 3, server)
+}
+
+func TestCleanerSoftDelete(t *testing.T) {
+	now := time.Now()
+	server := setupTestServer(t)
+	defer server.Close()
+	api := NewAdminAPI(server)
+
+	archiveEnvelope(t, now.Add(-10*time.Second), server)
+	archiveEnvelope(t, now.Add(-3*time.Second), server)
+	archiveEnvelope(t, now.Add(-1*time.Second), server)
+
+	server.ms.cleaner = newDBCleaner(server.ms.db, time.Hour)
+	_, err := api.RestorePruned(context.Background(), 0)
+	require.Equal(t, ErrSoftDeleteDisabled, err)
+
+	server.ms.cleaner.softDeleteWindow = time.Hour
+	testPrune(t, now.Add(-2*time.Second), 2, server.ms.cleaner)
+	testMessagesCount(t, 1, server)
+	stats, err := api.PruneEstimate(context.Background(), uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Rows)
+
+	restored, err := api.RestorePruned(context.Background(), uint32(now.Add(-time.Minute).Unix()))
+	require.NoError(t, err)
+	require.Equal(t, 2, restored)
+	testMessagesCount(t, 3, server)
+
+	// envelopes deleted before the window are removed permanently
+	testPrune(t, now.Add(-2*time.Second), 2, server.ms.cleaner)
+	vacuumed, err := server.ms.db.Vacuum(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 2, vacuumed)
+	restored, err = api.RestorePruned(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, restored)
+	testMessagesCount(t, 1, server)
+}
+
 func benchmarkCleanerPrune(b *testing.B, messages int, batchSize int) {
 	t := &testing.T{}
 	now := time.Now()
@@ -95,6 +157,7 @@ func setupTestServer(t *testing.T) *WhisperMailServer {
 	s.ms = &mailServer{
 		db:      &LevelDB{ldb: db},
 		adapter: &whisperAdapter{},
+		quit:    make(chan struct{}),
 	}
 	s.minRequestPoW = powRequirement
 	return &s
@@ -135,7 +198,7 @@ func countMessages(t *testing.T, db DB) int {
 		end:   ku.raw,
 	}
 
-	i, _ := db.BuildIterator(query)
+	i, _ := db.BuildIterator(context.Background(), query)
 	defer func() { _ = i.Release() }()
 
 	for i.Next() {
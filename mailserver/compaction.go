@@ -0,0 +1,134 @@
+package mailserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// dbCompactionThreshold is a number of pruned envelopes after which pruned ranges are compacted.
+	dbCompactionThreshold = 10000
+	// dbCompactorPeriod is how often the compactor checks if the mailserver is idle.
+	dbCompactorPeriod = time.Minute
+	// defaultCompactionIdleWindow is how long the mailserver must receive no requests
+	// before a periodic compaction is started.
+	defaultCompactionIdleWindow = 10 * time.Minute
+)
+
+// compactor is implemented by databases with explicit compaction control.
+type compactor interface {
+	// Compact compacts the whole database
+	Compact() error
+	// CompactPruned compacts ranges of envelopes pruned before time
+	CompactPruned(time.Time) error
+	// DiskUsage returns the approximate size on disk of envelopes between two times in buckets of a given length
+	DiskUsage(from, to time.Time, bucket time.Duration) ([]BucketDiskUsage, error)
+}
+
+// BucketDiskUsage is the approximate size on disk of envelopes in a time bucket.
+type BucketDiskUsage struct {
+	// Bucket is the unix timestamp of the start of the bucket
+	Bucket uint32 `json:"bucket"`
+	// Size is the size in bytes
+	Size uint64 `json:"size"`
+}
+
+// compact runs a compaction and records its duration.
+func compact(reason string, f func() error) error {
+	start := time.Now()
+	err := f()
+	compactionDuration.WithLabelValues(reason).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return err
+	}
+	log.Info("compacted database", "reason", reason, "duration", time.Since(start))
+	return nil
+}
+
+// dbCompactor compacts a database periodically when the mailserver doesn't receive requests.
+type dbCompactor struct {
+	sync.RWMutex
+
+	db compactor
+	// interval is the minimum time between compactions
+	interval time.Duration
+	// idleWindow is how long no requests must be received before a compaction
+	idleWindow time.Duration
+	// lastRequest returns the time the last request was received
+	lastRequest func() time.Time
+	// lastCompaction is the time of the last compaction, it is set by Start and then accessed only by the loop
+	lastCompaction time.Time
+
+	period time.Duration
+	cancel chan struct{}
+}
+
+// newDBCompactor returns a new compactor for db.
+func newDBCompactor(db compactor, interval, idleWindow time.Duration, lastRequest func() time.Time) *dbCompactor {
+	if idleWindow <= 0 {
+		idleWindow = defaultCompactionIdleWindow
+	}
+	return &dbCompactor{
+		db:          db,
+		interval:    interval,
+		idleWindow:  idleWindow,
+		lastRequest: lastRequest,
+
+		period: dbCompactorPeriod,
+	}
+}
+
+// Start starts a loop that compacts the database. The first compaction
+// is started after the interval passes.
+func (c *dbCompactor) Start() {
+	log.Info("Starting periodic compaction", "interval", c.interval, "idle", c.idleWindow)
+
+	cancel := make(chan struct{})
+
+	c.Lock()
+	c.cancel = cancel
+	c.lastCompaction = time.Now()
+	c.Unlock()
+
+	go c.schedule(c.period, cancel)
+}
+
+// Stop stops the compaction loop.
+func (c *dbCompactor) Stop() {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.cancel == nil {
+		return
+	}
+	close(c.cancel)
+	c.cancel = nil
+}
+
+func (c *dbCompactor) schedule(period time.Duration, cancel <-chan struct{}) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case now := <-t.C:
+			if _, err := c.compactIfIdle(now); err != nil {
+				log.Error("failed to compact database", "err", err)
+			}
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// compactIfIdle compacts the database if the interval passed since the last compaction
+// and no requests were received within the idle window. It returns whether the database was compacted.
+func (c *dbCompactor) compactIfIdle(now time.Time) (bool, error) {
+	if now.Sub(c.lastCompaction) < c.interval || now.Sub(c.lastRequest()) < c.idleWindow {
+		return false, nil
+	}
+	c.lastCompaction = now
+	return true, compact("idle", c.db.Compact)
+}
@@ -0,0 +1,115 @@
+package mailserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingCompactor struct {
+	*LevelDB
+	compacted int
+	pruned    []time.Time
+}
+
+func (c *countingCompactor) Compact() error {
+	c.compacted++
+	return c.LevelDB.Compact()
+}
+
+func (c *countingCompactor) CompactPruned(t time.Time) error {
+	c.pruned = append(c.pruned, t)
+	return c.LevelDB.CompactPruned(t)
+}
+
+func TestLevelDBDiskUsage(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	db := server.ms.db.(*LevelDB)
+
+	start := time.Now().Truncate(time.Hour).Add(-3 * time.Hour)
+	for i := 0; i < 100; i++ {
+		archiveEnvelope(t, start.Add(time.Minute), server)
+		archiveEnvelope(t, start.Add(2*time.Hour+time.Minute), server)
+	}
+	// envelopes are flushed to disk by the compaction
+	require.NoError(t, db.Compact())
+
+	usage, err := NewAdminAPI(server).DiskUsage(context.Background(), uint32(start.Unix()), uint32(start.Add(3*time.Hour).Unix()), 3600)
+	require.NoError(t, err)
+	require.Len(t, usage, 3)
+	require.Equal(t, uint32(start.Unix()), usage[0].Bucket)
+	require.NotZero(t, usage[0].Size)
+	require.Zero(t, usage[1].Size)
+	require.NotZero(t, usage[2].Size)
+
+	_, err = db.Prune(start.Add(time.Hour), dbCleanerBatchSize)
+	require.NoError(t, err)
+	require.NoError(t, db.CompactPruned(start.Add(time.Hour)))
+	usage, err = db.DiskUsage(start, start.Add(3*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, usage[0].Size)
+	require.NotZero(t, usage[2].Size)
+}
+
+func TestDiskUsageInvalidBuckets(t *testing.T) {
+	api := NewAdminAPI(&WhisperMailServer{})
+	_, err := api.DiskUsage(context.Background(), 0, 3600, 0)
+	require.Equal(t, ErrInvalidDiskUsageBuckets, err)
+	_, err = api.DiskUsage(context.Background(), 3600, 0, 60)
+	require.Equal(t, ErrInvalidDiskUsageBuckets, err)
+	_, err = api.DiskUsage(context.Background(), 0, 3600*maxDiskUsageBuckets, 3600)
+	require.Equal(t, ErrInvalidDiskUsageBuckets, err)
+	_, err = api.DiskUsage(context.Background(), 0, 3600, 60)
+	require.Equal(t, ErrMailServerNotInitialized, err)
+}
+
+func TestCleanerCompactsLargePrunes(t *testing.T) {
+	now := time.Now()
+	server := setupTestServer(t)
+	defer server.Close()
+	db := &countingCompactor{LevelDB: server.ms.db.(*LevelDB)}
+
+	cleaner := newDBCleaner(db, time.Hour)
+	cleaner.compactAfter = 2
+	archiveEnvelope(t, now.Add(-3*time.Second), server)
+	archiveEnvelope(t, now.Add(-2*time.Second), server)
+	archiveEnvelope(t, now.Add(-1*time.Second), server)
+
+	testPrune(t, now.Add(-2*time.Second), 1, cleaner)
+	require.Empty(t, db.pruned)
+	testPrune(t, now, 2, cleaner)
+	require.Equal(t, []time.Time{now}, db.pruned)
+}
+
+func TestCompactorIdleWindow(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	db := &countingCompactor{LevelDB: server.ms.db.(*LevelDB)}
+
+	now := time.Now()
+	lastRequest := now
+	compactor := newDBCompactor(db, time.Hour, 0, func() time.Time { return lastRequest })
+	require.Equal(t, defaultCompactionIdleWindow, compactor.idleWindow)
+	compactor.lastCompaction = now
+
+	compacted, err := compactor.compactIfIdle(now.Add(30 * time.Minute))
+	require.NoError(t, err)
+	require.False(t, compacted, "interval didn't pass")
+
+	lastRequest = now.Add(55 * time.Minute)
+	compacted, err = compactor.compactIfIdle(now.Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, compacted, "mailserver is not idle")
+
+	compacted, err = compactor.compactIfIdle(now.Add(time.Hour + 5*time.Minute))
+	require.NoError(t, err)
+	require.True(t, compacted)
+	require.Equal(t, 1, db.compacted)
+
+	compacted, err = compactor.compactIfIdle(now.Add(time.Hour + 30*time.Minute))
+	require.NoError(t, err)
+	require.False(t, compacted, "compacted within the interval")
+}
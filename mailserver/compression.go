@@ -0,0 +1,102 @@
+package mailserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// compressionStatsPeers is a maximum number of peers with kept compression stats.
+const compressionStatsPeers = 1000
+
+// compressedEnvelopes is a batch of envelopes sent to peers that accept compressed responses.
+// It matches whisper.CompressedEnvelopes and waku.CompressedEnvelopes.
+type compressedEnvelopes struct {
+	Gzip []byte
+}
+
+// compressEnvelopes encodes envelopes as an RLP list and compresses it with gzip.
+// The returned value is an RLP encoded compressedEnvelopes and its compressed size.
+func compressEnvelopes(envelopes []rlp.RawValue) (rlp.RawValue, int, error) {
+	raw, err := rlp.EncodeToBytes(envelopes)
+	if err != nil {
+		return nil, 0, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(raw); err != nil {
+		return nil, 0, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, 0, err
+	}
+	data, err := rlp.EncodeToBytes(compressedEnvelopes{Gzip: buf.Bytes()})
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// PeerCompressionStats is a number of compressed responses sent to a peer with
+// their total size before and after the compression.
+type PeerCompressionStats struct {
+	Peer           types.Hash `json:"peer"`
+	Responses      uint64     `json:"responses"`
+	RawSize        uint64     `json:"rawSize"`
+	CompressedSize uint64     `json:"compressedSize"`
+}
+
+// compressionStats keeps compression stats of recently served peers.
+type compressionStats struct {
+	mu    sync.Mutex
+	peers *simplelru.LRU
+}
+
+func newCompressionStats(size int) (*compressionStats, error) {
+	peers, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &compressionStats{peers: peers}, nil
+}
+
+// Sent records a compressed response.
+func (c *compressionStats) Sent(peerID types.Hash, rawSize, compressedSize int) {
+	compressedResponsesCounter.Inc()
+	compressionBytesCounter.WithLabelValues("raw").Add(float64(rawSize))
+	compressionBytesCounter.WithLabelValues("compressed").Add(float64(compressedSize))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := &PeerCompressionStats{Peer: peerID}
+	if value, ok := c.peers.Get(peerID); ok {
+		stats = value.(*PeerCompressionStats)
+	}
+	stats.Responses++
+	stats.RawSize += uint64(rawSize)
+	stats.CompressedSize += uint64(compressedSize)
+	c.peers.Add(peerID, stats)
+}
+
+// Stats returns stats of all known peers ordered by the number of saved bytes in a descending order.
+func (c *compressionStats) Stats() []PeerCompressionStats {
+	c.mu.Lock()
+	rst := make([]PeerCompressionStats, 0, c.peers.Len())
+	for _, key := range c.peers.Keys() {
+		if value, ok := c.peers.Peek(key); ok {
+			rst = append(rst, *value.(*PeerCompressionStats))
+		}
+	}
+	c.mu.Unlock()
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].RawSize-rst[i].CompressedSize > rst[j].RawSize-rst[j].CompressedSize
+	})
+	return rst
+}
@@ -0,0 +1,86 @@
+package mailserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+func rawTestEnvelopes(t *testing.T, n, size int) []rlp.RawValue {
+	var envelopes []rlp.RawValue
+	for i := 0; i < n; i++ {
+		raw, err := rlp.EncodeToBytes(&whisper.Envelope{Expiry: uint32(i), TTL: 10, Data: make([]byte, size)})
+		require.NoError(t, err)
+		envelopes = append(envelopes, raw)
+	}
+	return envelopes
+}
+
+func decompressTestEnvelopes(t *testing.T, data rlp.RawValue) []*whisper.Envelope {
+	var compressed compressedEnvelopes
+	require.NoError(t, rlp.DecodeBytes(data, &compressed))
+	r, err := gzip.NewReader(bytes.NewReader(compressed.Gzip))
+	require.NoError(t, err)
+	raw, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	var envelopes []*whisper.Envelope
+	require.NoError(t, rlp.DecodeBytes(raw, &envelopes))
+	return envelopes
+}
+
+func TestSendCompressedEnvelopes(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	service := &digestTestService{}
+	server.ms.service = service
+	var err error
+	server.ms.compression, err = newCompressionStats(compressionStatsPeers)
+	require.NoError(t, err)
+	peer := types.Hash{1}
+
+	envelopes := rawTestEnvelopes(t, 3, 1000)
+	require.NoError(t, server.ms.sendRawEnvelopes(peer, envelopes, true, true))
+	require.Len(t, service.envelopes, 1)
+	received := decompressTestEnvelopes(t, service.envelopes[0])
+	require.Len(t, received, 3)
+	for i := range received {
+		require.Equal(t, uint32(i), received[i].Expiry)
+		require.Len(t, received[i].Data, 1000)
+	}
+
+	stats, err := NewAdminAPI(server).CompressionStats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Equal(t, peer, stats[0].Peer)
+	require.Equal(t, uint64(1), stats[0].Responses)
+	require.Equal(t, uint64(len(service.envelopes[0])), stats[0].CompressedSize)
+	require.True(t, stats[0].RawSize > stats[0].CompressedSize)
+
+	// not requested by the peer
+	service.envelopes = nil
+	require.NoError(t, server.ms.sendRawEnvelopes(peer, envelopes, true, false))
+	require.Equal(t, envelopes, service.envelopes)
+
+	// not smaller after the compression
+	service.envelopes = nil
+	small := rawTestEnvelopes(t, 1, 0)
+	require.NoError(t, server.ms.sendRawEnvelopes(peer, small, true, true))
+	require.Equal(t, small, service.envelopes)
+
+	// disabled in the config
+	cfg := server.ms.config
+	cfg.DisableCompression = true
+	require.Equal(t, []string{"DisableCompression: false -> true"}, server.ms.reload(cfg))
+	service.envelopes = nil
+	require.NoError(t, server.ms.sendRawEnvelopes(peer, envelopes, true, true))
+	require.Equal(t, envelopes, service.envelopes)
+}
@@ -0,0 +1,217 @@
+package mailserver
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	coverageHeartbeatPeriod = time.Minute
+	// coverageDowntimeThreshold is how old the heartbeat must be at startup to record a gap.
+	// Restarts faster than a couple of heartbeats are not recorded.
+	coverageDowntimeThreshold = 2 * coverageHeartbeatPeriod
+
+	// coverageGapDown is a reason of gaps when the mailserver was not running.
+	coverageGapDown = "down"
+	// coverageGapUnavailable is a reason of gaps when envelopes couldn't be saved to the database.
+	coverageGapUnavailable = "unavailable"
+	// coverageGapReadOnly is a reason of gaps when envelopes were dropped in the read-only mode.
+	coverageGapReadOnly = "read-only"
+)
+
+// CoverageGap is a time range during which envelopes were not archived.
+// From and To are unix timestamps in seconds.
+type CoverageGap struct {
+	From   uint32 `json:"from"`
+	To     uint32 `json:"to"`
+	Reason string `json:"reason"`
+	// Ongoing is true if envelopes are still not archived, To is the current time then.
+	Ongoing bool `json:"ongoing,omitempty"`
+	// HealedAt is the unix time when envelopes of the gap were backfilled from a peer, zero if they were not.
+	HealedAt uint32 `json:"healedAt,omitempty"`
+}
+
+// mergeCoverageGaps sorts gaps by start and keeps the longest of gaps with the same start.
+// A gap is healed only if it is healed in every copy.
+func mergeCoverageGaps(gaps []CoverageGap) []CoverageGap {
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].From < gaps[j].From })
+	var rst []CoverageGap
+	for _, gap := range gaps {
+		if n := len(rst); n > 0 && rst[n-1].From == gap.From {
+			healedAt := rst[n-1].HealedAt
+			if gap.HealedAt == 0 || (healedAt != 0 && gap.HealedAt < healedAt) {
+				healedAt = gap.HealedAt
+			}
+			if gap.To > rst[n-1].To {
+				rst[n-1] = gap
+			}
+			rst[n-1].HealedAt = healedAt
+			continue
+		}
+		rst = append(rst, gap)
+	}
+	return rst
+}
+
+// coverageTracker records gaps in the archive. A heartbeat is persisted periodically and
+// a heartbeat older than the threshold at startup is recorded as a downtime. Failed writes
+// of envelopes start a gap that is recorded once a write succeeds again. Envelopes dropped
+// in the read-only mode start a gap that is recorded once the mode is disabled.
+type coverageTracker struct {
+	db  DB
+	now func() time.Time
+
+	// failedSince is the unix time of the first failed write since the last successful one
+	failedSince int64
+	// droppedSince is the unix time of the first envelope dropped in the read-only mode
+	droppedSince int64
+	// mu serializes recording of gaps
+	mu sync.Mutex
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newCoverageTracker(db DB) *coverageTracker {
+	return &coverageTracker{db: db, now: time.Now, period: coverageHeartbeatPeriod}
+}
+
+// Start records the downtime before the start and starts persisting heartbeats.
+func (c *coverageTracker) Start() {
+	c.recordDowntime()
+	c.heartbeat()
+	c.cancel = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.schedule(c.period, c.cancel)
+	}()
+}
+
+// Stop stops the heartbeat loop and persists the last heartbeat and a read-only gap.
+func (c *coverageTracker) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	close(c.cancel)
+	c.wg.Wait()
+	c.cancel = nil
+	c.heartbeat()
+	c.ReadOnlyDisabled()
+}
+
+func (c *coverageTracker) schedule(period time.Duration, cancel <-chan struct{}) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			c.heartbeat()
+		case <-cancel:
+			return
+		}
+	}
+}
+
+func (c *coverageTracker) recordDowntime() {
+	last, err := c.db.CoverageHeartbeat()
+	if err != nil {
+		log.Error("failed to read coverage heartbeat", "err", err)
+		return
+	}
+	now := c.now()
+	if last == 0 || now.Sub(time.Unix(int64(last), 0)) < coverageDowntimeThreshold {
+		return
+	}
+	gap := CoverageGap{From: last, To: uint32(now.Unix()), Reason: coverageGapDown}
+	if err := c.db.SaveCoverageGap(gap); err != nil {
+		log.Error("failed to save coverage gap", "err", err)
+		return
+	}
+	coverageGapsCounter.WithLabelValues(coverageGapDown).Inc()
+	log.Info("recorded mailserver downtime", "from", gap.From, "to", gap.To)
+}
+
+// heartbeat persists the current time. A successful write also closes a gap.
+func (c *coverageTracker) heartbeat() {
+	if err := c.db.SaveCoverageHeartbeat(uint32(c.now().Unix())); err != nil {
+		log.Error("failed to save coverage heartbeat", "err", err)
+		return
+	}
+	c.Succeeded()
+}
+
+// Failed starts a gap unless one is already started.
+func (c *coverageTracker) Failed() {
+	atomic.CompareAndSwapInt64(&c.failedSince, 0, c.now().Unix())
+}
+
+// Succeeded records a started gap.
+func (c *coverageTracker) Succeeded() {
+	c.record(&c.failedSince, coverageGapUnavailable)
+}
+
+// Dropped starts a read-only gap unless one is already started.
+func (c *coverageTracker) Dropped() {
+	atomic.CompareAndSwapInt64(&c.droppedSince, 0, c.now().Unix())
+}
+
+// ReadOnlyDisabled records a started read-only gap.
+func (c *coverageTracker) ReadOnlyDisabled() {
+	c.record(&c.droppedSince, coverageGapReadOnly)
+}
+
+func (c *coverageTracker) record(started *int64, reason string) {
+	if atomic.LoadInt64(started) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	since := atomic.LoadInt64(started)
+	if since == 0 {
+		return
+	}
+	gap := CoverageGap{From: uint32(since), To: uint32(c.now().Unix()), Reason: reason}
+	if err := c.db.SaveCoverageGap(gap); err != nil {
+		log.Error("failed to save coverage gap", "err", err)
+		return
+	}
+	atomic.StoreInt64(started, 0)
+	coverageGapsCounter.WithLabelValues(reason).Inc()
+	log.Info("recorded mailserver archive gap", "from", gap.From, "to", gap.To, "reason", reason)
+}
+
+// Gaps returns recorded gaps overlapping the range between two unix timestamps
+// together with the ongoing gap.
+func (c *coverageTracker) Gaps(from, to uint32) ([]CoverageGap, error) {
+	gaps, err := c.db.CoverageGaps(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if since := atomic.LoadInt64(&c.failedSince); since != 0 && uint32(since) <= to {
+		gaps = append(gaps, CoverageGap{From: uint32(since), To: uint32(c.now().Unix()), Reason: coverageGapUnavailable, Ongoing: true})
+	}
+	if since := atomic.LoadInt64(&c.droppedSince); since != 0 && uint32(since) <= to {
+		gaps = append(gaps, CoverageGap{From: uint32(since), To: uint32(c.now().Unix()), Reason: coverageGapReadOnly, Ongoing: true})
+	}
+	if gaps == nil {
+		gaps = []CoverageGap{}
+	}
+	return gaps, nil
+}
+
+// Healed marks the gap as backfilled.
+func (c *coverageTracker) Healed(gap CoverageGap) error {
+	gap.HealedAt = uint32(c.now().Unix())
+	if err := c.db.SaveCoverageGap(gap); err != nil {
+		return err
+	}
+	coverageHealedGapsCounter.Inc()
+	return nil
+}
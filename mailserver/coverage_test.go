@@ -0,0 +1,113 @@
+package mailserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageTrackerRecordsDowntime(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Unix(1590000000, 0)
+	tracker := newCoverageTracker(server.ms.db)
+	tracker.now = func() time.Time { return now }
+	tracker.Start()
+	tracker.Stop()
+
+	// a quick restart is not a gap
+	now = now.Add(coverageHeartbeatPeriod)
+	tracker.Start()
+	tracker.Stop()
+	gaps, err := server.ms.db.CoverageGaps(0, uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Empty(t, gaps)
+
+	stopped := now
+	now = now.Add(time.Hour)
+	tracker.Start()
+	tracker.Stop()
+	gaps, err = server.ms.db.CoverageGaps(0, uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{{From: uint32(stopped.Unix()), To: uint32(now.Unix()), Reason: coverageGapDown}}, gaps)
+}
+
+func TestCoverageTrackerRecordsFailedWrites(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Unix(1590000000, 0)
+	tracker := newCoverageTracker(server.ms.db)
+	tracker.now = func() time.Time { return now }
+	server.ms.coverage = tracker
+
+	failed := now
+	tracker.Failed()
+	now = now.Add(time.Minute)
+	// the gap starts with the first failure
+	tracker.Failed()
+
+	api := NewPublicAPI(server)
+	gaps, err := api.GetCoverageGaps(context.Background(), 0, uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{{From: uint32(failed.Unix()), To: uint32(now.Unix()), Reason: coverageGapUnavailable, Ongoing: true}}, gaps)
+
+	now = now.Add(time.Minute)
+	env, err := generateEnvelope(now)
+	require.NoError(t, err)
+	server.Archive(env)
+	gaps, err = api.GetCoverageGaps(context.Background(), uint32(failed.Unix()), uint32(failed.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{{From: uint32(failed.Unix()), To: uint32(now.Unix()), Reason: coverageGapUnavailable}}, gaps)
+
+	gaps, err = api.GetCoverageGaps(context.Background(), uint32(now.Unix())+1, uint32(now.Unix())+10)
+	require.NoError(t, err)
+	require.Empty(t, gaps)
+
+	_, err = api.GetCoverageGaps(context.Background(), 10, 1)
+	require.Equal(t, ErrInvalidCoverageRange, err)
+}
+
+func TestCoverageTrackerRecordsReadOnlyDrops(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Unix(1590000000, 0)
+	tracker := newCoverageTracker(server.ms.db)
+	tracker.now = func() time.Time { return now }
+	server.ms.coverage = tracker
+	server.ms.setReadOnly(true)
+
+	dropped := now
+	env, err := generateEnvelope(now)
+	require.NoError(t, err)
+	server.Archive(env)
+
+	// heartbeats don't close the gap
+	now = now.Add(time.Minute)
+	tracker.heartbeat()
+	api := NewPublicAPI(server)
+	gaps, err := api.GetCoverageGaps(context.Background(), 0, uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{{From: uint32(dropped.Unix()), To: uint32(now.Unix()), Reason: coverageGapReadOnly, Ongoing: true}}, gaps)
+
+	now = now.Add(time.Minute)
+	server.ms.setReadOnly(false)
+	gaps, err = api.GetCoverageGaps(context.Background(), 0, uint32(now.Unix()))
+	require.NoError(t, err)
+	require.Equal(t, []CoverageGap{{From: uint32(dropped.Unix()), To: uint32(now.Unix()), Reason: coverageGapReadOnly}}, gaps)
+}
+
+func TestMergeCoverageGaps(t *testing.T) {
+	require.Equal(t, []CoverageGap{
+		{From: 1, To: 5, Reason: coverageGapDown},
+		{From: 10, To: 30, Reason: coverageGapUnavailable},
+	}, mergeCoverageGaps([]CoverageGap{
+		{From: 10, To: 20, Reason: coverageGapUnavailable},
+		{From: 1, To: 5, Reason: coverageGapDown},
+		{From: 10, To: 30, Reason: coverageGapUnavailable},
+	}))
+}
@@ -0,0 +1,189 @@
+package mailserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	envelopeSourcesFlushPeriod = time.Minute
+	// envelopeSourcesPendingTTL is how long an archived envelope waits for
+	// a received event. Envelopes archived from sync responses never get it.
+	envelopeSourcesPendingTTL = time.Minute
+)
+
+// EnvelopeSourceRecord links an archived envelope to the peer that delivered it.
+type EnvelopeSourceRecord struct {
+	Key  *DBKey
+	Peer types.Hash
+	Size uint64
+}
+
+// EnvelopeSource is a number of envelopes and their total size delivered by a peer.
+type EnvelopeSource struct {
+	Peer      types.Hash `json:"peer"`
+	Envelopes uint64     `json:"envelopes"`
+	Bytes     uint64     `json:"bytes"`
+}
+
+// sortEnvelopeSources sorts sources by the number of envelopes and then by size in a descending order.
+func sortEnvelopeSources(sources []EnvelopeSource) {
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Envelopes == sources[j].Envelopes {
+			return sources[i].Bytes > sources[j].Bytes
+		}
+		return sources[i].Envelopes > sources[j].Envelopes
+	})
+}
+
+type pendingEnvelopeSource struct {
+	key      *DBKey
+	size     uint64
+	archived time.Time
+}
+
+// envelopeSourcesCollector matches archived envelopes with received events
+// that carry the delivering peer and periodically flushes matched records to the db.
+type envelopeSourcesCollector struct {
+	sync.Mutex
+
+	db      DB
+	pending map[types.Hash]pendingEnvelopeSource
+	records []EnvelopeSourceRecord
+
+	period time.Duration
+	ttl    time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newEnvelopeSourcesCollector(db DB) *envelopeSourcesCollector {
+	return &envelopeSourcesCollector{
+		db:      db,
+		pending: make(map[types.Hash]pendingEnvelopeSource),
+		period:  envelopeSourcesFlushPeriod,
+		ttl:     envelopeSourcesPendingTTL,
+	}
+}
+
+// Start starts a loop that flushes records.
+func (c *envelopeSourcesCollector) Start() {
+	c.cancel = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.schedule(c.period, c.cancel)
+	}()
+}
+
+// Stop stops the flushing loop and persists matched records.
+func (c *envelopeSourcesCollector) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	close(c.cancel)
+	c.wg.Wait()
+	c.cancel = nil
+	if err := c.Flush(); err != nil {
+		log.Error("failed to flush envelope sources", "err", err)
+	}
+}
+
+func (c *envelopeSourcesCollector) schedule(period time.Duration, cancel <-chan struct{}) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := c.Flush(); err != nil {
+				log.Error("failed to flush envelope sources", "err", err)
+			}
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// Archived remembers an archived envelope until the peer that delivered it is known.
+func (c *envelopeSourcesCollector) Archived(env types.Envelope) {
+	c.Lock()
+	defer c.Unlock()
+	c.pending[env.Hash()] = pendingEnvelopeSource{
+		key:      NewDBKey(env.Expiry()-env.TTL(), env.Topic(), env.Hash()),
+		size:     uint64(whisper.EnvelopeHeaderLength + env.Size()),
+		archived: time.Now(),
+	}
+}
+
+// Received records the peer as a source of the envelope if the envelope was archived.
+// Only the first peer is recorded, duplicates delivered by other peers are not archived.
+func (c *envelopeSourcesCollector) Received(hash types.Hash, peer types.Hash) {
+	c.Lock()
+	defer c.Unlock()
+	pending, ok := c.pending[hash]
+	if !ok {
+		return
+	}
+	delete(c.pending, hash)
+	c.records = append(c.records, EnvelopeSourceRecord{Key: pending.key, Peer: peer, Size: pending.size})
+}
+
+// Flush persists matched records and drops archived envelopes that were not matched in time.
+func (c *envelopeSourcesCollector) Flush() error {
+	c.Lock()
+	defer c.Unlock()
+
+	deadline := time.Now().Add(-c.ttl)
+	for hash, pending := range c.pending {
+		if pending.archived.Before(deadline) {
+			delete(c.pending, hash)
+		}
+	}
+	if len(c.records) == 0 {
+		return nil
+	}
+	if err := c.db.SaveEnvelopeSources(c.records); err != nil {
+		return err
+	}
+	c.records = nil
+	return nil
+}
+
+// TopSources returns n peers that delivered the highest number of envelopes sent since a given time.
+func (c *envelopeSourcesCollector) TopSources(n int, since time.Time) ([]EnvelopeSource, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	sources, err := c.db.EnvelopeSources(since)
+	if err != nil {
+		return nil, err
+	}
+	sortEnvelopeSources(sources)
+	if len(sources) > n {
+		sources = sources[:n]
+	}
+	return sources, nil
+}
+
+// aggregateEnvelopeSources merges sources of the same peer.
+func aggregateEnvelopeSources(sources []EnvelopeSource) []EnvelopeSource {
+	index := map[types.Hash]int{}
+	var rst []EnvelopeSource
+	for _, source := range sources {
+		if i, ok := index[source.Peer]; ok {
+			rst[i].Envelopes += source.Envelopes
+			rst[i].Bytes += source.Bytes
+			continue
+		}
+		index[source.Peer] = len(rst)
+		rst = append(rst, source)
+	}
+	return rst
+}
@@ -0,0 +1,84 @@
+package mailserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func TestEnvelopeSourcesTopSources(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	server.ms.envelopeSources = newEnvelopeSourcesCollector(server.ms.db)
+
+	now := time.Now()
+	first := types.Hash{1}
+	second := types.Hash{2}
+
+	old := archiveEnvelope(t, now.Add(-2*time.Hour), server)
+	server.ms.envelopeSources.Received(types.Hash(old.Hash()), first)
+	for i := 0; i < 2; i++ {
+		env := archiveEnvelope(t, now.Add(-time.Duration(i+1)*time.Second), server)
+		server.ms.envelopeSources.Received(types.Hash(env.Hash()), second)
+		// duplicates delivered by other peers are ignored
+		server.ms.envelopeSources.Received(types.Hash(env.Hash()), first)
+	}
+	// envelopes that were not archived are ignored
+	server.ms.envelopeSources.Received(types.Hash{0xff}, first)
+
+	api := NewAdminAPI(server)
+	sources, err := api.GetTopSources(context.Background(), 10, 60)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, second, sources[0].Peer)
+	require.Equal(t, uint64(2), sources[0].Envelopes)
+
+	sources, err = api.GetTopSources(context.Background(), 1, 3*60*60)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, second, sources[0].Peer)
+
+	_, err = api.GetTopSources(context.Background(), 0, 60)
+	require.Equal(t, ErrInvalidSourcesNumber, err)
+}
+
+func TestEnvelopeSourcesPrune(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	server.ms.envelopeSources = newEnvelopeSourcesCollector(server.ms.db)
+	cleaner := newDBCleaner(server.ms.db, time.Hour)
+
+	now := time.Now()
+	for _, sent := range []time.Time{now.Add(-3 * time.Hour), now.Add(-time.Second)} {
+		env := archiveEnvelope(t, sent, server)
+		server.ms.envelopeSources.Received(types.Hash(env.Hash()), types.Hash{1})
+	}
+	require.NoError(t, server.ms.envelopeSources.Flush())
+
+	testPrune(t, now.Add(-time.Hour), 1, cleaner)
+	testMessagesCount(t, 1, server)
+
+	sources, err := server.ms.db.EnvelopeSources(time.Unix(0, 0))
+	require.NoError(t, err)
+	require.Equal(t, []EnvelopeSource{{Peer: types.Hash{1}, Envelopes: 1, Bytes: sources[0].Bytes}}, sources)
+}
+
+func TestEnvelopeSourcesPendingExpire(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	collector := newEnvelopeSourcesCollector(server.ms.db)
+	collector.ttl = 0
+	server.ms.envelopeSources = collector
+
+	env := archiveEnvelope(t, time.Now(), server)
+	require.NoError(t, collector.Flush())
+	collector.Received(types.Hash(env.Hash()), types.Hash{1})
+
+	sources, err := collector.TopSources(10, time.Unix(0, 0))
+	require.NoError(t, err)
+	require.Empty(t, sources)
+}
@@ -0,0 +1,96 @@
+package mailserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+const (
+	// defaultMaxEnvelopeTTL is used if the maximum TTL of archived envelopes is not configured.
+	defaultMaxEnvelopeTTL = 30 * 24 * time.Hour
+	// defaultMaxEnvelopeDrift is used if the maximum time of archived envelopes
+	// ahead of the current time is not configured.
+	defaultMaxEnvelopeDrift = time.Hour
+)
+
+// Reasons of rejected envelopes, used as labels of mailserver_archive_rejected_envelopes_total.
+const (
+	envelopeRejectedTTL    = "ttl"
+	envelopeRejectedFuture = "future"
+	envelopeRejectedPast   = "past"
+	envelopeRejectedSize   = "size"
+)
+
+// envelopeLimits are sanity limits of envelopes that are archived.
+type envelopeLimits struct {
+	// maxSize is a maximum size of an envelope in bytes, no limit if zero
+	maxSize uint32
+	maxTTL  time.Duration
+	// maxDrift is how far ahead of the current time an envelope can be sent
+	maxDrift time.Duration
+	// retention is how far in the past an envelope can be sent, no limit if zero
+	retention time.Duration
+}
+
+func newEnvelopeLimits(cfg Config) envelopeLimits {
+	limits := envelopeLimits{
+		maxSize:   cfg.MaxEnvelopeSize,
+		maxTTL:    cfg.MaxEnvelopeTTL,
+		maxDrift:  cfg.MaxEnvelopeDrift,
+		retention: time.Duration(cfg.DataRetention) * time.Hour * 24,
+	}
+	if limits.maxTTL == 0 {
+		limits.maxTTL = defaultMaxEnvelopeTTL
+	}
+	if limits.maxDrift == 0 {
+		limits.maxDrift = defaultMaxEnvelopeDrift
+	}
+	return limits
+}
+
+// envelopeValidator rejects malformed or adversarial envelopes before they are archived.
+// Envelopes that are pruned right away or that would be served for an unbounded time
+// never reach the database.
+type envelopeValidator struct {
+	now func() time.Time
+
+	mu     sync.RWMutex
+	limits envelopeLimits
+}
+
+func newEnvelopeValidator(limits envelopeLimits) *envelopeValidator {
+	return &envelopeValidator{now: time.Now, limits: limits}
+}
+
+// Update replaces limits, it is used when the config is reloaded.
+func (v *envelopeValidator) Update(limits envelopeLimits) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.limits = limits
+}
+
+// Validate returns a reason why the envelope must not be archived, empty if it's valid.
+func (v *envelopeValidator) Validate(env types.Envelope) string {
+	v.mu.RLock()
+	limits := v.limits
+	v.mu.RUnlock()
+
+	ttl := time.Duration(env.TTL()) * time.Second
+	if env.TTL() == 0 || ttl > limits.maxTTL || env.Expiry() < env.TTL() {
+		return envelopeRejectedTTL
+	}
+	if limits.maxSize > 0 && env.Size() > int(limits.maxSize) {
+		return envelopeRejectedSize
+	}
+	sent := time.Unix(int64(env.Expiry()-env.TTL()), 0)
+	now := v.now()
+	if sent.After(now.Add(limits.maxDrift)) {
+		return envelopeRejectedFuture
+	}
+	if limits.retention > 0 && sent.Before(now.Add(-limits.retention)) {
+		return envelopeRejectedPast
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package mailserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+type envelopeStub struct {
+	types.Envelope
+	ttl    uint32
+	expiry uint32
+	size   int
+}
+
+func (e envelopeStub) TTL() uint32    { return e.ttl }
+func (e envelopeStub) Expiry() uint32 { return e.expiry }
+func (e envelopeStub) Size() int      { return e.size }
+
+func TestEnvelopeValidator(t *testing.T) {
+	now := time.Unix(1590000000, 0)
+	sent := uint32(now.Unix())
+	validator := newEnvelopeValidator(newEnvelopeLimits(Config{MaxEnvelopeSize: 1024, DataRetention: 1}))
+	validator.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		name     string
+		envelope envelopeStub
+		reason   string
+	}{
+		{"valid", envelopeStub{ttl: 10, expiry: sent + 10, size: 512}, ""},
+		{"zero ttl", envelopeStub{ttl: 0, expiry: sent, size: 512}, envelopeRejectedTTL},
+		{"ttl above limit", envelopeStub{ttl: 31 * 24 * 3600, expiry: sent + 31*24*3600, size: 512}, envelopeRejectedTTL},
+		{"expiry below ttl", envelopeStub{ttl: 10, expiry: 5, size: 512}, envelopeRejectedTTL},
+		{"too large", envelopeStub{ttl: 10, expiry: sent + 10, size: 1025}, envelopeRejectedSize},
+		{"within drift", envelopeStub{ttl: 10, expiry: sent + 3600 + 10, size: 512}, ""},
+		{"future", envelopeStub{ttl: 10, expiry: sent + 3601 + 10, size: 512}, envelopeRejectedFuture},
+		{"past", envelopeStub{ttl: 10, expiry: sent - 24*3600 - 1 + 10, size: 512}, envelopeRejectedPast},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.reason, validator.Validate(tc.envelope))
+		})
+	}
+
+	// limits are replaced when the config is reloaded
+	validator.Update(newEnvelopeLimits(Config{}))
+	require.Empty(t, validator.Validate(envelopeStub{ttl: 10, expiry: sent - 24*3600 - 1 + 10, size: 2048}))
+}
+
+func TestArchiveRejectsInvalidEnvelopes(t *testing.T) {
+	server := setupTestServer(t)
+	server.ms.validator = newEnvelopeValidator(newEnvelopeLimits(Config{DataRetention: 1}))
+
+	archiveEnvelope(t, time.Now().Add(-48*time.Hour), server)
+	archiveEnvelope(t, time.Now().Add(-time.Minute), server)
+	require.Equal(t, 1, countMessages(t, server.ms.db))
+}
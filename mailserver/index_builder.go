@@ -0,0 +1,191 @@
+package mailserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// indexBuildRetryInterval is a period after which a failed index build is started again.
+const indexBuildRetryInterval = 10 * time.Minute
+
+// States of an index build.
+const (
+	IndexBuildPending  = "pending"
+	IndexBuildBuilding = "building"
+	IndexBuildDone     = "done"
+	IndexBuildFailed   = "failed"
+)
+
+// backgroundIndexer is implemented by databases with indexes that are too expensive
+// to create in a migration, because the archive would be unavailable during the build.
+type backgroundIndexer interface {
+	// BackgroundIndexes returns names of indexes built in the background
+	BackgroundIndexes() []string
+	// BuildIndex creates an index if it doesn't exist or resumes an interrupted build.
+	// The progress is reported as a fraction between 0 and 1 if it's known.
+	BuildIndex(ctx context.Context, name string, progress func(float64)) error
+}
+
+// IndexBuildProgress is a state of a single index build.
+type IndexBuildProgress struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// Progress is a fraction of the build between 0 and 1
+	Progress float64 `json:"progress"`
+	// StartedAt and FinishedAt are unix timestamps, zero if the build hasn't started or finished
+	StartedAt  int64  `json:"startedAt"`
+	FinishedAt int64  `json:"finishedAt"`
+	Error      string `json:"error,omitempty"`
+}
+
+type indexBuildTask struct {
+	name  string
+	build func(ctx context.Context, progress func(float64)) error
+}
+
+// indexBuilder builds indexes one by one in the background while the mailserver keeps
+// archiving and serving envelopes. Builds are resumable, an index interrupted by a restart
+// continues on the next start and a failed build is retried after an interval.
+type indexBuilder struct {
+	tasks []indexBuildTask
+
+	mu       sync.RWMutex
+	progress map[string]*IndexBuildProgress
+
+	retryInterval time.Duration
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func newIndexBuilder() *indexBuilder {
+	return &indexBuilder{
+		progress:      make(map[string]*IndexBuildProgress),
+		retryInterval: indexBuildRetryInterval,
+	}
+}
+
+// Add adds an index to build. It must be called before Start.
+func (b *indexBuilder) Add(name string, build func(ctx context.Context, progress func(float64)) error) {
+	b.tasks = append(b.tasks, indexBuildTask{name: name, build: build})
+	b.progress[name] = &IndexBuildProgress{Name: name, State: IndexBuildPending}
+	indexBuildProgressGauge.WithLabelValues(name).Set(0)
+}
+
+// AddIndexer adds all background indexes of a database.
+func (b *indexBuilder) AddIndexer(db backgroundIndexer) {
+	for _, name := range db.BackgroundIndexes() {
+		name := name
+		b.Add(name, func(ctx context.Context, progress func(float64)) error {
+			return db.BuildIndex(ctx, name, progress)
+		})
+	}
+}
+
+// Start starts building indexes.
+func (b *indexBuilder) Start() {
+	if len(b.tasks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.run(ctx)
+	}()
+}
+
+// Stop interrupts the current build and waits until it returns.
+func (b *indexBuilder) Stop() {
+	if b.cancel == nil {
+		return
+	}
+	b.cancel()
+	b.wg.Wait()
+	b.cancel = nil
+}
+
+func (b *indexBuilder) run(ctx context.Context) {
+	pending := b.tasks
+	for len(pending) > 0 {
+		var failed []indexBuildTask
+		for _, task := range pending {
+			if !b.build(ctx, task) {
+				failed = append(failed, task)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		pending = failed
+		if len(pending) == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.retryInterval):
+		}
+	}
+}
+
+// build runs a single task and returns false if it failed.
+func (b *indexBuilder) build(ctx context.Context, task indexBuildTask) bool {
+	log.Info("building mailserver index", "name", task.name)
+	b.update(task.name, func(p *IndexBuildProgress) {
+		p.State = IndexBuildBuilding
+		p.StartedAt = time.Now().Unix()
+		p.FinishedAt = 0
+		p.Error = ""
+	})
+	err := task.build(ctx, func(progress float64) {
+		b.update(task.name, func(p *IndexBuildProgress) {
+			p.Progress = progress
+		})
+	})
+	if ctx.Err() != nil {
+		log.Info("mailserver index build interrupted", "name", task.name)
+		b.update(task.name, func(p *IndexBuildProgress) {
+			p.State = IndexBuildPending
+		})
+		return false
+	}
+	if err != nil {
+		log.Error("failed to build mailserver index", "name", task.name, "err", err)
+		b.update(task.name, func(p *IndexBuildProgress) {
+			p.State = IndexBuildFailed
+			p.Error = err.Error()
+		})
+		return false
+	}
+	log.Info("built mailserver index", "name", task.name)
+	b.update(task.name, func(p *IndexBuildProgress) {
+		p.State = IndexBuildDone
+		p.Progress = 1
+		p.FinishedAt = time.Now().Unix()
+	})
+	return true
+}
+
+func (b *indexBuilder) update(name string, f func(*IndexBuildProgress)) {
+	b.mu.Lock()
+	p := b.progress[name]
+	f(p)
+	progress := p.Progress
+	b.mu.Unlock()
+	indexBuildProgressGauge.WithLabelValues(name).Set(progress)
+}
+
+// Progress returns states of all builds in the order they are built.
+func (b *indexBuilder) Progress() []IndexBuildProgress {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rst := make([]IndexBuildProgress, 0, len(b.tasks))
+	for _, task := range b.tasks {
+		rst = append(rst, *b.progress[task.name])
+	}
+	return rst
+}
@@ -0,0 +1,72 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func waitIndexBuilds(t *testing.T, b *indexBuilder, states ...string) []IndexBuildProgress {
+	var progress []IndexBuildProgress
+	require.Eventually(t, func() bool {
+		progress = b.Progress()
+		for n, p := range progress {
+			if p.State != states[n] {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return progress
+}
+
+func TestIndexBuilderRetriesFailedBuilds(t *testing.T) {
+	b := newIndexBuilder()
+	b.retryInterval = 50 * time.Millisecond
+
+	var attempts int
+	b.Add("first", func(ctx context.Context, progress func(float64)) error {
+		progress(0.5)
+		attempts++
+		if attempts == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	built := make(chan struct{})
+	b.Add("second", func(ctx context.Context, progress func(float64)) error {
+		close(built)
+		return nil
+	})
+	require.Equal(t, IndexBuildPending, b.Progress()[0].State)
+
+	b.Start()
+	defer b.Stop()
+
+	// a failed build doesn't block the next one
+	<-built
+	progress := waitIndexBuilds(t, b, IndexBuildDone, IndexBuildDone)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, "first", progress[0].Name)
+	require.Equal(t, float64(1), progress[0].Progress)
+	require.Empty(t, progress[0].Error)
+	require.NotZero(t, progress[0].FinishedAt)
+}
+
+func TestIndexBuilderStopInterruptsBuild(t *testing.T) {
+	b := newIndexBuilder()
+	started := make(chan struct{})
+	b.Add("index", func(ctx context.Context, progress func(float64)) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	b.Start()
+	<-started
+	waitIndexBuilds(t, b, IndexBuildBuilding)
+	b.Stop()
+	require.Equal(t, IndexBuildPending, b.Progress()[0].State)
+}
@@ -0,0 +1,182 @@
+package mailserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/params"
+)
+
+var (
+	errNoListener         = errors.New("requests are not accepted on this address")
+	errListenerNotAllowed = errors.New("peer is not allowed to make requests on this address")
+	errListenerRateLimit  = errors.New("rate limit exceeded")
+)
+
+// ListenerConfig limits requests of peers connected through matching local addresses.
+// The node listens on all addresses of ListenAddr, e.g. ":30303" listens on every
+// IPv4 and IPv6 interface, listeners tell apart connections by their local address.
+type ListenerConfig struct {
+	// Name labels metrics of the listener, Addr is used if empty.
+	Name string
+	// Addr is a local IPv4 or IPv6 address, optionally with a port, or a CIDR of local addresses.
+	Addr string
+	// RateLimit is a minimum time between requests of a peer if greater than zero.
+	RateLimit time.Duration
+	// AllowedPeers are enodes of peers allowed to make requests, all peers are allowed if empty.
+	AllowedPeers []string
+}
+
+func listenerConfigs(listeners []params.MailServerListener) []ListenerConfig {
+	rst := make([]ListenerConfig, len(listeners))
+	for i, l := range listeners {
+		rst[i] = ListenerConfig{
+			Name:         l.Name,
+			Addr:         l.Addr,
+			RateLimit:    time.Duration(l.RateLimit) * time.Second,
+			AllowedPeers: l.AllowedPeers,
+		}
+	}
+	return rst
+}
+
+// peerAddrResolver is implemented by services that know local addresses of connections with peers.
+type peerAddrResolver interface {
+	PeerLocalAddr(peerID []byte) (net.Addr, error)
+}
+
+type listener struct {
+	name    string
+	network *net.IPNet
+	// port matches any port if zero
+	port    int
+	limiter *rateLimiter
+	allowed map[types.Hash]struct{}
+}
+
+// parseListenAddr parses an address, an address with a port or a CIDR.
+func parseListenAddr(addr string) (*net.IPNet, int, error) {
+	if _, network, err := net.ParseCIDR(addr); err == nil {
+		return network, 0, nil
+	}
+	host, port := addr, 0
+	if h, p, err := net.SplitHostPort(addr); err == nil {
+		port, err = strconv.Atoi(p)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, 0, fmt.Errorf("invalid port of listener address %s", addr)
+		}
+		host = h
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip == nil {
+		return nil, 0, fmt.Errorf("invalid listener address %s", addr)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, port, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, port, nil
+}
+
+func newListener(cfg ListenerConfig) (*listener, error) {
+	network, port, err := parseListenAddr(cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parseEnodeIDs(cfg.AllowedPeers)
+	if err != nil {
+		return nil, err
+	}
+	l := &listener{
+		name:    cfg.Name,
+		network: network,
+		port:    port,
+		allowed: make(map[types.Hash]struct{}, len(ids)),
+	}
+	if l.name == "" {
+		l.name = cfg.Addr
+	}
+	for _, id := range ids {
+		l.allowed[id] = struct{}{}
+	}
+	if cfg.RateLimit > 0 {
+		l.limiter = newRateLimiter(cfg.RateLimit)
+	}
+	return l, nil
+}
+
+func (l *listener) matches(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	if l.port != 0 && l.port != tcp.Port {
+		return false
+	}
+	return l.network.Contains(tcp.IP)
+}
+
+// allow checks the allowed peers and the rate limit of the listener.
+func (l *listener) allow(peerID types.Hash) error {
+	if len(l.allowed) > 0 {
+		if _, ok := l.allowed[peerID]; !ok {
+			return errListenerNotAllowed
+		}
+	}
+	if l.limiter == nil {
+		return nil
+	}
+	if !l.limiter.IsAllowed(peerID.String()) {
+		return errListenerRateLimit
+	}
+	l.limiter.Add(peerID.String())
+	return nil
+}
+
+// listenerSet selects the first listener matching the local address of a peer connection.
+type listenerSet struct {
+	listeners []*listener
+}
+
+func newListenerSet(cfgs []ListenerConfig) (*listenerSet, error) {
+	set := &listenerSet{}
+	for _, cfg := range cfgs {
+		l, err := newListener(cfg)
+		if err != nil {
+			return nil, err
+		}
+		set.listeners = append(set.listeners, l)
+	}
+	return set, nil
+}
+
+func (s *listenerSet) Start() {
+	for _, l := range s.listeners {
+		if l.limiter != nil {
+			l.limiter.Start()
+		}
+	}
+}
+
+func (s *listenerSet) Stop() {
+	for _, l := range s.listeners {
+		if l.limiter != nil {
+			l.limiter.Stop()
+		}
+	}
+}
+
+// Allow checks a request of a peer connected through the local address
+// against the matching listener and returns the name of the listener.
+func (s *listenerSet) Allow(peerID types.Hash, addr net.Addr) (string, error) {
+	for _, l := range s.listeners {
+		if l.matches(addr) {
+			return l.name, l.allow(peerID)
+		}
+	}
+	return "", errNoListener
+}
@@ -0,0 +1,103 @@
+package mailserver
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+type testPeerAddrs map[types.Hash]net.Addr
+
+func (a testPeerAddrs) PeerLocalAddr(peerID []byte) (net.Addr, error) {
+	addr, ok := a[types.BytesToHash(peerID)]
+	if !ok {
+		return nil, errors.New("unknown peer")
+	}
+	return addr, nil
+}
+
+func tcpAddr(ip string, port int) net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(ip), Port: port}
+}
+
+func TestParseListenAddr(t *testing.T) {
+	for _, addr := range []string{"10.8.0.1", "10.8.0.1:30303", "10.8.0.0/24", "::1", "[::1]:30303", "2001:db8::/32"} {
+		_, _, err := parseListenAddr(addr)
+		require.NoError(t, err, addr)
+	}
+	for _, addr := range []string{"", "localhost", "10.8.0.1:0", "[::1]:port", "10.8.0.0/33"} {
+		_, _, err := parseListenAddr(addr)
+		require.Error(t, err, addr)
+	}
+}
+
+func TestListenerSet(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	allowed := enode.NewV4(&key.PublicKey, net.ParseIP("127.0.0.1"), 30303, 30303)
+	allowedID := types.Hash(allowed.ID())
+
+	set, err := newListenerSet([]ListenerConfig{
+		{Name: "vpn", Addr: "10.8.0.0/24"},
+		{Name: "private6", Addr: "[2001:db8::1]:30303", AllowedPeers: []string{allowed.URLv4()}},
+		{Addr: "0.0.0.0/0", RateLimit: time.Hour},
+	})
+	require.NoError(t, err)
+	set.Start()
+	defer set.Stop()
+
+	// listeners without limits accept every request
+	for i := 0; i < 2; i++ {
+		name, err := set.Allow(types.Hash{1}, tcpAddr("10.8.0.1", 30303))
+		require.NoError(t, err)
+		require.Equal(t, "vpn", name)
+	}
+
+	// IPv4 addresses of dual-stack sockets match IPv4 listeners
+	name, err := set.Allow(types.Hash{1}, tcpAddr("::ffff:192.0.2.1", 30303))
+	require.NoError(t, err)
+	require.Equal(t, "0.0.0.0/0", name)
+	_, err = set.Allow(types.Hash{1}, tcpAddr("192.0.2.1", 30303))
+	require.Equal(t, errListenerRateLimit, err)
+	// limits are independent per listener and peer
+	_, err = set.Allow(types.Hash{2}, tcpAddr("192.0.2.1", 30303))
+	require.NoError(t, err)
+
+	_, err = set.Allow(types.Hash{1}, tcpAddr("2001:db8::1", 30303))
+	require.Equal(t, errListenerNotAllowed, err)
+	_, err = set.Allow(allowedID, tcpAddr("2001:db8::1", 30303))
+	require.NoError(t, err)
+	_, err = set.Allow(allowedID, tcpAddr("2001:db8::1", 30304))
+	require.Equal(t, errNoListener, err)
+
+	_, err = newListenerSet([]ListenerConfig{{Addr: "10.8.0.1", AllowedPeers: []string{"invalid"}}})
+	require.Error(t, err)
+}
+
+func TestCheckListener(t *testing.T) {
+	s := &mailServer{}
+	// listeners are disabled by default
+	require.NoError(t, s.checkListener(types.Hash{1}))
+
+	listeners, err := newListenerSet([]ListenerConfig{{Addr: "10.8.0.1"}})
+	require.NoError(t, err)
+	s.listeners = listeners
+	// addresses of peers are not available
+	require.NoError(t, s.checkListener(types.Hash{1}))
+
+	s.peerAddrs = testPeerAddrs{
+		{1}: tcpAddr("10.8.0.1", 30303),
+		{2}: tcpAddr("192.0.2.1", 30303),
+	}
+	require.NoError(t, s.checkListener(types.Hash{1}))
+	require.Equal(t, errNoListener, s.checkListener(types.Hash{2}))
+	require.Error(t, s.checkListener(types.Hash{3}))
+}
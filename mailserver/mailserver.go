@@ -17,18 +17,22 @@
 package mailserver
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	prom "github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
@@ -50,6 +54,9 @@ const (
 var (
 	errDirectoryNotProvided        = errors.New("data directory not provided")
 	errDecryptionMethodNotProvided = errors.New("decryption method is not provided")
+	errDeliveryCanceled            = errors.New("delivery canceled")
+	errDeliveryStalled             = errors.New("peer stalled")
+	errQueryTimeout                = errors.New("query timed out")
 )
 
 const (
@@ -57,6 +64,12 @@ const (
 	requestLimitLength     = 4
 	requestTimeRangeLength = timestampLength * 2
 	processRequestTimeout  = time.Minute
+	// defaultConsistencyCheckWindow is a number of minutes validated by the consistency check.
+	defaultConsistencyCheckWindow = 60
+	// deliveryQueueSize is a number of bundles queued for a peer.
+	// Together with the max message size it bounds memory used
+	// for a single request.
+	deliveryQueueSize = 5
 )
 
 type Config struct {
@@ -70,10 +83,71 @@ type Config struct {
 	MinimumPoW float64
 	// RateLimit is a maximum number of requests per second from a peer.
 	RateLimit int
+	// MaxQueryLimit is a maximum number of envelopes returned in a single response.
+	MaxQueryLimit uint32
+	// MaxResponseSize is a maximum total size of envelopes returned in a single response.
+	MaxResponseSize uint32
+	// QueryCacheSize is a number of response pages cached for repeated requests.
+	QueryCacheSize int
+	// QueryTimeout limits the duration of a single database query if greater than zero.
+	// Envelopes found before the timeout are sent with a cursor to resume from.
+	QueryTimeout time.Duration
+	// MaxConcurrentQueries limits the number of history queries processed at the same time
+	// if greater than zero. Queued queries are served in turns across peers.
+	MaxConcurrentQueries int
+	// AckBatchSize is a number of envelopes in a batch sent to clients that acknowledge batches, 100 if zero.
+	AckBatchSize int
+	// AckTimeout is how long an acknowledgement of a batch is awaited, 30 seconds if zero.
+	AckTimeout time.Duration
 	// DataRetention specifies a number of days an envelope should be stored for.
-	DataRetention   int
-	PostgresEnabled bool
-	PostgresURI     string
+	DataRetention int
+	// SoftDeleteWindow enables soft delete of pruned envelopes if greater than zero.
+	// Pruned envelopes can be restored within the window.
+	SoftDeleteWindow  time.Duration
+	PostgresEnabled   bool
+	PostgresURI       string
+	PostgresShardURIs []string
+	PostgresTLS       PostgresTLSConfig
+	// PostgresSpillDir enables a local queue of envelopes that could not be saved to Postgres.
+	PostgresSpillDir string
+	// PostgresTenant scopes envelopes in a Postgres database shared by multiple networks.
+	PostgresTenant TenantConfig
+	// Replicas are enodes of follower mailservers that receive archived envelopes.
+	Replicas []string
+	// ConsistencyCheckWindow enables validation of LevelDB envelopes archived
+	// within the window before the start if greater than zero.
+	ConsistencyCheckWindow time.Duration
+	// TopicIndex enables an index of topics per hour that is used to select envelopes
+	// by topics matching a bloom filter of a request.
+	TopicIndex bool
+	// BloomIndex enables blooms aggregated per hour that are used to skip time ranges
+	// without envelopes matching a bloom filter of a request.
+	BloomIndex bool
+	// QueryTiers restrict requests by the age of the oldest requested envelope.
+	QueryTiers []QueryTier
+	// AllowedPeers are enodes of peers allowed to make requests of tiers restricted to allowed peers.
+	AllowedPeers []string
+	// Listeners apply rate limits and allowed peers per local address of peer connections.
+	// Requests through addresses not matching any listener are rejected.
+	Listeners []ListenerConfig
+	// CompactionInterval enables periodic compaction of LevelDB archives if greater than zero.
+	// The archive is compacted at most once per interval, when no requests are received within the idle window.
+	CompactionInterval   time.Duration
+	CompactionIdleWindow time.Duration
+	// DisableCompression disables compression of batches of envelopes
+	// for peers that accept compressed responses.
+	DisableCompression bool
+	// BuildIndexes enables a background build of database indexes and of the topic index
+	// of envelopes archived before the index was enabled.
+	BuildIndexes bool
+	// MaxEnvelopeSize is a maximum size of archived envelopes in bytes, no limit if zero.
+	MaxEnvelopeSize uint32
+	// MaxEnvelopeTTL is a maximum TTL of archived envelopes, 30 days if zero.
+	MaxEnvelopeTTL time.Duration
+	// MaxEnvelopeDrift is how far ahead of the current time archived envelopes can be sent, an hour if zero.
+	MaxEnvelopeDrift time.Duration
+	// ReadOnly disables archiving of envelopes, requests are still served.
+	ReadOnly bool
 }
 
 // -----------------
@@ -87,23 +161,72 @@ type WhisperMailServer struct {
 
 	symFilter  *whisper.Filter
 	asymFilter *whisper.Filter
+
+	eventsSub event.Subscription
+	// follower replicates envelopes of a primary mailserver
+	follower *follower
+	// backfiller requests envelopes of coverage gaps from peer mailservers
+	backfiller *backfiller
+}
+
+// whisperMailServerConfig returns the mailserver configuration from the Whisper section of the node config.
+func whisperMailServerConfig(cfg *params.WhisperConfig) Config {
+	return Config{
+		DataDir:                cfg.DataDir,
+		Password:               cfg.MailServerPassword,
+		AsymKey:                cfg.MailServerAsymKey,
+		MinimumPoW:             cfg.MinimumPoW,
+		DataRetention:          cfg.MailServerDataRetention,
+		RateLimit:              cfg.MailServerRateLimit,
+		MaxQueryLimit:          cfg.MailServerMaxQueryLimit,
+		MaxResponseSize:        cfg.MailServerMaxResponseSize,
+		QueryCacheSize:         cfg.MailServerQueryCacheSize,
+		QueryTimeout:           time.Duration(cfg.MailServerQueryTimeout) * time.Second,
+		MaxConcurrentQueries:   cfg.MailServerMaxConcurrentQueries,
+		AckBatchSize:           cfg.MailServerAckBatchSize,
+		AckTimeout:             time.Duration(cfg.MailServerAckTimeout) * time.Second,
+		TopicIndex:             cfg.MailServerTopicIndex,
+		BloomIndex:             cfg.MailServerBloomIndex,
+		ConsistencyCheckWindow: consistencyCheckWindow(cfg.MailServerConsistencyCheck, cfg.MailServerConsistencyCheckWindow),
+		SoftDeleteWindow:       time.Duration(cfg.MailServerSoftDeleteWindow) * time.Hour,
+		QueryTiers:             queryTiers(cfg.MailServerQueryTiers),
+		AllowedPeers:           cfg.MailServerAllowedPeers,
+		Listeners:              listenerConfigs(cfg.MailServerListeners),
+		CompactionInterval:     time.Duration(cfg.MailServerCompactionInterval) * time.Hour,
+		CompactionIdleWindow:   time.Duration(cfg.MailServerCompactionIdleWindow) * time.Minute,
+		DisableCompression:     cfg.MailServerDisableCompression,
+		BuildIndexes:           cfg.MailServerBuildIndexes,
+		MaxEnvelopeSize:        cfg.MailServerMaxEnvelopeSize,
+		MaxEnvelopeTTL:         time.Duration(cfg.MailServerMaxEnvelopeTTL) * time.Second,
+		MaxEnvelopeDrift:       time.Duration(cfg.MailServerMaxEnvelopeDrift) * time.Second,
+		ReadOnly:               cfg.MailServerReadOnly,
+		PostgresEnabled:        cfg.DatabaseConfig.PGConfig.Enabled,
+		PostgresURI:            cfg.DatabaseConfig.PGConfig.URI,
+		PostgresShardURIs:      cfg.DatabaseConfig.PGConfig.ShardURIs,
+		PostgresTLS:            postgresTLSConfig(cfg.DatabaseConfig.PGConfig),
+		PostgresSpillDir:       cfg.DatabaseConfig.PGConfig.SpillDir,
+		PostgresTenant:         postgresTenantConfig(cfg.DatabaseConfig.PGConfig),
+		Replicas:               cfg.MailServerReplicas,
+	}
 }
 
 func (s *WhisperMailServer) Init(shh *whisper.Whisper, cfg *params.WhisperConfig) error {
 	s.shh = shh
 	s.minRequestPoW = cfg.MinimumPoW
 
-	config := Config{
-		DataDir:         cfg.DataDir,
-		Password:        cfg.MailServerPassword,
-		AsymKey:         cfg.MailServerAsymKey,
-		MinimumPoW:      cfg.MinimumPoW,
-		DataRetention:   cfg.MailServerDataRetention,
-		RateLimit:       cfg.MailServerRateLimit,
-		PostgresEnabled: cfg.DatabaseConfig.PGConfig.Enabled,
-		PostgresURI:     cfg.DatabaseConfig.PGConfig.URI,
+	config := whisperMailServerConfig(cfg)
+	var primary []types.Hash
+	if cfg.MailServerPrimary != "" {
+		var err error
+		primary, err = parseEnodeIDs([]string{cfg.MailServerPrimary})
+		if err != nil {
+			return err
+		}
+	}
+	backfillPeers, err := parseEnodeIDs(cfg.MailServerBackfillPeers)
+	if err != nil {
+		return err
 	}
-	var err error
 	s.ms, err = newMailServer(
 		config,
 		&whisperAdapter{},
@@ -117,10 +240,68 @@ func (s *WhisperMailServer) Init(shh *whisper.Whisper, cfg *params.WhisperConfig
 		return err
 	}
 
+	retention := time.Duration(config.DataRetention) * time.Hour * 24
+	if len(primary) > 0 {
+		s.follower = newFollower(shh, primary[0], config.DataDir, retention)
+		s.follower.Start()
+	}
+	if len(backfillPeers) > 0 {
+		s.backfiller = newBackfiller(shh, backfillPeers, s.ms.coverage, s.ms.isReadOnly, retention)
+		s.backfiller.Start()
+	}
+
+	s.watchEnvelopeSources()
+
 	return nil
 }
 
+// watchEnvelopeSources passes peers that delivered envelopes and acknowledged batches
+// to the mailserver and finished sync responses to the follower and the backfiller.
+func (s *WhisperMailServer) watchEnvelopeSources() {
+	events := make(chan whisper.EnvelopeEvent, 100)
+	s.eventsSub = s.shh.SubscribeEnvelopeEvents(events)
+	go func() {
+		for {
+			select {
+			case ev := <-events:
+				switch ev.Event {
+				case whisper.EventEnvelopeReceived:
+					s.ms.envelopeSources.Received(types.Hash(ev.Hash), types.Hash(ev.Peer))
+				case whisper.EventBatchAcknowledged:
+					s.ms.acks.Acknowledged(types.Hash(ev.Peer), types.Hash(ev.Batch))
+				case whisper.EventMailServerSyncFinished:
+					resp, ok := ev.Data.(whisper.SyncEventResponse)
+					if !ok {
+						continue
+					}
+					if s.follower != nil {
+						s.follower.SyncFinished(types.Hash(ev.Peer), resp.Cursor, resp.Error)
+					}
+					// replication gaps are reported by the primary outside of sync requests
+					if _, ok := parseReplicationGap(resp.Error); ok {
+						continue
+					}
+					if s.backfiller != nil {
+						s.backfiller.SyncFinished(types.Hash(ev.Peer), resp.Cursor, resp.Error)
+					}
+				}
+			case <-s.eventsSub.Err():
+				return
+			}
+		}
+	}()
+}
+
 func (s *WhisperMailServer) Close() {
+	if s.follower != nil {
+		s.follower.Stop()
+	}
+	if s.backfiller != nil {
+		s.backfiller.Stop()
+	}
+	if s.eventsSub != nil {
+		s.eventsSub.Unsubscribe()
+	}
 	if s.ms != nil {
 		s.ms.Close()
 	}
@@ -225,7 +406,8 @@ func (s *WhisperMailServer) decodeRequest(peerID []byte, request *whisper.Envelo
 		return payload, fmt.Errorf("check message signature failed: %v", err)
 	}
 
-	if err := rlp.DecodeBytes(decrypted.Payload, &payload); err != nil {
+	payload, err := decodeMessagesRequestPayload(decrypted.Payload)
+	if err != nil {
 		return payload, fmt.Errorf("failed to decode data: %v", err)
 	}
 
@@ -334,21 +516,54 @@ type WakuMailServer struct {
 
 	symFilter  *waku.Filter
 	asymFilter *waku.Filter
+
+	eventsSub event.Subscription
+}
+
+// wakuMailServerConfig returns the mailserver configuration from the Waku section of the node config.
+func wakuMailServerConfig(cfg *params.WakuConfig) Config {
+	return Config{
+		DataDir:                cfg.DataDir,
+		Password:               cfg.MailServerPassword,
+		MinimumPoW:             cfg.MinimumPoW,
+		DataRetention:          cfg.MailServerDataRetention,
+		RateLimit:              cfg.MailServerRateLimit,
+		MaxQueryLimit:          cfg.MailServerMaxQueryLimit,
+		MaxResponseSize:        cfg.MailServerMaxResponseSize,
+		QueryCacheSize:         cfg.MailServerQueryCacheSize,
+		QueryTimeout:           time.Duration(cfg.MailServerQueryTimeout) * time.Second,
+		MaxConcurrentQueries:   cfg.MailServerMaxConcurrentQueries,
+		AckBatchSize:           cfg.MailServerAckBatchSize,
+		AckTimeout:             time.Duration(cfg.MailServerAckTimeout) * time.Second,
+		TopicIndex:             cfg.MailServerTopicIndex,
+		BloomIndex:             cfg.MailServerBloomIndex,
+		ConsistencyCheckWindow: consistencyCheckWindow(cfg.MailServerConsistencyCheck, cfg.MailServerConsistencyCheckWindow),
+		SoftDeleteWindow:       time.Duration(cfg.MailServerSoftDeleteWindow) * time.Hour,
+		QueryTiers:             queryTiers(cfg.MailServerQueryTiers),
+		AllowedPeers:           cfg.MailServerAllowedPeers,
+		Listeners:              listenerConfigs(cfg.MailServerListeners),
+		CompactionInterval:     time.Duration(cfg.MailServerCompactionInterval) * time.Hour,
+		CompactionIdleWindow:   time.Duration(cfg.MailServerCompactionIdleWindow) * time.Minute,
+		DisableCompression:     cfg.MailServerDisableCompression,
+		BuildIndexes:           cfg.MailServerBuildIndexes,
+		MaxEnvelopeSize:        cfg.MailServerMaxEnvelopeSize,
+		MaxEnvelopeTTL:         time.Duration(cfg.MailServerMaxEnvelopeTTL) * time.Second,
+		MaxEnvelopeDrift:       time.Duration(cfg.MailServerMaxEnvelopeDrift) * time.Second,
+		ReadOnly:               cfg.MailServerReadOnly,
+		PostgresEnabled:        cfg.DatabaseConfig.PGConfig.Enabled,
+		PostgresURI:            cfg.DatabaseConfig.PGConfig.URI,
+		PostgresShardURIs:      cfg.DatabaseConfig.PGConfig.ShardURIs,
+		PostgresTLS:            postgresTLSConfig(cfg.DatabaseConfig.PGConfig),
+		PostgresSpillDir:       cfg.DatabaseConfig.PGConfig.SpillDir,
+		PostgresTenant:         postgresTenantConfig(cfg.DatabaseConfig.PGConfig),
+	}
 }
 
 func (s *WakuMailServer) Init(waku *waku.Waku, cfg *params.WakuConfig) error {
 	s.shh = waku
 	s.minRequestPoW = cfg.MinimumPoW
 
-	config := Config{
-		DataDir:         cfg.DataDir,
-		Password:        cfg.MailServerPassword,
-		MinimumPoW:      cfg.MinimumPoW,
-		DataRetention:   cfg.MailServerDataRetention,
-		RateLimit:       cfg.MailServerRateLimit,
-		PostgresEnabled: cfg.DatabaseConfig.PGConfig.Enabled,
-		PostgresURI:     cfg.DatabaseConfig.PGConfig.URI,
-	}
+	config := wakuMailServerConfig(cfg)
 	var err error
 	s.ms, err = newMailServer(
 		config,
@@ -363,10 +578,36 @@ func (s *WakuMailServer) Init(waku *waku.Waku, cfg *params.WakuConfig) error {
 		return err
 	}
 
+	s.watchEnvelopeSources()
+
 	return nil
 }
 
+// watchEnvelopeSources passes peers that delivered envelopes and acknowledged batches to the mailserver.
+func (s *WakuMailServer) watchEnvelopeSources() {
+	events := make(chan waku.EnvelopeEvent, 100)
+	s.eventsSub = s.shh.SubscribeEnvelopeEvents(events)
+	go func() {
+		for {
+			select {
+			case ev := <-events:
+				switch ev.Event {
+				case waku.EventEnvelopeReceived:
+					s.ms.envelopeSources.Received(types.Hash(ev.Hash), types.Hash(ev.Peer))
+				case waku.EventBatchAcknowledged:
+					s.ms.acks.Acknowledged(types.Hash(ev.Peer), types.Hash(ev.Batch))
+				}
+			case <-s.eventsSub.Err():
+				return
+			}
+		}
+	}()
+}
+
 func (s *WakuMailServer) Close() {
+	if s.eventsSub != nil {
+		s.eventsSub.Unsubscribe()
+	}
 	s.ms.Close()
 }
 
@@ -467,7 +708,8 @@ func (s *WakuMailServer) decodeRequest(peerID []byte, request *waku.Envelope) (M
 		return payload, fmt.Errorf("check message signature failed: %v", err)
 	}
 
-	if err := rlp.DecodeBytes(decrypted.Payload, &payload); err != nil {
+	payload, err := decodeMessagesRequestPayload(decrypted.Payload)
+	if err != nil {
 		return payload, fmt.Errorf("failed to decode data: %v", err)
 	}
 
@@ -490,8 +732,12 @@ func (s *WakuMailServer) decodeRequest(peerID []byte, request *waku.Envelope) (M
 type adapter interface {
 	CreateRequestFailedPayload(reqID types.Hash, err error) []byte
 	CreateRequestCompletedPayload(reqID, lastEnvelopeHash types.Hash, cursor []byte) []byte
+	CreateRequestDigestPayload(reqID types.Hash, cursor []byte, keys [][]byte) []byte
 	CreateSyncResponse(envelopes []types.Envelope, cursor []byte, final bool, err string) interface{}
 	CreateRawSyncResponse(envelopes []rlp.RawValue, cursor []byte, final bool, err string) interface{}
+	// P2PBatchHash returns a hash of a message with envelopes sent with SendRawP2PDirect,
+	// it is used by clients to acknowledge the batch.
+	P2PBatchHash(envelopes []rlp.RawValue) (types.Hash, error)
 }
 
 // --------------
@@ -510,6 +756,10 @@ func (whisperAdapter) CreateRequestCompletedPayload(reqID, lastEnvelopeHash type
 	return whisper.CreateMailServerRequestCompletedPayload(common.Hash(reqID), common.Hash(lastEnvelopeHash), cursor)
 }
 
+func (whisperAdapter) CreateRequestDigestPayload(reqID types.Hash, cursor []byte, keys [][]byte) []byte {
+	return whisper.CreateMailServerRequestDigestPayload(common.Hash(reqID), cursor, keys)
+}
+
 func (whisperAdapter) CreateSyncResponse(envelopes []types.Envelope, cursor []byte, final bool, err string) interface{} {
 	whisperEnvelopes := make([]*whisper.Envelope, len(envelopes))
 	for i, env := range envelopes {
@@ -532,6 +782,18 @@ func (whisperAdapter) CreateRawSyncResponse(envelopes []rlp.RawValue, cursor []b
 	}
 }
 
+func (whisperAdapter) P2PBatchHash(envelopes []rlp.RawValue) (types.Hash, error) {
+	// a single envelope is not sent in a list
+	if len(envelopes) == 1 {
+		return crypto.Keccak256Hash(envelopes[0]), nil
+	}
+	data, err := rlp.EncodeToBytes(envelopes)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
 // -----------
 // wakuAdapter
 // -----------
@@ -548,6 +810,10 @@ func (wakuAdapter) CreateRequestCompletedPayload(reqID, lastEnvelopeHash types.H
 	return waku.CreateMailServerRequestCompletedPayload(common.Hash(reqID), common.Hash(lastEnvelopeHash), cursor)
 }
 
+func (wakuAdapter) CreateRequestDigestPayload(reqID types.Hash, cursor []byte, keys [][]byte) []byte {
+	return waku.CreateMailServerRequestDigestPayload(common.Hash(reqID), cursor, keys)
+}
+
 func (wakuAdapter) CreateSyncResponse(_ []types.Envelope, _ []byte, _ bool, _ string) interface{} {
 	return nil
 }
@@ -556,6 +822,14 @@ func (wakuAdapter) CreateRawSyncResponse(_ []rlp.RawValue, _ []byte, _ bool, _ s
 	return nil
 }
 
+func (wakuAdapter) P2PBatchHash(envelopes []rlp.RawValue) (types.Hash, error) {
+	data, err := rlp.EncodeToBytes(envelopes)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
 // -------
 // service
 // -------
@@ -613,12 +887,72 @@ func (s *wakuService) SendSyncResponse(peerID []byte, data interface{}) error {
 // ----------
 
 type mailServer struct {
-	adapter       adapter
-	service       service
-	db            DB
-	cleaner       *dbCleaner // removes old envelopes
+	adapter   adapter
+	service   service
+	db        DB
+	muCleaner sync.RWMutex
+	cleaner   *dbCleaner // removes old envelopes
+	// compactor compacts LevelDB archives when no requests are received
+	compactor *dbCompactor
+	// lastRequest is the unix time in nanoseconds of the last received request
+	lastRequest   int64
 	muRateLimiter sync.RWMutex
 	rateLimiter   *rateLimiter
+	topicStats    *topicStatsCollector
+	// envelopeSources records peers that delivered archived envelopes
+	envelopeSources *envelopeSourcesCollector
+	// replicator streams archived envelopes to followers
+	replicator *replicator
+	// queryCache keeps response pages of repeated requests
+	queryCache *queryCache
+	// topicIndex narrows requests with a bloom filter down to topics
+	topicIndex *topicIndex
+	// bloomIndex skips time ranges without envelopes matching a bloom filter
+	bloomIndex *bloomIndex
+	// defaultOrder is the order in which the database returns envelopes of requests with OrderDefault
+	defaultOrder Order
+	// indexBuilder builds indexes in the background
+	indexBuilder *indexBuilder
+	// validator rejects envelopes that must not be archived
+	validator *envelopeValidator
+	// coverage records time ranges during which envelopes were not archived
+	coverage *coverageTracker
+	// maxQueryLimit overrides maxQueryLimit if greater than zero.
+	maxQueryLimit uint32
+	// maxResponseSize limits a total size of envelopes
+	// in a single response if greater than zero.
+	maxResponseSize uint32
+	// queryTimeout limits the duration of a database query if greater than zero.
+	queryTimeout int64
+	// queryScheduler limits the number of concurrent history queries
+	queryScheduler *queryScheduler
+	// queryPolicy restricts requests by the age of requested envelopes
+	muQueryPolicy sync.RWMutex
+	queryPolicy   *queryPolicy
+	// listeners limit requests per local address of peer connections
+	muListeners sync.RWMutex
+	listeners   *listenerSet
+	peerAddrs   peerAddrResolver
+	// acks notifies deliveries about batches acknowledged by peers
+	acks *ackTracker
+	// deliveries are acknowledged deliveries running in the background
+	deliveries sync.WaitGroup
+	// quit is closed when the mail server is closed
+	quit chan struct{}
+	// ackBatchSize and ackTimeout apply to requests with an ack window
+	ackBatchSize int64
+	ackTimeout   int64
+	// compressionDisabled is 1 if batches are never compressed
+	compressionDisabled uint32
+	compression         *compressionStats
+	// readOnly is 1 if envelopes are not archived
+	readOnly uint32
+	// postgresHealth reports health of postgres connection pools, nil for LevelDB
+	postgresHealth postgresHealthReporter
+
+	// muReload serializes config reloads, config is the currently applied config
+	muReload sync.Mutex
+	config   Config
 }
 
 func newMailServer(cfg Config, adapter adapter, service service) (*mailServer, error) {
@@ -631,24 +965,83 @@ func newMailServer(cfg Config, adapter adapter, service service) (*mailServer, e
 		return nil, errDecryptionMethodNotProvided
 	}
 
+	replicas, err := parseEnodeIDs(cfg.Replicas)
+	if err != nil {
+		return nil, err
+	}
+
 	s := mailServer{
-		adapter: adapter,
-		service: service,
+		adapter:         adapter,
+		service:         service,
+		maxQueryLimit:   cfg.MaxQueryLimit,
+		maxResponseSize: cfg.MaxResponseSize,
+		queryTimeout:    int64(cfg.QueryTimeout),
+		queryScheduler:  newQueryScheduler(cfg.MaxConcurrentQueries),
+		acks:            newAckTracker(),
+		quit:            make(chan struct{}),
+		ackBatchSize:    int64(cfg.AckBatchSize),
+		ackTimeout:      int64(cfg.AckTimeout),
+		validator:       newEnvelopeValidator(newEnvelopeLimits(cfg)),
+		config:          cfg,
+	}
+
+	if len(cfg.QueryTiers) > 0 {
+		s.queryPolicy, err = newQueryPolicy(cfg.QueryTiers, cfg.AllowedPeers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.QueryCacheSize > 0 {
+		s.queryCache, err = newQueryCache(cfg.QueryCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.DisableCompression {
+		s.compressionDisabled = 1
+	}
+	s.compression, err = newCompressionStats(compressionStatsPeers)
+	if err != nil {
+		return nil, err
 	}
 
 	if cfg.RateLimit > 0 {
 		s.setupRateLimiter(time.Duration(cfg.RateLimit) * time.Second)
 	}
 
+	s.peerAddrs, _ = service.(peerAddrResolver)
+	if len(cfg.Listeners) > 0 {
+		s.listeners, err = newListenerSet(cfg.Listeners)
+		if err != nil {
+			return nil, err
+		}
+		s.listeners.Start()
+		if s.peerAddrs == nil {
+			log.Warn("mailserver listeners are ignored, addresses of peers are not available")
+		}
+	}
+
 	// Open database in the last step in order not to init with error
 	// and leave the database open by accident.
-	if cfg.PostgresEnabled {
+	if cfg.PostgresEnabled && len(cfg.PostgresShardURIs) > 0 {
+		log.Info("Connecting to sharded postgres database", "shards", len(cfg.PostgresShardURIs))
+		database, err := NewShardedPostgresDB(cfg.PostgresShardURIs, cfg.PostgresTLS, cfg.PostgresTenant)
+		if err != nil {
+			return nil, fmt.Errorf("open DB: %s", err)
+		}
+		s.db = database
+		s.defaultOrder = OrderDescending
+		log.Info("Connected to sharded postgres database")
+	} else if cfg.PostgresEnabled {
 		log.Info("Connecting to postgres database")
-		database, err := NewPostgresDB(cfg.PostgresURI)
+		database, err := NewPostgresDB(cfg.PostgresURI, cfg.PostgresTLS, cfg.PostgresTenant)
 		if err != nil {
 			return nil, fmt.Errorf("open DB: %s", err)
 		}
 		s.db = database
+		s.defaultOrder = OrderDescending
 		log.Info("Connected to postgres database")
 	} else {
 		// Defaults to LevelDB
@@ -657,16 +1050,117 @@ func newMailServer(cfg Config, adapter adapter, service service) (*mailServer, e
 			return nil, fmt.Errorf("open DB: %s", err)
 		}
 		s.db = database
+		s.defaultOrder = OrderAscending
+		if cfg.ConsistencyCheckWindow > 0 {
+			checkConsistency(database, time.Now().Add(-cfg.ConsistencyCheckWindow))
+		}
+		if cfg.CompactionInterval > 0 {
+			s.compactor = newDBCompactor(database, cfg.CompactionInterval, cfg.CompactionIdleWindow, s.lastRequestTime)
+			s.compactor.Start()
+		}
+	}
+
+	// the spill queue doesn't build indexes of the wrapped database
+	indexer, _ := s.db.(backgroundIndexer)
+	s.postgresHealth, _ = s.db.(postgresHealthReporter)
+
+	if cfg.PostgresEnabled && cfg.PostgresSpillDir != "" {
+		// queued envelopes are archived before they are in the database,
+		// pages cached in between must be dropped once they are moved
+		var drained func(uint32)
+		if s.queryCache != nil {
+			drained = s.queryCache.Archived
+		}
+		database, err := NewSpillDB(s.db, cfg.PostgresSpillDir, drained)
+		if err != nil {
+			_ = s.db.Close()
+			return nil, fmt.Errorf("open spill queue: %s", err)
+		}
+		s.db = database
+	}
+	s.setReadOnly(cfg.ReadOnly)
+
+	if cfg.TopicIndex {
+		s.topicIndex, err = newTopicIndex(s.db, time.Now())
+		if err != nil {
+			log.Error("failed to read topic index, requests use bloom filters", "err", err)
+		}
+	} else if err := s.db.PruneTopicIndex(math.MaxUint32); err != nil {
+		// Envelopes archived while the index is disabled are not indexed,
+		// so the index is dropped and built from scratch when it's enabled again.
+		log.Error("failed to drop topic index", "err", err)
+	}
+	if cfg.BloomIndex {
+		s.bloomIndex, err = newBloomIndex(s.db, time.Now())
+		if err != nil {
+			log.Error("failed to read bloom index, requests read all time ranges", "err", err)
+		}
+	} else if err := s.db.PruneBloomIndex(math.MaxUint32); err != nil {
+		log.Error("failed to drop bloom index", "err", err)
+	}
+
+	if cfg.BuildIndexes {
+		s.indexBuilder = newIndexBuilder()
+		if indexer != nil {
+			s.indexBuilder.AddIndexer(indexer)
+		}
+		if s.topicIndex != nil {
+			s.indexBuilder.Add("topic_index", s.topicIndex.Backfill)
+		}
+		if s.bloomIndex != nil {
+			s.indexBuilder.Add("bloom_index", s.bloomIndex.Backfill)
+		}
+		s.indexBuilder.Start()
 	}
 
 	if cfg.DataRetention > 0 {
 		// MailServerDataRetention is a number of days.
-		s.setupCleaner(time.Duration(cfg.DataRetention) * time.Hour * 24)
+		s.setupCleaner(time.Duration(cfg.DataRetention)*time.Hour*24, cfg.SoftDeleteWindow)
+	}
+
+	s.topicStats = newTopicStatsCollector(s.db)
+	s.topicStats.Start()
+
+	s.envelopeSources = newEnvelopeSourcesCollector(s.db)
+	s.envelopeSources.Start()
+
+	s.coverage = newCoverageTracker(s.db)
+	s.coverage.Start()
+
+	if len(replicas) > 0 {
+		s.replicator = newReplicator(adapter, service, replicas)
+		s.replicator.Start()
 	}
 
 	return &s, nil
 }
 
+// consistencyCheckWindow returns the window of the consistency check in minutes,
+// zero if the check is disabled.
+func consistencyCheckWindow(enabled bool, minutes int) time.Duration {
+	if !enabled {
+		return 0
+	}
+	if minutes <= 0 {
+		minutes = defaultConsistencyCheckWindow
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// checkConsistency repairs envelopes archived since a given time. A failed check
+// is logged and does not prevent the mailserver from starting.
+func checkConsistency(db *LevelDB, since time.Time) {
+	start := time.Now()
+	stats, err := db.Repair(since)
+	consistencyCheckedCounter.Add(float64(stats.Checked))
+	consistencyDroppedCounter.Add(float64(stats.Dropped))
+	if err != nil {
+		log.Error("consistency check failed", "err", err)
+		return
+	}
+	log.Info("consistency check finished", "since", since, "checked", stats.Checked, "dropped", stats.Dropped, "duration", time.Since(start))
+}
+
 // setupRateLimiter in case limit is bigger than 0 it will setup an automated
 // limit db cleanup.
 func (s *mailServer) setupRateLimiter(limit time.Duration) {
@@ -674,15 +1168,72 @@ func (s *mailServer) setupRateLimiter(limit time.Duration) {
 	s.rateLimiter.Start()
 }
 
-func (s *mailServer) setupCleaner(retention time.Duration) {
+func (s *mailServer) setupCleaner(retention, softDeleteWindow time.Duration) {
 	s.cleaner = newDBCleaner(s.db, retention)
+	s.cleaner.softDeleteWindow = softDeleteWindow
+	s.cleaner.paused = s.isReadOnly
+	s.cleaner.pruned = func(t time.Time) {
+		if s.queryCache != nil {
+			s.queryCache.Pruned(t)
+		}
+		if s.topicIndex != nil {
+			s.topicIndex.Pruned(t)
+		}
+		if s.bloomIndex != nil {
+			s.bloomIndex.Pruned(t)
+		}
+	}
 	s.cleaner.Start()
 }
 
 func (s *mailServer) Archive(env types.Envelope) {
+	if s.validator != nil {
+		if reason := s.validator.Validate(env); reason != "" {
+			log.Debug("rejected envelope", "hash", env.Hash().String(), "reason", reason, "ttl", env.TTL(), "expiry", env.Expiry(), "size", env.Size())
+			archiveRejectedCounter.WithLabelValues(reason).Inc()
+			return
+		}
+	}
+	if s.isReadOnly() {
+		// the spill queue is paused and keeps envelopes until the mode is disabled
+		if _, ok := s.db.(*SpillDB); !ok {
+			readOnlyEnvelopesCounter.WithLabelValues("dropped").Inc()
+			if s.coverage != nil {
+				s.coverage.Dropped()
+			}
+			return
+		}
+		readOnlyEnvelopesCounter.WithLabelValues("spooled").Inc()
+	}
 	err := s.db.SaveEnvelope(env)
 	if err != nil {
 		log.Error("Could not save envelope", "hash", env.Hash().String())
+		// envelopes rejected by a tenant quota are dropped on purpose
+		if s.coverage != nil && err != ErrTenantQuotaExceeded {
+			s.coverage.Failed()
+		}
+		return
+	}
+	if s.coverage != nil {
+		s.coverage.Succeeded()
+	}
+	if s.queryCache != nil {
+		s.queryCache.Archived(env.Expiry() - env.TTL())
+	}
+	if s.topicStats != nil {
+		s.topicStats.Add(env)
+	}
+	if s.topicIndex != nil {
+		s.topicIndex.Add(env)
+	}
+	if s.bloomIndex != nil {
+		s.bloomIndex.Add(env)
+	}
+	if s.envelopeSources != nil {
+		s.envelopeSources.Archived(env)
+	}
+	if s.replicator != nil {
+		s.replicator.Add(env)
 	}
 }
 
@@ -691,12 +1242,17 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 	defer timer.ObserveDuration()
 
 	deliveryAttemptsCounter.Inc()
+	tenantRequestsCounter.WithLabelValues(tenantLabel(s.config.PostgresTenant.Name)).Inc()
+	s.markRequest()
 	log.Info(
 		"[mailserver:DeliverMail] delivering mail",
 		"peerID", peerID.String(),
 		"requestID", reqID.String(),
 	)
 
+	if !req.Digest {
+		s.applyQueryLimit(&req)
+	}
 	req.SetDefaults()
 
 	log.Info(
@@ -710,6 +1266,9 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		"limit", req.Limit,
 		"cursor", req.Cursor,
 		"batch", req.Batch,
+		"digest", req.Digest,
+		"keys", len(req.Keys),
+		"ackWindow", req.AckWindow,
 	)
 
 	if err := req.Validate(); err != nil {
@@ -735,35 +1294,154 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		return
 	}
 
+	if err := s.checkListener(peerID); err != nil {
+		deliveryFailuresCounter.WithLabelValues("listener").Inc()
+		log.Error(
+			"[mailserver:DeliverMail] request rejected by listener",
+			"peerID", peerID.String(),
+			"requestID", reqID.String(),
+			"err", err,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+		return
+	}
+
+	if err := s.applyQueryPolicy(peerID, &req); err != nil {
+		deliveryFailuresCounter.WithLabelValues("query_tier").Inc()
+		log.Error(
+			"[mailserver:DeliverMail] request restricted by query tier",
+			"peerID", peerID.String(),
+			"requestID", reqID.String(),
+			"err", err,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+		return
+	}
+
 	if req.Batch {
 		requestsBatchedCounter.Inc()
 	}
 
-	iter, err := s.createIterator(req)
+	// DeliverMail is called from the read loop of the peer connection, which also
+	// reads acknowledgements of batches, so acknowledged deliveries must not block it.
+	if req.Batch && req.AckWindow > 0 {
+		s.deliveries.Add(1)
+		go func() {
+			defer s.deliveries.Done()
+			s.deliverMail(peerID, reqID, req)
+		}()
+		return
+	}
+	s.deliverMail(peerID, reqID, req)
+}
+
+// deliverMail sends envelopes, keys of envelopes or a digest matching a validated request.
+func (s *mailServer) deliverMail(peerID, reqID types.Hash, req MessagesRequestPayload) {
+	if s.queryScheduler != nil {
+		if !s.queryScheduler.Acquire(peerID, s.quit) {
+			log.Debug(
+				"[mailserver:deliverMail] queued query canceled on close",
+				"peerID", peerID.String(),
+				"requestID", reqID.String(),
+			)
+			return
+		}
+		defer s.queryScheduler.Release()
+	}
+
+	if req.Digest {
+		requestsDigestCounter.Inc()
+		s.deliverDigest(peerID, reqID, req)
+		return
+	}
+
+	if len(req.Keys) > 0 {
+		requestsByKeysCounter.Inc()
+		s.deliverByKeys(peerID, reqID, req)
+		return
+	}
+
+	// Batches are acknowledged only if they are sent in a single message.
+	var acks *ackWindow
+	if req.Batch && req.AckWindow > 0 {
+		requestsAckedCounter.Inc()
+		acks = newAckWindow(
+			s.acks,
+			s.quit,
+			peerID,
+			int(req.AckWindow),
+			int(atomic.LoadInt64(&s.ackBatchSize)),
+			time.Duration(atomic.LoadInt64(&s.ackTimeout)),
+		)
+		defer acks.Close()
+	}
+
+	// Cached pages are not used if batches are acknowledged as they differ in size.
+	var (
+		cacheKey   queryCacheKey
+		cacheQuery *pendingQuery
+		cacheEntry *queryCacheEntry
+	)
+	if s.queryCache != nil && acks == nil {
+		cacheKey = newQueryCacheKey(req)
+		if entry, exist := s.queryCache.Get(cacheKey); exist {
+			queryCacheHitsCounter.Inc()
+			s.deliverCachedPage(peerID, reqID, req.Batch, req.Compress, entry)
+			return
+		}
+		queryCacheMissesCounter.Inc()
+		cacheQuery = s.queryCache.Begin(req.Lower, req.Upper)
+		// cacheEntry is set only if the whole page was sent
+		defer func() { s.queryCache.Finish(cacheQuery, cacheKey, cacheEntry) }()
+	}
+
+	ctx, cancel := s.queryContext()
+	defer cancel()
+	iter, err := s.createIterator(ctx, req)
 	if err != nil {
+		deliveryFailuresCounter.WithLabelValues("iterator").Inc()
 		log.Error(
 			"[mailserver:DeliverMail] request failed",
+			"err", err,
 			"peerID", peerID.String(),
 			"requestID", reqID.String(),
 		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
 		return
 	}
 	defer func() { _ = iter.Release() }()
 
-	bundles := make(chan []rlp.RawValue, 5)
+	bundles := make(chan []rlp.RawValue, deliveryQueueSize)
 	errCh := make(chan error)
 	cancelProcessing := make(chan struct{})
+	// sent bundles are read only after errCh is closed
+	var sent [][]rlp.RawValue
 
 	go func() {
 		counter := 0
+		var err error
 		for bundle := range bundles {
-			if err := s.sendRawEnvelopes(peerID, bundle, req.Batch); err != nil {
+			if acks != nil {
+				err = s.sendAcknowledgedBatch(peerID, bundle, req.Compress, acks)
+			} else {
+				err = s.sendRawEnvelopes(peerID, bundle, req.Batch, req.Compress)
+			}
+			if err != nil {
 				close(cancelProcessing)
-				errCh <- err
 				break
 			}
+			if s.queryCache != nil && acks == nil {
+				sent = append(sent, bundle)
+			}
 			counter++
 		}
+		// all batches have to be acknowledged before the response is sent
+		if err == nil && acks != nil {
+			err = acks.Wait(0)
+		}
+		if err != nil {
+			errCh <- err
+		}
 		close(errCh)
 		log.Info(
 			"[mailserver:DeliverMail] finished sending bundles",
@@ -773,7 +1451,8 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		)
 	}()
 
-	nextPageCursor, lastEnvelopeHash := s.processRequestInBundles(
+	nextPageCursor, lastEnvelopeHash, processErr := s.processRequestInBundles(
+		ctx,
 		iter,
 		req.Bloom,
 		req.Topics,
@@ -782,10 +1461,24 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		reqID.String(),
 		bundles,
 		cancelProcessing,
+		acks,
 	)
 
 	// Wait for the goroutine to finish the work. It may return an error.
-	if err := <-errCh; err != nil {
+	err = <-errCh
+	// The client can resume the request after the last acknowledged batch.
+	if err == errAckTimeout && acks.cursor != nil {
+		log.Info(
+			"[mailserver:DeliverMail] batch not acknowledged, sending cursor of the last acknowledged batch",
+			"peerID", peerID,
+			"requestID", reqID,
+			"last", acks.lastEnvelopeHash,
+			"next", acks.cursor,
+		)
+		s.sendHistoricMessageResponse(peerID, reqID, acks.lastEnvelopeHash, acks.cursor)
+		return
+	}
+	if err != nil {
 		deliveryFailuresCounter.WithLabelValues("process").Inc()
 		log.Error(
 			"[mailserver:DeliverMail] error while processing",
@@ -798,7 +1491,9 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 	}
 
 	// Processing of the request could be finished earlier due to iterator error.
-	if err := iter.Error(); err != nil {
+	// The iterator also fails if the query timed out, but then the envelopes found
+	// so far are sent with a cursor.
+	if err := iter.Error(); err != nil && processErr != errQueryTimeout {
 		deliveryFailuresCounter.WithLabelValues("iterator").Inc()
 		log.Error(
 			"[mailserver:DeliverMail] iterator failed",
@@ -810,6 +1505,31 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		return
 	}
 
+	// The peer did not read envelopes in time and nothing was queued,
+	// so there is no cursor to resume from.
+	if processErr == errDeliveryStalled && nextPageCursor == nil {
+		deliveryFailuresCounter.WithLabelValues("peer_stalled").Inc()
+		log.Error(
+			"[mailserver:DeliverMail] peer stalled",
+			"peerID", peerID,
+			"requestID", reqID,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, processErr)
+		return
+	}
+
+	// The query timed out before any envelope was found.
+	if processErr == errQueryTimeout && nextPageCursor == nil {
+		deliveryFailuresCounter.WithLabelValues("query_timeout").Inc()
+		log.Error(
+			"[mailserver:DeliverMail] query timed out",
+			"peerID", peerID,
+			"requestID", reqID,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, processErr)
+		return
+	}
+
 	log.Info(
 		"[mailserver:DeliverMail] sending historic message response",
 		"peerID", peerID,
@@ -818,15 +1538,204 @@ func (s *mailServer) DeliverMail(peerID, reqID types.Hash, req MessagesRequestPa
 		"next", nextPageCursor,
 	)
 
+	if processErr == nil {
+		cacheEntry = &queryCacheEntry{
+			bundles:          sent,
+			nextCursor:       nextPageCursor,
+			lastEnvelopeHash: lastEnvelopeHash,
+		}
+	}
+
 	s.sendHistoricMessageResponse(peerID, reqID, lastEnvelopeHash, nextPageCursor)
 }
 
+// deliverDigest sends keys of envelopes matching the request instead of envelopes.
+// The number of keys is limited by the request limit and the max message size.
+func (s *mailServer) deliverDigest(peerID, reqID types.Hash, req MessagesRequestPayload) {
+	limit := int(req.Limit)
+	if maxKeys := int(s.service.MaxMessageSize()) / DBKeyLength; maxKeys > 0 && limit > maxKeys-1 {
+		// one key is reserved for the prefix, the request ID and the cursor
+		limit = maxKeys - 1
+	}
+
+	ctx, cancel := s.queryContext()
+	defer cancel()
+	iter, err := s.createIterator(ctx, req)
+	if err != nil {
+		deliveryFailuresCounter.WithLabelValues("iterator").Inc()
+		log.Error(
+			"[mailserver:deliverDigest] request failed",
+			"err", err,
+			"peerID", peerID.String(),
+			"requestID", reqID.String(),
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+		return
+	}
+	defer func() { _ = iter.Release() }()
+
+	var (
+		keys   [][]byte
+		cursor []byte
+	)
+	for iter.Next() {
+		rawValue, err := iter.GetEnvelope(req.Bloom)
+		if err != nil || rawValue == nil {
+			continue
+		}
+		key, err := iter.DBKey()
+		if err != nil {
+			deliveryFailuresCounter.WithLabelValues("iterator").Inc()
+			log.Error(
+				"[mailserver:deliverDigest] invalid key",
+				"err", err,
+				"peerID", peerID,
+				"requestID", reqID,
+			)
+			s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+			return
+		}
+		if len(keys) == limit {
+			cursor = keys[len(keys)-1][:CursorLength]
+			break
+		}
+		// The key may be reused by the iterator so it needs to be copied.
+		keys = append(keys, append([]byte(nil), key.Bytes()...))
+	}
+
+	// Keys found before the query timed out are sent with a cursor to resume from.
+	timedOut := cursor == nil && len(keys) > 0 && ctx.Err() == context.DeadlineExceeded
+	if timedOut {
+		queryTimeoutCounter.Inc()
+		cursor = keys[len(keys)-1][:CursorLength]
+	}
+
+	if err := iter.Error(); err != nil && !timedOut {
+		deliveryFailuresCounter.WithLabelValues("iterator").Inc()
+		log.Error(
+			"[mailserver:deliverDigest] iterator failed",
+			"err", err,
+			"peerID", peerID,
+			"requestID", reqID,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+		return
+	}
+
+	log.Info(
+		"[mailserver:deliverDigest] sending digest",
+		"peerID", peerID,
+		"requestID", reqID,
+		"keys", len(keys),
+		"next", cursor,
+	)
+
+	payload := s.adapter.CreateRequestDigestPayload(reqID, cursor, keys)
+	if err := s.service.SendHistoricMessageResponse(peerID.Bytes(), payload); err != nil {
+		deliveryFailuresCounter.WithLabelValues("historic_msg_resp").Inc()
+		log.Error(
+			"[mailserver:deliverDigest] error sending digest",
+			"err", err,
+			"peerID", peerID,
+			"requestID", reqID,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+	}
+}
+
+// deliverByKeys sends envelopes with keys from the request. Keys of envelopes
+// that are no longer stored are skipped.
+func (s *mailServer) deliverByKeys(peerID, reqID types.Hash, req MessagesRequestPayload) {
+	keys := make([]*DBKey, len(req.Keys))
+	for i, raw := range req.Keys {
+		keys[i] = &DBKey{raw: raw}
+	}
+	envelopes, err := s.db.GetEnvelopes(keys)
+	if err != nil {
+		deliveryFailuresCounter.WithLabelValues("db").Inc()
+		log.Error(
+			"[mailserver:deliverByKeys] failed to get envelopes",
+			"err", err,
+			"peerID", peerID,
+			"requestID", reqID,
+		)
+		s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+		return
+	}
+
+	var (
+		bundle           []rlp.RawValue
+		bundleSize       uint32
+		lastEnvelopeHash types.Hash
+		found            int
+	)
+	for i, rawValue := range envelopes {
+		if rawValue == nil {
+			continue
+		}
+		found++
+		envelopeSize := uint32(len(rawValue))
+		if len(bundle) > 0 && bundleSize+envelopeSize >= s.service.MaxMessageSize() {
+			if err := s.sendRawEnvelopes(peerID, bundle, req.Batch, req.Compress); err != nil {
+				deliveryFailuresCounter.WithLabelValues("process").Inc()
+				s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+				return
+			}
+			bundle = nil
+			bundleSize = 0
+		}
+		bundle = append(bundle, rawValue)
+		bundleSize += envelopeSize
+		lastEnvelopeHash = keys[i].EnvelopeHash()
+	}
+	log.Info(
+		"[mailserver:deliverByKeys] sending envelopes",
+		"peerID", peerID,
+		"requestID", reqID,
+		"requested", len(keys),
+		"found", found,
+	)
+	if len(bundle) > 0 {
+		if err := s.sendRawEnvelopes(peerID, bundle, req.Batch, req.Compress); err != nil {
+			deliveryFailuresCounter.WithLabelValues("process").Inc()
+			s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+			return
+		}
+	}
+	s.sendHistoricMessageResponse(peerID, reqID, lastEnvelopeHash, nil)
+}
+
+// deliverCachedPage sends bundles of a cached page and the final response.
+func (s *mailServer) deliverCachedPage(peerID, reqID types.Hash, batch, compress bool, entry *queryCacheEntry) {
+	log.Info(
+		"[mailserver:DeliverMail] delivering cached page",
+		"peerID", peerID,
+		"requestID", reqID,
+		"bundles", len(entry.bundles),
+	)
+	for _, bundle := range entry.bundles {
+		if err := s.sendRawEnvelopes(peerID, bundle, batch, compress); err != nil {
+			deliveryFailuresCounter.WithLabelValues("process").Inc()
+			log.Error(
+				"[mailserver:DeliverMail] error while sending cached page",
+				"err", err,
+				"peerID", peerID,
+				"requestID", reqID,
+			)
+			s.sendHistoricMessageErrorResponse(peerID, reqID, err)
+			return
+		}
+	}
+	s.sendHistoricMessageResponse(peerID, reqID, entry.lastEnvelopeHash, entry.nextCursor)
+}
+
 func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) error {
 	log.Info("Started syncing envelopes", "peer", peerID.String(), "req", req)
 
 	requestID := fmt.Sprintf("%d-%d", time.Now().UnixNano(), rand.Intn(1000))
 
 	syncAttemptsCounter.Inc()
+	s.markRequest()
 
 	// Check rate limiting for a requesting peer.
 	if s.exceedsPeerRequests(peerID) {
@@ -835,6 +1744,14 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 		return fmt.Errorf("requests per seconds limit exceeded")
 	}
 
+	if err := s.checkListener(peerID); err != nil {
+		syncFailuresCounter.WithLabelValues("listener").Inc()
+		return err
+	}
+
+	if !req.Digest {
+		s.applyQueryLimit(&req)
+	}
 	req.SetDefaults()
 
 	if err := req.Validate(); err != nil {
@@ -842,14 +1759,22 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 		return fmt.Errorf("request is invalid: %v", err)
 	}
 
-	iter, err := s.createIterator(req)
+	if err := s.applyQueryPolicy(peerID, &req); err != nil {
+		syncFailuresCounter.WithLabelValues("query_tier").Inc()
+		return err
+	}
+
+	ctx, cancel := s.queryContext()
+	defer cancel()
+	iter, err := s.createIterator(ctx, req)
 	if err != nil {
 		syncFailuresCounter.WithLabelValues("iterator").Inc()
+		log.Error("failed to sync envelopes", "peerID", peerID.String(), "err", err)
 		return err
 	}
 	defer func() { _ = iter.Release() }()
 
-	bundles := make(chan []rlp.RawValue, 5)
+	bundles := make(chan []rlp.RawValue, deliveryQueueSize)
 	errCh := make(chan error)
 	cancelProcessing := make(chan struct{})
 
@@ -865,7 +1790,8 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 		close(errCh)
 	}()
 
-	nextCursor, _ := s.processRequestInBundles(
+	nextCursor, _, processErr := s.processRequestInBundles(
+		ctx,
 		iter,
 		req.Bloom,
 		req.Topics,
@@ -874,6 +1800,7 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 		requestID,
 		bundles,
 		cancelProcessing,
+		nil,
 	)
 
 	// Wait for the goroutine to finish the work. It may return an error.
@@ -887,7 +1814,7 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 	}
 
 	// Processing of the request could be finished earlier due to iterator error.
-	if err := iter.Error(); err != nil {
+	if err := iter.Error(); err != nil && processErr != errQueryTimeout {
 		syncFailuresCounter.WithLabelValues("iterator").Inc()
 		_ = s.service.SendSyncResponse(
 			peerID.Bytes(),
@@ -896,6 +1823,24 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 		return fmt.Errorf("LevelDB iterator failed: %v", err)
 	}
 
+	if processErr == errDeliveryStalled && nextCursor == nil {
+		syncFailuresCounter.WithLabelValues("peer_stalled").Inc()
+		_ = s.service.SendSyncResponse(
+			peerID.Bytes(),
+			s.adapter.CreateSyncResponse(nil, nil, false, "peer stalled"),
+		)
+		return processErr
+	}
+
+	if processErr == errQueryTimeout && nextCursor == nil {
+		syncFailuresCounter.WithLabelValues("query_timeout").Inc()
+		_ = s.service.SendSyncResponse(
+			peerID.Bytes(),
+			s.adapter.CreateSyncResponse(nil, nil, false, "query timed out"),
+		)
+		return processErr
+	}
+
 	log.Info("Finished syncing envelopes", "peer", peerID.String())
 
 	err = s.service.SendSyncResponse(
@@ -912,17 +1857,263 @@ func (s *mailServer) SyncMail(peerID types.Hash, req MessagesRequestPayload) err
 
 // Close the mailserver and its associated db connection.
 func (s *mailServer) Close() {
+	close(s.quit)
+	s.deliveries.Wait()
+	if s.topicStats != nil {
+		s.topicStats.Stop()
+	}
+	if s.envelopeSources != nil {
+		s.envelopeSources.Stop()
+	}
+	if s.replicator != nil {
+		s.replicator.Stop()
+	}
+	if s.compactor != nil {
+		s.compactor.Stop()
+	}
+	if s.indexBuilder != nil {
+		s.indexBuilder.Stop()
+	}
+	if s.coverage != nil {
+		s.coverage.Stop()
+	}
 	if s.db != nil {
 		if err := s.db.Close(); err != nil {
 			log.Error("closing database failed", "err", err)
 		}
 	}
+	s.muRateLimiter.Lock()
 	if s.rateLimiter != nil {
 		s.rateLimiter.Stop()
 	}
+	s.muRateLimiter.Unlock()
+	s.muListeners.Lock()
+	if s.listeners != nil {
+		s.listeners.Stop()
+	}
+	s.muListeners.Unlock()
+	s.muCleaner.Lock()
 	if s.cleaner != nil {
 		s.cleaner.Stop()
 	}
+	s.muCleaner.Unlock()
+}
+
+// reload applies reloadable fields of the config: the rate limit, the data retention,
+// the soft delete window, response limits and the query timeout. Affected components are replaced and
+// applied changes are logged and returned. Other fields require a restart.
+func (s *mailServer) reload(cfg Config) []string {
+	s.muReload.Lock()
+	defer s.muReload.Unlock()
+
+	var changes []string
+	changed := func(name string, old, new interface{}) bool {
+		if reflect.DeepEqual(old, new) {
+			return false
+		}
+		log.Info("mailserver config changed", "name", name, "old", old, "new", new)
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, old, new))
+		return true
+	}
+
+	if changed("RateLimit", s.config.RateLimit, cfg.RateLimit) {
+		s.muRateLimiter.Lock()
+		if s.rateLimiter != nil {
+			s.rateLimiter.Stop()
+			s.rateLimiter = nil
+		}
+		if cfg.RateLimit > 0 {
+			s.setupRateLimiter(time.Duration(cfg.RateLimit) * time.Second)
+		}
+		s.muRateLimiter.Unlock()
+		s.config.RateLimit = cfg.RateLimit
+	}
+
+	retentionChanged := changed("DataRetention", s.config.DataRetention, cfg.DataRetention)
+	if changed("SoftDeleteWindow", s.config.SoftDeleteWindow, cfg.SoftDeleteWindow) || retentionChanged {
+		s.muCleaner.Lock()
+		if s.cleaner != nil {
+			s.cleaner.Stop()
+			s.cleaner = nil
+		}
+		if cfg.DataRetention > 0 {
+			s.setupCleaner(time.Duration(cfg.DataRetention)*time.Hour*24, cfg.SoftDeleteWindow)
+		}
+		s.muCleaner.Unlock()
+		s.config.DataRetention = cfg.DataRetention
+		s.config.SoftDeleteWindow = cfg.SoftDeleteWindow
+	}
+
+	sizeChanged := changed("MaxEnvelopeSize", s.config.MaxEnvelopeSize, cfg.MaxEnvelopeSize)
+	ttlChanged := changed("MaxEnvelopeTTL", s.config.MaxEnvelopeTTL, cfg.MaxEnvelopeTTL)
+	if changed("MaxEnvelopeDrift", s.config.MaxEnvelopeDrift, cfg.MaxEnvelopeDrift) || sizeChanged || ttlChanged || retentionChanged {
+		s.config.MaxEnvelopeSize = cfg.MaxEnvelopeSize
+		s.config.MaxEnvelopeTTL = cfg.MaxEnvelopeTTL
+		s.config.MaxEnvelopeDrift = cfg.MaxEnvelopeDrift
+		s.validator.Update(newEnvelopeLimits(s.config))
+	}
+
+	if changed("MaxQueryLimit", s.config.MaxQueryLimit, cfg.MaxQueryLimit) {
+		atomic.StoreUint32(&s.maxQueryLimit, cfg.MaxQueryLimit)
+		s.config.MaxQueryLimit = cfg.MaxQueryLimit
+	}
+	if changed("MaxResponseSize", s.config.MaxResponseSize, cfg.MaxResponseSize) {
+		atomic.StoreUint32(&s.maxResponseSize, cfg.MaxResponseSize)
+		s.config.MaxResponseSize = cfg.MaxResponseSize
+	}
+	if changed("QueryTimeout", s.config.QueryTimeout, cfg.QueryTimeout) {
+		atomic.StoreInt64(&s.queryTimeout, int64(cfg.QueryTimeout))
+		s.config.QueryTimeout = cfg.QueryTimeout
+	}
+	if changed("MaxConcurrentQueries", s.config.MaxConcurrentQueries, cfg.MaxConcurrentQueries) {
+		s.queryScheduler.SetLimit(cfg.MaxConcurrentQueries)
+		s.config.MaxConcurrentQueries = cfg.MaxConcurrentQueries
+	}
+	if changed("AckBatchSize", s.config.AckBatchSize, cfg.AckBatchSize) {
+		atomic.StoreInt64(&s.ackBatchSize, int64(cfg.AckBatchSize))
+		s.config.AckBatchSize = cfg.AckBatchSize
+	}
+	if changed("AckTimeout", s.config.AckTimeout, cfg.AckTimeout) {
+		atomic.StoreInt64(&s.ackTimeout, int64(cfg.AckTimeout))
+		s.config.AckTimeout = cfg.AckTimeout
+	}
+
+	tiersChanged := changed("QueryTiers", s.config.QueryTiers, cfg.QueryTiers)
+	if changed("AllowedPeers", s.config.AllowedPeers, cfg.AllowedPeers) || tiersChanged {
+		var (
+			policy *queryPolicy
+			err    error
+		)
+		if len(cfg.QueryTiers) > 0 {
+			policy, err = newQueryPolicy(cfg.QueryTiers, cfg.AllowedPeers)
+		}
+		if err != nil {
+			log.Error("invalid mailserver query tiers", "err", err)
+		} else {
+			s.muQueryPolicy.Lock()
+			s.queryPolicy = policy
+			s.muQueryPolicy.Unlock()
+			s.config.QueryTiers = cfg.QueryTiers
+			s.config.AllowedPeers = cfg.AllowedPeers
+		}
+	}
+
+	if changed("Listeners", s.config.Listeners, cfg.Listeners) {
+		var (
+			listeners *listenerSet
+			err       error
+		)
+		if len(cfg.Listeners) > 0 {
+			listeners, err = newListenerSet(cfg.Listeners)
+		}
+		if err != nil {
+			log.Error("invalid mailserver listeners", "err", err)
+		} else {
+			if listeners != nil {
+				listeners.Start()
+			}
+			s.muListeners.Lock()
+			if s.listeners != nil {
+				s.listeners.Stop()
+			}
+			s.listeners = listeners
+			s.muListeners.Unlock()
+			s.config.Listeners = cfg.Listeners
+		}
+	}
+
+	if changed("ReadOnly", s.config.ReadOnly, cfg.ReadOnly) {
+		s.setReadOnly(cfg.ReadOnly)
+		s.config.ReadOnly = cfg.ReadOnly
+	}
+
+	if changed("DisableCompression", s.config.DisableCompression, cfg.DisableCompression) {
+		var disabled uint32
+		if cfg.DisableCompression {
+			disabled = 1
+		}
+		atomic.StoreUint32(&s.compressionDisabled, disabled)
+		s.config.DisableCompression = cfg.DisableCompression
+	}
+
+	// values are not logged as some of them are secrets
+	restart := map[string]bool{
+		"DataDir":              s.config.DataDir != cfg.DataDir,
+		"Password":             s.config.Password != cfg.Password,
+		"AsymKey":              s.config.AsymKey != cfg.AsymKey,
+		"QueryCacheSize":       s.config.QueryCacheSize != cfg.QueryCacheSize,
+		"PostgresEnabled":      s.config.PostgresEnabled != cfg.PostgresEnabled,
+		"PostgresURI":          s.config.PostgresURI != cfg.PostgresURI,
+		"PostgresShardURIs":    !reflect.DeepEqual(s.config.PostgresShardURIs, cfg.PostgresShardURIs),
+		"PostgresTLS":          s.config.PostgresTLS != cfg.PostgresTLS,
+		"PostgresSpillDir":     s.config.PostgresSpillDir != cfg.PostgresSpillDir,
+		"PostgresTenant":       s.config.PostgresTenant != cfg.PostgresTenant,
+		"Replicas":             !reflect.DeepEqual(s.config.Replicas, cfg.Replicas),
+		"CompactionInterval":   s.config.CompactionInterval != cfg.CompactionInterval,
+		"CompactionIdleWindow": s.config.CompactionIdleWindow != cfg.CompactionIdleWindow,
+		"BuildIndexes":         s.config.BuildIndexes != cfg.BuildIndexes,
+	}
+	for name, differs := range restart {
+		if differs {
+			log.Warn("mailserver config change requires a restart", "name", name)
+		}
+	}
+
+	log.Info("mailserver config reloaded", "changes", len(changes))
+	return changes
+}
+
+// setReadOnly enables or disables the read-only mode. Envelopes received in the mode are
+// queued if the spill queue is enabled, otherwise they are dropped. The queue is not moved
+// to the database and envelopes are not pruned until the mode is disabled.
+func (s *mailServer) setReadOnly(readOnly bool) {
+	var value uint32
+	if readOnly {
+		value = 1
+	}
+	if atomic.SwapUint32(&s.readOnly, value) == value {
+		return
+	}
+	if db, ok := s.db.(*SpillDB); ok {
+		db.Pause(readOnly)
+	}
+	if !readOnly && s.coverage != nil {
+		s.coverage.ReadOnlyDisabled()
+	}
+	readOnlyGauge.Set(float64(value))
+	log.Info("mailserver read-only mode changed", "enabled", readOnly)
+}
+
+func (s *mailServer) isReadOnly() bool {
+	return atomic.LoadUint32(&s.readOnly) == 1
+}
+
+func (s *mailServer) readOnlyStatus() ReadOnlyStatus {
+	status := ReadOnlyStatus{ReadOnly: s.isReadOnly()}
+	if db, ok := s.db.(*SpillDB); ok {
+		status.Spooled = db.Pending()
+	}
+	return status
+}
+
+// softDeleteWindow returns the soft delete window of the cleaner, zero if pruning is disabled.
+func (s *mailServer) softDeleteWindow() time.Duration {
+	s.muCleaner.RLock()
+	defer s.muCleaner.RUnlock()
+	if s.cleaner == nil {
+		return 0
+	}
+	return s.cleaner.softDeleteWindow
+}
+
+// markRequest records the time of a received request.
+func (s *mailServer) markRequest() {
+	atomic.StoreInt64(&s.lastRequest, time.Now().UnixNano())
+}
+
+// lastRequestTime returns the time of the last received request.
+func (s *mailServer) lastRequestTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastRequest))
 }
 
 func (s *mailServer) exceedsPeerRequests(peerID types.Hash) bool {
@@ -942,7 +2133,66 @@ func (s *mailServer) exceedsPeerRequests(peerID types.Hash) bool {
 	return true
 }
 
-func (s *mailServer) createIterator(req MessagesRequestPayload) (Iterator, error) {
+// checkListener checks the request against the listener matching the local address
+// of the connection with the peer.
+func (s *mailServer) checkListener(peerID types.Hash) error {
+	s.muListeners.RLock()
+	defer s.muListeners.RUnlock()
+
+	if s.listeners == nil || s.peerAddrs == nil {
+		return nil
+	}
+	addr, err := s.peerAddrs.PeerLocalAddr(peerID.Bytes())
+	if err != nil {
+		return err
+	}
+	name, err := s.listeners.Allow(peerID, addr)
+	switch err {
+	case nil:
+		listenerRequestsCounter.WithLabelValues(name, "accepted").Inc()
+	case errNoListener:
+		listenerRequestsCounter.WithLabelValues("none", "rejected").Inc()
+		log.Info("request from peer on an address without a listener", "peerID", peerID.String(), "addr", addr)
+	case errListenerNotAllowed:
+		listenerRequestsCounter.WithLabelValues(name, "not_allowed").Inc()
+	default:
+		listenerRequestsCounter.WithLabelValues(name, "rate_limit").Inc()
+	}
+	return err
+}
+
+// applyQueryPolicy checks the request against the query tier of the requested envelopes
+// and lowers its limit to the limit of the tier.
+func (s *mailServer) applyQueryPolicy(peerID types.Hash, req *MessagesRequestPayload) error {
+	s.muQueryPolicy.RLock()
+	policy := s.queryPolicy
+	s.muQueryPolicy.RUnlock()
+	if policy == nil {
+		return nil
+	}
+	return policy.Apply(peerID, req)
+}
+
+// applyQueryLimit lowers the request's limit to the configured maximum.
+func (s *mailServer) applyQueryLimit(req *MessagesRequestPayload) {
+	maxQueryLimit := atomic.LoadUint32(&s.maxQueryLimit)
+	if maxQueryLimit == 0 || maxQueryLimit >= maxMessagesRequestPayloadLimit {
+		return
+	}
+	if req.Limit == 0 || req.Limit > maxQueryLimit {
+		req.Limit = maxQueryLimit
+	}
+}
+
+// queryContext returns a context that expires after the configured query timeout.
+func (s *mailServer) queryContext() (context.Context, context.CancelFunc) {
+	if timeout := time.Duration(atomic.LoadInt64(&s.queryTimeout)); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (s *mailServer) createIterator(ctx context.Context, req MessagesRequestPayload) (Iterator, error) {
 	var (
 		emptyHash  types.Hash
 		emptyTopic types.TopicType
@@ -952,17 +2202,65 @@ func (s *mailServer) createIterator(req MessagesRequestPayload) (Iterator, error
 	ku = NewDBKey(req.Upper+1, emptyTopic, emptyHash)
 	kl = NewDBKey(req.Lower, emptyTopic, emptyHash)
 
+	// ranges of the bloom index are read in the order the database returns envelopes in
+	order := req.Order
+	if order == OrderDefault {
+		order = s.defaultOrder
+	}
 	query := CursorQuery{
 		start:  kl.Bytes(),
 		end:    ku.Bytes(),
 		cursor: req.Cursor,
 		bloom:  req.Bloom,
 		limit:  req.Limit,
+		order:  order,
+		topics: req.Topics,
+	}
+	if s.topicIndex != nil && len(req.Topics) == 0 {
+		topics, ok, err := s.topicIndex.Candidates(req.Lower, req.Upper, req.Bloom)
+		if err != nil {
+			log.Warn("failed to read topic index", "err", err)
+		}
+		switch {
+		case !ok:
+			topicIndexQueriesCounter.WithLabelValues("bloom").Inc()
+		case len(topics) == 0:
+			topicIndexQueriesCounter.WithLabelValues("empty").Inc()
+			return emptyIterator{}, nil
+		default:
+			topicIndexQueriesCounter.WithLabelValues("topics").Inc()
+			query.topics = topics
+		}
 	}
-	return s.db.BuildIterator(query)
+	if s.bloomIndex != nil && len(req.Topics) == 0 {
+		ranges, ok, err := s.bloomIndex.Ranges(req.Lower, req.Upper, req.Bloom)
+		if err != nil {
+			log.Warn("failed to read bloom index", "err", err)
+		}
+		if ok {
+			bloomIndexSkippedCounter.Add(float64(skippedBuckets(req.Lower, req.Upper, ranges)))
+			ranges = rangesFromCursor(ranges, req.Cursor, order)
+			if len(ranges) == 0 {
+				bloomIndexQueriesCounter.WithLabelValues("empty").Inc()
+				return emptyIterator{}, nil
+			}
+			bloomIndexQueriesCounter.WithLabelValues("ranges").Inc()
+			return newRangesIterator(ctx, s.db, query, ranges), nil
+		}
+		bloomIndexQueriesCounter.WithLabelValues("full").Inc()
+	}
+	return s.db.BuildIterator(ctx, query)
 }
 
+// processRequestInBundles iterates over envelopes and pushes them in bundles
+// to the output queue which is consumed by a goroutine sending them to the peer.
+// The queue is bounded so the iteration is paused if the peer reads slowly.
+// If a bundle can't be queued within the timeout, the processing is aborted
+// and the returned cursor points to the last queued envelope so that
+// the peer can resume from there. Similarly, if the query context expires,
+// envelopes taken so far are pushed and the cursor points to the last one.
 func (s *mailServer) processRequestInBundles(
+	ctx context.Context,
 	iter Iterator,
 	bloom []byte,
 	topics [][]byte,
@@ -971,24 +2269,68 @@ func (s *mailServer) processRequestInBundles(
 	requestID string,
 	output chan<- []rlp.RawValue,
 	cancel <-chan struct{},
-) ([]byte, types.Hash) {
+	acks *ackWindow,
+) ([]byte, types.Hash, error) {
 	timer := prom.NewTimer(requestsInBundlesDuration)
 	defer timer.ObserveDuration()
 
 	var (
 		bundle                 []rlp.RawValue
 		bundleSize             uint32
-		batches                [][]rlp.RawValue
+		bundleCursor           []byte
+		bundleHash             types.Hash
+		bundlesCount           int
+		queuedCursor           []byte
+		queuedHash             types.Hash
 		processedEnvelopes     int
 		processedEnvelopesSize int64
 		nextCursor             []byte
 		lastEnvelopeHash       types.Hash
+		pushErr                error
+		maxResponseSize        = atomic.LoadUint32(&s.maxResponseSize)
+		// maxBundleLen limits the number of envelopes in a bundle if greater than zero
+		maxBundleLen int
 	)
+	if acks != nil {
+		maxBundleLen = acks.batchSize
+	}
+
+	// push blocks until the bundle is queued.
+	push := func() error {
+		if acks != nil {
+			acks.Queued(bundleCursor, bundleHash)
+		}
+		select {
+		case output <- bundle:
+		// It might happen that during producing the batches,
+		// the connection with the peer goes down and
+		// the consumer of `output` channel exits prematurely.
+		// In such a case, we should stop pushing batches and exit.
+		case <-cancel:
+			log.Info(
+				"[mailserver:processRequestInBundles] failed to push all batches",
+				"requestID", requestID,
+			)
+			return errDeliveryCanceled
+		case <-time.After(timeout):
+			log.Error(
+				"[mailserver:processRequestInBundles] timed out pushing a batch",
+				"requestID", requestID,
+			)
+			return errDeliveryStalled
+		}
+		bundlesCount++
+		processedEnvelopesSize += int64(bundleSize)
+		queuedCursor = append(queuedCursor[:0], bundleCursor...)
+		queuedHash = bundleHash
+		return nil
+	}
 
 	log.Info(
 		"[mailserver:processRequestInBundles] processing request",
 		"requestID", requestID,
 		"limit", limit,
+		"maxResponseSize", maxResponseSize,
 	)
 
 	// We iterate over the envelopes.
@@ -996,7 +2338,9 @@ func (s *mailServer) processRequestInBundles(
 	// If there still room and we haven't reached the limit
 	// append and continue.
 	// Otherwise publish what you have so far, reset the bundle to the
-	// current envelope, and leave if we hit the limit
+	// current envelope, and leave if we hit the limit.
+	// If the next envelope does not fit into the response size budget,
+	// leave with a cursor pointing to the last envelope taken.
 	for iter.Next() {
 		rawValue, err := iter.GetEnvelope(bloom)
 		if err != nil {
@@ -1021,95 +2365,105 @@ func (s *mailServer) processRequestInBundles(
 
 		}
 
+		envelopeSize := uint32(len(rawValue))
+
+		// Always send at least one envelope so that the client can make progress.
+		if maxResponseSize > 0 && processedEnvelopes > 0 &&
+			processedEnvelopesSize+int64(bundleSize)+int64(envelopeSize) > int64(maxResponseSize) {
+			responseSizeLimitCounter.Inc()
+			nextCursor = bundleCursor
+			break
+		}
+
 		// TODO(adam): this is invalid code. If the limit is 1000,
 		// it will only send 999 items and send a cursor.
 		lastEnvelopeHash = key.EnvelopeHash()
 		processedEnvelopes++
-		envelopeSize := uint32(len(rawValue))
 		limitReached := processedEnvelopes >= limit
 		newSize := bundleSize + envelopeSize
 
 		// If we still have some room for messages, add and continue
-		if !limitReached && newSize < s.service.MaxMessageSize() {
+		if !limitReached && newSize < s.service.MaxMessageSize() && (maxBundleLen == 0 || len(bundle) < maxBundleLen) {
 			bundle = append(bundle, rawValue)
 			bundleSize = newSize
+			// The key may be reused by the iterator so it needs to be copied.
+			bundleCursor = append(bundleCursor[:0], key.Cursor()...)
+			bundleHash = lastEnvelopeHash
 			continue
 		}
 
 		// Publish if anything is in the bundle (there should always be
 		// something unless limit = 1)
 		if len(bundle) != 0 {
-			batches = append(batches, bundle)
-			processedEnvelopesSize += int64(bundleSize)
+			if pushErr = push(); pushErr != nil {
+				break
+			}
 		}
 
 		// Reset the bundle with the current envelope
 		bundle = []rlp.RawValue{rawValue}
 		bundleSize = envelopeSize
+		bundleCursor = append(bundleCursor[:0], key.Cursor()...)
+		bundleHash = lastEnvelopeHash
 
 		// Leave if we reached the limit
 		if limitReached {
-			nextCursor = key.Cursor()
+			nextCursor = bundleCursor
 			break
 		}
 	}
 
-	if len(bundle) > 0 {
-		batches = append(batches, bundle)
-		processedEnvelopesSize += int64(bundleSize)
+	// The iterator stops when the query context expires.
+	timedOut := nextCursor == nil && ctx.Err() == context.DeadlineExceeded
+
+	if pushErr == nil && len(bundle) > 0 {
+		pushErr = push()
+	}
+
+	if pushErr == nil && timedOut {
+		queryTimeoutCounter.Inc()
+		log.Warn(
+			"[mailserver:processRequestInBundles] query timed out",
+			"requestID", requestID,
+		)
+		// Resume from the last envelope that has been taken.
+		nextCursor = bundleCursor
+		pushErr = errQueryTimeout
+	}
+
+	if pushErr == errDeliveryStalled {
+		deliveryStalledCounter.Inc()
+		// Resume from the last envelope that has been queued.
+		nextCursor = queuedCursor
+		lastEnvelopeHash = queuedHash
 	}
 
 	log.Info(
-		"[mailserver:processRequestInBundles] publishing envelopes",
+		"[mailserver:processRequestInBundles] envelopes published",
 		"requestID", requestID,
-		"batchesCount", len(batches),
+		"batchesCount", bundlesCount,
 		"envelopeCount", processedEnvelopes,
 		"processedEnvelopesSize", processedEnvelopesSize,
 		"cursor", nextCursor,
+		"err", pushErr,
 	)
 
-	// Publish
-batchLoop:
-	for _, batch := range batches {
-		select {
-		case output <- batch:
-		// It might happen that during producing the batches,
-		// the connection with the peer goes down and
-		// the consumer of `output` channel exits prematurely.
-		// In such a case, we should stop pushing batches and exit.
-		case <-cancel:
-			log.Info(
-				"[mailserver:processRequestInBundles] failed to push all batches",
-				"requestID", requestID,
-			)
-			break batchLoop
-		case <-time.After(timeout):
-			log.Error(
-				"[mailserver:processRequestInBundles] timed out pushing a batch",
-				"requestID", requestID,
-			)
-			break batchLoop
-		}
-	}
-
 	envelopesCounter.Inc()
 	sentEnvelopeBatchSizeMeter.Observe(float64(processedEnvelopesSize))
 
-	log.Info(
-		"[mailserver:processRequestInBundles] envelopes published",
-		"requestID", requestID,
-	)
 	close(output)
 
-	return nextCursor, lastEnvelopeHash
+	return nextCursor, lastEnvelopeHash, pushErr
 }
 
-func (s *mailServer) sendRawEnvelopes(peerID types.Hash, envelopes []rlp.RawValue, batch bool) error {
+// sendRawEnvelopes sends envelopes in a single message if batch is true. Batches are compressed
+// if the peer accepts compressed responses and the compression makes them smaller.
+func (s *mailServer) sendRawEnvelopes(peerID types.Hash, envelopes []rlp.RawValue, batch, compress bool) error {
 	timer := prom.NewTimer(sendRawEnvelopeDuration)
 	defer timer.ObserveDuration()
 
 	if batch {
-		return s.service.SendRawP2PDirect(peerID.Bytes(), envelopes...)
+		return s.service.SendRawP2PDirect(peerID.Bytes(), s.encodeBatch(peerID, envelopes, compress)...)
 	}
 
 	for _, env := range envelopes {
@@ -1121,6 +2475,47 @@ func (s *mailServer) sendRawEnvelopes(peerID types.Hash, envelopes []rlp.RawValu
 	return nil
 }
 
+// encodeBatch returns envelopes as they are sent in a single message. They are compressed
+// if the peer accepts compressed responses and the compression makes them smaller.
+func (s *mailServer) encodeBatch(peerID types.Hash, envelopes []rlp.RawValue, compress bool) []rlp.RawValue {
+	if !compress || atomic.LoadUint32(&s.compressionDisabled) != 0 {
+		return envelopes
+	}
+	rawSize := 0
+	for _, env := range envelopes {
+		rawSize += len(env)
+	}
+	compressed, compressedSize, err := compressEnvelopes(envelopes)
+	if err != nil {
+		log.Warn("failed to compress envelopes", "peerID", peerID, "err", err)
+		return envelopes
+	}
+	if compressedSize >= rawSize {
+		return envelopes
+	}
+	s.compression.Sent(peerID, rawSize, compressedSize)
+	return []rlp.RawValue{compressed}
+}
+
+// sendAcknowledgedBatch sends envelopes in a single message and waits until
+// the number of batches that are not acknowledged fits into the ack window.
+func (s *mailServer) sendAcknowledgedBatch(peerID types.Hash, envelopes []rlp.RawValue, compress bool, acks *ackWindow) error {
+	timer := prom.NewTimer(sendRawEnvelopeDuration)
+	batch := s.encodeBatch(peerID, envelopes, compress)
+	hash, err := s.adapter.P2PBatchHash(batch)
+	if err != nil {
+		return err
+	}
+	// the acknowledgement can be received before SendRawP2PDirect returns
+	acks.Expect(hash)
+	err = s.service.SendRawP2PDirect(peerID.Bytes(), batch...)
+	timer.ObserveDuration()
+	if err != nil {
+		return err
+	}
+	return acks.Wait(acks.size - 1)
+}
+
 func (s *mailServer) sendHistoricMessageResponse(peerID, reqID, lastEnvelopeHash types.Hash, cursor []byte) {
 	payload := s.adapter.CreateRequestCompletedPayload(reqID, lastEnvelopeHash, cursor)
 	err := s.service.SendHistoricMessageResponse(peerID.Bytes(), payload)
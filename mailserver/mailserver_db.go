@@ -1,6 +1,9 @@
 package mailserver
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/status-im/status-go/eth-node/types"
@@ -14,10 +17,119 @@ type DB interface {
 	SaveEnvelope(types.Envelope) error
 	// GetEnvelope returns an rlp encoded envelope from the datastore
 	GetEnvelope(*DBKey) ([]byte, error)
+	// GetEnvelopes returns rlp encoded envelopes with given keys in the same order,
+	// an envelope is nil if it's not found
+	GetEnvelopes([]*DBKey) ([][]byte, error)
 	// Prune removes envelopes older than time
 	Prune(time.Time, int) (int, error)
-	// BuildIterator returns an iterator over envelopes
-	BuildIterator(query CursorQuery) (Iterator, error)
+	// PruneEstimate returns the number and the size of envelopes older than time
+	PruneEstimate(time.Time) (PruneStats, error)
+	// SoftPrune marks envelopes older than the first time as deleted at the second time.
+	// Deleted envelopes are not returned but can be restored until they are vacuumed
+	SoftPrune(time.Time, time.Time, int) (int, error)
+	// Restore returns envelopes deleted at or after time back to the archive
+	Restore(time.Time) (int, error)
+	// Vacuum permanently removes envelopes deleted before time
+	Vacuum(time.Time) (int, error)
+	// BuildIterator returns an iterator over envelopes. The iteration stops when the context is done
+	BuildIterator(ctx context.Context, query CursorQuery) (Iterator, error)
+	// SaveTopicStats adds stats to the counters of an hourly bucket
+	SaveTopicStats(bucket uint32, stats []TopicStats) error
+	// TopicStats returns stats per topic aggregated from buckets starting from a given one
+	TopicStats(since uint32) ([]TopicStats, error)
+	// PruneTopicStats removes buckets older than time
+	PruneTopicStats(time.Time) error
+	// SaveTopicIndex marks topics as present in an hourly bucket of envelope timestamps
+	SaveTopicIndex(bucket uint32, topics []types.TopicType) error
+	// IndexedTopics returns topics present in buckets between the first and the last one, inclusive
+	IndexedTopics(from, to uint32) ([]types.TopicType, error)
+	// TopicIndexStart returns the first bucket in the topic index, zero if the index is empty
+	TopicIndexStart() (uint32, error)
+	// PruneTopicIndex removes buckets before a given one
+	PruneTopicIndex(bucket uint32) error
+	// SaveBucketBloom merges a bloom into the aggregated bloom of an hourly bucket of envelope timestamps
+	SaveBucketBloom(bucket uint32, bloom []byte) error
+	// BucketBlooms returns aggregated blooms of buckets between the first and the last one, inclusive
+	BucketBlooms(from, to uint32) (map[uint32][]byte, error)
+	// BloomIndexStart returns the first bucket in the bloom index, zero if the index is empty
+	BloomIndexStart() (uint32, error)
+	// PruneBloomIndex removes buckets before a given one
+	PruneBloomIndex(bucket uint32) error
+	// SaveEnvelopeSources stores peers that delivered archived envelopes
+	SaveEnvelopeSources([]EnvelopeSourceRecord) error
+	// EnvelopeSources returns sources aggregated per peer of envelopes sent starting from time
+	EnvelopeSources(since time.Time) ([]EnvelopeSource, error)
+	// PruneEnvelopeSources removes sources of envelopes older than time
+	PruneEnvelopeSources(time.Time) error
+	// ArchiveStats returns cumulative stats of archived envelopes, it is updated
+	// together with envelopes by SaveEnvelope and pruning
+	ArchiveStats() (ArchiveStats, error)
+	// SaveCoverageGap stores a time range during which envelopes were not archived
+	SaveCoverageGap(CoverageGap) error
+	// CoverageGaps returns gaps overlapping the range between two unix timestamps, ordered by start
+	CoverageGaps(from, to uint32) ([]CoverageGap, error)
+	// SaveCoverageHeartbeat stores the last unix time the mailserver was known to archive envelopes
+	SaveCoverageHeartbeat(uint32) error
+	// CoverageHeartbeat returns the last stored heartbeat, zero if there is none
+	CoverageHeartbeat() (uint32, error)
+	// SchemaVersion returns the version of the database schema and the latest version supported by the binary
+	SchemaVersion() (SchemaVersion, error)
+}
+
+// ArchiveStats describes envelopes stored in the archive. Soft deleted envelopes are not counted.
+type ArchiveStats struct {
+	// Envelopes is the number of envelopes
+	Envelopes uint64 `json:"envelopes"`
+	// Size is the total size of envelopes data in bytes
+	Size uint64 `json:"size"`
+	// Oldest is the key of the oldest envelope, it starts with a big endian timestamp
+	Oldest types.HexBytes `json:"oldest"`
+	// Newest is the key of the newest envelope
+	Newest types.HexBytes `json:"newest"`
+}
+
+// add counts a new envelope.
+func (s *ArchiveStats) add(key []byte, size int64) {
+	s.update(1, size)
+	if len(s.Oldest) == 0 || bytes.Compare(key, s.Oldest) < 0 {
+		s.Oldest = append([]byte{}, key...)
+	}
+	if len(s.Newest) == 0 || bytes.Compare(key, s.Newest) > 0 {
+		s.Newest = append([]byte{}, key...)
+	}
+}
+
+// update adds deltas to the counters, they never go below zero.
+func (s *ArchiveStats) update(envelopes, size int64) {
+	s.Envelopes = addDelta(s.Envelopes, envelopes)
+	s.Size = addDelta(s.Size, size)
+}
+
+func addDelta(value uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > value {
+		return 0
+	}
+	return uint64(int64(value) + delta)
+}
+
+// merge adds stats of another database.
+func (s *ArchiveStats) merge(other ArchiveStats) {
+	s.Envelopes += other.Envelopes
+	s.Size += other.Size
+	if len(other.Oldest) != 0 && (len(s.Oldest) == 0 || bytes.Compare(other.Oldest, s.Oldest) < 0) {
+		s.Oldest = other.Oldest
+	}
+	if len(other.Newest) != 0 && (len(s.Newest) == 0 || bytes.Compare(other.Newest, s.Newest) > 0) {
+		s.Newest = other.Newest
+	}
+}
+
+// PruneStats describes envelopes that would be removed by Prune.
+type PruneStats struct {
+	// Rows is the number of envelopes
+	Rows int `json:"rows"`
+	// Size is the total size of envelopes data in bytes
+	Size int64 `json:"size"`
 }
 
 type Iterator interface {
@@ -28,6 +140,31 @@ type Iterator interface {
 	GetEnvelope(bloom []byte) ([]byte, error)
 }
 
+var errEmptyIterator = errors.New("iterator is empty")
+
+// emptyIterator is returned when it is known in advance that no envelopes match a query.
+type emptyIterator struct{}
+
+func (emptyIterator) Next() bool {
+	return false
+}
+
+func (emptyIterator) DBKey() (*DBKey, error) {
+	return nil, errEmptyIterator
+}
+
+func (emptyIterator) Release() error {
+	return nil
+}
+
+func (emptyIterator) Error() error {
+	return nil
+}
+
+func (emptyIterator) GetEnvelope(bloom []byte) ([]byte, error) {
+	return nil, errEmptyIterator
+}
+
 type CursorQuery struct {
 	start  []byte
 	end    []byte
@@ -35,4 +172,5 @@ type CursorQuery struct {
 	limit  uint32
 	bloom  []byte
 	topics [][]byte
+	order  Order
 }
@@ -1,28 +1,106 @@
 package mailserver
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 
+	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/whisper/v6"
 )
 
+// statsKeyPrefix is a prefix of the topic stats keys. Envelope keys start with
+// a timestamp so the prefix is placed at the end of the keyspace and excluded from envelope iterators.
+var statsKeyPrefix = []byte{0xff, 't', 's'}
+
+// sourcesKeyPrefix is a prefix of the envelope sources keys, it is followed by the envelope key.
+// It is the lowest of the prefixes at the end of the keyspace, so it is the limit of envelope iterators.
+var sourcesKeyPrefix = []byte{0xff, 'e', 's'}
+
+// deletedKeyPrefix is a prefix of soft deleted envelopes, it is followed by the deletion
+// timestamp and the envelope key. It is placed after the stats keys, so deleted envelopes
+// are excluded from envelope iterators.
+var deletedKeyPrefix = []byte{0xff, 'x', 'd'}
+
+// topicIndexKeyPrefix is a prefix of the topic index keys, it is followed by the bucket and the topic.
+var topicIndexKeyPrefix = []byte{0xff, 'x', 'i'}
+
+// bloomIndexKeyPrefix is a prefix of the bloom index keys, it is followed by the bucket.
+var bloomIndexKeyPrefix = []byte{0xff, 'x', 'b'}
+
+// archiveStatsKey is a key of the cumulative archive stats.
+var archiveStatsKey = []byte{0xff, 'x', 'a'}
+
+// coverageGapKeyPrefix is a prefix of the coverage gap keys, it is followed by the start of the gap.
+var coverageGapKeyPrefix = []byte{0xff, 'x', 'g'}
+
+// coverageHealedKeyPrefix is a prefix of the healing times of coverage gaps, it is followed by the start of the gap.
+var coverageHealedKeyPrefix = []byte{0xff, 'x', 'r'}
+
+// coverageHeartbeatKey is a key of the last coverage heartbeat.
+var coverageHeartbeatKey = []byte{0xff, 'x', 'h'}
+
+// schemaVersionKey is a key of the version of the keys layout.
+var schemaVersionKey = []byte{0xff, 'x', 'v'}
+
 type LevelDB struct {
 	// We can't embed as there are some state problems with go-routines
 	ldb *leveldb.DB
+	// statsMu serializes updates of the archive stats
+	statsMu sync.Mutex
+	// bloomIndexMu serializes merges of aggregated blooms
+	bloomIndexMu sync.Mutex
 }
 
 type LevelDBIterator struct {
 	iterator.Iterator
+	ctx context.Context
+	// descending iterates from the end of the range or from the cursor backwards
+	descending bool
+	cursor     []byte
+	started    bool
+}
+
+// Next stops the iteration when the context is done.
+func (i *LevelDBIterator) Next() bool {
+	if i.ctx.Err() != nil {
+		return false
+	}
+	if !i.descending {
+		return i.Iterator.Next()
+	}
+	if i.started {
+		return i.Iterator.Prev()
+	}
+	i.started = true
+	if len(i.cursor) == CursorLength {
+		// the first key at or after the cursor is the envelope of the cursor, it is not returned
+		if i.Iterator.Seek(i.cursor) {
+			return i.Iterator.Prev()
+		}
+	}
+	return i.Iterator.Last()
+}
+
+func (i *LevelDBIterator) Error() error {
+	if err := i.ctx.Err(); err != nil {
+		return err
+	}
+	return i.Iterator.Error()
 }
 
 func (i *LevelDBIterator) DBKey() (*DBKey, error) {
@@ -68,19 +146,66 @@ func NewLevelDB(dataDir string) (*LevelDB, error) {
 		log.Info("database is corrupted trying to recover", "path", dataDir)
 		db, err = leveldb.RecoverFile(dataDir, nil)
 	}
-	return &LevelDB{ldb: db}, err
+	if err != nil {
+		return &LevelDB{ldb: db}, err
+	}
+	ldb := &LevelDB{ldb: db}
+	if err := ldb.initSchemaVersion(); err != nil {
+		return ldb, err
+	}
+	return ldb, ldb.initArchiveStats()
+}
+
+// initSchemaVersion stores the version of a new database or refuses to use a database with a newer one.
+// Databases created before the version was stored have the first version.
+func (db *LevelDB) initSchemaVersion() error {
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version.Current == 0 {
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, levelDBSchemaVersion)
+		return db.ldb.Put(schemaVersionKey, value, nil)
+	}
+	return version.Check()
+}
+
+// SchemaVersion returns the version of the keys layout, zero if it is not stored
+func (db *LevelDB) SchemaVersion() (SchemaVersion, error) {
+	defer recoverLevelDBPanics("SchemaVersion")
+
+	version := SchemaVersion{Expected: levelDBSchemaVersion}
+	value, err := db.ldb.Get(schemaVersionKey, nil)
+	if err == leveldb.ErrNotFound {
+		return version, nil
+	} else if err != nil {
+		return version, err
+	}
+	if len(value) != 8 {
+		return version, fmt.Errorf("invalid schema version %x", value)
+	}
+	version.Current = binary.BigEndian.Uint64(value)
+	return version, nil
 }
 
 // Build iterator returns an iterator given a start/end and a cursor
-func (db *LevelDB) BuildIterator(query CursorQuery) (Iterator, error) {
+func (db *LevelDB) BuildIterator(ctx context.Context, query CursorQuery) (Iterator, error) {
 	defer recoverLevelDBPanics("BuildIterator")
 
-	i := db.ldb.NewIterator(&util.Range{Start: query.start, Limit: query.end}, nil)
-	// seek to the end as we want to return envelopes in a descending order
+	end := query.end
+	if bytes.Compare(end, sourcesKeyPrefix) > 0 {
+		end = sourcesKeyPrefix
+	}
+	i := db.ldb.NewIterator(&util.Range{Start: query.start, Limit: end}, nil)
+	if query.order == OrderDescending {
+		return &LevelDBIterator{Iterator: i, ctx: ctx, descending: true, cursor: query.cursor}, nil
+	}
+	// envelopes are returned in an ascending order, starting after the cursor
 	if len(query.cursor) == CursorLength {
 		i.Seek(query.cursor)
 	}
-	return &LevelDBIterator{i}, nil
+	return &LevelDBIterator{Iterator: i, ctx: ctx}, nil
 }
 
 // GetEnvelope get an envelope by its key
@@ -90,6 +215,29 @@ func (db *LevelDB) GetEnvelope(key *DBKey) ([]byte, error) {
 	return db.ldb.Get(key.Bytes(), nil)
 }
 
+// GetEnvelopes gets envelopes by their keys from a single snapshot.
+func (db *LevelDB) GetEnvelopes(keys []*DBKey) ([][]byte, error) {
+	defer recoverLevelDBPanics("GetEnvelopes")
+
+	snapshot, err := db.ldb.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	rst := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := snapshot.Get(key.Bytes(), nil)
+		if err == leveldb.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		rst[i] = value
+	}
+	return rst, nil
+}
+
 // Prune removes envelopes older than time
 func (db *LevelDB) Prune(t time.Time, batchSize int) (int, error) {
 	defer recoverLevelDBPanics("Prune")
@@ -98,46 +246,127 @@ func (db *LevelDB) Prune(t time.Time, batchSize int) (int, error) {
 	var emptyTopic types.TopicType
 	kl := NewDBKey(0, emptyTopic, zero)
 	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
-	query := CursorQuery{
-		start: kl.Bytes(),
-		end:   ku.Bytes(),
-	}
-	i, err := db.BuildIterator(query)
-	if err != nil {
-		return 0, err
+	i := db.ldb.NewIterator(&util.Range{Start: kl.Bytes(), Limit: ku.Bytes()}, nil)
+	defer i.Release()
+
+	return db.moveEntries(i, batchSize, nil, -1)
+}
+
+// PruneEstimate iterates over envelopes that would be removed by Prune without removing them.
+func (db *LevelDB) PruneEstimate(t time.Time) (stats PruneStats, err error) {
+	defer recoverLevelDBPanics("PruneEstimate")
+
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(0, emptyTopic, zero)
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
+	i := db.ldb.NewIterator(&util.Range{Start: kl.Bytes(), Limit: ku.Bytes()}, nil)
+	defer i.Release()
+
+	for i.Next() {
+		stats.Rows++
+		stats.Size += int64(len(i.Value()))
 	}
-	defer func() { _ = i.Release() }()
+	return stats, i.Error()
+}
 
+func deletedEnvelopeKey(deletedAt uint32, key []byte) []byte {
+	rst := make([]byte, len(deletedKeyPrefix)+timestampLength+len(key))
+	copy(rst, deletedKeyPrefix)
+	binary.BigEndian.PutUint32(rst[len(deletedKeyPrefix):], deletedAt)
+	copy(rst[len(deletedKeyPrefix)+timestampLength:], key)
+	return rst
+}
+
+// SoftPrune moves envelopes older than time under the deleted prefix with the deletion time.
+func (db *LevelDB) SoftPrune(t, deletedAt time.Time, batchSize int) (int, error) {
+	defer recoverLevelDBPanics("SoftPrune")
+
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(0, emptyTopic, zero)
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
+	i := db.ldb.NewIterator(&util.Range{Start: kl.Bytes(), Limit: ku.Bytes()}, nil)
+	defer i.Release()
+
+	return db.moveEntries(i, batchSize, func(key []byte) []byte {
+		return deletedEnvelopeKey(uint32(deletedAt.Unix()), key)
+	}, -1)
+}
+
+// Restore moves envelopes deleted at or after time back to their original keys.
+func (db *LevelDB) Restore(since time.Time) (int, error) {
+	defer recoverLevelDBPanics("Restore")
+
+	i := db.ldb.NewIterator(&util.Range{
+		Start: deletedEnvelopeKey(uint32(since.Unix()), nil),
+		Limit: util.BytesPrefix(deletedKeyPrefix).Limit,
+	}, nil)
+	defer i.Release()
+
+	return db.moveEntries(i, dbCleanerBatchSize, func(key []byte) []byte {
+		return key[len(deletedKeyPrefix)+timestampLength:]
+	}, 1)
+}
+
+// Vacuum removes envelopes deleted before time.
+func (db *LevelDB) Vacuum(t time.Time) (int, error) {
+	defer recoverLevelDBPanics("Vacuum")
+
+	i := db.ldb.NewIterator(&util.Range{
+		Start: deletedEnvelopeKey(0, nil),
+		Limit: deletedEnvelopeKey(uint32(t.Unix()), nil),
+	}, nil)
+	defer i.Release()
+
+	return db.moveEntries(i, dbCleanerBatchSize, nil, 0)
+}
+
+// moveEntries stores values of the iterator under keys returned by target and removes
+// the original keys. Entries are only removed if target is nil. The sign of archived tells
+// whether moved entries leave the archive (-1), return to it (1) or are not counted in it (0).
+func (db *LevelDB) moveEntries(i iterator.Iterator, batchSize int, target func([]byte) []byte, archived int64) (int, error) {
 	batch := leveldb.Batch{}
-	removed := 0
+	moved, pending := 0, 0
+	var size int64
+	write := func() error {
+		if archived == 0 {
+			return db.ldb.Write(&batch, nil)
+		}
+		return db.writeWithArchiveStats(&batch, archived*int64(pending), archived*size)
+	}
 
 	for i.Next() {
-		dbKey, err := i.DBKey()
-		if err != nil {
-			return 0, err
+		if target != nil {
+			batch.Put(target(i.Key()), i.Value())
 		}
+		batch.Delete(i.Key())
+		pending++
+		size += int64(len(i.Value()))
 
-		batch.Delete(dbKey.Bytes())
-
-		if batch.Len() == batchSize {
-			if err := db.ldb.Write(&batch, nil); err != nil {
-				return removed, err
+		if pending == batchSize {
+			if err := write(); err != nil {
+				return moved, err
 			}
 
-			removed = removed + batch.Len()
+			moved = moved + pending
+			pending, size = 0, 0
 			batch.Reset()
 		}
 	}
+	if err := i.Error(); err != nil {
+		return moved, err
+	}
 
-	if batch.Len() > 0 {
-		if err := db.ldb.Write(&batch, nil); err != nil {
-			return removed, err
+	if pending > 0 {
+		if err := write(); err != nil {
+			return moved, err
 		}
 
-		removed = removed + batch.Len()
+		moved = moved + pending
 	}
 
-	return removed, nil
+	return moved, nil
 }
 
 // SaveEnvelope stores an envelope in leveldb and increments the metrics
@@ -152,7 +381,7 @@ func (db *LevelDB) SaveEnvelope(env types.Envelope) error {
 		return err
 	}
 
-	if err = db.ldb.Put(key.Bytes(), rawEnvelope, nil); err != nil {
+	if err = db.putEnvelope(key.Bytes(), rawEnvelope); err != nil {
 		log.Error(fmt.Sprintf("Writing to DB failed: %s", err))
 		archivedErrorsCounter.Inc()
 	}
@@ -161,6 +390,670 @@ func (db *LevelDB) SaveEnvelope(env types.Envelope) error {
 	return err
 }
 
+func topicStatsKey(bucket uint32, topic types.TopicType) []byte {
+	key := make([]byte, len(statsKeyPrefix)+timestampLength+types.TopicLength)
+	copy(key, statsKeyPrefix)
+	binary.BigEndian.PutUint32(key[len(statsKeyPrefix):], bucket)
+	copy(key[len(statsKeyPrefix)+timestampLength:], topic[:])
+	return key
+}
+
+func decodeTopicStats(value []byte, stats *TopicStats) {
+	if len(value) != 16 {
+		return
+	}
+	stats.Envelopes += binary.BigEndian.Uint64(value[:8])
+	stats.Bytes += binary.BigEndian.Uint64(value[8:])
+}
+
+func encodeTopicStats(stats TopicStats) []byte {
+	value := make([]byte, 16)
+	binary.BigEndian.PutUint64(value[:8], stats.Envelopes)
+	binary.BigEndian.PutUint64(value[8:], stats.Bytes)
+	return value
+}
+
+// SaveTopicStats increments counters of the bucket
+func (db *LevelDB) SaveTopicStats(bucket uint32, stats []TopicStats) error {
+	defer recoverLevelDBPanics("SaveTopicStats")
+
+	batch := leveldb.Batch{}
+	for _, stat := range stats {
+		key := topicStatsKey(bucket, stat.Topic)
+		value, err := db.ldb.Get(key, nil)
+		if err != nil && err != errors.ErrNotFound {
+			return err
+		}
+		decodeTopicStats(value, &stat)
+		batch.Put(key, encodeTopicStats(stat))
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+// TopicStats returns stats aggregated per topic starting from a given bucket
+func (db *LevelDB) TopicStats(since uint32) ([]TopicStats, error) {
+	defer recoverLevelDBPanics("TopicStats")
+
+	var zero types.TopicType
+	i := db.ldb.NewIterator(&util.Range{Start: topicStatsKey(since, zero)}, nil)
+	defer i.Release()
+
+	aggregated := map[types.TopicType]*TopicStats{}
+	for i.Next() {
+		key := i.Key()
+		if !bytes.HasPrefix(key, statsKeyPrefix) {
+			break
+		}
+		topic := types.BytesToTopic(key[len(statsKeyPrefix)+timestampLength:])
+		stat, ok := aggregated[topic]
+		if !ok {
+			stat = &TopicStats{Topic: topic}
+			aggregated[topic] = stat
+		}
+		decodeTopicStats(i.Value(), stat)
+	}
+	if err := i.Error(); err != nil {
+		return nil, err
+	}
+
+	rst := make([]TopicStats, 0, len(aggregated))
+	for _, stat := range aggregated {
+		rst = append(rst, *stat)
+	}
+	return rst, nil
+}
+
+// PruneTopicStats removes buckets older than time
+func (db *LevelDB) PruneTopicStats(t time.Time) error {
+	defer recoverLevelDBPanics("PruneTopicStats")
+
+	var zero types.TopicType
+	i := db.ldb.NewIterator(&util.Range{
+		Start: topicStatsKey(0, zero),
+		Limit: topicStatsKey(topicStatsBucketFor(t), zero),
+	}, nil)
+	defer i.Release()
+
+	batch := leveldb.Batch{}
+	for i.Next() {
+		batch.Delete(i.Key())
+	}
+	if err := i.Error(); err != nil {
+		return err
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+func topicIndexKey(bucket uint32, topic types.TopicType) []byte {
+	key := make([]byte, len(topicIndexKeyPrefix)+timestampLength+types.TopicLength)
+	copy(key, topicIndexKeyPrefix)
+	binary.BigEndian.PutUint32(key[len(topicIndexKeyPrefix):], bucket)
+	copy(key[len(topicIndexKeyPrefix)+timestampLength:], topic[:])
+	return key
+}
+
+// SaveTopicIndex marks topics as present in the bucket
+func (db *LevelDB) SaveTopicIndex(bucket uint32, topics []types.TopicType) error {
+	defer recoverLevelDBPanics("SaveTopicIndex")
+
+	batch := leveldb.Batch{}
+	for _, topic := range topics {
+		batch.Put(topicIndexKey(bucket, topic), nil)
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+// IndexedTopics returns distinct topics present in buckets from the first to the last one
+func (db *LevelDB) IndexedTopics(from, to uint32) ([]types.TopicType, error) {
+	defer recoverLevelDBPanics("IndexedTopics")
+
+	var zero types.TopicType
+	limit := util.BytesPrefix(topicIndexKeyPrefix).Limit
+	if to < math.MaxUint32 {
+		limit = topicIndexKey(to+1, zero)
+	}
+	i := db.ldb.NewIterator(&util.Range{Start: topicIndexKey(from, zero), Limit: limit}, nil)
+	defer i.Release()
+
+	seen := map[types.TopicType]bool{}
+	var rst []types.TopicType
+	for i.Next() {
+		topic := types.BytesToTopic(i.Key()[len(topicIndexKeyPrefix)+timestampLength:])
+		if seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		rst = append(rst, topic)
+	}
+	return rst, i.Error()
+}
+
+// TopicIndexStart returns the first bucket in the topic index
+func (db *LevelDB) TopicIndexStart() (uint32, error) {
+	defer recoverLevelDBPanics("TopicIndexStart")
+
+	i := db.ldb.NewIterator(util.BytesPrefix(topicIndexKeyPrefix), nil)
+	defer i.Release()
+
+	if !i.Next() {
+		return 0, i.Error()
+	}
+	return binary.BigEndian.Uint32(i.Key()[len(topicIndexKeyPrefix):]), nil
+}
+
+// PruneTopicIndex removes buckets before a given one
+func (db *LevelDB) PruneTopicIndex(bucket uint32) error {
+	defer recoverLevelDBPanics("PruneTopicIndex")
+
+	var zero types.TopicType
+	i := db.ldb.NewIterator(&util.Range{
+		Start: topicIndexKey(0, zero),
+		Limit: topicIndexKey(bucket, zero),
+	}, nil)
+	defer i.Release()
+
+	batch := leveldb.Batch{}
+	for i.Next() {
+		batch.Delete(i.Key())
+	}
+	if err := i.Error(); err != nil {
+		return err
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+func bloomIndexKey(bucket uint32) []byte {
+	key := make([]byte, len(bloomIndexKeyPrefix)+timestampLength)
+	copy(key, bloomIndexKeyPrefix)
+	binary.BigEndian.PutUint32(key[len(bloomIndexKeyPrefix):], bucket)
+	return key
+}
+
+// SaveBucketBloom merges the bloom into the aggregated bloom of the bucket
+func (db *LevelDB) SaveBucketBloom(bucket uint32, bloom []byte) error {
+	defer recoverLevelDBPanics("SaveBucketBloom")
+
+	db.bloomIndexMu.Lock()
+	defer db.bloomIndexMu.Unlock()
+
+	key := bloomIndexKey(bucket)
+	stored, err := db.ldb.Get(key, nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return err
+	}
+	rst := make([]byte, types.BloomFilterSize)
+	copy(rst, stored)
+	for j := 0; j < len(bloom) && j < len(rst); j++ {
+		rst[j] |= bloom[j]
+	}
+	return db.ldb.Put(key, rst, nil)
+}
+
+// BucketBlooms returns aggregated blooms of buckets from the first to the last one
+func (db *LevelDB) BucketBlooms(from, to uint32) (map[uint32][]byte, error) {
+	defer recoverLevelDBPanics("BucketBlooms")
+
+	limit := util.BytesPrefix(bloomIndexKeyPrefix).Limit
+	if to < math.MaxUint32 {
+		limit = bloomIndexKey(to + 1)
+	}
+	i := db.ldb.NewIterator(&util.Range{Start: bloomIndexKey(from), Limit: limit}, nil)
+	defer i.Release()
+
+	rst := map[uint32][]byte{}
+	for i.Next() {
+		bucket := binary.BigEndian.Uint32(i.Key()[len(bloomIndexKeyPrefix):])
+		rst[bucket] = append([]byte{}, i.Value()...)
+	}
+	return rst, i.Error()
+}
+
+// BloomIndexStart returns the first bucket in the bloom index
+func (db *LevelDB) BloomIndexStart() (uint32, error) {
+	defer recoverLevelDBPanics("BloomIndexStart")
+
+	i := db.ldb.NewIterator(util.BytesPrefix(bloomIndexKeyPrefix), nil)
+	defer i.Release()
+
+	if !i.Next() {
+		return 0, i.Error()
+	}
+	return binary.BigEndian.Uint32(i.Key()[len(bloomIndexKeyPrefix):]), nil
+}
+
+// PruneBloomIndex removes buckets before a given one
+func (db *LevelDB) PruneBloomIndex(bucket uint32) error {
+	defer recoverLevelDBPanics("PruneBloomIndex")
+
+	i := db.ldb.NewIterator(&util.Range{
+		Start: bloomIndexKey(0),
+		Limit: bloomIndexKey(bucket),
+	}, nil)
+	defer i.Release()
+
+	batch := leveldb.Batch{}
+	for i.Next() {
+		batch.Delete(i.Key())
+	}
+	if err := i.Error(); err != nil {
+		return err
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+func envelopeSourceKey(key []byte) []byte {
+	return append(append([]byte{}, sourcesKeyPrefix...), key...)
+}
+
+func envelopeSourceKeyAt(t time.Time) []byte {
+	var (
+		zero       types.Hash
+		emptyTopic types.TopicType
+	)
+	return envelopeSourceKey(NewDBKey(uint32(t.Unix()), emptyTopic, zero).Bytes())
+}
+
+// SaveEnvelopeSources stores the peer and the size of every envelope
+func (db *LevelDB) SaveEnvelopeSources(records []EnvelopeSourceRecord) error {
+	defer recoverLevelDBPanics("SaveEnvelopeSources")
+
+	batch := leveldb.Batch{}
+	for _, record := range records {
+		value := make([]byte, types.HashLength+8)
+		copy(value, record.Peer[:])
+		binary.BigEndian.PutUint64(value[types.HashLength:], record.Size)
+		batch.Put(envelopeSourceKey(record.Key.Bytes()), value)
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+// EnvelopeSources returns sources aggregated per peer of envelopes sent starting from a given time
+func (db *LevelDB) EnvelopeSources(since time.Time) ([]EnvelopeSource, error) {
+	defer recoverLevelDBPanics("EnvelopeSources")
+
+	i := db.ldb.NewIterator(&util.Range{Start: envelopeSourceKeyAt(since)}, nil)
+	defer i.Release()
+
+	var sources []EnvelopeSource
+	for i.Next() {
+		if !bytes.HasPrefix(i.Key(), sourcesKeyPrefix) {
+			break
+		}
+		value := i.Value()
+		if len(value) != types.HashLength+8 {
+			continue
+		}
+		sources = append(sources, EnvelopeSource{
+			Peer:      types.BytesToHash(value[:types.HashLength]),
+			Envelopes: 1,
+			Bytes:     binary.BigEndian.Uint64(value[types.HashLength:]),
+		})
+	}
+	if err := i.Error(); err != nil {
+		return nil, err
+	}
+	return aggregateEnvelopeSources(sources), nil
+}
+
+// PruneEnvelopeSources removes sources of envelopes older than time
+func (db *LevelDB) PruneEnvelopeSources(t time.Time) error {
+	defer recoverLevelDBPanics("PruneEnvelopeSources")
+
+	i := db.ldb.NewIterator(&util.Range{
+		Start: envelopeSourceKeyAt(time.Unix(0, 0)),
+		Limit: envelopeSourceKeyAt(t),
+	}, nil)
+	defer i.Release()
+
+	batch := leveldb.Batch{}
+	for i.Next() {
+		batch.Delete(i.Key())
+	}
+	if err := i.Error(); err != nil {
+		return err
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+// RepairStats is a result of the consistency check.
+type RepairStats struct {
+	// Checked is a number of validated envelopes.
+	Checked int
+	// Dropped is a number of envelopes that could not be decoded or didn't match their keys.
+	Dropped int
+}
+
+// validEnvelope returns true if the value is a decodable envelope with a hash matching the key.
+func validEnvelope(key, value []byte) bool {
+	var envelope whisper.Envelope
+	if err := rlp.DecodeBytes(value, &envelope); err != nil {
+		return false
+	}
+	if len(key) != DBKeyLength {
+		// legacy keys don't include a topic, only the value is validated
+		return true
+	}
+	dbKey := DBKey{raw: key}
+	return crypto.Keccak256Hash(value) == dbKey.EnvelopeHash()
+}
+
+// Repair validates envelopes archived since a given time. Torn writes left after a crash
+// are removed together with their sources and the affected range is compacted.
+func (db *LevelDB) Repair(since time.Time) (stats RepairStats, err error) {
+	defer recoverLevelDBPanics("Repair")
+
+	var (
+		zero       types.Hash
+		emptyTopic types.TopicType
+	)
+	start := NewDBKey(uint32(since.Unix()), emptyTopic, zero).Bytes()
+	// envelope sources are the first keys after envelopes
+	i := db.ldb.NewIterator(&util.Range{Start: start, Limit: sourcesKeyPrefix}, nil)
+	defer i.Release()
+
+	var (
+		batch       leveldb.Batch
+		first, last []byte
+		size        int64
+	)
+	for i.Next() {
+		stats.Checked++
+		if validEnvelope(i.Key(), i.Value()) {
+			continue
+		}
+		size += int64(len(i.Value()))
+		key := append([]byte{}, i.Key()...)
+		log.Warn("dropping corrupted envelope", "key", fmt.Sprintf("%x", key))
+		batch.Delete(key)
+		batch.Delete(envelopeSourceKey(key))
+		if first == nil {
+			first = key
+		}
+		last = key
+		stats.Dropped++
+	}
+	if err := i.Error(); err != nil {
+		return stats, err
+	}
+	if stats.Dropped == 0 {
+		return stats, nil
+	}
+	if err := db.writeWithArchiveStats(&batch, -int64(stats.Dropped), -size); err != nil {
+		return stats, err
+	}
+	// the limit is exclusive, the last removed key is included with a zero byte suffix
+	err = db.ldb.CompactRange(util.Range{Start: first, Limit: append(last, 0)})
+	return stats, err
+}
+
+// leveldbReader is implemented by the database and its transactions.
+type leveldbReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+func readArchiveStats(r leveldbReader) (ArchiveStats, error) {
+	var stats ArchiveStats
+	value, err := r.Get(archiveStatsKey, nil)
+	if err == leveldb.ErrNotFound {
+		return stats, nil
+	} else if err != nil {
+		return stats, err
+	}
+	return stats, rlp.DecodeBytes(value, &stats)
+}
+
+// archiveBounds returns the first and the last envelope keys.
+func archiveBounds(r leveldbReader) (oldest, newest []byte, err error) {
+	i := r.NewIterator(&util.Range{Limit: sourcesKeyPrefix}, nil)
+	defer i.Release()
+	if i.First() {
+		oldest = append([]byte{}, i.Key()...)
+	}
+	if i.Last() {
+		newest = append([]byte{}, i.Key()...)
+	}
+	return oldest, newest, i.Error()
+}
+
+// initArchiveStats counts envelopes archived before the stats were introduced.
+func (db *LevelDB) initArchiveStats() error {
+	exists, err := db.ldb.Has(archiveStatsKey, nil)
+	if err != nil || exists {
+		return err
+	}
+	log.Info("computing mailserver archive stats")
+	var stats ArchiveStats
+	i := db.ldb.NewIterator(&util.Range{Limit: sourcesKeyPrefix}, nil)
+	defer i.Release()
+	for i.Next() {
+		stats.add(i.Key(), int64(len(i.Value())))
+	}
+	if err := i.Error(); err != nil {
+		return err
+	}
+	value, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	return db.ldb.Put(archiveStatsKey, value, nil)
+}
+
+// putEnvelope stores an envelope and counts it in the archive stats, unless it is already stored.
+func (db *LevelDB) putEnvelope(key, value []byte) error {
+	db.statsMu.Lock()
+	defer db.statsMu.Unlock()
+
+	exists, err := db.ldb.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	batch := leveldb.Batch{}
+	batch.Put(key, value)
+	if !exists {
+		stats, err := readArchiveStats(db.ldb)
+		if err != nil {
+			return err
+		}
+		stats.add(key, int64(len(value)))
+		encoded, err := rlp.EncodeToBytes(stats)
+		if err != nil {
+			return err
+		}
+		batch.Put(archiveStatsKey, encoded)
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+// writeWithArchiveStats writes the batch and updates the archive stats in a single transaction.
+func (db *LevelDB) writeWithArchiveStats(batch *leveldb.Batch, envelopes, size int64) error {
+	db.statsMu.Lock()
+	defer db.statsMu.Unlock()
+
+	tr, err := db.ldb.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		if err := tr.Write(batch, nil); err != nil {
+			return err
+		}
+		stats, err := readArchiveStats(tr)
+		if err != nil {
+			return err
+		}
+		stats.update(envelopes, size)
+		stats.Oldest, stats.Newest, err = archiveBounds(tr)
+		if err != nil {
+			return err
+		}
+		value, err := rlp.EncodeToBytes(stats)
+		if err != nil {
+			return err
+		}
+		return tr.Put(archiveStatsKey, value, nil)
+	}()
+	if err != nil {
+		tr.Discard()
+		return err
+	}
+	return tr.Commit()
+}
+
+// CompactPruned compacts ranges of envelopes older than time, their sources and soft deleted
+// envelopes. Pruning leaves tombstones in these ranges until they are compacted.
+func (db *LevelDB) CompactPruned(t time.Time) error {
+	defer recoverLevelDBPanics("CompactPruned")
+
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(0, emptyTopic, zero).Bytes()
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero).Bytes()
+	ranges := []util.Range{
+		{Start: kl, Limit: ku},
+		{Start: envelopeSourceKey(kl), Limit: envelopeSourceKey(ku)},
+		*util.BytesPrefix(deletedKeyPrefix),
+	}
+	for _, r := range ranges {
+		if err := db.ldb.CompactRange(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compact compacts the whole database.
+func (db *LevelDB) Compact() error {
+	defer recoverLevelDBPanics("Compact")
+
+	return db.ldb.CompactRange(util.Range{})
+}
+
+// DiskUsage returns the approximate size on disk of envelopes archived between two times,
+// split into buckets of a given length. Envelopes that are not flushed to disk yet are not counted.
+func (db *LevelDB) DiskUsage(from, to time.Time, bucket time.Duration) ([]BucketDiskUsage, error) {
+	defer recoverLevelDBPanics("DiskUsage")
+
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	var (
+		buckets []BucketDiskUsage
+		ranges  []util.Range
+	)
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		end := start.Add(bucket)
+		if end.After(to) {
+			end = to
+		}
+		buckets = append(buckets, BucketDiskUsage{Bucket: uint32(start.Unix())})
+		ranges = append(ranges, util.Range{
+			Start: NewDBKey(uint32(start.Unix()), emptyTopic, zero).Bytes(),
+			Limit: NewDBKey(uint32(end.Unix()), emptyTopic, zero).Bytes(),
+		})
+	}
+	sizes, err := db.ldb.SizeOf(ranges)
+	if err != nil {
+		return nil, err
+	}
+	for i, size := range sizes {
+		buckets[i].Size = uint64(size)
+	}
+	return buckets, nil
+}
+
+// ArchiveStats returns cumulative stats of archived envelopes
+func (db *LevelDB) ArchiveStats() (ArchiveStats, error) {
+	defer recoverLevelDBPanics("ArchiveStats")
+
+	return readArchiveStats(db.ldb)
+}
+
+// SaveCoverageGap stores a gap, a gap with the same start is replaced
+func (db *LevelDB) SaveCoverageGap(gap CoverageGap) error {
+	defer recoverLevelDBPanics("SaveCoverageGap")
+
+	value := make([]byte, timestampLength+len(gap.Reason))
+	binary.BigEndian.PutUint32(value, gap.To)
+	copy(value[timestampLength:], gap.Reason)
+	batch := leveldb.Batch{}
+	batch.Put(coverageKey(coverageGapKeyPrefix, gap.From), value)
+	if gap.HealedAt != 0 {
+		healedAt := make([]byte, timestampLength)
+		binary.BigEndian.PutUint32(healedAt, gap.HealedAt)
+		batch.Put(coverageKey(coverageHealedKeyPrefix, gap.From), healedAt)
+	} else {
+		batch.Delete(coverageKey(coverageHealedKeyPrefix, gap.From))
+	}
+	return db.ldb.Write(&batch, nil)
+}
+
+func coverageKey(prefix []byte, from uint32) []byte {
+	key := make([]byte, len(prefix)+timestampLength)
+	copy(key, prefix)
+	binary.BigEndian.PutUint32(key[len(prefix):], from)
+	return key
+}
+
+// CoverageGaps returns gaps overlapping the range between two unix timestamps
+func (db *LevelDB) CoverageGaps(from, to uint32) ([]CoverageGap, error) {
+	defer recoverLevelDBPanics("CoverageGaps")
+
+	i := db.ldb.NewIterator(util.BytesPrefix(coverageGapKeyPrefix), nil)
+	defer i.Release()
+
+	var gaps []CoverageGap
+	for i.Next() {
+		key, value := i.Key(), i.Value()
+		if len(key) != len(coverageGapKeyPrefix)+timestampLength || len(value) < timestampLength {
+			continue
+		}
+		gap := CoverageGap{
+			From:   binary.BigEndian.Uint32(key[len(coverageGapKeyPrefix):]),
+			To:     binary.BigEndian.Uint32(value),
+			Reason: string(value[timestampLength:]),
+		}
+		if gap.From > to {
+			break
+		}
+		if gap.To < from {
+			continue
+		}
+		healedAt, err := db.ldb.Get(coverageKey(coverageHealedKeyPrefix, gap.From), nil)
+		if err == nil && len(healedAt) == timestampLength {
+			gap.HealedAt = binary.BigEndian.Uint32(healedAt)
+		} else if err != nil && err != leveldb.ErrNotFound {
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps, i.Error()
+}
+
+// SaveCoverageHeartbeat stores the last unix time the mailserver was known to archive envelopes
+func (db *LevelDB) SaveCoverageHeartbeat(timestamp uint32) error {
+	defer recoverLevelDBPanics("SaveCoverageHeartbeat")
+
+	value := make([]byte, timestampLength)
+	binary.BigEndian.PutUint32(value, timestamp)
+	return db.ldb.Put(coverageHeartbeatKey, value, nil)
+}
+
+// CoverageHeartbeat returns the last stored heartbeat, zero if there is none
+func (db *LevelDB) CoverageHeartbeat() (uint32, error) {
+	defer recoverLevelDBPanics("CoverageHeartbeat")
+
+	value, err := db.ldb.Get(coverageHeartbeatKey, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(value) != timestampLength {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint32(value), nil
+}
+
 func (db *LevelDB) Close() error {
 	return db.ldb.Close()
 }
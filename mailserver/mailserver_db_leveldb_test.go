@@ -0,0 +1,178 @@
+package mailserver
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+func TestLevelDBRepair(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	db := server.ms.db.(*LevelDB)
+
+	now := time.Now()
+	old := archiveEnvelope(t, now.Add(-2*time.Hour), server)
+	valid := archiveEnvelope(t, now.Add(-time.Minute), server)
+	torn := archiveEnvelope(t, now.Add(-time.Minute), server)
+
+	// simulate torn writes
+	tornKey := NewDBKey(torn.Expiry-torn.TTL, types.TopicType(torn.Topic), types.Hash(torn.Hash()))
+	value, err := db.GetEnvelope(tornKey)
+	require.NoError(t, err)
+	require.NoError(t, db.ldb.Put(tornKey.Bytes(), value[:len(value)/2], nil))
+	require.NoError(t, db.SaveEnvelopeSources([]EnvelopeSourceRecord{{Key: tornKey, Size: 1}}))
+	oldKey := NewDBKey(old.Expiry-old.TTL, types.TopicType(old.Topic), types.Hash(old.Hash()))
+	require.NoError(t, db.ldb.Put(oldKey.Bytes(), []byte{1}, nil))
+	// a valid envelope stored under a key of another envelope
+	mismatchKey := NewDBKey(valid.Expiry-valid.TTL, types.TopicType(valid.Topic), types.Hash{1})
+	require.NoError(t, db.ldb.Put(mismatchKey.Bytes(), value, nil))
+
+	stats, err := db.Repair(now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, RepairStats{Checked: 3, Dropped: 2}, stats)
+
+	_, err = db.GetEnvelope(tornKey)
+	require.Error(t, err)
+	_, err = db.GetEnvelope(mismatchKey)
+	require.Error(t, err)
+	_, err = db.GetEnvelope(NewDBKey(valid.Expiry-valid.TTL, types.TopicType(valid.Topic), types.Hash(valid.Hash())))
+	require.NoError(t, err)
+	// envelopes outside of the window are not checked
+	_, err = db.GetEnvelope(oldKey)
+	require.NoError(t, err)
+
+	sources, err := db.EnvelopeSources(time.Unix(0, 0))
+	require.NoError(t, err)
+	require.Empty(t, sources)
+
+	stats, err = db.Repair(now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, RepairStats{Checked: 1}, stats)
+}
+
+func TestConsistencyCheckWindow(t *testing.T) {
+	require.Equal(t, time.Duration(0), consistencyCheckWindow(false, 10))
+	require.Equal(t, 10*time.Minute, consistencyCheckWindow(true, 10))
+	require.Equal(t, time.Hour, consistencyCheckWindow(true, 0))
+}
+
+func TestLevelDBGetEnvelopes(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Now()
+	first := archiveEnvelope(t, now.Add(-time.Minute), server)
+	second := archiveEnvelope(t, now, server)
+	missing := NewDBKey(uint32(now.Unix()), types.TopicType{}, types.Hash{0xff})
+
+	envelopes, err := server.ms.db.GetEnvelopes([]*DBKey{
+		NewDBKey(second.Expiry-second.TTL, types.TopicType(second.Topic), types.Hash(second.Hash())),
+		missing,
+		NewDBKey(first.Expiry-first.TTL, types.TopicType(first.Topic), types.Hash(first.Hash())),
+	})
+	require.NoError(t, err)
+	require.Len(t, envelopes, 3)
+	require.Nil(t, envelopes[1])
+	for n, env := range map[int]*whisper.Envelope{0: second, 2: first} {
+		var received whisper.Envelope
+		require.NoError(t, rlp.DecodeBytes(envelopes[n], &received))
+		require.Equal(t, env.Hash(), received.Hash())
+	}
+}
+
+func TestLevelDBArchiveStats(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	var (
+		keys []*DBKey
+		size uint64
+	)
+	for _, sent := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now} {
+		env, err := generateEnvelope(sent)
+		require.NoError(t, err)
+		wrapped := gethbridge.NewWhisperEnvelope(env)
+		require.NoError(t, db.SaveEnvelope(wrapped))
+		// saving the same envelope again doesn't change the stats
+		require.NoError(t, db.SaveEnvelope(wrapped))
+		key := NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash())
+		value, err := db.GetEnvelope(key)
+		require.NoError(t, err)
+		keys = append(keys, key)
+		size += uint64(len(value))
+	}
+	// sources are stored after envelopes and are not counted
+	require.NoError(t, db.SaveEnvelopeSources([]EnvelopeSourceRecord{{Key: keys[2], Peer: types.Hash{1}, Size: 10}}))
+
+	stats, err := db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), stats.Envelopes)
+	require.Equal(t, size, stats.Size)
+	require.Equal(t, types.HexBytes(keys[0].Bytes()), stats.Oldest)
+	require.Equal(t, types.HexBytes(keys[2].Bytes()), stats.Newest)
+
+	_, err = db.SoftPrune(now.Add(-90*time.Minute), now, 10)
+	require.NoError(t, err)
+	stats, err = db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), stats.Envelopes)
+	require.Equal(t, types.HexBytes(keys[1].Bytes()), stats.Oldest)
+
+	_, err = db.Restore(now)
+	require.NoError(t, err)
+	stats, err = db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), stats.Envelopes)
+	require.Equal(t, size, stats.Size)
+	require.Equal(t, types.HexBytes(keys[0].Bytes()), stats.Oldest)
+
+	_, err = db.Prune(now.Add(-30*time.Minute), 1)
+	require.NoError(t, err)
+	stats, err = db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats.Envelopes)
+	require.Equal(t, types.HexBytes(keys[2].Bytes()), stats.Oldest)
+	require.Equal(t, types.HexBytes(keys[2].Bytes()), stats.Newest)
+
+	// stats of an archive created before they were introduced are computed once
+	require.NoError(t, db.ldb.Delete(archiveStatsKey, nil))
+	require.NoError(t, db.initArchiveStats())
+	recomputed, err := db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, stats, recomputed)
+}
+
+func TestLevelDBSchemaVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailserver-schema-version")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := NewLevelDB(dir)
+	require.NoError(t, err)
+	version, err := db.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion{Current: levelDBSchemaVersion, Expected: levelDBSchemaVersion}, version)
+
+	// a newer mailserver changed the keys layout
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, levelDBSchemaVersion+1)
+	require.NoError(t, db.ldb.Put(schemaVersionKey, value, nil))
+	require.NoError(t, db.Close())
+
+	db, err = NewLevelDB(dir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrSchemaTooNew.Error())
+	require.NoError(t, db.Close())
+}
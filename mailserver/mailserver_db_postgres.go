@@ -1,7 +1,9 @@
 package mailserver
 
 import (
+	"context"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -20,23 +22,65 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/whisper/v6"
 )
 
+// ErrTenantQuotaExceeded is returned when an envelope doesn't fit into the quota of the tenant.
+var ErrTenantQuotaExceeded = errors.New("tenant quota exceeded")
+
+// TenantConfig scopes envelopes in a database shared by mailservers of multiple logical networks.
+type TenantConfig struct {
+	// Name of the tenant. Empty name is the default tenant that owns envelopes archived before tenants were introduced.
+	Name string
+	// Quota is a maximum total size of archived envelopes of the tenant in bytes, unlimited if zero.
+	Quota uint64
+}
+
+// tenantLabel returns a metrics label of the tenant.
+func tenantLabel(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+func postgresTenantConfig(cfg params.PGConfig) TenantConfig {
+	return TenantConfig{Name: cfg.Tenant, Quota: cfg.TenantQuota}
+}
+
+// PostgresDB stores envelopes of a single tenant.
 type PostgresDB struct {
-	db *sql.DB
+	db      *sql.DB
+	tenant  TenantConfig
+	monitor *postgresMonitor
 }
 
-func NewPostgresDB(uri string) (*PostgresDB, error) {
-	db, err := sql.Open("postgres", uri)
+// NewPostgresDB connects to the database, applies migrations and starts health checks of the connection pool.
+// Plaintext connections are refused unless allowed in the TLS config.
+func NewPostgresDB(uri string, tlsConfig PostgresTLSConfig, tenant TenantConfig) (*PostgresDB, error) {
+	if err := tlsConfig.Validate(); err != nil {
+		return nil, err
+	}
+	conn, err := postgresConnString(uri, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPostgresSSL(db, tlsConfig); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 
-	instance := &PostgresDB{db: db}
+	instance := &PostgresDB{db: db, tenant: tenant}
 	if err := instance.setup(); err != nil {
 		return nil, err
 	}
+	instance.monitor = newPostgresMonitor(db, tenant.Name)
+	instance.monitor.Start()
 
 	return instance, nil
 }
@@ -72,23 +116,30 @@ func (i *postgresIterator) GetEnvelope(bloom []byte) ([]byte, error) {
 	return value, nil
 }
 
-func (i *PostgresDB) BuildIterator(query CursorQuery) (Iterator, error) {
-	var args []interface{}
+// BuildIterator runs the query with the context, so the query is canceled in Postgres
+// when the context is done and rows that are not fetched yet are not returned.
+func (i *PostgresDB) BuildIterator(ctx context.Context, query CursorQuery) (Iterator, error) {
+	args := []interface{}{i.tenant.Name}
 
-	stmtString := "SELECT id, data FROM envelopes"
+	stmtString := "SELECT id, data FROM envelopes WHERE tenant = $1"
 
-	if len(query.cursor) > 0 {
+	switch {
+	case len(query.cursor) > 0 && query.order == OrderAscending:
+		args = append(args, query.cursor, query.end)
+		// If we have a cursor, we don't want to include that envelope in the result set
+		stmtString += " " + "AND id > $2 AND id <= $3 AND deleted_at IS NULL"
+	case len(query.cursor) > 0:
 		args = append(args, query.start, query.cursor)
 		// If we have a cursor, we don't want to include that envelope in the result set
-		stmtString += " " + "WHERE id >= $1 AND id < $2"
-	} else {
+		stmtString += " " + "AND id >= $2 AND id < $3 AND deleted_at IS NULL"
+	default:
 		args = append(args, query.start, query.end)
-		stmtString += " " + "WHERE id >= $1 AND id <= $2"
+		stmtString += " " + "AND id >= $2 AND id <= $3 AND deleted_at IS NULL"
 	}
 
 	if len(query.topics) > 0 {
 		args = append(args, pq.Array(query.topics))
-		stmtString += " " + "AND topic = any($3)"
+		stmtString += " " + "AND topic = any($4)"
 	} else {
 		stmtString += " " + fmt.Sprintf("AND bloom & b'%s'::bit(512) = bloom", toBitString(query.bloom))
 	}
@@ -97,13 +148,13 @@ func (i *PostgresDB) BuildIterator(query CursorQuery) (Iterator, error) {
 	// If topic is used, the list of topics is passed as an argument to the query.
 	// If bloom filter is used, it is included into the query statement.
 	args = append(args, query.limit)
-	stmtString += " " + fmt.Sprintf("ORDER BY ID DESC LIMIT $%d", len(args))
-
-	stmt, err := i.db.Prepare(stmtString)
-	if err != nil {
-		return nil, err
+	direction := "DESC"
+	if query.order == OrderAscending {
+		direction = "ASC"
 	}
-	rows, err := stmt.Query(args...)
+	stmtString += " " + fmt.Sprintf("ORDER BY ID %s LIMIT $%d", direction, len(args))
+
+	rows, err := i.db.QueryContext(ctx, stmtString, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,19 +188,36 @@ func (i *PostgresDB) setup() error {
 		return err
 	}
 
-	if err = m.Up(); err != migrate.ErrNoChange {
+	// refuse to use a database migrated by a newer version, migrations are applied only to older ones
+	current, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return err
+	}
+	version := SchemaVersion{Current: uint64(current), Expected: postgresSchemaVersion(), Dirty: dirty}
+	if err := version.Check(); err != nil {
 		return err
 	}
 
-	return nil
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	// archives of new tenants are counted once
+	_, err = i.db.Exec(`INSERT INTO archive_stats (tenant, envelopes, size, oldest, newest)
+	SELECT $1, COUNT(*), COALESCE(SUM(OCTET_LENGTH(data)), 0), MIN(id), MAX(id) FROM envelopes WHERE tenant = $1 AND deleted_at IS NULL
+	ON CONFLICT (tenant) DO NOTHING`, i.tenant.Name)
+	return err
 }
 
 func (i *PostgresDB) Close() error {
+	if i.monitor != nil {
+		i.monitor.Stop()
+	}
 	return i.db.Close()
 }
 
 func (i *PostgresDB) GetEnvelope(key *DBKey) ([]byte, error) {
-	statement := `SELECT data FROM envelopes WHERE id = $1`
+	statement := `SELECT data FROM envelopes WHERE tenant = $1 AND id = $2 AND deleted_at IS NULL`
 
 	stmt, err := i.db.Prepare(statement)
 	if err != nil {
@@ -159,27 +227,151 @@ func (i *PostgresDB) GetEnvelope(key *DBKey) ([]byte, error) {
 
 	var envelope []byte
 
-	if err = stmt.QueryRow(key.Bytes()).Scan(&envelope); err != nil {
+	if err = stmt.QueryRow(i.tenant.Name, key.Bytes()).Scan(&envelope); err != nil {
 		return nil, err
 	}
 
 	return envelope, nil
 }
 
+// GetEnvelopes gets envelopes by their keys with a single query.
+func (i *PostgresDB) GetEnvelopes(keys []*DBKey) ([][]byte, error) {
+	ids := make([][]byte, len(keys))
+	positions := make(map[string]int, len(keys))
+	for n, key := range keys {
+		ids[n] = key.Bytes()
+		positions[string(key.Bytes())] = n
+	}
+
+	rows, err := i.db.Query(`SELECT id, data FROM envelopes WHERE tenant = $1 AND id = any($2) AND deleted_at IS NULL`, i.tenant.Name, pq.ByteaArray(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rst := make([][]byte, len(keys))
+	for rows.Next() {
+		var id, envelope []byte
+		if err := rows.Scan(&id, &envelope); err != nil {
+			return nil, err
+		}
+		if n, exist := positions[string(id)]; exist {
+			rst[n] = envelope
+		}
+	}
+	return rst, rows.Err()
+}
+
+// Prune removes envelopes older than time. Soft deleted envelopes are removed too,
+// but they are not counted in the archive stats.
 func (i *PostgresDB) Prune(t time.Time, batch int) (int, error) {
 	var zero types.Hash
 	var emptyTopic types.TopicType
 	kl := NewDBKey(0, emptyTopic, zero)
 	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
-	statement := "DELETE FROM envelopes WHERE id BETWEEN $1 AND $2"
 
-	stmt, err := i.db.Prepare(statement)
+	var removed, archived, size int64
+	err := i.withArchiveStats(func(tx *sql.Tx) (int64, int64, error) {
+		err := tx.QueryRow(`WITH removed AS (DELETE FROM envelopes WHERE tenant = $1 AND id BETWEEN $2 AND $3 RETURNING data, deleted_at)
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE deleted_at IS NULL), COALESCE(SUM(OCTET_LENGTH(data)) FILTER (WHERE deleted_at IS NULL), 0) FROM removed`,
+			i.tenant.Name, kl.Bytes(), ku.Bytes(),
+		).Scan(&removed, &archived, &size)
+		return -archived, -size, err
+	})
 	if err != nil {
 		return 0, err
 	}
-	defer stmt.Close()
+	return int(removed), nil
+}
+
+func (i *PostgresDB) PruneEstimate(t time.Time) (PruneStats, error) {
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(0, emptyTopic, zero)
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
+
+	var stats PruneStats
+	err := i.db.QueryRow(
+		"SELECT COUNT(*), COALESCE(SUM(OCTET_LENGTH(data)), 0) FROM envelopes WHERE tenant = $1 AND id BETWEEN $2 AND $3 AND deleted_at IS NULL",
+		i.tenant.Name, kl.Bytes(), ku.Bytes(),
+	).Scan(&stats.Rows, &stats.Size)
+	return stats, err
+}
+
+// SoftPrune sets deletion time of envelopes older than time.
+func (i *PostgresDB) SoftPrune(t, deletedAt time.Time, batch int) (int, error) {
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(0, emptyTopic, zero)
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
+	return i.moveArchived(-1,
+		"UPDATE envelopes SET deleted_at = $4 WHERE tenant = $1 AND id BETWEEN $2 AND $3 AND deleted_at IS NULL RETURNING data",
+		i.tenant.Name, kl.Bytes(), ku.Bytes(), deletedAt.Unix(),
+	)
+}
+
+// Restore clears deletion time of envelopes deleted at or after time.
+func (i *PostgresDB) Restore(since time.Time) (int, error) {
+	return i.moveArchived(1, "UPDATE envelopes SET deleted_at = NULL WHERE tenant = $1 AND deleted_at >= $2 RETURNING data", i.tenant.Name, since.Unix())
+}
+
+// Vacuum removes envelopes deleted before time.
+func (i *PostgresDB) Vacuum(t time.Time) (int, error) {
+	return i.execAffected("DELETE FROM envelopes WHERE tenant = $1 AND deleted_at < $2", i.tenant.Name, t.Unix())
+}
 
-	result, err := stmt.Exec(kl.Bytes(), ku.Bytes())
+// moveArchived executes a statement that returns data of envelopes which leave the archive
+// if the sign is negative or return to it otherwise.
+func (i *PostgresDB) moveArchived(sign int64, statement string, args ...interface{}) (int, error) {
+	var moved, size int64
+	err := i.withArchiveStats(func(tx *sql.Tx) (int64, int64, error) {
+		err := tx.QueryRow(
+			"WITH moved AS ("+statement+") SELECT COUNT(*), COALESCE(SUM(OCTET_LENGTH(data)), 0) FROM moved",
+			args...,
+		).Scan(&moved, &size)
+		return sign * moved, sign * size, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(moved), nil
+}
+
+// withArchiveStats runs f in a transaction and adds the number and the size of envelopes
+// returned by f to the archive stats. Bounds of the archive are recomputed.
+func (i *PostgresDB) withArchiveStats(f func(*sql.Tx) (int64, int64, error)) error {
+	return i.withTx(func(tx *sql.Tx) error {
+		envelopes, size, err := f(tx)
+		if err != nil || (envelopes == 0 && size == 0) {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE archive_stats SET
+		envelopes = GREATEST(envelopes + $2, 0),
+		size = GREATEST(size + $3, 0),
+		oldest = (SELECT MIN(id) FROM envelopes WHERE tenant = $1 AND deleted_at IS NULL),
+		newest = (SELECT MAX(id) FROM envelopes WHERE tenant = $1 AND deleted_at IS NULL)
+		WHERE tenant = $1`, i.tenant.Name, envelopes, size)
+		return err
+	})
+}
+
+// ArchiveStats returns cumulative stats of archived envelopes
+func (i *PostgresDB) ArchiveStats() (ArchiveStats, error) {
+	var (
+		stats          ArchiveStats
+		oldest, newest []byte
+	)
+	err := i.db.QueryRow("SELECT envelopes, size, oldest, newest FROM archive_stats WHERE tenant = $1", i.tenant.Name).
+		Scan(&stats.Envelopes, &stats.Size, &oldest, &newest)
+	if err == sql.ErrNoRows {
+		return stats, nil
+	}
+	stats.Oldest, stats.Newest = oldest, newest
+	return stats, err
+}
+
+func (i *PostgresDB) execAffected(statement string, args ...interface{}) (int, error) {
+	result, err := i.db.Exec(statement, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -204,32 +396,362 @@ func (i *PostgresDB) SaveEnvelope(env types.Envelope) error {
 		return errors.New("failed to encode envelope to bytes")
 	}
 
-	statement := "INSERT INTO envelopes (id, data, topic, bloom) VALUES ($1, $2, $3, B'"
+	statement := "INSERT INTO envelopes (tenant, id, data, topic, bloom) VALUES ($1, $2, $3, $4, B'"
 	statement += toBitString(env.Bloom())
-	statement += "'::bit(512)) ON CONFLICT (id) DO NOTHING;"
+	statement += "'::bit(512)) ON CONFLICT (tenant, id) DO NOTHING;"
+	err = i.withTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(
+			statement,
+			i.tenant.Name,
+			key.Bytes(),
+			rawEnvelope,
+			topicToByte(topic),
+		)
+		if err != nil {
+			return err
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil || inserted == 0 {
+			return err
+		}
+		// the envelope is rolled back if it doesn't fit into the quota
+		result, err = tx.Exec(`UPDATE archive_stats SET
+		envelopes = envelopes + 1,
+		size = size + $2,
+		oldest = LEAST(oldest, $3),
+		newest = GREATEST(newest, $3)
+		WHERE tenant = $1 AND ($4::BIGINT = 0 OR size + $2 <= $4::BIGINT)`, i.tenant.Name, len(rawEnvelope), key.Bytes(), int64(i.tenant.Quota))
+		if err != nil {
+			return err
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrTenantQuotaExceeded
+		}
+		return nil
+	})
+
+	if err == ErrTenantQuotaExceeded {
+		tenantQuotaExceededCounter.WithLabelValues(tenantLabel(i.tenant.Name)).Inc()
+		return err
+	}
+	if err != nil {
+		archivedErrorsCounter.Inc()
+		return err
+	}
+
+	archivedEnvelopesCounter.Inc()
+	tenantArchivedEnvelopesCounter.WithLabelValues(tenantLabel(i.tenant.Name)).Inc()
+	archivedEnvelopeSizeMeter.Observe(float64(whisper.EnvelopeHeaderLength + env.Size()))
+
+	return nil
+}
+
+// withTx runs f in a transaction, the transaction is committed if f succeeds.
+func (i *PostgresDB) withTx(f func(*sql.Tx) error) error {
+	tx, err := i.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveTopicStats increments counters of the bucket
+func (i *PostgresDB) SaveTopicStats(bucket uint32, stats []TopicStats) error {
+	statement := `INSERT INTO topic_stats (tenant, bucket, topic, envelopes, size) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (tenant, bucket, topic) DO UPDATE SET
+	envelopes = topic_stats.envelopes + EXCLUDED.envelopes,
+	size = topic_stats.size + EXCLUDED.size`
+
 	stmt, err := i.db.Prepare(statement)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(
-		key.Bytes(),
-		rawEnvelope,
-		topicToByte(topic),
-	)
+	for _, stat := range stats {
+		_, err = stmt.Exec(i.tenant.Name, int64(bucket), topicToByte(stat.Topic), int64(stat.Envelopes), int64(stat.Bytes))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TopicStats returns stats aggregated per topic starting from a given bucket
+func (i *PostgresDB) TopicStats(since uint32) ([]TopicStats, error) {
+	statement := `SELECT topic, SUM(envelopes), SUM(size) FROM topic_stats WHERE tenant = $1 AND bucket >= $2 GROUP BY topic`
 
+	rows, err := i.db.Query(statement, i.tenant.Name, int64(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []TopicStats
+	for rows.Next() {
+		var (
+			topic     []byte
+			envelopes int64
+			size      int64
+		)
+		if err := rows.Scan(&topic, &envelopes, &size); err != nil {
+			return nil, err
+		}
+		rst = append(rst, TopicStats{
+			Topic:     types.BytesToTopic(topic),
+			Envelopes: uint64(envelopes),
+			Bytes:     uint64(size),
+		})
+	}
+	return rst, rows.Err()
+}
+
+// PruneTopicStats removes buckets older than time
+func (i *PostgresDB) PruneTopicStats(t time.Time) error {
+	_, err := i.db.Exec(`DELETE FROM topic_stats WHERE tenant = $1 AND bucket < $2`, i.tenant.Name, int64(topicStatsBucketFor(t)))
+	return err
+}
+
+// SaveTopicIndex marks topics as present in the bucket
+func (i *PostgresDB) SaveTopicIndex(bucket uint32, topics []types.TopicType) error {
+	statement := `INSERT INTO topic_index (tenant, bucket, topic) VALUES ($1, $2, $3) ON CONFLICT (tenant, bucket, topic) DO NOTHING`
+
+	stmt, err := i.db.Prepare(statement)
 	if err != nil {
-		archivedErrorsCounter.Inc()
 		return err
 	}
+	defer stmt.Close()
 
-	archivedEnvelopesCounter.Inc()
-	archivedEnvelopeSizeMeter.Observe(float64(whisper.EnvelopeHeaderLength + env.Size()))
+	for _, topic := range topics {
+		_, err = stmt.Exec(i.tenant.Name, int64(bucket), topicToByte(topic))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// IndexedTopics returns distinct topics present in buckets from the first to the last one
+func (i *PostgresDB) IndexedTopics(from, to uint32) ([]types.TopicType, error) {
+	rows, err := i.db.Query(`SELECT DISTINCT topic FROM topic_index WHERE tenant = $1 AND bucket >= $2 AND bucket <= $3`, i.tenant.Name, int64(from), int64(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []types.TopicType
+	for rows.Next() {
+		var topic []byte
+		if err := rows.Scan(&topic); err != nil {
+			return nil, err
+		}
+		rst = append(rst, types.BytesToTopic(topic))
+	}
+	return rst, rows.Err()
+}
+
+// TopicIndexStart returns the first bucket in the topic index
+func (i *PostgresDB) TopicIndexStart() (uint32, error) {
+	var start sql.NullInt64
+	if err := i.db.QueryRow(`SELECT MIN(bucket) FROM topic_index WHERE tenant = $1`, i.tenant.Name).Scan(&start); err != nil {
+		return 0, err
+	}
+	return uint32(start.Int64), nil
+}
+
+// PruneTopicIndex removes buckets before a given one
+func (i *PostgresDB) PruneTopicIndex(bucket uint32) error {
+	_, err := i.db.Exec(`DELETE FROM topic_index WHERE tenant = $1 AND bucket < $2`, i.tenant.Name, int64(bucket))
+	return err
+}
+
+// SaveBucketBloom merges the bloom into the aggregated bloom of the bucket
+func (i *PostgresDB) SaveBucketBloom(bucket uint32, bloom []byte) error {
+	statement := `INSERT INTO bloom_index (tenant, bucket, bloom) VALUES ($1, $2, ('x' || $3)::bit(512))
+		ON CONFLICT (tenant, bucket) DO UPDATE SET bloom = bloom_index.bloom | EXCLUDED.bloom`
+	_, err := i.db.Exec(statement, i.tenant.Name, int64(bucket), hex.EncodeToString(bloom))
+	return err
+}
+
+// BucketBlooms returns aggregated blooms of buckets from the first to the last one
+func (i *PostgresDB) BucketBlooms(from, to uint32) (map[uint32][]byte, error) {
+	rows, err := i.db.Query(`SELECT bucket, bloom::text FROM bloom_index WHERE tenant = $1 AND bucket >= $2 AND bucket <= $3`, i.tenant.Name, int64(from), int64(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rst := map[uint32][]byte{}
+	for rows.Next() {
+		var (
+			bucket int64
+			bits   string
+		)
+		if err := rows.Scan(&bucket, &bits); err != nil {
+			return nil, err
+		}
+		bloom := make([]byte, types.BloomFilterSize)
+		for j := 0; j < len(bits) && j/8 < len(bloom); j++ {
+			if bits[j] == '1' {
+				bloom[j/8] |= 0x80 >> uint(j%8)
+			}
+		}
+		rst[uint32(bucket)] = bloom
+	}
+	return rst, rows.Err()
+}
+
+// BloomIndexStart returns the first bucket in the bloom index
+func (i *PostgresDB) BloomIndexStart() (uint32, error) {
+	var start sql.NullInt64
+	if err := i.db.QueryRow(`SELECT MIN(bucket) FROM bloom_index WHERE tenant = $1`, i.tenant.Name).Scan(&start); err != nil {
+		return 0, err
+	}
+	return uint32(start.Int64), nil
+}
+
+// PruneBloomIndex removes buckets before a given one
+func (i *PostgresDB) PruneBloomIndex(bucket uint32) error {
+	_, err := i.db.Exec(`DELETE FROM bloom_index WHERE tenant = $1 AND bucket < $2`, i.tenant.Name, int64(bucket))
+	return err
+}
+
+// SaveEnvelopeSources stores the peer and the size of every envelope
+func (i *PostgresDB) SaveEnvelopeSources(records []EnvelopeSourceRecord) error {
+	statement := `INSERT INTO envelope_sources (tenant, id, peer, size) VALUES ($1, $2, $3, $4) ON CONFLICT (tenant, id) DO NOTHING`
+
+	stmt, err := i.db.Prepare(statement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		_, err = stmt.Exec(i.tenant.Name, record.Key.Bytes(), record.Peer.Bytes(), int64(record.Size))
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// EnvelopeSources returns sources aggregated per peer of envelopes sent starting from a given time
+func (i *PostgresDB) EnvelopeSources(since time.Time) ([]EnvelopeSource, error) {
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	kl := NewDBKey(uint32(since.Unix()), emptyTopic, zero)
+	statement := `SELECT peer, COUNT(*), SUM(size) FROM envelope_sources WHERE tenant = $1 AND id >= $2 GROUP BY peer`
+
+	rows, err := i.db.Query(statement, i.tenant.Name, kl.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []EnvelopeSource
+	for rows.Next() {
+		var (
+			peer      []byte
+			envelopes int64
+			size      int64
+		)
+		if err := rows.Scan(&peer, &envelopes, &size); err != nil {
+			return nil, err
+		}
+		rst = append(rst, EnvelopeSource{
+			Peer:      types.BytesToHash(peer),
+			Envelopes: uint64(envelopes),
+			Bytes:     uint64(size),
+		})
+	}
+	return rst, rows.Err()
+}
+
+// PruneEnvelopeSources removes sources of envelopes older than time
+func (i *PostgresDB) PruneEnvelopeSources(t time.Time) error {
+	var zero types.Hash
+	var emptyTopic types.TopicType
+	ku := NewDBKey(uint32(t.Unix()), emptyTopic, zero)
+	_, err := i.db.Exec(`DELETE FROM envelope_sources WHERE tenant = $1 AND id < $2`, i.tenant.Name, ku.Bytes())
+	return err
+}
+
+// SaveCoverageGap stores a gap of the tenant, a gap with the same start is replaced
+func (i *PostgresDB) SaveCoverageGap(gap CoverageGap) error {
+	_, err := i.db.Exec(`INSERT INTO coverage_gaps (tenant, gap_from, gap_to, reason, healed_at) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (tenant, gap_from) DO UPDATE SET gap_to = EXCLUDED.gap_to, reason = EXCLUDED.reason, healed_at = EXCLUDED.healed_at`,
+		i.tenant.Name, int64(gap.From), int64(gap.To), gap.Reason, int64(gap.HealedAt))
+	return err
+}
+
+// CoverageGaps returns gaps of the tenant overlapping the range between two unix timestamps
+func (i *PostgresDB) CoverageGaps(from, to uint32) ([]CoverageGap, error) {
+	rows, err := i.db.Query(`SELECT gap_from, gap_to, reason, healed_at FROM coverage_gaps
+	WHERE tenant = $1 AND gap_from <= $3 AND gap_to >= $2 ORDER BY gap_from`, i.tenant.Name, int64(from), int64(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gaps []CoverageGap
+	for rows.Next() {
+		var (
+			gapFrom, gapTo, healedAt int64
+			reason                   string
+		)
+		if err := rows.Scan(&gapFrom, &gapTo, &reason, &healedAt); err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, CoverageGap{From: uint32(gapFrom), To: uint32(gapTo), Reason: reason, HealedAt: uint32(healedAt)})
+	}
+	return gaps, rows.Err()
+}
+
+// SaveCoverageHeartbeat stores the last unix time the tenant was known to archive envelopes
+func (i *PostgresDB) SaveCoverageHeartbeat(timestamp uint32) error {
+	_, err := i.db.Exec(`INSERT INTO coverage_heartbeats (tenant, at) VALUES ($1, $2)
+	ON CONFLICT (tenant) DO UPDATE SET at = EXCLUDED.at`, i.tenant.Name, int64(timestamp))
+	return err
+}
+
+// CoverageHeartbeat returns the last stored heartbeat of the tenant, zero if there is none
+func (i *PostgresDB) CoverageHeartbeat() (uint32, error) {
+	var at int64
+	err := i.db.QueryRow(`SELECT at FROM coverage_heartbeats WHERE tenant = $1`, i.tenant.Name).Scan(&at)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return uint32(at), err
+}
+
+// SchemaVersion returns the version of the last applied migration
+func (i *PostgresDB) SchemaVersion() (SchemaVersion, error) {
+	version := SchemaVersion{Expected: postgresSchemaVersion()}
+	var current int64
+	err := i.db.QueryRow(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &version.Dirty)
+	if err == sql.ErrNoRows {
+		return version, nil
+	}
+	version.Current = uint64(current)
+	return version, err
+}
+
+// PostgresHealth returns a health status of the connection pool.
+func (i *PostgresDB) PostgresHealth() []PostgresHealth {
+	if i.monitor == nil {
+		return []PostgresHealth{}
+	}
+	return []PostgresHealth{i.monitor.Health()}
+}
+
 func topicToByte(t types.TopicType) []byte {
 	return []byte{t[0], t[1], t[2], t[3]}
 }
@@ -0,0 +1,205 @@
+package mailserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// postgresHealthCheckPeriod is how often a healthy connection pool is checked.
+	postgresHealthCheckPeriod = 15 * time.Second
+	// postgresProbeTimeout is a statement timeout of the probe query.
+	postgresProbeTimeout = 5 * time.Second
+	// postgresReconnectMinBackoff and postgresReconnectMaxBackoff limit a delay between
+	// reconnection attempts, the delay is doubled after every failed attempt.
+	postgresReconnectMinBackoff = time.Second
+	postgresReconnectMaxBackoff = time.Minute
+	// postgresDegradedThreshold is how long the database must be unreachable
+	// before the mailserver is reported as degraded.
+	postgresDegradedThreshold = time.Minute
+	// postgresMaxIdleConns is the default of database/sql, it is restored after idle connections are dropped.
+	postgresMaxIdleConns = 2
+)
+
+// PostgresHealth is a health status of a connection pool.
+type PostgresHealth struct {
+	Healthy bool `json:"healthy"`
+	// Degraded is true if the database has been unreachable longer than the threshold.
+	Degraded bool `json:"degraded"`
+	// UnreachableSince is a unix timestamp of the first failed check, zero if healthy.
+	UnreachableSince int64  `json:"unreachableSince,omitempty"`
+	LastError        string `json:"lastError,omitempty"`
+	// Reconnects is a number of times connections were dropped after a failed check.
+	Reconnects uint64 `json:"reconnects"`
+}
+
+// postgresHealthReporter is implemented by databases backed by Postgres connection pools.
+type postgresHealthReporter interface {
+	PostgresHealth() []PostgresHealth
+}
+
+// postgresMonitor checks a connection pool periodically. After a failed check idle connections
+// are dropped, so that the next check opens new ones, and checks are retried with a jittered backoff.
+type postgresMonitor struct {
+	// check returns a name of the failed check and its error
+	check     func(ctx context.Context) (string, error)
+	reconnect func()
+
+	mu       sync.Mutex
+	health   PostgresHealth
+	failures int
+
+	period    time.Duration
+	threshold time.Duration
+	now       func() time.Time
+	jitter    func() float64
+	cancel    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newPostgresMonitor(db *sql.DB, tenant string) *postgresMonitor {
+	return &postgresMonitor{
+		check: func(ctx context.Context) (string, error) {
+			if err := db.PingContext(ctx); err != nil {
+				return "ping", err
+			}
+			if err := probePostgres(ctx, db, tenant); err != nil {
+				return "probe", err
+			}
+			return "", nil
+		},
+		reconnect: func() {
+			db.SetMaxIdleConns(0)
+			db.SetMaxIdleConns(postgresMaxIdleConns)
+		},
+		health:    PostgresHealth{Healthy: true},
+		period:    postgresHealthCheckPeriod,
+		threshold: postgresDegradedThreshold,
+		now:       time.Now,
+		jitter:    rand.Float64,
+	}
+}
+
+// probePostgres runs a query on a table of the mailserver with a statement timeout,
+// it fails if the database accepts connections but can't serve queries in time.
+func probePostgres(ctx context.Context, db *sql.DB, tenant string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", postgresProbeTimeout.Milliseconds())); err != nil {
+		return err
+	}
+	var envelopes int64
+	err = tx.QueryRowContext(ctx, "SELECT envelopes FROM archive_stats WHERE tenant = $1", tenant).Scan(&envelopes)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// Start starts checking the pool.
+func (m *postgresMonitor) Start() {
+	m.cancel = make(chan struct{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			t := time.NewTimer(m.Check())
+			select {
+			case <-t.C:
+			case <-m.cancel:
+				t.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the pool.
+func (m *postgresMonitor) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	close(m.cancel)
+	m.wg.Wait()
+	m.cancel = nil
+}
+
+// Check checks the pool once and returns a delay before the next check.
+func (m *postgresMonitor) Check() time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*postgresProbeTimeout)
+	name, err := m.check(ctx)
+	cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		if !m.health.Healthy {
+			log.Info("mailserver postgres database recovered", "reconnects", m.failures)
+		}
+		m.setDegraded(false)
+		m.health.Healthy = true
+		m.health.UnreachableSince = 0
+		m.health.LastError = ""
+		m.failures = 0
+		return m.period
+	}
+
+	postgresHealthCheckFailuresCounter.WithLabelValues(name).Inc()
+	now := m.now()
+	if m.health.Healthy {
+		log.Error("mailserver postgres health check failed", "check", name, "err", err)
+		m.health.Healthy = false
+		m.health.UnreachableSince = now.Unix()
+	}
+	m.health.LastError = err.Error()
+	if now.Sub(time.Unix(m.health.UnreachableSince, 0)) >= m.threshold {
+		m.setDegraded(true)
+	}
+	m.reconnect()
+	m.health.Reconnects++
+	postgresReconnectsCounter.Inc()
+	m.failures++
+	return reconnectBackoff(m.failures, m.jitter)
+}
+
+func (m *postgresMonitor) setDegraded(degraded bool) {
+	if m.health.Degraded == degraded {
+		return
+	}
+	m.health.Degraded = degraded
+	if degraded {
+		log.Error("mailserver postgres database is degraded", "unreachableSince", m.health.UnreachableSince, "err", m.health.LastError)
+		postgresDegradedGauge.Inc()
+	} else {
+		postgresDegradedGauge.Dec()
+	}
+}
+
+// Health returns the current health status of the pool.
+func (m *postgresMonitor) Health() PostgresHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.health
+}
+
+// reconnectBackoff returns a delay before the next reconnection attempt. The delay is randomized
+// within the upper half of the backoff, so that mailservers sharing a database don't reconnect at once.
+func reconnectBackoff(failures int, jitter func() float64) time.Duration {
+	backoff := postgresReconnectMaxBackoff
+	if failures < 16 {
+		backoff = postgresReconnectMinBackoff << uint(failures-1)
+		if backoff > postgresReconnectMaxBackoff {
+			backoff = postgresReconnectMaxBackoff
+		}
+	}
+	return backoff/2 + time.Duration(jitter()*float64(backoff/2))
+}
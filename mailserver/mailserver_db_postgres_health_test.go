@@ -0,0 +1,63 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	none := func() float64 { return 0 }
+	full := func() float64 { return 1 }
+	require.Equal(t, postgresReconnectMinBackoff/2, reconnectBackoff(1, none))
+	require.Equal(t, postgresReconnectMinBackoff, reconnectBackoff(1, full))
+	require.Equal(t, 4*postgresReconnectMinBackoff, reconnectBackoff(3, full))
+	require.Equal(t, postgresReconnectMaxBackoff, reconnectBackoff(10, full))
+	require.Equal(t, postgresReconnectMaxBackoff/2, reconnectBackoff(1000, none))
+}
+
+func TestPostgresMonitor(t *testing.T) {
+	var (
+		checkErr   error
+		reconnects int
+		now        = time.Unix(1590000000, 0)
+	)
+	m := &postgresMonitor{
+		check: func(context.Context) (string, error) {
+			return "ping", checkErr
+		},
+		reconnect: func() { reconnects++ },
+		health:    PostgresHealth{Healthy: true},
+		period:    postgresHealthCheckPeriod,
+		threshold: postgresDegradedThreshold,
+		now:       func() time.Time { return now },
+		jitter:    func() float64 { return 1 },
+	}
+
+	require.Equal(t, postgresHealthCheckPeriod, m.Check())
+	require.Equal(t, PostgresHealth{Healthy: true}, m.Health())
+
+	// failed checks are retried with a backoff
+	checkErr = errors.New("connection refused")
+	require.Equal(t, postgresReconnectMinBackoff, m.Check())
+	now = now.Add(time.Second)
+	require.Equal(t, 2*postgresReconnectMinBackoff, m.Check())
+	require.Equal(t, 2, reconnects)
+	require.Equal(t, PostgresHealth{
+		UnreachableSince: 1590000000,
+		LastError:        "connection refused",
+		Reconnects:       2,
+	}, m.Health())
+
+	// the database is degraded after the threshold
+	now = now.Add(postgresDegradedThreshold)
+	m.Check()
+	require.True(t, m.Health().Degraded)
+
+	checkErr = nil
+	require.Equal(t, postgresHealthCheckPeriod, m.Check())
+	require.Equal(t, PostgresHealth{Healthy: true, Reconnects: 3}, m.Health())
+}
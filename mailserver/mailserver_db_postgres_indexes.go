@@ -0,0 +1,134 @@
+package mailserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// postgresIndexBuildLock is a key of the advisory lock that serializes index builds
+	// of mailservers sharing the database.
+	postgresIndexBuildLock = 0x6d61696c // "mail"
+	// postgresIndexProgressPeriod is how often the progress of an index build is read.
+	postgresIndexProgressPeriod = 5 * time.Second
+)
+
+// postgresIndex is an index created concurrently in the background, because creating
+// it in a migration would block writes to the envelopes table until it's built.
+type postgresIndex struct {
+	name       string
+	definition string
+}
+
+// postgresBackgroundIndexes are built by the index builder. New indexes are added to the list
+// instead of migrations, indexes that are not needed anymore are dropped by migrations.
+var postgresBackgroundIndexes = []postgresIndex{
+	// selects envelopes of requested topics without scanning the whole time range
+	{name: "envelopes_topic_idx", definition: "ON envelopes (tenant, topic, id DESC) WHERE deleted_at IS NULL"},
+}
+
+// BackgroundIndexes returns names of indexes built in the background.
+func (i *PostgresDB) BackgroundIndexes() []string {
+	names := make([]string, len(postgresBackgroundIndexes))
+	for n, index := range postgresBackgroundIndexes {
+		names[n] = index.name
+	}
+	return names
+}
+
+// BuildIndex creates an index concurrently, so that envelopes are archived and served during the build.
+// A build interrupted by a restart leaves an invalid index that is dropped and built again.
+// Builds of mailservers sharing the database are serialized with an advisory lock.
+func (i *PostgresDB) BuildIndex(ctx context.Context, name string, progress func(float64)) error {
+	var index *postgresIndex
+	for n := range postgresBackgroundIndexes {
+		if postgresBackgroundIndexes[n].name == name {
+			index = &postgresBackgroundIndexes[n]
+		}
+	}
+	if index == nil {
+		return fmt.Errorf("unknown index %s", name)
+	}
+
+	// the advisory lock is held by the session, so all statements use the same connection
+	conn, err := i.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, postgresIndexBuildLock); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, postgresIndexBuildLock); err != nil {
+			log.Error("failed to release index build lock", "err", err)
+		}
+	}()
+
+	var valid bool
+	err = conn.QueryRowContext(ctx, `SELECT i.indisvalid FROM pg_class c JOIN pg_index i ON i.indexrelid = c.oid WHERE c.relname = $1`, name).Scan(&valid)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return err
+	case valid:
+		return nil
+	default:
+		log.Info("dropping interrupted index build", "name", name)
+		if _, err := conn.ExecContext(ctx, `DROP INDEX CONCURRENTLY IF EXISTS `+name); err != nil {
+			return err
+		}
+	}
+
+	var (
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		i.watchIndexProgress(name, progress, done)
+	}()
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE INDEX CONCURRENTLY IF NOT EXISTS `+name+` `+index.definition)
+	return err
+}
+
+// watchIndexProgress reports the progress of an index build until done is closed.
+// The progress is not reported by servers older than PostgreSQL 12.
+func (i *PostgresDB) watchIndexProgress(name string, progress func(float64), done <-chan struct{}) {
+	ticker := time.NewTicker(postgresIndexProgressPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		var blocksDone, blocksTotal, tuplesDone, tuplesTotal int64
+		err := i.db.QueryRow(`SELECT p.blocks_done, p.blocks_total, p.tuples_done, p.tuples_total
+		FROM pg_stat_progress_create_index p JOIN pg_class c ON c.oid = p.index_relid WHERE c.relname = $1`, name).Scan(&blocksDone, &blocksTotal, &tuplesDone, &tuplesTotal)
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			log.Debug("index build progress is not available", "name", name, "err", err)
+			return
+		}
+		// the table is scanned twice by a concurrent build, the progress is of the current scan
+		switch {
+		case blocksTotal > 0:
+			progress(float64(blocksDone) / float64(blocksTotal))
+		case tuplesTotal > 0:
+			progress(float64(tuplesDone) / float64(tuplesTotal))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package mailserver
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/status-im/status-go/params"
+)
+
+// Supported values of libpq sslmode.
+const (
+	PostgresSSLDisable    = "disable"
+	PostgresSSLRequire    = "require"
+	PostgresSSLVerifyCA   = "verify-ca"
+	PostgresSSLVerifyFull = "verify-full"
+)
+
+// ErrPostgresPlaintext is returned when a connection to the database is not encrypted
+// and plaintext connections are not allowed explicitly.
+var ErrPostgresPlaintext = errors.New("postgres connection is not encrypted, set AllowPlaintext to allow it")
+
+// PostgresTLSConfig configures security of connections to postgres.
+// Empty fields keep values from the connection URI.
+type PostgresTLSConfig struct {
+	// SSLMode is one of disable, require, verify-ca and verify-full.
+	SSLMode string
+	// SSLRootCert is a path to a CA bundle used to verify the server certificate.
+	SSLRootCert string
+	// SSLCert and SSLKey are paths to a client certificate and its private key.
+	SSLCert string
+	SSLKey  string
+	// AllowPlaintext allows to run with unencrypted connections.
+	AllowPlaintext bool
+}
+
+// Validate checks that the config is consistent and all files exist.
+func (c PostgresTLSConfig) Validate() error {
+	switch c.SSLMode {
+	case "", PostgresSSLRequire, PostgresSSLVerifyCA, PostgresSSLVerifyFull:
+	case PostgresSSLDisable:
+		if !c.AllowPlaintext {
+			return ErrPostgresPlaintext
+		}
+	default:
+		return fmt.Errorf("unsupported sslmode %q", c.SSLMode)
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		return errors.New("client certificate and key must be set together")
+	}
+	for _, path := range []string{c.SSLRootCert, c.SSLCert, c.SSLKey} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postgresConnString converts the URI to the key/value format and appends TLS settings.
+// Settings that appear later override values from the URI.
+func postgresConnString(uri string, c PostgresTLSConfig) (string, error) {
+	conn := uri
+	if strings.HasPrefix(uri, "postgres://") || strings.HasPrefix(uri, "postgresql://") {
+		var err error
+		conn, err = pq.ParseURL(uri)
+		if err != nil {
+			return "", err
+		}
+	}
+	opts := []struct{ key, value string }{
+		{"sslmode", c.SSLMode},
+		{"sslrootcert", c.SSLRootCert},
+		{"sslcert", c.SSLCert},
+		{"sslkey", c.SSLKey},
+	}
+	for _, opt := range opts {
+		if opt.value == "" {
+			continue
+		}
+		conn += fmt.Sprintf(" %s=%s", opt.key, quotePostgresValue(opt.value))
+	}
+	return strings.TrimSpace(conn), nil
+}
+
+func quotePostgresValue(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return "'" + value + "'"
+}
+
+// checkPostgresSSL verifies that the current connection is encrypted.
+func checkPostgresSSL(db *sql.DB, c PostgresTLSConfig) error {
+	var ssl bool
+	err := db.QueryRow("SELECT ssl FROM pg_stat_ssl WHERE pid = pg_backend_pid()").Scan(&ssl)
+	if err != nil {
+		return fmt.Errorf("failed to check connection security: %v", err)
+	}
+	if !ssl && !c.AllowPlaintext {
+		return ErrPostgresPlaintext
+	}
+	return nil
+}
+
+func postgresTLSConfig(cfg params.PGConfig) PostgresTLSConfig {
+	return PostgresTLSConfig{
+		SSLMode:        cfg.SSLMode,
+		SSLRootCert:    cfg.SSLRootCert,
+		SSLCert:        cfg.SSLCert,
+		SSLKey:         cfg.SSLKey,
+		AllowPlaintext: cfg.AllowPlaintext,
+	}
+}
@@ -0,0 +1,40 @@
+package mailserver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresTLSConfigValidate(t *testing.T) {
+	cert, err := ioutil.TempFile("", "mailserver-cert-")
+	require.NoError(t, err)
+	defer os.Remove(cert.Name())
+
+	require.NoError(t, PostgresTLSConfig{}.Validate())
+	require.NoError(t, PostgresTLSConfig{SSLMode: PostgresSSLVerifyFull, SSLRootCert: cert.Name()}.Validate())
+	require.NoError(t, PostgresTLSConfig{SSLMode: PostgresSSLDisable, AllowPlaintext: true}.Validate())
+	require.Equal(t, ErrPostgresPlaintext, PostgresTLSConfig{SSLMode: PostgresSSLDisable}.Validate())
+	require.Error(t, PostgresTLSConfig{SSLMode: "prefer"}.Validate())
+	require.Error(t, PostgresTLSConfig{SSLCert: cert.Name()}.Validate())
+	require.Error(t, PostgresTLSConfig{SSLRootCert: cert.Name() + "-missing"}.Validate())
+}
+
+func TestPostgresConnString(t *testing.T) {
+	conn, err := postgresConnString("postgres://whisper:pass@db:5432/whisper?sslmode=disable", PostgresTLSConfig{})
+	require.NoError(t, err)
+	require.Contains(t, conn, "sslmode=disable")
+
+	conn, err = postgresConnString("postgres://whisper:pass@db:5432/whisper?sslmode=disable", PostgresTLSConfig{
+		SSLMode:     PostgresSSLVerifyCA,
+		SSLRootCert: "/etc/it's/ca.pem",
+	})
+	require.NoError(t, err)
+	require.Contains(t, conn, "user=whisper sslmode='verify-ca' sslrootcert='/etc/it\\'s/ca.pem'")
+
+	conn, err = postgresConnString("host=db dbname=whisper", PostgresTLSConfig{SSLCert: "c.pem", SSLKey: "c.key"})
+	require.NoError(t, err)
+	require.Equal(t, "host=db dbname=whisper sslcert='c.pem' sslkey='c.key'", conn)
+}
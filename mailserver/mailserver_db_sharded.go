@@ -0,0 +1,750 @@
+package mailserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// shardRetryInterval is a period after which an unhealthy shard is queried again.
+const shardRetryInterval = 30 * time.Second
+
+var (
+	errNoShards         = errors.New("at least one shard is required")
+	errShardUnavailable = errors.New("mailserver db shard is unavailable")
+)
+
+// dbShard wraps a DB and tracks its health.
+type dbShard struct {
+	DB
+	id int
+
+	mu          sync.Mutex
+	healthy     bool
+	lastFailure time.Time
+	lastErr     error
+}
+
+func (s *dbShard) track(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		if !s.healthy {
+			log.Info("mailserver db shard recovered", "shard", s.id)
+		}
+		s.healthy = true
+		s.lastErr = nil
+		shardHealthGauge.WithLabelValues(strconv.Itoa(s.id)).Set(1)
+		return
+	}
+	if s.healthy {
+		log.Error("mailserver db shard failed", "shard", s.id, "err", err)
+	}
+	s.healthy = false
+	s.lastFailure = time.Now()
+	s.lastErr = err
+	shardHealthGauge.WithLabelValues(strconv.Itoa(s.id)).Set(0)
+}
+
+// available returns false if shard failed recently.
+func (s *dbShard) available() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.healthy || time.Since(s.lastFailure) > shardRetryInterval
+}
+
+// ShardHealth is a health status of a single shard.
+type ShardHealth struct {
+	ID      int
+	Healthy bool
+	Err     error
+}
+
+// ShardedDB distributes envelopes between multiple databases by a hash of the topic.
+// Envelopes from all shards are merged in a descending key order, the same
+// that is used by PostgresDB.
+type ShardedDB struct {
+	shards []*dbShard
+}
+
+// NewShardedDB returns a router over a list of databases.
+func NewShardedDB(dbs []DB) (*ShardedDB, error) {
+	if len(dbs) == 0 {
+		return nil, errNoShards
+	}
+	shards := make([]*dbShard, len(dbs))
+	for i, db := range dbs {
+		shards[i] = &dbShard{DB: db, id: i, healthy: true}
+		shardHealthGauge.WithLabelValues(strconv.Itoa(i)).Set(1)
+	}
+	return &ShardedDB{shards: shards}, nil
+}
+
+// NewShardedPostgresDB connects to every postgres database and uses it as a shard.
+// The tenant quota is enforced by every shard separately.
+func NewShardedPostgresDB(uris []string, tlsConfig PostgresTLSConfig, tenant TenantConfig) (*ShardedDB, error) {
+	dbs := make([]DB, 0, len(uris))
+	for i, uri := range uris {
+		db, err := NewPostgresDB(uri, tlsConfig, tenant)
+		if err != nil {
+			for _, opened := range dbs {
+				_ = opened.Close()
+			}
+			return nil, fmt.Errorf("shard %d: %v", i, err)
+		}
+		dbs = append(dbs, db)
+	}
+	return NewShardedDB(dbs)
+}
+
+func (db *ShardedDB) shardFor(topic types.TopicType) *dbShard {
+	h := fnv.New32a()
+	_, _ = h.Write(topic[:])
+	return db.shards[h.Sum32()%uint32(len(db.shards))]
+}
+
+// Health returns health status of every shard.
+func (db *ShardedDB) Health() []ShardHealth {
+	rst := make([]ShardHealth, len(db.shards))
+	for i, shard := range db.shards {
+		shard.mu.Lock()
+		rst[i] = ShardHealth{ID: shard.id, Healthy: shard.healthy, Err: shard.lastErr}
+		shard.mu.Unlock()
+	}
+	return rst
+}
+
+// Close closes all shards.
+func (db *ShardedDB) Close() error {
+	var rst error
+	for _, shard := range db.shards {
+		if err := shard.Close(); err != nil {
+			log.Error("failed to close mailserver db shard", "shard", shard.id, "err", err)
+			rst = err
+		}
+	}
+	return rst
+}
+
+// SaveEnvelope stores an envelope in a shard selected by topic.
+func (db *ShardedDB) SaveEnvelope(env types.Envelope) error {
+	shard := db.shardFor(env.Topic())
+	err := shard.SaveEnvelope(env)
+	shard.track(err)
+	return err
+}
+
+// GetEnvelope returns an envelope from a shard selected by the topic of the key.
+func (db *ShardedDB) GetEnvelope(key *DBKey) ([]byte, error) {
+	shard := db.shardFor(key.Topic())
+	rst, err := shard.GetEnvelope(key)
+	shard.track(err)
+	return rst, err
+}
+
+// GetEnvelopes groups keys by shards and gets envelopes from each shard with a single call.
+func (db *ShardedDB) GetEnvelopes(keys []*DBKey) ([][]byte, error) {
+	var (
+		shards    []*dbShard
+		byShard   = map[*dbShard][]*DBKey{}
+		positions = map[*dbShard][]int{}
+	)
+	for n, key := range keys {
+		shard := db.shardFor(key.Topic())
+		if _, exist := byShard[shard]; !exist {
+			shards = append(shards, shard)
+		}
+		byShard[shard] = append(byShard[shard], key)
+		positions[shard] = append(positions[shard], n)
+	}
+
+	rst := make([][]byte, len(keys))
+	for _, shard := range shards {
+		envelopes, err := shard.GetEnvelopes(byShard[shard])
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		for n, envelope := range envelopes {
+			rst[positions[shard][n]] = envelope
+		}
+	}
+	return rst, nil
+}
+
+// Prune removes envelopes older than time from all shards.
+func (db *ShardedDB) Prune(t time.Time, batch int) (int, error) {
+	return db.sum(func(shard *dbShard) (int, error) {
+		return shard.Prune(t, batch)
+	})
+}
+
+// SoftPrune marks envelopes older than time as deleted in all shards.
+func (db *ShardedDB) SoftPrune(t, deletedAt time.Time, batch int) (int, error) {
+	return db.sum(func(shard *dbShard) (int, error) {
+		return shard.SoftPrune(t, deletedAt, batch)
+	})
+}
+
+// Restore restores envelopes deleted at or after time in all shards.
+func (db *ShardedDB) Restore(since time.Time) (int, error) {
+	return db.sum(func(shard *dbShard) (int, error) {
+		return shard.Restore(since)
+	})
+}
+
+// Vacuum removes envelopes deleted before time from all shards.
+func (db *ShardedDB) Vacuum(t time.Time) (int, error) {
+	return db.sum(func(shard *dbShard) (int, error) {
+		return shard.Vacuum(t)
+	})
+}
+
+// sum calls f for every shard and sums the results. The last error is returned.
+func (db *ShardedDB) sum(f func(*dbShard) (int, error)) (int, error) {
+	var (
+		total int
+		rst   error
+	)
+	for _, shard := range db.shards {
+		n, err := f(shard)
+		shard.track(err)
+		if err != nil {
+			rst = err
+			continue
+		}
+		total += n
+	}
+	return total, rst
+}
+
+// PruneEstimate sums estimates of all shards.
+func (db *ShardedDB) PruneEstimate(t time.Time) (PruneStats, error) {
+	var (
+		stats PruneStats
+		rst   error
+	)
+	for _, shard := range db.shards {
+		shardStats, err := shard.PruneEstimate(t)
+		shard.track(err)
+		if err != nil {
+			rst = err
+			continue
+		}
+		stats.Rows += shardStats.Rows
+		stats.Size += shardStats.Size
+	}
+	return stats, rst
+}
+
+// BuildIterator builds iterators over shards that can have envelopes of the query and merges them.
+// If the query has topics, only their shards are queried, so a failed shard doesn't affect
+// requests for topics stored in other shards. A query that needs a shard that failed recently
+// returns an error rather than an incomplete history, the client retries it later.
+func (db *ShardedDB) BuildIterator(ctx context.Context, query CursorQuery) (Iterator, error) {
+	shards := db.shardsForTopics(query.topics)
+	iterators := make([]Iterator, 0, len(shards))
+	release := func() {
+		for _, i := range iterators {
+			_ = i.Release()
+		}
+	}
+	for _, shard := range shards {
+		if !shard.available() {
+			release()
+			return nil, fmt.Errorf("%v: shard %d", errShardUnavailable, shard.id)
+		}
+		i, err := shard.BuildIterator(ctx, query)
+		// an expired query is not a failure of the shard
+		if ctx.Err() == nil {
+			shard.track(err)
+		}
+		if err != nil {
+			release()
+			return nil, err
+		}
+		iterators = append(iterators, i)
+	}
+	return newMergedIterator(iterators, query.order == OrderAscending), nil
+}
+
+// shardsForTopics returns shards that store given topics, all shards if there are no topics.
+func (db *ShardedDB) shardsForTopics(topics [][]byte) []*dbShard {
+	if len(topics) == 0 {
+		return db.shards
+	}
+	selected := map[*dbShard]bool{}
+	for _, topic := range topics {
+		selected[db.shardFor(types.BytesToTopic(topic))] = true
+	}
+	rst := make([]*dbShard, 0, len(selected))
+	for _, shard := range db.shards {
+		if selected[shard] {
+			rst = append(rst, shard)
+		}
+	}
+	return rst
+}
+
+// SaveTopicStats stores stats of every topic in the same shard as its envelopes.
+func (db *ShardedDB) SaveTopicStats(bucket uint32, stats []TopicStats) error {
+	byShard := map[*dbShard][]TopicStats{}
+	for _, stat := range stats {
+		shard := db.shardFor(stat.Topic)
+		byShard[shard] = append(byShard[shard], stat)
+	}
+	for shard, stats := range byShard {
+		err := shard.SaveTopicStats(bucket, stats)
+		shard.track(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TopicStats collects stats from all shards. Topics don't overlap between shards.
+func (db *ShardedDB) TopicStats(since uint32) ([]TopicStats, error) {
+	var rst []TopicStats
+	for _, shard := range db.shards {
+		stats, err := shard.TopicStats(since)
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, stats...)
+	}
+	return rst, nil
+}
+
+// PruneTopicStats removes old stats from all shards.
+func (db *ShardedDB) PruneTopicStats(t time.Time) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.PruneTopicStats(t)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// SaveTopicIndex stores topics in the same shard as their envelopes.
+func (db *ShardedDB) SaveTopicIndex(bucket uint32, topics []types.TopicType) error {
+	byShard := map[*dbShard][]types.TopicType{}
+	for _, topic := range topics {
+		shard := db.shardFor(topic)
+		byShard[shard] = append(byShard[shard], topic)
+	}
+	for shard, topics := range byShard {
+		err := shard.SaveTopicIndex(bucket, topics)
+		shard.track(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexedTopics collects topics from all shards. Topics don't overlap between shards.
+func (db *ShardedDB) IndexedTopics(from, to uint32) ([]types.TopicType, error) {
+	var rst []types.TopicType
+	for _, shard := range db.shards {
+		topics, err := shard.IndexedTopics(from, to)
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, topics...)
+	}
+	return rst, nil
+}
+
+// TopicIndexStart returns the latest of the first buckets of shards, so that
+// the index is complete in every shard starting from the returned bucket.
+func (db *ShardedDB) TopicIndexStart() (uint32, error) {
+	var rst uint32
+	for _, shard := range db.shards {
+		start, err := shard.TopicIndexStart()
+		shard.track(err)
+		if err != nil {
+			return 0, err
+		}
+		if start > rst {
+			rst = start
+		}
+	}
+	return rst, nil
+}
+
+// PruneTopicIndex removes old buckets from all shards.
+func (db *ShardedDB) PruneTopicIndex(bucket uint32) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.PruneTopicIndex(bucket)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// SaveBucketBloom stores the bloom in all shards, so that every shard
+// can answer for the bucket on its own.
+func (db *ShardedDB) SaveBucketBloom(bucket uint32, bloom []byte) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.SaveBucketBloom(bucket, bloom)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// BucketBlooms merges blooms from all shards.
+func (db *ShardedDB) BucketBlooms(from, to uint32) (map[uint32][]byte, error) {
+	rst := map[uint32][]byte{}
+	for _, shard := range db.shards {
+		blooms, err := shard.BucketBlooms(from, to)
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		for bucket, bloom := range blooms {
+			merged, ok := rst[bucket]
+			if !ok {
+				rst[bucket] = bloom
+				continue
+			}
+			for j := 0; j < len(bloom) && j < len(merged); j++ {
+				merged[j] |= bloom[j]
+			}
+		}
+	}
+	return rst, nil
+}
+
+// BloomIndexStart returns the latest of the first buckets of shards, so that
+// the index is complete in every shard starting from the returned bucket.
+func (db *ShardedDB) BloomIndexStart() (uint32, error) {
+	var rst uint32
+	for _, shard := range db.shards {
+		start, err := shard.BloomIndexStart()
+		shard.track(err)
+		if err != nil {
+			return 0, err
+		}
+		if start > rst {
+			rst = start
+		}
+	}
+	return rst, nil
+}
+
+// PruneBloomIndex removes old buckets from all shards.
+func (db *ShardedDB) PruneBloomIndex(bucket uint32) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.PruneBloomIndex(bucket)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// SaveEnvelopeSources stores sources in the same shard as their envelopes.
+func (db *ShardedDB) SaveEnvelopeSources(records []EnvelopeSourceRecord) error {
+	byShard := map[*dbShard][]EnvelopeSourceRecord{}
+	for _, record := range records {
+		shard := db.shardFor(record.Key.Topic())
+		byShard[shard] = append(byShard[shard], record)
+	}
+	for shard, records := range byShard {
+		err := shard.SaveEnvelopeSources(records)
+		shard.track(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnvelopeSources collects sources from all shards and merges sources of the same peer.
+func (db *ShardedDB) EnvelopeSources(since time.Time) ([]EnvelopeSource, error) {
+	var rst []EnvelopeSource
+	for _, shard := range db.shards {
+		sources, err := shard.EnvelopeSources(since)
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, sources...)
+	}
+	return aggregateEnvelopeSources(rst), nil
+}
+
+// PruneEnvelopeSources removes old sources from all shards.
+func (db *ShardedDB) PruneEnvelopeSources(t time.Time) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.PruneEnvelopeSources(t)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// SaveCoverageGap stores the gap in all shards, the gap applies to envelopes of every shard.
+func (db *ShardedDB) SaveCoverageGap(gap CoverageGap) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.SaveCoverageGap(gap)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// CoverageGaps collects gaps from all shards, gaps stored in several shards are returned once.
+func (db *ShardedDB) CoverageGaps(from, to uint32) ([]CoverageGap, error) {
+	var rst []CoverageGap
+	for _, shard := range db.shards {
+		gaps, err := shard.CoverageGaps(from, to)
+		shard.track(err)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, gaps...)
+	}
+	return mergeCoverageGaps(rst), nil
+}
+
+// SaveCoverageHeartbeat stores the heartbeat in all shards.
+func (db *ShardedDB) SaveCoverageHeartbeat(timestamp uint32) error {
+	var rst error
+	for _, shard := range db.shards {
+		err := shard.SaveCoverageHeartbeat(timestamp)
+		shard.track(err)
+		if err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
+
+// CoverageHeartbeat returns the latest heartbeat of all shards.
+func (db *ShardedDB) CoverageHeartbeat() (uint32, error) {
+	var rst uint32
+	for _, shard := range db.shards {
+		heartbeat, err := shard.CoverageHeartbeat()
+		shard.track(err)
+		if err != nil {
+			return 0, err
+		}
+		if heartbeat > rst {
+			rst = heartbeat
+		}
+	}
+	return rst, nil
+}
+
+// PostgresHealth returns health statuses of connection pools of postgres shards.
+func (db *ShardedDB) PostgresHealth() []PostgresHealth {
+	rst := []PostgresHealth{}
+	for _, shard := range db.shards {
+		if reporter, ok := shard.DB.(postgresHealthReporter); ok {
+			rst = append(rst, reporter.PostgresHealth()...)
+		}
+	}
+	return rst
+}
+
+// SchemaVersion returns the newest schema version of all shards.
+func (db *ShardedDB) SchemaVersion() (SchemaVersion, error) {
+	var rst SchemaVersion
+	for i, shard := range db.shards {
+		version, err := shard.SchemaVersion()
+		shard.track(err)
+		if err != nil {
+			return SchemaVersion{}, err
+		}
+		if i == 0 {
+			rst = version
+			continue
+		}
+		rst.merge(version)
+	}
+	return rst, nil
+}
+
+// ArchiveStats sums stats of all shards.
+func (db *ShardedDB) ArchiveStats() (ArchiveStats, error) {
+	var rst ArchiveStats
+	for _, shard := range db.shards {
+		stats, err := shard.ArchiveStats()
+		shard.track(err)
+		if err != nil {
+			return ArchiveStats{}, err
+		}
+		rst.merge(stats)
+	}
+	return rst, nil
+}
+
+// BackgroundIndexes returns names of indexes built in the background by shards.
+func (db *ShardedDB) BackgroundIndexes() []string {
+	var (
+		rst  []string
+		seen = map[string]bool{}
+	)
+	for _, shard := range db.shards {
+		indexer, ok := shard.DB.(backgroundIndexer)
+		if !ok {
+			continue
+		}
+		for _, name := range indexer.BackgroundIndexes() {
+			if !seen[name] {
+				seen[name] = true
+				rst = append(rst, name)
+			}
+		}
+	}
+	return rst
+}
+
+// BuildIndex builds an index in every shard that has it, one shard at a time.
+func (db *ShardedDB) BuildIndex(ctx context.Context, name string, progress func(float64)) error {
+	var indexers []backgroundIndexer
+	for _, shard := range db.shards {
+		if indexer, ok := shard.DB.(backgroundIndexer); ok {
+			indexers = append(indexers, indexer)
+		}
+	}
+	for n, indexer := range indexers {
+		err := indexer.BuildIndex(ctx, name, func(p float64) {
+			progress((float64(n) + p) / float64(len(indexers)))
+		})
+		if err != nil {
+			return fmt.Errorf("shard %d: %v", n, err)
+		}
+	}
+	return nil
+}
+
+// mergedIterator merges iterators that return keys in a descending order,
+// or in an ascending order if ascending is set.
+type mergedIterator struct {
+	iterators []Iterator
+	ascending bool
+	// keys holds the current key of each iterator, nil if the iterator is exhausted
+	keys    [][]byte
+	current int
+	started bool
+	err     error
+}
+
+func newMergedIterator(iterators []Iterator, ascending bool) *mergedIterator {
+	return &mergedIterator{
+		iterators: iterators,
+		ascending: ascending,
+		keys:      make([][]byte, len(iterators)),
+		current:   -1,
+	}
+}
+
+func (i *mergedIterator) advance(idx int) {
+	i.keys[idx] = nil
+	if !i.iterators[idx].Next() {
+		return
+	}
+	key, err := i.iterators[idx].DBKey()
+	if err != nil {
+		i.err = err
+		return
+	}
+	i.keys[idx] = append([]byte{}, key.Bytes()...)
+}
+
+func (i *mergedIterator) Next() bool {
+	if !i.started {
+		for idx := range i.iterators {
+			i.advance(idx)
+		}
+		i.started = true
+	} else if i.current >= 0 {
+		i.advance(i.current)
+	}
+	if i.err != nil {
+		return false
+	}
+	i.current = -1
+	for idx, key := range i.keys {
+		if key == nil {
+			continue
+		}
+		if i.current == -1 {
+			i.current = idx
+			continue
+		}
+		cmp := bytes.Compare(key, i.keys[i.current])
+		if (i.ascending && cmp < 0) || (!i.ascending && cmp > 0) {
+			i.current = idx
+		}
+	}
+	return i.current >= 0
+}
+
+func (i *mergedIterator) DBKey() (*DBKey, error) {
+	if i.current < 0 {
+		return nil, errors.New("iterator is not positioned")
+	}
+	return &DBKey{raw: i.keys[i.current]}, nil
+}
+
+func (i *mergedIterator) GetEnvelope(bloom []byte) ([]byte, error) {
+	if i.current < 0 {
+		return nil, errors.New("iterator is not positioned")
+	}
+	return i.iterators[i.current].GetEnvelope(bloom)
+}
+
+func (i *mergedIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+	for _, iter := range i.iterators {
+		if err := iter.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *mergedIterator) Release() error {
+	var rst error
+	for _, iter := range i.iterators {
+		if err := iter.Release(); err != nil {
+			rst = err
+		}
+	}
+	return rst
+}
@@ -0,0 +1,253 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// sliceIterator iterates over keys in the given order.
+type sliceIterator struct {
+	keys [][]byte
+	pos  int
+}
+
+func (i *sliceIterator) Next() bool {
+	i.pos++
+	return i.pos <= len(i.keys)
+}
+
+func (i *sliceIterator) DBKey() (*DBKey, error) {
+	return &DBKey{raw: i.keys[i.pos-1]}, nil
+}
+
+func (i *sliceIterator) Release() error { return nil }
+
+func (i *sliceIterator) Error() error { return nil }
+
+func (i *sliceIterator) GetEnvelope(bloom []byte) ([]byte, error) {
+	return i.keys[i.pos-1], nil
+}
+
+// failingDB fails every operation.
+type failingDB struct {
+	DB
+}
+
+func (failingDB) BuildIterator(context.Context, CursorQuery) (Iterator, error) {
+	return nil, errors.New("failed")
+}
+
+func (failingDB) Close() error { return nil }
+
+func TestMergedIteratorDescendingOrder(t *testing.T) {
+	iter := newMergedIterator([]Iterator{
+		&sliceIterator{keys: [][]byte{{9}, {5}, {1}}},
+		&sliceIterator{},
+		&sliceIterator{keys: [][]byte{{8}, {6}, {2}}},
+	}, false)
+	var keys []byte
+	for iter.Next() {
+		key, err := iter.DBKey()
+		require.NoError(t, err)
+		value, err := iter.GetEnvelope(nil)
+		require.NoError(t, err)
+		require.Equal(t, key.Bytes(), value)
+		keys = append(keys, key.Bytes()[0])
+	}
+	require.NoError(t, iter.Error())
+	require.NoError(t, iter.Release())
+	require.Equal(t, []byte{9, 8, 6, 5, 2, 1}, keys)
+}
+
+func TestMergedIteratorAscendingOrder(t *testing.T) {
+	iter := newMergedIterator([]Iterator{
+		&sliceIterator{keys: [][]byte{{1}, {5}, {9}}},
+		&sliceIterator{keys: [][]byte{{2}, {6}, {8}}},
+	}, true)
+	var keys []byte
+	for iter.Next() {
+		key, err := iter.DBKey()
+		require.NoError(t, err)
+		keys = append(keys, key.Bytes()[0])
+	}
+	require.NoError(t, iter.Error())
+	require.Equal(t, []byte{1, 2, 5, 6, 8, 9}, keys)
+}
+
+func TestShardedDBRoutesByTopic(t *testing.T) {
+	shards := []DB{setupTopicStatsDB(t), setupTopicStatsDB(t)}
+	db, err := NewShardedDB(shards)
+	require.NoError(t, err)
+	defer db.Close()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	wrapped := gethbridge.NewWhisperEnvelope(env)
+	require.NoError(t, db.SaveEnvelope(wrapped))
+
+	key := NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash())
+	shard := db.shardFor(wrapped.Topic())
+	_, err = shard.GetEnvelope(key)
+	require.NoError(t, err)
+	for _, other := range db.shards {
+		if other != shard {
+			_, err = other.GetEnvelope(key)
+			require.Error(t, err)
+		}
+	}
+	_, err = db.GetEnvelope(key)
+	require.NoError(t, err)
+
+	topics := []types.TopicType{{1}, {2}, {3}, {4}}
+	var stats []TopicStats
+	for _, topic := range topics {
+		stats = append(stats, TopicStats{Topic: topic, Envelopes: 1, Bytes: 1})
+	}
+	require.NoError(t, db.SaveTopicStats(1, stats))
+	rst, err := db.TopicStats(0)
+	require.NoError(t, err)
+	require.Len(t, rst, len(topics))
+}
+
+func TestShardedDBGetEnvelopes(t *testing.T) {
+	shards := []DB{setupTopicStatsDB(t), setupTopicStatsDB(t)}
+	db, err := NewShardedDB(shards)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var keys []*DBKey
+	for i := 0; i < 4; i++ {
+		env, err := generateEnvelope(time.Now())
+		require.NoError(t, err)
+		wrapped := gethbridge.NewWhisperEnvelope(env)
+		require.NoError(t, db.SaveEnvelope(wrapped))
+		keys = append(keys, NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash()))
+	}
+	keys = append(keys, NewDBKey(uint32(time.Now().Unix()), types.TopicType{}, types.Hash{0xff}))
+
+	envelopes, err := db.GetEnvelopes(keys)
+	require.NoError(t, err)
+	require.Len(t, envelopes, len(keys))
+	for i, key := range keys[:4] {
+		expected, err := db.GetEnvelope(key)
+		require.NoError(t, err)
+		require.Equal(t, expected, envelopes[i])
+	}
+	require.Nil(t, envelopes[4])
+}
+
+// topicInShard returns a topic that is stored in the given shard.
+func topicInShard(db *ShardedDB, shard int) []byte {
+	for i := 0; ; i++ {
+		topic := types.TopicType{byte(i)}
+		if db.shardFor(topic) == db.shards[shard] {
+			return topic[:]
+		}
+	}
+}
+
+func TestShardedDBUnhealthyShard(t *testing.T) {
+	db, err := NewShardedDB([]DB{failingDB{}, setupTopicStatsDB(t)})
+	require.NoError(t, err)
+
+	_, err = db.BuildIterator(context.Background(), CursorQuery{})
+	require.Error(t, err)
+
+	health := db.Health()
+	require.False(t, health[0].Healthy)
+	require.Error(t, health[0].Err)
+	require.True(t, health[1].Healthy)
+	require.False(t, db.shards[0].available())
+
+	// history is not returned without envelopes of the unhealthy shard
+	_, err = db.BuildIterator(context.Background(), CursorQuery{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errShardUnavailable.Error())
+	_, err = db.BuildIterator(context.Background(), CursorQuery{topics: [][]byte{topicInShard(db, 0), topicInShard(db, 1)}})
+	require.Contains(t, err.Error(), errShardUnavailable.Error())
+
+	// topics of healthy shards are served
+	iter, err := db.BuildIterator(context.Background(), CursorQuery{topics: [][]byte{topicInShard(db, 1)}})
+	require.NoError(t, err)
+	require.NoError(t, iter.Release())
+
+	_, err = NewShardedDB(nil)
+	require.Equal(t, errNoShards, err)
+}
+
+func TestDeliverMailFromUnavailableShard(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	db, err := NewShardedDB([]DB{failingDB{}, setupTopicStatsDB(t)})
+	require.NoError(t, err)
+	server.ms.db = db
+
+	service := &digestTestService{}
+	server.ms.service = service
+
+	now := time.Now()
+	req := MessagesRequestPayload{
+		Lower: uint32(now.Add(-time.Minute).Unix()),
+		Upper: uint32(now.Add(time.Minute).Unix()),
+		Bloom: types.MakeFullNodeBloom(),
+		Limit: 10,
+		Batch: true,
+	}
+	// the peer is told right away that the history is unavailable
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, req)
+	require.Error(t, service.lastResponse(t).Error)
+
+	// topics of the healthy shard are served while the other shard is unavailable
+	req.Topics = [][]byte{topicInShard(db, 1)}
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{3}, req)
+	require.NoError(t, service.lastResponse(t).Error)
+	require.False(t, db.shards[0].available())
+}
+
+func TestShardedDBSoftPrune(t *testing.T) {
+	shards := []DB{setupTopicStatsDB(t), setupTopicStatsDB(t)}
+	db, err := NewShardedDB(shards)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	var keys []*DBKey
+	for i := 0; i < 4; i++ {
+		env, err := generateEnvelope(now.Add(-10 * time.Second))
+		require.NoError(t, err)
+		wrapped := gethbridge.NewWhisperEnvelope(env)
+		require.NoError(t, db.SaveEnvelope(wrapped))
+		keys = append(keys, NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash()))
+	}
+	stats, err := db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), stats.Envelopes)
+
+	deleted, err := db.SoftPrune(now, now, 1)
+	require.NoError(t, err)
+	require.Equal(t, 4, deleted)
+	stats, err = db.ArchiveStats()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), stats.Envelopes)
+	require.Empty(t, stats.Oldest)
+	for _, key := range keys {
+		_, err = db.GetEnvelope(key)
+		require.Error(t, err)
+	}
+
+	restored, err := db.Restore(now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 4, restored)
+	envelopes, err := db.GetEnvelopes(keys)
+	require.NoError(t, err)
+	for _, envelope := range envelopes {
+		require.NotNil(t, envelope)
+	}
+}
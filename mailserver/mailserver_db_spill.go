@@ -0,0 +1,222 @@
+package mailserver
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	// spillDrainPeriod is how often queued envelopes are moved to the primary database.
+	spillDrainPeriod = 10 * time.Second
+	// spillDrainBatch is a maximum number of envelopes moved in a single step of the drain.
+	spillDrainBatch = 1000
+)
+
+// SpillDB saves envelopes to a local LevelDB queue when the primary database is unavailable.
+// Queued envelopes are moved back to the primary in the order of their keys once it recovers.
+// While the queue is not empty, new envelopes are queued too, so they reach the primary in order.
+// Queued envelopes are not returned by queries until they are moved.
+type SpillDB struct {
+	DB
+	spill *leveldb.DB
+	// drained is called with the timestamp of every envelope moved to the primary
+	drained func(timestamp uint32)
+
+	// mu protects pending and serializes queueing with removal of moved envelopes
+	mu      sync.Mutex
+	pending int
+	// paused is 1 if all envelopes are queued and the queue is not moved
+	paused uint32
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSpillDB opens the queue in a given directory and starts moving queued envelopes to the primary.
+// drained is optional, it is called for every envelope moved to the primary.
+func NewSpillDB(primary DB, dir string, drained func(timestamp uint32)) (*SpillDB, error) {
+	spill, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	db := &SpillDB{DB: primary, spill: spill, drained: drained, period: spillDrainPeriod}
+	iter := spill.NewIterator(nil, nil)
+	for iter.Next() {
+		db.pending++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		_ = spill.Close()
+		return nil, err
+	}
+	spillQueueGauge.Set(float64(db.pending))
+	if db.pending > 0 {
+		log.Info("mailserver spill queue is not empty", "envelopes", db.pending)
+	}
+	db.start()
+	return db, nil
+}
+
+func (db *SpillDB) start() {
+	db.cancel = make(chan struct{})
+	db.wg.Add(1)
+	go func() {
+		defer db.wg.Done()
+		t := time.NewTicker(db.period)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				if _, err := db.drain(); err != nil {
+					log.Warn("failed to move queued envelopes to the database", "err", err)
+				}
+			case <-db.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the drain and closes both the queue and the primary database.
+func (db *SpillDB) Close() error {
+	close(db.cancel)
+	db.wg.Wait()
+	spillErr := db.spill.Close()
+	if err := db.DB.Close(); err != nil {
+		return err
+	}
+	return spillErr
+}
+
+// Pending returns the number of queued envelopes.
+func (db *SpillDB) Pending() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.pending
+}
+
+// Pause makes all envelopes queued and stops moving them to the primary database
+// until the queue is resumed.
+func (db *SpillDB) Pause(paused bool) {
+	var value uint32
+	if paused {
+		value = 1
+	}
+	atomic.StoreUint32(&db.paused, value)
+}
+
+func (db *SpillDB) isPaused() bool {
+	return atomic.LoadUint32(&db.paused) == 1
+}
+
+// SaveEnvelope saves an envelope to the primary database, the envelope is queued
+// if the primary fails, there are envelopes waiting to be moved or the queue is paused.
+func (db *SpillDB) SaveEnvelope(env types.Envelope) error {
+	if db.Pending() == 0 && !db.isPaused() {
+		err := db.DB.SaveEnvelope(env)
+		if err == nil || err == ErrTenantQuotaExceeded {
+			return err
+		}
+		log.Error("failed to save envelope, queueing it locally", "hash", env.Hash().String(), "err", err)
+	}
+	return db.queue(env)
+}
+
+func (db *SpillDB) queue(env types.Envelope) error {
+	key := NewDBKey(env.Expiry()-env.TTL(), env.Topic(), env.Hash())
+	rawEnvelope, err := rlp.EncodeToBytes(env.Unwrap())
+	if err != nil {
+		return err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	exists, err := db.spill.Has(key.Bytes(), nil)
+	if err != nil || exists {
+		return err
+	}
+	if err := db.spill.Put(key.Bytes(), rawEnvelope, nil); err != nil {
+		return err
+	}
+	db.pending++
+	spilledEnvelopesCounter.Inc()
+	spillQueueGauge.Set(float64(db.pending))
+	return nil
+}
+
+// drain moves queued envelopes to the primary database in the order of their keys
+// until the queue is empty, the primary fails or the queue is paused. It returns the number
+// of moved envelopes.
+func (db *SpillDB) drain() (int, error) {
+	moved := 0
+	for !db.isPaused() {
+		keys, values, err := db.nextBatch()
+		if err != nil || len(keys) == 0 {
+			return moved, err
+		}
+		done := 0
+		for i := range keys {
+			var envelope whisper.Envelope
+			if err := rlp.DecodeBytes(values[i], &envelope); err != nil {
+				log.Error("dropping corrupted queued envelope", "key", types.EncodeHex(keys[i]), "err", err)
+			} else if err := db.DB.SaveEnvelope(gethbridge.NewWhisperEnvelope(&envelope)); err == ErrTenantQuotaExceeded {
+				log.Error("dropping queued envelope over the tenant quota", "key", types.EncodeHex(keys[i]))
+			} else if err != nil {
+				if removeErr := db.remove(keys[:done]); removeErr != nil {
+					return moved, removeErr
+				}
+				return moved, err
+			} else {
+				moved++
+				if db.drained != nil {
+					db.drained(envelope.Expiry - envelope.TTL)
+				}
+			}
+			done++
+		}
+		if err := db.remove(keys); err != nil {
+			return moved, err
+		}
+	}
+	return moved, nil
+}
+
+// nextBatch returns the first queued envelopes in the order of their keys.
+func (db *SpillDB) nextBatch() (keys, values [][]byte, err error) {
+	iter := db.spill.NewIterator(nil, nil)
+	defer iter.Release()
+	for len(keys) < spillDrainBatch && iter.Next() {
+		keys = append(keys, append([]byte{}, iter.Key()...))
+		values = append(values, append([]byte{}, iter.Value()...))
+	}
+	return keys, values, iter.Error()
+}
+
+// remove deletes moved envelopes from the queue.
+func (db *SpillDB) remove(keys [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var batch leveldb.Batch
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if err := db.spill.Write(&batch, nil); err != nil {
+		return err
+	}
+	db.pending -= len(keys)
+	spillQueueGauge.Set(float64(db.pending))
+	return nil
+}
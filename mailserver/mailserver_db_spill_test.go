@@ -0,0 +1,224 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// unavailableDB fails to save envelopes while down is set.
+type unavailableDB struct {
+	*LevelDB
+	down  bool
+	full  bool
+	saved []*DBKey
+}
+
+func (db *unavailableDB) SaveEnvelope(env types.Envelope) error {
+	if db.down {
+		return errors.New("connection refused")
+	}
+	if db.full {
+		return ErrTenantQuotaExceeded
+	}
+	db.saved = append(db.saved, NewDBKey(env.Expiry()-env.TTL(), env.Topic(), env.Hash()))
+	return db.LevelDB.SaveEnvelope(env)
+}
+
+func setupSpillDB(t *testing.T, primary DB) (*SpillDB, func()) {
+	dir, err := ioutil.TempDir("", "mailserver-spill")
+	require.NoError(t, err)
+	db, err := NewSpillDB(primary, dir, nil)
+	require.NoError(t, err)
+	return db, func() {
+		require.NoError(t, db.Close())
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+func TestSpillDBQueuesAndDrainsInOrder(t *testing.T) {
+	primary := &unavailableDB{LevelDB: setupTopicStatsDB(t), down: true}
+	db, cleanup := setupSpillDB(t, primary)
+	defer cleanup()
+
+	now := time.Now()
+	var keys []*DBKey
+	for _, ts := range []time.Time{now, now.Add(-2 * time.Hour), now.Add(-time.Hour)} {
+		env, err := generateEnvelope(ts)
+		require.NoError(t, err)
+		wrapped := gethbridge.NewWhisperEnvelope(env)
+		require.NoError(t, db.SaveEnvelope(wrapped))
+		keys = append(keys, NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash()))
+	}
+	require.Equal(t, 3, db.Pending())
+	_, err := primary.GetEnvelope(keys[0])
+	require.Error(t, err)
+
+	moved, err := db.drain()
+	require.Error(t, err)
+	require.Equal(t, 0, moved)
+	require.Equal(t, 3, db.Pending())
+
+	primary.down = false
+	// envelopes are queued until the queue is drained
+	env, err := generateEnvelope(now.Add(-3 * time.Hour))
+	require.NoError(t, err)
+	wrapped := gethbridge.NewWhisperEnvelope(env)
+	require.NoError(t, db.SaveEnvelope(wrapped))
+	keys = append(keys, NewDBKey(wrapped.Expiry()-wrapped.TTL(), wrapped.Topic(), wrapped.Hash()))
+	require.Equal(t, 4, db.Pending())
+
+	moved, err = db.drain()
+	require.NoError(t, err)
+	require.Equal(t, 4, moved)
+	require.Equal(t, 0, db.Pending())
+	require.Equal(t, []*DBKey{keys[3], keys[1], keys[2], keys[0]}, primary.saved)
+	for _, key := range keys {
+		_, err := primary.GetEnvelope(key)
+		require.NoError(t, err)
+	}
+
+	env, err = generateEnvelope(now)
+	require.NoError(t, err)
+	require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	require.Equal(t, 0, db.Pending())
+	require.Len(t, primary.saved, 5)
+}
+
+func TestSpillDBReportsDrainedEnvelopes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailserver-spill")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var drained []uint32
+	primary := &unavailableDB{LevelDB: setupTopicStatsDB(t), down: true}
+	db, err := NewSpillDB(primary, dir, func(timestamp uint32) {
+		drained = append(drained, timestamp)
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	require.Empty(t, drained)
+
+	primary.down = false
+	_, err = db.drain()
+	require.NoError(t, err)
+	require.Equal(t, []uint32{env.Expiry - env.TTL}, drained)
+}
+
+func TestSpillDBReopensQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailserver-spill")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := &unavailableDB{LevelDB: setupTopicStatsDB(t), down: true}
+	db, err := NewSpillDB(primary, dir, nil)
+	require.NoError(t, err)
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	// queueing the same envelope again is a no-op
+	require.NoError(t, db.queue(gethbridge.NewWhisperEnvelope(env)))
+	close(db.cancel)
+	db.wg.Wait()
+	require.NoError(t, db.spill.Close())
+
+	db, err = NewSpillDB(primary, dir, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	require.Equal(t, 1, db.Pending())
+}
+
+func TestSpillDBDropsEnvelopesOverQuota(t *testing.T) {
+	primary := &unavailableDB{LevelDB: setupTopicStatsDB(t), full: true}
+	db, cleanup := setupSpillDB(t, primary)
+	defer cleanup()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	require.Equal(t, ErrTenantQuotaExceeded, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	require.Equal(t, 0, db.Pending())
+
+	// queued envelopes over the quota are dropped
+	require.NoError(t, db.queue(gethbridge.NewWhisperEnvelope(env)))
+	moved, err := db.drain()
+	require.NoError(t, err)
+	require.Equal(t, 0, moved)
+	require.Equal(t, 0, db.Pending())
+}
+
+func TestSpillDBPaused(t *testing.T) {
+	primary := &unavailableDB{LevelDB: setupTopicStatsDB(t)}
+	db, cleanup := setupSpillDB(t, primary)
+	defer cleanup()
+
+	db.Pause(true)
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	require.Equal(t, 1, db.Pending())
+
+	// the queue is not moved while it's paused
+	moved, err := db.drain()
+	require.NoError(t, err)
+	require.Equal(t, 0, moved)
+	require.Empty(t, primary.saved)
+
+	db.Pause(false)
+	moved, err = db.drain()
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+	require.Len(t, primary.saved, 1)
+}
+
+func TestArchiveInReadOnlyMode(t *testing.T) {
+	server := setupTestServer(t)
+	api := NewAdminAPI(server)
+
+	status, err := api.SetReadOnly(context.Background(), true)
+	require.NoError(t, err)
+	require.Equal(t, ReadOnlyStatus{ReadOnly: true}, status)
+	archiveEnvelope(t, time.Now().Add(-time.Minute), server)
+	require.Equal(t, 0, countMessages(t, server.ms.db))
+
+	status, err = api.SetReadOnly(context.Background(), false)
+	require.NoError(t, err)
+	require.False(t, status.ReadOnly)
+	archiveEnvelope(t, time.Now().Add(-time.Minute), server)
+	require.Equal(t, 1, countMessages(t, server.ms.db))
+}
+
+func TestArchiveInReadOnlyModeIsSpooled(t *testing.T) {
+	server := setupTestServer(t)
+	primary := server.ms.db
+	db, cleanup := setupSpillDB(t, primary)
+	defer cleanup()
+	server.ms.db = db
+	api := NewAdminAPI(server)
+
+	_, err := api.SetReadOnly(context.Background(), true)
+	require.NoError(t, err)
+	archiveEnvelope(t, time.Now().Add(-time.Minute), server)
+	require.Equal(t, 0, countMessages(t, primary))
+	status, err := api.GetReadOnlyStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ReadOnlyStatus{ReadOnly: true, Spooled: 1}, status)
+
+	_, err = api.SetReadOnly(context.Background(), false)
+	require.NoError(t, err)
+	moved, err := db.drain()
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+	require.Equal(t, 1, countMessages(t, primary))
+}
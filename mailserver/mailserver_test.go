@@ -17,6 +17,7 @@
 package mailserver
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/binary"
 	"encoding/hex"
@@ -279,6 +280,61 @@ func (s *MailserverSuite) TestManageLimits() {
 	s.Equal(firstSaved, s.server.ms.rateLimiter.db["peerID"])
 }
 
+func (s *MailserverSuite) TestReload() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+	ms := s.server.ms
+	s.Nil(ms.rateLimiter)
+	s.Nil(ms.cleaner)
+
+	cfg := ms.config
+	cfg.RateLimit = 5
+	cfg.DataRetention = 10
+	cfg.MaxQueryLimit = 100
+	cfg.QueryTimeout = time.Second
+	cfg.Password = "new password"
+	changes := ms.reload(cfg)
+	s.Len(changes, 4)
+	s.NotNil(ms.rateLimiter)
+	s.NotNil(ms.cleaner)
+	s.Equal(uint32(100), ms.maxQueryLimit)
+	s.Equal(int64(time.Second), ms.queryTimeout)
+	s.Equal(time.Duration(0), ms.softDeleteWindow())
+	// fields that require a restart are not applied
+	s.NotEqual(cfg.Password, ms.config.Password)
+
+	s.Empty(ms.reload(cfg))
+
+	cfg.RateLimit = 0
+	cfg.DataRetention = 0
+	s.Len(ms.reload(cfg), 2)
+	s.Nil(ms.rateLimiter)
+	s.Nil(ms.cleaner)
+
+	cfg.QueryTiers = []QueryTier{{MinAge: time.Hour, AllowedPeersOnly: true}}
+	s.Len(ms.reload(cfg), 1)
+	s.NotNil(ms.queryPolicy)
+	// invalid allowed peers keep the current policy
+	cfg.AllowedPeers = []string{"invalid"}
+	s.Len(ms.reload(cfg), 1)
+	s.NotNil(ms.queryPolicy)
+	s.Empty(ms.config.AllowedPeers)
+	cfg.AllowedPeers = nil
+	cfg.QueryTiers = nil
+	s.Len(ms.reload(cfg), 1)
+	s.Nil(ms.queryPolicy)
+
+	cfg.MaxEnvelopeSize = 1024
+	cfg.MaxEnvelopeTTL = time.Hour
+	s.Len(ms.reload(cfg), 2)
+	s.Equal(uint32(1024), ms.validator.limits.maxSize)
+	s.Equal(time.Hour, ms.validator.limits.maxTTL)
+
+	cfg.ReadOnly = true
+	s.Len(ms.reload(cfg), 1)
+	s.True(ms.isReadOnly())
+}
+
 func (s *MailserverSuite) TestDBKey() {
 	var h types.Hash
 	var emptyTopic types.TopicType
@@ -343,6 +399,291 @@ func (s *MailserverSuite) TestRequestPaginationLimit() {
 	s.Nil(cursor)
 }
 
+func (s *MailserverSuite) TestRequestPaginationResponseSize() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+
+	var (
+		sentEnvelopes []*whisper.Envelope
+		sentHashes    []common.Hash
+		sentSizes     []int
+		archiveKeys   []string
+	)
+
+	now := time.Now()
+	count := uint32(10)
+
+	for i := count; i > 0; i-- {
+		sentTime := now.Add(time.Duration(-i) * time.Second)
+		env, err := generateEnvelope(sentTime)
+		s.NoError(err)
+		s.server.Archive(env)
+		key := NewDBKey(env.Expiry-env.TTL, types.TopicType(env.Topic), types.Hash(env.Hash()))
+		archiveKeys = append(archiveKeys, fmt.Sprintf("%x", key.Cursor()))
+		rawEnvelope, err := rlp.EncodeToBytes(env)
+		s.NoError(err)
+		sentSizes = append(sentSizes, len(rawEnvelope))
+		sentEnvelopes = append(sentEnvelopes, env)
+		sentHashes = append(sentHashes, env.Hash())
+	}
+
+	// enough for three envelopes but not for four
+	s.server.ms.maxResponseSize = uint32(sentSizes[0] + sentSizes[1] + sentSizes[2] + sentSizes[3]/2)
+
+	peerID, request, err := s.prepareRequest(sentEnvelopes, count)
+	s.NoError(err)
+	payload, err := s.server.decompositeRequest(peerID, request)
+	s.NoError(err)
+
+	receivedHashes, cursor, lastHash := processRequestAndCollectHashes(s.server, payload)
+	s.Equal(sentHashes[:3], receivedHashes)
+	s.Equal(types.Hash(sentHashes[2]), lastHash)
+	// cursor should be the key of the last envelope sent
+	s.Equal(archiveKeys[2], fmt.Sprintf("%x", cursor))
+
+	// second page starts right after the cursor
+	payload.Cursor = cursor
+	receivedHashes, _, _ = processRequestAndCollectHashes(s.server, payload)
+	s.Equal(sentHashes[3:6], receivedHashes)
+
+	// a single envelope exceeding the budget is still sent
+	s.server.ms.maxResponseSize = 1
+	payload.Cursor = nil
+	receivedHashes, cursor, _ = processRequestAndCollectHashes(s.server, payload)
+	s.Equal(sentHashes[:1], receivedHashes)
+	s.Equal(archiveKeys[0], fmt.Sprintf("%x", cursor))
+}
+
+type maxMessageSizeService struct {
+	service
+	maxMessageSize uint32
+}
+
+func (s maxMessageSizeService) MaxMessageSize() uint32 {
+	return s.maxMessageSize
+}
+
+func (s *MailserverSuite) TestProcessRequestStalledPeer() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+
+	var (
+		sentEnvelopes []*whisper.Envelope
+		sentHashes    []common.Hash
+		archiveKeys   []string
+		maxSize       int
+	)
+
+	now := time.Now()
+	count := uint32(10)
+
+	for i := count; i > 0; i-- {
+		sentTime := now.Add(time.Duration(-i) * time.Second)
+		env, err := generateEnvelope(sentTime)
+		s.NoError(err)
+		s.server.Archive(env)
+		key := NewDBKey(env.Expiry-env.TTL, types.TopicType(env.Topic), types.Hash(env.Hash()))
+		archiveKeys = append(archiveKeys, fmt.Sprintf("%x", key.Cursor()))
+		rawEnvelope, err := rlp.EncodeToBytes(env)
+		s.NoError(err)
+		if len(rawEnvelope) > maxSize {
+			maxSize = len(rawEnvelope)
+		}
+		sentEnvelopes = append(sentEnvelopes, env)
+		sentHashes = append(sentHashes, env.Hash())
+	}
+
+	// two envelopes in every bundle
+	s.server.ms.service = maxMessageSizeService{service: s.server.ms.service, maxMessageSize: uint32(maxSize*2 + maxSize/2)}
+
+	peerID, request, err := s.prepareRequest(sentEnvelopes, count)
+	s.NoError(err)
+	payload, err := s.server.decompositeRequest(peerID, request)
+	s.NoError(err)
+
+	iter, err := s.server.ms.createIterator(context.Background(), payload)
+	s.Require().NoError(err)
+	defer func() { _ = iter.Release() }()
+
+	// The peer reads only a single bundle, another one waits in the queue.
+	bundles := make(chan []rlp.RawValue, 1)
+	received := make(chan []rlp.RawValue, 1)
+	go func() { received <- <-bundles }()
+
+	cursor, lastHash, err := s.server.ms.processRequestInBundles(context.Background(), iter, payload.Bloom, payload.Topics, int(payload.Limit), 100*time.Millisecond, "req-01", bundles, nil, nil)
+	s.Equal(errDeliveryStalled, err)
+	s.Len(<-received, 2)
+	s.Len(<-bundles, 2)
+	// cursor points to the last queued envelope
+	s.Equal(archiveKeys[3], fmt.Sprintf("%x", cursor))
+	s.Equal(types.Hash(sentHashes[3]), lastHash)
+
+	// the peer can resume from the cursor
+	payload.Cursor = cursor
+	receivedHashes, cursor, _ := processRequestAndCollectHashes(s.server, payload)
+	s.Equal(sentHashes[4:], receivedHashes)
+	s.Nil(cursor)
+}
+
+// slowIterator blocks until the context is done after a number of envelopes.
+type slowIterator struct {
+	Iterator
+	ctx   context.Context
+	after int
+}
+
+func (i *slowIterator) Next() bool {
+	if i.after == 0 {
+		<-i.ctx.Done()
+	}
+	i.after--
+	return i.Iterator.Next()
+}
+
+func (s *MailserverSuite) TestProcessRequestQueryTimeout() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+
+	var (
+		sentEnvelopes []*whisper.Envelope
+		sentHashes    []common.Hash
+		archiveKeys   []string
+	)
+
+	now := time.Now()
+	count := uint32(10)
+
+	for i := count; i > 0; i-- {
+		sentTime := now.Add(time.Duration(-i) * time.Second)
+		env, err := generateEnvelope(sentTime)
+		s.NoError(err)
+		s.server.Archive(env)
+		key := NewDBKey(env.Expiry-env.TTL, types.TopicType(env.Topic), types.Hash(env.Hash()))
+		archiveKeys = append(archiveKeys, fmt.Sprintf("%x", key.Cursor()))
+		sentEnvelopes = append(sentEnvelopes, env)
+		sentHashes = append(sentHashes, env.Hash())
+	}
+
+	peerID, request, err := s.prepareRequest(sentEnvelopes, count)
+	s.NoError(err)
+	payload, err := s.server.decompositeRequest(peerID, request)
+	s.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	iter, err := s.server.ms.createIterator(ctx, payload)
+	s.Require().NoError(err)
+	defer func() { _ = iter.Release() }()
+
+	// The query times out after three envelopes.
+	bundles := make(chan []rlp.RawValue, 10)
+	cursor, lastHash, err := s.server.ms.processRequestInBundles(ctx, &slowIterator{Iterator: iter, ctx: ctx, after: 3}, payload.Bloom, payload.Topics, int(payload.Limit), time.Minute, "req-01", bundles, nil, nil)
+	s.Equal(errQueryTimeout, err)
+	s.Equal(context.DeadlineExceeded, iter.Error())
+	var received int
+	for bundle := range bundles {
+		received += len(bundle)
+	}
+	s.Equal(3, received)
+	// cursor points to the last envelope taken before the timeout
+	s.Equal(archiveKeys[2], fmt.Sprintf("%x", cursor))
+	s.Equal(types.Hash(sentHashes[2]), lastHash)
+
+	// the peer can resume from the cursor
+	payload.Cursor = cursor
+	receivedHashes, cursor, _ := processRequestAndCollectHashes(s.server, payload)
+	s.Equal(sentHashes[3:], receivedHashes)
+	s.Nil(cursor)
+
+	// there is no cursor if the query timed out before any envelope was found
+	iter, err = s.server.ms.createIterator(ctx, payload)
+	s.Require().NoError(err)
+	defer func() { _ = iter.Release() }()
+	cursor, _, err = s.server.ms.processRequestInBundles(ctx, iter, payload.Bloom, payload.Topics, int(payload.Limit), time.Minute, "req-01", make(chan []rlp.RawValue, 10), nil, nil)
+	s.Equal(errQueryTimeout, err)
+	s.Nil(cursor)
+}
+
+func (s *MailserverSuite) TestTopicIndex() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+
+	index, err := newTopicIndex(s.server.ms.db, time.Now())
+	s.Require().NoError(err)
+	// the index is complete from the beginning
+	index.start = 0
+	s.server.ms.topicIndex = index
+
+	now := time.Now()
+	env, err := generateEnvelope(now)
+	s.Require().NoError(err)
+	s.server.Archive(env)
+	lower, upper := uint32(now.Add(-time.Hour).Unix()), uint32(now.Add(time.Hour).Unix())
+
+	s.True(s.messageExists(env, lower, upper, types.TopicToBloom(types.TopicType(env.Topic)), 10))
+	s.True(s.messageExists(env, lower, upper, types.MakeFullNodeBloom(), 10))
+
+	// no indexed topics match the bloom filter
+	iter, err := s.server.ms.createIterator(context.Background(), MessagesRequestPayload{
+		Lower: lower,
+		Upper: upper,
+		Bloom: types.TopicToBloom(types.TopicType{0xAA, 0xBB, 0xCC, 0xDD}),
+		Limit: 10,
+	})
+	s.Require().NoError(err)
+	s.Equal(emptyIterator{}, iter)
+}
+
+func (s *MailserverSuite) TestBloomIndex() {
+	s.setupServer(s.server)
+	defer s.server.Close()
+
+	index, err := newBloomIndex(s.server.ms.db, time.Now())
+	s.Require().NoError(err)
+	// the index is complete from the beginning
+	index.start = 0
+	s.server.ms.bloomIndex = index
+
+	now := time.Now()
+	env, err := generateEnvelope(now)
+	s.Require().NoError(err)
+	s.server.Archive(env)
+	lower, upper := uint32(now.Add(-time.Hour).Unix()), uint32(now.Add(time.Hour).Unix())
+
+	s.True(s.messageExists(env, lower, upper, types.TopicToBloom(types.TopicType(env.Topic)), 10))
+	s.True(s.messageExists(env, lower, upper, types.MakeFullNodeBloom(), 10))
+
+	// no buckets in the range match the bloom filter
+	iter, err := s.server.ms.createIterator(context.Background(), MessagesRequestPayload{
+		Lower: lower,
+		Upper: upper,
+		Bloom: types.TopicToBloom(types.TopicType{0xAA, 0xBB, 0xCC, 0xDD}),
+		Limit: 10,
+	})
+	s.Require().NoError(err)
+	s.Equal(emptyIterator{}, iter)
+}
+
+func (s *MailserverSuite) TestApplyQueryLimit() {
+	ms := &mailServer{}
+
+	req := MessagesRequestPayload{Limit: 500}
+	ms.applyQueryLimit(&req)
+	s.Equal(uint32(500), req.Limit)
+
+	ms.maxQueryLimit = 100
+	ms.applyQueryLimit(&req)
+	s.Equal(uint32(100), req.Limit)
+
+	req = MessagesRequestPayload{}
+	ms.applyQueryLimit(&req)
+	s.Equal(uint32(100), req.Limit)
+
+	req = MessagesRequestPayload{Limit: 10}
+	ms.applyQueryLimit(&req)
+	s.Equal(uint32(10), req.Limit)
+}
+
 func (s *MailserverSuite) TestMailServer() {
 	s.setupServer(s.server)
 	defer s.server.Close()
@@ -531,7 +872,7 @@ func (s *MailserverSuite) TestProcessRequestDeadlockHandling() {
 				processFinished := make(chan struct{})
 
 				go func() {
-					s.server.ms.processRequestInBundles(iter, payload.Bloom, payload.Topics, int(payload.Limit), timeout, "req-01", bundles, done)
+					s.server.ms.processRequestInBundles(context.Background(), iter, payload.Bloom, payload.Topics, int(payload.Limit), timeout, "req-01", bundles, done, nil)
 					close(processFinished)
 				}()
 				go close(done)
@@ -555,7 +896,7 @@ func (s *MailserverSuite) TestProcessRequestDeadlockHandling() {
 				processFinished := make(chan struct{})
 
 				go func() {
-					s.server.ms.processRequestInBundles(iter, payload.Bloom, payload.Topics, int(payload.Limit), time.Second, "req-01", bundles, done)
+					s.server.ms.processRequestInBundles(context.Background(), iter, payload.Bloom, payload.Topics, int(payload.Limit), time.Second, "req-01", bundles, done, nil)
 					close(processFinished)
 				}()
 
@@ -570,7 +911,7 @@ func (s *MailserverSuite) TestProcessRequestDeadlockHandling() {
 
 	for _, tc := range testCases {
 		s.T().Run(tc.Name, func(t *testing.T) {
-			iter, err := s.server.ms.createIterator(payload)
+			iter, err := s.server.ms.createIterator(context.Background(), payload)
 			s.Require().NoError(err)
 
 			defer func() { _ = iter.Release() }()
@@ -772,7 +1113,7 @@ func generateEnvelope(sentTime time.Time) (*whisper.Envelope, error) {
 }
 
 func processRequestAndCollectHashes(server *WhisperMailServer, payload MessagesRequestPayload) ([]common.Hash, []byte, types.Hash) {
-	iter, _ := server.ms.createIterator(payload)
+	iter, _ := server.ms.createIterator(context.Background(), payload)
 	defer func() { _ = iter.Release() }()
 	bundles := make(chan []rlp.RawValue, 10)
 	done := make(chan struct{})
@@ -791,7 +1132,7 @@ func processRequestAndCollectHashes(server *WhisperMailServer, payload MessagesR
 		close(done)
 	}()
 
-	cursor, lastHash := server.ms.processRequestInBundles(iter, payload.Bloom, payload.Topics, int(payload.Limit), time.Minute, "req-01", bundles, done)
+	cursor, lastHash, _ := server.ms.processRequestInBundles(context.Background(), iter, payload.Bloom, payload.Topics, int(payload.Limit), time.Minute, "req-01", bundles, done, nil)
 
 	<-done
 
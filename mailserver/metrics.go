@@ -22,6 +22,14 @@ var (
 		Name: "mailserver_requests_batched_total",
 		Help: "Number of processed batched requests.",
 	})
+	requestsDigestCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_requests_digest_total",
+		Help: "Number of processed digest requests.",
+	})
+	requestsByKeysCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_requests_by_keys_total",
+		Help: "Number of processed requests for envelopes with given keys.",
+	})
 	requestsInBundlesDuration = prom.NewHistogram(prom.HistogramOpts{
 		Name: "mailserver_requests_bundle_process_duration_seconds",
 		Help: "The time it took to process message bundles.",
@@ -60,6 +68,154 @@ var (
 		Name: "mailserver_delivery_duration_seconds",
 		Help: "Time it takes to deliver messages to a Whisper peer.",
 	})
+	responseSizeLimitCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_response_size_limit_reached_total",
+		Help: "Number of responses cut short because of the response size limit.",
+	})
+	deliveryStalledCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_delivery_stalled_total",
+		Help: "Number of requests aborted because a peer did not read envelopes in time.",
+	})
+	queryTimeoutCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_query_timeout_total",
+		Help: "Number of requests cut short because a database query timed out.",
+	})
+	queryCacheHitsCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_query_cache_hits_total",
+		Help: "Number of requests served from the query cache.",
+	})
+	queryCacheMissesCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_query_cache_misses_total",
+		Help: "Number of requests not found in the query cache.",
+	})
+	topicIndexQueriesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_topic_index_queries_total",
+		Help: "Number of requests with a bloom filter by the way the topic index narrowed them down.",
+	}, []string{"result"})
+	bloomIndexQueriesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_bloom_index_queries_total",
+		Help: "Number of requests with a bloom filter by the way the bloom index narrowed their time ranges.",
+	}, []string{"result"})
+	bloomIndexSkippedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_bloom_index_skipped_buckets_total",
+		Help: "Number of hourly buckets skipped by requests because their blooms don't match.",
+	})
+	consistencyCheckedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_consistency_check_envelopes_total",
+		Help: "Number of envelopes validated by the startup consistency check.",
+	})
+	consistencyDroppedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_consistency_check_dropped_total",
+		Help: "Number of corrupted envelopes dropped by the startup consistency check.",
+	})
+	queryTierRequestsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_query_tier_requests_total",
+		Help: "Number of requests by the query tier of requested envelopes and the result of its policy.",
+	}, []string{"tier", "result"})
+	compactionDuration = prom.NewHistogramVec(prom.HistogramOpts{
+		Name: "mailserver_compaction_duration_seconds",
+		Help: "The time it took to compact the LevelDB archive by the reason of the compaction.",
+	}, []string{"reason"})
+	spilledEnvelopesCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_spilled_envelopes_total",
+		Help: "Number of envelopes queued locally because the database was unavailable.",
+	})
+	spillQueueGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_spill_queue_envelopes",
+		Help: "Number of envelopes queued locally waiting to be moved to the database.",
+	})
+	compressedResponsesCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_compressed_responses_total",
+		Help: "Number of batches of envelopes sent compressed.",
+	})
+	compressionBytesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_compression_bytes_total",
+		Help: "Size of compressed batches of envelopes before (raw) and after (compressed) the compression.",
+	}, []string{"type"})
+	tenantArchivedEnvelopesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_tenant_archived_envelopes_total",
+		Help: "Number of envelopes archived by the tenant.",
+	}, []string{"tenant"})
+	tenantQuotaExceededCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_tenant_quota_exceeded_total",
+		Help: "Number of envelopes rejected because the quota of the tenant was exceeded.",
+	}, []string{"tenant"})
+	tenantRequestsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_tenant_requests_total",
+		Help: "Number of history requests served for the tenant.",
+	}, []string{"tenant"})
+	readOnlyGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_read_only",
+		Help: "Whether the mailserver is in the read-only mode.",
+	})
+	readOnlyEnvelopesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_read_only_envelopes_total",
+		Help: "Number of envelopes received in the read-only mode by the action taken.",
+	}, []string{"action"})
+	archiveRejectedCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_archive_rejected_envelopes_total",
+		Help: "Number of envelopes rejected before they were archived.",
+	}, []string{"reason"})
+	indexBuildProgressGauge = prom.NewGaugeVec(prom.GaugeOpts{
+		Name: "mailserver_index_build_progress",
+		Help: "Fraction of a background index build that is done.",
+	}, []string{"index"})
+	shardHealthGauge = prom.NewGaugeVec(prom.GaugeOpts{
+		Name: "mailserver_db_shard_healthy",
+		Help: "Whether the last operation on a database shard succeeded.",
+	}, []string{"shard"})
+	coverageGapsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_coverage_gaps_total",
+		Help: "Number of recorded time ranges during which envelopes were not archived.",
+	}, []string{"reason"})
+	coverageHealedGapsCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_coverage_healed_gaps_total",
+		Help: "Number of coverage gaps backfilled from peer mailservers.",
+	})
+	postgresHealthCheckFailuresCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_db_health_check_failures_total",
+		Help: "Number of failed health checks of postgres connection pools by the check.",
+	}, []string{"check"})
+	postgresReconnectsCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_db_reconnects_total",
+		Help: "Number of times postgres connections were dropped after a failed health check.",
+	})
+	postgresDegradedGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_db_degraded",
+		Help: "Number of postgres databases unreachable longer than the degraded threshold.",
+	})
+	queryQueueDepthGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_query_queue_depth",
+		Help: "Number of history queries waiting for a free slot.",
+	})
+	queryQueuePeersGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_query_queue_peers",
+		Help: "Number of peers with history queries waiting for a free slot.",
+	})
+	queriesRunningGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "mailserver_queries_running",
+		Help: "Number of history queries being processed.",
+	})
+	queryQueueWaitDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name: "mailserver_query_queue_wait_duration_seconds",
+		Help: "Time history queries waited for a free slot.",
+	})
+	listenerRequestsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "mailserver_listener_requests_total",
+		Help: "Number of requests by the listener of the peer connection and the result of its limits.",
+	}, []string{"listener", "result"})
+	requestsAckedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_requests_acked_total",
+		Help: "Number of requests delivered in batches acknowledged by the client.",
+	})
+	acknowledgedBatchesCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_acknowledged_batches_total",
+		Help: "Number of batches acknowledged by clients.",
+	})
+	ackTimeoutsCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "mailserver_ack_timeouts_total",
+		Help: "Number of batches not acknowledged in time.",
+	})
 )
 
 func init() {
@@ -67,6 +223,8 @@ func init() {
 	prom.MustRegister(deliveryFailuresCounter)
 	prom.MustRegister(deliveryAttemptsCounter)
 	prom.MustRegister(requestsBatchedCounter)
+	prom.MustRegister(requestsDigestCounter)
+	prom.MustRegister(requestsByKeysCounter)
 	prom.MustRegister(requestsInBundlesDuration)
 	prom.MustRegister(syncFailuresCounter)
 	prom.MustRegister(syncAttemptsCounter)
@@ -76,4 +234,41 @@ func init() {
 	prom.MustRegister(archivedEnvelopesCounter)
 	prom.MustRegister(archivedEnvelopeSizeMeter)
 	prom.MustRegister(mailDeliveryDuration)
+	prom.MustRegister(responseSizeLimitCounter)
+	prom.MustRegister(deliveryStalledCounter)
+	prom.MustRegister(queryTimeoutCounter)
+	prom.MustRegister(queryCacheHitsCounter)
+	prom.MustRegister(queryCacheMissesCounter)
+	prom.MustRegister(topicIndexQueriesCounter)
+	prom.MustRegister(bloomIndexQueriesCounter)
+	prom.MustRegister(bloomIndexSkippedCounter)
+	prom.MustRegister(consistencyCheckedCounter)
+	prom.MustRegister(consistencyDroppedCounter)
+	prom.MustRegister(queryTierRequestsCounter)
+	prom.MustRegister(compactionDuration)
+	prom.MustRegister(spilledEnvelopesCounter)
+	prom.MustRegister(spillQueueGauge)
+	prom.MustRegister(shardHealthGauge)
+	prom.MustRegister(compressedResponsesCounter)
+	prom.MustRegister(compressionBytesCounter)
+	prom.MustRegister(tenantArchivedEnvelopesCounter)
+	prom.MustRegister(tenantQuotaExceededCounter)
+	prom.MustRegister(tenantRequestsCounter)
+	prom.MustRegister(indexBuildProgressGauge)
+	prom.MustRegister(archiveRejectedCounter)
+	prom.MustRegister(readOnlyGauge)
+	prom.MustRegister(readOnlyEnvelopesCounter)
+	prom.MustRegister(coverageGapsCounter)
+	prom.MustRegister(coverageHealedGapsCounter)
+	prom.MustRegister(postgresHealthCheckFailuresCounter)
+	prom.MustRegister(postgresReconnectsCounter)
+	prom.MustRegister(postgresDegradedGauge)
+	prom.MustRegister(queryQueueDepthGauge)
+	prom.MustRegister(queryQueuePeersGauge)
+	prom.MustRegister(queriesRunningGauge)
+	prom.MustRegister(queryQueueWaitDuration)
+	prom.MustRegister(listenerRequestsCounter)
+	prom.MustRegister(requestsAckedCounter)
+	prom.MustRegister(acknowledgedBatchesCounter)
+	prom.MustRegister(ackTimeoutsCounter)
 }
@@ -2,6 +2,30 @@
 // sources:
 // 1557732988_initialize_db.down.sql (72B)
 // 1557732988_initialize_db.up.sql (234B)
+// 1581600000_topic_stats.down.sql (24B)
+// 1581600000_topic_stats.up.sql (171B)
+// 1582000000_envelope_sources.down.sql (29B)
+// 1582000000_envelope_sources.up.sql (106B)
+// 1583000000_envelopes_deleted_at.down.sql (79B)
+// 1583000000_envelopes_deleted_at.up.sql (142B)
+// 1584000000_topic_index.down.sql (24B)
+// 1584000000_topic_index.up.sql (102B)
+// 1585000000_archive_stats.down.sql (26B)
+// 1585000000_archive_stats.up.sql (340B)
+// 1586000000_tenants.down.sql (556B)
+// 1586000000_tenants.up.sql (510B)
+// 1587000000_coverage_gaps.down.sql (58B)
+// 1587000000_coverage_gaps.up.sql (313B)
+// 1587100000_coverage_gaps_healed.down.sql (49B)
+// 1587100000_coverage_gaps_healed.up.sql (74B)
+// 1587200000_bloom_index.down.sql (24B)
+// 1587200000_bloom_index.up.sql (78B)
+// 1587300000_tenant_stats.down.sql (625B)
+// 1587300000_tenant_stats.up.sql (584B)
+// 1587400000_bloom_index_tenant.down.sql (196B)
+// 1587400000_bloom_index_tenant.up.sql (184B)
+// 1587500000_drop_envelopes_hash_idx.down.sql (98B)
+// 1587500000_drop_envelopes_hash_idx.up.sql (41B)
 // static.go (178B)
 
 package migrations
@@ -71,7 +95,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __1557732988_initialize_dbDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xc8\x4c\x89\x4f\xca\xc9\xcf\xcf\x8d\xcf\x4c\xa9\xb0\xe6\x42\x95\x28\xc9\x2f\xc8\x4c\x46\x92\x08\x71\x74\xf2\x71\x55\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\xb6\xe6\x02\x04\x00\x00\xff\xff\x6b\x93\xaa\x08\x48\x00\x00\x00")
+var __1557732988_initialize_dbDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x48\x00\xb7\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x69\x64\x5f\x62\x6c\x6f\x6f\x6d\x5f\x69\x64\x78\x3b\x0a\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x69\x64\x5f\x74\x6f\x70\x69\x63\x5f\x69\x64\x78\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x65\x6e\x76\x65\x6c\x6f\x70\x65\x73\x3b\x0a\x03\x00\x6b\x93\xaa\x08\x48\x00\x00\x00")
 
 func _1557732988_initialize_dbDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -86,12 +110,12 @@ func _1557732988_initialize_dbDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "1557732988_initialize_db.down.sql", size: 72, mode: os.FileMode(0644), modTime: time.Unix(1574771268, 0)}
+	info := bindataFileInfo{name: "1557732988_initialize_db.down.sql", size: 72, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x40, 0x78, 0xb7, 0x71, 0x3c, 0x20, 0x3b, 0xc9, 0xb, 0x2f, 0x49, 0xe4, 0xff, 0x1c, 0x84, 0x54, 0xa1, 0x30, 0xe3, 0x90, 0xf8, 0x73, 0xda, 0xb0, 0x2a, 0xea, 0x8e, 0xf1, 0x82, 0xe7, 0xd2}}
 	return a, nil
 }
 
-var __1557732988_initialize_dbUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x0e\x72\x75\x0c\x71\x55\x08\x71\x74\xf2\x71\x55\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x56\xd0\xc8\x4c\x51\x70\x8a\x0c\x71\x75\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x08\xf5\xf3\x0c\x0c\x75\xd5\x51\x48\x49\x2c\x49\x44\x93\xd3\x51\x28\xc9\x2f\xc8\x4c\xc6\x10\x4d\xca\xc9\xcf\xcf\x55\x70\xf2\x0c\xd1\x30\x35\x34\xd2\x84\x4b\x68\x5a\x73\x71\x41\xed\xf5\xf4\x73\x71\x8d\x50\xc8\x4c\x89\x07\x2b\x8d\xcf\x4c\xa9\x50\xf0\xf7\x43\x73\x87\x8b\x6b\xb0\x33\xd4\x2c\x4d\x6b\x0c\x8d\x60\x9b\xf1\x69\x04\x2b\xd0\xb4\xe6\x02\x04\x00\x00\xff\xff\xe2\x06\x6b\x16\xea\x00\x00\x00")
+var __1557732988_initialize_dbUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x0e\x72\x75\x0c\x71\x55\x08\x71\x74\xf2\x71\x55\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x56\xd0\xc8\x4c\x51\x70\x8a\x0c\x71\x75\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x08\xf5\xf3\x0c\x0c\x75\xd5\x51\x48\x49\x2c\x49\x44\x93\xd3\x51\x28\xc9\x2f\xc8\x4c\xc6\x10\x4d\xca\xc9\xcf\xcf\x55\x70\xf2\x0c\xd1\x30\x35\x34\xd2\x84\x4b\x68\x5a\x73\x71\x41\xed\xf5\xf4\x73\x71\x8d\x50\xc8\x4c\x89\x07\x2b\x8d\xcf\x4c\xa9\x50\xf0\xf7\x43\x73\x87\x8b\x6b\xb0\x33\xd4\x2c\x4d\x6b\x0c\x8d\x60\x9b\xf1\x69\x2c\xc9\x2f\xc8\x4c\xd6\xb4\xe6\x02\x0c\x00\xe2\x06\x6b\x16\xea\x00\x00\x00")
 
 func _1557732988_initialize_dbUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -106,12 +130,492 @@ func _1557732988_initialize_dbUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "1557732988_initialize_db.up.sql", size: 234, mode: os.FileMode(0644), modTime: time.Unix(1574771268, 0)}
+	info := bindataFileInfo{name: "1557732988_initialize_db.up.sql", size: 234, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8f, 0xa, 0x31, 0xf, 0x94, 0xe, 0xd7, 0xd6, 0xaa, 0x22, 0xd6, 0x6c, 0x7a, 0xbc, 0xad, 0x6a, 0xed, 0x2e, 0x7a, 0xf0, 0x24, 0x81, 0x87, 0x14, 0xe, 0x1c, 0x8a, 0xf1, 0x45, 0xaf, 0x9e, 0x85}}
 	return a, nil
 }
 
-var _staticGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8c\x41\x6a\xc3\x40\x0c\x45\xf7\x73\x8a\xbf\x6c\xa1\x1e\xed\x7b\x82\x52\x12\x08\x24\x17\x90\x6d\x21\x0b\xc7\x33\x46\x52\x72\xfe\x6c\x12\x42\x96\x8f\xc7\x7b\x44\x38\xf1\xb4\xb2\x0a\x22\x39\x6d\x82\x6c\xa3\xcc\xf1\xa2\xaf\xff\xf3\x0f\xfe\x2e\xc7\xc3\x37\x5c\xa2\xdf\x7c\x92\x80\x9b\x2e\x09\x6b\xd9\x91\x8b\x60\xb4\xc6\x6e\x12\x65\xff\x38\x95\x42\xa4\xfd\x57\xa5\x89\x73\x0a\xb4\x0f\xa3\xb5\x99\x93\x31\xec\xab\x62\x33\x75\x4e\xeb\x2d\x30\x74\xd4\x4a\xb5\xd2\xc6\x76\x0d\xf1\xbb\x38\xbd\x35\x3d\xb3\xaa\x1d\xb5\x3c\x02\x00\x00\xff\xff\xf4\xe4\x35\xe2\xb2\x00\x00\x00")
+var __1581600000_topic_statsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x18\x00\xe7\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x74\x6f\x70\x69\x63\x5f\x73\x74\x61\x74\x73\x3b\x0a\x03\x00\xf1\x5f\x84\xfd\x18\x00\x00\x00")
+
+func _1581600000_topic_statsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1581600000_topic_statsDownSql,
+		"1581600000_topic_stats.down.sql",
+	)
+}
+
+func _1581600000_topic_statsDownSql() (*asset, error) {
+	bytes, err := _1581600000_topic_statsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1581600000_topic_stats.down.sql", size: 24, mode: os.FileMode(0644), modTime: time.Unix(1792054441, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x74, 0x75, 0xe2, 0xac, 0x99, 0xc9, 0x74, 0x39, 0x4e, 0xa8, 0xd2, 0xbf, 0x44, 0xfc, 0x56, 0x21, 0x28, 0xfb, 0x40, 0xe6, 0x13, 0x1e, 0xf2, 0xea, 0x0, 0x15, 0x8b, 0x27, 0x13, 0xd1, 0x5d, 0x46}}
+	return a, nil
+}
+
+var __1581600000_topic_statsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x0e\x72\x75\x0c\x71\x55\x08\x71\x74\xf2\x71\x55\x28\xc9\x2f\xc8\x4c\x8e\x2f\x2e\x49\x2c\x29\x56\xd0\x48\x2a\x4d\xce\x4e\x2d\x51\x70\xf2\x74\xf7\xf4\x0b\x51\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\xd1\x81\x28\x52\x70\x8a\x0c\x71\x75\x44\x12\x4d\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x46\xd7\xa0\xe0\xe2\xea\xe6\x18\xea\x13\xa2\x60\xa0\xa3\x50\x9c\x59\x95\x8a\x4f\x3e\x20\xc8\xd3\xd7\x31\x28\x52\xc1\xdb\x35\x12\x66\xbf\x8e\x42\x49\x7e\x41\x66\xb2\xa6\xa6\x35\x17\x60\x00\x75\x4b\x95\xfa\xab\x00\x00\x00")
+
+func _1581600000_topic_statsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1581600000_topic_statsUpSql,
+		"1581600000_topic_stats.up.sql",
+	)
+}
+
+func _1581600000_topic_statsUpSql() (*asset, error) {
+	bytes, err := _1581600000_topic_statsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1581600000_topic_stats.up.sql", size: 171, mode: os.FileMode(0644), modTime: time.Unix(1792054441, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xde, 0xd1, 0x3b, 0x75, 0x54, 0xe9, 0x86, 0xdb, 0xba, 0x70, 0xae, 0x98, 0xff, 0xb3, 0xe9, 0x2d, 0xdb, 0x78, 0x7b, 0x33, 0xdb, 0xb9, 0xe5, 0x94, 0xb4, 0x55, 0xff, 0x8, 0xbc, 0xfb, 0xb4, 0xe7}}
+	return a, nil
+}
+
+var __1582000000_envelope_sourcesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1d\x00\xe2\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x65\x6e\x76\x65\x6c\x6f\x70\x65\x5f\x73\x6f\x75\x72\x63\x65\x73\x3b\x0a\x03\x00\x1d\x6c\xbe\xec\x1d\x00\x00\x00")
+
+func _1582000000_envelope_sourcesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1582000000_envelope_sourcesDownSql,
+		"1582000000_envelope_sources.down.sql",
+	)
+}
+
+func _1582000000_envelope_sourcesDownSql() (*asset, error) {
+	bytes, err := _1582000000_envelope_sourcesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1582000000_envelope_sources.down.sql", size: 29, mode: os.FileMode(0644), modTime: time.Unix(1792057347, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdd, 0xfe, 0x15, 0x2f, 0x2b, 0xea, 0x9d, 0x62, 0x6a, 0x3, 0x32, 0x9d, 0x80, 0xc4, 0x64, 0xdd, 0x6c, 0xfd, 0xf3, 0x29, 0x1b, 0xba, 0x44, 0xa5, 0xcd, 0x57, 0x75, 0x4, 0x11, 0xc7, 0x26, 0x2d}}
+	return a, nil
+}
+
+var __1582000000_envelope_sourcesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6a\x00\x95\xff\x43\x52\x45\x41\x54\x45\x20\x54\x41\x42\x4c\x45\x20\x65\x6e\x76\x65\x6c\x6f\x70\x65\x5f\x73\x6f\x75\x72\x63\x65\x73\x20\x28\x69\x64\x20\x42\x59\x54\x45\x41\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x50\x52\x49\x4d\x41\x52\x59\x20\x4b\x45\x59\x2c\x20\x70\x65\x65\x72\x20\x42\x59\x54\x45\x41\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x2c\x20\x73\x69\x7a\x65\x20\x42\x49\x47\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x29\x3b\x0a\x03\x00\x84\xe4\xae\xea\x6a\x00\x00\x00")
+
+func _1582000000_envelope_sourcesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1582000000_envelope_sourcesUpSql,
+		"1582000000_envelope_sources.up.sql",
+	)
+}
+
+func _1582000000_envelope_sourcesUpSql() (*asset, error) {
+	bytes, err := _1582000000_envelope_sourcesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1582000000_envelope_sources.up.sql", size: 106, mode: os.FileMode(0644), modTime: time.Unix(1792057347, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x99, 0x5, 0x6e, 0x56, 0xbc, 0x49, 0x74, 0x9, 0x7a, 0xff, 0xce, 0xfa, 0x82, 0x9a, 0x20, 0xea, 0x74, 0xf9, 0x67, 0x7, 0xcc, 0x2d, 0xfa, 0xf6, 0xeb, 0x63, 0x82, 0x8c, 0x93, 0x9, 0xce, 0xc7}}
+	return a, nil
+}
+
+var __1583000000_envelopes_deleted_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4f\x00\xb0\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x65\x6e\x76\x65\x6c\x6f\x70\x65\x73\x5f\x64\x65\x6c\x65\x74\x65\x64\x5f\x61\x74\x3b\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x65\x6e\x76\x65\x6c\x6f\x70\x65\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x64\x65\x6c\x65\x74\x65\x64\x5f\x61\x74\x3b\x0a\x03\x00\xdc\x2f\x05\x11\x4f\x00\x00\x00")
+
+func _1583000000_envelopes_deleted_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1583000000_envelopes_deleted_atDownSql,
+		"1583000000_envelopes_deleted_at.down.sql",
+	)
+}
+
+func _1583000000_envelopes_deleted_atDownSql() (*asset, error) {
+	bytes, err := _1583000000_envelopes_deleted_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1583000000_envelopes_deleted_at.down.sql", size: 79, mode: os.FileMode(0644), modTime: time.Unix(1792061163, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc5, 0x98, 0x9b, 0xc9, 0xc3, 0x8d, 0x5c, 0x92, 0xc, 0xd8, 0x7d, 0x92, 0xa6, 0xe2, 0xcb, 0x69, 0x7f, 0xfb, 0x65, 0xce, 0xab, 0xff, 0x7d, 0xe3, 0x3b, 0xb5, 0x98, 0x66, 0xbf, 0x1a, 0xef, 0x49}}
+	return a, nil
+}
+
+var __1583000000_envelopes_deleted_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x48\x49\xcd\x49\x2d\x49\x4d\x89\x4f\x2c\x51\x70\xf2\x74\xf7\xf4\x0b\xb1\xe6\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x40\x68\x8a\x47\x52\xe9\xef\x87\x64\x98\x06\x42\x42\x53\x21\xdc\xc3\x35\xc8\x15\xd9\x50\xcf\x60\x05\x3f\xff\x10\x05\xbf\x50\x1f\x1f\x6b\x2e\xc0\x00\xce\xc5\x92\xa0\x8e\x00\x00\x00")
+
+func _1583000000_envelopes_deleted_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1583000000_envelopes_deleted_atUpSql,
+		"1583000000_envelopes_deleted_at.up.sql",
+	)
+}
+
+func _1583000000_envelopes_deleted_atUpSql() (*asset, error) {
+	bytes, err := _1583000000_envelopes_deleted_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1583000000_envelopes_deleted_at.up.sql", size: 142, mode: os.FileMode(0644), modTime: time.Unix(1792061163, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe7, 0x80, 0xef, 0xa2, 0x94, 0x80, 0x20, 0x13, 0xd8, 0x61, 0x1, 0x8f, 0x4e, 0x26, 0x9f, 0x4f, 0x79, 0x8f, 0x43, 0x54, 0xf3, 0x88, 0x1b, 0xfe, 0xe0, 0x33, 0x6b, 0x6c, 0x29, 0x23, 0xc3, 0x42}}
+	return a, nil
+}
+
+var __1584000000_topic_indexDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x18\x00\xe7\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x74\x6f\x70\x69\x63\x5f\x69\x6e\x64\x65\x78\x3b\x0a\x03\x00\xe7\xed\x77\xb0\x18\x00\x00\x00")
+
+func _1584000000_topic_indexDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1584000000_topic_indexDownSql,
+		"1584000000_topic_index.down.sql",
+	)
+}
+
+func _1584000000_topic_indexDownSql() (*asset, error) {
+	bytes, err := _1584000000_topic_indexDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1584000000_topic_index.down.sql", size: 24, mode: os.FileMode(0644), modTime: time.Unix(1792062834, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcb, 0xa8, 0xfc, 0xb7, 0x83, 0x69, 0xce, 0x13, 0x80, 0xe5, 0x6f, 0x82, 0xb5, 0x31, 0x9c, 0x82, 0x4e, 0xc6, 0xbd, 0xcd, 0x20, 0x9b, 0x14, 0x47, 0x4d, 0x18, 0xe0, 0x5f, 0xa1, 0xb5, 0xbe, 0x1d}}
+	return a, nil
+}
+
+var __1584000000_topic_indexUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x66\x00\x99\xff\x43\x52\x45\x41\x54\x45\x20\x54\x41\x42\x4c\x45\x20\x74\x6f\x70\x69\x63\x5f\x69\x6e\x64\x65\x78\x20\x28\x62\x75\x63\x6b\x65\x74\x20\x42\x49\x47\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x2c\x20\x74\x6f\x70\x69\x63\x20\x42\x59\x54\x45\x41\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x2c\x20\x50\x52\x49\x4d\x41\x52\x59\x20\x4b\x45\x59\x20\x28\x62\x75\x63\x6b\x65\x74\x2c\x20\x74\x6f\x70\x69\x63\x29\x29\x3b\x0a\x03\x00\x11\x14\xcf\x63\x66\x00\x00\x00")
+
+func _1584000000_topic_indexUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1584000000_topic_indexUpSql,
+		"1584000000_topic_index.up.sql",
+	)
+}
+
+func _1584000000_topic_indexUpSql() (*asset, error) {
+	bytes, err := _1584000000_topic_indexUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1584000000_topic_index.up.sql", size: 102, mode: os.FileMode(0644), modTime: time.Unix(1792062834, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x20, 0xab, 0x55, 0x55, 0x0, 0x1d, 0x5b, 0xf2, 0x91, 0xd, 0x53, 0x7b, 0x81, 0x27, 0xe9, 0x27, 0xba, 0xbb, 0xe8, 0x38, 0xe2, 0xf8, 0x6f, 0xaf, 0x4b, 0x3c, 0xb3, 0x8b, 0xfb, 0xf2, 0x4c, 0xd2}}
+	return a, nil
+}
+
+var __1585000000_archive_statsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1a\x00\xe5\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x61\x72\x63\x68\x69\x76\x65\x5f\x73\x74\x61\x74\x73\x3b\x0a\x03\x00\x98\xf9\x1f\x07\x1a\x00\x00\x00")
+
+func _1585000000_archive_statsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1585000000_archive_statsDownSql,
+		"1585000000_archive_stats.down.sql",
+	)
+}
+
+func _1585000000_archive_statsDownSql() (*asset, error) {
+	bytes, err := _1585000000_archive_statsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1585000000_archive_stats.down.sql", size: 26, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x14, 0x5f, 0x4d, 0x9b, 0xf6, 0x25, 0x3, 0x51, 0xee, 0x7e, 0x36, 0x8c, 0xbd, 0xa9, 0x53, 0xae, 0xbe, 0xff, 0x1e, 0x59, 0x2b, 0xef, 0xc2, 0x81, 0x25, 0xfb, 0x16, 0x9e, 0xfc, 0x52, 0x50, 0xbb}}
+	return a, nil
+}
+
+var __1585000000_archive_statsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\x41\x4b\x03\x31\x10\x85\xef\xf9\x15\xef\x98\x48\x0e\xde\x7b\xca\xc6\x69\x1b\x9a\x4d\x24\x3b\x41\x7b\x2a\x8b\x09\xb8\x50\x5a\x71\x43\x05\x7f\xbd\x6c\x29\x45\xc4\xdb\xbc\x19\xf8\xe6\x7d\x36\x91\x61\x02\x9b\xce\x13\xc6\xcf\xb7\xf7\xe9\x52\x0f\x73\x1b\xdb\x0c\x29\x80\xa9\xa0\x8b\xd1\x93\x09\x78\x4e\xae\x37\x69\x8f\x1d\xed\xf1\x44\x6b\x93\x3d\x83\x53\x26\xd8\x2d\xd9\x1d\xe4\x54\x94\x16\x40\x3d\x5d\xea\xf1\xfc\x51\x67\x74\x6e\xe3\x02\x23\x44\x46\xc8\xde\x2f\xc7\x79\xfa\xae\xff\xed\xcf\xc7\x52\xe7\x86\x6e\xcf\x64\x96\x7c\xaa\x5f\xf7\x2c\xd4\x4a\x08\x17\x06\x4a\x0c\x17\x38\xfe\x6d\x79\x7f\xa8\xaf\x78\x7d\x83\xe9\x1b\x44\x89\x81\x3c\x59\x86\x8d\x39\xb0\x7c\x50\x1a\x36\x1a\x4f\x83\x25\x39\xe4\x5e\x46\xcb\xc4\x07\x4f\x61\xc3\x5b\x59\xc6\x36\x2a\xa5\xf1\xa8\x34\x7a\x17\xae\x4e\xe8\xcd\xeb\x32\x60\x9d\x62\xff\x4b\xef\x65\x4b\x89\x50\xea\xb1\xb6\x5a\x0e\x63\x83\x1b\x10\xb2\xf7\x2b\xf1\x33\x00\x91\xed\xb3\x1d\x54\x01\x00\x00")
+
+func _1585000000_archive_statsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1585000000_archive_statsUpSql,
+		"1585000000_archive_stats.up.sql",
+	)
+}
+
+func _1585000000_archive_statsUpSql() (*asset, error) {
+	bytes, err := _1585000000_archive_statsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1585000000_archive_stats.up.sql", size: 340, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0xc3, 0x98, 0xe7, 0xfe, 0xbb, 0x30, 0xb7, 0xd, 0xc1, 0x24, 0xd2, 0xbb, 0x3, 0xff, 0x6b, 0xd9, 0xb9, 0xaf, 0x73, 0xd4, 0xd7, 0x78, 0x6f, 0xf9, 0x16, 0x73, 0xe5, 0x83, 0xe9, 0x1f, 0x5b}}
+	return a, nil
+}
+
+var __1586000000_tenantsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\xd1\x4a\xc3\x30\x18\x85\xef\xfb\x14\xe7\x6e\x2b\xf8\x06\x15\x21\x6b\xfe\xb1\xb2\x34\x99\x59\x82\xee\x2a\xd4\x25\x60\x70\xb6\xc3\x85\xa1\x6f\x2f\xb4\xa2\x9d\x74\xec\xfa\x7c\xe7\x3f\xff\x39\x9c\x04\x19\xc2\x52\xab\x1a\xa1\x3d\x87\x43\x77\x0c\x27\x3c\xad\x48\x13\x52\x68\x9b\x36\xe1\xfe\x01\xb3\x59\x91\x71\xad\x36\xa8\x24\xa7\x67\x44\xef\x5e\x0e\x5d\xf7\xee\xa2\xff\xfc\x2f\xa4\xee\x18\xf7\x83\xc0\x84\x21\x0d\xc3\x16\x82\x46\xb7\x7b\xbc\x54\x72\x6b\x34\xab\xa4\xf9\x53\xdc\x90\xe7\xa2\x77\x6f\xe1\xeb\x86\x5d\xd8\x5a\xfe\x3c\x78\x8d\x64\x9c\x4f\xe7\x0c\x01\xb0\xb2\x7a\xb4\x84\x79\xf4\x79\x91\x95\x9a\x98\xa1\x89\x7e\x50\x72\x74\x73\x1e\x3d\x38\x6d\xcb\x3b\xf4\xc0\x84\xf1\xb7\xff\x35\x63\x0f\xe4\x45\x96\x8d\xa7\x6f\x3e\xf6\xaf\xf1\x1c\xdc\x29\x35\x69\x7a\xfe\x71\xc7\x4b\xfa\xd6\x22\x97\xf4\xb0\x4a\x3f\x5f\xf4\x58\x28\x25\x88\x49\x6c\x74\x55\x33\xbd\xc3\x9a\x76\xe0\xb4\x64\x56\x18\x18\x6d\x09\xe5\x8a\xca\x35\xe6\xd1\xe7\x45\xf6\x3d\x00\x0f\xa0\xde\x03\x2c\x02\x00\x00")
+
+func _1586000000_tenantsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1586000000_tenantsDownSql,
+		"1586000000_tenants.down.sql",
+	)
+}
+
+func _1586000000_tenantsDownSql() (*asset, error) {
+	bytes, err := _1586000000_tenantsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1586000000_tenants.down.sql", size: 556, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x11, 0xf8, 0x18, 0x33, 0xa8, 0x9a, 0xc8, 0xa9, 0x20, 0x2a, 0x80, 0x93, 0xc0, 0x3f, 0x96, 0xae, 0xb6, 0x59, 0xc, 0xae, 0x58, 0x68, 0xaa, 0xf4, 0x3c, 0x47, 0x2f, 0x14, 0x68, 0x52, 0xd, 0x2b}}
+	return a, nil
+}
+
+var __1586000000_tenantsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x51\xcb\x4a\x03\x31\x14\xdd\xcf\x57\x9c\x5d\x2d\xf4\x0f\xb2\x8a\x93\x2b\x0c\xa6\x49\x4d\x6f\xa0\x5d\x85\xda\x04\x0c\xd6\x99\x62\x43\xd1\xbf\x17\x67\xa4\x0e\x3a\x3e\xb6\xf7\x3c\xb9\x47\x6a\x26\x07\x96\xd7\x9a\x90\xda\x73\x3a\x74\xc7\x74\x82\x54\x0a\xb5\xd5\x7e\x69\x50\x52\xbb\x6b\x0b\x98\x36\x0c\x63\x19\xc6\x6b\x0d\x45\x37\xd2\x6b\xc6\x6c\x26\xaa\x69\x07\xe5\xec\x0a\xb5\x35\x6b\x76\xb2\x31\xfc\x89\x84\x1c\xc3\x63\x7a\xfd\x49\x37\x24\x4f\xc8\x86\x1e\x1f\x6a\x78\xd3\xdc\x79\xc2\xd5\x70\x5d\x20\xc7\xb9\xa8\xaa\x3e\xb4\x31\x8a\x36\xc8\x31\xdc\x1f\xba\xee\x29\xe4\xf8\x22\xbe\x00\xa5\x3b\xe6\xfd\x00\xd4\x8e\x24\xd3\x84\x06\xd6\x8c\x5a\x8d\x72\xa0\x68\x5d\x2f\xd0\x13\xe7\xdf\x0d\x2e\xde\x7f\x19\xf4\xc4\xf7\xd2\xe3\x3f\xec\x9e\xf7\x0f\xf9\x9c\xc2\xa9\xec\xca\xe5\x87\xfd\x0c\x39\x8a\x5f\x98\xff\xdf\x0b\x2b\xd7\x2c\xa5\xdb\xe2\x96\xb6\xa2\x7a\x1b\x00\xa0\xe7\x34\x35\xfe\x01\x00\x00")
+
+func _1586000000_tenantsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1586000000_tenantsUpSql,
+		"1586000000_tenants.up.sql",
+	)
+}
+
+func _1586000000_tenantsUpSql() (*asset, error) {
+	bytes, err := _1586000000_tenantsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1586000000_tenants.up.sql", size: 510, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8, 0x98, 0x43, 0xae, 0xc0, 0xd7, 0x5c, 0x30, 0x4c, 0x46, 0x71, 0xc9, 0xc1, 0x56, 0x7, 0x86, 0x9e, 0x26, 0xf2, 0x86, 0xa7, 0xaa, 0x9b, 0xe0, 0xb7, 0xc3, 0xfe, 0xdc, 0xb1, 0x33, 0xc0, 0x47}}
+	return a, nil
+}
+
+var __1587000000_coverage_gapsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3a\x00\xc5\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x68\x65\x61\x72\x74\x62\x65\x61\x74\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x67\x61\x70\x73\x3b\x0a\x03\x00\x01\xb4\x27\x95\x3a\x00\x00\x00")
+
+func _1587000000_coverage_gapsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587000000_coverage_gapsDownSql,
+		"1587000000_coverage_gaps.down.sql",
+	)
+}
+
+func _1587000000_coverage_gapsDownSql() (*asset, error) {
+	bytes, err := _1587000000_coverage_gapsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587000000_coverage_gaps.down.sql", size: 58, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1a, 0x22, 0x84, 0x84, 0xc6, 0xec, 0xe3, 0x6f, 0x89, 0x61, 0x59, 0xf9, 0x81, 0x7, 0x1b, 0xe, 0x80, 0xf6, 0x7d, 0x82, 0xb2, 0xd6, 0x7, 0xba, 0xf7, 0x63, 0xa8, 0xed, 0x33, 0xa9, 0x8a, 0x5f}}
+	return a, nil
+}
+
+var __1587000000_coverage_gapsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xcd\x8a\x83\x30\x14\x85\xf7\x79\x8a\xb3\x53\xc1\x37\x70\x15\x67\xe2\x10\x26\xe3\x14\xbd\x82\xae\xe4\xb6\xa4\x76\x53\x23\x31\xf4\xf9\x8b\x2d\xfd\xa5\x0b\xb7\xf7\x1c\xee\x77\xbe\xaf\x4a\x49\x52\x20\x99\x1b\x05\x5d\xa0\xfc\x27\xa8\x56\xd7\x54\x63\xe7\x4e\xd6\xf3\x60\xfb\x81\xa7\x19\xb1\x00\x82\x1d\x79\x0c\x20\xd5\xd2\xa5\x58\x36\xc6\xe0\x5b\x15\xb2\x31\x84\x28\x4a\x05\x30\xf0\xd4\xef\xbd\x3b\x22\xd7\x3f\xba\x7c\xd4\x6e\x59\x70\x9f\x12\x6f\x79\x76\xe3\xeb\xe3\xe5\xbe\xa9\xf4\x9f\xac\x3a\xfc\xaa\x0e\xf1\x95\x9e\xde\x11\x89\x48\x32\x21\xd6\x08\x1c\x2c\xfb\xb0\xb5\x1c\x56\x69\x3c\x53\x97\x11\x1c\xde\x27\x8b\x24\x13\xe7\x01\x00\x08\x87\x74\xb7\x39\x01\x00\x00")
+
+func _1587000000_coverage_gapsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587000000_coverage_gapsUpSql,
+		"1587000000_coverage_gaps.up.sql",
+	)
+}
+
+func _1587000000_coverage_gapsUpSql() (*asset, error) {
+	bytes, err := _1587000000_coverage_gapsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587000000_coverage_gaps.up.sql", size: 313, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa, 0x69, 0x4d, 0x1c, 0x53, 0x9f, 0xbc, 0x1b, 0xfd, 0xc0, 0x5a, 0xc0, 0x26, 0xc9, 0x91, 0x11, 0x5c, 0x6f, 0x78, 0xb3, 0x6e, 0x6, 0x9, 0x38, 0x97, 0x51, 0x7, 0xe4, 0xcb, 0xe3, 0x39, 0x7}}
+	return a, nil
+}
+
+var __1587100000_coverage_gaps_healedDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x31\x00\xce\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x67\x61\x70\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x68\x65\x61\x6c\x65\x64\x5f\x61\x74\x3b\x0a\x03\x00\x7d\x9e\xf3\x8c\x31\x00\x00\x00")
+
+func _1587100000_coverage_gaps_healedDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587100000_coverage_gaps_healedDownSql,
+		"1587100000_coverage_gaps_healed.down.sql",
+	)
+}
+
+func _1587100000_coverage_gaps_healedDownSql() (*asset, error) {
+	bytes, err := _1587100000_coverage_gaps_healedDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587100000_coverage_gaps_healed.down.sql", size: 49, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdd, 0x4a, 0x5, 0x43, 0x56, 0x7, 0x29, 0xbb, 0xea, 0xc6, 0x16, 0x21, 0x90, 0x9b, 0x74, 0xee, 0xb2, 0x37, 0x54, 0x1d, 0xb6, 0xa7, 0x5d, 0x36, 0xad, 0x93, 0x19, 0x20, 0xb0, 0xc6, 0x92, 0x2a}}
+	return a, nil
+}
+
+var __1587100000_coverage_gaps_healedUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4a\x00\xb5\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x76\x65\x72\x61\x67\x65\x5f\x67\x61\x70\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x68\x65\x61\x6c\x65\x64\x5f\x61\x74\x20\x42\x49\x47\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x30\x3b\x0a\x03\x00\x01\x07\xe7\xe7\x4a\x00\x00\x00")
+
+func _1587100000_coverage_gaps_healedUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587100000_coverage_gaps_healedUpSql,
+		"1587100000_coverage_gaps_healed.up.sql",
+	)
+}
+
+func _1587100000_coverage_gaps_healedUpSql() (*asset, error) {
+	bytes, err := _1587100000_coverage_gaps_healedUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587100000_coverage_gaps_healed.up.sql", size: 74, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x20, 0xa2, 0xc0, 0x38, 0xb0, 0xfa, 0x24, 0x20, 0xd1, 0x77, 0xf4, 0x48, 0x77, 0x44, 0xe5, 0xda, 0xcd, 0x2, 0x57, 0xc3, 0x6f, 0xf8, 0x8d, 0x28, 0xe2, 0x8e, 0x66, 0xf0, 0xd5, 0x88, 0xc5, 0x4e}}
+	return a, nil
+}
+
+var __1587200000_bloom_indexDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x18\x00\xe7\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x62\x6c\x6f\x6f\x6d\x5f\x69\x6e\x64\x65\x78\x3b\x0a\x03\x00\xc8\xdd\xd9\x2b\x18\x00\x00\x00")
+
+func _1587200000_bloom_indexDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587200000_bloom_indexDownSql,
+		"1587200000_bloom_index.down.sql",
+	)
+}
+
+func _1587200000_bloom_indexDownSql() (*asset, error) {
+	bytes, err := _1587200000_bloom_indexDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587200000_bloom_index.down.sql", size: 24, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbc, 0x72, 0x1e, 0x19, 0x6b, 0x43, 0xfc, 0x89, 0xce, 0x50, 0x46, 0x59, 0x34, 0x7b, 0xe6, 0xef, 0xe4, 0xb9, 0xe3, 0x32, 0x1, 0x2c, 0xae, 0x6, 0x1a, 0xc, 0xbe, 0x39, 0xc4, 0xa4, 0xc4, 0x75}}
+	return a, nil
+}
+
+var __1587200000_bloom_indexUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4e\x00\xb1\xff\x43\x52\x45\x41\x54\x45\x20\x54\x41\x42\x4c\x45\x20\x62\x6c\x6f\x6f\x6d\x5f\x69\x6e\x64\x65\x78\x20\x28\x62\x75\x63\x6b\x65\x74\x20\x42\x49\x47\x49\x4e\x54\x20\x50\x52\x49\x4d\x41\x52\x59\x20\x4b\x45\x59\x2c\x20\x62\x6c\x6f\x6f\x6d\x20\x42\x49\x54\x28\x35\x31\x32\x29\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x29\x3b\x03\x00\xaa\x10\xa3\xc5\x4e\x00\x00\x00")
+
+func _1587200000_bloom_indexUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587200000_bloom_indexUpSql,
+		"1587200000_bloom_index.up.sql",
+	)
+}
+
+func _1587200000_bloom_indexUpSql() (*asset, error) {
+	bytes, err := _1587200000_bloom_indexUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587200000_bloom_index.up.sql", size: 78, mode: os.FileMode(0644), modTime: time.Unix(1792088416, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfb, 0x4e, 0xc5, 0xc1, 0xa6, 0xc4, 0xe0, 0x86, 0x9, 0xa9, 0xad, 0x36, 0x63, 0xfa, 0x70, 0xbd, 0x74, 0xc4, 0x49, 0xe8, 0xaf, 0x22, 0x79, 0x8e, 0x56, 0x6a, 0xb4, 0xa2, 0x9a, 0x2, 0xbb, 0x85}}
+	return a, nil
+}
+
+var __1587300000_tenant_statsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xd1\xcd\x6a\x84\x30\x14\x05\xe0\x7d\x9e\xe2\xee\xac\xd0\x37\xb0\x14\xd2\xe6\x96\x4a\xe3\x0f\x69\x4a\x71\x25\x56\xef\x42\x2c\x51\x6a\x2c\x33\x6f\x3f\x30\xce\x62\x34\x06\x67\xd6\xf7\x9c\x1c\x3e\x22\x50\xa2\x46\x78\x53\x59\x02\xb6\x1f\xda\xba\x1c\x6d\x65\x47\xf8\x7e\x47\x85\x60\xc9\x54\xc6\xc2\xd3\x33\x04\x41\xc4\xb8\xd4\xa8\x40\xf3\x17\x89\x8b\xac\x50\x59\x0e\xaf\x59\xfa\xa9\x15\x8f\x53\x7d\x7d\x2b\x87\x8e\x8e\xbb\x4d\xf9\x95\xa4\x97\x2d\x7f\x96\x0b\x01\xb9\x8a\x13\xae\x0a\xf8\xc0\x02\x1e\x7e\xa6\xba\x23\xfb\x38\xcf\x85\x11\x63\xae\xa5\x35\x0d\x1d\x6e\xb4\xcc\xd9\x6d\xcb\xf9\xe6\xb5\x2c\x9a\x3b\x96\x39\x7b\x9f\x85\xcc\x3f\xfd\xf6\x03\x95\x63\x3f\xfd\xd5\xb4\xff\x39\x4e\x61\xad\x5a\x07\x36\x68\xbe\x37\xbc\x3e\xa7\xe0\x20\xdb\x26\x8c\xd8\x69\x00\x86\x77\x6f\x7f\x71\x02\x00\x00")
+
+func _1587300000_tenant_statsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587300000_tenant_statsDownSql,
+		"1587300000_tenant_stats.down.sql",
+	)
+}
+
+func _1587300000_tenant_statsDownSql() (*asset, error) {
+	bytes, err := _1587300000_tenant_statsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587300000_tenant_stats.down.sql", size: 625, mode: os.FileMode(0644), modTime: time.Unix(1792088473, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9a, 0xbe, 0x67, 0xcd, 0x3d, 0x89, 0x42, 0x46, 0xce, 0xc7, 0xe3, 0xf5, 0x9e, 0x26, 0x70, 0x23, 0x6c, 0x4b, 0x4a, 0xb4, 0xa5, 0x1e, 0x5c, 0xcd, 0xfa, 0x74, 0x1d, 0xf7, 0xbc, 0xcc, 0x76, 0x1e}}
+	return a, nil
+}
+
+var __1587300000_tenant_statsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x91\xc1\x6a\x84\x30\x14\x45\xf7\x7e\xc5\xdb\x59\xc1\x3f\x70\x95\x9a\x14\xa4\x31\x91\xf4\x09\x75\x25\x56\xdf\x42\x2c\x51\x6a\x2c\xed\xdf\x0f\x33\x2e\x66\x50\xb3\x98\x99\xf5\x4d\xce\x3d\x97\xc7\x24\x0a\x03\xc8\x5e\xa5\x00\x37\x4e\x7d\x5b\xcf\xae\x71\x33\x30\xce\x21\xd5\xb2\xcc\x15\x38\xb2\x8d\x75\x80\xe2\x13\x41\x69\x04\x55\x4a\x09\x5c\xbc\xb1\x52\x22\x84\x61\x12\xf8\x18\xdc\xe8\x02\x52\xad\x3e\xd0\xb0\x4c\xe1\x6d\x56\x4f\x03\xfd\xfb\x7f\x9e\xdb\x0b\x93\xe5\xcc\x54\xf0\x2e\x2a\x78\x59\x1d\x62\xf8\x5a\xda\x81\x5c\xbc\xa2\xa2\x24\x38\x20\xf4\xb6\xa3\xbf\x27\xfd\x57\xc6\xb1\xff\x25\xf3\xfa\x5f\xdb\xef\xf7\x27\xfb\x4b\xdf\xe3\x44\xf5\x3c\x2e\x3f\x2d\x3d\x7e\x84\x1d\x68\xbb\x64\xfb\xe0\x60\xce\x8e\xe1\xdd\xd4\x77\x51\x12\x9c\x06\x00\xdc\xa0\xba\xe6\x48\x02\x00\x00")
+
+func _1587300000_tenant_statsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587300000_tenant_statsUpSql,
+		"1587300000_tenant_stats.up.sql",
+	)
+}
+
+func _1587300000_tenant_statsUpSql() (*asset, error) {
+	bytes, err := _1587300000_tenant_statsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587300000_tenant_stats.up.sql", size: 584, mode: os.FileMode(0644), modTime: time.Unix(1792088473, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6, 0xcd, 0x1f, 0x58, 0xc3, 0x1f, 0x43, 0x4f, 0xe, 0x3b, 0x77, 0x5b, 0x34, 0x95, 0xed, 0xc0, 0x70, 0x9a, 0x3c, 0x70, 0xbd, 0x7f, 0x2a, 0x51, 0x55, 0xd0, 0x40, 0xf1, 0x89, 0xea, 0x6f, 0x34}}
+	return a, nil
+}
+
+var __1587400000_bloom_index_tenantDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x71\xf5\x71\x0d\x71\x55\x70\x0b\xf2\xf7\x55\x48\xca\xc9\xcf\xcf\x8d\xcf\xcc\x4b\x49\xad\x50\x08\xf7\x70\x0d\x72\x55\x28\x49\xcd\x4b\xcc\x2b\x51\xb0\xb1\x53\x50\x57\xb7\xe6\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x45\x51\xeb\x12\xe4\x1f\xa0\xe0\xec\xef\x17\x1c\x12\xe4\xe8\xe9\x17\x82\x2c\x17\x5f\x90\x9d\x5a\x49\x50\xa7\x4f\xa8\xaf\x1f\xd4\x2e\xdc\x6a\x1d\x5d\x5c\x14\x02\x82\x3c\x7d\x1d\x83\x22\x15\xbc\x5d\x23\x15\x34\x92\x4a\x93\xb3\x53\x4b\x34\xad\xb9\x00\x03\x00\x4b\x8b\x90\xd6\xc4\x00\x00\x00")
+
+func _1587400000_bloom_index_tenantDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587400000_bloom_index_tenantDownSql,
+		"1587400000_bloom_index_tenant.down.sql",
+	)
+}
+
+func _1587400000_bloom_index_tenantDownSql() (*asset, error) {
+	bytes, err := _1587400000_bloom_index_tenantDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587400000_bloom_index_tenant.down.sql", size: 196, mode: os.FileMode(0644), modTime: time.Unix(1792088513, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x80, 0x76, 0xca, 0xd2, 0xf9, 0xc0, 0x34, 0x20, 0x3e, 0x7c, 0xa7, 0x5d, 0x90, 0x58, 0x8c, 0xfc, 0x98, 0xea, 0x8b, 0x57, 0x64, 0xfe, 0x14, 0x4a, 0x21, 0xa9, 0xeb, 0x30, 0xe8, 0xc8, 0x36, 0xac}}
+	return a, nil
+}
+
+var __1587400000_bloom_index_tenantUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8e\xb1\x0a\xc2\x30\x14\x45\xf7\x7e\xc5\xdd\xaa\xe0\x1f\x74\x7a\x36\x11\x8a\xaf\x49\x79\xbe\x80\x9d\x8a\xd5\x0c\x52\x4d\x1d\x22\xe8\xdf\x8b\xb8\xb8\x74\xbe\xdc\x73\x0e\xb1\x5a\x81\xd2\x96\x2d\xc6\xdb\x3c\xdf\x87\x6b\xba\xc4\x17\xc8\x18\xd4\x9e\x43\xeb\x90\x63\x3a\xa5\x0c\xb5\x47\x85\xf3\x0a\x17\x98\x61\xec\x8e\x02\x2b\xca\xb2\x2a\x96\x18\x46\x7c\x87\xda\xbb\x83\x0a\x35\x4e\xff\xb7\xe1\x31\xc5\xf7\xf2\xf3\x6b\xef\xa4\x69\x49\x7a\xec\x6d\x8f\xd5\xaf\x61\x83\xf1\x79\x9e\x62\x5e\x57\xc5\x67\x00\xc9\x44\xc2\xe8\xb8\x00\x00\x00")
+
+func _1587400000_bloom_index_tenantUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587400000_bloom_index_tenantUpSql,
+		"1587400000_bloom_index_tenant.up.sql",
+	)
+}
+
+func _1587400000_bloom_index_tenantUpSql() (*asset, error) {
+	bytes, err := _1587400000_bloom_index_tenantUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587400000_bloom_index_tenant.up.sql", size: 184, mode: os.FileMode(0644), modTime: time.Unix(1792088513, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb1, 0xcc, 0x59, 0xe1, 0x13, 0xec, 0xe2, 0xef, 0x9e, 0xe6, 0xa6, 0xcf, 0x88, 0xf3, 0x54, 0x89, 0xad, 0x61, 0x60, 0x94, 0xd8, 0x16, 0x3e, 0x25, 0x57, 0xbe, 0xd0, 0x0, 0x2, 0xc, 0x49, 0x67}}
+	return a, nil
+}
+
+var __1587500000_drop_envelopes_hash_idxDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x8e\xcf\x48\x2c\xce\x88\xcf\x4c\xa9\x50\xf0\xf7\x43\x88\x2a\x68\x94\xa4\xe6\x25\xe6\x95\xe8\x28\x14\x97\x26\x15\x97\x14\x65\xe6\xa5\x6b\x64\xa6\x28\xa4\x15\xe5\xe7\x2a\x98\x2a\xa4\xe5\x17\x29\x18\x1b\x69\x6a\x5a\x73\x01\x00\xd2\x8a\xbd\xa8\x62\x00\x00\x00")
+
+func _1587500000_drop_envelopes_hash_idxDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587500000_drop_envelopes_hash_idxDownSql,
+		"1587500000_drop_envelopes_hash_idx.down.sql",
+	)
+}
+
+func _1587500000_drop_envelopes_hash_idxDownSql() (*asset, error) {
+	bytes, err := _1587500000_drop_envelopes_hash_idxDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587500000_drop_envelopes_hash_idx.down.sql", size: 98, mode: os.FileMode(0644), modTime: time.Unix(1792099703, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xab, 0x83, 0x4, 0x96, 0x87, 0x11, 0xf7, 0xf4, 0xa5, 0xe4, 0x2c, 0x18, 0x72, 0x9a, 0xe0, 0x23, 0x15, 0x3b, 0x5b, 0xb1, 0x9e, 0x26, 0xc6, 0xe3, 0x15, 0xc3, 0x89, 0xf2, 0x1f, 0xc5, 0x9d, 0x9c}}
+	return a, nil
+}
+
+var __1587500000_drop_envelopes_hash_idxUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xcd\x2b\x4b\xcd\xc9\x2f\x48\x2d\x8e\xcf\x48\x2c\xce\x88\xcf\x4c\xa9\xb0\xe6\x02\x00\x07\x6f\xd9\xe8\x29\x00\x00\x00")
+
+func _1587500000_drop_envelopes_hash_idxUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1587500000_drop_envelopes_hash_idxUpSql,
+		"1587500000_drop_envelopes_hash_idx.up.sql",
+	)
+}
+
+func _1587500000_drop_envelopes_hash_idxUpSql() (*asset, error) {
+	bytes, err := _1587500000_drop_envelopes_hash_idxUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1587500000_drop_envelopes_hash_idx.up.sql", size: 41, mode: os.FileMode(0644), modTime: time.Unix(1792099703, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9, 0xfb, 0x80, 0xb6, 0xdb, 0x4f, 0x17, 0x52, 0x5f, 0x57, 0xd1, 0xf8, 0xe6, 0xae, 0x29, 0x9, 0xfb, 0x91, 0xc0, 0xe4, 0xa, 0x34, 0x3f, 0xfb, 0x4a, 0xaa, 0x44, 0xd5, 0x32, 0x1c, 0xb7, 0x22}}
+	return a, nil
+}
+
+var _staticGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8c\x41\x6a\xc3\x40\x0c\x45\xf7\x73\x8a\xbf\x6c\xa1\x1e\xed\x7b\x82\x52\x12\x08\x24\x17\x90\x6d\x21\x0b\xc7\x33\x46\x52\x72\xfe\x6c\x12\x42\x96\x8f\xc7\x7b\x44\x38\xf1\xb4\xb2\x0a\x22\x39\x6d\x82\x6c\xa3\xcc\xf1\xa2\xaf\xff\xf3\x0f\xfe\x2e\xc7\xc3\x37\x5c\xa2\xdf\x7c\x92\x80\x9b\x2e\x09\x6b\xd9\x91\x8b\x60\xb4\xc6\x6e\x12\x65\xff\x38\x95\x42\xa4\xfd\x57\xa5\x89\x73\x0a\xb4\x0f\xa3\xb5\x99\x93\x31\xec\xab\x62\x33\x75\x4e\xeb\x2d\x30\x74\xd4\x4a\xb5\xd2\xc6\x76\x0d\xf1\xbb\x38\xbd\x35\x3d\xb3\xaa\x1d\xb5\x3c\x06\x00\xf4\xe4\x35\xe2\xb2\x00\x00\x00")
 
 func staticGoBytes() ([]byte, error) {
 	return bindataRead(
@@ -126,7 +630,7 @@ func staticGo() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "static.go", size: 178, mode: os.FileMode(0644), modTime: time.Unix(1574771268, 0)}
+	info := bindataFileInfo{name: "static.go", size: 178, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xab, 0x8a, 0xf4, 0x27, 0x24, 0x9d, 0x2a, 0x1, 0x7b, 0x54, 0xea, 0xae, 0x4a, 0x35, 0x40, 0x92, 0xb5, 0xf9, 0xb3, 0x54, 0x3e, 0x3a, 0x1a, 0x2b, 0xae, 0xfb, 0x9e, 0x82, 0xeb, 0x4c, 0xf, 0x6}}
 	return a, nil
 }
@@ -223,8 +727,58 @@ func AssetNames() []string {
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
 	"1557732988_initialize_db.down.sql": _1557732988_initialize_dbDownSql,
-	"1557732988_initialize_db.up.sql":   _1557732988_initialize_dbUpSql,
-	"static.go":                         staticGo,
+
+	"1557732988_initialize_db.up.sql": _1557732988_initialize_dbUpSql,
+
+	"1581600000_topic_stats.down.sql": _1581600000_topic_statsDownSql,
+
+	"1581600000_topic_stats.up.sql": _1581600000_topic_statsUpSql,
+
+	"1582000000_envelope_sources.down.sql": _1582000000_envelope_sourcesDownSql,
+
+	"1582000000_envelope_sources.up.sql": _1582000000_envelope_sourcesUpSql,
+
+	"1583000000_envelopes_deleted_at.down.sql": _1583000000_envelopes_deleted_atDownSql,
+
+	"1583000000_envelopes_deleted_at.up.sql": _1583000000_envelopes_deleted_atUpSql,
+
+	"1584000000_topic_index.down.sql": _1584000000_topic_indexDownSql,
+
+	"1584000000_topic_index.up.sql": _1584000000_topic_indexUpSql,
+
+	"1585000000_archive_stats.down.sql": _1585000000_archive_statsDownSql,
+
+	"1585000000_archive_stats.up.sql": _1585000000_archive_statsUpSql,
+
+	"1586000000_tenants.down.sql": _1586000000_tenantsDownSql,
+
+	"1586000000_tenants.up.sql": _1586000000_tenantsUpSql,
+
+	"1587000000_coverage_gaps.down.sql": _1587000000_coverage_gapsDownSql,
+
+	"1587000000_coverage_gaps.up.sql": _1587000000_coverage_gapsUpSql,
+
+	"1587100000_coverage_gaps_healed.down.sql": _1587100000_coverage_gaps_healedDownSql,
+
+	"1587100000_coverage_gaps_healed.up.sql": _1587100000_coverage_gaps_healedUpSql,
+
+	"1587200000_bloom_index.down.sql": _1587200000_bloom_indexDownSql,
+
+	"1587200000_bloom_index.up.sql": _1587200000_bloom_indexUpSql,
+
+	"1587300000_tenant_stats.down.sql": _1587300000_tenant_statsDownSql,
+
+	"1587300000_tenant_stats.up.sql": _1587300000_tenant_statsUpSql,
+
+	"1587400000_bloom_index_tenant.down.sql": _1587400000_bloom_index_tenantDownSql,
+
+	"1587400000_bloom_index_tenant.up.sql": _1587400000_bloom_index_tenantUpSql,
+
+	"1587500000_drop_envelopes_hash_idx.down.sql": _1587500000_drop_envelopes_hash_idxDownSql,
+
+	"1587500000_drop_envelopes_hash_idx.up.sql": _1587500000_drop_envelopes_hash_idxUpSql,
+
+	"static.go": staticGo,
 }
 
 // AssetDir returns the file names below a certain
@@ -268,9 +822,33 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"1557732988_initialize_db.down.sql": &bintree{_1557732988_initialize_dbDownSql, map[string]*bintree{}},
-	"1557732988_initialize_db.up.sql":   &bintree{_1557732988_initialize_dbUpSql, map[string]*bintree{}},
-	"static.go":                         &bintree{staticGo, map[string]*bintree{}},
+	"1557732988_initialize_db.down.sql":           &bintree{_1557732988_initialize_dbDownSql, map[string]*bintree{}},
+	"1557732988_initialize_db.up.sql":             &bintree{_1557732988_initialize_dbUpSql, map[string]*bintree{}},
+	"1581600000_topic_stats.down.sql":             &bintree{_1581600000_topic_statsDownSql, map[string]*bintree{}},
+	"1581600000_topic_stats.up.sql":               &bintree{_1581600000_topic_statsUpSql, map[string]*bintree{}},
+	"1582000000_envelope_sources.down.sql":        &bintree{_1582000000_envelope_sourcesDownSql, map[string]*bintree{}},
+	"1582000000_envelope_sources.up.sql":          &bintree{_1582000000_envelope_sourcesUpSql, map[string]*bintree{}},
+	"1583000000_envelopes_deleted_at.down.sql":    &bintree{_1583000000_envelopes_deleted_atDownSql, map[string]*bintree{}},
+	"1583000000_envelopes_deleted_at.up.sql":      &bintree{_1583000000_envelopes_deleted_atUpSql, map[string]*bintree{}},
+	"1584000000_topic_index.down.sql":             &bintree{_1584000000_topic_indexDownSql, map[string]*bintree{}},
+	"1584000000_topic_index.up.sql":               &bintree{_1584000000_topic_indexUpSql, map[string]*bintree{}},
+	"1585000000_archive_stats.down.sql":           &bintree{_1585000000_archive_statsDownSql, map[string]*bintree{}},
+	"1585000000_archive_stats.up.sql":             &bintree{_1585000000_archive_statsUpSql, map[string]*bintree{}},
+	"1586000000_tenants.down.sql":                 &bintree{_1586000000_tenantsDownSql, map[string]*bintree{}},
+	"1586000000_tenants.up.sql":                   &bintree{_1586000000_tenantsUpSql, map[string]*bintree{}},
+	"1587000000_coverage_gaps.down.sql":           &bintree{_1587000000_coverage_gapsDownSql, map[string]*bintree{}},
+	"1587000000_coverage_gaps.up.sql":             &bintree{_1587000000_coverage_gapsUpSql, map[string]*bintree{}},
+	"1587100000_coverage_gaps_healed.down.sql":    &bintree{_1587100000_coverage_gaps_healedDownSql, map[string]*bintree{}},
+	"1587100000_coverage_gaps_healed.up.sql":      &bintree{_1587100000_coverage_gaps_healedUpSql, map[string]*bintree{}},
+	"1587200000_bloom_index.down.sql":             &bintree{_1587200000_bloom_indexDownSql, map[string]*bintree{}},
+	"1587200000_bloom_index.up.sql":               &bintree{_1587200000_bloom_indexUpSql, map[string]*bintree{}},
+	"1587300000_tenant_stats.down.sql":            &bintree{_1587300000_tenant_statsDownSql, map[string]*bintree{}},
+	"1587300000_tenant_stats.up.sql":              &bintree{_1587300000_tenant_statsUpSql, map[string]*bintree{}},
+	"1587400000_bloom_index_tenant.down.sql":      &bintree{_1587400000_bloom_index_tenantDownSql, map[string]*bintree{}},
+	"1587400000_bloom_index_tenant.up.sql":        &bintree{_1587400000_bloom_index_tenantUpSql, map[string]*bintree{}},
+	"1587500000_drop_envelopes_hash_idx.down.sql": &bintree{_1587500000_drop_envelopes_hash_idxDownSql, map[string]*bintree{}},
+	"1587500000_drop_envelopes_hash_idx.up.sql":   &bintree{_1587500000_drop_envelopes_hash_idxUpSql, map[string]*bintree{}},
+	"static.go": &bintree{staticGo, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -0,0 +1,171 @@
+package mailserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// queryCacheKey identifies a page of envelopes returned for a request.
+// It is a hash of the topic set, the time range, the limit, the order and the cursor.
+type queryCacheKey [sha256.Size]byte
+
+func newQueryCacheKey(req MessagesRequestPayload) queryCacheKey {
+	h := sha256.New()
+	var buf [12]byte
+	binary.BigEndian.PutUint32(buf[0:], req.Lower)
+	binary.BigEndian.PutUint32(buf[4:], req.Upper)
+	binary.BigEndian.PutUint32(buf[8:], req.Limit)
+	_, _ = h.Write(buf[:])
+	writeCacheKeyPart(h, req.Bloom)
+
+	// topics are a set, the order doesn't change the result
+	topics := make([][]byte, len(req.Topics))
+	copy(topics, req.Topics)
+	sort.Slice(topics, func(i, j int) bool {
+		return bytes.Compare(topics[i], topics[j]) < 0
+	})
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(topics)))
+	_, _ = h.Write(buf[:4])
+	for _, topic := range topics {
+		writeCacheKeyPart(h, topic)
+	}
+	writeCacheKeyPart(h, []byte{byte(req.Order)})
+	writeCacheKeyPart(h, req.Cursor)
+
+	var key queryCacheKey
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// writeCacheKeyPart writes data prefixed with its length so that
+// different parts can't produce the same key.
+func writeCacheKeyPart(h hash.Hash, data []byte) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+	_, _ = h.Write(size[:])
+	_, _ = h.Write(data)
+}
+
+// queryCacheEntry is a page of envelopes serialized in bundles
+// as they were sent to a peer.
+type queryCacheEntry struct {
+	lower            uint32
+	upper            uint32
+	bundles          [][]rlp.RawValue
+	nextCursor       []byte
+	lastEnvelopeHash types.Hash
+}
+
+// pendingQuery is a request that is being processed. It becomes stale
+// if an envelope within its range is archived or pruned in the meantime.
+type pendingQuery struct {
+	lower uint32
+	upper uint32
+	stale bool
+}
+
+// queryCache is an LRU cache of response pages. Popular topics are requested
+// with the same ranges by many clients after they reconnect, so the pages
+// are served without iterating over the database again.
+type queryCache struct {
+	mu      sync.Mutex
+	entries *simplelru.LRU
+	pending map[*pendingQuery]struct{}
+}
+
+func newQueryCache(size int) (*queryCache, error) {
+	entries, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &queryCache{
+		entries: entries,
+		pending: make(map[*pendingQuery]struct{}),
+	}, nil
+}
+
+// Get returns a cached page.
+func (c *queryCache) Get(key queryCacheKey) (*queryCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, exist := c.entries.Get(key)
+	if !exist {
+		return nil, false
+	}
+	return value.(*queryCacheEntry), true
+}
+
+// Begin registers a request that is about to be processed. The result
+// must be passed to Finish.
+func (c *queryCache) Begin(lower, upper uint32) *pendingQuery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	query := &pendingQuery{lower: lower, upper: upper}
+	c.pending[query] = struct{}{}
+	return query
+}
+
+// Finish stores the page unless the envelopes in its range changed
+// while the request was processed. Nil entry is not stored.
+func (c *queryCache) Finish(query *pendingQuery, key queryCacheKey, entry *queryCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, query)
+	if entry == nil || query.stale {
+		return
+	}
+	entry.lower = query.lower
+	entry.upper = query.upper
+	c.entries.Add(key, entry)
+}
+
+// Archived removes pages with a range that includes the timestamp
+// of a new envelope.
+func (c *queryCache) Archived(timestamp uint32) {
+	c.invalidate(func(lower, upper uint32) bool {
+		return lower <= timestamp && timestamp <= upper
+	})
+}
+
+// Pruned removes pages with a range that includes envelopes older than t.
+func (c *queryCache) Pruned(t time.Time) {
+	timestamp := uint32(t.Unix())
+	c.invalidate(func(lower, _ uint32) bool {
+		return lower < timestamp
+	})
+}
+
+// Restored removes all pages as restored envelopes can belong to any range.
+func (c *queryCache) Restored() {
+	c.invalidate(func(_, _ uint32) bool {
+		return true
+	})
+}
+
+func (c *queryCache) invalidate(affected func(lower, upper uint32) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query := range c.pending {
+		if affected(query.lower, query.upper) {
+			query.stale = true
+		}
+	}
+	for _, key := range c.entries.Keys() {
+		value, _ := c.entries.Peek(key)
+		entry := value.(*queryCacheEntry)
+		if affected(entry.lower, entry.upper) {
+			c.entries.Remove(key)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package mailserver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func TestQueryCacheKey(t *testing.T) {
+	req := MessagesRequestPayload{
+		Lower:  10,
+		Upper:  20,
+		Bloom:  []byte{1},
+		Topics: [][]byte{{1}, {2}},
+		Limit:  100,
+	}
+	key := newQueryCacheKey(req)
+
+	reordered := req
+	reordered.Topics = [][]byte{{2}, {1}}
+	require.Equal(t, key, newQueryCacheKey(reordered))
+	require.Equal(t, [][]byte{{2}, {1}}, reordered.Topics)
+
+	nextPage := req
+	nextPage.Cursor = []byte{1}
+	require.NotEqual(t, key, newQueryCacheKey(nextPage))
+
+	otherLimit := req
+	otherLimit.Limit = 50
+	require.NotEqual(t, key, newQueryCacheKey(otherLimit))
+}
+
+func TestQueryCacheInvalidation(t *testing.T) {
+	cache, err := newQueryCache(10)
+	require.NoError(t, err)
+
+	key := queryCacheKey{1}
+	cache.Finish(cache.Begin(10, 20), key, &queryCacheEntry{})
+	_, exist := cache.Get(key)
+	require.True(t, exist)
+
+	cache.Archived(21)
+	_, exist = cache.Get(key)
+	require.True(t, exist)
+
+	cache.Archived(20)
+	_, exist = cache.Get(key)
+	require.False(t, exist)
+
+	// envelope archived while the request is processed
+	query := cache.Begin(10, 20)
+	cache.Archived(15)
+	cache.Finish(query, key, &queryCacheEntry{})
+	_, exist = cache.Get(key)
+	require.False(t, exist)
+
+	cache.Finish(cache.Begin(10, 20), key, &queryCacheEntry{})
+	cache.Pruned(time.Unix(10, 0))
+	_, exist = cache.Get(key)
+	require.True(t, exist)
+	cache.Pruned(time.Unix(11, 0))
+	_, exist = cache.Get(key)
+	require.False(t, exist)
+}
+
+type queryCacheTestService struct {
+	service
+
+	mu        sync.Mutex
+	envelopes []rlp.RawValue
+	responses int
+}
+
+func (s *queryCacheTestService) MaxMessageSize() uint32 {
+	return 1 << 20
+}
+
+func (s *queryCacheTestService) SendRawP2PDirect(peerID []byte, envelopes ...rlp.RawValue) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.envelopes = append(s.envelopes, envelopes...)
+	return nil
+}
+
+func (s *queryCacheTestService) SendHistoricMessageResponse(peerID []byte, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses++
+	return nil
+}
+
+func (s *queryCacheTestService) reset() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	envelopes, responses := len(s.envelopes), s.responses
+	s.envelopes, s.responses = nil, 0
+	return envelopes, responses
+}
+
+func TestDeliverMailFromQueryCache(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	service := &queryCacheTestService{}
+	server.ms.service = service
+	cache, err := newQueryCache(10)
+	require.NoError(t, err)
+	server.ms.queryCache = cache
+
+	now := time.Now()
+	archiveEnvelope(t, now.Add(-2*time.Second), server)
+	archiveEnvelope(t, now.Add(-time.Second), server)
+
+	req := MessagesRequestPayload{
+		Lower: uint32(now.Add(-time.Minute).Unix()),
+		Upper: uint32(now.Add(time.Minute).Unix()),
+		Bloom: types.MakeFullNodeBloom(),
+		Limit: 10,
+		Batch: true,
+	}
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, req)
+	envelopes, responses := service.reset()
+	require.Equal(t, 2, envelopes)
+	require.Equal(t, 1, responses)
+	_, exist := cache.Get(newQueryCacheKey(req))
+	require.True(t, exist)
+
+	// served from the cache
+	server.ms.DeliverMail(types.Hash{3}, types.Hash{4}, req)
+	envelopes, responses = service.reset()
+	require.Equal(t, 2, envelopes)
+	require.Equal(t, 1, responses)
+
+	// a new envelope within the range invalidates the page
+	archiveEnvelope(t, now, server)
+	_, exist = cache.Get(newQueryCacheKey(req))
+	require.False(t, exist)
+	server.ms.DeliverMail(types.Hash{3}, types.Hash{5}, req)
+	envelopes, _ = service.reset()
+	require.Equal(t, 3, envelopes)
+}
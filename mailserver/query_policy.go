@@ -0,0 +1,120 @@
+package mailserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/params"
+)
+
+var errQueryTierRestricted = errors.New("requested envelopes are available only to allowed peers")
+
+// QueryTier restricts requests for envelopes older than MinAge.
+type QueryTier struct {
+	// Name labels metrics of the tier.
+	Name string
+	// MinAge is the age of the oldest requested envelope from which the tier applies.
+	MinAge time.Duration
+	// MaxQueryLimit lowers the limit of a request if greater than zero.
+	MaxQueryLimit uint32
+	// AllowedPeersOnly rejects requests from peers that are not allowed.
+	AllowedPeersOnly bool
+}
+
+func queryTiers(tiers []params.MailServerQueryTier) []QueryTier {
+	rst := make([]QueryTier, len(tiers))
+	for i, tier := range tiers {
+		rst[i] = QueryTier{
+			Name:             tier.Name,
+			MinAge:           time.Duration(tier.MinAge) * time.Hour,
+			MaxQueryLimit:    tier.MaxQueryLimit,
+			AllowedPeersOnly: tier.AllowedPeersOnly,
+		}
+	}
+	return rst
+}
+
+// queryPolicy selects a tier of a request by the age of the oldest requested envelope.
+// Requests for envelopes newer than every tier are not restricted.
+type queryPolicy struct {
+	// tiers are sorted from the oldest
+	tiers   []QueryTier
+	allowed map[types.Hash]struct{}
+	now     func() time.Time
+}
+
+func newQueryPolicy(tiers []QueryTier, allowedPeers []string) (*queryPolicy, error) {
+	ids, err := parseEnodeIDs(allowedPeers)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryPolicy{
+		tiers:   make([]QueryTier, len(tiers)),
+		allowed: make(map[types.Hash]struct{}, len(ids)),
+		now:     time.Now,
+	}
+	copy(p.tiers, tiers)
+	for i := range p.tiers {
+		if p.tiers[i].MinAge < 0 {
+			return nil, errors.New("query tier age can't be negative")
+		}
+		if p.tiers[i].Name == "" {
+			p.tiers[i].Name = p.tiers[i].MinAge.String()
+		}
+	}
+	sort.SliceStable(p.tiers, func(i, j int) bool { return p.tiers[i].MinAge > p.tiers[j].MinAge })
+	for _, id := range ids {
+		p.allowed[id] = struct{}{}
+	}
+	return p, nil
+}
+
+// tier returns the tier of requests for envelopes since the timestamp, nil if no tier applies.
+func (p *queryPolicy) tier(oldest uint32) *QueryTier {
+	age := p.now().Sub(time.Unix(int64(oldest), 0))
+	for i := range p.tiers {
+		if age >= p.tiers[i].MinAge {
+			return &p.tiers[i]
+		}
+	}
+	return nil
+}
+
+// Apply rejects the request if its tier is restricted to allowed peers and the peer
+// is not allowed, otherwise lowers the limit of the request to the limit of the tier.
+// The request must be validated and have its defaults set.
+func (p *queryPolicy) Apply(peerID types.Hash, req *MessagesRequestPayload) error {
+	tier := p.tier(oldestRequested(req))
+	if tier == nil {
+		return nil
+	}
+	if _, allowed := p.allowed[peerID]; tier.AllowedPeersOnly && !allowed {
+		queryTierRequestsCounter.WithLabelValues(tier.Name, "rejected").Inc()
+		return errQueryTierRestricted
+	}
+	if tier.MaxQueryLimit > 0 && !req.Digest && req.Limit > tier.MaxQueryLimit {
+		req.Limit = tier.MaxQueryLimit
+		queryTierRequestsCounter.WithLabelValues(tier.Name, "limited").Inc()
+		return nil
+	}
+	queryTierRequestsCounter.WithLabelValues(tier.Name, "allowed").Inc()
+	return nil
+}
+
+// oldestRequested returns a timestamp of the oldest envelope the request can return.
+func oldestRequested(req *MessagesRequestPayload) uint32 {
+	if len(req.Keys) == 0 {
+		return req.Lower
+	}
+	oldest := uint32(math.MaxUint32)
+	for _, key := range req.Keys {
+		if timestamp := binary.BigEndian.Uint32(key); timestamp < oldest {
+			oldest = timestamp
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,60 @@
+package mailserver
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func TestQueryPolicy(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	allowed := enode.NewV4(&key.PublicKey, net.ParseIP("127.0.0.1"), 30303, 30303)
+
+	policy, err := newQueryPolicy([]QueryTier{
+		{Name: "old", MinAge: 7 * 24 * time.Hour, AllowedPeersOnly: true},
+		{MinAge: 24 * time.Hour, MaxQueryLimit: 10},
+	}, []string{allowed.URLv4()})
+	require.NoError(t, err)
+	now := time.Unix(1000000000, 0)
+	policy.now = func() time.Time { return now }
+	since := func(age time.Duration) uint32 { return uint32(now.Add(-age).Unix()) }
+
+	// recent envelopes are not restricted
+	req := &MessagesRequestPayload{Lower: since(time.Hour), Limit: 100}
+	require.NoError(t, policy.Apply(types.Hash{1}, req))
+	require.Equal(t, uint32(100), req.Limit)
+
+	req = &MessagesRequestPayload{Lower: since(2 * 24 * time.Hour), Limit: 100}
+	require.NoError(t, policy.Apply(types.Hash{1}, req))
+	require.Equal(t, uint32(10), req.Limit)
+	require.Equal(t, "24h0m0s", policy.tier(req.Lower).Name)
+
+	req = &MessagesRequestPayload{Lower: since(2 * 24 * time.Hour), Limit: 100, Digest: true}
+	require.NoError(t, policy.Apply(types.Hash{1}, req))
+	require.Equal(t, uint32(100), req.Limit)
+
+	req = &MessagesRequestPayload{Lower: since(8 * 24 * time.Hour), Limit: 100}
+	require.Equal(t, errQueryTierRestricted, policy.Apply(types.Hash{1}, req))
+	require.NoError(t, policy.Apply(types.Hash(allowed.ID()), req))
+	require.Equal(t, uint32(100), req.Limit)
+
+	// requests by keys are checked by the oldest key
+	req = &MessagesRequestPayload{Keys: [][]byte{
+		NewDBKey(since(time.Hour), types.TopicType{}, types.Hash{}).Bytes(),
+		NewDBKey(since(8*24*time.Hour), types.TopicType{}, types.Hash{}).Bytes(),
+	}}
+	require.Equal(t, errQueryTierRestricted, policy.Apply(types.Hash{1}, req))
+
+	_, err = newQueryPolicy([]QueryTier{{MinAge: time.Hour}}, []string{"invalid"})
+	require.Error(t, err)
+	_, err = newQueryPolicy([]QueryTier{{MinAge: -time.Hour}}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package mailserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// queryScheduler limits the number of history queries processed at the same time.
+// Queries that can't run are queued per peer and peers are served in turns, so a peer
+// that sends many requests delays requests of other peers by at most one query each.
+type queryScheduler struct {
+	mu sync.Mutex
+
+	limit   int // no limit if zero
+	running int
+	waiting int
+
+	queues map[types.Hash][]chan struct{}
+	// order is a round-robin order of peers with queued queries
+	order []types.Hash
+}
+
+func newQueryScheduler(limit int) *queryScheduler {
+	s := &queryScheduler{
+		limit:  limit,
+		queues: make(map[types.Hash][]chan struct{}),
+	}
+	s.updateMetrics()
+	return s
+}
+
+// Acquire blocks until a query of the peer can run or quit is closed. It returns false
+// if the query was dequeued because of quit. Release must be called only if it returns true.
+func (s *queryScheduler) Acquire(peerID types.Hash, quit <-chan struct{}) bool {
+	s.mu.Lock()
+	if s.limit <= 0 || (s.running < s.limit && s.waiting == 0) {
+		s.running++
+		s.updateMetrics()
+		s.mu.Unlock()
+		return true
+	}
+
+	ready := make(chan struct{})
+	if _, exist := s.queues[peerID]; !exist {
+		s.order = append(s.order, peerID)
+	}
+	s.queues[peerID] = append(s.queues[peerID], ready)
+	s.waiting++
+	s.updateMetrics()
+	s.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-ready:
+		queryQueueWaitDuration.Observe(time.Since(start).Seconds())
+		return true
+	case <-quit:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dequeue(peerID, ready) {
+		// the query was started before the lock was taken, its slot is passed on
+		s.running--
+		s.schedule()
+	}
+	s.updateMetrics()
+	return false
+}
+
+// Release frees the slot of a finished query and starts queued queries.
+func (s *queryScheduler) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running--
+	s.schedule()
+	s.updateMetrics()
+}
+
+// SetLimit changes the number of queries processed at the same time.
+// Running queries are not interrupted if the limit is lowered.
+func (s *queryScheduler) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limit = limit
+	s.schedule()
+	s.updateMetrics()
+}
+
+// Pending returns the number of queued queries and the number of peers they belong to.
+func (s *queryScheduler) Pending() (queries, peers int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiting, len(s.order)
+}
+
+// schedule starts queued queries while there are free slots, taking the oldest
+// query of the next peer in turn. Must be called with the lock held.
+func (s *queryScheduler) schedule() {
+	for len(s.order) > 0 && (s.limit <= 0 || s.running < s.limit) {
+		peerID := s.order[0]
+		s.order = s.order[1:]
+
+		queue := s.queues[peerID]
+		close(queue[0])
+		if len(queue) > 1 {
+			s.queues[peerID] = queue[1:]
+			s.order = append(s.order, peerID)
+		} else {
+			delete(s.queues, peerID)
+		}
+		s.waiting--
+		s.running++
+	}
+}
+
+// dequeue removes a queued query of the peer. It returns false if the query
+// is not queued anymore. Must be called with the lock held.
+func (s *queryScheduler) dequeue(peerID types.Hash, ready chan struct{}) bool {
+	queue := s.queues[peerID]
+	for i, waiter := range queue {
+		if waiter != ready {
+			continue
+		}
+		if len(queue) > 1 {
+			s.queues[peerID] = append(queue[:i:i], queue[i+1:]...)
+		} else {
+			delete(s.queues, peerID)
+			for j, id := range s.order {
+				if id == peerID {
+					s.order = append(s.order[:j:j], s.order[j+1:]...)
+					break
+				}
+			}
+		}
+		s.waiting--
+		return true
+	}
+	return false
+}
+
+func (s *queryScheduler) updateMetrics() {
+	queryQueueDepthGauge.Set(float64(s.waiting))
+	queryQueuePeersGauge.Set(float64(len(s.order)))
+	queriesRunningGauge.Set(float64(s.running))
+}
@@ -0,0 +1,129 @@
+package mailserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func waitPending(t *testing.T, s *queryScheduler, queries int) {
+	require.Eventually(t, func() bool {
+		pending, _ := s.Pending()
+		return pending == queries
+	}, time.Second, time.Millisecond)
+}
+
+func TestQuerySchedulerLimit(t *testing.T) {
+	s := newQueryScheduler(1)
+	s.Acquire(types.Hash{1}, nil)
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire(types.Hash{2}, nil)
+		close(acquired)
+	}()
+	waitPending(t, s, 1)
+
+	select {
+	case <-acquired:
+		t.Fatal("query started above the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.Release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("queued query was not started")
+	}
+	s.Release()
+
+	queries, peers := s.Pending()
+	require.Zero(t, queries)
+	require.Zero(t, peers)
+}
+
+func TestQuerySchedulerRoundRobin(t *testing.T) {
+	s := newQueryScheduler(1)
+	s.Acquire(types.Hash{0xff}, nil)
+
+	started := make(chan types.Hash, 5)
+	queue := func(peerID types.Hash) {
+		go func() {
+			s.Acquire(peerID, nil)
+			started <- peerID
+		}()
+	}
+	// the first peer queues three queries before the second peer queues two
+	for i := 0; i < 3; i++ {
+		queue(types.Hash{1})
+		waitPending(t, s, i+1)
+	}
+	for i := 0; i < 2; i++ {
+		queue(types.Hash{2})
+		waitPending(t, s, i+4)
+	}
+	_, peers := s.Pending()
+	require.Equal(t, 2, peers)
+
+	var order []types.Hash
+	for i := 0; i < 5; i++ {
+		s.Release()
+		order = append(order, <-started)
+	}
+	require.Equal(t, []types.Hash{{1}, {2}, {1}, {2}, {1}}, order)
+}
+
+func TestQuerySchedulerSetLimit(t *testing.T) {
+	s := newQueryScheduler(1)
+	s.Acquire(types.Hash{1}, nil)
+
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			s.Acquire(types.Hash{2}, nil)
+			started <- struct{}{}
+		}()
+	}
+	waitPending(t, s, 2)
+
+	// removing the limit starts all queued queries
+	s.SetLimit(0)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("queued query was not started")
+		}
+	}
+}
+
+func TestQuerySchedulerQuit(t *testing.T) {
+	s := newQueryScheduler(1)
+	s.Acquire(types.Hash{1}, nil)
+
+	quit := make(chan struct{})
+	acquired := make(chan bool)
+	go func() {
+		acquired <- s.Acquire(types.Hash{2}, quit)
+	}()
+	waitPending(t, s, 1)
+
+	close(quit)
+	select {
+	case ok := <-acquired:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("queued query was not canceled")
+	}
+	queries, peers := s.Pending()
+	require.Zero(t, queries)
+	require.Zero(t, peers)
+
+	// the canceled query doesn't take the slot of the running one
+	s.Release()
+	require.True(t, s.Acquire(types.Hash{3}, nil))
+}
@@ -0,0 +1,426 @@
+package mailserver
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	replicationQueueSize   = 1000
+	replicationFlushPeriod = time.Second
+
+	followerCheckPeriod = 10 * time.Second
+	// followerSyncTimeout is how long a follower waits for a sync response page.
+	followerSyncTimeout = time.Minute
+	// followerSyncOverlap is subtracted from the checkpoint to catch up envelopes
+	// with timestamps slightly in the past.
+	followerSyncOverlap = whisperTTLSafeThreshold
+	// followerInitialSyncPeriod is used when a follower starts without a checkpoint
+	// and data retention is not set.
+	followerInitialSyncPeriod = 24 * time.Hour
+	followerCheckpointFile    = "replication_checkpoint"
+
+	// replicationGapPrefix starts an error of a sync response that tells a follower
+	// that envelopes archived since the given timestamp were not delivered to it.
+	replicationGapPrefix = "replication gap since "
+)
+
+func replicationGapError(since uint32) string {
+	return replicationGapPrefix + strconv.FormatUint(uint64(since), 10)
+}
+
+// parseReplicationGap returns a timestamp since which envelopes were not replicated
+// if errMsg reports a replication gap.
+func parseReplicationGap(errMsg string) (uint32, bool) {
+	if !strings.HasPrefix(errMsg, replicationGapPrefix) {
+		return 0, false
+	}
+	since, err := strconv.ParseUint(strings.TrimPrefix(errMsg, replicationGapPrefix), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(since), true
+}
+
+func envelopeTimestamp(env types.Envelope) uint32 {
+	return env.Expiry() - env.TTL()
+}
+
+// parseEnodeIDs returns IDs of peers from enode URLs.
+func parseEnodeIDs(urls []string) ([]types.Hash, error) {
+	ids := make([]types.Hash, 0, len(urls))
+	for _, url := range urls {
+		node, err := enode.ParseV4(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enode %s: %v", url, err)
+		}
+		ids = append(ids, types.Hash(node.ID()))
+	}
+	return ids, nil
+}
+
+// replicator streams archived envelopes to follower mailservers using sync responses.
+// Envelopes are sent to connected followers only, followers that were offline
+// catch up by sending sync requests to the primary.
+//
+// Envelopes that were dropped because the queue was full or that failed to be sent
+// are reported to a follower as a gap with the next response, so that the follower
+// requests them again.
+type replicator struct {
+	adapter adapter
+	service service
+	peers   []types.Hash
+
+	mu sync.Mutex
+	// gaps holds the timestamp of the oldest envelope that was not delivered to a peer.
+	gaps map[types.Hash]uint32
+
+	queue   chan types.Envelope
+	period  time.Duration
+	maxSize uint32
+	cancel  chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newReplicator(adapter adapter, service service, peers []types.Hash) *replicator {
+	return &replicator{
+		adapter: adapter,
+		service: service,
+		peers:   peers,
+		gaps:    make(map[types.Hash]uint32),
+		queue:   make(chan types.Envelope, replicationQueueSize),
+		period:  replicationFlushPeriod,
+		maxSize: service.MaxMessageSize(),
+		cancel:  make(chan struct{}),
+	}
+}
+
+// Start starts a loop that sends queued envelopes.
+func (r *replicator) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.schedule(r.period, r.cancel)
+	}()
+}
+
+// Stop stops the loop. Queued envelopes are not sent.
+func (r *replicator) Stop() {
+	select {
+	case <-r.cancel:
+		return
+	default:
+	}
+	close(r.cancel)
+	r.wg.Wait()
+}
+
+// Add queues an envelope. It never blocks, if the queue is full the envelope is dropped
+// and a gap is reported to all followers.
+func (r *replicator) Add(env types.Envelope) {
+	select {
+	case r.queue <- env:
+	default:
+		log.Debug("replication queue is full, dropping envelope", "hash", env.Hash())
+		r.mu.Lock()
+		for _, peer := range r.peers {
+			r.addGap(peer, envelopeTimestamp(env))
+		}
+		r.mu.Unlock()
+	}
+}
+
+// addGap must be called with the mutex held.
+func (r *replicator) addGap(peer types.Hash, since uint32) {
+	if gap, ok := r.gaps[peer]; !ok || since < gap {
+		r.gaps[peer] = since
+	}
+}
+
+func (r *replicator) hasGaps() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.gaps) > 0
+}
+
+func (r *replicator) schedule(period time.Duration, cancel <-chan struct{}) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	var (
+		batch []types.Envelope
+		size  uint32
+	)
+	for {
+		select {
+		case env := <-r.queue:
+			envSize := uint32(whisper.EnvelopeHeaderLength + env.Size())
+			if len(batch) > 0 && size+envSize > r.maxSize {
+				r.send(batch)
+				batch, size = nil, 0
+			}
+			batch = append(batch, env)
+			size += envSize
+		case <-t.C:
+			// gaps are reported even without new envelopes
+			if len(batch) > 0 || r.hasGaps() {
+				r.send(batch)
+				batch, size = nil, 0
+			}
+		case <-cancel:
+			return
+		}
+	}
+}
+
+func (r *replicator) send(batch []types.Envelope) {
+	resp := r.adapter.CreateSyncResponse(batch, nil, false, "")
+	for _, peer := range r.peers {
+		r.mu.Lock()
+		gap, hasGap := r.gaps[peer]
+		r.mu.Unlock()
+		if !hasGap && len(batch) == 0 {
+			continue
+		}
+		peerResp := resp
+		if hasGap {
+			peerResp = r.adapter.CreateSyncResponse(batch, nil, false, replicationGapError(gap))
+		}
+		err := r.service.SendSyncResponse(peer.Bytes(), peerResp)
+
+		r.mu.Lock()
+		if err != nil {
+			log.Warn("failed to replicate envelopes", "peer", peer, "count", len(batch), "err", err)
+			for _, env := range batch {
+				r.addGap(peer, envelopeTimestamp(env))
+			}
+		} else if hasGap && r.gaps[peer] == gap {
+			delete(r.gaps, peer)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// primaryPeer is a subset of whisper methods used by a follower.
+type primaryPeer interface {
+	AllowP2PMessagesFromPeer(peerID []byte) error
+	SyncMessages(peerID []byte, req whisper.SyncMailRequest) error
+}
+
+// follower accepts envelopes streamed by a primary mailserver and requests envelopes
+// that were missed while the primary was not connected. Envelopes received
+// in sync responses are archived by whisper.
+//
+// Checkpoint is a timestamp up to which the follower has all envelopes of the primary,
+// it is persisted so that gaps are filled after a restart too. While caught up, the checkpoint
+// follows the current time, a gap reported by the primary moves it back and starts a new sync.
+type follower struct {
+	mu sync.Mutex
+
+	primary    primaryPeer
+	peer       types.Hash
+	checkpoint uint32
+	path       string
+
+	connected bool
+	caughtUp  bool
+	syncing   bool
+	// resync is set if a gap was reported while syncing, the finished sync
+	// doesn't cover it.
+	resync    bool
+	request   whisper.SyncMailRequest
+	requested time.Time
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newFollower(primary primaryPeer, peer types.Hash, dataDir string, retention time.Duration) *follower {
+	f := &follower{
+		primary: primary,
+		peer:    peer,
+		path:    filepath.Join(dataDir, followerCheckpointFile),
+		period:  followerCheckPeriod,
+	}
+	checkpoint, err := readFollowerCheckpoint(f.path)
+	if err != nil {
+		log.Warn("failed to read replication checkpoint", "path", f.path, "err", err)
+	}
+	if checkpoint == 0 {
+		if retention == 0 {
+			retention = followerInitialSyncPeriod
+		}
+		checkpoint = uint32(time.Now().Add(-retention).Unix())
+	}
+	f.checkpoint = checkpoint
+	return f
+}
+
+func readFollowerCheckpoint(path string) (uint32, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	checkpoint, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(checkpoint), nil
+}
+
+func (f *follower) saveCheckpoint(checkpoint uint32) {
+	f.checkpoint = checkpoint
+	if err := ioutil.WriteFile(f.path, []byte(strconv.FormatUint(uint64(checkpoint), 10)), 0600); err != nil {
+		log.Error("failed to save replication checkpoint", "path", f.path, "err", err)
+	}
+}
+
+// Start starts a loop that tracks the connection to the primary.
+func (f *follower) Start() {
+	f.cancel = make(chan struct{})
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		t := time.NewTicker(f.period)
+		defer t.Stop()
+		f.Check()
+		for {
+			select {
+			case <-t.C:
+				f.Check()
+			case <-f.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop.
+func (f *follower) Stop() {
+	if f.cancel == nil {
+		return
+	}
+	close(f.cancel)
+	f.wg.Wait()
+	f.cancel = nil
+}
+
+// Check marks the primary as trusted once it is connected and starts
+// a catch up after the primary (re)connects.
+func (f *follower) Check() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// fails if the primary is not connected
+	if err := f.primary.AllowP2PMessagesFromPeer(f.peer.Bytes()); err != nil {
+		if f.connected {
+			log.Info("primary mailserver disconnected", "peer", f.peer)
+		}
+		f.connected = false
+		f.caughtUp = false
+		f.syncing = false
+		return
+	}
+	f.connected = true
+
+	if f.syncing {
+		if time.Since(f.requested) < followerSyncTimeout {
+			return
+		}
+		log.Warn("sync with primary mailserver timed out", "peer", f.peer)
+		f.syncing = false
+	}
+	now := uint32(time.Now().Unix())
+	if f.caughtUp {
+		f.saveCheckpoint(now)
+		return
+	}
+
+	lower := f.checkpoint
+	if lower > followerSyncOverlap {
+		lower -= followerSyncOverlap
+	}
+	f.request = whisper.SyncMailRequest{
+		Lower: lower,
+		Upper: now,
+		Bloom: types.MakeFullNodeBloom(),
+		Limit: whisper.MaxLimitInSyncMailRequest,
+	}
+	f.sendRequest()
+}
+
+// SyncFinished handles the last response to a sync request. If the response has a cursor,
+// the next page is requested.
+func (f *follower) SyncFinished(peer types.Hash, cursor []byte, errMsg string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if peer != f.peer {
+		return
+	}
+	if since, ok := parseReplicationGap(errMsg); ok {
+		f.gapReported(since)
+		return
+	}
+	if !f.syncing {
+		return
+	}
+	f.syncing = false
+	if errMsg != "" {
+		log.Warn("sync with primary mailserver failed", "peer", f.peer, "err", errMsg)
+		return
+	}
+	if len(cursor) > 0 && !bytes.Equal(cursor, f.request.Cursor) {
+		f.request.Cursor = cursor
+		f.sendRequest()
+		return
+	}
+	if f.resync {
+		f.resync = false
+		log.Info("replication gap reported during sync, syncing again", "peer", f.peer, "checkpoint", f.checkpoint)
+		return
+	}
+	log.Info("caught up with primary mailserver", "peer", f.peer, "upper", f.request.Upper)
+	f.caughtUp = true
+	f.saveCheckpoint(f.request.Upper)
+}
+
+// gapReported moves the checkpoint back to the start of the gap. Envelopes are requested again
+// with the next check. Must be called with the mutex held.
+func (f *follower) gapReported(since uint32) {
+	log.Warn("primary mailserver reported a replication gap", "peer", f.peer, "since", since)
+	if since < f.checkpoint {
+		f.saveCheckpoint(since)
+	}
+	f.caughtUp = false
+	if f.syncing {
+		f.resync = true
+	}
+}
+
+func (f *follower) sendRequest() {
+	log.Info("syncing with primary mailserver", "peer", f.peer, "lower", f.request.Lower, "upper", f.request.Upper)
+	if err := f.primary.SyncMessages(f.peer.Bytes(), f.request); err != nil {
+		log.Warn("failed to send sync request to primary mailserver", "peer", f.peer, "err", err)
+		return
+	}
+	f.syncing = true
+	f.requested = time.Now()
+}
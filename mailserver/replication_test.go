@@ -0,0 +1,304 @@
+package mailserver
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+type syncResponse struct {
+	peer []byte
+	resp whisper.SyncResponse
+}
+
+type replicationTestService struct {
+	service
+	maxMessageSize uint32
+	responses      chan syncResponse
+}
+
+func (s replicationTestService) MaxMessageSize() uint32 {
+	return s.maxMessageSize
+}
+
+func (s replicationTestService) SendSyncResponse(peerID []byte, data interface{}) error {
+	s.responses <- syncResponse{peer: peerID, resp: data.(whisper.SyncResponse)}
+	return nil
+}
+
+func TestReplicatorStreamsArchivedEnvelopes(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	service := replicationTestService{maxMessageSize: 1 << 20, responses: make(chan syncResponse, 10)}
+	peers := []types.Hash{{1}, {2}}
+	server.ms.replicator = newReplicator(server.ms.adapter, service, peers)
+	server.ms.replicator.period = 10 * time.Millisecond
+	server.ms.replicator.Start()
+
+	now := time.Now()
+	first := archiveEnvelope(t, now.Add(-time.Second), server)
+	second := archiveEnvelope(t, now, server)
+
+	for _, peer := range peers {
+		select {
+		case rst := <-service.responses:
+			require.Equal(t, peer.Bytes(), rst.peer)
+			require.False(t, rst.resp.Final)
+			require.Len(t, rst.resp.Envelopes, 2)
+			require.Equal(t, first.Hash(), rst.resp.Envelopes[0].Hash())
+			require.Equal(t, second.Hash(), rst.resp.Envelopes[1].Hash())
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for replicated envelopes")
+		}
+	}
+}
+
+func TestReplicatorSplitsBatches(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	service := replicationTestService{
+		// fits a single envelope only
+		maxMessageSize: uint32(whisper.EnvelopeHeaderLength+gethbridge.NewWhisperEnvelope(env).Size()) + 1,
+		responses:      make(chan syncResponse, 10),
+	}
+	server.ms.replicator = newReplicator(server.ms.adapter, service, []types.Hash{{1}})
+	server.ms.replicator.period = 10 * time.Millisecond
+	server.ms.replicator.Start()
+
+	server.Archive(env)
+	archiveEnvelope(t, time.Now(), server)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case rst := <-service.responses:
+			require.Len(t, rst.resp.Envelopes, 1)
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for replicated envelopes")
+		}
+	}
+}
+
+func TestReplicatorReportsGapWhenQueueIsFull(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	service := replicationTestService{maxMessageSize: 1 << 20, responses: make(chan syncResponse, replicationQueueSize)}
+	r := newReplicator(server.ms.adapter, service, []types.Hash{{1}})
+	r.period = 10 * time.Millisecond
+	// the last envelope is dropped without blocking
+	for i := 0; i < replicationQueueSize+1; i++ {
+		r.Add(gethbridge.NewWhisperEnvelope(env))
+	}
+
+	r.Start()
+	defer r.Stop()
+
+	var (
+		replicated int
+		gaps       []string
+	)
+	for replicated < replicationQueueSize || len(gaps) == 0 {
+		select {
+		case rst := <-service.responses:
+			replicated += len(rst.resp.Envelopes)
+			if rst.resp.Error != "" {
+				gaps = append(gaps, rst.resp.Error)
+			}
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for replicated envelopes", "replicated %d", replicated)
+		}
+	}
+	require.Equal(t, replicationQueueSize, replicated)
+	require.Equal(t, []string{replicationGapError(env.Expiry - env.TTL)}, gaps)
+	require.False(t, r.hasGaps())
+}
+
+type failingReplicationTestService struct {
+	replicationTestService
+	failures int
+}
+
+func (s *failingReplicationTestService) SendSyncResponse(peerID []byte, data interface{}) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("write timeout")
+	}
+	return s.replicationTestService.SendSyncResponse(peerID, data)
+}
+
+func TestReplicatorReportsGapAfterFailedSend(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	env, err := generateEnvelope(time.Now())
+	require.NoError(t, err)
+	service := &failingReplicationTestService{
+		replicationTestService: replicationTestService{maxMessageSize: 1 << 20, responses: make(chan syncResponse, 10)},
+		failures:               1,
+	}
+	peer := types.Hash{1}
+	r := newReplicator(server.ms.adapter, service, []types.Hash{peer})
+
+	r.send([]types.Envelope{gethbridge.NewWhisperEnvelope(env)})
+	require.Empty(t, service.responses)
+	require.True(t, r.hasGaps())
+
+	// gap is reported even without new envelopes
+	r.send(nil)
+	rst := <-service.responses
+	require.Empty(t, rst.resp.Envelopes)
+	require.Equal(t, replicationGapError(env.Expiry-env.TTL), rst.resp.Error)
+	require.False(t, r.hasGaps())
+
+	r.send(nil)
+	require.Empty(t, service.responses)
+}
+
+type primaryTestPeer struct {
+	mu        sync.Mutex
+	connected bool
+	requests  []whisper.SyncMailRequest
+}
+
+func (p *primaryTestPeer) AllowP2PMessagesFromPeer(peerID []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.connected {
+		return errors.New("peer not found")
+	}
+	return nil
+}
+
+func (p *primaryTestPeer) SyncMessages(peerID []byte, req whisper.SyncMailRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	return nil
+}
+
+func (p *primaryTestPeer) setConnected(connected bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connected = connected
+}
+
+func TestFollowerCatchUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "follower-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := &primaryTestPeer{}
+	peer := types.Hash{1}
+	f := newFollower(primary, peer, dir, time.Hour)
+	checkpoint := f.checkpoint
+	require.InDelta(t, time.Now().Add(-time.Hour).Unix(), checkpoint, 5)
+
+	// primary is not connected
+	f.Check()
+	require.Empty(t, primary.requests)
+
+	primary.setConnected(true)
+	f.Check()
+	require.Len(t, primary.requests, 1)
+	require.Equal(t, checkpoint-followerSyncOverlap, primary.requests[0].Lower)
+	require.Equal(t, types.MakeFullNodeBloom(), primary.requests[0].Bloom)
+
+	// request is in flight
+	f.Check()
+	require.Len(t, primary.requests, 1)
+
+	// responses from other peers are ignored
+	f.SyncFinished(types.Hash{2}, nil, "")
+	require.False(t, f.caughtUp)
+
+	// next page is requested
+	f.SyncFinished(peer, []byte{1}, "")
+	require.Len(t, primary.requests, 2)
+	require.Equal(t, []byte{1}, primary.requests[1].Cursor)
+
+	f.SyncFinished(peer, nil, "")
+	require.True(t, f.caughtUp)
+	upper := primary.requests[1].Upper
+	require.Equal(t, upper, f.checkpoint)
+
+	// checkpoint is restored after restart
+	restored := newFollower(primary, peer, dir, time.Hour)
+	require.Equal(t, upper, restored.checkpoint)
+
+	// gap after the primary reconnects is requested
+	primary.setConnected(false)
+	f.Check()
+	require.False(t, f.caughtUp)
+	primary.setConnected(true)
+	f.Check()
+	require.Len(t, primary.requests, 3)
+	require.Equal(t, upper-followerSyncOverlap, primary.requests[2].Lower)
+	require.Empty(t, primary.requests[2].Cursor)
+}
+
+func TestFollowerRetriesFailedSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "follower-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := &primaryTestPeer{connected: true}
+	peer := types.Hash{1}
+	f := newFollower(primary, peer, dir, time.Hour)
+
+	f.Check()
+	require.Len(t, primary.requests, 1)
+	f.SyncFinished(peer, nil, "requests per seconds limit exceeded")
+	require.False(t, f.caughtUp)
+
+	f.Check()
+	require.Len(t, primary.requests, 2)
+	require.Equal(t, primary.requests[0].Lower, primary.requests[1].Lower)
+}
+
+func TestFollowerResyncsAfterReplicationGap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "follower-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	primary := &primaryTestPeer{connected: true}
+	peer := types.Hash{1}
+	f := newFollower(primary, peer, dir, time.Hour)
+
+	f.Check()
+	f.SyncFinished(peer, nil, "")
+	require.True(t, f.caughtUp)
+
+	since := f.checkpoint - 100
+	f.SyncFinished(peer, nil, replicationGapError(since))
+	require.False(t, f.caughtUp)
+	require.Equal(t, since, f.checkpoint)
+
+	f.Check()
+	require.Len(t, primary.requests, 2)
+	require.Equal(t, since-followerSyncOverlap, primary.requests[1].Lower)
+
+	// gap reported during a sync isn't covered by it
+	f.SyncFinished(peer, nil, replicationGapError(since-10))
+	f.SyncFinished(peer, nil, "")
+	require.False(t, f.caughtUp)
+	require.Equal(t, since-10, f.checkpoint)
+
+	f.Check()
+	require.Len(t, primary.requests, 3)
+	require.Equal(t, since-10-followerSyncOverlap, primary.requests[2].Lower)
+}
@@ -2,11 +2,32 @@ package mailserver
 
 import (
 	"errors"
+	"io"
 	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
 	maxMessagesRequestPayloadLimit = 1000
+	// maxDigestRequestPayloadLimit is the max number of keys returned for a digest request.
+	// A key is much smaller than an envelope so it's higher than the limit for envelopes.
+	maxDigestRequestPayloadLimit = 10000
+	// maxAckWindow is the max number of batches sent before they are acknowledged.
+	maxAckWindow = 64
+)
+
+// Order is an order of envelopes in a response.
+type Order uint8
+
+const (
+	// OrderDefault is the order of the archive, ascending in LevelDB and descending in Postgres.
+	OrderDefault Order = iota
+	// OrderAscending returns the oldest envelopes first, clients syncing forward from
+	// a checkpoint can apply them as they are received.
+	OrderAscending
+	// OrderDescending returns the newest envelopes first.
+	OrderDescending
 )
 
 // MessagesRequestPayload is a payload sent to the Mail Server.
@@ -25,10 +46,94 @@ type MessagesRequestPayload struct {
 	Cursor []byte
 	// Batch set to true indicates that the client supports batched response.
 	Batch bool
+	// Digest set to true indicates that only keys of matching envelopes should be returned.
+	// The client can compare them with envelopes it already has and request the rest with Keys.
+	Digest bool
+	// Keys is a list of envelope keys returned in a digest. If set, only envelopes
+	// with these keys are returned and the time range and the bloom filter are ignored.
+	Keys [][]byte
+	// Compress set to true indicates that the client accepts batches of envelopes compressed with gzip.
+	Compress bool
+	// AckWindow set to a value greater than zero indicates that the client acknowledges batches.
+	// It is the max number of batches sent before they are acknowledged. If a batch is not
+	// acknowledged in time, the response has a cursor of the last acknowledged batch.
+	AckWindow uint32
+	// Order is an order of envelopes, the cursor continues in the same order.
+	Order Order
+}
+
+// fields returns the fields of the payload in the order they are encoded. Fields after
+// Batch were added later and are optional, they are omitted from the end of the list if
+// they are not set, so that mail servers that don't support them decode the payload.
+func (r *MessagesRequestPayload) fields() []interface{} {
+	return []interface{}{
+		&r.Lower, &r.Upper, &r.Bloom, &r.Topics, &r.Limit, &r.Cursor, &r.Batch,
+		&r.Digest, &r.Keys, &r.Compress, &r.AckWindow, &r.Order,
+	}
+}
+
+// requiredMessagesRequestFields is the number of fields sent by all clients.
+const requiredMessagesRequestFields = 7
+
+// EncodeRLP implements rlp.Encoder. The payload is encoded in the smallest layout
+// that carries all options that are set.
+func (r MessagesRequestPayload) EncodeRLP(w io.Writer) error {
+	fields := r.fields()
+	n := requiredMessagesRequestFields
+	switch {
+	case r.Order != OrderDefault:
+		n = len(fields)
+	case r.AckWindow > 0:
+		n = len(fields) - 1
+	case r.Compress:
+		n = len(fields) - 2
+	case r.Digest || len(r.Keys) > 0:
+		n = len(fields) - 3
+	}
+	return rlp.Encode(w, fields[:n])
+}
+
+// DecodeRLP implements rlp.Decoder. Optional fields missing at the end of the list are left unset.
+func (r *MessagesRequestPayload) DecodeRLP(s *rlp.Stream) error {
+	if _, err := s.List(); err != nil {
+		return err
+	}
+	var payload MessagesRequestPayload
+	for i, field := range payload.fields() {
+		err := s.Decode(field)
+		if err == rlp.EOL && i >= requiredMessagesRequestFields {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.ListEnd(); err != nil {
+		return err
+	}
+	*r = payload
+	return nil
+}
+
+// decodeMessagesRequestPayload decodes a payload sent with or without digest, compression, ack window and order fields.
+func decodeMessagesRequestPayload(data []byte) (MessagesRequestPayload, error) {
+	var payload MessagesRequestPayload
+	err := rlp.DecodeBytes(data, &payload)
+	return payload, err
+}
+
+// EncodeMessagesRequestPayload encodes the payload in the smallest layout that carries all
+// options that are set, so that mail servers that don't support the other options decode it.
+// A request without digests, compression, acknowledged batches and order is decoded
+// by all mail servers.
+func EncodeMessagesRequestPayload(r MessagesRequestPayload) ([]byte, error) {
+	return rlp.EncodeToBytes(r)
 }
 
 func (r *MessagesRequestPayload) SetDefaults() {
-	if r.Limit == 0 {
+	if r.Limit == 0 && r.Digest {
+		r.Limit = maxDigestRequestPayloadLimit
+	} else if r.Limit == 0 {
 		r.Limit = maxQueryLimit
 	}
 
@@ -41,10 +146,33 @@ func (r MessagesRequestPayload) Validate() error {
 	if r.Upper < r.Lower {
 		return errors.New("query range is invalid: lower > upper")
 	}
+	if r.AckWindow > maxAckWindow {
+		return errors.New("ack window exceeds the maximum allowed value")
+	}
+	if r.Order > OrderDescending {
+		return errors.New("order is invalid")
+	}
+	if len(r.Keys) > 0 {
+		if r.Digest {
+			return errors.New("keys can't be requested in a digest")
+		}
+		if len(r.Keys) > maxMessagesRequestPayloadLimit {
+			return errors.New("number of keys exceeds the maximum allowed value")
+		}
+		for _, key := range r.Keys {
+			if len(key) != DBKeyLength {
+				return ErrInvalidByteSize
+			}
+		}
+		return nil
+	}
 	if len(r.Bloom) == 0 {
 		return errors.New("bloom filter is empty")
 	}
-	if r.Limit > maxMessagesRequestPayloadLimit {
+	if r.Digest && r.Limit > maxDigestRequestPayloadLimit {
+		return errors.New("limit exceeds the maximum allowed value")
+	}
+	if !r.Digest && r.Limit > maxMessagesRequestPayloadLimit {
 		return errors.New("limit exceeds the maximum allowed value")
 	}
 	return nil
@@ -0,0 +1,284 @@
+package mailserver
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+func TestDecodeLegacyMessagesRequestPayload(t *testing.T) {
+	// lower, upper, bloom, topics, limit, cursor and batch are sent by all clients
+	legacy := []interface{}{uint32(50), uint32(100), []byte{0x01}, [][]byte{}, uint32(10), []byte{}, true}
+	data, err := rlp.EncodeToBytes(legacy)
+	require.NoError(t, err)
+	payload, err := decodeMessagesRequestPayload(data)
+	require.NoError(t, err)
+	require.Equal(t, uint32(50), payload.Lower)
+	require.Equal(t, uint32(100), payload.Upper)
+	require.Equal(t, uint32(10), payload.Limit)
+	require.True(t, payload.Batch)
+	require.False(t, payload.Digest)
+
+	// digest and keys
+	data, err = rlp.EncodeToBytes(append(legacy, true, [][]byte{}))
+	require.NoError(t, err)
+	payload, err = decodeMessagesRequestPayload(data)
+	require.NoError(t, err)
+	require.True(t, payload.Digest)
+	require.False(t, payload.Compress)
+
+	// compression
+	data, err = rlp.EncodeToBytes(append(legacy, false, [][]byte{}, true))
+	require.NoError(t, err)
+	payload, err = decodeMessagesRequestPayload(data)
+	require.NoError(t, err)
+	require.True(t, payload.Compress)
+	require.Zero(t, payload.AckWindow)
+
+	// ack window
+	data, err = rlp.EncodeToBytes(append(legacy, false, [][]byte{}, true, uint32(4)))
+	require.NoError(t, err)
+	payload, err = decodeMessagesRequestPayload(data)
+	require.NoError(t, err)
+	require.True(t, payload.Compress)
+	require.Equal(t, uint32(4), payload.AckWindow)
+	require.Equal(t, OrderDefault, payload.Order)
+
+	data, err = rlp.EncodeToBytes(MessagesRequestPayload{Lower: 50, Upper: 100, Batch: true, AckWindow: 4, Order: OrderAscending})
+	require.NoError(t, err)
+	payload, err = decodeMessagesRequestPayload(data)
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), payload.AckWindow)
+	require.Equal(t, OrderAscending, payload.Order)
+
+	// required fields can't be omitted and unknown fields are rejected
+	data, err = rlp.EncodeToBytes(legacy[:6])
+	require.NoError(t, err)
+	_, err = decodeMessagesRequestPayload(data)
+	require.Error(t, err)
+	data, err = rlp.EncodeToBytes(append(legacy, false, [][]byte{}, false, uint32(0), uint8(0), uint8(0)))
+	require.NoError(t, err)
+	_, err = decodeMessagesRequestPayload(data)
+	require.Error(t, err)
+}
+
+func TestEncodeMessagesRequestPayload(t *testing.T) {
+	testCases := []struct {
+		name    string
+		payload MessagesRequestPayload
+		fields  int
+	}{
+		{"legacy", MessagesRequestPayload{Lower: 50, Upper: 100, Bloom: []byte{1}, Batch: true}, 7},
+		{"digest", MessagesRequestPayload{Lower: 50, Upper: 100, Bloom: []byte{1}, Digest: true}, 9},
+		{"compress", MessagesRequestPayload{Lower: 50, Upper: 100, Bloom: []byte{1}, Batch: true, Compress: true}, 10},
+		{"ack window", MessagesRequestPayload{Lower: 50, Upper: 100, Bloom: []byte{1}, Batch: true, AckWindow: 4}, 11},
+		{"order", MessagesRequestPayload{Lower: 50, Upper: 100, Bloom: []byte{1}, Batch: true, Order: OrderAscending}, 12},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := EncodeMessagesRequestPayload(tc.payload)
+			require.NoError(t, err)
+			// the smallest layout is used, so that older mail servers decode it strictly
+			var fields []rlp.RawValue
+			require.NoError(t, rlp.DecodeBytes(data, &fields))
+			require.Len(t, fields, tc.fields)
+			payload, err := decodeMessagesRequestPayload(data)
+			require.NoError(t, err)
+			require.Equal(t, tc.payload.Lower, payload.Lower)
+			require.Equal(t, tc.payload.Digest, payload.Digest)
+			require.Equal(t, tc.payload.Compress, payload.Compress)
+			require.Equal(t, tc.payload.AckWindow, payload.AckWindow)
+			require.Equal(t, tc.payload.Order, payload.Order)
+		})
+	}
+}
+
+func TestDeliverMailInOrder(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	service := &digestTestService{}
+	server.ms.service = service
+
+	now := time.Now()
+	var sent []common.Hash
+	for i := 4; i > 0; i-- {
+		sent = append(sent, archiveEnvelope(t, now.Add(-time.Duration(i)*time.Second), server).Hash())
+	}
+
+	received := func() []common.Hash {
+		var rst []common.Hash
+		for _, raw := range service.envelopes {
+			var env whisper.Envelope
+			require.NoError(t, rlp.DecodeBytes(raw, &env))
+			rst = append(rst, env.Hash())
+		}
+		service.envelopes = nil
+		return rst
+	}
+
+	req := MessagesRequestPayload{
+		Lower: uint32(now.Add(-time.Minute).Unix()),
+		Upper: uint32(now.Add(time.Minute).Unix()),
+		Bloom: types.MakeFullNodeBloom(),
+		Limit: 3,
+		Batch: true,
+		Order: OrderDescending,
+	}
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, req)
+	resp := service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.Equal(t, []common.Hash{sent[3], sent[2], sent[1]}, received())
+
+	// the cursor continues in the same order
+	req.Cursor = resp.Cursor
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{3}, req)
+	resp = service.lastResponse(t)
+	require.Nil(t, resp.Cursor)
+	require.Equal(t, []common.Hash{sent[0]}, received())
+
+	req.Order, req.Cursor = OrderAscending, nil
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{4}, req)
+	resp = service.lastResponse(t)
+	require.Equal(t, sent[:3], received())
+	req.Cursor = resp.Cursor
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{5}, req)
+	require.Equal(t, sent[3:], received())
+
+	require.Error(t, MessagesRequestPayload{Bloom: req.Bloom, Order: OrderDescending + 1}.Validate())
+}
+
+func TestValidateKeysRequest(t *testing.T) {
+	key := NewDBKey(1, types.TopicType{1}, types.Hash{1}).Bytes()
+	require.NoError(t, MessagesRequestPayload{Keys: [][]byte{key}}.Validate())
+	require.Equal(t, ErrInvalidByteSize, MessagesRequestPayload{Keys: [][]byte{key[:CursorLength]}}.Validate())
+	require.Error(t, MessagesRequestPayload{Keys: [][]byte{key}, Digest: true}.Validate())
+
+	digest := MessagesRequestPayload{Bloom: types.MakeFullNodeBloom(), Digest: true}
+	digest.SetDefaults()
+	require.Equal(t, uint32(maxDigestRequestPayloadLimit), digest.Limit)
+	require.NoError(t, digest.Validate())
+}
+
+type digestTestService struct {
+	service
+
+	mu        sync.Mutex
+	envelopes []rlp.RawValue
+	responses [][]byte
+	// failures is the number of responses to fail before sending succeeds
+	failures int
+}
+
+func (s *digestTestService) MaxMessageSize() uint32 {
+	return 1 << 20
+}
+
+func (s *digestTestService) SendRawP2PDirect(peerID []byte, envelopes ...rlp.RawValue) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.envelopes = append(s.envelopes, envelopes...)
+	return nil
+}
+
+func (s *digestTestService) SendHistoricMessageResponse(peerID []byte, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("response is too large")
+	}
+	s.responses = append(s.responses, payload)
+	return nil
+}
+
+func (s *digestTestService) lastResponse(t *testing.T) *whisper.MailServerResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.NotEmpty(t, s.responses)
+	event, err := whisper.CreateMailServerEvent(enode.ID{}, s.responses[len(s.responses)-1])
+	require.NoError(t, err)
+	return event.Data.(*whisper.MailServerResponse)
+}
+
+func TestDeliverDigestAndKeys(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	service := &digestTestService{}
+	server.ms.service = service
+
+	now := time.Now()
+	var sent []*whisper.Envelope
+	for i := 3; i > 0; i-- {
+		sent = append(sent, archiveEnvelope(t, now.Add(-time.Duration(i)*time.Second), server))
+	}
+
+	req := MessagesRequestPayload{
+		Lower:  uint32(now.Add(-time.Minute).Unix()),
+		Upper:  uint32(now.Add(time.Minute).Unix()),
+		Bloom:  types.MakeFullNodeBloom(),
+		Limit:  2,
+		Digest: true,
+	}
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, req)
+	require.Empty(t, service.envelopes)
+	resp := service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Digest, 2)
+	require.Equal(t, resp.Digest[1][:CursorLength], resp.Cursor)
+
+	req.Cursor = resp.Cursor
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{3}, req)
+	resp = service.lastResponse(t)
+	require.Len(t, resp.Digest, 1)
+	require.Nil(t, resp.Cursor)
+
+	// request only one envelope and a key of a missing envelope
+	known := (&DBKey{raw: resp.Digest[0]}).EnvelopeHash()
+	missing := NewDBKey(uint32(now.Unix()), types.TopicType{}, types.Hash{0xff}).Bytes()
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{4}, MessagesRequestPayload{
+		Keys:  [][]byte{resp.Digest[0], missing},
+		Batch: true,
+	})
+	require.Len(t, service.envelopes, 1)
+	var received whisper.Envelope
+	require.NoError(t, rlp.DecodeBytes(service.envelopes[0], &received))
+	require.Equal(t, common.Hash(known), received.Hash())
+	resp = service.lastResponse(t)
+	require.NoError(t, resp.Error)
+	require.Equal(t, common.Hash(known), resp.LastEnvelopeHash)
+	require.Contains(t, []common.Hash{sent[0].Hash(), sent[1].Hash(), sent[2].Hash()}, received.Hash())
+}
+
+func TestDeliverDigestReportsFailures(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	service := &digestTestService{failures: 1}
+	server.ms.service = service
+
+	now := time.Now()
+	archiveEnvelope(t, now.Add(-time.Second), server)
+
+	server.ms.DeliverMail(types.Hash{1}, types.Hash{2}, MessagesRequestPayload{
+		Lower:  uint32(now.Add(-time.Minute).Unix()),
+		Upper:  uint32(now.Add(time.Minute).Unix()),
+		Bloom:  types.MakeFullNodeBloom(),
+		Limit:  2,
+		Digest: true,
+	})
+	// the digest was not sent, so the request must fail instead of timing out
+	require.Len(t, service.responses, 1)
+	require.Error(t, service.lastResponse(t).Error)
+}
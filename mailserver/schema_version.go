@@ -0,0 +1,62 @@
+package mailserver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/status-im/status-go/mailserver/migrations"
+)
+
+// levelDBSchemaVersion is the version of the layout of LevelDB keys. It must be increased
+// when keys are changed in a way that older versions can't read.
+const levelDBSchemaVersion = 1
+
+// ErrSchemaTooNew is returned if the database was migrated by a newer version of the mailserver.
+var ErrSchemaTooNew = errors.New("database schema is newer than supported")
+
+// ErrSchemaDirty is returned if the last migration of the database failed.
+var ErrSchemaDirty = errors.New("database schema is dirty, the last migration failed")
+
+// SchemaVersion is the version of the database schema. Mailservers of different versions
+// can share a database as long as its schema is not newer than expected by any of them.
+type SchemaVersion struct {
+	// Current is the version of the database schema
+	Current uint64 `json:"current"`
+	// Expected is the latest version supported by the mailserver
+	Expected uint64 `json:"expected"`
+	// Dirty is true if the last migration failed and the schema must be fixed manually
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// Check returns an error if the mailserver can't use the database.
+func (v SchemaVersion) Check() error {
+	if v.Dirty {
+		return fmt.Errorf("%v: version %d", ErrSchemaDirty, v.Current)
+	}
+	if v.Current > v.Expected {
+		return fmt.Errorf("%v: version %d, supported %d", ErrSchemaTooNew, v.Current, v.Expected)
+	}
+	return nil
+}
+
+// merge combines versions of shards. The schema is as new as the newest shard.
+func (v *SchemaVersion) merge(other SchemaVersion) {
+	if other.Current > v.Current {
+		v.Current = other.Current
+	}
+	v.Dirty = v.Dirty || other.Dirty
+}
+
+// postgresSchemaVersion returns the version of the latest migration, names of migrations start with a version.
+func postgresSchemaVersion() uint64 {
+	var latest uint64
+	for _, name := range migrations.AssetNames() {
+		version, err := strconv.ParseUint(strings.SplitN(name, "_", 2)[0], 10, 64)
+		if err == nil && version > latest {
+			latest = version
+		}
+	}
+	return latest
+}
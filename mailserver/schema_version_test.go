@@ -0,0 +1,32 @@
+package mailserver
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/mailserver/migrations"
+)
+
+func TestSchemaVersionCheck(t *testing.T) {
+	require.NoError(t, SchemaVersion{Current: 0, Expected: 2}.Check())
+	require.NoError(t, SchemaVersion{Current: 2, Expected: 2}.Check())
+	require.Contains(t, SchemaVersion{Current: 3, Expected: 2}.Check().Error(), ErrSchemaTooNew.Error())
+	require.Contains(t, SchemaVersion{Current: 2, Expected: 2, Dirty: true}.Check().Error(), ErrSchemaDirty.Error())
+
+	version := SchemaVersion{Current: 1, Expected: 2}
+	version.merge(SchemaVersion{Current: 3, Expected: 2, Dirty: true})
+	require.Equal(t, SchemaVersion{Current: 3, Expected: 2, Dirty: true}, version)
+
+	// migrations are named by unix timestamps of the same length, so the latest sorts last
+	var latest string
+	for _, name := range migrations.AssetNames() {
+		if strings.HasSuffix(name, ".up.sql") && name > latest {
+			latest = name
+		}
+	}
+	require.NotEmpty(t, latest)
+	require.Equal(t, strings.SplitN(latest, "_", 2)[0], strconv.FormatUint(postgresSchemaVersion(), 10))
+}
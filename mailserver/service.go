@@ -0,0 +1,85 @@
+package mailserver
+
+import (
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/status-im/status-go/params"
+)
+
+// serverProvider is implemented by WhisperMailServer and WakuMailServer.
+type serverProvider interface {
+	server() *mailServer
+	// config returns the mailserver section of the node config.
+	config(*params.NodeConfig) Config
+}
+
+func (s *WhisperMailServer) server() *mailServer {
+	return s.ms
+}
+
+func (s *WhisperMailServer) config(cfg *params.NodeConfig) Config {
+	return whisperMailServerConfig(&cfg.WhisperConfig)
+}
+
+func (s *WakuMailServer) server() *mailServer {
+	return s.ms
+}
+
+func (s *WakuMailServer) config(cfg *params.NodeConfig) Config {
+	return wakuMailServerConfig(&cfg.WakuConfig)
+}
+
+// Service exposes the mailserver API as a node service.
+type Service struct {
+	provider serverProvider
+}
+
+// NewService returns a new Service for a Whisper or Waku mailserver.
+func NewService(provider serverProvider) *Service {
+	return &Service{provider: provider}
+}
+
+// Reload applies the mailserver section of the node config without a restart
+// and returns applied changes. Only the rate limit, the data retention, the soft
+// delete window and response limits can be changed.
+func (s *Service) Reload(cfg *params.NodeConfig) ([]string, error) {
+	server, err := serverFrom(s.provider)
+	if err != nil {
+		return nil, err
+	}
+	return server.reload(s.provider.config(cfg)), nil
+}
+
+// Protocols returns a new protocols list. In this case, there are none.
+func (s *Service) Protocols() []p2p.Protocol {
+	return nil
+}
+
+// APIs returns a list of new APIs.
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "mailserver",
+			Version:   "1.0",
+			Service:   NewPublicAPI(s.provider),
+			Public:    true,
+		},
+		{
+			Namespace: "mailserver",
+			Version:   "1.0",
+			Service:   NewAdminAPI(s.provider),
+			Public:    false,
+		},
+	}
+}
+
+// Start is run when a service is started.
+func (s *Service) Start(*p2p.Server) error {
+	return nil
+}
+
+// Stop is run when a service is stopped.
+func (s *Service) Stop() error {
+	return nil
+}
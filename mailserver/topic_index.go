@@ -0,0 +1,316 @@
+package mailserver
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+const (
+	topicIndexBucket = uint32(time.Hour / time.Second)
+	// topicIndexMaxTopics is a maximum number of candidate topics used instead of a bloom filter.
+	// Wide bloom filters match too many topics to make the index useful.
+	topicIndexMaxTopics = 1000
+	// topicIndexRecentBuckets is a number of recent buckets which topics are kept in memory.
+	topicIndexRecentBuckets = 24
+)
+
+// topicIndexBucketFor returns a beginning of the hourly bucket for an envelope timestamp.
+func topicIndexBucketFor(timestamp uint32) uint32 {
+	return timestamp - timestamp%topicIndexBucket
+}
+
+// topicIndex maintains a side index of topics present in hourly buckets of envelope timestamps.
+// A request with a bloom filter is translated to topics that are present in the requested range
+// and match the bloom filter, so the database selects envelopes by topics instead of
+// testing the bloom filter of every envelope in the range.
+//
+// The index is complete starting from its first bucket. Envelopes archived before
+// the index was created are not indexed and requests for them use the bloom filter
+// until they are indexed by Backfill.
+type topicIndex struct {
+	mu sync.RWMutex
+
+	db DB
+	// start is the first bucket the index is complete for
+	start uint32
+	// recent holds topics of recent buckets, a topic is true once it is stored
+	recent map[uint32]map[types.TopicType]bool
+	// pending is a number of topics that are not stored yet
+	pending int
+	// backfilling is a bucket before start that is being indexed by Backfill,
+	// envelopes archived in the meantime are indexed as if it was already in the index
+	backfilling   uint32
+	isBackfilling bool
+}
+
+// newTopicIndex reads the first bucket of the index. If the index is empty,
+// it is complete starting from the next bucket.
+func newTopicIndex(db DB, now time.Time) (*topicIndex, error) {
+	start, err := db.TopicIndexStart()
+	if err != nil {
+		return nil, err
+	}
+	if start == 0 {
+		start = topicIndexBucketFor(uint32(now.Unix())) + topicIndexBucket
+	}
+	return &topicIndex{
+		db:     db,
+		start:  start,
+		recent: make(map[uint32]map[types.TopicType]bool),
+	}, nil
+}
+
+// Add indexes an archived envelope. A topic is stored once per bucket.
+// If it can't be stored, it is kept in memory and stored with the next envelope.
+func (i *topicIndex) Add(env types.Envelope) {
+	i.add(env.Expiry()-env.TTL(), env.Topic())
+}
+
+func (i *topicIndex) add(timestamp uint32, topic types.TopicType) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	bucket := topicIndexBucketFor(timestamp)
+	if bucket < i.start && !(i.isBackfilling && bucket == i.backfilling) {
+		return
+	}
+	topics, ok := i.recent[bucket]
+	if !ok {
+		topics = make(map[types.TopicType]bool)
+		i.recent[bucket] = topics
+	}
+	if _, ok := topics[topic]; !ok {
+		topics[topic] = false
+		i.pending++
+	}
+	if i.pending == 0 {
+		return
+	}
+	if err := i.flush(); err != nil {
+		log.Error("failed to store topic index", "err", err)
+	}
+	i.trim(bucket)
+}
+
+// flush stores topics that are not stored yet. Must be called with the lock held.
+func (i *topicIndex) flush() error {
+	for bucket, topics := range i.recent {
+		var pending []types.TopicType
+		for topic, stored := range topics {
+			if !stored {
+				pending = append(pending, topic)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		if err := i.db.SaveTopicIndex(bucket, pending); err != nil {
+			return err
+		}
+		for _, topic := range pending {
+			topics[topic] = true
+		}
+		i.pending -= len(pending)
+	}
+	return nil
+}
+
+// trim forgets stored buckets that are older than the recent ones.
+// Must be called with the lock held.
+func (i *topicIndex) trim(latest uint32) {
+	if len(i.recent) <= topicIndexRecentBuckets {
+		return
+	}
+	for bucket, topics := range i.recent {
+		if bucket+topicIndexRecentBuckets*topicIndexBucket > latest {
+			continue
+		}
+		stored := true
+		for _, s := range topics {
+			stored = stored && s
+		}
+		if stored {
+			delete(i.recent, bucket)
+		}
+	}
+}
+
+// Candidates returns topics present between lower and upper timestamps that match the bloom filter.
+// False is returned if the index can't be used for the range or too many topics match.
+func (i *topicIndex) Candidates(lower, upper uint32, bloom []byte) ([][]byte, bool, error) {
+	i.mu.RLock()
+	start := i.start
+	i.mu.RUnlock()
+	if len(bloom) == 0 || lower < start {
+		return nil, false, nil
+	}
+
+	from, to := topicIndexBucketFor(lower), topicIndexBucketFor(upper)
+	indexed, err := i.db.IndexedTopics(from, to)
+	if err != nil {
+		return nil, false, err
+	}
+
+	i.mu.RLock()
+	for bucket, topics := range i.recent {
+		if bucket < from || bucket > to {
+			continue
+		}
+		for topic := range topics {
+			indexed = append(indexed, topic)
+		}
+	}
+	i.mu.RUnlock()
+
+	seen := make(map[types.TopicType]bool, len(indexed))
+	rst := [][]byte{}
+	for _, topic := range indexed {
+		if seen[topic] || !types.BloomFilterMatch(bloom, types.TopicToBloom(topic)) {
+			continue
+		}
+		seen[topic] = true
+		rst = append(rst, topicToByte(topic))
+		if len(rst) > topicIndexMaxTopics {
+			return nil, false, nil
+		}
+	}
+	return rst, true, nil
+}
+
+// Pruned removes buckets of envelopes older than a given time. The bucket
+// of the time itself is kept as it may still contain envelopes.
+func (i *topicIndex) Pruned(t time.Time) {
+	bucket := topicIndexBucketFor(uint32(t.Unix()))
+	i.mu.Lock()
+	if bucket > i.start {
+		i.start = bucket
+	}
+	for b, topics := range i.recent {
+		if b < bucket {
+			for _, stored := range topics {
+				if !stored {
+					i.pending--
+				}
+			}
+			delete(i.recent, b)
+		}
+	}
+	i.mu.Unlock()
+	if err := i.db.PruneTopicIndex(bucket); err != nil {
+		log.Error("failed to prune topic index", "err", err)
+	}
+}
+
+// Backfill indexes envelopes archived before the first bucket of the index, from the newest
+// bucket to the bucket of the oldest archived envelope. The first bucket is moved back after
+// every indexed bucket, so an interrupted backfill continues where it stopped. A bucket
+// without envelopes is stored with an empty topic to keep the progress.
+func (i *topicIndex) Backfill(ctx context.Context, progress func(float64)) error {
+	stats, err := i.db.ArchiveStats()
+	if err != nil {
+		return err
+	}
+	if len(stats.Oldest) < timestampLength {
+		return nil
+	}
+	oldest := topicIndexBucketFor(binary.BigEndian.Uint32(stats.Oldest))
+
+	i.mu.RLock()
+	start := i.start
+	i.mu.RUnlock()
+	if start <= oldest {
+		return nil
+	}
+	total := float64((start - oldest) / topicIndexBucket)
+	defer func() {
+		i.mu.Lock()
+		i.isBackfilling = false
+		i.mu.Unlock()
+	}()
+	for bucket := start - topicIndexBucket; bucket >= oldest; bucket -= topicIndexBucket {
+		if !i.startBackfilling(bucket) {
+			// the index was pruned in the meantime
+			return nil
+		}
+		topics, err := i.bucketTopics(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			topics = []types.TopicType{{}}
+		}
+		if ok, err := i.extend(bucket, topics); err != nil || !ok {
+			return err
+		}
+		progress(float64((start-bucket)/topicIndexBucket) / total)
+		if bucket < topicIndexBucket {
+			break
+		}
+	}
+	return nil
+}
+
+// bucketTopics returns distinct topics of envelopes archived in the bucket.
+func (i *topicIndex) bucketTopics(ctx context.Context, bucket uint32) ([]types.TopicType, error) {
+	iter, err := i.db.BuildIterator(ctx, CursorQuery{
+		start: NewDBKey(bucket, types.TopicType{}, types.Hash{}).Bytes(),
+		end:   NewDBKey(bucket+topicIndexBucket, types.TopicType{}, types.Hash{}).Bytes(),
+		bloom: types.MakeFullNodeBloom(),
+		limit: math.MaxUint32,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = iter.Release() }()
+
+	seen := make(map[types.TopicType]bool)
+	var rst []types.TopicType
+	for iter.Next() {
+		key, err := iter.DBKey()
+		if err != nil {
+			return nil, err
+		}
+		topic := key.Topic()
+		if !seen[topic] {
+			seen[topic] = true
+			rst = append(rst, topic)
+		}
+	}
+	return rst, iter.Error()
+}
+
+// startBackfilling marks the bucket right before the first one as being indexed.
+// False is returned if the first bucket was moved by pruning.
+func (i *topicIndex) startBackfilling(bucket uint32) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if bucket+topicIndexBucket != i.start {
+		return false
+	}
+	i.backfilling = bucket
+	i.isBackfilling = true
+	return true
+}
+
+// extend stores topics of the backfilled bucket and makes it the first bucket.
+// False is returned if the first bucket was moved by pruning in the meantime.
+func (i *topicIndex) extend(bucket uint32, topics []types.TopicType) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if bucket+topicIndexBucket != i.start {
+		return false, nil
+	}
+	if err := i.db.SaveTopicIndex(bucket, topics); err != nil {
+		return false, err
+	}
+	i.start = bucket
+	i.isBackfilling = false
+	return true, nil
+}
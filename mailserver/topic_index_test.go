@@ -0,0 +1,162 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// failingTopicIndexDB fails to store the topic index.
+type failingTopicIndexDB struct {
+	*LevelDB
+	fail bool
+}
+
+func (db *failingTopicIndexDB) SaveTopicIndex(bucket uint32, topics []types.TopicType) error {
+	if db.fail {
+		return errors.New("failed")
+	}
+	return db.LevelDB.SaveTopicIndex(bucket, topics)
+}
+
+func TestTopicIndexCandidates(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	index, err := newTopicIndex(db, now)
+	require.NoError(t, err)
+	start := topicIndexBucketFor(uint32(now.Unix())) + topicIndexBucket
+	require.Equal(t, start, index.start)
+
+	first := types.TopicType{1, 1, 1, 1}
+	second := types.TopicType{2, 2, 2, 2}
+	// envelopes from before the start are not indexed
+	index.add(start-1, first)
+	index.add(start+10, first)
+	index.add(start+topicIndexBucket*2, second)
+
+	// requests for envelopes before the start use the bloom filter
+	_, ok, err := index.Candidates(start-1, start+10, types.TopicToBloom(first))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	topics, ok, err := index.Candidates(start, start+10, types.TopicToBloom(first))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, [][]byte{first[:]}, topics)
+
+	// no topics in the range match the bloom filter
+	topics, ok, err = index.Candidates(start, start+10, types.TopicToBloom(second))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, topics)
+
+	// the index is read from the db by a new instance
+	index, err = newTopicIndex(db, now.Add(10*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, start, index.start)
+	topics, ok, err = index.Candidates(start, start+topicIndexBucket*3, types.MakeFullNodeBloom())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, topics, 2)
+}
+
+func TestTopicIndexStoresPendingTopics(t *testing.T) {
+	db := &failingTopicIndexDB{LevelDB: setupTopicStatsDB(t), fail: true}
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	index, err := newTopicIndex(db, now)
+	require.NoError(t, err)
+
+	first := types.TopicType{1, 1, 1, 1}
+	second := types.TopicType{2, 2, 2, 2}
+	index.add(index.start, first)
+	require.Equal(t, 1, index.pending)
+	// topics that are not stored yet are still candidates
+	topics, ok, err := index.Candidates(index.start, index.start+10, types.TopicToBloom(first))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, [][]byte{first[:]}, topics)
+
+	db.fail = false
+	index.add(index.start, second)
+	require.Equal(t, 0, index.pending)
+	stored, err := db.IndexedTopics(index.start, index.start)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.TopicType{first, second}, stored)
+}
+
+func TestTopicIndexPruned(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	index, err := newTopicIndex(db, now)
+	require.NoError(t, err)
+	start := index.start
+
+	topic := types.TopicType{1, 1, 1, 1}
+	index.add(start+10, topic)
+	index.add(start+topicIndexBucket+10, topic)
+
+	index.Pruned(time.Unix(int64(start+topicIndexBucket+5), 0))
+	require.Equal(t, start+topicIndexBucket, index.start)
+	first, err := db.TopicIndexStart()
+	require.NoError(t, err)
+	require.Equal(t, start+topicIndexBucket, first)
+
+	_, ok, err := index.Candidates(start+10, start+topicIndexBucket+10, types.TopicToBloom(topic))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestTopicIndexBackfill(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+
+	now := time.Unix(1588000000, 0)
+	for _, sent := range []time.Time{now.Add(-5 * time.Hour), now.Add(-3 * time.Hour)} {
+		env, err := generateEnvelope(sent)
+		require.NoError(t, err)
+		require.NoError(t, db.SaveEnvelope(gethbridge.NewWhisperEnvelope(env)))
+	}
+	topic := types.TopicType{0x1F, 0x7E, 0xA1, 0x7F}
+
+	index, err := newTopicIndex(db, now)
+	require.NoError(t, err)
+	oldest := topicIndexBucketFor(uint32(now.Add(-5 * time.Hour).Unix()))
+	_, ok, err := index.Candidates(oldest, uint32(now.Unix()), types.TopicToBloom(topic))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var progress []float64
+	require.NoError(t, index.Backfill(context.Background(), func(p float64) {
+		progress = append(progress, p)
+	}))
+	require.Equal(t, oldest, index.start)
+	require.Len(t, progress, 6)
+	require.Equal(t, float64(1), progress[len(progress)-1])
+
+	topics, ok, err := index.Candidates(oldest, uint32(now.Unix()), types.TopicToBloom(topic))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, [][]byte{topic[:]}, topics)
+
+	// the start is stored, a new instance doesn't backfill again
+	index, err = newTopicIndex(db, now)
+	require.NoError(t, err)
+	require.Equal(t, oldest, index.start)
+	progress = nil
+	require.NoError(t, index.Backfill(context.Background(), func(p float64) {
+		progress = append(progress, p)
+	}))
+	require.Empty(t, progress)
+}
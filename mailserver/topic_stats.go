@@ -0,0 +1,163 @@
+package mailserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	topicStatsBucket        = time.Hour
+	topicStatsFlushPeriod   = time.Minute
+	topicStatsRetentionTime = 30 * 24 * time.Hour
+)
+
+// TopicStats is a number of envelopes and their total size received for a topic.
+type TopicStats struct {
+	Topic     types.TopicType `json:"topic"`
+	Envelopes uint64          `json:"envelopes"`
+	Bytes     uint64          `json:"bytes"`
+}
+
+// topicStatsBucketFor returns a beginning of the hourly bucket for a given time.
+func topicStatsBucketFor(t time.Time) uint32 {
+	return uint32(t.Truncate(topicStatsBucket).Unix())
+}
+
+// sortTopicStats sorts stats by the number of envelopes and then by size in a descending order.
+func sortTopicStats(stats []TopicStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Envelopes == stats[j].Envelopes {
+			return stats[i].Bytes > stats[j].Bytes
+		}
+		return stats[i].Envelopes > stats[j].Envelopes
+	})
+}
+
+// topicStatsCollector counts archived envelopes per topic in memory
+// and periodically flushes the counters to the db rolled up by hour.
+type topicStatsCollector struct {
+	sync.Mutex
+
+	db      DB
+	pending map[uint32]map[types.TopicType]*TopicStats
+
+	period    time.Duration
+	retention time.Duration
+	cancel    chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newTopicStatsCollector(db DB) *topicStatsCollector {
+	return &topicStatsCollector{
+		db:        db,
+		pending:   make(map[uint32]map[types.TopicType]*TopicStats),
+		period:    topicStatsFlushPeriod,
+		retention: topicStatsRetentionTime,
+	}
+}
+
+// Start starts a loop that flushes counters.
+func (c *topicStatsCollector) Start() {
+	c.cancel = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.schedule(c.period, c.cancel)
+	}()
+}
+
+// Stop stops the flushing loop and persists pending counters.
+func (c *topicStatsCollector) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	close(c.cancel)
+	c.wg.Wait()
+	c.cancel = nil
+	if err := c.Flush(); err != nil {
+		log.Error("failed to flush topic stats", "err", err)
+	}
+}
+
+func (c *topicStatsCollector) schedule(period time.Duration, cancel <-chan struct{}) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := c.Flush(); err != nil {
+				log.Error("failed to flush topic stats", "err", err)
+			}
+			if err := c.db.PruneTopicStats(time.Now().Add(-c.retention)); err != nil {
+				log.Error("failed to prune topic stats", "err", err)
+			}
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// Add counts an archived envelope.
+func (c *topicStatsCollector) Add(env types.Envelope) {
+	c.add(time.Now(), env.Topic(), uint64(whisper.EnvelopeHeaderLength+env.Size()))
+}
+
+func (c *topicStatsCollector) add(t time.Time, topic types.TopicType, size uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	bucket := topicStatsBucketFor(t)
+	stats, ok := c.pending[bucket]
+	if !ok {
+		stats = make(map[types.TopicType]*TopicStats)
+		c.pending[bucket] = stats
+	}
+	stat, ok := stats[topic]
+	if !ok {
+		stat = &TopicStats{Topic: topic}
+		stats[topic] = stat
+	}
+	stat.Envelopes++
+	stat.Bytes += size
+}
+
+// Flush persists pending counters.
+func (c *topicStatsCollector) Flush() error {
+	c.Lock()
+	defer c.Unlock()
+
+	for bucket, stats := range c.pending {
+		rst := make([]TopicStats, 0, len(stats))
+		for _, stat := range stats {
+			rst = append(rst, *stat)
+		}
+		if err := c.db.SaveTopicStats(bucket, rst); err != nil {
+			return err
+		}
+		delete(c.pending, bucket)
+	}
+	return nil
+}
+
+// TopTopics returns n topics with the highest number of envelopes received since a given time.
+func (c *topicStatsCollector) TopTopics(n int, since time.Time) ([]TopicStats, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	stats, err := c.db.TopicStats(topicStatsBucketFor(since))
+	if err != nil {
+		return nil, err
+	}
+	sortTopicStats(stats)
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats, nil
+}
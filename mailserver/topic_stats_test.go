@@ -0,0 +1,81 @@
+package mailserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+func setupTopicStatsDB(t *testing.T) *LevelDB {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	require.NoError(t, err)
+	return &LevelDB{ldb: db}
+}
+
+func TestTopicStatsTopTopics(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+	collector := newTopicStatsCollector(db)
+
+	now := time.Now()
+	first := types.TopicType{1}
+	second := types.TopicType{2}
+	third := types.TopicType{3}
+
+	collector.add(now, first, 10)
+	collector.add(now, second, 10)
+	collector.add(now, second, 10)
+	collector.add(now, third, 30)
+	collector.add(now.Add(-2*time.Hour), first, 10)
+	collector.add(now.Add(-2*time.Hour), first, 10)
+	require.NoError(t, collector.Flush())
+	// counters are accumulated with the stored ones
+	collector.add(now, third, 30)
+
+	stats, err := collector.TopTopics(2, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []TopicStats{
+		{Topic: third, Envelopes: 2, Bytes: 60},
+		{Topic: second, Envelopes: 2, Bytes: 20},
+	}, stats)
+
+	stats, err = collector.TopTopics(3, now.Add(-3*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, TopicStats{Topic: first, Envelopes: 3, Bytes: 30}, stats[0])
+}
+
+func TestTopicStatsPrune(t *testing.T) {
+	db := setupTopicStatsDB(t)
+	defer db.Close()
+	collector := newTopicStatsCollector(db)
+
+	now := time.Now()
+	collector.add(now.Add(-3*time.Hour), types.TopicType{1}, 10)
+	collector.add(now, types.TopicType{2}, 10)
+	require.NoError(t, collector.Flush())
+
+	require.NoError(t, db.PruneTopicStats(now.Add(-time.Hour)))
+	stats, err := db.TopicStats(0)
+	require.NoError(t, err)
+	require.Equal(t, []TopicStats{{Topic: types.TopicType{2}, Envelopes: 1, Bytes: 10}}, stats)
+}
+
+func TestTopicStatsNotIncludedInEnvelopes(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+	server.ms.topicStats = newTopicStatsCollector(server.ms.db)
+
+	archiveEnvelope(t, time.Now().Add(-time.Second), server)
+	require.NoError(t, server.ms.topicStats.Flush())
+
+	testMessagesCount(t, 1, server)
+	stats, err := server.ms.topicStats.TopTopics(10, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	require.Equal(t, uint64(1), stats[0].Envelopes)
+}
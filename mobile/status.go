@@ -191,6 +191,16 @@ func CallRPC(inputJSON string) string {
 	return resp
 }
 
+// CallDappRPC calls public APIs via RPC on behalf of a dapp with the given origin.
+// Calls fail unless the dapp was granted the required permissions.
+func CallDappRPC(origin, inputJSON string) string {
+	resp, err := statusBackend.CallDappRPC(origin, inputJSON)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	return resp
+}
+
 // CallPrivateRPC calls both public and private APIs via RPC.
 func CallPrivateRPC(inputJSON string) string {
 	resp, err := statusBackend.CallPrivateRPC(inputJSON)
@@ -447,6 +457,33 @@ func Logout() string {
 	return makeJSONResponse(statusBackend.StopNode())
 }
 
+// SwitchAccount logs out the current account and logs in another one. The node is restarted,
+// so that chats, mailserver requests and wallet data of the accounts are not mixed.
+// Only one account is open at a time, accounts are not kept loaded side by side.
+func SwitchAccount(accountData, password string) string {
+	var account multiaccounts.Account
+	err := json.Unmarshal([]byte(accountData), &account)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	api.RunAsync(func() error {
+		log.Debug("switch to account", "key-uid", account.KeyUID)
+		err := statusBackend.SwitchAccount(account, password)
+		if err != nil {
+			log.Error("failed to switch account", "key-uid", account.KeyUID, "error", err)
+			return err
+		}
+		log.Debug("switched to account", "key-uid", account.KeyUID)
+		return nil
+	})
+	return makeJSONResponse(nil)
+}
+
+// DeleteMultiaccount deletes an account that is not logged in, its keys and its database.
+func DeleteMultiaccount(keyUID, password string) string {
+	return makeJSONResponse(statusBackend.DeleteMultiaccount(keyUID, password))
+}
+
 // SignMessage unmarshals rpc params {data, address, password} and
 // passes them onto backend.SignMessage.
 func SignMessage(rpcParams string) string {
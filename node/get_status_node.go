@@ -27,6 +27,7 @@ import (
 
 	"github.com/status-im/status-go/db"
 	"github.com/status-im/status-go/discovery"
+	"github.com/status-im/status-go/mailserver"
 	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/peers"
 	"github.com/status-im/status-go/rpc"
@@ -625,6 +626,17 @@ func (n *StatusNode) WakuExtService() (s *wakuext.Service, err error) {
 	return
 }
 
+// MailServerService returns mailserver.Service instance if a mailserver was enabled.
+func (n *StatusNode) MailServerService() (s *mailserver.Service, err error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	err = n.gethService(&s)
+	if err == node.ErrServiceUnknown {
+		err = ErrServiceUnknown
+	}
+	return
+}
+
 // WalletService returns wallet.Service instance if it was started.
 func (n *StatusNode) WalletService() (s *wallet.Service, err error) {
 	n.mu.RLock()
@@ -15,6 +15,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/downloader"
@@ -31,6 +32,7 @@ import (
 	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/mailserver"
 	"github.com/status-im/status-go/params"
+	"github.com/status-im/status-go/services/bridge"
 	"github.com/status-im/status-go/services/ext"
 	"github.com/status-im/status-go/services/incentivisation"
 	"github.com/status-im/status-go/services/nodebridge"
@@ -56,6 +58,7 @@ var (
 	ErrStatusServiceRegistrationFailure           = errors.New("failed to register the Status service")
 	ErrPeerServiceRegistrationFailure             = errors.New("failed to register the Peer service")
 	ErrIncentivisationServiceRegistrationFailure  = errors.New("failed to register the Incentivisation service")
+	ErrBridgeServiceRegistrationFailure           = errors.New("failed to register the Bridge service")
 )
 
 // All general log messages in this package should be routed through this logger.
@@ -151,6 +154,11 @@ func activateNodeServices(stack *node.Node, config *params.NodeConfig, db *level
 		return fmt.Errorf("%v: %v", ErrWakuServiceRegistrationFailure, err)
 	}
 
+	// start Whisper-Waku bridge
+	if err := activateBridgeService(stack, config); err != nil {
+		return fmt.Errorf("%v: %v", ErrBridgeServiceRegistrationFailure, err)
+	}
+
 	// start incentivisation service
 	if err := activateIncentivisationService(stack, config); err != nil {
 		return fmt.Errorf("%v: %v", ErrIncentivisationServiceRegistrationFailure, err)
@@ -162,7 +170,7 @@ func activateNodeServices(stack *node.Node, config *params.NodeConfig, db *level
 	}
 
 	// start peer service
-	if err := activatePeerService(stack); err != nil {
+	if err := activatePeerService(stack, db); err != nil {
 		return fmt.Errorf("%v: %v", ErrPeerServiceRegistrationFailure, err)
 	}
 	return nil
@@ -310,23 +318,21 @@ func activateStatusService(stack *node.Node, config *params.NodeConfig) error {
 	})
 }
 
-func activatePeerService(stack *node.Node) error {
+func activatePeerService(stack *node.Node, db *leveldb.DB) error {
 	return stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		svc := peer.New()
+		svc := peer.New(peer.NewStore(db))
 		return svc, nil
 	})
 }
 
-func registerWhisperMailServer(whisperService *whisper.Whisper, config *params.WhisperConfig) (err error) {
-	var mailServer mailserver.WhisperMailServer
-	whisperService.RegisterMailServer(&mailServer)
+func registerWhisperMailServer(whisperService *whisper.Whisper, mailServer *mailserver.WhisperMailServer, config *params.WhisperConfig) (err error) {
+	whisperService.RegisterMailServer(mailServer)
 
 	return mailServer.Init(whisperService, config)
 }
 
-func registerWakuMailServer(wakuService *waku.Waku, config *params.WakuConfig) (err error) {
-	var mailServer mailserver.WakuMailServer
-	wakuService.RegisterMailServer(&mailServer)
+func registerWakuMailServer(wakuService *waku.Waku, mailServer *mailserver.WakuMailServer, config *params.WakuConfig) (err error) {
+	wakuService.RegisterMailServer(mailServer)
 
 	return mailServer.Init(wakuService, config)
 }
@@ -338,13 +344,23 @@ func activateShhService(stack *node.Node, config *params.NodeConfig, db *leveldb
 		return nil
 	}
 
+	var mailServer mailserver.WhisperMailServer
 	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		return createShhService(ctx, &config.WhisperConfig, &config.ClusterConfig)
+		return createShhService(ctx, &mailServer, &config.WhisperConfig, &config.ClusterConfig)
 	})
 	if err != nil {
 		return
 	}
 
+	if config.WhisperConfig.EnableMailServer {
+		err = stack.Register(func(*node.ServiceContext) (node.Service, error) {
+			return mailserver.NewService(&mailServer), nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
 	// Register Whisper eth-node bridge
 	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		var ethnode *nodebridge.NodeService
@@ -378,13 +394,25 @@ func activateWakuService(stack *node.Node, config *params.NodeConfig, db *leveld
 		return nil
 	}
 
+	var mailServer mailserver.WakuMailServer
 	err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		return createWakuService(ctx, &config.WakuConfig, &config.ClusterConfig)
+		return createWakuService(ctx, &mailServer, &config.WakuConfig, &config.ClusterConfig)
 	})
 	if err != nil {
 		return
 	}
 
+	// only one mailserver API can be registered, whisper one takes precedence
+	whisperMailServerEnabled := config.WhisperConfig.Enabled && config.WhisperConfig.EnableMailServer
+	if config.WakuConfig.EnableMailServer && !whisperMailServerEnabled {
+		err = stack.Register(func(*node.ServiceContext) (node.Service, error) {
+			return mailserver.NewService(&mailServer), nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
 	// TODO(dshulyak) add a config option to enable it by default, but disable if app is started from statusd
 	return stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		var ethnode *nodebridge.NodeService
@@ -395,7 +423,27 @@ func activateWakuService(stack *node.Node, config *params.NodeConfig, db *leveld
 	})
 }
 
-func createShhService(ctx *node.ServiceContext, whisperConfig *params.WhisperConfig, clusterConfig *params.ClusterConfig) (*whisper.Whisper, error) {
+// activateBridgeService configures a bridge between Whisper and Waku and adds it to the given node.
+func activateBridgeService(stack *node.Node, config *params.NodeConfig) error {
+	if !config.BridgeConfig.Enabled {
+		logger.Info("Whisper-Waku bridge is disabled")
+		return nil
+	}
+
+	return stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var shh *whisper.Whisper
+		if err := ctx.Service(&shh); err != nil {
+			return nil, err
+		}
+		var w *waku.Waku
+		if err := ctx.Service(&w); err != nil {
+			return nil, err
+		}
+		return bridge.New(shh, w, config.BridgeConfig)
+	})
+}
+
+func createShhService(ctx *node.ServiceContext, mailServer *mailserver.WhisperMailServer, whisperConfig *params.WhisperConfig, clusterConfig *params.ClusterConfig) (*whisper.Whisper, error) {
 	whisperServiceConfig := &whisper.Config{
 		MaxMessageSize:     whisper.DefaultMaxMessageSize,
 		MinimumAcceptedPOW: params.WhisperMinimumPoW,
@@ -407,6 +455,16 @@ func createShhService(ctx *node.ServiceContext, whisperConfig *params.WhisperCon
 	if whisperConfig.MinimumPoW > 0 {
 		whisperServiceConfig.MinimumAcceptedPOW = whisperConfig.MinimumPoW
 	}
+	if len(whisperConfig.MinimumPoWByTopic) > 0 {
+		whisperServiceConfig.MinimumPoWByTopic = make(map[whisper.TopicType]float64, len(whisperConfig.MinimumPoWByTopic))
+		for topic, pow := range whisperConfig.MinimumPoWByTopic {
+			b, err := decodeTopic(topic)
+			if err != nil {
+				return nil, err
+			}
+			whisperServiceConfig.MinimumPoWByTopic[whisper.BytesToTopic(b)] = pow
+		}
+	}
 
 	whisperService := whisper.New(whisperServiceConfig)
 
@@ -421,7 +479,7 @@ func createShhService(ctx *node.ServiceContext, whisperConfig *params.WhisperCon
 
 	// enable mail service
 	if whisperConfig.EnableMailServer {
-		if err := registerWhisperMailServer(whisperService, whisperConfig); err != nil {
+		if err := registerWhisperMailServer(whisperService, mailServer, whisperConfig); err != nil {
 			return nil, fmt.Errorf("failed to register MailServer: %v", err)
 		}
 	}
@@ -436,7 +494,16 @@ func createShhService(ctx *node.ServiceContext, whisperConfig *params.WhisperCon
 	return whisperService, nil
 }
 
-func createWakuService(ctx *node.ServiceContext, wakuCfg *params.WakuConfig, clusterCfg *params.ClusterConfig) (*waku.Waku, error) {
+// decodeTopic decodes a hex-encoded 4-byte topic used in the node configuration.
+func decodeTopic(topic string) ([]byte, error) {
+	b, err := hexutil.Decode(topic)
+	if err != nil || len(b) != whisper.TopicLength {
+		return nil, fmt.Errorf("invalid topic %s", topic)
+	}
+	return b, nil
+}
+
+func createWakuService(ctx *node.ServiceContext, mailServer *mailserver.WakuMailServer, wakuCfg *params.WakuConfig, clusterCfg *params.ClusterConfig) (*waku.Waku, error) {
 	cfg := &waku.Config{
 		MaxMessageSize:     waku.DefaultMaxMessageSize,
 		MinimumAcceptedPoW: params.WakuMinimumPoW,
@@ -448,6 +515,16 @@ func createWakuService(ctx *node.ServiceContext, wakuCfg *params.WakuConfig, clu
 	if wakuCfg.MinimumPoW > 0 {
 		cfg.MinimumAcceptedPoW = wakuCfg.MinimumPoW
 	}
+	if len(wakuCfg.MinimumPoWByTopic) > 0 {
+		cfg.MinimumPoWByTopic = make(map[waku.TopicType]float64, len(wakuCfg.MinimumPoWByTopic))
+		for topic, pow := range wakuCfg.MinimumPoWByTopic {
+			b, err := decodeTopic(topic)
+			if err != nil {
+				return nil, err
+			}
+			cfg.MinimumPoWByTopic[waku.BytesToTopic(b)] = pow
+		}
+	}
 
 	// TODO: provide a logger
 	w := waku.New(cfg, nil)
@@ -463,7 +540,7 @@ func createWakuService(ctx *node.ServiceContext, wakuCfg *params.WakuConfig, clu
 
 	// enable mail service
 	if wakuCfg.EnableMailServer {
-		if err := registerWakuMailServer(w, wakuCfg); err != nil {
+		if err := registerWakuMailServer(w, mailServer, wakuCfg); err != nil {
 			return nil, fmt.Errorf("failed to register WakuMailServer: %v", err)
 		}
 	}
@@ -60,6 +60,62 @@ type PGConfig struct {
 	Enabled bool
 	// The URI of the server
 	URI string
+	// ShardURIs are URIs of the databases between which envelopes are sharded by topic.
+	// If set, URI is not used.
+	ShardURIs []string
+	// SSLMode is one of disable, require, verify-ca and verify-full.
+	// If empty, sslmode from the URI is used.
+	SSLMode string
+	// SSLRootCert is a path to a CA bundle used to verify the server certificate.
+	SSLRootCert string
+	// SSLCert is a path to a client certificate.
+	SSLCert string
+	// SSLKey is a path to a private key of the client certificate.
+	SSLKey string
+	// AllowPlaintext allows unencrypted connections to the database.
+	AllowPlaintext bool
+	// SpillDir is a directory of a local queue of envelopes that could not be saved
+	// while the database was unavailable. If empty, such envelopes are dropped.
+	SpillDir string
+	// Tenant scopes envelopes of a mailserver in a database shared by multiple networks.
+	// Empty tenant owns envelopes archived before tenants were introduced.
+	Tenant string
+	// TenantQuota is a maximum total size of envelopes of the tenant in bytes, unlimited if zero.
+	TenantQuota uint64
+}
+
+// ----------
+// MailServerQueryTier
+// ----------
+
+// MailServerQueryTier restricts MailServer requests for envelopes older than MinAge.
+type MailServerQueryTier struct {
+	// Name labels metrics of the tier.
+	Name string
+	// MinAge is a number of hours. The tier applies to requests for envelopes at least that old.
+	MinAge int
+	// MaxQueryLimit is a maximum number of envelopes returned in a single response
+	// within the tier. Zero keeps the limit of the MailServer.
+	MaxQueryLimit uint32
+	// AllowedPeersOnly rejects requests from peers not listed in MailServerAllowedPeers.
+	AllowedPeersOnly bool
+}
+
+// ----------
+// MailServerListener
+// ----------
+
+// MailServerListener limits MailServer requests of peers connected through matching local addresses.
+type MailServerListener struct {
+	// Name labels metrics of the listener.
+	Name string
+	// Addr is a local IPv4 or IPv6 address, optionally with a port, or a CIDR
+	// of local addresses, e.g. "10.8.0.1", "[2001:db8::1]:30303" or "0.0.0.0/0".
+	Addr string
+	// RateLimit is a minimum number of seconds between requests of a peer. Zero disables the limit.
+	RateLimit int
+	// AllowedPeers is a list of enodes of peers allowed to make requests. Empty allows all peers.
+	AllowedPeers []string
 }
 
 // ----------
@@ -84,6 +140,10 @@ type WhisperConfig struct {
 	// MinimumPoW minimum PoW for Whisper messages
 	MinimumPoW float64
 
+	// MinimumPoWByTopic minimum PoW for messages with specific topics, overrides MinimumPoW.
+	// Keys are hex-encoded topics, e.g. "0xf8946aac".
+	MinimumPoWByTopic map[string]float64
+
 	// MailServerPassword for symmetric encryption of whisper message history requests.
 	// (if no account file selected, then this password is used for symmetric encryption).
 	MailServerPassword string
@@ -97,6 +157,111 @@ type WhisperConfig struct {
 	// MailServerDataRetention is a number of days data should be stored by MailServer.
 	MailServerDataRetention int
 
+	// MailServerMaxQueryLimit is a maximum number of envelopes returned by MailServer in a single response.
+	// It can't exceed 1000 which is also the default value.
+	MailServerMaxQueryLimit uint32
+
+	// MailServerMaxResponseSize is a maximum total size in bytes of envelopes returned by MailServer
+	// in a single response. Zero means that the size is not limited.
+	MailServerMaxResponseSize uint32
+
+	// MailServerQueryCacheSize is a number of response pages cached by MailServer
+	// for repeated requests. Zero disables the cache.
+	MailServerQueryCacheSize int
+
+	// MailServerQueryTimeout is a number of seconds a single database query of MailServer can run.
+	// Envelopes found before the timeout are returned with a cursor to resume from. Zero means no timeout.
+	MailServerQueryTimeout int
+
+	// MailServerMaxConcurrentQueries is a maximum number of history queries processed by MailServer
+	// at the same time. Other queries wait and are served in turns across peers. Zero means no limit.
+	MailServerMaxConcurrentQueries int
+
+	// MailServerAckBatchSize is a number of envelopes in a batch sent by MailServer to clients
+	// that acknowledge batches. Zero means 100 envelopes.
+	MailServerAckBatchSize int
+
+	// MailServerAckTimeout is a number of seconds MailServer waits for an acknowledgement of a batch.
+	// The response has a cursor of the last acknowledged batch if it is not received. Zero means 30 seconds.
+	MailServerAckTimeout int
+
+	// MailServerTopicIndex enables an hourly index of topics used by MailServer to select envelopes
+	// by topics matching a bloom filter instead of testing the bloom filter of every envelope.
+	MailServerTopicIndex bool
+
+	// MailServerBloomIndex enables hourly blooms aggregated from archived envelopes used by MailServer
+	// to skip time ranges without envelopes that can match a bloom filter of a request.
+	MailServerBloomIndex bool
+
+	// MailServerConsistencyCheck enables validation of recently archived envelopes on start.
+	// Envelopes damaged by a crash are removed. Only LevelDB archives are checked.
+	MailServerConsistencyCheck bool
+
+	// MailServerConsistencyCheckWindow is a number of minutes before the start
+	// checked by the consistency check. Defaults to 60 minutes.
+	MailServerConsistencyCheckWindow int
+
+	// MailServerSoftDeleteWindow is a number of hours pruned envelopes are kept before
+	// they are removed permanently. Within the window they can be restored with the admin API.
+	// Zero disables soft delete.
+	MailServerSoftDeleteWindow int
+
+	// MailServerQueryTiers restrict MailServer requests by the age of requested envelopes.
+	MailServerQueryTiers []MailServerQueryTier
+
+	// MailServerCompactionInterval is a minimum number of hours between periodic compactions
+	// of a LevelDB archive. Zero disables periodic compaction.
+	MailServerCompactionInterval int
+
+	// MailServerCompactionIdleWindow is a number of minutes without requests after which
+	// a periodic compaction can start. Defaults to 10 minutes.
+	MailServerCompactionIdleWindow int
+
+	// MailServerAllowedPeers is a list of enodes of peers allowed to make requests
+	// of query tiers restricted to allowed peers.
+	MailServerAllowedPeers []string
+
+	// MailServerListeners apply rate limits and allowed peers per local address of connections.
+	// Requests of peers connected through addresses not matching any listener are rejected.
+	// Disabled if empty.
+	MailServerListeners []MailServerListener
+
+	// MailServerDisableCompression disables compression of responses for peers
+	// that accept compressed batches of envelopes.
+	MailServerDisableCompression bool
+
+	// MailServerBuildIndexes enables a background build of database indexes and of the topic index
+	// of envelopes archived before it was enabled. Builds are resumed after a restart.
+	MailServerBuildIndexes bool
+
+	// MailServerMaxEnvelopeSize is a maximum size of archived envelopes in bytes, no limit if zero.
+	MailServerMaxEnvelopeSize uint32
+
+	// MailServerMaxEnvelopeTTL is a maximum TTL of archived envelopes in seconds, 30 days if zero.
+	MailServerMaxEnvelopeTTL uint32
+
+	// MailServerMaxEnvelopeDrift is a number of seconds archived envelopes can be sent
+	// ahead of the current time, an hour if zero.
+	MailServerMaxEnvelopeDrift uint32
+
+	// MailServerReadOnly disables archiving of envelopes while requests are still served,
+	// e.g. during a maintenance of the database.
+	MailServerReadOnly bool
+
+	// MailServerReplicas is a list of enodes of follower mailservers. Archived envelopes
+	// are streamed to connected followers. Replication is not supported by Waku.
+	MailServerReplicas []string
+
+	// MailServerPrimary is an enode of a primary mailserver. Envelopes streamed by the primary
+	// are archived and envelopes missed while the primary was not connected are requested.
+	// Replication is not supported by Waku.
+	MailServerPrimary string
+
+	// MailServerBackfillPeers is a list of enodes of peer mailservers. Envelopes sent while
+	// the mailserver was not archiving are requested from connected peers. Backfill is not
+	// supported by Waku.
+	MailServerBackfillPeers []string
+
 	// TTL time to live for messages, in seconds
 	TTL int
 
@@ -152,6 +317,10 @@ type WakuConfig struct {
 	// MinimumPoW minimum PoW for Whisper messages
 	MinimumPoW float64
 
+	// MinimumPoWByTopic minimum PoW for messages with specific topics, overrides MinimumPoW.
+	// Keys are hex-encoded topics, e.g. "0xf8946aac".
+	MinimumPoWByTopic map[string]float64
+
 	// MailServerPassword for symmetric encryption of whisper message history requests.
 	// (if no account file selected, then this password is used for symmetric encryption).
 	MailServerPassword string
@@ -162,6 +331,97 @@ type WakuConfig struct {
 	// MailServerDataRetention is a number of days data should be stored by MailServer.
 	MailServerDataRetention int
 
+	// MailServerMaxQueryLimit is a maximum number of envelopes returned by MailServer in a single response.
+	// It can't exceed 1000 which is also the default value.
+	MailServerMaxQueryLimit uint32
+
+	// MailServerMaxResponseSize is a maximum total size in bytes of envelopes returned by MailServer
+	// in a single response. Zero means that the size is not limited.
+	MailServerMaxResponseSize uint32
+
+	// MailServerQueryCacheSize is a number of response pages cached by MailServer
+	// for repeated requests. Zero disables the cache.
+	MailServerQueryCacheSize int
+
+	// MailServerQueryTimeout is a number of seconds a single database query of MailServer can run.
+	// Envelopes found before the timeout are returned with a cursor to resume from. Zero means no timeout.
+	MailServerQueryTimeout int
+
+	// MailServerMaxConcurrentQueries is a maximum number of history queries processed by MailServer
+	// at the same time. Other queries wait and are served in turns across peers. Zero means no limit.
+	MailServerMaxConcurrentQueries int
+
+	// MailServerAckBatchSize is a number of envelopes in a batch sent by MailServer to clients
+	// that acknowledge batches. Zero means 100 envelopes.
+	MailServerAckBatchSize int
+
+	// MailServerAckTimeout is a number of seconds MailServer waits for an acknowledgement of a batch.
+	// The response has a cursor of the last acknowledged batch if it is not received. Zero means 30 seconds.
+	MailServerAckTimeout int
+
+	// MailServerTopicIndex enables an hourly index of topics used by MailServer to select envelopes
+	// by topics matching a bloom filter instead of testing the bloom filter of every envelope.
+	MailServerTopicIndex bool
+
+	// MailServerBloomIndex enables hourly blooms aggregated from archived envelopes used by MailServer
+	// to skip time ranges without envelopes that can match a bloom filter of a request.
+	MailServerBloomIndex bool
+
+	// MailServerConsistencyCheck enables validation of recently archived envelopes on start.
+	// Envelopes damaged by a crash are removed. Only LevelDB archives are checked.
+	MailServerConsistencyCheck bool
+
+	// MailServerConsistencyCheckWindow is a number of minutes before the start
+	// checked by the consistency check. Defaults to 60 minutes.
+	MailServerConsistencyCheckWindow int
+
+	// MailServerSoftDeleteWindow is a number of hours pruned envelopes are kept before
+	// they are removed permanently. Within the window they can be restored with the admin API.
+	// Zero disables soft delete.
+	MailServerSoftDeleteWindow int
+
+	// MailServerQueryTiers restrict MailServer requests by the age of requested envelopes.
+	MailServerQueryTiers []MailServerQueryTier
+
+	// MailServerCompactionInterval is a minimum number of hours between periodic compactions
+	// of a LevelDB archive. Zero disables periodic compaction.
+	MailServerCompactionInterval int
+
+	// MailServerCompactionIdleWindow is a number of minutes without requests after which
+	// a periodic compaction can start. Defaults to 10 minutes.
+	MailServerCompactionIdleWindow int
+
+	// MailServerAllowedPeers is a list of enodes of peers allowed to make requests
+	// of query tiers restricted to allowed peers.
+	MailServerAllowedPeers []string
+
+	// MailServerListeners apply rate limits and allowed peers per local address of connections.
+	// Requests of peers connected through addresses not matching any listener are rejected.
+	// Disabled if empty.
+	MailServerListeners []MailServerListener
+
+	// MailServerDisableCompression disables compression of responses for peers
+	// that accept compressed batches of envelopes.
+	MailServerDisableCompression bool
+
+	// MailServerBuildIndexes enables a background build of database indexes and of the topic index
+	// of envelopes archived before it was enabled. Builds are resumed after a restart.
+	MailServerBuildIndexes bool
+
+	// MailServerMaxEnvelopeSize is a maximum size of archived envelopes in bytes, no limit if zero.
+	MailServerMaxEnvelopeSize uint32
+
+	// MailServerMaxEnvelopeTTL is a maximum TTL of archived envelopes in seconds, 30 days if zero.
+	MailServerMaxEnvelopeTTL uint32
+
+	// MailServerMaxEnvelopeDrift is a number of seconds archived envelopes can be sent
+	// ahead of the current time, an hour if zero.
+	MailServerMaxEnvelopeDrift uint32
+
+	// MailServerReadOnly disables archiving of envelopes while requests are still served,
+	// e.g. during a maintenance of the database.
+	MailServerReadOnly bool
+
 	// TTL time to live for messages, in seconds
 	TTL int
 
@@ -189,6 +449,25 @@ type WakuConfig struct {
 	RateLimitTolerance int64
 }
 
+// ----------
+// BridgeConfig
+// ----------
+
+// BridgeConfig provides a configuration for a bridge that relays envelopes
+// between Whisper and Waku. Both protocols must be enabled.
+type BridgeConfig struct {
+	// Enabled flag specifies whether the bridge is enabled.
+	Enabled bool
+
+	// WhisperToWakuTopics is a list of hex encoded topics relayed from Whisper to Waku.
+	// All topics are relayed if empty.
+	WhisperToWakuTopics []string
+
+	// WakuToWhisperTopics is a list of hex encoded topics relayed from Waku to Whisper.
+	// All topics are relayed if empty.
+	WakuToWhisperTopics []string
+}
+
 // IncentivisationConfig holds incentivisation-related configuration
 type IncentivisationConfig struct {
 	// Enabled flag specifies whether protocol is enabled
@@ -428,6 +707,9 @@ type NodeConfig struct {
 	// WakuConfig provides a configuration for Waku subprotocol.
 	WakuConfig WakuConfig `json:"WakuConfig" validate:"structonly"`
 
+	// BridgeConfig provides a configuration for the Whisper-Waku bridge.
+	BridgeConfig BridgeConfig `json:"BridgeConfig" validate:"structonly"`
+
 	// IncentivisationConfig extra configuration for incentivisation service
 	IncentivisationConfig IncentivisationConfig `json:"IncentivisationConfig," validate:"structonly"`
 
@@ -465,6 +747,34 @@ type NodeConfig struct {
 // WalletConfig extra configuration for wallet.Service.
 type WalletConfig struct {
 	Enabled bool
+	// IndexerURL is an etherscan-compatible API endpoint used only for initial discovery
+	// of historical blocks with transfers. Discovered blocks are verified against the node.
+	IndexerURL string
+	// IndexerAPIKey is an optional API key for the indexer.
+	IndexerAPIKey string
+	// PriceSourceURL is a cryptocompare-compatible `pricehistorical` endpoint used to fetch
+	// historical prices for fiat values of transfers. Fetched prices are cached in the database.
+	PriceSourceURL string
+	// PriceSourceAPIKey is an optional API key for the price source.
+	PriceSourceAPIKey string
+	// HardwareWalletConfirmationTimeout is how long a user has to confirm a transaction
+	// on a hardware wallet. Defaults to 2 minutes.
+	HardwareWalletConfirmationTimeout time.Duration
+	// OnRampProvidersURL is an endpoint that returns a JSON list of fiat on-ramp providers.
+	// Fetched providers are cached in the database.
+	OnRampProvidersURL string
+	// CheckpointsFile is a path to a JSON list of checkpoint bundles shipped with the node.
+	// Bundles summarize activity in block ranges, ranges without activity of accounts are
+	// skipped by the initial sync.
+	CheckpointsFile string
+	// CheckpointsURL is a trusted endpoint that returns checkpoint bundles in the same format.
+	// Fetched bundles replace bundles loaded from CheckpointsFile.
+	CheckpointsURL string
+	// IPFSGateways are tried in order to fetch metadata of collectibles with ipfs:// token URIs,
+	// e.g. "https://ipfs.io/ipfs/". Public gateways are used if empty.
+	IPFSGateways []string
+	// ArweaveGateways are tried in order to fetch metadata with ar:// token URIs. Defaults to "https://arweave.net/".
+	ArweaveGateways []string
 }
 
 // BrowsersConfig extra configuration for browsers.Service.
@@ -503,9 +813,56 @@ type ShhextConfig struct {
 	// MaxServerFailures defines maximum allowed expired requests before server will be swapped to another one.
 	MaxServerFailures int
 
+	// EnableMailServerRegistry turns on selection of a mail server for history requests
+	// based on latency and error rate of previous requests. The active mail server is replaced
+	// after MaxServerFailures failed requests unless it is pinned.
+	EnableMailServerRegistry bool
+
 	// MaxMessageDeliveryAttempts defines how many times we will try to deliver not-acknowledged envelopes.
 	MaxMessageDeliveryAttempts int
 
+	// OutboxEnabled turns on the persistent outbox. Messages that fail to be posted or are not
+	// delivered after MaxMessageDeliveryAttempts are stored and retried when peers are connected.
+	OutboxEnabled bool
+
+	// OutboxMaxAttempts is a number of outbox retries before a message is dropped. 10 is used if not set.
+	OutboxMaxAttempts int
+
+	// OutboxMaxAge is how long a postponed message is retried. 24 hours is used if not set.
+	OutboxMaxAge time.Duration
+
+	// ChatIndicatorsEnabled turns on typing and presence indicators in one-to-one and group chats.
+	ChatIndicatorsEnabled bool
+
+	// ChatIndicatorsMinInterval is the minimal interval between indicators of the same type
+	// in a chat. 3 seconds is used if not set.
+	ChatIndicatorsMinInterval time.Duration
+
+	// FilterSilencePeriod enables a signal when a listening filter received nothing for the period.
+	FilterSilencePeriod time.Duration
+
+	// PushNotificationServer is a hex encoded public key of a push notification server.
+	// If set, device tokens can be registered with the server and notifications are
+	// requested for contacts that are offline.
+	PushNotificationServer string
+
+	// PushNotificationsOfflineAfter is the time since the last message received from a contact
+	// after which the contact is notified about new messages. 5 minutes is used if not set.
+	PushNotificationsOfflineAfter time.Duration
+
+	// PushNotificationServerEnabled runs a push notification server. Registrations of device
+	// tokens are accepted from any user and notifications requested by contacts are forwarded
+	// to PushNotificationGatewayURL.
+	PushNotificationServerEnabled bool
+
+	// PushNotificationGatewayURL is the address of a gorush-compatible gateway that delivers
+	// notifications with APNS and FCM.
+	PushNotificationGatewayURL string
+
+	// MessageSegmentSize is the maximum size in bytes of a payload sent in a single envelope.
+	// If set, larger payloads are split into segments and reassembled by receivers.
+	MessageSegmentSize uint32
+
 	// WhisperCacheDir is a folder where whisper filters may persist messages before delivering them
 	// to a client.
 	WhisperCacheDir string
@@ -519,6 +876,10 @@ type ShhextConfig struct {
 	// DatasyncEnabled indicates whether we should enable dataasync
 	DataSyncEnabled bool
 
+	// PublicChatsDirectoryEnabled indicates whether joined public chats should be periodically
+	// announced to the public chats directory and announcements of other users collected
+	PublicChatsDirectoryEnabled bool
+
 	// VerifyTransactionURL is the URL for verifying transactions.
 	// IMPORTANT: It should always be mainnet unless used for testing
 	VerifyTransactionURL string
@@ -532,6 +893,18 @@ type ShhextConfig struct {
 	VerifyENSContractAddress string
 
 	VerifyTransactionChainID int64
+
+	// PartitionsCount is the number of partitioned topics used for contact discovery.
+	// It must be the same for all clients of a network, 5000 is used if not set.
+	PartitionsCount int
+
+	// PreviousPartitionsCount is the number of partitioned topics used before
+	// PartitionsCount was changed.
+	PreviousPartitionsCount int
+
+	// PreviousPartitionsUntil is a unix timestamp of the end of the migration to PartitionsCount.
+	// Until then both previous and new partitions are listened to.
+	PreviousPartitionsUntil int64
 }
 
 // Validate validates the ShhextConfig struct and returns an error if inconsistent values are found
@@ -542,6 +915,9 @@ func (c *ShhextConfig) Validate(validate *validator.Validate) error {
 	if c.PFSEnabled && len(c.BackupDisabledDataDir) == 0 {
 		return errors.New("field BackupDisabledDataDir is required if PFSEnabled is true")
 	}
+	if c.PushNotificationServerEnabled && len(c.PushNotificationGatewayURL) == 0 {
+		return errors.New("field PushNotificationGatewayURL is required if PushNotificationServerEnabled is true")
+	}
 	return nil
 }
 
@@ -782,6 +1158,10 @@ func (c *NodeConfig) Validate() error {
 		return fmt.Errorf("both Whisper and Waku are enabled and use the same data dir")
 	}
 
+	if c.BridgeConfig.Enabled && !(c.WhisperConfig.Enabled && c.WakuConfig.Enabled) {
+		return fmt.Errorf("BridgeConfig is enabled, but Whisper or Waku is disabled")
+	}
+
 	// Whisper's data directory must be relative to the main data directory
 	// if EnableMailServer is true.
 	if c.WhisperConfig.Enabled && c.WhisperConfig.EnableMailServer {
@@ -790,6 +1170,17 @@ func (c *NodeConfig) Validate() error {
 		}
 	}
 
+	// Replication uses sync messages between mail servers which Waku doesn't support.
+	if (len(c.WhisperConfig.MailServerReplicas) > 0 || c.WhisperConfig.MailServerPrimary != "") &&
+		!(c.WhisperConfig.Enabled && c.WhisperConfig.EnableMailServer) {
+		return fmt.Errorf("WhisperConfig.MailServerReplicas or WhisperConfig.MailServerPrimary is set, but the Whisper mail server is disabled")
+	}
+
+	// Backfill requests envelopes with SyncMail which Waku doesn't support.
+	if len(c.WhisperConfig.MailServerBackfillPeers) > 0 && !(c.WhisperConfig.Enabled && c.WhisperConfig.EnableMailServer) {
+		return fmt.Errorf("WhisperConfig.MailServerBackfillPeers is set, but the Whisper mail server is disabled")
+	}
+
 	if !c.NoDiscovery && len(c.ClusterConfig.BootNodes) == 0 {
 		// No point in running discovery if we don't have bootnodes.
 		// In case we do have bootnodes, NoDiscovery should be true.
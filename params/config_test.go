@@ -326,6 +326,44 @@ func TestNodeConfigValidate(t *testing.T) {
 			}`,
 			Error: "WhisperConfig.MailServerAsymKey is invalid",
 		},
+		{
+			Name: "Validate that replication requires the Whisper mail server",
+			Config: `{
+				"NetworkId": 1,
+				"DataDir": "/some/dir",
+				"KeyStoreDir": "/some/dir",
+				"NoDiscovery": true,
+				"WhisperConfig": {
+					"MailServerPrimary": "enode://a2f1e5d3c4b7@10.0.0.1:30303"
+				},
+				"WakuConfig": {
+					"Enabled": true,
+					"EnableMailServer": true,
+					"DataDir": "/some/dir/waku",
+					"MailServerPassword": "foo"
+				}
+			}`,
+			Error: "WhisperConfig.MailServerReplicas or WhisperConfig.MailServerPrimary is set, but the Whisper mail server is disabled",
+		},
+		{
+			Name: "Validate that backfill requires the Whisper mail server",
+			Config: `{
+				"NetworkId": 1,
+				"DataDir": "/some/dir",
+				"KeyStoreDir": "/some/dir",
+				"NoDiscovery": true,
+				"WhisperConfig": {
+					"MailServerBackfillPeers": ["enode://a2f1e5d3c4b7@10.0.0.1:30303"]
+				},
+				"WakuConfig": {
+					"Enabled": true,
+					"EnableMailServer": true,
+					"DataDir": "/some/dir/waku",
+					"MailServerPassword": "foo"
+				}
+			}`,
+			Error: "WhisperConfig.MailServerBackfillPeers is set, but the Whisper mail server is disabled",
+		},
 		{
 			Name: "Validate that PFSEnabled & InstallationID are checked for validity",
 			Config: `{
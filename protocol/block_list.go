@@ -0,0 +1,248 @@
+package protocol
+
+import (
+	"crypto/ecdsa"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// ErrChatNotFound is returned when a chat that is not known to the messenger is muted.
+var ErrChatNotFound = errors.New("chat not found")
+
+// blockList keeps blocked public keys and muted chats in memory, they are checked
+// for every received envelope. It has its own lock because it's used by handlers
+// of negotiated secrets, which can be called with the messenger mutex held.
+type blockList struct {
+	mu      sync.RWMutex
+	blocked map[string]bool
+	muted   map[string]bool
+}
+
+func newBlockList() *blockList {
+	return &blockList{
+		blocked: make(map[string]bool),
+		muted:   make(map[string]bool),
+	}
+}
+
+func (l *blockList) load(blocked, muted []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, publicKey := range blocked {
+		l.blocked[publicKey] = true
+	}
+	for _, chatID := range muted {
+		l.muted[chatID] = true
+	}
+}
+
+func (l *blockList) setBlocked(publicKey string, blocked bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if blocked {
+		l.blocked[publicKey] = true
+	} else {
+		delete(l.blocked, publicKey)
+	}
+}
+
+func (l *blockList) setMuted(chatID string, muted bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if muted {
+		l.muted[chatID] = true
+	} else {
+		delete(l.muted, chatID)
+	}
+}
+
+func (l *blockList) isBlocked(publicKey string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.blocked[publicKey]
+}
+
+// isBlockedSignature returns true if the envelope was signed by a blocked key,
+// sig is the uncompressed public key recovered from the signature.
+func (l *blockList) isBlockedSignature(sig []byte) bool {
+	if len(sig) == 0 {
+		return false
+	}
+	return l.isBlocked(types.EncodeHex(sig))
+}
+
+func (l *blockList) isMuted(chatID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.muted[chatID]
+}
+
+func (l *blockList) blockedKeys() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sortedKeys(l.blocked)
+}
+
+func (l *blockList) mutedChats() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sortedKeys(l.muted)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	rst := make([]string, 0, len(set))
+	for key := range set {
+		rst = append(rst, key)
+	}
+	sort.Strings(rst)
+	return rst
+}
+
+// publicKeyFromContactID decodes a hex encoded public key prefixed with 0x.
+func publicKeyFromContactID(id string) (*ecdsa.PublicKey, error) {
+	b, err := types.DecodeHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPubkey(b)
+}
+
+// withoutBlocked returns public keys that are not blocked.
+func (l *blockList) withoutBlocked(publicKeys []*ecdsa.PublicKey) []*ecdsa.PublicKey {
+	var rst []*ecdsa.PublicKey
+	for _, publicKey := range publicKeys {
+		if !l.isBlocked(contactIDFromPublicKey(publicKey)) {
+			rst = append(rst, publicKey)
+		}
+	}
+	return rst
+}
+
+// BlockPublicKey adds the public key to the block list. One-to-one, partitioned and negotiated
+// filters of the key are removed and envelopes signed by it are dropped before they are decrypted.
+// Chats and messages are kept, use BlockContact to remove them as well.
+func (m *Messenger) BlockPublicKey(publicKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.blockPublicKey(publicKey)
+}
+
+func (m *Messenger) blockPublicKey(publicKey string) error {
+	pk, err := publicKeyFromContactID(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	publicKey = contactIDFromPublicKey(pk)
+	if publicKey == contactIDFromPublicKey(&m.identity.PublicKey) {
+		return errors.New("can't block own public key")
+	}
+	if err := m.persistence.BlockPublicKey(publicKey, time.Now().Unix()); err != nil {
+		return err
+	}
+	m.blockList.setBlocked(publicKey, true)
+	return m.transport.LeavePrivate(pk)
+}
+
+// UnblockPublicKey removes the public key from the block list and from blocked contacts.
+// Filters are loaded again if the key is an added contact or a peer of an active one-to-one chat.
+func (m *Messenger) UnblockPublicKey(publicKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	pk, err := publicKeyFromContactID(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	publicKey = contactIDFromPublicKey(pk)
+	if err := m.persistence.UnblockPublicKey(publicKey); err != nil {
+		return err
+	}
+	m.blockList.setBlocked(publicKey, false)
+
+	contact, ok := m.allContacts[publicKey]
+	if ok && contact.IsBlocked() {
+		var tags []string
+		for _, tag := range contact.SystemTags {
+			if tag != contactBlocked {
+				tags = append(tags, tag)
+			}
+		}
+		contact.SystemTags = tags
+		if err := m.persistence.SaveContact(contact, nil); err != nil {
+			return err
+		}
+	}
+
+	chat, hasChat := m.allChats[publicKey]
+	if !(ok && contact.IsAdded()) && !(hasChat && chat.Active) {
+		return nil
+	}
+	if err := m.transport.JoinPrivate(pk); err != nil {
+		return err
+	}
+	// the negotiated filter is restored from the stored secret
+	secrets, err := m.encryptor.ExportSecrets()
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		identity, err := crypto.DecompressPubkey(secret.Identity)
+		if err != nil || contactIDFromPublicKey(identity) != publicKey {
+			continue
+		}
+		if _, err := m.transport.ProcessNegotiatedSecret(types.NegotiatedSecret{PublicKey: identity, Key: secret.Key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockedPublicKeys returns all blocked public keys.
+func (m *Messenger) BlockedPublicKeys() []string {
+	return m.blockList.blockedKeys()
+}
+
+// MuteChat adds the chat to the mute list. Messages of muted chats are still received,
+// but the chat is flagged as muted in responses and chat indicators are not signalled,
+// so that the client doesn't notify the user.
+func (m *Messenger) MuteChat(chatID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	chat, ok := m.allChats[chatID]
+	if !ok {
+		return ErrChatNotFound
+	}
+	if err := m.persistence.MuteChat(chatID, time.Now().Unix()); err != nil {
+		return err
+	}
+	m.blockList.setMuted(chatID, true)
+	chat.Muted = true
+	return nil
+}
+
+// UnmuteChat removes the chat from the mute list.
+func (m *Messenger) UnmuteChat(chatID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.persistence.UnmuteChat(chatID); err != nil {
+		return err
+	}
+	m.blockList.setMuted(chatID, false)
+	if chat, ok := m.allChats[chatID]; ok {
+		chat.Muted = false
+	}
+	return nil
+}
+
+// MutedChats returns IDs of all muted chats.
+func (m *Messenger) MutedChats() []string {
+	return m.blockList.mutedChats()
+}
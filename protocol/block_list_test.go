@@ -0,0 +1,123 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerBlockListSuite(t *testing.T) {
+	suite.Run(t, new(MessengerBlockListSuite))
+}
+
+type MessengerBlockListSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerBlockListSuite) joinStatus(m *Messenger) Chat {
+	chat := CreatePublicChat("status", m.transport)
+	s.Require().NoError(m.SaveChat(&chat))
+	s.Require().NoError(m.Join(chat))
+	return chat
+}
+
+func (s *MessengerBlockListSuite) hasFilterOf(publicKey string) bool {
+	for _, filter := range s.m.transport.Filters() {
+		if filter.Identity == publicKey[2:] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MessengerBlockListSuite) TestBlockedEnvelopesAreDropped() {
+	blocked := s.newMessenger(s.shh)
+	other := s.newMessenger(s.shh)
+	blockedID := types.EncodeHex(crypto.FromECDSAPub(&blocked.identity.PublicKey))
+
+	// the one-to-one filter of the key is removed
+	s.Require().NoError(s.m.transport.JoinPrivate(&blocked.identity.PublicKey))
+	s.Require().True(s.hasFilterOf(blockedID))
+	s.Require().NoError(s.m.BlockPublicKey(blockedID))
+	s.Require().False(s.hasFilterOf(blockedID))
+	s.Require().Equal([]string{blockedID}, s.m.BlockedPublicKeys())
+
+	chat := s.joinStatus(s.m)
+	s.joinStatus(blocked)
+	s.joinStatus(other)
+
+	message := buildTestMessage(chat)
+	message.Text = "blocked"
+	_, err := blocked.SendChatMessage(context.Background(), message)
+	s.Require().NoError(err)
+	message = buildTestMessage(chat)
+	message.Text = "other"
+	_, err = other.SendChatMessage(context.Background(), message)
+	s.Require().NoError(err)
+
+	var texts []string
+	err = tt.RetryWithBackOff(func() error {
+		response, err := s.m.RetrieveAll()
+		if err != nil {
+			return err
+		}
+		for _, message := range response.Messages {
+			texts = append(texts, message.Text)
+		}
+		if len(texts) == 0 {
+			return errors.New("message not received")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"other"}, texts)
+
+	// the block list is persisted
+	s.m.blockList = newBlockList()
+	s.Require().NoError(s.m.Init())
+	s.Require().Equal([]string{blockedID}, s.m.BlockedPublicKeys())
+
+	s.Require().NoError(s.m.UnblockPublicKey(blockedID))
+	s.Require().Empty(s.m.BlockedPublicKeys())
+}
+
+func (s *MessengerBlockListSuite) TestMutedChatIsFlagged() {
+	s.Require().Equal(ErrChatNotFound, s.m.MuteChat("status"))
+
+	chat := s.joinStatus(s.m)
+	s.Require().NoError(s.m.MuteChat(chat.ID))
+	s.Require().Equal([]string{chat.ID}, s.m.MutedChats())
+
+	theirMessenger := s.newMessenger(s.shh)
+	s.joinStatus(theirMessenger)
+	_, err := theirMessenger.SendChatMessage(context.Background(), buildTestMessage(chat))
+	s.Require().NoError(err)
+
+	var response *MessengerResponse
+	err = tt.RetryWithBackOff(func() error {
+		var err error
+		response, err = s.m.RetrieveAll()
+		if err == nil && len(response.Messages) == 0 {
+			err = errors.New("message not received")
+		}
+		return err
+	})
+	s.Require().NoError(err)
+	s.Require().Len(response.Chats, 1)
+	s.Require().True(response.Chats[0].Muted)
+
+	// the mute list is persisted
+	s.m.blockList = newBlockList()
+	s.Require().NoError(s.m.Init())
+	s.Require().True(s.m.allChats[chat.ID].Muted)
+
+	s.Require().NoError(s.m.UnmuteChat(chat.ID))
+	s.Require().Empty(s.m.MutedChats())
+	s.Require().False(s.m.allChats[chat.ID].Muted)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/transport"
 	v1protocol "github.com/status-im/status-go/protocol/v1"
 )
 
@@ -59,6 +60,14 @@ type Chat struct {
 	Members []ChatMember `json:"members"`
 	// MembershipUpdates is all the membership events in the chat
 	MembershipUpdates []v1protocol.MembershipUpdateEvent `json:"membershipUpdateEvents"`
+
+	// Community fields
+	// CommunityChannel is set if the public chat is a channel of a community,
+	// it is signed by the community master key
+	CommunityChannel *transport.ChannelDescriptor `json:"communityChannel,omitempty"`
+
+	// Muted is set if the chat is in the mute list, the client should not notify about its messages
+	Muted bool `json:"muted,omitempty"`
 }
 
 func (c *Chat) PublicKey() (*ecdsa.PublicKey, error) {
@@ -92,9 +101,28 @@ func (c *Chat) Validate() error {
 		_, err := c.PublicKey()
 		return err
 	}
+
+	if c.CommunityChannel != nil {
+		if !c.Public() || c.ID != c.CommunityChannel.ChatID() {
+			return errors.New("chat doesn't match the community channel")
+		}
+		return c.CommunityChannel.Verify()
+	}
+	if c.Public() && transport.IsCommunityChannelChatID(c.ID) {
+		return transport.ErrUnauthorizedChannel
+	}
 	return nil
 }
 
+// CommunityID returns the hex encoded public key of the community
+// if the chat is a community channel.
+func (c *Chat) CommunityID() string {
+	if c.CommunityChannel == nil {
+		return ""
+	}
+	return c.CommunityChannel.CommunityID
+}
+
 func (c *Chat) MarshalJSON() ([]byte, error) {
 	type ChatAlias Chat
 	item := struct {
@@ -131,6 +159,7 @@ func (c *Chat) UnmarshalJSON(data []byte) error {
 	c.UnviewedMessagesCount = aux.UnviewedMessagesCount
 	c.Members = aux.Members
 	c.MembershipUpdates = aux.MembershipUpdates
+	c.CommunityChannel = aux.CommunityChannel
 
 	if aux.LastMessage != nil {
 		data, err := json.Marshal(aux.LastMessage)
@@ -150,6 +179,26 @@ func (c *Chat) MembersAsPublicKeys() ([]*ecdsa.PublicKey, error) {
 	return stringSliceToPublicKeys(publicKeys, true)
 }
 
+// hasJoinedMember returns true if the member with the given ID has joined the group chat.
+func (c *Chat) hasJoinedMember(id string) bool {
+	for _, member := range c.Members {
+		if member.ID == id {
+			return member.Joined
+		}
+	}
+	return false
+}
+
+// hasAdminMember returns true if the member with the given ID is an admin of the group chat.
+func (c *Chat) hasAdminMember(id string) bool {
+	for _, member := range c.Members {
+		if member.ID == id {
+			return member.Admin
+		}
+	}
+	return false
+}
+
 func (c *Chat) updateChatFromProtocolGroup(g *v1protocol.Group) {
 	// ID
 	c.ID = g.ChatID()
@@ -273,6 +322,14 @@ func CreatePublicChat(name string, timesource TimeSource) Chat {
 	}
 }
 
+// CreateCommunityChannelChat creates a public chat for a channel of a community.
+func CreateCommunityChannelChat(name string, descriptor *transport.ChannelDescriptor, timesource TimeSource) Chat {
+	chat := CreatePublicChat(name, timesource)
+	chat.ID = descriptor.ChatID()
+	chat.CommunityChannel = descriptor
+	return chat
+}
+
 func CreateGroupChat(timesource TimeSource) Chat {
 	return Chat{
 		Active:    true,
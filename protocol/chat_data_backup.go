@@ -0,0 +1,236 @@
+package protocol
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	chatDataBackupVersion = 1
+
+	// chatDataMessagesPageSize is a number of messages read or written at once,
+	// it is below the limit of SQLite query parameters.
+	chatDataMessagesPageSize = 500
+)
+
+// ErrInvalidChatDataBackup is returned when a chat data archive can't be decrypted
+// or decoded, most likely because of a wrong password.
+var ErrInvalidChatDataBackup = errors.New("invalid chat data archive or wrong password")
+
+// chatDataBackup is the content of an exported chat data archive.
+type chatDataBackup struct {
+	Version int `json:"version"`
+	// Keys are symmetric keys, negotiated secrets and filter definitions,
+	// negotiated topics are restored from the secrets.
+	Keys     *keysBackup `json:"keys"`
+	Chats    []*Chat     `json:"chats"`
+	Contacts []*Contact  `json:"contacts"`
+	// Messages are empty unless the message store is exported.
+	Messages []*archivedMessage `json:"messages,omitempty"`
+}
+
+// archivedMessage is a stored message with its content encoded as protobuf,
+// the JSON encoding of Message is meant for clients and doesn't restore all fields.
+type archivedMessage struct {
+	ChatMessage       []byte             `json:"chatMessage"`
+	ID                string             `json:"id"`
+	WhisperTimestamp  uint64             `json:"whisperTimestamp"`
+	From              string             `json:"from"`
+	Alias             string             `json:"alias"`
+	Identicon         string             `json:"identicon"`
+	LocalChatID       string             `json:"localChatId"`
+	Seen              bool               `json:"seen"`
+	OutgoingStatus    string             `json:"outgoingStatus,omitempty"`
+	QuotedMessage     *QuotedMessage     `json:"quotedMessage"`
+	CommandParameters *CommandParameters `json:"commandParameters"`
+	Replace           string             `json:"replace,omitempty"`
+}
+
+func newArchivedMessage(message *Message) (*archivedMessage, error) {
+	chatMessage, err := proto.Marshal(&message.ChatMessage)
+	if err != nil {
+		return nil, err
+	}
+	return &archivedMessage{
+		ChatMessage:       chatMessage,
+		ID:                message.ID,
+		WhisperTimestamp:  message.WhisperTimestamp,
+		From:              message.From,
+		Alias:             message.Alias,
+		Identicon:         message.Identicon,
+		LocalChatID:       message.LocalChatID,
+		Seen:              message.Seen,
+		OutgoingStatus:    message.OutgoingStatus,
+		QuotedMessage:     message.QuotedMessage,
+		CommandParameters: message.CommandParameters,
+		Replace:           message.Replace,
+	}, nil
+}
+
+func (a *archivedMessage) message() (*Message, error) {
+	message := &Message{
+		ID:                a.ID,
+		WhisperTimestamp:  a.WhisperTimestamp,
+		From:              a.From,
+		Alias:             a.Alias,
+		Identicon:         a.Identicon,
+		LocalChatID:       a.LocalChatID,
+		Seen:              a.Seen,
+		OutgoingStatus:    a.OutgoingStatus,
+		QuotedMessage:     a.QuotedMessage,
+		CommandParameters: a.CommandParameters,
+		Replace:           a.Replace,
+	}
+	var chatMessage protobuf.ChatMessage
+	if err := proto.Unmarshal(a.ChatMessage, &chatMessage); err != nil {
+		return nil, err
+	}
+	message.ChatMessage = chatMessage
+	return message, message.PrepareContent()
+}
+
+// ExportChatData returns chats, contacts, filters with their keys and negotiated secrets
+// and optionally all messages, encrypted with a key derived from the password.
+// The archive restores the state on a new device with ImportChatData.
+func (m *Messenger) ExportChatData(password string, includeMessages bool) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	keys, err := m.keysBackup()
+	if err != nil {
+		return nil, err
+	}
+
+	backup := chatDataBackup{
+		Version: chatDataBackupVersion,
+		Keys:    keys,
+	}
+	for _, chat := range m.allChats {
+		backup.Chats = append(backup.Chats, chat)
+	}
+	for _, contact := range m.allContacts {
+		backup.Contacts = append(backup.Contacts, contact)
+	}
+
+	if includeMessages {
+		for _, chat := range backup.Chats {
+			var cursor string
+			for {
+				messages, next, err := m.persistence.MessageByChatID(chat.ID, cursor, chatDataMessagesPageSize)
+				if err != nil {
+					return nil, err
+				}
+				for _, message := range messages {
+					archived, err := newArchivedMessage(message)
+					if err != nil {
+						return nil, err
+					}
+					backup.Messages = append(backup.Messages, archived)
+				}
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+		}
+	}
+
+	plaintext, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptBackup(password, plaintext)
+}
+
+// ImportChatData restores state from an archive created by ExportChatData.
+// Imported chats and contacts replace the ones with the same ID,
+// messages that are already stored are skipped.
+func (m *Messenger) ImportChatData(blob []byte, password string) error {
+	plaintext, err := decryptBackup(blob, password)
+	if err == ErrInvalidKeysBackup {
+		return ErrInvalidChatDataBackup
+	} else if err != nil {
+		return err
+	}
+
+	var backup chatDataBackup
+	if err := json.Unmarshal(plaintext, &backup); err != nil {
+		return ErrInvalidChatDataBackup
+	}
+	if backup.Version != chatDataBackupVersion {
+		return errors.Errorf("unsupported chat data archive version %d", backup.Version)
+	}
+	if backup.Keys == nil {
+		return ErrInvalidChatDataBackup
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.restoreKeys(backup.Keys); err != nil {
+		return err
+	}
+
+	var chats []*Chat
+	for _, chat := range backup.Chats {
+		if err := chat.Validate(); err != nil {
+			m.logger.Warn("skipping invalid chat in the archive", zap.String("chatID", chat.ID), zap.Error(err))
+			continue
+		}
+		chats = append(chats, chat)
+	}
+	if err := m.saveChats(chats); err != nil {
+		return errors.Wrap(err, "failed to import chats")
+	}
+
+	if err := m.persistence.SaveContacts(backup.Contacts); err != nil {
+		return errors.Wrap(err, "failed to import contacts")
+	}
+	for _, contact := range backup.Contacts {
+		m.allContacts[contact.ID] = contact
+	}
+
+	for start := 0; start < len(backup.Messages); start += chatDataMessagesPageSize {
+		end := start + chatDataMessagesPageSize
+		if end > len(backup.Messages) {
+			end = len(backup.Messages)
+		}
+		if err := m.importMessages(backup.Messages[start:end]); err != nil {
+			return errors.Wrap(err, "failed to import messages")
+		}
+	}
+	return nil
+}
+
+func (m *Messenger) importMessages(archived []*archivedMessage) error {
+	ids := make([]string, 0, len(archived))
+	for _, message := range archived {
+		ids = append(ids, message.ID)
+	}
+	existing, err := m.persistence.MessagesExist(ids)
+	if err != nil {
+		return err
+	}
+	var messages []*Message
+	for _, a := range archived {
+		if existing[a.ID] {
+			continue
+		}
+		existing[a.ID] = true
+		message, err := a.message()
+		if err != nil {
+			return ErrInvalidChatDataBackup
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return m.persistence.SaveMessagesLegacy(messages)
+}
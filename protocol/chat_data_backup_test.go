@@ -0,0 +1,81 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func TestMessengerChatDataBackupSuite(t *testing.T) {
+	suite.Run(t, new(MessengerChatDataBackupSuite))
+}
+
+type MessengerChatDataBackupSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerChatDataBackupSuite) TestExportImportChatData() {
+	privateKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	m := s.newMessengerWithKey(s.shh, privateKey)
+
+	chat := CreatePublicChat("status", m.getTimesource())
+	s.Require().NoError(m.Join(chat))
+	s.Require().NoError(m.SaveChat(&chat))
+
+	contactKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	contact, err := buildContact(&contactKey.PublicKey)
+	s.Require().NoError(err)
+	contact.SystemTags = []string{contactAdded}
+	s.Require().NoError(m.SaveContact(contact))
+
+	message := &Message{
+		ID:          "0x01",
+		From:        "0x02",
+		LocalChatID: chat.ID,
+		Seen:        true,
+	}
+	message.Text = "hello"
+	message.ChatId = chat.ID
+	message.Clock = 10
+	message.MessageType = protobuf.ChatMessage_PUBLIC_GROUP
+	message.ContentType = protobuf.ChatMessage_TEXT_PLAIN
+	s.Require().NoError(m.persistence.SaveMessagesLegacy([]*Message{message}))
+
+	withoutMessages, err := m.ExportChatData("password", false)
+	s.Require().NoError(err)
+	blob, err := m.ExportChatData("password", true)
+	s.Require().NoError(err)
+
+	// A messenger with the same identity but an empty database.
+	restored := s.newMessengerWithKey(s.shh, privateKey)
+	s.Require().Equal(ErrInvalidChatDataBackup, restored.ImportChatData(blob, "wrong"))
+
+	s.Require().NoError(restored.ImportChatData(withoutMessages, "password"))
+	s.Require().Equal(m.transport.SymmetricKeys()["status"], restored.transport.SymmetricKeys()["status"])
+	s.Require().Contains(restored.allChats, chat.ID)
+	s.Require().Contains(restored.allContacts, contact.ID)
+	s.Require().True(restored.allContacts[contact.ID].IsAdded())
+	_, err = restored.persistence.MessageByID(message.ID)
+	s.Require().Error(err)
+
+	// Importing twice doesn't duplicate messages.
+	s.Require().NoError(restored.ImportChatData(blob, "password"))
+	s.Require().NoError(restored.ImportChatData(blob, "password"))
+	imported, err := restored.persistence.MessageByID(message.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("hello", imported.Text)
+	s.Require().Equal(protobuf.ChatMessage_PUBLIC_GROUP, imported.MessageType)
+	s.Require().True(imported.Seen)
+
+	chats, err := restored.persistence.Chats()
+	s.Require().NoError(err)
+	s.Require().Len(chats, 1)
+	contacts, err := restored.persistence.Contacts()
+	s.Require().NoError(err)
+	s.Require().Len(contacts, 1)
+}
@@ -0,0 +1,194 @@
+package protocol
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+const (
+	// chatIdentityPublishInterval is how often our identity is published on the contact code topic.
+	chatIdentityPublishInterval = 24 * time.Hour
+	// chatIdentityCheckPeriod is how often the publisher checks if the identity is due.
+	chatIdentityCheckPeriod    = 10 * time.Minute
+	chatIdentityPublishTimeout = 10 * time.Second
+)
+
+// ChatIdentity is a display name and a profile image hash announced by a user.
+// Identities are published on the contact code topic, so they are received
+// by anyone who has a one-to-one chat with the user or added them as a contact.
+type ChatIdentity struct {
+	// PublicKey is the hex encoded public key of the user
+	PublicKey   string         `json:"publicKey"`
+	DisplayName string         `json:"displayName"`
+	ImageHash   types.HexBytes `json:"imageHash,omitempty"`
+	// Clock is increased with every change of the identity
+	Clock uint64 `json:"clock"`
+	// ReceivedAt is the time in milliseconds the identity was received, zero for our own identity
+	ReceivedAt uint64 `json:"receivedAt"`
+	// publishedAt is the time in milliseconds our identity was last published
+	publishedAt uint64
+}
+
+// chatIdentityPublisher republishes our identity periodically.
+type chatIdentityPublisher struct {
+	// mu serializes changes and publishing of our identity
+	mu sync.Mutex
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newChatIdentityPublisher() *chatIdentityPublisher {
+	return &chatIdentityPublisher{period: chatIdentityCheckPeriod}
+}
+
+// Start starts a loop that calls publish periodically.
+func (p *chatIdentityPublisher) Start(publish func()) {
+	p.cancel = make(chan struct{})
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(p.period)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				publish()
+			case <-p.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop.
+func (p *chatIdentityPublisher) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	close(p.cancel)
+	p.wg.Wait()
+	p.cancel = nil
+}
+
+// SetChatIdentity changes our display name and profile image hash and publishes them
+// on our contact code topic. The identity is republished periodically.
+func (m *Messenger) SetChatIdentity(ctx context.Context, displayName string, imageHash types.HexBytes) (*ChatIdentity, error) {
+	m.chatIdentityPublisher.mu.Lock()
+	defer m.chatIdentityPublisher.mu.Unlock()
+
+	publicKey := contactIDFromPublicKey(&m.identity.PublicKey)
+	previous, err := m.persistence.ChatIdentity(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clock := uint64(m.getTimesource().GetCurrentTime())
+	if previous != nil && previous.Clock >= clock {
+		clock = previous.Clock + 1
+	}
+	identity := &ChatIdentity{
+		PublicKey:   publicKey,
+		DisplayName: displayName,
+		ImageHash:   imageHash,
+		Clock:       clock,
+	}
+	if err := ValidateReceivedChatIdentity(identity.toProtobuf()); err != nil {
+		return nil, err
+	}
+	if err := m.persistence.SaveChatIdentity(identity); err != nil {
+		return nil, err
+	}
+	m.mentions.setDisplayName(displayName)
+	if err := m.publishChatIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ChatIdentity returns the identity announced by a user, nil if it wasn't received.
+func (m *Messenger) ChatIdentity(publicKey string) (*ChatIdentity, error) {
+	return m.persistence.ChatIdentity(publicKey)
+}
+
+// publishChatIdentity sends our identity to our contact code topic and records the time it was published.
+func (m *Messenger) publishChatIdentity(ctx context.Context, identity *ChatIdentity) error {
+	encodedMessage, err := proto.Marshal(identity.toProtobuf())
+	if err != nil {
+		return err
+	}
+	chatName := transport.ContactCodeTopic(&m.identity.PublicKey)
+	_, err = m.processor.SendPublicRaw(ctx, chatName, encodedMessage, protobuf.ApplicationMetadataMessage_CHAT_IDENTITY)
+	if err != nil {
+		return err
+	}
+	identity.publishedAt = uint64(m.getTimesource().GetCurrentTime())
+	return m.persistence.SaveChatIdentity(identity)
+}
+
+// republishChatIdentity publishes our identity if it was set and the publish interval has passed.
+func (m *Messenger) republishChatIdentity() {
+	m.chatIdentityPublisher.mu.Lock()
+	defer m.chatIdentityPublisher.mu.Unlock()
+
+	identity, err := m.persistence.ChatIdentity(contactIDFromPublicKey(&m.identity.PublicKey))
+	if err != nil {
+		m.logger.Warn("failed to read chat identity", zap.Error(err))
+		return
+	}
+	now := uint64(m.getTimesource().GetCurrentTime())
+	if identity == nil || now-identity.publishedAt < uint64(chatIdentityPublishInterval/time.Millisecond) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), chatIdentityPublishTimeout)
+	defer cancel()
+	if err := m.publishChatIdentity(ctx, identity); err != nil {
+		m.logger.Warn("failed to publish chat identity", zap.Error(err))
+	}
+}
+
+// handleChatIdentity stores an identity of the sender if it's newer than the stored one,
+// older identities are ignored. Our identity published by another installation is stored as published, so it's not sent again.
+func (m *Messenger) handleChatIdentity(state *ReceivedMessageState, message protobuf.ChatIdentity) error {
+	if err := ValidateReceivedChatIdentity(&message); err != nil {
+		return err
+	}
+	publicKey := contactIDFromPublicKey(state.CurrentMessageState.PublicKey)
+	previous, err := m.persistence.ChatIdentity(publicKey)
+	if err != nil {
+		return err
+	}
+	if previous != nil && previous.Clock >= message.Clock {
+		return nil
+	}
+	identity := &ChatIdentity{
+		PublicKey:   publicKey,
+		DisplayName: message.DisplayName,
+		Clock:       message.Clock,
+		ReceivedAt:  uint64(state.Timesource.GetCurrentTime()),
+	}
+	if len(message.ImageHash) > 0 {
+		identity.ImageHash = message.ImageHash
+	}
+	if isPubKeyEqual(state.CurrentMessageState.PublicKey, &m.identity.PublicKey) {
+		identity.ReceivedAt = 0
+		identity.publishedAt = uint64(state.Timesource.GetCurrentTime())
+	}
+	return m.persistence.SaveChatIdentity(identity)
+}
+
+func (i *ChatIdentity) toProtobuf() *protobuf.ChatIdentity {
+	return &protobuf.ChatIdentity{
+		Clock:       i.Clock,
+		DisplayName: i.DisplayName,
+		ImageHash:   i.ImageHash,
+	}
+}
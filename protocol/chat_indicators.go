@@ -0,0 +1,246 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	defaultChatIndicatorsMinInterval = 3 * time.Second
+	// chatIndicatorMaxAge is how old a received indicator can be. Older indicators,
+	// for example fetched from a mailserver, are dropped.
+	chatIndicatorMaxAge = 30 * time.Second
+	// chatIndicatorsCacheSize is a number of rate limiter entries after which
+	// expired entries are removed.
+	chatIndicatorsCacheSize = 1000
+)
+
+// Types of chat indicators.
+const (
+	ChatIndicatorTyping        = "typing"
+	ChatIndicatorTypingStopped = "typing-stopped"
+	ChatIndicatorOnline        = "online"
+)
+
+var chatIndicatorTypes = map[string]protobuf.ChatIndicator_Type{
+	ChatIndicatorTyping:        protobuf.ChatIndicator_TYPING,
+	ChatIndicatorTypingStopped: protobuf.ChatIndicator_TYPING_STOPPED,
+	ChatIndicatorOnline:        protobuf.ChatIndicator_ONLINE,
+}
+
+var (
+	// ErrChatIndicatorsDisabled is returned when sending an indicator without WithChatIndicators option.
+	ErrChatIndicatorsDisabled = errors.New("chat indicators are disabled")
+	// ErrChatIndicatorsNotSupported is returned when sending an indicator to a public chat.
+	ErrChatIndicatorsNotSupported = errors.New("chat indicators are supported only in one-to-one and private group chats")
+	// ErrChatIndicatorRateLimited is returned when an indicator of the same type
+	// was sent to the chat within the configured interval.
+	ErrChatIndicatorRateLimited = errors.New("chat indicator was sent too recently")
+	// ErrUnknownChatIndicatorType is returned for types other than typing, typing-stopped and online.
+	ErrUnknownChatIndicatorType = errors.New("unknown chat indicator type")
+)
+
+// ChatIndicator is a typing or presence indicator received from a member of a chat.
+type ChatIndicator struct {
+	ChatID string `json:"chatId"`
+	// From is the hex encoded public key of the sender
+	From  string `json:"from"`
+	Type  string `json:"type"`
+	Clock uint64 `json:"clock"`
+}
+
+// ChatIndicatorsHandler is notified about received chat indicators.
+type ChatIndicatorsHandler interface {
+	ChatIndicatorReceived(indicator ChatIndicator)
+}
+
+// ChatIndicatorsConfig configures typing and presence indicators. Indicators are
+// sent with a short TTL, are never stored and are not retried.
+type ChatIndicatorsConfig struct {
+	// MinInterval is the minimal interval between indicators of the same type
+	// in a chat. Indicators sent more often are rejected, received ones are dropped.
+	// 3 seconds is used if not set.
+	MinInterval time.Duration
+	Handler     ChatIndicatorsHandler
+}
+
+type chatIndicatorKey struct {
+	chatID        string
+	from          string
+	indicatorType protobuf.ChatIndicator_Type
+}
+
+// chatIndicators rate limits sent and received chat indicators.
+type chatIndicators struct {
+	config ChatIndicatorsConfig
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[chatIndicatorKey]time.Time
+}
+
+func newChatIndicators(config ChatIndicatorsConfig) *chatIndicators {
+	if config.MinInterval == 0 {
+		config.MinInterval = defaultChatIndicatorsMinInterval
+	}
+	return &chatIndicators{
+		config: config,
+		now:    time.Now,
+		seen:   make(map[chatIndicatorKey]time.Time),
+	}
+}
+
+// allow returns true and records the indicator if no indicator with the same key
+// was recorded within the min interval.
+func (c *chatIndicators) allow(key chatIndicatorKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if last, ok := c.seen[key]; ok && now.Sub(last) < c.config.MinInterval {
+		return false
+	}
+	if len(c.seen) >= chatIndicatorsCacheSize {
+		for k, last := range c.seen {
+			if now.Sub(last) >= c.config.MinInterval {
+				delete(c.seen, k)
+			}
+		}
+	}
+	c.seen[key] = now
+	return true
+}
+
+// SendChatIndicator sends a typing or presence indicator to a one-to-one
+// or a private group chat.
+func (m *Messenger) SendChatIndicator(ctx context.Context, chatID, indicatorType string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.chatIndicators == nil {
+		return ErrChatIndicatorsDisabled
+	}
+
+	t, ok := chatIndicatorTypes[indicatorType]
+	if !ok {
+		return ErrUnknownChatIndicatorType
+	}
+
+	chat, ok := m.allChats[chatID]
+	if !ok {
+		return errors.New("Chat not found")
+	}
+
+	var recipients []*ecdsa.PublicKey
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		publicKey, err := chat.PublicKey()
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, publicKey)
+	case ChatTypePrivateGroupChat:
+		members, err := chat.MembersAsPublicKeys()
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if !isPubKeyEqual(member, &m.identity.PublicKey) {
+				recipients = append(recipients, member)
+			}
+		}
+	default:
+		return ErrChatIndicatorsNotSupported
+	}
+
+	if !m.chatIndicators.allow(chatIndicatorKey{chatID: chat.ID, indicatorType: t}) {
+		return ErrChatIndicatorRateLimited
+	}
+
+	encodedMessage, err := proto.Marshal(&protobuf.ChatIndicator{
+		Clock:  m.getTimesource().GetCurrentTime(),
+		ChatId: chat.ID,
+		Type:   t,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.processor.SendEphemeral(ctx, recipients, encodedMessage, protobuf.ApplicationMetadataMessage_CHAT_INDICATOR)
+}
+
+// handleChatIndicator passes a received indicator to the handler if the sender
+// is a peer of the one-to-one chat or a member of the group chat.
+func (m *Messenger) handleChatIndicator(state *ReceivedMessageState, message protobuf.ChatIndicator) error {
+	if m.chatIndicators == nil || m.chatIndicators.config.Handler == nil {
+		return nil
+	}
+
+	if err := ValidateReceivedChatIndicator(&message, state.CurrentMessageState.WhisperTimestamp); err != nil {
+		return err
+	}
+
+	sent := time.Unix(0, int64(state.CurrentMessageState.WhisperTimestamp)*int64(time.Millisecond))
+	if m.chatIndicators.now().Sub(sent) > chatIndicatorMaxAge {
+		return errors.New("chat indicator is too old")
+	}
+
+	from := state.CurrentMessageState.Contact.ID
+	if from == contactIDFromPublicKey(&m.identity.PublicKey) {
+		return nil
+	}
+
+	chatID := message.ChatId
+	if chatID == contactIDFromPublicKey(&m.identity.PublicKey) {
+		// ChatID of an incoming one-to-one indicator is calculated from the signature.
+		chatID = from
+	}
+
+	chat, ok := state.AllChats[chatID]
+	if !ok {
+		return errors.New("chat not found")
+	}
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		if chat.ID != from {
+			return errors.New("sender is not a peer of the chat")
+		}
+	case ChatTypePrivateGroupChat:
+		if !chat.hasJoinedMember(from) {
+			return errors.New("sender is not a member of the chat")
+		}
+	default:
+		return ErrChatIndicatorsNotSupported
+	}
+
+	if !m.chatIndicators.allow(chatIndicatorKey{chatID: chatID, from: from, indicatorType: message.Type}) {
+		return ErrChatIndicatorRateLimited
+	}
+
+	// indicators of muted chats are accepted, but the client is not notified
+	if m.blockList.isMuted(chatID) {
+		return nil
+	}
+
+	var indicatorType string
+	for name, t := range chatIndicatorTypes {
+		if t == message.Type {
+			indicatorType = name
+		}
+	}
+
+	m.chatIndicators.config.Handler.ChatIndicatorReceived(ChatIndicator{
+		ChatID: chatID,
+		From:   from,
+		Type:   indicatorType,
+		Clock:  message.Clock,
+	})
+	return nil
+}
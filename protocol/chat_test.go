@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/transport"
 )
 
 type ChatTestSuite struct {
@@ -70,3 +73,37 @@ func (s *ChatTestSuite) TestValidateChat() {
 	}
 
 }
+
+func (s *ChatTestSuite) TestCommunityChannelChat() {
+	communityKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	descriptor, err := transport.NewChannelDescriptor(communityKey, "general", 1)
+	s.Require().NoError(err)
+
+	chat := CreateCommunityChannelChat("general", descriptor, &testTimeSource{})
+	s.Require().NoError(chat.Validate())
+	s.Require().Equal(descriptor.CommunityID, chat.CommunityID())
+
+	// a community channel can't be joined as a public chat
+	public := CreatePublicChat(descriptor.ChatID(), &testTimeSource{})
+	s.Require().Equal(transport.ErrUnauthorizedChannel, public.Validate())
+
+	unauthorized := chat
+	unauthorized.CommunityChannel = &transport.ChannelDescriptor{CommunityID: descriptor.CommunityID, ChannelID: "general", Clock: 2, Signature: descriptor.Signature}
+	s.Require().Equal(transport.ErrUnauthorizedChannel, unauthorized.Validate())
+
+	db, err := openTestDB()
+	s.Require().NoError(err)
+	p := sqlitePersistence{db: db}
+	s.Require().NoError(p.SaveChat(chat))
+	s.Require().Error(p.SaveChat(unauthorized))
+
+	saved, err := p.Chat(chat.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(descriptor, saved.CommunityChannel)
+
+	chats, err := p.Chats()
+	s.Require().NoError(err)
+	s.Require().Len(chats, 1)
+	s.Require().Equal(descriptor, chats[0].CommunityChannel)
+}
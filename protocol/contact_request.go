@@ -0,0 +1,27 @@
+package protocol
+
+type ContactRequestState int
+
+const (
+	ContactRequestStatePending ContactRequestState = iota + 1
+	ContactRequestStateAccepted
+	ContactRequestStateDeclined
+)
+
+// ContactRequest is a request to be added as a contact received from another user.
+type ContactRequest struct {
+	// ID is the hex-encoded public key of the sender (prefixed with 0x).
+	ID string `json:"id"`
+	// Message is the text attached to the request
+	Message string `json:"message"`
+	// Clock is the lamport timestamp of the request
+	Clock uint64 `json:"clock"`
+	// Timestamp is the whisper timestamp of the request in milliseconds
+	Timestamp uint64 `json:"timestamp"`
+	// State is pending until the user accepts or declines the request
+	State ContactRequestState `json:"state"`
+}
+
+func (r *ContactRequest) IsPending() bool {
+	return r.State == ContactRequestStatePending
+}
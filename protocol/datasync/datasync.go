@@ -16,15 +16,19 @@ type DataSync struct {
 	*datasyncnode.Node
 	// NodeTransport is the implementation of the datasync transport interface.
 	*NodeTransport
-	logger         *zap.Logger
-	sendingEnabled bool
+	// Retransmissions tracks acknowledgements of each installation of a peer.
+	Retransmissions *Retransmissions
+	logger          *zap.Logger
+	sendingEnabled  bool
 }
 
-func New(node *datasyncnode.Node, transport *NodeTransport, sendingEnabled bool, logger *zap.Logger) *DataSync {
-	return &DataSync{Node: node, NodeTransport: transport, sendingEnabled: sendingEnabled, logger: logger}
+func New(node *datasyncnode.Node, transport *NodeTransport, retransmissions *Retransmissions, sendingEnabled bool, logger *zap.Logger) *DataSync {
+	return &DataSync{Node: node, NodeTransport: transport, Retransmissions: retransmissions, sendingEnabled: sendingEnabled, logger: logger}
 }
 
-func (d *DataSync) Handle(sender *ecdsa.PublicKey, payload []byte) [][]byte {
+// Handle returns payloads of datasync messages, installationID is the installation
+// of the sender that is used to record its acknowledgements.
+func (d *DataSync) Handle(sender *ecdsa.PublicKey, installationID string, payload []byte) [][]byte {
 	var payloads [][]byte
 	logger := d.logger.With(zap.String("site", "Handle"))
 
@@ -41,6 +45,9 @@ func (d *DataSync) Handle(sender *ecdsa.PublicKey, payload []byte) [][]byte {
 			payloads = append(payloads, message.Body)
 		}
 		if d.sendingEnabled {
+			if err := d.Retransmissions.Acked(sender, installationID, datasyncMessage.Acks); err != nil {
+				logger.Error("failed to record acks", zap.Error(err))
+			}
 			d.add(sender, datasyncMessage)
 		}
 	}
@@ -49,6 +56,7 @@ func (d *DataSync) Handle(sender *ecdsa.PublicKey, payload []byte) [][]byte {
 }
 
 func (d *DataSync) Stop() {
+	d.Retransmissions.Stop()
 	d.Node.Stop()
 }
 
@@ -0,0 +1,299 @@
+package datasync
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	datasyncproto "github.com/vacp2p/mvds/protobuf"
+	"github.com/vacp2p/mvds/state"
+	"github.com/vacp2p/mvds/store"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+const (
+	// retransmissionBaseDelay is the delay of the first retransmission, it's doubled after each one.
+	retransmissionBaseDelay = 30 * time.Second
+	// retransmissionMaxDelay is the longest delay between retransmissions.
+	retransmissionMaxDelay = time.Hour
+	// maxRetransmissions is the number of retransmissions after which a message is given up.
+	maxRetransmissions = 10
+	// anyInstallation is tracked when a message is sent before installations of the recipient
+	// are known. It is acknowledged by any installation.
+	anyInstallation = ""
+)
+
+// Dispatch encrypts and sends an encoded datasync payload to the peer.
+type Dispatch func(context.Context, *ecdsa.PublicKey, []byte, *datasyncproto.Payload) error
+
+// Retransmissions tracks acknowledgements of one-to-one messages per installation of the recipient
+// and sends messages again with an exponential backoff until every installation acknowledged them.
+// MVDS stops retransmitting a message after the first acknowledgement of the peer, which is sent
+// by any of its installations. State is persisted, so retransmissions continue after a restart.
+type Retransmissions struct {
+	db       *sql.DB
+	store    store.MessageStore
+	dispatch Dispatch
+	now      func() time.Time
+	logger   *zap.Logger
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func NewRetransmissions(db *sql.DB, logger *zap.Logger) *Retransmissions {
+	return &Retransmissions{
+		db:     db,
+		store:  store.NewPersistentMessageStore(db),
+		now:    time.Now,
+		logger: logger.With(zap.String("site", "Retransmissions")),
+	}
+}
+
+// retransmissionDelay returns the delay before the next retransmission of a message
+// that was sent sendCount times.
+func retransmissionDelay(sendCount int) time.Duration {
+	delay := retransmissionBaseDelay
+	for i := 0; i < sendCount && delay < retransmissionMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retransmissionMaxDelay {
+		return retransmissionMaxDelay
+	}
+	return delay
+}
+
+// Start retransmits unacknowledged messages every interval.
+func (r *Retransmissions) Start(dispatch Dispatch, interval time.Duration) {
+	r.dispatch = dispatch
+	r.quit = make(chan struct{})
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.retransmit(); err != nil {
+					r.logger.Error("failed to retransmit messages", zap.Error(err))
+				}
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop retransmissions, it's a no-op if they were not started.
+func (r *Retransmissions) Stop() {
+	if r.quit == nil {
+		return
+	}
+	close(r.quit)
+	r.wg.Wait()
+	r.quit = nil
+}
+
+// Track records messages of the payload as sent to the installations of the recipient.
+// Messages that are already tracked keep their state, installations that are not tracked
+// yet are added.
+func (r *Retransmissions) Track(publicKey *ecdsa.PublicKey, payload *datasyncproto.Payload, installationIDs []string) (err error) {
+	if len(payload.Messages) == 0 {
+		return nil
+	}
+	if len(installationIDs) == 0 {
+		installationIDs = []string{anyInstallation}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.Prepare(`INSERT INTO datasync_installation_acks (message_id, public_key, installation_id, next_send_at) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	recipient := types.EncodeHex(crypto.FromECDSAPub(publicKey))
+	nextSendAt := r.now().Add(retransmissionDelay(0)).Unix()
+	for _, message := range payload.Messages {
+		id := message.ID()
+		for _, installationID := range installationIDs {
+			if _, err = stmt.Exec(id[:], recipient, installationID, nextSendAt); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// Acked records acknowledgements sent by the installation of the peer.
+func (r *Retransmissions) Acked(publicKey *ecdsa.PublicKey, installationID string, acks [][]byte) (err error) {
+	if len(acks) == 0 {
+		return nil
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.Prepare(`UPDATE datasync_installation_acks SET acked_at = ?
+		WHERE message_id = ? AND public_key = ? AND (installation_id = ? OR installation_id = ?) AND acked_at = 0`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	sender := types.EncodeHex(crypto.FromECDSAPub(publicKey))
+	now := r.now().Unix()
+	for _, ack := range acks {
+		if _, err = stmt.Exec(now, ack, sender, installationID, anyInstallation); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// UnackedInstallations returns installations of the recipient that didn't acknowledge the message yet.
+func (r *Retransmissions) UnackedInstallations(id state.MessageID) ([]string, error) {
+	rows, err := r.db.Query(`SELECT installation_id FROM datasync_installation_acks
+		WHERE message_id = ? AND acked_at = 0 ORDER BY installation_id`, id[:])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []string
+	for rows.Next() {
+		var installationID string
+		if err := rows.Scan(&installationID); err != nil {
+			return nil, err
+		}
+		rst = append(rst, installationID)
+	}
+	return rst, rows.Err()
+}
+
+type dueMessage struct {
+	id        state.MessageID
+	recipient string
+	sendCount int
+}
+
+func (r *Retransmissions) due(now time.Time) ([]dueMessage, error) {
+	rows, err := r.db.Query(`SELECT message_id, public_key, MAX(send_count) FROM datasync_installation_acks
+		WHERE acked_at = 0 AND next_send_at <= ? AND send_count < ?
+		GROUP BY message_id, public_key`, now.Unix(), maxRetransmissions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []dueMessage
+	for rows.Next() {
+		var (
+			m  dueMessage
+			id []byte
+		)
+		if err := rows.Scan(&id, &m.recipient, &m.sendCount); err != nil {
+			return nil, err
+		}
+		copy(m.id[:], id)
+		rst = append(rst, m)
+	}
+	return rst, rows.Err()
+}
+
+// retransmit sends messages that are due to their recipients, one payload per recipient.
+func (r *Retransmissions) retransmit() error {
+	now := r.now()
+	due, err := r.due(now)
+	if err != nil {
+		return err
+	}
+
+	payloads := make(map[string]*datasyncproto.Payload)
+	var sent []dueMessage
+	for _, m := range due {
+		message, err := r.store.Get(m.id)
+		if err == sql.ErrNoRows {
+			r.logger.Warn("retransmitted message does not exist", zap.Binary("messageID", m.id[:4]))
+			if err := r.remove(m.id); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if payloads[m.recipient] == nil {
+			payloads[m.recipient] = &datasyncproto.Payload{}
+		}
+		payloads[m.recipient].Messages = append(payloads[m.recipient].Messages, message)
+		sent = append(sent, m)
+	}
+
+	failed := make(map[string]bool)
+	for recipient, payload := range payloads {
+		if err := r.send(recipient, payload); err != nil {
+			r.logger.Debug("failed to retransmit messages", zap.String("recipient", recipient), zap.Error(err))
+			failed[recipient] = true
+		}
+	}
+
+	for _, m := range sent {
+		if failed[m.recipient] {
+			continue
+		}
+		_, err := r.db.Exec(`UPDATE datasync_installation_acks SET send_count = send_count + 1, next_send_at = ?
+			WHERE message_id = ? AND public_key = ? AND acked_at = 0`,
+			now.Add(retransmissionDelay(m.sendCount+1)).Unix(), m.id[:], m.recipient)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Retransmissions) send(recipient string, payload *datasyncproto.Payload) error {
+	b, err := types.DecodeHex(recipient)
+	if err != nil {
+		return err
+	}
+	publicKey, err := crypto.UnmarshalPubkey(b)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return r.dispatch(context.TODO(), publicKey, data, payload)
+}
+
+func (r *Retransmissions) remove(id state.MessageID) error {
+	_, err := r.db.Exec(`DELETE FROM datasync_installation_acks WHERE message_id = ?`, id[:])
+	return err
+}
@@ -0,0 +1,112 @@
+package datasync
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	datasyncproto "github.com/vacp2p/mvds/protobuf"
+	"github.com/vacp2p/mvds/store"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/sqlite"
+)
+
+func setupRetransmissions(t *testing.T) (*Retransmissions, func()) {
+	dbFile, err := ioutil.TempFile("", "retransmissions")
+	require.NoError(t, err)
+	db, err := sqlite.Open(dbFile.Name(), "")
+	require.NoError(t, err)
+	return NewRetransmissions(db, zap.NewNop()), func() {
+		_ = db.Close()
+		_ = os.Remove(dbFile.Name())
+	}
+}
+
+func TestRetransmissionDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, retransmissionDelay(0))
+	require.Equal(t, time.Minute, retransmissionDelay(1))
+	require.Equal(t, 8*time.Minute, retransmissionDelay(4))
+	require.Equal(t, time.Hour, retransmissionDelay(maxRetransmissions))
+}
+
+func TestRetransmissionsUntilEveryInstallationAcked(t *testing.T) {
+	r, cleanup := setupRetransmissions(t)
+	defer cleanup()
+
+	now := time.Unix(1590000000, 0)
+	r.now = func() time.Time { return now }
+	var sent []*datasyncproto.Payload
+	r.dispatch = func(_ context.Context, _ *ecdsa.PublicKey, _ []byte, payload *datasyncproto.Payload) error {
+		sent = append(sent, payload)
+		return nil
+	}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	first := &datasyncproto.Message{GroupId: []byte{1}, Timestamp: now.Unix(), Body: []byte("first")}
+	second := &datasyncproto.Message{GroupId: []byte{1}, Timestamp: now.Unix(), Body: []byte("second")}
+	messages := store.NewPersistentMessageStore(r.db)
+	require.NoError(t, messages.Add(first))
+	require.NoError(t, messages.Add(second))
+
+	payload := &datasyncproto.Payload{Messages: []*datasyncproto.Message{first, second}}
+	require.NoError(t, r.Track(&key.PublicKey, payload, []string{"a", "b"}))
+	// tracking the same message again keeps its state
+	require.NoError(t, r.Track(&key.PublicKey, payload, []string{"a", "b"}))
+
+	firstID := first.ID()
+	require.NoError(t, r.Acked(&key.PublicKey, "a", [][]byte{firstID[:]}))
+	unacked, err := r.UnackedInstallations(firstID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, unacked)
+
+	// messages are not due yet
+	require.NoError(t, r.retransmit())
+	require.Empty(t, sent)
+
+	now = now.Add(retransmissionDelay(0))
+	require.NoError(t, r.retransmit())
+	require.Len(t, sent, 1)
+	require.Len(t, sent[0].Messages, 2)
+
+	// the next retransmission is delayed
+	require.NoError(t, r.retransmit())
+	require.Len(t, sent, 1)
+
+	secondID := second.ID()
+	require.NoError(t, r.Acked(&key.PublicKey, "b", [][]byte{firstID[:], secondID[:]}))
+	require.NoError(t, r.Acked(&key.PublicKey, "a", [][]byte{secondID[:]}))
+	now = now.Add(retransmissionMaxDelay)
+	require.NoError(t, r.retransmit())
+	require.Len(t, sent, 1)
+}
+
+func TestRetransmissionsOfUnknownInstallations(t *testing.T) {
+	r, cleanup := setupRetransmissions(t)
+	defer cleanup()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	message := &datasyncproto.Message{GroupId: []byte{1}, Timestamp: 1, Body: []byte("message")}
+	require.NoError(t, r.Track(&key.PublicKey, &datasyncproto.Payload{Messages: []*datasyncproto.Message{message}}, nil))
+
+	// acks of another peer are ignored
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	id := message.ID()
+	require.NoError(t, r.Acked(&other.PublicKey, "a", [][]byte{id[:]}))
+	unacked, err := r.UnackedInstallations(id)
+	require.NoError(t, err)
+	require.Equal(t, []string{anyInstallation}, unacked)
+
+	require.NoError(t, r.Acked(&key.PublicKey, "a", [][]byte{id[:]}))
+	unacked, err = r.UnackedInstallations(id)
+	require.NoError(t, err)
+	require.Empty(t, unacked)
+}
@@ -0,0 +1,94 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// bundleCheckInterval is how often we check if our bundle needs a refresh.
+const bundleCheckInterval = 10 * time.Minute
+
+// bundleLifecycle periodically refreshes our bundle, publishes it on the contact code topic
+// when it changes and removes private keys of bundles past the retention period.
+type bundleLifecycle struct {
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+
+	// version is the version of our signed pre key seen by the last check
+	version uint32
+}
+
+func newBundleLifecycle() *bundleLifecycle {
+	return &bundleLifecycle{period: bundleCheckInterval}
+}
+
+// Start starts a loop that calls check immediately and then periodically.
+func (l *bundleLifecycle) Start(check func()) {
+	l.cancel = make(chan struct{})
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		t := time.NewTicker(l.period)
+		defer t.Stop()
+		for {
+			check()
+			select {
+			case <-t.C:
+			case <-l.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop.
+func (l *bundleLifecycle) Stop() {
+	if l.cancel == nil {
+		return
+	}
+	close(l.cancel)
+	l.wg.Wait()
+	l.cancel = nil
+}
+
+// checkBundle refreshes our bundle if it expired and publishes it if its version changed
+// since the previous check. The bundle might also be refreshed when a message is sent.
+func (p *Protocol) checkBundle(myIdentityKey *ecdsa.PrivateKey) {
+	logger := p.logger.With(zap.String("site", "checkBundle"))
+
+	installations, err := p.multidevice.GetOurActiveInstallations(&myIdentityKey.PublicKey)
+	if err != nil {
+		logger.Error("failed to get installations", zap.Error(err))
+		return
+	}
+
+	bundle, err := p.encryptor.CreateBundle(myIdentityKey, installations)
+	if err != nil {
+		logger.Error("failed to refresh bundle", zap.Error(err))
+		return
+	}
+
+	if err := p.encryptor.DeleteExpiredBundleKeys(myIdentityKey); err != nil {
+		logger.Error("failed to delete expired bundle keys", zap.Error(err))
+	}
+
+	version := bundle.GetSignedPreKeys()[p.encryptor.config.InstallationID].GetVersion()
+	previous := p.bundles.version
+	p.bundles.version = version
+	// The publisher announces the bundle after start, we only need to announce refreshes.
+	if previous == 0 || previous == version {
+		return
+	}
+
+	logger.Info("publishing refreshed bundle", zap.Uint32("version", version))
+	messageSpec, err := p.buildContactCodeMessage(myIdentityKey)
+	if err != nil {
+		logger.Error("failed to build contact code message", zap.Error(err))
+		return
+	}
+	p.onSendContactCodeHandler(messageSpec)
+}
@@ -898,6 +898,91 @@ func (s *EncryptionServiceTestSuite) TestRefreshedBundle() {
 	s.Require().NoError(err)
 }
 
+// The bundle is refreshed after it's used to establish a session
+func (s *EncryptionServiceTestSuite) TestBundleRefreshedAfterMaxUses() {
+	config := defaultEncryptorConfig("none", s.logger)
+	config.BundleMaxUses = 1
+
+	s.initDatabases(config)
+
+	bobKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	aliceKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	bobBundle1, err := s.bob.GetBundle(bobKey)
+	s.Require().NoError(err)
+	_, err = s.alice.ProcessPublicBundle(aliceKey, bobBundle1)
+	s.Require().NoError(err)
+
+	response1, err := s.alice.BuildDirectMessage(aliceKey, &bobKey.PublicKey, []byte("first"))
+	s.Require().NoError(err)
+	_, err = s.bob.HandleMessage(bobKey, &aliceKey.PublicKey, response1.Message, defaultMessageID)
+	s.Require().NoError(err)
+
+	bobBundle2, err := s.bob.GetBundle(bobKey)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(2), bobBundle2.GetSignedPreKeys()[bobInstallationID].GetVersion())
+
+	// Messages sent with the expired bundle can still be decrypted
+	response2, err := s.alice.BuildDirectMessage(aliceKey, &bobKey.PublicKey, []byte("second"))
+	s.Require().NoError(err)
+	s.Equal(bobBundle1.GetSignedPreKeys()[bobInstallationID].GetSignedPreKey(), response2.Message.GetDirectMessage()[bobInstallationID].GetX3DHHeader().GetId())
+	decrypted, err := s.bob.HandleMessage(bobKey, &aliceKey.PublicKey, response2.Message, []byte("second"))
+	s.Require().NoError(err)
+	s.Equal([]byte("second"), decrypted)
+}
+
+// Private keys of bundles are deleted after the retention period
+func (s *EncryptionServiceTestSuite) TestExpiredBundleKeysDeleted() {
+	config := defaultEncryptorConfig("none", s.logger)
+	config.BundleRetention = 0
+
+	s.initDatabases(config)
+
+	bobKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	bobBundle, err := s.bob.GetBundle(bobKey)
+	s.Require().NoError(err)
+	bundleID := bobBundle.GetSignedPreKeys()[bobInstallationID].GetSignedPreKey()
+
+	persistence := s.bob.encryptor.persistence
+	s.Require().NoError(s.bob.encryptor.DeleteExpiredBundleKeys(bobKey))
+	privateKey, err := persistence.GetPrivateKeyBundle(bundleID)
+	s.Require().NoError(err)
+	s.NotNil(privateKey, "the bundle is not expired")
+
+	s.Require().NoError(persistence.MarkBundleExpired(bobBundle.GetIdentity()))
+	s.Require().NoError(s.bob.encryptor.DeleteExpiredBundleKeys(bobKey))
+	privateKey, err = persistence.GetPrivateKeyBundle(bundleID)
+	s.Require().NoError(err)
+	s.Nil(privateKey)
+}
+
+// A refreshed bundle is published on the contact code topic
+func (s *EncryptionServiceTestSuite) TestCheckBundlePublishesRefreshedBundle() {
+	var published []*ProtocolMessageSpec
+	s.bob.onSendContactCodeHandler = func(spec *ProtocolMessageSpec) {
+		published = append(published, spec)
+	}
+
+	bobKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	s.bob.checkBundle(bobKey)
+	s.bob.checkBundle(bobKey)
+	s.Require().Empty(published)
+
+	s.Require().NoError(s.bob.encryptor.persistence.MarkBundleExpired(crypto.CompressPubkey(&bobKey.PublicKey)))
+	s.bob.checkBundle(bobKey)
+	s.Require().Len(published, 1)
+	bundles := published[0].Message.GetBundles()
+	s.Require().Len(bundles, 1)
+	s.Equal(uint32(2), bundles[0].GetSignedPreKeys()[bobInstallationID].GetVersion())
+}
+
 func (s *EncryptionServiceTestSuite) TestMessageConfirmation() {
 	bobText1 := []byte("bob text 1")
 
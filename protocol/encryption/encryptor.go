@@ -56,6 +56,11 @@ type encryptorConfig struct {
 	MaxMessageKeysPerSession int
 	// How long before we refresh the interval in milliseconds
 	BundleRefreshInterval int64
+	// How many sessions can be established with a bundle before we refresh it, 0 means no limit.
+	BundleMaxUses int
+	// How long we keep private keys of expired bundles in milliseconds,
+	// so that messages sent to them before the refresh can still be decrypted.
+	BundleRetention int64
 	// The logging object
 	Logger *zap.Logger
 }
@@ -72,6 +77,8 @@ func defaultEncryptorConfig(installationID string, logger *zap.Logger) encryptor
 		MaxKeep:                  3000,
 		MaxMessageKeysPerSession: 2000,
 		BundleRefreshInterval:    24 * 60 * 60 * 1000,
+		BundleMaxUses:            100,
+		BundleRetention:          30 * 24 * 60 * 60 * 1000,
 		InstallationID:           installationID,
 		Logger:                   logger,
 	}
@@ -151,7 +158,10 @@ func (s *encryptor) CreateBundle(privateKey *ecdsa.PrivateKey, installations []*
 		return nil, err
 	}
 
-	expired := bundleContainer != nil && bundleContainer.GetBundle().Timestamp < time.Now().Add(-1*time.Duration(s.config.BundleRefreshInterval)*time.Millisecond).UnixNano()
+	expired, err := s.bundleExpired(bundleContainer)
+	if err != nil {
+		return nil, err
+	}
 
 	// If the bundle has expired we create a new one
 	if expired {
@@ -182,6 +192,35 @@ func (s *encryptor) CreateBundle(privateKey *ecdsa.PrivateKey, installations []*
 	return s.CreateBundle(privateKey, installations)
 }
 
+// bundleExpired returns true if the bundle is older than the refresh interval
+// or it was used to establish too many sessions.
+func (s *encryptor) bundleExpired(bundleContainer *BundleContainer) (bool, error) {
+	if bundleContainer == nil {
+		return false, nil
+	}
+	if bundleContainer.GetBundle().Timestamp < time.Now().Add(-1*time.Duration(s.config.BundleRefreshInterval)*time.Millisecond).UnixNano() {
+		return true, nil
+	}
+	if s.config.BundleMaxUses == 0 {
+		return false, nil
+	}
+	signedPreKey := bundleContainer.GetBundle().GetSignedPreKeys()[s.config.InstallationID]
+	if signedPreKey == nil {
+		return false, nil
+	}
+	uses, err := s.persistence.GetBundleUses(signedPreKey.GetSignedPreKey())
+	if err != nil {
+		return false, err
+	}
+	return uses >= s.config.BundleMaxUses, nil
+}
+
+// DeleteExpiredBundleKeys removes private keys of our bundles which expired longer than the retention period ago
+func (s *encryptor) DeleteExpiredBundleKeys(privateKey *ecdsa.PrivateKey) error {
+	before := time.Now().Add(-1 * time.Duration(s.config.BundleRetention) * time.Millisecond).UnixNano()
+	return s.persistence.DeleteExpiredBundleKeys(crypto.CompressPubkey(&privateKey.PublicKey), before)
+}
+
 // DecryptWithDH decrypts message sent with a DH key exchange, and throws away the key after decryption
 func (s *encryptor) DecryptWithDH(myIdentityKey *ecdsa.PrivateKey, theirEphemeralKey *ecdsa.PublicKey, payload []byte) ([]byte, error) {
 	key, err := PerformDH(
@@ -16,6 +16,8 @@
 // 1559627659_add_contact_code.up.sql (198B)
 // 1561368210_add_installation_metadata.down.sql (35B)
 // 1561368210_add_installation_metadata.up.sql (267B)
+// 1590500000_add_bundles_expired_at.down.sql (44B)
+// 1590500000_add_bundles_expired_at.up.sql (173B)
 // doc.go (377B)
 
 package migrations
@@ -405,6 +407,46 @@ func _1561368210_add_installation_metadataUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1590500000_add_bundles_expired_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x2a\xcd\x4b\xc9\x49\x2d\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xad\x28\xc8\x2c\x4a\x4d\x89\x4f\x2c\xb1\xe6\x02\x00\xba\xda\x04\x8e\x2c\x00\x00\x00")
+
+func _1590500000_add_bundles_expired_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590500000_add_bundles_expired_atDownSql,
+		"1590500000_add_bundles_expired_at.down.sql",
+	)
+}
+
+func _1590500000_add_bundles_expired_atDownSql() (*asset, error) {
+	bytes, err := _1590500000_add_bundles_expired_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590500000_add_bundles_expired_at.down.sql", size: 44, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5b, 0x8b, 0x5e, 0x7e, 0xd0, 0x4, 0x7b, 0xf2, 0x58, 0x4b, 0x54, 0x48, 0x56, 0x92, 0x99, 0xa4, 0xe, 0x4a, 0xc8, 0x41, 0x2e, 0x7b, 0x9d, 0x5d, 0x91, 0x90, 0x45, 0xb7, 0xfc, 0xd2, 0x7f, 0xc1}}
+	return a, nil
+}
+
+var __1590500000_add_bundles_expired_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\xcc\x4d\x0b\x82\x40\x14\x85\xe1\xbd\xbf\xe2\x6c\x62\x34\x5a\xe8\x5a\x5a\xdc\x9c\xdb\x07\x4c\x63\xe8\x1d\x5a\x46\xe1\x04\x42\x59\x38\x46\xfd\xfc\xdc\x48\x9d\xf5\x7b\x1e\x32\xc2\x15\x84\x56\x86\x71\x79\x75\xcd\xcd\x07\x90\xd6\x28\x4a\xe3\xf6\x16\xfe\xf3\x6c\x7b\xdf\x9c\xce\x03\x76\x56\x78\x33\xb6\xb6\x14\x58\x67\x0c\x34\xaf\xc9\x19\x41\x9a\x47\xee\xa0\x49\x7e\x40\xcd\xf2\xff\x5c\xa2\xa0\x5a\xe2\x30\xf4\xd7\xa1\xbd\xfb\x58\xcd\x82\x5a\x40\x75\x8f\xb7\x4a\x40\xf5\x24\x27\x98\x23\x4b\xa7\xe1\xb8\xe5\x8a\x27\x66\x34\xb2\x3c\xfa\x02\x73\xfd\x47\xef\xad\x00\x00\x00")
+
+func _1590500000_add_bundles_expired_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590500000_add_bundles_expired_atUpSql,
+		"1590500000_add_bundles_expired_at.up.sql",
+	)
+}
+
+func _1590500000_add_bundles_expired_atUpSql() (*asset, error) {
+	bytes, err := _1590500000_add_bundles_expired_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590500000_add_bundles_expired_at.up.sql", size: 173, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd, 0x4, 0x9, 0x1b, 0x43, 0xf, 0x86, 0x18, 0x29, 0x37, 0xd1, 0xdd, 0x20, 0xf1, 0xa, 0xf9, 0xc5, 0x53, 0x95, 0xab, 0xe1, 0x21, 0xeb, 0x13, 0x7a, 0xac, 0x3c, 0x77, 0x98, 0xb5, 0xd0, 0xf7}}
+	return a, nil
+}
+
 var _docGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x8f\xbb\x6e\xc3\x30\x0c\x45\x77\x7f\xc5\x45\x96\x2c\xb5\xb4\x74\xea\xd6\xb1\x7b\x7f\x80\x91\x68\x89\x88\x1e\xae\x48\xe7\xf1\xf7\x85\xd3\x02\xcd\xd6\xf5\x00\xe7\xf0\xd2\x7b\x7c\x66\x51\x2c\x52\x18\xa2\x68\x1c\x58\x95\xc6\x1d\x27\x0e\xb4\x29\xe3\x90\xc4\xf2\x76\x72\xa1\x57\xaf\x46\xb6\xe9\x2c\xd5\x57\x49\x83\x8c\xfd\xe5\xf5\x30\x79\x8f\x40\xed\x68\xc8\xd4\x62\xe1\x47\x4b\xa1\x46\xc3\xa4\x25\x5c\xc5\x32\x08\xeb\xe0\x45\x6e\x0e\xef\x86\xc2\xa4\x06\xcb\x64\x47\x85\x65\x46\x20\xe5\x3d\xb3\xf4\x81\xd4\xe7\x93\xb4\x48\x46\x6e\x47\x1f\xcb\x13\xd9\x17\x06\x2a\x85\x23\x96\xd1\xeb\xc3\x55\xaa\x8c\x28\x83\x83\xf5\x71\x7f\x01\xa9\xb2\xa1\x51\x65\xdd\xfd\x4c\x17\x46\xeb\xbf\xe7\x41\x2d\xfe\xff\x11\xae\x7d\x9c\x15\xa4\xe0\xdb\xca\xc1\x38\xba\x69\x5a\x29\x9c\x29\x31\xf4\xab\x88\xf1\x34\x79\x9f\xfa\x5b\xe2\xc6\xbb\xf5\xbc\x71\x5e\xcf\x09\x3f\x35\xe9\x4d\x31\x77\x38\xe7\xff\x80\x4b\x1d\x6e\xfa\x0e\x00\x00\xff\xff\x9d\x60\x3d\x88\x79\x01\x00\x00")
 
 func docGoBytes() ([]byte, error) {
@@ -548,6 +590,10 @@ var _bindata = map[string]func() (*asset, error){
 
 	"1561368210_add_installation_metadata.up.sql": _1561368210_add_installation_metadataUpSql,
 
+	"1590500000_add_bundles_expired_at.down.sql": _1590500000_add_bundles_expired_atDownSql,
+
+	"1590500000_add_bundles_expired_at.up.sql": _1590500000_add_bundles_expired_atUpSql,
+
 	"doc.go": docGo,
 }
 
@@ -555,11 +601,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -608,6 +656,8 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1559627659_add_contact_code.up.sql":            &bintree{_1559627659_add_contact_codeUpSql, map[string]*bintree{}},
 	"1561368210_add_installation_metadata.down.sql": &bintree{_1561368210_add_installation_metadataDownSql, map[string]*bintree{}},
 	"1561368210_add_installation_metadata.up.sql":   &bintree{_1561368210_add_installation_metadataUpSql, map[string]*bintree{}},
+	"1590500000_add_bundles_expired_at.down.sql":    &bintree{_1590500000_add_bundles_expired_atDownSql, map[string]*bintree{}},
+	"1590500000_add_bundles_expired_at.up.sql":      &bintree{_1590500000_add_bundles_expired_atUpSql, map[string]*bintree{}},
 	"doc.go": &bintree{docGo, map[string]*bintree{}},
 }}
 
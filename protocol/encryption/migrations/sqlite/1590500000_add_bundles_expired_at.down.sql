@@ -0,0 +1 @@
+ALTER TABLE bundles DROP COLUMN expired_at;
@@ -0,0 +1,2 @@
+ALTER TABLE bundles ADD COLUMN expired_at INTEGER NOT NULL DEFAULT 0;
+UPDATE bundles SET expired_at = CAST(strftime('%s', 'now') AS INTEGER) * 1000000000 WHERE expired = 1;
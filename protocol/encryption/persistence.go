@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"database/sql"
 	"strings"
+	"time"
 
 	dr "github.com/status-im/doubleratchet"
 
@@ -266,18 +267,41 @@ func (s *sqlitePersistence) GetPrivateKeyBundle(bundleID []byte) ([]byte, error)
 // MarkBundleExpired expires any private bundle for a given identity
 func (s *sqlitePersistence) MarkBundleExpired(identity []byte) error {
 	stmt, err := s.DB.Prepare(`UPDATE bundles
-				   SET expired = 1
-				   WHERE identity = ? AND private_key IS NOT NULL`)
+				   SET expired = 1, expired_at = ?
+				   WHERE identity = ? AND private_key IS NOT NULL AND expired = 0`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(identity)
+	_, err = stmt.Exec(time.Now().UnixNano(), identity)
 
 	return err
 }
 
+// DeleteExpiredBundleKeys removes private keys of bundles of a given identity which expired
+// before the given time in nanoseconds. Public keys are kept as they are referenced by ratchet info.
+func (s *sqlitePersistence) DeleteExpiredBundleKeys(identity []byte, before int64) error {
+	stmt, err := s.DB.Prepare(`UPDATE bundles
+				   SET private_key = NULL
+				   WHERE identity = ? AND private_key IS NOT NULL AND expired = 1 AND expired_at < ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(identity, before)
+
+	return err
+}
+
+// GetBundleUses returns the number of sessions established using a bundle
+func (s *sqlitePersistence) GetBundleUses(bundleID []byte) (int, error) {
+	var uses int
+	err := s.DB.QueryRow(`SELECT COUNT(*) FROM ratchet_info_v2 WHERE bundle_id = ?`, bundleID).Scan(&uses)
+	return uses, err
+}
+
 // GetPublicBundle retrieves an existing Bundle for the specified public key from the database
 func (s *sqlitePersistence) GetPublicBundle(publicKey *ecdsa.PublicKey, installations []*multidevice.Installation) (*Bundle, error) {
 
@@ -70,6 +70,7 @@ type Protocol struct {
 	secret      *sharedsecret.SharedSecret
 	multidevice *multidevice.Multidevice
 	publisher   *publisher.Publisher
+	bundles     *bundleLifecycle
 
 	onAddedBundlesHandler    func([]*multidevice.Installation)
 	onNewSharedSecretHandler func([]*sharedsecret.Secret)
@@ -123,6 +124,7 @@ func NewWithEncryptorConfig(
 			InstallationID:   installationID,
 		}),
 		publisher:                publisher.New(logger),
+		bundles:                  newBundleLifecycle(),
 		onAddedBundlesHandler:    addedBundlesHandler,
 		onNewSharedSecretHandler: onNewSharedSecretHandler,
 		onSendContactCodeHandler: onSendContactCodeHandler,
@@ -155,9 +157,17 @@ func (p *Protocol) Start(myIdentity *ecdsa.PrivateKey) error {
 		}
 	}()
 
+	p.bundles.Start(func() { p.checkBundle(myIdentity) })
+
 	return nil
 }
 
+// Stop stops publishing and refreshing of our bundle.
+func (p *Protocol) Stop() {
+	p.bundles.Stop()
+	p.publisher.Stop()
+}
+
 func (p *Protocol) addBundle(myIdentityKey *ecdsa.PrivateKey, msg *ProtocolMessage) error {
 	logger := p.logger.With(zap.String("site", "addBundle"))
 
@@ -410,6 +420,24 @@ func (p *Protocol) ConfirmMessageProcessed(messageID []byte) error {
 	return p.encryptor.ConfirmMessageProcessed(messageID)
 }
 
+// ExportSecrets returns all negotiated secrets, e.g. for a backup.
+func (p *Protocol) ExportSecrets() ([]*sharedsecret.ExportedSecret, error) {
+	return p.secret.Export()
+}
+
+// ImportSecrets persists negotiated secrets restored from a backup
+// and propagates them so that the corresponding filters get loaded.
+func (p *Protocol) ImportSecrets(secrets []*sharedsecret.ExportedSecret) error {
+	imported, err := p.secret.Import(secrets)
+	if err != nil {
+		return errors.Wrap(err, "failed to import secrets")
+	}
+	if len(imported) > 0 {
+		p.onNewSharedSecretHandler(imported)
+	}
+	return nil
+}
+
 // HandleMessage unmarshals a message and processes it, decrypting it if it is a 1:1 message.
 func (p *Protocol) HandleMessage(
 	myIdentityKey *ecdsa.PrivateKey,
@@ -55,6 +55,10 @@ func (p *Publisher) Start() <-chan struct{} {
 }
 
 func (p *Publisher) Stop() {
+	if p.quit == nil {
+		// not started
+		return
+	}
 	select {
 	case _, ok := <-p.quit:
 		if !ok {
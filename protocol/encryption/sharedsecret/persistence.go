@@ -116,3 +116,23 @@ func (s *sqlitePersistence) All() ([][][]byte, error) {
 
 	return secrets, nil
 }
+
+func (s *sqlitePersistence) InstallationIDs() (map[string][]string, error) {
+	rows, err := s.db.Query("SELECT identity_id, id FROM secret_installation_ids")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var identity []byte
+		var installationID string
+		if err := rows.Scan(&identity, &installationID); err != nil {
+			return nil, err
+		}
+		result[string(identity)] = append(result[string(identity)], installationID)
+	}
+
+	return result, rows.Err()
+}
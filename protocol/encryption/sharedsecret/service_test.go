@@ -119,3 +119,41 @@ func (s *SharedSecretTestSuite) TestAll() {
 	}
 	s.Require().Equal(expected, secrets)
 }
+
+func (s *SharedSecretTestSuite) TestExportImport() {
+	myKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	theirKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	secret, err := s.service.Generate(myKey, &theirKey.PublicKey, "1")
+	s.Require().NoError(err)
+	_, err = s.service.Generate(myKey, &theirKey.PublicKey, "2")
+	s.Require().NoError(err)
+
+	exported, err := s.service.Export()
+	s.Require().NoError(err)
+	s.Require().Len(exported, 1)
+	s.Require().ElementsMatch([]string{"1", "2"}, exported[0].InstallationIDs)
+
+	dbFile, err := ioutil.TempFile(os.TempDir(), "sharedsecret")
+	s.Require().NoError(err)
+	defer os.Remove(dbFile.Name())
+
+	db, err := sqlite.Open(dbFile.Name(), "")
+	s.Require().NoError(err)
+	restored := New(db, s.logger)
+
+	imported, err := restored.Import(exported)
+	s.Require().NoError(err)
+	s.Require().Len(imported, 1)
+	s.Require().Equal(secret.Key, imported[0].Key)
+	s.Require().Equal(crypto.FromECDSAPub(&theirKey.PublicKey), crypto.FromECDSAPub(imported[0].Identity))
+
+	// The restored secret is acknowledged by the same installations.
+	agreedSecret, agreed, err := restored.Agreed(myKey, "our", &theirKey.PublicKey, []string{"1", "2"})
+	s.Require().NoError(err)
+	s.Require().True(agreed)
+	s.Require().Equal(secret.Key, agreedSecret.Key)
+}
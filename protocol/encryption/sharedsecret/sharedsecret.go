@@ -126,3 +126,58 @@ func (s *SharedSecret) All() ([]*Secret, error) {
 
 	return secrets, nil
 }
+
+// ExportedSecret is a persisted secret together with the installations
+// that acknowledged it. Identity is a compressed public key.
+type ExportedSecret struct {
+	Identity        []byte   `json:"identity"`
+	Key             []byte   `json:"key"`
+	InstallationIDs []string `json:"installationIds"`
+}
+
+// Export returns all persisted secrets with their installation IDs.
+func (s *SharedSecret) Export() ([]*ExportedSecret, error) {
+	tuples, err := s.persistence.All()
+	if err != nil {
+		return nil, err
+	}
+
+	installationIDs, err := s.persistence.InstallationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []*ExportedSecret
+	for _, tuple := range tuples {
+		secrets = append(secrets, &ExportedSecret{
+			Identity:        tuple[0],
+			Key:             tuple[1],
+			InstallationIDs: installationIDs[string(tuple[0])],
+		})
+	}
+
+	return secrets, nil
+}
+
+// Import persists previously exported secrets and returns them
+// in the form used for loading negotiated filters.
+// Secrets already stored for an identity are kept.
+func (s *SharedSecret) Import(secrets []*ExportedSecret) ([]*Secret, error) {
+	var result []*Secret
+	for _, secret := range secrets {
+		key, err := crypto.DecompressPubkey(secret.Identity)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, installationID := range secret.InstallationIDs {
+			if err := s.persistence.Add(secret.Identity, secret.Key, installationID); err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, &Secret{Identity: key, Key: secret.Key})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+// filterStatsCheckPeriod is how often filters are checked for silence.
+const filterStatsCheckPeriod = time.Minute
+
+// FilterStats describes envelopes received by a filter since the messenger was started.
+type FilterStats struct {
+	ChatID   string          `json:"chatId"`
+	FilterID string          `json:"filterId"`
+	Topic    types.TopicType `json:"topic"`
+	// Listen is whether messages are expected on the filter
+	Listen bool `json:"listen"`
+	// Received is the number of received envelopes
+	Received uint64 `json:"received"`
+	// DecryptionFailures is the number of encrypted envelopes that could not be decrypted
+	DecryptionFailures uint64 `json:"decryptionFailures"`
+	// LastMessageAt is the time in milliseconds the last envelope was received, zero if none
+	LastMessageAt uint64 `json:"lastMessageAt"`
+}
+
+// FilterStatsHandler is notified about filters that stopped receiving envelopes.
+type FilterStatsHandler interface {
+	FilterSilent(stats FilterStats)
+}
+
+// FilterStatsConfig enables warnings about silent filters.
+type FilterStatsConfig struct {
+	// SilencePeriod is how long a listening filter may receive nothing before
+	// the handler is notified. The handler is notified once until a new envelope is received.
+	SilencePeriod time.Duration
+	Handler       FilterStatsHandler
+}
+
+type filterStatsEntry struct {
+	stats FilterStats
+	// watchedSince is when the filter was first seen, silence is measured from it if nothing was received
+	watchedSince time.Time
+	lastMessage  time.Time
+	warned       bool
+}
+
+// filterStats counts envelopes received by filters.
+type filterStats struct {
+	config *FilterStatsConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*filterStatsEntry
+
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newFilterStats(config *FilterStatsConfig) *filterStats {
+	return &filterStats{
+		config:  config,
+		now:     time.Now,
+		entries: make(map[string]*filterStatsEntry),
+	}
+}
+
+// entry returns the entry of a filter and updates its description. Must be called with the lock held.
+func (s *filterStats) entry(filter *transport.Filter) *filterStatsEntry {
+	e, ok := s.entries[filter.ChatID]
+	if !ok {
+		e = &filterStatsEntry{watchedSince: s.now()}
+		s.entries[filter.ChatID] = e
+	}
+	e.stats.ChatID = filter.ChatID
+	e.stats.FilterID = filter.FilterID
+	e.stats.Topic = filter.Topic
+	e.stats.Listen = filter.Listen
+	return e
+}
+
+// received counts envelopes received by a filter.
+func (s *filterStats) received(filter transport.Filter, envelopes int) {
+	if envelopes == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entry(&filter)
+	e.stats.Received += uint64(envelopes)
+	e.lastMessage = s.now()
+	e.stats.LastMessageAt = uint64(e.lastMessage.UnixNano() / int64(time.Millisecond))
+	e.warned = false
+}
+
+// decryptionFailed counts an envelope received by a filter that could not be decrypted.
+func (s *filterStats) decryptionFailed(filter transport.Filter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(&filter).stats.DecryptionFailures++
+}
+
+// Stats returns stats of given filters ordered by chat ID. Stats of other filters are removed.
+func (s *filterStats) Stats(filters []*transport.Filter) []FilterStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sync(filters)
+	rst := make([]FilterStats, 0, len(s.entries))
+	for _, e := range s.entries {
+		rst = append(rst, e.stats)
+	}
+	sort.Slice(rst, func(i, j int) bool { return rst[i].ChatID < rst[j].ChatID })
+	return rst
+}
+
+// sync adds entries of new filters and removes entries of removed ones. Must be called with the lock held.
+func (s *filterStats) sync(filters []*transport.Filter) {
+	current := make(map[string]bool, len(filters))
+	for _, filter := range filters {
+		current[filter.ChatID] = true
+		s.entry(filter)
+	}
+	for chatID := range s.entries {
+		if !current[chatID] {
+			delete(s.entries, chatID)
+		}
+	}
+}
+
+// silent returns listening filters that received nothing within the silence period
+// and were not reported yet. Returned filters are marked as reported.
+func (s *filterStats) silent(filters []*transport.Filter) []FilterStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sync(filters)
+	now := s.now()
+	var rst []FilterStats
+	for _, e := range s.entries {
+		if !e.stats.Listen || e.warned {
+			continue
+		}
+		since := e.watchedSince
+		if e.lastMessage.After(since) {
+			since = e.lastMessage
+		}
+		if now.Sub(since) >= s.config.SilencePeriod {
+			e.warned = true
+			rst = append(rst, e.stats)
+		}
+	}
+	sort.Slice(rst, func(i, j int) bool { return rst[i].ChatID < rst[j].ChatID })
+	return rst
+}
+
+// Start starts a loop that notifies the handler about silent filters, it's a no-op if warnings are disabled.
+func (s *filterStats) Start(filters func() []*transport.Filter) {
+	if s.config == nil || s.config.SilencePeriod <= 0 || s.config.Handler == nil {
+		return
+	}
+	period := filterStatsCheckPeriod
+	if s.config.SilencePeriod < period {
+		period = s.config.SilencePeriod
+	}
+	s.cancel = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t := time.NewTicker(period)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				for _, stats := range s.silent(filters()) {
+					s.config.Handler.FilterSilent(stats)
+				}
+			case <-s.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop.
+func (s *filterStats) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	close(s.cancel)
+	s.wg.Wait()
+	s.cancel = nil
+}
+
+// FilterStats returns stats of envelopes received by current filters since the messenger was started.
+func (m *Messenger) FilterStats() []FilterStats {
+	return m.filterStats.Stats(m.transport.Filters())
+}
@@ -0,0 +1,85 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+type silentFilters struct {
+	silent []FilterStats
+}
+
+func (h *silentFilters) FilterSilent(stats FilterStats) {
+	h.silent = append(h.silent, stats)
+}
+
+func TestFilterStatsCounts(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stats := newFilterStats(nil)
+	stats.now = func() time.Time { return now }
+	public := &transport.Filter{ChatID: "status", FilterID: "1", Listen: true}
+	private := &transport.Filter{ChatID: "0x04", FilterID: "2", OneToOne: true, Listen: true}
+
+	stats.received(*public, 2)
+	stats.received(*private, 1)
+	stats.decryptionFailed(*private)
+	stats.received(*private, 0)
+
+	rst := stats.Stats([]*transport.Filter{public, private})
+	require.Equal(t, []FilterStats{
+		{ChatID: "0x04", FilterID: "2", Listen: true, Received: 1, DecryptionFailures: 1, LastMessageAt: 1000000},
+		{ChatID: "status", FilterID: "1", Listen: true, Received: 2, LastMessageAt: 1000000},
+	}, rst)
+
+	// stats of removed filters are dropped
+	rst = stats.Stats([]*transport.Filter{public})
+	require.Len(t, rst, 1)
+	require.Equal(t, "status", rst[0].ChatID)
+}
+
+func TestFilterStatsSilent(t *testing.T) {
+	now := time.Unix(1000, 0)
+	stats := newFilterStats(&FilterStatsConfig{SilencePeriod: time.Hour})
+	stats.now = func() time.Time { return now }
+	active := &transport.Filter{ChatID: "status", Listen: true}
+	idle := &transport.Filter{ChatID: "idle", Listen: true}
+	sendOnly := &transport.Filter{ChatID: "send-only"}
+	filters := []*transport.Filter{active, idle, sendOnly}
+
+	require.Empty(t, stats.silent(filters))
+
+	now = now.Add(30 * time.Minute)
+	stats.received(*active, 1)
+	now = now.Add(31 * time.Minute)
+	silent := stats.silent(filters)
+	require.Len(t, silent, 1)
+	require.Equal(t, "idle", silent[0].ChatID)
+	// reported once
+	require.Empty(t, stats.silent(filters))
+
+	now = now.Add(time.Hour)
+	silent = stats.silent(filters)
+	require.Len(t, silent, 1)
+	require.Equal(t, "status", silent[0].ChatID)
+
+	stats.received(*idle, 1)
+	now = now.Add(time.Hour)
+	silent = stats.silent(filters)
+	require.Len(t, silent, 1)
+	require.Equal(t, "idle", silent[0].ChatID)
+}
+
+func TestFilterStatsStartNotifiesHandler(t *testing.T) {
+	handler := &silentFilters{}
+	stats := newFilterStats(&FilterStatsConfig{SilencePeriod: 10 * time.Millisecond, Handler: handler})
+	filter := &transport.Filter{ChatID: "status", Listen: true}
+	stats.Start(func() []*transport.Filter { return []*transport.Filter{filter} })
+	time.Sleep(50 * time.Millisecond)
+	stats.Stop()
+	require.Len(t, handler.silent, 1)
+	require.Equal(t, "status", handler.silent[0].ChatID)
+}
@@ -0,0 +1,175 @@
+package protocol
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/encryption/sharedsecret"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+const (
+	keysBackupVersion = 1
+
+	keysBackupSaltLength = 32
+	keysBackupKeyLength  = 32
+	keysBackupScryptN    = 1 << 15
+	keysBackupScryptR    = 8
+	keysBackupScryptP    = 1
+)
+
+// ErrInvalidKeysBackup is returned when a keys backup can't be decrypted
+// or decoded, most likely because of a wrong password.
+var ErrInvalidKeysBackup = errors.New("invalid keys backup or wrong password")
+
+// keysBackup is the content of an exported keys blob.
+type keysBackup struct {
+	Version int `json:"version"`
+	// SymmetricKeys are keys of symmetric filters indexed by chat ID.
+	SymmetricKeys map[string][]byte `json:"symmetricKeys"`
+	// Secrets are negotiated secrets with their installation IDs.
+	Secrets []*sharedsecret.ExportedSecret `json:"secrets"`
+	// PublicChats are chat IDs of symmetric filters.
+	PublicChats []string `json:"publicChats"`
+	// OneToOne are hex-encoded public keys of one-to-one filters.
+	OneToOne []string `json:"oneToOne"`
+}
+
+// ExportKeys returns all persisted symmetric keys, negotiated secrets
+// and filter definitions encrypted with a key derived from the password.
+func (m *Messenger) ExportKeys(password string) ([]byte, error) {
+	backup, err := m.keysBackup()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptBackup(password, plaintext)
+}
+
+// ImportKeys restores keys and filters from a blob created by ExportKeys.
+// Keys already present are left untouched.
+func (m *Messenger) ImportKeys(blob []byte, password string) error {
+	plaintext, err := decryptBackup(blob, password)
+	if err != nil {
+		return err
+	}
+
+	var backup keysBackup
+	if err := json.Unmarshal(plaintext, &backup); err != nil {
+		return ErrInvalidKeysBackup
+	}
+
+	return m.restoreKeys(&backup)
+}
+
+func (m *Messenger) keysBackup() (*keysBackup, error) {
+	secrets, err := m.encryptor.ExportSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &keysBackup{
+		Version:       keysBackupVersion,
+		SymmetricKeys: m.transport.SymmetricKeys(),
+		Secrets:       secrets,
+	}
+
+	identities := make(map[string]bool)
+	for _, filter := range m.transport.Filters() {
+		switch {
+		case filter.OneToOne:
+			// Partitioned and discovery filters are derived from public keys
+			// and negotiated filters are restored from secrets.
+			continue
+		case filter.Identity != "":
+			// Contact code filter of a one-to-one chat.
+			if !identities[filter.Identity] {
+				identities[filter.Identity] = true
+				backup.OneToOne = append(backup.OneToOne, filter.Identity)
+			}
+		case filter.ChatID != "":
+			backup.PublicChats = append(backup.PublicChats, filter.ChatID)
+		}
+	}
+
+	return backup, nil
+}
+
+func (m *Messenger) restoreKeys(backup *keysBackup) error {
+	if backup.Version != keysBackupVersion {
+		return errors.Errorf("unsupported keys backup version %d", backup.Version)
+	}
+
+	// Keys must be restored before filters are created,
+	// otherwise new keys would be derived and persisted.
+	if err := m.transport.ImportSymmetricKeys(backup.SymmetricKeys); err != nil {
+		return errors.Wrap(err, "failed to import symmetric keys")
+	}
+
+	var publicKeys []*ecdsa.PublicKey
+	for _, identity := range backup.OneToOne {
+		publicKey, err := transport.StrToPublicKey(identity)
+		if err != nil {
+			return err
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if _, err := m.transport.InitFilters(backup.PublicChats, publicKeys); err != nil {
+		return errors.Wrap(err, "failed to load filters")
+	}
+
+	return m.encryptor.ImportSecrets(backup.Secrets)
+}
+
+// encryptBackup encrypts the plaintext with a key derived from the password
+// and prefixes it with the random salt used for the derivation.
+func encryptBackup(password string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, keysBackupSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKeysBackupKey(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := crypto.EncryptSymmetric(key, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(salt, encrypted...), nil
+}
+
+func decryptBackup(blob []byte, password string) ([]byte, error) {
+	if len(blob) <= keysBackupSaltLength {
+		return nil, ErrInvalidKeysBackup
+	}
+
+	key, err := deriveKeysBackupKey(password, blob[:keysBackupSaltLength])
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := crypto.DecryptSymmetric(key, blob[keysBackupSaltLength:])
+	if err != nil {
+		return nil, ErrInvalidKeysBackup
+	}
+	return plaintext, nil
+}
+
+func deriveKeysBackupKey(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, keysBackupScryptN, keysBackupScryptR, keysBackupScryptP, keysBackupKeyLength)
+}
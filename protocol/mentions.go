@@ -0,0 +1,161 @@
+package protocol
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// maxMentions is the maximum number of mentions returned at once.
+const maxMentions = 100
+
+// ErrInvalidMentionName is returned when a mention name is empty or too long.
+var ErrInvalidMentionName = errors.New("invalid mention name")
+
+// Mention is a message of a public chat that mentions the user.
+type Mention struct {
+	ChatID    string `json:"chatId"`
+	MessageID string `json:"messageId"`
+	// From is the public key of the author of the message
+	From      string `json:"from"`
+	Clock     uint64 `json:"clock"`
+	Timestamp uint64 `json:"timestamp"`
+	// Muted is set if the chat is muted, so that the client doesn't notify the user
+	Muted bool `json:"muted"`
+	// Message is the message with the mention, nil if it was deleted
+	Message *Message `json:"message,omitempty"`
+}
+
+// mentionMatcher finds messages mentioning the user with @ followed by the public key,
+// the display name of the chat identity or one of names registered by the client,
+// such as ENS names. Names are matched regardless of the case.
+type mentionMatcher struct {
+	publicKey string
+
+	mu          sync.RWMutex
+	displayName string
+	names       []string
+}
+
+func newMentionMatcher(publicKey string) *mentionMatcher {
+	return &mentionMatcher{publicKey: publicKey}
+}
+
+func (m *mentionMatcher) setDisplayName(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.displayName = name
+}
+
+func (m *mentionMatcher) setNames(names []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names = names
+}
+
+// targets returns lower-cased texts that mention the user.
+func (m *mentionMatcher) targets() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rst := []string{"@" + strings.ToLower(m.publicKey)}
+	if m.displayName != "" {
+		rst = append(rst, "@"+strings.ToLower(m.displayName))
+	}
+	for _, name := range m.names {
+		rst = append(rst, "@"+strings.ToLower(name))
+	}
+	return rst
+}
+
+// Find returns mentions in messages of public chats received from other users.
+func (m *mentionMatcher) Find(messages []*Message) []*Mention {
+	var (
+		rst     []*Mention
+		targets = m.targets()
+	)
+	for _, message := range messages {
+		if message.MessageType != protobuf.ChatMessage_PUBLIC_GROUP || message.From == m.publicKey {
+			continue
+		}
+		if !mentions(strings.ToLower(message.Text), targets) {
+			continue
+		}
+		rst = append(rst, &Mention{
+			ChatID:    message.LocalChatID,
+			MessageID: message.ID,
+			From:      message.From,
+			Clock:     message.Clock,
+			Timestamp: message.Timestamp,
+			Message:   message,
+		})
+	}
+	return rst
+}
+
+// mentions checks that one of targets is in the text and is not a prefix of a longer word,
+// e.g. @alice doesn't mention alice in @alice2.
+func mentions(text string, targets []string) bool {
+	for _, target := range targets {
+		for offset := 0; ; {
+			idx := strings.Index(text[offset:], target)
+			if idx < 0 {
+				break
+			}
+			end := offset + idx + len(target)
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if end == len(text) || !(unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_') {
+				return true
+			}
+			offset = end
+		}
+	}
+	return false
+}
+
+func validateMentionNames(names []string) error {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" || len(name) > maxChatIdentityDisplayNameLength {
+			return ErrInvalidMentionName
+		}
+	}
+	return nil
+}
+
+// SetMentionNames replaces names mentioning the user in addition to the public key
+// and the display name, e.g. ENS names of the user.
+func (m *Messenger) SetMentionNames(names []string) error {
+	if err := validateMentionNames(names); err != nil {
+		return err
+	}
+	if err := m.persistence.SaveMentionNames(names); err != nil {
+		return err
+	}
+	m.mentions.setNames(names)
+	return nil
+}
+
+// MentionNames returns names set with SetMentionNames.
+func (m *Messenger) MentionNames() ([]string, error) {
+	return m.persistence.MentionNames()
+}
+
+// Mentions returns at most limit messages mentioning the user in a public chat, or in all
+// public chats if chatID is empty, the most recent first.
+func (m *Messenger) Mentions(chatID string, limit int) ([]*Mention, error) {
+	if limit <= 0 || limit > maxMentions {
+		limit = maxMentions
+	}
+	rst, err := m.persistence.Mentions(chatID, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, mention := range rst {
+		mention.Muted = m.blockList.isMuted(mention.ChatID)
+	}
+	return rst, nil
+}
@@ -260,6 +260,60 @@ func (m *MessageHandler) HandleContactUpdate(state *ReceivedMessageState, messag
 	return nil
 }
 
+// HandleContactRequest stores a received contact request. A request from a user
+// that we have already added is a response to our own request and is accepted right away.
+func (m *MessageHandler) HandleContactRequest(state *ReceivedMessageState, message protobuf.ContactRequest) error {
+	logger := m.logger.With(zap.String("site", "HandleContactRequest"))
+	contact := state.CurrentMessageState.Contact
+	if contact.ID == contactIDFromPublicKey(&m.identity.PublicKey) {
+		return errors.New("can't handle our own contact request")
+	}
+
+	chat, ok := state.AllChats[contact.ID]
+	if !ok {
+		chat = OneToOneFromPublicKey(state.CurrentMessageState.PublicKey, state.Timesource)
+		// We don't want to show the chat to the user
+		chat.Active = false
+	}
+
+	request, ok := state.AllContactRequests[contact.ID]
+	if ok && request.Clock >= message.Clock {
+		logger.Debug("ignoring outdated contact request")
+		return nil
+	}
+	if !ok {
+		request = &ContactRequest{ID: contact.ID}
+	}
+
+	logger.Info("Handling contact request")
+
+	request.Message = message.Message
+	request.Clock = message.Clock
+	request.Timestamp = state.CurrentMessageState.WhisperTimestamp
+	if contact.IsAdded() {
+		request.State = ContactRequestStateAccepted
+	} else {
+		request.State = ContactRequestStatePending
+	}
+	state.AllContactRequests[contact.ID] = request
+	state.ModifiedContactRequests[contact.ID] = true
+
+	if !contact.HasBeenAdded() && !stringSliceContains(contact.SystemTags, contactRequestReceived) {
+		contact.SystemTags = append(contact.SystemTags, contactRequestReceived)
+		state.ModifiedContacts[contact.ID] = true
+		state.AllContacts[contact.ID] = contact
+	}
+
+	if chat.LastClockValue < message.Clock {
+		chat.LastClockValue = message.Clock
+	}
+
+	state.ModifiedChats[chat.ID] = true
+	state.AllChats[chat.ID] = chat
+
+	return nil
+}
+
 func (m *MessageHandler) HandlePairInstallation(state *ReceivedMessageState, message protobuf.PairInstallation) error {
 	logger := m.logger.With(zap.String("site", "HandlePairInstallation"))
 	if err := ValidateReceivedPairInstallation(&message, state.CurrentMessageState.WhisperTimestamp); err != nil {
@@ -658,3 +712,60 @@ func (m *MessageHandler) messageExists(messageID string, existingMessagesMap map
 	}
 	return false, nil
 }
+
+// HandlePublicChatsAnnouncement records chats announced by another user in the public chats directory.
+func (m *MessageHandler) HandlePublicChatsAnnouncement(state *ReceivedMessageState, message protobuf.PublicChatsAnnouncement) error {
+	contact := state.CurrentMessageState.Contact
+	if contact.ID == contactIDFromPublicKey(&m.identity.PublicKey) {
+		// Our own announcements are not counted
+		return nil
+	}
+
+	if err := ValidateReceivedPublicChatsAnnouncement(&message, state.CurrentMessageState.WhisperTimestamp); err != nil {
+		return err
+	}
+
+	return m.persistence.SavePublicChatsAnnouncement(contact.ID, message.Clock, state.CurrentMessageState.WhisperTimestamp, message.Chats)
+}
+
+// HandleReaction stores a reaction to a message, reactions are aggregated per message.
+func (m *MessageHandler) HandleReaction(state *ReceivedMessageState, message protobuf.Reaction) error {
+	if err := ValidateReceivedReaction(&message, state.CurrentMessageState.WhisperTimestamp); err != nil {
+		return err
+	}
+
+	author := state.CurrentMessageState.Contact.ID
+	chatID := message.ChatId
+	if message.MessageType == protobuf.ChatMessage_ONE_TO_ONE && author != contactIDFromPublicKey(&m.identity.PublicKey) {
+		// ChatID of an incoming private reaction is calculated from the signature.
+		chatID = author
+	}
+
+	return m.persistence.SaveReaction(chatID, message.MessageId, author, message.Reaction, message.Clock, message.Retracted)
+}
+
+// HandlePinMessage updates the set of pinned messages of a chat if the author is allowed to pin messages in it.
+func (m *MessageHandler) HandlePinMessage(state *ReceivedMessageState, message protobuf.PinMessage) error {
+	if err := ValidateReceivedPinMessage(&message, state.CurrentMessageState.WhisperTimestamp); err != nil {
+		return err
+	}
+
+	author := state.CurrentMessageState.Contact.ID
+	chatID := message.ChatId
+	if message.MessageType == protobuf.ChatMessage_ONE_TO_ONE {
+		if author != contactIDFromPublicKey(&m.identity.PublicKey) {
+			// ChatID of an incoming private pin is calculated from the signature.
+			chatID = author
+		}
+	} else {
+		chat, ok := state.AllChats[chatID]
+		if !ok {
+			return errors.New("chat not found")
+		}
+		if err := authorizePin(chat, author); err != nil {
+			return err
+		}
+	}
+
+	return m.persistence.SavePinnedMessage(chatID, message.MessageId, author, message.Clock, message.Pinned)
+}
@@ -27,14 +27,19 @@ const (
 	whisperTTL     = 15
 	whisperPoW     = 0.002
 	whisperPoWTime = 5
+	// ephemeralTTL is used for messages that are meaningful only for a few seconds.
+	ephemeralTTL = 5
+	// retransmissionInterval is how often unacknowledged direct messages are checked.
+	retransmissionInterval = 5 * time.Second
 )
 
 type messageProcessor struct {
-	identity  *ecdsa.PrivateKey
-	datasync  *datasync.DataSync
-	protocol  *encryption.Protocol
-	transport transport.Transport
-	logger    *zap.Logger
+	identity    *ecdsa.PrivateKey
+	datasync    *datasync.DataSync
+	protocol    *encryption.Protocol
+	transport   transport.Transport
+	persistence *sqlitePersistence
+	logger      *zap.Logger
 
 	featureFlags featureFlags
 }
@@ -59,13 +64,15 @@ func newMessageProcessor(
 	if err != nil {
 		return nil, err
 	}
-	ds := datasync.New(dataSyncNode, dataSyncTransport, features.datasync, logger)
+	retransmissions := datasync.NewRetransmissions(database, logger)
+	ds := datasync.New(dataSyncNode, dataSyncTransport, retransmissions, features.datasync, logger)
 
 	p := &messageProcessor{
 		identity:     identity,
 		datasync:     ds,
 		protocol:     enc,
 		transport:    transport,
+		persistence:  &sqlitePersistence{db: database},
 		logger:       logger,
 		featureFlags: features,
 	}
@@ -77,6 +84,7 @@ func newMessageProcessor(
 	if features.datasync {
 		ds.Init(p.sendDataSync)
 		ds.Start(300 * time.Millisecond)
+		retransmissions.Start(p.sendDataSync, retransmissionInterval)
 	}
 
 	return p, nil
@@ -161,7 +169,10 @@ func (p *messageProcessor) sendPrivate(
 
 		hash, newMessage, err := p.sendMessageSpec(ctx, recipient, messageSpec)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to send a message spec")
+			if err := p.postpone([][]byte{messageID}, newMessage, err); err != nil {
+				return nil, errors.Wrap(err, "failed to send a message spec")
+			}
+			return messageID, nil
 		}
 
 		p.transport.Track([][]byte{messageID}, hash, newMessage)
@@ -170,6 +181,39 @@ func (p *messageProcessor) sendPrivate(
 	return messageID, nil
 }
 
+// SendEphemeral encrypts and sends data to the recipients with a short TTL.
+// Ephemeral messages don't go through datasync, are not tracked and are not
+// postponed, a message that fails to be posted is dropped.
+func (p *messageProcessor) SendEphemeral(
+	ctx context.Context,
+	recipients []*ecdsa.PublicKey,
+	data []byte,
+	messageType protobuf.ApplicationMetadataMessage_Type,
+) error {
+	wrappedMessage, err := p.wrapMessageV1(data, messageType)
+	if err != nil {
+		return errors.Wrap(err, "failed to wrap message")
+	}
+
+	for _, recipient := range recipients {
+		messageSpec, err := p.protocol.BuildDirectMessage(p.identity, recipient, wrappedMessage)
+		if err != nil {
+			return errors.Wrap(err, "failed to encrypt message")
+		}
+
+		newMessage, err := messageSpecToWhisper(messageSpec)
+		if err != nil {
+			return err
+		}
+		newMessage.TTL = ephemeralTTL
+
+		if _, err := p.postMessageSpec(ctx, recipient, messageSpec, newMessage); err != nil {
+			return errors.Wrap(err, "failed to send ephemeral message")
+		}
+	}
+	return nil
+}
+
 // sendPairInstallation sends data to the recipients, using DH
 func (p *messageProcessor) SendPairInstallation(
 	ctx context.Context,
@@ -189,12 +233,16 @@ func (p *messageProcessor) SendPairInstallation(
 		return nil, errors.Wrap(err, "failed to encrypt message")
 	}
 
+	messageID := v1protocol.MessageID(&p.identity.PublicKey, wrappedMessage)
+
 	hash, newMessage, err := p.sendMessageSpec(ctx, recipient, messageSpec)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to send a message spec")
+		if err := p.postpone([][]byte{messageID}, newMessage, err); err != nil {
+			return nil, errors.Wrap(err, "failed to send a message spec")
+		}
+		return messageID, nil
 	}
 
-	messageID := v1protocol.MessageID(&p.identity.PublicKey, wrappedMessage)
 	p.transport.Track([][]byte{messageID}, hash, newMessage)
 
 	return messageID, nil
@@ -239,14 +287,19 @@ func (p *messageProcessor) SendPublicRaw(
 		PowTime:   whisperPoWTime,
 	}
 
-	hash, err := p.transport.SendPublic(ctx, newMessage, chatName)
+	messageID := v1protocol.MessageID(&p.identity.PublicKey, wrappedMessage)
+
+	hash, sentMessage, err := p.sendSegmented(newMessage, func(m *types.NewMessage) ([]byte, error) {
+		return p.transport.SendPublic(ctx, m, chatName)
+	})
 	if err != nil {
-		return nil, err
+		if err := p.postpone([][]byte{messageID}, newMessage, err); err != nil {
+			return nil, err
+		}
+		return messageID, nil
 	}
 
-	messageID := v1protocol.MessageID(&p.identity.PublicKey, wrappedMessage)
-
-	p.transport.Track([][]byte{messageID}, hash, newMessage)
+	p.transport.Track([][]byte{messageID}, hash, sentMessage)
 
 	return messageID, nil
 }
@@ -267,6 +320,15 @@ func (p *messageProcessor) handleMessages(shhMessage *types.Message, application
 		return nil, err
 	}
 
+	err = p.handleSegmentationLayer(&statusMessage)
+	if err == ErrMessageSegmentsIncomplete {
+		return nil, nil
+	}
+	if err != nil {
+		hlogger.Debug("failed to handle segmentation layer message", zap.Error(err))
+		return nil, err
+	}
+
 	err = p.handleEncryptionLayer(context.Background(), &statusMessage)
 	if err != nil {
 		hlogger.Debug("failed to handle an encryption message", zap.Error(err))
@@ -384,35 +446,68 @@ func (p *messageProcessor) sendDataSync(ctx context.Context, publicKey *ecdsa.Pu
 		return err
 	}
 
+	installationIDs := make([]string, 0, len(messageSpec.Installations))
+	for _, installation := range messageSpec.Installations {
+		installationIDs = append(installationIDs, installation.ID)
+	}
+	if err := p.datasync.Retransmissions.Track(publicKey, payload, installationIDs); err != nil {
+		p.logger.Error("failed to track datasync messages", zap.Error(err))
+	}
+
 	p.transport.Track(messageIDs, hash, newMessage)
 
 	return nil
 }
 
 // sendMessageSpec analyses the spec properties and selects a proper transport method.
+// Large messages are sent in segments and the last segment is returned to be tracked.
 func (p *messageProcessor) sendMessageSpec(ctx context.Context, publicKey *ecdsa.PublicKey, messageSpec *encryption.ProtocolMessageSpec) ([]byte, *types.NewMessage, error) {
 	newMessage, err := messageSpecToWhisper(messageSpec)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	logger := p.logger.With(zap.String("site", "sendMessageSpec"))
+	hash, sentMessage, err := p.sendSegmented(newMessage, func(m *types.NewMessage) ([]byte, error) {
+		return p.postMessageSpec(ctx, publicKey, messageSpec, m)
+	})
+	if err != nil {
+		// the message is returned so that it can be postponed
+		return nil, newMessage, err
+	}
+
+	return hash, sentMessage, nil
+}
 
-	var hash []byte
+// postMessageSpec posts a message built from the spec using a shared secret
+// if it was negotiated or a partitioned topic otherwise.
+func (p *messageProcessor) postMessageSpec(ctx context.Context, publicKey *ecdsa.PublicKey, messageSpec *encryption.ProtocolMessageSpec, newMessage *types.NewMessage) ([]byte, error) {
+	logger := p.logger.With(zap.String("site", "sendMessageSpec"))
 
 	switch {
 	case messageSpec.SharedSecret != nil:
 		logger.Debug("sending using shared secret")
-		hash, err = p.transport.SendPrivateWithSharedSecret(ctx, newMessage, publicKey, messageSpec.SharedSecret)
+		return p.transport.SendPrivateWithSharedSecret(ctx, newMessage, publicKey, messageSpec.SharedSecret)
 	default:
 		logger.Debug("sending partitioned topic")
-		hash, err = p.transport.SendPrivateWithPartitioned(ctx, newMessage, publicKey)
-	}
-	if err != nil {
-		return nil, nil, err
+		return p.transport.SendPrivateWithPartitioned(ctx, newMessage, publicKey)
 	}
+}
 
-	return hash, newMessage, nil
+// postpone passes a message that failed to be posted to the outbox.
+// The original error is returned if the message can't be postponed.
+// Segmented messages are never postponed, as the outbox posts messages as is.
+func (p *messageProcessor) postpone(identifiers [][]byte, newMessage *types.NewMessage, err error) error {
+	if p.isSegmented(newMessage) {
+		return err
+	}
+	if postponeErr := p.transport.Postpone(identifiers, newMessage, err); postponeErr != nil {
+		if postponeErr != transport.ErrOutboxDisabled {
+			p.logger.Warn("failed to postpone message", zap.Error(postponeErr))
+		}
+		return err
+	}
+	p.logger.Info("message postponed", zap.Error(err))
+	return nil
 }
 
 func messageSpecToWhisper(spec *encryption.ProtocolMessageSpec) (*types.NewMessage, error) {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -236,3 +237,55 @@ func (s *MessageProcessorSuite) TestHandleDecodedMessagesDatasyncEncrypted() {
 	s.Require().True(proto.Equal(&s.testMessage.ChatMessage, &parsedMessage))
 	s.Require().Equal(protobuf.ApplicationMetadataMessage_CHAT_MESSAGE, decodedMessages[0].Type)
 }
+
+func (s *MessageProcessorSuite) TestHandleSegmentedMessages() {
+	authorKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	encodedPayload, err := proto.Marshal(&s.testMessage)
+	s.Require().NoError(err)
+
+	wrappedPayload, err := v1protocol.WrapMessageV1(encodedPayload, protobuf.ApplicationMetadataMessage_CHAT_MESSAGE, authorKey)
+	s.Require().NoError(err)
+
+	segments, err := segmentMessage(&types.NewMessage{Payload: wrappedPayload}, 30)
+	s.Require().NoError(err)
+	s.Require().True(len(segments) > 2)
+
+	// an expired segment of another message is removed when a new segment is received
+	expired := &protobuf.SegmentMessage{EntireMessageHash: crypto.Keccak256([]byte("expired")), SegmentsCount: 2, Payload: []byte{1}}
+	s.Require().NoError(s.processor.persistence.SaveMessageSegment(expired, &authorKey.PublicKey, time.Now().Add(-messageSegmentsExpiry-time.Minute).Unix()))
+
+	// segments are received in a reverse order
+	for i := len(segments) - 1; i > 0; i-- {
+		message := &types.Message{
+			Sig:     crypto.FromECDSAPub(&authorKey.PublicKey),
+			Payload: segments[i].Payload,
+		}
+		decodedMessages, err := s.processor.handleMessages(message, true)
+		s.Require().NoError(err)
+		s.Require().Empty(decodedMessages)
+	}
+
+	stored, err := s.processor.persistence.MessageSegments(crypto.Keccak256(wrappedPayload), uint32(len(segments)), &authorKey.PublicKey)
+	s.Require().NoError(err)
+	s.Require().Len(stored, len(segments)-1)
+	stored, err = s.processor.persistence.MessageSegments(expired.EntireMessageHash, expired.SegmentsCount, &authorKey.PublicKey)
+	s.Require().NoError(err)
+	s.Require().Empty(stored)
+
+	message := &types.Message{
+		Sig:     crypto.FromECDSAPub(&authorKey.PublicKey),
+		Payload: segments[0].Payload,
+	}
+	decodedMessages, err := s.processor.handleMessages(message, true)
+	s.Require().NoError(err)
+	s.Require().Len(decodedMessages, 1)
+	s.Require().Equal(v1protocol.MessageID(&authorKey.PublicKey, wrappedPayload), decodedMessages[0].ID)
+	parsedMessage := decodedMessages[0].ParsedMessage.(protobuf.ChatMessage)
+	s.Require().True(proto.Equal(&s.testMessage.ChatMessage, &parsedMessage))
+
+	stored, err = s.processor.persistence.MessageSegments(crypto.Keccak256(wrappedPayload), uint32(len(segments)), &authorKey.PublicKey)
+	s.Require().NoError(err)
+	s.Require().Empty(stored)
+}
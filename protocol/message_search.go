@@ -0,0 +1,86 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxSearchResults is a maximum number of messages returned by a single search.
+	maxSearchResults = 100
+	// searchSnippetTokens is a number of tokens in a snippet of a matched message.
+	searchSnippetTokens = 15
+)
+
+// ErrEmptySearchQuery is returned when a search query is empty.
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+// MessageSearchResult is a message matching a full-text search query.
+type MessageSearchResult struct {
+	Message *Message `json:"message"`
+	// Snippet is a fragment of the message text with matched terms wrapped in <b></b>
+	Snippet string `json:"snippet"`
+	// Rank is a relevance of the message, higher is better
+	Rank float64 `json:"rank"`
+}
+
+// SearchMessages returns messages matching the query ordered by relevance and then
+// by clock, newest first. The query uses SQLite full-text query syntax, e.g. "status OR keycard".
+// If chatIDs are given, only messages of these chats are searched.
+func (m *Messenger) SearchMessages(query string, chatIDs []string, limit int) ([]*MessageSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if limit <= 0 || limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+	return m.persistence.SearchMessages(query, chatIDs, limit)
+}
+
+// searchMatch is a message matching a search query before it is loaded.
+type searchMatch struct {
+	rowID int64
+	id    string
+	clock uint64
+	rank  float64
+}
+
+// sortSearchMatches sorts matches by rank and then by clock in a descending order.
+func sortSearchMatches(matches []searchMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank == matches[j].rank {
+			return matches[i].clock > matches[j].clock
+		}
+		return matches[i].rank > matches[j].rank
+	})
+}
+
+// rankMatchInfo computes a relevance of a row from the result of matchinfo(table, 'pcx').
+// Every phrase contributes the number of its hits in the row divided by the number
+// of its hits in all rows, so rare terms weigh more than common ones.
+func rankMatchInfo(info []byte) float64 {
+	if len(info) < 8 {
+		return 0
+	}
+	value := func(i int) uint32 {
+		return binary.LittleEndian.Uint32(info[i*4:])
+	}
+	phrases, columns := int(value(0)), int(value(1))
+	if len(info) < (2+3*phrases*columns)*4 {
+		return 0
+	}
+	var rank float64
+	for p := 0; p < phrases; p++ {
+		for c := 0; c < columns; c++ {
+			offset := 2 + 3*(p*columns+c)
+			hitsInRow, hitsInAllRows := value(offset), value(offset+1)
+			if hitsInAllRows > 0 {
+				rank += float64(hitsInRow) / float64(hitsInAllRows)
+			}
+		}
+	}
+	return rank
+}
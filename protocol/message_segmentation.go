@@ -0,0 +1,149 @@
+package protocol
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+const (
+	// maxMessageSegments is the maximum number of segments of a single payload.
+	maxMessageSegments = 64
+	// messageSegmentsExpiry is how long segments of an incomplete payload are kept.
+	messageSegmentsExpiry = 24 * time.Hour
+)
+
+var (
+	// ErrMessageSegmentsIncomplete is returned when a segment was stored, but the payload
+	// can't be reassembled until the remaining segments are received.
+	ErrMessageSegmentsIncomplete = errors.New("message segments incomplete")
+	// ErrMessageSegmentsHashMismatch is returned when reassembled segments don't match
+	// the hash of the entire payload.
+	ErrMessageSegmentsHashMismatch = errors.New("reassembled message doesn't match the hash")
+	// ErrMessageTooLarge is returned when a payload can't fit into maxMessageSegments.
+	ErrMessageTooLarge = errors.New("message is too large")
+)
+
+// segmentMessage splits the payload of a message into segments of at most segmentSize bytes.
+// The message is returned as is if the payload fits into a single segment.
+func segmentMessage(newMessage *types.NewMessage, segmentSize int) ([]*types.NewMessage, error) {
+	if segmentSize <= 0 || len(newMessage.Payload) <= segmentSize {
+		return []*types.NewMessage{newMessage}, nil
+	}
+
+	count := (len(newMessage.Payload) + segmentSize - 1) / segmentSize
+	if count > maxMessageSegments {
+		return nil, ErrMessageTooLarge
+	}
+
+	hash := crypto.Keccak256(newMessage.Payload)
+	segments := make([]*types.NewMessage, 0, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * segmentSize
+		if end > len(newMessage.Payload) {
+			end = len(newMessage.Payload)
+		}
+		payload, err := proto.Marshal(&protobuf.SegmentMessage{
+			EntireMessageHash: hash,
+			Index:             uint32(i),
+			SegmentsCount:     uint32(count),
+			Payload:           newMessage.Payload[i*segmentSize : end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		segment := *newMessage
+		segment.Payload = payload
+		segments = append(segments, &segment)
+	}
+	return segments, nil
+}
+
+// isMessageSegment returns true if the segment was decoded from a valid SegmentMessage.
+// Other messages can be decoded as a SegmentMessage without an error, so all fields are checked.
+func isMessageSegment(segment *protobuf.SegmentMessage) bool {
+	return len(segment.XXX_unrecognized) == 0 &&
+		len(segment.EntireMessageHash) == types.HashLength &&
+		segment.SegmentsCount > 1 &&
+		segment.SegmentsCount <= maxMessageSegments &&
+		segment.Index < segment.SegmentsCount &&
+		len(segment.Payload) > 0
+}
+
+// sendSegmented posts a message with send. If its payload is larger than the segment size,
+// segments are posted instead. The hash and the message of the last posted envelope
+// are returned so that they can be tracked.
+func (p *messageProcessor) sendSegmented(newMessage *types.NewMessage, send func(*types.NewMessage) ([]byte, error)) ([]byte, *types.NewMessage, error) {
+	segments, err := segmentMessage(newMessage, p.featureFlags.segmentSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(segments) > 1 {
+		p.logger.Debug("sending message in segments", zap.Int("segments", len(segments)))
+	}
+
+	var hash []byte
+	for _, segment := range segments {
+		hash, err = send(segment)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return hash, segments[len(segments)-1], nil
+}
+
+// isSegmented returns true if the message is split into segments when sent.
+func (p *messageProcessor) isSegmented(newMessage *types.NewMessage) bool {
+	return p.featureFlags.segmentSize > 0 && len(newMessage.Payload) > p.featureFlags.segmentSize
+}
+
+// handleSegmentationLayer stores a received segment and replaces the transport payload
+// with the entire payload once all segments are received. Other messages are not changed.
+// ErrMessageSegmentsIncomplete is returned if some segments are still missing.
+func (p *messageProcessor) handleSegmentationLayer(message *v1protocol.StatusMessage) error {
+	var segment protobuf.SegmentMessage
+	if err := proto.Unmarshal(message.TransportPayload, &segment); err != nil || !isMessageSegment(&segment) {
+		return nil
+	}
+
+	logger := p.logger.With(zap.String("site", "handleSegmentationLayer"), zap.Binary("hash", segment.EntireMessageHash))
+	publicKey := message.TransportLayerSigPubKey
+	now := time.Now()
+	if err := p.persistence.DeleteMessageSegmentsOlderThan(now.Add(-messageSegmentsExpiry).Unix()); err != nil {
+		logger.Warn("failed to remove expired message segments", zap.Error(err))
+	}
+	if err := p.persistence.SaveMessageSegment(&segment, publicKey, now.Unix()); err != nil {
+		return errors.Wrap(err, "failed to save message segment")
+	}
+
+	segments, err := p.persistence.MessageSegments(segment.EntireMessageHash, segment.SegmentsCount, publicKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to get message segments")
+	}
+	if len(segments) < int(segment.SegmentsCount) {
+		logger.Debug("waiting for message segments", zap.Int("received", len(segments)), zap.Uint32("count", segment.SegmentsCount))
+		return ErrMessageSegmentsIncomplete
+	}
+
+	var entirePayload bytes.Buffer
+	for _, s := range segments {
+		entirePayload.Write(s.Payload)
+	}
+	if err := p.persistence.DeleteMessageSegments(segment.EntireMessageHash, publicKey); err != nil {
+		logger.Warn("failed to remove message segments", zap.Error(err))
+	}
+	if !bytes.Equal(crypto.Keccak256(entirePayload.Bytes()), segment.EntireMessageHash) {
+		return ErrMessageSegmentsHashMismatch
+	}
+
+	message.TransportPayload = entirePayload.Bytes()
+	return nil
+}
@@ -0,0 +1,60 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/encryption"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func TestSegmentMessage(t *testing.T) {
+	newMessage := &types.NewMessage{TTL: 10, Payload: bytes.Repeat([]byte{1, 2, 3}, 10)}
+
+	segments, err := segmentMessage(newMessage, 0)
+	require.NoError(t, err)
+	require.Equal(t, []*types.NewMessage{newMessage}, segments)
+	segments, err = segmentMessage(newMessage, 30)
+	require.NoError(t, err)
+	require.Equal(t, []*types.NewMessage{newMessage}, segments)
+
+	segments, err = segmentMessage(newMessage, 7)
+	require.NoError(t, err)
+	require.Len(t, segments, 5)
+	var payload []byte
+	for i, segment := range segments {
+		require.Equal(t, newMessage.TTL, segment.TTL)
+		var segmentMessage protobuf.SegmentMessage
+		require.NoError(t, proto.Unmarshal(segment.Payload, &segmentMessage))
+		require.True(t, isMessageSegment(&segmentMessage))
+		require.Equal(t, uint32(i), segmentMessage.Index)
+		require.Equal(t, uint32(5), segmentMessage.SegmentsCount)
+		payload = append(payload, segmentMessage.Payload...)
+	}
+	require.Equal(t, newMessage.Payload, payload)
+
+	_, err = segmentMessage(&types.NewMessage{Payload: make([]byte, maxMessageSegments+1)}, 1)
+	require.Equal(t, ErrMessageTooLarge, err)
+}
+
+func TestIsMessageSegment(t *testing.T) {
+	protocolMessage, err := proto.Marshal(&encryption.ProtocolMessage{
+		InstallationId: "installation-1",
+		PublicMessage:  []byte("hello"),
+	})
+	require.NoError(t, err)
+	var segment protobuf.SegmentMessage
+	require.NoError(t, proto.Unmarshal(protocolMessage, &segment))
+	require.False(t, isMessageSegment(&segment))
+
+	require.False(t, isMessageSegment(&protobuf.SegmentMessage{
+		EntireMessageHash: make([]byte, 32),
+		Index:             2,
+		SegmentsCount:     2,
+		Payload:           []byte{1},
+	}))
+}
@@ -2,9 +2,11 @@ package protocol
 
 import (
 	"errors"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/protocol/protobuf"
 	"github.com/status-im/status-go/protocol/v1"
 )
@@ -13,6 +15,17 @@ import (
 // from whisperTimestamp
 const maxWhisperFutureDriftMs uint64 = 120000
 
+// maxAnnouncedPublicChats is the maximum number of chats in a single PublicChatsAnnouncement
+const maxAnnouncedPublicChats = 100
+
+// maxReactionLength is the maximum length of a reaction in bytes
+const maxReactionLength = 32
+
+// maxChatIdentityDisplayNameLength is the maximum length of a display name in bytes
+const maxChatIdentityDisplayNameLength = 64
+
+var publicChatNameRegexp = regexp.MustCompile("^[a-z0-9-]+$")
+
 func validateClockValue(clock uint64, whisperTimestamp uint64) error {
 	if clock == 0 {
 		return errors.New("clock can't be 0")
@@ -193,3 +206,119 @@ func ValidateReceivedChatMessage(message *protobuf.ChatMessage, whisperTimestamp
 	}
 	return nil
 }
+
+func ValidateReceivedPublicChatsAnnouncement(message *protobuf.PublicChatsAnnouncement, whisperTimestamp uint64) error {
+	if err := validateClockValue(message.Clock, whisperTimestamp); err != nil {
+		return err
+	}
+
+	if len(message.Chats) > maxAnnouncedPublicChats {
+		return errors.New("too many chats announced")
+	}
+
+	for _, chat := range message.Chats {
+		if !publicChatNameRegexp.MatchString(chat) {
+			return errors.New("invalid public chat name")
+		}
+	}
+
+	return nil
+}
+
+func ValidateReceivedReaction(message *protobuf.Reaction, whisperTimestamp uint64) error {
+	if err := validateClockValue(message.Clock, whisperTimestamp); err != nil {
+		return err
+	}
+
+	if len(strings.TrimSpace(message.ChatId)) == 0 {
+		return errors.New("chatId can't be empty")
+	}
+
+	if len(message.MessageId) == 0 {
+		return errors.New("messageId can't be empty")
+	}
+
+	if len(strings.TrimSpace(message.Reaction)) == 0 {
+		return errors.New("reaction can't be empty")
+	}
+
+	if len(message.Reaction) > maxReactionLength {
+		return errors.New("reaction too long")
+	}
+
+	if message.MessageType != protobuf.ChatMessage_ONE_TO_ONE && message.MessageType != protobuf.ChatMessage_PUBLIC_GROUP {
+		return errors.New("unsupported message type")
+	}
+
+	return nil
+}
+
+func ValidateReceivedPinMessage(message *protobuf.PinMessage, whisperTimestamp uint64) error {
+	if err := validateClockValue(message.Clock, whisperTimestamp); err != nil {
+		return err
+	}
+
+	if len(strings.TrimSpace(message.ChatId)) == 0 {
+		return errors.New("chatId can't be empty")
+	}
+
+	if len(message.MessageId) == 0 {
+		return errors.New("messageId can't be empty")
+	}
+
+	switch message.MessageType {
+	case protobuf.ChatMessage_ONE_TO_ONE, protobuf.ChatMessage_PUBLIC_GROUP, protobuf.ChatMessage_PRIVATE_GROUP:
+	default:
+		return errors.New("unsupported message type")
+	}
+
+	return nil
+}
+
+func ValidateReceivedChatIndicator(message *protobuf.ChatIndicator, whisperTimestamp uint64) error {
+	if err := validateClockValue(message.Clock, whisperTimestamp); err != nil {
+		return err
+	}
+
+	if len(strings.TrimSpace(message.ChatId)) == 0 {
+		return errors.New("chatId can't be empty")
+	}
+
+	if _, ok := protobuf.ChatIndicator_Type_name[int32(message.Type)]; !ok || message.Type == protobuf.ChatIndicator_UNKNOWN_INDICATOR_TYPE {
+		return errors.New("unknown indicator type")
+	}
+
+	return nil
+}
+
+func ValidateReceivedPushNotificationInfo(message *protobuf.PushNotificationInfo) error {
+	if len(strings.TrimSpace(message.InstallationId)) == 0 {
+		return errors.New("installationId can't be empty")
+	}
+
+	if len(message.ServerPublicKey) == 0 {
+		return errors.New("serverPublicKey can't be empty")
+	}
+
+	if message.Version == 0 {
+		return errors.New("version can't be 0")
+	}
+
+	return nil
+}
+
+func ValidateReceivedChatIdentity(message *protobuf.ChatIdentity) error {
+	if message.Clock == 0 {
+		return errors.New("clock can't be 0")
+	}
+
+	if len(message.DisplayName) > maxChatIdentityDisplayNameLength {
+		return errors.New("displayName is too long")
+	}
+
+	if len(message.ImageHash) != 0 && len(message.ImageHash) != types.HashLength {
+		return errors.New("imageHash must be 32 bytes long")
+	}
+
+	return nil
+}
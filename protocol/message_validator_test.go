@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -85,6 +86,186 @@ func (s *MessageValidatorSuite) TestValidateRequestAddressForTransaction() {
 
 }
 
+func (s *MessageValidatorSuite) TestValidatePublicChatsAnnouncement() {
+	tooManyChats := make([]string, maxAnnouncedPublicChats+1)
+	for i := range tooManyChats {
+		tooManyChats[i] = "status"
+	}
+
+	testCases := []struct {
+		Name             string
+		WhisperTimestamp uint64
+		Valid            bool
+		Message          protobuf.PublicChatsAnnouncement
+	}{
+		{
+			Name:             "valid message",
+			WhisperTimestamp: 30,
+			Valid:            true,
+			Message: protobuf.PublicChatsAnnouncement{
+				Clock: 30,
+				Chats: []string{"status", "status-go-1"},
+			},
+		},
+		{
+			Name:             "missing clock value",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.PublicChatsAnnouncement{
+				Chats: []string{"status"},
+			},
+		},
+		{
+			Name:             "invalid chat name",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.PublicChatsAnnouncement{
+				Clock: 30,
+				Chats: []string{"Not A Chat"},
+			},
+		},
+		{
+			Name:             "too many chats",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.PublicChatsAnnouncement{
+				Clock: 30,
+				Chats: tooManyChats,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.Name, func() {
+			err := ValidateReceivedPublicChatsAnnouncement(&tc.Message, tc.WhisperTimestamp)
+			if tc.Valid {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
+func (s *MessageValidatorSuite) TestValidateReaction() {
+	testCases := []struct {
+		Name             string
+		WhisperTimestamp uint64
+		Valid            bool
+		Message          protobuf.Reaction
+	}{
+		{
+			Name:             "valid message",
+			WhisperTimestamp: 30,
+			Valid:            true,
+			Message: protobuf.Reaction{
+				Clock:       30,
+				ChatId:      "status",
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_PUBLIC_GROUP,
+				Reaction:    "👍",
+			},
+		},
+		{
+			Name:             "missing message id",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.Reaction{
+				Clock:       30,
+				ChatId:      "status",
+				MessageType: protobuf.ChatMessage_PUBLIC_GROUP,
+				Reaction:    "👍",
+			},
+		},
+		{
+			Name:             "reaction too long",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.Reaction{
+				Clock:       30,
+				ChatId:      "status",
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_PUBLIC_GROUP,
+				Reaction:    strings.Repeat("a", maxReactionLength+1),
+			},
+		},
+		{
+			Name:             "group chat",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.Reaction{
+				Clock:       30,
+				ChatId:      "status",
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_PRIVATE_GROUP,
+				Reaction:    "👍",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.Name, func() {
+			err := ValidateReceivedReaction(&tc.Message, tc.WhisperTimestamp)
+			if tc.Valid {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
+func (s *MessageValidatorSuite) TestValidatePinMessage() {
+	testCases := []struct {
+		Name             string
+		WhisperTimestamp uint64
+		Valid            bool
+		Message          protobuf.PinMessage
+	}{
+		{
+			Name:             "valid message",
+			WhisperTimestamp: 30,
+			Valid:            true,
+			Message: protobuf.PinMessage{
+				Clock:       30,
+				ChatId:      "group",
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_PRIVATE_GROUP,
+				Pinned:      true,
+			},
+		},
+		{
+			Name:             "missing chat id",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.PinMessage{
+				Clock:       30,
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_ONE_TO_ONE,
+			},
+		},
+		{
+			Name:             "system message",
+			WhisperTimestamp: 30,
+			Valid:            false,
+			Message: protobuf.PinMessage{
+				Clock:       30,
+				ChatId:      "group",
+				MessageId:   "0x01",
+				MessageType: protobuf.ChatMessage_SYSTEM_MESSAGE_PRIVATE_GROUP,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.Name, func() {
+			err := ValidateReceivedPinMessage(&tc.Message, tc.WhisperTimestamp)
+			if tc.Valid {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
 func (s *MessageValidatorSuite) TestValidatePlainTextMessage() {
 	testCases := []struct {
 		Name             string
@@ -34,8 +34,17 @@ const PubKeyStringLength = 132
 const transactionSentTxt = "Transaction sent"
 
 var (
-	ErrChatIDEmpty    = errors.New("chat ID is empty")
-	ErrNotImplemented = errors.New("not implemented")
+	ErrChatIDEmpty             = errors.New("chat ID is empty")
+	ErrNotImplemented          = errors.New("not implemented")
+	ErrNoPendingContactRequest = errors.New("no pending contact request")
+	// ErrPublicChatsDirectoryDisabled is returned when the public chats directory is not enabled.
+	ErrPublicChatsDirectoryDisabled = errors.New("public chats directory is disabled")
+	// ErrReactionsNotSupported is returned when reacting to a message in a group chat.
+	ErrReactionsNotSupported = errors.New("reactions are supported only in public and one-to-one chats")
+	// ErrPinningNotSupported is returned when pinning a message in a public chat that is not a community channel.
+	ErrPinningNotSupported = errors.New("pinning is supported only in one-to-one, group chats and community channels")
+	// ErrPinNotAuthorized is returned when the author is not allowed to pin messages in the chat.
+	ErrPinNotAuthorized = errors.New("not authorized to pin messages in the chat")
 )
 
 // Messenger is a entity managing chats and messages.
@@ -61,9 +70,32 @@ type Messenger struct {
 	systemMessagesTranslations map[protobuf.MembershipUpdateEvent_EventType]string
 	allChats                   map[string]*Chat
 	allContacts                map[string]*Contact
+	allContactRequests         map[string]*ContactRequest
 	allInstallations           map[string]*multidevice.Installation
 	modifiedInstallations      map[string]bool
 	installationID             string
+	// publicChatsDirectoryEnabled indicates whether public chats are announced
+	// to and collected from the public chats directory
+	publicChatsDirectoryEnabled bool
+	publicChatsDirectoryClock   uint64
+	// outbox retries messages that were not delivered, nil if disabled
+	outbox *outbox
+	// chatIndicators rate limits typing and presence indicators, nil if disabled
+	chatIndicators *chatIndicators
+	// pushNotifications tracks offline contacts to notify, nil if disabled
+	pushNotifications *pushNotifications
+	// pushNotificationServer accepts registrations and forwards notifications, nil if disabled
+	pushNotificationServer *pushNotificationServer
+	// chatIdentityPublisher republishes our chat identity on the contact code topic
+	chatIdentityPublisher *chatIdentityPublisher
+	// filterStats counts envelopes received by filters
+	filterStats *filterStats
+	// payloadFilters drop received messages of types skipped by the client
+	payloadFilters *payloadFilters
+	// blockList drops envelopes of blocked public keys and flags muted chats
+	blockList *blockList
+	// mentions finds received messages of public chats mentioning the user
+	mentions *mentionMatcher
 
 	mutex sync.Mutex
 }
@@ -74,16 +106,19 @@ type RawResponse struct {
 }
 
 type MessengerResponse struct {
-	Chats         []*Chat                     `json:"chats,omitempty"`
-	Messages      []*Message                  `json:"messages,omitempty"`
-	Contacts      []*Contact                  `json:"contacts,omitempty"`
-	Installations []*multidevice.Installation `json:"installations,omitempty"`
+	Chats           []*Chat                     `json:"chats,omitempty"`
+	Messages        []*Message                  `json:"messages,omitempty"`
+	Contacts        []*Contact                  `json:"contacts,omitempty"`
+	ContactRequests []*ContactRequest           `json:"contactRequests,omitempty"`
+	Installations   []*multidevice.Installation `json:"installations,omitempty"`
 	// Raw unprocessed messages
 	RawMessages []*RawResponse `json:"rawMessages,omitempty"`
+	// Mentions of the user in received messages, sent to the client with a separate signal
+	Mentions []*Mention `json:"-"`
 }
 
 func (m *MessengerResponse) IsEmpty() bool {
-	return len(m.Chats) == 0 && len(m.Messages) == 0 && len(m.Contacts) == 0 && len(m.RawMessages) == 0 && len(m.Installations) == 0
+	return len(m.Chats) == 0 && len(m.Messages) == 0 && len(m.Contacts) == 0 && len(m.ContactRequests) == 0 && len(m.RawMessages) == 0 && len(m.Installations) == 0
 }
 
 type featureFlags struct {
@@ -91,6 +126,9 @@ type featureFlags struct {
 	// using datasync, breaking change for non-v1 clients. Public messages
 	// are not impacted
 	datasync bool
+	// segmentSize is the maximum size of a payload sent in a single envelope.
+	// Larger payloads are split into segments if it is greater than zero.
+	segmentSize int
 }
 
 type dbConfig struct {
@@ -110,10 +148,24 @@ type config struct {
 	systemMessagesTranslations map[protobuf.MembershipUpdateEvent_EventType]string
 	// Config for the envelopes monitor
 	envelopesMonitorConfig *transport.EnvelopesMonitorConfig
+	// partitionsConfig sets the number of partitioned topics
+	partitionsConfig transport.PartitionsConfig
+	// outboxConfig enables the persistent outbox if set
+	outboxConfig *OutboxConfig
+	// chatIndicatorsConfig enables typing and presence indicators if set
+	chatIndicatorsConfig *ChatIndicatorsConfig
+	// pushNotificationsConfig enables registering with a push notification server if set
+	pushNotificationsConfig *PushNotificationsConfig
+	// pushNotificationServerConfig runs the push notification server if set
+	pushNotificationServerConfig *PushNotificationServerConfig
+	// filterStatsConfig enables warnings about silent filters if set
+	filterStatsConfig *FilterStatsConfig
 
 	messagesPersistenceEnabled bool
 	featureFlags               featureFlags
 
+	publicChatsDirectoryEnabled bool
+
 	// A path to a database or a database instance is required.
 	// The database instance has a higher priority.
 	dbConfig dbConfig
@@ -191,6 +243,81 @@ func WithEnvelopesMonitorConfig(emc *transport.EnvelopesMonitorConfig) Option {
 	}
 }
 
+// WithPartitionsConfig sets the number of partitioned topics.
+func WithPartitionsConfig(pc transport.PartitionsConfig) Option {
+	return func(c *config) error {
+		c.partitionsConfig = pc
+		return nil
+	}
+}
+
+// WithMessageSegmentation enables splitting of payloads larger than segmentSize
+// into segments. Segments are reassembled by receivers regardless of this option.
+func WithMessageSegmentation(segmentSize uint32) Option {
+	return func(c *config) error {
+		c.featureFlags.segmentSize = int(segmentSize)
+		return nil
+	}
+}
+
+// WithOutbox enables the persistent outbox. It requires the envelopes monitor
+// to be configured with WithEnvelopesMonitorConfig.
+func WithOutbox(oc OutboxConfig) Option {
+	return func(c *config) error {
+		c.outboxConfig = &oc
+		return nil
+	}
+}
+
+// WithChatIndicators enables sending and receiving typing and presence indicators.
+func WithChatIndicators(cc ChatIndicatorsConfig) Option {
+	return func(c *config) error {
+		c.chatIndicatorsConfig = &cc
+		return nil
+	}
+}
+
+// WithFilterStats enables warnings about filters that receive nothing for a period.
+func WithFilterStats(fc FilterStatsConfig) Option {
+	return func(c *config) error {
+		c.filterStatsConfig = &fc
+		return nil
+	}
+}
+
+// WithPushNotifications enables registering device tokens with a push notification
+// server and requesting notifications for offline contacts.
+func WithPushNotifications(pc PushNotificationsConfig) Option {
+	return func(c *config) error {
+		if pc.Server == nil {
+			return errors.New("push notification server is required")
+		}
+		c.pushNotificationsConfig = &pc
+		return nil
+	}
+}
+
+// WithPushNotificationServer enables accepting registrations of device tokens and
+// forwarding notifications requested by contacts to the gateway.
+func WithPushNotificationServer(sc PushNotificationServerConfig) Option {
+	return func(c *config) error {
+		if sc.Gateway == nil {
+			return errors.New("push notification gateway is required")
+		}
+		c.pushNotificationServerConfig = &sc
+		return nil
+	}
+}
+
+// WithPublicChatsDirectory enables announcing public chats to and collecting
+// announcements from the public chats directory.
+func WithPublicChatsDirectory() Option {
+	return func(c *config) error {
+		c.publicChatsDirectoryEnabled = true
+		return nil
+	}
+}
+
 func NewMessenger(
 	identity *ecdsa.PrivateKey,
 	node types.Node,
@@ -283,6 +410,21 @@ func NewMessenger(
 		return nil, errors.Wrap(err, "failed to apply migrations")
 	}
 
+	// Initialize the outbox. It receives messages that were not delivered
+	// from the envelopes monitor and observes envelope events.
+	var ob *outbox
+	envelopesMonitorConfig := c.envelopesMonitorConfig
+	if c.outboxConfig != nil {
+		if envelopesMonitorConfig == nil {
+			return nil, errors.New("outbox requires the envelopes monitor config")
+		}
+		ob = newOutbox(&sqlitePersistence{db: database}, *c.outboxConfig, envelopesMonitorConfig.EnvelopeEventsHandler, logger)
+		monitorConfig := *envelopesMonitorConfig
+		monitorConfig.Outbox = ob
+		monitorConfig.EnvelopeEventsHandler = ob
+		envelopesMonitorConfig = &monitorConfig
+	}
+
 	// Initialize transport layer.
 	var transp transport.Transport
 	if shh, err := node.GetWhisper(nil); err == nil && shh != nil {
@@ -291,8 +433,9 @@ func NewMessenger(
 			identity,
 			database,
 			nil,
-			c.envelopesMonitorConfig,
+			envelopesMonitorConfig,
 			logger,
+			shhtransp.WithPartitionsConfig(c.partitionsConfig),
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create Transport")
@@ -308,8 +451,9 @@ func NewMessenger(
 			identity,
 			database,
 			nil,
-			c.envelopesMonitorConfig,
+			envelopesMonitorConfig,
 			logger,
+			wakutransp.WithPartitionsConfig(c.partitionsConfig),
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create  Transport")
@@ -340,33 +484,72 @@ func NewMessenger(
 
 	handler := newMessageHandler(identity, logger, &sqlitePersistence{db: database})
 
+	shutdownTasks := []func() error{
+		database.Close,
+		transp.ResetFilters,
+		transp.Stop,
+		func() error { processor.Stop(); return nil },
+		// Currently this often fails, seems like it's safe to ignore them
+		// https://github.com/uber-go/zap/issues/328
+		func() error { _ = logger.Sync; return nil },
+	}
+	if ob != nil {
+		ob.transport = transp
+		// the outbox must be stopped before the database is closed
+		shutdownTasks = append([]func() error{func() error { ob.Stop(); return nil }}, shutdownTasks...)
+	}
+	identityPublisher := newChatIdentityPublisher()
+	shutdownTasks = append([]func() error{func() error { identityPublisher.Stop(); return nil }}, shutdownTasks...)
+	stats := newFilterStats(c.filterStatsConfig)
+	shutdownTasks = append([]func() error{func() error { stats.Stop(); return nil }}, shutdownTasks...)
+	// the bundle is refreshed using the database
+	shutdownTasks = append([]func() error{func() error { encryptionProtocol.Stop(); return nil }}, shutdownTasks...)
+
+	var indicators *chatIndicators
+	if c.chatIndicatorsConfig != nil {
+		indicators = newChatIndicators(*c.chatIndicatorsConfig)
+	}
+
+	var notifications *pushNotifications
+	if c.pushNotificationsConfig != nil {
+		notifications = newPushNotifications(*c.pushNotificationsConfig)
+	}
+
+	var notificationServer *pushNotificationServer
+	if c.pushNotificationServerConfig != nil {
+		notificationServer = newPushNotificationServer(*c.pushNotificationServerConfig, identity)
+	}
+
 	messenger = &Messenger{
-		node:                       node,
-		identity:                   identity,
-		persistence:                &sqlitePersistence{db: database},
-		transport:                  transp,
-		encryptor:                  encryptionProtocol,
-		processor:                  processor,
-		handler:                    handler,
-		featureFlags:               c.featureFlags,
-		systemMessagesTranslations: c.systemMessagesTranslations,
-		allChats:                   make(map[string]*Chat),
-		allContacts:                make(map[string]*Contact),
-		allInstallations:           make(map[string]*multidevice.Installation),
-		installationID:             installationID,
-		modifiedInstallations:      make(map[string]bool),
-		messagesPersistenceEnabled: c.messagesPersistenceEnabled,
-		verifyTransactionClient:    c.verifyTransactionClient,
-		shutdownTasks: []func() error{
-			database.Close,
-			transp.ResetFilters,
-			transp.Stop,
-			func() error { processor.Stop(); return nil },
-			// Currently this often fails, seems like it's safe to ignore them
-			// https://github.com/uber-go/zap/issues/328
-			func() error { _ = logger.Sync; return nil },
-		},
-		logger: logger,
+		node:                        node,
+		identity:                    identity,
+		persistence:                 &sqlitePersistence{db: database},
+		transport:                   transp,
+		encryptor:                   encryptionProtocol,
+		processor:                   processor,
+		handler:                     handler,
+		featureFlags:                c.featureFlags,
+		systemMessagesTranslations:  c.systemMessagesTranslations,
+		allChats:                    make(map[string]*Chat),
+		allContacts:                 make(map[string]*Contact),
+		allContactRequests:          make(map[string]*ContactRequest),
+		allInstallations:            make(map[string]*multidevice.Installation),
+		installationID:              installationID,
+		modifiedInstallations:       make(map[string]bool),
+		messagesPersistenceEnabled:  c.messagesPersistenceEnabled,
+		verifyTransactionClient:     c.verifyTransactionClient,
+		publicChatsDirectoryEnabled: c.publicChatsDirectoryEnabled,
+		outbox:                      ob,
+		chatIndicators:              indicators,
+		pushNotifications:           notifications,
+		pushNotificationServer:      notificationServer,
+		chatIdentityPublisher:       identityPublisher,
+		filterStats:                 stats,
+		payloadFilters:              newPayloadFilters(),
+		blockList:                   newBlockList(),
+		mentions:                    newMentionMatcher(contactIDFromPublicKey(&identity.PublicKey)),
+		shutdownTasks:               shutdownTasks,
+		logger:                      logger,
 	}
 
 	logger.Debug("messages persistence", zap.Bool("enabled", c.messagesPersistenceEnabled))
@@ -375,6 +558,11 @@ func NewMessenger(
 }
 
 func (m *Messenger) Start() error {
+	if m.outbox != nil {
+		m.outbox.Start()
+	}
+	m.chatIdentityPublisher.Start(m.republishChatIdentity)
+	m.filterStats.Start(m.transport.Filters)
 	return m.encryptor.Start(m.identity)
 }
 
@@ -390,10 +578,34 @@ func (m *Messenger) Init() error {
 	logger := m.logger.With(zap.String("site", "Init"))
 
 	var (
-		publicChatIDs []string
-		publicKeys    []*ecdsa.PublicKey
+		publicChatIDs     []string
+		publicKeys        []*ecdsa.PublicKey
+		communityChannels []*transport.ChannelDescriptor
 	)
 
+	blocked, err := m.persistence.BlockedPublicKeys()
+	if err != nil {
+		return err
+	}
+	muted, err := m.persistence.MutedChats()
+	if err != nil {
+		return err
+	}
+	m.blockList.load(blocked, muted)
+
+	mentionNames, err := m.persistence.MentionNames()
+	if err != nil {
+		return err
+	}
+	m.mentions.setNames(mentionNames)
+	chatIdentity, err := m.persistence.ChatIdentity(contactIDFromPublicKey(&m.identity.PublicKey))
+	if err != nil {
+		return err
+	}
+	if chatIdentity != nil {
+		m.mentions.setDisplayName(chatIdentity.DisplayName)
+	}
+
 	// Get chat IDs and public keys from the existing chats.
 	// TODO: Get only active chats by the query.
 	chats, err := m.persistence.Chats()
@@ -406,12 +618,17 @@ func (m *Messenger) Init() error {
 			continue
 		}
 
+		chat.Muted = m.blockList.isMuted(chat.ID)
 		m.allChats[chat.ID] = chat
 		if !chat.Active {
 			continue
 		}
 		switch chat.ChatType {
 		case ChatTypePublic:
+			if chat.CommunityChannel != nil {
+				communityChannels = append(communityChannels, chat.CommunityChannel)
+				continue
+			}
 			publicChatIDs = append(publicChatIDs, chat.ID)
 		case ChatTypeOneToOne:
 			pk, err := chat.PublicKey()
@@ -451,6 +668,14 @@ func (m *Messenger) Init() error {
 		publicKeys = append(publicKeys, publicKey)
 	}
 
+	contactRequests, err := m.persistence.ContactRequests()
+	if err != nil {
+		return err
+	}
+	for _, request := range contactRequests {
+		m.allContactRequests[request.ID] = request
+	}
+
 	installations, err := m.encryptor.GetOurInstallations(&m.identity.PublicKey)
 	if err != nil {
 		return err
@@ -460,8 +685,21 @@ func (m *Messenger) Init() error {
 		m.allInstallations[installation.ID] = installation
 	}
 
-	_, err = m.transport.InitFilters(publicChatIDs, publicKeys)
-	return err
+	if m.publicChatsDirectoryEnabled {
+		publicChatIDs = append(publicChatIDs, PublicChatsDirectoryID)
+	}
+
+	_, err = m.transport.InitFilters(publicChatIDs, m.blockList.withoutBlocked(publicKeys))
+	if err != nil {
+		return err
+	}
+
+	for _, descriptor := range communityChannels {
+		if err := m.transport.JoinCommunityChannel(descriptor); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Shutdown takes care of ensuring a clean shutdown of Messenger
@@ -486,6 +724,9 @@ func (m *Messenger) handleSharedSecrets(secrets []*sharedsecret.Secret) ([]*tran
 	var result []*transport.Filter
 	for _, secret := range secrets {
 		logger.Debug("received shared secret", zap.Binary("identity", crypto.FromECDSAPub(secret.Identity)))
+		if m.blockList.isBlocked(contactIDFromPublicKey(secret.Identity)) {
+			continue
+		}
 		fSecret := types.NegotiatedSecret{
 			PublicKey: secret.Identity,
 			Key:       secret.Key,
@@ -499,6 +740,9 @@ func (m *Messenger) handleSharedSecrets(secrets []*sharedsecret.Secret) ([]*tran
 	return result, nil
 }
 
+// EnableInstallation marks an installation as trusted. When a new installation
+// is enabled, public chats and contacts are replicated to it. A failed replication
+// is logged and doesn't fail enabling the installation.
 func (m *Messenger) EnableInstallation(id string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -512,8 +756,22 @@ func (m *Messenger) EnableInstallation(id string) error {
 	if err != nil {
 		return err
 	}
+	wasEnabled := installation.Enabled
 	installation.Enabled = true
 	m.allInstallations[id] = installation
+
+	if wasEnabled {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.syncChatsAndContacts(ctx); err != nil {
+		m.logger.Warn("failed to sync chats and contacts with enabled installation",
+			zap.String("installationID", id),
+			zap.Error(err),
+		)
+	}
 	return nil
 }
 
@@ -600,6 +858,9 @@ func (m *Messenger) Join(chat Chat) error {
 		}
 		return m.transport.JoinGroup(members)
 	case ChatTypePublic:
+		if chat.CommunityChannel != nil {
+			return m.transport.JoinCommunityChannel(chat.CommunityChannel)
+		}
 		return m.transport.JoinPublic(chat.ID)
 	default:
 		return errors.New("chat is neither public nor private")
@@ -1132,6 +1393,9 @@ func (m *Messenger) BlockContact(contact *Contact) ([]*Chat, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := m.blockPublicKey(contact.ID); err != nil {
+		return nil, err
+	}
 	m.allContacts[contact.ID] = contact
 	for _, chat := range chats {
 		m.allChats[chat.ID] = chat
@@ -1367,6 +1631,8 @@ func (m *Messenger) SendChatMessage(ctx context.Context, message *Message) (*Mes
 	}
 
 	message.ID = types.EncodeHex(id)
+	m.notifyOfflineChatMembers(ctx, chat, id)
+
 	err = message.PrepareContent()
 	if err != nil {
 		return nil, err
@@ -1477,6 +1743,151 @@ func (m *Messenger) sendContactUpdate(ctx context.Context, chatID, ensName, prof
 	response.Contacts = []*Contact{contact}
 	response.Chats = []*Chat{chat}
 
+	chat.LastClockValue = clock
+	err = m.saveChat(chat)
+	if err != nil {
+		return nil, err
+	}
+	err = m.saveContact(contact)
+	if err != nil {
+		return nil, err
+	}
+
+	// Added contacts can request push notifications for this installation
+	if publicKey, err := contact.PublicKey(); err == nil && contact.IsAdded() {
+		m.sharePushNotificationInfoWith(ctx, publicKey)
+	}
+	return &response, nil
+}
+
+// SendContactRequest asks a user to be added as a contact and adds the user to our contacts.
+// If the user has already sent us a request, it's accepted.
+func (m *Messenger) SendContactRequest(ctx context.Context, chatID, message string) (*MessengerResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sendContactRequest(ctx, chatID, message)
+}
+
+// AcceptContactRequest accepts a pending request and sends a contact request back,
+// which lets the other side know that the request has been accepted.
+func (m *Messenger) AcceptContactRequest(ctx context.Context, chatID string) (*MessengerResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	request, ok := m.allContactRequests[chatID]
+	if !ok || !request.IsPending() {
+		return nil, ErrNoPendingContactRequest
+	}
+	return m.sendContactRequest(ctx, chatID, "")
+}
+
+// DeclineContactRequest declines a pending request. Nothing is sent to the other side.
+func (m *Messenger) DeclineContactRequest(chatID string) (*MessengerResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	request, ok := m.allContactRequests[chatID]
+	if !ok || !request.IsPending() {
+		return nil, ErrNoPendingContactRequest
+	}
+	request.State = ContactRequestStateDeclined
+	err := m.persistence.SaveContactRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return &MessengerResponse{ContactRequests: []*ContactRequest{request}}, nil
+}
+
+// ContactRequests returns all received contact requests.
+func (m *Messenger) ContactRequests() []*ContactRequest {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var requests []*ContactRequest
+	for _, request := range m.allContactRequests {
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func (m *Messenger) sendContactRequest(ctx context.Context, chatID, message string) (*MessengerResponse, error) {
+	var response MessengerResponse
+
+	if chatID == contactIDFromPublicKey(&m.identity.PublicKey) {
+		return nil, errors.New("can't send contact request to ourselves")
+	}
+
+	contact, ok := m.allContacts[chatID]
+	if !ok {
+		pubkeyBytes, err := types.DecodeHex(chatID)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err := crypto.UnmarshalPubkey(pubkeyBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		contact, err = buildContact(publicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	publicKey, err := contact.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	chat, ok := m.allChats[chatID]
+	if !ok {
+		chat = OneToOneFromPublicKey(publicKey, m.getTimesource())
+		// We don't want to show the chat to the user
+		chat.Active = false
+	}
+
+	m.allChats[chat.ID] = chat
+	clock, _ := chat.NextClockAndTimestamp(m.getTimesource())
+
+	encodedMessage, err := proto.Marshal(&protobuf.ContactRequest{
+		Clock:   clock,
+		Message: message,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.dispatchMessage(ctx, &RawMessage{
+		LocalChatID:         chatID,
+		Payload:             encodedMessage,
+		MessageType:         protobuf.ApplicationMetadataMessage_CONTACT_REQUEST,
+		ResendAutomatically: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Listen to the contact code topic of the contact, so that once both sides
+	// exchange bundles a negotiated topic is set up
+	if err := m.transport.JoinPrivate(publicKey); err != nil {
+		return nil, err
+	}
+
+	if !contact.IsAdded() {
+		contact.SystemTags = append(contact.SystemTags, contactAdded)
+	}
+
+	if request, ok := m.allContactRequests[chatID]; ok && request.IsPending() {
+		request.State = ContactRequestStateAccepted
+		if err := m.persistence.SaveContactRequest(request); err != nil {
+			return nil, err
+		}
+		response.ContactRequests = []*ContactRequest{request}
+	}
+
+	response.Contacts = []*Contact{contact}
+	response.Chats = []*Chat{chat}
+
 	chat.LastClockValue = clock
 	err = m.saveChat(chat)
 	if err != nil {
@@ -1496,6 +1907,13 @@ func (m *Messenger) SyncDevices(ctx context.Context, ensName, photoPath string)
 		return err
 	}
 
+	return m.syncChatsAndContacts(ctx)
+}
+
+// syncChatsAndContacts sends active public chats and added contacts to paired devices
+func (m *Messenger) syncChatsAndContacts(ctx context.Context) error {
+	myID := contactIDFromPublicKey(&m.identity.PublicKey)
+
 	for _, chat := range m.allChats {
 		if chat.Public() && chat.Active {
 			if err := m.syncPublicChat(ctx, chat); err != nil {
@@ -1708,6 +2126,10 @@ type ReceivedMessageState struct {
 	AllContacts map[string]*Contact
 	// List of contacts modified
 	ModifiedContacts map[string]bool
+	// All contact requests in memory
+	AllContactRequests map[string]*ContactRequest
+	// List of contact requests modified
+	ModifiedContactRequests map[string]bool
 	// All installations in memory
 	AllInstallations map[string]*multidevice.Installation
 	// List of installations modified
@@ -1724,30 +2146,45 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	messageState := &ReceivedMessageState{
-		AllChats:              m.allChats,
-		ModifiedChats:         make(map[string]bool),
-		AllContacts:           m.allContacts,
-		ModifiedContacts:      make(map[string]bool),
-		AllInstallations:      m.allInstallations,
-		ModifiedInstallations: m.modifiedInstallations,
-		ExistingMessagesMap:   make(map[string]bool),
-		Response:              &MessengerResponse{},
-		Timesource:            m.getTimesource(),
+		AllChats:                m.allChats,
+		ModifiedChats:           make(map[string]bool),
+		AllContacts:             m.allContacts,
+		ModifiedContacts:        make(map[string]bool),
+		AllContactRequests:      m.allContactRequests,
+		ModifiedContactRequests: make(map[string]bool),
+		AllInstallations:        m.allInstallations,
+		ModifiedInstallations:   m.modifiedInstallations,
+		ExistingMessagesMap:     make(map[string]bool),
+		Response:                &MessengerResponse{},
+		Timesource:              m.getTimesource(),
 	}
 
 	logger := m.logger.With(zap.String("site", "RetrieveAll"))
 	rawMessages := make(map[transport.Filter][]*v1protocol.StatusMessage)
 
 	for chat, messages := range chatWithMessages {
+		m.filterStats.received(chat, len(messages))
 		for _, shhMessage := range messages {
+			// Envelopes of blocked users are dropped before they are decrypted
+			if m.blockList.isBlockedSignature(shhMessage.Sig) {
+				continue
+			}
+
 			// TODO: fix this to use an exported method.
 			statusMessages, err := m.processor.handleMessages(shhMessage, true)
 			if err != nil {
 				logger.Info("failed to decode messages", zap.Error(err))
 				continue
 			}
+			if len(statusMessages) > 0 && statusMessages[0].DecryptionFailed {
+				m.filterStats.decryptionFailed(chat)
+			}
 
 			for _, msg := range statusMessages {
+				if m.payloadFilters.skip(chat.ChatID, msg) {
+					continue
+				}
+
 				publicKey := msg.SigPubKey()
 
 				// Check for messages from blocked users
@@ -1784,6 +2221,9 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 					Contact:          contact,
 					PublicKey:        publicKey,
 				}
+				if m.pushNotifications != nil {
+					m.pushNotifications.seen(senderID)
+				}
 
 				if msg.ParsedMessage != nil {
 					logger.Debug("Handling parsed message")
@@ -1908,6 +2348,107 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 							continue
 						}
 
+					case protobuf.ContactRequest:
+						logger.Debug("Handling ContactRequest")
+
+						contactRequest := msg.ParsedMessage.(protobuf.ContactRequest)
+
+						err = m.handler.HandleContactRequest(messageState, contactRequest)
+						if err != nil {
+							logger.Warn("failed to handle ContactRequest", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PublicChatsAnnouncement:
+						if !m.publicChatsDirectoryEnabled || chat.ChatID != PublicChatsDirectoryID {
+							logger.Warn("not coming from the public chats directory, ignoring")
+							continue
+						}
+
+						announcement := msg.ParsedMessage.(protobuf.PublicChatsAnnouncement)
+						logger.Debug("Handling PublicChatsAnnouncement")
+						err = m.handler.HandlePublicChatsAnnouncement(messageState, announcement)
+						if err != nil {
+							logger.Warn("failed to handle PublicChatsAnnouncement", zap.Error(err))
+							continue
+						}
+
+					case protobuf.Reaction:
+						reaction := msg.ParsedMessage.(protobuf.Reaction)
+						if reaction.MessageType == protobuf.ChatMessage_PUBLIC_GROUP && chat.ChatID != reaction.ChatId {
+							logger.Warn("reaction for a different public chat, ignoring")
+							continue
+						}
+
+						logger.Debug("Handling Reaction")
+						err = m.handler.HandleReaction(messageState, reaction)
+						if err != nil {
+							logger.Warn("failed to handle Reaction", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PinMessage:
+						pin := msg.ParsedMessage.(protobuf.PinMessage)
+						if pin.MessageType == protobuf.ChatMessage_PUBLIC_GROUP && chat.ChatID != pin.ChatId {
+							logger.Warn("pin for a different public chat, ignoring")
+							continue
+						}
+
+						logger.Debug("Handling PinMessage")
+						err = m.handler.HandlePinMessage(messageState, pin)
+						if err != nil {
+							logger.Warn("failed to handle PinMessage", zap.Error(err))
+							continue
+						}
+
+					case protobuf.ChatIndicator:
+						logger.Debug("Handling ChatIndicator")
+						err = m.handleChatIndicator(messageState, msg.ParsedMessage.(protobuf.ChatIndicator))
+						if err != nil {
+							logger.Debug("failed to handle ChatIndicator", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PushNotificationRegistrationResponse:
+						logger.Debug("Handling PushNotificationRegistrationResponse")
+						err = m.handlePushNotificationRegistrationResponse(messageState, msg.ParsedMessage.(protobuf.PushNotificationRegistrationResponse))
+						if err != nil {
+							logger.Warn("failed to handle PushNotificationRegistrationResponse", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PushNotificationInfo:
+						logger.Debug("Handling PushNotificationInfo")
+						err = m.handlePushNotificationInfo(messageState, msg.ParsedMessage.(protobuf.PushNotificationInfo))
+						if err != nil {
+							logger.Warn("failed to handle PushNotificationInfo", zap.Error(err))
+							continue
+						}
+
+					case protobuf.ChatIdentity:
+						logger.Debug("Handling ChatIdentity")
+						err = m.handleChatIdentity(messageState, msg.ParsedMessage.(protobuf.ChatIdentity))
+						if err != nil {
+							logger.Warn("failed to handle ChatIdentity", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PushNotificationRegistration:
+						logger.Debug("Handling PushNotificationRegistration")
+						err = m.handlePushNotificationRegistration(messageState, msg.ParsedMessage.(protobuf.PushNotificationRegistration))
+						if err != nil {
+							logger.Warn("failed to handle PushNotificationRegistration", zap.Error(err))
+							continue
+						}
+
+					case protobuf.PushNotificationRequest:
+						logger.Debug("Handling PushNotificationRequest")
+						err = m.handlePushNotificationRequest(messageState, msg.ParsedMessage.(protobuf.PushNotificationRequest))
+						if err != nil {
+							logger.Warn("failed to handle PushNotificationRequest", zap.Error(err))
+							continue
+						}
+
 					default:
 						// RawMessage, not processed here, pass straight to the client
 						rawMessages[chat] = append(rawMessages[chat], msg)
@@ -1922,13 +2463,19 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 	}
 
 	for id := range messageState.ModifiedChats {
-		messageState.Response.Chats = append(messageState.Response.Chats, messageState.AllChats[id])
+		chat := messageState.AllChats[id]
+		chat.Muted = m.blockList.isMuted(id)
+		messageState.Response.Chats = append(messageState.Response.Chats, chat)
 	}
 
 	for id := range messageState.ModifiedContacts {
 		messageState.Response.Contacts = append(messageState.Response.Contacts, messageState.AllContacts[id])
 	}
 
+	for id := range messageState.ModifiedContactRequests {
+		messageState.Response.ContactRequests = append(messageState.Response.ContactRequests, messageState.AllContactRequests[id])
+	}
+
 	for id := range messageState.ModifiedInstallations {
 		installation := messageState.AllInstallations[id]
 		messageState.Response.Installations = append(messageState.Response.Installations, installation)
@@ -1952,6 +2499,17 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 		if err != nil {
 			return nil, err
 		}
+		mentions := m.mentions.Find(messageState.Response.Messages)
+		if len(mentions) > 0 {
+			err = m.persistence.SaveMentions(mentions)
+			if err != nil {
+				return nil, err
+			}
+			for _, mention := range mentions {
+				mention.Muted = m.blockList.isMuted(mention.ChatID)
+			}
+			messageState.Response.Mentions = mentions
+		}
 	}
 
 	if len(messageState.Response.Contacts) > 0 {
@@ -1961,6 +2519,13 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 		}
 	}
 
+	for _, request := range messageState.Response.ContactRequests {
+		err = m.persistence.SaveContactRequest(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for filter, messages := range rawMessages {
 		messageState.Response.RawMessages = append(messageState.Response.RawMessages, &RawResponse{Filter: &filter, Messages: messages})
 	}
@@ -2015,6 +2580,11 @@ func (m *Messenger) MessageByChatID(chatID, cursor string, limit int) ([]*Messag
 	return m.persistence.MessageByChatID(chatID, cursor, limit)
 }
 
+// MessageByChatIDInRange returns messages of a chat sent between from and to timestamps in milliseconds.
+func (m *Messenger) MessageByChatIDInRange(chatID string, from, to uint64, cursor string, limit int) ([]*Message, string, error) {
+	return m.persistence.MessageByChatIDInRange(chatID, from, to, cursor, limit)
+}
+
 // DEPRECATED: required by status-react.
 func (m *Messenger) SaveMessages(messages []*Message) error {
 	return m.persistence.SaveMessagesLegacy(messages)
@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerChatIdentitySuite(t *testing.T) {
+	suite.Run(t, new(MessengerChatIdentitySuite))
+}
+
+type MessengerChatIdentitySuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerChatIdentitySuite) TestPublishAndReceive() {
+	theirMessenger := s.newMessenger(s.shh)
+	theirID := contactIDFromPublicKey(&theirMessenger.identity.PublicKey)
+	imageHash := crypto.Keccak256([]byte("image"))
+
+	// the contact code topic is joined together with the one-to-one chat
+	s.Require().NoError(s.m.transport.JoinPrivate(&theirMessenger.identity.PublicKey))
+
+	published, err := theirMessenger.SetChatIdentity(context.Background(), "Alice", imageHash)
+	s.Require().NoError(err)
+	s.Require().NotZero(published.Clock)
+	s.Require().NotZero(published.publishedAt)
+
+	var received *ChatIdentity
+	err = tt.RetryWithBackOff(func() error {
+		if _, err := s.m.RetrieveAll(); err != nil {
+			return err
+		}
+		received, err = s.m.ChatIdentity(theirID)
+		if err == nil && received == nil {
+			err = errors.New("chat identity not received")
+		}
+		return err
+	})
+	s.Require().NoError(err)
+	s.Require().Equal("Alice", received.DisplayName)
+	s.Require().Equal(imageHash, []byte(received.ImageHash))
+	s.Require().Equal(published.Clock, received.Clock)
+	s.Require().NotZero(received.ReceivedAt)
+}
+
+func (s *MessengerChatIdentitySuite) TestSetChatIdentity() {
+	first, err := s.m.SetChatIdentity(context.Background(), "Alice", nil)
+	s.Require().NoError(err)
+	second, err := s.m.SetChatIdentity(context.Background(), "Bob", nil)
+	s.Require().NoError(err)
+	s.Require().True(second.Clock > first.Clock)
+
+	stored, err := s.m.ChatIdentity(contactIDFromPublicKey(&s.m.identity.PublicKey))
+	s.Require().NoError(err)
+	s.Require().Equal(second, stored)
+
+	_, err = s.m.SetChatIdentity(context.Background(), strings.Repeat("a", maxChatIdentityDisplayNameLength+1), nil)
+	s.Require().Error(err)
+	_, err = s.m.SetChatIdentity(context.Background(), "Alice", []byte{1})
+	s.Require().Error(err)
+}
+
+func (s *MessengerChatIdentitySuite) TestHandleChatIdentity() {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	contactID := contactIDFromPublicKey(&key.PublicKey)
+	state := &ReceivedMessageState{
+		CurrentMessageState: &CurrentMessageState{PublicKey: &key.PublicKey},
+		Timesource:          s.m.getTimesource(),
+	}
+
+	s.Require().Error(s.m.handleChatIdentity(state, protobuf.ChatIdentity{DisplayName: "Alice"}))
+	s.Require().NoError(s.m.handleChatIdentity(state, protobuf.ChatIdentity{Clock: 2, DisplayName: "Alice"}))
+	// older identities are ignored
+	s.Require().NoError(s.m.handleChatIdentity(state, protobuf.ChatIdentity{Clock: 1, DisplayName: "Bob"}))
+
+	identity, err := s.m.ChatIdentity(contactID)
+	s.Require().NoError(err)
+	s.Require().Equal("Alice", identity.DisplayName)
+	s.Require().Nil(identity.ImageHash)
+
+	unknown, err := s.m.ChatIdentity(contactIDFromPublicKey(&s.m.identity.PublicKey))
+	s.Require().NoError(err)
+	s.Require().Nil(unknown)
+}
@@ -0,0 +1,137 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerChatIndicatorsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerChatIndicatorsSuite))
+}
+
+type chatIndicatorsRecorder struct {
+	mu         sync.Mutex
+	indicators []ChatIndicator
+}
+
+func (r *chatIndicatorsRecorder) ChatIndicatorReceived(indicator ChatIndicator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indicators = append(r.indicators, indicator)
+}
+
+func (r *chatIndicatorsRecorder) received() []ChatIndicator {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ChatIndicator{}, r.indicators...)
+}
+
+type MessengerChatIndicatorsSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerChatIndicatorsSuite) enableIndicators(m *Messenger) *chatIndicatorsRecorder {
+	recorder := &chatIndicatorsRecorder{}
+	m.chatIndicators = newChatIndicators(ChatIndicatorsConfig{Handler: recorder})
+	return recorder
+}
+
+func (s *MessengerChatIndicatorsSuite) TestOneToOneIndicators() {
+	s.enableIndicators(s.m)
+	alice := s.newMessenger(s.shh)
+	recorder := s.enableIndicators(alice)
+
+	chat := CreateOneToOneChat("alice", &alice.identity.PublicKey, s.m.getTimesource())
+	s.Require().NoError(s.m.SaveChat(&chat))
+	ourChat := CreateOneToOneChat("us", &s.m.identity.PublicKey, alice.getTimesource())
+	s.Require().NoError(alice.SaveChat(&ourChat))
+
+	s.Require().NoError(s.m.SendChatIndicator(context.Background(), chat.ID, ChatIndicatorTyping))
+	s.Require().Equal(ErrChatIndicatorRateLimited, s.m.SendChatIndicator(context.Background(), chat.ID, ChatIndicatorTyping))
+	s.Require().NoError(s.m.SendChatIndicator(context.Background(), chat.ID, ChatIndicatorTypingStopped))
+
+	err := tt.RetryWithBackOff(func() error {
+		if _, err := alice.RetrieveAll(); err != nil {
+			return err
+		}
+		if len(recorder.received()) != 2 {
+			return errors.New("indicators not received")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+
+	types := map[string]bool{}
+	for _, indicator := range recorder.received() {
+		s.Require().Equal(ourChat.ID, indicator.ChatID)
+		s.Require().Equal(ourChat.ID, indicator.From)
+		types[indicator.Type] = true
+	}
+	s.Require().Equal(map[string]bool{ChatIndicatorTyping: true, ChatIndicatorTypingStopped: true}, types)
+}
+
+func (s *MessengerChatIndicatorsSuite) TestSendIndicatorErrors() {
+	s.Require().Equal(ErrChatIndicatorsDisabled, s.m.SendChatIndicator(context.Background(), "status", ChatIndicatorOnline))
+
+	s.enableIndicators(s.m)
+	chat := CreatePublicChat("status", s.m.getTimesource())
+	s.Require().NoError(s.m.SaveChat(&chat))
+	s.Require().Equal(ErrChatIndicatorsNotSupported, s.m.SendChatIndicator(context.Background(), chat.ID, ChatIndicatorOnline))
+	s.Require().Equal(ErrUnknownChatIndicatorType, s.m.SendChatIndicator(context.Background(), chat.ID, "away"))
+}
+
+func (s *MessengerChatIndicatorsSuite) TestHandleIndicator() {
+	recorder := s.enableIndicators(s.m)
+	now := time.Now()
+	s.m.chatIndicators.now = func() time.Time { return now }
+
+	member, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	stranger, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	memberID := contactIDFromPublicKey(&member.PublicKey)
+	strangerID := contactIDFromPublicKey(&stranger.PublicKey)
+
+	group := &Chat{
+		ID:       "group-chat",
+		ChatType: ChatTypePrivateGroupChat,
+		Members:  []ChatMember{{ID: memberID, Joined: true}, {ID: strangerID}},
+	}
+	state := &ReceivedMessageState{AllChats: map[string]*Chat{group.ID: group}}
+	timestamp := uint64(now.UnixNano() / int64(time.Millisecond))
+	receive := func(from string, sent uint64) error {
+		state.CurrentMessageState = &CurrentMessageState{
+			Contact:          &Contact{ID: from},
+			WhisperTimestamp: sent,
+		}
+		return s.m.handleChatIndicator(state, protobuf.ChatIndicator{
+			Clock:  sent,
+			ChatId: group.ID,
+			Type:   protobuf.ChatIndicator_TYPING,
+		})
+	}
+
+	s.Require().NoError(receive(memberID, timestamp))
+	s.Require().Equal(ErrChatIndicatorRateLimited, receive(memberID, timestamp))
+	// only members that joined the group can send indicators
+	s.Require().Error(receive(strangerID, timestamp))
+	// indicators fetched from mailservers are too old
+	now = now.Add(time.Minute)
+	s.Require().Error(receive(memberID, timestamp))
+
+	s.Require().Equal([]ChatIndicator{{
+		ChatID: group.ID,
+		From:   memberID,
+		Type:   ChatIndicatorTyping,
+		Clock:  timestamp,
+	}}, recorder.received())
+}
@@ -0,0 +1,116 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerContactRequestSuite(t *testing.T) {
+	suite.Run(t, new(MessengerContactRequestSuite))
+}
+
+type MessengerContactRequestSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerContactRequestSuite) retrieveContactRequest(m *Messenger) *ContactRequest {
+	var response *MessengerResponse
+	err := tt.RetryWithBackOff(func() error {
+		var err error
+		response, err = m.RetrieveAll()
+		if err == nil && len(response.ContactRequests) == 0 {
+			err = errors.New("contact request not received")
+		}
+		return err
+	})
+	s.Require().NoError(err)
+	s.Require().Len(response.ContactRequests, 1)
+	return response.ContactRequests[0]
+}
+
+func (s *MessengerContactRequestSuite) TestAcceptContactRequest() {
+	contactID := types.EncodeHex(crypto.FromECDSAPub(&s.m.identity.PublicKey))
+
+	theirMessenger := s.newMessenger(s.shh)
+	theirContactID := types.EncodeHex(crypto.FromECDSAPub(&theirMessenger.identity.PublicKey))
+
+	response, err := theirMessenger.SendContactRequest(context.Background(), contactID, "hello")
+	s.Require().NoError(err)
+	s.Require().Len(response.Contacts, 1)
+	s.Require().True(response.Contacts[0].IsAdded())
+
+	request := s.retrieveContactRequest(s.m)
+	s.Require().Equal(theirContactID, request.ID)
+	s.Require().Equal("hello", request.Message)
+	s.Require().True(request.IsPending())
+
+	contact, err := s.m.GetContactByID(theirContactID)
+	s.Require().NoError(err)
+	s.Require().True(contact.HasBeenAdded())
+	s.Require().False(contact.IsAdded())
+
+	response, err = s.m.AcceptContactRequest(context.Background(), theirContactID)
+	s.Require().NoError(err)
+	s.Require().Len(response.ContactRequests, 1)
+	s.Require().Equal(ContactRequestStateAccepted, response.ContactRequests[0].State)
+	s.Require().Len(response.Contacts, 1)
+	s.Require().True(response.Contacts[0].IsAdded())
+
+	// They receive our request, which is accepted as they've already added us
+	request = s.retrieveContactRequest(theirMessenger)
+	s.Require().Equal(contactID, request.ID)
+	s.Require().Equal(ContactRequestStateAccepted, request.State)
+
+	_, err = s.m.AcceptContactRequest(context.Background(), theirContactID)
+	s.Require().Equal(ErrNoPendingContactRequest, err)
+}
+
+func (s *MessengerContactRequestSuite) TestDeclineContactRequest() {
+	contactID := types.EncodeHex(crypto.FromECDSAPub(&s.m.identity.PublicKey))
+
+	theirMessenger := s.newMessenger(s.shh)
+	theirContactID := types.EncodeHex(crypto.FromECDSAPub(&theirMessenger.identity.PublicKey))
+
+	_, err := theirMessenger.SendContactRequest(context.Background(), contactID, "hello")
+	s.Require().NoError(err)
+
+	s.retrieveContactRequest(s.m)
+
+	response, err := s.m.DeclineContactRequest(theirContactID)
+	s.Require().NoError(err)
+	s.Require().Equal(ContactRequestStateDeclined, response.ContactRequests[0].State)
+
+	requests, err := s.m.persistence.ContactRequests()
+	s.Require().NoError(err)
+	s.Require().Len(requests, 1)
+	s.Require().Equal(ContactRequestStateDeclined, requests[0].State)
+
+	contact, err := s.m.GetContactByID(theirContactID)
+	s.Require().NoError(err)
+	s.Require().False(contact.IsAdded())
+}
+
+func (s *MessengerContactRequestSuite) TestRepeatedContactRequest() {
+	contactID := types.EncodeHex(crypto.FromECDSAPub(&s.m.identity.PublicKey))
+
+	theirMessenger := s.newMessenger(s.shh)
+	theirContactID := types.EncodeHex(crypto.FromECDSAPub(&theirMessenger.identity.PublicKey))
+
+	for _, message := range []string{"hello", "hello again"} {
+		_, err := theirMessenger.SendContactRequest(context.Background(), contactID, message)
+		s.Require().NoError(err)
+		request := s.retrieveContactRequest(s.m)
+		s.Require().Equal(message, request.Message)
+	}
+
+	contact, err := s.m.GetContactByID(theirContactID)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{contactRequestReceived}, contact.SystemTags)
+}
@@ -275,3 +275,70 @@ func (s *MessengerInstallationSuite) TestSyncInstallation() {
 	s.Require().Equal("profile-image", ourContact.Photo)
 
 }
+
+func (s *MessengerInstallationSuite) TestSyncOnEnableInstallation() {
+	contactKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	contact, err := buildContact(&contactKey.PublicKey)
+	s.Require().NoError(err)
+	contact.SystemTags = append(contact.SystemTags, contactAdded)
+	err = s.m.SaveContact(contact)
+	s.Require().NoError(err)
+
+	chat := CreatePublicChat("status", s.m.transport)
+	err = s.m.SaveChat(&chat)
+	s.Require().NoError(err)
+
+	theirMessenger := s.newMessengerWithKey(s.shh, s.privateKey)
+
+	err = theirMessenger.SetInstallationMetadata(theirMessenger.installationID, &multidevice.InstallationMetadata{
+		Name:       "their-name",
+		DeviceType: "their-device-type",
+	})
+	s.Require().NoError(err)
+	_, err = theirMessenger.SendPairInstallation(context.Background())
+	s.Require().NoError(err)
+
+	// Wait for the message to reach its destination
+	err = tt.RetryWithBackOff(func() error {
+		response, err := s.m.RetrieveAll()
+		if err == nil && len(response.Installations) == 0 {
+			err = errors.New("installation not received")
+		}
+		return err
+	})
+	s.Require().NoError(err)
+
+	// enabling a new installation replicates state without an explicit sync
+	err = s.m.EnableInstallation(theirMessenger.installationID)
+	s.Require().NoError(err)
+
+	var (
+		statusChat    *Chat
+		actualContact *Contact
+	)
+	err = tt.RetryWithBackOff(func() error {
+		response, err := theirMessenger.RetrieveAll()
+		if err != nil {
+			return err
+		}
+		for _, c := range response.Chats {
+			if c.ID == "status" {
+				statusChat = c
+			}
+		}
+		for _, c := range response.Contacts {
+			if c.ID == contact.ID {
+				actualContact = c
+			}
+		}
+		if statusChat == nil || actualContact == nil {
+			return errors.New("not received chat & contact")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().True(statusChat.Active)
+	s.Require().True(actualContact.IsAdded())
+}
@@ -0,0 +1,56 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+func TestMessengerKeysBackupSuite(t *testing.T) {
+	suite.Run(t, new(MessengerKeysBackupSuite))
+}
+
+type MessengerKeysBackupSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerKeysBackupSuite) TestExportImportKeys() {
+	privateKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	m := s.newMessengerWithKey(s.shh, privateKey)
+
+	chat := CreatePublicChat("status", m.getTimesource())
+	s.Require().NoError(m.Join(chat))
+
+	contactKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.Require().NoError(m.transport.JoinPrivate(&contactKey.PublicKey))
+
+	blob, err := m.ExportKeys("password")
+	s.Require().NoError(err)
+
+	// A messenger with the same identity but an empty database.
+	restored := s.newMessengerWithKey(s.shh, privateKey)
+	s.Require().NotContains(restored.transport.SymmetricKeys(), "status")
+
+	s.Require().Equal(ErrInvalidKeysBackup, restored.ImportKeys(blob, "wrong"))
+
+	s.Require().NoError(restored.ImportKeys(blob, "password"))
+	s.Require().Equal(m.transport.SymmetricKeys()["status"], restored.transport.SymmetricKeys()["status"])
+
+	var hasPublic, hasOneToOne bool
+	contactID := contactIDFromPublicKey(&contactKey.PublicKey)
+	for _, filter := range restored.transport.Filters() {
+		if filter.ChatID == "status" {
+			hasPublic = true
+		}
+		if filter.ChatID == transport.ContactCodeTopic(&contactKey.PublicKey) && "0x"+filter.Identity == contactID {
+			hasOneToOne = true
+		}
+	}
+	s.Require().True(hasPublic)
+	s.Require().True(hasOneToOne)
+}
@@ -0,0 +1,116 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMentionMatcherFind(t *testing.T) {
+	matcher := newMentionMatcher("0x04aa")
+	matcher.setDisplayName("Alice")
+	matcher.setNames([]string{"alice.stateofus.eth"})
+
+	publicMessage := func(id, from, text string) *Message {
+		message := &Message{ID: id, From: from, LocalChatID: "status"}
+		message.MessageType = protobuf.ChatMessage_PUBLIC_GROUP
+		message.Text = text
+		return message
+	}
+	private := publicMessage("0x06", "0x04bb", "@alice")
+	private.MessageType = protobuf.ChatMessage_ONE_TO_ONE
+
+	found := matcher.Find([]*Message{
+		publicMessage("0x01", "0x04bb", "hi @0x04AA"),
+		publicMessage("0x02", "0x04bb", "@alice, look"),
+		publicMessage("0x03", "0x04bb", "ask @ALICE.stateofus.eth"),
+		publicMessage("0x04", "0x04bb", "@alice2 is not alice"),
+		publicMessage("0x05", "0x04aa", "mentioning myself @alice"),
+		private,
+		publicMessage("0x07", "0x04bb", "alice without @"),
+	})
+	var ids []string
+	for _, mention := range found {
+		ids = append(ids, mention.MessageID)
+		require.Equal(t, "status", mention.ChatID)
+		require.Equal(t, "0x04bb", mention.From)
+	}
+	require.Equal(t, []string{"0x01", "0x02", "0x03"}, ids)
+}
+
+func TestMentionNamesValidation(t *testing.T) {
+	require.NoError(t, validateMentionNames([]string{"alice.eth"}))
+	require.Equal(t, ErrInvalidMentionName, validateMentionNames([]string{" "}))
+	require.Equal(t, ErrInvalidMentionName, validateMentionNames([]string{strings.Repeat("a", maxChatIdentityDisplayNameLength+1)}))
+}
+
+func TestMessengerMentionsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerMentionsSuite))
+}
+
+type MessengerMentionsSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerMentionsSuite) TestReceiveMentions() {
+	s.Require().NoError(s.m.SetMentionNames([]string{"bob.stateofus.eth"}))
+	names, err := s.m.MentionNames()
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"bob.stateofus.eth"}, names)
+
+	chat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(&chat))
+	s.Require().NoError(s.m.Join(chat))
+	s.Require().NoError(s.m.MuteChat(chat.ID))
+
+	alice := s.newMessenger(s.shh)
+	aliceChat := CreatePublicChat("status", alice.transport)
+	s.Require().NoError(alice.SaveChat(&aliceChat))
+
+	for _, text := range []string{"hello @bob.stateofus.eth", "nothing for bob"} {
+		message := buildTestMessage(aliceChat)
+		message.Text = text
+		_, err = alice.SendChatMessage(context.Background(), message)
+		s.Require().NoError(err)
+	}
+
+	var (
+		received int
+		signaled []*Mention
+	)
+	err = tt.RetryWithBackOff(func() error {
+		response, err := s.m.RetrieveAll()
+		if err != nil {
+			return err
+		}
+		received += len(response.Messages)
+		signaled = append(signaled, response.Mentions...)
+		if received < 2 {
+			return errors.New("messages not received")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(signaled, 1)
+	s.Require().True(signaled[0].Muted)
+
+	mentions, err := s.m.Mentions("", 0)
+	s.Require().NoError(err)
+	s.Require().Len(mentions, 1)
+	s.Require().Equal(chat.ID, mentions[0].ChatID)
+	s.Require().Equal(contactIDFromPublicKey(&alice.identity.PublicKey), mentions[0].From)
+	s.Require().True(mentions[0].Muted)
+	s.Require().NotNil(mentions[0].Message)
+	s.Require().Equal("hello @bob.stateofus.eth", mentions[0].Message.Text)
+
+	mentions, err = s.m.Mentions("other", 10)
+	s.Require().NoError(err)
+	s.Require().Len(mentions, 0)
+}
@@ -0,0 +1,133 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/transport"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerPinsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerPinsSuite))
+}
+
+type MessengerPinsSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerPinsSuite) retrievePinnedMessages(m *Messenger, chatID string, expected int) []*PinnedMessage {
+	var pinned []*PinnedMessage
+	err := tt.RetryWithBackOff(func() error {
+		if _, err := m.RetrieveAll(); err != nil {
+			return err
+		}
+		var err error
+		pinned, err = m.PinnedMessages(chatID)
+		if err != nil {
+			return err
+		}
+		if len(pinned) != expected {
+			return errors.New("pinned messages not received")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+	return pinned
+}
+
+func (s *MessengerPinsSuite) TestOneToOnePins() {
+	alice := s.newMessenger(s.shh)
+	chat := CreateOneToOneChat("alice", &alice.identity.PublicKey, s.m.getTimesource())
+	s.Require().NoError(s.m.SaveChat(&chat))
+
+	messageID := "0x01"
+	s.Require().NoError(s.m.PinMessage(context.Background(), chat.ID, messageID))
+
+	// the pin is stored in alice's chat with us
+	ourChatID := contactIDFromPublicKey(&s.m.identity.PublicKey)
+	pinned := s.retrievePinnedMessages(alice, ourChatID, 1)
+	s.Require().Equal(messageID, pinned[0].MessageID)
+	s.Require().Equal(ourChatID, pinned[0].PinnedBy)
+	s.Require().Nil(pinned[0].Message)
+
+	s.Require().NoError(s.m.UnpinMessage(context.Background(), chat.ID, messageID))
+	s.retrievePinnedMessages(alice, ourChatID, 0)
+}
+
+func (s *MessengerPinsSuite) TestPublicChatPinsNotSupported() {
+	chat := CreatePublicChat("status", s.m.getTimesource())
+	s.Require().NoError(s.m.SaveChat(&chat))
+	s.Require().Equal(ErrPinningNotSupported, s.m.PinMessage(context.Background(), "status", "0x01"))
+}
+
+func (s *MessengerPinsSuite) TestAuthorizePin() {
+	admin := "0x04aa"
+	member := "0x04bb"
+	group := &Chat{
+		ChatType: ChatTypePrivateGroupChat,
+		Members:  []ChatMember{{ID: admin, Admin: true, Joined: true}, {ID: member, Joined: true}},
+	}
+	s.Require().NoError(authorizePin(group, admin))
+	s.Require().Equal(ErrPinNotAuthorized, authorizePin(group, member))
+
+	communityKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	descriptor, err := transport.NewChannelDescriptor(communityKey, "general", 1)
+	s.Require().NoError(err)
+	channel := CreateCommunityChannelChat("general", descriptor, s.m.getTimesource())
+	s.Require().NoError(authorizePin(&channel, contactIDFromPublicKey(&communityKey.PublicKey)))
+	s.Require().Equal(ErrPinNotAuthorized, authorizePin(&channel, admin))
+
+	oneToOne := &Chat{ChatType: ChatTypeOneToOne}
+	s.Require().NoError(authorizePin(oneToOne, member))
+}
+
+func (s *MessengerPinsSuite) TestHandlePinMessageFromNonAdmin() {
+	member, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	contact, err := buildContact(&member.PublicKey)
+	s.Require().NoError(err)
+
+	chatID := "group-0x04aa"
+	state := &ReceivedMessageState{
+		CurrentMessageState: &CurrentMessageState{WhisperTimestamp: 10, Contact: contact},
+		AllChats: map[string]*Chat{chatID: {
+			ID:       chatID,
+			ChatType: ChatTypePrivateGroupChat,
+			Members:  []ChatMember{{ID: "0x04aa", Admin: true, Joined: true}, {ID: contact.ID, Joined: true}},
+		}},
+	}
+	message := protobuf.PinMessage{Clock: 1, ChatId: chatID, MessageId: "0x01", MessageType: protobuf.ChatMessage_PRIVATE_GROUP, Pinned: true}
+	s.Require().Equal(ErrPinNotAuthorized, s.m.handler.HandlePinMessage(state, message))
+
+	state.AllChats[chatID].Members[1].Admin = true
+	s.Require().NoError(s.m.handler.HandlePinMessage(state, message))
+	pinned, err := s.m.PinnedMessages(chatID)
+	s.Require().NoError(err)
+	s.Require().Len(pinned, 1)
+	s.Require().Equal(contact.ID, pinned[0].PinnedBy)
+}
+
+func (s *MessengerPinsSuite) TestNewerPinWins() {
+	author := "0x04aa"
+	s.Require().NoError(s.m.persistence.SavePinnedMessage("chat", "0x01", author, 2, true))
+	s.Require().NoError(s.m.persistence.SavePinnedMessage("chat", "0x01", author, 1, false))
+	s.Require().NoError(s.m.persistence.SavePinnedMessage("chat", "0x02", author, 3, true))
+
+	pinned, err := s.m.PinnedMessages("chat")
+	s.Require().NoError(err)
+	s.Require().Len(pinned, 2)
+	s.Require().Equal("0x02", pinned[0].MessageID)
+
+	s.Require().NoError(s.m.persistence.SavePinnedMessage("chat", "0x01", author, 4, false))
+	pinned, err = s.m.PinnedMessages("chat")
+	s.Require().NoError(err)
+	s.Require().Len(pinned, 1)
+	s.Require().Equal("0x02", pinned[0].MessageID)
+}
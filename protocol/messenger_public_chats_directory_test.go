@@ -0,0 +1,111 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerPublicChatsDirectorySuite(t *testing.T) {
+	suite.Run(t, new(MessengerPublicChatsDirectorySuite))
+}
+
+type MessengerPublicChatsDirectorySuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerPublicChatsDirectorySuite) SetupTest() {
+	s.MessengerContactUpdateSuite.SetupTest()
+	s.m = s.newDirectoryMessenger()
+}
+
+func (s *MessengerPublicChatsDirectorySuite) newDirectoryMessenger() *Messenger {
+	tmpFile, err := ioutil.TempFile("", "")
+	s.Require().NoError(err)
+	s.tmpFiles = append(s.tmpFiles, tmpFile)
+
+	privateKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	m, err := NewMessenger(
+		privateKey,
+		&testNode{shh: s.shh},
+		uuid.New().String(),
+		WithCustomLogger(s.logger),
+		WithDatabaseConfig(tmpFile.Name(), "some-key"),
+		WithPublicChatsDirectory(),
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(m.Init())
+	return m
+}
+
+func (s *MessengerPublicChatsDirectorySuite) joinPublicChats(m *Messenger, names ...string) {
+	for _, name := range names {
+		chat := CreatePublicChat(name, m.getTimesource())
+		s.Require().NoError(m.SaveChat(&chat))
+		s.Require().NoError(m.Join(chat))
+	}
+}
+
+func (s *MessengerPublicChatsDirectorySuite) TestSuggestedChannels() {
+	s.joinPublicChats(s.m, "status")
+
+	alice := s.newDirectoryMessenger()
+	s.joinPublicChats(alice, "status", "crypto", "music")
+	bob := s.newDirectoryMessenger()
+	s.joinPublicChats(bob, "crypto")
+
+	s.Require().NoError(alice.PublishPublicChats(context.Background()))
+	s.Require().NoError(bob.PublishPublicChats(context.Background()))
+
+	var channels []*SuggestedChannel
+	err := tt.RetryWithBackOff(func() error {
+		if _, err := s.m.RetrieveAll(); err != nil {
+			return err
+		}
+		var err error
+		channels, err = s.m.SuggestedChannels(10)
+		if err == nil && len(channels) < 2 {
+			err = errors.New("announcements not received")
+		}
+		return err
+	})
+	s.Require().NoError(err)
+
+	// "status" is omitted because it's already joined
+	s.Require().Equal([]*SuggestedChannel{
+		{Name: "crypto", Count: 2},
+		{Name: "music", Count: 1},
+	}, channels)
+
+	channels, err = s.m.SuggestedChannels(1)
+	s.Require().NoError(err)
+	s.Require().Len(channels, 1)
+	s.Require().Equal("crypto", channels[0].Name)
+}
+
+func (s *MessengerPublicChatsDirectorySuite) TestNewerAnnouncementReplacesPrevious() {
+	announcer := "0x04aa"
+	s.Require().NoError(s.m.persistence.SavePublicChatsAnnouncement(announcer, 2, 2, []string{"status", "crypto"}))
+	s.Require().NoError(s.m.persistence.SavePublicChatsAnnouncement(announcer, 1, 3, []string{"outdated"}))
+	s.Require().NoError(s.m.persistence.SavePublicChatsAnnouncement(announcer, 3, 4, []string{"crypto"}))
+
+	channels, err := s.m.persistence.SuggestedChannels(0)
+	s.Require().NoError(err)
+	s.Require().Equal([]*SuggestedChannel{{Name: "crypto", Count: 1}}, channels)
+}
+
+func (s *MessengerPublicChatsDirectorySuite) TestDisabled() {
+	m := s.newMessenger(s.shh)
+	s.Require().Equal(ErrPublicChatsDirectoryDisabled, m.PublishPublicChats(context.Background()))
+	_, err := m.SuggestedChannels(10)
+	s.Require().Equal(ErrPublicChatsDirectoryDisabled, err)
+}
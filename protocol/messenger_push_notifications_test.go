@@ -0,0 +1,231 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func TestMessengerPushNotificationsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerPushNotificationsSuite))
+}
+
+type pushNotificationsRecorder struct {
+	mu            sync.Mutex
+	registrations []PushNotificationRegistration
+}
+
+func (r *pushNotificationsRecorder) PushNotificationRegistrationChanged(registration PushNotificationRegistration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registrations = append(r.registrations, registration)
+}
+
+func (r *pushNotificationsRecorder) received() []PushNotificationRegistration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]PushNotificationRegistration{}, r.registrations...)
+}
+
+type MessengerPushNotificationsSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerPushNotificationsSuite) enablePushNotifications(m *Messenger) (*pushNotificationsRecorder, string) {
+	server, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	recorder := &pushNotificationsRecorder{}
+	m.pushNotifications = newPushNotifications(PushNotificationsConfig{Server: &server.PublicKey, Handler: recorder})
+	return recorder, contactIDFromPublicKey(&server.PublicKey)
+}
+
+func (s *MessengerPushNotificationsSuite) TestRegisterErrors() {
+	_, err := s.m.RegisterForPushNotifications(context.Background(), PushNotificationTokenAPN, "token")
+	s.Require().Equal(ErrPushNotificationsDisabled, err)
+
+	s.enablePushNotifications(s.m)
+	_, err = s.m.RegisterForPushNotifications(context.Background(), "sms", "token")
+	s.Require().Equal(ErrUnknownPushNotificationTokenType, err)
+	_, err = s.m.RegisterForPushNotifications(context.Background(), PushNotificationTokenAPN, "")
+	s.Require().Error(err)
+	s.Require().Equal(ErrNotRegisteredForPushNotifications, s.m.UnregisterFromPushNotifications(context.Background()))
+}
+
+func (s *MessengerPushNotificationsSuite) TestRegistration() {
+	recorder, serverID := s.enablePushNotifications(s.m)
+
+	registration, err := s.m.RegisterForPushNotifications(context.Background(), PushNotificationTokenAPN, "token")
+	s.Require().NoError(err)
+	s.Require().Equal(serverID, registration.Server)
+	s.Require().Equal(PushNotificationRegistrationPending, registration.Status)
+	s.Require().Equal(uint64(1), registration.Version)
+	s.Require().NotEmpty(registration.accessToken)
+
+	stored, err := s.m.PushNotificationRegistration()
+	s.Require().NoError(err)
+	s.Require().Equal(registration, stored)
+
+	requestID, err := types.DecodeHex(registration.requestID)
+	s.Require().NoError(err)
+	state := &ReceivedMessageState{}
+	respond := func(from string, response protobuf.PushNotificationRegistrationResponse) error {
+		state.CurrentMessageState = &CurrentMessageState{Contact: &Contact{ID: from}}
+		return s.m.handlePushNotificationRegistrationResponse(state, response)
+	}
+	// only the server can respond
+	s.Require().Error(respond(contactIDFromPublicKey(&s.m.identity.PublicKey), protobuf.PushNotificationRegistrationResponse{Success: true, RequestId: requestID}))
+	s.Require().Error(respond(serverID, protobuf.PushNotificationRegistrationResponse{Success: true, RequestId: []byte{1}}))
+	s.Require().NoError(respond(serverID, protobuf.PushNotificationRegistrationResponse{Success: true, RequestId: requestID}))
+
+	s.Require().Len(recorder.received(), 1)
+	s.Require().Equal(PushNotificationRegistrationRegistered, recorder.received()[0].Status)
+
+	// the access token is kept while the device token doesn't change
+	again, err := s.m.RegisterForPushNotifications(context.Background(), PushNotificationTokenAPN, "token")
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(2), again.Version)
+	s.Require().Equal(registration.accessToken, again.accessToken)
+
+	changed, err := s.m.RegisterForPushNotifications(context.Background(), PushNotificationTokenFirebase, "other")
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(3), changed.Version)
+	s.Require().NotEqual(registration.accessToken, changed.accessToken)
+
+	requestID, err = types.DecodeHex(changed.requestID)
+	s.Require().NoError(err)
+	s.Require().NoError(respond(serverID, protobuf.PushNotificationRegistrationResponse{Error: "invalid token", RequestId: requestID}))
+	s.Require().Len(recorder.received(), 2)
+	s.Require().Equal(PushNotificationRegistrationFailed, recorder.received()[1].Status)
+	s.Require().Equal("invalid token", recorder.received()[1].Error)
+
+	s.Require().NoError(s.m.UnregisterFromPushNotifications(context.Background()))
+	stored, err = s.m.PushNotificationRegistration()
+	s.Require().NoError(err)
+	s.Require().Equal(PushNotificationRegistrationUnregistered, stored.Status)
+	s.Require().Empty(stored.accessToken)
+	s.Require().Len(recorder.received(), 3)
+}
+
+func (s *MessengerPushNotificationsSuite) TestHandleInfo() {
+	s.enablePushNotifications(s.m)
+	contactKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	server, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	contactID := contactIDFromPublicKey(&contactKey.PublicKey)
+
+	contact := &Contact{ID: contactID}
+	state := &ReceivedMessageState{CurrentMessageState: &CurrentMessageState{Contact: contact}}
+	receive := func(accessToken string, version uint64) error {
+		return s.m.handlePushNotificationInfo(state, protobuf.PushNotificationInfo{
+			ServerPublicKey: crypto.CompressPubkey(&server.PublicKey),
+			InstallationId:  "installation",
+			AccessToken:     accessToken,
+			Version:         version,
+		})
+	}
+
+	// only added contacts can share access tokens
+	s.Require().Error(receive("token", 1))
+	contact.SystemTags = []string{contactAdded}
+	s.Require().NoError(receive("token", 2))
+	s.Require().NoError(receive("older", 1))
+
+	infos, err := s.m.persistence.PushNotificationInfos(contactID)
+	s.Require().NoError(err)
+	s.Require().Len(infos, 1)
+	s.Require().Equal("token", infos[0].accessToken)
+	s.Require().Equal(uint64(2), infos[0].version)
+	s.Require().True(isPubKeyEqual(&server.PublicKey, infos[0].server))
+
+	// an empty access token revokes the installation
+	s.Require().NoError(receive("", 3))
+	s.Require().NoError(receive("token", 2))
+	infos, err = s.m.persistence.PushNotificationInfos(contactID)
+	s.Require().NoError(err)
+	s.Require().Len(infos, 1)
+	s.Require().Empty(infos[0].accessToken)
+}
+
+func (s *MessengerPushNotificationsSuite) TestOffline() {
+	s.enablePushNotifications(s.m)
+	now := time.Now()
+	s.m.pushNotifications.now = func() time.Time { return now }
+
+	s.Require().True(s.m.pushNotifications.isOffline("contact"))
+	s.m.pushNotifications.seen("contact")
+	s.Require().False(s.m.pushNotifications.isOffline("contact"))
+	now = now.Add(defaultPushNotificationsOfflineAfter)
+	s.Require().True(s.m.pushNotifications.isOffline("contact"))
+}
+
+type pushNotificationGatewayRecorder struct {
+	deliveries []*PushNotificationDelivery
+}
+
+func (r *pushNotificationGatewayRecorder) Push(deliveries []*PushNotificationDelivery) error {
+	r.deliveries = append(r.deliveries, deliveries...)
+	return nil
+}
+
+func (s *MessengerPushNotificationsSuite) TestServer() {
+	gateway := &pushNotificationGatewayRecorder{}
+	s.m.pushNotificationServer = newPushNotificationServer(PushNotificationServerConfig{Gateway: gateway}, s.m.identity)
+
+	user, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	contact, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	stranger, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+
+	state := &ReceivedMessageState{}
+	register := func(registration protobuf.PushNotificationRegistration) error {
+		return s.m.savePushNotificationServerRegistration(&user.PublicKey, &registration)
+	}
+	request := func(from *ecdsa.PublicKey, accessToken string) error {
+		state.CurrentMessageState = &CurrentMessageState{PublicKey: from}
+		return s.m.handlePushNotificationRequest(state, protobuf.PushNotificationRequest{
+			Notifications: []*protobuf.PushNotification{{InstallationId: "installation", AccessToken: accessToken}},
+			MessageId:     []byte{1},
+		})
+	}
+
+	s.Require().Equal(errPushNotificationInvalidToken, register(protobuf.PushNotificationRegistration{InstallationId: "installation", Version: 1}))
+	s.Require().NoError(register(protobuf.PushNotificationRegistration{
+		TokenType:      protobuf.PushNotificationRegistration_FIREBASE_TOKEN,
+		DeviceToken:    "device",
+		InstallationId: "installation",
+		AccessToken:    "secret",
+		Version:        1,
+		AllowedKeyList: [][]byte{crypto.CompressPubkey(&contact.PublicKey)},
+	}))
+	s.Require().Equal(errPushNotificationStaleVersion, register(protobuf.PushNotificationRegistration{InstallationId: "installation", Version: 1, Unregister: true}))
+
+	// device tokens are not stored in plain text
+	registrations, err := s.m.persistence.PushNotificationServerRegistrations("installation")
+	s.Require().NoError(err)
+	s.Require().Len(registrations, 1)
+	s.Require().NotContains(string(registrations[0]), "device")
+
+	s.Require().Error(request(&contact.PublicKey, "wrong"))
+	s.Require().Error(request(&stranger.PublicKey, "secret"))
+	s.Require().NoError(request(&contact.PublicKey, "secret"))
+	s.Require().Equal([]*PushNotificationDelivery{{
+		TokenType:   PushNotificationTokenFirebase,
+		DeviceToken: "device",
+		MessageID:   "0x01",
+	}}, gateway.deliveries)
+
+	s.Require().NoError(register(protobuf.PushNotificationRegistration{InstallationId: "installation", Version: 2, Unregister: true}))
+	s.Require().Error(request(&contact.PublicKey, "secret"))
+	s.Require().Len(gateway.deliveries, 1)
+}
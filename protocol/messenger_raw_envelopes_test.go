@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerRawEnvelopesSuite(t *testing.T) {
+	suite.Run(t, new(MessengerRawEnvelopesSuite))
+}
+
+type MessengerRawEnvelopesSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerRawEnvelopesSuite) waitForPayload(filterID string, payload []byte) {
+	api := s.shh.PublicWhisperAPI()
+	err := tt.RetryWithBackOff(func() error {
+		messages, err := api.GetFilterMessages(filterID)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if bytes.Equal(message.Payload, payload) {
+				return nil
+			}
+		}
+		return errors.New("envelope not received")
+	})
+	s.Require().NoError(err)
+}
+
+func (s *MessengerRawEnvelopesSuite) TestSendWithSymKey() {
+	topic := types.BytesToTopic([]byte("raw1"))
+	symKey := bytes.Repeat([]byte{1}, 32)
+	symKeyID, err := s.shh.AddSymKeyDirect(symKey)
+	s.Require().NoError(err)
+	filterID, err := s.shh.PublicWhisperAPI().NewMessageFilter(types.Criteria{
+		SymKeyID: symKeyID,
+		Topics:   []types.TopicType{topic},
+	})
+	s.Require().NoError(err)
+
+	payload := []byte("raw payload")
+	hash, err := s.m.SendRawEnvelope(context.Background(), topic, payload, RawEnvelopeOptions{SymKey: symKey, TTL: 60})
+	s.Require().NoError(err)
+	s.Require().Len(hash, types.HashLength)
+
+	s.waitForPayload(filterID, payload)
+}
+
+func (s *MessengerRawEnvelopesSuite) TestSendWithPublicKey() {
+	topic := types.BytesToTopic([]byte("raw2"))
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	keyID, err := s.shh.AddKeyPair(key)
+	s.Require().NoError(err)
+	filterID, err := s.shh.PublicWhisperAPI().NewMessageFilter(types.Criteria{
+		PrivateKeyID: keyID,
+		Topics:       []types.TopicType{topic},
+	})
+	s.Require().NoError(err)
+
+	payload := []byte("raw payload")
+	hash, err := s.m.SendRawEnvelope(context.Background(), topic, payload, RawEnvelopeOptions{PublicKey: &key.PublicKey})
+	s.Require().NoError(err)
+	s.Require().Len(hash, types.HashLength)
+
+	s.waitForPayload(filterID, payload)
+}
+
+func (s *MessengerRawEnvelopesSuite) TestKeysRequired() {
+	topic := types.BytesToTopic([]byte("raw3"))
+	_, err := s.m.SendRawEnvelope(context.Background(), topic, []byte{1}, RawEnvelopeOptions{})
+	s.Require().Equal(ErrRawEnvelopeKeys, err)
+
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	_, err = s.m.SendRawEnvelope(context.Background(), topic, []byte{1}, RawEnvelopeOptions{SymKey: []byte{1}, PublicKey: &key.PublicKey})
+	s.Require().Equal(ErrRawEnvelopeKeys, err)
+}
@@ -0,0 +1,101 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestMessengerReactionsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerReactionsSuite))
+}
+
+type MessengerReactionsSuite struct {
+	MessengerContactUpdateSuite
+}
+
+func (s *MessengerReactionsSuite) joinPublicChat(m *Messenger, name string) {
+	chat := CreatePublicChat(name, m.getTimesource())
+	s.Require().NoError(m.SaveChat(&chat))
+	s.Require().NoError(m.Join(chat))
+}
+
+func (s *MessengerReactionsSuite) retrieveReactions(m *Messenger, chatID, messageID string, expected int) []*ReactionSummary {
+	var reactions map[string][]*ReactionSummary
+	err := tt.RetryWithBackOff(func() error {
+		if _, err := m.RetrieveAll(); err != nil {
+			return err
+		}
+		var err error
+		reactions, err = m.Reactions(chatID, []string{messageID})
+		if err != nil {
+			return err
+		}
+		count := 0
+		for _, reaction := range reactions[messageID] {
+			count += reaction.Count
+		}
+		if count != expected {
+			return errors.New("reactions not received")
+		}
+		return nil
+	})
+	s.Require().NoError(err)
+	return reactions[messageID]
+}
+
+func (s *MessengerReactionsSuite) TestPublicChatReactions() {
+	alice := s.newMessenger(s.shh)
+	s.joinPublicChat(s.m, "status")
+	s.joinPublicChat(alice, "status")
+
+	messageID := "0x01"
+	s.Require().NoError(s.m.SendReaction(context.Background(), "status", messageID, "👍"))
+	s.Require().NoError(alice.SendReaction(context.Background(), "status", messageID, "👍"))
+	s.Require().NoError(alice.SendReaction(context.Background(), "status", messageID, "🎉"))
+
+	reactions := s.retrieveReactions(s.m, "status", messageID, 3)
+	s.Require().Len(reactions, 2)
+	s.Require().Equal("🎉", reactions[0].Reaction)
+	s.Require().Equal(1, reactions[0].Count)
+	s.Require().Equal("👍", reactions[1].Reaction)
+	s.Require().Equal(2, reactions[1].Count)
+	s.Require().Contains(reactions[1].Authors, contactIDFromPublicKey(&alice.identity.PublicKey))
+
+	s.Require().NoError(alice.RetractReaction(context.Background(), "status", messageID, "🎉"))
+	reactions = s.retrieveReactions(s.m, "status", messageID, 2)
+	s.Require().Len(reactions, 1)
+	s.Require().Equal("👍", reactions[0].Reaction)
+}
+
+func (s *MessengerReactionsSuite) TestOneToOneReactions() {
+	alice := s.newMessenger(s.shh)
+	chat := CreateOneToOneChat("alice", &alice.identity.PublicKey, s.m.getTimesource())
+	s.Require().NoError(s.m.SaveChat(&chat))
+
+	messageID := "0x02"
+	s.Require().NoError(s.m.SendReaction(context.Background(), chat.ID, messageID, "❤️"))
+
+	// the reaction is stored in alice's chat with us
+	reactions := s.retrieveReactions(alice, contactIDFromPublicKey(&s.m.identity.PublicKey), messageID, 1)
+	s.Require().Equal("❤️", reactions[0].Reaction)
+}
+
+func (s *MessengerReactionsSuite) TestNewerReactionWins() {
+	author := "0x04aa"
+	s.Require().NoError(s.m.persistence.SaveReaction("status", "0x01", author, "👍", 2, false))
+	s.Require().NoError(s.m.persistence.SaveReaction("status", "0x01", author, "👍", 1, true))
+
+	reactions, err := s.m.Reactions("status", []string{"0x01"})
+	s.Require().NoError(err)
+	s.Require().Len(reactions["0x01"], 1)
+
+	s.Require().NoError(s.m.persistence.SaveReaction("status", "0x01", author, "👍", 3, true))
+	reactions, err = s.m.Reactions("status", []string{"0x01"})
+	s.Require().NoError(err)
+	s.Require().Empty(reactions)
+}
@@ -4,6 +4,34 @@
 // 000001_init.up.db.sql (2.719kB)
 // 000002_add_last_ens_clock_value.down.sql (0)
 // 000002_add_last_ens_clock_value.up.sql (77B)
+// 000003_add_contact_requests.down.sql (29B)
+// 000003_add_contact_requests.up.sql (203B)
+// 000004_add_public_chats_directory.down.sql (35B)
+// 000004_add_public_chats_directory.up.sql (301B)
+// 000005_add_reactions.down.sql (22B)
+// 000005_add_reactions.up.sql (366B)
+// 1589365189_add_outbox.down.sql (28B)
+// 1589365189_add_outbox.up.sql (476B)
+// 1589460000_add_community_channel.down.sql (0)
+// 1589460000_add_community_channel.up.sql (53B)
+// 1589550000_add_message_segments.down.sql (29B)
+// 1589550000_add_message_segments.up.sql (368B)
+// 1589640000_add_user_messages_fts.down.sql (211B)
+// 1589640000_add_user_messages_fts.up.sql (1.031kB)
+// 1589730000_add_push_notifications.down.sql (80B)
+// 1589730000_add_push_notifications.up.sql (662B)
+// 1589740000_add_push_notification_server.down.sql (51B)
+// 1589740000_add_push_notification_server.up.sql (400B)
+// 1589750000_add_chat_identities.down.sql (28B)
+// 1589750000_add_chat_identities.up.sql (263B)
+// 1589760000_add_block_and_mute_lists.down.sql (56B)
+// 1589760000_add_block_and_mute_lists.up.sql (252B)
+// 1589846400_add_datasync_installation_acks.down.sql (90B)
+// 1589846400_add_datasync_installation_acks.up.sql (452B)
+// 1590600000_add_pinned_messages.down.sql (38B)
+// 1590600000_add_pinned_messages.up.sql (255B)
+// 1590700000_add_mentions.down.sql (47B)
+// 1590700000_add_mentions.up.sql (357B)
 // doc.go (377B)
 
 package migrations
@@ -73,7 +101,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __000001_initDownDbSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x48\xce\x48\x2c\x29\xb6\xe6\x42\x12\x29\x2d\x4e\x2d\x8a\xcf\x4d\x2d\x2e\x4e\x4c\x4f\x45\x95\x49\xce\xcf\x2b\x49\x4c\x06\x29\x07\x04\x00\x00\xff\xff\x61\x86\xbd\x5f\x41\x00\x00\x00")
+var __000001_initDownDbSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x41\x00\xbe\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x74\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x5f\x6d\x65\x73\x73\x61\x67\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x6e\x74\x61\x63\x74\x73\x3b\x0a\x03\x00\x61\x86\xbd\x5f\x41\x00\x00\x00")
 
 func _000001_initDownDbSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -88,12 +116,12 @@ func _000001_initDownDbSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "000001_init.down.db.sql", size: 65, mode: os.FileMode(0644), modTime: time.Unix(1578682784, 0)}
+	info := bindataFileInfo{name: "000001_init.down.db.sql", size: 65, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5e, 0xbb, 0x3f, 0x1, 0x75, 0x19, 0x70, 0x86, 0xa7, 0x34, 0x40, 0x17, 0x34, 0x3e, 0x18, 0x51, 0x79, 0xd4, 0x22, 0xad, 0x8f, 0x80, 0xcc, 0xa6, 0xcc, 0x6, 0x2b, 0x62, 0x2, 0x47, 0xba, 0xf9}}
 	return a, nil
 }
 
-var __000001_initUpDbSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x56\xd1\x6f\xe2\xb8\x13\x7e\xe7\xaf\x18\xe9\xf7\x40\x2b\xd1\x9f\xf6\xa4\xbd\xbd\x93\xfa\x44\xd9\xf4\x0e\x1d\x07\x2b\x9a\x9e\xba\x4f\xd6\xe0\x0c\xc4\xc2\xb1\x23\x7b\x02\x8b\xb4\x7f\xfc\xc9\x81\x40\x0c\x81\xee\xea\xfa\x50\xb5\x33\xe3\x19\xcf\x37\xf3\x7d\xce\x68\x9e\x0c\xd3\x04\xd2\xe1\xd3\x24\x81\xf1\x33\x4c\x67\x29\x24\x6f\xe3\x97\xf4\x05\x64\x8e\xec\xe1\xae\x07\xa0\x32\xf8\x67\x38\x1f\xfd\x39\x9c\xc3\x97\xf9\xf8\xef\xe1\xfc\x2b\xfc\x95\x7c\x85\xd9\x14\x46\xb3\xe9\xf3\x64\x3c\x4a\x61\x9e\x7c\x99\x0c\x47\xc9\xa0\x07\x60\xb0\xa0\x63\x7c\xc8\x37\x7d\x9d\x4c\x82\x43\x5a\x6d\xdd\x85\x07\x3e\x27\xcf\xc3\xd7\x49\x0a\xfd\xff\xe1\x2f\xbf\xff\x96\xfd\xda\x0f\xb1\xbc\x2b\x09\xc6\xd3\x34\x4a\x80\x92\xd5\x86\xe0\x69\x36\x9b\x24\xc3\xe9\x65\x86\x74\xfe\x5a\xdf\x80\x55\x41\x9e\xb1\x28\x2f\x32\x64\xa4\x89\x29\x13\xc8\x42\x6a\x2b\xd7\x62\x83\xba\x8a\x0b\x1d\xb3\x7d\x08\x07\xca\x6a\xa1\x95\x14\x6b\xda\xc1\xd3\x64\xf6\x14\x4c\x95\xd9\x28\xda\x52\x26\x0a\xf2\x1e\x57\x24\xa4\xad\x0c\xdf\xc8\xa1\xd1\xff\x68\xb9\x3a\xf4\x90\xf7\x58\xb0\xa0\x62\x41\xce\x9f\xff\x9f\xab\x52\x54\x65\x86\x4c\x7b\x57\xef\xfe\xb1\xd7\x8b\xe6\x29\xad\x61\x94\xa7\x21\xa6\xc9\x5b\xfa\x23\x13\xc4\x2c\x73\xe4\xfd\x3e\xbe\x0d\x5f\x3d\xda\x0b\x2b\x19\x2f\x36\xe4\xd4\x52\x51\x76\x1c\x4e\xd3\xd6\xf3\x70\xf2\x92\x9c\x47\x09\xbc\x85\x17\x6a\x85\x1d\xc5\x55\x46\x86\x95\xb4\xe6\xd2\x55\xe6\x96\xed\xa5\xb9\x46\x73\x0f\x51\x76\xa3\x9e\xdf\x79\xa6\x42\x30\xae\x4e\x18\x67\xb4\x51\x92\x84\x32\x4b\x7b\xb4\xb1\x53\x8b\x8a\x49\xb0\x15\x8c\x7a\x1d\xd7\xab\xd1\x7f\x78\x80\x31\xf7\x3d\xa8\xa2\xb4\x8e\xd1\x30\x70\x8e\xe1\x97\xf2\xc0\xb8\xd0\x04\x39\x7a\x70\x76\xab\x32\x40\x0f\x5b\x02\x47\x7a\x07\xd6\x80\xe2\x70\x78\x9b\x93\x09\x87\x35\x15\xa1\x57\xb3\x02\x65\x96\xca\x28\xa6\x07\x2f\x9d\xd5\xfa\xff\xbd\x1b\x84\xad\x3c\xb9\x66\x79\xf6\x33\xff\x59\xea\x02\x6c\x73\xe5\x4b\x72\x22\xa2\x50\xf2\x47\x12\x33\x19\xc0\xdb\xca\xc9\x8e\x5d\x08\xc8\x79\x56\x06\x59\x59\x73\x44\x0e\x80\xe9\x1b\x77\x8a\x02\xd4\x5b\x4a\x86\x45\x27\xe5\xa1\xee\xaa\x2d\x29\x87\x7c\x57\x29\x0e\xb5\x70\x89\x56\xe3\xb1\x57\x5b\x89\x5a\xdc\x8e\xc9\x55\x46\xd7\x37\x19\xc0\x91\x2f\xad\xf1\x61\x15\xe2\x6b\x35\x92\xd0\xf4\x72\xb8\xd0\x15\xee\x1f\xa0\x24\x32\xd7\x35\xad\x55\xd5\x56\xbc\xb2\xca\xac\x84\x67\xe4\xca\xc7\x95\x4b\x74\x9e\x32\x51\xe3\x7c\x82\xdd\xe1\x56\x94\xb8\xd3\x16\xb3\x96\xd5\xb3\x92\x6b\x72\xa2\x44\xb9\x3e\xdd\xb2\xb1\xe6\xe8\xf3\x38\xb7\xb4\x45\x81\x26\x6b\xe1\x15\xdb\xf7\x9d\x75\xba\x1a\x29\xe9\x74\x2e\x9d\x2d\xba\x3d\x61\x27\x1c\x4a\xee\xf6\xb2\x43\xe3\xc3\x63\x60\xcd\x8d\xdb\x7a\xb5\x32\xc8\x95\xa3\x56\xe7\x47\x1f\x23\xd7\xb3\x68\x8b\xe6\x78\xfa\x39\x79\x03\x95\x7d\x13\x87\xed\x9e\x4d\x63\x4e\xdd\xed\xed\xf7\x8f\x1d\x27\x08\x9d\xcc\xc5\x62\x77\xdc\xac\xd9\x14\xce\x4e\xef\x51\xae\x16\x9e\xdd\x5d\xff\xc3\x7f\xfc\xe9\xc3\xf7\xef\xed\xc5\x1a\xc0\xc3\xa7\x8f\x03\xf8\xf4\xf1\x3e\x38\x54\x36\x68\x68\x30\xa8\xb7\xf9\xf2\x71\x88\xb5\x23\x2c\x4a\x24\x1d\x3f\x27\x1c\xef\x93\xaa\xd6\x62\x4f\x67\x0f\x65\x2d\xbf\x54\x0f\xfc\xfc\x0d\x3d\xb8\xf8\x3a\x0f\x1d\xd5\x47\xb1\x62\x5b\x20\x2b\x89\x5a\xef\xae\x47\x77\x51\xd3\x91\x54\xa5\x22\xc3\x27\xe1\x6f\xb3\xe5\x1d\xcc\x42\x46\x32\xab\xa0\x96\xa7\x85\xf4\xe1\x79\xd8\xa0\x56\xe1\xd5\xa9\x91\x6c\x0a\xc7\xec\xb9\xe4\x54\xd4\xf9\xb5\x15\x6f\x0f\x63\xdf\x01\xbb\xdd\x09\xbd\x60\x5a\x2a\x57\x43\x4d\xa6\xb1\xc4\x4c\x88\xeb\xb4\x2e\x7b\x0e\x5d\xf3\x39\x75\xf6\x0d\xd4\xc9\x98\x4e\x2c\x22\x28\x02\x1f\xde\x47\xec\xae\xf5\xf7\xfd\x63\xef\xdf\x00\x00\x00\xff\xff\xd7\x95\x8b\x6b\x9f\x0a\x00\x00")
+var __000001_initUpDbSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x56\xd1\x6f\xe3\xb6\x0f\x7e\xcf\x5f\x41\xe0\xf7\xd0\x16\x48\x7e\xb8\x01\xb7\xdb\x80\x3e\xa5\x39\x77\x0b\x96\x25\x87\xd4\x1d\x7a\x4f\x02\x23\x31\xb1\x10\x5b\x32\x24\x3a\xb9\x00\xf7\xc7\x0f\x72\x62\xc7\x8e\x9d\xf4\xb6\xf5\xa1\x68\x49\x8a\xa4\x3e\x7e\x1f\xe5\xc9\x32\x1a\xc7\x11\xc4\xe3\xa7\x59\x04\xd3\x67\x98\x2f\x62\x88\xde\xa6\x2f\xf1\x0b\xc8\x04\xd9\xc3\xfd\x00\x40\x2b\xf8\x6b\xbc\x9c\xfc\x3e\x5e\xc2\x97\xe5\xf4\xcf\xf1\xf2\x2b\xfc\x11\x7d\x85\xc5\x1c\x26\x8b\xf9\xf3\x6c\x3a\x89\x61\x19\x7d\x99\x8d\x27\xd1\x70\x00\x60\x30\xa3\x3a\x3e\xe4\x9b\xbf\xce\x66\xc1\x21\x6d\x6a\x5d\xc7\x03\x9f\xa3\xe7\xf1\xeb\x2c\x86\xbb\xff\xe1\x4f\xbf\xfe\xa2\x7e\xbe\x0b\xb1\x7c\xc8\x09\xa6\xf3\xb8\x0e\x0b\x46\x94\xac\x77\x04\x4f\x8b\xc5\x2c\x1a\xcf\xbb\x19\xe2\xe5\x6b\xd9\x01\xeb\x8c\x3c\x63\x96\x77\x32\x28\x4a\x89\x49\x09\x64\x21\x53\x2b\xb7\x62\x87\x69\xd1\x2e\x54\x67\xfb\x10\x52\xe5\xc5\x2a\xd5\x52\x6c\xe9\x00\x4f\xb3\xc5\x53\x30\x15\x66\xa7\x69\x4f\x4a\x64\xe4\x3d\x6e\x48\x48\x5b\x18\xbe\x91\x23\x45\xff\xa3\xe5\xca\xd0\x53\xde\xba\x60\x46\xd9\x8a\x9c\xbf\xfc\x3f\xd1\xb9\x28\x72\x85\x4c\x47\xd7\xe0\xe1\x71\x30\x68\xcd\x53\x5a\xc3\x28\xcf\x43\x8c\xa3\xb7\xf8\x47\x26\x88\x4a\x39\xf2\xfe\x18\x5f\x35\x5a\x8f\xb6\x63\x25\xe3\xc5\x8e\x9c\x5e\x6b\x52\xf5\x70\xaa\x6b\x3d\x8f\x67\x2f\xd1\x65\x94\xc0\x5b\x78\x61\xaa\xb1\xa7\xb8\x56\x64\x58\x4b\x6b\xba\xae\x3c\xb1\x6c\xbb\xe6\x12\xcd\x23\x44\xea\x46\x3d\x7f\xf0\x4c\x99\x60\xdc\x9c\x31\x56\xb4\xd3\x92\x84\x36\x6b\x5b\xdb\xd8\xe9\x55\xc1\x24\xd8\x0a\xc6\x74\xdb\xae\x57\xa2\x3f\x1a\xc1\x94\xef\x3c\xe8\x2c\xb7\x8e\xd1\x30\x70\x82\xe1\x97\xf6\xc0\xb8\x4a\x09\x12\xf4\xe0\xec\x5e\x2b\x40\x0f\x7b\x02\x47\xe9\x01\xac\x01\xcd\x83\xd1\x08\xf6\x09\x99\x70\x38\xa5\x2c\xdc\xd5\x6c\x40\x9b\xb5\x36\x9a\x69\xe4\xa5\xb3\x69\xfa\xff\xc1\x0d\xc1\x16\x9e\x5c\x45\xca\xe3\xcc\xff\xa9\x74\x01\xf6\x89\xf6\x39\x39\xd1\x92\x50\xf4\x5b\xd4\x56\x32\x80\xb7\x85\x93\x3d\x5c\x08\xc8\x79\xd6\x06\x59\x5b\x53\x23\x07\xc0\xf4\x8d\x3b\xd2\x3f\xa6\x0a\x2c\x25\xc3\xa2\x57\xf2\x50\xde\xaa\xb9\x52\x4e\xf9\xae\x4a\x1c\xca\xc5\x25\x1a\x17\x6f\x7b\x53\x2b\x31\x15\xb7\x63\x12\xad\xce\x6b\xa6\xc3\x64\x00\x47\x3e\xb7\xc6\x07\x2a\x54\x19\x8e\x07\x4f\xe8\xd7\x77\x39\x35\x74\x45\xfb\x27\x28\x89\x4c\x5d\xac\xc3\xd0\x46\x55\x5b\xf0\xc6\x6a\xb3\x11\x9e\x91\x0b\xdf\xae\x9c\xa3\xf3\xa4\x44\x89\xf3\x19\x76\x87\x7b\x91\xe3\x21\xb5\xa8\x1a\xc3\xf0\xac\xe5\x96\x9c\xc8\x51\x6e\xcf\x5d\x56\xd6\x04\x7d\xd2\xce\x2d\x6d\x96\xa1\x51\x0d\xbc\xda\xf6\xe3\x56\xeb\x75\x55\xab\xa4\xd7\xb9\x76\x36\xeb\xf7\x04\x4e\x38\x94\xdc\xef\x65\x87\xc6\x87\xc7\xc0\x9a\x1b\xdd\x7a\xbd\x31\xc8\x85\xa3\xc6\xcd\x6b\x1f\x23\x97\x03\x6a\x2e\xcd\xe9\xfc\x73\xf4\x06\x5a\x7d\x13\x27\x76\x2f\xe6\x6d\x4d\xdd\x1f\xed\x0f\x8f\x3d\x27\x08\x9d\x4c\xc4\xea\x50\x33\x6b\x31\x87\x8b\xd3\x65\x07\xbe\x58\x79\x76\xf7\x77\x1f\xfe\xe3\xcf\x1d\x7c\xff\xde\x24\xd6\x10\x46\x9f\x3e\x0e\xe1\xd3\xc7\x87\xe0\xd0\x6a\x58\xc9\x60\x58\xb2\xb9\xfb\x38\xb4\x77\x47\x20\x4a\xd5\xe8\xbf\x78\xf3\xdf\x17\x55\xb9\x8b\x3d\x5d\x3c\x94\xc1\xe3\xc9\xa8\x9e\x37\xf4\xe4\xe2\xeb\x3a\x74\x54\x1e\xc5\x82\x6d\x86\xac\x25\xa6\xe9\xe1\x7a\x74\x9f\x34\x1d\x49\x9d\x6b\x32\x7c\x5e\xfc\x4d\xb5\xbc\x83\x59\xc8\x48\x66\x43\xae\x49\x48\x1f\x9e\x87\x1d\xa6\x3a\xbc\x3a\x70\xdf\x28\x7c\x06\x66\x38\x38\x33\xf1\x0a\x5c\xd7\x28\xde\x1c\x46\x48\xe3\x88\xdd\xe1\x8c\x5e\x30\xad\xb5\x2b\xa1\x26\x53\x59\xda\x4a\x68\xd7\x69\x34\x7b\x09\x5d\xf5\x39\x75\xf1\x0d\xd4\xab\x98\x5e\x2c\x5a\x50\x04\x3d\xbc\x8f\xd8\x7d\xe3\xef\x87\xc7\xc1\xdf\x03\x00\xd7\x95\x8b\x6b\x9f\x0a\x00\x00")
 
 func _000001_initUpDbSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -108,12 +136,12 @@ func _000001_initUpDbSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "000001_init.up.db.sql", size: 2719, mode: os.FileMode(0644), modTime: time.Unix(1578682784, 0)}
+	info := bindataFileInfo{name: "000001_init.up.db.sql", size: 2719, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x60, 0xdc, 0xeb, 0xe, 0xc2, 0x4f, 0x75, 0xa, 0xf6, 0x3e, 0xc7, 0xc4, 0x4, 0xe2, 0xe1, 0xa4, 0x73, 0x2f, 0x4a, 0xad, 0x1a, 0x0, 0xc3, 0x93, 0x9d, 0x77, 0x3e, 0x31, 0x91, 0x77, 0x2e, 0xc8}}
 	return a, nil
 }
 
-var __000002_add_last_ens_clock_valueDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x01\x00\x00\xff\xff\x00\x00\x00\x00\x00\x00\x00\x00")
+var __000002_add_last_ens_clock_valueDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
 
 func _000002_add_last_ens_clock_valueDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -128,12 +156,12 @@ func _000002_add_last_ens_clock_valueDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "000002_add_last_ens_clock_value.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(1580459788, 0)}
+	info := bindataFileInfo{name: "000002_add_last_ens_clock_value.down.sql", size: 0, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24, 0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55}}
 	return a, nil
 }
 
-var __000002_add_last_ens_clock_valueUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x04\xc0\x41\x0a\x85\x20\x10\x06\xe0\xfd\x3b\xc5\x7f\x84\xb7\x6f\x35\xa5\x41\x30\x8d\x10\xe3\x5a\x64\x70\x95\xe8\x42\xeb\xfc\x7d\xc4\xea\x2f\x28\xad\xec\x61\xbd\xcd\x6c\x73\x80\x9c\xc3\x16\x38\x9e\x82\x9a\xc7\x4c\xa5\x8d\x64\xb5\xdb\x9d\xde\x5c\x9f\x82\x43\x14\x12\x14\x12\x99\xe1\xfc\x4e\x91\x15\xff\xe5\xf7\x05\x00\x00\xff\xff\xd0\x66\x8a\xf7\x4d\x00\x00\x00")
+var __000002_add_last_ens_clock_valueUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4d\x00\xb2\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x6e\x74\x61\x63\x74\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x6c\x61\x73\x74\x5f\x65\x6e\x73\x5f\x63\x6c\x6f\x63\x6b\x5f\x76\x61\x6c\x75\x65\x20\x49\x4e\x54\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x30\x3b\x0a\x03\x00\xd0\x66\x8a\xf7\x4d\x00\x00\x00")
 
 func _000002_add_last_ens_clock_valueUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -148,12 +176,572 @@ func _000002_add_last_ens_clock_valueUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "000002_add_last_ens_clock_value.up.sql", size: 77, mode: os.FileMode(0644), modTime: time.Unix(1580459768, 0)}
+	info := bindataFileInfo{name: "000002_add_last_ens_clock_value.up.sql", size: 77, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4d, 0x3, 0x8f, 0xd5, 0x85, 0x83, 0x47, 0xbe, 0xf9, 0x82, 0x7e, 0x81, 0xa4, 0xbd, 0xaa, 0xd5, 0x98, 0x18, 0x5, 0x2d, 0x82, 0x42, 0x3b, 0x3, 0x50, 0xc3, 0x1e, 0x84, 0x35, 0xf, 0xb6, 0x2b}}
 	return a, nil
 }
 
-var _docGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x8f\xbb\x6e\xc3\x30\x0c\x45\x77\x7f\xc5\x45\x96\x2c\xb5\xb4\x74\xea\xd6\xb1\x7b\x7f\x80\x91\x68\x89\x88\x1e\xae\x48\xe7\xf1\xf7\x85\xd3\x02\xcd\xd6\xf5\x00\xe7\xf0\xd2\x7b\x7c\x66\x51\x2c\x52\x18\xa2\x68\x1c\x58\x95\xc6\x1d\x27\x0e\xb4\x29\xe3\x90\xc4\xf2\x76\x72\xa1\x57\xaf\x46\xb6\xe9\x2c\xd5\x57\x49\x83\x8c\xfd\xe5\xf5\x30\x79\x8f\x40\xed\x68\xc8\xd4\x62\xe1\x47\x4b\xa1\x46\xc3\xa4\x25\x5c\xc5\x32\x08\xeb\xe0\x45\x6e\x0e\xef\x86\xc2\xa4\x06\xcb\x64\x47\x85\x65\x46\x20\xe5\x3d\xb3\xf4\x81\xd4\xe7\x93\xb4\x48\x46\x6e\x47\x1f\xcb\x13\xd9\x17\x06\x2a\x85\x23\x96\xd1\xeb\xc3\x55\xaa\x8c\x28\x83\x83\xf5\x71\x7f\x01\xa9\xb2\xa1\x51\x65\xdd\xfd\x4c\x17\x46\xeb\xbf\xe7\x41\x2d\xfe\xff\x11\xae\x7d\x9c\x15\xa4\xe0\xdb\xca\xc1\x38\xba\x69\x5a\x29\x9c\x29\x31\xf4\xab\x88\xf1\x34\x79\x9f\xfa\x5b\xe2\xc6\xbb\xf5\xbc\x71\x5e\xcf\x09\x3f\x35\xe9\x4d\x31\x77\x38\xe7\xff\x80\x4b\x1d\x6e\xfa\x0e\x00\x00\xff\xff\x9d\x60\x3d\x88\x79\x01\x00\x00")
+var __000003_add_contact_requestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1d\x00\xe2\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x6f\x6e\x74\x61\x63\x74\x5f\x72\x65\x71\x75\x65\x73\x74\x73\x3b\x0a\x03\x00\x33\x1a\x94\x73\x1d\x00\x00\x00")
+
+func _000003_add_contact_requestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_add_contact_requestsDownSql,
+		"000003_add_contact_requests.down.sql",
+	)
+}
+
+func _000003_add_contact_requestsDownSql() (*asset, error) {
+	bytes, err := _000003_add_contact_requestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_add_contact_requests.down.sql", size: 29, mode: os.FileMode(0644), modTime: time.Unix(1792054950, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8, 0x3a, 0xf2, 0xa7, 0x94, 0xb8, 0x8b, 0x9a, 0x83, 0x35, 0xe1, 0xfa, 0xb8, 0x9e, 0xd6, 0x84, 0xe6, 0x54, 0xa1, 0xce, 0x42, 0xcb, 0x82, 0x68, 0x15, 0x58, 0x38, 0x60, 0xb0, 0x6b, 0xf0, 0xe4}}
+	return a, nil
+}
+
+var __000003_add_contact_requestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x8c\xb1\x0a\xc2\x30\x14\x45\xf7\x7c\xc5\xa5\x93\x82\x7f\xe0\x14\xe3\x2b\x06\x63\x5a\xd2\x54\xec\x24\x21\x06\x29\x5a\xab\xe6\xf9\xff\xd2\xa5\x20\xae\xe7\x9e\x7b\x94\x23\xe9\x09\x5e\x6e\x0c\x41\x97\xb0\x95\x07\x9d\x74\xe3\x1b\xc4\xf1\xc1\x21\xf2\xf9\x9d\x5e\x9f\x94\x39\x63\x21\x80\xfe\x82\xa3\x74\x6a\x27\x1d\x6a\xa7\x0f\xd2\x75\xd8\x53\x87\xca\x42\x55\xb6\x34\x5a\x79\x38\xaa\x8d\x54\xb4\x12\xc0\x90\x72\x0e\xd7\x34\x5f\xa6\xba\x6d\x8d\xc1\x96\x4a\xd9\x1a\x8f\xa2\x98\xb4\x78\x1f\xe3\x0d\xda\xfa\x59\x98\x28\xf7\x43\xca\x1c\x86\xe7\xdf\x92\x39\x70\xfa\xa1\x62\xb9\x16\xdf\x01\x00\x97\x18\x0c\xd0\xcb\x00\x00\x00")
+
+func _000003_add_contact_requestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000003_add_contact_requestsUpSql,
+		"000003_add_contact_requests.up.sql",
+	)
+}
+
+func _000003_add_contact_requestsUpSql() (*asset, error) {
+	bytes, err := _000003_add_contact_requestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000003_add_contact_requests.up.sql", size: 203, mode: os.FileMode(0644), modTime: time.Unix(1792054955, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0x66, 0x7c, 0x5b, 0x47, 0x4c, 0xfc, 0x9d, 0x35, 0xde, 0x5f, 0x9f, 0xf1, 0xa4, 0xf3, 0x48, 0x25, 0xe5, 0x37, 0x69, 0x6c, 0x44, 0x52, 0xa2, 0xc3, 0x37, 0xb, 0x49, 0x41, 0xad, 0xb2, 0x51}}
+	return a, nil
+}
+
+var __000004_add_public_chats_directoryDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x23\x00\xdc\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x70\x75\x62\x6c\x69\x63\x5f\x63\x68\x61\x74\x73\x5f\x64\x69\x72\x65\x63\x74\x6f\x72\x79\x3b\x0a\x03\x00\x7f\x70\xe1\x3f\x23\x00\x00\x00")
+
+func _000004_add_public_chats_directoryDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_add_public_chats_directoryDownSql,
+		"000004_add_public_chats_directory.down.sql",
+	)
+}
+
+func _000004_add_public_chats_directoryDownSql() (*asset, error) {
+	bytes, err := _000004_add_public_chats_directoryDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_add_public_chats_directory.down.sql", size: 35, mode: os.FileMode(0644), modTime: time.Unix(1792055485, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc2, 0x65, 0x1e, 0xa5, 0xc6, 0x69, 0x18, 0xdc, 0xaf, 0xfb, 0x44, 0xe2, 0xc8, 0x82, 0x78, 0x31, 0xd0, 0x86, 0x87, 0xb6, 0xbd, 0x73, 0x17, 0x60, 0x71, 0xe4, 0x30, 0x93, 0xcc, 0x1c, 0xcf, 0xff}}
+	return a, nil
+}
+
+var __000004_add_public_chats_directoryUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8f\x4b\x4a\xc5\x30\x18\x85\xe7\x59\xc5\x19\xb6\x70\x77\x70\x47\x31\xfe\xc5\x60\x4c\x4a\x1a\xa5\x1d\x85\x9a\x16\x0c\xf6\x45\x9b\x0e\xdc\xbd\x28\x5a\x11\xed\xf4\x3c\xf8\xf8\x84\x25\xee\x08\x8e\xdf\x28\x82\x2c\xa0\x8d\x03\xd5\xb2\x72\x15\x96\xfd\x79\x88\xc1\x87\x97\x36\x6d\xbe\x8b\x6b\x1f\xd2\xbc\xbe\x21\x63\x40\x3b\x4d\xf3\x3e\x85\x7e\xc5\x13\xb7\xe2\x8e\xdb\xcf\x9f\x7e\x54\xea\xc2\x80\x8f\x87\x8f\xdd\xff\xdd\x30\x87\x57\x48\xed\x7e\xa5\x29\x8e\xfd\x96\xda\x71\xf9\xd3\x94\x56\x3e\x70\xdb\xe0\x9e\x1a\x64\x07\xf6\xf2\xcd\xc8\x61\x34\x84\xd1\x85\x92\xc2\xc1\x52\xa9\xb8\x20\x96\x5f\x19\xfb\x12\x93\xfa\x96\xea\x13\x15\xff\x83\x35\xfa\x64\x93\x1d\x9b\xfc\xca\xde\x07\x00\x3b\x27\xd6\x11\x2d\x01\x00\x00")
+
+func _000004_add_public_chats_directoryUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_add_public_chats_directoryUpSql,
+		"000004_add_public_chats_directory.up.sql",
+	)
+}
+
+func _000004_add_public_chats_directoryUpSql() (*asset, error) {
+	bytes, err := _000004_add_public_chats_directoryUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_add_public_chats_directory.up.sql", size: 301, mode: os.FileMode(0644), modTime: time.Unix(1792055485, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd9, 0x57, 0x43, 0xa, 0x64, 0x92, 0x88, 0x40, 0x87, 0x61, 0xb7, 0xf1, 0xed, 0x8d, 0x8d, 0x3a, 0x16, 0x7f, 0xee, 0xfb, 0x8, 0x36, 0xe, 0xba, 0xd2, 0x25, 0x9d, 0x3, 0x3a, 0xb3, 0x6, 0x4f}}
+	return a, nil
+}
+
+var __000005_add_reactionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x16\x00\xe9\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x61\x63\x74\x69\x6f\x6e\x73\x3b\x0a\x03\x00\xaf\x05\xac\xde\x16\x00\x00\x00")
+
+func _000005_add_reactionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_add_reactionsDownSql,
+		"000005_add_reactions.down.sql",
+	)
+}
+
+func _000005_add_reactionsDownSql() (*asset, error) {
+	bytes, err := _000005_add_reactionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_add_reactions.down.sql", size: 22, mode: os.FileMode(0644), modTime: time.Unix(1792056652, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9d, 0x5c, 0x37, 0xfb, 0x30, 0x33, 0x39, 0x4a, 0x69, 0x88, 0x98, 0x9e, 0xa8, 0x77, 0x3c, 0xaa, 0xc3, 0x9e, 0xe9, 0xae, 0x24, 0x54, 0x9d, 0xd, 0xd8, 0x1c, 0x4d, 0xd3, 0xcd, 0x56, 0xed, 0xa0}}
+	return a, nil
+}
+
+var __000005_add_reactionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\xcd\x6a\xc3\x30\x10\x84\xef\x7a\x8a\x39\xda\xe0\x37\xc8\x69\xa3\xac\xa9\xa8\xba\x0a\xb2\x52\x92\x93\x11\x8a\x69\x42\x7f\x0c\xb6\xfa\xfe\xa5\x45\x8d\x43\x71\xcf\xdf\x2e\x33\xf3\x69\xcf\x14\x18\x81\xb6\x96\x61\x5a\x88\x0b\xe0\xa3\xe9\x42\x87\x69\x88\x29\x5f\xc7\x8f\x19\x95\x02\xd2\x25\xe6\xfe\x7a\xc6\x33\x79\xfd\x40\xfe\xe7\x50\x0e\xd6\x36\x0a\x78\x1f\xe6\x39\xbe\x0c\xff\xe1\xf8\x99\x2f\xe3\xb4\x8a\x7e\x33\x56\x61\x7a\x1b\xd3\x2b\x8c\x84\x3f\x2f\x79\x8a\x29\x0f\x67\x6c\x9d\xb3\x4c\x72\xa3\xd8\x71\x4b\x07\x1b\xd0\x92\xed\xf8\x3b\x79\xef\xcd\x13\xf9\x13\x1e\xf9\x84\x6a\x69\xd9\x94\x4a\xcd\x6d\x63\x0d\x27\xd0\x4e\x5a\x6b\x74\x80\xe7\xbd\x25\xcd\xaa\xde\x28\x55\x04\x19\xd9\xf1\x71\x51\xd2\x17\x1d\xfd\xdd\x74\x27\x0b\xaf\x0a\x6f\xee\xdc\xd4\x1b\xf5\x35\x00\xd4\x04\xa3\x6d\x6e\x01\x00\x00")
+
+func _000005_add_reactionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_add_reactionsUpSql,
+		"000005_add_reactions.up.sql",
+	)
+}
+
+func _000005_add_reactionsUpSql() (*asset, error) {
+	bytes, err := _000005_add_reactionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_add_reactions.up.sql", size: 366, mode: os.FileMode(0644), modTime: time.Unix(1792056652, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x15, 0xd, 0x11, 0x20, 0x9f, 0x7e, 0x5f, 0x4d, 0x2f, 0x66, 0xf2, 0x51, 0x28, 0xa3, 0xd7, 0x78, 0xdd, 0x3d, 0x16, 0xf2, 0xe5, 0xf5, 0x62, 0xc5, 0x82, 0x6e, 0x7f, 0x85, 0xed, 0x80, 0xf1, 0x4c}}
+	return a, nil
+}
+
+var __1589365189_add_outboxDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1c\x00\xe3\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x6f\x75\x74\x62\x6f\x78\x5f\x6d\x65\x73\x73\x61\x67\x65\x73\x3b\x0a\x03\x00\x3e\x68\x0e\x2d\x1c\x00\x00\x00")
+
+func _1589365189_add_outboxDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589365189_add_outboxDownSql,
+		"1589365189_add_outbox.down.sql",
+	)
+}
+
+func _1589365189_add_outboxDownSql() (*asset, error) {
+	bytes, err := _1589365189_add_outboxDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589365189_add_outbox.down.sql", size: 28, mode: os.FileMode(0644), modTime: time.Unix(1792058942, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9c, 0xb1, 0x2b, 0x3, 0xe0, 0x68, 0x67, 0x75, 0x4d, 0x38, 0x2c, 0xb7, 0x40, 0x25, 0x47, 0x7f, 0x3c, 0x8, 0x11, 0x97, 0x73, 0xb6, 0xd2, 0x8c, 0xbe, 0x8d, 0xd1, 0x26, 0xa4, 0x3e, 0x6b, 0x25}}
+	return a, nil
+}
+
+var __1589365189_add_outboxUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xd1\xb1\x6e\xc2\x30\x10\x06\xe0\x3d\x4f\x71\x23\x48\x1d\xba\x33\x99\x60\x54\xab\xae\x8d\x8c\x11\x30\x59\x26\xb9\x22\xab\x09\x8e\xec\x43\x25\x6f\x5f\x85\x32\x34\xa4\xa3\xfd\xfd\xff\xdd\x70\xa5\xe1\xcc\x72\xb0\x6c\x29\x39\x88\x35\x28\x6d\x81\x1f\xc4\xd6\x6e\x21\x5e\xe9\x14\x6f\xae\xc5\x9c\xfd\x19\x33\xcc\x0a\x80\x50\xc3\x52\xea\x25\x6c\x8c\xf8\x60\xe6\x08\xef\xfc\x08\x5a\x41\xa9\xd5\x5a\x8a\xd2\x82\xe1\x1b\xc9\x4a\xfe\x72\x8f\xe2\x85\xc2\x67\xc0\x94\x7f\x3b\xc3\x68\xb5\x93\x72\x40\x8a\x5d\xa8\xa6\xdf\x9d\xef\x9b\xe8\xeb\x7f\xe0\x7a\x6a\x42\xe5\xbe\xb0\xbf\xdb\x90\xcd\x7d\x3b\x7a\x13\x35\x20\x94\x1d\xd7\xe2\xb7\x23\x9f\xce\x48\x60\x38\x93\x53\x0b\x2d\x4e\x4a\x9e\x08\xdb\x8e\xf2\x08\x60\xc5\xd7\x6c\x27\x2d\xbc\x0e\xbb\x2f\x78\x23\xf7\xc8\x4d\xfa\x55\x42\x4f\x58\x3b\x3f\xa5\xc6\x67\x72\x98\x52\x4c\x60\xf9\xc1\x16\x73\xd8\x0b\xfb\xa6\x77\x16\x8c\xde\x8b\xd5\xa2\x28\x1e\xf7\x10\x6a\xc5\x0f\xcf\x17\x70\xa3\xb5\x5a\x3d\xfb\xec\xaf\xcf\x17\xc5\xcf\x00\xb9\x4a\x0e\xba\xdc\x01\x00\x00")
+
+func _1589365189_add_outboxUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589365189_add_outboxUpSql,
+		"1589365189_add_outbox.up.sql",
+	)
+}
+
+func _1589365189_add_outboxUpSql() (*asset, error) {
+	bytes, err := _1589365189_add_outboxUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589365189_add_outbox.up.sql", size: 476, mode: os.FileMode(0644), modTime: time.Unix(1792058942, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4a, 0x30, 0x1a, 0x27, 0x5d, 0xa1, 0xa0, 0x22, 0x31, 0x42, 0x2e, 0xca, 0xd3, 0xd5, 0x36, 0x8e, 0xf7, 0xc5, 0x80, 0xc6, 0x83, 0xbe, 0x50, 0x5, 0xe9, 0x84, 0xb9, 0x8a, 0x6, 0x1e, 0x13, 0x2e}}
+	return a, nil
+}
+
+var __1589460000_add_community_channelDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+func _1589460000_add_community_channelDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589460000_add_community_channelDownSql,
+		"1589460000_add_community_channel.down.sql",
+	)
+}
+
+func _1589460000_add_community_channelDownSql() (*asset, error) {
+	bytes, err := _1589460000_add_community_channelDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589460000_add_community_channel.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(1792059588, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24, 0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55}}
+	return a, nil
+}
+
+var __1589460000_add_community_channelUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x35\x00\xca\xff\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x74\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x63\x6f\x6d\x6d\x75\x6e\x69\x74\x79\x5f\x63\x68\x61\x6e\x6e\x65\x6c\x20\x42\x4c\x4f\x42\x3b\x0a\x03\x00\xf8\x1d\x4a\x1e\x35\x00\x00\x00")
+
+func _1589460000_add_community_channelUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589460000_add_community_channelUpSql,
+		"1589460000_add_community_channel.up.sql",
+	)
+}
+
+func _1589460000_add_community_channelUpSql() (*asset, error) {
+	bytes, err := _1589460000_add_community_channelUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589460000_add_community_channel.up.sql", size: 53, mode: os.FileMode(0644), modTime: time.Unix(1792059588, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x83, 0xe, 0x10, 0x8c, 0xf, 0xc5, 0x62, 0xc4, 0xfa, 0xc, 0x32, 0xf5, 0x8c, 0x6f, 0x83, 0xb, 0xa, 0xe7, 0x32, 0x1, 0x18, 0x7b, 0xe4, 0x4e, 0x77, 0xd3, 0x69, 0xde, 0x2f, 0x1a, 0xc5, 0xd4}}
+	return a, nil
+}
+
+var __1589550000_add_message_segmentsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1d\x00\xe2\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x6d\x65\x73\x73\x61\x67\x65\x5f\x73\x65\x67\x6d\x65\x6e\x74\x73\x3b\x0a\x03\x00\x33\xbd\xaa\xe2\x1d\x00\x00\x00")
+
+func _1589550000_add_message_segmentsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589550000_add_message_segmentsDownSql,
+		"1589550000_add_message_segments.down.sql",
+	)
+}
+
+func _1589550000_add_message_segmentsDownSql() (*asset, error) {
+	bytes, err := _1589550000_add_message_segmentsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589550000_add_message_segments.down.sql", size: 29, mode: os.FileMode(0644), modTime: time.Unix(1792061770, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd3, 0x2a, 0x99, 0xba, 0x32, 0xa0, 0x99, 0x85, 0xb5, 0xf7, 0xd, 0xfc, 0x0, 0xc6, 0x82, 0x12, 0xa, 0x8, 0xb9, 0x15, 0xd0, 0x6, 0x94, 0xe8, 0x66, 0x1c, 0x1e, 0xf2, 0x14, 0xe7, 0x79, 0x3d}}
+	return a, nil
+}
+
+var __1589550000_add_message_segmentsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x4d\x6a\xc3\x30\x10\x46\xf7\x3a\xc5\xb7\xb4\xc1\x37\xc8\xca\x76\x14\x3a\x54\x95\x82\x32\x21\xc9\x4a\xa8\x8d\x70\x4c\x6b\xc7\x30\x0e\x34\xb7\x2f\xfd\xa1\x6d\x50\xd6\x6f\xbe\xe1\xf1\x5a\xaf\x6b\xd6\xe0\xba\x31\x1a\xb4\x82\x75\x0c\xbd\xa7\x0d\x6f\x30\x24\x91\xd8\xa5\x20\xa9\x1b\xd2\x38\x0b\x0a\x05\x9c\xa2\x9c\xd0\x18\xd7\x7c\x5d\xda\xad\x31\x95\x02\xa4\xef\xc2\x74\x79\x0e\xaf\xe9\x7a\x07\x7e\xef\x43\x3f\x1e\xd3\x3b\xc8\xf2\x3d\x2a\xe1\xe5\x7c\x19\xe7\x0c\x4f\xf1\xfa\x76\x8e\xc7\xfc\xeb\xdc\x0f\x49\xe6\x38\x4c\xd9\x64\xed\xe9\xa9\xf6\x07\x3c\xea\x03\x8a\x4f\xdf\xea\xbf\x5f\x75\xeb\x53\xc2\x59\xb4\xce\xae\x0c\xb5\x0c\xaf\xd7\xa6\x6e\xb5\x2a\xb1\x23\x7e\x70\x5b\x86\x77\x3b\x5a\x2e\x94\xfa\xe9\x44\x76\xa9\xf7\x59\x99\xf0\x27\xe3\x6c\x46\x8b\x5f\x5a\x2e\xd4\xc7\x00\x7c\x8b\x97\x2e\x70\x01\x00\x00")
+
+func _1589550000_add_message_segmentsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589550000_add_message_segmentsUpSql,
+		"1589550000_add_message_segments.up.sql",
+	)
+}
+
+func _1589550000_add_message_segmentsUpSql() (*asset, error) {
+	bytes, err := _1589550000_add_message_segmentsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589550000_add_message_segments.up.sql", size: 368, mode: os.FileMode(0644), modTime: time.Unix(1792061773, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x34, 0x64, 0xf5, 0x95, 0x34, 0x39, 0x2c, 0x9e, 0x65, 0x7, 0xce, 0x72, 0xd1, 0xd7, 0x3f, 0xe9, 0x7, 0xd2, 0x58, 0x11, 0xd, 0xf9, 0x1c, 0x9b, 0xa5, 0xe4, 0x15, 0x63, 0x31, 0x42, 0x53, 0x74}}
+	return a, nil
+}
+
+var __1589640000_add_user_messages_ftsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x09\xf2\x74\x77\x77\x0d\x52\x28\x2d\x4e\x2d\x8a\xcf\x4d\x2d\x2e\x4e\x4c\x4f\x2d\x8e\x4f\x2b\x29\x8e\x4f\x4c\x2b\x49\x2d\x8a\x4f\x49\xcd\x49\x2d\x49\xb5\xe6\x22\x4a\x71\x69\x41\x4a\x22\xd1\x8a\x33\xf3\x8a\x53\x8b\x4a\x08\x2a\x4e\x4a\x4d\xcb\x2f\x4a\x45\x53\xed\xe8\xe4\xe3\x8a\xa9\xd6\x9a\x0b\x30\x00\x15\x56\x23\xea\xd3\x00\x00\x00")
+
+func _1589640000_add_user_messages_ftsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589640000_add_user_messages_ftsDownSql,
+		"1589640000_add_user_messages_fts.down.sql",
+	)
+}
+
+func _1589640000_add_user_messages_ftsDownSql() (*asset, error) {
+	bytes, err := _1589640000_add_user_messages_ftsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589640000_add_user_messages_fts.down.sql", size: 211, mode: os.FileMode(0644), modTime: time.Unix(1792063037, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xce, 0xb7, 0x13, 0xc1, 0xd6, 0x82, 0xb6, 0x74, 0x9d, 0x7c, 0xde, 0x13, 0xa9, 0xe7, 0x47, 0xe9, 0x3b, 0x5b, 0x1b, 0x48, 0xbf, 0x7a, 0x1f, 0x7a, 0xe, 0xeb, 0xbe, 0x77, 0xf4, 0xf2, 0xd1, 0x47}}
+	return a, nil
+}
+
+var __1589640000_add_user_messages_ftsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x92\x41\x6b\xdb\x40\x10\x85\xef\xfa\x15\xef\x68\x83\x13\x28\x94\x5e\x8c\x0f\x72\x3c\x76\x05\xaa\x54\xa4\x75\x7a\x34\xb2\x76\xe4\x2c\x95\xb5\x61\x77\x55\x27\xfd\xf5\x65\xa5\x4d\xa8\x71\x8a\x31\xf4\xa6\x1d\xe6\xbd\xf9\x78\x7a\x0f\x05\xc5\x82\xf0\x98\x14\x62\x1b\xa7\x10\xf1\x32\x25\xf4\x96\xcd\xee\xc8\xd6\x56\x07\xb6\xbb\xc6\x59\x6c\xcb\x24\xdb\xa0\x71\xf6\xf3\xc4\xf1\x8b\x9b\xc1\xe9\x9f\xdc\xa9\xdf\xbc\xe8\x3b\x55\x6b\xc9\x5f\x3e\x4d\xe7\x51\x94\x64\x25\x15\x02\x49\x26\xf2\x4b\x93\x89\xd4\xb5\x92\x33\x78\x83\x29\x4a\x4a\xe9\x41\xc0\xe8\xd3\xdb\x0c\xeb\x22\xff\x76\x2e\x9b\x47\xd1\xdd\x1d\x62\x84\x37\x4e\xca\x3d\xc1\x3d\x31\x6c\x75\x64\x24\x2b\x18\x7e\x6e\xab\x9a\xed\x38\x74\xda\xb0\x84\xee\x78\x36\xbc\x55\x27\xf9\x05\xdc\x39\xf3\x0a\xdd\x0c\xa3\xb0\x2f\xfd\x5d\x28\x0b\xc3\x47\xfd\x8b\xa5\xbf\xb2\xe7\x46\x1b\x0e\x42\xcb\xc6\x61\xcf\x75\xd5\x5b\x86\xe4\x96\x1d\xc3\x19\x75\x38\xb0\xb1\xa8\x0c\xa3\xd3\x0e\x8d\xf2\xe7\x1a\x6d\xbc\xdb\xbb\x17\xf6\xaf\x83\x49\xad\xbb\xa6\x55\xb5\x83\x61\xab\xdb\xde\x29\xdd\xdd\x47\x21\x6e\x51\x24\x9b\x0d\x15\x97\x19\xed\x46\x8a\x5d\x20\x58\xd2\x3a\x2f\x08\x21\xd6\x3c\x3b\x17\x60\x49\x9b\x24\x8b\x80\x15\xa5\x24\xe8\x83\xfc\xbc\x25\x7e\x7c\xa5\x82\x30\x84\x8f\x05\x26\x7f\x07\xff\x81\x24\xac\x2b\x89\x05\x3a\x3e\xdd\x2b\x39\x9d\x47\x94\xad\xe6\xd1\x75\xf8\xaa\x71\x6c\xde\xd8\xe3\xb5\xa0\xe2\x2a\xfa\x0d\x8d\x79\x8c\xd3\x2d\x95\x98\x78\xaa\x50\x1b\xff\xe9\xab\x73\x2b\x62\xff\x2c\x2b\xc7\x01\x71\xfb\x7d\xe5\x35\xf9\x7a\xb8\xf3\x6f\xd4\xb0\x77\xe1\x89\x92\xc4\x28\x1d\x13\x1b\x3e\xcf\x43\x7f\x47\xbe\x91\x33\x34\x6f\xe4\x0c\x7f\xf9\xbf\xb4\x40\xb7\xf2\x0c\xe8\xcf\x00\x87\x53\x30\x76\x07\x04\x00\x00")
+
+func _1589640000_add_user_messages_ftsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589640000_add_user_messages_ftsUpSql,
+		"1589640000_add_user_messages_fts.up.sql",
+	)
+}
+
+func _1589640000_add_user_messages_ftsUpSql() (*asset, error) {
+	bytes, err := _1589640000_add_user_messages_ftsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589640000_add_user_messages_fts.up.sql", size: 1031, mode: os.FileMode(0644), modTime: time.Unix(1792063037, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x26, 0x21, 0x1a, 0x1d, 0xa3, 0x2f, 0xec, 0x9c, 0xe0, 0x9e, 0x2d, 0x45, 0xa2, 0x6a, 0x3f, 0xb, 0xb5, 0x58, 0x82, 0xe1, 0x90, 0xb0, 0x6a, 0xf5, 0xe1, 0x2f, 0xec, 0x21, 0x51, 0x68, 0x7, 0x89}}
+	return a, nil
+}
+
+var __1589730000_add_push_notificationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x50\x00\xaf\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x70\x75\x73\x68\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x5f\x69\x6e\x66\x6f\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x70\x75\x73\x68\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x5f\x72\x65\x67\x69\x73\x74\x72\x61\x74\x69\x6f\x6e\x73\x3b\x0a\x03\x00\xd1\xb2\xa4\x39\x50\x00\x00\x00")
+
+func _1589730000_add_push_notificationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589730000_add_push_notificationsDownSql,
+		"1589730000_add_push_notifications.down.sql",
+	)
+}
+
+func _1589730000_add_push_notificationsDownSql() (*asset, error) {
+	bytes, err := _1589730000_add_push_notificationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589730000_add_push_notifications.down.sql", size: 80, mode: os.FileMode(0644), modTime: time.Unix(1792064285, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xff, 0x1b, 0x3, 0xe, 0x17, 0x90, 0x12, 0xa8, 0x51, 0x87, 0x5e, 0x46, 0xfc, 0xd5, 0x8c, 0xe2, 0x6d, 0x7c, 0xea, 0x2f, 0x16, 0xf4, 0xdc, 0xe7, 0x26, 0x43, 0xb6, 0xff, 0xa3, 0x23, 0x7f, 0x89}}
+	return a, nil
+}
+
+var __1589730000_add_push_notificationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xcf\x6e\xb3\x30\x10\xc4\xef\x7e\x8a\xbd\x25\x48\x79\x83\x9c\x0c\x9f\xd1\x87\xea\x42\xe4\x38\x55\x73\xb2\x28\xd9\xb4\x56\x90\x4d\xbd\x06\x29\x6f\x5f\x41\xa5\x36\x6d\x50\xff\x1d\xbd\x33\xda\xb1\x7f\xe3\x4c\x09\xae\x05\x68\x9e\x4a\x01\x45\x0e\x65\xa5\x41\xdc\x17\x5b\xbd\x85\xae\xa7\x27\xe3\x7c\xb4\x47\xdb\xd4\xd1\x7a\x67\x02\x3e\x5a\x8a\x61\x3a\x10\x2c\x19\x80\x75\x14\xeb\xb6\x9d\x26\xc6\x1e\xe0\x8e\xab\xec\x3f\x57\xd3\x9a\x72\x27\x25\x6c\x54\x71\xcb\xd5\x1e\x6e\xc4\x1e\xaa\x12\xb2\xaa\xcc\x65\x91\x69\x50\x62\x23\x79\x26\x56\x0c\x80\x30\x0c\x18\x4c\xd7\x3f\xb4\xb6\x31\x27\x3c\x43\x2a\xab\xf4\x6d\xc5\x68\x89\xfe\x84\xce\xc4\x73\x87\x57\x09\xa3\x7c\xc0\xc1\x36\x68\x26\xd7\xac\xa1\x6e\x1a\x24\xfa\xc2\x30\x60\x20\xeb\x1d\x14\xa5\xfe\x30\x0f\xf8\xdc\x23\xc5\xb9\xa7\x8d\xc1\x14\xeb\xd8\xd3\xac\x84\x21\xf8\x70\xa5\xc0\x3f\x91\xf3\x9d\xd4\xb0\x58\xb0\x64\xcd\xd8\xaf\xf0\x5b\x77\xf4\xaf\xd8\x2f\x58\xcd\x65\x7f\xd7\xca\x0f\xa9\xff\x99\xda\x65\xe9\xcb\xf7\x84\xd5\xe7\x8b\x25\x73\x5f\x82\x25\x6b\xf6\x32\x00\x1e\xf2\x07\x0a\x96\x02\x00\x00")
+
+func _1589730000_add_push_notificationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589730000_add_push_notificationsUpSql,
+		"1589730000_add_push_notifications.up.sql",
+	)
+}
+
+func _1589730000_add_push_notificationsUpSql() (*asset, error) {
+	bytes, err := _1589730000_add_push_notificationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589730000_add_push_notifications.up.sql", size: 662, mode: os.FileMode(0644), modTime: time.Unix(1792064285, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb, 0xbc, 0x87, 0xa4, 0x63, 0xc0, 0x50, 0x0, 0x23, 0xf9, 0xc7, 0x5f, 0xee, 0x3c, 0x93, 0xaa, 0x20, 0x91, 0x3, 0x56, 0x87, 0x9e, 0xcc, 0x3b, 0x2f, 0xaf, 0xd3, 0xd9, 0x3e, 0x31, 0xa0, 0x46}}
+	return a, nil
+}
+
+var __1589740000_add_push_notification_serverDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x33\x00\xcc\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x70\x75\x73\x68\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x5f\x73\x65\x72\x76\x65\x72\x5f\x72\x65\x67\x69\x73\x74\x72\x61\x74\x69\x6f\x6e\x73\x3b\x0a\x03\x00\x70\xce\x1f\x3f\x33\x00\x00\x00")
+
+func _1589740000_add_push_notification_serverDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589740000_add_push_notification_serverDownSql,
+		"1589740000_add_push_notification_server.down.sql",
+	)
+}
+
+func _1589740000_add_push_notification_serverDownSql() (*asset, error) {
+	bytes, err := _1589740000_add_push_notification_serverDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589740000_add_push_notification_server.down.sql", size: 51, mode: os.FileMode(0644), modTime: time.Unix(1792064664, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x24, 0xd2, 0xc7, 0x44, 0x5c, 0xec, 0x16, 0x3e, 0x6b, 0x78, 0x8b, 0x56, 0xe, 0x78, 0xf5, 0xaa, 0x8a, 0x11, 0x29, 0x6e, 0x70, 0xfa, 0x0, 0xc2, 0x15, 0x4c, 0xa5, 0x66, 0xaf, 0x5c, 0x29, 0xa1}}
+	return a, nil
+}
+
+var __1589740000_add_push_notification_serverUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x90\xc1\x4a\x03\x31\x18\x84\xef\x79\x8a\x39\x6e\xa1\x6f\xd0\x53\x36\xfe\xc5\x60\x4c\x4a\x1a\xa5\x3d\x85\xda\x46\xfd\x71\xd9\x2d\xc9\xb6\xe8\xdb\x0b\x8b\xa0\x8d\x97\xbd\x0e\xc3\xcc\x7c\xa3\x3c\xc9\x40\x08\xb2\x35\x04\xbd\x86\x75\x01\xb4\xd3\xdb\xb0\xc5\xf9\x52\xde\x63\x3f\x8c\xfc\xca\xc7\xc3\xc8\x43\x1f\x4b\xca\xd7\x94\x63\x4e\x6f\x5c\xc6\x3c\x69\x05\x8d\x00\xce\x97\x97\x8e\x8f\xf1\x23\x7d\xa1\x35\xae\x9d\x52\xec\x93\x31\x4b\x01\x70\x5f\xc6\x43\xd7\x4d\xee\xc8\x27\x3c\x4b\xaf\xee\xa5\xbf\xf1\x5c\x53\x2e\x3c\xf4\xd0\x36\xdc\xe8\x7f\x9b\xfe\x27\x6f\xbc\x7e\x94\x7e\x8f\x07\xda\xa3\xf9\x9d\xb0\xac\x2b\x17\x70\x16\xca\xd9\xb5\xd1\x2a\xc0\xd3\xc6\x48\x45\x62\xb1\x12\xe2\x87\x5e\xdb\x3b\xda\x55\xf4\x7c\xfa\x8c\xf3\x1e\x88\x35\xa1\xb3\x33\xbf\x6b\xea\xa1\x2b\xf1\x3d\x00\xa4\xcc\x33\xdb\x90\x01\x00\x00")
+
+func _1589740000_add_push_notification_serverUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589740000_add_push_notification_serverUpSql,
+		"1589740000_add_push_notification_server.up.sql",
+	)
+}
+
+func _1589740000_add_push_notification_serverUpSql() (*asset, error) {
+	bytes, err := _1589740000_add_push_notification_serverUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589740000_add_push_notification_server.up.sql", size: 400, mode: os.FileMode(0644), modTime: time.Unix(1792064661, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x29, 0xb5, 0x6c, 0x39, 0x1e, 0x87, 0x1c, 0xba, 0x32, 0x1, 0x89, 0x8f, 0xa, 0x4a, 0xc2, 0xdb, 0x4d, 0x62, 0x29, 0xe3, 0x7e, 0xfd, 0x7d, 0x2e, 0xbd, 0x73, 0x7, 0x3d, 0x27, 0xf1, 0x44, 0x63}}
+	return a, nil
+}
+
+var __1589750000_add_chat_identitiesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1c\x00\xe3\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x63\x68\x61\x74\x5f\x69\x64\x65\x6e\x74\x69\x74\x69\x65\x73\x3b\x0a\x03\x00\xab\x93\xa3\x91\x1c\x00\x00\x00")
+
+func _1589750000_add_chat_identitiesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589750000_add_chat_identitiesDownSql,
+		"1589750000_add_chat_identities.down.sql",
+	)
+}
+
+func _1589750000_add_chat_identitiesDownSql() (*asset, error) {
+	bytes, err := _1589750000_add_chat_identitiesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589750000_add_chat_identities.down.sql", size: 28, mode: os.FileMode(0644), modTime: time.Unix(1792065298, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x13, 0x44, 0xf6, 0x9a, 0x85, 0x6f, 0x89, 0x67, 0x8f, 0xde, 0x3c, 0xde, 0x1e, 0xf1, 0xc2, 0xd3, 0x7c, 0xbc, 0x50, 0x81, 0x44, 0xa8, 0xb1, 0xea, 0x5f, 0xc7, 0x12, 0xff, 0x4b, 0x48, 0x4a, 0xac}}
+	return a, nil
+}
+
+var __1589750000_add_chat_identitiesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\xc1\x4a\x03\x31\x14\x45\xf7\xf3\x15\x97\xae\x14\xba\x70\xef\x2a\x13\xdf\x60\x30\x26\x25\x4d\xc5\xae\x42\xcc\x3c\x4c\xe8\xb4\x16\x13\x85\xfe\xbd\x8c\xd0\x01\x37\x6e\x0f\xe7\x1e\xae\x74\x24\x3c\xc1\x8b\x5e\x13\xd4\x00\x63\x3d\xe8\x55\x6d\xfd\x16\x29\xc7\x16\xca\xc8\xa7\x56\x5a\xe1\x8a\x9b\x0e\x38\x7f\xbd\x4d\x25\x85\x03\x5f\xf0\x22\x9c\x7c\x14\x0e\x1b\xa7\x9e\x85\xdb\xe3\x89\xf6\xb0\x06\xd2\x9a\x41\x2b\xe9\xe1\x68\xa3\x85\xa4\x75\x07\xa4\xe9\x23\x1d\xa0\x8c\xff\xcd\x9b\x9d\xd6\x33\x1d\x4b\x3d\x4f\xf1\x12\x4e\xf1\xc8\x4b\xed\x2a\xe0\x81\x06\xb1\xd3\x1e\xab\xd5\xec\x96\x63\x7c\xe7\x90\x63\xcd\xe8\xb5\xed\x67\xf4\xc9\x89\xcb\x37\x8f\x21\xb6\x3f\xe9\x65\x79\xb7\xbe\x1e\xae\xf9\x3f\xad\xbb\xbd\xef\x7e\x06\x00\xc2\x64\x5f\x32\x07\x01\x00\x00")
+
+func _1589750000_add_chat_identitiesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589750000_add_chat_identitiesUpSql,
+		"1589750000_add_chat_identities.up.sql",
+	)
+}
+
+func _1589750000_add_chat_identitiesUpSql() (*asset, error) {
+	bytes, err := _1589750000_add_chat_identitiesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589750000_add_chat_identities.up.sql", size: 263, mode: os.FileMode(0644), modTime: time.Unix(1792065298, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0xcb, 0xd4, 0x31, 0xba, 0x31, 0xd2, 0xa3, 0x69, 0x5b, 0xa9, 0x12, 0x70, 0x39, 0x87, 0xa2, 0xd1, 0x1a, 0x26, 0xbd, 0xd6, 0x5, 0xa, 0xf0, 0x84, 0xa3, 0xe3, 0x6d, 0x28, 0xb8, 0x8a, 0x82}}
+	return a, nil
+}
+
+var __1589760000_add_block_and_mute_listsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x48\xca\xc9\x4f\xce\x4e\x4d\x89\x2f\x28\x4d\xca\xc9\x4c\x8e\xcf\x4e\xad\x2c\xb6\xe6\x72\x41\xc8\xe7\x96\x96\x00\x65\x93\x33\x12\x4b\x80\xe2\x00\xf3\xde\x05\xde\x38\x00\x00\x00")
+
+func _1589760000_add_block_and_mute_listsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589760000_add_block_and_mute_listsDownSql,
+		"1589760000_add_block_and_mute_lists.down.sql",
+	)
+}
+
+func _1589760000_add_block_and_mute_listsDownSql() (*asset, error) {
+	bytes, err := _1589760000_add_block_and_mute_listsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589760000_add_block_and_mute_lists.down.sql", size: 56, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5b, 0xa3, 0x90, 0xb9, 0x5a, 0x5d, 0xcc, 0x10, 0x38, 0x4e, 0x69, 0x17, 0xef, 0x49, 0x13, 0xdf, 0xf1, 0xe0, 0xdc, 0x8, 0x61, 0x6, 0xdb, 0x22, 0x76, 0xf5, 0xd, 0xfc, 0x5b, 0xc0, 0x1c, 0x91}}
+	return a, nil
+}
+
+var __1589760000_add_block_and_mute_listsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x8e\xbd\x0a\xc2\x30\x14\x85\xf7\x3c\xc5\x19\x15\x7c\x03\xa7\x18\x6e\x31\x18\x93\x92\x5e\xc5\x4e\xa1\x7f\xd0\xd2\x8a\x82\xe9\xe0\xdb\x5b\x2d\xe2\xa2\x83\xdb\x39\xc3\xf9\xbe\xa3\x3c\x49\x26\xb0\xdc\x18\x82\x4e\x60\x1d\x83\x4e\x3a\xe3\x0c\xe5\x70\xa9\xfa\xa6\x0e\xd7\xb1\x1c\xba\x2a\xf4\xcd\xfd\x86\x85\x00\x3e\x1d\x47\xe9\xd5\x56\x7a\xa4\x5e\xef\xa5\xcf\xb1\xa3\x1c\xce\x42\x39\x9b\x18\xad\x18\x9e\x52\x23\x15\xad\xa6\xd5\x9b\x56\x44\x68\xcb\x2f\x8f\x3d\x18\x23\x96\x6b\x21\xd4\xef\x13\xe7\x31\x4e\xa3\xaa\x2d\xe2\x2c\x7f\xa6\xd0\xd5\xff\x98\x67\xc4\x17\xef\x03\xeb\xd2\x86\x4a\xfc\x00\x00\x00")
+
+func _1589760000_add_block_and_mute_listsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589760000_add_block_and_mute_listsUpSql,
+		"1589760000_add_block_and_mute_lists.up.sql",
+	)
+}
+
+func _1589760000_add_block_and_mute_listsUpSql() (*asset, error) {
+	bytes, err := _1589760000_add_block_and_mute_listsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589760000_add_block_and_mute_lists.up.sql", size: 252, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x23, 0x19, 0x85, 0x88, 0xe3, 0x60, 0x70, 0xd9, 0x33, 0xd7, 0xa2, 0xf1, 0xa5, 0x19, 0x11, 0xb, 0xb9, 0x22, 0x2b, 0x3d, 0xa, 0x94, 0x7d, 0xb5, 0x11, 0x63, 0xda, 0x32, 0x6c, 0x4c, 0x55, 0x67}}
+	return a, nil
+}
+
+var __1589846400_add_datasync_installation_acksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xc8\x4c\xa9\x88\x4f\x49\x2c\x49\x2c\xae\xcc\x4b\x8e\xcf\xcc\x2b\x2e\x49\xcc\xc9\x49\x2c\xc9\xcc\xcf\x8b\x4f\x4c\xce\x2e\x8e\x2f\xcd\x03\x52\xa9\x29\xd6\x5c\x2e\x20\x2d\x21\x8e\x4e\x3e\xae\x0a\xb8\x95\x5b\x73\x01\x00\xce\x98\x59\x67\x5a\x00\x00\x00")
+
+func _1589846400_add_datasync_installation_acksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589846400_add_datasync_installation_acksDownSql,
+		"1589846400_add_datasync_installation_acks.down.sql",
+	)
+}
+
+func _1589846400_add_datasync_installation_acksDownSql() (*asset, error) {
+	bytes, err := _1589846400_add_datasync_installation_acksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589846400_add_datasync_installation_acks.down.sql", size: 90, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x81, 0x89, 0xf7, 0x39, 0x2a, 0xfd, 0x69, 0xe4, 0x6a, 0xa7, 0x68, 0xa0, 0x29, 0x6f, 0xee, 0x2d, 0x8c, 0xbc, 0x3d, 0x3e, 0xfb, 0x84, 0x87, 0x71, 0x7b, 0x29, 0x15, 0x8c, 0x98, 0x9a, 0x54, 0x6d}}
+	return a, nil
+}
+
+var __1589846400_add_datasync_installation_acksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x90\x3d\x4f\xc3\x30\x18\x84\xf7\xfc\x8a\x1b\x53\x29\x03\x3b\x93\xe3\x3a\x60\x61\x6c\xe4\xba\xa8\x9d\x2c\x93\x58\xc8\x6a\x70\x91\x9c\x48\xed\xbf\x27\x09\xe2\xc3\x85\xce\xcf\xdd\xfb\xde\x1d\xd5\x8c\x18\x06\x43\x6a\xc1\xc0\x1b\x48\x65\xc0\x76\x7c\x63\x36\xe8\xdc\xe0\xd2\x39\xb6\x36\xc4\x34\xb8\xbe\x77\x43\x38\x46\xeb\xda\x43\x42\x59\x00\x6f\x3e\x25\xf7\xea\x6d\xe8\x50\x0b\x55\x2f\x4e\xb9\x15\xa2\x9a\xd8\xfb\xf8\xd2\x87\xd6\x1e\xfc\x19\xcf\x44\xd3\x7b\xa2\x33\x9c\x1d\x9c\xfc\xff\x69\x92\x8f\x9d\x6d\x8f\x63\x1c\xc0\xa5\xf9\x46\x58\xb3\x86\x6c\x85\xc1\xcd\x2c\x8a\xfe\x34\xd8\x45\xe9\x72\xd9\x0c\xa7\xa4\xfe\x0f\xc8\xfd\x4f\x9a\x3f\x12\xbd\xc7\x03\xdb\xa3\xfc\x29\x54\xfd\x2a\x50\x5d\xa6\x5d\x41\x49\x50\x25\x1b\xc1\xa9\x01\xbf\x93\x4a\xb3\x62\x75\x5b\x14\xf4\x73\x4a\x2e\xd7\x6c\x77\x31\x65\xe8\x4e\xf6\xfa\x9c\x76\x8c\x4b\xd6\xf9\xf0\x75\x55\xf9\xd5\xa7\xca\x6a\x4f\x9f\x3f\x00\xeb\xf2\x74\xb6\xc4\x01\x00\x00")
+
+func _1589846400_add_datasync_installation_acksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1589846400_add_datasync_installation_acksUpSql,
+		"1589846400_add_datasync_installation_acks.up.sql",
+	)
+}
+
+func _1589846400_add_datasync_installation_acksUpSql() (*asset, error) {
+	bytes, err := _1589846400_add_datasync_installation_acksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1589846400_add_datasync_installation_acks.up.sql", size: 452, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x25, 0xf1, 0x6f, 0x64, 0x24, 0x2f, 0x47, 0x37, 0xd6, 0xd9, 0x1, 0x12, 0x1e, 0x93, 0x78, 0xfc, 0x5f, 0x41, 0x70, 0xfe, 0x74, 0xf0, 0x23, 0xdd, 0x1c, 0xf4, 0xe2, 0xd9, 0xe, 0x23, 0x74, 0x8d}}
+	return a, nil
+}
+
+var __1590600000_add_pinned_messagesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\xc8\xcc\xcb\x4b\x4d\x89\xcf\x4d\x2d\x2e\x4e\x4c\x4f\x2d\xb6\xe6\x02\x00\x2d\x11\x94\x80\x26\x00\x00\x00")
+
+func _1590600000_add_pinned_messagesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590600000_add_pinned_messagesDownSql,
+		"1590600000_add_pinned_messages.down.sql",
+	)
+}
+
+func _1590600000_add_pinned_messagesDownSql() (*asset, error) {
+	bytes, err := _1590600000_add_pinned_messagesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590600000_add_pinned_messages.down.sql", size: 38, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf4, 0x65, 0xb8, 0x21, 0x28, 0x8e, 0x2e, 0x5c, 0x5d, 0x1b, 0x97, 0x88, 0x94, 0xf2, 0x3b, 0x13, 0x82, 0x9d, 0xb6, 0xdb, 0xe2, 0x2c, 0x47, 0x0, 0x44, 0xaf, 0x3e, 0xc8, 0x70, 0xe9, 0x32, 0xa3}}
+	return a, nil
+}
+
+var __1590600000_add_pinned_messagesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x8e\xcd\x0a\x82\x40\x14\x46\xf7\x3e\xc5\xb7\x54\xf0\x0d\x5a\x5d\xa7\x2b\x0d\x4d\x33\x32\x5e\x23\x57\x62\x2a\x25\x95\x05\xb6\xe9\xed\xfb\x41\xa2\x85\xad\xcf\xf9\x3e\x8e\xf2\x4c\xc2\x10\x4a\x0c\x43\xa7\xb0\x4e\xc0\x3b\x9d\x4b\x8e\x5b\x3f\x0c\x5d\x5b\x5d\xba\x71\xac\x0f\xdd\x88\x30\x00\x9a\x63\x7d\xaf\xfa\x16\x5b\xf2\x6a\x45\xfe\xa3\xdb\xc2\x98\xf8\xc5\x26\xf1\x1f\x9e\xee\xf6\x8f\x59\xda\x9c\xaf\xcd\x09\xda\xca\xcc\x06\x89\x73\x86\xc9\x7e\x11\x96\x9c\x52\x61\x04\xe2\x0b\x7e\x7b\x99\xd7\x1b\xf2\x25\xd6\x5c\x22\x9c\x1a\xe3\x9f\xa0\x08\xce\x42\x39\x9b\x1a\xad\x04\x9e\x33\x43\x8a\x83\x68\x11\x3c\x01\x12\x62\x37\x0b\xff\x00\x00\x00")
+
+func _1590600000_add_pinned_messagesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590600000_add_pinned_messagesUpSql,
+		"1590600000_add_pinned_messages.up.sql",
+	)
+}
+
+func _1590600000_add_pinned_messagesUpSql() (*asset, error) {
+	bytes, err := _1590600000_add_pinned_messagesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590600000_add_pinned_messages.up.sql", size: 255, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0xf2, 0x72, 0xd5, 0xe6, 0xad, 0xdb, 0xda, 0x37, 0x4, 0x25, 0xa3, 0x4e, 0xea, 0xea, 0xa4, 0x41, 0xb9, 0x48, 0xde, 0xaf, 0xf1, 0xa4, 0x4c, 0x42, 0x2b, 0x7c, 0x1f, 0x4f, 0x79, 0xbf, 0xb2}}
+	return a, nil
+}
+
+var __1590700000_add_mentionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xc8\x4d\xcd\x2b\xc9\xcc\xcf\x8b\xcf\x4b\xcc\x4d\x2d\xb6\xe6\x72\xc1\x90\x01\x0a\x02\x00\x06\xb5\xd4\xfb\x2f\x00\x00\x00")
+
+func _1590700000_add_mentionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590700000_add_mentionsDownSql,
+		"1590700000_add_mentions.down.sql",
+	)
+}
+
+func _1590700000_add_mentionsDownSql() (*asset, error) {
+	bytes, err := _1590700000_add_mentionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590700000_add_mentions.down.sql", size: 47, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfd, 0x21, 0x21, 0xf9, 0xfa, 0x38, 0x5f, 0x92, 0x78, 0x2, 0x8a, 0x4a, 0x55, 0x9c, 0xa1, 0x7, 0xa0, 0x5d, 0x69, 0x1a, 0x4b, 0x15, 0xc7, 0xda, 0x79, 0xdd, 0x6e, 0xbf, 0x47, 0xd8, 0x62, 0x25}}
+	return a, nil
+}
+
+var __1590700000_add_mentionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x90\xc1\x0e\x82\x30\x10\x44\xef\x7c\xc5\x1e\x35\xe1\x0f\x3c\xd5\xba\xc4\xc6\x5a\x48\xa9\x06\x4e\x4d\x83\x44\x88\x02\x46\xea\xff\xdb\x0a\x1a\x48\x8c\xf1\xb6\xdd\x97\x9d\x99\x0e\x95\x48\x14\x82\x22\x6b\x8e\xc0\x22\x10\xb1\x02\xcc\x58\xaa\x52\x68\xca\xd6\xd6\x5d\xdb\xc3\x22\x00\xf7\xe8\x7b\x73\x2e\x75\x7d\x82\x23\x91\x74\x4b\x24\x24\x92\xed\x89\xcc\x61\x87\x39\xc4\x02\x68\x2c\x22\xce\xa8\x02\x89\x09\x27\x14\x43\x77\x55\x54\xc6\x4e\x4f\xbc\xba\x38\x70\xee\x99\x79\xd8\xaa\xbb\x7f\x45\xc5\xb5\x2b\x2e\xc0\x84\x9a\x6d\x6d\xed\x42\x58\xd3\xdc\x66\x24\x58\xae\x82\x80\x0e\xbf\x60\x62\x83\xd9\x27\xb7\x1e\xdd\xf5\x20\xe7\x22\xbe\xc9\x62\x24\xe1\xe0\x34\x51\xf8\xd1\x83\x6e\x8d\xf3\x7f\x95\xe1\xa7\x7f\x6b\xf0\xf9\x9e\x85\x7d\x11\xe5\x65\x01\x00\x00")
+
+func _1590700000_add_mentionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1590700000_add_mentionsUpSql,
+		"1590700000_add_mentions.up.sql",
+	)
+}
+
+func _1590700000_add_mentionsUpSql() (*asset, error) {
+	bytes, err := _1590700000_add_mentionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1590700000_add_mentions.up.sql", size: 357, mode: os.FileMode(0644), modTime: time.Unix(1792063676, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xff, 0x19, 0xf0, 0x5, 0x91, 0xe5, 0x9b, 0x1b, 0x3b, 0x84, 0x78, 0x9d, 0x1e, 0xa9, 0xb1, 0x24, 0x9d, 0xb4, 0x43, 0x97, 0xa6, 0x42, 0xc6, 0xe9, 0x19, 0x57, 0xd7, 0x2e, 0x4e, 0x75, 0x1, 0x20}}
+	return a, nil
+}
+
+var _docGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\x3b\x72\xc3\x30\x0c\x44\x7b\x9d\x62\xc7\x8d\x9b\x88\x6c\x52\xa5\x4b\x99\x3e\x17\x80\x29\x88\xc4\x58\x24\x14\x02\xf2\xe7\xf6\x19\x39\x2e\xdc\xa5\xdd\x99\xf7\xb0\xd8\x18\xf1\x5d\xc4\x30\xcb\xc2\x10\x43\xe3\xc4\x66\xd4\xef\x38\x71\xa2\xcd\x18\x87\x2c\x5e\xb6\x53\x48\x5a\xa3\x39\xf9\x66\xa3\xd4\x58\x25\x77\x72\x8e\x97\xf7\xc3\x10\x23\x12\xb5\xa3\xa3\x50\x9b\x16\x7e\xb8\x0c\xe6\xd4\x5d\x5a\xc6\x55\xbc\x80\xb0\x76\x9e\xe5\x16\xf0\xe9\x58\x98\xcc\xe1\x85\xfc\x68\xf0\xc2\x48\x64\xbc\x6b\x66\xed\xc8\x3a\x9e\xa4\x4d\xe4\x14\xf6\xe8\x6b\x7e\x49\xf6\x86\x89\x96\x85\x27\xcc\x5d\xeb\x83\x35\xaa\x8c\x49\x3a\x27\xd7\x7e\x7f\x03\x99\xb1\xa3\x51\x65\xdb\xf9\x42\x17\x46\xd3\xe7\x79\x50\x9b\xfe\xff\x08\x57\xed\x67\x03\x19\xf8\xb6\x72\x72\x9e\xc2\x30\xac\x94\xce\x94\x19\xf6\xb3\x88\xf3\x30\xc4\x98\xf5\x23\x73\xe3\x9d\x7a\xed\x38\xae\xe7\x8c\xbf\x7d\x44\x9b\x61\x54\x84\xf0\xd4\x8b\x36\x0b\x59\x11\x86\xdf\x01\x00\x9d\x60\x3d\x88\x79\x01\x00\x00")
 
 func docGoBytes() ([]byte, error) {
 	return bindataRead(
@@ -168,7 +756,7 @@ func docGo() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "doc.go", size: 377, mode: os.FileMode(0644), modTime: time.Unix(1574354941, 0)}
+	info := bindataFileInfo{name: "doc.go", size: 377, mode: os.FileMode(0664), modTime: time.Unix(1581604220, 0)}
 	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xef, 0xaf, 0xdf, 0xcf, 0x65, 0xae, 0x19, 0xfc, 0x9d, 0x29, 0xc1, 0x91, 0xaf, 0xb5, 0xd5, 0xb1, 0x56, 0xf3, 0xee, 0xa8, 0xba, 0x13, 0x65, 0xdb, 0xab, 0xcf, 0x4e, 0xac, 0x92, 0xe9, 0x60, 0xf1}}
 	return a, nil
 }
@@ -272,6 +860,62 @@ var _bindata = map[string]func() (*asset, error){
 
 	"000002_add_last_ens_clock_value.up.sql": _000002_add_last_ens_clock_valueUpSql,
 
+	"000003_add_contact_requests.down.sql": _000003_add_contact_requestsDownSql,
+
+	"000003_add_contact_requests.up.sql": _000003_add_contact_requestsUpSql,
+
+	"000004_add_public_chats_directory.down.sql": _000004_add_public_chats_directoryDownSql,
+
+	"000004_add_public_chats_directory.up.sql": _000004_add_public_chats_directoryUpSql,
+
+	"000005_add_reactions.down.sql": _000005_add_reactionsDownSql,
+
+	"000005_add_reactions.up.sql": _000005_add_reactionsUpSql,
+
+	"1589365189_add_outbox.down.sql": _1589365189_add_outboxDownSql,
+
+	"1589365189_add_outbox.up.sql": _1589365189_add_outboxUpSql,
+
+	"1589460000_add_community_channel.down.sql": _1589460000_add_community_channelDownSql,
+
+	"1589460000_add_community_channel.up.sql": _1589460000_add_community_channelUpSql,
+
+	"1589550000_add_message_segments.down.sql": _1589550000_add_message_segmentsDownSql,
+
+	"1589550000_add_message_segments.up.sql": _1589550000_add_message_segmentsUpSql,
+
+	"1589640000_add_user_messages_fts.down.sql": _1589640000_add_user_messages_ftsDownSql,
+
+	"1589640000_add_user_messages_fts.up.sql": _1589640000_add_user_messages_ftsUpSql,
+
+	"1589730000_add_push_notifications.down.sql": _1589730000_add_push_notificationsDownSql,
+
+	"1589730000_add_push_notifications.up.sql": _1589730000_add_push_notificationsUpSql,
+
+	"1589740000_add_push_notification_server.down.sql": _1589740000_add_push_notification_serverDownSql,
+
+	"1589740000_add_push_notification_server.up.sql": _1589740000_add_push_notification_serverUpSql,
+
+	"1589750000_add_chat_identities.down.sql": _1589750000_add_chat_identitiesDownSql,
+
+	"1589750000_add_chat_identities.up.sql": _1589750000_add_chat_identitiesUpSql,
+
+	"1589760000_add_block_and_mute_lists.down.sql": _1589760000_add_block_and_mute_listsDownSql,
+
+	"1589760000_add_block_and_mute_lists.up.sql": _1589760000_add_block_and_mute_listsUpSql,
+
+	"1589846400_add_datasync_installation_acks.down.sql": _1589846400_add_datasync_installation_acksDownSql,
+
+	"1589846400_add_datasync_installation_acks.up.sql": _1589846400_add_datasync_installation_acksUpSql,
+
+	"1590600000_add_pinned_messages.down.sql": _1590600000_add_pinned_messagesDownSql,
+
+	"1590600000_add_pinned_messages.up.sql": _1590600000_add_pinned_messagesUpSql,
+
+	"1590700000_add_mentions.down.sql": _1590700000_add_mentionsDownSql,
+
+	"1590700000_add_mentions.up.sql": _1590700000_add_mentionsUpSql,
+
 	"doc.go": docGo,
 }
 
@@ -279,11 +923,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -316,11 +962,39 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"000001_init.down.db.sql":                  &bintree{_000001_initDownDbSql, map[string]*bintree{}},
-	"000001_init.up.db.sql":                    &bintree{_000001_initUpDbSql, map[string]*bintree{}},
-	"000002_add_last_ens_clock_value.down.sql": &bintree{_000002_add_last_ens_clock_valueDownSql, map[string]*bintree{}},
-	"000002_add_last_ens_clock_value.up.sql":   &bintree{_000002_add_last_ens_clock_valueUpSql, map[string]*bintree{}},
-	"doc.go":                                   &bintree{docGo, map[string]*bintree{}},
+	"000001_init.down.db.sql":                            &bintree{_000001_initDownDbSql, map[string]*bintree{}},
+	"000001_init.up.db.sql":                              &bintree{_000001_initUpDbSql, map[string]*bintree{}},
+	"000002_add_last_ens_clock_value.down.sql":           &bintree{_000002_add_last_ens_clock_valueDownSql, map[string]*bintree{}},
+	"000002_add_last_ens_clock_value.up.sql":             &bintree{_000002_add_last_ens_clock_valueUpSql, map[string]*bintree{}},
+	"000003_add_contact_requests.down.sql":               &bintree{_000003_add_contact_requestsDownSql, map[string]*bintree{}},
+	"000003_add_contact_requests.up.sql":                 &bintree{_000003_add_contact_requestsUpSql, map[string]*bintree{}},
+	"000004_add_public_chats_directory.down.sql":         &bintree{_000004_add_public_chats_directoryDownSql, map[string]*bintree{}},
+	"000004_add_public_chats_directory.up.sql":           &bintree{_000004_add_public_chats_directoryUpSql, map[string]*bintree{}},
+	"000005_add_reactions.down.sql":                      &bintree{_000005_add_reactionsDownSql, map[string]*bintree{}},
+	"000005_add_reactions.up.sql":                        &bintree{_000005_add_reactionsUpSql, map[string]*bintree{}},
+	"1589365189_add_outbox.down.sql":                     &bintree{_1589365189_add_outboxDownSql, map[string]*bintree{}},
+	"1589365189_add_outbox.up.sql":                       &bintree{_1589365189_add_outboxUpSql, map[string]*bintree{}},
+	"1589460000_add_community_channel.down.sql":          &bintree{_1589460000_add_community_channelDownSql, map[string]*bintree{}},
+	"1589460000_add_community_channel.up.sql":            &bintree{_1589460000_add_community_channelUpSql, map[string]*bintree{}},
+	"1589550000_add_message_segments.down.sql":           &bintree{_1589550000_add_message_segmentsDownSql, map[string]*bintree{}},
+	"1589550000_add_message_segments.up.sql":             &bintree{_1589550000_add_message_segmentsUpSql, map[string]*bintree{}},
+	"1589640000_add_user_messages_fts.down.sql":          &bintree{_1589640000_add_user_messages_ftsDownSql, map[string]*bintree{}},
+	"1589640000_add_user_messages_fts.up.sql":            &bintree{_1589640000_add_user_messages_ftsUpSql, map[string]*bintree{}},
+	"1589730000_add_push_notifications.down.sql":         &bintree{_1589730000_add_push_notificationsDownSql, map[string]*bintree{}},
+	"1589730000_add_push_notifications.up.sql":           &bintree{_1589730000_add_push_notificationsUpSql, map[string]*bintree{}},
+	"1589740000_add_push_notification_server.down.sql":   &bintree{_1589740000_add_push_notification_serverDownSql, map[string]*bintree{}},
+	"1589740000_add_push_notification_server.up.sql":     &bintree{_1589740000_add_push_notification_serverUpSql, map[string]*bintree{}},
+	"1589750000_add_chat_identities.down.sql":            &bintree{_1589750000_add_chat_identitiesDownSql, map[string]*bintree{}},
+	"1589750000_add_chat_identities.up.sql":              &bintree{_1589750000_add_chat_identitiesUpSql, map[string]*bintree{}},
+	"1589760000_add_block_and_mute_lists.down.sql":       &bintree{_1589760000_add_block_and_mute_listsDownSql, map[string]*bintree{}},
+	"1589760000_add_block_and_mute_lists.up.sql":         &bintree{_1589760000_add_block_and_mute_listsUpSql, map[string]*bintree{}},
+	"1589846400_add_datasync_installation_acks.down.sql": &bintree{_1589846400_add_datasync_installation_acksDownSql, map[string]*bintree{}},
+	"1589846400_add_datasync_installation_acks.up.sql":   &bintree{_1589846400_add_datasync_installation_acksUpSql, map[string]*bintree{}},
+	"1590600000_add_pinned_messages.down.sql":            &bintree{_1590600000_add_pinned_messagesDownSql, map[string]*bintree{}},
+	"1590600000_add_pinned_messages.up.sql":              &bintree{_1590600000_add_pinned_messagesUpSql, map[string]*bintree{}},
+	"1590700000_add_mentions.down.sql":                   &bintree{_1590700000_add_mentionsDownSql, map[string]*bintree{}},
+	"1590700000_add_mentions.up.sql":                     &bintree{_1590700000_add_mentionsUpSql, map[string]*bintree{}},
+	"doc.go":                                             &bintree{docGo, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS contact_requests (
+  id VARCHAR PRIMARY KEY ON CONFLICT REPLACE,
+  message VARCHAR NOT NULL DEFAULT "",
+  clock INT NOT NULL,
+  timestamp INT NOT NULL,
+  state INT NOT NULL
+);
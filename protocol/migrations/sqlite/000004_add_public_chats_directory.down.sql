@@ -0,0 +1 @@
+DROP TABLE public_chats_directory;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS public_chats_directory (
+  announcer VARCHAR NOT NULL,
+  chat_id VARCHAR NOT NULL,
+  clock INT NOT NULL,
+  timestamp INT NOT NULL,
+  PRIMARY KEY (announcer, chat_id) ON CONFLICT REPLACE
+);
+
+CREATE INDEX public_chats_directory_timestamp ON public_chats_directory(timestamp);
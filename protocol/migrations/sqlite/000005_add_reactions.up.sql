@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS reactions (
+  chat_id VARCHAR NOT NULL,
+  message_id VARCHAR NOT NULL,
+  author VARCHAR NOT NULL,
+  reaction VARCHAR NOT NULL,
+  clock INT NOT NULL,
+  retracted BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (message_id, author, reaction) ON CONFLICT REPLACE
+);
+
+CREATE INDEX reactions_chat_id_message_id ON reactions(chat_id, message_id);
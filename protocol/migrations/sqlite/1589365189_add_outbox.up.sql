@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+  id BLOB PRIMARY KEY ON CONFLICT REPLACE,
+  identifiers BLOB NOT NULL,
+  topic BLOB NOT NULL,
+  payload BLOB NOT NULL,
+  public_key BLOB,
+  sym_key BLOB,
+  ttl INT NOT NULL,
+  pow_target REAL NOT NULL,
+  pow_time INT NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt INT NOT NULL,
+  created_at INT NOT NULL,
+  last_error TEXT
+) WITHOUT ROWID;
+
+CREATE INDEX outbox_messages_next_attempt ON outbox_messages(next_attempt);
@@ -0,0 +1 @@
+ALTER TABLE chats ADD COLUMN community_channel BLOB;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS message_segments (
+  hash BLOB NOT NULL,
+  sig_pub_key BLOB NOT NULL,
+  segment_index INT NOT NULL,
+  segments_count INT NOT NULL,
+  payload BLOB NOT NULL,
+  timestamp INT NOT NULL,
+  PRIMARY KEY (hash, sig_pub_key, segment_index) ON CONFLICT REPLACE
+) WITHOUT ROWID;
+
+CREATE INDEX message_segments_timestamp ON message_segments(timestamp);
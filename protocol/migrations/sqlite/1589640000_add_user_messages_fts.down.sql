@@ -0,0 +1,5 @@
+DROP TRIGGER user_messages_fts_after_delete;
+DROP TRIGGER user_messages_fts_after_update;
+DROP TRIGGER user_messages_fts_after_insert;
+DROP TRIGGER user_messages_fts_before_insert;
+DROP TABLE user_messages_fts;
@@ -0,0 +1,21 @@
+CREATE VIRTUAL TABLE user_messages_fts USING fts4(text, tokenize=unicode61);
+
+INSERT INTO user_messages_fts(docid, text) SELECT rowid, text FROM user_messages;
+
+-- A message with the same ID replaces the stored one, the index entry of the replaced row is removed
+-- before the insert because delete triggers are not fired for rows removed by the conflict resolution.
+CREATE TRIGGER user_messages_fts_before_insert BEFORE INSERT ON user_messages BEGIN
+  DELETE FROM user_messages_fts WHERE docid = (SELECT rowid FROM user_messages WHERE id = new.id);
+END;
+
+CREATE TRIGGER user_messages_fts_after_insert AFTER INSERT ON user_messages BEGIN
+  INSERT INTO user_messages_fts(docid, text) VALUES (new.rowid, new.text);
+END;
+
+CREATE TRIGGER user_messages_fts_after_update AFTER UPDATE OF text ON user_messages BEGIN
+  UPDATE user_messages_fts SET text = new.text WHERE docid = new.rowid;
+END;
+
+CREATE TRIGGER user_messages_fts_after_delete AFTER DELETE ON user_messages BEGIN
+  DELETE FROM user_messages_fts WHERE docid = old.rowid;
+END;
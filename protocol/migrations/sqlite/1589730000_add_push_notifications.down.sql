@@ -0,0 +1,2 @@
+DROP TABLE push_notification_infos;
+DROP TABLE push_notification_registrations;
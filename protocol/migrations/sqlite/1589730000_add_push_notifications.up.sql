@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS push_notification_registrations (
+  installation_id VARCHAR NOT NULL PRIMARY KEY ON CONFLICT REPLACE,
+  server_public_key BLOB NOT NULL,
+  token_type VARCHAR NOT NULL,
+  device_token VARCHAR NOT NULL,
+  access_token VARCHAR NOT NULL,
+  version INT NOT NULL,
+  request_id VARCHAR NOT NULL,
+  status VARCHAR NOT NULL,
+  error VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS push_notification_infos (
+  public_key VARCHAR NOT NULL,
+  installation_id VARCHAR NOT NULL,
+  server_public_key BLOB NOT NULL,
+  access_token VARCHAR NOT NULL,
+  version INT NOT NULL,
+  PRIMARY KEY (public_key, installation_id) ON CONFLICT REPLACE
+);
@@ -0,0 +1 @@
+DROP TABLE push_notification_server_registrations;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS push_notification_server_registrations (
+  public_key BLOB NOT NULL,
+  installation_id VARCHAR NOT NULL,
+  version INT NOT NULL,
+  registration BLOB NOT NULL,
+  PRIMARY KEY (public_key, installation_id) ON CONFLICT REPLACE
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_notification_server_registrations_installation_id ON push_notification_server_registrations(installation_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS chat_identities (
+  public_key VARCHAR PRIMARY KEY ON CONFLICT REPLACE,
+  clock INT NOT NULL,
+  display_name VARCHAR NOT NULL DEFAULT "",
+  image_hash BLOB,
+  received_at INT NOT NULL DEFAULT 0,
+  published_at INT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,2 @@
+DROP TABLE blocked_public_keys;
+DROP TABLE muted_chats;
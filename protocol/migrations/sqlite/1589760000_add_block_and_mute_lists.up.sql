@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS blocked_public_keys (
+  public_key VARCHAR PRIMARY KEY ON CONFLICT REPLACE,
+  blocked_at INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS muted_chats (
+  chat_id VARCHAR PRIMARY KEY ON CONFLICT REPLACE,
+  muted_at INT NOT NULL
+);
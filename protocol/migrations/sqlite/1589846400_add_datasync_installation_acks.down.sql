@@ -0,0 +1,2 @@
+DROP INDEX idx_datasync_installation_acks_unacked;
+DROP TABLE datasync_installation_acks;
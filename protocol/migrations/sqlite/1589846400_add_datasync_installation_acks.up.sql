@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS datasync_installation_acks (
+  message_id BLOB NOT NULL,
+  public_key VARCHAR NOT NULL,
+  installation_id VARCHAR NOT NULL,
+  send_count INT NOT NULL DEFAULT 0,
+  next_send_at INT NOT NULL,
+  acked_at INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (message_id, public_key, installation_id) ON CONFLICT IGNORE
+);
+
+CREATE INDEX IF NOT EXISTS idx_datasync_installation_acks_unacked ON datasync_installation_acks(acked_at, next_send_at);
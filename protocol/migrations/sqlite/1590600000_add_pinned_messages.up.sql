@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS pinned_messages (
+  chat_id VARCHAR NOT NULL,
+  message_id VARCHAR NOT NULL,
+  pinned_by VARCHAR NOT NULL,
+  clock INT NOT NULL,
+  pinned BOOLEAN NOT NULL DEFAULT TRUE,
+  PRIMARY KEY (chat_id, message_id) ON CONFLICT REPLACE
+);
@@ -0,0 +1,2 @@
+DROP TABLE mention_names;
+DROP TABLE mentions;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS mentions (
+  message_id VARCHAR PRIMARY KEY ON CONFLICT REPLACE,
+  chat_id VARCHAR NOT NULL,
+  author VARCHAR NOT NULL,
+  clock INT NOT NULL,
+  timestamp INT NOT NULL
+);
+
+CREATE INDEX mentions_chat_id_clock ON mentions(chat_id, clock);
+
+CREATE TABLE IF NOT EXISTS mention_names (
+  name VARCHAR PRIMARY KEY ON CONFLICT REPLACE
+);
@@ -0,0 +1,282 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+const (
+	defaultOutboxMaxAttempts = 10
+	defaultOutboxMaxAge      = 24 * time.Hour
+
+	outboxCheckPeriod     = time.Second
+	outboxInitialBackoff  = 5 * time.Second
+	outboxMaxBackoff      = 10 * time.Minute
+	outboxDispatchTimeout = 10 * time.Second
+)
+
+// ErrOutboxMessageExpired is reported when a postponed message was not delivered
+// within the configured number of attempts or age.
+var ErrOutboxMessageExpired = errors.New("postponed message was not delivered")
+
+// OutboxEventsHandler is notified when a postponed message is finally delivered
+// or dropped.
+type OutboxEventsHandler interface {
+	OutboxMessageSent(identifiers [][]byte)
+	OutboxMessageFailed(identifiers [][]byte, err error)
+}
+
+// OutboxConfig configures the persistent outbox. Messages that fail to be posted
+// or are not delivered by the envelopes monitor are stored and retried
+// with an exponential backoff while there are connected peers.
+type OutboxConfig struct {
+	// MaxAttempts is a number of retries before a message is dropped.
+	MaxAttempts int
+	// MaxAge is how long a message is retried since it was postponed.
+	MaxAge time.Duration
+	// IsConnected returns true if messages can be sent. If not set,
+	// the node is considered connected.
+	IsConnected func() bool
+	Handler     OutboxEventsHandler
+}
+
+type outboxEntry struct {
+	message     *transport.OutboxMessage
+	attempts    int
+	nextAttempt time.Time
+	createdAt   time.Time
+	lastError   string
+}
+
+// outbox retries postponed messages. It wraps envelope events handler
+// to learn when a retried message is delivered.
+type outbox struct {
+	mu          sync.Mutex
+	persistence *sqlitePersistence
+	transport   transport.Transport
+	config      OutboxConfig
+	next        transport.EnvelopeEventsHandler
+	// inflight are messages posted by the outbox that are tracked by the envelopes monitor
+	inflight map[types.Hash]struct{}
+	now      func() time.Time
+
+	period time.Duration
+	cancel chan struct{}
+	wg     sync.WaitGroup
+
+	logger *zap.Logger
+}
+
+func newOutbox(persistence *sqlitePersistence, config OutboxConfig, next transport.EnvelopeEventsHandler, logger *zap.Logger) *outbox {
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = defaultOutboxMaxAge
+	}
+	return &outbox{
+		persistence: persistence,
+		config:      config,
+		next:        next,
+		inflight:    make(map[types.Hash]struct{}),
+		now:         time.Now,
+		period:      outboxCheckPeriod,
+		logger:      logger.With(zap.Namespace("Outbox")),
+	}
+}
+
+// Start starts a loop that retries due messages.
+func (o *outbox) Start() {
+	o.cancel = make(chan struct{})
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		t := time.NewTicker(o.period)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				o.retry()
+			case <-o.cancel:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the loop. Postponed messages are retried after the next start.
+func (o *outbox) Stop() {
+	if o.cancel == nil {
+		return
+	}
+	close(o.cancel)
+	o.wg.Wait()
+	o.cancel = nil
+}
+
+func backoff(attempts int) time.Duration {
+	delay := outboxInitialBackoff
+	for i := 0; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxBackoff {
+		delay = outboxMaxBackoff
+	}
+	return delay
+}
+
+// Enqueue stores a message that was not delivered. A message that was already
+// retried too many times is dropped and reported as failed.
+func (o *outbox) Enqueue(message *transport.OutboxMessage, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := message.ID()
+	delete(o.inflight, id)
+
+	now := o.now()
+	entry, err := o.persistence.OutboxEntry(id)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &outboxEntry{message: message, createdAt: now}
+	}
+	if cause != nil {
+		entry.lastError = cause.Error()
+	}
+
+	if entry.attempts >= o.config.MaxAttempts || now.Sub(entry.createdAt) > o.config.MaxAge {
+		o.logger.Debug("dropping postponed message", zap.Int("attempts", entry.attempts), zap.String("error", entry.lastError))
+		if err := o.persistence.DeleteOutboxEntry(id); err != nil {
+			return err
+		}
+		if o.config.Handler != nil {
+			o.config.Handler.OutboxMessageFailed(message.Identifiers, ErrOutboxMessageExpired)
+		}
+		return nil
+	}
+
+	entry.nextAttempt = now.Add(backoff(entry.attempts))
+	o.logger.Debug("message postponed", zap.Int("attempts", entry.attempts), zap.Time("next", entry.nextAttempt))
+	return o.persistence.SaveOutboxEntry(entry)
+}
+
+// retry posts due messages if there are peers to send them to.
+func (o *outbox) retry() {
+	if o.config.IsConnected != nil && !o.config.IsConnected() {
+		return
+	}
+
+	// The envelopes monitor calls the outbox with its lock held,
+	// so the transport is not called with the outbox lock.
+	due, err := o.dueEntries()
+	if err != nil {
+		o.logger.Error("failed to load postponed messages", zap.Error(err))
+		return
+	}
+
+	for _, entry := range due {
+		o.dispatch(entry)
+	}
+}
+
+func (o *outbox) dueEntries() ([]*outboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.now()
+	entries, err := o.persistence.DueOutboxEntries(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*outboxEntry
+	for _, entry := range entries {
+		id := entry.message.ID()
+		if _, exist := o.inflight[id]; exist {
+			continue
+		}
+		entry.attempts++
+		// postpone the next attempt in case the delivery is not reported, e.g. after a restart
+		entry.nextAttempt = now.Add(backoff(entry.attempts))
+		if err := o.persistence.SaveOutboxEntry(entry); err != nil {
+			return nil, err
+		}
+		o.inflight[id] = struct{}{}
+		due = append(due, entry)
+	}
+	return due, nil
+}
+
+func (o *outbox) dispatch(entry *outboxEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxDispatchTimeout)
+	defer cancel()
+
+	message := entry.message
+	newMessage := message.NewMessage()
+	hash, err := o.transport.SendRaw(ctx, newMessage, message.SymKey)
+	if err != nil {
+		o.logger.Debug("failed to retry postponed message", zap.Int("attempt", entry.attempts), zap.Error(err))
+		if err := o.Enqueue(message, err); err != nil {
+			o.logger.Error("failed to postpone message", zap.Error(err))
+		}
+		return
+	}
+	o.transport.Track(message.Identifiers, hash, newMessage)
+}
+
+// sent removes a message that was delivered.
+func (o *outbox) sent(identifiers [][]byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	message := transport.OutboxMessage{Identifiers: identifiers}
+	id := message.ID()
+	if _, exist := o.inflight[id]; !exist {
+		return
+	}
+	delete(o.inflight, id)
+	if err := o.persistence.DeleteOutboxEntry(id); err != nil {
+		o.logger.Error("failed to remove delivered message", zap.Error(err))
+	}
+	if o.config.Handler != nil {
+		o.config.Handler.OutboxMessageSent(identifiers)
+	}
+}
+
+// EnvelopeSent implements transport.EnvelopeEventsHandler.
+func (o *outbox) EnvelopeSent(identifiers [][]byte) {
+	o.sent(identifiers)
+	if o.next != nil {
+		o.next.EnvelopeSent(identifiers)
+	}
+}
+
+// EnvelopeExpired implements transport.EnvelopeEventsHandler.
+func (o *outbox) EnvelopeExpired(identifiers [][]byte, err error) {
+	if o.next != nil {
+		o.next.EnvelopeExpired(identifiers, err)
+	}
+}
+
+// MailServerRequestCompleted implements transport.EnvelopeEventsHandler.
+func (o *outbox) MailServerRequestCompleted(requestID types.Hash, lastEnvelopeHash types.Hash, cursor []byte, err error) {
+	if o.next != nil {
+		o.next.MailServerRequestCompleted(requestID, lastEnvelopeHash, cursor, err)
+	}
+}
+
+// MailServerRequestExpired implements transport.EnvelopeEventsHandler.
+func (o *outbox) MailServerRequestExpired(hash types.Hash) {
+	if o.next != nil {
+		o.next.MailServerRequestExpired(hash)
+	}
+}
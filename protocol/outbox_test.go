@@ -0,0 +1,174 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/transport"
+)
+
+type outboxTestTransport struct {
+	transport.Transport
+
+	sendErr error
+	sent    []*types.NewMessage
+	symKeys [][]byte
+	tracked [][][]byte
+}
+
+func (t *outboxTestTransport) SendRaw(ctx context.Context, newMessage *types.NewMessage, symKey []byte) ([]byte, error) {
+	if t.sendErr != nil {
+		return nil, t.sendErr
+	}
+	t.sent = append(t.sent, newMessage)
+	t.symKeys = append(t.symKeys, symKey)
+	return []byte{byte(len(t.sent))}, nil
+}
+
+func (t *outboxTestTransport) Track(identifiers [][]byte, hash []byte, newMessage *types.NewMessage) {
+	t.tracked = append(t.tracked, identifiers)
+}
+
+type outboxTestHandler struct {
+	sent   [][][]byte
+	failed [][][]byte
+}
+
+func (h *outboxTestHandler) OutboxMessageSent(identifiers [][]byte) {
+	h.sent = append(h.sent, identifiers)
+}
+
+func (h *outboxTestHandler) OutboxMessageFailed(identifiers [][]byte, err error) {
+	h.failed = append(h.failed, identifiers)
+}
+
+func setupTestOutbox(t *testing.T, config OutboxConfig) (*outbox, *outboxTestTransport, *outboxTestHandler, *time.Time) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+
+	handler := &outboxTestHandler{}
+	config.Handler = handler
+	ob := newOutbox(&sqlitePersistence{db: db}, config, nil, zap.NewNop())
+	transp := &outboxTestTransport{}
+	ob.transport = transp
+	now := time.Unix(1000, 0)
+	ob.now = func() time.Time { return now }
+	return ob, transp, handler, &now
+}
+
+func testOutboxMessage() *transport.OutboxMessage {
+	return &transport.OutboxMessage{
+		Identifiers: [][]byte{{1}, {2}},
+		Topic:       types.TopicType{1, 2, 3, 4},
+		Payload:     []byte("payload"),
+		SymKey:      []byte("key"),
+		TTL:         10,
+		PowTarget:   0.002,
+		PowTime:     1,
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	require.Equal(t, outboxInitialBackoff, backoff(0))
+	require.Equal(t, 4*outboxInitialBackoff, backoff(2))
+	require.Equal(t, outboxMaxBackoff, backoff(100))
+}
+
+func TestOutboxRetriesUntilSent(t *testing.T) {
+	connected := false
+	ob, transp, handler, now := setupTestOutbox(t, OutboxConfig{IsConnected: func() bool { return connected }})
+	message := testOutboxMessage()
+
+	require.NoError(t, ob.Enqueue(message, errors.New("no peers")))
+	entry, err := ob.persistence.OutboxEntry(message.ID())
+	require.NoError(t, err)
+	require.Equal(t, message, entry.message)
+	require.Equal(t, "no peers", entry.lastError)
+	require.Equal(t, now.Add(outboxInitialBackoff), entry.nextAttempt)
+
+	// not due yet
+	ob.retry()
+	*now = now.Add(outboxInitialBackoff)
+	// not connected
+	ob.retry()
+	require.Empty(t, transp.sent)
+
+	connected = true
+	ob.retry()
+	require.Len(t, transp.sent, 1)
+	require.Equal(t, message.Payload, transp.sent[0].Payload)
+	require.Equal(t, message.Topic, transp.sent[0].Topic)
+	require.Equal(t, message.SymKey, transp.symKeys[0])
+	require.Equal(t, [][][]byte{message.Identifiers}, transp.tracked)
+
+	// in flight messages are not retried
+	*now = now.Add(time.Hour)
+	ob.retry()
+	require.Len(t, transp.sent, 1)
+
+	// envelopes of other messages are ignored
+	ob.EnvelopeSent([][]byte{{3}})
+	require.Empty(t, handler.sent)
+
+	ob.EnvelopeSent(message.Identifiers)
+	require.Equal(t, [][][]byte{message.Identifiers}, handler.sent)
+	entry, err = ob.persistence.OutboxEntry(message.ID())
+	require.NoError(t, err)
+	require.Nil(t, entry)
+}
+
+func TestOutboxDropsAfterMaxAttempts(t *testing.T) {
+	ob, transp, handler, now := setupTestOutbox(t, OutboxConfig{MaxAttempts: 2})
+	transp.sendErr = errors.New("pow too low")
+	message := testOutboxMessage()
+
+	require.NoError(t, ob.Enqueue(message, errors.New("expired")))
+	for i := 1; i <= 2; i++ {
+		*now = now.Add(outboxMaxBackoff)
+		ob.retry()
+		entry, err := ob.persistence.OutboxEntry(message.ID())
+		require.NoError(t, err)
+		if i < 2 {
+			require.Equal(t, i, entry.attempts)
+			require.Equal(t, "pow too low", entry.lastError)
+		} else {
+			require.Nil(t, entry)
+		}
+	}
+	require.Equal(t, [][][]byte{message.Identifiers}, handler.failed)
+}
+
+func TestOutboxDropsAfterMaxAge(t *testing.T) {
+	ob, _, handler, now := setupTestOutbox(t, OutboxConfig{MaxAge: time.Minute})
+	message := testOutboxMessage()
+
+	require.NoError(t, ob.Enqueue(message, nil))
+	*now = now.Add(2 * time.Minute)
+	require.NoError(t, ob.Enqueue(message, nil))
+	require.Equal(t, [][][]byte{message.Identifiers}, handler.failed)
+}
+
+func TestNewOutboxMessage(t *testing.T) {
+	getSymKey := func(id string) ([]byte, error) {
+		require.Equal(t, "key-id", id)
+		return []byte("key"), nil
+	}
+
+	_, err := transport.NewOutboxMessage(nil, &types.NewMessage{SymKeyID: "key-id"}, getSymKey)
+	require.Equal(t, transport.ErrMessageNotPostponable, err)
+
+	message, err := transport.NewOutboxMessage([][]byte{{1}}, &types.NewMessage{Topic: types.TopicType{1}, SymKeyID: "key-id", Payload: []byte{2}}, getSymKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), message.SymKey)
+	require.Nil(t, message.NewMessage().PublicKey)
+
+	message, err = transport.NewOutboxMessage([][]byte{{1}}, &types.NewMessage{Topic: types.TopicType{1}, PublicKey: []byte{3}}, getSymKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, message.NewMessage().PublicKey)
+}
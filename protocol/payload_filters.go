@@ -0,0 +1,121 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+// Types of payloads that can be skipped by payload filters.
+const (
+	PayloadTypeChatIndicator = "chat-indicator"
+	PayloadTypeReaction      = "reaction"
+	PayloadTypeEmoji         = "emoji"
+)
+
+// PayloadFilterAllChats is a chat ID of a payload filter applied to messages of all chats.
+const PayloadFilterAllChats = "*"
+
+var payloadTypes = map[string]bool{
+	PayloadTypeChatIndicator: true,
+	PayloadTypeReaction:      true,
+	PayloadTypeEmoji:         true,
+}
+
+// ErrUnknownPayloadType is returned for types other than chat-indicator, reaction and emoji.
+var ErrUnknownPayloadType = errors.New("unknown payload type")
+
+// PayloadFilter lists types of payloads that are dropped before they are handled.
+type PayloadFilter struct {
+	// ChatID is an ID of the transport filter, the name of a public chat or PayloadFilterAllChats.
+	ChatID string   `json:"chatId"`
+	Skip   []string `json:"skip"`
+}
+
+// payloadFilters drops messages of skipped types as soon as they are decoded,
+// so they don't reach the client. Filters are not persisted.
+type payloadFilters struct {
+	mu    sync.RWMutex
+	chats map[string]map[string]bool
+}
+
+func newPayloadFilters() *payloadFilters {
+	return &payloadFilters{chats: make(map[string]map[string]bool)}
+}
+
+// Set replaces skipped types of the chat. An empty list removes the filter.
+func (f *payloadFilters) Set(chatID string, skip []string) error {
+	types := make(map[string]bool, len(skip))
+	for _, t := range skip {
+		if !payloadTypes[t] {
+			return errors.Wrap(ErrUnknownPayloadType, t)
+		}
+		types[t] = true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(types) == 0 {
+		delete(f.chats, chatID)
+		return nil
+	}
+	f.chats[chatID] = types
+	return nil
+}
+
+// Filters returns all filters ordered by chat ID.
+func (f *payloadFilters) Filters() []PayloadFilter {
+	f.mu.RLock()
+	rst := make([]PayloadFilter, 0, len(f.chats))
+	for chatID, types := range f.chats {
+		filter := PayloadFilter{ChatID: chatID}
+		for t := range types {
+			filter.Skip = append(filter.Skip, t)
+		}
+		sort.Strings(filter.Skip)
+		rst = append(rst, filter)
+	}
+	f.mu.RUnlock()
+	sort.Slice(rst, func(i, j int) bool { return rst[i].ChatID < rst[j].ChatID })
+	return rst
+}
+
+// skip returns true if the message received by the filter of the chat should be dropped.
+func (f *payloadFilters) skip(chatID string, msg *v1protocol.StatusMessage) bool {
+	payloadType := statusMessagePayloadType(msg)
+	if payloadType == "" {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.chats[chatID][payloadType] || f.chats[PayloadFilterAllChats][payloadType]
+}
+
+// statusMessagePayloadType returns a type used by payload filters, empty for types that can't be skipped.
+func statusMessagePayloadType(msg *v1protocol.StatusMessage) string {
+	switch parsed := msg.ParsedMessage.(type) {
+	case protobuf.ChatIndicator:
+		return PayloadTypeChatIndicator
+	case protobuf.Reaction:
+		return PayloadTypeReaction
+	case protobuf.ChatMessage:
+		if parsed.ContentType == protobuf.ChatMessage_EMOJI {
+			return PayloadTypeEmoji
+		}
+	}
+	return ""
+}
+
+// SetPayloadFilter sets types of payloads received by the chat filter that are dropped
+// before they are handled. An empty list removes the filter.
+func (m *Messenger) SetPayloadFilter(chatID string, skip []string) error {
+	return m.payloadFilters.Set(chatID, skip)
+}
+
+// PayloadFilters returns all payload filters.
+func (m *Messenger) PayloadFilters() []PayloadFilter {
+	return m.payloadFilters.Filters()
+}
@@ -0,0 +1,42 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+func TestPayloadFiltersSkip(t *testing.T) {
+	filters := newPayloadFilters()
+	reaction := &v1protocol.StatusMessage{ParsedMessage: protobuf.Reaction{}}
+	indicator := &v1protocol.StatusMessage{ParsedMessage: protobuf.ChatIndicator{}}
+	emoji := &v1protocol.StatusMessage{ParsedMessage: protobuf.ChatMessage{ContentType: protobuf.ChatMessage_EMOJI}}
+	text := &v1protocol.StatusMessage{ParsedMessage: protobuf.ChatMessage{ContentType: protobuf.ChatMessage_TEXT_PLAIN}}
+
+	require.False(t, filters.skip("status", reaction))
+
+	require.NoError(t, filters.Set("status", []string{PayloadTypeReaction, PayloadTypeEmoji}))
+	require.True(t, filters.skip("status", reaction))
+	require.True(t, filters.skip("status", emoji))
+	require.False(t, filters.skip("status", text))
+	require.False(t, filters.skip("status", indicator))
+	require.False(t, filters.skip("other", reaction))
+
+	require.NoError(t, filters.Set(PayloadFilterAllChats, []string{PayloadTypeChatIndicator}))
+	require.True(t, filters.skip("other", indicator))
+	require.Equal(t, []PayloadFilter{
+		{ChatID: PayloadFilterAllChats, Skip: []string{PayloadTypeChatIndicator}},
+		{ChatID: "status", Skip: []string{PayloadTypeEmoji, PayloadTypeReaction}},
+	}, filters.Filters())
+
+	require.NoError(t, filters.Set("status", nil))
+	require.False(t, filters.skip("status", reaction))
+	require.Len(t, filters.Filters(), 1)
+
+	err := filters.Set("status", []string{"unknown"})
+	require.Equal(t, ErrUnknownPayloadType, errors.Cause(err))
+}
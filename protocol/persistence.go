@@ -3,12 +3,19 @@ package protocol
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"database/sql"
 	"encoding/gob"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/transport"
 )
 
 var (
@@ -102,9 +109,18 @@ func (db sqlitePersistence) saveChat(tx *sql.Tx, chat Chat) error {
 		return err
 	}
 
+	// Encode community channel
+	var encodedCommunityChannel []byte
+	if chat.CommunityChannel != nil {
+		encodedCommunityChannel, err = json.Marshal(chat.CommunityChannel)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Insert record
-	stmt, err := tx.Prepare(`INSERT INTO chats(id, name, color, active, type, timestamp,  deleted_at_clock_value, unviewed_message_count, last_clock_value, last_message, members, membership_updates)
-	    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	stmt, err := tx.Prepare(`INSERT INTO chats(id, name, color, active, type, timestamp,  deleted_at_clock_value, unviewed_message_count, last_clock_value, last_message, members, membership_updates, community_channel)
+	    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return err
 	}
@@ -123,6 +139,7 @@ func (db sqlitePersistence) saveChat(tx *sql.Tx, chat Chat) error {
 		chat.LastMessage,
 		encodedMembers.Bytes(),
 		encodedMembershipUpdates.Bytes(),
+		encodedCommunityChannel,
 	)
 	if err != nil {
 		return err
@@ -169,7 +186,8 @@ func (db sqlitePersistence) chats(tx *sql.Tx) (chats []*Chat, err error) {
 			last_clock_value,
 			last_message,
 			members,
-			membership_updates
+			membership_updates,
+			community_channel
 		FROM chats
 		ORDER BY chats.timestamp DESC
 	`)
@@ -183,6 +201,7 @@ func (db sqlitePersistence) chats(tx *sql.Tx) (chats []*Chat, err error) {
 			chat                     Chat
 			encodedMembers           []byte
 			encodedMembershipUpdates []byte
+			encodedCommunityChannel  []byte
 		)
 		err = rows.Scan(
 			&chat.ID,
@@ -197,6 +216,7 @@ func (db sqlitePersistence) chats(tx *sql.Tx) (chats []*Chat, err error) {
 			&chat.LastMessage,
 			&encodedMembers,
 			&encodedMembershipUpdates,
+			&encodedCommunityChannel,
 		)
 		if err != nil {
 			return
@@ -216,6 +236,12 @@ func (db sqlitePersistence) chats(tx *sql.Tx) (chats []*Chat, err error) {
 			return
 		}
 
+		// Restore community channel
+		err = decodeCommunityChannel(encodedCommunityChannel, &chat)
+		if err != nil {
+			return
+		}
+
 		chats = append(chats, &chat)
 	}
 
@@ -227,6 +253,7 @@ func (db sqlitePersistence) Chat(chatID string) (*Chat, error) {
 		chat                     Chat
 		encodedMembers           []byte
 		encodedMembershipUpdates []byte
+		encodedCommunityChannel  []byte
 	)
 
 	err := db.db.QueryRow(`
@@ -242,7 +269,8 @@ func (db sqlitePersistence) Chat(chatID string) (*Chat, error) {
 			last_clock_value,
 			last_message,
 			members,
-			membership_updates
+			membership_updates,
+			community_channel
 		FROM chats
 		WHERE id = ?
 	`, chatID).Scan(&chat.ID,
@@ -257,6 +285,7 @@ func (db sqlitePersistence) Chat(chatID string) (*Chat, error) {
 		&chat.LastMessage,
 		&encodedMembers,
 		&encodedMembershipUpdates,
+		&encodedCommunityChannel,
 	)
 	switch err {
 	case sql.ErrNoRows:
@@ -276,6 +305,12 @@ func (db sqlitePersistence) Chat(chatID string) (*Chat, error) {
 			return nil, err
 		}
 
+		// Restore community channel
+		err = decodeCommunityChannel(encodedCommunityChannel, &chat)
+		if err != nil {
+			return nil, err
+		}
+
 		return &chat, nil
 	}
 
@@ -283,6 +318,14 @@ func (db sqlitePersistence) Chat(chatID string) (*Chat, error) {
 
 }
 
+func decodeCommunityChannel(data []byte, chat *Chat) error {
+	if len(data) == 0 {
+		return nil
+	}
+	chat.CommunityChannel = &transport.ChannelDescriptor{}
+	return json.Unmarshal(data, chat.CommunityChannel)
+}
+
 func (db sqlitePersistence) Contacts() ([]*Contact, error) {
 	rows, err := db.db.Query(`
 		SELECT
@@ -602,3 +645,840 @@ func (db sqlitePersistence) TransactionsToValidate() ([]*TransactionToValidate,
 
 	return transactions, nil
 }
+
+func (db sqlitePersistence) SaveContactRequest(request *ContactRequest) error {
+	_, err := db.db.Exec(`INSERT INTO contact_requests(
+		id,
+		message,
+		clock,
+		timestamp,
+		state)
+		VALUES (?, ?, ?, ?, ?)`,
+		request.ID,
+		request.Message,
+		request.Clock,
+		request.Timestamp,
+		request.State,
+	)
+	return err
+}
+
+func (db sqlitePersistence) ContactRequests() ([]*ContactRequest, error) {
+	var requests []*ContactRequest
+	rows, err := db.db.Query(`
+		SELECT
+			id,
+			message,
+			clock,
+			timestamp,
+			state
+		FROM contact_requests
+		ORDER BY timestamp DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r ContactRequest
+		err = rows.Scan(
+			&r.ID,
+			&r.Message,
+			&r.Clock,
+			&r.Timestamp,
+			&r.State,
+		)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, &r)
+	}
+
+	return requests, nil
+}
+
+// SavePublicChatsAnnouncement replaces chats announced previously by the announcer.
+// Outdated announcements are ignored.
+func (db sqlitePersistence) SavePublicChatsAnnouncement(announcer string, clock, timestamp uint64, chats []string) (err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	var lastClock sql.NullInt64
+	err = tx.QueryRow(`SELECT MAX(clock) FROM public_chats_directory WHERE announcer = ?`, announcer).Scan(&lastClock)
+	if err != nil {
+		return err
+	}
+	if lastClock.Valid && uint64(lastClock.Int64) >= clock {
+		return nil
+	}
+
+	_, err = tx.Exec(`DELETE FROM public_chats_directory WHERE announcer = ?`, announcer)
+	if err != nil {
+		return err
+	}
+
+	for _, chatID := range chats {
+		_, err = tx.Exec(`INSERT INTO public_chats_directory(announcer, chat_id, clock, timestamp) VALUES (?, ?, ?, ?)`,
+			announcer,
+			chatID,
+			clock,
+			timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SuggestedChannels returns public chats announced after the given timestamp,
+// ordered by the number of distinct announcers.
+func (db sqlitePersistence) SuggestedChannels(since uint64) ([]*SuggestedChannel, error) {
+	var channels []*SuggestedChannel
+	rows, err := db.db.Query(`
+		SELECT
+			chat_id,
+			COUNT(DISTINCT announcer) AS announcers
+		FROM public_chats_directory
+		WHERE timestamp >= ?
+		GROUP BY chat_id
+		ORDER BY announcers DESC, chat_id ASC
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c SuggestedChannel
+		err = rows.Scan(&c.Name, &c.Count)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, &c)
+	}
+
+	return channels, nil
+}
+
+// SaveReaction stores a reaction of the author to a message, unless a reaction
+// with a higher clock value has been already stored.
+func (db sqlitePersistence) SaveReaction(chatID, messageID, author, reaction string, clock uint64, retracted bool) (err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	var lastClock sql.NullInt64
+	err = tx.QueryRow(`SELECT clock FROM reactions WHERE message_id = ? AND author = ? AND reaction = ?`, messageID, author, reaction).Scan(&lastClock)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if lastClock.Valid && uint64(lastClock.Int64) >= clock {
+		return nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO reactions(chat_id, message_id, author, reaction, clock, retracted) VALUES (?, ?, ?, ?, ?, ?)`,
+		chatID,
+		messageID,
+		author,
+		reaction,
+		clock,
+		retracted,
+	)
+	return err
+}
+
+// Reactions returns reactions to the given messages of a chat aggregated by message ID.
+func (db sqlitePersistence) Reactions(chatID string, messageIDs []string) (map[string][]*ReactionSummary, error) {
+	result := make(map[string][]*ReactionSummary)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]interface{}, 0, len(messageIDs)+1)
+	args = append(args, chatID)
+	for _, id := range messageIDs {
+		args = append(args, id)
+	}
+
+	inVector := strings.Repeat("?, ", len(messageIDs)-1) + "?"
+	query := "SELECT message_id, reaction, author FROM reactions WHERE chat_id = ? AND NOT retracted AND message_id IN (" + inVector + ") ORDER BY message_id, reaction, clock" // nolint: gosec
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID, reaction, author string
+		err = rows.Scan(&messageID, &reaction, &author)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries := result[messageID]
+		if len(summaries) == 0 || summaries[len(summaries)-1].Reaction != reaction {
+			summaries = append(summaries, &ReactionSummary{Reaction: reaction})
+		}
+		summary := summaries[len(summaries)-1]
+		summary.Count++
+		summary.Authors = append(summary.Authors, author)
+		result[messageID] = summaries
+	}
+
+	return result, rows.Err()
+}
+
+// SavePinnedMessage pins or unpins a message of a chat, unless a pin or an unpin
+// of the message with a higher clock value has been already stored.
+func (db sqlitePersistence) SavePinnedMessage(chatID, messageID, pinnedBy string, clock uint64, pinned bool) (err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	var lastClock sql.NullInt64
+	err = tx.QueryRow(`SELECT clock FROM pinned_messages WHERE chat_id = ? AND message_id = ?`, chatID, messageID).Scan(&lastClock)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if lastClock.Valid && uint64(lastClock.Int64) >= clock {
+		return nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO pinned_messages(chat_id, message_id, pinned_by, clock, pinned) VALUES (?, ?, ?, ?, ?)`,
+		chatID,
+		messageID,
+		pinnedBy,
+		clock,
+		pinned,
+	)
+	return err
+}
+
+// PinnedMessages returns messages pinned in a chat, the most recently pinned first.
+func (db sqlitePersistence) PinnedMessages(chatID string) ([]*PinnedMessage, error) {
+	rows, err := db.db.Query(`SELECT message_id, pinned_by, clock FROM pinned_messages WHERE chat_id = ? AND pinned ORDER BY clock DESC`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*PinnedMessage
+	for rows.Next() {
+		pinned := &PinnedMessage{ChatID: chatID}
+		err = rows.Scan(&pinned.MessageID, &pinned.PinnedBy, &pinned.Clock)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pinned)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, pinned := range result {
+		message, err := db.MessageByID(pinned.MessageID)
+		if err == errRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pinned.Message = message
+	}
+
+	return result, nil
+}
+
+// SaveMentions stores messages mentioning the user.
+func (db sqlitePersistence) SaveMentions(mentions []*Mention) (err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.Prepare(`INSERT INTO mentions(message_id, chat_id, author, clock, timestamp) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, mention := range mentions {
+		_, err = stmt.Exec(mention.MessageID, mention.ChatID, mention.From, mention.Clock, mention.Timestamp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mentions returns at most limit mentions of a chat, or of all chats if chatID is empty,
+// the most recent first.
+func (db sqlitePersistence) Mentions(chatID string, limit int) ([]*Mention, error) {
+	query := `SELECT message_id, chat_id, author, clock, timestamp FROM mentions`
+	var args []interface{}
+	if chatID != "" {
+		query += ` WHERE chat_id = ?`
+		args = append(args, chatID)
+	}
+	query += ` ORDER BY clock DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Mention
+	for rows.Next() {
+		mention := &Mention{}
+		err = rows.Scan(&mention.MessageID, &mention.ChatID, &mention.From, &mention.Clock, &mention.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, mention)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, mention := range result {
+		message, err := db.MessageByID(mention.MessageID)
+		if err == errRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		mention.Message = message
+	}
+
+	return result, nil
+}
+
+// SaveMentionNames replaces names mentioning the user.
+func (db sqlitePersistence) SaveMentionNames(names []string) (err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.Exec(`DELETE FROM mention_names`); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err = tx.Exec(`INSERT INTO mention_names(name) VALUES (?)`, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MentionNames returns names mentioning the user.
+func (db sqlitePersistence) MentionNames() ([]string, error) {
+	return db.queryStrings(`SELECT name FROM mention_names ORDER BY name`)
+}
+
+// SearchMessages returns messages matching a full-text query, at most limit of them.
+// If chatIDs are given, only messages of these chats are searched.
+func (db sqlitePersistence) SearchMessages(query string, chatIDs []string, limit int) ([]*MessageSearchResult, error) {
+	args := []interface{}{query}
+	chatsWhere := ""
+	if len(chatIDs) > 0 {
+		chatsWhere = "AND m.local_chat_id IN (" + strings.Repeat("?, ", len(chatIDs)-1) + "?)"
+		for _, id := range chatIDs {
+			args = append(args, id)
+		}
+	}
+	rows, err := db.db.Query(`
+		SELECT
+			user_messages_fts.docid,
+			m.id,
+			m.clock_value,
+			matchinfo(user_messages_fts, 'pcx')
+		FROM
+			user_messages_fts
+		JOIN
+			user_messages m
+		ON
+			m.rowid = user_messages_fts.docid
+		WHERE
+			user_messages_fts MATCH ? AND m.hide != 1 `+chatsWhere, // nolint: gosec
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []searchMatch
+	for rows.Next() {
+		var (
+			match searchMatch
+			info  []byte
+		)
+		if err := rows.Scan(&match.rowID, &match.id, &match.clock, &info); err != nil {
+			return nil, err
+		}
+		match.rank = rankMatchInfo(info)
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortSearchMatches(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]*MessageSearchResult, 0, len(matches))
+	for _, match := range matches {
+		message, err := db.MessageByID(match.id)
+		if err != nil {
+			return nil, err
+		}
+		var snippet string
+		err = db.db.QueryRow(
+			`SELECT snippet(user_messages_fts, '<b>', '</b>', '...', -1, ?) FROM user_messages_fts WHERE user_messages_fts MATCH ? AND docid = ?`,
+			searchSnippetTokens, query, match.rowID,
+		).Scan(&snippet)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &MessageSearchResult{
+			Message: message,
+			Snippet: snippet,
+			Rank:    match.rank,
+		})
+	}
+	return result, nil
+}
+
+// SaveOutboxEntry inserts or replaces a postponed message.
+func (db sqlitePersistence) SaveOutboxEntry(entry *outboxEntry) error {
+	var encodedIdentifiers bytes.Buffer
+	if err := gob.NewEncoder(&encodedIdentifiers).Encode(entry.message.Identifiers); err != nil {
+		return err
+	}
+	message := entry.message
+	id := message.ID()
+	_, err := db.db.Exec(`INSERT INTO outbox_messages(id, identifiers, topic, payload, public_key, sym_key, ttl, pow_target, pow_time, attempts, next_attempt, created_at, last_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id[:],
+		encodedIdentifiers.Bytes(),
+		message.Topic[:],
+		message.Payload,
+		message.PublicKey,
+		message.SymKey,
+		message.TTL,
+		message.PowTarget,
+		message.PowTime,
+		entry.attempts,
+		entry.nextAttempt.Unix(),
+		entry.createdAt.Unix(),
+		entry.lastError,
+	)
+	return err
+}
+
+// OutboxEntry returns a postponed message by ID or nil if it doesn't exist.
+func (db sqlitePersistence) OutboxEntry(id types.Hash) (*outboxEntry, error) {
+	rows, err := db.db.Query(outboxEntriesQuery+" WHERE id = ?", id[:]) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanOutboxEntries(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// DueOutboxEntries returns postponed messages that should be retried at the given time.
+func (db sqlitePersistence) DueOutboxEntries(now time.Time) ([]*outboxEntry, error) {
+	rows, err := db.db.Query(outboxEntriesQuery+" WHERE next_attempt <= ? ORDER BY next_attempt", now.Unix()) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	return scanOutboxEntries(rows)
+}
+
+// DeleteOutboxEntry removes a postponed message.
+func (db sqlitePersistence) DeleteOutboxEntry(id types.Hash) error {
+	_, err := db.db.Exec(`DELETE FROM outbox_messages WHERE id = ?`, id[:])
+	return err
+}
+
+const outboxEntriesQuery = `SELECT identifiers, topic, payload, public_key, sym_key, ttl, pow_target, pow_time, attempts, next_attempt, created_at, last_error FROM outbox_messages`
+
+func scanOutboxEntries(rows *sql.Rows) ([]*outboxEntry, error) {
+	defer rows.Close()
+
+	var entries []*outboxEntry
+	for rows.Next() {
+		var (
+			message            transport.OutboxMessage
+			entry              = outboxEntry{message: &message}
+			encodedIdentifiers []byte
+			topic              []byte
+			nextAttempt        int64
+			createdAt          int64
+			lastError          sql.NullString
+		)
+		err := rows.Scan(
+			&encodedIdentifiers,
+			&topic,
+			&message.Payload,
+			&message.PublicKey,
+			&message.SymKey,
+			&message.TTL,
+			&message.PowTarget,
+			&message.PowTime,
+			&entry.attempts,
+			&nextAttempt,
+			&createdAt,
+			&lastError,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := gob.NewDecoder(bytes.NewBuffer(encodedIdentifiers)).Decode(&message.Identifiers); err != nil {
+			return nil, err
+		}
+		message.Topic = types.BytesToTopic(topic)
+		entry.nextAttempt = time.Unix(nextAttempt, 0)
+		entry.createdAt = time.Unix(createdAt, 0)
+		entry.lastError = lastError.String
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// SaveMessageSegment stores a segment of a message received from the sender
+// identified by sigPubKey. timestamp is a unix time when the segment was received.
+func (db sqlitePersistence) SaveMessageSegment(segment *protobuf.SegmentMessage, sigPubKey *ecdsa.PublicKey, timestamp int64) error {
+	_, err := db.db.Exec(`INSERT INTO message_segments(hash, sig_pub_key, segment_index, segments_count, payload, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		segment.EntireMessageHash,
+		crypto.CompressPubkey(sigPubKey),
+		segment.Index,
+		segment.SegmentsCount,
+		segment.Payload,
+		timestamp,
+	)
+	return err
+}
+
+// MessageSegments returns stored segments of a message ordered by index.
+// Only segments with a matching number of segments are returned.
+func (db sqlitePersistence) MessageSegments(hash []byte, segmentsCount uint32, sigPubKey *ecdsa.PublicKey) ([]*protobuf.SegmentMessage, error) {
+	rows, err := db.db.Query(`SELECT segment_index, payload FROM message_segments
+		WHERE hash = ? AND sig_pub_key = ? AND segments_count = ? ORDER BY segment_index`,
+		hash,
+		crypto.CompressPubkey(sigPubKey),
+		segmentsCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*protobuf.SegmentMessage
+	for rows.Next() {
+		segment := &protobuf.SegmentMessage{
+			EntireMessageHash: hash,
+			SegmentsCount:     segmentsCount,
+		}
+		if err := rows.Scan(&segment.Index, &segment.Payload); err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	return segments, rows.Err()
+}
+
+// DeleteMessageSegments removes all segments of a message.
+func (db sqlitePersistence) DeleteMessageSegments(hash []byte, sigPubKey *ecdsa.PublicKey) error {
+	_, err := db.db.Exec(`DELETE FROM message_segments WHERE hash = ? AND sig_pub_key = ?`, hash, crypto.CompressPubkey(sigPubKey))
+	return err
+}
+
+// DeleteMessageSegmentsOlderThan removes segments received before the given unix time.
+func (db sqlitePersistence) DeleteMessageSegmentsOlderThan(timestamp int64) error {
+	_, err := db.db.Exec(`DELETE FROM message_segments WHERE timestamp < ?`, timestamp)
+	return err
+}
+
+// SavePushNotificationRegistration stores the registration of an installation replacing the previous one.
+func (db sqlitePersistence) SavePushNotificationRegistration(registration *PushNotificationRegistration) error {
+	server, err := registration.serverPublicKey()
+	if err != nil {
+		return err
+	}
+	_, err = db.db.Exec(`INSERT INTO push_notification_registrations(installation_id, server_public_key, token_type, device_token, access_token, version, request_id, status, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		registration.InstallationID,
+		crypto.CompressPubkey(server),
+		registration.TokenType,
+		registration.DeviceToken,
+		registration.accessToken,
+		registration.Version,
+		registration.requestID,
+		registration.Status,
+		registration.Error,
+	)
+	return err
+}
+
+// PushNotificationRegistration returns the registration of an installation or nil if it doesn't exist.
+func (db sqlitePersistence) PushNotificationRegistration(installationID string) (*PushNotificationRegistration, error) {
+	var (
+		registration = PushNotificationRegistration{InstallationID: installationID}
+		server       []byte
+	)
+	err := db.db.QueryRow(`SELECT server_public_key, token_type, device_token, access_token, version, request_id, status, error
+		FROM push_notification_registrations WHERE installation_id = ?`, installationID).Scan(
+		&server,
+		&registration.TokenType,
+		&registration.DeviceToken,
+		&registration.accessToken,
+		&registration.Version,
+		&registration.requestID,
+		&registration.Status,
+		&registration.Error,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	publicKey, err := crypto.DecompressPubkey(server)
+	if err != nil {
+		return nil, err
+	}
+	registration.Server = types.EncodeHex(crypto.FromECDSAPub(publicKey))
+	return &registration, nil
+}
+
+// SavePushNotificationInfo stores an access token shared by a contact for one of its installations.
+func (db sqlitePersistence) SavePushNotificationInfo(contactID string, info *pushNotificationInfo) error {
+	_, err := db.db.Exec(`INSERT INTO push_notification_infos(public_key, installation_id, server_public_key, access_token, version)
+		VALUES (?, ?, ?, ?, ?)`,
+		contactID,
+		info.installationID,
+		crypto.CompressPubkey(info.server),
+		info.accessToken,
+		info.version,
+	)
+	return err
+}
+
+// PushNotificationInfos returns access tokens shared by a contact.
+func (db sqlitePersistence) PushNotificationInfos(contactID string) ([]*pushNotificationInfo, error) {
+	rows, err := db.db.Query(`SELECT installation_id, server_public_key, access_token, version
+		FROM push_notification_infos WHERE public_key = ? ORDER BY installation_id`, contactID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var infos []*pushNotificationInfo
+	for rows.Next() {
+		var (
+			info   pushNotificationInfo
+			server []byte
+		)
+		if err := rows.Scan(&info.installationID, &server, &info.accessToken, &info.version); err != nil {
+			return nil, err
+		}
+		info.server, err = crypto.DecompressPubkey(server)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, &info)
+	}
+	return infos, rows.Err()
+}
+
+// SavePushNotificationServerRegistration stores an encrypted registration of an installation
+// of a user served by this node.
+func (db sqlitePersistence) SavePushNotificationServerRegistration(publicKey *ecdsa.PublicKey, installationID string, version uint64, registration []byte) error {
+	_, err := db.db.Exec(`INSERT INTO push_notification_server_registrations(public_key, installation_id, version, registration)
+		VALUES (?, ?, ?, ?)`,
+		crypto.CompressPubkey(publicKey),
+		installationID,
+		version,
+		registration,
+	)
+	return err
+}
+
+// PushNotificationServerRegistrationVersion returns the version of a stored registration, 0 if it doesn't exist.
+func (db sqlitePersistence) PushNotificationServerRegistrationVersion(publicKey *ecdsa.PublicKey, installationID string) (uint64, error) {
+	var version uint64
+	err := db.db.QueryRow(`SELECT version FROM push_notification_server_registrations WHERE public_key = ? AND installation_id = ?`,
+		crypto.CompressPubkey(publicKey),
+		installationID,
+	).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, err
+}
+
+// PushNotificationServerRegistrations returns encrypted registrations of an installation.
+func (db sqlitePersistence) PushNotificationServerRegistrations(installationID string) ([][]byte, error) {
+	rows, err := db.db.Query(`SELECT registration FROM push_notification_server_registrations WHERE installation_id = ?`, installationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var registrations [][]byte
+	for rows.Next() {
+		var registration []byte
+		if err := rows.Scan(&registration); err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, registration)
+	}
+	return registrations, rows.Err()
+}
+
+// SaveChatIdentity stores the identity announced by a user, replacing the previous one.
+func (db sqlitePersistence) SaveChatIdentity(identity *ChatIdentity) error {
+	_, err := db.db.Exec(`INSERT INTO chat_identities(public_key, clock, display_name, image_hash, received_at, published_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		identity.PublicKey,
+		identity.Clock,
+		identity.DisplayName,
+		[]byte(identity.ImageHash),
+		identity.ReceivedAt,
+		identity.publishedAt,
+	)
+	return err
+}
+
+// ChatIdentity returns the identity announced by a user or nil if it wasn't received.
+func (db sqlitePersistence) ChatIdentity(publicKey string) (*ChatIdentity, error) {
+	var (
+		identity  = ChatIdentity{PublicKey: publicKey}
+		imageHash []byte
+	)
+	err := db.db.QueryRow(`SELECT clock, display_name, image_hash, received_at, published_at
+		FROM chat_identities WHERE public_key = ?`, publicKey).Scan(
+		&identity.Clock,
+		&identity.DisplayName,
+		&imageHash,
+		&identity.ReceivedAt,
+		&identity.publishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(imageHash) > 0 {
+		identity.ImageHash = imageHash
+	}
+	return &identity, nil
+}
+
+// BlockPublicKey adds the public key to the block list.
+func (db sqlitePersistence) BlockPublicKey(publicKey string, blockedAt int64) error {
+	_, err := db.db.Exec(`INSERT INTO blocked_public_keys(public_key, blocked_at) VALUES (?, ?)`, publicKey, blockedAt)
+	return err
+}
+
+// UnblockPublicKey removes the public key from the block list.
+func (db sqlitePersistence) UnblockPublicKey(publicKey string) error {
+	_, err := db.db.Exec(`DELETE FROM blocked_public_keys WHERE public_key = ?`, publicKey)
+	return err
+}
+
+// BlockedPublicKeys returns all blocked public keys.
+func (db sqlitePersistence) BlockedPublicKeys() ([]string, error) {
+	return db.queryStrings(`SELECT public_key FROM blocked_public_keys ORDER BY blocked_at, public_key`)
+}
+
+// MuteChat adds the chat to the mute list.
+func (db sqlitePersistence) MuteChat(chatID string, mutedAt int64) error {
+	_, err := db.db.Exec(`INSERT INTO muted_chats(chat_id, muted_at) VALUES (?, ?)`, chatID, mutedAt)
+	return err
+}
+
+// UnmuteChat removes the chat from the mute list.
+func (db sqlitePersistence) UnmuteChat(chatID string) error {
+	_, err := db.db.Exec(`DELETE FROM muted_chats WHERE chat_id = ?`, chatID)
+	return err
+}
+
+// MutedChats returns IDs of all muted chats.
+func (db sqlitePersistence) MutedChats() ([]string, error) {
+	return db.queryStrings(`SELECT chat_id FROM muted_chats ORDER BY muted_at, chat_id`)
+}
+
+func (db sqlitePersistence) queryStrings(query string, args ...interface{}) ([]string, error) {
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		rst = append(rst, value)
+	}
+	return rst, rows.Err()
+}
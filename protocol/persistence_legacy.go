@@ -317,12 +317,29 @@ func (db sqlitePersistence) MessagesExist(ids []string) (map[string]bool, error)
 // Ordering is accomplished using two concatenated values: ClockValue and ID.
 // These two values are also used to compose a cursor which is returned to the result.
 func (db sqlitePersistence) MessageByChatID(chatID string, currCursor string, limit int) ([]*Message, string, error) {
+	return db.messagesByChatID(chatID, "", nil, currCursor, limit)
+}
+
+// MessageByChatIDInRange returns messages of a chat sent between from and to timestamps
+// in milliseconds, inclusive. If to is zero, the range is not bounded from above.
+// Messages are paginated the same way as by MessageByChatID.
+func (db sqlitePersistence) MessageByChatIDInRange(chatID string, from, to uint64, currCursor string, limit int) ([]*Message, string, error) {
+	rangeWhere := "AND m1.timestamp >= ?"
+	args := []interface{}{from}
+	if to > 0 {
+		rangeWhere += " AND m1.timestamp <= ?"
+		args = append(args, to)
+	}
+	return db.messagesByChatID(chatID, rangeWhere, args, currCursor, limit)
+}
+
+func (db sqlitePersistence) messagesByChatID(chatID string, where string, whereArgs []interface{}, currCursor string, limit int) ([]*Message, string, error) {
 	cursorWhere := ""
 	if currCursor != "" {
 		cursorWhere = "AND cursor <= ?"
 	}
 	allFields := db.tableUserMessagesLegacyAllFieldsJoin()
-	args := []interface{}{chatID}
+	args := append([]interface{}{chatID}, whereArgs...)
 	if currCursor != "" {
 		args = append(args, currCursor)
 	}
@@ -347,10 +364,10 @@ func (db sqlitePersistence) MessageByChatID(chatID string, currCursor string, li
 
 			m1.source = c.id
 			WHERE
-				m1.hide != 1 AND m1.local_chat_id = ? %s
+				m1.hide != 1 AND m1.local_chat_id = ? %s %s
 			ORDER BY cursor DESC
 			LIMIT ?
-		`, allFields, cursorWhere),
+		`, allFields, where, cursorWhere),
 		append(args, limit+1)..., // take one more to figure our whether a cursor should be returned
 	)
 	if err != nil {
@@ -213,6 +213,102 @@ func TestMessageReplies(t *testing.T) {
 	require.Nil(t, retrievedMessages[2].QuotedMessage)
 }
 
+func TestMessageByChatIDInRange(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := sqlitePersistence{db: db}
+	chatID := "super-chat"
+
+	var messages []*Message
+	for i := 0; i < 10; i++ {
+		messages = append(messages, &Message{
+			ID:          strconv.Itoa(i),
+			LocalChatID: chatID,
+			ChatMessage: protobuf.ChatMessage{
+				Clock:     uint64(i),
+				Timestamp: uint64(1000 * i),
+			},
+			From: "me",
+		})
+	}
+	messages = append(messages, &Message{
+		ID:          "other",
+		LocalChatID: "other-chat",
+		ChatMessage: protobuf.ChatMessage{
+			Clock:     5,
+			Timestamp: 5000,
+		},
+		From: "me",
+	})
+	require.NoError(t, p.SaveMessagesLegacy(messages))
+
+	result, cursor, err := p.MessageByChatIDInRange(chatID, 3000, 7000, "", 3)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	require.Equal(t, "7", result[0].ID)
+	require.Equal(t, "5", result[2].ID)
+	require.NotEmpty(t, cursor)
+
+	result, cursor, err = p.MessageByChatIDInRange(chatID, 3000, 7000, cursor, 3)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "4", result[0].ID)
+	require.Equal(t, "3", result[1].ID)
+	require.Empty(t, cursor)
+
+	// the range is not bounded from above
+	result, _, err = p.MessageByChatIDInRange(chatID, 8000, 0, "", 10)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+}
+
+func TestSearchMessages(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := sqlitePersistence{db: db}
+
+	messages := []*Message{
+		{ID: "1", LocalChatID: "status", From: "me", ChatMessage: protobuf.ChatMessage{Clock: 1, Text: "keycard is shipping"}},
+		{ID: "2", LocalChatID: "status", From: "me", ChatMessage: protobuf.ChatMessage{Clock: 2, Text: "keycard keycard keycard"}},
+		{ID: "3", LocalChatID: "other", From: "me", ChatMessage: protobuf.ChatMessage{Clock: 3, Text: "my keycard arrived"}},
+		{ID: "4", LocalChatID: "status", From: "me", ChatMessage: protobuf.ChatMessage{Clock: 4, Text: "unrelated"}},
+	}
+	require.NoError(t, p.SaveMessagesLegacy(messages))
+
+	results, err := p.SearchMessages("keycard", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "2", results[0].Message.ID)
+	require.Equal(t, "3", results[1].Message.ID)
+	require.Equal(t, "1", results[2].Message.ID)
+	require.True(t, results[0].Rank > results[1].Rank)
+	require.Equal(t, "my <b>keycard</b> arrived", results[1].Snippet)
+
+	results, err = p.SearchMessages("keycard", []string{"status"}, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "2", results[0].Message.ID)
+
+	// the index follows replaced and deleted messages
+	require.NoError(t, p.SaveMessagesLegacy([]*Message{
+		{ID: "2", LocalChatID: "status", From: "me", ChatMessage: protobuf.ChatMessage{Clock: 2, Text: "edited"}},
+	}))
+	require.NoError(t, p.DeleteMessage("3"))
+	var indexed int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM user_messages_fts`).Scan(&indexed))
+	require.Equal(t, 3, indexed)
+	results, err = p.SearchMessages("keycard", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1", results[0].Message.ID)
+
+	// hidden messages are not returned
+	require.NoError(t, p.HideMessage("1"))
+	results, err = p.SearchMessages("keycard", nil, 10)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
 func TestMessageByChatIDWithTheSameClocks(t *testing.T) {
 	db, err := openTestDB()
 	require.NoError(t, err)
@@ -0,0 +1,114 @@
+package protocol
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// PinnedMessage is a message pinned in a chat.
+type PinnedMessage struct {
+	ChatID    string `json:"chatId"`
+	MessageID string `json:"messageId"`
+	// PinnedBy is the public key of the user that pinned the message
+	PinnedBy string `json:"pinnedBy"`
+	Clock    uint64 `json:"clock"`
+	// Message is the pinned message, nil if it hasn't been received
+	Message *Message `json:"message,omitempty"`
+}
+
+// authorizePin checks that the author is allowed to pin messages in the chat.
+// Anyone can pin messages in one-to-one chats, only admins in group chats and
+// only the community in its channels.
+func authorizePin(chat *Chat, author string) error {
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		return nil
+	case ChatTypePrivateGroupChat:
+		if !chat.hasAdminMember(author) {
+			return ErrPinNotAuthorized
+		}
+		return nil
+	case ChatTypePublic:
+		if chat.CommunityChannel == nil {
+			return ErrPinningNotSupported
+		}
+		if "0x"+chat.CommunityID() != author {
+			return ErrPinNotAuthorized
+		}
+		return nil
+	default:
+		return ErrPinningNotSupported
+	}
+}
+
+// PinMessage pins a message in the chat it belongs to.
+func (m *Messenger) PinMessage(ctx context.Context, chatID, messageID string) error {
+	return m.sendPinMessage(ctx, chatID, messageID, true)
+}
+
+// UnpinMessage unpins a message previously pinned with PinMessage.
+func (m *Messenger) UnpinMessage(ctx context.Context, chatID, messageID string) error {
+	return m.sendPinMessage(ctx, chatID, messageID, false)
+}
+
+func (m *Messenger) sendPinMessage(ctx context.Context, chatID, messageID string, pinned bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	chat, ok := m.allChats[chatID]
+	if !ok {
+		return errors.New("Chat not found")
+	}
+
+	author := contactIDFromPublicKey(&m.identity.PublicKey)
+	if err := authorizePin(chat, author); err != nil {
+		return err
+	}
+
+	var messageType protobuf.ChatMessage_MessageType
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		messageType = protobuf.ChatMessage_ONE_TO_ONE
+	case ChatTypePublic:
+		messageType = protobuf.ChatMessage_PUBLIC_GROUP
+	case ChatTypePrivateGroupChat:
+		messageType = protobuf.ChatMessage_PRIVATE_GROUP
+	}
+
+	clock, _ := chat.NextClockAndTimestamp(m.getTimesource())
+	message := &protobuf.PinMessage{
+		Clock:       clock,
+		ChatId:      chat.ID,
+		MessageId:   messageID,
+		MessageType: messageType,
+		Pinned:      pinned,
+	}
+	if err := ValidateReceivedPinMessage(message, clock); err != nil {
+		return err
+	}
+
+	encodedMessage, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.dispatchMessage(ctx, &RawMessage{
+		LocalChatID: chat.ID,
+		Payload:     encodedMessage,
+		MessageType: protobuf.ApplicationMetadataMessage_PIN_MESSAGE,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.persistence.SavePinnedMessage(chat.ID, messageID, author, clock, pinned)
+}
+
+// PinnedMessages returns messages pinned in a chat.
+func (m *Messenger) PinnedMessages(chatID string) ([]*PinnedMessage, error) {
+	return m.persistence.PinnedMessages(chatID)
+}
@@ -38,6 +38,16 @@ const (
 	ApplicationMetadataMessage_SYNC_INSTALLATION_CONTACT               ApplicationMetadataMessage_Type = 12
 	ApplicationMetadataMessage_SYNC_INSTALLATION_ACCOUNT               ApplicationMetadataMessage_Type = 13
 	ApplicationMetadataMessage_SYNC_INSTALLATION_PUBLIC_CHAT           ApplicationMetadataMessage_Type = 14
+	ApplicationMetadataMessage_CONTACT_REQUEST                         ApplicationMetadataMessage_Type = 15
+	ApplicationMetadataMessage_PUBLIC_CHATS_ANNOUNCEMENT               ApplicationMetadataMessage_Type = 16
+	ApplicationMetadataMessage_REACTION                                ApplicationMetadataMessage_Type = 17
+	ApplicationMetadataMessage_CHAT_INDICATOR                          ApplicationMetadataMessage_Type = 18
+	ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION          ApplicationMetadataMessage_Type = 19
+	ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION_RESPONSE ApplicationMetadataMessage_Type = 20
+	ApplicationMetadataMessage_PUSH_NOTIFICATION_INFO                  ApplicationMetadataMessage_Type = 21
+	ApplicationMetadataMessage_PUSH_NOTIFICATION_REQUEST               ApplicationMetadataMessage_Type = 22
+	ApplicationMetadataMessage_CHAT_IDENTITY                           ApplicationMetadataMessage_Type = 23
+	ApplicationMetadataMessage_PIN_MESSAGE                             ApplicationMetadataMessage_Type = 24
 )
 
 var ApplicationMetadataMessage_Type_name = map[int32]string{
@@ -56,6 +66,16 @@ var ApplicationMetadataMessage_Type_name = map[int32]string{
 	12: "SYNC_INSTALLATION_CONTACT",
 	13: "SYNC_INSTALLATION_ACCOUNT",
 	14: "SYNC_INSTALLATION_PUBLIC_CHAT",
+	15: "CONTACT_REQUEST",
+	16: "PUBLIC_CHATS_ANNOUNCEMENT",
+	17: "REACTION",
+	18: "CHAT_INDICATOR",
+	19: "PUSH_NOTIFICATION_REGISTRATION",
+	20: "PUSH_NOTIFICATION_REGISTRATION_RESPONSE",
+	21: "PUSH_NOTIFICATION_INFO",
+	22: "PUSH_NOTIFICATION_REQUEST",
+	23: "CHAT_IDENTITY",
+	24: "PIN_MESSAGE",
 }
 
 var ApplicationMetadataMessage_Type_value = map[string]int32{
@@ -74,6 +94,16 @@ var ApplicationMetadataMessage_Type_value = map[string]int32{
 	"SYNC_INSTALLATION_CONTACT":               12,
 	"SYNC_INSTALLATION_ACCOUNT":               13,
 	"SYNC_INSTALLATION_PUBLIC_CHAT":           14,
+	"CONTACT_REQUEST":                         15,
+	"PUBLIC_CHATS_ANNOUNCEMENT":               16,
+	"REACTION":                                17,
+	"CHAT_INDICATOR":                          18,
+	"PUSH_NOTIFICATION_REGISTRATION":          19,
+	"PUSH_NOTIFICATION_REGISTRATION_RESPONSE": 20,
+	"PUSH_NOTIFICATION_INFO":                  21,
+	"PUSH_NOTIFICATION_REQUEST":               22,
+	"CHAT_IDENTITY":                           23,
+	"PIN_MESSAGE":                             24,
 }
 
 func (x ApplicationMetadataMessage_Type) String() string {
@@ -150,29 +180,36 @@ func init() {
 func init() { proto.RegisterFile("application_metadata_message.proto", fileDescriptor_ad09a6406fcf24c7) }
 
 var fileDescriptor_ad09a6406fcf24c7 = []byte{
-	// 377 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xcf, 0x8e, 0xd3, 0x30,
-	0x10, 0xc6, 0xc9, 0x36, 0x6c, 0x77, 0x67, 0x4b, 0x65, 0x06, 0x10, 0xe1, 0xcf, 0x6a, 0x97, 0x22,
-	0xc1, 0x02, 0x52, 0x0e, 0x70, 0xe6, 0xe0, 0x75, 0x0c, 0x1b, 0x91, 0x38, 0xc1, 0x76, 0x84, 0x38,
-	0x59, 0x2e, 0x0d, 0x55, 0xa5, 0xb6, 0x89, 0xda, 0xf4, 0xd0, 0x07, 0xe3, 0x29, 0x78, 0x29, 0x94,
-	0xd0, 0xd2, 0x96, 0x82, 0x7a, 0xb2, 0xe6, 0xfb, 0x7e, 0x9f, 0x47, 0x33, 0x03, 0x3d, 0x5b, 0x96,
-	0xe3, 0xd1, 0x37, 0x5b, 0x8d, 0x8a, 0xa9, 0x99, 0xe4, 0x95, 0x1d, 0xd8, 0xca, 0x9a, 0x49, 0x3e,
-	0x9f, 0xdb, 0x61, 0xee, 0x97, 0xb3, 0xa2, 0x2a, 0xf0, 0xa4, 0x79, 0xfa, 0x8b, 0xef, 0xbd, 0x9f,
-	0x2e, 0x3c, 0xa6, 0x9b, 0x40, 0xbc, 0xe2, 0xe3, 0xdf, 0x38, 0x3e, 0x85, 0xd3, 0xf9, 0x68, 0x38,
-	0xb5, 0xd5, 0x62, 0x96, 0x7b, 0xce, 0xa5, 0x73, 0xd5, 0x91, 0x1b, 0x01, 0x3d, 0x68, 0x97, 0x76,
-	0x39, 0x2e, 0xec, 0xc0, 0x3b, 0x6a, 0xbc, 0x75, 0x89, 0xef, 0xc1, 0xad, 0x96, 0x65, 0xee, 0xb5,
-	0x2e, 0x9d, 0xab, 0xee, 0xdb, 0x57, 0xfe, 0xba, 0x9f, 0xff, 0xff, 0x5e, 0xbe, 0x5e, 0x96, 0xb9,
-	0x6c, 0x62, 0xbd, 0x1f, 0x2d, 0x70, 0xeb, 0x12, 0xcf, 0xa0, 0x9d, 0x89, 0x4f, 0x22, 0xf9, 0x22,
-	0xc8, 0x2d, 0x24, 0xd0, 0x61, 0x37, 0x54, 0x9b, 0x98, 0x2b, 0x45, 0x3f, 0x72, 0xe2, 0x20, 0x42,
-	0x97, 0x25, 0x42, 0x53, 0xa6, 0x4d, 0x96, 0x06, 0x54, 0x73, 0x72, 0x84, 0xe7, 0xf0, 0x28, 0xe6,
-	0xf1, 0x35, 0x97, 0xea, 0x26, 0x4c, 0x57, 0xf2, 0x9f, 0x48, 0x0b, 0x1f, 0xc0, 0xdd, 0x94, 0x86,
-	0xd2, 0x84, 0x42, 0x69, 0x1a, 0x45, 0x54, 0x87, 0x89, 0x20, 0x6e, 0x2d, 0xab, 0xaf, 0x82, 0xed,
-	0xca, 0xb7, 0xf1, 0x39, 0x5c, 0x48, 0xfe, 0x39, 0xe3, 0x4a, 0x1b, 0x1a, 0x04, 0x92, 0x2b, 0x65,
-	0x3e, 0x24, 0xd2, 0x68, 0x49, 0x85, 0xa2, 0xac, 0x81, 0x8e, 0xf1, 0x35, 0xbc, 0xa0, 0x8c, 0xf1,
-	0x54, 0x9b, 0x43, 0x6c, 0x1b, 0xdf, 0xc0, 0xcb, 0x80, 0xb3, 0x28, 0x14, 0xfc, 0x20, 0x7c, 0x82,
-	0x0f, 0xe1, 0xde, 0x1a, 0xda, 0x36, 0x4e, 0xf1, 0x3e, 0x10, 0xc5, 0x45, 0xb0, 0xa3, 0x02, 0x5e,
-	0xc0, 0x93, 0xbf, 0xff, 0xde, 0x06, 0xce, 0xea, 0xd5, 0xec, 0x0d, 0x69, 0x56, 0x0b, 0x24, 0x9d,
-	0x7f, 0xdb, 0x94, 0xb1, 0x24, 0x13, 0x9a, 0xdc, 0xc1, 0x67, 0x70, 0xbe, 0x6f, 0xa7, 0xd9, 0x75,
-	0x14, 0x32, 0x53, 0xdf, 0x85, 0x74, 0xfb, 0xc7, 0xcd, 0x9d, 0xdf, 0xfd, 0x0a, 0x00, 0x00, 0xff,
-	0xff, 0xb7, 0x6c, 0xd6, 0xba, 0x84, 0x02, 0x00, 0x00,
+	// 489 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0x4d, 0x6f, 0xd3, 0x4c,
+	0x10, 0xc7, 0x9f, 0xf4, 0x2d, 0xe9, 0x24, 0x4d, 0x36, 0x93, 0xbe, 0xe4, 0x29, 0x94, 0x96, 0x20,
+	0x41, 0x01, 0x29, 0x07, 0x38, 0x73, 0xd8, 0xae, 0x37, 0xcd, 0x8a, 0x78, 0x6c, 0x76, 0xd7, 0x42,
+	0x3d, 0xad, 0x5c, 0x6a, 0xaa, 0x48, 0x6d, 0x63, 0x35, 0xee, 0x21, 0x9f, 0x8d, 0x4f, 0xc1, 0x37,
+	0x42, 0x76, 0x92, 0x36, 0x25, 0x40, 0x4f, 0xab, 0xf9, 0xcf, 0x6f, 0x5e, 0x76, 0x66, 0xa0, 0x13,
+	0xa7, 0xe9, 0xd5, 0xf0, 0x5b, 0x9c, 0x0d, 0x47, 0x37, 0xee, 0x3a, 0xc9, 0xe2, 0x8b, 0x38, 0x8b,
+	0xdd, 0x75, 0x32, 0x1e, 0xc7, 0x97, 0x49, 0x37, 0xbd, 0x1d, 0x65, 0x23, 0xac, 0x14, 0xcf, 0xf9,
+	0xdd, 0xf7, 0xce, 0xcf, 0x0d, 0xd8, 0xe7, 0x0f, 0x01, 0xfe, 0x8c, 0xf7, 0xa7, 0x38, 0x3e, 0x87,
+	0xcd, 0xf1, 0xf0, 0xf2, 0x26, 0xce, 0xee, 0x6e, 0x93, 0x76, 0xe9, 0xa8, 0x74, 0x5c, 0xd3, 0x0f,
+	0x02, 0xb6, 0xa1, 0x9c, 0xc6, 0x93, 0xab, 0x51, 0x7c, 0xd1, 0x5e, 0x29, 0x7c, 0x73, 0x13, 0x3f,
+	0xc1, 0x5a, 0x36, 0x49, 0x93, 0xf6, 0xea, 0x51, 0xe9, 0xb8, 0xfe, 0xe1, 0x6d, 0x77, 0x5e, 0xaf,
+	0xfb, 0xf7, 0x5a, 0x5d, 0x3b, 0x49, 0x13, 0x5d, 0x84, 0x75, 0x7e, 0xac, 0xc3, 0x5a, 0x6e, 0x62,
+	0x15, 0xca, 0x11, 0x7d, 0xa6, 0xe0, 0x2b, 0xb1, 0xff, 0x90, 0x41, 0x4d, 0xf4, 0xb9, 0x75, 0xbe,
+	0x34, 0x86, 0x9f, 0x4a, 0x56, 0x42, 0x84, 0xba, 0x08, 0xc8, 0x72, 0x61, 0x5d, 0x14, 0x7a, 0xdc,
+	0x4a, 0xb6, 0x82, 0x07, 0xf0, 0xbf, 0x2f, 0xfd, 0x13, 0xa9, 0x4d, 0x5f, 0x85, 0x33, 0xf9, 0x3e,
+	0x64, 0x15, 0x77, 0xa0, 0x19, 0x72, 0xa5, 0x9d, 0x22, 0x63, 0xf9, 0x60, 0xc0, 0xad, 0x0a, 0x88,
+	0xad, 0xe5, 0xb2, 0x39, 0x23, 0xf1, 0x58, 0x5e, 0xc7, 0x57, 0x70, 0xa8, 0xe5, 0x97, 0x48, 0x1a,
+	0xeb, 0xb8, 0xe7, 0x69, 0x69, 0x8c, 0xeb, 0x05, 0xda, 0x59, 0xcd, 0xc9, 0x70, 0x51, 0x40, 0x1b,
+	0xf8, 0x0e, 0x5e, 0x73, 0x21, 0x64, 0x68, 0xdd, 0x53, 0x6c, 0x19, 0xdf, 0xc3, 0x1b, 0x4f, 0x8a,
+	0x81, 0x22, 0xf9, 0x24, 0x5c, 0xc1, 0x3d, 0x68, 0xcd, 0xa1, 0x45, 0xc7, 0x26, 0x6e, 0x03, 0x33,
+	0x92, 0xbc, 0x47, 0x2a, 0xe0, 0x21, 0x3c, 0xfb, 0x3d, 0xf7, 0x22, 0x50, 0xcd, 0x47, 0xb3, 0xf4,
+	0x49, 0x37, 0x1b, 0x20, 0xab, 0xfd, 0xd9, 0xcd, 0x85, 0x08, 0x22, 0xb2, 0x6c, 0x0b, 0x5f, 0xc2,
+	0xc1, 0xb2, 0x3b, 0x8c, 0x4e, 0x06, 0x4a, 0xb8, 0x7c, 0x2f, 0xac, 0x8e, 0x2d, 0x68, 0xcc, 0xf7,
+	0x31, 0xeb, 0x80, 0x35, 0xf2, 0xb4, 0x0b, 0x94, 0x71, 0x9c, 0x28, 0x88, 0x48, 0x48, 0x5f, 0x92,
+	0x65, 0x0c, 0x6b, 0x50, 0xd1, 0x72, 0xd6, 0x62, 0xb3, 0xd8, 0x68, 0xbe, 0x63, 0x45, 0x9e, 0x12,
+	0xdc, 0x06, 0x9a, 0x21, 0x76, 0xe0, 0x45, 0x18, 0x99, 0xbe, 0xa3, 0xc0, 0xaa, 0x9e, 0x12, 0xd3,
+	0xc2, 0x5a, 0x9e, 0x2a, 0x63, 0x75, 0x61, 0xb0, 0x56, 0x3e, 0xd7, 0x7f, 0x33, 0x4e, 0x4b, 0x13,
+	0x06, 0x64, 0x24, 0xdb, 0xc6, 0x7d, 0xd8, 0x5d, 0x86, 0x15, 0xf5, 0x02, 0xb6, 0x33, 0xed, 0x76,
+	0x39, 0xd1, 0xf4, 0x33, 0xbb, 0xd8, 0x84, 0xad, 0x69, 0x7f, 0x9e, 0x24, 0xab, 0xec, 0x19, 0xdb,
+	0xc3, 0x06, 0x54, 0x43, 0x45, 0xf7, 0x27, 0xd6, 0x3e, 0xdf, 0x28, 0xae, 0xfd, 0xe3, 0xaf, 0x01,
+	0x00, 0x3c, 0xb2, 0xad, 0xea, 0x8a, 0x03, 0x00, 0x00,
 }
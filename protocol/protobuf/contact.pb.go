@@ -75,14 +75,63 @@ func (m *ContactUpdate) GetProfileImage() string {
 	return ""
 }
 
+type ContactRequest struct {
+	Clock uint64 `protobuf:"varint,1,opt,name=clock,proto3" json:"clock,omitempty"`
+	// Text attached to the request
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContactRequest) Reset()         { *m = ContactRequest{} }
+func (m *ContactRequest) String() string { return proto.CompactTextString(m) }
+func (*ContactRequest) ProtoMessage()    {}
+func (*ContactRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5036fff2565fb15, []int{1}
+}
+
+func (m *ContactRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContactRequest.Unmarshal(m, b)
+}
+func (m *ContactRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContactRequest.Marshal(b, m, deterministic)
+}
+func (m *ContactRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContactRequest.Merge(m, src)
+}
+func (m *ContactRequest) XXX_Size() int {
+	return xxx_messageInfo_ContactRequest.Size(m)
+}
+func (m *ContactRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContactRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContactRequest proto.InternalMessageInfo
+
+func (m *ContactRequest) GetClock() uint64 {
+	if m != nil {
+		return m.Clock
+	}
+	return 0
+}
+
+func (m *ContactRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ContactUpdate)(nil), "protobuf.ContactUpdate")
+	proto.RegisterType((*ContactRequest)(nil), "protobuf.ContactRequest")
 }
 
 func init() { proto.RegisterFile("contact.proto", fileDescriptor_a5036fff2565fb15) }
 
 var fileDescriptor_a5036fff2565fb15 = []byte{
-	// 135 bytes of a gzipped FileDescriptorProto
+	// 160 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4d, 0xce, 0xcf, 0x2b,
 	0x49, 0x4c, 0x2e, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x00, 0x53, 0x49, 0xa5, 0x69,
 	0x4a, 0xa9, 0x5c, 0xbc, 0xce, 0x10, 0xa9, 0xd0, 0x82, 0x94, 0xc4, 0x92, 0x54, 0x21, 0x11, 0x2e,
@@ -90,6 +139,7 @@ var fileDescriptor_a5036fff2565fb15 = []byte{
 	0x92, 0x8b, 0x23, 0x35, 0xaf, 0x38, 0x3e, 0x2f, 0x31, 0x37, 0x55, 0x82, 0x49, 0x81, 0x51, 0x83,
 	0x33, 0x88, 0x3d, 0x35, 0xaf, 0xd8, 0x2f, 0x31, 0x37, 0x55, 0x48, 0x99, 0x8b, 0xb7, 0xa0, 0x28,
 	0x3f, 0x2d, 0x33, 0x27, 0x35, 0x3e, 0x33, 0x37, 0x31, 0x3d, 0x55, 0x82, 0x19, 0x2c, 0xcf, 0x03,
-	0x15, 0xf4, 0x04, 0x89, 0x25, 0xb1, 0x81, 0x2d, 0x34, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0x74,
-	0xf9, 0x5c, 0xff, 0x88, 0x00, 0x00, 0x00,
+	0x15, 0xf4, 0x04, 0x89, 0x29, 0x39, 0x70, 0xf1, 0x41, 0xad, 0x09, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d,
+	0x2e, 0xc1, 0x61, 0x8f, 0x04, 0x17, 0x7b, 0x6e, 0x6a, 0x71, 0x71, 0x62, 0x3a, 0xdc, 0x1a, 0x28,
+	0x37, 0x89, 0x0d, 0xec, 0x64, 0x63, 0xc0, 0x00, 0x99, 0x72, 0x94, 0xcc, 0xca, 0x00, 0x00, 0x00,
 }
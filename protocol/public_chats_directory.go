@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// PublicChatsDirectoryID is a well-known public chat used to announce
+// public chats users participate in.
+const PublicChatsDirectoryID = "status-public-chats-directory"
+
+// suggestedChannelsWindow is how long announcements are taken into account.
+const suggestedChannelsWindow = 30 * 24 * time.Hour
+
+// SuggestedChannel is a public chat observed in the public chats directory.
+type SuggestedChannel struct {
+	// Name is the name of the public chat
+	Name string `json:"name"`
+	// Count is the number of users that announced the chat
+	Count int `json:"count"`
+}
+
+// PublishPublicChats announces active public chats to the public chats directory.
+func (m *Messenger) PublishPublicChats(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.publicChatsDirectoryEnabled {
+		return ErrPublicChatsDirectoryDisabled
+	}
+
+	var chats []string
+	for _, chat := range m.allChats {
+		if chat.Active && chat.Public() {
+			chats = append(chats, chat.ID)
+		}
+	}
+	if len(chats) == 0 {
+		return nil
+	}
+	if len(chats) > maxAnnouncedPublicChats {
+		chats = chats[:maxAnnouncedPublicChats]
+	}
+
+	m.publicChatsDirectoryClock++
+	if now := m.getTimesource().GetCurrentTime(); m.publicChatsDirectoryClock < now {
+		m.publicChatsDirectoryClock = now
+	}
+
+	encodedMessage, err := proto.Marshal(&protobuf.PublicChatsAnnouncement{
+		Clock: m.publicChatsDirectoryClock,
+		Chats: chats,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Debug("publishing public chats", zap.Int("count", len(chats)))
+	_, err = m.processor.SendPublicRaw(ctx, PublicChatsDirectoryID, encodedMessage, protobuf.ApplicationMetadataMessage_PUBLIC_CHATS_ANNOUNCEMENT)
+	return err
+}
+
+// SuggestedChannels returns public chats announced by other users ranked by
+// the number of users participating in them. Chats that have been already
+// joined are omitted.
+func (m *Messenger) SuggestedChannels(limit int) ([]*SuggestedChannel, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.publicChatsDirectoryEnabled {
+		return nil, ErrPublicChatsDirectoryDisabled
+	}
+
+	since := m.getTimesource().GetCurrentTime() - uint64(suggestedChannelsWindow/time.Millisecond)
+	channels, err := m.persistence.SuggestedChannels(since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*SuggestedChannel{}
+	for _, channel := range channels {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		if chat, ok := m.allChats[channel.Name]; ok && chat.Active {
+			continue
+		}
+		result = append(result, channel)
+	}
+
+	return result, nil
+}
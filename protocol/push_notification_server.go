@@ -0,0 +1,186 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/subtle"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// Errors returned to clients in a registration response.
+var (
+	errPushNotificationStaleVersion        = errors.New("registration version is not newer than the stored one")
+	errPushNotificationInvalidInstallation = errors.New("installation id can't be empty")
+	errPushNotificationInvalidToken        = errors.New("invalid device token")
+)
+
+// PushNotificationDelivery is a notification for a single device.
+type PushNotificationDelivery struct {
+	// TokenType is one of PushNotificationTokenAPN or PushNotificationTokenFirebase
+	TokenType   string
+	DeviceToken string
+	// MessageID is the hex encoded id of the message the device is notified about
+	MessageID string
+}
+
+// PushNotificationGateway delivers notifications to devices, e.g. with APNS and FCM.
+type PushNotificationGateway interface {
+	Push(notifications []*PushNotificationDelivery) error
+}
+
+// PushNotificationServerConfig configures the node to act as a push notification server.
+// Registrations of device tokens are accepted from any user and are stored encrypted.
+// Notifications are requested by contacts that hold an access token of an installation.
+type PushNotificationServerConfig struct {
+	Gateway PushNotificationGateway
+}
+
+type pushNotificationServer struct {
+	config PushNotificationServerConfig
+	// key encrypts stored registrations
+	key []byte
+}
+
+func newPushNotificationServer(config PushNotificationServerConfig, identity *ecdsa.PrivateKey) *pushNotificationServer {
+	return &pushNotificationServer{
+		config: config,
+		key:    crypto.Keccak256(crypto.FromECDSA(identity)),
+	}
+}
+
+// handlePushNotificationRegistration stores or removes a registration and responds to its sender.
+func (m *Messenger) handlePushNotificationRegistration(state *ReceivedMessageState, message protobuf.PushNotificationRegistration) error {
+	if m.pushNotificationServer == nil {
+		return nil
+	}
+	requestID, err := types.DecodeHex(state.CurrentMessageState.MessageID)
+	if err != nil {
+		return err
+	}
+	response := &protobuf.PushNotificationRegistrationResponse{RequestId: requestID}
+	if err := m.savePushNotificationServerRegistration(state.CurrentMessageState.PublicKey, &message); err != nil {
+		m.logger.Debug("push notification registration rejected", zap.Error(err))
+		response.Error = err.Error()
+	} else {
+		response.Success = true
+	}
+
+	encodedMessage, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = m.processor.SendPrivateRaw(context.Background(), state.CurrentMessageState.PublicKey, encodedMessage, protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION_RESPONSE)
+	return err
+}
+
+func (m *Messenger) savePushNotificationServerRegistration(publicKey *ecdsa.PublicKey, message *protobuf.PushNotificationRegistration) error {
+	if message.InstallationId == "" {
+		return errPushNotificationInvalidInstallation
+	}
+	if !message.Unregister {
+		if _, ok := protobuf.PushNotificationRegistration_TokenType_name[int32(message.TokenType)]; !ok ||
+			message.TokenType == protobuf.PushNotificationRegistration_UNKNOWN_TOKEN_TYPE ||
+			message.DeviceToken == "" || message.AccessToken == "" {
+			return errPushNotificationInvalidToken
+		}
+	}
+	version, err := m.persistence.PushNotificationServerRegistrationVersion(publicKey, message.InstallationId)
+	if err != nil {
+		return err
+	}
+	if message.Version <= version {
+		return errPushNotificationStaleVersion
+	}
+
+	// Unregistered installations are kept without tokens, to ignore older registrations
+	if message.Unregister {
+		message = &protobuf.PushNotificationRegistration{
+			InstallationId: message.InstallationId,
+			Version:        message.Version,
+			Unregister:     true,
+		}
+	}
+	encoded, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	encrypted, err := crypto.EncryptSymmetric(m.pushNotificationServer.key, encoded)
+	if err != nil {
+		return err
+	}
+	return m.persistence.SavePushNotificationServerRegistration(publicKey, message.InstallationId, message.Version, encrypted)
+}
+
+// handlePushNotificationRequest forwards notifications with valid access tokens to the gateway.
+func (m *Messenger) handlePushNotificationRequest(state *ReceivedMessageState, message protobuf.PushNotificationRequest) error {
+	if m.pushNotificationServer == nil {
+		return nil
+	}
+	requester := crypto.CompressPubkey(state.CurrentMessageState.PublicKey)
+	var deliveries []*PushNotificationDelivery
+	for _, notification := range message.Notifications {
+		registrations, err := m.persistence.PushNotificationServerRegistrations(notification.InstallationId)
+		if err != nil {
+			return err
+		}
+		for _, encrypted := range registrations {
+			registration, err := m.decryptPushNotificationServerRegistration(encrypted)
+			if err != nil {
+				m.logger.Warn("failed to decrypt push notification registration", zap.Error(err))
+				continue
+			}
+			if !pushNotificationAllowed(registration, notification.AccessToken, requester) {
+				continue
+			}
+			tokenType := PushNotificationTokenAPN
+			if registration.TokenType == protobuf.PushNotificationRegistration_FIREBASE_TOKEN {
+				tokenType = PushNotificationTokenFirebase
+			}
+			deliveries = append(deliveries, &PushNotificationDelivery{
+				TokenType:   tokenType,
+				DeviceToken: registration.DeviceToken,
+				MessageID:   types.EncodeHex(message.MessageId),
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return errors.New("no valid notifications in the request")
+	}
+	return m.pushNotificationServer.config.Gateway.Push(deliveries)
+}
+
+func (m *Messenger) decryptPushNotificationServerRegistration(encrypted []byte) (*protobuf.PushNotificationRegistration, error) {
+	decrypted, err := crypto.DecryptSymmetric(m.pushNotificationServer.key, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	var registration protobuf.PushNotificationRegistration
+	if err := proto.Unmarshal(decrypted, &registration); err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// pushNotificationAllowed checks the access token and the list of allowed users of a registration.
+func pushNotificationAllowed(registration *protobuf.PushNotificationRegistration, accessToken string, requester []byte) bool {
+	if registration.Unregister || accessToken == "" ||
+		subtle.ConstantTimeCompare([]byte(registration.AccessToken), []byte(accessToken)) != 1 {
+		return false
+	}
+	if len(registration.AllowedKeyList) == 0 {
+		return true
+	}
+	for _, key := range registration.AllowedKeyList {
+		if subtle.ConstantTimeCompare(key, requester) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,473 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	defaultPushNotificationsOfflineAfter = 5 * time.Minute
+	pushNotificationAccessTokenLength    = 16
+)
+
+// Types of device tokens.
+const (
+	PushNotificationTokenAPN      = "apn"
+	PushNotificationTokenFirebase = "firebase"
+)
+
+var pushNotificationTokenTypes = map[string]protobuf.PushNotificationRegistration_TokenType{
+	PushNotificationTokenAPN:      protobuf.PushNotificationRegistration_APN_TOKEN,
+	PushNotificationTokenFirebase: protobuf.PushNotificationRegistration_FIREBASE_TOKEN,
+}
+
+// Statuses of a push notification registration.
+const (
+	PushNotificationRegistrationPending      = "pending"
+	PushNotificationRegistrationRegistered   = "registered"
+	PushNotificationRegistrationFailed       = "failed"
+	PushNotificationRegistrationUnregistered = "unregistered"
+)
+
+var (
+	// ErrPushNotificationsDisabled is returned when registering without WithPushNotifications option.
+	ErrPushNotificationsDisabled = errors.New("push notifications are disabled")
+	// ErrUnknownPushNotificationTokenType is returned for token types other than apn and firebase.
+	ErrUnknownPushNotificationTokenType = errors.New("unknown push notification token type")
+	// ErrNotRegisteredForPushNotifications is returned when unregistering an installation that is not registered.
+	ErrNotRegisteredForPushNotifications = errors.New("not registered for push notifications")
+)
+
+// PushNotificationRegistration is a registration of a device token of this installation
+// with a push notification server.
+type PushNotificationRegistration struct {
+	InstallationID string `json:"installationId"`
+	// Server is the hex encoded public key of the push notification server
+	Server      string `json:"server"`
+	TokenType   string `json:"tokenType"`
+	DeviceToken string `json:"deviceToken"`
+	Version     uint64 `json:"version"`
+	Status      string `json:"status"`
+	// Error is the reason of a registration rejected by the server
+	Error string `json:"error,omitempty"`
+
+	accessToken string
+	requestID   string
+}
+
+// pushNotificationInfo is shared by a contact so that notifications can be requested
+// for an installation of the contact.
+type pushNotificationInfo struct {
+	installationID string
+	server         *ecdsa.PublicKey
+	accessToken    string
+	version        uint64
+}
+
+// PushNotificationsHandler is notified about changes of the registration.
+type PushNotificationsHandler interface {
+	PushNotificationRegistrationChanged(registration PushNotificationRegistration)
+}
+
+// PushNotificationsConfig configures the push notification client. Device tokens are
+// registered with the server, contacts receive an access token that allows them to ask
+// the server to notify this installation.
+type PushNotificationsConfig struct {
+	// Server is the public key of the push notification server.
+	Server *ecdsa.PublicKey
+	// OfflineAfter is the time since the last message received from a contact after
+	// which the contact is considered offline and notified. 5 minutes is used if not set.
+	OfflineAfter time.Duration
+	Handler      PushNotificationsHandler
+}
+
+// pushNotifications keeps track of contacts that are online.
+type pushNotifications struct {
+	config PushNotificationsConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+func newPushNotifications(config PushNotificationsConfig) *pushNotifications {
+	if config.OfflineAfter == 0 {
+		config.OfflineAfter = defaultPushNotificationsOfflineAfter
+	}
+	return &pushNotifications{
+		config:   config,
+		now:      time.Now,
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// seen records that a message was received from the contact.
+func (p *pushNotifications) seen(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastSeen[id] = p.now()
+}
+
+// isOffline returns true if no message was received from the contact recently.
+func (p *pushNotifications) isOffline(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last, ok := p.lastSeen[id]
+	return !ok || p.now().Sub(last) >= p.config.OfflineAfter
+}
+
+func (p *pushNotifications) notify(registration *PushNotificationRegistration) {
+	if p.config.Handler != nil {
+		p.config.Handler.PushNotificationRegistrationChanged(*registration)
+	}
+}
+
+func newPushNotificationAccessToken() (string, error) {
+	token := make([]byte, pushNotificationAccessTokenLength)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// RegisterForPushNotifications registers the device token of this installation with
+// the push notification server and shares an access token with added contacts.
+// Registering a different token or with a different server rotates the access token,
+// so that contacts holding the previous one can't request notifications anymore.
+func (m *Messenger) RegisterForPushNotifications(ctx context.Context, tokenType, deviceToken string) (*PushNotificationRegistration, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.pushNotifications == nil {
+		return nil, ErrPushNotificationsDisabled
+	}
+	t, ok := pushNotificationTokenTypes[tokenType]
+	if !ok {
+		return nil, ErrUnknownPushNotificationTokenType
+	}
+	if deviceToken == "" {
+		return nil, errors.New("device token can't be empty")
+	}
+
+	previous, err := m.persistence.PushNotificationRegistration(m.installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	registration := &PushNotificationRegistration{
+		InstallationID: m.installationID,
+		Server:         types.EncodeHex(crypto.FromECDSAPub(m.pushNotifications.config.Server)),
+		TokenType:      tokenType,
+		DeviceToken:    deviceToken,
+		Version:        1,
+		Status:         PushNotificationRegistrationPending,
+	}
+	if previous != nil {
+		registration.Version = previous.Version + 1
+		if previous.Status != PushNotificationRegistrationUnregistered && previous.Server == registration.Server &&
+			previous.TokenType == tokenType && previous.DeviceToken == deviceToken {
+			registration.accessToken = previous.accessToken
+		}
+	}
+	if registration.accessToken == "" {
+		registration.accessToken, err = newPushNotificationAccessToken()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	encodedMessage, err := proto.Marshal(&protobuf.PushNotificationRegistration{
+		TokenType:      t,
+		DeviceToken:    deviceToken,
+		InstallationId: m.installationID,
+		AccessToken:    registration.accessToken,
+		Version:        registration.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := m.processor.SendPrivateRaw(ctx, m.pushNotifications.config.Server, encodedMessage, protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION)
+	if err != nil {
+		return nil, err
+	}
+	registration.requestID = types.EncodeHex(id)
+
+	if err := m.persistence.SavePushNotificationRegistration(registration); err != nil {
+		return nil, err
+	}
+	m.sharePushNotificationInfo(ctx, registration, m.pushNotificationContacts())
+	return registration, nil
+}
+
+// UnregisterFromPushNotifications removes the device token of this installation from the
+// push notification server and revokes the access token shared with contacts.
+func (m *Messenger) UnregisterFromPushNotifications(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.pushNotifications == nil {
+		return ErrPushNotificationsDisabled
+	}
+	registration, err := m.persistence.PushNotificationRegistration(m.installationID)
+	if err != nil {
+		return err
+	}
+	if registration == nil || registration.Status == PushNotificationRegistrationUnregistered {
+		return ErrNotRegisteredForPushNotifications
+	}
+	server, err := registration.serverPublicKey()
+	if err != nil {
+		return err
+	}
+
+	registration.Version++
+	encodedMessage, err := proto.Marshal(&protobuf.PushNotificationRegistration{
+		InstallationId: m.installationID,
+		AccessToken:    registration.accessToken,
+		Version:        registration.Version,
+		Unregister:     true,
+	})
+	if err != nil {
+		return err
+	}
+	id, err := m.processor.SendPrivateRaw(ctx, server, encodedMessage, protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION)
+	if err != nil {
+		return err
+	}
+
+	registration.requestID = types.EncodeHex(id)
+	registration.accessToken = ""
+	registration.Status = PushNotificationRegistrationUnregistered
+	registration.Error = ""
+	if err := m.persistence.SavePushNotificationRegistration(registration); err != nil {
+		return err
+	}
+	m.sharePushNotificationInfo(ctx, registration, m.pushNotificationContacts())
+	m.pushNotifications.notify(registration)
+	return nil
+}
+
+// PushNotificationRegistration returns the registration of this installation, nil if it was never registered.
+func (m *Messenger) PushNotificationRegistration() (*PushNotificationRegistration, error) {
+	if m.pushNotifications == nil {
+		return nil, ErrPushNotificationsDisabled
+	}
+	return m.persistence.PushNotificationRegistration(m.installationID)
+}
+
+func (r *PushNotificationRegistration) serverPublicKey() (*ecdsa.PublicKey, error) {
+	b, err := types.DecodeHex(r.Server)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPubkey(b)
+}
+
+// pushNotificationContacts returns public keys of contacts that were added and are not blocked.
+func (m *Messenger) pushNotificationContacts() []*ecdsa.PublicKey {
+	var contacts []*ecdsa.PublicKey
+	for _, contact := range m.allContacts {
+		if !contact.IsAdded() || contact.IsBlocked() || contact.ID == contactIDFromPublicKey(&m.identity.PublicKey) {
+			continue
+		}
+		publicKey, err := contact.PublicKey()
+		if err != nil {
+			continue
+		}
+		contacts = append(contacts, publicKey)
+	}
+	return contacts
+}
+
+// sharePushNotificationInfo sends the access token of the registration to the contacts.
+// An unregistered installation is shared with an empty access token. Failures are logged,
+// contacts receive the info again with the next registration.
+func (m *Messenger) sharePushNotificationInfo(ctx context.Context, registration *PushNotificationRegistration, contacts []*ecdsa.PublicKey) {
+	server, err := registration.serverPublicKey()
+	if err != nil {
+		m.logger.Error("invalid push notification server", zap.Error(err))
+		return
+	}
+	encodedMessage, err := proto.Marshal(&protobuf.PushNotificationInfo{
+		ServerPublicKey: crypto.CompressPubkey(server),
+		InstallationId:  registration.InstallationID,
+		AccessToken:     registration.accessToken,
+		Version:         registration.Version,
+	})
+	if err != nil {
+		m.logger.Error("failed to encode push notification info", zap.Error(err))
+		return
+	}
+	for _, contact := range contacts {
+		if _, err := m.processor.SendPrivateRaw(ctx, contact, encodedMessage, protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_INFO); err != nil {
+			m.logger.Warn("failed to share push notification info", zap.Error(err))
+		}
+	}
+}
+
+// sharePushNotificationInfoWith sends the access token of the current registration to a contact.
+func (m *Messenger) sharePushNotificationInfoWith(ctx context.Context, contact *ecdsa.PublicKey) {
+	if m.pushNotifications == nil {
+		return
+	}
+	registration, err := m.persistence.PushNotificationRegistration(m.installationID)
+	if err != nil {
+		m.logger.Error("failed to load push notification registration", zap.Error(err))
+		return
+	}
+	if registration == nil || registration.Status == PushNotificationRegistrationUnregistered {
+		return
+	}
+	m.sharePushNotificationInfo(ctx, registration, []*ecdsa.PublicKey{contact})
+}
+
+// notifyOfflineChatMembers requests push notifications for members of a one-to-one
+// or a private group chat about a message sent to the chat.
+func (m *Messenger) notifyOfflineChatMembers(ctx context.Context, chat *Chat, messageID []byte) {
+	if m.pushNotifications == nil {
+		return
+	}
+	var (
+		recipients []*ecdsa.PublicKey
+		err        error
+	)
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		var publicKey *ecdsa.PublicKey
+		publicKey, err = chat.PublicKey()
+		recipients = []*ecdsa.PublicKey{publicKey}
+	case ChatTypePrivateGroupChat:
+		recipients, err = chat.MembersAsPublicKeys()
+	default:
+		return
+	}
+	if err != nil {
+		m.logger.Error("failed to get recipients of push notifications", zap.Error(err))
+		return
+	}
+	m.sendPushNotifications(ctx, recipients, messageID)
+}
+
+// sendPushNotifications asks servers of the offline recipients to notify their installations
+// about a new message. Failures are logged, the message itself was already sent.
+func (m *Messenger) sendPushNotifications(ctx context.Context, recipients []*ecdsa.PublicKey, messageID []byte) {
+	requests := make(map[string]*protobuf.PushNotificationRequest)
+	servers := make(map[string]*ecdsa.PublicKey)
+	for _, recipient := range recipients {
+		id := contactIDFromPublicKey(recipient)
+		if isPubKeyEqual(recipient, &m.identity.PublicKey) || !m.pushNotifications.isOffline(id) {
+			continue
+		}
+		infos, err := m.persistence.PushNotificationInfos(id)
+		if err != nil {
+			m.logger.Error("failed to load push notification info", zap.Error(err))
+			return
+		}
+		for _, info := range infos {
+			if info.accessToken == "" {
+				continue
+			}
+			server := types.EncodeHex(crypto.FromECDSAPub(info.server))
+			if _, ok := requests[server]; !ok {
+				requests[server] = &protobuf.PushNotificationRequest{MessageId: messageID}
+				servers[server] = info.server
+			}
+			requests[server].Notifications = append(requests[server].Notifications, &protobuf.PushNotification{
+				InstallationId: info.installationID,
+				AccessToken:    info.accessToken,
+			})
+		}
+	}
+	for server, request := range requests {
+		encodedMessage, err := proto.Marshal(request)
+		if err != nil {
+			m.logger.Error("failed to encode push notification request", zap.Error(err))
+			return
+		}
+		if _, err := m.processor.SendPrivateRaw(ctx, servers[server], encodedMessage, protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REQUEST); err != nil {
+			m.logger.Warn("failed to send push notification request", zap.String("server", server), zap.Error(err))
+		}
+	}
+}
+
+// handlePushNotificationRegistrationResponse updates the status of the registration
+// the response was sent for.
+func (m *Messenger) handlePushNotificationRegistrationResponse(state *ReceivedMessageState, message protobuf.PushNotificationRegistrationResponse) error {
+	if m.pushNotifications == nil {
+		return nil
+	}
+	registration, err := m.persistence.PushNotificationRegistration(m.installationID)
+	if err != nil {
+		return err
+	}
+	if registration == nil || registration.requestID != types.EncodeHex(message.RequestId) {
+		return errors.New("response to an unknown registration")
+	}
+	if state.CurrentMessageState.Contact.ID != registration.Server {
+		return errors.New("response not sent by the push notification server")
+	}
+	if registration.Status != PushNotificationRegistrationPending {
+		return nil
+	}
+
+	if message.Success {
+		registration.Status = PushNotificationRegistrationRegistered
+		registration.Error = ""
+	} else {
+		registration.Status = PushNotificationRegistrationFailed
+		registration.Error = message.Error
+	}
+	if err := m.persistence.SavePushNotificationRegistration(registration); err != nil {
+		return err
+	}
+	m.pushNotifications.notify(registration)
+	return nil
+}
+
+// handlePushNotificationInfo stores an access token shared by an added contact.
+// An info with an empty access token revokes the installation of the contact.
+func (m *Messenger) handlePushNotificationInfo(state *ReceivedMessageState, message protobuf.PushNotificationInfo) error {
+	if m.pushNotifications == nil {
+		return nil
+	}
+	contact := state.CurrentMessageState.Contact
+	if !contact.IsAdded() || contact.ID == contactIDFromPublicKey(&m.identity.PublicKey) {
+		return errors.New("push notification info not sent by a contact")
+	}
+	if err := ValidateReceivedPushNotificationInfo(&message); err != nil {
+		return err
+	}
+	server, err := crypto.DecompressPubkey(message.ServerPublicKey)
+	if err != nil {
+		return err
+	}
+
+	infos, err := m.persistence.PushNotificationInfos(contact.ID)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if info.installationID == message.InstallationId && info.version >= message.Version {
+			return nil
+		}
+	}
+	// revoked installations are kept with an empty access token to ignore older infos
+	return m.persistence.SavePushNotificationInfo(contact.ID, &pushNotificationInfo{
+		installationID: message.InstallationId,
+		server:         server,
+		accessToken:    message.AccessToken,
+		version:        message.Version,
+	})
+}
@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// ErrRawEnvelopeKeys is returned when a raw envelope has none or both of encryption keys.
+var ErrRawEnvelopeKeys = errors.New("exactly one of symmetric key and public key must be set")
+
+// RawEnvelopeOptions configures an envelope sent with SendRawEnvelope.
+// Zero TTL, PowTarget and PowTime are replaced with defaults used for other messages.
+type RawEnvelopeOptions struct {
+	TTL       uint32
+	PowTarget float64
+	PowTime   uint32
+	// SymKey is a raw symmetric key used to encrypt the envelope.
+	SymKey []byte
+	// PublicKey is a recipient key used to encrypt the envelope.
+	PublicKey *ecdsa.PublicKey
+}
+
+// SendRawEnvelope sends a payload as is on a given topic. The payload is not wrapped
+// nor encrypted by the protocol, only the envelope encryption is applied.
+// Returned hash of the envelope is tracked and reported with envelope events.
+func (m *Messenger) SendRawEnvelope(ctx context.Context, topic types.TopicType, payload []byte, opts RawEnvelopeOptions) ([]byte, error) {
+	if (len(opts.SymKey) == 0) == (opts.PublicKey == nil) {
+		return nil, ErrRawEnvelopeKeys
+	}
+
+	newMessage := &types.NewMessage{
+		TTL:       opts.TTL,
+		Topic:     topic,
+		Payload:   payload,
+		PowTarget: opts.PowTarget,
+		PowTime:   opts.PowTime,
+	}
+	if newMessage.TTL == 0 {
+		newMessage.TTL = whisperTTL
+	}
+	if newMessage.PowTarget == 0 {
+		newMessage.PowTarget = whisperPoW
+	}
+	if newMessage.PowTime == 0 {
+		newMessage.PowTime = whisperPoWTime
+	}
+	if opts.PublicKey != nil {
+		newMessage.PublicKey = crypto.FromECDSAPub(opts.PublicKey)
+	}
+
+	hash, err := m.transport.SendRaw(ctx, newMessage, opts.SymKey)
+	if err != nil {
+		return nil, err
+	}
+
+	m.transport.Track([][]byte{hash}, hash, newMessage)
+
+	return hash, nil
+}
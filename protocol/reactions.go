@@ -0,0 +1,84 @@
+package protocol
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// ReactionSummary aggregates reactions of the same kind to a single message.
+type ReactionSummary struct {
+	// Reaction is the reaction, usually an emoji
+	Reaction string `json:"reaction"`
+	// Count is the number of users that reacted
+	Count int `json:"count"`
+	// Authors are public keys of users that reacted
+	Authors []string `json:"authors"`
+}
+
+// SendReaction sends a reaction to a message to the chat the message belongs to.
+func (m *Messenger) SendReaction(ctx context.Context, chatID, messageID, reaction string) error {
+	return m.sendReaction(ctx, chatID, messageID, reaction, false)
+}
+
+// RetractReaction removes a reaction previously sent with SendReaction.
+func (m *Messenger) RetractReaction(ctx context.Context, chatID, messageID, reaction string) error {
+	return m.sendReaction(ctx, chatID, messageID, reaction, true)
+}
+
+func (m *Messenger) sendReaction(ctx context.Context, chatID, messageID, reaction string, retracted bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	chat, ok := m.allChats[chatID]
+	if !ok {
+		return errors.New("Chat not found")
+	}
+
+	var messageType protobuf.ChatMessage_MessageType
+	switch chat.ChatType {
+	case ChatTypeOneToOne:
+		messageType = protobuf.ChatMessage_ONE_TO_ONE
+	case ChatTypePublic:
+		messageType = protobuf.ChatMessage_PUBLIC_GROUP
+	default:
+		return ErrReactionsNotSupported
+	}
+
+	clock, _ := chat.NextClockAndTimestamp(m.getTimesource())
+	message := &protobuf.Reaction{
+		Clock:       clock,
+		ChatId:      chat.ID,
+		MessageId:   messageID,
+		MessageType: messageType,
+		Reaction:    reaction,
+		Retracted:   retracted,
+	}
+	if err := ValidateReceivedReaction(message, clock); err != nil {
+		return err
+	}
+
+	encodedMessage, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.dispatchMessage(ctx, &RawMessage{
+		LocalChatID: chat.ID,
+		Payload:     encodedMessage,
+		MessageType: protobuf.ApplicationMetadataMessage_REACTION,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.persistence.SaveReaction(chat.ID, messageID, contactIDFromPublicKey(&m.identity.PublicKey), reaction, clock, retracted)
+}
+
+// Reactions returns reactions to the given messages of a chat aggregated by message ID.
+func (m *Messenger) Reactions(chatID string, messageIDs []string) (map[string][]*ReactionSummary, error) {
+	return m.persistence.Reactions(chatID, messageIDs)
+}
@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// communityChannelSeparator separates a community ID and a channel ID in a chat ID.
+const communityChannelSeparator = "/"
+
+// ErrUnauthorizedChannel is returned when a filter is requested for a community channel
+// without a descriptor signed by the community master key.
+var ErrUnauthorizedChannel = errors.New("channel is not authorized by the community")
+
+// ChannelDescriptor describes a channel of a community. It is signed
+// by the community master key, so only channels created by the owner
+// of the community are accepted.
+type ChannelDescriptor struct {
+	// CommunityID is the hex encoded public key of the community master key.
+	CommunityID string `json:"communityId"`
+	ChannelID   string `json:"channelId"`
+	// Clock is a clock value of the descriptor, a newer descriptor replaces an older one.
+	Clock     uint64         `json:"clock"`
+	Signature types.HexBytes `json:"signature"`
+}
+
+// NewChannelDescriptor creates a channel descriptor signed by the community master key.
+func NewChannelDescriptor(communityKey *ecdsa.PrivateKey, channelID string, clock uint64) (*ChannelDescriptor, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID can't be blank")
+	}
+	d := &ChannelDescriptor{
+		CommunityID: PublicKeyToStr(&communityKey.PublicKey),
+		ChannelID:   channelID,
+		Clock:       clock,
+	}
+	signature, err := crypto.Sign(d.hash(), communityKey)
+	if err != nil {
+		return nil, err
+	}
+	d.Signature = signature
+	return d, nil
+}
+
+func (d *ChannelDescriptor) hash() []byte {
+	var clock [8]byte
+	binary.BigEndian.PutUint64(clock[:], d.Clock)
+	return crypto.Keccak256([]byte(d.CommunityID), []byte{0}, []byte(d.ChannelID), clock[:])
+}
+
+// Verify checks that the descriptor is signed by the community master key.
+func (d *ChannelDescriptor) Verify() error {
+	if d.ChannelID == "" || len(d.Signature) == 0 {
+		return ErrUnauthorizedChannel
+	}
+	signer, err := crypto.SigToPub(d.hash(), d.Signature)
+	if err != nil || PublicKeyToStr(signer) != d.CommunityID {
+		return ErrUnauthorizedChannel
+	}
+	return nil
+}
+
+// ChatID returns an ID of the chat of the channel.
+func (d *ChannelDescriptor) ChatID() string {
+	return CommunityChannelChatID(d.CommunityID, d.ChannelID)
+}
+
+// CommunityChannelChatID returns an ID of the chat of a community channel.
+func CommunityChannelChatID(communityID, channelID string) string {
+	return communityID + communityChannelSeparator + channelID
+}
+
+// IsCommunityChannelChatID returns true if the chat ID belongs to a community channel.
+func IsCommunityChannelChatID(chatID string) bool {
+	parts := strings.SplitN(chatID, communityChannelSeparator, 2)
+	if len(parts) != 2 {
+		return false
+	}
+	_, err := StrToPublicKey(parts[0])
+	return err == nil
+}
@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+)
+
+func TestChannelDescriptorVerify(t *testing.T) {
+	communityKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	descriptor, err := NewChannelDescriptor(communityKey, "general", 1)
+	require.NoError(t, err)
+	require.NoError(t, descriptor.Verify())
+	require.True(t, IsCommunityChannelChatID(descriptor.ChatID()))
+
+	changed := *descriptor
+	changed.ChannelID = "random"
+	require.Equal(t, ErrUnauthorizedChannel, changed.Verify())
+
+	changed = *descriptor
+	changed.Clock = 2
+	require.Equal(t, ErrUnauthorizedChannel, changed.Verify())
+
+	// signed by another key
+	forged, err := NewChannelDescriptor(otherKey, "general", 1)
+	require.NoError(t, err)
+	forged.CommunityID = descriptor.CommunityID
+	require.Equal(t, ErrUnauthorizedChannel, forged.Verify())
+
+	unsigned := *descriptor
+	unsigned.Signature = nil
+	require.Equal(t, ErrUnauthorizedChannel, unsigned.Verify())
+}
+
+func TestIsCommunityChannelChatID(t *testing.T) {
+	require.False(t, IsCommunityChannelChatID("status"))
+	require.False(t, IsCommunityChannelChatID("status/general"))
+	require.False(t, IsCommunityChannelChatID("0xabc/general"))
+}
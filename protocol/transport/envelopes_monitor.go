@@ -11,7 +11,10 @@ type EnvelopesMonitorConfig struct {
 	MaxAttempts                    int
 	MailserverConfirmationsEnabled bool
 	IsMailserver                   func(types.EnodeID) bool
-	Logger                         *zap.Logger
+	// Outbox receives messages that were not delivered after MaxAttempts.
+	// If not set, such messages are reported as expired.
+	Outbox Outbox
+	Logger *zap.Logger
 }
 
 // EnvelopeEventsHandler used for two different event types.
@@ -21,6 +21,8 @@ type Filter struct {
 	Discovery bool `json:"discovery"`
 	// Negotiated tells us whether is a negotiated topic
 	Negotiated bool `json:"negotiated"`
+	// CommunityID is the hex encoded public key of the community master key for community channels
+	CommunityID string `json:"communityId,omitempty"`
 	// Listen is whether we are actually listening for messages on this chat, or the filter is only created in order to be able to post on the topic
 	Listen bool `json:"listen"`
 }
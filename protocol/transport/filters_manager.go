@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -35,6 +36,8 @@ type FiltersService interface {
 	GetSymKey(id string) ([]byte, error)
 	DeleteSymKey(id string) bool
 
+	GetPrivateKey(id string) (*ecdsa.PrivateKey, error)
+
 	Subscribe(opts *types.SubscriptionOptions) (string, error)
 	Unsubscribe(id string) error
 }
@@ -47,6 +50,7 @@ type FiltersManager struct {
 	logger      *zap.Logger
 	mutex       sync.Mutex
 	filters     map[string]*Filter
+	partitions  PartitionsConfig
 }
 
 // NewFiltersManager returns a new filtersManager.
@@ -85,7 +89,9 @@ func (s *FiltersManager) Init(
 	}
 
 	// Load partitioned topic.
+	s.mutex.Lock()
 	_, err = s.loadMyPartitioned()
+	s.mutex.Unlock()
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +141,8 @@ func (s *FiltersManager) InitWithFilters(filters []*Filter) ([]*Filter, error) {
 				return nil, err
 			}
 			publicKeys = append(publicKeys, publicKey)
-		} else if filter.ChatID != "" {
+		} else if filter.ChatID != "" && !IsCommunityChannelChatID(filter.ChatID) {
+			// community channels are loaded with their descriptors
 			chatIDs = append(chatIDs, filter.ChatID)
 		}
 	}
@@ -143,7 +150,8 @@ func (s *FiltersManager) InitWithFilters(filters []*Filter) ([]*Filter, error) {
 	return s.Init(chatIDs, publicKeys)
 }
 
-func (s *FiltersManager) Reset() error {
+// RemoveAll removes all filters.
+func (s *FiltersManager) RemoveAll() error {
 	var filters []*Filter
 
 	s.mutex.Lock()
@@ -155,6 +163,105 @@ func (s *FiltersManager) Reset() error {
 	return s.Remove(filters...)
 }
 
+// Reset switches filters to the key pair with a given ID. Filters that use our key pair
+// are re-installed with the new key and filters derived from our public key (discovery,
+// contact code and partitioned topics) are re-derived for the new identity.
+// Negotiated filters remain valid only if the identity is the same, otherwise they are removed.
+// Filters are replaced atomically: if any filter can't be installed, old filters are kept.
+func (s *FiltersManager) Reset(keyID string) ([]*Filter, error) {
+	privateKey, err := s.service.GetPrivateKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	oldPrivateKey := s.privateKey
+	oldFilters := s.filters
+	oldIdentity := PublicKeyToStr(&oldPrivateKey.PublicKey)
+	changed := oldIdentity != PublicKeyToStr(&privateKey.PublicKey)
+
+	s.privateKey = privateKey
+	s.filters = make(map[string]*Filter, len(oldFilters))
+
+	var obsolete []*Filter
+	err = func() error {
+		for chatID, f := range oldFilters {
+			switch {
+			case f.Negotiated:
+				if changed {
+					obsolete = append(obsolete, f)
+					continue
+				}
+			case f.SymKeyID == "":
+				// asymmetric filters are subscribed with our key pair
+				obsolete = append(obsolete, f)
+				if changed && f.Identity == oldIdentity {
+					continue
+				}
+				filter, err := s.addAsymmetric(chatID, f.Listen)
+				if err != nil {
+					return err
+				}
+				reinstalled := *f
+				reinstalled.FilterID = filter.FilterID
+				s.filters[chatID] = &reinstalled
+				continue
+			case changed && f.Identity == oldIdentity:
+				// our contact code
+				obsolete = append(obsolete, f)
+				continue
+			}
+			s.filters[chatID] = f
+		}
+
+		if !changed {
+			return nil
+		}
+		if _, err := s.loadContactCode(&s.privateKey.PublicKey); err != nil {
+			return err
+		}
+		if _, err := s.loadMyPartitioned(); err != nil {
+			return err
+		}
+		_, err := s.loadDiscovery()
+		return err
+	}()
+	if err != nil {
+		s.logger.Error("failed to reset filters", zap.Error(err))
+		// remove filters installed so far and restore the previous state
+		for chatID, f := range s.filters {
+			if old, ok := oldFilters[chatID]; ok && old.FilterID == f.FilterID {
+				continue
+			}
+			s.unsubscribe(f)
+		}
+		s.privateKey = oldPrivateKey
+		s.filters = oldFilters
+		return nil, err
+	}
+
+	for _, f := range obsolete {
+		s.unsubscribe(f)
+	}
+
+	var allFilters []*Filter
+	for _, f := range s.filters {
+		allFilters = append(allFilters, f)
+	}
+	return allFilters, nil
+}
+
+func (s *FiltersManager) unsubscribe(f *Filter) {
+	if err := s.service.Unsubscribe(f.FilterID); err != nil {
+		s.logger.Warn("failed to unsubscribe filter", zap.String("chatID", f.ChatID), zap.Error(err))
+	}
+	if f.SymKeyID != "" {
+		s.service.DeleteSymKey(f.SymKeyID)
+	}
+}
+
 func (s *FiltersManager) Filters() (result []*Filter) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -217,20 +324,42 @@ func (s *FiltersManager) Remove(filters ...*Filter) error {
 	return nil
 }
 
-// LoadPartitioned creates a filter for a partitioned topic.
-func (s *FiltersManager) LoadPartitioned(publicKey *ecdsa.PublicKey) (*Filter, error) {
-	return s.loadPartitioned(publicKey, false)
-}
-
-func (s *FiltersManager) loadMyPartitioned() (*Filter, error) {
-	return s.loadPartitioned(&s.privateKey.PublicKey, true)
+// SetPartitionsConfig changes the number of partitioned topics.
+// It must be called before filters are initialized.
+func (s *FiltersManager) SetPartitionsConfig(config PartitionsConfig) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.partitions = config
 }
 
-func (s *FiltersManager) loadPartitioned(publicKey *ecdsa.PublicKey, listen bool) (*Filter, error) {
+// LoadPartitioned creates a filter for a partitioned topic
+// that should be used to send messages to the public key.
+func (s *FiltersManager) LoadPartitioned(publicKey *ecdsa.PublicKey) (*Filter, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	chatID := PartitionedTopic(publicKey)
+	count := s.partitions.sendCount(time.Now())
+	return s.loadPartitioned(publicKey, count, false)
+}
+
+// loadMyPartitioned creates filters for our partitioned topics.
+// During migration of the partitions count both partitions are listened to.
+// The caller must hold the mutex.
+func (s *FiltersManager) loadMyPartitioned() ([]*Filter, error) {
+	var filters []*Filter
+	for _, count := range s.partitions.listenCounts(time.Now()) {
+		filter, err := s.loadPartitioned(&s.privateKey.PublicKey, count, true)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// loadPartitioned creates a filter for a partitioned topic. The caller must hold the mutex.
+func (s *FiltersManager) loadPartitioned(publicKey *ecdsa.PublicKey, count int, listen bool) (*Filter, error) {
+	chatID := PartitionedTopicWithCount(publicKey, count)
 	if _, ok := s.filters[chatID]; ok {
 		return s.filters[chatID], nil
 	}
@@ -294,7 +423,11 @@ func (s *FiltersManager) LoadNegotiated(secret types.NegotiatedSecret) (*Filter,
 func (s *FiltersManager) LoadDiscovery() ([]*Filter, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	return s.loadDiscovery()
+}
 
+// loadDiscovery adds the personal discovery filter. The caller must hold the mutex.
+func (s *FiltersManager) loadDiscovery() ([]*Filter, error) {
 	personalDiscoveryTopic := PersonalDiscoveryTopic(&s.privateKey.PublicKey)
 
 	// Check if filters are already loaded.
@@ -334,8 +467,13 @@ func (s *FiltersManager) LoadDiscovery() ([]*Filter, error) {
 	return []*Filter{personalDiscoveryChat}, nil
 }
 
-// LoadPublic adds a filter for a public chat.
+// LoadPublic adds a filter for a public chat. Filters for community channels
+// must be loaded with LoadCommunityChannel.
 func (s *FiltersManager) LoadPublic(chatID string) (*Filter, error) {
+	if IsCommunityChannelChatID(chatID) {
+		return nil, ErrUnauthorizedChannel
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -362,11 +500,51 @@ func (s *FiltersManager) LoadPublic(chatID string) (*Filter, error) {
 	return chat, nil
 }
 
+// LoadCommunityChannel adds a filter for a channel of a community.
+// The descriptor of the channel must be signed by the community master key.
+func (s *FiltersManager) LoadCommunityChannel(descriptor *ChannelDescriptor) (*Filter, error) {
+	if err := descriptor.Verify(); err != nil {
+		s.logger.Warn("rejected unauthorized channel", zap.String("communityID", descriptor.CommunityID), zap.String("channelID", descriptor.ChannelID))
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chatID := descriptor.ChatID()
+	if chat, ok := s.filters[chatID]; ok {
+		return chat, nil
+	}
+
+	filterAndTopic, err := s.addSymmetric(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	chat := &Filter{
+		ChatID:      chatID,
+		FilterID:    filterAndTopic.FilterID,
+		SymKeyID:    filterAndTopic.SymKeyID,
+		Topic:       filterAndTopic.Topic,
+		CommunityID: descriptor.CommunityID,
+		Listen:      true,
+		OneToOne:    false,
+	}
+
+	s.filters[chatID] = chat
+
+	return chat, nil
+}
+
 // LoadContactCode creates a filter for the advertise topic for a given public key.
 func (s *FiltersManager) LoadContactCode(pubKey *ecdsa.PublicKey) (*Filter, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	return s.loadContactCode(pubKey)
+}
 
+// loadContactCode creates a contact code filter. The caller must hold the mutex.
+func (s *FiltersManager) loadContactCode(pubKey *ecdsa.PublicKey) (*Filter, error) {
 	chatID := ContactCodeTopic(pubKey)
 
 	if _, ok := s.filters[chatID]; ok {
@@ -391,6 +569,36 @@ func (s *FiltersManager) LoadContactCode(pubKey *ecdsa.PublicKey) (*Filter, erro
 	return chat, nil
 }
 
+// Keys returns a copy of the cached symmetric keys indexed by chat ID.
+func (s *FiltersManager) Keys() map[string][]byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make(map[string][]byte, len(s.keys))
+	for chatID, key := range s.keys {
+		keys[chatID] = key
+	}
+	return keys
+}
+
+// AddKeys persists symmetric keys, e.g. restored from a backup.
+// Keys already known for a chat ID are not overwritten.
+func (s *FiltersManager) AddKeys(keys map[string][]byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for chatID, key := range keys {
+		if _, ok := s.keys[chatID]; ok {
+			continue
+		}
+		if err := s.persistence.Add(chatID, key); err != nil {
+			return err
+		}
+		s.keys[chatID] = key
+	}
+	return nil
+}
+
 // addSymmetric adds a symmetric key filter
 func (s *FiltersManager) addSymmetric(chatID string) (*RawFilter, error) {
 	var symKeyID string
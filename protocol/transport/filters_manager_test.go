@@ -3,10 +3,12 @@ package transport
 import (
 	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	gethbridge "github.com/status-im/status-go/eth-node/bridge/geth"
 	"github.com/status-im/status-go/protocol/tt"
@@ -16,6 +18,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/whisper/v6"
 )
 
@@ -113,6 +116,144 @@ func (s *FiltersManagerSuite) TestPartitionedTopicWithDiscoveryDisabled() {
 	s.assertRequiredFilters()
 }
 
+func (s *FiltersManagerSuite) TestPartitionedTopicWithCustomCount() {
+	publicKey := &s.manager[0].privateKey.PublicKey
+	s.chats.SetPartitionsConfig(PartitionsConfig{Count: 100})
+
+	_, err := s.chats.Init(nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(3, len(s.chats.filters))
+
+	filter := s.chats.filters[PartitionedTopicWithCount(publicKey, 100)]
+	s.Require().NotNil(filter)
+	s.Require().True(filter.Listen)
+
+	other := &s.manager[1].privateKey.PublicKey
+	filter, err = s.chats.LoadPartitioned(other)
+	s.Require().NoError(err)
+	s.Require().Equal(PartitionedTopicWithCount(other, 100), filter.ChatID)
+}
+
+func (s *FiltersManagerSuite) TestPartitionedTopicMigration() {
+	publicKey := &s.manager[0].privateKey.PublicKey
+	s.chats.SetPartitionsConfig(PartitionsConfig{
+		Count:         100,
+		PreviousCount: DefaultPartitionsCount,
+		PreviousUntil: time.Now().Add(time.Hour),
+	})
+
+	_, err := s.chats.Init(nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(4, len(s.chats.filters), "It listens on both partitions")
+	s.assertRequiredFilters()
+	s.Require().NotNil(s.chats.filters[PartitionedTopicWithCount(publicKey, 100)])
+
+	other := &s.manager[1].privateKey.PublicKey
+	filter, err := s.chats.LoadPartitioned(other)
+	s.Require().NoError(err)
+	s.Require().Equal(PartitionedTopic(other), filter.ChatID, "It sends to the previous partition")
+}
+
+func (s *FiltersManagerSuite) TestPartitionedTopicMigrationFinished() {
+	s.chats.SetPartitionsConfig(PartitionsConfig{
+		Count:         100,
+		PreviousCount: DefaultPartitionsCount,
+		PreviousUntil: time.Now().Add(-time.Hour),
+	})
+
+	_, err := s.chats.Init(nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(3, len(s.chats.filters))
+	s.Require().Nil(s.chats.filters[PartitionedTopic(&s.manager[0].privateKey.PublicKey)])
+}
+
+func (s *FiltersManagerSuite) TestResetWithNewKey() {
+	_, err := s.chats.Init([]string{"status"}, nil)
+	s.Require().NoError(err)
+	other, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	sendFilter, err := s.chats.LoadPartitioned(&other.PublicKey)
+	s.Require().NoError(err)
+	negotiated, err := s.chats.LoadNegotiated(types.NegotiatedSecret{PublicKey: &other.PublicKey, Key: make([]byte, 32)})
+	s.Require().NoError(err)
+	public := s.chats.Filter("status")
+	oldDiscovery := s.chats.filters[PersonalDiscoveryTopic(&s.manager[0].privateKey.PublicKey)]
+
+	keyID, err := s.chats.service.AddKeyPair(s.manager[1].privateKey)
+	s.Require().NoError(err)
+	filters, err := s.chats.Reset(keyID)
+	s.Require().NoError(err)
+	s.Require().Len(filters, 5)
+
+	// filters of the old identity are uninstalled
+	s.Require().Nil(s.chats.filters[PersonalDiscoveryTopic(&s.manager[0].privateKey.PublicKey)])
+	s.Require().Nil(s.chats.filters[ContactCodeTopic(&s.manager[0].privateKey.PublicKey)])
+	s.Require().Nil(s.chats.filters[fmt.Sprintf("contact-discovery-%d", s.manager[0].partitionedTopic)])
+	s.Require().Nil(s.chats.GetNegotiated(&other.PublicKey))
+	s.Require().Error(s.chats.service.Unsubscribe(oldDiscovery.FilterID))
+	s.Require().Error(s.chats.service.Unsubscribe(negotiated.FilterID))
+
+	// filters of the new identity are installed
+	s.Require().NotNil(s.chats.filters[PersonalDiscoveryTopic(&s.manager[1].privateKey.PublicKey)])
+	s.Require().NotNil(s.chats.filters[ContactCodeTopic(&s.manager[1].privateKey.PublicKey)])
+	partitioned := s.chats.filters[fmt.Sprintf("contact-discovery-%d", s.manager[1].partitionedTopic)]
+	s.Require().NotNil(partitioned)
+	s.Require().True(partitioned.Listen)
+
+	// filters that don't depend on the identity are kept
+	s.Require().Equal(public, s.chats.Filter("status"))
+	reinstalled := s.chats.Filter(sendFilter.ChatID)
+	s.Require().NotNil(reinstalled)
+	s.Require().NotEqual(sendFilter.FilterID, reinstalled.FilterID)
+	s.Require().False(reinstalled.Listen)
+}
+
+func (s *FiltersManagerSuite) TestResetWithSameKey() {
+	_, err := s.chats.Init(nil, nil)
+	s.Require().NoError(err)
+	other, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	negotiated, err := s.chats.LoadNegotiated(types.NegotiatedSecret{PublicKey: &other.PublicKey, Key: make([]byte, 32)})
+	s.Require().NoError(err)
+
+	keyID, err := s.chats.service.AddKeyPair(s.manager[0].privateKey)
+	s.Require().NoError(err)
+	filters, err := s.chats.Reset(keyID)
+	s.Require().NoError(err)
+	s.Require().Len(filters, 4)
+	s.Require().Equal(negotiated, s.chats.GetNegotiated(&other.PublicKey))
+	s.assertRequiredFilters()
+}
+
+type failingFiltersService struct {
+	FiltersService
+	subscriptions int
+}
+
+func (s *failingFiltersService) Subscribe(opts *types.SubscriptionOptions) (string, error) {
+	if s.subscriptions == 0 {
+		return "", errors.New("failed to subscribe")
+	}
+	s.subscriptions--
+	return s.FiltersService.Subscribe(opts)
+}
+
+func (s *FiltersManagerSuite) TestResetKeepsFiltersOnError() {
+	_, err := s.chats.Init(nil, nil)
+	s.Require().NoError(err)
+	before := s.chats.Filters()
+
+	keyID, err := s.chats.service.AddKeyPair(s.manager[1].privateKey)
+	s.Require().NoError(err)
+	s.chats.service = &failingFiltersService{FiltersService: s.chats.service, subscriptions: 1}
+	_, err = s.chats.Reset(keyID)
+	s.Require().Error(err)
+
+	s.Require().ElementsMatch(before, s.chats.Filters())
+	s.Require().Equal(s.manager[0].privateKey, s.chats.privateKey)
+	s.assertRequiredFilters()
+}
+
 func (s *FiltersManagerSuite) assertRequiredFilters() {
 	partitionedTopic := fmt.Sprintf("contact-discovery-%d", s.manager[0].partitionedTopic)
 	personalDiscoveryTopic := fmt.Sprintf("contact-discovery-%s", s.manager[0].publicKeyString())
@@ -130,3 +271,27 @@ func (s *FiltersManagerSuite) assertRequiredFilters() {
 	s.Require().NotNil(partitionedFilter, "It adds the partitioned filter")
 	s.Require().True(partitionedFilter.Listen)
 }
+
+func (s *FiltersManagerSuite) TestLoadCommunityChannel() {
+	communityKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	descriptor, err := NewChannelDescriptor(communityKey, "general", 1)
+	s.Require().NoError(err)
+
+	filter, err := s.chats.LoadCommunityChannel(descriptor)
+	s.Require().NoError(err)
+	s.Require().Equal(descriptor.ChatID(), filter.ChatID)
+	s.Require().Equal(descriptor.CommunityID, filter.CommunityID)
+	s.Require().True(filter.Listen)
+	s.Require().Equal(filter, s.chats.Filter(descriptor.ChatID()))
+
+	// the channel can't be joined without a descriptor
+	_, err = s.chats.LoadPublic(CommunityChannelChatID(descriptor.CommunityID, "random"))
+	s.Require().Equal(ErrUnauthorizedChannel, err)
+
+	unauthorized := *descriptor
+	unauthorized.ChannelID = "random"
+	_, err = s.chats.LoadCommunityChannel(&unauthorized)
+	s.Require().Equal(ErrUnauthorizedChannel, err)
+	s.Require().Nil(s.chats.Filter(unauthorized.ChatID()))
+}
@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+var (
+	// ErrOutboxDisabled is returned when a message is postponed but the outbox is not configured.
+	ErrOutboxDisabled = errors.New("outbox is disabled")
+	// ErrMessageNotPostponable is returned for messages without a topic or an encryption key,
+	// for example when sending failed before the message was fully prepared.
+	ErrMessageNotPostponable = errors.New("message can't be postponed")
+)
+
+// OutboxMessage is a message that was not delivered and is retried later.
+// Keys are stored as raw bytes because IDs of keys added to Whisper
+// or Waku don't survive a restart.
+type OutboxMessage struct {
+	Identifiers [][]byte
+	Topic       types.TopicType
+	Payload     []byte
+	PublicKey   []byte
+	SymKey      []byte
+	TTL         uint32
+	PowTarget   float64
+	PowTime     uint32
+}
+
+// ID is the same for all attempts to deliver a message.
+func (m *OutboxMessage) ID() types.Hash {
+	return crypto.Keccak256Hash(m.Identifiers...)
+}
+
+// NewMessage returns a message to post. A symmetric key must be passed
+// to Transport.SendRaw separately.
+func (m *OutboxMessage) NewMessage() *types.NewMessage {
+	return &types.NewMessage{
+		TTL:       m.TTL,
+		Topic:     m.Topic,
+		Payload:   m.Payload,
+		PublicKey: m.PublicKey,
+		PowTarget: m.PowTarget,
+		PowTime:   m.PowTime,
+	}
+}
+
+// NewOutboxMessage converts a message that failed to be delivered. getSymKey returns
+// a symmetric key by its ID.
+func NewOutboxMessage(identifiers [][]byte, message *types.NewMessage, getSymKey func(id string) ([]byte, error)) (*OutboxMessage, error) {
+	if message == nil || message.Topic == (types.TopicType{}) {
+		return nil, ErrMessageNotPostponable
+	}
+	rst := &OutboxMessage{
+		Identifiers: identifiers,
+		Topic:       message.Topic,
+		Payload:     message.Payload,
+		TTL:         message.TTL,
+		PowTarget:   message.PowTarget,
+		PowTime:     message.PowTime,
+	}
+	switch {
+	case message.SymKeyID != "":
+		key, err := getSymKey(message.SymKeyID)
+		if err != nil {
+			return nil, err
+		}
+		rst.SymKey = key
+	case len(message.PublicKey) > 0:
+		rst.PublicKey = message.PublicKey
+	default:
+		return nil, ErrMessageNotPostponable
+	}
+	return rst, nil
+}
+
+// Outbox accepts messages that were not delivered to retry them later.
+type Outbox interface {
+	Enqueue(message *OutboxMessage, err error) error
+}
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"time"
+)
+
+// DefaultPartitionsCount is the number of partitioned topics used
+// if PartitionsConfig.Count is not set.
+const DefaultPartitionsCount = 5000
+
+// PartitionsConfig configures the number of partitioned topics. All clients
+// of a network must use the same config, otherwise they won't receive messages
+// sent to partitioned topics of each other.
+type PartitionsConfig struct {
+	// Count is the number of partitioned topics.
+	Count int
+	// PreviousCount is the number of partitioned topics used before Count was changed.
+	PreviousCount int
+	// PreviousUntil is the end of the migration from PreviousCount to Count.
+	// Until then messages are sent to previous partitions and both partitions are listened to,
+	// so that clients that have not updated yet are still reachable.
+	PreviousUntil time.Time
+}
+
+func (c PartitionsConfig) count() int {
+	if c.Count <= 0 {
+		return DefaultPartitionsCount
+	}
+	return c.Count
+}
+
+// migrating returns true if previous partitions are still in use.
+func (c PartitionsConfig) migrating(now time.Time) bool {
+	return c.PreviousCount > 0 && c.PreviousCount != c.count() && now.Before(c.PreviousUntil)
+}
+
+// sendCount returns the number of partitions used to send messages.
+func (c PartitionsConfig) sendCount(now time.Time) int {
+	if c.migrating(now) {
+		return c.PreviousCount
+	}
+	return c.count()
+}
+
+// listenCounts returns numbers of partitions that should be listened to.
+func (c PartitionsConfig) listenCounts(now time.Time) []int {
+	if c.migrating(now) {
+		return []int{c.count(), c.PreviousCount}
+	}
+	return []int{c.count()}
+}
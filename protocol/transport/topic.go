@@ -12,11 +12,6 @@ import (
 
 const discoveryTopic = "contact-discovery"
 
-var (
-	// The number of partitions.
-	nPartitions = big.NewInt(5000)
-)
-
 // ToTopic converts a string to a whisper topic.
 func ToTopic(s string) []byte {
 	return crypto.Keccak256([]byte(s))[:types.TopicLength]
@@ -39,10 +34,16 @@ func PersonalDiscoveryTopic(publicKey *ecdsa.PublicKey) string {
 }
 
 // PartitionedTopic returns the associated partitioned topic string
-// with the given public key.
+// with the given public key using the default number of partitions.
 func PartitionedTopic(publicKey *ecdsa.PublicKey) string {
+	return PartitionedTopicWithCount(publicKey, DefaultPartitionsCount)
+}
+
+// PartitionedTopicWithCount returns the associated partitioned topic string
+// with the given public key when topics are split into count partitions.
+func PartitionedTopicWithCount(publicKey *ecdsa.PublicKey, count int) string {
 	partition := big.NewInt(0)
-	partition.Mod(publicKey.X, nPartitions)
+	partition.Mod(publicKey.X, big.NewInt(int64(count)))
 	return "contact-discovery-" + strconv.FormatInt(partition.Int64(), 10)
 }
 
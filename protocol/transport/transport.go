@@ -16,11 +16,14 @@ type Transport interface {
 	LeaveGroup(publicKeys []*ecdsa.PublicKey) error
 	JoinPublic(chatID string) error
 	LeavePublic(chatID string) error
+	// JoinCommunityChannel adds a filter for a channel if its descriptor is signed by the community.
+	JoinCommunityChannel(descriptor *ChannelDescriptor) error
 	GetCurrentTime() uint64
 
 	SendPublic(ctx context.Context, newMessage *types.NewMessage, chatName string) ([]byte, error)
 	SendPrivateWithSharedSecret(ctx context.Context, newMessage *types.NewMessage, publicKey *ecdsa.PublicKey, secret []byte) ([]byte, error)
 	SendPrivateWithPartitioned(ctx context.Context, newMessage *types.NewMessage, publicKey *ecdsa.PublicKey) ([]byte, error)
+	SendRaw(ctx context.Context, newMessage *types.NewMessage, symKey []byte) ([]byte, error)
 	SendMessagesRequest(
 		ctx context.Context,
 		peerID []byte,
@@ -29,6 +32,8 @@ type Transport interface {
 	) (cursor []byte, err error)
 
 	Track(identifiers [][]byte, hash []byte, newMessage *types.NewMessage)
+	// Postpone passes a message that failed to be posted to the outbox.
+	Postpone(identifiers [][]byte, newMessage *types.NewMessage, err error) error
 
 	InitFilters(chatIDs []string, publicKeys []*ecdsa.PublicKey) ([]*Filter, error)
 	LoadFilters(filters []*Filter) ([]*Filter, error)
@@ -36,5 +41,7 @@ type Transport interface {
 	ResetFilters() error
 	Filters() []*Filter
 	ProcessNegotiatedSecret(secret types.NegotiatedSecret) (*Filter, error)
+	SymmetricKeys() map[string][]byte
+	ImportSymmetricKeys(keys map[string][]byte) error
 	RetrieveRawAll() (map[Filter][]*types.Message, error)
 }
@@ -50,6 +50,7 @@ func NewEnvelopesMonitor(w types.Waku, config transport.EnvelopesMonitorConfig)
 		handler:                config.EnvelopeEventsHandler,
 		mailServerConfirmation: config.MailserverConfirmationsEnabled,
 		maxAttempts:            config.MaxAttempts,
+		outbox:                 config.Outbox,
 		isMailserver:           config.IsMailserver,
 		logger:                 logger.With(zap.Namespace("EnvelopesMonitor")),
 
@@ -71,6 +72,7 @@ type EnvelopesMonitor struct {
 	handler                EnvelopeEventsHandler
 	mailServerConfirmation bool
 	maxAttempts            int
+	outbox                 transport.Outbox
 
 	mu        sync.Mutex
 	envelopes map[types.Hash]EnvelopeState
@@ -262,10 +264,8 @@ func (m *EnvelopesMonitor) handleEnvelopeFailure(hash types.Hash, err error) {
 			hex, err := m.api.Post(context.TODO(), *message)
 			if err != nil {
 				m.logger.Error("failed to retry sending message", zap.String("hash", hash.String()), zap.Int("attempt", attempt+1), zap.Error(err))
-				if m.handler != nil {
-					m.handler.EnvelopeExpired(identifiers, err)
-				}
-
+				m.postpone(identifiers, message, err)
+				return
 			}
 			envelopeID := types.BytesToHash(hex)
 			m.envelopes[envelopeID] = EnvelopePosted
@@ -274,13 +274,38 @@ func (m *EnvelopesMonitor) handleEnvelopeFailure(hash types.Hash, err error) {
 			m.identifiers[envelopeID] = identifiers
 		} else {
 			m.logger.Debug("envelope expired", zap.String("hash", hash.String()))
-			if m.handler != nil {
-				m.handler.EnvelopeExpired(identifiers, err)
-			}
+			m.postpone(identifiers, message, err)
 		}
 	}
 }
 
+// Postpone passes a message that was not delivered to the outbox.
+func (m *EnvelopesMonitor) Postpone(identifiers [][]byte, message *types.NewMessage, err error) error {
+	if m.outbox == nil {
+		return transport.ErrOutboxDisabled
+	}
+	outboxMessage, convErr := transport.NewOutboxMessage(identifiers, message, m.w.GetSymKey)
+	if convErr != nil {
+		return convErr
+	}
+	return m.outbox.Enqueue(outboxMessage, err)
+}
+
+// postpone reports a message as expired if it can't be passed to the outbox.
+func (m *EnvelopesMonitor) postpone(identifiers [][]byte, message *types.NewMessage, err error) {
+	postponeErr := m.Postpone(identifiers, message, err)
+	if postponeErr == nil {
+		m.logger.Debug("message postponed", zap.Int("identifiers", len(identifiers)))
+		return
+	}
+	if postponeErr != transport.ErrOutboxDisabled {
+		m.logger.Error("failed to postpone message", zap.Error(postponeErr))
+	}
+	if m.handler != nil {
+		m.handler.EnvelopeExpired(identifiers, err)
+	}
+}
+
 func (m *EnvelopesMonitor) handleEventEnvelopeReceived(event types.EnvelopeEvent) {
 	if m.mailServerConfirmation {
 		if !m.isMailserver(event.Peer) {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"database/sql"
+	"encoding/hex"
 	"sync"
 	"time"
 
@@ -29,6 +30,9 @@ type whisperServiceKeysManager struct {
 
 	passToSymKeyMutex sync.RWMutex
 	passToSymKeyCache map[string]string
+
+	symKeyMutex sync.Mutex
+	symKeyCache map[string]string
 }
 
 func (m *whisperServiceKeysManager) AddOrGetKeyPair(priv *ecdsa.PrivateKey) (string, error) {
@@ -54,12 +58,40 @@ func (m *whisperServiceKeysManager) AddOrGetSymKeyFromPassword(password string)
 	return id, nil
 }
 
+// AddOrGetSymKey returns an ID of a raw symmetric key, the key is added once.
+func (m *whisperServiceKeysManager) AddOrGetSymKey(key []byte) (string, error) {
+	m.symKeyMutex.Lock()
+	defer m.symKeyMutex.Unlock()
+
+	cacheKey := hex.EncodeToString(key)
+	if val, ok := m.symKeyCache[cacheKey]; ok {
+		return val, nil
+	}
+
+	id, err := m.shh.AddSymKeyDirect(key)
+	if err != nil {
+		return id, err
+	}
+
+	m.symKeyCache[cacheKey] = id
+
+	return id, nil
+}
+
 func (m *whisperServiceKeysManager) RawSymKey(id string) ([]byte, error) {
 	return m.shh.GetSymKey(id)
 }
 
 type Option func(*Transport) error
 
+// WithPartitionsConfig sets the number of partitioned topics.
+func WithPartitionsConfig(config transport.PartitionsConfig) Option {
+	return func(t *Transport) error {
+		t.filters.SetPartitionsConfig(config)
+		return nil
+	}
+}
+
 // Transport is a transport based on Whisper service.
 type Transport struct {
 	shh         types.Whisper
@@ -108,6 +140,7 @@ func NewTransport(
 			shh:               shh,
 			privateKey:        privateKey,
 			passToSymKeyCache: make(map[string]string),
+			symKeyCache:       make(map[string]string),
 		},
 		filters:     filtersManager,
 		mailservers: mailservers,
@@ -142,7 +175,7 @@ func (a *Transport) RemoveFilters(filters []*transport.Filter) error {
 }
 
 func (a *Transport) ResetFilters() error {
-	return a.filters.Reset()
+	return a.filters.RemoveAll()
 }
 
 func (a *Transport) ProcessNegotiatedSecret(secret types.NegotiatedSecret) (*transport.Filter, error) {
@@ -153,11 +186,24 @@ func (a *Transport) ProcessNegotiatedSecret(secret types.NegotiatedSecret) (*tra
 	return filter, nil
 }
 
+func (a *Transport) SymmetricKeys() map[string][]byte {
+	return a.filters.Keys()
+}
+
+func (a *Transport) ImportSymmetricKeys(keys map[string][]byte) error {
+	return a.filters.AddKeys(keys)
+}
+
 func (a *Transport) JoinPublic(chatID string) error {
 	_, err := a.filters.LoadPublic(chatID)
 	return err
 }
 
+func (a *Transport) JoinCommunityChannel(descriptor *transport.ChannelDescriptor) error {
+	_, err := a.filters.LoadCommunityChannel(descriptor)
+	return err
+}
+
 func (a *Transport) LeavePublic(chatID string) error {
 	chat := a.filters.Filter(chatID)
 	if chat != nil {
@@ -346,6 +392,25 @@ func (a *Transport) SendPrivateOnDiscovery(ctx context.Context, newMessage *type
 	return a.shhAPI.Post(ctx, *newMessage)
 }
 
+// SendRaw sends a message with a topic and keys set by the caller.
+// If symKey is not empty it is used for encryption instead of newMessage.PublicKey.
+func (a *Transport) SendRaw(ctx context.Context, newMessage *types.NewMessage, symKey []byte) ([]byte, error) {
+	if err := a.addSig(newMessage); err != nil {
+		return nil, err
+	}
+
+	if len(symKey) > 0 {
+		symKeyID, err := a.keysManager.AddOrGetSymKey(symKey)
+		if err != nil {
+			return nil, err
+		}
+		newMessage.SymKeyID = symKeyID
+		newMessage.PublicKey = nil
+	}
+
+	return a.shhAPI.Post(ctx, *newMessage)
+}
+
 func (a *Transport) addSig(newMessage *types.NewMessage) error {
 	sigID, err := a.keysManager.AddOrGetKeyPair(a.keysManager.privateKey)
 	if err != nil {
@@ -361,6 +426,14 @@ func (a *Transport) Track(identifiers [][]byte, hash []byte, newMessage *types.N
 	}
 }
 
+// Postpone passes a message that failed to be posted to the outbox.
+func (a *Transport) Postpone(identifiers [][]byte, newMessage *types.NewMessage, err error) error {
+	if a.envelopesMonitor == nil {
+		return transport.ErrOutboxDisabled
+	}
+	return a.envelopesMonitor.Postpone(identifiers, newMessage, err)
+}
+
 // GetCurrentTime returns the current unix timestamp in milliseconds
 func (a *Transport) GetCurrentTime() uint64 {
 	return uint64(a.shh.GetCurrentTime().UnixNano() / int64(time.Millisecond))
@@ -31,6 +31,10 @@ type StatusMessage struct {
 	TransportPayload []byte `json:"-"`
 	// DecryptedPayload is the payload after having been processed by the encryption layer
 	DecryptedPayload []byte `json:"decryptedPayload"`
+	// DecryptionFailed is set if the payload is an encrypted direct message that could not be decrypted
+	DecryptionFailed bool `json:"-"`
+	// InstallationID is the installation of the sender, set by the encryption layer
+	InstallationID string `json:"-"`
 
 	// ID is the canonical ID of the message
 	ID types.HexBytes `json:"id"`
@@ -100,6 +104,7 @@ func (m *StatusMessage) HandleEncryption(myKey *ecdsa.PrivateKey, senderKey *ecd
 	if err != nil {
 		return errors.Wrap(err, "failed to unmarshal ProtocolMessage")
 	}
+	m.InstallationID = protocolMessage.GetInstallationId()
 
 	payload, err := enc.HandleMessage(
 		myKey,
@@ -109,6 +114,7 @@ func (m *StatusMessage) HandleEncryption(myKey *ecdsa.PrivateKey, senderKey *ecd
 	)
 
 	if err != nil {
+		m.DecryptionFailed = protocolMessage.GetDirectMessage() != nil
 		return errors.Wrap(err, "failed to handle Encryption message")
 	}
 
@@ -124,6 +130,7 @@ func (m *StatusMessage) HandleDatasync(datasync *datasync.DataSync) ([]*StatusMe
 
 	payloads := datasync.Handle(
 		m.SigPubKey(),
+		m.InstallationID,
 		m.DecryptedPayload,
 	)
 
@@ -261,6 +268,116 @@ func (m *StatusMessage) HandleApplication() error {
 		} else {
 			m.ParsedMessage = message
 
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_CONTACT_REQUEST:
+		var message protobuf.ContactRequest
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode ContactRequest: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PUBLIC_CHATS_ANNOUNCEMENT:
+		var message protobuf.PublicChatsAnnouncement
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PublicChatsAnnouncement: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_REACTION:
+		var message protobuf.Reaction
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode Reaction: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PIN_MESSAGE:
+		var message protobuf.PinMessage
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PinMessage: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_CHAT_INDICATOR:
+		var message protobuf.ChatIndicator
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode ChatIndicator: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION_RESPONSE:
+		var message protobuf.PushNotificationRegistrationResponse
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PushNotificationRegistrationResponse: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_INFO:
+		var message protobuf.PushNotificationInfo
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PushNotificationInfo: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REGISTRATION:
+		var message protobuf.PushNotificationRegistration
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PushNotificationRegistration: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_PUSH_NOTIFICATION_REQUEST:
+		var message protobuf.PushNotificationRequest
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode PushNotificationRequest: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
+			return nil
+		}
+	case protobuf.ApplicationMetadataMessage_CHAT_IDENTITY:
+		var message protobuf.ChatIdentity
+		err := proto.Unmarshal(m.DecryptedPayload, &message)
+		if err != nil {
+			m.ParsedMessage = nil
+			log.Printf("[message::DecodeMessage] could not decode ChatIdentity: %#x, err: %v", m.Hash, err.Error())
+		} else {
+			m.ParsedMessage = message
+
 			return nil
 		}
 	case protobuf.ApplicationMetadataMessage_SYNC_INSTALLATION:
@@ -123,11 +123,16 @@ func (c *Client) Call(result interface{}, method string, args ...interface{}) er
 //
 // It uses custom routing scheme for calls.
 // If there are any local handlers registered for this call, they will handle it.
+// Calls made on behalf of a dapp are authorized before they are routed.
 func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	if c.router.routeBlocked(method) {
 		return ErrMethodNotFound
 	}
 
+	if err := authorizeOrigin(ctx, method); err != nil {
+		return err
+	}
+
 	// check locally registered handlers first
 	if handler, ok := c.handler(method); ok {
 		return c.callMethod(ctx, result, handler, args...)
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Authorizer decides whether a dapp is allowed to call an RPC method.
+type Authorizer interface {
+	Authorize(origin, method string) error
+}
+
+// ErrNoAuthorizer is returned for calls made on behalf of a dapp without an authorizer.
+// It has the EIP-1193 unauthorized code, so that dapps don't mistake it for a missing method.
+var ErrNoAuthorizer error = noAuthorizerError{}
+
+type noAuthorizerError struct{}
+
+func (noAuthorizerError) Error() string  { return "no authorizer for calls of dapps" }
+func (noAuthorizerError) ErrorCode() int { return 4100 }
+
+type originKey struct{}
+
+// origin is a dapp on behalf of which calls are made.
+type origin struct {
+	name       string
+	authorizer Authorizer
+}
+
+// CallRawFromOrigin performs a JSON-RPC call with already crafted JSON-RPC body on behalf of
+// a dapp with the given origin. Every call, also every call of a batch, must be authorized.
+func (c *Client) CallRawFromOrigin(name string, authorizer Authorizer, body string) string {
+	ctx := context.WithValue(context.Background(), originKey{}, origin{name: name, authorizer: authorizer})
+	return c.callRawContext(ctx, json.RawMessage(body))
+}
+
+// authorizeOrigin returns an error if the call is made on behalf of a dapp
+// that is not allowed to call the method.
+func authorizeOrigin(ctx context.Context, method string) error {
+	o, ok := ctx.Value(originKey{}).(origin)
+	if !ok {
+		return nil
+	}
+	if o.authorizer == nil {
+		return ErrNoAuthorizer
+	}
+	return o.authorizer.Authorize(o.name, method)
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/params"
+)
+
+type testUnauthorizedError struct{}
+
+func (testUnauthorizedError) Error() string  { return "unauthorized" }
+func (testUnauthorizedError) ErrorCode() int { return 4100 }
+
+type testAuthorizer map[string]bool
+
+func (a testAuthorizer) Authorize(origin, method string) error {
+	if !a[origin+" "+method] {
+		return testUnauthorizedError{}
+	}
+	return nil
+}
+
+func TestCallRawFromOrigin(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	c.RegisterHandler("eth_chainId", func(context.Context, ...interface{}) (interface{}, error) {
+		return "0x1", nil
+	})
+	authorizer := testAuthorizer{"https://dapp.eth eth_chainId": true}
+
+	rsp := c.CallRawFromOrigin("https://dapp.eth", authorizer, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, rsp)
+
+	rsp = c.CallRawFromOrigin("https://other.eth", authorizer, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`)
+	require.Contains(t, rsp, `"code":4100`)
+
+	// every call of a batch is authorized
+	rsp = c.CallRawFromOrigin("https://other.eth", authorizer, `[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}]`)
+	require.Contains(t, rsp, `"code":4100`)
+
+	// calls without an origin are not authorized
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, c.CallRaw(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`))
+}
+
+func TestCallRawFromOriginWithoutAuthorizer(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	c.RegisterHandler("eth_chainId", func(context.Context, ...interface{}) (interface{}, error) {
+		return "0x1", nil
+	})
+
+	// calls of dapps fail as unauthorized, not as missing methods
+	rsp := c.CallRawFromOrigin("https://dapp.eth", nil, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"error":{"code":4100,"message":"no authorizer for calls of dapps"}}`, rsp)
+}
@@ -0,0 +1,217 @@
+package bridge
+
+import (
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/params"
+	"github.com/status-im/status-go/waku"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+const (
+	// seenCacheSize is a number of hashes of relayed envelopes that are remembered
+	// to prevent relaying them back.
+	seenCacheSize = 10000
+	// pipeSize is a size of buffers between the bridge and the protocols.
+	pipeSize = 1000
+)
+
+// Make sure that Bridge implements node.Service interface.
+var _ node.Service = (*Bridge)(nil)
+
+// topicFilter matches envelopes by topic. An empty filter matches all topics.
+type topicFilter map[types.TopicType]struct{}
+
+func newTopicFilter(topics []string) (topicFilter, error) {
+	filter := make(topicFilter, len(topics))
+	for _, topic := range topics {
+		data, err := hexutil.Decode(topic)
+		if err != nil || len(data) != types.TopicLength {
+			return nil, fmt.Errorf("invalid topic %s", topic)
+		}
+		filter[types.BytesToTopic(data)] = struct{}{}
+	}
+	return filter, nil
+}
+
+func (f topicFilter) Match(topic types.TopicType) bool {
+	if len(f) == 0 {
+		return true
+	}
+	_, exist := f[topic]
+	return exist
+}
+
+// Bridge relays envelopes between Whisper and Waku, so that nodes that support
+// only one of the protocols can communicate.
+type Bridge struct {
+	whisper *whisper.Whisper
+	waku    *waku.Waku
+
+	whisperToWaku topicFilter
+	wakuToWhisper topicFilter
+	// seen stores hashes of envelopes relayed in any direction.
+	seen *lru.Cache
+
+	whisperIn  chan *whisper.Envelope
+	whisperOut chan *whisper.Envelope
+	wakuIn     chan *waku.Envelope
+	wakuOut    chan *waku.Envelope
+
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	cancel chan struct{}
+}
+
+// New returns a new Bridge between shh and w.
+func New(shh *whisper.Whisper, w *waku.Waku, config params.BridgeConfig) (*Bridge, error) {
+	whisperToWaku, err := newTopicFilter(config.WhisperToWakuTopics)
+	if err != nil {
+		return nil, err
+	}
+	wakuToWhisper, err := newTopicFilter(config.WakuToWhisperTopics)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := lru.New(seenCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Bridge{
+		whisper:       shh,
+		waku:          w,
+		whisperToWaku: whisperToWaku,
+		wakuToWhisper: wakuToWhisper,
+		seen:          seen,
+		whisperIn:     make(chan *whisper.Envelope, pipeSize),
+		whisperOut:    make(chan *whisper.Envelope, pipeSize),
+		wakuIn:        make(chan *waku.Envelope, pipeSize),
+		wakuOut:       make(chan *waku.Envelope, pipeSize),
+	}, nil
+}
+
+type whisperPipe struct {
+	b *Bridge
+}
+
+func (p whisperPipe) Pipe() (<-chan *whisper.Envelope, chan<- *whisper.Envelope) {
+	return p.b.whisperOut, p.b.whisperIn
+}
+
+type wakuPipe struct {
+	b *Bridge
+}
+
+func (p wakuPipe) Pipe() (<-chan *waku.Envelope, chan<- *waku.Envelope) {
+	return p.b.wakuOut, p.b.wakuIn
+}
+
+// Protocols returns a new protocols list. In this case, there are none.
+func (b *Bridge) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{}
+}
+
+// APIs returns a list of new APIs. In this case, there are none.
+func (b *Bridge) APIs() []rpc.API {
+	return []rpc.API{}
+}
+
+// Start registers the bridge in both protocols and starts relaying envelopes.
+func (b *Bridge) Start(*p2p.Server) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		return nil
+	}
+	b.whisper.RegisterBridge(whisperPipe{b})
+	b.waku.RegisterBridge(wakuPipe{b})
+	b.cancel = make(chan struct{})
+	b.wg.Add(1)
+	go b.loop(b.cancel)
+	log.Info("Whisper-Waku bridge started", "whisperToWaku", len(b.whisperToWaku), "wakuToWhisper", len(b.wakuToWhisper))
+	return nil
+}
+
+// Stop stops relaying envelopes.
+func (b *Bridge) Stop() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel == nil {
+		return nil
+	}
+	close(b.cancel)
+	b.wg.Wait()
+	b.cancel = nil
+	return nil
+}
+
+func (b *Bridge) loop(cancel <-chan struct{}) {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-cancel:
+			return
+		case env := <-b.whisperIn:
+			if !b.relay(types.TopicType(env.Topic), env.Hash(), b.whisperToWaku) {
+				continue
+			}
+			select {
+			case b.wakuOut <- whisperToWakuEnvelope(env):
+			case <-cancel:
+				return
+			}
+		case env := <-b.wakuIn:
+			if !b.relay(types.TopicType(env.Topic), env.Hash(), b.wakuToWhisper) {
+				continue
+			}
+			select {
+			case b.whisperOut <- wakuToWhisperEnvelope(env):
+			case <-cancel:
+				return
+			}
+		}
+	}
+}
+
+// relay returns true if an envelope matches the filter and wasn't relayed before.
+func (b *Bridge) relay(topic types.TopicType, hash common.Hash, filter topicFilter) bool {
+	if !filter.Match(topic) {
+		return false
+	}
+	if seen, _ := b.seen.ContainsOrAdd(hash, struct{}{}); seen {
+		log.Trace("envelope already relayed by the bridge", "hash", hash)
+		return false
+	}
+	return true
+}
+
+func whisperToWakuEnvelope(env *whisper.Envelope) *waku.Envelope {
+	return &waku.Envelope{
+		Expiry: env.Expiry,
+		TTL:    env.TTL,
+		Topic:  waku.TopicType(env.Topic),
+		Data:   env.Data,
+		Nonce:  env.Nonce,
+	}
+}
+
+func wakuToWhisperEnvelope(env *waku.Envelope) *whisper.Envelope {
+	return &whisper.Envelope{
+		Expiry: env.Expiry,
+		TTL:    env.TTL,
+		Topic:  whisper.TopicType(env.Topic),
+		Data:   env.Data,
+		Nonce:  env.Nonce,
+	}
+}
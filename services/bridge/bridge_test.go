@@ -0,0 +1,114 @@
+package bridge
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/params"
+	"github.com/status-im/status-go/protocol/tt"
+	"github.com/status-im/status-go/waku"
+	"github.com/status-im/status-go/whisper/v6"
+)
+
+func newTestEnvelope(t *testing.T, topic whisper.TopicType) *whisper.Envelope {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	params := whisper.MessageParams{
+		TTL:      10,
+		PoW:      2.0,
+		Payload:  []byte("hello"),
+		WorkTime: 1,
+		Topic:    topic,
+		Dst:      &key.PublicKey,
+	}
+	message, err := whisper.NewSentMessage(&params)
+	require.NoError(t, err)
+	env, err := message.Wrap(&params, time.Now())
+	require.NoError(t, err)
+	return env
+}
+
+func setupTestBridge(t *testing.T, config params.BridgeConfig) (*Bridge, *whisper.Whisper, *waku.Waku) {
+	shh := whisper.New(nil)
+	w := waku.New(nil, nil)
+	b, err := New(shh, w, config)
+	require.NoError(t, err)
+	require.NoError(t, b.Start(nil))
+	return b, shh, w
+}
+
+func hasEnvelope(hash common.Hash, hashes []common.Hash) error {
+	for _, h := range hashes {
+		if h == hash {
+			return nil
+		}
+	}
+	return errors.New("envelope not found")
+}
+
+func wakuHashes(w *waku.Waku) (rst []common.Hash) {
+	for _, env := range w.Envelopes() {
+		rst = append(rst, env.Hash())
+	}
+	return
+}
+
+func whisperHashes(shh *whisper.Whisper) (rst []common.Hash) {
+	for _, env := range shh.Envelopes() {
+		rst = append(rst, env.Hash())
+	}
+	return
+}
+
+func TestBridgeRelaysInBothDirections(t *testing.T) {
+	b, shh, w := setupTestBridge(t, params.BridgeConfig{})
+	defer func() { require.NoError(t, b.Stop()) }()
+
+	fromWhisper := newTestEnvelope(t, whisper.TopicType{1})
+	b.whisperIn <- fromWhisper
+	require.NoError(t, tt.RetryWithBackOff(func() error {
+		return hasEnvelope(fromWhisper.Hash(), wakuHashes(w))
+	}))
+
+	fromWaku := whisperToWakuEnvelope(newTestEnvelope(t, whisper.TopicType{2}))
+	b.wakuIn <- fromWaku
+	require.NoError(t, tt.RetryWithBackOff(func() error {
+		return hasEnvelope(fromWaku.Hash(), whisperHashes(shh))
+	}))
+}
+
+func TestBridgeTopicFiltersAndLoops(t *testing.T) {
+	b, shh, w := setupTestBridge(t, params.BridgeConfig{
+		WhisperToWakuTopics: []string{"0x01000000"},
+	})
+	defer func() { require.NoError(t, b.Stop()) }()
+
+	filtered := newTestEnvelope(t, whisper.TopicType{2})
+	relayed := newTestEnvelope(t, whisper.TopicType{1})
+	b.whisperIn <- filtered
+	b.whisperIn <- relayed
+	require.NoError(t, tt.RetryWithBackOff(func() error {
+		return hasEnvelope(relayed.Hash(), wakuHashes(w))
+	}))
+	require.Error(t, hasEnvelope(filtered.Hash(), wakuHashes(w)))
+
+	// an envelope relayed to waku is not relayed back to whisper
+	b.wakuIn <- whisperToWakuEnvelope(relayed)
+	marker := whisperToWakuEnvelope(newTestEnvelope(t, whisper.TopicType{3}))
+	b.wakuIn <- marker
+	require.NoError(t, tt.RetryWithBackOff(func() error {
+		return hasEnvelope(marker.Hash(), whisperHashes(shh))
+	}))
+	require.Error(t, hasEnvelope(relayed.Hash(), whisperHashes(shh)))
+}
+
+func TestNewBridgeInvalidTopic(t *testing.T) {
+	_, err := New(nil, nil, params.BridgeConfig{WakuToWhisperTopics: []string{"0x01"}})
+	require.Error(t, err)
+}
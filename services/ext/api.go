@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/mailserver"
@@ -35,6 +35,9 @@ var (
 	// ErrPFSNotEnabled is returned when an endpoint PFS only is called but
 	// PFS is disabled
 	ErrPFSNotEnabled = errors.New("pfs not enabled")
+	// ErrMailServerRegistryDisabled is returned when the mail server registry is not enabled
+	// with EnableMailServerRegistry.
+	ErrMailServerRegistryDisabled = errors.New("mail server registry is disabled")
 )
 
 // -----
@@ -78,6 +81,19 @@ type MessagesRequest struct {
 
 	// Force ensures that requests will bypass enforced delay.
 	Force bool `json:"force"`
+
+	// Compress asks MailServer to send compressed batches of envelopes.
+	// MailServers that don't support compression reject such requests.
+	Compress bool `json:"compress"`
+
+	// AckWindow asks MailServer to send batches of envelopes that are acknowledged by the client,
+	// at most AckWindow batches are sent before they are acknowledged. If the client stops
+	// acknowledging them, the cursor points after the last acknowledged batch.
+	AckWindow uint32 `json:"ackWindow"`
+
+	// Order is an order of envelopes, "asc" for the oldest first or "desc" for the newest first.
+	// Default is the order of the MailServer archive.
+	Order string `json:"order"`
 }
 
 func (r *MessagesRequest) SetDefaults(now time.Time) {
@@ -275,6 +291,38 @@ func (api *PublicAPI) BlockContact(parent context.Context, contact *protocol.Con
 	return api.service.messenger.BlockContact(contact)
 }
 
+// BlockPublicKey adds the public key to the block list, its filters are removed
+// and its envelopes are dropped before they are decrypted.
+func (api *PublicAPI) BlockPublicKey(publicKey string) error {
+	api.log.Info("blocking public key", "publicKey", publicKey)
+	return api.service.messenger.BlockPublicKey(publicKey)
+}
+
+// UnblockPublicKey removes the public key from the block list.
+func (api *PublicAPI) UnblockPublicKey(publicKey string) error {
+	return api.service.messenger.UnblockPublicKey(publicKey)
+}
+
+// GetBlockedPublicKeys returns all blocked public keys.
+func (api *PublicAPI) GetBlockedPublicKeys() []string {
+	return api.service.messenger.BlockedPublicKeys()
+}
+
+// MuteChat adds the chat to the mute list, its messages are received without notifications.
+func (api *PublicAPI) MuteChat(chatID string) error {
+	return api.service.messenger.MuteChat(chatID)
+}
+
+// UnmuteChat removes the chat from the mute list.
+func (api *PublicAPI) UnmuteChat(chatID string) error {
+	return api.service.messenger.UnmuteChat(chatID)
+}
+
+// GetMutedChats returns IDs of all muted chats.
+func (api *PublicAPI) GetMutedChats() []string {
+	return api.service.messenger.MutedChats()
+}
+
 func (api *PublicAPI) Contacts(parent context.Context) []*protocol.Contact {
 	return api.service.messenger.Contacts()
 }
@@ -320,6 +368,26 @@ func (api *PublicAPI) ChatMessages(chatID, cursor string, limit int) (*Applicati
 	}, nil
 }
 
+// ChatMessagesInRange returns messages of a chat sent between from and to timestamps in milliseconds.
+// If to is zero, messages up to now are returned. Pages are returned with a cursor as by ChatMessages.
+func (api *PublicAPI) ChatMessagesInRange(chatID string, from, to uint64, cursor string, limit int) (*ApplicationMessagesResponse, error) {
+	messages, cursor, err := api.service.messenger.MessageByChatIDInRange(chatID, from, to, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApplicationMessagesResponse{
+		Messages: messages,
+		Cursor:   cursor,
+	}, nil
+}
+
+// SearchMessages returns messages matching a full-text query ordered by relevance.
+// If chatIDs are given, only messages of these chats are searched.
+func (api *PublicAPI) SearchMessages(query string, chatIDs []string, limit int) ([]*protocol.MessageSearchResult, error) {
+	return api.service.messenger.SearchMessages(query, chatIDs, limit)
+}
+
 func (api *PublicAPI) StartMessenger() error {
 	return api.service.StartMessenger()
 }
@@ -384,6 +452,169 @@ func (api *PublicAPI) SendContactUpdate(ctx context.Context, contactID, name, pi
 	return api.service.messenger.SendContactUpdate(ctx, contactID, name, picture)
 }
 
+func (api *PublicAPI) SendContactRequest(ctx context.Context, contactID, message string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.SendContactRequest(ctx, contactID, message)
+}
+
+func (api *PublicAPI) AcceptContactRequest(ctx context.Context, contactID string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.AcceptContactRequest(ctx, contactID)
+}
+
+func (api *PublicAPI) DeclineContactRequest(ctx context.Context, contactID string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.DeclineContactRequest(contactID)
+}
+
+func (api *PublicAPI) ContactRequests(parent context.Context) []*protocol.ContactRequest {
+	return api.service.messenger.ContactRequests()
+}
+
+// GetSuggestedChannels returns public chats announced in the public chats directory,
+// ranked by the number of users participating in them.
+func (api *PublicAPI) GetSuggestedChannels(limit int) ([]*protocol.SuggestedChannel, error) {
+	return api.service.messenger.SuggestedChannels(limit)
+}
+
+// SendReaction sends a reaction to a message in a public or one-to-one chat.
+func (api *PublicAPI) SendReaction(ctx context.Context, chatID, messageID, reaction string) error {
+	return api.service.messenger.SendReaction(ctx, chatID, messageID, reaction)
+}
+
+// RetractReaction removes a reaction previously sent with SendReaction.
+func (api *PublicAPI) RetractReaction(ctx context.Context, chatID, messageID, reaction string) error {
+	return api.service.messenger.RetractReaction(ctx, chatID, messageID, reaction)
+}
+
+// SendChatIndicator sends a typing or presence indicator to a one-to-one or a private group chat.
+// indicatorType is one of "typing", "typing-stopped" or "online".
+func (api *PublicAPI) SendChatIndicator(ctx context.Context, chatID, indicatorType string) error {
+	return api.service.messenger.SendChatIndicator(ctx, chatID, indicatorType)
+}
+
+// SubscribeMessages delivers received messages that match the filter in messages.subscription
+// signals with the returned subscription id. While there is any subscription received messages
+// are not included in messages.new signals. Subscriptions are kept until logout.
+func (api *PublicAPI) SubscribeMessages(filter MessagesFilter) (string, error) {
+	return api.service.subscriptions.Subscribe(filter)
+}
+
+// UnsubscribeMessages removes a subscription created with SubscribeMessages.
+func (api *PublicAPI) UnsubscribeMessages(id string) error {
+	return api.service.subscriptions.Unsubscribe(id)
+}
+
+// RegisterForPushNotifications registers the device token with the configured push notification server.
+// tokenType is one of "apn" or "firebase".
+func (api *PublicAPI) RegisterForPushNotifications(ctx context.Context, tokenType, deviceToken string) (*protocol.PushNotificationRegistration, error) {
+	return api.service.messenger.RegisterForPushNotifications(ctx, tokenType, deviceToken)
+}
+
+// UnregisterFromPushNotifications removes the device token from the push notification server.
+func (api *PublicAPI) UnregisterFromPushNotifications(ctx context.Context) error {
+	return api.service.messenger.UnregisterFromPushNotifications(ctx)
+}
+
+// GetPushNotificationRegistration returns the registration of this installation, null if it was never registered.
+func (api *PublicAPI) GetPushNotificationRegistration() (*protocol.PushNotificationRegistration, error) {
+	return api.service.messenger.PushNotificationRegistration()
+}
+
+// SetChatIdentity sets the display name and the keccak256 hash of the profile image of the user.
+// The identity is published on the contact code topic of the user and republished periodically.
+func (api *PublicAPI) SetChatIdentity(ctx context.Context, displayName string, imageHash types.HexBytes) (*protocol.ChatIdentity, error) {
+	return api.service.messenger.SetChatIdentity(ctx, displayName, imageHash)
+}
+
+// GetContactIdentity returns the last identity published by a user, null if it wasn't received.
+func (api *PublicAPI) GetContactIdentity(pubKey string) (*protocol.ChatIdentity, error) {
+	return api.service.messenger.ChatIdentity(pubKey)
+}
+
+// GetFilterStats returns per-filter counts of received envelopes and decryption failures.
+func (api *PublicAPI) GetFilterStats() []protocol.FilterStats {
+	return api.service.messenger.FilterStats()
+}
+
+// SetPayloadFilter drops received messages of the given types, any of "chat-indicator", "reaction"
+// or "emoji", before they are delivered. chatID is the ID of a chat filter or "*" for all chats.
+// An empty list removes the filter. Filters are kept until logout.
+func (api *PublicAPI) SetPayloadFilter(chatID string, skip []string) error {
+	return api.service.messenger.SetPayloadFilter(chatID, skip)
+}
+
+// GetPayloadFilters returns payload filters set with SetPayloadFilter.
+func (api *PublicAPI) GetPayloadFilters() []protocol.PayloadFilter {
+	return api.service.messenger.PayloadFilters()
+}
+
+// GetReactions returns reactions to the given messages of a chat aggregated by message ID.
+func (api *PublicAPI) GetReactions(chatID string, messageIDs []string) (map[string][]*protocol.ReactionSummary, error) {
+	return api.service.messenger.Reactions(chatID, messageIDs)
+}
+
+// PinMessage pins a message in a one-to-one chat, a group chat or a community channel.
+func (api *PublicAPI) PinMessage(ctx context.Context, chatID, messageID string) error {
+	return api.service.messenger.PinMessage(ctx, chatID, messageID)
+}
+
+// UnpinMessage unpins a message previously pinned with PinMessage.
+func (api *PublicAPI) UnpinMessage(ctx context.Context, chatID, messageID string) error {
+	return api.service.messenger.UnpinMessage(ctx, chatID, messageID)
+}
+
+// GetPinnedMessages returns messages pinned in a chat.
+func (api *PublicAPI) GetPinnedMessages(chatID string) ([]*protocol.PinnedMessage, error) {
+	return api.service.messenger.PinnedMessages(chatID)
+}
+
+// GetMentions returns messages of public chats mentioning the user, of all chats if chatID is empty.
+func (api *PublicAPI) GetMentions(chatID string, limit int) ([]*protocol.Mention, error) {
+	return api.service.messenger.Mentions(chatID, limit)
+}
+
+// SetMentionNames sets names mentioning the user in addition to the public key and the display name.
+func (api *PublicAPI) SetMentionNames(names []string) error {
+	return api.service.messenger.SetMentionNames(names)
+}
+
+// GetMentionNames returns names set with SetMentionNames.
+func (api *PublicAPI) GetMentionNames() ([]string, error) {
+	return api.service.messenger.MentionNames()
+}
+
+// ExportKeys returns symmetric keys, negotiated secrets and filter definitions
+// encrypted with the given password, so that they can be backed up.
+func (api *PublicAPI) ExportKeys(password string) (types.HexBytes, error) {
+	return api.service.messenger.ExportKeys(password)
+}
+
+// ImportKeys restores keys and filters from a blob returned by ExportKeys.
+func (api *PublicAPI) ImportKeys(blob types.HexBytes, password string) error {
+	return api.service.messenger.ImportKeys(blob, password)
+}
+
+// ExportChatData returns chats, contacts, filters with their keys and optionally
+// stored messages encrypted with the given password, so that the account can be
+// moved to a new device.
+func (api *PublicAPI) ExportChatData(password string, includeMessages bool) (types.HexBytes, error) {
+	return api.service.messenger.ExportChatData(password, includeMessages)
+}
+
+// ImportChatData restores the state from an archive returned by ExportChatData.
+func (api *PublicAPI) ImportChatData(blob types.HexBytes, password string) error {
+	return api.service.messenger.ImportChatData(blob, password)
+}
+
+// SendRawMessage sends a payload as is on a given topic, encrypted with either
+// a symmetric or a public key. Returns the hash of the envelope, the envelope is
+// tracked and reported with envelope signals.
+func (api *PublicAPI) SendRawMessage(ctx context.Context, topic types.TopicType, payload types.HexBytes, opts SendRawMessageOptionsRPC) (types.HexBytes, error) {
+	options, err := opts.options()
+	if err != nil {
+		return nil, err
+	}
+	return api.service.messenger.SendRawEnvelope(ctx, topic, payload, options)
+}
+
 func (api *PublicAPI) SendPairInstallation(ctx context.Context) (*protocol.MessengerResponse, error) {
 	return api.service.messenger.SendPairInstallation(ctx)
 }
@@ -392,6 +623,39 @@ func (api *PublicAPI) SyncDevices(ctx context.Context, name, picture string) err
 	return api.service.messenger.SyncDevices(ctx, name, picture)
 }
 
+// MailServers returns mail servers with their latency and error rate of history requests.
+func (api *PublicAPI) MailServers() ([]mailservers.MailServerStatus, error) {
+	registry := api.service.MailServers()
+	if registry == nil {
+		return nil, ErrMailServerRegistryDisabled
+	}
+	return registry.Status(), nil
+}
+
+// PinMailServer makes the mail server active for history requests until it is unpinned.
+func (api *PublicAPI) PinMailServer(rawURL string) error {
+	registry := api.service.MailServers()
+	if registry == nil {
+		return ErrMailServerRegistryDisabled
+	}
+	node, err := enode.ParseV4(rawURL)
+	if err != nil {
+		return fmt.Errorf("%v: %v", ErrInvalidMailServerPeer, err)
+	}
+	registry.Pin(node)
+	return nil
+}
+
+// UnpinMailServer selects the healthiest mail server for history requests again.
+func (api *PublicAPI) UnpinMailServer() error {
+	registry := api.service.MailServers()
+	if registry == nil {
+		return ErrMailServerRegistryDisabled
+	}
+	registry.Unpin()
+	return nil
+}
+
 // Echo is a method for testing purposes.
 func (api *PublicAPI) Echo(ctx context.Context, message string) (string, error) {
 	return message, nil
@@ -414,6 +678,17 @@ func MakeMessagesRequestPayload(r MessagesRequest) ([]byte, error) {
 		return nil, fmt.Errorf("invalid cursor size: expected %d but got %d", mailserver.CursorLength, len(cursor))
 	}
 
+	var order mailserver.Order
+	switch r.Order {
+	case "":
+	case "asc":
+		order = mailserver.OrderAscending
+	case "desc":
+		order = mailserver.OrderDescending
+	default:
+		return nil, fmt.Errorf("invalid order: %s", r.Order)
+	}
+
 	payload := mailserver.MessagesRequestPayload{
 		Lower:  r.From,
 		Upper:  r.To,
@@ -422,10 +697,13 @@ func MakeMessagesRequestPayload(r MessagesRequest) ([]byte, error) {
 		Cursor: cursor,
 		// Client must tell the MailServer if it supports batch responses.
 		// This can be removed in the future.
-		Batch: true,
+		Batch:     true,
+		Compress:  r.Compress,
+		AckWindow: r.AckWindow,
+		Order:     order,
 	}
 
-	return rlp.EncodeToBytes(payload)
+	return mailserver.EncodeMessagesRequestPayload(payload)
 }
 
 func createBloomFilter(r MessagesRequest) []byte {
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/rlp"
+
 	"github.com/status-im/status-go/eth-node/types"
 
 	"github.com/status-im/status-go/mailserver"
@@ -83,6 +85,16 @@ func TestMakeMessagesRequestPayload(t *testing.T) {
 			},
 			Err: "",
 		},
+		{
+			Name: "ascending order",
+			Req:  MessagesRequest{Order: "asc"},
+			Err:  "",
+		},
+		{
+			Name: "invalid order",
+			Req:  MessagesRequest{Order: "random"},
+			Err:  "invalid order: random",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -97,6 +109,36 @@ func TestMakeMessagesRequestPayload(t *testing.T) {
 	}
 }
 
+// baselineMessagesRequestPayload is the payload decoded by mail servers that support
+// only batches, they reject payloads with more fields.
+type baselineMessagesRequestPayload struct {
+	Lower  uint32
+	Upper  uint32
+	Bloom  []byte
+	Topics [][]byte
+	Limit  uint32
+	Cursor []byte
+	Batch  bool
+}
+
+func TestMakeMessagesRequestPayloadDecodedByBaselineMailServers(t *testing.T) {
+	cursor := mailserver.NewDBKey(123, types.TopicType{}, types.Hash{}).Cursor()
+	data, err := MakeMessagesRequestPayload(MessagesRequest{From: 10, To: 20, Limit: 5, Cursor: hex.EncodeToString(cursor)})
+	require.NoError(t, err)
+	var payload baselineMessagesRequestPayload
+	require.NoError(t, rlp.DecodeBytes(data, &payload))
+	require.Equal(t, uint32(10), payload.Lower)
+	require.Equal(t, uint32(20), payload.Upper)
+	require.Equal(t, uint32(5), payload.Limit)
+	require.Equal(t, cursor, payload.Cursor)
+	require.True(t, payload.Batch)
+
+	// options unknown to baseline mail servers are sent only if they are set
+	data, err = MakeMessagesRequestPayload(MessagesRequest{From: 10, To: 20, Compress: true})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(data, &payload))
+}
+
 func TestTopicsToBloom(t *testing.T) {
 	t1 := stringToTopic("t1")
 	b1 := types.TopicToBloom(t1)
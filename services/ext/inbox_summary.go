@@ -0,0 +1,77 @@
+package ext
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/status-im/status-go/protocol"
+	"github.com/status-im/status-go/signal"
+)
+
+// inboxSummary counts messages received while history requests to mail servers are
+// in flight, so that clients can update badges once the sync is completed instead of
+// processing every synced message. It is used only by the messages retrieving loop.
+type inboxSummary struct {
+	// mention is a text mentioning the user
+	mention string
+	syncing bool
+	chats   map[string]*signal.ChatInboxSummary
+}
+
+func newInboxSummary(publicKey string) *inboxSummary {
+	return &inboxSummary{
+		mention: "@" + publicKey,
+		chats:   make(map[string]*signal.ChatInboxSummary),
+	}
+}
+
+// Update starts a sync when there are pending history requests. Messages are counted while
+// the sync is in progress. When there are no pending requests anymore, the sync is completed
+// and its summary is returned. Messages must be retrieved after pending requests are checked,
+// so that envelopes of the last request are counted.
+func (s *inboxSummary) Update(pending int, messages []*protocol.Message) ([]signal.ChatInboxSummary, bool) {
+	if pending > 0 {
+		s.syncing = true
+	}
+	if !s.syncing {
+		return nil, false
+	}
+	for _, m := range messages {
+		s.add(m)
+	}
+	if pending > 0 {
+		return nil, false
+	}
+	rst := make([]signal.ChatInboxSummary, 0, len(s.chats))
+	for _, chat := range s.chats {
+		rst = append(rst, *chat)
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].ChatID < rst[j].ChatID
+	})
+	s.syncing = false
+	s.chats = make(map[string]*signal.ChatInboxSummary)
+	return rst, true
+}
+
+// add counts unseen messages of other users.
+func (s *inboxSummary) add(m *protocol.Message) {
+	if m.Seen || m.OutgoingStatus != "" {
+		return
+	}
+	chat, ok := s.chats[m.LocalChatID]
+	if !ok {
+		chat = &signal.ChatInboxSummary{ChatID: m.LocalChatID, Earliest: m.Timestamp, Latest: m.Timestamp}
+		s.chats[m.LocalChatID] = chat
+	}
+	chat.NewMessages++
+	if strings.Contains(m.Text, s.mention) {
+		chat.Mentions++
+	}
+	if m.Timestamp < chat.Earliest {
+		chat.Earliest = m.Timestamp
+	}
+	if m.Timestamp > chat.Latest {
+		chat.Latest = m.Timestamp
+	}
+}
@@ -0,0 +1,54 @@
+package ext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/signal"
+)
+
+func TestInboxSummary(t *testing.T) {
+	summary := newInboxSummary("0x04aa")
+
+	// messages received without pending requests are not counted
+	_, completed := summary.Update(0, []*protocol.Message{testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 50)})
+	require.False(t, completed)
+
+	mention := testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 300)
+	mention.Text = "hey @0x04aa"
+	seen := testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 10)
+	seen.Seen = true
+	own := testMessage("other", "0x04aa", protobuf.ChatMessage_TEXT_PLAIN, 10)
+	own.OutgoingStatus = protocol.OutgoingStatusSent
+
+	_, completed = summary.Update(2, []*protocol.Message{
+		testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 200),
+		mention,
+		seen,
+		own,
+	})
+	require.False(t, completed)
+
+	// messages of the last request are retrieved after it is completed
+	chats, completed := summary.Update(0, []*protocol.Message{
+		testMessage("other", "0x04cd", protobuf.ChatMessage_STICKER, 100),
+		testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 150),
+	})
+	require.True(t, completed)
+	require.Equal(t, []signal.ChatInboxSummary{
+		{ChatID: "other", NewMessages: 1, Earliest: 100, Latest: 100},
+		{ChatID: "status", NewMessages: 3, Mentions: 1, Earliest: 150, Latest: 300},
+	}, chats)
+
+	// the summary is sent once per sync, also without new messages
+	_, completed = summary.Update(0, nil)
+	require.False(t, completed)
+	_, completed = summary.Update(1, nil)
+	require.False(t, completed)
+	chats, completed = summary.Update(0, nil)
+	require.True(t, completed)
+	require.Empty(t, chats)
+}
@@ -70,6 +70,13 @@ func (m *MailRequestMonitor) GetState(hash types.Hash) EnvelopeState {
 	return state
 }
 
+// Pending returns a number of requests that were sent and not completed or expired yet.
+func (m *MailRequestMonitor) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.cache)
+}
+
 // handleEnvelopeEvents processes whisper envelope events
 func (m *MailRequestMonitor) handleEnvelopeEvents() {
 	events := make(chan types.EnvelopeEvent, 100) // must be buffered to prevent blocking whisper
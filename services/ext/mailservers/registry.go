@@ -0,0 +1,393 @@
+package mailservers
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+const (
+	// healthSmoothing is a weight of the latest request in the latency and the error rate.
+	healthSmoothing = 0.3
+)
+
+var (
+	// ErrNoMailServers returned when there are no mail servers to select from.
+	ErrNoMailServers = errors.New("no mail servers")
+)
+
+// ActiveMailServerHandler is notified when the active mail server changes.
+// The node is nil if there are no mail servers left.
+type ActiveMailServerHandler interface {
+	ActiveMailServerChanged(node *enode.Node, pinned bool)
+}
+
+// MailServerStatus describes health of a mail server as measured by history requests.
+type MailServerStatus struct {
+	Enode     string `json:"enode"`
+	Active    bool   `json:"active"`
+	Pinned    bool   `json:"pinned"`
+	Connected bool   `json:"connected"`
+	// Latency is an average time in milliseconds between a request and its response.
+	Latency int64 `json:"latency"`
+	// ErrorRate is an average rate of failed and expired requests, between 0 and 1.
+	ErrorRate float64 `json:"errorRate"`
+	Requests  int     `json:"requests"`
+	Failures  int     `json:"failures"`
+}
+
+type candidate struct {
+	node *enode.Node
+	// order is a position of the node in the list of mail servers
+	order int
+
+	latency   time.Duration
+	errorRate float64
+	requests  int
+	failures  int
+	// consecutive failures since the last successful request
+	consecutive int
+}
+
+func (c *candidate) track(latency time.Duration, failed bool) {
+	c.requests++
+	if failed {
+		c.failures++
+		c.consecutive++
+		c.errorRate = (1-healthSmoothing)*c.errorRate + healthSmoothing
+		return
+	}
+	c.consecutive = 0
+	c.errorRate = (1 - healthSmoothing) * c.errorRate
+	if c.latency == 0 {
+		c.latency = latency
+	} else {
+		c.latency = time.Duration((1-healthSmoothing)*float64(c.latency) + healthSmoothing*float64(latency))
+	}
+}
+
+type pendingRequest struct {
+	peer types.EnodeID
+	sent time.Time
+}
+
+// NewRegistry returns an instance of Registry. A mail server is replaced after maxFailures
+// consecutive failed or expired requests.
+func NewRegistry(provider PeersProvider, eventSub EnvelopeEventSubscriber, maxFailures int, handler ActiveMailServerHandler) *Registry {
+	return &Registry{
+		provider:    provider,
+		eventSub:    eventSub,
+		maxFailures: maxFailures,
+		handler:     handler,
+		candidates:  map[types.EnodeID]*candidate{},
+		pending:     map[types.Hash]pendingRequest{},
+		now:         time.Now,
+	}
+}
+
+// Registry selects a mail server for history requests. Health of mail servers is measured
+// by latency and error rate of requests and the registry fails over to the next healthy
+// mail server when the active one fails. A pinned mail server is used until it is unpinned.
+type Registry struct {
+	provider    PeersProvider
+	eventSub    EnvelopeEventSubscriber
+	maxFailures int
+	handler     ActiveMailServerHandler
+	now         func() time.Time
+
+	mu         sync.Mutex
+	candidates map[types.EnodeID]*candidate
+	pending    map[types.Hash]pendingRequest
+	active     *enode.Node
+	pinned     *enode.Node
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Start spins a separate goroutine to measure requests to mail servers.
+func (r *Registry) Start() {
+	r.quit = make(chan struct{})
+	r.wg.Add(1)
+	go func() {
+		events := make(chan types.EnvelopeEvent, whisperEventsBuffer)
+		sub := r.eventSub.SubscribeEnvelopeEvents(events)
+		defer sub.Unsubscribe()
+		defer r.wg.Done()
+		for {
+			select {
+			case <-r.quit:
+				return
+			case err := <-sub.Err():
+				log.Error("retry after error suscribing to eventSub events", "error", err)
+				return
+			case ev := <-events:
+				switch ev.Event {
+				case types.EventMailServerRequestSent:
+					r.requestSent(ev.Hash, ev.Peer)
+				case types.EventMailServerRequestCompleted:
+					var err error
+					if resp, ok := ev.Data.(*types.MailServerResponse); ok {
+						err = resp.Error
+					}
+					r.requestFinished(ev.Hash, err != nil)
+				case types.EventMailServerRequestExpired:
+					r.requestFinished(ev.Hash, true)
+				}
+			}
+		}
+	}()
+}
+
+// Stop closes channel to signal a quit and waits until all goroutines are stoppped.
+func (r *Registry) Stop() {
+	if r.quit == nil {
+		return
+	}
+	select {
+	case <-r.quit:
+		return
+	default:
+	}
+	close(r.quit)
+	r.wg.Wait()
+	r.quit = nil
+}
+
+// Update replaces mail servers. Measurements of mail servers that are kept are preserved.
+func (r *Registry) Update(nodes []*enode.Node) {
+	r.mu.Lock()
+	candidates := make(map[types.EnodeID]*candidate, len(nodes))
+	for i, n := range nodes {
+		id := types.EnodeID(n.ID())
+		c, exist := r.candidates[id]
+		if !exist {
+			c = &candidate{}
+		}
+		c.node = n
+		c.order = i
+		candidates[id] = c
+	}
+	r.candidates = candidates
+	changed := r.selectActive(false)
+	r.mu.Unlock()
+	r.notify(changed)
+}
+
+// Notify replaces mail servers. It makes the registry a NodesNotifee, so that the last used
+// mail server is selected first when records are loaded from the cache.
+func (r *Registry) Notify(nodes []*enode.Node) {
+	r.Update(nodes)
+}
+
+// Pin makes the node an active mail server until Unpin is called.
+// Failed requests don't make the registry fail over from a pinned mail server.
+func (r *Registry) Pin(node *enode.Node) {
+	r.mu.Lock()
+	r.pinned = node
+	changed := r.selectActive(false)
+	r.mu.Unlock()
+	r.notify(changed)
+}
+
+// Unpin selects the healthiest mail server again.
+func (r *Registry) Unpin() {
+	r.mu.Lock()
+	r.pinned = nil
+	changed := r.selectActive(true)
+	r.mu.Unlock()
+	r.notify(changed)
+}
+
+// Active returns a mail server that should be used for history requests.
+// If the active mail server is disconnected, the next connected one is selected.
+func (r *Registry) Active() (*enode.Node, error) {
+	r.mu.Lock()
+	changed := false
+	if r.pinned == nil && (r.active == nil || !r.isConnected(types.EnodeID(r.active.ID()))) {
+		changed = r.selectActive(true)
+	}
+	active := r.active
+	r.mu.Unlock()
+	r.notify(changed)
+	if active == nil {
+		return nil, ErrNoMailServers
+	}
+	return active, nil
+}
+
+// Status returns measurements of all mail servers ordered as they were added.
+func (r *Registry) Status() []MailServerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	connected := r.connected()
+	candidates := make([]*candidate, 0, len(r.candidates))
+	for _, c := range r.candidates {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].order < candidates[j].order
+	})
+	rst := make([]MailServerStatus, 0, len(candidates)+1)
+	for _, c := range candidates {
+		rst = append(rst, MailServerStatus{
+			Enode:     c.node.String(),
+			Active:    sameNode(r.active, c.node),
+			Pinned:    sameNode(r.pinned, c.node),
+			Connected: connected[types.EnodeID(c.node.ID())],
+			Latency:   int64(c.latency / time.Millisecond),
+			ErrorRate: c.errorRate,
+			Requests:  c.requests,
+			Failures:  c.failures,
+		})
+	}
+	if r.pinned != nil {
+		if _, exist := r.candidates[types.EnodeID(r.pinned.ID())]; !exist {
+			rst = append(rst, MailServerStatus{
+				Enode:     r.pinned.String(),
+				Active:    true,
+				Pinned:    true,
+				Connected: connected[types.EnodeID(r.pinned.ID())],
+			})
+		}
+	}
+	return rst
+}
+
+// RequestExpired marks a request as failed. It can be called by a client that stopped
+// waiting for a response before the request expired, so that the next request is sent
+// to another mail server. Requests are tracked once, so it is safe to call it in addition
+// to the expiration event.
+func (r *Registry) RequestExpired(hash types.Hash) {
+	r.requestFinished(hash, true)
+}
+
+func (r *Registry) requestSent(hash types.Hash, peer types.EnodeID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exist := r.candidates[peer]; !exist {
+		return
+	}
+	r.pending[hash] = pendingRequest{peer: peer, sent: r.now()}
+}
+
+func (r *Registry) requestFinished(hash types.Hash, failed bool) {
+	r.mu.Lock()
+	req, exist := r.pending[hash]
+	if !exist {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.pending, hash)
+	c, exist := r.candidates[req.peer]
+	if !exist {
+		r.mu.Unlock()
+		return
+	}
+	c.track(r.now().Sub(req.sent), failed)
+	changed := false
+	if failed && r.pinned == nil && r.active != nil && r.active.ID() == c.node.ID() && c.consecutive >= r.maxFailures {
+		log.Warn("mail server failed, selecting another one", "peer", req.peer, "failures", c.consecutive)
+		changed = r.selectActive(true)
+	}
+	r.mu.Unlock()
+	r.notify(changed)
+}
+
+// selectActive selects the pinned mail server or the healthiest one. If switchActive is false,
+// the current active mail server is kept as long as it is still available.
+// Must be called with the lock held. Returns true if the active mail server changed.
+func (r *Registry) selectActive(switchActive bool) bool {
+	previous := r.active
+	if r.pinned != nil {
+		r.active = r.pinned
+		return !sameNode(previous, r.active)
+	}
+	if !switchActive && previous != nil {
+		if _, exist := r.candidates[types.EnodeID(previous.ID())]; exist {
+			return false
+		}
+	}
+
+	connected := r.connected()
+	healthy := func(c *candidate) bool {
+		return r.maxFailures <= 0 || c.consecutive < r.maxFailures
+	}
+	var best *candidate
+	for _, c := range r.candidates {
+		if best == nil || r.better(c, best, connected, healthy) {
+			best = c
+		}
+	}
+	r.active = nil
+	if best != nil {
+		r.active = best.node
+		// A mail server that failed is given another chance when it is selected again.
+		if !healthy(best) {
+			best.consecutive = 0
+		}
+	}
+	return !sameNode(previous, r.active)
+}
+
+// better returns true if a should be preferred to b. Connected and healthy mail servers
+// come first, then the ones with a lower error rate and latency.
+func (r *Registry) better(a, b *candidate, connected map[types.EnodeID]bool, healthy func(*candidate) bool) bool {
+	aConnected, bConnected := connected[types.EnodeID(a.node.ID())], connected[types.EnodeID(b.node.ID())]
+	if aConnected != bConnected {
+		return aConnected
+	}
+	if healthy(a) != healthy(b) {
+		return healthy(a)
+	}
+	if a.errorRate != b.errorRate {
+		return a.errorRate < b.errorRate
+	}
+	// mail servers that were not measured yet are tried after the measured ones
+	if (a.latency == 0) != (b.latency == 0) {
+		return a.latency != 0
+	}
+	if a.latency != b.latency {
+		return a.latency < b.latency
+	}
+	return a.order < b.order
+}
+
+func (r *Registry) connected() map[types.EnodeID]bool {
+	rst := map[types.EnodeID]bool{}
+	if r.provider == nil {
+		return rst
+	}
+	for _, p := range r.provider.Peers() {
+		rst[types.EnodeID(p.ID())] = true
+	}
+	return rst
+}
+
+func (r *Registry) isConnected(id types.EnodeID) bool {
+	return r.connected()[id]
+}
+
+func (r *Registry) notify(changed bool) {
+	if !changed || r.handler == nil {
+		return
+	}
+	r.mu.Lock()
+	active, pinned := r.active, r.pinned != nil
+	r.mu.Unlock()
+	log.Info("active mail server changed", "node", active, "pinned", pinned)
+	r.handler.ActiveMailServerChanged(active, pinned)
+}
+
+func sameNode(a, b *enode.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID() == b.ID()
+}
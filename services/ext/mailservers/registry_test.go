@@ -0,0 +1,167 @@
+package mailservers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/t/utils"
+)
+
+type fakePeersProvider struct {
+	mu    sync.Mutex
+	peers []*p2p.Peer
+}
+
+func (f *fakePeersProvider) Peers() []*p2p.Peer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.peers
+}
+
+func (f *fakePeersProvider) Connect(nodes ...*enode.Node) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.peers = nil
+	for _, n := range nodes {
+		f.peers = append(f.peers, p2p.NewPeer(n.ID(), n.ID().TerminalString(), nil))
+	}
+}
+
+type activeChanges struct {
+	mu    sync.Mutex
+	nodes []*enode.Node
+}
+
+func (h *activeChanges) ActiveMailServerChanged(node *enode.Node, pinned bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nodes = append(h.nodes, node)
+}
+
+func (h *activeChanges) Last() *enode.Node {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.nodes) == 0 {
+		return nil
+	}
+	return h.nodes[len(h.nodes)-1]
+}
+
+func setupTestRegistry(t *testing.T, maxFailures int) (*Registry, []*enode.Node, *fakePeersProvider, *activeChanges) {
+	nodes := make([]*enode.Node, 3)
+	fillWithRandomNodes(t, nodes)
+	provider := &fakePeersProvider{}
+	provider.Connect(nodes...)
+	changes := &activeChanges{}
+	registry := NewRegistry(provider, newFakeEnvelopesEvents(), maxFailures, changes)
+	registry.Update(nodes)
+	return registry, nodes, provider, changes
+}
+
+func trackRequest(registry *Registry, node *enode.Node, latency time.Duration, failed bool) {
+	var hash types.Hash
+	copy(hash[:], fmt.Sprintf("%s%d", node.ID(), time.Now().UnixNano()))
+	sent := time.Now()
+	registry.now = func() time.Time { return sent }
+	registry.requestSent(hash, types.EnodeID(node.ID()))
+	registry.now = func() time.Time { return sent.Add(latency) }
+	registry.requestFinished(hash, failed)
+}
+
+func TestRegistryFailover(t *testing.T) {
+	registry, nodes, _, changes := setupTestRegistry(t, 2)
+	active, err := registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[0].ID(), active.ID())
+	require.Equal(t, nodes[0].ID(), changes.Last().ID())
+
+	trackRequest(registry, nodes[1], 100*time.Millisecond, false)
+	trackRequest(registry, nodes[2], 10*time.Millisecond, false)
+	trackRequest(registry, nodes[0], 0, true)
+	active, err = registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[0].ID(), active.ID())
+
+	// the fastest mail server is selected after consecutive failures
+	trackRequest(registry, nodes[0], 0, true)
+	active, err = registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[2].ID(), active.ID())
+	require.Equal(t, nodes[2].ID(), changes.Last().ID())
+
+	status := registry.Status()
+	require.Len(t, status, 3)
+	require.Equal(t, nodes[0].String(), status[0].Enode)
+	require.Equal(t, 2, status[0].Failures)
+	require.True(t, status[0].ErrorRate > 0)
+	require.True(t, status[2].Active)
+	require.Equal(t, int64(10), status[2].Latency)
+}
+
+func TestRegistryPin(t *testing.T) {
+	registry, nodes, _, changes := setupTestRegistry(t, 1)
+
+	registry.Pin(nodes[1])
+	require.Equal(t, nodes[1].ID(), changes.Last().ID())
+	trackRequest(registry, nodes[1], 0, true)
+	active, err := registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[1].ID(), active.ID())
+
+	registry.Unpin()
+	active, err = registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[0].ID(), active.ID())
+	require.Equal(t, nodes[0].ID(), changes.Last().ID())
+}
+
+func TestRegistryDisconnectedActive(t *testing.T) {
+	registry, nodes, provider, _ := setupTestRegistry(t, 1)
+
+	provider.Connect(nodes[2])
+	active, err := registry.Active()
+	require.NoError(t, err)
+	require.Equal(t, nodes[2].ID(), active.ID())
+
+	registry.Update(nil)
+	_, err = registry.Active()
+	require.Equal(t, ErrNoMailServers, err)
+}
+
+func TestRegistryTracksEvents(t *testing.T) {
+	nodes := make([]*enode.Node, 2)
+	fillWithRandomNodes(t, nodes)
+	provider := &fakePeersProvider{}
+	provider.Connect(nodes...)
+	events := newFakeEnvelopesEvents()
+	registry := NewRegistry(provider, events, 1, nil)
+	registry.Update(nodes)
+	registry.Start()
+	defer registry.Stop()
+
+	hash := types.Hash{1}
+	events.input <- types.EnvelopeEvent{Event: types.EventMailServerRequestSent, Hash: hash, Peer: types.EnodeID(nodes[0].ID())}
+	events.input <- types.EnvelopeEvent{Event: types.EventMailServerRequestExpired, Hash: hash, Peer: types.EnodeID(nodes[0].ID())}
+	require.NoError(t, utils.Eventually(func() error {
+		active, err := registry.Active()
+		if err != nil {
+			return err
+		}
+		if active.ID() != nodes[1].ID() {
+			return fmt.Errorf("active mail server wasn't replaced")
+		}
+		return nil
+	}, time.Second, 10*time.Millisecond))
+
+	// a request is tracked once
+	registry.RequestExpired(hash)
+	require.Equal(t, 1, registry.Status()[0].Failures)
+}
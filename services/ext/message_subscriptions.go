@@ -0,0 +1,159 @@
+package ext
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/pborman/uuid"
+
+	"github.com/status-im/status-go/protocol"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+var (
+	// ErrInvalidMessagesTimeRange is returned when Since of a messages filter is after Until.
+	ErrInvalidMessagesTimeRange = errors.New("since must not be after until")
+	// ErrMessagesSubscriptionNotFound is returned when unsubscribing with an unknown id.
+	ErrMessagesSubscriptionNotFound = errors.New("messages subscription not found")
+)
+
+// MessagesFilter selects messages delivered to a subscription. A message must match
+// every field that is set, empty fields match any message.
+type MessagesFilter struct {
+	ChatIDs []string `json:"chatIds"`
+	// From are public keys of authors of messages in hex.
+	From         []string                           `json:"from"`
+	ContentTypes []protobuf.ChatMessage_ContentType `json:"contentTypes"`
+	// Since and Until are inclusive bounds of the message timestamp in milliseconds.
+	Since uint64 `json:"since"`
+	Until uint64 `json:"until"`
+}
+
+// Validate returns an error if the filter can't match any message.
+func (f MessagesFilter) Validate() error {
+	if f.Until != 0 && f.Since > f.Until {
+		return ErrInvalidMessagesTimeRange
+	}
+	return nil
+}
+
+// messagesMatcher is a MessagesFilter prepared for matching.
+type messagesMatcher struct {
+	chatIDs      map[string]struct{}
+	from         map[string]struct{}
+	contentTypes map[protobuf.ChatMessage_ContentType]struct{}
+	since        uint64
+	until        uint64
+}
+
+func newMessagesMatcher(f MessagesFilter) *messagesMatcher {
+	m := &messagesMatcher{since: f.Since, until: f.Until}
+	if len(f.ChatIDs) > 0 {
+		m.chatIDs = make(map[string]struct{}, len(f.ChatIDs))
+		for _, id := range f.ChatIDs {
+			m.chatIDs[id] = struct{}{}
+		}
+	}
+	if len(f.From) > 0 {
+		m.from = make(map[string]struct{}, len(f.From))
+		for _, from := range f.From {
+			m.from[strings.ToLower(from)] = struct{}{}
+		}
+	}
+	if len(f.ContentTypes) > 0 {
+		m.contentTypes = make(map[protobuf.ChatMessage_ContentType]struct{}, len(f.ContentTypes))
+		for _, contentType := range f.ContentTypes {
+			m.contentTypes[contentType] = struct{}{}
+		}
+	}
+	return m
+}
+
+func (m *messagesMatcher) matches(message *protocol.Message) bool {
+	if m.chatIDs != nil {
+		if _, ok := m.chatIDs[message.LocalChatID]; !ok {
+			return false
+		}
+	}
+	if m.from != nil {
+		if _, ok := m.from[strings.ToLower(message.From)]; !ok {
+			return false
+		}
+	}
+	if m.contentTypes != nil {
+		if _, ok := m.contentTypes[message.ContentType]; !ok {
+			return false
+		}
+	}
+	if message.Timestamp < m.since {
+		return false
+	}
+	return m.until == 0 || message.Timestamp <= m.until
+}
+
+// messageSubscriptions delivers received messages to subscriptions with matching filters.
+type messageSubscriptions struct {
+	mu   sync.RWMutex
+	subs map[string]*messagesMatcher
+	send func(subscriptionID string, messages []*protocol.Message)
+}
+
+func newMessageSubscriptions() *messageSubscriptions {
+	return &messageSubscriptions{
+		subs: make(map[string]*messagesMatcher),
+		send: PublisherSignalHandler{}.MessagesSubscription,
+	}
+}
+
+// Subscribe adds a subscription and returns its id.
+func (s *messageSubscriptions) Subscribe(filter MessagesFilter) (string, error) {
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+	id := uuid.New()
+	s.mu.Lock()
+	s.subs[id] = newMessagesMatcher(filter)
+	s.mu.Unlock()
+	return id, nil
+}
+
+// Unsubscribe removes the subscription.
+func (s *messageSubscriptions) Unsubscribe(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[id]; !ok {
+		return ErrMessagesSubscriptionNotFound
+	}
+	delete(s.subs, id)
+	return nil
+}
+
+// Clear removes all subscriptions.
+func (s *messageSubscriptions) Clear() {
+	s.mu.Lock()
+	s.subs = make(map[string]*messagesMatcher)
+	s.mu.Unlock()
+}
+
+// Publish sends matching messages to each subscription, subscriptions without matches
+// are not notified. It returns false if there are no subscriptions.
+func (s *messageSubscriptions) Publish(messages []*protocol.Message) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.subs) == 0 {
+		return false
+	}
+	for id, matcher := range s.subs {
+		var matched []*protocol.Message
+		for _, message := range messages {
+			if matcher.matches(message) {
+				matched = append(matched, message)
+			}
+		}
+		if len(matched) > 0 {
+			s.send(id, matched)
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package ext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func testMessage(chatID, from string, contentType protobuf.ChatMessage_ContentType, timestamp uint64) *protocol.Message {
+	return &protocol.Message{
+		ChatMessage: protobuf.ChatMessage{ContentType: contentType, Timestamp: timestamp},
+		LocalChatID: chatID,
+		From:        from,
+	}
+}
+
+func TestMessagesMatcher(t *testing.T) {
+	message := testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 100)
+	testCases := []struct {
+		name    string
+		filter  MessagesFilter
+		matches bool
+	}{
+		{"empty filter", MessagesFilter{}, true},
+		{"chat", MessagesFilter{ChatIDs: []string{"other", "status"}}, true},
+		{"other chat", MessagesFilter{ChatIDs: []string{"other"}}, false},
+		{"author in upper case", MessagesFilter{From: []string{"0x04AB"}}, true},
+		{"other author", MessagesFilter{From: []string{"0x04cd"}}, false},
+		{"content type", MessagesFilter{ContentTypes: []protobuf.ChatMessage_ContentType{protobuf.ChatMessage_TEXT_PLAIN}}, true},
+		{"other content type", MessagesFilter{ContentTypes: []protobuf.ChatMessage_ContentType{protobuf.ChatMessage_STICKER}}, false},
+		{"inclusive time range", MessagesFilter{Since: 100, Until: 100}, true},
+		{"since only", MessagesFilter{Since: 50}, true},
+		{"after", MessagesFilter{Since: 101}, false},
+		{"before", MessagesFilter{Until: 99}, false},
+		{"every field", MessagesFilter{ChatIDs: []string{"status"}, From: []string{"0x04ab"}, Since: 100}, true},
+		{"one field differs", MessagesFilter{ChatIDs: []string{"status"}, From: []string{"0x04cd"}}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.matches, newMessagesMatcher(tc.filter).matches(message))
+		})
+	}
+}
+
+func TestMessageSubscriptions(t *testing.T) {
+	subscriptions := newMessageSubscriptions()
+	sent := make(map[string][]*protocol.Message)
+	subscriptions.send = func(id string, messages []*protocol.Message) {
+		sent[id] = append(sent[id], messages...)
+	}
+
+	first := testMessage("status", "0x04ab", protobuf.ChatMessage_TEXT_PLAIN, 100)
+	second := testMessage("other", "0x04ab", protobuf.ChatMessage_STICKER, 200)
+	require.False(t, subscriptions.Publish([]*protocol.Message{first, second}))
+
+	_, err := subscriptions.Subscribe(MessagesFilter{Since: 2, Until: 1})
+	require.Equal(t, ErrInvalidMessagesTimeRange, err)
+
+	status, err := subscriptions.Subscribe(MessagesFilter{ChatIDs: []string{"status"}})
+	require.NoError(t, err)
+	stickers, err := subscriptions.Subscribe(MessagesFilter{ContentTypes: []protobuf.ChatMessage_ContentType{protobuf.ChatMessage_STICKER}})
+	require.NoError(t, err)
+	none, err := subscriptions.Subscribe(MessagesFilter{From: []string{"0x04cd"}})
+	require.NoError(t, err)
+
+	require.True(t, subscriptions.Publish([]*protocol.Message{first, second}))
+	require.Equal(t, []*protocol.Message{first}, sent[status])
+	require.Equal(t, []*protocol.Message{second}, sent[stickers])
+	require.NotContains(t, sent, none)
+
+	require.NoError(t, subscriptions.Unsubscribe(status))
+	require.Equal(t, ErrMessagesSubscriptionNotFound, subscriptions.Unsubscribe(status))
+	subscriptions.Clear()
+	require.False(t, subscriptions.Publish([]*protocol.Message{first}))
+}
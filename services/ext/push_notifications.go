@@ -0,0 +1,42 @@
+package ext
+
+import (
+	"github.com/status-im/status-go/notifier"
+	"github.com/status-im/status-go/protocol"
+)
+
+// pushNotificationMessage is shown on devices, the content of messages is never sent to the gateway.
+const pushNotificationMessage = "You have a new message"
+
+// notifierGateway delivers push notifications with a gorush-compatible gateway.
+type notifierGateway struct {
+	notifier *notifier.Notifier
+}
+
+func newNotifierGateway(url string) *notifierGateway {
+	return &notifierGateway{notifier: notifier.New(url)}
+}
+
+// Push sends a single gorush request with iOS and Android tokens grouped by platform.
+func (g *notifierGateway) Push(deliveries []*protocol.PushNotificationDelivery) error {
+	var ios, android []string
+	for _, delivery := range deliveries {
+		switch delivery.TokenType {
+		case protocol.PushNotificationTokenAPN:
+			ios = append(ios, delivery.DeviceToken)
+		case protocol.PushNotificationTokenFirebase:
+			android = append(android, delivery.DeviceToken)
+		}
+	}
+	var notifications []*notifier.Notification
+	if len(ios) > 0 {
+		notifications = append(notifications, &notifier.Notification{Tokens: ios, Platform: notifier.IOS, Message: pushNotificationMessage})
+	}
+	if len(android) > 0 {
+		notifications = append(notifications, &notifier.Notification{Tokens: android, Platform: notifier.Android, Message: pushNotificationMessage})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	return g.notifier.Send(notifications)
+}
@@ -0,0 +1,36 @@
+package ext
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/notifier"
+	"github.com/status-im/status-go/protocol"
+)
+
+func TestNotifierGatewayPush(t *testing.T) {
+	var received struct {
+		Notifications []*notifier.Notification `json:"notifications"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/push", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"logs":[]}`))
+	}))
+	defer server.Close()
+
+	gateway := newNotifierGateway(server.URL)
+	require.NoError(t, gateway.Push([]*protocol.PushNotificationDelivery{
+		{TokenType: protocol.PushNotificationTokenAPN, DeviceToken: "apn-1"},
+		{TokenType: protocol.PushNotificationTokenFirebase, DeviceToken: "fcm-1"},
+		{TokenType: protocol.PushNotificationTokenAPN, DeviceToken: "apn-2"},
+	}))
+	require.Equal(t, []*notifier.Notification{
+		{Tokens: []string{"apn-1", "apn-2"}, Platform: notifier.IOS, Message: pushNotificationMessage},
+		{Tokens: []string{"fcm-1"}, Platform: notifier.Android, Message: pushNotificationMessage},
+	}, received.Notifications)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol"
 )
 
 // SendPublicMessageRPC represents the RPC payload for the SendPublicMessage RPC method
@@ -65,3 +66,29 @@ func (m JoinRPC) PublicKey() *ecdsa.PublicKey {
 	publicKey, _ := crypto.UnmarshalPubkey(m.PubKey)
 	return publicKey
 }
+
+// SendRawMessageOptionsRPC represents options of the SendRawMessage RPC method.
+type SendRawMessageOptionsRPC struct {
+	TTL       uint32         `json:"ttl"`
+	PowTarget float64        `json:"powTarget"`
+	PowTime   uint32         `json:"powTime"`
+	SymKey    types.HexBytes `json:"symKey"`
+	PubKey    types.HexBytes `json:"pubKey"`
+}
+
+func (m SendRawMessageOptionsRPC) options() (protocol.RawEnvelopeOptions, error) {
+	opts := protocol.RawEnvelopeOptions{
+		TTL:       m.TTL,
+		PowTarget: m.PowTarget,
+		PowTime:   m.PowTime,
+		SymKey:    m.SymKey,
+	}
+	if len(m.PubKey) > 0 {
+		publicKey, err := crypto.UnmarshalPubkey(m.PubKey)
+		if err != nil {
+			return opts, ErrInvalidPublicKey
+		}
+		opts.PublicKey = publicKey
+	}
+	return opts, nil
+}
@@ -33,6 +33,7 @@ import (
 	"go.uber.org/zap"
 
 	coretypes "github.com/status-im/status-go/eth-node/core/types"
+	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/protocol"
 	"github.com/status-im/status-go/protocol/transport"
@@ -69,7 +70,9 @@ type Service struct {
 	cache            *mailservers.Cache
 	connManager      *mailservers.ConnectionManager
 	lastUsedMonitor  *mailservers.LastUsedConnectionMonitor
+	mailServers      *mailservers.Registry
 	accountsDB       *accounts.Database
+	subscriptions    *messageSubscriptions
 }
 
 // Make sure that Service implements node.Service interface.
@@ -94,6 +97,7 @@ func New(
 		peerStore:        peerStore,
 		cache:            mailservers.NewCache(ldb),
 		eventSub:         eventSub,
+		subscriptions:    newMessageSubscriptions(),
 	}
 }
 
@@ -110,6 +114,9 @@ func (s *Service) RequestsRegistry() *RequestsRegistry {
 
 func (s *Service) GetPeer(rawURL string) (*enode.Node, error) {
 	if len(rawURL) == 0 {
+		if s.mailServers != nil {
+			return s.mailServers.Active()
+		}
 		return mailservers.GetFirstConnected(s.server, s.peerStore)
 	}
 	return enode.ParseV4(rawURL)
@@ -153,6 +160,42 @@ func (s *Service) InitProtocol(identity *ecdsa.PrivateKey, db *sql.DB) error {
 		Logger:                zapLogger,
 	}
 	options := buildMessengerOptions(s.config, db, envelopesMonitorConfig, zapLogger)
+	if s.config.OutboxEnabled {
+		options = append(options, protocol.WithOutbox(protocol.OutboxConfig{
+			MaxAttempts: s.config.OutboxMaxAttempts,
+			MaxAge:      s.config.OutboxMaxAge,
+			IsConnected: s.isConnected,
+			Handler:     OutboxSignalHandler{},
+		}))
+	}
+	if s.config.ChatIndicatorsEnabled {
+		options = append(options, protocol.WithChatIndicators(protocol.ChatIndicatorsConfig{
+			MinInterval: s.config.ChatIndicatorsMinInterval,
+			Handler:     ChatIndicatorSignalHandler{},
+		}))
+	}
+	if s.config.FilterSilencePeriod > 0 {
+		options = append(options, protocol.WithFilterStats(protocol.FilterStatsConfig{
+			SilencePeriod: s.config.FilterSilencePeriod,
+			Handler:       FilterStatsSignalHandler{},
+		}))
+	}
+	if s.config.PushNotificationServer != "" {
+		server, err := decodePublicKey(s.config.PushNotificationServer)
+		if err != nil {
+			return err
+		}
+		options = append(options, protocol.WithPushNotifications(protocol.PushNotificationsConfig{
+			Server:       server,
+			OfflineAfter: s.config.PushNotificationsOfflineAfter,
+			Handler:      PushNotificationSignalHandler{},
+		}))
+	}
+	if s.config.PushNotificationServerEnabled {
+		options = append(options, protocol.WithPushNotificationServer(protocol.PushNotificationServerConfig{
+			Gateway: newNotifierGateway(s.config.PushNotificationGatewayURL),
+		}))
+	}
 
 	messenger, err := protocol.NewMessenger(
 		identity,
@@ -168,36 +211,75 @@ func (s *Service) InitProtocol(identity *ecdsa.PrivateKey, db *sql.DB) error {
 	return messenger.Init()
 }
 
+// isConnected returns true if there is at least one peer to send messages to.
+func (s *Service) isConnected() bool {
+	return s.server != nil && s.server.PeerCount() > 0
+}
+
 func (s *Service) StartMessenger() error {
 	// Start a loop that retrieves all messages and propagates them to status-react.
+	// Loops keep the messenger they were started with, it is replaced when another account is logged in.
 	s.cancelMessenger = make(chan struct{})
-	go s.retrieveMessagesLoop(time.Second, s.cancelMessenger)
-	go s.verifyTransactionLoop(30*time.Second, s.cancelMessenger)
-	go s.verifyENSLoop(30*time.Second, s.cancelMessenger)
+	go s.retrieveMessagesLoop(s.messenger, time.Second, s.cancelMessenger)
+	go s.verifyTransactionLoop(s.messenger, 30*time.Second, s.cancelMessenger)
+	go s.verifyENSLoop(s.messenger, 30*time.Second, s.cancelMessenger)
+	go s.publishPublicChatsLoop(s.messenger, time.Hour, s.cancelMessenger)
 	return s.messenger.Start()
 }
 
-func (s *Service) retrieveMessagesLoop(tick time.Duration, cancel <-chan struct{}) {
+func (s *Service) retrieveMessagesLoop(messenger *protocol.Messenger, tick time.Duration, cancel <-chan struct{}) {
 	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
+	var summary *inboxSummary
+	if s.mailMonitor != nil && s.identity != nil {
+		summary = newInboxSummary(types.EncodeHex(crypto.FromECDSAPub(&s.identity.PublicKey)))
+	}
+
 	for {
 		select {
 		case <-ticker.C:
-			response, err := s.messenger.RetrieveAll()
+			pending := 0
+			if summary != nil {
+				pending = s.mailMonitor.Pending()
+			}
+			response, err := messenger.RetrieveAll()
 			if err != nil {
 				log.Error("failed to retrieve raw messages", "err", err)
 				continue
 			}
-			if !response.IsEmpty() {
-				PublisherSignalHandler{}.NewMessages(response)
+			if summary != nil {
+				if chats, completed := summary.Update(pending, response.Messages); completed {
+					PublisherSignalHandler{}.InboxSummary(chats)
+				}
 			}
+			s.publishMessages(response)
 		case <-cancel:
 			return
 		}
 	}
 }
 
+// publishMessages sends messages of the response to matching subscriptions. While there are
+// subscriptions messages are not included in the messages.new signal. Mentions of the user
+// are sent with the messages.mentions signal.
+func (s *Service) publishMessages(response *protocol.MessengerResponse) {
+	if response.IsEmpty() {
+		return
+	}
+	if len(response.Mentions) > 0 {
+		PublisherSignalHandler{}.Mentions(response.Mentions)
+	}
+	if s.subscriptions.Publish(response.Messages) && len(response.Messages) > 0 {
+		withoutMessages := *response
+		withoutMessages.Messages = nil
+		response = &withoutMessages
+	}
+	if !response.IsEmpty() {
+		PublisherSignalHandler{}.NewMessages(response)
+	}
+}
+
 type verifyTransactionClient struct {
 	chainID *big.Int
 	url     string
@@ -262,7 +344,7 @@ func (c *verifyTransactionClient) TransactionByHash(ctx context.Context, hash ty
 	return coremessage, coretypes.TransactionStatus(receipt.Status), nil
 }
 
-func (s *Service) verifyENSLoop(tick time.Duration, cancel <-chan struct{}) {
+func (s *Service) verifyENSLoop(messenger *protocol.Messenger, tick time.Duration, cancel <-chan struct{}) {
 	if s.config.VerifyENSURL == "" || s.config.VerifyENSContractAddress == "" {
 		log.Warn("not starting ENS loop")
 		return
@@ -276,14 +358,12 @@ func (s *Service) verifyENSLoop(tick time.Duration, cancel <-chan struct{}) {
 	for {
 		select {
 		case <-ticker.C:
-			response, err := s.messenger.VerifyENSNames(ctx, s.config.VerifyENSURL, s.config.VerifyENSContractAddress)
+			response, err := messenger.VerifyENSNames(ctx, s.config.VerifyENSURL, s.config.VerifyENSContractAddress)
 			if err != nil {
 				log.Error("failed to validate ens", "err", err)
 				continue
 			}
-			if !response.IsEmpty() {
-				PublisherSignalHandler{}.NewMessages(response)
-			}
+			s.publishMessages(response)
 		case <-cancel:
 			cancelVerifyENS()
 			return
@@ -291,7 +371,30 @@ func (s *Service) verifyENSLoop(tick time.Duration, cancel <-chan struct{}) {
 	}
 }
 
-func (s *Service) verifyTransactionLoop(tick time.Duration, cancel <-chan struct{}) {
+func (s *Service) publishPublicChatsLoop(messenger *protocol.Messenger, tick time.Duration, cancel <-chan struct{}) {
+	if !s.config.PublicChatsDirectoryEnabled {
+		return
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	ctx, cancelPublish := context.WithCancel(context.Background())
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := messenger.PublishPublicChats(ctx); err != nil {
+				log.Error("failed to publish public chats", "err", err)
+			}
+		case <-cancel:
+			cancelPublish()
+			return
+		}
+	}
+}
+
+func (s *Service) verifyTransactionLoop(messenger *protocol.Messenger, tick time.Duration, cancel <-chan struct{}) {
 	if s.config.VerifyTransactionURL == "" {
 		log.Warn("not starting transaction loop")
 		return
@@ -316,14 +419,12 @@ func (s *Service) verifyTransactionLoop(tick time.Duration, cancel <-chan struct
 				}
 			}
 
-			response, err := s.messenger.ValidateTransactions(ctx, wallets)
+			response, err := messenger.ValidateTransactions(ctx, wallets)
 			if err != nil {
 				log.Error("failed to validate transactions", "err", err)
 				continue
 			}
-			if !response.IsEmpty() {
-				PublisherSignalHandler{}.NewMessages(response)
-			}
+			s.publishMessages(response)
 		case <-cancel:
 			cancelVerifyTransaction()
 			return
@@ -352,9 +453,17 @@ func (s *Service) UpdateMailservers(nodes []*enode.Node) error {
 	if s.connManager != nil {
 		s.connManager.Notify(nodes)
 	}
+	if s.mailServers != nil {
+		s.mailServers.Update(nodes)
+	}
 	return nil
 }
 
+// MailServers returns the mail server registry or nil if it is disabled.
+func (s *Service) MailServers() *mailservers.Registry {
+	return s.mailServers
+}
+
 // Protocols returns a new protocols list. In this case, there are none.
 func (s *Service) Protocols() []p2p.Protocol {
 	return []p2p.Protocol{}
@@ -388,6 +497,17 @@ func (s *Service) Start(server *p2p.Server) error {
 		s.lastUsedMonitor = mailservers.NewLastUsedConnectionMonitor(s.peerStore, s.cache, s.eventSub)
 		s.lastUsedMonitor.Start()
 	}
+	if s.config.EnableMailServerRegistry {
+		maxFailures := s.config.MaxServerFailures
+		if maxFailures == 0 {
+			maxFailures = 1
+		}
+		s.mailServers = mailservers.NewRegistry(server, s.eventSub, maxFailures, MailServerSignalHandler{})
+		s.mailServers.Start()
+		if err := mailservers.EnsureUsedRecordsAddedFirst(s.peerStore, s.mailServers); err != nil {
+			return err
+		}
+	}
 	s.mailMonitor.Start()
 	s.server = server
 	return nil
@@ -402,8 +522,19 @@ func (s *Service) Stop() error {
 	if s.config.EnableLastUsedMonitor {
 		s.lastUsedMonitor.Stop()
 	}
-	s.requestsRegistry.Clear()
+	if s.mailServers != nil {
+		s.mailServers.Stop()
+	}
 	s.mailMonitor.Stop()
+	return s.StopMessenger()
+}
+
+// StopMessenger stops the messenger of the logged in account and removes its filters,
+// mailserver requests and message subscriptions. The messenger can be initialized again
+// with InitProtocol for another account.
+func (s *Service) StopMessenger() error {
+	s.requestsRegistry.Clear()
+	s.subscriptions.Clear()
 
 	if s.cancelMessenger != nil {
 		select {
@@ -419,7 +550,9 @@ func (s *Service) Stop() error {
 		if err := s.messenger.Shutdown(); err != nil {
 			return err
 		}
+		s.messenger = nil
 	}
+	s.identity = nil
 
 	return nil
 }
@@ -462,6 +595,22 @@ func buildMessengerOptions(
 		options = append(options, protocol.WithDatasync())
 	}
 
+	if config.PublicChatsDirectoryEnabled {
+		options = append(options, protocol.WithPublicChatsDirectory())
+	}
+
+	if config.MessageSegmentSize > 0 {
+		options = append(options, protocol.WithMessageSegmentation(config.MessageSegmentSize))
+	}
+
+	if config.PartitionsCount > 0 || config.PreviousPartitionsCount > 0 {
+		options = append(options, protocol.WithPartitionsConfig(transport.PartitionsConfig{
+			Count:         config.PartitionsCount,
+			PreviousCount: config.PreviousPartitionsCount,
+			PreviousUntil: time.Unix(config.PreviousPartitionsUntil, 0),
+		}))
+	}
+
 	if config.VerifyTransactionURL != "" {
 		client := &verifyTransactionClient{
 			url:     config.VerifyTransactionURL,
@@ -472,3 +621,12 @@ func buildMessengerOptions(
 
 	return options
 }
+
+// decodePublicKey decodes a hex encoded uncompressed public key.
+func decodePublicKey(key string) (*ecdsa.PublicKey, error) {
+	b, err := types.DecodeHex(key)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPubkey(b)
+}
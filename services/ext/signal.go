@@ -1,6 +1,8 @@
 package ext
 
 import (
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/protocol"
 	"github.com/status-im/status-go/signal"
@@ -29,6 +31,55 @@ func (h EnvelopeSignalHandler) MailServerRequestExpired(hash types.Hash) {
 	signal.SendMailServerRequestExpired(hash)
 }
 
+// MailServerSignalHandler sends signals when the active mail server changes.
+type MailServerSignalHandler struct{}
+
+// ActiveMailServerChanged triggered when a mail server is selected, pinned or replaced after failures.
+func (h MailServerSignalHandler) ActiveMailServerChanged(node *enode.Node, pinned bool) {
+	var rawURL string
+	if node != nil {
+		rawURL = node.String()
+	}
+	signal.SendMailServerChanged(rawURL, pinned)
+}
+
+// OutboxSignalHandler sends signals when a postponed message is sent or dropped.
+type OutboxSignalHandler struct{}
+
+// OutboxMessageSent triggered when a postponed message was delivered at least to 1 peer.
+func (h OutboxSignalHandler) OutboxMessageSent(identifiers [][]byte) {
+	signal.SendOutboxMessageSent(identifiers)
+}
+
+// OutboxMessageFailed triggered when a postponed message was not delivered after all retries.
+func (h OutboxSignalHandler) OutboxMessageFailed(identifiers [][]byte, err error) {
+	signal.SendOutboxMessageFailed(identifiers, err)
+}
+
+// PushNotificationSignalHandler sends signals when the push notification registration changes.
+type PushNotificationSignalHandler struct{}
+
+// PushNotificationRegistrationChanged triggered when the server responds to a registration or the installation is unregistered.
+func (h PushNotificationSignalHandler) PushNotificationRegistrationChanged(registration protocol.PushNotificationRegistration) {
+	signal.SendPushNotificationRegistration(registration)
+}
+
+// ChatIndicatorSignalHandler sends signals when a typing or presence indicator is received.
+type ChatIndicatorSignalHandler struct{}
+
+// ChatIndicatorReceived triggered when an indicator from a member of a chat is accepted.
+func (h ChatIndicatorSignalHandler) ChatIndicatorReceived(indicator protocol.ChatIndicator) {
+	signal.SendChatIndicator(indicator)
+}
+
+// FilterStatsSignalHandler sends signals when a filter stops receiving envelopes.
+type FilterStatsSignalHandler struct{}
+
+// FilterSilent triggered when a listening filter received nothing for the configured period.
+func (h FilterStatsSignalHandler) FilterSilent(stats protocol.FilterStats) {
+	signal.SendFilterSilent(stats)
+}
+
 // PublisherSignalHandler sends signals on protocol events
 type PublisherSignalHandler struct{}
 
@@ -48,3 +99,15 @@ func (h PublisherSignalHandler) FilterAdded(filters []*signal.Filter) {
 func (h PublisherSignalHandler) NewMessages(response *protocol.MessengerResponse) {
 	signal.SendNewMessages(response)
 }
+
+func (h PublisherSignalHandler) InboxSummary(chats []signal.ChatInboxSummary) {
+	signal.SendInboxSummary(chats)
+}
+
+func (h PublisherSignalHandler) MessagesSubscription(subscriptionID string, messages []*protocol.Message) {
+	signal.SendMessagesSubscription(subscriptionID, messages)
+}
+
+func (h PublisherSignalHandler) Mentions(mentions []*protocol.Mention) {
+	signal.SendMentions(mentions)
+}
@@ -0,0 +1,39 @@
+package localsettings
+
+import (
+	"context"
+	"encoding/json"
+)
+
+func NewAPI(store *Store) *API {
+	return &API{store}
+}
+
+// API is class with methods available over RPC.
+type API struct {
+	store *Store
+}
+
+// GetSetting returns a value of a setting, null if it is not set.
+func (a *API) GetSetting(ctx context.Context, key Key) (json.RawMessage, error) {
+	value, err := a.store.GetRaw(key)
+	if err == ErrNotFound {
+		return json.RawMessage("null"), nil
+	}
+	return value, err
+}
+
+// SetSetting sets a value of a setting.
+func (a *API) SetSetting(ctx context.Context, key Key, value json.RawMessage) error {
+	return a.store.SetRaw(key, value)
+}
+
+// DeleteSetting deletes a setting.
+func (a *API) DeleteSetting(ctx context.Context, key Key) error {
+	return a.store.Delete(key)
+}
+
+// GetSettings returns all settings of a service.
+func (a *API) GetSettings(ctx context.Context, service string) (map[string]json.RawMessage, error) {
+	return a.store.All(service)
+}
@@ -0,0 +1,67 @@
+package localsettings
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/status-im/status-go/signal"
+)
+
+// NewService returns a Service exposing the store over RPC.
+func NewService(store *Store) *Service {
+	return &Service{store: store}
+}
+
+// Service exposes local settings over RPC and sends a signal for every change.
+type Service struct {
+	store *Store
+	sub   event.Subscription
+	wg    sync.WaitGroup
+}
+
+// Start forwards changes of settings to signals.
+func (s *Service) Start(*p2p.Server) error {
+	changes := make(chan Change, 10)
+	s.sub = s.store.Subscribe(changes)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case change := <-changes:
+				signal.SendLocalSettingChanged(change)
+			case <-s.sub.Err():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sending signals.
+func (s *Service) Stop() error {
+	if s.sub != nil {
+		s.sub.Unsubscribe()
+		s.wg.Wait()
+		s.sub = nil
+	}
+	return nil
+}
+
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "localsettings",
+			Version:   "0.1.0",
+			Service:   NewAPI(s.store),
+			Public:    true,
+		},
+	}
+}
+
+func (s *Service) Protocols() []p2p.Protocol {
+	return nil
+}
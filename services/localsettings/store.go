@@ -0,0 +1,205 @@
+package localsettings
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	// ErrNotFound is returned when a setting was never set or was deleted.
+	ErrNotFound = errors.New("setting not found")
+	// ErrInvalidKey is returned for keys without a service or a name.
+	ErrInvalidKey = errors.New("setting key must have a service and a name")
+)
+
+// Key identifies a setting. Settings are grouped by the service that owns them.
+type Key struct {
+	Service string `json:"service"`
+	Name    string `json:"name"`
+}
+
+// Settings shared by services.
+var (
+	// WalletCurrency is a currency of fiat values shown in the wallet, e.g. "usd".
+	WalletCurrency = Key{Service: "wallet", Name: "currency"}
+	// ShhextMailserver is an enode of the mailserver selected by the user.
+	ShhextMailserver = Key{Service: "shhext", Name: "mailserver"}
+	// NodeNotificationsEnabled enables notifications about new messages and transfers.
+	NodeNotificationsEnabled = Key{Service: "node", Name: "notificationsEnabled"}
+)
+
+func (k Key) validate() error {
+	if k.Service == "" || k.Name == "" {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Change is sent to subscribers when a setting is set to a new value or deleted.
+type Change struct {
+	Key
+	// Value is a JSON encoded value, nil if the setting was deleted.
+	Value json.RawMessage `json:"value"`
+}
+
+// Store keeps settings as JSON values in the app database, which is encrypted
+// with the password of the account. A single Store is shared by services of a node,
+// so that they are notified about changes made by each other.
+type Store struct {
+	db   *sql.DB
+	feed event.Feed
+}
+
+// NewStore returns a Store of settings in the app database.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Subscribe subscribes to changes of settings.
+func (s *Store) Subscribe(ch chan<- Change) event.Subscription {
+	return s.feed.Subscribe(ch)
+}
+
+// GetRaw returns a JSON encoded value of a setting.
+func (s *Store) GetRaw(key Key) (json.RawMessage, error) {
+	if err := key.validate(); err != nil {
+		return nil, err
+	}
+	var value []byte
+	err := s.db.QueryRow("SELECT value FROM local_settings WHERE service = ? AND name = ?", key.Service, key.Name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+// SetRaw sets a JSON encoded value of a setting. Subscribers are notified only if the value changed.
+func (s *Store) SetRaw(key Key, value json.RawMessage) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+	if !json.Valid(value) {
+		return errors.New("setting value is not valid json")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return err
+	}
+	value = buf.Bytes()
+	current, err := s.GetRaw(key)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if err == nil && bytes.Equal(current, value) {
+		return nil
+	}
+	_, err = s.db.Exec("INSERT OR REPLACE INTO local_settings (service, name, value, updated_at) VALUES (?, ?, ?, ?)",
+		key.Service, key.Name, []byte(value), time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	s.feed.Send(Change{Key: key, Value: value})
+	return nil
+}
+
+// Get decodes a value of a setting into value.
+func (s *Store) Get(key Key, value interface{}) error {
+	raw, err := s.GetRaw(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, value)
+}
+
+// Set encodes value and sets it as a value of a setting.
+func (s *Store) Set(key Key, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.SetRaw(key, raw)
+}
+
+// Delete deletes a setting, getters return ErrNotFound or a default value afterwards.
+func (s *Store) Delete(key Key) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+	result, err := s.db.Exec("DELETE FROM local_settings WHERE service = ? AND name = ?", key.Service, key.Name)
+	if err != nil {
+		return err
+	}
+	if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+		return err
+	}
+	s.feed.Send(Change{Key: key})
+	return nil
+}
+
+// All returns JSON encoded values of all settings of a service keyed by their names.
+func (s *Store) All(service string) (map[string]json.RawMessage, error) {
+	rows, err := s.db.Query("SELECT name, value FROM local_settings WHERE service = ?", service)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rst := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var (
+			name  string
+			value []byte
+		)
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		rst[name] = value
+	}
+	return rst, rows.Err()
+}
+
+// GetString returns a string setting or def if it is not set.
+func (s *Store) GetString(key Key, def string) (string, error) {
+	value := def
+	if err := s.Get(key, &value); err != nil && err != ErrNotFound {
+		return def, err
+	}
+	return value, nil
+}
+
+// SetString sets a string setting.
+func (s *Store) SetString(key Key, value string) error {
+	return s.Set(key, value)
+}
+
+// GetBool returns a boolean setting or def if it is not set.
+func (s *Store) GetBool(key Key, def bool) (bool, error) {
+	value := def
+	if err := s.Get(key, &value); err != nil && err != ErrNotFound {
+		return def, err
+	}
+	return value, nil
+}
+
+// SetBool sets a boolean setting.
+func (s *Store) SetBool(key Key, value bool) error {
+	return s.Set(key, value)
+}
+
+// GetInt64 returns an integer setting or def if it is not set.
+func (s *Store) GetInt64(key Key, def int64) (int64, error) {
+	value := def
+	if err := s.Get(key, &value); err != nil && err != ErrNotFound {
+		return def, err
+	}
+	return value, nil
+}
+
+// SetInt64 sets an integer setting.
+func (s *Store) SetInt64(key Key, value int64) error {
+	return s.Set(key, value)
+}
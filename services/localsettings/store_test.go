@@ -0,0 +1,79 @@
+package localsettings
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/appdatabase"
+)
+
+func setupTestStore(t *testing.T) (*Store, func()) {
+	tmpfile, err := ioutil.TempFile("", "localsettings-tests-")
+	require.NoError(t, err)
+	db, err := appdatabase.InitializeDB(tmpfile.Name(), "localsettings-tests")
+	require.NoError(t, err)
+	return NewStore(db), func() {
+		require.NoError(t, db.Close())
+		require.NoError(t, os.Remove(tmpfile.Name()))
+	}
+}
+
+func TestTypedSettings(t *testing.T) {
+	store, stop := setupTestStore(t)
+	defer stop()
+
+	currency, err := store.GetString(WalletCurrency, "usd")
+	require.NoError(t, err)
+	require.Equal(t, "usd", currency)
+	require.NoError(t, store.SetString(WalletCurrency, "eur"))
+	currency, err = store.GetString(WalletCurrency, "usd")
+	require.NoError(t, err)
+	require.Equal(t, "eur", currency)
+
+	require.NoError(t, store.SetBool(NodeNotificationsEnabled, true))
+	enabled, err := store.GetBool(NodeNotificationsEnabled, false)
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	limit := Key{Service: "wallet", Name: "limit"}
+	require.NoError(t, store.SetInt64(limit, 10))
+	value, err := store.GetInt64(limit, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), value)
+	_, err = store.GetBool(limit, false)
+	require.Error(t, err)
+
+	all, err := store.All("wallet")
+	require.NoError(t, err)
+	require.Equal(t, map[string]json.RawMessage{"currency": json.RawMessage(`"eur"`), "limit": json.RawMessage(`10`)}, all)
+
+	require.NoError(t, store.Delete(WalletCurrency))
+	_, err = store.GetRaw(WalletCurrency)
+	require.Equal(t, ErrNotFound, err)
+
+	require.Equal(t, ErrInvalidKey, store.SetString(Key{Service: "wallet"}, "eur"))
+	require.Error(t, store.SetRaw(ShhextMailserver, json.RawMessage(`{`)))
+}
+
+func TestSettingsChanges(t *testing.T) {
+	store, stop := setupTestStore(t)
+	defer stop()
+
+	changes := make(chan Change, 10)
+	sub := store.Subscribe(changes)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, store.SetRaw(ShhextMailserver, json.RawMessage(`{ "enode": "enode://a" }`)))
+	require.Equal(t, Change{Key: ShhextMailserver, Value: json.RawMessage(`{"enode":"enode://a"}`)}, <-changes)
+
+	// unchanged values are not sent
+	require.NoError(t, store.Set(ShhextMailserver, map[string]string{"enode": "enode://a"}))
+	require.NoError(t, store.Delete(ShhextMailserver))
+	require.Equal(t, Change{Key: ShhextMailserver}, <-changes)
+	require.NoError(t, store.Delete(ShhextMailserver))
+	require.Len(t, changes, 0)
+}
@@ -46,3 +46,43 @@ func (api *PublicAPI) Discover(context context.Context, req DiscoverRequest) (er
 	}
 	return api.s.d.Discover(req.Topic, req.Max, req.Min)
 }
+
+// AdminAPI manages static peers and trusted mailservers of the status node.
+// Peers added with the API are persisted and reconnected when dropped.
+type AdminAPI struct {
+	s *Service
+}
+
+// NewAdminAPI creates an instance of the admin API.
+func NewAdminAPI(s *Service) *AdminAPI {
+	return &AdminAPI{s: s}
+}
+
+// AddStaticPeer is an implementation of `admin_addStaticPeer` API.
+func (api *AdminAPI) AddStaticPeer(context context.Context, enode string) error {
+	return api.s.manager.AddStaticPeer(enode)
+}
+
+// AddTrustedMailserver is an implementation of `admin_addTrustedMailserver` API.
+func (api *AdminAPI) AddTrustedMailserver(context context.Context, enode string) error {
+	return api.s.manager.AddTrustedMailserver(enode)
+}
+
+// RemoveStaticPeer is an implementation of `admin_removeStaticPeer` API.
+// It removes a static peer or a trusted mailserver.
+func (api *AdminAPI) RemoveStaticPeer(context context.Context, enode string) error {
+	return api.s.manager.RemovePeer(enode)
+}
+
+// RemoveTrustedPeer is an implementation of `admin_removeTrustedPeer` API.
+// It removes a static peer or a trusted mailserver, it replaces the method
+// of the p2p server that doesn't remove persisted peers.
+func (api *AdminAPI) RemoveTrustedPeer(context context.Context, enode string) error {
+	return api.s.manager.RemovePeer(enode)
+}
+
+// GetPeers is an implementation of `admin_getPeers` API.
+// It returns static peers and trusted mailservers followed by other connected peers.
+func (api *AdminAPI) GetPeers(context context.Context) ([]PeerInfo, error) {
+	return api.s.manager.Peers()
+}
@@ -23,7 +23,7 @@ type PeerSuite struct {
 func (s *PeerSuite) SetupTest() {
 	ctrl := gomock.NewController(s.T())
 	s.d = NewMockDiscoverer(ctrl)
-	s.s = New()
+	s.s = New(nil)
 	s.api = NewAPI(s.s)
 }
 
@@ -0,0 +1,322 @@
+package peer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// minReconnectBackoff is a delay of the first reconnect of a dropped peer.
+	minReconnectBackoff = 5 * time.Second
+	// maxReconnectBackoff limits the delay between reconnects.
+	maxReconnectBackoff = 5 * time.Minute
+	// dialTimeout is how long a peer is dialed before the next attempt is delayed.
+	dialTimeout = 30 * time.Second
+)
+
+var (
+	// ErrPeerNotManaged is returned when a peer was not added with the API.
+	ErrPeerNotManaged = errors.New("peer was not added as a static peer or a trusted mailserver")
+	// ErrManagerNotStarted is returned when the node is not running.
+	ErrManagerNotStarted = errors.New("peer manager is not started")
+)
+
+// peerServer is a subset of p2p.Server used by the manager.
+type peerServer interface {
+	AddPeer(*enode.Node)
+	RemovePeer(*enode.Node)
+	AddTrustedPeer(*enode.Node)
+	RemoveTrustedPeer(*enode.Node)
+	SubscribeEvents(chan *p2p.PeerEvent) event.Subscription
+	PeersInfo() []*p2p.PeerInfo
+}
+
+// PeerInfo is a managed or a connected peer.
+type PeerInfo struct {
+	ID    string `json:"id"`
+	Enode string `json:"enode"`
+	Name  string `json:"name"`
+	// Static is true for peers added with the API, they are reconnected when dropped.
+	Static bool `json:"static"`
+	// Mailserver is true for trusted mailservers.
+	Mailserver bool `json:"mailserver"`
+	Connected  bool `json:"connected"`
+	// Failures is a number of reconnects since the peer was dropped.
+	Failures int `json:"failures"`
+}
+
+type managedPeer struct {
+	node       *enode.Node
+	mailserver bool
+	connected  bool
+	dialing    bool
+	failures   int
+	// deadline is the time of the next dial, or the end of the current dial if dialing
+	deadline time.Time
+}
+
+// Manager keeps static peers and trusted mailservers added at runtime connected.
+// Peers are persisted and added again when the node is started. A dropped peer
+// is reconnected with an exponential backoff, its dial is stopped in between, so
+// that the p2p server doesn't redial it on its own.
+type Manager struct {
+	store *Store
+
+	mu     sync.Mutex
+	server peerServer
+	peers  map[enode.ID]*managedPeer
+
+	period time.Duration
+	now    func() time.Time
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewManager returns a Manager of peers persisted in the store. Peers are not persisted if the store is nil.
+func NewManager(store *Store) *Manager {
+	return &Manager{
+		store:  store,
+		peers:  make(map[enode.ID]*managedPeer),
+		period: time.Second,
+		now:    time.Now,
+	}
+}
+
+// Start adds persisted peers to the server and starts reconnecting dropped peers.
+func (m *Manager) Start(server peerServer) error {
+	var persisted []ManagedPeer
+	if m.store != nil {
+		var err error
+		persisted, err = m.store.All()
+		if err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	m.server = server
+	for _, p := range persisted {
+		node, err := enode.ParseV4(p.Enode)
+		if err != nil {
+			log.Error("invalid persisted peer", "enode", p.Enode, "err", err)
+			continue
+		}
+		m.add(node, p.Mailserver)
+	}
+	m.mu.Unlock()
+
+	events := make(chan *p2p.PeerEvent, 10)
+	sub := server.SubscribeEvents(events)
+	m.quit = make(chan struct{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.loop(events, sub)
+	}()
+	return nil
+}
+
+// Stop stops reconnecting peers.
+func (m *Manager) Stop() {
+	if m.quit == nil {
+		return
+	}
+	close(m.quit)
+	m.wg.Wait()
+	m.quit = nil
+}
+
+func (m *Manager) loop(events chan *p2p.PeerEvent, sub event.Subscription) {
+	defer sub.Unsubscribe()
+	ticker := time.NewTicker(m.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.quit:
+			return
+		case err := <-sub.Err():
+			log.Error("peer events subscription failed", "err", err)
+			return
+		case ev := <-events:
+			m.handleEvent(ev)
+		case <-ticker.C:
+			m.reconnect()
+		}
+	}
+}
+
+// AddStaticPeer adds a static peer and persists it.
+func (m *Manager) AddStaticPeer(url string) error {
+	return m.addPeer(url, false)
+}
+
+// AddTrustedMailserver adds a mailserver as a static and trusted peer and persists it.
+// Trusted peers are connected even if the maximum number of peers is reached.
+func (m *Manager) AddTrustedMailserver(url string) error {
+	return m.addPeer(url, true)
+}
+
+func (m *Manager) addPeer(url string, mailserver bool) error {
+	node, err := enode.ParseV4(url)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return ErrManagerNotStarted
+	}
+	if m.store != nil {
+		if err := m.store.Add(node.ID(), ManagedPeer{Enode: node.URLv4(), Mailserver: mailserver}); err != nil {
+			return err
+		}
+	}
+	if p, ok := m.peers[node.ID()]; ok {
+		// replaced peers keep their connection
+		if p.mailserver && !mailserver {
+			m.server.RemoveTrustedPeer(p.node)
+		}
+		if mailserver {
+			m.server.AddTrustedPeer(node)
+		}
+		p.node = node
+		p.mailserver = mailserver
+		return nil
+	}
+	m.add(node, mailserver)
+	return nil
+}
+
+func (m *Manager) add(node *enode.Node, mailserver bool) {
+	if mailserver {
+		m.server.AddTrustedPeer(node)
+	}
+	m.server.AddPeer(node)
+	m.peers[node.ID()] = &managedPeer{
+		node:       node,
+		mailserver: mailserver,
+		dialing:    true,
+		deadline:   m.now().Add(dialTimeout),
+	}
+}
+
+// RemovePeer removes a static peer or a trusted mailserver, it is disconnected and not persisted anymore.
+func (m *Manager) RemovePeer(url string) error {
+	node, err := enode.ParseV4(url)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.peers[node.ID()]
+	if !ok {
+		return ErrPeerNotManaged
+	}
+	if m.store != nil {
+		if err := m.store.Delete(node.ID()); err != nil {
+			return err
+		}
+	}
+	delete(m.peers, node.ID())
+	if p.mailserver {
+		m.server.RemoveTrustedPeer(p.node)
+	}
+	m.server.RemovePeer(p.node)
+	return nil
+}
+
+// Peers returns managed peers followed by other connected peers.
+func (m *Manager) Peers() ([]PeerInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return nil, ErrManagerNotStarted
+	}
+	rst := make([]PeerInfo, 0, len(m.peers))
+	for _, p := range m.peers {
+		rst = append(rst, PeerInfo{
+			ID:         p.node.ID().String(),
+			Enode:      p.node.URLv4(),
+			Static:     true,
+			Mailserver: p.mailserver,
+			Connected:  p.connected,
+			Failures:   p.failures,
+		})
+	}
+	sort.Slice(rst, func(i, j int) bool { return rst[i].ID < rst[j].ID })
+	managed := len(rst)
+	for _, info := range m.server.PeersInfo() {
+		i := sort.Search(managed, func(i int) bool { return rst[i].ID >= info.ID })
+		if i < managed && rst[i].ID == info.ID {
+			rst[i].Name = info.Name
+			continue
+		}
+		rst = append(rst, PeerInfo{ID: info.ID, Enode: info.Enode, Name: info.Name, Connected: true})
+	}
+	return rst, nil
+}
+
+func (m *Manager) handleEvent(ev *p2p.PeerEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.peers[ev.Peer]
+	if !ok {
+		return
+	}
+	switch ev.Type {
+	case p2p.PeerEventTypeAdd:
+		p.connected = true
+		p.dialing = false
+		p.failures = 0
+	case p2p.PeerEventTypeDrop:
+		log.Debug("managed peer dropped", "enode", p.node.URLv4(), "err", ev.Error)
+		p.connected = false
+		m.delay(p)
+	}
+}
+
+// reconnect dials peers whose backoff expired and delays peers that were not connected within the dial timeout.
+func (m *Manager) reconnect() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for _, p := range m.peers {
+		if p.connected || now.Before(p.deadline) {
+			continue
+		}
+		if p.dialing {
+			m.delay(p)
+			continue
+		}
+		log.Debug("reconnecting managed peer", "enode", p.node.URLv4(), "failures", p.failures)
+		m.server.AddPeer(p.node)
+		p.dialing = true
+		p.deadline = now.Add(dialTimeout)
+	}
+}
+
+// delay stops dialing a peer until its backoff expires.
+func (m *Manager) delay(p *managedPeer) {
+	m.server.RemovePeer(p.node)
+	p.dialing = false
+	p.deadline = m.now().Add(reconnectBackoff(p.failures))
+	p.failures++
+}
+
+func reconnectBackoff(failures int) time.Duration {
+	backoff := minReconnectBackoff
+	for i := 0; i < failures && backoff < maxReconnectBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxReconnectBackoff {
+		return maxReconnectBackoff
+	}
+	return backoff
+}
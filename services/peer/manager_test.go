@@ -0,0 +1,149 @@
+package peer
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/db"
+)
+
+type testServer struct {
+	feed    event.Feed
+	static  map[enode.ID]bool
+	trusted map[enode.ID]bool
+	dials   int
+	info    []*p2p.PeerInfo
+}
+
+func newTestServer() *testServer {
+	return &testServer{static: make(map[enode.ID]bool), trusted: make(map[enode.ID]bool)}
+}
+
+func (s *testServer) AddPeer(n *enode.Node) {
+	s.static[n.ID()] = true
+	s.dials++
+}
+
+func (s *testServer) RemovePeer(n *enode.Node)        { delete(s.static, n.ID()) }
+func (s *testServer) AddTrustedPeer(n *enode.Node)    { s.trusted[n.ID()] = true }
+func (s *testServer) RemoveTrustedPeer(n *enode.Node) { delete(s.trusted, n.ID()) }
+func (s *testServer) PeersInfo() []*p2p.PeerInfo      { return s.info }
+
+func (s *testServer) SubscribeEvents(ch chan *p2p.PeerEvent) event.Subscription {
+	return s.feed.Subscribe(ch)
+}
+
+func testNode(t *testing.T) *enode.Node {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return enode.NewV4(&key.PublicKey, net.ParseIP("127.0.0.1"), 30303, 30303)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	require.Equal(t, minReconnectBackoff, reconnectBackoff(0))
+	require.Equal(t, 4*minReconnectBackoff, reconnectBackoff(2))
+	require.Equal(t, maxReconnectBackoff, reconnectBackoff(100))
+}
+
+func TestManagerPersistsPeers(t *testing.T) {
+	ldb, err := db.NewMemoryDB()
+	require.NoError(t, err)
+	store := NewStore(ldb)
+	static, mailserver := testNode(t), testNode(t)
+
+	m := NewManager(store)
+	require.Equal(t, ErrManagerNotStarted, m.AddStaticPeer(static.URLv4()))
+	server := newTestServer()
+	require.NoError(t, m.Start(server))
+	require.NoError(t, m.AddStaticPeer(static.URLv4()))
+	require.NoError(t, m.AddTrustedMailserver(mailserver.URLv4()))
+	require.Error(t, m.AddStaticPeer("invalid"))
+	require.True(t, server.static[static.ID()])
+	require.False(t, server.trusted[static.ID()])
+	require.True(t, server.static[mailserver.ID()])
+	require.True(t, server.trusted[mailserver.ID()])
+	m.Stop()
+
+	// peers are added again when the node is started
+	m = NewManager(store)
+	server = newTestServer()
+	server.info = []*p2p.PeerInfo{{ID: mailserver.ID().String(), Name: "mailserver"}, {ID: "other", Enode: "enode://other"}}
+	require.NoError(t, m.Start(server))
+	defer m.Stop()
+	require.True(t, server.static[static.ID()])
+	require.True(t, server.trusted[mailserver.ID()])
+
+	peers, err := m.Peers()
+	require.NoError(t, err)
+	require.Len(t, peers, 3)
+	require.Equal(t, PeerInfo{ID: "other", Enode: "enode://other", Connected: true}, peers[2])
+	for _, p := range peers[:2] {
+		require.True(t, p.Static)
+		require.Equal(t, p.ID == mailserver.ID().String(), p.Mailserver)
+		if p.Mailserver {
+			require.Equal(t, "mailserver", p.Name)
+		}
+	}
+
+	require.NoError(t, m.RemovePeer(mailserver.URLv4()))
+	require.Equal(t, ErrPeerNotManaged, m.RemovePeer(mailserver.URLv4()))
+	require.False(t, server.static[mailserver.ID()])
+	require.False(t, server.trusted[mailserver.ID()])
+	persisted, err := store.All()
+	require.NoError(t, err)
+	require.Equal(t, []ManagedPeer{{Enode: static.URLv4()}}, persisted)
+}
+
+func TestManagerReconnects(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewManager(nil)
+	m.now = func() time.Time { return now }
+	m.period = time.Hour
+	server := newTestServer()
+	require.NoError(t, m.Start(server))
+	defer m.Stop()
+
+	node := testNode(t)
+	require.NoError(t, m.AddStaticPeer(node.URLv4()))
+	require.Equal(t, 1, server.dials)
+
+	m.handleEvent(&p2p.PeerEvent{Type: p2p.PeerEventTypeAdd, Peer: node.ID()})
+	// a dropped peer is not dialed until the backoff expires
+	m.handleEvent(&p2p.PeerEvent{Type: p2p.PeerEventTypeDrop, Peer: node.ID()})
+	require.False(t, server.static[node.ID()])
+	now = now.Add(minReconnectBackoff - time.Second)
+	m.reconnect()
+	require.Equal(t, 1, server.dials)
+	now = now.Add(time.Second)
+	m.reconnect()
+	require.Equal(t, 2, server.dials)
+	require.True(t, server.static[node.ID()])
+
+	// a failed dial doubles the backoff
+	now = now.Add(dialTimeout)
+	m.reconnect()
+	require.False(t, server.static[node.ID()])
+	now = now.Add(2*minReconnectBackoff - time.Second)
+	m.reconnect()
+	require.Equal(t, 2, server.dials)
+	now = now.Add(time.Second)
+	m.reconnect()
+	require.Equal(t, 3, server.dials)
+
+	peers, err := m.Peers()
+	require.NoError(t, err)
+	require.Equal(t, 2, peers[0].Failures)
+	m.handleEvent(&p2p.PeerEvent{Type: p2p.PeerEventTypeAdd, Peer: node.ID()})
+	peers, err = m.Peers()
+	require.NoError(t, err)
+	require.Equal(t, 0, peers[0].Failures)
+	require.True(t, peers[0].Connected)
+}
@@ -16,12 +16,14 @@ type Discoverer interface {
 
 // Service it manages all endpoints for peer operations.
 type Service struct {
-	d Discoverer
+	d       Discoverer
+	manager *Manager
 }
 
-// New returns a new Service.
-func New() *Service {
-	return &Service{}
+// New returns a new Service. Static peers and trusted mailservers added
+// with the API are persisted in the store unless it is nil.
+func New(store *Store) *Service {
+	return &Service{manager: NewManager(store)}
 }
 
 // Protocols returns a new protocols list. In this case, there are none.
@@ -38,6 +40,12 @@ func (s *Service) APIs() []rpc.API {
 			Service:   NewAPI(s),
 			Public:    false,
 		},
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewAdminAPI(s),
+			Public:    false,
+		},
 	}
 }
 
@@ -47,13 +55,13 @@ func (s *Service) SetDiscoverer(d Discoverer) {
 }
 
 // Start is run when a service is started.
-// It does nothing in this case but is required by `node.Service` interface.
+// It connects persisted static peers and trusted mailservers.
 func (s *Service) Start(server *p2p.Server) error {
-	return nil
+	return s.manager.Start(server)
 }
 
 // Stop is run when a service is stopped.
-// It does nothing in this case but is required by `node.Service` interface.
 func (s *Service) Stop() error {
+	s.manager.Stop()
 	return nil
 }
@@ -0,0 +1,56 @@
+package peer
+
+import (
+	"encoding/json"
+
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/status-im/status-go/db"
+)
+
+// ManagedPeer is a static peer or a trusted mailserver added at runtime.
+type ManagedPeer struct {
+	Enode      string `json:"enode"`
+	Mailserver bool   `json:"mailserver"`
+}
+
+// Store persists managed peers keyed by their enode ID.
+type Store struct {
+	db db.LevelDBNamespace
+}
+
+// NewStore returns a Store of managed peers in the node database.
+func NewStore(ldb *leveldb.DB) *Store {
+	return &Store{db: db.NewDBNamespace(db.NewLevelDBStorage(ldb), db.ManagedPeers)}
+}
+
+// Add adds or replaces a managed peer.
+func (s *Store) Add(id enode.ID, peer ManagedPeer) error {
+	value, err := json.Marshal(peer)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(id[:], value)
+}
+
+// Delete removes a managed peer.
+func (s *Store) Delete(id enode.ID) error {
+	return s.db.Delete(id[:])
+}
+
+// All returns all managed peers.
+func (s *Store) All() ([]ManagedPeer, error) {
+	iter := s.db.NewIterator(s.db.Range(nil, nil))
+	defer iter.Release()
+	var rst []ManagedPeer
+	for iter.Next() {
+		var peer ManagedPeer
+		if err := json.Unmarshal(iter.Value(), &peer); err != nil {
+			return nil, err
+		}
+		rst = append(rst, peer)
+	}
+	return rst, iter.Error()
+}
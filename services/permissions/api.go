@@ -24,3 +24,13 @@ func (api *API) GetDappPermissions(ctx context.Context) ([]DappPermissions, erro
 func (api *API) DeleteDappPermissions(ctx context.Context, name string) error {
 	return api.db.DeletePermission(name)
 }
+
+// GrantDappPermission grants a single permission to the dapp, e.g. "web3", "chain-id" or "rpc:<method>".
+func (api *API) GrantDappPermission(ctx context.Context, dapp, permission string) error {
+	return api.db.AddPermission(dapp, permission)
+}
+
+// RevokeDappPermission revokes a single permission of the dapp.
+func (api *API) RevokeDappPermission(ctx context.Context, dapp, permission string) error {
+	return api.db.RemovePermission(dapp, permission)
+}
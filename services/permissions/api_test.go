@@ -96,3 +96,51 @@ func TestDappPermissionsDeleted(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, rst, 0)
 }
+
+func TestGrantAndRevokeDappPermission(t *testing.T) {
+	api, cancel := setupTestAPI(t)
+	defer cancel()
+
+	require.NoError(t, api.AddDappPermissions(context.TODO(), DappPermissions{Name: "first", Permissions: []string{"r"}}))
+	require.NoError(t, api.GrantDappPermission(context.TODO(), "first", PermissionWeb3))
+	// granting the same permission twice doesn't duplicate it
+	require.NoError(t, api.GrantDappPermission(context.TODO(), "first", PermissionWeb3))
+	require.NoError(t, api.GrantDappPermission(context.TODO(), "second", PermissionChainID))
+
+	rst, err := api.GetDappPermissions(context.TODO())
+	require.NoError(t, err)
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].Name < rst[j].Name
+	})
+	require.Equal(t, []DappPermissions{
+		{Name: "first", Permissions: []string{"r", PermissionWeb3}},
+		{Name: "second", Permissions: []string{PermissionChainID}},
+	}, rst)
+
+	require.NoError(t, api.RevokeDappPermission(context.TODO(), "first", "r"))
+	granted, err := api.db.HasPermission("first", "r")
+	require.NoError(t, err)
+	require.False(t, granted)
+	granted, err = api.db.HasPermission("first", PermissionWeb3)
+	require.NoError(t, err)
+	require.True(t, granted)
+}
+
+func TestAuthorize(t *testing.T) {
+	db, cancel := setupTestDB(t)
+	defer cancel()
+	service := NewService(db)
+
+	require.NoError(t, service.Authorize("dapp", "eth_blockNumber"))
+	require.Equal(t, ErrPermissionDenied{Dapp: "dapp", Permission: PermissionWeb3}, service.Authorize("dapp", "eth_accounts"))
+	require.Equal(t, ErrPermissionDenied{Dapp: "dapp", Permission: PermissionChainID}, service.Authorize("dapp", "eth_chainId"))
+	require.Equal(t, ErrPermissionDenied{Dapp: "dapp", Permission: "rpc:wallet_getTransfersByAddress"}, service.Authorize("dapp", "wallet_getTransfersByAddress"))
+
+	require.NoError(t, db.AddPermission("dapp", PermissionWeb3))
+	require.NoError(t, db.AddPermission("dapp", MethodPermission("wallet_getTransfersByAddress")))
+	require.NoError(t, service.Authorize("dapp", "eth_accounts"))
+	require.NoError(t, service.Authorize("dapp", "eth_sendTransaction"))
+	require.NoError(t, service.Authorize("dapp", "wallet_getTransfersByAddress"))
+	require.Error(t, service.Authorize("other", "eth_accounts"))
+	require.Equal(t, 4100, ErrPermissionDenied{}.ErrorCode())
+}
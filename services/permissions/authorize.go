@@ -0,0 +1,124 @@
+package permissions
+
+import (
+	"fmt"
+
+	"github.com/status-im/status-go/signal"
+)
+
+const (
+	// PermissionWeb3 allows a dapp to access accounts and to request signatures.
+	PermissionWeb3 = "web3"
+	// PermissionChainID allows a dapp to read the chain ID of the node.
+	PermissionChainID = "chain-id"
+	// methodPermissionPrefix prefixes permissions to call a single RPC method.
+	methodPermissionPrefix = "rpc:"
+
+	// errUnauthorizedCode is returned to dapps for calls that were not authorized, see EIP-1193.
+	errUnauthorizedCode = 4100
+)
+
+var (
+	// accountMethods require PermissionWeb3.
+	accountMethods = map[string]bool{
+		"eth_accounts":         true,
+		"eth_coinbase":         true,
+		"eth_sign":             true,
+		"eth_signTransaction":  true,
+		"eth_sendTransaction":  true,
+		"eth_signTypedData":    true,
+		"eth_signTypedData_v3": true,
+		"eth_signTypedData_v4": true,
+		"personal_sign":        true,
+	}
+	// chainMethods require PermissionChainID.
+	chainMethods = map[string]bool{
+		"eth_chainId": true,
+		"net_version": true,
+	}
+	// publicMethods read public chain data and can be called by every dapp.
+	publicMethods = map[string]bool{
+		"web3_clientVersion":                      true,
+		"web3_sha3":                               true,
+		"net_listening":                           true,
+		"eth_protocolVersion":                     true,
+		"eth_syncing":                             true,
+		"eth_gasPrice":                            true,
+		"eth_blockNumber":                         true,
+		"eth_getBalance":                          true,
+		"eth_getStorageAt":                        true,
+		"eth_getTransactionCount":                 true,
+		"eth_getBlockTransactionCountByHash":      true,
+		"eth_getBlockTransactionCountByNumber":    true,
+		"eth_getCode":                             true,
+		"eth_call":                                true,
+		"eth_estimateGas":                         true,
+		"eth_getBlockByHash":                      true,
+		"eth_getBlockByNumber":                    true,
+		"eth_getTransactionByHash":                true,
+		"eth_getTransactionByBlockHashAndIndex":   true,
+		"eth_getTransactionByBlockNumberAndIndex": true,
+		"eth_getTransactionReceipt":               true,
+		"eth_getLogs":                             true,
+		"eth_newFilter":                           true,
+		"eth_newBlockFilter":                      true,
+		"eth_getFilterChanges":                    true,
+		"eth_getFilterLogs":                       true,
+		"eth_uninstallFilter":                     true,
+	}
+)
+
+// MethodPermission returns a permission to call the RPC method.
+func MethodPermission(method string) string {
+	return methodPermissionPrefix + method
+}
+
+// requiredPermission returns a permission a dapp needs to call the method, empty for public methods.
+func requiredPermission(method string) string {
+	switch {
+	case publicMethods[method]:
+		return ""
+	case accountMethods[method]:
+		return PermissionWeb3
+	case chainMethods[method]:
+		return PermissionChainID
+	}
+	return MethodPermission(method)
+}
+
+// ErrPermissionDenied is returned for calls of a dapp without a required permission.
+type ErrPermissionDenied struct {
+	Dapp       string
+	Permission string
+}
+
+func (e ErrPermissionDenied) Error() string {
+	return fmt.Sprintf("dapp %s is not granted permission %s", e.Dapp, e.Permission)
+}
+
+// ErrorCode returns the JSON-RPC error code.
+func (e ErrPermissionDenied) ErrorCode() int {
+	return errUnauthorizedCode
+}
+
+// Authorize returns an error if the dapp is not granted a permission required to call the method.
+// A signal is sent for a missing permission, so the user can be prompted to grant it.
+func (s *Service) Authorize(dapp, method string) error {
+	permission := requiredPermission(method)
+	if permission == "" {
+		return nil
+	}
+	granted, err := s.db.HasPermission(dapp, permission)
+	if err != nil {
+		return err
+	}
+	if granted {
+		return nil
+	}
+	signal.SendDappPermissionRequested(signal.DappPermissionRequestEvent{
+		Dapp:       dapp,
+		Permission: permission,
+		Method:     method,
+	})
+	return ErrPermissionDenied{Dapp: dapp, Permission: permission}
+}
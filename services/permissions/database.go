@@ -121,3 +121,42 @@ func (db *Database) DeletePermission(name string) error {
 	_, err := db.db.Exec("DELETE FROM dapps WHERE name = ?", name)
 	return err
 }
+
+// AddPermission grants a single permission to the dapp, other permissions of the dapp are kept.
+func (db *Database) AddPermission(dapp, permission string) (err error) {
+	var tx *sql.Tx
+	tx, err = db.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+	_, err = tx.Exec("INSERT OR IGNORE INTO dapps(name) VALUES(?)", dapp)
+	if err != nil {
+		return
+	}
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM permissions WHERE dapp_name = ? AND permission = ?)", dapp, permission).Scan(&exists)
+	if err != nil || exists {
+		return
+	}
+	_, err = tx.Exec("INSERT INTO permissions(dapp_name, permission) VALUES(?, ?)", dapp, permission)
+	return
+}
+
+// RemovePermission revokes a single permission of the dapp.
+func (db *Database) RemovePermission(dapp, permission string) error {
+	_, err := db.db.Exec("DELETE FROM permissions WHERE dapp_name = ? AND permission = ?", dapp, permission)
+	return err
+}
+
+// HasPermission returns true if the dapp was granted the permission.
+func (db *Database) HasPermission(dapp, permission string) (exists bool, err error) {
+	err = db.db.QueryRow("SELECT EXISTS(SELECT 1 FROM permissions WHERE dapp_name = ? AND permission = ?)", dapp, permission).Scan(&exists)
+	return
+}
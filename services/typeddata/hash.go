@@ -22,10 +22,20 @@ var (
 )
 
 // ValidateAndHash generates a hash of TypedData and verifies that chainId in the typed data matches currently selected chain.
+// Typed data with an invalid domain or resembling a transaction is rejected.
 func ValidateAndHash(typed TypedData, chain *big.Int) (common.Hash, error) {
 	if err := typed.ValidateChainID(chain); err != nil {
 		return common.Hash{}, err
 	}
+	if err := typed.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	if err := typed.ValidateDomain(); err != nil {
+		return common.Hash{}, err
+	}
+	if err := typed.ValidateNotTransactionLike(); err != nil {
+		return common.Hash{}, err
+	}
 
 	return encodeData(typed)
 }
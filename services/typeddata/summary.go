@@ -0,0 +1,99 @@
+package typeddata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Summary is a human-readable representation of typed data to be confirmed by the user before signing.
+type Summary struct {
+	Domain      []SummaryField `json:"domain"`
+	PrimaryType string         `json:"primaryType"`
+	Message     []SummaryField `json:"message"`
+}
+
+// SummaryField is a field with a value formatted as text. Fields of nested structs
+// are flattened and their names are joined with a dot, e.g. `from.wallet`.
+type SummaryField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Summarize formats the domain and the message of typed data. Addresses are checksummed,
+// integers are decimal and bytes are hex encoded.
+func Summarize(typed TypedData) (Summary, error) {
+	if err := typed.Validate(); err != nil {
+		return Summary{}, err
+	}
+	domain, err := summarizeStruct("", eip712Domain, typed.Domain, typed.Types)
+	if err != nil {
+		return Summary{}, err
+	}
+	message, err := summarizeStruct("", typed.PrimaryType, typed.Message, typed.Types)
+	if err != nil {
+		return Summary{}, err
+	}
+	return Summary{Domain: domain, PrimaryType: typed.PrimaryType, Message: message}, nil
+}
+
+func summarizeStruct(prefix, target string, data map[string]json.RawMessage, types Types) ([]SummaryField, error) {
+	var rst []SummaryField
+	for _, f := range types[target] {
+		name := prefix + f.Name
+		if _, composite := types[f.Type]; composite {
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(data[f.Name], &obj); err != nil {
+				return nil, fmt.Errorf("field `%s`: %v", name, err)
+			}
+			fields, err := summarizeStruct(name+".", f.Type, obj, types)
+			if err != nil {
+				return nil, err
+			}
+			rst = append(rst, fields...)
+			continue
+		}
+		value, err := formatValue(f, data[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field `%s`: %v", name, err)
+		}
+		rst = append(rst, SummaryField{Name: name, Type: f.Type, Value: value})
+	}
+	return rst, nil
+}
+
+func formatValue(f Field, data json.RawMessage) (string, error) {
+	if f.Type == "string" {
+		var str string
+		err := json.Unmarshal(data, &str)
+		return str, err
+	} else if strings.HasPrefix(f.Type, "bytes") {
+		var bytes hexutil.Bytes
+		err := json.Unmarshal(data, &bytes)
+		return bytes.String(), err
+	}
+	typ, err := abi.NewType(f.Type, nil)
+	if err != nil {
+		return "", err
+	}
+	switch typ.T {
+	case abi.AddressTy:
+		address, err := toAddress(f, data)
+		return address.Hex(), err
+	case abi.IntTy, abi.UintTy:
+		val, _, err := toInt(f, data)
+		if err != nil {
+			return "", err
+		}
+		return val.String(), nil
+	case abi.BoolTy:
+		val, err := toBool(f, data)
+		return strconv.FormatBool(val), err
+	}
+	return "", fmt.Errorf("type %s is not supported", f.Type)
+}
@@ -0,0 +1,60 @@
+package typeddata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	typed := TypedData{
+		Types: Types{
+			eip712Domain: []Field{
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Person": []Field{
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": []Field{
+				{Name: "from", Type: "Person"},
+				{Name: "amount", Type: "uint256"},
+				{Name: "urgent", Type: "bool"},
+				{Name: "tag", Type: "bytes4"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: map[string]json.RawMessage{
+			"name":    json.RawMessage(`"Ether Mail"`),
+			"chainId": json.RawMessage(`"0x1"`),
+		},
+		Message: map[string]json.RawMessage{
+			"from":   json.RawMessage(`{"name": "Cow", "wallet": "0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826"}`),
+			"amount": json.RawMessage(`1000`),
+			"urgent": json.RawMessage(`true`),
+			"tag":    json.RawMessage(`"0x01020304"`),
+		},
+	}
+	summary, err := Summarize(typed)
+	require.NoError(t, err)
+	require.Equal(t, Summary{
+		Domain: []SummaryField{
+			{Name: "name", Type: "string", Value: "Ether Mail"},
+			{Name: "chainId", Type: "uint256", Value: "1"},
+		},
+		PrimaryType: "Mail",
+		Message: []SummaryField{
+			{Name: "from.name", Type: "string", Value: "Cow"},
+			{Name: "from.wallet", Type: "address", Value: "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			{Name: "amount", Type: "uint256", Value: "1000"},
+			{Name: "urgent", Type: "bool", Value: "true"},
+			{Name: "tag", Type: "bytes4", Value: "0x01020304"},
+		},
+	}, summary)
+
+	typed.Message["amount"] = json.RawMessage(`"ten"`)
+	_, err = Summarize(typed)
+	require.EqualError(t, err, "field `amount`: not an integer")
+}
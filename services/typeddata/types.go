@@ -5,11 +5,30 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 const (
 	eip712Domain = "EIP712Domain"
 	chainIDKey   = "chainId"
+
+	verifyingContractKey = "verifyingContract"
+)
+
+var (
+	// domainFields are fields of the domain separator defined by EIP-712 with their types.
+	domainFields = map[string]string{
+		"name":               "string",
+		"version":            "string",
+		chainIDKey:           "uint256",
+		verifyingContractKey: "address",
+		"salt":               "bytes32",
+	}
+
+	// ErrTransactionLikeData is returned for typed data that resembles a transaction or consists
+	// of a single hash. Users can't tell what they sign, e.g. a hash of a transaction.
+	ErrTransactionLikeData = errors.New("typed data resembles a transaction or a raw hash")
 )
 
 // Types define fields for each composite type.
@@ -84,3 +103,61 @@ func (t TypedData) ValidateChainID(chain *big.Int) error {
 	}
 	return nil
 }
+
+// ValidateDomain checks that the domain declares only fields defined by EIP-712 with their types,
+// including the chain id, and that values of all declared fields, and only them, are set.
+func (t TypedData) ValidateDomain() error {
+	declared := map[string]struct{}{}
+	for _, f := range t.Types[eip712Domain] {
+		typ, known := domainFields[f.Name]
+		if !known {
+			return fmt.Errorf("`%s` is not a field of `%s`", f.Name, eip712Domain)
+		}
+		if f.Type != typ {
+			return fmt.Errorf("domain field `%s` must be of type %s", f.Name, typ)
+		}
+		if _, exist := declared[f.Name]; exist {
+			return fmt.Errorf("domain field `%s` is declared twice", f.Name)
+		}
+		if _, exist := t.Domain[f.Name]; !exist {
+			return fmt.Errorf("domain field `%s` is not set", f.Name)
+		}
+		declared[f.Name] = struct{}{}
+	}
+	for name := range t.Domain {
+		if _, exist := declared[name]; !exist {
+			return fmt.Errorf("domain field `%s` is not declared in `%s`", name, eip712Domain)
+		}
+	}
+	if _, exist := declared[chainIDKey]; !exist {
+		return fmt.Errorf("`%s` must declare `%s`", eip712Domain, chainIDKey)
+	}
+	if raw, exist := t.Domain[verifyingContractKey]; exist {
+		var address string
+		if err := json.Unmarshal(raw, &address); err != nil || !common.IsHexAddress(address) {
+			return fmt.Errorf("`%s` is not a valid address", verifyingContractKey)
+		}
+	}
+	return nil
+}
+
+// ValidateNotTransactionLike returns ErrTransactionLikeData if the primary type has fields
+// of a transaction, the nonce, the recipient and the gas, or consists of a single 32 bytes long field.
+func (t TypedData) ValidateNotTransactionLike() error {
+	fields := t.Types[t.PrimaryType]
+	if len(fields) == 1 && fields[0].Type == "bytes32" {
+		return ErrTransactionLikeData
+	}
+	names := map[string]struct{}{}
+	for _, f := range fields {
+		names[f.Name] = struct{}{}
+	}
+	has := func(name string) bool {
+		_, exist := names[name]
+		return exist
+	}
+	if has("nonce") && has("to") && (has("gas") || has("gasLimit") || has("gasPrice")) {
+		return ErrTransactionLikeData
+	}
+	return nil
+}
@@ -104,3 +104,54 @@ func TestValidateTypedData(t *testing.T) {
 	d.Types[d.PrimaryType][0].Type = "tttt"
 	require.NoError(t, d.Validate())
 }
+
+func TestValidateDomain(t *testing.T) {
+	d := TypedData{
+		Types: Types{eip712Domain: []Field{
+			{Name: "name", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+		}},
+		Domain: map[string]json.RawMessage{
+			"name":              json.RawMessage(`"Ether Mail"`),
+			"chainId":           json.RawMessage("1"),
+			"verifyingContract": json.RawMessage(`"0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"`),
+		},
+	}
+	require.NoError(t, d.ValidateDomain())
+
+	d.Domain["verifyingContract"] = json.RawMessage(`"0xCc"`)
+	require.EqualError(t, d.ValidateDomain(), "`verifyingContract` is not a valid address")
+	d.Domain["salt"] = json.RawMessage(`"0x01"`)
+	require.EqualError(t, d.ValidateDomain(), "domain field `salt` is not declared in `EIP712Domain`")
+	delete(d.Domain, "salt")
+	delete(d.Domain, "name")
+	require.EqualError(t, d.ValidateDomain(), "domain field `name` is not set")
+
+	d.Types[eip712Domain] = []Field{{Name: "chainId", Type: "uint8"}}
+	require.EqualError(t, d.ValidateDomain(), "domain field `chainId` must be of type uint256")
+	d.Types[eip712Domain] = []Field{{Name: "owner", Type: "address"}}
+	require.EqualError(t, d.ValidateDomain(), "`owner` is not a field of `EIP712Domain`")
+	d.Types[eip712Domain] = []Field{}
+	d.Domain = map[string]json.RawMessage{}
+	require.EqualError(t, d.ValidateDomain(), "`EIP712Domain` must declare `chainId`")
+}
+
+func TestValidateNotTransactionLike(t *testing.T) {
+	d := TypedData{PrimaryType: "primary", Types: Types{}}
+	d.Types[d.PrimaryType] = []Field{{Name: "hash", Type: "bytes32"}}
+	require.Equal(t, ErrTransactionLikeData, d.ValidateNotTransactionLike())
+	d.Types[d.PrimaryType] = []Field{
+		{Name: "nonce", Type: "uint256"},
+		{Name: "to", Type: "address"},
+		{Name: "gasLimit", Type: "uint256"},
+		{Name: "value", Type: "uint256"},
+	}
+	require.Equal(t, ErrTransactionLikeData, d.ValidateNotTransactionLike())
+	d.Types[d.PrimaryType] = []Field{
+		{Name: "nonce", Type: "uint256"},
+		{Name: "to", Type: "address"},
+		{Name: "contents", Type: "string"},
+	}
+	require.NoError(t, d.ValidateNotTransactionLike())
+}
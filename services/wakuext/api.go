@@ -128,7 +128,11 @@ func (api *PublicAPI) RequestMessages(_ context.Context, r ext.MessagesRequest)
 		}
 	}
 
-	if err := api.service.w.RequestHistoricMessagesWithTimeout(mailServerNode.ID().Bytes(), envelope, r.Timeout*time.Second); err != nil {
+	request := api.service.w.RequestHistoricMessagesWithTimeout
+	if r.AckWindow > 0 {
+		request = api.service.w.RequestAcknowledgedHistoricMessagesWithTimeout
+	}
+	if err := request(mailServerNode.ID().Bytes(), envelope, r.Timeout*time.Second); err != nil {
 		if !r.Force {
 			api.service.RequestsRegistry().Unregister(hash)
 		}
@@ -166,6 +170,10 @@ func (api *PublicAPI) RequestMessagesSync(conf ext.RetryConfig, r ext.MessagesRe
 			resp.Error = mailServerResp.Error
 			return resp, nil
 		}
+		// The next request is sent to another mail server if it was selected by the registry.
+		if registry := api.service.MailServers(); registry != nil && r.MailServerPeer == "" {
+			registry.RequestExpired(types.BytesToHash(requestID))
+		}
 		retries++
 		api.log.Error("[RequestMessagesSync] failed", "err", err, "retries", retries)
 	}
@@ -0,0 +1,187 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	ens "github.com/wealdtech/go-ens/v3"
+
+	"github.com/status-im/status-go/signal"
+)
+
+// addressBookResolvePeriod is how often ENS names of contacts are resolved again.
+const addressBookResolvePeriod = time.Hour
+
+// ErrInvalidContactAddress is returned when a contact has neither an address nor an ENS name.
+var ErrInvalidContactAddress = errors.New("contact requires a name and either an address or an ENS name")
+
+// ContactAddress is an entry of the address book.
+type ContactAddress struct {
+	Address common.Address `json:"address"`
+	Name    string         `json:"name"`
+	// ENSName is an optional ENS name of the contact. It is periodically resolved
+	// to detect when the name starts pointing to a different address.
+	ENSName string `json:"ensName,omitempty"`
+	// ENSAddress is the address the ENS name resolved to on the last check.
+	ENSAddress *common.Address `json:"ensAddress,omitempty"`
+	// ENSChanged is true if the ENS name resolves to an address different from the stored one.
+	ENSChanged bool `json:"ensChanged"`
+	// ResolvedAt is a unix timestamp of the last successful resolution of the ENS name.
+	ResolvedAt int64 `json:"resolvedAt,omitempty"`
+}
+
+// ENSResolver resolves ENS names to addresses.
+type ENSResolver interface {
+	Resolve(name string) (common.Address, error)
+}
+
+type chainENSResolver struct {
+	backend bind.ContractBackend
+}
+
+func (r chainENSResolver) Resolve(name string) (common.Address, error) {
+	return ens.Resolve(r.backend, name)
+}
+
+// addressBook stores contacts and keeps track of targets of their ENS names.
+type addressBook struct {
+	db     *Database
+	now    func() time.Time
+	notify func(ContactAddress)
+
+	mu       sync.Mutex
+	resolver ENSResolver
+}
+
+func newAddressBook(db *Database) *addressBook {
+	return &addressBook{
+		db:  db,
+		now: time.Now,
+		notify: func(contact ContactAddress) {
+			signal.SendWalletENSAddressChanged(contact)
+		},
+	}
+}
+
+// SetResolver sets a resolver of ENS names. Names are not resolved until it is set.
+func (b *addressBook) SetResolver(resolver ENSResolver) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.resolver = resolver
+}
+
+func (b *addressBook) getResolver() ENSResolver {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resolver
+}
+
+// Add stores the contact. If the address is not set it is resolved from the ENS name,
+// otherwise the ENS name is resolved if possible to check whether it points to the address.
+func (b *addressBook) Add(contact ContactAddress) (ContactAddress, error) {
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.ENSName = strings.TrimSpace(contact.ENSName)
+	if contact.Name == "" || (contact.Address == (common.Address{}) && contact.ENSName == "") {
+		return contact, ErrInvalidContactAddress
+	}
+	if contact.ENSName != "" && !strings.Contains(contact.ENSName, ".") {
+		return contact, errors.New("invalid ENS name")
+	}
+	contact.ENSAddress, contact.ENSChanged, contact.ResolvedAt = nil, false, 0
+
+	resolver := b.getResolver()
+	if contact.ENSName != "" && resolver != nil {
+		resolved, err := resolver.Resolve(contact.ENSName)
+		switch {
+		case err != nil && contact.Address == (common.Address{}):
+			return contact, err
+		case err != nil:
+			log.Warn("failed to resolve ENS name of a contact", "name", contact.ENSName, "error", err)
+		default:
+			if contact.Address == (common.Address{}) {
+				contact.Address = resolved
+			}
+			contact.ENSAddress = &resolved
+			contact.ENSChanged = resolved != contact.Address
+			contact.ResolvedAt = b.now().Unix()
+		}
+	} else if contact.Address == (common.Address{}) {
+		return contact, ErrServiceNotInitialized
+	}
+	return contact, b.db.SaveContactAddress(contact)
+}
+
+// Watch resolves ENS names of contacts every period until the context is canceled.
+func (b *addressBook) Watch(ctx context.Context, period time.Duration) error {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		if err := b.Check(); err != nil {
+			log.Error("failed to check ENS names of contacts", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check resolves ENS names of all contacts and notifies when a name starts pointing
+// to an address other than the stored one. A change is reported once.
+func (b *addressBook) Check() error {
+	resolver := b.getResolver()
+	if resolver == nil {
+		return nil
+	}
+	contacts, err := b.db.GetContactAddresses()
+	if err != nil {
+		return err
+	}
+	for _, contact := range contacts {
+		if contact.ENSName == "" {
+			continue
+		}
+		resolved, err := resolver.Resolve(contact.ENSName)
+		if err != nil {
+			log.Warn("failed to resolve ENS name of a contact", "name", contact.ENSName, "error", err)
+			continue
+		}
+		previous := contact.ENSAddress
+		contact.ENSAddress = &resolved
+		contact.ENSChanged = resolved != contact.Address
+		contact.ResolvedAt = b.now().Unix()
+		if err := b.db.SaveContactAddress(contact); err != nil {
+			return err
+		}
+		if contact.ENSChanged && (previous == nil || *previous != resolved) {
+			b.notify(contact)
+		}
+	}
+	return nil
+}
+
+// setCounterparties labels transfer views with contacts from the address book. The counterparty
+// is the recipient of an outgoing transfer and the sender of an incoming one.
+func setCounterparties(views []TransferView, contacts []ContactAddress) {
+	if len(contacts) == 0 {
+		return
+	}
+	byAddress := make(map[common.Address]*ContactAddress, len(contacts))
+	for i := range contacts {
+		byAddress[contacts[i].Address] = &contacts[i]
+	}
+	for i := range views {
+		counterparty := views[i].From
+		if views[i].From == views[i].Address {
+			counterparty = views[i].To
+		}
+		views[i].Counterparty = byAddress[counterparty]
+	}
+}
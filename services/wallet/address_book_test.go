@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type staticENSResolver map[string]common.Address
+
+func (r staticENSResolver) Resolve(name string) (common.Address, error) {
+	address, exist := r[name]
+	if !exist {
+		return common.Address{}, errors.New("no address")
+	}
+	return address, nil
+}
+
+func TestAddressBookAdd(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	book := newAddressBook(db)
+	_, err := book.Add(ContactAddress{Name: "alice", ENSName: "alice.eth"})
+	require.Equal(t, ErrServiceNotInitialized, err)
+	_, err = book.Add(ContactAddress{Name: " "})
+	require.Equal(t, ErrInvalidContactAddress, err)
+
+	resolver := staticENSResolver{"alice.eth": {1}}
+	book.SetResolver(resolver)
+	alice, err := book.Add(ContactAddress{Name: "alice", ENSName: "alice.eth"})
+	require.NoError(t, err)
+	require.Equal(t, common.Address{1}, alice.Address)
+	require.False(t, alice.ENSChanged)
+
+	_, err = book.Add(ContactAddress{Name: "bob", ENSName: "bob.eth"})
+	require.Error(t, err)
+	// the address is stored even if the name can't be resolved
+	bob, err := book.Add(ContactAddress{Address: common.Address{2}, Name: "bob", ENSName: "bob.eth"})
+	require.NoError(t, err)
+	require.Nil(t, bob.ENSAddress)
+
+	contacts, err := db.GetContactAddresses()
+	require.NoError(t, err)
+	require.Equal(t, []ContactAddress{alice, bob}, contacts)
+
+	require.NoError(t, db.DeleteContactAddress(bob.Address))
+	contacts, err = db.GetContactAddresses()
+	require.NoError(t, err)
+	require.Equal(t, []ContactAddress{alice}, contacts)
+}
+
+func TestAddressBookNotifiesENSChange(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	resolver := staticENSResolver{"alice.eth": {1}}
+	var events []ContactAddress
+	book := newAddressBook(db)
+	book.SetResolver(resolver)
+	book.now = func() time.Time { return time.Unix(100, 0) }
+	book.notify = func(contact ContactAddress) { events = append(events, contact) }
+
+	_, err := book.Add(ContactAddress{Name: "alice", ENSName: "alice.eth"})
+	require.NoError(t, err)
+	require.NoError(t, book.Check())
+	require.Empty(t, events)
+
+	resolver["alice.eth"] = common.Address{3}
+	require.NoError(t, book.Check())
+	require.NoError(t, book.Check())
+	require.Len(t, events, 1)
+	require.Equal(t, common.Address{1}, events[0].Address)
+	require.Equal(t, common.Address{3}, *events[0].ENSAddress)
+	require.True(t, events[0].ENSChanged)
+
+	contacts, err := db.GetContactAddresses()
+	require.NoError(t, err)
+	require.True(t, contacts[0].ENSChanged)
+	require.Equal(t, int64(100), contacts[0].ResolvedAt)
+}
+
+func TestSetCounterparties(t *testing.T) {
+	account := common.Address{1}
+	contacts := []ContactAddress{{Address: common.Address{2}, Name: "alice"}}
+	views := []TransferView{
+		{Address: account, From: account, To: common.Address{2}},
+		{Address: account, From: common.Address{2}, To: account},
+		{Address: account, From: account, To: common.Address{3}},
+	}
+	setCounterparties(views, contacts)
+	require.Equal(t, "alice", views[0].Counterparty.Name)
+	require.Equal(t, "alice", views[1].Counterparty.Name)
+	require.Nil(t, views[2].Counterparty)
+}
@@ -0,0 +1,157 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/services/wallet/ierc20"
+	"github.com/status-im/status-go/transactions"
+)
+
+const erc20ApprovalEventSignature = "Approval(address,address,uint256)"
+
+// Allowance is an amount of the owner's tokens the spender is allowed to transfer.
+type Allowance struct {
+	Token   common.Address `json:"token"`
+	Spender common.Address `json:"spender"`
+	Value   *hexutil.Big   `json:"value"`
+	// BlockNumber is a block of the last approval.
+	BlockNumber *hexutil.Big `json:"blockNumber"`
+	// Metadata is nil if it couldn't be read from the contract.
+	Metadata *Token `json:"metadata,omitempty"`
+}
+
+type allowancesClient interface {
+	bind.ContractCaller
+	LogsReader
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// GetActiveAllowances returns non-zero allowances given by the owner. Approval events are scanned
+// starting from the block after the last scan and allowances are checked against the token contracts,
+// because transfers by spenders decrease allowances without an approval event.
+func GetActiveAllowances(parent context.Context, db *Database, client allowancesClient, owner common.Address) ([]Allowance, error) {
+	if err := scanApprovals(parent, db, client, owner); err != nil {
+		return nil, err
+	}
+	stored, _, err := db.GetAllowances(owner)
+	if err != nil {
+		return nil, err
+	}
+	rst := []Allowance{}
+	for _, allowance := range stored {
+		caller, err := ierc20.NewIERC20Caller(allowance.Token, client)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+		value, err := caller.Allowance(&bind.CallOpts{Context: ctx}, owner, allowance.Spender)
+		cancel()
+		if err != nil {
+			log.Warn("failed to check allowance", "token", allowance.Token, "spender", allowance.Spender, "error", err)
+			rst = append(rst, allowance)
+			continue
+		}
+		if value.Sign() == 0 {
+			if err := db.DeleteAllowance(owner, allowance.Token, allowance.Spender); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		allowance.Value = (*hexutil.Big)(value)
+		rst = append(rst, allowance)
+	}
+	return rst, nil
+}
+
+// scanApprovals stores the last erc20 approval of every token and spender of the owner.
+func scanApprovals(parent context.Context, db *Database, client allowancesClient, owner common.Address) error {
+	_, scanned, err := db.GetAllowances(owner)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	header, err := client.HeaderByNumber(ctx, nil)
+	cancel()
+	if err != nil {
+		return err
+	}
+	from := zero
+	if scanned != nil {
+		if scanned.Cmp(header.Number) >= 0 {
+			return nil
+		}
+		from = new(big.Int).Add(scanned, one)
+	}
+
+	ctx, cancel = context.WithTimeout(parent, 30*time.Second)
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: from,
+		ToBlock:   header.Number,
+		Topics: [][]common.Hash{
+			{crypto.Keccak256Hash([]byte(erc20ApprovalEventSignature))},
+			{common.BytesToHash(owner.Bytes())},
+		},
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	// logs are ordered, so the last approval for a token and a spender replaces earlier ones
+	var (
+		allowances []Allowance
+		index      = map[[2]common.Address]int{}
+	)
+	for _, l := range logs {
+		// erc721 approvals have the same signature with an indexed token id
+		if l.Removed || len(l.Topics) != 3 || len(l.Data) != 32 {
+			continue
+		}
+		allowance := Allowance{
+			Token:       l.Address,
+			Spender:     common.BytesToAddress(l.Topics[2].Bytes()),
+			Value:       (*hexutil.Big)(new(big.Int).SetBytes(l.Data)),
+			BlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(l.BlockNumber)),
+		}
+		key := [2]common.Address{allowance.Token, allowance.Spender}
+		if i, exist := index[key]; exist {
+			allowances[i] = allowance
+			continue
+		}
+		index[key] = len(allowances)
+		allowances = append(allowances, allowance)
+	}
+	return db.SaveAllowances(owner, allowances, header.Number)
+}
+
+// BuildRevokeAllowanceTx returns an unsigned transaction that sets the allowance of the spender to zero.
+// The sender must be set to the owner of the allowance before the transaction is sent.
+func BuildRevokeAllowanceTx(token, spender common.Address) (*transactions.SendTxArgs, error) {
+	contract, err := abi.JSON(strings.NewReader(ierc20.IERC20ABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := contract.Pack("approve", spender, big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+	to := statustypes.Address(token)
+	return &transactions.SendTxArgs{
+		To:    &to,
+		Value: (*hexutil.Big)(big.NewInt(0)),
+		Data:  data,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+type allowancesTestClient struct {
+	latest int64
+	logs   []types.Log
+	// allowances are current allowances indexed by token and spender, missing tokens fail the call
+	allowances map[common.Address]map[common.Address]int64
+	// queries are ranges of requested blocks
+	queries [][2]int64
+}
+
+func (c *allowancesTestClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(c.latest)}, nil
+}
+
+func (c *allowancesTestClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.queries = append(c.queries, [2]int64{q.FromBlock.Int64(), q.ToBlock.Int64()})
+	var rst []types.Log
+	for _, l := range c.logs {
+		if int64(l.BlockNumber) >= q.FromBlock.Int64() && int64(l.BlockNumber) <= q.ToBlock.Int64() {
+			rst = append(rst, l)
+		}
+	}
+	return rst, nil
+}
+
+func (c *allowancesTestClient) CodeAt(ctx context.Context, contract common.Address, block *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *allowancesTestClient) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	spenders, exist := c.allowances[*call.To]
+	if !exist {
+		return nil, errors.New("execution reverted")
+	}
+	// selector, owner, spender
+	spender := common.BytesToAddress(call.Data[36:68])
+	return common.LeftPadBytes(big.NewInt(spenders[spender]).Bytes(), 32), nil
+}
+
+func approvalLog(block uint64, token, owner, spender common.Address, value int64) types.Log {
+	return types.Log{
+		Address:     token,
+		BlockNumber: block,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte(erc20ApprovalEventSignature)),
+			common.BytesToHash(owner.Bytes()),
+			common.BytesToHash(spender.Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+	}
+}
+
+func TestGetActiveAllowances(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	owner := common.Address{1}
+	token, other, broken := common.Address{0xaa}, common.Address{0xbb}, common.Address{0xcc}
+	spender, router := common.Address{2}, common.Address{3}
+	nft := approvalLog(3, other, owner, spender, 0)
+	nft.Topics = append(nft.Topics, common.Hash{1})
+	client := &allowancesTestClient{
+		latest: 10,
+		logs: []types.Log{
+			approvalLog(2, token, owner, spender, 100),
+			nft,
+			approvalLog(4, token, owner, spender, 50),
+			approvalLog(5, other, owner, router, 30),
+			approvalLog(6, broken, owner, router, 20),
+		},
+		allowances: map[common.Address]map[common.Address]int64{
+			// 10 tokens were transferred by the spender
+			token: {spender: 40},
+			// spent completely
+			other: {router: 0},
+		},
+	}
+
+	allowances, err := GetActiveAllowances(context.Background(), db, client, owner)
+	require.NoError(t, err)
+	require.Len(t, allowances, 2)
+	require.Equal(t, token, allowances[0].Token)
+	require.Equal(t, spender, allowances[0].Spender)
+	require.Equal(t, int64(40), allowances[0].Value.ToInt().Int64())
+	require.Equal(t, int64(4), allowances[0].BlockNumber.ToInt().Int64())
+	// the value of the last approval is returned if the token doesn't respond
+	require.Equal(t, broken, allowances[1].Token)
+	require.Equal(t, int64(20), allowances[1].Value.ToInt().Int64())
+
+	// only new blocks are scanned and a revocation removes the allowance
+	client.latest = 12
+	client.logs = append(client.logs, approvalLog(11, token, owner, spender, 0))
+	allowances, err = GetActiveAllowances(context.Background(), db, client, owner)
+	require.NoError(t, err)
+	require.Len(t, allowances, 1)
+	require.Equal(t, broken, allowances[0].Token)
+	require.Equal(t, [][2]int64{{0, 10}, {11, 12}}, client.queries)
+
+	// blocks are not scanned again
+	_, err = GetActiveAllowances(context.Background(), db, client, owner)
+	require.NoError(t, err)
+	require.Len(t, client.queries, 2)
+}
+
+func TestBuildRevokeAllowanceTx(t *testing.T) {
+	token, spender := common.Address{0xaa}, common.Address{2}
+	tx, err := BuildRevokeAllowanceTx(token, spender)
+	require.NoError(t, err)
+	require.Equal(t, token.Bytes(), tx.To.Bytes())
+	require.Equal(t, int64(0), tx.Value.ToInt().Int64())
+
+	contract, err := abi.JSON(strings.NewReader(ierc20.IERC20ABI))
+	require.NoError(t, err)
+	expected, err := contract.Pack("approve", spender, big.NewInt(0))
+	require.NoError(t, err)
+	require.Equal(t, expected, []byte(tx.Data))
+}
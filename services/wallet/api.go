@@ -4,10 +4,18 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/multiaccounts/accounts"
+	"github.com/status-im/status-go/services/typeddata"
+	"github.com/status-im/status-go/transactions"
 )
 
 var (
@@ -24,9 +32,12 @@ type API struct {
 	s *Service
 }
 
-// GetTransfersByAddress returns transfers for a single address
-func (api *API) GetTransfersByAddress(ctx context.Context, address common.Address, toBlock, limit *hexutil.Big) ([]TransferView, error) {
-	log.Debug("[WalletAPI:: GetTransfersByAddress] get transfers for an address", "address", address, "block", toBlock, "limit", limit)
+// GetTransfersByAddress returns transfers for a single address. If currency is set
+// transfers include their value in the currency at the day of the transfer.
+// Options are optional, transfers with tags are selected from the database only,
+// history is not downloaded to fill the limit.
+func (api *API) GetTransfersByAddress(ctx context.Context, address common.Address, toBlock, limit *hexutil.Big, currency *string, options *TransfersOptions) ([]TransferView, error) {
+	log.Debug("[WalletAPI:: GetTransfersByAddress] get transfers for an address", "address", address, "block", toBlock, "limit", limit, "currency", currency)
 	if api.s.db == nil {
 		log.Error("[WalletAPI:: GetTransfersByAddress] db is not initialized")
 		return nil, ErrServiceNotInitialized
@@ -37,21 +48,25 @@ func (api *API) GetTransfersByAddress(ctx context.Context, address common.Addres
 		toBlockBN = toBlock.ToInt()
 	}
 
-	rst, err := api.s.db.GetTransfersByAddress(address, toBlockBN, limit.ToInt().Int64())
+	var tags []string
+	if options != nil {
+		tags = normalizeTags(options.Tags)
+	}
+	rst, err := api.s.db.GetTaggedTransfersByAddress(address, toBlockBN, limit.ToInt().Int64(), tags)
 	if err != nil {
 		log.Error("[WalletAPI:: GetTransfersByAddress] can't fetch transfers", "err", err)
 		return nil, err
 	}
 
 	transfersCount := big.NewInt(int64(len(rst)))
-	if limit.ToInt().Cmp(transfersCount) == 1 {
+	if len(tags) == 0 && limit.ToInt().Cmp(transfersCount) == 1 {
 		block, err := api.s.db.GetFirstKnownBlock(address)
 		if err != nil {
 			return nil, err
 		}
 
 		if block == nil {
-			return castToTransferViews(rst), nil
+			return api.transferViews(ctx, rst, currency), nil
 		}
 
 		from, err := findFirstRange(ctx, address, block, api.s.client)
@@ -71,6 +86,8 @@ func (api *API) GetTransfersByAddress(ctx context.Context, address common.Addres
 			feed:          api.s.feed,
 			fromByAddress: fromByAddress,
 			toByAddress:   toByAddress,
+			l2:            api.s.reactor.l2,
+			checkpoints:   api.s.reactor.checkpoints.Bundle(ctx, api.s.reactor.chain),
 		}
 
 		if err = blocksCommand.Command()(ctx); err != nil {
@@ -89,6 +106,7 @@ func (api *API) GetTransfersByAddress(ctx context.Context, address common.Addres
 				db:       api.s.db,
 				chain:    api.s.reactor.chain,
 				client:   api.s.client,
+				l2:       api.s.reactor.l2,
 			}
 
 			err = txCommand.Command()(ctx)
@@ -102,7 +120,72 @@ func (api *API) GetTransfersByAddress(ctx context.Context, address common.Addres
 		}
 	}
 
-	return castToTransferViews(rst), nil
+	return api.transferViews(ctx, rst, currency), nil
+}
+
+// transferViews converts transfers to views with metadata of erc20 contracts.
+// Metadata of unknown contracts is fetched from the chain.
+func (api *API) transferViews(ctx context.Context, transfers []Transfer, currency *string) []TransferView {
+	views := castToTransferViews(transfers)
+	if api.s.client != nil {
+		tokens, err := GetTokensMetadata(ctx, api.s.db, api.s.client, tokenContracts(transfers))
+		if err != nil {
+			log.Error("[WalletAPI:: transferViews] can't get tokens metadata", "err", err)
+		} else {
+			setTokensMetadata(views, tokens)
+		}
+	}
+	if api.s.client != nil {
+		if err := api.loadShareTokens(); err != nil {
+			log.Error("[WalletAPI:: transferViews] can't load share tokens", "err", err)
+		} else if err := setUnderlyingValues(ctx, api.s.shares, api.s.client, views); err != nil {
+			log.Error("[WalletAPI:: transferViews] can't set underlying values", "err", err)
+		}
+	}
+	if currency != nil && *currency != "" {
+		if err := setFiatValues(ctx, api.s.db, api.s.prices, views, *currency); err != nil {
+			log.Error("[WalletAPI:: transferViews] can't set fiat values", "err", err)
+		}
+	}
+	contacts, err := api.s.db.GetContactAddresses()
+	if err != nil {
+		log.Error("[WalletAPI:: transferViews] can't get address book", "err", err)
+	} else {
+		setCounterparties(views, contacts)
+	}
+	if err := setBridgeCounterparts(api.s.db, views); err != nil {
+		log.Error("[WalletAPI:: transferViews] can't get bridge counterparts", "err", err)
+	}
+	signatures, err := api.s.db.GetMethodSignatures()
+	if err != nil {
+		log.Error("[WalletAPI:: transferViews] can't get method signatures", "err", err)
+	}
+	setDecodedCalls(views, transfers, api.s.abis, newMethodSignatures(signatures))
+	hashes := make([]common.Hash, len(views))
+	for i := range views {
+		hashes[i] = views[i].ID
+	}
+	notes, err := api.s.db.GetTransferNotes(hashes)
+	if err != nil {
+		log.Error("[WalletAPI:: transferViews] can't get transfer notes", "err", err)
+	} else {
+		setTransferNotes(views, notes)
+	}
+	return views
+}
+
+// SetTransferNote attaches a note and tags to a transfer with the given ID, they replace previous ones.
+// An empty note without tags removes them. Notes are stored only locally.
+func (api *API) SetTransferNote(ctx context.Context, hash common.Hash, note string, tags []string) error {
+	log.Debug("[WalletAPI:: SetTransferNote] set transfer note", "hash", hash)
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	normalized, err := normalizeTransferNote(note, tags)
+	if err != nil {
+		return err
+	}
+	return api.s.db.SaveTransferNote(hash, normalized)
 }
 
 // GetTokensBalances return mapping of token balances for every account.
@@ -113,6 +196,61 @@ func (api *API) GetTokensBalances(ctx context.Context, accounts, tokens []common
 	return GetTokensBalances(ctx, api.s.client, accounts, tokens)
 }
 
+// GetUnderlyingBalances returns token balances of every account in terms of underlying assets
+// of registered interest-bearing tokens, e.g. compound cTokens. Rates are read from token contracts.
+func (api *API) GetUnderlyingBalances(ctx context.Context, accounts, tokens []common.Address) (map[common.Address]map[common.Address]*UnderlyingBalance, error) {
+	log.Debug("[WalletAPI:: GetUnderlyingBalances] get underlying balances", "accounts", accounts, "tokens", tokens)
+	if api.s.client == nil || api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	if err := api.loadShareTokens(); err != nil {
+		return nil, err
+	}
+	balances, err := GetTokensBalances(ctx, api.s.client, accounts, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return GetUnderlyingBalances(ctx, api.s.shares, api.s.client, balances)
+}
+
+// RegisterShareToken stores a kind of the interest-bearing token. Built-in kinds are
+// compound, aave and wsteth.
+func (api *API) RegisterShareToken(ctx context.Context, token common.Address, kind string) error {
+	log.Debug("[WalletAPI:: RegisterShareToken] register share token", "token", token, "kind", kind)
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	if err := api.s.shares.Register(token, kind); err != nil {
+		return err
+	}
+	return api.s.db.SaveShareToken(token, kind)
+}
+
+// loadShareTokens registers converters for interest-bearing tokens stored in the database.
+func (api *API) loadShareTokens() error {
+	tokens, err := api.s.db.GetShareTokens()
+	if err != nil {
+		return err
+	}
+	api.s.shares.RegisterTokens(tokens)
+	return nil
+}
+
+// GetBalanceAt returns eth and tokens balances of a watched address at a historical block.
+func (api *API) GetBalanceAt(ctx context.Context, address common.Address, tokens []common.Address, block *hexutil.Big) (*BalanceAt, error) {
+	log.Debug("[WalletAPI:: GetBalanceAt] get balance", "address", address, "tokens", len(tokens), "block", block)
+	if api.s.client == nil || api.s.reactor == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	if block == nil {
+		return nil, errors.New("block is required")
+	}
+	if !api.s.reactor.watching(address) {
+		return nil, ErrAddressNotWatched
+	}
+	return GetBalanceAt(ctx, api.s.db, api.s.client, address, tokens, block.ToInt())
+}
+
 func (api *API) GetCustomTokens(ctx context.Context) ([]*Token, error) {
 	log.Debug("call to get custom tokens")
 	rst, err := api.s.db.GetCustomTokens()
@@ -133,3 +271,494 @@ func (api *API) DeleteCustomToken(ctx context.Context, address common.Address) e
 	log.Debug("result from database for remove custom token", "err", err)
 	return err
 }
+
+// EstimateTransaction simulates a transaction with increased balance of the sender and returns
+// estimated gas or a revert reason if the transaction is going to fail.
+func (api *API) EstimateTransaction(ctx context.Context, args CallArgs) (*TransactionEstimate, error) {
+	log.Debug("[WalletAPI:: EstimateTransaction] estimate transaction", "from", args.From, "to", args.To)
+	if api.s.rpc == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	tokens, err := api.s.db.GetCustomTokens()
+	if err != nil {
+		return nil, err
+	}
+	err = api.s.abis.RegisterTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+	return EstimateTransaction(ctx, api.s.rpc, api.s.abis, args)
+}
+
+// RegisterABI stores an ABI of the contract. It is used to decode logs emitted by the contract
+// with higher priority than built-in events.
+func (api *API) RegisterABI(ctx context.Context, address common.Address, contract string) error {
+	log.Debug("[WalletAPI:: RegisterABI] register abi", "address", address)
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	if _, err := abi.JSON(strings.NewReader(contract)); err != nil {
+		return err
+	}
+	if err := api.s.db.SaveContractABI(address, contract); err != nil {
+		return err
+	}
+	return api.s.abis.RegisterContracts(map[common.Address]string{address: contract})
+}
+
+// GetTransactionReceiptDecoded returns a receipt of the transaction with logs decoded using
+// registered ABIs and built-in erc20, erc721, weth and uniswap events.
+func (api *API) GetTransactionReceiptDecoded(ctx context.Context, hash common.Hash) (*DecodedReceipt, error) {
+	log.Debug("[WalletAPI:: GetTransactionReceiptDecoded] get receipt", "hash", hash)
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	contracts, err := api.s.db.GetContractABIs()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.s.abis.RegisterContracts(contracts); err != nil {
+		return nil, err
+	}
+	return GetDecodedReceipt(ctx, api.s.client, api.s.abis, hash)
+}
+
+// FetchDecodedTxCalldata returns calldata of the transaction decoded using the registered ABI of
+// the called contract or a known method signature. Nil is returned for plain eth transfers.
+func (api *API) FetchDecodedTxCalldata(ctx context.Context, hash common.Hash) (*DecodedCall, error) {
+	log.Debug("[WalletAPI:: FetchDecodedTxCalldata] decode calldata", "hash", hash)
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	tx, _, err := api.s.client.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := api.s.db.GetContractABIs()
+	if err != nil {
+		return nil, err
+	}
+	if err := api.s.abis.RegisterContracts(contracts); err != nil {
+		return nil, err
+	}
+	signatures, err := api.s.db.GetMethodSignatures()
+	if err != nil {
+		return nil, err
+	}
+	return decodeCalldata(api.s.abis, newMethodSignatures(signatures), tx.To(), tx.Data()), nil
+}
+
+// AddMethodSignature stores a method signature, e.g. "approve(address spender,uint256 amount)".
+// Signatures added by the user are used to decode calldata with higher priority than built-in ones.
+func (api *API) AddMethodSignature(ctx context.Context, signature string) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	method, err := parseMethodSignature(signature)
+	if err != nil {
+		return err
+	}
+	return api.s.db.SaveMethodSignature(method.selector[:], strings.TrimSpace(signature))
+}
+
+// GetMethodSignatures returns method signatures added by the user.
+func (api *API) GetMethodSignatures(ctx context.Context) ([]string, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetMethodSignatures()
+}
+
+// DeleteMethodSignature removes a method signature added by the user.
+func (api *API) DeleteMethodSignature(ctx context.Context, signature string) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	return api.s.db.DeleteMethodSignature(strings.TrimSpace(signature))
+}
+
+// CheckRecentHistory returns addresses with on-chain activity, it can be used to discover used accounts.
+func (api *API) CheckRecentHistory(ctx context.Context, addresses []common.Address) ([]common.Address, error) {
+	log.Debug("[WalletAPI:: CheckRecentHistory] check history for addresses", "addresses", len(addresses))
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return CheckRecentHistory(ctx, api.s.client, addresses)
+}
+
+// GetTransferByHash returns a transfer of the address made by the transaction with a given hash.
+// Transfers missing in the database are fetched from the chain and persisted.
+// Currency is optional, see GetTransfersByAddress.
+func (api *API) GetTransferByHash(ctx context.Context, address common.Address, hash common.Hash, currency *string) (*TransferView, error) {
+	log.Debug("[WalletAPI:: GetTransferByHash] get transfer", "address", address, "hash", hash)
+	if api.s.client == nil || api.s.reactor == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	signer := types.NewEIP155Signer(api.s.reactor.chain)
+	transfer, err := GetTransferByHash(ctx, api.s.db, api.s.client, signer, address, hash)
+	if err != nil {
+		log.Error("[WalletAPI:: GetTransferByHash] can't get transfer", "err", err)
+		return nil, err
+	}
+	views := api.transferViews(ctx, []Transfer{*transfer}, currency)
+	return &views[0], nil
+}
+
+// GetSyncState returns progress of transfers download for the address.
+func (api *API) GetSyncState(ctx context.Context, address common.Address) (*SyncState, error) {
+	log.Debug("[WalletAPI:: GetSyncState] get sync state", "address", address)
+	if api.s.db == nil || api.s.reactor == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return GetSyncState(api.s.db, api.s.reactor.tracker, address)
+}
+
+// Resync removes transfers of the address starting from fromBlock and restarts downloader,
+// so that removed blocks are scanned again.
+func (api *API) Resync(ctx context.Context, address common.Address, fromBlock *hexutil.Big) error {
+	log.Debug("[WalletAPI:: Resync] resync address", "address", address, "from", fromBlock)
+	if api.s.db == nil || api.s.reactor == nil {
+		return ErrServiceNotInitialized
+	}
+	if fromBlock == nil {
+		return errors.New("fromBlock is required")
+	}
+	// transfers are reset while downloader is stopped, so that it doesn't write them back
+	return api.s.reactor.Restart(func() error {
+		err := api.s.db.ResetFromBlock(address, fromBlock.ToInt())
+		if err != nil {
+			log.Error("[WalletAPI:: Resync] can't reset transfers", "err", err)
+		}
+		return err
+	})
+}
+
+// SendTransaction sends a transaction from an account on a hardware wallet. The user is requested
+// to confirm the transaction on the device, see hardware-wallet-confirmation-required signal.
+func (api *API) SendTransaction(ctx context.Context, args transactions.SendTxArgs) (common.Hash, error) {
+	log.Debug("[WalletAPI:: SendTransaction] send transaction", "from", args.From, "to", args.To)
+	if api.s.db == nil || api.s.transactor == nil {
+		return common.Hash{}, ErrServiceNotInitialized
+	}
+	chainID := new(big.Int).SetUint64(api.s.db.network)
+	return SendTransaction(ctx, api.s.transactor, api.s.hardware, chainID, args)
+}
+
+// HashTypedData returns the hash of EIP-712 typed data together with a human-readable summary
+// to be confirmed by the user. Typed data for another chain, with an invalid domain or resembling
+// a transaction is rejected.
+func (api *API) HashTypedData(ctx context.Context, typed typeddata.TypedData) (*TypedDataHash, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return HashTypedData(typed, new(big.Int).SetUint64(api.s.db.network))
+}
+
+// SignTypedData signs EIP-712 typed data with a wallet account, typed data is validated as by HashTypedData.
+func (api *API) SignTypedData(ctx context.Context, typed typeddata.TypedData, address common.Address, password string) (hexutil.Bytes, error) {
+	log.Debug("[WalletAPI:: SignTypedData] sign typed data", "address", address, "primaryType", typed.PrimaryType)
+	if api.s.db == nil || api.s.keys == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return SignTypedData(api.s.keys, typed, new(big.Int).SetUint64(api.s.db.network), address, password)
+}
+
+// AddHardwareAccount derives an account at the derivation path from a connected hardware wallet
+// and stores the path to sign transactions of the account.
+func (api *API) AddHardwareAccount(ctx context.Context, wallet string, path string) (HardwareAccount, error) {
+	log.Debug("[WalletAPI:: AddHardwareAccount] add account", "wallet", wallet, "path", path)
+	if api.s.db == nil {
+		return HardwareAccount{}, ErrServiceNotInitialized
+	}
+	return api.s.hardware.AddAccount(ctx, wallet, path)
+}
+
+// GetHardwareAccounts returns accounts added from hardware wallets.
+func (api *API) GetHardwareAccounts(ctx context.Context) ([]HardwareAccount, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetHardwareAccounts()
+}
+
+// DeleteHardwareAccount removes a derivation path of the account.
+func (api *API) DeleteHardwareAccount(ctx context.Context, address common.Address) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	return api.s.db.DeleteHardwareAccount(address)
+}
+
+// PairKeycard pairs the keycard tapped to the reader using the pairing password, see wallet.keycard signal.
+func (api *API) PairKeycard(ctx context.Context, pairingPassword string) (KeycardPairing, error) {
+	log.Debug("[WalletAPI:: PairKeycard] pair keycard")
+	if api.s.db == nil {
+		return KeycardPairing{}, ErrServiceNotInitialized
+	}
+	return api.s.keycard.Pair(ctx, pairingPassword)
+}
+
+// GetKeycards returns paired keycards with the number of PIN attempts left.
+func (api *API) GetKeycards(ctx context.Context) ([]KeycardPairing, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetKeycardPairings()
+}
+
+// UnpairKeycard removes the pairing of a keycard and accounts added from the keycard.
+func (api *API) UnpairKeycard(ctx context.Context, instanceUID string) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	return api.s.keycard.Unpair(instanceUID)
+}
+
+// AddKeycardAccount derives an account at the derivation path from a paired keycard
+// and stores the path to sign with the account.
+func (api *API) AddKeycardAccount(ctx context.Context, instanceUID string, pin string, path string) (KeycardAccount, error) {
+	log.Debug("[WalletAPI:: AddKeycardAccount] add account", "instanceUID", instanceUID, "path", path)
+	if api.s.db == nil {
+		return KeycardAccount{}, ErrServiceNotInitialized
+	}
+	return api.s.keycard.AddAccount(ctx, instanceUID, pin, path)
+}
+
+// GetKeycardAccounts returns accounts added from keycards.
+func (api *API) GetKeycardAccounts(ctx context.Context) ([]KeycardAccount, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetKeycardAccounts()
+}
+
+// DeleteKeycardAccount removes a derivation path of the account.
+func (api *API) DeleteKeycardAccount(ctx context.Context, address common.Address) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	return api.s.db.DeleteKeycardAccount(address)
+}
+
+// SendTransactionWithKeycard sends a transaction from an account on a keycard. The transaction is
+// signed once the card is tapped and the PIN is verified, see wallet.keycard signal.
+func (api *API) SendTransactionWithKeycard(ctx context.Context, args transactions.SendTxArgs, pin string) (common.Hash, error) {
+	log.Debug("[WalletAPI:: SendTransactionWithKeycard] send transaction", "from", args.From, "to", args.To)
+	if api.s.db == nil || api.s.transactor == nil {
+		return common.Hash{}, ErrServiceNotInitialized
+	}
+	chainID := new(big.Int).SetUint64(api.s.db.network)
+	return SendTransaction(ctx, api.s.transactor, api.s.keycard.WithPIN(pin), chainID, args)
+}
+
+// SignTypedDataWithKeycard signs EIP-712 typed data with an account on a keycard,
+// typed data is validated as by HashTypedData.
+func (api *API) SignTypedDataWithKeycard(ctx context.Context, typed typeddata.TypedData, address common.Address, pin string) (hexutil.Bytes, error) {
+	log.Debug("[WalletAPI:: SignTypedDataWithKeycard] sign typed data", "address", address, "primaryType", typed.PrimaryType)
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.keycard.SignTypedData(ctx, typed, new(big.Int).SetUint64(api.s.db.network), address, pin)
+}
+
+// GetDailySpent returns ether and tokens sent from the address in the last 24 hours and 7 days.
+func (api *API) GetDailySpent(ctx context.Context, address common.Address) (*SpendingReport, error) {
+	log.Debug("[WalletAPI:: GetDailySpent] get spent value", "address", address)
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return GetSpending(api.s.db, address, time.Now())
+}
+
+// GetRecurringTransfers returns recurring payments, e.g. subscriptions, detected in transfers sent from the address.
+func (api *API) GetRecurringTransfers(ctx context.Context, address common.Address) ([]RecurringTransfer, error) {
+	log.Debug("[WalletAPI:: GetRecurringTransfers] detect recurring transfers", "address", address)
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return GetRecurringTransfers(api.s.db, address, time.Now())
+}
+
+// GetTransfersForChart returns received and sent values and numbers of transfers of the addresses
+// per token in buckets of the given size in seconds, within the [from, to) range of timestamps.
+func (api *API) GetTransfersForChart(ctx context.Context, addresses []common.Address, from, to, bucket uint64) ([]TransfersChartPoint, error) {
+	log.Debug("[WalletAPI:: GetTransfersForChart] get transfers chart", "addresses", len(addresses), "from", from, "to", to, "bucket", bucket)
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetTransfersChart(addresses, from, to, bucket)
+}
+
+// GetFeeHistoryChart returns gas price statistics of the latest blocks split into buckets for rendering a chart.
+// Each bucket includes min, median and max gas prices and gas prices at the requested percentiles.
+// Blocks are cached, so that only new blocks are requested when the chart is refreshed.
+func (api *API) GetFeeHistoryChart(ctx context.Context, blocks, buckets int, percentiles []float64) (*FeeHistoryChart, error) {
+	log.Debug("[WalletAPI:: GetFeeHistoryChart] get fee history", "blocks", blocks, "buckets", buckets)
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.fees.Chart(ctx, api.s.client, blocks, buckets, percentiles)
+}
+
+// GetActiveAllowances returns erc20 allowances given by the address with a non-zero value.
+// Approval events are stored, so only new blocks are scanned on every request.
+func (api *API) GetActiveAllowances(ctx context.Context, address common.Address) ([]Allowance, error) {
+	log.Debug("[WalletAPI:: GetActiveAllowances] get allowances", "address", address)
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	allowances, err := GetActiveAllowances(ctx, api.s.db, api.s.client, address)
+	if err != nil {
+		return nil, err
+	}
+	contracts := make([]common.Address, len(allowances))
+	for i := range allowances {
+		contracts[i] = allowances[i].Token
+	}
+	metadata, err := GetTokensMetadata(ctx, api.s.db, api.s.client, contracts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range allowances {
+		allowances[i].Metadata = metadata[allowances[i].Token]
+	}
+	return allowances, nil
+}
+
+// BuildRevokeAllowanceTx returns an unsigned transaction that sets the allowance of the spender to zero.
+func (api *API) BuildRevokeAllowanceTx(ctx context.Context, token, spender common.Address) (*transactions.SendTxArgs, error) {
+	log.Debug("[WalletAPI:: BuildRevokeAllowanceTx] build revoke transaction", "token", token, "spender", spender)
+	return BuildRevokeAllowanceTx(token, spender)
+}
+
+// GetPendingNonceAndSuggestedCancel returns the next nonce of the address, accounting for transactions
+// sent by this node that are still pending, and for transactions pending for longer than 3 minutes
+// unsigned speed up and cancel transactions with the same nonce and a higher gas price.
+func (api *API) GetPendingNonceAndSuggestedCancel(ctx context.Context, address common.Address) (*PendingNonce, error) {
+	log.Debug("[WalletAPI:: GetPendingNonceAndSuggestedCancel] get pending nonce", "address", address)
+	transactor, ok := api.s.transactor.(pendingTransactor)
+	if api.s.client == nil || !ok {
+		return nil, ErrServiceNotInitialized
+	}
+	return GetPendingNonce(ctx, transactor, api.s.client, address, time.Now())
+}
+
+// SetSpendingLimit sets a soft spending limit of the address for ether or a token.
+// The limit is removed if neither daily nor weekly value is set.
+func (api *API) SetSpendingLimit(ctx context.Context, address common.Address, limit SpendingLimit) error {
+	log.Debug("[WalletAPI:: SetSpendingLimit] set limit", "address", address, "token", limit.Token)
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	if err := api.s.db.SaveSpendingLimit(address, limit); err != nil {
+		return err
+	}
+	return api.s.spending.Check(address)
+}
+
+// GetSpendingLimits returns spending limits of the address.
+func (api *API) GetSpendingLimits(ctx context.Context, address common.Address) ([]SpendingLimit, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetSpendingLimits(address)
+}
+
+// AddContactAddress stores a contact in the address book. If the address is not set, it is resolved
+// from the ENS name. ENS names of contacts are resolved periodically to detect when they change targets.
+func (api *API) AddContactAddress(ctx context.Context, contact ContactAddress) (ContactAddress, error) {
+	log.Debug("[WalletAPI:: AddContactAddress] add contact", "address", contact.Address, "ens", contact.ENSName)
+	if api.s.db == nil {
+		return ContactAddress{}, ErrServiceNotInitialized
+	}
+	return api.s.addressBook.Add(contact)
+}
+
+// GetContactAddresses returns all contacts in the address book.
+func (api *API) GetContactAddresses(ctx context.Context) ([]ContactAddress, error) {
+	if api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	return api.s.db.GetContactAddresses()
+}
+
+// DeleteContactAddress removes a contact from the address book.
+func (api *API) DeleteContactAddress(ctx context.Context, address common.Address) error {
+	if api.s.db == nil {
+		return ErrServiceNotInitialized
+	}
+	return api.s.db.DeleteContactAddress(address)
+}
+
+// GetOwnedTokens returns erc20 tokens with a non-zero balance of the address. Tokens are discovered
+// from stored transfers, popular and custom tokens. Balances are cached and checked periodically.
+func (api *API) GetOwnedTokens(ctx context.Context, address common.Address) ([]OwnedToken, error) {
+	log.Debug("[WalletAPI:: GetOwnedTokens] get owned tokens", "address", address)
+	if api.s.client == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	owned, err := api.s.ownedTokens.Owned(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	contracts := make([]common.Address, len(owned))
+	for i := range owned {
+		contracts[i] = owned[i].Address
+	}
+	metadata, err := GetTokensMetadata(ctx, api.s.db, api.s.client, contracts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range owned {
+		owned[i].Metadata = metadata[owned[i].Address]
+	}
+	return owned, nil
+}
+
+// DeriveAccounts returns accounts derived from the master key at BIP-44 paths, e.g. m/44'/60'/0'/0/1.
+// Accounts are not stored.
+func (api *API) DeriveAccounts(ctx context.Context, password string, paths []string) ([]DerivedAccount, error) {
+	log.Debug("[WalletAPI:: DeriveAccounts] derive accounts", "paths", paths)
+	return api.s.derived.Derive(password, paths)
+}
+
+// StoreDerivedAccount stores the key of an account derived from the master key at the path and
+// adds the account with the name and the color to the accounts list. Transfers of the account are downloaded.
+func (api *API) StoreDerivedAccount(ctx context.Context, password, path, name, color string) (accounts.Account, error) {
+	log.Debug("[WalletAPI:: StoreDerivedAccount] store account", "path", path)
+	return api.s.derived.Store(password, path, name, color)
+}
+
+// RestoreDerivedAccounts derives keys of all stored accounts from the master key again
+// and stores them in the keystore, e.g. after the keystore was restored.
+func (api *API) RestoreDerivedAccounts(ctx context.Context, password string) ([]accounts.Account, error) {
+	log.Debug("[WalletAPI:: RestoreDerivedAccounts] restore accounts")
+	return api.s.derived.Restore(password)
+}
+
+// GetCryptoOnRampProviders returns fiat on-ramp providers from the configured source.
+// Providers are cached for an hour and the cache is used if the source is unavailable.
+func (api *API) GetCryptoOnRampProviders(ctx context.Context) ([]CryptoOnRamp, error) {
+	log.Debug("[WalletAPI:: GetCryptoOnRampProviders] get on-ramp providers")
+	return api.s.onRamps.Get(ctx)
+}
+
+// GetNFTMetadata returns metadata of a collectible with the token URI read from the contract.
+// Metadata is cached, failed fetches are returned with the failed status and retried after an hour.
+func (api *API) GetNFTMetadata(ctx context.Context, contract common.Address, tokenID *hexutil.Big) (*NFTMetadata, error) {
+	log.Debug("[WalletAPI:: GetNFTMetadata] get nft metadata", "contract", contract, "tokenID", tokenID)
+	if api.s.client == nil || api.s.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	if tokenID == nil {
+		return nil, errors.New("token id is required")
+	}
+	return api.s.nftMetadata.Get(ctx, api.s.client, contract, tokenID.ToInt())
+}
+
+// SuggestRoutes evaluates balances of the sender on registered chains and returns viable routes
+// of the transfer, a direct send or a bridge followed by a send, with estimated fees ordered by fee.
+func (api *API) SuggestRoutes(ctx context.Context, req RouteRequest) ([]Route, error) {
+	log.Debug("[WalletAPI:: SuggestRoutes] suggest routes", "from", req.From, "to", req.To, "chain", req.ToChain)
+	return api.s.router.SuggestRoutes(ctx, req)
+}
@@ -0,0 +1,299 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	bridgeDeposit    = "deposit"
+	bridgeWithdrawal = "withdrawal"
+
+	bridgeInitiated = "initiated"
+	bridgeFinalized = "finalized"
+)
+
+// optimismNetworks and arbitrumNetworks are chain IDs of layer 2 networks
+// which receipts have additional fee fields.
+var (
+	optimismNetworks = map[uint64]bool{10: true, 69: true, 420: true}
+	arbitrumNetworks = map[uint64]bool{42161: true, 421611: true, 421613: true}
+)
+
+// isL2Network returns true if the chain is a known layer 2 network.
+func isL2Network(chain *big.Int) bool {
+	if chain == nil || !chain.IsUint64() {
+		return false
+	}
+	return optimismNetworks[chain.Uint64()] || arbitrumNetworks[chain.Uint64()]
+}
+
+// bridgeEventsABI describes events of Optimism-style standard bridges and cross domain messengers.
+const bridgeEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"ETHDepositInitiated","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"l1Token","type":"address"},{"indexed":true,"name":"l2Token","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"ERC20DepositInitiated","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"ETHWithdrawalFinalized","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"l1Token","type":"address"},{"indexed":true,"name":"l2Token","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"ERC20WithdrawalFinalized","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"l1Token","type":"address"},{"indexed":true,"name":"l2Token","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"DepositFinalized","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"l1Token","type":"address"},{"indexed":true,"name":"l2Token","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"extraData","type":"bytes"}],"name":"WithdrawalInitiated","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"target","type":"address"},{"indexed":false,"name":"sender","type":"address"},{"indexed":false,"name":"message","type":"bytes"},{"indexed":false,"name":"messageNonce","type":"uint256"},{"indexed":false,"name":"gasLimit","type":"uint256"}],"name":"SentMessage","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"SentMessageExtension1","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"msgHash","type":"bytes32"}],"name":"RelayedMessage","type":"event"}
+]`
+
+// relayMessageV0ABI and relayMessageV1ABI are used to compute hashes of legacy
+// and current versions of cross domain messages, the same way as messengers do.
+const (
+	relayMessageV0ABI = `[{"inputs":[{"name":"_target","type":"address"},{"name":"_sender","type":"address"},{"name":"_message","type":"bytes"},{"name":"_messageNonce","type":"uint256"}],"name":"relayMessage","outputs":[],"type":"function"}]`
+	relayMessageV1ABI = `[{"inputs":[{"name":"_nonce","type":"uint256"},{"name":"_sender","type":"address"},{"name":"_target","type":"address"},{"name":"_value","type":"uint256"},{"name":"_minGasLimit","type":"uint256"},{"name":"_message","type":"bytes"}],"name":"relayMessage","outputs":[],"type":"function"}]`
+)
+
+var (
+	bridgeContract abi.ABI
+	relayMessageV0 abi.ABI
+	relayMessageV1 abi.ABI
+	// bridgeEvents maps IDs of bridge events to the direction and the stage of a bridge transfer.
+	bridgeEvents              = map[common.Hash]BridgeTransfer{}
+	sentMessageEvent          common.Hash
+	sentMessageExtensionEvent common.Hash
+	relayedMessageEvent       common.Hash
+)
+
+func init() {
+	var err error
+	for definition, contract := range map[string]*abi.ABI{
+		bridgeEventsABI:   &bridgeContract,
+		relayMessageV0ABI: &relayMessageV0,
+		relayMessageV1ABI: &relayMessageV1,
+	} {
+		*contract, err = abi.JSON(strings.NewReader(definition))
+		if err != nil {
+			panic(err)
+		}
+	}
+	for name, transfer := range map[string]BridgeTransfer{
+		"ETHDepositInitiated":      {Direction: bridgeDeposit, Stage: bridgeInitiated},
+		"ERC20DepositInitiated":    {Direction: bridgeDeposit, Stage: bridgeInitiated},
+		"DepositFinalized":         {Direction: bridgeDeposit, Stage: bridgeFinalized},
+		"WithdrawalInitiated":      {Direction: bridgeWithdrawal, Stage: bridgeInitiated},
+		"ETHWithdrawalFinalized":   {Direction: bridgeWithdrawal, Stage: bridgeFinalized},
+		"ERC20WithdrawalFinalized": {Direction: bridgeWithdrawal, Stage: bridgeFinalized},
+	} {
+		bridgeEvents[bridgeContract.Events[name].ID()] = transfer
+	}
+	sentMessageEvent = bridgeContract.Events["SentMessage"].ID()
+	sentMessageExtensionEvent = bridgeContract.Events["SentMessageExtension1"].ID()
+	relayedMessageEvent = bridgeContract.Events["RelayedMessage"].ID()
+}
+
+// BridgeTransfer describes a transfer between layer 1 and layer 2 through a standard bridge.
+type BridgeTransfer struct {
+	// Direction is "deposit" from layer 1 to layer 2 or "withdrawal" from layer 2 to layer 1.
+	Direction string `json:"direction"`
+	// Stage is "initiated" on the source network or "finalized" on the destination network.
+	Stage string `json:"stage"`
+	// MessageHash identifies the cross domain message on both networks.
+	MessageHash common.Hash `json:"messageHash"`
+	// Counterpart is the transfer on the other network, nil if it is not downloaded.
+	Counterpart *BridgeCounterpart `json:"counterpart,omitempty"`
+}
+
+// BridgeCounterpart is the other side of a bridge transfer.
+type BridgeCounterpart struct {
+	NetworkID uint64         `json:"networkId"`
+	TxHash    common.Hash    `json:"txHash"`
+	Address   common.Address `json:"address"`
+}
+
+// parseBridgeTransfer recognizes a bridge transfer by events in the receipt.
+// Nil is returned if the transaction didn't go through a bridge.
+func parseBridgeTransfer(receipt *types.Receipt) *BridgeTransfer {
+	if receipt == nil {
+		return nil
+	}
+	var (
+		rst     *BridgeTransfer
+		hash    common.Hash
+		message *sentMessage
+		value   = new(big.Int)
+	)
+	for _, l := range receipt.Logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+		if transfer, ok := bridgeEvents[l.Topics[0]]; ok && rst == nil {
+			rst = &transfer
+			continue
+		}
+		switch l.Topics[0] {
+		case relayedMessageEvent:
+			if len(l.Topics) == 2 {
+				hash = l.Topics[1]
+			}
+		case sentMessageEvent:
+			message = parseSentMessage(l)
+		case sentMessageExtensionEvent:
+			if len(l.Data) == 32 {
+				value.SetBytes(l.Data)
+			}
+		}
+	}
+	if rst == nil {
+		return nil
+	}
+	if rst.Stage == bridgeInitiated && message != nil {
+		hash = message.hash(value)
+	}
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	rst.MessageHash = hash
+	return rst
+}
+
+// sentMessage is a cross domain message sent by a messenger.
+type sentMessage struct {
+	Target       common.Address
+	Sender       common.Address
+	Message      []byte
+	MessageNonce *big.Int
+	GasLimit     *big.Int
+}
+
+func parseSentMessage(l *types.Log) *sentMessage {
+	if len(l.Topics) != 2 {
+		return nil
+	}
+	var message sentMessage
+	if err := bridgeContract.Unpack(&message, "SentMessage", l.Data); err != nil {
+		log.Warn("can't unpack cross domain message", "tx", l.TxHash, "error", err)
+		return nil
+	}
+	message.Target = common.BytesToAddress(l.Topics[1].Bytes())
+	return &message
+}
+
+// hash returns the hash of the message that is emitted by the messenger on the other network
+// when the message is relayed. The version of the message is in the first two bytes of the nonce.
+func (m *sentMessage) hash(value *big.Int) common.Hash {
+	var (
+		data []byte
+		err  error
+	)
+	if new(big.Int).Rsh(m.MessageNonce, 240).Sign() == 0 {
+		data, err = relayMessageV0.Pack("relayMessage", m.Target, m.Sender, m.Message, m.MessageNonce)
+	} else {
+		data, err = relayMessageV1.Pack("relayMessage", m.MessageNonce, m.Sender, m.Target, value, m.GasLimit, m.Message)
+	}
+	if err != nil {
+		log.Warn("can't encode cross domain message", "error", err)
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// bridgeMessageHash returns the hash of the cross domain message of a bridge transfer, nil otherwise.
+func bridgeMessageHash(receipt *types.Receipt) interface{} {
+	if bridge := parseBridgeTransfer(receipt); bridge != nil {
+		return bridge.MessageHash
+	}
+	return nil
+}
+
+// setBridgeCounterparts links bridge transfers to their counterparts downloaded on other networks.
+func setBridgeCounterparts(db *Database, views []TransferView) error {
+	var hashes []common.Hash
+	for i := range views {
+		if views[i].Bridge != nil {
+			hashes = append(hashes, views[i].Bridge.MessageHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	counterparts, err := db.GetBridgeCounterparts(hashes)
+	if err != nil {
+		return err
+	}
+	for i := range views {
+		if views[i].Bridge == nil {
+			continue
+		}
+		if counterpart, ok := counterparts[views[i].Bridge.MessageHash]; ok {
+			views[i].Bridge.Counterpart = &counterpart
+		}
+	}
+	return nil
+}
+
+// L2Fee is a part of the fee of a layer 2 transaction that pays for publishing it on layer 1.
+type L2Fee struct {
+	// L1Fee is charged in addition to gasUsed * gasPrice on Optimism.
+	L1Fee *hexutil.Big `json:"l1Fee,omitempty"`
+	// L1GasUsed is gas spent to publish the transaction on layer 1.
+	// On Arbitrum it is a part of gasUsed and it is already paid with gasPrice.
+	L1GasUsed *hexutil.Big `json:"l1GasUsed,omitempty"`
+	// L1GasPrice is the layer 1 gas price used to compute L1Fee.
+	L1GasPrice *hexutil.Big `json:"l1GasPrice,omitempty"`
+}
+
+// l2ReceiptFields are fee fields of Optimism and Arbitrum receipts.
+type l2ReceiptFields struct {
+	L1Fee        *hexutil.Big `json:"l1Fee"`
+	L1GasUsed    *hexutil.Big `json:"l1GasUsed"`
+	L1GasPrice   *hexutil.Big `json:"l1GasPrice"`
+	GasUsedForL1 *hexutil.Big `json:"gasUsedForL1"`
+}
+
+func (f l2ReceiptFields) fee() *L2Fee {
+	if f.L1Fee == nil && f.L1GasUsed == nil && f.GasUsedForL1 == nil {
+		return nil
+	}
+	fee := &L2Fee{L1Fee: f.L1Fee, L1GasUsed: f.L1GasUsed, L1GasPrice: f.L1GasPrice}
+	if fee.L1GasUsed == nil {
+		fee.L1GasUsed = f.GasUsedForL1
+	}
+	return fee
+}
+
+// receiptReader fetches receipts of transactions. On layer 2 networks receipts are fetched
+// with a raw call, so that fee fields that are unknown to layer 1 receipts are kept.
+type receiptReader struct {
+	client *ethclient.Client
+	// l2 is set only on layer 2 networks
+	l2 RPCClient
+}
+
+func (r receiptReader) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, *L2Fee, error) {
+	if r.l2 == nil {
+		receipt, err := r.client.TransactionReceipt(ctx, hash)
+		return receipt, nil, err
+	}
+	var raw json.RawMessage
+	if err := r.l2.CallContext(ctx, &raw, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+	receipt := new(types.Receipt)
+	if err := json.Unmarshal(raw, receipt); err != nil {
+		return nil, nil, err
+	}
+	var fields l2ReceiptFields
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+	return receipt, fields.fee(), nil
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// bridgeDepositReceipts returns receipts of a deposit initiated on layer 1 and finalized on layer 2.
+func bridgeDepositReceipts(t *testing.T, nonce *big.Int) (initiated, finalized *types.Receipt, hash common.Hash) {
+	target, sender := common.Address{1}, common.Address{2}
+	message := []byte{1, 2, 3}
+	value := big.NewInt(100)
+	gasLimit := big.NewInt(200000)
+
+	data, err := bridgeContract.Events["SentMessage"].Inputs.NonIndexed().Pack(sender, message, nonce, gasLimit)
+	require.NoError(t, err)
+	initiated = &types.Receipt{Logs: []*types.Log{
+		{Topics: []common.Hash{bridgeContract.Events["ETHDepositInitiated"].ID(), {}, {}}},
+		{Topics: []common.Hash{sentMessageEvent, common.BytesToHash(target.Bytes())}, Data: data},
+		{Topics: []common.Hash{sentMessageExtensionEvent, {}}, Data: common.BigToHash(value).Bytes()},
+	}}
+
+	if new(big.Int).Rsh(nonce, 240).Sign() == 0 {
+		data, err = relayMessageV0.Pack("relayMessage", target, sender, message, nonce)
+	} else {
+		data, err = relayMessageV1.Pack("relayMessage", nonce, sender, target, value, gasLimit, message)
+	}
+	require.NoError(t, err)
+	hash = crypto.Keccak256Hash(data)
+	finalized = &types.Receipt{Logs: []*types.Log{
+		{Topics: []common.Hash{relayedMessageEvent, hash}},
+		{Topics: []common.Hash{bridgeContract.Events["DepositFinalized"].ID(), {}, {}, {}}},
+	}}
+	return initiated, finalized, hash
+}
+
+func TestParseBridgeTransfer(t *testing.T) {
+	for _, nonce := range []*big.Int{
+		big.NewInt(10),
+		new(big.Int).Or(new(big.Int).Lsh(big.NewInt(1), 240), big.NewInt(10)),
+	} {
+		initiated, finalized, hash := bridgeDepositReceipts(t, nonce)
+
+		rst := parseBridgeTransfer(initiated)
+		require.NotNil(t, rst)
+		require.Equal(t, bridgeDeposit, rst.Direction)
+		require.Equal(t, bridgeInitiated, rst.Stage)
+		require.Equal(t, hash, rst.MessageHash)
+
+		rst = parseBridgeTransfer(finalized)
+		require.NotNil(t, rst)
+		require.Equal(t, bridgeDeposit, rst.Direction)
+		require.Equal(t, bridgeFinalized, rst.Stage)
+		require.Equal(t, hash, rst.MessageHash)
+	}
+
+	require.Nil(t, parseBridgeTransfer(types.NewReceipt(nil, false, 100)))
+	require.Nil(t, parseBridgeTransfer(nil))
+}
+
+func TestL2ReceiptFee(t *testing.T) {
+	receipt := types.NewReceipt(nil, false, 100)
+	receipt.Logs = []*types.Log{}
+	encoded, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	fields := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	fields["l1Fee"] = "0x64"
+	fields["l1GasUsed"] = "0xa"
+	fields["l1GasPrice"] = "0xa"
+
+	client := &fakeRPCClient{results: map[string]interface{}{"eth_getTransactionReceipt": fields}}
+	rst, fee, err := receiptReader{l2: client}.TransactionReceipt(context.Background(), common.Hash{1})
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), rst.CumulativeGasUsed)
+	require.NotNil(t, fee)
+	require.Equal(t, big.NewInt(100), fee.L1Fee.ToInt())
+	require.Equal(t, big.NewInt(10), fee.L1GasUsed.ToInt())
+
+	// arbitrum receipts report only gas used for layer 1
+	delete(fields, "l1Fee")
+	delete(fields, "l1GasUsed")
+	delete(fields, "l1GasPrice")
+	fields["gasUsedForL1"] = "0x5"
+	_, fee, err = receiptReader{l2: client}.TransactionReceipt(context.Background(), common.Hash{1})
+	require.NoError(t, err)
+	require.Nil(t, fee.L1Fee)
+	require.Equal(t, big.NewInt(5), fee.L1GasUsed.ToInt())
+
+	client.results["eth_getTransactionReceipt"] = nil
+	_, _, err = receiptReader{l2: client}.TransactionReceipt(context.Background(), common.Hash{1})
+	require.Error(t, err)
+}
+
+func TestDBBridgeCounterparts(t *testing.T) {
+	l1, stop := setupTestDB(t)
+	defer stop()
+	l2 := NewDB(l1.db, 10)
+
+	initiated, finalized, hash := bridgeDepositReceipts(t, big.NewInt(10))
+	save := func(db *Database, number int64, receipt *types.Receipt, fee *L2Fee) {
+		header := &DBHeader{
+			Number:  big.NewInt(number),
+			Hash:    common.Hash{byte(number)},
+			Address: common.Address{1},
+		}
+		tx := types.NewTransaction(uint64(number), common.Address{1}, nil, 10, big.NewInt(10), nil)
+		transfers := []Transfer{{
+			ID:          tx.Hash(),
+			Type:        ethTransfer,
+			BlockHash:   header.Hash,
+			BlockNumber: header.Number,
+			Transaction: tx,
+			Receipt:     receipt,
+			Address:     header.Address,
+			L2Fee:       fee,
+		}}
+		require.NoError(t, db.ProcessBlocks(header.Address, header.Number, header.Number, []*DBHeader{header}))
+		require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+	}
+	save(l1, 1, initiated, nil)
+	save(l2, 2, finalized, &L2Fee{L1Fee: (*hexutil.Big)(big.NewInt(100))})
+
+	transfers, err := l2.GetTransfersByAddress(common.Address{1}, big.NewInt(2), 10)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.NotNil(t, transfers[0].L2Fee)
+	require.Equal(t, big.NewInt(100), transfers[0].L2Fee.L1Fee.ToInt())
+
+	views := castToTransferViews(transfers)
+	require.NoError(t, setBridgeCounterparts(l2, views))
+	require.NotNil(t, views[0].Bridge)
+	require.Equal(t, hash, views[0].Bridge.MessageHash)
+	require.NotNil(t, views[0].Bridge.Counterpart)
+	require.Equal(t, uint64(1777), views[0].Bridge.Counterpart.NetworkID)
+	require.Equal(t, types.NewTransaction(1, common.Address{1}, nil, 10, big.NewInt(10), nil).Hash(), views[0].Bridge.Counterpart.TxHash)
+
+	transfers, err = l1.GetTransfersByAddress(common.Address{1}, big.NewInt(1), 10)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Nil(t, transfers[0].L2Fee)
+}
@@ -0,0 +1,206 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// builtinMethodSignatures are signatures of commonly used erc20, erc721, weth and uniswap methods.
+// Names of arguments are not a part of a selector, they are used only to label decoded values.
+var builtinMethodSignatures = []string{
+	"transfer(address to,uint256 value)",
+	"approve(address spender,uint256 amount)",
+	"transferFrom(address from,address to,uint256 value)",
+	"increaseAllowance(address spender,uint256 addedValue)",
+	"decreaseAllowance(address spender,uint256 subtractedValue)",
+	"setApprovalForAll(address operator,bool approved)",
+	"safeTransferFrom(address from,address to,uint256 tokenId)",
+	"safeTransferFrom(address from,address to,uint256 tokenId,bytes data)",
+	"deposit()",
+	"withdraw(uint256 wad)",
+	"ethToTokenSwapInput(uint256 min_tokens,uint256 deadline)",
+	"tokenToEthSwapInput(uint256 tokens_sold,uint256 min_eth,uint256 deadline)",
+	"swapExactETHForTokens(uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+	"swapExactTokensForETH(uint256 amountIn,uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+	"swapExactTokensForTokens(uint256 amountIn,uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+}
+
+var (
+	methodNameRegex = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+	// shortIntRegex matches int and uint types without a size that are aliases of int256 and uint256.
+	shortIntRegex = regexp.MustCompile(`^(u?int)(\[.*)?$`)
+
+	errCalldataMismatch = errors.New("calldata is not encoded canonically")
+)
+
+// builtinMethods are known methods indexed by their selector.
+var builtinMethods methodSignatures
+
+func init() {
+	builtinMethods = methodSignatures{}
+	for _, signature := range builtinMethodSignatures {
+		method, err := parseMethodSignature(signature)
+		if err != nil {
+			panic(err)
+		}
+		builtinMethods.add(method)
+	}
+}
+
+// DecodedCall is calldata of a contract call decoded using a known method signature.
+type DecodedCall struct {
+	Selector hexutil.Bytes `json:"selector"`
+	// Method and Signature are empty if the method is not known.
+	Method    string         `json:"method,omitempty"`
+	Signature string         `json:"signature,omitempty"`
+	Params    []DecodedParam `json:"params,omitempty"`
+}
+
+// methodSignature is a parsed text signature of a contract method.
+type methodSignature struct {
+	selector  [4]byte
+	name      string
+	canonical string
+	inputs    abi.Arguments
+}
+
+// parseMethodSignature parses a signature in a form of "approve(address spender,uint256 amount)".
+// Names of arguments are optional, tuples are not supported.
+func parseMethodSignature(signature string) (*methodSignature, error) {
+	signature = strings.TrimSpace(signature)
+	open := strings.IndexByte(signature, '(')
+	if open < 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid method signature %s", signature)
+	}
+	method := &methodSignature{name: strings.TrimSpace(signature[:open])}
+	if !methodNameRegex.MatchString(method.name) {
+		return nil, fmt.Errorf("invalid method name %s", method.name)
+	}
+	args := strings.TrimSpace(signature[open+1 : len(signature)-1])
+	if strings.ContainsAny(args, "()") {
+		return nil, fmt.Errorf("tuple arguments are not supported: %s", signature)
+	}
+	var types []string
+	if args != "" {
+		for _, arg := range strings.Split(args, ",") {
+			fields := strings.Fields(arg)
+			if len(fields) == 0 || len(fields) > 2 {
+				return nil, fmt.Errorf("invalid argument %s", arg)
+			}
+			typ, err := abi.NewType(shortIntRegex.ReplaceAllString(fields[0], "${1}256${2}"), nil)
+			if err != nil {
+				return nil, err
+			}
+			input := abi.Argument{Type: typ}
+			if len(fields) == 2 {
+				input.Name = fields[1]
+			}
+			method.inputs = append(method.inputs, input)
+			types = append(types, typ.String())
+		}
+	}
+	method.canonical = fmt.Sprintf("%v(%v)", method.name, strings.Join(types, ","))
+	copy(method.selector[:], crypto.Keccak256([]byte(method.canonical)))
+	return method, nil
+}
+
+// methodSignatures is a table of method signatures indexed by their selector. Different
+// methods may share a selector, they are tried in the order they were added.
+type methodSignatures map[[4]byte][]*methodSignature
+
+func (s methodSignatures) add(method *methodSignature) {
+	s[method.selector] = append(s[method.selector], method)
+}
+
+// newMethodSignatures creates a table of signatures added by the user followed by the built-in ones.
+func newMethodSignatures(signatures []string) methodSignatures {
+	rst := methodSignatures{}
+	for _, signature := range signatures {
+		method, err := parseMethodSignature(signature)
+		if err != nil {
+			log.Warn("skipping invalid method signature", "signature", signature, "error", err)
+			continue
+		}
+		rst.add(method)
+	}
+	for _, methods := range builtinMethods {
+		for _, method := range methods {
+			rst.add(method)
+		}
+	}
+	return rst
+}
+
+// decodeCalldata decodes a call of the contract using its registered ABI and then known method
+// signatures. Nil is returned if the input is too short to be a contract call.
+func decodeCalldata(registry *abiRegistry, signatures methodSignatures, to *common.Address, input []byte) *DecodedCall {
+	if len(input) < 4 {
+		return nil
+	}
+	rst := &DecodedCall{Selector: hexutil.Bytes(common.CopyBytes(input[:4]))}
+	if to != nil && registry != nil {
+		if contract, exist := registry.Lookup(*to); exist {
+			if method, err := contract.MethodById(input[:4]); err == nil {
+				if params, err := decodeCallParams(method.Inputs, input[4:]); err == nil {
+					rst.Method, rst.Signature, rst.Params = method.RawName, method.Sig(), params
+					return rst
+				}
+			}
+		}
+	}
+	var selector [4]byte
+	copy(selector[:], input)
+	for _, method := range signatures[selector] {
+		params, err := decodeCallParams(method.inputs, input[4:])
+		if err != nil {
+			log.Debug("failed to decode calldata", "method", method.canonical, "error", err)
+			continue
+		}
+		rst.Method, rst.Signature, rst.Params = method.name, method.canonical, params
+		return rst
+	}
+	return rst
+}
+
+// decodeCallParams decodes arguments of the call. Data must be encoded exactly as the arguments
+// would be, it helps to tell apart methods with colliding selectors.
+func decodeCallParams(inputs abi.Arguments, data []byte) ([]DecodedParam, error) {
+	values, err := inputs.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := inputs.PackValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(encoded, data) {
+		return nil, errCalldataMismatch
+	}
+	params := make([]DecodedParam, len(inputs))
+	for i, input := range inputs {
+		params[i] = DecodedParam{Name: input.Name, Type: input.Type.String(), Value: formatABIValue(values[i])}
+	}
+	return params, nil
+}
+
+// setDecodedCalls attaches decoded calldata to views of transfers made by contract calls.
+func setDecodedCalls(views []TransferView, transfers []Transfer, registry *abiRegistry, signatures methodSignatures) {
+	for i := range views {
+		if transfers[i].Transaction == nil {
+			continue
+		}
+		call := decodeCalldata(registry, signatures, transfers[i].Transaction.To(), transfers[i].Transaction.Data())
+		if call != nil && call.Method != "" {
+			views[i].Call = call
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+func TestParseMethodSignature(t *testing.T) {
+	method, err := parseMethodSignature("approve(address spender, uint amount)")
+	require.NoError(t, err)
+	require.Equal(t, "approve", method.name)
+	require.Equal(t, "approve(address,uint256)", method.canonical)
+	require.Equal(t, [4]byte{0x09, 0x5e, 0xa7, 0xb3}, method.selector)
+	require.Equal(t, "spender", method.inputs[0].Name)
+
+	method, err = parseMethodSignature("deposit()")
+	require.NoError(t, err)
+	require.Equal(t, [4]byte{0xd0, 0xe3, 0x0d, 0xb0}, method.selector)
+
+	for _, invalid := range []string{"", "approve", "1approve()", "approve(address,)", "approve(unknown)", "swap((address,uint256))"} {
+		_, err = parseMethodSignature(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	spender := common.Address{1}
+	contract, err := abi.JSON(strings.NewReader(ierc20.IERC20ABI))
+	require.NoError(t, err)
+	input, err := contract.Pack("approve", spender, big.NewInt(100))
+	require.NoError(t, err)
+
+	call := decodeCalldata(nil, newMethodSignatures(nil), nil, input)
+	require.NotNil(t, call)
+	require.Equal(t, hexutil.Bytes{0x09, 0x5e, 0xa7, 0xb3}, call.Selector)
+	require.Equal(t, "approve", call.Method)
+	require.Equal(t, "approve(address,uint256)", call.Signature)
+	require.Equal(t, []DecodedParam{
+		{Name: "spender", Type: "address", Value: spender.Hex()},
+		{Name: "amount", Type: "uint256", Value: "100"},
+	}, call.Params)
+
+	// names from the registered ABI take precedence
+	registered, err := abi.JSON(strings.NewReader(`[{"inputs":[{"name":"guy","type":"address"},{"name":"wad","type":"uint256"}],"name":"approve","outputs":[],"type":"function"}]`))
+	require.NoError(t, err)
+	registry := newABIRegistry()
+	registry.Register(common.Address{2}, &registered)
+	call = decodeCalldata(registry, newMethodSignatures(nil), &common.Address{2}, input)
+	require.Equal(t, "guy", call.Params[0].Name)
+
+	// signatures added by the user take precedence over built-in ones
+	call = decodeCalldata(nil, newMethodSignatures([]string{"approve(address operator,uint256 limit)"}), nil, input)
+	require.Equal(t, "operator", call.Params[0].Name)
+
+	// trailing data doesn't match the signature
+	call = decodeCalldata(nil, newMethodSignatures(nil), nil, append(input, 1))
+	require.Equal(t, hexutil.Bytes{0x09, 0x5e, 0xa7, 0xb3}, call.Selector)
+	require.Empty(t, call.Method)
+
+	call = decodeCalldata(nil, newMethodSignatures(nil), nil, []byte{1, 2, 3, 4})
+	require.Empty(t, call.Method)
+	require.Nil(t, decodeCalldata(nil, newMethodSignatures(nil), nil, nil))
+
+	method, err := parseMethodSignature("swapExactETHForTokens(uint256,address[],address,uint256)")
+	require.NoError(t, err)
+	input, err = method.inputs.Pack(big.NewInt(1), []common.Address{{1}, {2}}, common.Address{3}, big.NewInt(4))
+	require.NoError(t, err)
+	call = decodeCalldata(nil, newMethodSignatures(nil), nil, append(method.selector[:], input...))
+	require.Equal(t, "swapExactETHForTokens", call.Method)
+	require.Equal(t, "["+common.Address{1}.Hex()+","+common.Address{2}.Hex()+"]", call.Params[1].Value)
+}
+
+func TestDBMethodSignatures(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	method, err := parseMethodSignature("mint(address to,uint256 amount)")
+	require.NoError(t, err)
+	require.NoError(t, db.SaveMethodSignature(method.selector[:], "mint(address to,uint256 amount)"))
+	require.NoError(t, db.SaveMethodSignature(method.selector[:], "mint(address to,uint256 amount)"))
+	signatures, err := db.GetMethodSignatures()
+	require.NoError(t, err)
+	require.Equal(t, []string{"mint(address to,uint256 amount)"}, signatures)
+
+	input, err := method.inputs.Pack(common.Address{1}, big.NewInt(10))
+	require.NoError(t, err)
+	tx := types.NewTransaction(1, common.Address{2}, nil, 10, big.NewInt(10), append(method.selector[:], input...))
+	views := []TransferView{{}, {}}
+	setDecodedCalls(views, []Transfer{
+		{Transaction: tx},
+		{Transaction: types.NewTransaction(1, common.Address{2}, big.NewInt(1), 10, big.NewInt(10), nil)},
+	}, newABIRegistry(), newMethodSignatures(signatures))
+	require.NotNil(t, views[0].Call)
+	require.Equal(t, "mint", views[0].Call.Method)
+	require.Nil(t, views[1].Call)
+
+	require.NoError(t, db.DeleteMethodSignature("mint(address to,uint256 amount)"))
+	signatures, err = db.GetMethodSignatures()
+	require.NoError(t, err)
+	require.Empty(t, signatures)
+}
@@ -0,0 +1,201 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const checkpointsRequestTimeout = 30 * time.Second
+
+var errCheckpointsSourceFailed = errors.New("checkpoints source request failed")
+
+// CheckpointRange summarizes activity in blocks From to To, both inclusive.
+type CheckpointRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	// Bloom is built like a logs bloom from addresses that sent or received ether and from
+	// addresses of log topics in the range. It is omitted if there were no transactions.
+	Bloom *types.Bloom `json:"bloom,omitempty"`
+}
+
+// inactive returns true if the range provably has no transfers of the address.
+func (r CheckpointRange) inactive(address common.Address) bool {
+	return r.Bloom == nil || !types.BloomLookup(*r.Bloom, address)
+}
+
+// CheckpointBundle is a list of checkpointed ranges of a single network.
+type CheckpointBundle struct {
+	ChainID uint64            `json:"chainId"`
+	Ranges  []CheckpointRange `json:"ranges"`
+}
+
+func (b *CheckpointBundle) validate() error {
+	if b.ChainID == 0 {
+		return errors.New("chain id is not set")
+	}
+	for i, r := range b.Ranges {
+		if r.From > r.To {
+			return fmt.Errorf("range %d: from %d is after to %d", i, r.From, r.To)
+		}
+		if i > 0 && b.Ranges[i-1].To >= r.From {
+			return fmt.Errorf("range %d: ranges are not sorted or overlap", i)
+		}
+	}
+	return nil
+}
+
+// activeRanges returns parts of the (from, to] range that may contain transfers of the address,
+// in the form expected by findBlocksWithEthTransfers: balance at the first block of a part is
+// equal to the balance at the last block of the preceding part.
+func (b *CheckpointBundle) activeRanges(address common.Address, from, to *big.Int) [][]*big.Int {
+	if b == nil || !from.IsUint64() || !to.IsUint64() {
+		return [][]*big.Int{{from, to}}
+	}
+	var (
+		cur  = from.Uint64()
+		high = to.Uint64()
+		rst  [][]*big.Int
+	)
+	for _, r := range b.Ranges {
+		if r.To <= cur || !r.inactive(address) {
+			continue
+		}
+		// balance doesn't change between the block before the range and its last block
+		low := r.From
+		if low > 0 {
+			low--
+		}
+		if low >= high {
+			break
+		}
+		if low > cur {
+			rst = append(rst, []*big.Int{new(big.Int).SetUint64(cur), new(big.Int).SetUint64(low)})
+		}
+		cur = r.To
+		if cur >= high {
+			return rst
+		}
+	}
+	return append(rst, []*big.Int{new(big.Int).SetUint64(cur), new(big.Int).SetUint64(high)})
+}
+
+// CheckpointsSource is an external source of checkpoint bundles.
+type CheckpointsSource interface {
+	CheckpointBundles(ctx context.Context) ([]CheckpointBundle, error)
+}
+
+// NewHTTPCheckpointsSource returns a source that reads a JSON list of bundles from the URL.
+func NewHTTPCheckpointsSource(endpoint string) *HTTPCheckpointsSource {
+	return &HTTPCheckpointsSource{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: checkpointsRequestTimeout},
+	}
+}
+
+// HTTPCheckpointsSource fetches a JSON list of bundles from a trusted URL.
+type HTTPCheckpointsSource struct {
+	endpoint string
+	client   *http.Client
+}
+
+// CheckpointBundles fetches bundles.
+func (s *HTTPCheckpointsSource) CheckpointBundles(ctx context.Context) ([]CheckpointBundle, error) {
+	req, err := http.NewRequest(http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v: unexpected status %d", errCheckpointsSourceFailed, resp.StatusCode)
+	}
+	return decodeCheckpointBundles(resp.Body)
+}
+
+// LoadCheckpointBundles reads a JSON list of bundles shipped with the node.
+func LoadCheckpointBundles(path string) ([]CheckpointBundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeCheckpointBundles(f)
+}
+
+func decodeCheckpointBundles(r io.Reader) ([]CheckpointBundle, error) {
+	var bundles []CheckpointBundle
+	if err := json.NewDecoder(r).Decode(&bundles); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// checkpoints keeps bundles per network. Bundles from the source are fetched once, when
+// a bundle is requested for the first time, and replace bundles shipped with the node.
+type checkpoints struct {
+	source CheckpointsSource
+
+	mu      sync.Mutex
+	fetched bool
+	bundles map[uint64]*CheckpointBundle
+}
+
+func newCheckpoints(source CheckpointsSource) *checkpoints {
+	return &checkpoints{source: source, bundles: map[uint64]*CheckpointBundle{}}
+}
+
+// Add validates bundles and adds them, invalid bundles are skipped.
+func (c *checkpoints) Add(bundles []CheckpointBundle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(bundles)
+}
+
+func (c *checkpoints) add(bundles []CheckpointBundle) {
+	for i := range bundles {
+		bundle := bundles[i]
+		if err := bundle.validate(); err != nil {
+			log.Warn("skipping invalid checkpoint bundle", "chain", bundle.ChainID, "error", err)
+			continue
+		}
+		sort.Slice(bundle.Ranges, func(i, j int) bool { return bundle.Ranges[i].From < bundle.Ranges[j].From })
+		c.bundles[bundle.ChainID] = &bundle
+	}
+}
+
+// Bundle returns the bundle of the network or nil if there is none.
+func (c *checkpoints) Bundle(ctx context.Context, chain *big.Int) *CheckpointBundle {
+	if c == nil || !chain.IsUint64() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.source != nil && !c.fetched {
+		c.fetched = true
+		callCtx, cancel := context.WithTimeout(ctx, checkpointsRequestTimeout)
+		bundles, err := c.source.CheckpointBundles(callCtx)
+		cancel()
+		if err != nil {
+			log.Warn("failed to fetch checkpoint bundles", "error", err)
+		} else {
+			c.add(bundles)
+		}
+	}
+	return c.bundles[chain.Uint64()]
+}
@@ -0,0 +1,109 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func bloomOf(addresses ...common.Address) *types.Bloom {
+	var bloom types.Bloom
+	for _, address := range addresses {
+		bloom.Add(new(big.Int).SetBytes(address.Bytes()))
+	}
+	return &bloom
+}
+
+func TestCheckpointActiveRanges(t *testing.T) {
+	account := common.Address{1}
+	other := common.Address{2}
+	bundle := &CheckpointBundle{
+		ChainID: 1,
+		Ranges: []CheckpointRange{
+			{From: 0, To: 99},
+			{From: 100, To: 199, Bloom: bloomOf(account)},
+			{From: 200, To: 299, Bloom: bloomOf(other)},
+			{From: 400, To: 499, Bloom: bloomOf(other)},
+		},
+	}
+	ranges := func(pairs ...int64) [][]*big.Int {
+		var rst [][]*big.Int
+		for i := 0; i < len(pairs); i += 2 {
+			rst = append(rst, []*big.Int{big.NewInt(pairs[i]), big.NewInt(pairs[i+1])})
+		}
+		return rst
+	}
+
+	require.Equal(t, ranges(99, 199, 299, 399, 499, 600), bundle.activeRanges(account, big.NewInt(0), big.NewInt(600)))
+	require.Equal(t, ranges(150, 199, 299, 350), bundle.activeRanges(account, big.NewInt(150), big.NewInt(350)))
+	require.Equal(t, ranges(299, 399), bundle.activeRanges(account, big.NewInt(250), big.NewInt(450)))
+	require.Empty(t, bundle.activeRanges(account, big.NewInt(200), big.NewInt(299)))
+	require.Empty(t, bundle.activeRanges(other, big.NewInt(0), big.NewInt(99)))
+
+	var missing *CheckpointBundle
+	require.Equal(t, ranges(10, 20), missing.activeRanges(account, big.NewInt(10), big.NewInt(20)))
+}
+
+func TestCheckpointBundleValidate(t *testing.T) {
+	require.NoError(t, (&CheckpointBundle{ChainID: 1, Ranges: []CheckpointRange{{From: 0, To: 10}, {From: 11, To: 11}}}).validate())
+	require.Error(t, (&CheckpointBundle{Ranges: []CheckpointRange{{From: 0, To: 10}}}).validate())
+	require.Error(t, (&CheckpointBundle{ChainID: 1, Ranges: []CheckpointRange{{From: 10, To: 0}}}).validate())
+	require.Error(t, (&CheckpointBundle{ChainID: 1, Ranges: []CheckpointRange{{From: 0, To: 10}, {From: 10, To: 20}}}).validate())
+}
+
+type checkpointsSourceStub struct {
+	bundles []CheckpointBundle
+	err     error
+	calls   int
+}
+
+func (s *checkpointsSourceStub) CheckpointBundles(context.Context) ([]CheckpointBundle, error) {
+	s.calls++
+	return s.bundles, s.err
+}
+
+func TestCheckpointsFetchedOnce(t *testing.T) {
+	source := &checkpointsSourceStub{bundles: []CheckpointBundle{
+		{ChainID: 1, Ranges: []CheckpointRange{{From: 0, To: 10}}},
+		{ChainID: 3, Ranges: []CheckpointRange{{From: 10, To: 0}}},
+	}}
+	c := newCheckpoints(source)
+	c.Add([]CheckpointBundle{{ChainID: 1}, {ChainID: 4}})
+
+	bundle := c.Bundle(context.Background(), big.NewInt(1))
+	require.NotNil(t, bundle)
+	require.Len(t, bundle.Ranges, 1)
+	require.NotNil(t, c.Bundle(context.Background(), big.NewInt(4)))
+	require.Nil(t, c.Bundle(context.Background(), big.NewInt(3)), "invalid bundle is skipped")
+	require.Equal(t, 1, source.calls)
+
+	failing := newCheckpoints(&checkpointsSourceStub{err: errors.New("unavailable")})
+	failing.Add([]CheckpointBundle{{ChainID: 1}})
+	require.NotNil(t, failing.Bundle(context.Background(), big.NewInt(1)))
+
+	var disabled *checkpoints
+	require.Nil(t, disabled.Bundle(context.Background(), big.NewInt(1)))
+}
+
+func TestLoadCheckpointBundles(t *testing.T) {
+	f, err := ioutil.TempFile("", "checkpoints")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`[{"chainId":1,"ranges":[{"from":0,"to":10},{"from":11,"to":20,"bloom":"0x` + common.Bytes2Hex(bloomOf(common.Address{1}).Bytes()) + `"}]}]`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	bundles, err := LoadCheckpointBundles(f.Name())
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	require.Nil(t, bundles[0].Ranges[0].Bloom)
+	require.Equal(t, bloomOf(common.Address{1}), bundles[0].Ranges[1].Bloom)
+}
@@ -25,6 +25,8 @@ type ethHistoricalCommand struct {
 	feed         *event.Feed
 	foundHeaders []*DBHeader
 	noLimit      bool
+	// checkpoints are optional. If set, ranges without activity of the address are skipped.
+	checkpoints *CheckpointBundle
 
 	from, to, resultingFrom *big.Int
 }
@@ -40,7 +42,14 @@ func (c *ethHistoricalCommand) Run(ctx context.Context) (err error) {
 	start := time.Now()
 	totalRequests, cacheHits := c.balanceCache.getStats(c.address)
 	log.Info("balance cache before checking range", "total", totalRequests, "cached", totalRequests-cacheHits)
-	from, headers, err := findBlocksWithEthTransfers(ctx, c.client, c.balanceCache, c.eth, c.address, c.from, c.to, c.noLimit)
+	ranges := c.checkpoints.activeRanges(c.address, c.from, c.to)
+	if len(ranges) == 0 {
+		log.Info("eth historical range is inactive according to checkpoints", "address", c.address, "from", c.from, "to", c.to)
+		c.foundHeaders = []*DBHeader{}
+		c.resultingFrom = c.from
+		return nil
+	}
+	from, headers, err := findBlocksWithEthTransfersInRanges(ctx, c.client, c.balanceCache, c.eth, c.address, ranges, c.noLimit)
 
 	if err != nil {
 		return err
@@ -174,6 +183,8 @@ func (c *newBlocksTransfersCommand) getAllTransfers(parent context.Context, from
 			fromByAddress: fromByAddress,
 			toByAddress:   toByAddress,
 			noLimit:       true,
+			l2:            c.eth.l2,
+			withdrawals:   c.eth.withdrawals,
 		}
 
 		if err := blocksCommand.Command()(parent); err != nil {
@@ -191,6 +202,8 @@ func (c *newBlocksTransfersCommand) getAllTransfers(parent context.Context, from
 				chain:           c.chain,
 				client:          c.erc20.client,
 				blocksByAddress: map[common.Address][]*big.Int{address: blocks},
+				l2:              c.eth.l2,
+				withdrawals:     c.eth.withdrawals,
 			}
 
 			err := txCommand.Command()(parent)
@@ -245,6 +258,14 @@ func (c *newBlocksTransfersCommand) getAllTransfers(parent context.Context, from
 			log.Error("failed to persist transfers", "error", err)
 			return nil, err
 		}
+
+		if c.erc20 != nil {
+			// Errors are not critical, metadata is fetched again when transfers are requested.
+			_, err = GetTokensMetadata(parent, c.db, c.erc20.client, tokenContracts(all))
+			if err != nil {
+				log.Error("failed to load tokens metadata", "error", err)
+			}
+		}
 	}
 
 	return transfersByAddress, nil
@@ -450,6 +471,11 @@ type controlCommand struct {
 	client      *ethclient.Client
 	feed        *event.Feed
 	safetyDepth *big.Int
+	indexer     HistoryIndexer
+	tracker     *syncTracker
+	l2          RPCClient
+	withdrawals RPCClient
+	checkpoints *checkpoints
 }
 
 // run fast indexing for every accont up to canonical chain head minus safety depth.
@@ -466,15 +492,18 @@ func (c *findAndCheckBlockRangeCommand) fastIndex(ctx context.Context, bCache *b
 			balanceCache: bCache,
 			address:      address,
 			eth: &ETHTransferDownloader{
-				client:   c.client,
-				accounts: []common.Address{address},
-				signer:   types.NewEIP155Signer(c.chain),
-				db:       c.db,
+				client:      c.client,
+				accounts:    []common.Address{address},
+				signer:      types.NewEIP155Signer(c.chain),
+				db:          c.db,
+				l2:          c.l2,
+				withdrawals: c.withdrawals,
 			},
-			feed:    c.feed,
-			from:    fromByAddress[address],
-			to:      toByAddress[address],
-			noLimit: c.noLimit,
+			feed:        c.feed,
+			from:        fromByAddress[address],
+			to:          toByAddress[address],
+			noLimit:     c.noLimit,
+			checkpoints: c.checkpoints,
 		}
 		commands[i] = eth
 		group.Add(eth.Command())
@@ -500,28 +529,35 @@ func (c *findAndCheckBlockRangeCommand) fastIndexErc20(ctx context.Context, from
 	start := time.Now()
 	group := NewGroup(ctx)
 
-	commands := make([]*erc20HistoricalCommand, len(c.accounts))
-	for i, address := range c.accounts {
-		erc20 := &erc20HistoricalCommand{
-			db:           c.db,
-			erc20:        NewERC20TransfersDownloader(c.client, []common.Address{address}, types.NewEIP155Signer(c.chain)),
-			client:       c.client,
-			feed:         c.feed,
-			address:      address,
-			from:         fromByAddress[address],
-			to:           toByAddress[address],
-			foundHeaders: []*DBHeader{},
+	commands := make([]*erc20HistoricalCommand, 0, len(c.accounts))
+	for _, address := range c.accounts {
+		for _, blocksRange := range c.checkpoints.activeRanges(address, fromByAddress[address], toByAddress[address]) {
+			downloader := NewERC20TransfersDownloader(c.client, []common.Address{address}, types.NewEIP155Signer(c.chain))
+			downloader.l2 = c.l2
+			erc20 := &erc20HistoricalCommand{
+				db:           c.db,
+				erc20:        downloader,
+				client:       c.client,
+				feed:         c.feed,
+				address:      address,
+				from:         blocksRange[0],
+				to:           blocksRange[1],
+				foundHeaders: []*DBHeader{},
+			}
+			commands = append(commands, erc20)
+			group.Add(erc20.Command())
 		}
-		commands[i] = erc20
-		group.Add(erc20.Command())
 	}
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case <-group.WaitAsync():
 		headres := map[common.Address][]*DBHeader{}
+		for _, address := range c.accounts {
+			headres[address] = []*DBHeader{}
+		}
 		for _, command := range commands {
-			headres[command.address] = command.foundHeaders
+			headres[command.address] = append(headres[command.address], command.foundHeaders...)
 		}
 		log.Info("fast indexer Erc20 finished", "in", time.Since(start))
 		return headres, nil
@@ -545,7 +581,7 @@ func getTransfersByBlocks(ctx context.Context, db *Database, downloader *ETHTran
 	return allTransfers, nil
 }
 
-func loadTransfers(ctx context.Context, accounts []common.Address, db *Database, client *ethclient.Client, chain *big.Int, limit int, blocksByAddress map[common.Address][]*big.Int) (map[common.Address][]Transfer, error) {
+func loadTransfers(ctx context.Context, accounts []common.Address, db *Database, client *ethclient.Client, l2, withdrawals RPCClient, chain *big.Int, limit int, blocksByAddress map[common.Address][]*big.Int) (map[common.Address][]Transfer, error) {
 	start := time.Now()
 	group := NewGroup(ctx)
 
@@ -563,10 +599,12 @@ func loadTransfers(ctx context.Context, accounts []common.Address, db *Database,
 				client:  client,
 				address: address,
 				eth: &ETHTransferDownloader{
-					client:   client,
-					accounts: []common.Address{address},
-					signer:   types.NewEIP155Signer(chain),
-					db:       db,
+					client:      client,
+					accounts:    []common.Address{address},
+					signer:      types.NewEIP155Signer(chain),
+					db:          db,
+					l2:          l2,
+					withdrawals: withdrawals,
 				},
 				block: block,
 			}
@@ -599,7 +637,7 @@ func loadTransfers(ctx context.Context, accounts []common.Address, db *Database,
 }
 
 func (c *controlCommand) LoadTransfers(ctx context.Context, downloader *ETHTransferDownloader, limit int) (map[common.Address][]Transfer, error) {
-	return loadTransfers(ctx, c.accounts, c.db, c.client, c.chain, limit, make(map[common.Address][]*big.Int))
+	return loadTransfers(ctx, c.accounts, c.db, c.client, c.l2, c.withdrawals, c.chain, limit, make(map[common.Address][]*big.Int))
 }
 
 /*
@@ -701,8 +739,13 @@ func findFirstRanges(c context.Context, accounts []common.Address, initialTo *bi
 	return res, nil
 }
 
-func (c *controlCommand) Run(parent context.Context) error {
+func (c *controlCommand) Run(parent context.Context) (err error) {
 	log.Info("start control command")
+	defer func() {
+		if err != nil {
+			c.tracker.setError(c.accounts, err)
+		}
+	}()
 	ctx, cancel := context.WithTimeout(parent, 3*time.Second)
 	head, err := c.client.HeaderByNumber(ctx, nil)
 	cancel()
@@ -722,15 +765,20 @@ func (c *controlCommand) Run(parent context.Context) error {
 		return err
 	}
 
+	target := new(big.Int).Sub(head.Number, c.safetyDepth)
+	if target.Cmp(zero) <= 0 {
+		target = zero
+	}
+
+	if c.indexer != nil && len(accountsWithoutHistory) > 0 {
+		accountsWithoutHistory = c.indexHistory(parent, accountsWithoutHistory, target, lastKnownEthBlocks)
+	}
+
 	fromMap, err := findFirstRanges(parent, accountsWithoutHistory, head.Number, c.client)
 	if err != nil {
 		return err
 	}
 
-	target := new(big.Int).Sub(head.Number, c.safetyDepth)
-	if target.Cmp(zero) <= 0 {
-		target = zero
-	}
 	ctx, cancel = context.WithTimeout(parent, 3*time.Second)
 	head, err = c.client.HeaderByNumber(ctx, target)
 	cancel()
@@ -761,6 +809,10 @@ func (c *controlCommand) Run(parent context.Context) error {
 		feed:          c.feed,
 		fromByAddress: fromByAddress,
 		toByAddress:   toByAddress,
+		tracker:       c.tracker,
+		l2:            c.l2,
+		withdrawals:   c.withdrawals,
+		checkpoints:   c.checkpoints.Bundle(parent, c.chain),
 	}
 
 	err = cmnd.Command()(parent)
@@ -769,15 +821,18 @@ func (c *controlCommand) Run(parent context.Context) error {
 	}
 
 	downloader := &ETHTransferDownloader{
-		client:   c.client,
-		accounts: c.accounts,
-		signer:   types.NewEIP155Signer(c.chain),
-		db:       c.db,
+		client:      c.client,
+		accounts:    c.accounts,
+		signer:      types.NewEIP155Signer(c.chain),
+		db:          c.db,
+		l2:          c.l2,
+		withdrawals: c.withdrawals,
 	}
 	_, err = c.LoadTransfers(parent, downloader, 40)
 	if err != nil {
 		return err
 	}
+	c.tracker.setError(c.accounts, nil)
 
 	c.feed.Send(Event{
 		Type:        EventRecentHistoryReady,
@@ -809,6 +864,33 @@ func (c *controlCommand) Run(parent context.Context) error {
 	return err
 }
 
+// indexHistory runs external indexer for accounts without history. Accounts that were indexed
+// successfully are added to lastKnown, the rest is returned to be discovered from the node.
+// If indexer results were truncated the range after the last indexed block is discovered from the node.
+func (c *controlCommand) indexHistory(parent context.Context, accounts []common.Address, to *big.Int, lastKnown map[common.Address]*big.Int) []common.Address {
+	rst := []common.Address{}
+	for _, address := range accounts {
+		cmd := &indexHistoryCommand{
+			db:      c.db,
+			indexer: c.indexer,
+			client:  c.client,
+			chain:   c.chain,
+			address: address,
+			to:      to,
+		}
+		ctx, cancel := context.WithTimeout(parent, 5*time.Minute)
+		err := cmd.Run(ctx)
+		cancel()
+		if err != nil {
+			log.Warn("history indexer failed, falling back to the node", "address", address, "error", err)
+			rst = append(rst, address)
+			continue
+		}
+		lastKnown[address] = cmd.indexedTo
+	}
+	return rst
+}
+
 func (c *controlCommand) Command() Command {
 	return FiniteCommand{
 		Interval: 5 * time.Second,
@@ -898,6 +980,8 @@ type loadTransfersCommand struct {
 	client                  *ethclient.Client
 	blocksByAddress         map[common.Address][]*big.Int
 	foundTransfersByAddress map[common.Address][]Transfer
+	l2                      RPCClient
+	withdrawals             RPCClient
 }
 
 func (c *loadTransfersCommand) Command() Command {
@@ -908,15 +992,17 @@ func (c *loadTransfersCommand) Command() Command {
 }
 
 func (c *loadTransfersCommand) LoadTransfers(ctx context.Context, downloader *ETHTransferDownloader, limit int, blocksByAddress map[common.Address][]*big.Int) (map[common.Address][]Transfer, error) {
-	return loadTransfers(ctx, c.accounts, c.db, c.client, c.chain, limit, blocksByAddress)
+	return loadTransfers(ctx, c.accounts, c.db, c.client, c.l2, c.withdrawals, c.chain, limit, blocksByAddress)
 }
 
 func (c *loadTransfersCommand) Run(parent context.Context) (err error) {
 	downloader := &ETHTransferDownloader{
-		client:   c.client,
-		accounts: c.accounts,
-		signer:   types.NewEIP155Signer(c.chain),
-		db:       c.db,
+		client:      c.client,
+		accounts:    c.accounts,
+		signer:      types.NewEIP155Signer(c.chain),
+		db:          c.db,
+		l2:          c.l2,
+		withdrawals: c.withdrawals,
 	}
 	transfersByAddress, err := c.LoadTransfers(parent, downloader, 40, c.blocksByAddress)
 	if err != nil {
@@ -938,6 +1024,11 @@ type findAndCheckBlockRangeCommand struct {
 	toByAddress   map[common.Address]*big.Int
 	foundHeaders  map[common.Address][]*DBHeader
 	noLimit       bool
+	tracker       *syncTracker
+	l2            RPCClient
+	withdrawals   RPCClient
+	// checkpoints are optional. If set, ranges without activity of accounts are not downloaded.
+	checkpoints *CheckpointBundle
 }
 
 func (c *findAndCheckBlockRangeCommand) Command() Command {
@@ -949,6 +1040,10 @@ func (c *findAndCheckBlockRangeCommand) Command() Command {
 
 func (c *findAndCheckBlockRangeCommand) Run(parent context.Context) (err error) {
 	log.Debug("start findAndCHeckBlockRangeCommand")
+	for _, address := range c.accounts {
+		done := c.tracker.start(address, c.fromByAddress[address], c.toByAddress[address])
+		defer done()
+	}
 	newFromByAddress, ethHeadersByAddress, err := c.fastIndex(parent, c.balanceCache, c.fromByAddress, c.toByAddress)
 	if err != nil {
 		return err
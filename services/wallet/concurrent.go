@@ -167,8 +167,13 @@ func checkRanges(parent context.Context, client reactorClient, cache BalanceCach
 }
 
 func findBlocksWithEthTransfers(parent context.Context, client reactorClient, cache BalanceCache, downloader TransferDownloader, account common.Address, low, high *big.Int, noLimit bool) (from *big.Int, headers []*DBHeader, err error) {
-	ranges := [][]*big.Int{{low, high}}
-	minBlock := big.NewInt(low.Int64())
+	return findBlocksWithEthTransfersInRanges(parent, client, cache, downloader, account, [][]*big.Int{{low, high}}, noLimit)
+}
+
+// findBlocksWithEthTransfersInRanges is like findBlocksWithEthTransfers, but checks only given
+// sorted ranges, e.g. ranges that checkpoints don't prove to be inactive.
+func findBlocksWithEthTransfersInRanges(parent context.Context, client reactorClient, cache BalanceCache, downloader TransferDownloader, account common.Address, ranges [][]*big.Int, noLimit bool) (from *big.Int, headers []*DBHeader, err error) {
+	minBlock := big.NewInt(ranges[0][0].Int64())
 	headers = []*DBHeader{}
 	var lvl = 1
 	for len(ranges) > 0 && lvl <= 30 {
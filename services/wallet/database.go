@@ -7,8 +7,10 @@ import (
 	"errors"
 	"math/big"
 	"reflect"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -233,13 +235,31 @@ func (db *Database) GetTransfersInRange(address common.Address, start, end *big.
 	return query.Scan(rows)
 }
 
+// GetTransfersSince loads transfers for a given address made in blocks with a timestamp not older than since.
+func (db *Database) GetTransfersSince(address common.Address, since uint64) (rst []Transfer, err error) {
+	query := newTransfersQuery().FilterNetwork(db.network).FilterAddress(address).FilterTimestampFrom(since).FilterLoaded(1)
+	rows, err := db.db.Query(query.String(), query.Args()...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	return query.Scan(rows)
+}
+
 // GetTransfersByAddress loads transfers for a given address between two blocks.
 func (db *Database) GetTransfersByAddress(address common.Address, toBlock *big.Int, limit int64) (rst []Transfer, err error) {
+	return db.GetTaggedTransfersByAddress(address, toBlock, limit, nil)
+}
+
+// GetTaggedTransfersByAddress loads transfers for a given address that have at least one of the tags,
+// all transfers if tags are empty.
+func (db *Database) GetTaggedTransfersByAddress(address common.Address, toBlock *big.Int, limit int64, tags []string) (rst []Transfer, err error) {
 	query := newTransfersQuery().
 		FilterNetwork(db.network).
 		FilterAddress(address).
 		FilterEnd(toBlock).
 		FilterLoaded(1).
+		FilterTags(db.network, tags).
 		Limit(limit)
 
 	rows, err := db.db.Query(query.String(), query.Args()...)
@@ -452,6 +472,41 @@ func (db *Database) GetLastKnownBlockByAddresses(addresses []common.Address) (ma
 	return res, accountsWithoutHistory, nil
 }
 
+// ResetFromBlock removes transfers and blocks of the address starting from a given block
+// and truncates checked ranges, so that these blocks are scanned again.
+func (db Database) ResetFromBlock(address common.Address, from *big.Int) (err error) {
+	var (
+		tx *sql.Tx
+	)
+	tx, err = db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.Exec("DELETE FROM transfers WHERE address = ? AND network_id = ? AND blk_number >= ?", address, db.network, (*SQLBigInt)(from))
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec("DELETE FROM blocks WHERE address = ? AND network_id = ? AND blk_number >= ?", address, db.network, (*SQLBigInt)(from))
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec("DELETE FROM blocks_ranges WHERE address = ? AND network_id = ? AND blk_from >= ?", address, db.network, (*SQLBigInt)(from))
+	if err != nil {
+		return
+	}
+	last := new(big.Int).Sub(from, big.NewInt(1))
+	_, err = tx.Exec("UPDATE blocks_ranges SET blk_to = ? WHERE address = ? AND network_id = ? AND blk_to > ?", (*SQLBigInt)(last), address, db.network, (*SQLBigInt)(last))
+	return
+}
+
 // GetTransfers load transfers transfer betweeen two blocks.
 func (db *Database) GetTransfers(start, end *big.Int) (rst []Transfer, err error) {
 	query := newTransfersQuery().FilterNetwork(db.network).FilterStart(start).FilterEnd(end).FilterLoaded(1)
@@ -463,6 +518,96 @@ func (db *Database) GetTransfers(start, end *big.Int) (rst []Transfer, err error
 	return query.Scan(rows)
 }
 
+// GetTransferByHash loads a transfer of the address made by the transaction with a given hash.
+// Nil is returned if the transfer is not in the database.
+func (db *Database) GetTransferByHash(address common.Address, txHash common.Hash) (*Transfer, error) {
+	query := newTransfersQuery().
+		FilterNetwork(db.network).
+		FilterAddress(address).
+		FilterTransactionHash(txHash).
+		FilterLoaded(1)
+
+	rows, err := db.db.Query(query.String(), query.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rst, err := query.Scan(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rst) == 0 {
+		return nil, nil
+	}
+	return &rst[0], nil
+}
+
+// SaveTransferWithBlock stores a transfer that was fetched outside of the regular
+// blocks processing together with its block.
+func (db Database) SaveTransferWithBlock(transfer Transfer) (err error) {
+	var (
+		tx *sql.Tx
+	)
+	tx, err = db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	header := &DBHeader{
+		Number: transfer.BlockNumber,
+		Hash:   transfer.BlockHash,
+		Loaded: true,
+	}
+	err = insertBlocksWithTransactions(tx, transfer.Address, db.network, []*DBHeader{header})
+	if err != nil {
+		return
+	}
+
+	err = updateOrInsertTransfers(tx, db.network, []Transfer{transfer})
+	return
+}
+
+// GetBridgeCounterparts returns transfers downloaded on other networks which carry the same
+// cross domain messages, indexed by message hash.
+func (db *Database) GetBridgeCounterparts(hashes []common.Hash) (map[common.Hash]BridgeCounterpart, error) {
+	rst := map[common.Hash]BridgeCounterpart{}
+	if len(hashes) == 0 {
+		return rst, nil
+	}
+	/* #nosec */
+	query := "SELECT bridge_message_hash, network_id, tx_hash, address FROM transfers WHERE network_id != ? AND bridge_message_hash IN (?" + strings.Repeat(",?", len(hashes)-1) + ")"
+	args := make([]interface{}, 0, len(hashes)+1)
+	args = append(args, db.network)
+	for _, hash := range hashes {
+		args = append(args, hash)
+	}
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			hash        common.Hash
+			counterpart BridgeCounterpart
+		)
+		err := rows.Scan(&hash, &counterpart.NetworkID, &counterpart.TxHash, &counterpart.Address)
+		if err != nil {
+			return nil, err
+		}
+		rst[hash] = counterpart
+	}
+	return rst, rows.Err()
+}
+
 func (db *Database) GetPreloadedTransactions(address common.Address, blockHash common.Hash) (rst []Transfer, err error) {
 	query := newTransfersQuery().
 		FilterNetwork(db.network).
@@ -583,6 +728,709 @@ func (db *Database) DeleteCustomToken(address common.Address) error {
 	return err
 }
 
+// GetTokensMetadata returns metadata of tokens fetched from their contracts indexed by contract address.
+func (db *Database) GetTokensMetadata(addresses []common.Address) (map[common.Address]*Token, error) {
+	rst := map[common.Address]*Token{}
+	if len(addresses) == 0 {
+		return rst, nil
+	}
+	/* #nosec */
+	query := "SELECT address, name, symbol, decimals FROM token_metadata WHERE network_id = ? AND address IN (?" + strings.Repeat(",?", len(addresses)-1) + ")"
+	args := make([]interface{}, 0, len(addresses)+1)
+	args = append(args, db.network)
+	for _, address := range addresses {
+		args = append(args, address)
+	}
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		token := &Token{}
+		err := rows.Scan(&token.Address, &token.Name, &token.Symbol, &token.Decimals)
+		if err != nil {
+			return nil, err
+		}
+		rst[token.Address] = token
+	}
+	return rst, rows.Err()
+}
+
+// SaveTokenMetadata stores metadata of a token contract.
+func (db *Database) SaveTokenMetadata(token Token) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO token_metadata (network_id, address, name, symbol, decimals) VALUES (?, ?, ?, ?, ?)", db.network, token.Address, token.Name, token.Symbol, token.Decimals)
+	return err
+}
+
+// GetHistoricalPrice returns a cached price of the symbol in the currency at the day.
+func (db *Database) GetHistoricalPrice(symbol, currency string, day uint64) (price float64, exist bool, err error) {
+	err = db.db.QueryRow("SELECT price FROM historical_prices WHERE symbol = ? AND currency = ? AND day = ?", symbol, currency, day).Scan(&price)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return price, true, nil
+}
+
+// SaveHistoricalPrice caches a price of the symbol in the currency at the day.
+func (db *Database) SaveHistoricalPrice(symbol, currency string, day uint64, price float64) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO historical_prices (symbol, currency, day, price) VALUES (?, ?, ?, ?)", symbol, currency, day, price)
+	return err
+}
+
+// GetContractABIs returns ABIs registered by the user indexed by contract address.
+func (db *Database) GetContractABIs() (map[common.Address]string, error) {
+	rows, err := db.db.Query("SELECT address, abi FROM contract_abis WHERE network_id = ?", db.network)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rst := map[common.Address]string{}
+	for rows.Next() {
+		var (
+			address  common.Address
+			contract string
+		)
+		if err := rows.Scan(&address, &contract); err != nil {
+			return nil, err
+		}
+		rst[address] = contract
+	}
+	return rst, rows.Err()
+}
+
+// SaveContractABI stores an ABI of the contract, an existing ABI is replaced.
+func (db *Database) SaveContractABI(address common.Address, contract string) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO contract_abis (network_id, address, abi) VALUES (?, ?, ?)", db.network, address, contract)
+	return err
+}
+
+// GetShareTokens returns kinds of share converters registered by the user indexed by token address.
+func (db *Database) GetShareTokens() (map[common.Address]string, error) {
+	rows, err := db.db.Query("SELECT address, kind FROM share_tokens WHERE network_id = ?", db.network)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rst := map[common.Address]string{}
+	for rows.Next() {
+		var (
+			address common.Address
+			kind    string
+		)
+		if err := rows.Scan(&address, &kind); err != nil {
+			return nil, err
+		}
+		rst[address] = kind
+	}
+	return rst, rows.Err()
+}
+
+// SaveShareToken stores a kind of the share converter for the token, an existing kind is replaced.
+func (db *Database) SaveShareToken(address common.Address, kind string) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO share_tokens (network_id, address, kind) VALUES (?, ?, ?)", db.network, address, kind)
+	return err
+}
+
+// GetMethodSignatures returns method signatures added by the user.
+func (db *Database) GetMethodSignatures() ([]string, error) {
+	rows, err := db.db.Query("SELECT signature FROM method_signatures ORDER BY signature")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []string
+	for rows.Next() {
+		var signature string
+		if err := rows.Scan(&signature); err != nil {
+			return nil, err
+		}
+		rst = append(rst, signature)
+	}
+	return rst, rows.Err()
+}
+
+// SaveMethodSignature stores a method signature with its selector.
+func (db *Database) SaveMethodSignature(selector []byte, signature string) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO method_signatures (selector, signature) VALUES (?, ?)", hexutil.Encode(selector), signature)
+	return err
+}
+
+// DeleteMethodSignature removes a method signature added by the user.
+func (db *Database) DeleteMethodSignature(signature string) error {
+	_, err := db.db.Exec("DELETE FROM method_signatures WHERE signature = ?", signature)
+	return err
+}
+
+// SaveHardwareAccount stores a derivation path of the account on a hardware wallet.
+func (db *Database) SaveHardwareAccount(account HardwareAccount) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO hardware_accounts (address, wallet, path) VALUES (?, ?, ?)", account.Address, account.Wallet, account.Path)
+	return err
+}
+
+// GetHardwareAccount returns an account on a hardware wallet. Nil is returned if the account is not known.
+func (db *Database) GetHardwareAccount(address common.Address) (*HardwareAccount, error) {
+	account := &HardwareAccount{Address: address}
+	err := db.db.QueryRow("SELECT wallet, path FROM hardware_accounts WHERE address = ?", address).Scan(&account.Wallet, &account.Path)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetHardwareAccounts returns all accounts on hardware wallets.
+func (db *Database) GetHardwareAccounts() ([]HardwareAccount, error) {
+	rows, err := db.db.Query("SELECT address, wallet, path FROM hardware_accounts")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []HardwareAccount
+	for rows.Next() {
+		var account HardwareAccount
+		if err := rows.Scan(&account.Address, &account.Wallet, &account.Path); err != nil {
+			return nil, err
+		}
+		rst = append(rst, account)
+	}
+	return rst, rows.Err()
+}
+
+// DeleteHardwareAccount removes an account on a hardware wallet.
+func (db *Database) DeleteHardwareAccount(address common.Address) error {
+	_, err := db.db.Exec("DELETE FROM hardware_accounts WHERE address = ?", address)
+	return err
+}
+
+// SaveKeycardPairing stores a pairing of a keycard.
+func (db *Database) SaveKeycardPairing(pairing KeycardPairing) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO keycard_pairings (instance_uid, pairing_key, pairing_index, pin_retries, paired_at) VALUES (?, ?, ?, ?, ?)",
+		pairing.InstanceUID, []byte(pairing.Key), pairing.Index, pairing.PINRetries, pairing.PairedAt)
+	return err
+}
+
+// SaveKeycardPINRetries updates the number of PIN attempts left on a paired keycard.
+func (db *Database) SaveKeycardPINRetries(uid string, retries int) error {
+	_, err := db.db.Exec("UPDATE keycard_pairings SET pin_retries = ? WHERE instance_uid = ?", retries, uid)
+	return err
+}
+
+// GetKeycardPairing returns a pairing of a keycard. Nil is returned if the keycard is not paired.
+func (db *Database) GetKeycardPairing(uid string) (*KeycardPairing, error) {
+	pairing := &KeycardPairing{InstanceUID: uid}
+	err := db.db.QueryRow("SELECT pairing_key, pairing_index, pin_retries, paired_at FROM keycard_pairings WHERE instance_uid = ?", uid).
+		Scan(&pairing.Key, &pairing.Index, &pairing.PINRetries, &pairing.PairedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pairing, nil
+}
+
+// GetKeycardPairings returns pairings of all paired keycards.
+func (db *Database) GetKeycardPairings() ([]KeycardPairing, error) {
+	rows, err := db.db.Query("SELECT instance_uid, pairing_key, pairing_index, pin_retries, paired_at FROM keycard_pairings ORDER BY paired_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []KeycardPairing
+	for rows.Next() {
+		var pairing KeycardPairing
+		if err := rows.Scan(&pairing.InstanceUID, &pairing.Key, &pairing.Index, &pairing.PINRetries, &pairing.PairedAt); err != nil {
+			return nil, err
+		}
+		rst = append(rst, pairing)
+	}
+	return rst, rows.Err()
+}
+
+// DeleteKeycardPairing removes a pairing of a keycard together with accounts added from the keycard.
+func (db *Database) DeleteKeycardPairing(uid string) (err error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+	if _, err = tx.Exec("DELETE FROM keycard_accounts WHERE instance_uid = ?", uid); err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM keycard_pairings WHERE instance_uid = ?", uid)
+	return err
+}
+
+// SaveKeycardAccount stores a derivation path of the account on a keycard.
+func (db *Database) SaveKeycardAccount(account KeycardAccount) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO keycard_accounts (address, instance_uid, path) VALUES (?, ?, ?)", account.Address, account.InstanceUID, account.Path)
+	return err
+}
+
+// GetKeycardAccount returns an account on a keycard. Nil is returned if the account is not known.
+func (db *Database) GetKeycardAccount(address common.Address) (*KeycardAccount, error) {
+	account := &KeycardAccount{Address: address}
+	err := db.db.QueryRow("SELECT instance_uid, path FROM keycard_accounts WHERE address = ?", address).Scan(&account.InstanceUID, &account.Path)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetKeycardAccounts returns all accounts on keycards.
+func (db *Database) GetKeycardAccounts() ([]KeycardAccount, error) {
+	rows, err := db.db.Query("SELECT address, instance_uid, path FROM keycard_accounts")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []KeycardAccount
+	for rows.Next() {
+		var account KeycardAccount
+		if err := rows.Scan(&account.Address, &account.InstanceUID, &account.Path); err != nil {
+			return nil, err
+		}
+		rst = append(rst, account)
+	}
+	return rst, rows.Err()
+}
+
+// DeleteKeycardAccount removes an account on a keycard.
+func (db *Database) DeleteKeycardAccount(address common.Address) error {
+	_, err := db.db.Exec("DELETE FROM keycard_accounts WHERE address = ?", address)
+	return err
+}
+
+// SaveSpendingLimit stores a spending limit of the address. A limit without daily
+// and weekly values is removed.
+func (db *Database) SaveSpendingLimit(address common.Address, limit SpendingLimit) error {
+	if limit.Daily == nil && limit.Weekly == nil {
+		_, err := db.db.Exec("DELETE FROM spending_limits WHERE network_id = ? AND address = ? AND token = ?", db.network, address, limit.Token)
+		return err
+	}
+	_, err := db.db.Exec("INSERT OR REPLACE INTO spending_limits (network_id, address, token, daily, weekly) VALUES (?, ?, ?, ?, ?)",
+		db.network, address, limit.Token, bigToNullString(limit.Daily), bigToNullString(limit.Weekly))
+	return err
+}
+
+// GetSpendingLimits returns spending limits of the address.
+func (db *Database) GetSpendingLimits(address common.Address) ([]SpendingLimit, error) {
+	rows, err := db.db.Query("SELECT token, daily, weekly FROM spending_limits WHERE network_id = ? AND address = ?", db.network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []SpendingLimit
+	for rows.Next() {
+		var (
+			limit         SpendingLimit
+			daily, weekly sql.NullString
+		)
+		if err := rows.Scan(&limit.Token, &daily, &weekly); err != nil {
+			return nil, err
+		}
+		if limit.Daily, err = nullStringToBig(daily); err != nil {
+			return nil, err
+		}
+		if limit.Weekly, err = nullStringToBig(weekly); err != nil {
+			return nil, err
+		}
+		rst = append(rst, limit)
+	}
+	return rst, rows.Err()
+}
+
+// SaveContactAddress inserts or replaces a contact in the address book.
+func (db *Database) SaveContactAddress(contact ContactAddress) error {
+	var ensName, ensAddress sql.NullString
+	if contact.ENSName != "" {
+		ensName = sql.NullString{String: contact.ENSName, Valid: true}
+	}
+	if contact.ENSAddress != nil {
+		ensAddress = sql.NullString{String: contact.ENSAddress.Hex(), Valid: true}
+	}
+	_, err := db.db.Exec("INSERT OR REPLACE INTO address_book (network_id, address, name, ens_name, ens_address, resolved_at) VALUES (?, ?, ?, ?, ?, ?)",
+		db.network, contact.Address, contact.Name, ensName, ensAddress, contact.ResolvedAt)
+	return err
+}
+
+// GetContactAddresses returns all contacts in the address book.
+func (db *Database) GetContactAddresses() ([]ContactAddress, error) {
+	rows, err := db.db.Query("SELECT address, name, ens_name, ens_address, resolved_at FROM address_book WHERE network_id = ? ORDER BY name", db.network)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []ContactAddress
+	for rows.Next() {
+		var (
+			contact             ContactAddress
+			ensName, ensAddress sql.NullString
+			resolvedAt          sql.NullInt64
+		)
+		if err := rows.Scan(&contact.Address, &contact.Name, &ensName, &ensAddress, &resolvedAt); err != nil {
+			return nil, err
+		}
+		contact.ENSName = ensName.String
+		contact.ResolvedAt = resolvedAt.Int64
+		if ensAddress.Valid {
+			address := common.HexToAddress(ensAddress.String)
+			contact.ENSAddress = &address
+			contact.ENSChanged = address != contact.Address
+		}
+		rst = append(rst, contact)
+	}
+	return rst, rows.Err()
+}
+
+// DeleteContactAddress removes a contact from the address book.
+func (db *Database) DeleteContactAddress(address common.Address) error {
+	_, err := db.db.Exec("DELETE FROM address_book WHERE network_id = ? AND address = ?", db.network, address)
+	return err
+}
+
+// SaveTransferNote replaces the note and tags of a transfer. An empty note without tags is removed.
+func (db *Database) SaveTransferNote(hash common.Hash, note TransferNote) (err error) {
+	var tx *sql.Tx
+	tx, err = db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.Exec("DELETE FROM transfer_notes WHERE network_id = ? AND hash = ?", db.network, hash); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM transfer_tags WHERE network_id = ? AND hash = ?", db.network, hash); err != nil {
+		return err
+	}
+	if note.Note != "" || len(note.Tags) > 0 {
+		if _, err = tx.Exec("INSERT INTO transfer_notes (network_id, hash, note) VALUES (?, ?, ?)", db.network, hash, note.Note); err != nil {
+			return err
+		}
+	}
+	for _, tag := range note.Tags {
+		if _, err = tx.Exec("INSERT INTO transfer_tags (network_id, hash, tag) VALUES (?, ?, ?)", db.network, hash, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTransferNotes returns notes and tags of transfers with given hashes, transfers without a note are omitted.
+func (db *Database) GetTransferNotes(hashes []common.Hash) (map[common.Hash]TransferNote, error) {
+	rst := make(map[common.Hash]TransferNote)
+	if len(hashes) == 0 {
+		return rst, nil
+	}
+	placeholders := strings.Repeat("?, ", len(hashes)-1) + "?"
+	args := make([]interface{}, 0, len(hashes)+1)
+	args = append(args, db.network)
+	for _, hash := range hashes {
+		args = append(args, hash)
+	}
+
+	rows, err := db.db.Query("SELECT hash, note FROM transfer_notes WHERE network_id = ? AND hash IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			hash common.Hash
+			note TransferNote
+		)
+		if err := rows.Scan(&hash, &note.Note); err != nil {
+			return nil, err
+		}
+		note.Tags = []string{}
+		rst[hash] = note
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagRows, err := db.db.Query("SELECT hash, tag FROM transfer_tags WHERE network_id = ? AND hash IN ("+placeholders+") ORDER BY tag", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var (
+			hash common.Hash
+			tag  string
+		)
+		if err := tagRows.Scan(&hash, &tag); err != nil {
+			return nil, err
+		}
+		note := rst[hash]
+		note.Tags = append(note.Tags, tag)
+		rst[hash] = note
+	}
+	return rst, tagRows.Err()
+}
+
+// SaveOwnedToken inserts or replaces a non-zero token balance of the address.
+func (db *Database) SaveOwnedToken(address common.Address, token OwnedToken) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO owned_tokens (network_id, address, token, balance, checked_at) VALUES (?, ?, ?, ?, ?)",
+		db.network, address, token.Address, bigToNullString(token.Balance), token.CheckedAt)
+	return err
+}
+
+// GetOwnedTokens returns cached token balances of the address.
+func (db *Database) GetOwnedTokens(address common.Address) ([]OwnedToken, error) {
+	rows, err := db.db.Query("SELECT token, balance, checked_at FROM owned_tokens WHERE network_id = ? AND address = ? ORDER BY token", db.network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rst []OwnedToken
+	for rows.Next() {
+		var (
+			token   OwnedToken
+			balance sql.NullString
+		)
+		if err := rows.Scan(&token.Address, &balance, &token.CheckedAt); err != nil {
+			return nil, err
+		}
+		token.Balance, err = nullStringToBig(balance)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, token)
+	}
+	return rst, rows.Err()
+}
+
+// DeleteOwnedToken removes a cached token balance of the address.
+func (db *Database) DeleteOwnedToken(address, token common.Address) error {
+	_, err := db.db.Exec("DELETE FROM owned_tokens WHERE network_id = ? AND address = ? AND token = ?", db.network, address, token)
+	return err
+}
+
+// SaveAllowances inserts or replaces allowances of the owner set by approvals and
+// stores the block up to which approvals of the owner were scanned.
+func (db *Database) SaveAllowances(owner common.Address, allowances []Allowance, scanned *big.Int) (err error) {
+	var (
+		tx *sql.Tx
+	)
+	tx, err = db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+	insert, err := tx.Prepare("INSERT OR REPLACE INTO allowances (network_id, owner, token, spender, value, blk_number) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return
+	}
+	for _, allowance := range allowances {
+		_, err = insert.Exec(db.network, owner, allowance.Token, allowance.Spender, bigToNullString(allowance.Value), (*SQLBigInt)(allowance.BlockNumber.ToInt()))
+		if err != nil {
+			return
+		}
+	}
+	_, err = tx.Exec("INSERT OR REPLACE INTO allowances_scanned (network_id, owner, blk_number) VALUES (?, ?, ?)", db.network, owner, (*SQLBigInt)(scanned))
+	return
+}
+
+// GetAllowances returns non-zero allowances of the owner and the last block scanned for approvals.
+// The block is nil if approvals of the owner were never scanned.
+func (db *Database) GetAllowances(owner common.Address) (rst []Allowance, scanned *big.Int, err error) {
+	var block sql.NullInt64
+	err = db.db.QueryRow("SELECT blk_number FROM allowances_scanned WHERE network_id = ? AND owner = ?", db.network, owner).Scan(&block)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, err
+	}
+	if block.Valid {
+		scanned = big.NewInt(block.Int64)
+	}
+
+	rows, err := db.db.Query("SELECT token, spender, value, blk_number FROM allowances WHERE network_id = ? AND owner = ? AND value != '0' ORDER BY token, spender", db.network, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			allowance Allowance
+			value     sql.NullString
+			number    = new(big.Int)
+		)
+		if err := rows.Scan(&allowance.Token, &allowance.Spender, &value, (*SQLBigInt)(number)); err != nil {
+			return nil, nil, err
+		}
+		allowance.Value, err = nullStringToBig(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		allowance.BlockNumber = (*hexutil.Big)(number)
+		rst = append(rst, allowance)
+	}
+	return rst, scanned, rows.Err()
+}
+
+// DeleteAllowance removes an allowance that was spent or revoked.
+func (db *Database) DeleteAllowance(owner, token, spender common.Address) error {
+	_, err := db.db.Exec("DELETE FROM allowances WHERE network_id = ? AND owner = ? AND token = ? AND spender = ?", db.network, owner, token, spender)
+	return err
+}
+
+// SaveCryptoOnRamps replaces cached on-ramp providers, keeping their order.
+func (db *Database) SaveCryptoOnRamps(providers []CryptoOnRamp, fetchedAt int64) (err error) {
+	var (
+		tx *sql.Tx
+	)
+	tx, err = db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+	if _, err = tx.Exec("DELETE FROM crypto_on_ramps"); err != nil {
+		return
+	}
+	insert, err := tx.Prepare("INSERT OR REPLACE INTO crypto_on_ramps (name, position, descriptor, fetched_at) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return
+	}
+	defer insert.Close()
+	for i := range providers {
+		if _, err = insert.Exec(providers[i].Name, i, &JSONBlob{&providers[i]}, fetchedAt); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// GetCryptoOnRamps returns cached on-ramp providers and the unix timestamp of the fetch.
+// The timestamp is zero if nothing is cached.
+func (db *Database) GetCryptoOnRamps() ([]CryptoOnRamp, int64, error) {
+	rows, err := db.db.Query("SELECT descriptor, fetched_at FROM crypto_on_ramps ORDER BY position")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		rst       []CryptoOnRamp
+		fetchedAt int64
+	)
+	for rows.Next() {
+		var provider CryptoOnRamp
+		if err := rows.Scan(&JSONBlob{&provider}, &fetchedAt); err != nil {
+			return nil, 0, err
+		}
+		rst = append(rst, provider)
+	}
+	return rst, fetchedAt, rows.Err()
+}
+
+// SaveNFTMetadata stores metadata of a collectible and the status of its fetch.
+func (db *Database) SaveNFTMetadata(metadata NFTMetadata) error {
+	_, err := db.db.Exec("INSERT OR REPLACE INTO nft_metadata (network_id, contract, token_id, token_uri, status, metadata, error, attempts, fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		db.network, metadata.Contract, metadata.TokenID.ToInt().String(), metadata.TokenURI, metadata.Status, &JSONBlob{&metadata}, metadata.Error, metadata.Attempts, metadata.FetchedAt)
+	return err
+}
+
+// GetNFTMetadata returns stored metadata of a collectible, nil if it was never fetched.
+func (db *Database) GetNFTMetadata(contract common.Address, tokenID *big.Int) (*NFTMetadata, error) {
+	metadata := &NFTMetadata{}
+	err := db.db.QueryRow("SELECT metadata FROM nft_metadata WHERE network_id = ? AND contract = ? AND token_id = ?", db.network, contract, tokenID.String()).Scan(&JSONBlob{metadata})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// GetTransferredTokens returns contracts of stored erc20 transfers of the address.
+func (db *Database) GetTransferredTokens(address common.Address) ([]common.Address, error) {
+	rows, err := db.db.Query("SELECT log FROM transfers WHERE network_id = ? AND address = ? AND type = ?", db.network, address, erc20Transfer)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		rst  []common.Address
+		seen = map[common.Address]bool{}
+	)
+	for rows.Next() {
+		l := &types.Log{}
+		if err := rows.Scan(&JSONBlob{l}); err != nil {
+			return nil, err
+		}
+		if !seen[l.Address] {
+			seen[l.Address] = true
+			rst = append(rst, l.Address)
+		}
+	}
+	return rst, rows.Err()
+}
+
+// bigToNullString stores big integers as decimal strings, they don't fit into sqlite integers.
+func bigToNullString(value *hexutil.Big) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: value.ToInt().String(), Valid: true}
+}
+
+func nullStringToBig(value sql.NullString) (*hexutil.Big, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	rst, ok := new(big.Int).SetString(value.String, 10)
+	if !ok {
+		return nil, errors.New("not a decimal integer")
+	}
+	return (*hexutil.Big)(rst), nil
+}
+
 // statementCreator allows to pass transaction or database to use in consumer.
 type statementCreator interface {
 	Prepare(query string) (*sql.Stmt, error)
@@ -626,8 +1474,8 @@ func insertBlocksWithTransactions(creator statementCreator, account common.Addre
 	}
 
 	insertTx, err := creator.Prepare(`INSERT OR IGNORE 
-	INTO transfers (network_id, address, sender, hash, blk_number, blk_hash, type, timestamp, log, loaded)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0)`)
+	INTO transfers (network_id, address, sender, hash, tx_hash, blk_number, blk_hash, type, timestamp, log, loaded)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0)`)
 	if err != nil {
 		return err
 	}
@@ -650,7 +1498,7 @@ func insertBlocksWithTransactions(creator statementCreator, account common.Addre
 				continue
 			}
 
-			_, err = insertTx.Exec(network, account, account, header.Erc20Transfer.ID, (*SQLBigInt)(header.Number), header.Hash, erc20Transfer, header.Erc20Transfer.Timestamp, &JSONBlob{header.Erc20Transfer.Log})
+			_, err = insertTx.Exec(network, account, account, header.Erc20Transfer.ID, header.Erc20Transfer.Log.TxHash, (*SQLBigInt)(header.Number), header.Hash, erc20Transfer, header.Erc20Transfer.Timestamp, &JSONBlob{header.Erc20Transfer.Log})
 			if err != nil {
 				log.Error("error saving erc20transfer", "err", err)
 				return err
@@ -676,21 +1524,24 @@ func insertRange(creator statementCreator, account common.Address, network uint6
 
 func updateOrInsertTransfers(creator statementCreator, network uint64, transfers []Transfer) error {
 	update, err := creator.Prepare(`UPDATE transfers 
-	SET tx = ?, sender = ?, receipt = ?, timestamp = ?, loaded = 1
+	SET tx = ?, tx_hash = ?, sender = ?, receipt = ?, timestamp = ?, l2_fee = ?, withdrawal = ?, bridge_message_hash = ?, token = ?, amount_in = ?, amount_out = ?, loaded = 1
 	WHERE address =?  AND hash = ?`)
 	if err != nil {
 		return err
 	}
 
 	insert, err := creator.Prepare(`INSERT OR IGNORE INTO transfers
-	(network_id, hash, blk_hash, blk_number, timestamp, address, tx, sender, receipt, log, type, loaded) 
+	(network_id, hash, tx_hash, blk_hash, blk_number, timestamp, address, tx, sender, receipt, log, type, l2_fee, withdrawal, bridge_message_hash, token, amount_in, amount_out, loaded) 
 	VALUES 
-	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`)
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`)
 	if err != nil {
 		return err
 	}
 	for _, t := range transfers {
-		res, err := update.Exec(&JSONBlob{t.Transaction}, t.From, &JSONBlob{t.Receipt}, t.Timestamp, t.Address, t.ID)
+		txHash := t.TransactionHash()
+		messageHash := bridgeMessageHash(t.Receipt)
+		token, in, out := chartValues(&t)
+		res, err := update.Exec(&JSONBlob{t.Transaction}, txHash, t.From, &JSONBlob{t.Receipt}, t.Timestamp, &JSONBlob{t.L2Fee}, &JSONBlob{t.Withdrawal}, messageHash, token, in, out, t.Address, t.ID)
 
 		if err != nil {
 			return err
@@ -703,7 +1554,7 @@ func updateOrInsertTransfers(creator statementCreator, network uint64, transfers
 			continue
 		}
 
-		_, err = insert.Exec(network, t.ID, t.BlockHash, (*SQLBigInt)(t.BlockNumber), t.Timestamp, t.Address, &JSONBlob{t.Transaction}, t.From, &JSONBlob{t.Receipt}, &JSONBlob{t.Log}, t.Type)
+		_, err = insert.Exec(network, t.ID, txHash, t.BlockHash, (*SQLBigInt)(t.BlockNumber), t.Timestamp, t.Address, &JSONBlob{t.Transaction}, t.From, &JSONBlob{t.Receipt}, &JSONBlob{t.Log}, t.Type, &JSONBlob{t.L2Fee}, &JSONBlob{t.Withdrawal}, messageHash, token, in, out)
 		if err != nil {
 			log.Error("can't save transfer", "b-hash", t.BlockHash, "b-n", t.BlockNumber, "a", t.Address, "h", t.ID)
 			return err
@@ -122,11 +122,11 @@ func TestDBReorgTransfers(t *testing.T) {
 	replacedTX := types.NewTransaction(2, common.Address{1}, nil, 10, big.NewInt(10), nil)
 	require.NoError(t, db.ProcessBlocks(original.Address, original.Number, original.Number, []*DBHeader{original}))
 	require.NoError(t, db.ProcessTranfers([]Transfer{
-		{ethTransfer, common.Hash{1}, *originalTX.To(), original.Number, original.Hash, 100, originalTX, true, common.Address{1}, rcpt, nil},
+		{ethTransfer, common.Hash{1}, *originalTX.To(), original.Number, original.Hash, 100, originalTX, true, common.Address{1}, rcpt, nil, nil, nil},
 	}, []*DBHeader{}))
 	require.NoError(t, db.ProcessBlocks(replaced.Address, replaced.Number, replaced.Number, []*DBHeader{replaced}))
 	require.NoError(t, db.ProcessTranfers([]Transfer{
-		{ethTransfer, common.Hash{2}, *replacedTX.To(), replaced.Number, replaced.Hash, 100, replacedTX, true, common.Address{1}, rcpt, nil},
+		{ethTransfer, common.Hash{2}, *replacedTX.To(), replaced.Number, replaced.Hash, 100, replacedTX, true, common.Address{1}, rcpt, nil, nil, nil},
 	}, []*DBHeader{original}))
 
 	all, err := db.GetTransfers(big.NewInt(0), nil)
@@ -173,6 +173,51 @@ func TestDBGetTransfersFromBlock(t *testing.T) {
 
 }
 
+func TestDBResetFromBlock(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	address := common.Address{1}
+	headers := []*DBHeader{}
+	transfers := []Transfer{}
+	for i := 1; i < 10; i++ {
+		header := &DBHeader{
+			Number:  big.NewInt(int64(i)),
+			Hash:    common.Hash{byte(i)},
+			Address: address,
+		}
+		headers = append(headers, header)
+		tx := types.NewTransaction(uint64(i), address, nil, 10, big.NewInt(10), nil)
+		receipt := types.NewReceipt(nil, false, 100)
+		receipt.Logs = []*types.Log{}
+		transfers = append(transfers, Transfer{
+			ID:          tx.Hash(),
+			Type:        ethTransfer,
+			BlockNumber: header.Number,
+			BlockHash:   header.Hash,
+			Transaction: tx,
+			Receipt:     receipt,
+			Address:     address,
+		})
+	}
+	require.NoError(t, db.ProcessBlocks(address, big.NewInt(1), big.NewInt(9), headers))
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+
+	require.NoError(t, db.ResetFromBlock(address, big.NewInt(6)))
+
+	rst, err := db.GetTransfers(big.NewInt(0), nil)
+	require.NoError(t, err)
+	require.Len(t, rst, 5)
+	blocks, err := db.GetBlocksByAddress(address, 40)
+	require.NoError(t, err)
+	require.Len(t, blocks, 5)
+	first, err := db.GetFirstKnownBlock(address)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), first)
+	last, err := db.GetLastKnownBlockByAddress(address)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5), last)
+}
+
 func TestCustomTokens(t *testing.T) {
 	db, stop := setupTestDB(t)
 	defer stop()
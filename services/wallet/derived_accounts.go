@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"errors"
+	"sync"
+
+	ethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/account/generator"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/multiaccounts/accounts"
+)
+
+// derivedAccountType is a type of accounts derived from the master key, same as used by clients.
+const derivedAccountType = "generated"
+
+var (
+	// ErrInvalidDerivationPath returned when a path is not a BIP-44 path, m/44'/coin'/account'/change/index.
+	ErrInvalidDerivationPath = errors.New("derivation path must be a BIP-44 path")
+	// ErrDerivedAccountMismatch returned when a stored path derives a different address than stored.
+	ErrDerivedAccountMismatch = errors.New("derived address doesn't match a stored account")
+)
+
+// AccountsGenerator derives accounts from keys stored in the keystore. Implemented by generator.Generator.
+type AccountsGenerator interface {
+	LoadAccount(address string, password string) (generator.IdentifiedAccountInfo, error)
+	DeriveAddresses(accountID string, pathStrings []string) (map[string]generator.AccountInfo, error)
+	StoreDerivedAccounts(accountID string, password string, pathStrings []string) (map[string]generator.AccountInfo, error)
+	RemoveAccount(accountID string)
+}
+
+// DerivedAccount is an account derived from the master key.
+type DerivedAccount struct {
+	Address   common.Address `json:"address"`
+	PublicKey types.HexBytes `json:"publicKey"`
+	Path      string         `json:"path"`
+	// Stored is true if the account is in the accounts list.
+	Stored bool `json:"stored"`
+}
+
+// derivedAccounts derives accounts from the master key and stores them with their paths,
+// so that the same accounts can be derived again when the keystore is restored.
+type derivedAccounts struct {
+	db        *accounts.Database
+	generator AccountsGenerator
+	feed      *event.Feed
+
+	mu sync.Mutex
+}
+
+func newDerivedAccounts(db *accounts.Database, generator AccountsGenerator, feed *event.Feed) *derivedAccounts {
+	return &derivedAccounts{db: db, generator: generator, feed: feed}
+}
+
+// parseBIP44Path returns a derivation path in the canonical form.
+func parseBIP44Path(path string) (ethaccounts.DerivationPath, error) {
+	derivationPath, err := ethaccounts.ParseDerivationPath(path)
+	if err != nil || len(derivationPath) != 5 || derivationPath[0] != 0x80000000+44 {
+		return nil, ErrInvalidDerivationPath
+	}
+	return derivationPath, nil
+}
+
+// withMasterKey loads the master key for the duration of fn.
+func (d *derivedAccounts) withMasterKey(password string, fn func(accountID string) error) error {
+	if d.db == nil || d.generator == nil {
+		return ErrServiceNotInitialized
+	}
+	settings, err := d.db.GetSettings()
+	if err != nil {
+		return err
+	}
+	info, err := d.generator.LoadAccount(settings.Address.Hex(), password)
+	if err != nil {
+		return err
+	}
+	defer d.generator.RemoveAccount(info.ID)
+	return fn(info.ID)
+}
+
+// Derive returns accounts at the paths without storing them.
+func (d *derivedAccounts) Derive(password string, paths []string) ([]DerivedAccount, error) {
+	canonical := make([]string, 0, len(paths))
+	for _, path := range paths {
+		derivationPath, err := parseBIP44Path(path)
+		if err != nil {
+			return nil, err
+		}
+		canonical = append(canonical, derivationPath.String())
+	}
+
+	var infos map[string]generator.AccountInfo
+	err := d.withMasterKey(password, func(accountID string) (err error) {
+		infos, err = d.generator.DeriveAddresses(accountID, canonical)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := d.db.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	exist := make(map[common.Address]bool, len(stored))
+	for _, acc := range stored {
+		exist[common.Address(acc.Address)] = true
+	}
+	rst := make([]DerivedAccount, 0, len(canonical))
+	for _, path := range canonical {
+		derived := toDerivedAccount(path, infos[path])
+		derived.Stored = exist[derived.Address]
+		rst = append(rst, derived)
+	}
+	return rst, nil
+}
+
+// Store stores the key of an account at the path in the keystore and adds the account
+// to the accounts list. New accounts are sent to the accounts feed to download their transfers.
+func (d *derivedAccounts) Store(password, path, name, color string) (accounts.Account, error) {
+	derivationPath, err := parseBIP44Path(path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	path = derivationPath.String()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var infos map[string]generator.AccountInfo
+	err = d.withMasterKey(password, func(accountID string) (err error) {
+		infos, err = d.generator.StoreDerivedAccounts(accountID, password, []string{path})
+		return err
+	})
+	if err != nil {
+		return accounts.Account{}, err
+	}
+
+	derived := toDerivedAccount(path, infos[path])
+	account := accounts.Account{
+		Address:   types.Address(derived.Address),
+		Type:      derivedAccountType,
+		Path:      path,
+		PublicKey: derived.PublicKey,
+		Name:      name,
+		Color:     color,
+	}
+	if err := d.db.SaveAccounts([]accounts.Account{account}); err != nil {
+		return accounts.Account{}, err
+	}
+	if err := d.updateLatestDerivedPath(derivationPath); err != nil {
+		log.Error("failed to update latest derived path", "path", path, "error", err)
+	}
+	if d.feed != nil {
+		d.feed.Send([]accounts.Account{account})
+	}
+	return account, nil
+}
+
+// Restore derives all stored accounts again and stores their keys in the keystore.
+// Accounts that are not derived with BIP-44 paths are skipped.
+func (d *derivedAccounts) Restore(password string) ([]accounts.Account, error) {
+	if d.db == nil {
+		return nil, ErrServiceNotInitialized
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stored, err := d.db.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		restored []accounts.Account
+		paths    []string
+	)
+	for _, acc := range stored {
+		if acc.Type != derivedAccountType {
+			continue
+		}
+		if _, err := parseBIP44Path(acc.Path); err != nil {
+			continue
+		}
+		restored = append(restored, acc)
+		paths = append(paths, acc.Path)
+	}
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	var infos map[string]generator.AccountInfo
+	err = d.withMasterKey(password, func(accountID string) (err error) {
+		infos, err = d.generator.StoreDerivedAccounts(accountID, password, paths)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, acc := range restored {
+		if toDerivedAccount(acc.Path, infos[acc.Path]).Address != common.Address(acc.Address) {
+			log.Error("derived address doesn't match a stored account", "address", acc.Address, "path", acc.Path)
+			return nil, ErrDerivedAccountMismatch
+		}
+	}
+	if d.feed != nil {
+		d.feed.Send(restored)
+	}
+	return restored, nil
+}
+
+// updateLatestDerivedPath moves the index of the latest derived wallet account
+// if the path is under the default wallet root, m/44'/60'/0'/0.
+func (d *derivedAccounts) updateLatestDerivedPath(path ethaccounts.DerivationPath) error {
+	root := ethaccounts.DefaultRootDerivationPath
+	for i := range root {
+		if path[i] != root[i] {
+			return nil
+		}
+	}
+	settings, err := d.db.GetSettings()
+	if err != nil {
+		return err
+	}
+	index := uint(path[len(path)-1])
+	if index <= settings.LatestDerivedPath {
+		return nil
+	}
+	return d.db.SaveSetting("latest-derived-path", index)
+}
+
+func toDerivedAccount(path string, info generator.AccountInfo) DerivedAccount {
+	return DerivedAccount{
+		Address:   common.HexToAddress(info.Address),
+		PublicKey: types.FromHex(info.PublicKey),
+		Path:      path,
+	}
+}
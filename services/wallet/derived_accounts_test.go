@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/status-im/status-go/account/generator"
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/extkeys"
+	"github.com/status-im/status-go/multiaccounts/accounts"
+	"github.com/status-im/status-go/params"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+type testKeystore struct {
+	master   *extkeys.ExtendedKey
+	password string
+	imported map[types.Address]bool
+}
+
+func (k *testKeystore) AddressToDecryptedAccount(address, password string) (types.Account, *types.Key, error) {
+	if password != k.password {
+		return types.Account{}, nil, errors.New("invalid password")
+	}
+	key := &types.Key{PrivateKey: k.master.ToECDSA(), ExtendedKey: k.master}
+	return types.Account{Address: types.Address(crypto.PubkeyToAddress(key.PrivateKey.PublicKey))}, key, nil
+}
+
+func (k *testKeystore) ImportSingleExtendedKey(extKey *extkeys.ExtendedKey, password string) (address, pubKey string, err error) {
+	privateKey := extKey.ToECDSA()
+	k.imported[types.Address(crypto.PubkeyToAddress(privateKey.PublicKey))] = true
+	return crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), types.EncodeHex(crypto.FromECDSAPub(&privateKey.PublicKey)), nil
+}
+
+func (k *testKeystore) ImportAccount(privateKey *ecdsa.PrivateKey, password string) (types.Address, error) {
+	return types.Address(crypto.PubkeyToAddress(privateKey.PublicKey)), nil
+}
+
+func setupDerivedAccounts(t *testing.T, db *Database) (*derivedAccounts, *testKeystore) {
+	master, err := extkeys.NewMaster(extkeys.NewMnemonic().MnemonicSeed(testMnemonic, ""))
+	require.NoError(t, err)
+	keystore := &testKeystore{master: master, password: "password", imported: map[types.Address]bool{}}
+
+	networks := json.RawMessage("{}")
+	accountsDB := accounts.NewDB(db.db)
+	require.NoError(t, accountsDB.CreateSettings(accounts.Settings{
+		Address:  types.Address(crypto.PubkeyToAddress(master.ToECDSA().PublicKey)),
+		Networks: &networks,
+	}, params.NodeConfig{}))
+	return newDerivedAccounts(accountsDB, generator.New(keystore), &event.Feed{}), keystore
+}
+
+func TestDeriveAccounts(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	derived, _ := setupDerivedAccounts(t, db)
+
+	_, err := derived.Derive("password", []string{"m/43'/60'/1581'/0'/0"})
+	require.Equal(t, ErrInvalidDerivationPath, err)
+	_, err = derived.Derive("wrong", []string{"m/44'/60'/0'/0/0"})
+	require.Error(t, err)
+
+	rst, err := derived.Derive("password", []string{"m/44'/60'/0'/0/0", "m/44'/60'/0'/0/1"})
+	require.NoError(t, err)
+	require.Len(t, rst, 2)
+	// well-known address of the test mnemonic
+	require.Equal(t, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"), rst[0].Address)
+	require.Equal(t, "m/44'/60'/0'/0/1", rst[1].Path)
+	require.False(t, rst[0].Stored)
+	require.NotEqual(t, rst[0].Address, rst[1].Address)
+}
+
+func TestStoreAndRestoreDerivedAccounts(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	derived, keystore := setupDerivedAccounts(t, db)
+	feed := make(chan []accounts.Account, 2)
+	sub := derived.feed.Subscribe(feed)
+	defer sub.Unsubscribe()
+
+	account, err := derived.Store("password", "m/44'/60'/0'/0/3", "savings", "#fff")
+	require.NoError(t, err)
+	require.Equal(t, "generated", account.Type)
+	require.Equal(t, "savings", account.Name)
+	require.True(t, keystore.imported[account.Address])
+	require.Equal(t, []accounts.Account{account}, <-feed)
+
+	settings, err := derived.db.GetSettings()
+	require.NoError(t, err)
+	require.Equal(t, uint(3), settings.LatestDerivedPath)
+
+	rst, err := derived.Derive("password", []string{"m/44'/60'/0'/0/3"})
+	require.NoError(t, err)
+	require.True(t, rst[0].Stored)
+
+	// keys are stored again after the keystore was restored
+	keystore.imported = map[types.Address]bool{}
+	restored, err := derived.Restore("password")
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, account.Address, restored[0].Address)
+	require.True(t, keystore.imported[account.Address])
+	require.Len(t, <-feed, 1)
+
+	// a stored address that doesn't match its path is reported
+	account.Address = types.Address{1}
+	require.NoError(t, derived.db.SaveAccounts([]accounts.Account{account}))
+	_, err = derived.Restore("password")
+	require.Equal(t, ErrDerivedAccountMismatch, err)
+}
@@ -21,6 +21,10 @@ type TransferType string
 const (
 	ethTransfer   TransferType = "eth"
 	erc20Transfer TransferType = "erc20"
+	// depositTransfer is an eth transfer that made deposits to the beacon chain deposit contract.
+	depositTransfer TransferType = "deposit"
+	// withdrawalTransfer is a validator withdrawal credited by the consensus layer, it has no transaction.
+	withdrawalTransfer TransferType = "withdrawal"
 
 	erc20TransferEventSignature = "Transfer(address,address,uint256)"
 )
@@ -46,6 +50,21 @@ type Transfer struct {
 	Receipt *types.Receipt `json:"receipt"`
 	// Log that was used to generate erc20 transfer. Nil for eth transfer.
 	Log *types.Log `json:"log"`
+	// L2Fee is set for transfers on layer 2 networks which receipts have layer 1 fee fields.
+	L2Fee *L2Fee `json:"l2Fee,omitempty"`
+	// Withdrawal is set for withdrawal transfers.
+	Withdrawal *Withdrawal `json:"withdrawal,omitempty"`
+}
+
+// TransactionHash returns the hash of the transaction that made the transfer.
+func (t *Transfer) TransactionHash() common.Hash {
+	if t.Transaction != nil {
+		return t.Transaction.Hash()
+	}
+	if t.Log != nil {
+		return t.Log.TxHash
+	}
+	return t.ID
 }
 
 // ETHTransferDownloader downloads regular eth transfers.
@@ -54,6 +73,10 @@ type ETHTransferDownloader struct {
 	accounts []common.Address
 	signer   types.Signer
 	db       *Database
+	// l2 is used to fetch receipts with layer 2 fee fields, set only on layer 2 networks.
+	l2 RPCClient
+	// withdrawals is used to fetch validator withdrawals of blocks, set only on networks that support them.
+	withdrawals RPCClient
 }
 
 var errLogsDownloaderStuck = errors.New("logs downloader stuck")
@@ -113,7 +136,7 @@ func (d *ETHTransferDownloader) getTransfersInBlock(ctx context.Context, blk *ty
 			}
 
 			if from == address || (tx.To() != nil && *tx.To() == address) {
-				receipt, err := d.client.TransactionReceipt(ctx, tx.Hash())
+				receipt, l2Fee, err := receiptReader{client: d.client, l2: d.l2}.TransactionReceipt(ctx, tx.Hash())
 				if err != nil {
 					return nil, err
 				}
@@ -121,8 +144,12 @@ func (d *ETHTransferDownloader) getTransfersInBlock(ctx context.Context, blk *ty
 				transactionLog := getTokenLog(receipt.Logs)
 
 				if transactionLog == nil {
+					transferType := ethTransfer
+					if from == address && isStakingDeposit(receipt) {
+						transferType = depositTransfer
+					}
 					rst = append(rst, Transfer{
-						Type:        ethTransfer,
+						Type:        transferType,
 						ID:          tx.Hash(),
 						Address:     address,
 						BlockNumber: blk.Number(),
@@ -131,11 +158,19 @@ func (d *ETHTransferDownloader) getTransfersInBlock(ctx context.Context, blk *ty
 						Transaction: tx,
 						From:        from,
 						Receipt:     receipt,
-						Log:         transactionLog})
+						Log:         transactionLog,
+						L2Fee:       l2Fee})
 				}
 			}
 		}
 	}
+	if d.withdrawals != nil {
+		withdrawals, err := getWithdrawalTransfers(ctx, d.withdrawals, blk, accounts)
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, withdrawals...)
+	}
 	log.Debug("getTransfersInBlock found", "block", blk.Number(), "len", len(rst))
 	// TODO(dshulyak) test that balance difference was covered by transactions
 	return rst, nil
@@ -162,6 +197,9 @@ type ERC20TransfersDownloader struct {
 
 	// signer is used to derive tx sender from tx signature
 	signer types.Signer
+
+	// l2 is used to fetch receipts with layer 2 fee fields, set only on layer 2 networks.
+	l2 RPCClient
 }
 
 func (d *ERC20TransfersDownloader) paddedAddress(address common.Address) common.Hash {
@@ -190,7 +228,7 @@ func (d *ETHTransferDownloader) transferFromLog(parent context.Context, ethlog t
 		return Transfer{}, err
 	}
 	ctx, cancel = context.WithTimeout(parent, 3*time.Second)
-	receipt, err := d.client.TransactionReceipt(ctx, ethlog.TxHash)
+	receipt, l2Fee, err := receiptReader{client: d.client, l2: d.l2}.TransactionReceipt(ctx, ethlog.TxHash)
 	cancel()
 	if err != nil {
 		return Transfer{}, err
@@ -212,6 +250,7 @@ func (d *ETHTransferDownloader) transferFromLog(parent context.Context, ethlog t
 		Receipt:     receipt,
 		Timestamp:   blk.Time(),
 		Log:         &ethlog,
+		L2Fee:       l2Fee,
 	}, nil
 }
 
@@ -227,7 +266,7 @@ func (d *ERC20TransfersDownloader) transferFromLog(parent context.Context, ethlo
 		return Transfer{}, err
 	}
 	ctx, cancel = context.WithTimeout(parent, 3*time.Second)
-	receipt, err := d.client.TransactionReceipt(ctx, ethlog.TxHash)
+	receipt, l2Fee, err := receiptReader{client: d.client, l2: d.l2}.TransactionReceipt(ctx, ethlog.TxHash)
 	cancel()
 	if err != nil {
 		return Transfer{}, err
@@ -252,6 +291,7 @@ func (d *ERC20TransfersDownloader) transferFromLog(parent context.Context, ethlo
 		Receipt:     receipt,
 		Timestamp:   blk.Time(),
 		Log:         &ethlog,
+		L2Fee:       l2Fee,
 	}, nil
 }
 
@@ -5,7 +5,6 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -16,7 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/status-im/status-go/services/wallet/erc20"
-	"github.com/status-im/status-go/t/devtests/miner"
+	"github.com/status-im/status-go/t/devtests/testchain"
 )
 
 func TestETHTransfers(t *testing.T) {
@@ -26,6 +25,7 @@ func TestETHTransfers(t *testing.T) {
 type ETHTransferSuite struct {
 	suite.Suite
 
+	backend             *testchain.SimulatedBackend
 	ethclient           *ethclient.Client
 	identity, secondary *ecdsa.PrivateKey
 	faucet              *ecdsa.PrivateKey
@@ -39,19 +39,14 @@ func (s *ETHTransferSuite) SetupTest() {
 	var err error
 	s.identity, err = crypto.GenerateKey()
 	s.Require().NoError(err)
-	s.faucet, err = crypto.GenerateKey()
-	s.Require().NoError(err)
 	s.secondary, err = crypto.GenerateKey()
 	s.Require().NoError(err)
 
-	node, err := miner.NewDevNode(crypto.PubkeyToAddress(s.faucet.PublicKey))
-	s.Require().NoError(err)
-	s.Require().NoError(miner.StartWithMiner(node))
-
-	client, err := node.Attach()
+	s.backend, err = testchain.NewSimulatedBackend()
 	s.Require().NoError(err)
-	s.ethclient = ethclient.NewClient(client)
-	s.signer = types.NewEIP155Signer(big.NewInt(1337))
+	s.ethclient = s.backend.Client
+	s.faucet = s.backend.Faucet
+	s.signer = s.backend.Signer
 	db, stop := setupTestDB(s.Suite.T())
 	s.dbStop = stop
 	s.downloader = &ETHTransferDownloader{
@@ -66,6 +61,7 @@ func (s *ETHTransferSuite) SetupTest() {
 
 func (s *ETHTransferSuite) TearDownTest() {
 	s.dbStop()
+	s.Require().NoError(s.backend.Stop())
 }
 
 // signAndMineTx signs transaction with provided key and waits for it to be mined.
@@ -77,10 +73,7 @@ func (s *ETHTransferSuite) signAndMineTx(tx *types.Transaction, pkey *ecdsa.Priv
 	tx, err := types.SignTx(tx, s.signer, pkey)
 	s.Require().NoError(err)
 	s.Require().NoError(s.ethclient.SendTransaction(context.Background(), tx))
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	s.Require().NoError(err)
+	s.backend.Commit()
 }
 
 func (s *ETHTransferSuite) TestNoBalance() {
@@ -98,13 +91,7 @@ func (s *ETHTransferSuite) TestNoBalance() {
 func (s *ETHTransferSuite) TestBalanceUpdatedOnInbound() {
 	ctx := context.TODO()
 	tx := types.NewTransaction(0, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(1e18), 1e6, big.NewInt(10), nil)
-	tx, err := types.SignTx(tx, s.signer, s.faucet)
-	s.Require().NoError(err)
-	s.Require().NoError(s.ethclient.SendTransaction(ctx, tx))
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	s.Require().NoError(err)
+	s.signAndMineTx(tx, nil)
 
 	header, err := s.ethclient.HeaderByNumber(ctx, nil)
 	s.Require().NoError(err)
@@ -117,22 +104,9 @@ func (s *ETHTransferSuite) TestBalanceUpdatedOnInbound() {
 func (s *ETHTransferSuite) TestBalanceUpdatedOnOutbound() {
 	ctx := context.TODO()
 	tx := types.NewTransaction(0, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(1e18), 1e6, big.NewInt(10), nil)
-	tx, err := types.SignTx(tx, s.signer, s.faucet)
-	s.Require().NoError(err)
-	s.Require().NoError(s.ethclient.SendTransaction(ctx, tx))
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	cancel()
-	s.Require().NoError(err)
-
+	s.signAndMineTx(tx, nil)
 	tx = types.NewTransaction(0, common.Address{1}, big.NewInt(5e17), 1e6, big.NewInt(10), nil)
-	tx, err = types.SignTx(tx, s.signer, s.identity)
-	s.Require().NoError(err)
-	s.Require().NoError(s.ethclient.SendTransaction(ctx, tx))
-	timeout, cancel = context.WithTimeout(context.Background(), 5*time.Second)
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	cancel()
-	s.Require().NoError(err)
+	s.signAndMineTx(tx, s.identity)
 
 	header, err := s.ethclient.HeaderByNumber(ctx, nil)
 	s.Require().NoError(err)
@@ -156,6 +130,30 @@ func (s *ETHTransferSuite) TestMultipleReferences() {
 	s.Require().Len(transfers, 2)
 }
 
+func (s *ETHTransferSuite) TestReorg() {
+	ctx := context.Background()
+	tx := types.NewTransaction(0, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(1e18), 1e6, big.NewInt(10), nil)
+	s.signAndMineTx(tx, nil)
+	replaced, err := s.ethclient.HeaderByNumber(ctx, big.NewInt(1))
+	s.Require().NoError(err)
+	transfers, err := s.downloader.GetTransfers(ctx, toDBHeader(replaced))
+	s.Require().NoError(err)
+	s.Require().Len(transfers, 1)
+
+	// the transfer is dropped with the block that included it
+	s.Require().NoError(s.backend.Reorg(1, nil))
+	header, err := s.ethclient.HeaderByNumber(ctx, big.NewInt(1))
+	s.Require().NoError(err)
+	s.Require().NotEqual(replaced.Hash(), header.Hash())
+	transfers, err = s.downloader.GetTransfers(ctx, toDBHeader(header))
+	s.Require().NoError(err)
+	s.Require().Empty(transfers)
+
+	header, err = s.ethclient.HeaderByNumber(ctx, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(big.NewInt(2), header.Number)
+}
+
 func TestERC20Transfers(t *testing.T) {
 	suite.Run(t, new(ERC20TransferSuite))
 }
@@ -163,6 +161,7 @@ func TestERC20Transfers(t *testing.T) {
 type ERC20TransferSuite struct {
 	suite.Suite
 
+	backend   *testchain.SimulatedBackend
 	ethclient *ethclient.Client
 	identity  *ecdsa.PrivateKey
 	faucet    *ecdsa.PrivateKey
@@ -175,38 +174,22 @@ type ERC20TransferSuite struct {
 
 func (s *ERC20TransferSuite) SetupTest() {
 	var err error
-	s.signer = types.NewEIP155Signer(big.NewInt(1337))
 	s.identity, err = crypto.GenerateKey()
 	s.Require().NoError(err)
-	s.faucet, err = crypto.GenerateKey()
-	s.Require().NoError(err)
-
-	node, err := miner.NewDevNode(crypto.PubkeyToAddress(s.faucet.PublicKey))
-	s.Require().NoError(err)
-	s.Require().NoError(miner.StartWithMiner(node))
 
-	client, err := node.Attach()
+	s.backend, err = testchain.NewSimulatedBackend()
 	s.Require().NoError(err)
-	s.ethclient = ethclient.NewClient(client)
+	s.ethclient = s.backend.Client
+	s.faucet = s.backend.Faucet
+	s.signer = s.backend.Signer
 	s.downloader = NewERC20TransfersDownloader(s.ethclient, []common.Address{crypto.PubkeyToAddress(s.identity.PublicKey)}, s.signer)
 
-	var (
-		tx       *types.Transaction
-		contract *erc20.ERC20Transfer
-	)
-	for i := 0; i <= 3; i++ {
-		opts := bind.NewKeyedTransactor(s.faucet)
-		_, tx, contract, err = erc20.DeployERC20Transfer(opts, s.ethclient)
-		if err != nil {
-			continue
-		}
-	}
-	s.Require().NoError(err)
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
+	_, s.contract, err = s.backend.DeployERC20(s.faucet)
 	s.Require().NoError(err)
-	s.contract = contract
+}
+
+func (s *ERC20TransferSuite) TearDownTest() {
+	s.Require().NoError(s.backend.Stop())
 }
 
 func (s *ERC20TransferSuite) TestNoEvents() {
@@ -220,12 +203,9 @@ func (s *ERC20TransferSuite) TestNoEvents() {
 
 func (s *ERC20TransferSuite) TestInboundEvent() {
 	opts := bind.NewKeyedTransactor(s.faucet)
-	tx, err := s.contract.Transfer(opts, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(100))
-	s.Require().NoError(err)
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
+	_, err := s.contract.Transfer(opts, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(100))
 	s.Require().NoError(err)
+	s.backend.Commit()
 
 	header, err := s.ethclient.HeaderByNumber(context.TODO(), nil)
 	s.Require().NoError(err)
@@ -237,23 +217,13 @@ func (s *ERC20TransferSuite) TestInboundEvent() {
 
 func (s *ERC20TransferSuite) TestOutboundEvent() {
 	// give some eth to pay for gas
-	ctx := context.TODO()
-	tx := types.NewTransaction(4, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(1e18), 1e6, big.NewInt(10), nil)
-	tx, err := types.SignTx(tx, s.signer, s.faucet)
-	s.Require().NoError(err)
-	s.Require().NoError(s.ethclient.SendTransaction(ctx, tx))
-	timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	cancel()
+	_, err := s.backend.SendETH(s.faucet, crypto.PubkeyToAddress(s.identity.PublicKey), big.NewInt(1e18))
 	s.Require().NoError(err)
 
 	opts := bind.NewKeyedTransactor(s.identity)
-	tx, err = s.contract.Transfer(opts, common.Address{1}, big.NewInt(100))
-	s.Require().NoError(err)
-	timeout, cancel = context.WithTimeout(context.Background(), 5*time.Second)
-	_, err = bind.WaitMined(timeout, s.ethclient, tx)
-	cancel()
+	_, err = s.contract.Transfer(opts, common.Address{1}, big.NewInt(100))
 	s.Require().NoError(err)
+	s.backend.Commit()
 
 	header, err := s.ethclient.HeaderByNumber(context.TODO(), nil)
 	s.Require().NoError(err)
@@ -265,13 +235,10 @@ func (s *ERC20TransferSuite) TestOutboundEvent() {
 
 func (s *ERC20TransferSuite) TestInRange() {
 	for i := 0; i < 5; i++ {
-		tx, err := s.contract.Transfer(bind.NewKeyedTransactor(s.faucet), crypto.PubkeyToAddress(s.identity.PublicKey),
+		_, err := s.contract.Transfer(bind.NewKeyedTransactor(s.faucet), crypto.PubkeyToAddress(s.identity.PublicKey),
 			big.NewInt(100))
 		s.Require().NoError(err)
-		timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_, err = bind.WaitMined(timeout, s.ethclient, tx)
-		s.Require().NoError(err)
+		s.backend.Commit()
 	}
 	transfers, err := s.downloader.GetHeadersInRange(context.TODO(), big.NewInt(1), nil)
 	s.Require().NoError(err)
@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+// revertSelector is a selector of the Error(string) that is used by solidity to encode revert reasons.
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// simulationBalance is a balance of the sender during simulation, so that the result
+// doesn't depend on whether the user has enough funds.
+var simulationBalance = new(big.Int).Lsh(big.NewInt(1), 128)
+
+var errInvalidRevertData = errors.New("invalid revert data")
+
+// RPCClient performs raw json-rpc calls.
+type RPCClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// CallArgs are arguments of a transaction that will be simulated.
+type CallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas,omitempty"`
+	GasPrice *hexutil.Big    `json:"gasPrice,omitempty"`
+	Value    *hexutil.Big    `json:"value,omitempty"`
+	Data     hexutil.Bytes   `json:"data,omitempty"`
+}
+
+type accountOverride struct {
+	Balance *hexutil.Big `json:"balance"`
+}
+
+// TransactionEstimate is a result of a transaction simulation.
+type TransactionEstimate struct {
+	// Gas is an estimated gas usage. Not set if transaction reverts.
+	Gas hexutil.Uint64 `json:"gas"`
+	// Reverted is true if the transaction will be reverted.
+	Reverted bool `json:"reverted"`
+	// RevertReason is a decoded revert string.
+	RevertReason string `json:"revertReason,omitempty"`
+	// Method is a name of the called contract method if ABI of the contract is known.
+	Method string `json:"method,omitempty"`
+}
+
+// abiRegistry keeps ABIs of the known contracts.
+type abiRegistry struct {
+	mu   sync.RWMutex
+	abis map[common.Address]*abi.ABI
+}
+
+func newABIRegistry() *abiRegistry {
+	return &abiRegistry{abis: map[common.Address]*abi.ABI{}}
+}
+
+func (r *abiRegistry) Register(address common.Address, contract *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.abis[address] = contract
+}
+
+func (r *abiRegistry) Lookup(address common.Address) (*abi.ABI, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	contract, exist := r.abis[address]
+	return contract, exist
+}
+
+// RegisterTokens adds erc20 ABI for every token.
+func (r *abiRegistry) RegisterTokens(tokens []*Token) error {
+	contract, err := abi.JSON(strings.NewReader(ierc20.IERC20ABI))
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		r.Register(token.Address, &contract)
+	}
+	return nil
+}
+
+// RegisterContracts adds ABIs stored in the database.
+func (r *abiRegistry) RegisterContracts(contracts map[common.Address]string) error {
+	for address, definition := range contracts {
+		contract, err := abi.JSON(strings.NewReader(definition))
+		if err != nil {
+			return err
+		}
+		r.Register(address, &contract)
+	}
+	return nil
+}
+
+// EstimateTransaction simulates transaction with eth_call and increased balance of the sender
+// and estimates gas if the transaction is not reverted.
+func EstimateTransaction(ctx context.Context, client RPCClient, registry *abiRegistry, args CallArgs) (*TransactionEstimate, error) {
+	rst := &TransactionEstimate{}
+	var contract *abi.ABI
+	if args.To != nil {
+		contract, _ = registry.Lookup(*args.To)
+	}
+	if contract != nil && len(args.Data) >= 4 {
+		method, err := contract.MethodById(args.Data[:4])
+		if err == nil {
+			rst.Method = method.Name
+		}
+	}
+
+	overrides := map[common.Address]accountOverride{
+		args.From: {Balance: (*hexutil.Big)(simulationBalance)},
+	}
+	var result hexutil.Bytes
+	err := client.CallContext(ctx, &result, "eth_call", args, "latest", overrides)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(result, revertSelector) {
+		rst.Reverted = true
+		rst.RevertReason, err = decodeRevertReason(result)
+		if err != nil {
+			log.Warn("failed to decode revert reason", "to", args.To, "error", err)
+		}
+		return rst, nil
+	}
+
+	err = client.CallContext(ctx, &rst.Gas, "eth_estimateGas", args)
+	if err != nil {
+		return nil, err
+	}
+	return rst, nil
+}
+
+// decodeRevertReason decodes data encoded as Error(string).
+func decodeRevertReason(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, revertSelector) {
+		return "", errInvalidRevertData
+	}
+	typ, err := abi.NewType("string", nil)
+	if err != nil {
+		return "", err
+	}
+	var reason string
+	err = abi.Arguments{{Type: typ}}.Unpack(&reason, data[len(revertSelector):])
+	if err != nil {
+		return "", err
+	}
+	return reason, nil
+}
@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type fakeRPCClient struct {
+	results map[string]interface{}
+	calls   map[string][]interface{}
+}
+
+func (c *fakeRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if c.calls == nil {
+		c.calls = map[string][]interface{}{}
+	}
+	c.calls[method] = args
+	rst, exist := c.results[method]
+	if !exist {
+		return errors.New("method not found")
+	}
+	data, err := json.Marshal(rst)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func encodeRevert(t *testing.T, reason string) hexutil.Bytes {
+	typ, err := abi.NewType("string", nil)
+	require.NoError(t, err)
+	data, err := abi.Arguments{{Type: typ}}.Pack(reason)
+	require.NoError(t, err)
+	return append(append([]byte{}, revertSelector...), data...)
+}
+
+func TestEstimateTransactionReverted(t *testing.T) {
+	token := common.Address{2}
+	client := &fakeRPCClient{results: map[string]interface{}{
+		"eth_call": encodeRevert(t, "transfer amount exceeds balance"),
+	}}
+	registry := newABIRegistry()
+	require.NoError(t, registry.RegisterTokens([]*Token{{Address: token}}))
+
+	erc20, _ := registry.Lookup(token)
+	data, err := erc20.Pack("transfer", common.Address{3}, big.NewInt(10))
+	require.NoError(t, err)
+
+	from := common.Address{1}
+	rst, err := EstimateTransaction(context.Background(), client, registry, CallArgs{From: from, To: &token, Data: data})
+	require.NoError(t, err)
+	require.True(t, rst.Reverted)
+	require.Equal(t, "transfer amount exceeds balance", rst.RevertReason)
+	require.Equal(t, "transfer", rst.Method)
+	require.Zero(t, rst.Gas)
+
+	overrides := client.calls["eth_call"][2].(map[common.Address]accountOverride)
+	require.Equal(t, simulationBalance, overrides[from].Balance.ToInt())
+	require.NotContains(t, client.calls, "eth_estimateGas")
+}
+
+func TestEstimateTransactionGas(t *testing.T) {
+	client := &fakeRPCClient{results: map[string]interface{}{
+		"eth_call":        hexutil.Bytes{},
+		"eth_estimateGas": hexutil.Uint64(21000),
+	}}
+	to := common.Address{2}
+	rst, err := EstimateTransaction(context.Background(), client, newABIRegistry(), CallArgs{From: common.Address{1}, To: &to})
+	require.NoError(t, err)
+	require.False(t, rst.Reverted)
+	require.Empty(t, rst.Method)
+	require.Equal(t, hexutil.Uint64(21000), rst.Gas)
+}
+
+func TestDecodeRevertReasonInvalid(t *testing.T) {
+	_, err := decodeRevertReason([]byte{1, 2, 3, 4})
+	require.Equal(t, errInvalidRevertData, err)
+	_, err = decodeRevertReason(append(append([]byte{}, revertSelector...), []byte(strings.Repeat("f", 10))...))
+	require.Error(t, err)
+}
@@ -20,6 +20,10 @@ const (
 	EventFetchingRecentHistory EventType = "recent-history-fetching"
 	// EventRecentHistoryFetched emitted when fetching of lastest tx history is started
 	EventRecentHistoryReady EventType = "recent-history-ready"
+	// EventHardwareWalletConfirmationRequired emitted when a transaction has to be confirmed on a hardware wallet.
+	EventHardwareWalletConfirmationRequired EventType = "hardware-wallet-confirmation-required"
+	// EventHardwareWalletConfirmationTimeout emitted when a transaction wasn't confirmed on a hardware wallet in time.
+	EventHardwareWalletConfirmationTimeout EventType = "hardware-wallet-confirmation-timeout"
 )
 
 // Event is a type for wallet events.
@@ -0,0 +1,268 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxFeeHistoryBlocks is a maximum number of blocks in a chart, blocks are cached up to this depth.
+	maxFeeHistoryBlocks = 1024
+	// maxFeeHistoryPercentiles is a maximum number of requested percentiles.
+	maxFeeHistoryPercentiles = 10
+	// feeHistoryConcurrency is a maximum number of blocks requested concurrently.
+	feeHistoryConcurrency = 8
+)
+
+var (
+	// ErrInvalidFeeHistoryBlocks is returned if the number of blocks is not between 1 and 1024.
+	ErrInvalidFeeHistoryBlocks = errors.New("number of blocks must be between 1 and 1024")
+	// ErrInvalidFeeHistoryBuckets is returned if the number of buckets is not positive.
+	ErrInvalidFeeHistoryBuckets = errors.New("number of buckets must be positive")
+	// ErrInvalidFeeHistoryPercentiles is returned if there are more than 10 percentiles
+	// or they are not ascending values between 0 and 100.
+	ErrInvalidFeeHistoryPercentiles = errors.New("at most 10 percentiles between 0 and 100 in ascending order are allowed")
+)
+
+// FeeHistoryClient reads blocks for fee statistics.
+type FeeHistoryClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// FeeHistoryBucket is a summary of gas prices paid in consecutive blocks.
+type FeeHistoryBucket struct {
+	FirstBlock   *hexutil.Big `json:"firstBlock"`
+	LastBlock    *hexutil.Big `json:"lastBlock"`
+	Transactions int          `json:"transactions"`
+	// MinGasPrice, MedianGasPrice and MaxGasPrice are not set if there are no transactions in the bucket.
+	MinGasPrice    *hexutil.Big `json:"minGasPrice"`
+	MedianGasPrice *hexutil.Big `json:"medianGasPrice"`
+	MaxGasPrice    *hexutil.Big `json:"maxGasPrice"`
+	// Percentiles are gas prices at the requested percentiles, empty if there are no transactions.
+	Percentiles []*hexutil.Big `json:"percentiles"`
+	// GasUsedRatio is the gas used by blocks of the bucket divided by their gas limit.
+	GasUsedRatio float64 `json:"gasUsedRatio"`
+}
+
+// FeeHistoryChart is a list of buckets of the latest blocks, the oldest first.
+type FeeHistoryChart struct {
+	LatestBlock *hexutil.Big       `json:"latestBlock"`
+	Percentiles []float64          `json:"percentiles"`
+	Buckets     []FeeHistoryBucket `json:"buckets"`
+}
+
+// blockFees are sorted gas prices of transactions in a block.
+type blockFees struct {
+	hash      common.Hash
+	parent    common.Hash
+	gasPrices []*big.Int
+	gasUsed   uint64
+	gasLimit  uint64
+}
+
+func newBlockFees(block *types.Block) *blockFees {
+	fees := &blockFees{
+		hash:     block.Hash(),
+		parent:   block.ParentHash(),
+		gasUsed:  block.GasUsed(),
+		gasLimit: block.GasLimit(),
+	}
+	for _, tx := range block.Transactions() {
+		fees.gasPrices = append(fees.gasPrices, tx.GasPrice())
+	}
+	sortBigInts(fees.gasPrices)
+	return fees
+}
+
+// feeHistory caches gas prices of the latest blocks, so that only new blocks are requested
+// when the chart is refreshed. Cached blocks are verified by hashes of their children.
+type feeHistory struct {
+	mu     sync.Mutex
+	blocks map[uint64]*blockFees
+}
+
+func newFeeHistory() *feeHistory {
+	return &feeHistory{blocks: map[uint64]*blockFees{}}
+}
+
+// Chart returns gas price statistics of the latest blocks split into buckets. Chains without a base fee
+// don't distinguish the priority fee, so percentiles are computed from gas prices of transactions.
+func (h *feeHistory) Chart(ctx context.Context, client FeeHistoryClient, blocks, buckets int, percentiles []float64) (*FeeHistoryChart, error) {
+	if blocks <= 0 || blocks > maxFeeHistoryBlocks {
+		return nil, ErrInvalidFeeHistoryBlocks
+	}
+	if buckets <= 0 {
+		return nil, ErrInvalidFeeHistoryBuckets
+	}
+	if err := validatePercentiles(percentiles); err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	latest := header.Number.Uint64()
+	if latest+1 < uint64(blocks) {
+		blocks = int(latest + 1)
+	}
+	first := latest + 1 - uint64(blocks)
+
+	fetched, err := h.fetchMissing(ctx, client, first, latest, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	fees := make([]*blockFees, blocks)
+	for n := latest + 1; n > first; n-- {
+		number := n - 1
+		block, exist := fetched[number]
+		if !exist {
+			block = h.blocks[number]
+		}
+		// a cached block is replaced if it doesn't match the parent hash of the next block
+		if number != latest && block.hash != fees[number+1-first].parent {
+			rst, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil {
+				return nil, err
+			}
+			block = newBlockFees(rst)
+		}
+		fees[number-first] = block
+	}
+
+	for number := range h.blocks {
+		if number+maxFeeHistoryBlocks <= latest || number > latest {
+			delete(h.blocks, number)
+		}
+	}
+	for i, block := range fees {
+		h.blocks[first+uint64(i)] = block
+	}
+
+	return &FeeHistoryChart{
+		LatestBlock: (*hexutil.Big)(new(big.Int).SetUint64(latest)),
+		Percentiles: percentiles,
+		Buckets:     feeHistoryBuckets(fees, first, buckets, percentiles),
+	}, nil
+}
+
+// fetchMissing requests blocks that are not cached and the latest block unless its hash matches.
+func (h *feeHistory) fetchMissing(ctx context.Context, client FeeHistoryClient, first, latest uint64, latestHash common.Hash) (map[uint64]*blockFees, error) {
+	var numbers []uint64
+	for n := first; n <= latest; n++ {
+		block, exist := h.blocks[n]
+		if !exist || (n == latest && block.hash != latestHash) {
+			numbers = append(numbers, n)
+		}
+	}
+	var (
+		group   = NewAtomicGroup(ctx)
+		limit   = make(chan struct{}, feeHistoryConcurrency)
+		mu      sync.Mutex
+		fetched = make(map[uint64]*blockFees, len(numbers))
+	)
+	for _, n := range numbers {
+		n := n
+		group.Add(func(ctx context.Context) error {
+			select {
+			case limit <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-limit }()
+			block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			fetched[n] = newBlockFees(block)
+			mu.Unlock()
+			return nil
+		})
+	}
+	select {
+	case <-group.WaitAsync():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return fetched, group.Error()
+}
+
+// feeHistoryBuckets splits blocks into buckets of equal size, older buckets are shorter by one block if
+// the number of blocks is not divisible by the number of buckets.
+func feeHistoryBuckets(fees []*blockFees, first uint64, buckets int, percentiles []float64) []FeeHistoryBucket {
+	if buckets > len(fees) {
+		buckets = len(fees)
+	}
+	rst := make([]FeeHistoryBucket, buckets)
+	end := len(fees)
+	for i := buckets - 1; i >= 0; i-- {
+		start := len(fees) * i / buckets
+		var (
+			prices            []*big.Int
+			gasUsed, gasLimit uint64
+		)
+		for _, block := range fees[start:end] {
+			prices = append(prices, block.gasPrices...)
+			gasUsed += block.gasUsed
+			gasLimit += block.gasLimit
+		}
+		sortBigInts(prices)
+		bucket := FeeHistoryBucket{
+			FirstBlock:   (*hexutil.Big)(new(big.Int).SetUint64(first + uint64(start))),
+			LastBlock:    (*hexutil.Big)(new(big.Int).SetUint64(first + uint64(end) - 1)),
+			Transactions: len(prices),
+			Percentiles:  []*hexutil.Big{},
+		}
+		if gasLimit > 0 {
+			bucket.GasUsedRatio = float64(gasUsed) / float64(gasLimit)
+		}
+		if len(prices) > 0 {
+			bucket.MinGasPrice = (*hexutil.Big)(prices[0])
+			bucket.MedianGasPrice = (*hexutil.Big)(percentile(prices, 50))
+			bucket.MaxGasPrice = (*hexutil.Big)(prices[len(prices)-1])
+			for _, p := range percentiles {
+				bucket.Percentiles = append(bucket.Percentiles, (*hexutil.Big)(percentile(prices, p)))
+			}
+		}
+		rst[i] = bucket
+		end = start
+	}
+	return rst
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []*big.Int, p float64) *big.Int {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func validatePercentiles(percentiles []float64) error {
+	if len(percentiles) > maxFeeHistoryPercentiles {
+		return ErrInvalidFeeHistoryPercentiles
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 || (i > 0 && p < percentiles[i-1]) {
+			return ErrInvalidFeeHistoryPercentiles
+		}
+	}
+	return nil
+}
+
+func sortBigInts(values []*big.Int) {
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+}
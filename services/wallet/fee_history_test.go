@@ -0,0 +1,116 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type feeHistoryChain struct {
+	mu       sync.Mutex
+	blocks   []*types.Block
+	requests int
+}
+
+func (c *feeHistoryChain) extend(gasPrices ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := &types.Header{Number: big.NewInt(int64(len(c.blocks))), GasLimit: 100, GasUsed: uint64(len(gasPrices) * 10)}
+	if len(c.blocks) > 0 {
+		header.ParentHash = c.blocks[len(c.blocks)-1].Hash()
+	}
+	var txs []*types.Transaction
+	for i, price := range gasPrices {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{1}, big.NewInt(1), 21000, big.NewInt(price), nil))
+	}
+	c.blocks = append(c.blocks, types.NewBlock(header, txs, nil, nil))
+}
+
+func (c *feeHistoryChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocks[len(c.blocks)-1].Header(), nil
+}
+
+func (c *feeHistoryChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	return c.blocks[number.Int64()], nil
+}
+
+func TestFeeHistoryChart(t *testing.T) {
+	chain := &feeHistoryChain{}
+	chain.extend(100)
+	chain.extend(1, 2, 3, 4)
+	chain.extend()
+	chain.extend(10, 20)
+	chain.extend(5)
+	history := newFeeHistory()
+
+	chart, err := history.Chart(context.Background(), chain, 4, 2, []float64{25, 75})
+	require.NoError(t, err)
+	require.Equal(t, 4, chain.requests)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(4)), chart.LatestBlock)
+	require.Len(t, chart.Buckets, 2)
+
+	older := chart.Buckets[0]
+	require.Equal(t, (*hexutil.Big)(big.NewInt(1)), older.FirstBlock)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(2)), older.LastBlock)
+	require.Equal(t, 4, older.Transactions)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(1)), older.MinGasPrice)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(2)), older.MedianGasPrice)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(4)), older.MaxGasPrice)
+	require.Equal(t, []*hexutil.Big{(*hexutil.Big)(big.NewInt(1)), (*hexutil.Big)(big.NewInt(3))}, older.Percentiles)
+	require.Equal(t, 0.2, older.GasUsedRatio)
+
+	newer := chart.Buckets[1]
+	require.Equal(t, (*hexutil.Big)(big.NewInt(3)), newer.FirstBlock)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(4)), newer.LastBlock)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(10)), newer.MedianGasPrice)
+
+	// only the new block is requested
+	chain.extend(7)
+	chart, err = history.Chart(context.Background(), chain, 4, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, 5, chain.requests)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(2)), chart.Buckets[0].FirstBlock)
+	require.Equal(t, 4, chart.Buckets[0].Transactions)
+	require.Empty(t, chart.Buckets[0].Percentiles)
+
+	// cached blocks are replaced after a reorg
+	chain.blocks = chain.blocks[:4]
+	chain.extend(1000)
+	chain.extend(2000)
+	chart, err = history.Chart(context.Background(), chain, 4, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(2000)), chart.Buckets[0].MaxGasPrice)
+	require.Equal(t, 7, chain.requests)
+}
+
+func TestFeeHistoryChartValidation(t *testing.T) {
+	chain := &feeHistoryChain{}
+	chain.extend(1)
+	history := newFeeHistory()
+	_, err := history.Chart(context.Background(), chain, 0, 1, nil)
+	require.Equal(t, ErrInvalidFeeHistoryBlocks, err)
+	_, err = history.Chart(context.Background(), chain, maxFeeHistoryBlocks+1, 1, nil)
+	require.Equal(t, ErrInvalidFeeHistoryBlocks, err)
+	_, err = history.Chart(context.Background(), chain, 1, 0, nil)
+	require.Equal(t, ErrInvalidFeeHistoryBuckets, err)
+	_, err = history.Chart(context.Background(), chain, 1, 1, []float64{50, 10})
+	require.Equal(t, ErrInvalidFeeHistoryPercentiles, err)
+
+	// more blocks than the chain has
+	chart, err := history.Chart(context.Background(), chain, 10, 5, nil)
+	require.NoError(t, err)
+	require.Len(t, chart.Buckets, 1)
+	require.Equal(t, 1, chart.Buckets[0].Transactions)
+}
@@ -0,0 +1,178 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	priceSourceRequestTimeout = 10 * time.Second
+	secondsPerDay             = 24 * 60 * 60
+	ethSymbol                 = "ETH"
+	ethDecimals               = 18
+)
+
+var errPriceSourceFailed = errors.New("price source request failed")
+
+// PriceSource is an external source of historical prices.
+type PriceSource interface {
+	// HistoricalPrice returns a price of a single unit of the asset with the symbol
+	// in the currency at the end of the day.
+	HistoricalPrice(ctx context.Context, symbol, currency string, day time.Time) (float64, error)
+}
+
+// NewCryptoCompareSource returns price source that uses cryptocompare-compatible API.
+func NewCryptoCompareSource(endpoint, apiKey string) *CryptoCompareSource {
+	return &CryptoCompareSource{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: priceSourceRequestTimeout},
+	}
+}
+
+// CryptoCompareSource queries `pricehistorical` method of cryptocompare-compatible API.
+type CryptoCompareSource struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// HistoricalPrice returns a price of the symbol in the currency at the given day.
+func (s *CryptoCompareSource) HistoricalPrice(ctx context.Context, symbol, currency string, day time.Time) (float64, error) {
+	params := url.Values{}
+	params.Set("fsym", symbol)
+	params.Set("tsyms", currency)
+	params.Set("ts", strconv.FormatInt(day.Unix(), 10))
+	if s.apiKey != "" {
+		params.Set("api_key", s.apiKey)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%v: unexpected status %d", errPriceSourceFailed, resp.StatusCode)
+	}
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, err
+	}
+	raw, ok := body[symbol]
+	if !ok {
+		// errors are reported with 200 status and a message
+		return 0, fmt.Errorf("%v: no price for %s", errPriceSourceFailed, symbol)
+	}
+	var prices map[string]float64
+	if err := json.Unmarshal(raw, &prices); err != nil {
+		return 0, err
+	}
+	price, ok := prices[currency]
+	if !ok || price == 0 {
+		return 0, fmt.Errorf("%v: no price for %s in %s", errPriceSourceFailed, symbol, currency)
+	}
+	return price, nil
+}
+
+// startOfDay returns a unix timestamp of the beginning of a day (UTC) that contains the timestamp.
+func startOfDay(timestamp uint64) uint64 {
+	return timestamp - timestamp%secondsPerDay
+}
+
+type priceKey struct {
+	symbol string
+	day    uint64
+}
+
+// historicalPrices reads prices from the database and fetches missing ones from the source.
+type historicalPrices struct {
+	db       *Database
+	source   PriceSource
+	currency string
+	prices   map[priceKey]float64
+}
+
+func (p *historicalPrices) get(ctx context.Context, symbol string, day uint64) (float64, bool, error) {
+	key := priceKey{symbol: symbol, day: day}
+	if price, exist := p.prices[key]; exist {
+		return price, price != 0, nil
+	}
+	price, exist, err := p.db.GetHistoricalPrice(symbol, p.currency, day)
+	if err != nil {
+		return 0, false, err
+	}
+	if !exist && p.source != nil {
+		callCtx, cancel := context.WithTimeout(ctx, priceSourceRequestTimeout)
+		price, err = p.source.HistoricalPrice(callCtx, symbol, p.currency, time.Unix(int64(day+secondsPerDay-1), 0))
+		cancel()
+		if err != nil {
+			log.Warn("failed to fetch historical price", "symbol", symbol, "currency", p.currency, "day", day, "error", err)
+		} else {
+			exist = true
+			if err := p.db.SaveHistoricalPrice(symbol, p.currency, day, price); err != nil {
+				return 0, false, err
+			}
+		}
+	}
+	// missing prices are remembered as zero to avoid repeated requests
+	p.prices[key] = price
+	return price, exist, nil
+}
+
+// setFiatValues computes values of transfers in the currency using prices at the day of a transfer.
+// Values of transfers without known price or token metadata are left empty.
+func setFiatValues(ctx context.Context, db *Database, source PriceSource, views []TransferView, currency string) error {
+	prices := &historicalPrices{
+		db:       db,
+		source:   source,
+		currency: strings.ToUpper(currency),
+		prices:   map[priceKey]float64{},
+	}
+	for i := range views {
+		view := &views[i]
+		if view.Value == nil {
+			continue
+		}
+		symbol, decimals := ethSymbol, uint(ethDecimals)
+		if view.Type == erc20Transfer {
+			if view.Token == nil {
+				continue
+			}
+			symbol, decimals = strings.ToUpper(view.Token.Symbol), view.Token.Decimals
+		}
+		price, exist, err := prices.get(ctx, symbol, startOfDay(uint64(view.Timestamp)))
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		value := fiatValue(view.Value.ToInt(), decimals, price)
+		view.FiatValue = &value
+	}
+	return nil
+}
+
+// fiatValue converts an amount in the smallest units of an asset to the value in fiat.
+func fiatValue(amount *big.Int, decimals uint, price float64) float64 {
+	units := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	value := new(big.Float).Quo(new(big.Float).SetInt(amount), units)
+	value.Mul(value, big.NewFloat(price))
+	rst, _ := value.Float64()
+	return rst
+}
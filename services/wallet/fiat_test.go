@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type priceTestSource struct {
+	// prices indexed by symbol and unix timestamp of the requested time
+	prices map[string]map[int64]float64
+	calls  int
+}
+
+func (s *priceTestSource) HistoricalPrice(ctx context.Context, symbol, currency string, day time.Time) (float64, error) {
+	s.calls++
+	price, exist := s.prices[symbol][day.Unix()]
+	if !exist || currency != "USD" {
+		return 0, errors.New("no price")
+	}
+	return price, nil
+}
+
+func TestStartOfDay(t *testing.T) {
+	require.Equal(t, uint64(0), startOfDay(100))
+	require.Equal(t, uint64(secondsPerDay), startOfDay(secondsPerDay))
+	require.Equal(t, uint64(secondsPerDay), startOfDay(2*secondsPerDay-1))
+}
+
+func TestSetFiatValues(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	day := uint64(10 * secondsPerDay)
+	source := &priceTestSource{prices: map[string]map[int64]float64{
+		"ETH": {int64(day + secondsPerDay - 1): 200},
+	}}
+	require.NoError(t, db.SaveHistoricalPrice("SNT", "USD", day, 0.02))
+
+	eth, _ := new(big.Int).SetString("1500000000000000000", 10)
+	views := []TransferView{
+		{Type: ethTransfer, Timestamp: hexutil.Uint64(day + 100), Value: (*hexutil.Big)(eth)},
+		{Type: ethTransfer, Timestamp: hexutil.Uint64(day + 200), Value: (*hexutil.Big)(eth)},
+		{Type: erc20Transfer, Timestamp: hexutil.Uint64(day + 300), Value: (*hexutil.Big)(big.NewInt(1000)), Token: &Token{Symbol: "SNT", Decimals: 2}},
+		// no token metadata
+		{Type: erc20Transfer, Timestamp: hexutil.Uint64(day), Value: (*hexutil.Big)(big.NewInt(1000))},
+		// no price for the day
+		{Type: ethTransfer, Timestamp: hexutil.Uint64(day + secondsPerDay), Value: (*hexutil.Big)(eth)},
+	}
+	require.NoError(t, setFiatValues(context.Background(), db, source, views, "usd"))
+
+	require.NotNil(t, views[0].FiatValue)
+	require.Equal(t, 300.0, *views[0].FiatValue)
+	require.NotNil(t, views[1].FiatValue)
+	require.Equal(t, 300.0, *views[1].FiatValue)
+	require.NotNil(t, views[2].FiatValue)
+	require.Equal(t, 0.2, *views[2].FiatValue)
+	require.Nil(t, views[3].FiatValue)
+	require.Nil(t, views[4].FiatValue)
+	// one request per missing day, cached prices are not requested
+	require.Equal(t, 2, source.calls)
+
+	price, exist, err := db.GetHistoricalPrice("ETH", "USD", day)
+	require.NoError(t, err)
+	require.True(t, exist)
+	require.Equal(t, 200.0, price)
+	_, exist, err = db.GetHistoricalPrice("ETH", "USD", day+secondsPerDay)
+	require.NoError(t, err)
+	require.False(t, exist)
+}
+
+func TestCryptoCompareSourceHistoricalPrice(t *testing.T) {
+	day := time.Unix(10*secondsPerDay, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.URL.Query().Get("api_key"))
+		require.Equal(t, fmt.Sprint(day.Unix()), r.URL.Query().Get("ts"))
+		switch r.URL.Query().Get("fsym") {
+		case "ETH":
+			fmt.Fprint(w, `{"ETH":{"USD":201.5}}`)
+		default:
+			fmt.Fprint(w, `{"Response":"Error","Message":"There is no data for the symbol"}`)
+		}
+	}))
+	defer server.Close()
+
+	source := NewCryptoCompareSource(server.URL, "key")
+	price, err := source.HistoricalPrice(context.Background(), "ETH", "USD", day)
+	require.NoError(t, err)
+	require.Equal(t, 201.5, price)
+
+	_, err = source.HistoricalPrice(context.Background(), "XYZ", "USD", day)
+	require.Error(t, err)
+}
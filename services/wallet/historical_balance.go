@@ -0,0 +1,210 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+// ErrAddressNotWatched is returned when the history of an address is not downloaded by the wallet.
+var ErrAddressNotWatched = errors.New("address is not watched")
+
+// HistoricalBalance is a balance of a single asset at a historical block.
+type HistoricalBalance struct {
+	Balance *hexutil.Big `json:"balance"`
+	// Exact is true if the balance was read from the chain state of an archive node.
+	// Otherwise the balance was reconstructed from stored transfers, such balance
+	// doesn't account for internal transactions and other changes that are not transfers.
+	Exact bool `json:"exact"`
+	// Complete is false if stored transfers don't cover all blocks after the requested block,
+	// in that case reconstructed balance is not reliable.
+	Complete bool `json:"complete"`
+}
+
+// BalanceAt contains balances of an address at a historical block.
+type BalanceAt struct {
+	Block  *hexutil.Big                         `json:"block"`
+	Eth    HistoricalBalance                    `json:"eth"`
+	Tokens map[common.Address]HistoricalBalance `json:"tokens"`
+}
+
+// balanceAtClient is a subset of ethclient methods required to read balances.
+type balanceAtClient interface {
+	bind.ContractCaller
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// GetBalanceAt returns eth and tokens balances of the address at the block.
+// Balances are read from the chain state if the node keeps it for the block, otherwise
+// they are reconstructed from the balances at the last scanned block and stored transfers.
+func GetBalanceAt(parent context.Context, db *Database, client balanceAtClient, address common.Address, tokens []common.Address, block *big.Int) (*BalanceAt, error) {
+	rst := &BalanceAt{
+		Block:  (*hexutil.Big)(block),
+		Tokens: map[common.Address]HistoricalBalance{},
+	}
+	history := &transfersHistory{db: db, client: client, address: address, block: block}
+
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	balance, err := client.BalanceAt(ctx, address, block)
+	cancel()
+	if err == nil {
+		rst.Eth = HistoricalBalance{Balance: (*hexutil.Big)(balance), Exact: true, Complete: true}
+	} else {
+		log.Debug("can't read historical eth balance, reconstructing from transfers", "address", address, "block", block, "error", err)
+		rst.Eth, err = history.ethBalance(parent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, token := range tokens {
+		caller, err := ierc20.NewIERC20Caller(token, client)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+		balance, err := caller.BalanceOf(&bind.CallOpts{BlockNumber: block, Context: ctx}, address)
+		cancel()
+		if err == nil {
+			rst.Tokens[token] = HistoricalBalance{Balance: (*hexutil.Big)(balance), Exact: true, Complete: true}
+			continue
+		}
+		log.Debug("can't read historical token balance, reconstructing from transfers", "address", address, "token", token, "block", block, "error", err)
+		rst.Tokens[token], err = history.tokenBalance(parent, caller, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rst, nil
+}
+
+// transfersHistory reconstructs balances by reverting stored transfers
+// made after the block from balances at the last scanned block.
+type transfersHistory struct {
+	db      *Database
+	client  balanceAtClient
+	address common.Address
+	block   *big.Int
+
+	loaded    bool
+	last      *big.Int
+	complete  bool
+	transfers []Transfer
+}
+
+func (h *transfersHistory) load() error {
+	if h.loaded {
+		return nil
+	}
+	last, err := h.db.GetLastKnownBlockByAddress(h.address)
+	if err != nil {
+		return err
+	}
+	if last == nil || last.Cmp(h.block) < 0 {
+		return errors.New("block is not scanned yet")
+	}
+	first, err := h.db.GetFirstKnownBlock(h.address)
+	if err != nil {
+		return err
+	}
+	start := new(big.Int).Add(h.block, big.NewInt(1))
+	transfers, err := h.db.GetTransfersInRange(h.address, start, last)
+	if err != nil {
+		return err
+	}
+	h.last = last
+	h.complete = first != nil && first.Cmp(start) <= 0
+	h.transfers = transfers
+	h.loaded = true
+	return nil
+}
+
+func (h *transfersHistory) ethBalance(parent context.Context) (HistoricalBalance, error) {
+	if err := h.load(); err != nil {
+		return HistoricalBalance{}, err
+	}
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	balance, err := h.client.BalanceAt(ctx, h.address, h.last)
+	cancel()
+	if err != nil {
+		return HistoricalBalance{}, err
+	}
+	balance = new(big.Int).Sub(balance, ethBalanceChange(h.address, h.transfers))
+	return HistoricalBalance{Balance: (*hexutil.Big)(balance), Complete: h.complete}, nil
+}
+
+func (h *transfersHistory) tokenBalance(parent context.Context, caller *ierc20.IERC20Caller, token common.Address) (HistoricalBalance, error) {
+	if err := h.load(); err != nil {
+		return HistoricalBalance{}, err
+	}
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	balance, err := caller.BalanceOf(&bind.CallOpts{BlockNumber: h.last, Context: ctx}, h.address)
+	cancel()
+	if err != nil {
+		return HistoricalBalance{}, err
+	}
+	balance = new(big.Int).Sub(balance, tokenBalanceChange(h.address, token, h.transfers))
+	return HistoricalBalance{Balance: (*hexutil.Big)(balance), Complete: h.complete}, nil
+}
+
+// ethBalanceChange sums received and sent ether, including fees paid by the address.
+func ethBalanceChange(address common.Address, transfers []Transfer) *big.Int {
+	change := new(big.Int)
+	for i := range transfers {
+		transfer := &transfers[i]
+		if transfer.Type == withdrawalTransfer && transfer.Withdrawal != nil {
+			change.Add(change, transfer.Withdrawal.Value())
+			continue
+		}
+		if (transfer.Type != ethTransfer && transfer.Type != depositTransfer) || transfer.Transaction == nil {
+			continue
+		}
+		tx := transfer.Transaction
+		success := transfer.Receipt == nil || transfer.Receipt.Status == 1
+		if success && tx.To() != nil && *tx.To() == address {
+			change.Add(change, tx.Value())
+		}
+		if transfer.From == address {
+			if success {
+				change.Sub(change, tx.Value())
+			}
+			if transfer.Receipt != nil {
+				fee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(transfer.Receipt.GasUsed))
+				change.Sub(change, fee)
+			}
+		}
+	}
+	return change
+}
+
+// tokenBalanceChange sums received and sent tokens of a given contract.
+func tokenBalanceChange(address common.Address, token common.Address, transfers []Transfer) *big.Int {
+	signature := crypto.Keccak256Hash([]byte(erc20TransferEventSignature))
+	change := new(big.Int)
+	for i := range transfers {
+		l := transfers[i].Log
+		if transfers[i].Type != erc20Transfer || l == nil || l.Address != token {
+			continue
+		}
+		if len(l.Topics) != 3 || l.Topics[0] != signature {
+			continue
+		}
+		amount := new(big.Int).SetBytes(l.Data)
+		if common.BytesToAddress(l.Topics[2].Bytes()) == address {
+			change.Add(change, amount)
+		}
+		if common.BytesToAddress(l.Topics[1].Bytes()) == address {
+			change.Sub(change, amount)
+		}
+	}
+	return change
+}
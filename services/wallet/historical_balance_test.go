@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type historicalBalanceTestClient struct {
+	// balances available in the node state indexed by block number
+	balances map[uint64]*big.Int
+}
+
+func (c *historicalBalanceTestClient) BalanceAt(ctx context.Context, account common.Address, block *big.Int) (*big.Int, error) {
+	balance, exist := c.balances[block.Uint64()]
+	if !exist {
+		return nil, errors.New("missing trie node")
+	}
+	return balance, nil
+}
+
+func (c *historicalBalanceTestClient) CodeAt(ctx context.Context, contract common.Address, block *big.Int) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *historicalBalanceTestClient) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestGetBalanceAtFromState(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	client := &historicalBalanceTestClient{balances: map[uint64]*big.Int{5: big.NewInt(100)}}
+
+	rst, err := GetBalanceAt(context.Background(), db, client, common.Address{1}, nil, big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, HistoricalBalance{Balance: (*hexutil.Big)(big.NewInt(100)), Exact: true, Complete: true}, rst.Eth)
+}
+
+func TestGetBalanceAtFromTransfers(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	address := common.Address{1}
+	other := common.Address{2}
+
+	headers := []*DBHeader{}
+	transfers := []Transfer{}
+	// incoming 10 wei at block 3, outgoing 4 wei with 21000 gas at price 1 at block 7
+	for i, tx := range []*types.Transaction{
+		types.NewTransaction(1, address, big.NewInt(10), 21000, big.NewInt(1), nil),
+		types.NewTransaction(2, other, big.NewInt(4), 21000, big.NewInt(1), nil),
+	} {
+		header := &DBHeader{Number: big.NewInt(int64(3 + 4*i)), Hash: common.Hash{byte(i + 1)}, Address: address}
+		headers = append(headers, header)
+		receipt := types.NewReceipt(nil, false, 21000)
+		receipt.Status = types.ReceiptStatusSuccessful
+		receipt.GasUsed = 21000
+		receipt.Logs = []*types.Log{}
+		from := other
+		if i == 1 {
+			from = address
+		}
+		transfers = append(transfers, Transfer{
+			ID:          tx.Hash(),
+			Type:        ethTransfer,
+			BlockNumber: header.Number,
+			BlockHash:   header.Hash,
+			Transaction: tx,
+			Receipt:     receipt,
+			Address:     address,
+			From:        from,
+		})
+	}
+	require.NoError(t, db.ProcessBlocks(address, big.NewInt(2), big.NewInt(10), headers))
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+
+	client := &historicalBalanceTestClient{balances: map[uint64]*big.Int{10: big.NewInt(50000)}}
+
+	rst, err := GetBalanceAt(context.Background(), db, client, address, nil, big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, HistoricalBalance{Balance: (*hexutil.Big)(big.NewInt(50000 + 4 + 21000)), Complete: true}, rst.Eth)
+
+	rst, err = GetBalanceAt(context.Background(), db, client, address, nil, big.NewInt(0))
+	require.NoError(t, err)
+	require.Equal(t, HistoricalBalance{Balance: (*hexutil.Big)(big.NewInt(50000 + 4 + 21000 - 10)), Complete: false}, rst.Eth)
+
+	_, err = GetBalanceAt(context.Background(), db, client, address, nil, big.NewInt(11))
+	require.Error(t, err)
+}
+
+func TestTokenBalanceChange(t *testing.T) {
+	address := common.Address{1}
+	token := common.Address{9}
+	signature := crypto.Keccak256Hash([]byte(erc20TransferEventSignature))
+	transferLog := func(contract, from, to common.Address, amount int64) Transfer {
+		return Transfer{
+			Type: erc20Transfer,
+			Log: &types.Log{
+				Address: contract,
+				Topics:  []common.Hash{signature, from.Hash(), to.Hash()},
+				Data:    common.LeftPadBytes(big.NewInt(amount).Bytes(), 32),
+			},
+		}
+	}
+	transfers := []Transfer{
+		transferLog(token, common.Address{2}, address, 100),
+		transferLog(token, address, common.Address{3}, 30),
+		transferLog(common.Address{8}, common.Address{2}, address, 1000),
+	}
+	require.Equal(t, big.NewInt(70), tokenBalanceChange(address, token, transfers))
+}
@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LogsReader interface for reading logs.
+type LogsReader interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+type historyClient interface {
+	BalanceReader
+	LogsReader
+}
+
+// CheckRecentHistory returns addresses that have any on-chain activity: non-zero balance,
+// non-zero nonce or at least one erc20 transfer.
+func CheckRecentHistory(parent context.Context, client historyClient, addresses []common.Address) ([]common.Address, error) {
+	var (
+		group  = NewAtomicGroup(parent)
+		mu     sync.Mutex
+		active = map[common.Address]bool{}
+	)
+	for _, address := range addresses {
+		address := address
+		group.Add(func(parent context.Context) error {
+			used, err := hasActivity(parent, client, address)
+			if err != nil {
+				return err
+			}
+			if used {
+				mu.Lock()
+				active[address] = true
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	select {
+	case <-group.WaitAsync():
+	case <-parent.Done():
+		return nil, parent.Err()
+	}
+	if err := group.Error(); err != nil {
+		return nil, err
+	}
+	// preserve the order of addresses, clients rely on it to find a gap in the derivation path
+	rst := []common.Address{}
+	for _, address := range addresses {
+		if active[address] {
+			rst = append(rst, address)
+		}
+	}
+	return rst, nil
+}
+
+func hasActivity(parent context.Context, client historyClient, address common.Address) (bool, error) {
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	balance, err := client.BalanceAt(ctx, address, nil)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	if balance.Cmp(zero) > 0 {
+		return true, nil
+	}
+
+	ctx, cancel = context.WithTimeout(parent, 5*time.Second)
+	nonce, err := client.NonceAt(ctx, address, nil)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	if nonce > 0 {
+		return true, nil
+	}
+
+	signature := crypto.Keccak256Hash([]byte(erc20TransferEventSignature))
+	padded := common.BytesToHash(address.Bytes())
+	// outbound token transfers increase nonce, so only inbound have to be checked
+	ctx, cancel = context.WithTimeout(parent, 10*time.Second)
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: zero,
+		Topics:    [][]common.Hash{{signature}, {}, {padded}},
+	})
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	return len(logs) > 0, nil
+}
@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type historyTestClient struct {
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	// receivers of erc20 transfers
+	receivers map[common.Hash]bool
+}
+
+func (c historyTestClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if balance, exist := c.balances[account]; exist {
+		return balance, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (c historyTestClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.nonces[account], nil
+}
+
+func (c historyTestClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if c.receivers[q.Topics[2][0]] {
+		return []types.Log{{}}, nil
+	}
+	return nil, nil
+}
+
+func TestCheckRecentHistory(t *testing.T) {
+	withBalance := common.Address{1}
+	withNonce := common.Address{2}
+	withTokens := common.Address{3}
+	unused := common.Address{4}
+	client := historyTestClient{
+		balances:  map[common.Address]*big.Int{withBalance: big.NewInt(1)},
+		nonces:    map[common.Address]uint64{withNonce: 1},
+		receivers: map[common.Hash]bool{common.BytesToHash(withTokens.Bytes()): true},
+	}
+
+	rst, err := CheckRecentHistory(context.Background(), client, []common.Address{withTokens, unused, withBalance, withNonce})
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{withTokens, withBalance, withNonce}, rst)
+
+	rst, err = CheckRecentHistory(context.Background(), client, []common.Address{unused})
+	require.NoError(t, err)
+	require.Empty(t, rst)
+}
@@ -0,0 +1,290 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	indexerRequestTimeout = 30 * time.Second
+	// etherscanPageSize is a number of transactions requested per page.
+	etherscanPageSize = 1000
+	// etherscanMaxResults is a maximum number of results that etherscan returns for a single
+	// query regardless of pagination (page * offset).
+	etherscanMaxResults = 10000
+)
+
+var errIndexerFailed = errors.New("indexer request failed")
+
+// HistoryIndexer is an external source of block numbers with transfers for an address.
+// Results are used only as hints and are verified against the node before storing. Blocks
+// that can't be verified because the node doesn't keep their state are stored unverified.
+// Indexer returns the last block for which results are complete, it is lower than `to`
+// if indexer truncated the results.
+type HistoryIndexer interface {
+	BlocksWithTransfers(ctx context.Context, address common.Address, from, to *big.Int) (blocks []*big.Int, indexedTo *big.Int, err error)
+}
+
+// NewEtherscanIndexer returns indexer that uses etherscan-compatible API.
+func NewEtherscanIndexer(endpoint, apiKey string) *EtherscanIndexer {
+	return &EtherscanIndexer{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: indexerRequestTimeout},
+	}
+}
+
+// EtherscanIndexer queries `account` module of etherscan-compatible API.
+type EtherscanIndexer struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+type etherscanTx struct {
+	BlockNumber string `json:"blockNumber"`
+}
+
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// BlocksWithTransfers returns sorted unique block numbers with eth, internal eth and erc20 transfers for an address.
+// If etherscan truncated any of the lists, blocks are returned only up to the last block that is complete in all of them.
+func (i *EtherscanIndexer) BlocksWithTransfers(ctx context.Context, address common.Address, from, to *big.Int) ([]*big.Int, *big.Int, error) {
+	unique := map[string]*big.Int{}
+	indexedTo := to
+	for _, action := range []string{"txlist", "txlistinternal", "tokentx"} {
+		blocks, complete, err := i.queryAll(ctx, action, address, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		if complete.Cmp(indexedTo) < 0 {
+			indexedTo = complete
+		}
+		for _, block := range blocks {
+			unique[block.String()] = block
+		}
+	}
+	rst := make([]*big.Int, 0, len(unique))
+	for _, block := range unique {
+		if block.Cmp(indexedTo) > 0 {
+			continue
+		}
+		rst = append(rst, block)
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		return rst[i].Cmp(rst[j]) < 0
+	})
+	return rst, indexedTo, nil
+}
+
+// queryAll requests pages in ascending order until a short page is returned or etherscan limit is reached.
+// In the latter case the last block may be listed only partially, so results are complete only up to the block before it.
+func (i *EtherscanIndexer) queryAll(ctx context.Context, action string, address common.Address, from, to *big.Int) ([]*big.Int, *big.Int, error) {
+	rst := []*big.Int{}
+	for page := 1; ; page++ {
+		blocks, err := i.query(ctx, action, address, from, to, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		rst = append(rst, blocks...)
+		if len(blocks) < etherscanPageSize {
+			return rst, to, nil
+		}
+		if (page+1)*etherscanPageSize > etherscanMaxResults {
+			last := rst[len(rst)-1]
+			log.Warn("indexer results truncated", "action", action, "address", address, "last", last)
+			return rst, new(big.Int).Sub(last, one), nil
+		}
+	}
+}
+
+func (i *EtherscanIndexer) query(ctx context.Context, action string, address common.Address, from, to *big.Int, page int) ([]*big.Int, error) {
+	params := url.Values{}
+	params.Set("module", "account")
+	params.Set("action", action)
+	params.Set("address", address.Hex())
+	params.Set("startblock", from.String())
+	params.Set("endblock", to.String())
+	params.Set("page", strconv.Itoa(page))
+	params.Set("offset", strconv.Itoa(etherscanPageSize))
+	params.Set("sort", "asc")
+	if i.apiKey != "" {
+		params.Set("apikey", i.apiKey)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, i.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := i.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v: unexpected status %d", errIndexerFailed, resp.StatusCode)
+	}
+	return parseEtherscanResponse(resp)
+}
+
+func parseEtherscanResponse(resp *http.Response) ([]*big.Int, error) {
+	var body etherscanResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Status != "1" {
+		// etherscan reports an empty result set with status 0
+		if body.Message == "No transactions found" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%v: %s", errIndexerFailed, body.Message)
+	}
+	var txs []etherscanTx
+	if err := json.Unmarshal(body.Result, &txs); err != nil {
+		return nil, err
+	}
+	rst := make([]*big.Int, 0, len(txs))
+	for _, tx := range txs {
+		block, ok := new(big.Int).SetString(tx.BlockNumber, 10)
+		if !ok {
+			return nil, fmt.Errorf("%v: invalid block number %s", errIndexerFailed, tx.BlockNumber)
+		}
+		rst = append(rst, block)
+	}
+	return rst, nil
+}
+
+// indexHistoryCommand discovers historical blocks with transfers using external indexer
+// and saves blocks that were confirmed by the node.
+type indexHistoryCommand struct {
+	db      *Database
+	indexer HistoryIndexer
+	client  *ethclient.Client
+	chain   *big.Int
+	address common.Address
+	to      *big.Int
+
+	foundHeaders []*DBHeader
+	// indexedTo is the last block of the range that was marked as scanned.
+	// Range after it must be discovered from the node.
+	indexedTo *big.Int
+	// unverified are blocks reported by the indexer that were stored without verification
+	// because the node doesn't have the state required to verify them.
+	unverified []*big.Int
+}
+
+func (c *indexHistoryCommand) Run(ctx context.Context) error {
+	start := time.Now()
+	blocks, indexedTo, err := c.indexer.BlocksWithTransfers(ctx, c.address, zero, c.to)
+	if err != nil {
+		log.Error("failed to query history indexer", "address", c.address, "error", err)
+		return err
+	}
+	if indexedTo.Cmp(zero) < 0 {
+		return fmt.Errorf("%v: no complete range", errIndexerFailed)
+	}
+	signer := types.NewEIP155Signer(c.chain)
+	erc20 := NewERC20TransfersDownloader(c.client, []common.Address{c.address}, signer)
+	headers := []*DBHeader{}
+	for _, block := range blocks {
+		if block.Cmp(indexedTo) > 0 {
+			continue
+		}
+		blockHeaders, verified, err := verifyIndexedBlock(ctx, c.client, erc20, signer, c.address, block)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			log.Warn("node doesn't have state to verify block reported by indexer, keeping it unverified", "address", c.address, "block", block)
+			c.unverified = append(c.unverified, block)
+		}
+		if len(blockHeaders) == 0 {
+			log.Warn("block reported by indexer doesn't have transfers", "address", c.address, "block", block)
+			continue
+		}
+		headers = append(headers, blockHeaders...)
+	}
+	err = c.db.ProcessBlocks(c.address, zero, indexedTo, headers)
+	if err != nil {
+		return err
+	}
+	c.foundHeaders = headers
+	c.indexedTo = indexedTo
+	log.Info("history indexer finished", "address", c.address, "reported", len(blocks), "stored", len(headers), "unverified", len(c.unverified), "indexed to", indexedTo, "in", time.Since(start))
+	return nil
+}
+
+// verifyIndexedBlock returns headers for a block only if the node confirms that it has transfers for the address.
+// Internal transfers are not visible in transactions and logs, they are confirmed by the balance change.
+// If the node doesn't keep the state of the block to compare balances, the block is returned unverified.
+func verifyIndexedBlock(parent context.Context, client *ethclient.Client, erc20 *ERC20TransfersDownloader, signer types.Signer, address common.Address, number *big.Int) (headers []*DBHeader, verified bool, err error) {
+	headers, err = erc20.GetHeadersInRange(parent, number, number)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(headers) > 0 {
+		return headers, true, nil
+	}
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	blk, err := client.BlockByNumber(ctx, number)
+	cancel()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, tx := range blk.Transactions() {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, false, err
+		}
+		if from == address || (tx.To() != nil && *tx.To() == address) {
+			return []*DBHeader{toDBHeader(blk.Header())}, true, nil
+		}
+	}
+	if number.Cmp(zero) == 0 {
+		return nil, true, nil
+	}
+	ctx, cancel = context.WithTimeout(parent, 5*time.Second)
+	defer cancel()
+	before, err := client.BalanceAt(ctx, address, new(big.Int).Sub(number, one))
+	if isMissingStateError(err) {
+		return []*DBHeader{toDBHeader(blk.Header())}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	after, err := client.BalanceAt(ctx, address, number)
+	if isMissingStateError(err) {
+		return []*DBHeader{toDBHeader(blk.Header())}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if before.Cmp(after) != 0 {
+		return []*DBHeader{toDBHeader(blk.Header())}, true, nil
+	}
+	return nil, true, nil
+}
+
+// isMissingStateError returns true if the node doesn't have the state of a block,
+// e.g. because it is pruned or the node is a light client.
+func isMissingStateError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "missing trie node")
+}
@@ -0,0 +1,171 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestEtherscanIndexerBlocksWithTransfers(t *testing.T) {
+	address := common.Address{1}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, address.Hex(), r.URL.Query().Get("address"))
+		require.Equal(t, "key", r.URL.Query().Get("apikey"))
+		switch r.URL.Query().Get("action") {
+		case "txlist":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"20"},{"blockNumber":"10"}]}`)
+		case "txlistinternal":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"12"}]}`)
+		case "tokentx":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"20"},{"blockNumber":"15"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	indexer := NewEtherscanIndexer(server.URL, "key")
+	blocks, indexedTo, err := indexer.BlocksWithTransfers(context.Background(), address, zero, big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, []*big.Int{big.NewInt(10), big.NewInt(12), big.NewInt(15), big.NewInt(20)}, blocks)
+	require.Equal(t, big.NewInt(100), indexedTo)
+}
+
+func etherscanPage(start, n int) string {
+	txs := make([]string, n)
+	for i := range txs {
+		txs[i] = fmt.Sprintf(`{"blockNumber":"%d"}`, start+i)
+	}
+	return `{"status":"1","message":"OK","result":[` + strings.Join(txs, ",") + `]}`
+}
+
+func TestEtherscanIndexerPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, strconv.Itoa(etherscanPageSize), r.URL.Query().Get("offset"))
+		if r.URL.Query().Get("action") != "txlist" {
+			fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, etherscanPage(1, etherscanPageSize))
+		case "2":
+			fmt.Fprint(w, etherscanPage(1+etherscanPageSize, 10))
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	indexer := NewEtherscanIndexer(server.URL, "")
+	blocks, indexedTo, err := indexer.BlocksWithTransfers(context.Background(), common.Address{1}, zero, big.NewInt(100000))
+	require.NoError(t, err)
+	require.Len(t, blocks, etherscanPageSize+10)
+	require.Equal(t, big.NewInt(100000), indexedTo)
+}
+
+func TestEtherscanIndexerTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "tokentx" {
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"5"},{"blockNumber":"50000"}]}`)
+			return
+		}
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		require.True(t, page*etherscanPageSize <= etherscanMaxResults)
+		fmt.Fprint(w, etherscanPage(1+(page-1)*etherscanPageSize, etherscanPageSize))
+	}))
+	defer server.Close()
+
+	indexer := NewEtherscanIndexer(server.URL, "")
+	blocks, indexedTo, err := indexer.BlocksWithTransfers(context.Background(), common.Address{1}, zero, big.NewInt(100000))
+	require.NoError(t, err)
+	// last block might be listed partially
+	require.Equal(t, big.NewInt(etherscanMaxResults-1), indexedTo)
+	require.Len(t, blocks, etherscanMaxResults-1)
+	require.Equal(t, indexedTo, blocks[len(blocks)-1])
+}
+
+func TestEtherscanIndexerNoTransactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+	}))
+	defer server.Close()
+
+	indexer := NewEtherscanIndexer(server.URL, "")
+	blocks, indexedTo, err := indexer.BlocksWithTransfers(context.Background(), common.Address{1}, zero, big.NewInt(100))
+	require.NoError(t, err)
+	require.Empty(t, blocks)
+	require.Equal(t, big.NewInt(100), indexedTo)
+}
+
+func TestEtherscanIndexerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	}))
+	defer server.Close()
+
+	indexer := NewEtherscanIndexer(server.URL, "")
+	_, _, err := indexer.BlocksWithTransfers(context.Background(), common.Address{1}, zero, big.NewInt(100))
+	require.Error(t, err)
+}
+
+// prunedStateAPI serves blocks without transactions and logs, but doesn't keep the state.
+type prunedStateAPI struct{}
+
+func (prunedStateAPI) GetLogs(ctx context.Context, crit interface{}) ([]types.Log, error) {
+	return []types.Log{}, nil
+}
+
+func (prunedStateAPI) GetBlockByNumber(ctx context.Context, number hexutil.Big, full bool) (map[string]interface{}, error) {
+	header := &types.Header{
+		Number:     number.ToInt(),
+		UncleHash:  types.EmptyUncleHash,
+		TxHash:     types.EmptyRootHash,
+		Difficulty: big.NewInt(1),
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	var rst map[string]interface{}
+	if err := json.Unmarshal(data, &rst); err != nil {
+		return nil, err
+	}
+	rst["transactions"] = []interface{}{}
+	rst["uncles"] = []interface{}{}
+	return rst, nil
+}
+
+func (prunedStateAPI) GetBalance(ctx context.Context, address common.Address, number hexutil.Big) (*hexutil.Big, error) {
+	return nil, errors.New("missing trie node 0a8e6be1f5a6f6e3d6ee0d6c33f4e1f2d6f1c9e0 (path )")
+}
+
+func TestVerifyIndexedBlockWithoutState(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", prunedStateAPI{}))
+	defer server.Stop()
+	client := ethclient.NewClient(rpc.DialInProc(server))
+
+	address := common.Address{1}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	erc20 := NewERC20TransfersDownloader(client, []common.Address{address}, signer)
+	headers, verified, err := verifyIndexedBlock(context.Background(), client, erc20, signer, address, big.NewInt(10))
+	require.NoError(t, err)
+	require.False(t, verified)
+	require.Len(t, headers, 1)
+	require.Equal(t, big.NewInt(10), headers[0].Number)
+}
@@ -0,0 +1,316 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/typeddata"
+	"github.com/status-im/status-go/signal"
+)
+
+var (
+	// ErrKeycardNotConnected returned when a keycard wasn't tapped to the reader in time.
+	ErrKeycardNotConnected = errors.New("keycard is not connected")
+	// ErrKeycardNotPaired returned when the connected keycard wasn't paired with wallet_pairKeycard.
+	ErrKeycardNotPaired = errors.New("keycard is not paired")
+	// ErrNotKeycardAccount returned when signing with an account that wasn't added from a keycard.
+	ErrNotKeycardAccount = errors.New("account is not on a keycard")
+	// ErrKeycardWrongPIN returned when the PIN is wrong and the keycard has attempts left.
+	ErrKeycardWrongPIN = errors.New("wrong keycard PIN")
+	// ErrKeycardBlocked returned when there are no PIN attempts left. The keycard has to be unblocked with the PUK.
+	ErrKeycardBlocked = errors.New("keycard is blocked")
+	// ErrInvalidKeycardSignature returned when a signature made by the keycard doesn't belong to the account.
+	ErrInvalidKeycardSignature = errors.New("keycard returned invalid signature")
+)
+
+// Keycard is a channel to a keycard on the reader, commands are sent to the card
+// once it is tapped and a secure channel is opened.
+// No implementation is provided, the card transport is supplied by an application
+// embedding status-go with Service.ConnectKeycard.
+type Keycard interface {
+	// InstanceUID returns a unique identifier of the applet on the card.
+	InstanceUID() string
+	// Pair pairs the card using the pairing password and returns the pairing key and index
+	// together with the number of PIN attempts left.
+	Pair(ctx context.Context, pairingPassword string) (KeycardPairing, error)
+	// OpenSecureChannel opens a secure channel with a pairing made before.
+	OpenSecureChannel(ctx context.Context, pairing KeycardPairing) error
+	// VerifyPIN returns whether the PIN is correct and the number of attempts left after the verification.
+	VerifyPIN(ctx context.Context, pin string) (bool, int, error)
+	// Derive returns an address of the account at the derivation path.
+	Derive(ctx context.Context, path accounts.DerivationPath) (common.Address, error)
+	// Sign signs the hash with the key at the derivation path and returns
+	// the signature in the [R || S || V] format with V equal to 0 or 1.
+	Sign(ctx context.Context, path accounts.DerivationPath, hash common.Hash) ([]byte, error)
+}
+
+// KeycardPairing is a pairing of a keycard, it is required to open a secure channel with the card.
+type KeycardPairing struct {
+	InstanceUID string        `json:"instanceUID"`
+	Key         hexutil.Bytes `json:"-"`
+	Index       uint8         `json:"-"`
+	// PINRetries is the number of PIN attempts left after the last verification.
+	PINRetries int   `json:"pinRetries"`
+	PairedAt   int64 `json:"pairedAt"`
+}
+
+// KeycardAccount is an account on a keycard.
+type KeycardAccount struct {
+	Address     common.Address `json:"address"`
+	InstanceUID string         `json:"instanceUID"`
+	Path        string         `json:"path"`
+}
+
+// KeycardEventType is a step of an interaction with a keycard.
+type KeycardEventType string
+
+const (
+	// KeycardInsertRequired the keycard has to be tapped to the reader.
+	KeycardInsertRequired KeycardEventType = "insert-required"
+	// KeycardConnected the keycard was tapped to the reader.
+	KeycardConnected KeycardEventType = "connected"
+	// KeycardWrongPIN the PIN is wrong, the number of attempts left is sent with the event.
+	KeycardWrongPIN KeycardEventType = "wrong-pin"
+	// KeycardBlocked there are no PIN attempts left.
+	KeycardBlocked KeycardEventType = "blocked"
+	// KeycardSigning the keycard is signing, it must stay on the reader.
+	KeycardSigning KeycardEventType = "signing"
+	// KeycardSigned the keycard can be removed from the reader.
+	KeycardSigned KeycardEventType = "signed"
+)
+
+// KeycardEvent is sent with the wallet.keycard signal.
+type KeycardEvent struct {
+	Type              KeycardEventType `json:"type"`
+	InstanceUID       string           `json:"instanceUID,omitempty"`
+	Account           *common.Address  `json:"account,omitempty"`
+	RemainingAttempts int              `json:"remainingAttempts,omitempty"`
+}
+
+// keycardSigner pairs keycards and signs transactions and typed data of accounts added from them.
+// A single reader is supported, the card is connected by the client when it is tapped.
+type keycardSigner struct {
+	db      *Database
+	timeout time.Duration
+	notify  func(KeycardEvent)
+
+	mu   sync.Mutex
+	card Keycard
+	// changed is closed when a card is connected
+	changed chan struct{}
+}
+
+func newKeycardSigner(db *Database, timeout time.Duration) *keycardSigner {
+	if timeout == 0 {
+		timeout = defaultConfirmationTimeout
+	}
+	return &keycardSigner{
+		db:      db,
+		timeout: timeout,
+		notify: func(event KeycardEvent) {
+			signal.SendWalletKeycardEvent(event)
+		},
+		changed: make(chan struct{}),
+	}
+}
+
+// Connect sets the card tapped to the reader.
+func (s *keycardSigner) Connect(card Keycard) {
+	s.mu.Lock()
+	s.card = card
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+	s.notify(KeycardEvent{Type: KeycardConnected, InstanceUID: card.InstanceUID()})
+}
+
+// Disconnect is called when the card is removed from the reader.
+func (s *keycardSigner) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.card = nil
+}
+
+// waitCard returns the connected card with the instance UID, any card if uid is empty.
+// The user is requested to tap the card if it is not connected.
+func (s *keycardSigner) waitCard(ctx context.Context, uid string, account *common.Address) (Keycard, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	requested := false
+	for {
+		s.mu.Lock()
+		card, changed := s.card, s.changed
+		s.mu.Unlock()
+		if card != nil && (uid == "" || card.InstanceUID() == uid) {
+			return card, nil
+		}
+		if !requested {
+			s.notify(KeycardEvent{Type: KeycardInsertRequired, InstanceUID: uid, Account: account})
+			requested = true
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, ErrKeycardNotConnected
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Pair pairs the card on the reader and stores the pairing.
+func (s *keycardSigner) Pair(ctx context.Context, pairingPassword string) (KeycardPairing, error) {
+	card, err := s.waitCard(ctx, "", nil)
+	if err != nil {
+		return KeycardPairing{}, err
+	}
+	pairing, err := card.Pair(ctx, pairingPassword)
+	if err != nil {
+		return KeycardPairing{}, err
+	}
+	pairing.InstanceUID = card.InstanceUID()
+	pairing.PairedAt = time.Now().Unix()
+	return pairing, s.db.SaveKeycardPairing(pairing)
+}
+
+// Unpair removes the pairing of the card and its accounts.
+func (s *keycardSigner) Unpair(uid string) error {
+	return s.db.DeleteKeycardPairing(uid)
+}
+
+// open waits for the card, opens a secure channel and verifies the PIN.
+// Attempts left are stored, so that a blocked card isn't used again.
+func (s *keycardSigner) open(ctx context.Context, uid string, account *common.Address, pin string) (Keycard, error) {
+	pairing, err := s.db.GetKeycardPairing(uid)
+	if err != nil {
+		return nil, err
+	}
+	if pairing == nil {
+		return nil, ErrKeycardNotPaired
+	}
+	if pairing.PINRetries == 0 {
+		return nil, ErrKeycardBlocked
+	}
+	card, err := s.waitCard(ctx, uid, account)
+	if err != nil {
+		return nil, err
+	}
+	if err := card.OpenSecureChannel(ctx, *pairing); err != nil {
+		return nil, err
+	}
+	verified, remaining, err := card.VerifyPIN(ctx, pin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.SaveKeycardPINRetries(uid, remaining); err != nil {
+		return nil, err
+	}
+	if verified {
+		return card, nil
+	}
+	if remaining == 0 {
+		log.Info("keycard is blocked", "instanceUID", uid)
+		s.notify(KeycardEvent{Type: KeycardBlocked, InstanceUID: uid, Account: account})
+		return nil, ErrKeycardBlocked
+	}
+	s.notify(KeycardEvent{Type: KeycardWrongPIN, InstanceUID: uid, Account: account, RemainingAttempts: remaining})
+	return nil, ErrKeycardWrongPIN
+}
+
+// AddAccount derives an account from the paired card and stores its derivation path.
+func (s *keycardSigner) AddAccount(ctx context.Context, uid, pin, path string) (KeycardAccount, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return KeycardAccount{}, err
+	}
+	card, err := s.open(ctx, uid, nil, pin)
+	if err != nil {
+		return KeycardAccount{}, err
+	}
+	address, err := card.Derive(ctx, derivationPath)
+	if err != nil {
+		return KeycardAccount{}, err
+	}
+	account := KeycardAccount{Address: address, InstanceUID: uid, Path: derivationPath.String()}
+	return account, s.db.SaveKeycardAccount(account)
+}
+
+// SignHash signs the hash on the card of the account and verifies that the signature
+// belongs to the account. V of the signature is 0 or 1.
+func (s *keycardSigner) SignHash(ctx context.Context, address common.Address, pin string, hash common.Hash) ([]byte, error) {
+	account, err := s.db.GetKeycardAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrNotKeycardAccount
+	}
+	path, err := accounts.ParseDerivationPath(account.Path)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.open(ctx, account.InstanceUID, &address, pin)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(KeycardEvent{Type: KeycardSigning, InstanceUID: account.InstanceUID, Account: &address})
+	sig, err := card.Sign(ctx, path, hash)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(KeycardEvent{Type: KeycardSigned, InstanceUID: account.InstanceUID, Account: &address})
+
+	if len(sig) != 65 {
+		return nil, ErrInvalidKeycardSignature
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != address {
+		return nil, ErrInvalidKeycardSignature
+	}
+	return sig, nil
+}
+
+// SignTypedData validates typed data for the chain and signs it on the card of the account.
+func (s *keycardSigner) SignTypedData(ctx context.Context, typed typeddata.TypedData, chainID *big.Int, address common.Address, pin string) ([]byte, error) {
+	hash, err := typeddata.ValidateAndHash(typed, chainID)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.SignHash(ctx, address, pin, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// WithPIN returns a Signer of transactions that unlocks keycards with the PIN.
+func (s *keycardSigner) WithPIN(pin string) Signer {
+	return keycardPINSigner{signer: s, pin: pin}
+}
+
+type keycardPINSigner struct {
+	signer *keycardSigner
+	pin    string
+}
+
+// SignTx implements Signer.
+func (s keycardPINSigner) SignTx(ctx context.Context, account common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.NewEIP155Signer(chainID)
+	sig, err := s.signer.SignHash(ctx, account, s.pin, signer.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
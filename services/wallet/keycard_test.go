@@ -0,0 +1,231 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/services/typeddata"
+	"github.com/status-im/status-go/transactions"
+)
+
+type keycardTestCard struct {
+	uid     string
+	pin     string
+	retries int
+	keys    map[string]*ecdsa.PrivateKey
+	secure  bool
+}
+
+func newKeycardTestCard(t *testing.T, uid string, paths ...string) *keycardTestCard {
+	c := &keycardTestCard{uid: uid, pin: "123456", retries: 3, keys: map[string]*ecdsa.PrivateKey{}}
+	for _, path := range paths {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		c.keys[path] = key
+	}
+	return c
+}
+
+func (c *keycardTestCard) InstanceUID() string { return c.uid }
+
+func (c *keycardTestCard) Pair(ctx context.Context, pairingPassword string) (KeycardPairing, error) {
+	if pairingPassword != "KeycardTest" {
+		return KeycardPairing{}, errors.New("invalid pairing password")
+	}
+	return KeycardPairing{Key: hexutil.Bytes{1, 2, 3}, Index: 1, PINRetries: c.retries}, nil
+}
+
+func (c *keycardTestCard) OpenSecureChannel(ctx context.Context, pairing KeycardPairing) error {
+	if pairing.Index != 1 {
+		return errors.New("invalid pairing")
+	}
+	c.secure = true
+	return nil
+}
+
+func (c *keycardTestCard) VerifyPIN(ctx context.Context, pin string) (bool, int, error) {
+	if !c.secure {
+		return false, 0, errors.New("secure channel is not opened")
+	}
+	if c.retries == 0 {
+		return false, 0, nil
+	}
+	if pin != c.pin {
+		c.retries--
+		return false, c.retries, nil
+	}
+	c.retries = 3
+	return true, c.retries, nil
+}
+
+func (c *keycardTestCard) Derive(ctx context.Context, path accounts.DerivationPath) (common.Address, error) {
+	key, exist := c.keys[path.String()]
+	if !exist {
+		return common.Address{}, errors.New("unknown path")
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+func (c *keycardTestCard) Sign(ctx context.Context, path accounts.DerivationPath, hash common.Hash) ([]byte, error) {
+	return crypto.Sign(hash.Bytes(), c.keys[path.String()])
+}
+
+func newTestKeycardSigner(t *testing.T, timeout time.Duration) (*keycardSigner, chan KeycardEvent, func()) {
+	db, stop := setupTestDB(t)
+	events := make(chan KeycardEvent, 10)
+	signer := newKeycardSigner(db, timeout)
+	signer.notify = func(event KeycardEvent) {
+		events <- event
+	}
+	return signer, events, stop
+}
+
+func TestKeycardSignerSendTransaction(t *testing.T) {
+	signer, events, stop := newTestKeycardSigner(t, time.Minute)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	card := newKeycardTestCard(t, "card1", path)
+
+	// pairing waits for the card to be tapped
+	go func() {
+		require.Equal(t, KeycardEvent{Type: KeycardInsertRequired}, <-events)
+		signer.Connect(card)
+	}()
+	pairing, err := signer.Pair(context.Background(), "KeycardTest")
+	require.NoError(t, err)
+	require.Equal(t, KeycardEvent{Type: KeycardConnected, InstanceUID: "card1"}, <-events)
+	require.Equal(t, "card1", pairing.InstanceUID)
+	require.Equal(t, 3, pairing.PINRetries)
+
+	account, err := signer.AddAccount(context.Background(), "card1", "123456", path)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(card.keys[path].PublicKey), account.Address)
+	accounts, err := signer.db.GetKeycardAccounts()
+	require.NoError(t, err)
+	require.Equal(t, []KeycardAccount{account}, accounts)
+
+	chainID := big.NewInt(1777)
+	transactor := &signerTestTransactor{chainID: chainID}
+	to := statustypes.Address{1}
+	args := transactions.SendTxArgs{
+		From:  statustypes.Address(account.Address),
+		To:    &to,
+		Value: (*hexutil.Big)(big.NewInt(100)),
+	}
+	hash, err := SendTransaction(context.Background(), transactor, signer.WithPIN("123456"), chainID, args)
+	require.NoError(t, err)
+	require.Equal(t, transactor.sent.Hash(), hash)
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), transactor.sent)
+	require.NoError(t, err)
+	require.Equal(t, account.Address, sender)
+	require.Equal(t, KeycardEvent{Type: KeycardSigning, InstanceUID: "card1", Account: &account.Address}, <-events)
+	require.Equal(t, KeycardEvent{Type: KeycardSigned, InstanceUID: "card1", Account: &account.Address}, <-events)
+
+	_, err = SendTransaction(context.Background(), transactor, signer.WithPIN("123456"), chainID, transactions.SendTxArgs{From: statustypes.Address{2}, To: &to})
+	require.Equal(t, ErrNotKeycardAccount, err)
+
+	require.NoError(t, signer.Unpair("card1"))
+	_, err = SendTransaction(context.Background(), transactor, signer.WithPIN("123456"), chainID, args)
+	require.Equal(t, ErrNotKeycardAccount, err)
+}
+
+func TestKeycardSignerPINAttempts(t *testing.T) {
+	signer, events, stop := newTestKeycardSigner(t, time.Minute)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	card := newKeycardTestCard(t, "card1", path)
+	signer.Connect(card)
+	<-events
+	_, err := signer.Pair(context.Background(), "KeycardTest")
+	require.NoError(t, err)
+	account, err := signer.AddAccount(context.Background(), "card1", "123456", path)
+	require.NoError(t, err)
+
+	hash := common.Hash{1}
+	_, err = signer.SignHash(context.Background(), account.Address, "000000", hash)
+	require.Equal(t, ErrKeycardWrongPIN, err)
+	require.Equal(t, KeycardEvent{Type: KeycardWrongPIN, InstanceUID: "card1", Account: &account.Address, RemainingAttempts: 2}, <-events)
+	pairing, err := signer.db.GetKeycardPairing("card1")
+	require.NoError(t, err)
+	require.Equal(t, 2, pairing.PINRetries)
+
+	// a correct PIN resets attempts
+	_, err = signer.SignHash(context.Background(), account.Address, "123456", hash)
+	require.NoError(t, err)
+	<-events
+	<-events
+	pairing, err = signer.db.GetKeycardPairing("card1")
+	require.NoError(t, err)
+	require.Equal(t, 3, pairing.PINRetries)
+
+	for i := 2; i > 0; i-- {
+		_, err = signer.SignHash(context.Background(), account.Address, "000000", hash)
+		require.Equal(t, ErrKeycardWrongPIN, err)
+		require.Equal(t, i, (<-events).RemainingAttempts)
+	}
+	_, err = signer.SignHash(context.Background(), account.Address, "000000", hash)
+	require.Equal(t, ErrKeycardBlocked, err)
+	require.Equal(t, KeycardEvent{Type: KeycardBlocked, InstanceUID: "card1", Account: &account.Address}, <-events)
+
+	// a blocked card is not used again
+	_, err = signer.SignHash(context.Background(), account.Address, "123456", hash)
+	require.Equal(t, ErrKeycardBlocked, err)
+	require.Len(t, events, 0)
+}
+
+func TestKeycardSignerNotConnected(t *testing.T) {
+	signer, events, stop := newTestKeycardSigner(t, 10*time.Millisecond)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	signer.Connect(newKeycardTestCard(t, "card1", path))
+	<-events
+	_, err := signer.Pair(context.Background(), "KeycardTest")
+	require.NoError(t, err)
+	account, err := signer.AddAccount(context.Background(), "card1", "123456", path)
+	require.NoError(t, err)
+
+	// another card is tapped
+	signer.Connect(newKeycardTestCard(t, "card2", path))
+	<-events
+	_, err = signer.SignHash(context.Background(), account.Address, "123456", common.Hash{1})
+	require.Equal(t, ErrKeycardNotConnected, err)
+	require.Equal(t, KeycardEvent{Type: KeycardInsertRequired, InstanceUID: "card1", Account: &account.Address}, <-events)
+
+	signer.Disconnect()
+	_, err = signer.Pair(context.Background(), "KeycardTest")
+	require.Equal(t, ErrKeycardNotConnected, err)
+}
+
+func TestKeycardSignerSignTypedData(t *testing.T) {
+	signer, events, stop := newTestKeycardSigner(t, time.Minute)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	card := newKeycardTestCard(t, "card1", path)
+	signer.Connect(card)
+	<-events
+	_, err := signer.Pair(context.Background(), "KeycardTest")
+	require.NoError(t, err)
+	account, err := signer.AddAccount(context.Background(), "card1", "123456", path)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(1)
+	typed := testTypedData()
+	sig, err := signer.SignTypedData(context.Background(), typed, chainID, account.Address, "123456")
+	require.NoError(t, err)
+	expected, err := typeddata.Sign(typed, card.keys[path], chainID)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+}
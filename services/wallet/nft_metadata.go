@@ -0,0 +1,413 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	nftMetadataRequestTimeout = 10 * time.Second
+	// nftMetadataRetryInterval is how long a failed fetch is returned before it is retried.
+	nftMetadataRetryInterval = time.Hour
+	// maxNFTMetadataSize is a maximum size of a metadata document in bytes.
+	maxNFTMetadataSize = 256 * 1024
+
+	maxNFTNameLength        = 256
+	maxNFTDescriptionLength = 4096
+	maxNFTURILength         = 2048
+	maxNFTAttributes        = 100
+
+	// NFTMetadataFetched is a status of metadata that was fetched and validated.
+	NFTMetadataFetched = "fetched"
+	// NFTMetadataFailed is a status of metadata that couldn't be fetched from any gateway
+	// or was invalid. Clients show a placeholder, the fetch is retried later.
+	NFTMetadataFailed = "failed"
+)
+
+// nftMetadataABI describes erc721 tokenURI and erc1155 uri methods.
+const nftMetadataABI = `[
+{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI","outputs":[{"name":"","type":"string"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"uri","outputs":[{"name":"","type":"string"}],"type":"function"}
+]`
+
+var (
+	nftMetadata abi.ABI
+
+	defaultIPFSGateways    = []string{"https://ipfs.io/ipfs/", "https://cloudflare-ipfs.com/ipfs/"}
+	defaultArweaveGateways = []string{"https://arweave.net/"}
+
+	errNFTMetadataFailed   = errors.New("nft metadata request failed")
+	errUnsupportedTokenURI = errors.New("unsupported token uri")
+	errInvalidNFTMetadata  = errors.New("invalid nft metadata")
+)
+
+func init() {
+	var err error
+	nftMetadata, err = abi.JSON(strings.NewReader(nftMetadataABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// NFTAttribute is a trait of a collectible. Values of other types than strings are kept as JSON.
+type NFTAttribute struct {
+	TraitType string `json:"traitType"`
+	Value     string `json:"value"`
+}
+
+// NFTMetadata is sanitized metadata of a collectible and the status of its fetch.
+type NFTMetadata struct {
+	Contract common.Address `json:"contract"`
+	TokenID  *hexutil.Big   `json:"tokenId"`
+	TokenURI string         `json:"tokenUri"`
+
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Image is the image URI from the metadata, ImageURL resolves ipfs and arweave URIs with the first gateway.
+	Image       string         `json:"image"`
+	ImageURL    string         `json:"imageUrl"`
+	ExternalURL string         `json:"externalUrl"`
+	Attributes  []NFTAttribute `json:"attributes"`
+
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Attempts is a number of consecutive failed fetches.
+	Attempts  int   `json:"attempts"`
+	FetchedAt int64 `json:"fetchedAt"`
+}
+
+// nftMetadataFetcher fetches metadata documents, ipfs and arweave URIs are tried with gateways in order.
+type nftMetadataFetcher struct {
+	client          *http.Client
+	ipfsGateways    []string
+	arweaveGateways []string
+}
+
+func newNFTMetadataFetcher(ipfsGateways, arweaveGateways []string) *nftMetadataFetcher {
+	if len(ipfsGateways) == 0 {
+		ipfsGateways = defaultIPFSGateways
+	}
+	if len(arweaveGateways) == 0 {
+		arweaveGateways = defaultArweaveGateways
+	}
+	return &nftMetadataFetcher{
+		client:          &http.Client{Timeout: nftMetadataRequestTimeout},
+		ipfsGateways:    ipfsGateways,
+		arweaveGateways: arweaveGateways,
+	}
+}
+
+func withGateways(gateways []string, path string) []string {
+	rst := make([]string, len(gateways))
+	for i, gateway := range gateways {
+		rst[i] = strings.TrimRight(gateway, "/") + "/" + path
+	}
+	return rst
+}
+
+// gatewayURLs returns URLs to fetch the URI from in order. Gateway URLs of ipfs
+// content are followed by configured gateways.
+func (f *nftMetadataFetcher) gatewayURLs(uri string) ([]string, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return withGateways(f.ipfsGateways, path), nil
+	case strings.HasPrefix(uri, "ar://"):
+		return withGateways(f.arweaveGateways, strings.TrimPrefix(uri, "ar://")), nil
+	case strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://"):
+		rst := []string{uri}
+		if i := strings.Index(uri, "/ipfs/"); i >= 0 {
+			rst = append(rst, withGateways(f.ipfsGateways, uri[i+len("/ipfs/"):])...)
+		}
+		return rst, nil
+	}
+	return nil, errUnsupportedTokenURI
+}
+
+// Fetch returns a metadata document of the URI. Data URIs are decoded, other URIs
+// are fetched from gateways in order until one returns a valid document.
+func (f *nftMetadataFetcher) Fetch(ctx context.Context, uri string) (NFTMetadata, error) {
+	if strings.HasPrefix(uri, "data:") {
+		data, err := decodeDataURI(uri)
+		if err != nil {
+			return NFTMetadata{}, err
+		}
+		return parseNFTMetadata(data)
+	}
+	urls, err := f.gatewayURLs(uri)
+	if err != nil {
+		return NFTMetadata{}, err
+	}
+	for _, u := range urls {
+		var data []byte
+		data, err = f.get(ctx, u)
+		if err == nil {
+			var metadata NFTMetadata
+			metadata, err = parseNFTMetadata(data)
+			if err == nil {
+				return metadata, nil
+			}
+		}
+		log.Debug("failed to fetch nft metadata", "url", u, "error", err)
+	}
+	return NFTMetadata{}, err
+}
+
+func (f *nftMetadataFetcher) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v: unexpected status %d", errNFTMetadataFailed, resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxNFTMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxNFTMetadataSize {
+		return nil, fmt.Errorf("%v: document is too large", errInvalidNFTMetadata)
+	}
+	return data, nil
+}
+
+// decodeDataURI decodes JSON documents embedded in token URIs, e.g. data:application/json;base64,...
+func decodeDataURI(uri string) ([]byte, error) {
+	i := strings.Index(uri, ",")
+	if i < 0 {
+		return nil, errUnsupportedTokenURI
+	}
+	header, payload := uri[len("data:"):i], uri[i+1:]
+	if !strings.HasPrefix(header, "application/json") {
+		return nil, errUnsupportedTokenURI
+	}
+	if strings.HasSuffix(header, ";base64") {
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	decoded, err := url.PathUnescape(payload)
+	return []byte(decoded), err
+}
+
+// parseNFTMetadata validates a metadata document and sanitizes its fields. Texts are stripped
+// of control characters and truncated, URIs with unsupported schemes are dropped.
+func parseNFTMetadata(data []byte) (NFTMetadata, error) {
+	var doc struct {
+		Name        json.RawMessage `json:"name"`
+		Description json.RawMessage `json:"description"`
+		Image       json.RawMessage `json:"image"`
+		ImageURL    json.RawMessage `json:"image_url"`
+		ExternalURL json.RawMessage `json:"external_url"`
+		Attributes  json.RawMessage `json:"attributes"`
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return NFTMetadata{}, fmt.Errorf("%v: not a JSON object", errInvalidNFTMetadata)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return NFTMetadata{}, fmt.Errorf("%v: %v", errInvalidNFTMetadata, err)
+	}
+	rst := NFTMetadata{
+		Name:        sanitizeText(jsonString(doc.Name), maxNFTNameLength),
+		Description: sanitizeText(jsonString(doc.Description), maxNFTDescriptionLength),
+		Image:       sanitizeURI(jsonString(doc.Image)),
+		ExternalURL: sanitizeURI(jsonString(doc.ExternalURL)),
+		Attributes:  []NFTAttribute{},
+	}
+	if rst.Image == "" {
+		rst.Image = sanitizeURI(jsonString(doc.ImageURL))
+	}
+	if rst.Name == "" && rst.Image == "" {
+		return NFTMetadata{}, fmt.Errorf("%v: neither name nor image", errInvalidNFTMetadata)
+	}
+	// attributes in other formats are ignored
+	var attributes []json.RawMessage
+	_ = json.Unmarshal(doc.Attributes, &attributes)
+	for _, raw := range attributes {
+		if len(rst.Attributes) == maxNFTAttributes {
+			break
+		}
+		var attr struct {
+			TraitType json.RawMessage `json:"trait_type"`
+			Value     json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &attr); err != nil || len(attr.Value) == 0 {
+			continue
+		}
+		rst.Attributes = append(rst.Attributes, NFTAttribute{
+			TraitType: sanitizeText(jsonString(attr.TraitType), maxNFTNameLength),
+			Value:     sanitizeText(jsonString(attr.Value), maxNFTNameLength),
+		})
+	}
+	return rst, nil
+}
+
+// jsonString returns a JSON string value, other values are returned as JSON.
+func jsonString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func sanitizeText(s string, maxLength int) string {
+	s = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || (unicode.IsControl(r) && r != '\n' && r != '\t') {
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.TrimSpace(s)
+	if len(s) <= maxLength {
+		return s
+	}
+	// truncate at a rune boundary
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength]
+}
+
+// sanitizeURI keeps http(s), ipfs and arweave URIs and embedded raster images.
+func sanitizeURI(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if len(uri) > maxNFTURILength && !strings.HasPrefix(uri, "data:") {
+		return ""
+	}
+	for _, prefix := range []string{"https://", "http://", "ipfs://", "ar://", "data:image/png;", "data:image/jpeg;", "data:image/gif;", "data:image/webp;"} {
+		if strings.HasPrefix(uri, prefix) {
+			return uri
+		}
+	}
+	return ""
+}
+
+// resolveURI returns a URL of ipfs and arweave URIs with the first gateway.
+func (f *nftMetadataFetcher) resolveURI(uri string) string {
+	if strings.HasPrefix(uri, "ipfs://") || strings.HasPrefix(uri, "ar://") {
+		urls, err := f.gatewayURLs(uri)
+		if err == nil && len(urls) > 0 {
+			return urls[0]
+		}
+	}
+	return uri
+}
+
+// FetchTokenURI reads the erc721 tokenURI of the token, erc1155 uri is used if it fails.
+// The {id} placeholder of erc1155 URIs is replaced with the hex token ID.
+func FetchTokenURI(ctx context.Context, client ethereum.ContractCaller, contract common.Address, tokenID *big.Int) (string, error) {
+	uri, err := callTokenURIMethod(ctx, client, contract, "tokenURI", tokenID)
+	if err == nil && uri != "" {
+		return uri, nil
+	}
+	uri, err = callTokenURIMethod(ctx, client, contract, "uri", tokenID)
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(uri, "{id}", fmt.Sprintf("%064x", tokenID), -1), nil
+}
+
+func callTokenURIMethod(ctx context.Context, client ethereum.ContractCaller, contract common.Address, method string, tokenID *big.Int) (string, error) {
+	input, err := nftMetadata.Pack(method, tokenID)
+	if err != nil {
+		return "", err
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: input}, nil)
+	if err != nil {
+		return "", err
+	}
+	var rst string
+	if err := nftMetadata.Unpack(&rst, method, output); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(rst), nil
+}
+
+// nftMetadataCache returns metadata of collectibles cached in the database. Metadata is fetched
+// once, failed fetches are retried after nftMetadataRetryInterval.
+type nftMetadataCache struct {
+	db      *Database
+	fetcher *nftMetadataFetcher
+	now     func() time.Time
+
+	mu sync.Mutex
+}
+
+func newNFTMetadataCache(db *Database, fetcher *nftMetadataFetcher) *nftMetadataCache {
+	return &nftMetadataCache{db: db, fetcher: fetcher, now: time.Now}
+}
+
+// Get returns cached metadata of the token or fetches it. Failures are returned as metadata
+// with the failed status, errors are returned only if the database fails.
+func (c *nftMetadataCache) Get(ctx context.Context, client ethereum.ContractCaller, contract common.Address, tokenID *big.Int) (*NFTMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, err := c.db.GetNFTMetadata(contract, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	now := c.now()
+	if cached != nil && (cached.Status == NFTMetadataFetched || now.Sub(time.Unix(cached.FetchedAt, 0)) < nftMetadataRetryInterval) {
+		return cached, nil
+	}
+
+	var tokenURI string
+	if cached != nil {
+		tokenURI = cached.TokenURI
+	}
+	if tokenURI == "" {
+		callCtx, cancel := context.WithTimeout(ctx, nftMetadataRequestTimeout)
+		tokenURI, err = FetchTokenURI(callCtx, client, contract, tokenID)
+		cancel()
+	}
+	var metadata NFTMetadata
+	if err == nil {
+		metadata, err = c.fetcher.Fetch(ctx, tokenURI)
+	}
+	if err != nil {
+		log.Warn("failed to fetch nft metadata", "contract", contract, "tokenID", tokenID, "uri", tokenURI, "error", err)
+		metadata = NFTMetadata{Status: NFTMetadataFailed, Error: err.Error(), Attempts: 1, Attributes: []NFTAttribute{}}
+		if cached != nil {
+			metadata.Attempts = cached.Attempts + 1
+		}
+	} else {
+		metadata.Status = NFTMetadataFetched
+		metadata.ImageURL = c.fetcher.resolveURI(metadata.Image)
+	}
+	metadata.Contract = contract
+	metadata.TokenID = (*hexutil.Big)(tokenID)
+	metadata.TokenURI = tokenURI
+	metadata.FetchedAt = now.Unix()
+	if err := c.db.SaveNFTMetadata(metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,161 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type nftTestClient struct {
+	// token URIs indexed by method name
+	uris map[string]string
+}
+
+func (c *nftTestClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := nftMetadata.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	uri, exist := c.uris[method.Name]
+	if !exist {
+		return nil, errors.New("execution reverted")
+	}
+	return method.Outputs.Pack(uri)
+}
+
+func TestFetchTokenURI(t *testing.T) {
+	uri, err := FetchTokenURI(context.Background(), &nftTestClient{uris: map[string]string{"tokenURI": "ipfs://QmToken/1"}}, common.Address{1}, big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://QmToken/1", uri)
+
+	uri, err = FetchTokenURI(context.Background(), &nftTestClient{uris: map[string]string{"uri": "https://example.com/{id}.json"}}, common.Address{1}, big.NewInt(255))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/"+strings.Repeat("0", 62)+"ff.json", uri)
+
+	_, err = FetchTokenURI(context.Background(), &nftTestClient{}, common.Address{1}, big.NewInt(1))
+	require.Error(t, err)
+}
+
+func TestParseNFTMetadata(t *testing.T) {
+	metadata, err := parseNFTMetadata([]byte(`{
+		"name": " Kitty\u0000 #1 ",
+		"description": "` + strings.Repeat("a", maxNFTDescriptionLength+10) + `",
+		"image": "javascript:alert(1)",
+		"image_url": "ipfs://QmImage",
+		"external_url": "https://example.com/1",
+		"attributes": [{"trait_type": "color", "value": "red"}, {"trait_type": "level", "value": 5}, {"trait_type": "empty"}]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, "Kitty #1", metadata.Name)
+	require.Len(t, metadata.Description, maxNFTDescriptionLength)
+	require.Equal(t, "ipfs://QmImage", metadata.Image)
+	require.Equal(t, "https://example.com/1", metadata.ExternalURL)
+	require.Equal(t, []NFTAttribute{{TraitType: "color", Value: "red"}, {TraitType: "level", Value: "5"}}, metadata.Attributes)
+
+	_, err = parseNFTMetadata([]byte(`[]`))
+	require.Error(t, err)
+	_, err = parseNFTMetadata([]byte(`{"description": "no name and image"}`))
+	require.Error(t, err)
+
+	require.Equal(t, "", sanitizeURI("data:image/svg+xml;base64,PHN2Zz4="))
+	require.Equal(t, "ab", sanitizeText("abé", 3))
+}
+
+func TestNFTMetadataGatewayFallback(t *testing.T) {
+	var requests []string
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "failing"+r.URL.Path)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer failing.Close()
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "invalid"+r.URL.Path)
+		fmt.Fprint(w, `<html>rate limited</html>`)
+	}))
+	defer invalid.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "working"+r.URL.Path)
+		fmt.Fprint(w, `{"name":"Kitty","image":"ipfs://QmImage"}`)
+	}))
+	defer working.Close()
+
+	fetcher := newNFTMetadataFetcher([]string{failing.URL + "/ipfs/", invalid.URL + "/ipfs", working.URL + "/ipfs/"}, nil)
+	metadata, err := fetcher.Fetch(context.Background(), "ipfs://ipfs/QmToken/1")
+	require.NoError(t, err)
+	require.Equal(t, "Kitty", metadata.Name)
+	require.Equal(t, []string{"failing/ipfs/QmToken/1", "invalid/ipfs/QmToken/1", "working/ipfs/QmToken/1"}, requests)
+	require.Equal(t, failing.URL+"/ipfs/QmImage", fetcher.resolveURI(metadata.Image))
+
+	urls, err := fetcher.gatewayURLs("ar://tx")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://arweave.net/tx"}, urls)
+	_, err = fetcher.gatewayURLs("ftp://example.com/1")
+	require.Equal(t, errUnsupportedTokenURI, err)
+
+	metadata, err = fetcher.Fetch(context.Background(), "data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiJ9")
+	require.NoError(t, err)
+	require.Equal(t, "Onchain", metadata.Name)
+}
+
+func TestNFTMetadataCache(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	available := false
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"name":"Kitty","image":"ipfs://QmImage"}`)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1000, 0)
+	cache := newNFTMetadataCache(db, newNFTMetadataFetcher([]string{server.URL}, nil))
+	cache.now = func() time.Time { return now }
+	client := &nftTestClient{uris: map[string]string{"tokenURI": "ipfs://QmToken/1"}}
+	contract, tokenID := common.Address{1}, big.NewInt(1)
+
+	metadata, err := cache.Get(context.Background(), client, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, NFTMetadataFailed, metadata.Status)
+	require.Equal(t, 1, metadata.Attempts)
+	require.NotEmpty(t, metadata.Error)
+
+	// failures are cached until the retry interval passes
+	available = true
+	metadata, err = cache.Get(context.Background(), client, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, NFTMetadataFailed, metadata.Status)
+	require.Equal(t, 1, calls)
+
+	now = now.Add(nftMetadataRetryInterval)
+	metadata, err = cache.Get(context.Background(), client, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, NFTMetadataFetched, metadata.Status)
+	require.Equal(t, "Kitty", metadata.Name)
+	require.Equal(t, server.URL+"/QmImage", metadata.ImageURL)
+	require.Equal(t, "ipfs://QmToken/1", metadata.TokenURI)
+	require.Equal(t, 2, calls)
+
+	// fetched metadata is returned from the database
+	cache = newNFTMetadataCache(db, newNFTMetadataFetcher([]string{server.URL}, nil))
+	cached, err := cache.Get(context.Background(), client, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, metadata, cached)
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	onRampSourceRequestTimeout = 10 * time.Second
+	// onRampProvidersCacheTTL is how long cached providers are returned before they are fetched again.
+	onRampProvidersCacheTTL = time.Hour
+)
+
+var errOnRampSourceFailed = errors.New("on-ramp source request failed")
+
+// CryptoOnRamp describes a service that sells crypto assets for fiat.
+type CryptoOnRamp struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Fees is a human readable description of fees, e.g. "0.49%-2.9%".
+	Fees string `json:"fees"`
+	Logo string `json:"logoUrl"`
+	// SiteURL is a landing page of the provider.
+	SiteURL string `json:"siteUrl"`
+	// URLTemplate is a URL to buy assets, clients replace {address} and {asset} placeholders.
+	URLTemplate     string   `json:"urlTemplate"`
+	SupportedAssets []string `json:"supportedAssets"`
+}
+
+func (p CryptoOnRamp) validate() error {
+	if p.Name == "" {
+		return errors.New("name is empty")
+	}
+	parsed, err := url.Parse(p.URLTemplate)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("url template must use https: %s", p.URLTemplate)
+	}
+	return nil
+}
+
+// OnRampSource is an external source of on-ramp providers.
+type OnRampSource interface {
+	CryptoOnRamps(ctx context.Context) ([]CryptoOnRamp, error)
+}
+
+// NewHTTPOnRampSource returns a source that reads a JSON list of provider descriptors from the URL.
+func NewHTTPOnRampSource(endpoint string) *HTTPOnRampSource {
+	return &HTTPOnRampSource{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: onRampSourceRequestTimeout},
+	}
+}
+
+// HTTPOnRampSource fetches a JSON list of provider descriptors.
+type HTTPOnRampSource struct {
+	endpoint string
+	client   *http.Client
+}
+
+// CryptoOnRamps fetches descriptors of providers.
+func (s *HTTPOnRampSource) CryptoOnRamps(ctx context.Context) ([]CryptoOnRamp, error) {
+	req, err := http.NewRequest(http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v: unexpected status %d", errOnRampSourceFailed, resp.StatusCode)
+	}
+	var providers []CryptoOnRamp
+	if err := json.NewDecoder(resp.Body).Decode(&providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// cryptoOnRamps returns providers fetched from the source and caches them in the database.
+type cryptoOnRamps struct {
+	db     *Database
+	source OnRampSource
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+func newCryptoOnRamps(db *Database, source OnRampSource) *cryptoOnRamps {
+	return &cryptoOnRamps{db: db, source: source, now: time.Now}
+}
+
+// Get returns cached providers if they were fetched recently. Otherwise providers are
+// fetched again; if the source fails, the cached providers are returned regardless of their age.
+// Invalid descriptors are skipped.
+func (o *cryptoOnRamps) Get(ctx context.Context) ([]CryptoOnRamp, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	cached, fetchedAt, err := o.db.GetCryptoOnRamps()
+	if err != nil {
+		return nil, err
+	}
+	now := o.now()
+	if fetchedAt != 0 && now.Sub(time.Unix(fetchedAt, 0)) < onRampProvidersCacheTTL {
+		return cached, nil
+	}
+	if o.source == nil {
+		if fetchedAt != 0 {
+			return cached, nil
+		}
+		return nil, ErrServiceNotInitialized
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, onRampSourceRequestTimeout)
+	fetched, err := o.source.CryptoOnRamps(callCtx)
+	cancel()
+	if err != nil {
+		if fetchedAt != 0 {
+			log.Warn("failed to fetch on-ramp providers, using cached", "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	providers := make([]CryptoOnRamp, 0, len(fetched))
+	seen := map[string]bool{}
+	for _, provider := range fetched {
+		if err := provider.validate(); err != nil {
+			log.Warn("skipping invalid on-ramp provider", "name", provider.Name, "error", err)
+			continue
+		}
+		if seen[provider.Name] {
+			continue
+		}
+		seen[provider.Name] = true
+		providers = append(providers, provider)
+	}
+	if err := o.db.SaveCryptoOnRamps(providers, now.Unix()); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoOnRampsCache(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	calls := 0
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"name":"Ramp","fees":"0.49%-2.9%","urlTemplate":"https://buy.ramp.network/?userAddress={address}&swapAsset={asset}","supportedAssets":["ETH","DAI"]},
+			{"name":"Insecure","urlTemplate":"http://example.com/{address}"},
+			{"name":"","urlTemplate":"https://example.com"},
+			{"name":"Wyre","fees":"2.9%","urlTemplate":"https://pay.sendwyre.com/?dest={address}","supportedAssets":["ETH"]}
+		]`)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1000, 0)
+	onRamps := newCryptoOnRamps(db, NewHTTPOnRampSource(server.URL))
+	onRamps.now = func() time.Time { return now }
+
+	providers, err := onRamps.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	require.Equal(t, "Ramp", providers[0].Name)
+	require.Equal(t, []string{"ETH", "DAI"}, providers[0].SupportedAssets)
+	require.Equal(t, "Wyre", providers[1].Name)
+	require.Equal(t, 1, calls)
+
+	// cached providers are returned until they expire
+	providers, err = onRamps.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	require.Equal(t, 1, calls)
+
+	// expired cache is used if the source is unavailable
+	now = now.Add(onRampProvidersCacheTTL)
+	available = false
+	providers, err = onRamps.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	require.Equal(t, 2, calls)
+
+	// without a cache the error is returned
+	require.NoError(t, db.SaveCryptoOnRamps(nil, 0))
+	_, err = onRamps.Get(context.Background())
+	require.Error(t, err)
+}
+
+func TestCryptoOnRampsNoSource(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	onRamps := newCryptoOnRamps(db, nil)
+	_, err := onRamps.Get(context.Background())
+	require.Equal(t, ErrServiceNotInitialized, err)
+
+	require.NoError(t, db.SaveCryptoOnRamps([]CryptoOnRamp{{Name: "Ramp", URLTemplate: "https://buy.ramp.network"}}, 1))
+	providers, err := onRamps.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+}
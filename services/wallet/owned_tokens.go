@@ -0,0 +1,214 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+// ownedTokensCheckPeriod is how often balances of discovered tokens are checked again.
+const ownedTokensCheckPeriod = 10 * time.Minute
+
+// popularTokens are probed on every discovery in addition to tokens found in transfers.
+var popularTokens = map[uint64][]common.Address{
+	1: {
+		common.HexToAddress("0x744d70FDBE2Ba4CF95131626614a1763DF805B9E"), // SNT
+		common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), // DAI
+		common.HexToAddress("0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359"), // SAI
+		common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), // USDC
+		common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), // USDT
+		common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), // WETH
+		common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA"), // LINK
+		common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"), // MKR
+		common.HexToAddress("0x0D8775F648430679A709E98d2b0Cb6250d2887EF"), // BAT
+		common.HexToAddress("0xE41d2489571d322189246DaFA5ebDe1F4699F498"), // ZRX
+		common.HexToAddress("0x1985365e9f78359a9B6AD760e32412f4a445E862"), // REP
+		common.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07"), // OMG
+	},
+	3: {
+		common.HexToAddress("0xc55cF4B03948D7EBc8b9E8BAD92643703811d162"), // STT
+	},
+}
+
+// OwnedToken is an erc20 token with a non-zero balance.
+type OwnedToken struct {
+	Address common.Address `json:"address"`
+	Balance *hexutil.Big   `json:"balance"`
+	// CheckedAt is a unix timestamp of the last balance check.
+	CheckedAt int64 `json:"checkedAt"`
+	// Metadata is nil if it couldn't be read from the contract.
+	Metadata *Token `json:"metadata,omitempty"`
+}
+
+// TokenBalanceReader reads erc20 balances.
+type TokenBalanceReader interface {
+	TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error)
+}
+
+type chainTokenBalanceReader struct {
+	backend bind.ContractCaller
+}
+
+func (r chainTokenBalanceReader) TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	caller, err := ierc20.NewIERC20Caller(token, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	return caller.BalanceOf(&bind.CallOpts{Context: ctx}, account)
+}
+
+// tokenDiscovery finds tokens owned by addresses and keeps their balances up to date.
+type tokenDiscovery struct {
+	db      *Database
+	popular []common.Address
+	now     func() time.Time
+
+	mu        sync.Mutex
+	reader    TokenBalanceReader
+	addresses map[common.Address]struct{}
+}
+
+func newTokenDiscovery(db *Database) *tokenDiscovery {
+	return &tokenDiscovery{
+		db:        db,
+		popular:   popularTokens[db.network],
+		now:       time.Now,
+		addresses: map[common.Address]struct{}{},
+	}
+}
+
+// SetReader sets a reader of token balances. Tokens are not discovered until it is set.
+func (d *tokenDiscovery) SetReader(reader TokenBalanceReader) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reader = reader
+}
+
+func (d *tokenDiscovery) getReader() TokenBalanceReader {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reader
+}
+
+// Owned returns tokens with a non-zero balance of the address. Tokens of an address are
+// discovered on the first request and the address is checked periodically afterwards.
+func (d *tokenDiscovery) Owned(ctx context.Context, address common.Address) ([]OwnedToken, error) {
+	d.mu.Lock()
+	_, known := d.addresses[address]
+	d.mu.Unlock()
+	if !known {
+		if err := d.Discover(ctx, address); err != nil {
+			return nil, err
+		}
+		d.Add(address)
+	}
+	return d.db.GetOwnedTokens(address)
+}
+
+// Add adds an address that is checked periodically.
+func (d *tokenDiscovery) Add(address common.Address) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.addresses[address] = struct{}{}
+}
+
+// Discover checks balances of tokens the address transferred, of popular and custom tokens
+// and of previously discovered tokens. Non-zero balances are cached, zero balances are removed.
+// Tokens that failed to respond keep their cached balances.
+func (d *tokenDiscovery) Discover(ctx context.Context, address common.Address) error {
+	reader := d.getReader()
+	if reader == nil {
+		return ErrServiceNotInitialized
+	}
+	candidates, err := d.candidates(address)
+	if err != nil {
+		return err
+	}
+	for _, token := range candidates {
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		balance, err := reader.TokenBalance(callCtx, token, address)
+		cancel()
+		if err != nil {
+			log.Warn("failed to check token balance", "token", token, "address", address, "error", err)
+			continue
+		}
+		if balance.Sign() == 0 {
+			err = d.db.DeleteOwnedToken(address, token)
+		} else {
+			err = d.db.SaveOwnedToken(address, OwnedToken{
+				Address:   token,
+				Balance:   (*hexutil.Big)(balance),
+				CheckedAt: d.now().Unix(),
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *tokenDiscovery) candidates(address common.Address) ([]common.Address, error) {
+	var (
+		rst  []common.Address
+		seen = map[common.Address]bool{}
+	)
+	add := func(tokens ...common.Address) {
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				rst = append(rst, token)
+			}
+		}
+	}
+	owned, err := d.db.GetOwnedTokens(address)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range owned {
+		add(token.Address)
+	}
+	transferred, err := d.db.GetTransferredTokens(address)
+	if err != nil {
+		return nil, err
+	}
+	add(transferred...)
+	add(d.popular...)
+	custom, err := d.db.GetCustomTokens()
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range custom {
+		add(token.Address)
+	}
+	return rst, nil
+}
+
+// Watch discovers tokens of added addresses every period until the context is canceled.
+func (d *tokenDiscovery) Watch(ctx context.Context, period time.Duration) error {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		d.mu.Lock()
+		addresses := mapToList(d.addresses)
+		d.mu.Unlock()
+		for _, address := range addresses {
+			if err := d.Discover(ctx, address); err != nil {
+				log.Error("failed to discover owned tokens", "address", address, "error", err)
+			}
+		}
+	}
+}
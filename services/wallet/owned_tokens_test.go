@@ -0,0 +1,88 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type staticTokenBalances map[common.Address]int64
+
+func (b staticTokenBalances) TokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	balance, exist := b[token]
+	if !exist {
+		return nil, errors.New("not a token")
+	}
+	return big.NewInt(balance), nil
+}
+
+func TestTokenDiscovery(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	address := common.Address{1}
+	transferred := common.Address{0xaa}
+	popular := common.Address{0xbb}
+	custom := common.Address{0xcc}
+	empty := common.Address{0xdd}
+	broken := common.Address{0xee}
+	saveTransfers(t, db, erc20TransferAt(1, transferred, address, common.Address{2}, 5, time.Now()))
+	require.NoError(t, db.AddCustomToken(Token{Address: custom, Name: "Custom", Symbol: "CST"}))
+
+	discovery := newTokenDiscovery(db)
+	discovery.popular = []common.Address{popular, empty, broken}
+	discovery.now = func() time.Time { return time.Unix(100, 0) }
+	_, err := discovery.Owned(context.Background(), address)
+	require.Equal(t, ErrServiceNotInitialized, err)
+
+	balances := staticTokenBalances{transferred: 10, popular: 20, custom: 30, empty: 0}
+	discovery.SetReader(balances)
+	owned, err := discovery.Owned(context.Background(), address)
+	require.NoError(t, err)
+	require.Len(t, owned, 3)
+	for _, token := range owned {
+		require.Equal(t, balances[token.Address], token.Balance.ToInt().Int64())
+		require.Equal(t, int64(100), token.CheckedAt)
+	}
+
+	// balances are not checked again until the next discovery
+	balances[transferred] = 0
+	balances[popular] = 25
+	owned, err = discovery.Owned(context.Background(), address)
+	require.NoError(t, err)
+	require.Len(t, owned, 3)
+
+	require.NoError(t, discovery.Discover(context.Background(), address))
+	owned, err = db.GetOwnedTokens(address)
+	require.NoError(t, err)
+	require.Len(t, owned, 2)
+	require.Equal(t, popular, owned[0].Address)
+	require.Equal(t, int64(25), owned[0].Balance.ToInt().Int64())
+	require.Equal(t, custom, owned[1].Address)
+}
+
+func TestTokenDiscoveryKeepsBalanceOnFailure(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	address := common.Address{1}
+	token := common.Address{0xaa}
+	discovery := newTokenDiscovery(db)
+	discovery.popular = []common.Address{token}
+	balances := staticTokenBalances{token: 10}
+	discovery.SetReader(balances)
+	require.NoError(t, discovery.Discover(context.Background(), address))
+
+	delete(balances, token)
+	require.NoError(t, discovery.Discover(context.Background(), address))
+	owned, err := db.GetOwnedTokens(address)
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	require.Equal(t, int64(10), owned[0].Balance.ToInt().Int64())
+}
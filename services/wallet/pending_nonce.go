@@ -0,0 +1,132 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/transactions"
+)
+
+const (
+	// pendingTxStuckAfter is how long a transaction is pending before a replacement is suggested.
+	pendingTxStuckAfter = 3 * time.Minute
+	// cancelTxGas is the gas of a plain transfer used to cancel a transaction.
+	cancelTxGas = 21000
+)
+
+// PendingNonce is the next nonce of an address and replacements for its stuck transactions.
+type PendingNonce struct {
+	Address common.Address `json:"address"`
+	// Nonce is the nonce of the next transaction, including transactions that are pending.
+	Nonce hexutil.Uint64 `json:"nonce"`
+	// ConfirmedNonce is the nonce of the next transaction at the latest block.
+	ConfirmedNonce hexutil.Uint64 `json:"confirmedNonce"`
+	// Pending are transactions sent by this node that are not mined yet.
+	Pending []transactions.PendingTransaction `json:"pending"`
+	Stuck   []StuckTransaction                `json:"stuck"`
+}
+
+// StuckTransaction is a pending transaction with unsigned replacements that have the same nonce
+// and a higher gas price. SpeedUp sends the same transaction, Cancel sends nothing to the sender.
+type StuckTransaction struct {
+	Hash    statustypes.Hash         `json:"hash"`
+	Nonce   hexutil.Uint64           `json:"nonce"`
+	SpeedUp *transactions.SendTxArgs `json:"speedUp"`
+	Cancel  *transactions.SendTxArgs `json:"cancel"`
+}
+
+type pendingTransactor interface {
+	NextNonce(from statustypes.Address) (uint64, error)
+	PendingTransactions(from statustypes.Address, confirmedNonce uint64) []transactions.PendingTransaction
+}
+
+type pendingNonceClient interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// GetPendingNonce returns the next nonce of the address and suggests replacements for transactions
+// that have been pending for longer than pendingTxStuckAfter. The gas price of a replacement is
+// the current suggestion or the minimal accepted bump of the stuck transaction, whichever is higher.
+func GetPendingNonce(parent context.Context, transactor pendingTransactor, client pendingNonceClient, address common.Address, now time.Time) (*PendingNonce, error) {
+	ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+	confirmed, err := client.NonceAt(ctx, address, nil)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	from := statustypes.Address(address)
+	next, err := transactor.NextNonce(from)
+	if err != nil {
+		return nil, err
+	}
+	rst := &PendingNonce{
+		Address:        address,
+		Nonce:          hexutil.Uint64(next),
+		ConfirmedNonce: hexutil.Uint64(confirmed),
+		Pending:        transactor.PendingTransactions(from, confirmed),
+		Stuck:          []StuckTransaction{},
+	}
+
+	var suggested *big.Int
+	for _, tx := range rst.Pending {
+		if now.Sub(time.Unix(tx.SentAt, 0)) < pendingTxStuckAfter {
+			continue
+		}
+		if suggested == nil {
+			ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+			suggested, err = client.SuggestGasPrice(ctx)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+		}
+		rst.Stuck = append(rst.Stuck, buildReplacements(tx, suggested))
+	}
+	return rst, nil
+}
+
+// replacementGasPrice returns the gas price of a transaction replacing a pending one.
+func replacementGasPrice(pending, suggested *big.Int) *big.Int {
+	bumped := transactions.MinReplacementGasPrice(pending)
+	if suggested.Cmp(bumped) > 0 {
+		return new(big.Int).Set(suggested)
+	}
+	return bumped
+}
+
+func buildReplacements(tx transactions.PendingTransaction, suggested *big.Int) StuckTransaction {
+	var (
+		gasPrice  = (*hexutil.Big)(replacementGasPrice(tx.GasPrice.ToInt(), suggested))
+		nonce     = tx.Nonce
+		gas       = tx.Gas
+		cancelGas = hexutil.Uint64(cancelTxGas)
+		to        = tx.From
+	)
+	return StuckTransaction{
+		Hash:  tx.Hash,
+		Nonce: tx.Nonce,
+		SpeedUp: &transactions.SendTxArgs{
+			From:     tx.From,
+			To:       tx.To,
+			Gas:      &gas,
+			GasPrice: gasPrice,
+			Value:    tx.Value,
+			Nonce:    &nonce,
+			Data:     statustypes.HexBytes(tx.Data),
+		},
+		Cancel: &transactions.SendTxArgs{
+			From:     tx.From,
+			To:       &to,
+			Gas:      &cancelGas,
+			GasPrice: gasPrice,
+			Value:    (*hexutil.Big)(big.NewInt(0)),
+			Nonce:    &nonce,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/transactions"
+)
+
+type pendingTestTransactor struct {
+	next    uint64
+	pending []transactions.PendingTransaction
+}
+
+func (t *pendingTestTransactor) NextNonce(from statustypes.Address) (uint64, error) {
+	return t.next, nil
+}
+
+func (t *pendingTestTransactor) PendingTransactions(from statustypes.Address, confirmedNonce uint64) []transactions.PendingTransaction {
+	var rst []transactions.PendingTransaction
+	for _, tx := range t.pending {
+		if uint64(tx.Nonce) >= confirmedNonce {
+			rst = append(rst, tx)
+		}
+	}
+	return rst
+}
+
+type pendingTestClient struct {
+	confirmed uint64
+	gasPrice  int64
+}
+
+func (c *pendingTestClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.confirmed, nil
+}
+
+func (c *pendingTestClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(c.gasPrice), nil
+}
+
+func TestGetPendingNonce(t *testing.T) {
+	address := common.Address{1}
+	to := statustypes.Address{2}
+	now := time.Unix(1589800000, 0)
+	pending := func(nonce uint64, gasPrice int64, sentAt time.Time) transactions.PendingTransaction {
+		return transactions.PendingTransaction{
+			Hash:     statustypes.Hash{byte(nonce)},
+			From:     statustypes.Address(address),
+			To:       &to,
+			Nonce:    hexutil.Uint64(nonce),
+			Value:    (*hexutil.Big)(big.NewInt(10)),
+			Gas:      hexutil.Uint64(50000),
+			GasPrice: (*hexutil.Big)(big.NewInt(gasPrice)),
+			Data:     hexutil.Bytes{1},
+			SentAt:   sentAt.Unix(),
+		}
+	}
+	transactor := &pendingTestTransactor{
+		next: 8,
+		pending: []transactions.PendingTransaction{
+			// mined
+			pending(4, 100, now.Add(-time.Hour)),
+			pending(5, 100, now.Add(-time.Hour)),
+			pending(6, 1000, now.Add(-5*time.Minute)),
+			// sent recently
+			pending(7, 100, now.Add(-time.Minute)),
+		},
+	}
+	client := &pendingTestClient{confirmed: 5, gasPrice: 200}
+
+	rst, err := GetPendingNonce(context.Background(), transactor, client, address, now)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(8), rst.Nonce)
+	require.Equal(t, hexutil.Uint64(5), rst.ConfirmedNonce)
+	require.Len(t, rst.Pending, 3)
+	require.Len(t, rst.Stuck, 2)
+
+	// the suggested gas price is higher than the bump
+	stuck := rst.Stuck[0]
+	require.Equal(t, hexutil.Uint64(5), stuck.Nonce)
+	require.Equal(t, int64(200), stuck.SpeedUp.GasPrice.ToInt().Int64())
+	require.Equal(t, hexutil.Uint64(5), *stuck.SpeedUp.Nonce)
+	require.Equal(t, to, *stuck.SpeedUp.To)
+	require.Equal(t, hexutil.Uint64(50000), *stuck.SpeedUp.Gas)
+	require.Equal(t, statustypes.HexBytes{1}, stuck.SpeedUp.Data)
+	require.Equal(t, statustypes.Address(address), *stuck.Cancel.To)
+	require.Equal(t, int64(0), stuck.Cancel.Value.ToInt().Int64())
+	require.Equal(t, hexutil.Uint64(cancelTxGas), *stuck.Cancel.Gas)
+	require.Equal(t, hexutil.Uint64(5), *stuck.Cancel.Nonce)
+
+	// the gas price is bumped by 10%
+	require.Equal(t, int64(1100), rst.Stuck[1].Cancel.GasPrice.ToInt().Int64())
+}
+
+func TestReplacementGasPriceRoundsUp(t *testing.T) {
+	require.Equal(t, int64(17), replacementGasPrice(big.NewInt(15), big.NewInt(1)).Int64())
+	require.Equal(t, int64(20), replacementGasPrice(big.NewInt(15), big.NewInt(20)).Int64())
+}
@@ -65,10 +65,11 @@ type reactorClient interface {
 // NewReactor creates instance of the Reactor.
 func NewReactor(db *Database, feed *event.Feed, client *ethclient.Client, chain *big.Int) *Reactor {
 	return &Reactor{
-		db:     db,
-		client: client,
-		feed:   feed,
-		chain:  chain,
+		db:      db,
+		client:  client,
+		feed:    feed,
+		chain:   chain,
+		tracker: newSyncTracker(),
 	}
 }
 
@@ -78,9 +79,20 @@ type Reactor struct {
 	db     *Database
 	feed   *event.Feed
 	chain  *big.Int
-
-	mu    sync.Mutex
-	group *Group
+	// indexer is optional. If set it is used for initial historical discovery.
+	indexer HistoryIndexer
+	// l2 is set on layer 2 networks to fetch receipts with layer 1 fee fields.
+	l2 RPCClient
+	// withdrawals is set on networks where validator withdrawals are credited to accounts.
+	withdrawals RPCClient
+	// tracker holds state of running downloads, it is kept between restarts.
+	tracker *syncTracker
+	// checkpoints are optional. If set, ranges without activity are skipped by the initial sync.
+	checkpoints *checkpoints
+
+	mu       sync.Mutex
+	group    *Group
+	accounts []common.Address
 }
 
 func (r *Reactor) newControlCommand(accounts []common.Address) *controlCommand {
@@ -91,15 +103,23 @@ func (r *Reactor) newControlCommand(accounts []common.Address) *controlCommand {
 		client:   r.client,
 		accounts: accounts,
 		eth: &ETHTransferDownloader{
-			client:   r.client,
-			accounts: accounts,
-			signer:   signer,
-			db:       r.db,
+			client:      r.client,
+			accounts:    accounts,
+			signer:      signer,
+			db:          r.db,
+			l2:          r.l2,
+			withdrawals: r.withdrawals,
 		},
 		erc20:       NewERC20TransfersDownloader(r.client, accounts, signer),
 		feed:        r.feed,
 		safetyDepth: reorgSafetyDepth(r.chain),
+		indexer:     r.indexer,
+		tracker:     r.tracker,
+		l2:          r.l2,
+		withdrawals: r.withdrawals,
+		checkpoints: r.checkpoints,
 	}
+	ctl.erc20.l2 = r.l2
 
 	return ctl
 }
@@ -112,6 +132,7 @@ func (r *Reactor) Start(accounts []common.Address) error {
 		return errAlreadyRunning
 	}
 	r.group = NewGroup(context.Background())
+	r.accounts = accounts
 	ctl := r.newControlCommand(accounts)
 	r.group.Add(ctl.Command())
 	return nil
@@ -128,3 +149,29 @@ func (r *Reactor) Stop() {
 	r.group.Wait()
 	r.group = nil
 }
+
+// Restart stops reactor, calls reset while transfers are not downloaded and starts
+// reactor again with the same accounts, also if reset fails.
+func (r *Reactor) Restart(reset func() error) error {
+	r.mu.Lock()
+	accounts := r.accounts
+	r.mu.Unlock()
+	r.Stop()
+	err := reset()
+	if startErr := r.Start(accounts); err == nil {
+		err = startErr
+	}
+	return err
+}
+
+// watching returns true if transfers of the address are downloaded.
+func (r *Reactor) watching(address common.Address) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, account := range r.accounts {
+		if account == address {
+			return true
+		}
+	}
+	return false
+}
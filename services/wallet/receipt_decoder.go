@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+// builtinEventsABI describes events of erc721, weth and uniswap contracts. Erc20 events
+// are taken from ierc20. Events with the same signature are distinguished by the number of
+// indexed arguments, e.g. erc20 and erc721 Transfer.
+const builtinEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"approved","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Approval","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"operator","type":"address"},{"indexed":false,"name":"approved","type":"bool"}],"name":"ApprovalForAll","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"dst","type":"address"},{"indexed":false,"name":"wad","type":"uint256"}],"name":"Deposit","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"src","type":"address"},{"indexed":false,"name":"wad","type":"uint256"}],"name":"Withdrawal","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"buyer","type":"address"},{"indexed":true,"name":"eth_sold","type":"uint256"},{"indexed":true,"name":"tokens_bought","type":"uint256"}],"name":"TokenPurchase","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"buyer","type":"address"},{"indexed":true,"name":"tokens_sold","type":"uint256"},{"indexed":true,"name":"eth_bought","type":"uint256"}],"name":"EthPurchase","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"provider","type":"address"},{"indexed":true,"name":"eth_amount","type":"uint256"},{"indexed":true,"name":"token_amount","type":"uint256"}],"name":"AddLiquidity","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"provider","type":"address"},{"indexed":true,"name":"eth_amount","type":"uint256"},{"indexed":true,"name":"token_amount","type":"uint256"}],"name":"RemoveLiquidity","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"amount0In","type":"uint256"},{"indexed":false,"name":"amount1In","type":"uint256"},{"indexed":false,"name":"amount0Out","type":"uint256"},{"indexed":false,"name":"amount1Out","type":"uint256"},{"indexed":true,"name":"to","type":"address"}],"name":"Swap","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":false,"name":"reserve0","type":"uint112"},{"indexed":false,"name":"reserve1","type":"uint112"}],"name":"Sync","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"amount0","type":"uint256"},{"indexed":false,"name":"amount1","type":"uint256"}],"name":"Mint","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"amount0","type":"uint256"},{"indexed":false,"name":"amount1","type":"uint256"},{"indexed":true,"name":"to","type":"address"}],"name":"Burn","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"token0","type":"address"},{"indexed":true,"name":"token1","type":"address"},{"indexed":false,"name":"pair","type":"address"},{"indexed":false,"name":"","type":"uint256"}],"name":"PairCreated","type":"event"}
+]`
+
+// builtinEvents are known events indexed by their ID.
+var builtinEvents map[common.Hash][]abi.Event
+
+func init() {
+	builtinEvents = map[common.Hash][]abi.Event{}
+	for _, definition := range []string{ierc20.IERC20ABI, builtinEventsABI} {
+		contract, err := abi.JSON(strings.NewReader(definition))
+		if err != nil {
+			panic(err)
+		}
+		for _, event := range contract.Events {
+			builtinEvents[event.ID()] = append(builtinEvents[event.ID()], event)
+		}
+	}
+}
+
+// ErrReceiptNotFound is returned if the node doesn't have a receipt of the transaction.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// DecodedParam is a single argument of a decoded event.
+type DecodedParam struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+	// Value is a human-readable value: numbers in decimal, addresses and bytes in hex.
+	// Indexed arguments of dynamic types are hashes of their values.
+	Value string `json:"value"`
+}
+
+// DecodedLog is a log with an event decoded using a known ABI.
+type DecodedLog struct {
+	Address  common.Address `json:"address"`
+	LogIndex hexutil.Uint   `json:"logIndex"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	// Event and Signature are empty if the event is not known.
+	Event     string         `json:"event,omitempty"`
+	Signature string         `json:"signature,omitempty"`
+	Params    []DecodedParam `json:"params,omitempty"`
+}
+
+// DecodedReceipt is a transaction receipt with decoded logs.
+type DecodedReceipt struct {
+	TxHash          common.Hash    `json:"txHash"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     *hexutil.Big   `json:"blockNumber"`
+	Status          hexutil.Uint64 `json:"status"`
+	GasUsed         hexutil.Uint64 `json:"gasUsed"`
+	ContractAddress common.Address `json:"contractAddress"`
+	Logs            []DecodedLog   `json:"logs"`
+}
+
+// receiptClient is a subset of ethclient methods required to decode a receipt.
+type receiptClient interface {
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+}
+
+// GetDecodedReceipt fetches a receipt of the transaction and decodes its logs using ABIs registered
+// for the emitting contracts and built-in erc20, erc721, weth and uniswap events.
+func GetDecodedReceipt(ctx context.Context, client receiptClient, registry *abiRegistry, hash common.Hash) (*DecodedReceipt, error) {
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err == ethereum.NotFound {
+		return nil, ErrReceiptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rst := &DecodedReceipt{
+		TxHash:          receipt.TxHash,
+		BlockHash:       receipt.BlockHash,
+		BlockNumber:     (*hexutil.Big)(receipt.BlockNumber),
+		Status:          hexutil.Uint64(receipt.Status),
+		GasUsed:         hexutil.Uint64(receipt.GasUsed),
+		ContractAddress: receipt.ContractAddress,
+		Logs:            make([]DecodedLog, len(receipt.Logs)),
+	}
+	for i, l := range receipt.Logs {
+		rst.Logs[i] = decodeLog(registry, l)
+	}
+	return rst, nil
+}
+
+func decodeLog(registry *abiRegistry, l *types.Log) DecodedLog {
+	rst := DecodedLog{
+		Address:  l.Address,
+		LogIndex: hexutil.Uint(l.Index),
+		Topics:   l.Topics,
+		Data:     l.Data,
+	}
+	event := lookupEvent(registry, l)
+	if event == nil {
+		return rst
+	}
+	params, err := decodeEventParams(event, l)
+	if err != nil {
+		log.Debug("failed to decode log", "address", l.Address, "event", event.Name, "error", err)
+		return rst
+	}
+	rst.Event = event.Name
+	rst.Signature = event.Sig()
+	rst.Params = params
+	return rst
+}
+
+// lookupEvent finds an event of the log in the ABI of the contract and then in built-in events.
+func lookupEvent(registry *abiRegistry, l *types.Log) *abi.Event {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	if contract, exist := registry.Lookup(l.Address); exist {
+		event, err := contract.EventByID(l.Topics[0])
+		if err == nil && indexedCount(event) == len(l.Topics)-1 {
+			return event
+		}
+	}
+	for _, event := range builtinEvents[l.Topics[0]] {
+		if indexedCount(&event) == len(l.Topics)-1 {
+			return &event
+		}
+	}
+	return nil
+}
+
+func indexedCount(event *abi.Event) int {
+	return len(event.Inputs) - len(event.Inputs.NonIndexed())
+}
+
+func decodeEventParams(event *abi.Event, l *types.Log) ([]DecodedParam, error) {
+	values, err := event.Inputs.NonIndexed().UnpackValues(l.Data)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]DecodedParam, 0, len(event.Inputs))
+	topic, value := 1, 0
+	for _, input := range event.Inputs {
+		param := DecodedParam{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed}
+		if input.Indexed {
+			param.Value = decodeTopic(input.Type, l.Topics[topic])
+			topic++
+		} else {
+			param.Value = formatABIValue(values[value])
+			value++
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// decodeTopic decodes an indexed argument. Dynamic types are stored as a hash of the value.
+func decodeTopic(typ abi.Type, topic common.Hash) string {
+	switch typ.T {
+	case abi.AddressTy:
+		return common.BytesToAddress(topic[common.HashLength-common.AddressLength:]).Hex()
+	case abi.UintTy:
+		return new(big.Int).SetBytes(topic[:]).String()
+	case abi.IntTy:
+		value := new(big.Int).SetBytes(topic[:])
+		if topic[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return value.String()
+	case abi.BoolTy:
+		return strconv.FormatBool(topic[common.HashLength-1] == 1)
+	case abi.FixedBytesTy:
+		return hexutil.Encode(topic[:typ.Size])
+	}
+	return topic.Hex()
+}
+
+func formatABIValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		bytes := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(bytes), rv)
+		return hexutil.Encode(bytes)
+	}
+	if rv.Kind() == reflect.Array || rv.Kind() == reflect.Slice {
+		elems := make([]string, rv.Len())
+		for i := range elems {
+			elems[i] = formatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	}
+	return fmt.Sprint(value)
+}
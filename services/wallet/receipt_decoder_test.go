@@ -0,0 +1,141 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type receiptTestClient struct {
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (c receiptTestClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, exist := c.receipts[hash]
+	if !exist {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func eventID(signature string) common.Hash {
+	return crypto.Keccak256Hash([]byte(signature))
+}
+
+func TestGetDecodedReceipt(t *testing.T) {
+	from := common.Address{1}
+	to := common.Address{2}
+	custom := common.Address{0xff}
+	uintArgs := abi.Arguments{{Type: mustNewType(t, "uint256")}, {Type: mustNewType(t, "uint256")}, {Type: mustNewType(t, "uint256")}, {Type: mustNewType(t, "uint256")}}
+	swapData, err := uintArgs.Pack(big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(2))
+	require.NoError(t, err)
+
+	hash := common.Hash{1}
+	receipt := &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      hash,
+		BlockNumber: big.NewInt(10),
+		Logs: []*types.Log{
+			// erc20 transfer
+			{
+				Address: common.Address{3},
+				Topics:  []common.Hash{eventID("Transfer(address,address,uint256)"), from.Hash(), to.Hash()},
+				Data:    common.BigToHash(big.NewInt(100)).Bytes(),
+			},
+			// erc721 transfer
+			{
+				Address: common.Address{4},
+				Topics:  []common.Hash{eventID("Transfer(address,address,uint256)"), from.Hash(), to.Hash(), common.BigToHash(big.NewInt(7))},
+				Index:   1,
+			},
+			// uniswap v2 swap
+			{
+				Address: common.Address{5},
+				Topics:  []common.Hash{eventID("Swap(address,uint256,uint256,uint256,uint256,address)"), from.Hash(), to.Hash()},
+				Data:    swapData,
+				Index:   2,
+			},
+			// unknown event
+			{
+				Address: common.Address{6},
+				Topics:  []common.Hash{eventID("Unknown(uint256)")},
+				Data:    common.BigToHash(big.NewInt(1)).Bytes(),
+				Index:   3,
+			},
+			// event of a registered contract
+			{
+				Address: custom,
+				Topics:  []common.Hash{eventID("Paid(address,int256)"), from.Hash(), common.BigToHash(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(5)))},
+				Index:   4,
+			},
+		},
+	}
+	client := receiptTestClient{receipts: map[common.Hash]*types.Receipt{hash: receipt}}
+
+	registry := newABIRegistry()
+	require.NoError(t, registry.RegisterContracts(map[common.Address]string{
+		custom: `[{"anonymous":false,"inputs":[{"indexed":true,"name":"payer","type":"address"},{"indexed":true,"name":"amount","type":"int256"}],"name":"Paid","type":"event"}]`,
+	}))
+
+	rst, err := GetDecodedReceipt(context.Background(), client, registry, hash)
+	require.NoError(t, err)
+	require.Equal(t, hash, rst.TxHash)
+	require.Len(t, rst.Logs, 5)
+
+	require.Equal(t, "Transfer", rst.Logs[0].Event)
+	require.Equal(t, []DecodedParam{
+		{Name: "from", Type: "address", Indexed: true, Value: from.Hex()},
+		{Name: "to", Type: "address", Indexed: true, Value: to.Hex()},
+		{Name: "value", Type: "uint256", Value: "100"},
+	}, rst.Logs[0].Params)
+
+	require.Equal(t, "Transfer", rst.Logs[1].Event)
+	require.Equal(t, "tokenId", rst.Logs[1].Params[2].Name)
+	require.Equal(t, "7", rst.Logs[1].Params[2].Value)
+
+	require.Equal(t, "Swap", rst.Logs[2].Event)
+	require.Equal(t, "Swap(address,uint256,uint256,uint256,uint256,address)", rst.Logs[2].Signature)
+	require.Equal(t, "1", rst.Logs[2].Params[1].Value)
+	require.Equal(t, "2", rst.Logs[2].Params[4].Value)
+	require.Equal(t, to.Hex(), rst.Logs[2].Params[5].Value)
+
+	require.Empty(t, rst.Logs[3].Event)
+	require.Empty(t, rst.Logs[3].Params)
+	require.Len(t, rst.Logs[3].Topics, 1)
+
+	require.Equal(t, "Paid", rst.Logs[4].Event)
+	require.Equal(t, "-5", rst.Logs[4].Params[1].Value)
+
+	_, err = GetDecodedReceipt(context.Background(), client, registry, common.Hash{2})
+	require.Equal(t, ErrReceiptNotFound, err)
+}
+
+func mustNewType(t *testing.T, typ string) abi.Type {
+	rst, err := abi.NewType(typ, nil)
+	require.NoError(t, err)
+	return rst
+}
+
+func TestDBContractABIs(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	contract := `[{"anonymous":false,"inputs":[],"name":"Ping","type":"event"}]`
+	_, err := abi.JSON(strings.NewReader(contract))
+	require.NoError(t, err)
+	require.NoError(t, db.SaveContractABI(common.Address{1}, contract))
+	require.NoError(t, db.SaveContractABI(common.Address{1}, contract))
+
+	rst, err := db.GetContractABIs()
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{{1}: contract}, rst)
+}
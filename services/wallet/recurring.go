@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// recurringLookback is how far back transfers are analyzed.
+	recurringLookback = 400 * day
+	// minRecurringPayments is the minimal number of payments to detect a recurring transfer.
+	minRecurringPayments = 3
+	// minRecurringInterval excludes payments repeated in bursts, e.g. retries.
+	minRecurringInterval = day
+	// recurringAmountTolerance and recurringIntervalTolerance are maximal deviations
+	// from the median amount and interval in percents.
+	recurringAmountTolerance   = 10
+	recurringIntervalTolerance = 20
+)
+
+// RecurringTransfer is a series of payments of a similar amount sent to the same counterparty
+// in regular intervals, e.g. a subscription.
+type RecurringTransfer struct {
+	Counterparty common.Address `json:"counterparty"`
+	// Token is an address of the token contract, zero address for ether.
+	Token common.Address `json:"token"`
+	// Amount is the median amount of the payments, the lower one for an even number of payments.
+	Amount *hexutil.Big `json:"amount"`
+	// Interval is the median interval between payments in seconds.
+	Interval uint64 `json:"interval"`
+	Payments int    `json:"payments"`
+	// First and Last are timestamps of the first and the last payment.
+	First uint64 `json:"first"`
+	Last  uint64 `json:"last"`
+	// Next is the expected timestamp of the next payment.
+	Next uint64 `json:"next"`
+	// Active is false if the next payment is overdue by more than the tolerance.
+	Active bool `json:"active"`
+}
+
+type recurringKey struct {
+	counterparty common.Address
+	token        common.Address
+}
+
+type payment struct {
+	timestamp uint64
+	amount    *big.Int
+}
+
+// GetRecurringTransfers detects recurring payments sent from the address within the last 400 days.
+// Payments to the same counterparty in the same token are recurring if there are at least 3 of them,
+// amounts are within 10% of the median amount and intervals, at least a day long, are within 20% of the median interval.
+// Payments with amounts outside of the tolerance are ignored. Results are ordered by the next expected payment.
+func GetRecurringTransfers(db *Database, address common.Address, now time.Time) ([]RecurringTransfer, error) {
+	transfers, err := db.GetTransfersSince(address, uint64(now.Add(-recurringLookback).Unix()))
+	if err != nil {
+		return nil, err
+	}
+	groups := map[recurringKey][]payment{}
+	for i := range transfers {
+		token, amount := outgoingValue(address, &transfers[i])
+		counterparty := recipient(&transfers[i])
+		if amount == nil || amount.Sign() == 0 || counterparty == (common.Address{}) {
+			continue
+		}
+		key := recurringKey{counterparty: counterparty, token: token}
+		groups[key] = append(groups[key], payment{timestamp: transfers[i].Timestamp, amount: amount})
+	}
+	rst := []RecurringTransfer{}
+	for key, payments := range groups {
+		recurring, ok := detectRecurring(payments, uint64(now.Unix()))
+		if !ok {
+			continue
+		}
+		recurring.Counterparty = key.counterparty
+		recurring.Token = key.token
+		rst = append(rst, recurring)
+	}
+	sort.Slice(rst, func(i, j int) bool {
+		if rst[i].Next != rst[j].Next {
+			return rst[i].Next < rst[j].Next
+		}
+		return rst[i].Counterparty.Hex() < rst[j].Counterparty.Hex()
+	})
+	return rst, nil
+}
+
+// recipient returns the receiver of an outgoing transfer, zero address for contract creations.
+func recipient(transfer *Transfer) common.Address {
+	if transfer.Type == erc20Transfer {
+		return common.BytesToAddress(transfer.Log.Topics[2].Bytes())
+	}
+	if transfer.Transaction == nil || transfer.Transaction.To() == nil {
+		return common.Address{}
+	}
+	return *transfer.Transaction.To()
+}
+
+// detectRecurring checks if payments to a single counterparty are regular.
+func detectRecurring(payments []payment, now uint64) (RecurringTransfer, bool) {
+	if len(payments) < minRecurringPayments {
+		return RecurringTransfer{}, false
+	}
+	amounts := make([]*big.Int, len(payments))
+	for i := range payments {
+		amounts[i] = payments[i].amount
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].Cmp(amounts[j]) < 0 })
+	median := amounts[(len(amounts)-1)/2]
+
+	var similar []payment
+	for _, p := range payments {
+		if withinTolerance(p.amount, median, recurringAmountTolerance) {
+			similar = append(similar, p)
+		}
+	}
+	if len(similar) < minRecurringPayments {
+		return RecurringTransfer{}, false
+	}
+	sort.Slice(similar, func(i, j int) bool { return similar[i].timestamp < similar[j].timestamp })
+
+	intervals := make([]*big.Int, len(similar)-1)
+	for i := 1; i < len(similar); i++ {
+		intervals[i-1] = new(big.Int).SetUint64(similar[i].timestamp - similar[i-1].timestamp)
+	}
+	sorted := append([]*big.Int{}, intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	interval := sorted[(len(sorted)-1)/2]
+	if interval.Uint64() < uint64(minRecurringInterval/time.Second) {
+		return RecurringTransfer{}, false
+	}
+	for _, i := range intervals {
+		if !withinTolerance(i, interval, recurringIntervalTolerance) {
+			return RecurringTransfer{}, false
+		}
+	}
+
+	last := similar[len(similar)-1].timestamp
+	next := last + interval.Uint64()
+	return RecurringTransfer{
+		Amount:   (*hexutil.Big)(new(big.Int).Set(median)),
+		Interval: interval.Uint64(),
+		Payments: len(similar),
+		First:    similar[0].timestamp,
+		Last:     last,
+		Next:     next,
+		Active:   now <= next+interval.Uint64()*recurringIntervalTolerance/100,
+	}, true
+}
+
+// withinTolerance returns true if the value differs from the reference by at most tolerance percents of the reference.
+func withinTolerance(value, reference *big.Int, tolerance int64) bool {
+	diff := new(big.Int).Sub(value, reference)
+	diff.Abs(diff).Mul(diff, big.NewInt(100))
+	limit := new(big.Int).Mul(reference, big.NewInt(tolerance))
+	return diff.Cmp(limit) <= 0
+}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestGetRecurringTransfers(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	now := time.Now()
+	address := common.Address{1}
+	service := common.Address{2}
+	friend := common.Address{3}
+	token := common.Address{0xaa}
+	month := 30 * day
+	saveTransfers(t, db,
+		// monthly token subscription with one payment of a different amount
+		erc20TransferAt(1, token, address, service, 1000, now.Add(-3*month)),
+		erc20TransferAt(2, token, address, service, 1050, now.Add(-2*month+day)),
+		erc20TransferAt(3, token, address, service, 5000, now.Add(-2*month+2*day)),
+		erc20TransferAt(4, token, address, service, 1000, now.Add(-month)),
+		// weekly ether payments that stopped
+		ethTransferAt(5, address, friend, 10, now.Add(-10*week), false),
+		ethTransferAt(6, address, friend, 10, now.Add(-9*week), false),
+		ethTransferAt(7, address, friend, 10, now.Add(-8*week), false),
+		// irregular payments in ether to the service
+		ethTransferAt(8, address, service, 10, now.Add(-60*day), false),
+		ethTransferAt(9, address, service, 10, now.Add(-50*day), false),
+		ethTransferAt(10, address, service, 10, now.Add(-10*day), false),
+		// incoming payments are not counted
+		ethTransferAt(11, service, address, 10, now.Add(-2*week), false),
+		ethTransferAt(12, service, address, 10, now.Add(-week), false),
+		ethTransferAt(13, service, address, 10, now, false),
+	)
+
+	rst, err := GetRecurringTransfers(db, address, now)
+	require.NoError(t, err)
+	require.Len(t, rst, 2)
+
+	weekly := rst[0]
+	require.Equal(t, friend, weekly.Counterparty)
+	require.Equal(t, common.Address{}, weekly.Token)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(10)), weekly.Amount)
+	require.Equal(t, uint64(week/time.Second), weekly.Interval)
+	require.Equal(t, 3, weekly.Payments)
+	require.Equal(t, uint64(now.Add(-7*week).Unix()), weekly.Next)
+	require.False(t, weekly.Active)
+
+	monthly := rst[1]
+	require.Equal(t, service, monthly.Counterparty)
+	require.Equal(t, token, monthly.Token)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(1000)), monthly.Amount)
+	require.Equal(t, 3, monthly.Payments)
+	require.Equal(t, uint64(now.Add(-3*month).Unix()), monthly.First)
+	require.Equal(t, uint64(now.Add(-month).Unix()), monthly.Last)
+	require.True(t, monthly.Active)
+}
+
+func TestDetectRecurringBursts(t *testing.T) {
+	payments := []payment{
+		{timestamp: 100, amount: big.NewInt(1)},
+		{timestamp: 200, amount: big.NewInt(1)},
+		{timestamp: 300, amount: big.NewInt(1)},
+	}
+	_, ok := detectRecurring(payments, 400)
+	require.False(t, ok)
+}
@@ -0,0 +1,425 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/services/wallet/ierc20"
+)
+
+const (
+	routeDirect = "direct"
+	routeBridge = "bridge"
+
+	stepApprove = "approve"
+	stepBridge  = "bridge"
+	stepSend    = "send"
+
+	// Gas of steps that depend on previous steps of a route can't be simulated
+	// before previous steps are mined, typical gas is used instead.
+	sendETHGas   = 21000
+	sendTokenGas = 65000
+	approveGas   = 50000
+	bridgeGas    = 150000
+
+	// depositMinGasLimit is a gas limit of a deposit relayed on layer 2.
+	depositMinGasLimit = 200000
+)
+
+// routeBridgeABI describes deposits of Optimism-style layer 1 standard bridges and
+// withdrawals of layer 2 standard bridges.
+const routeBridgeABI = `[
+{"inputs":[{"name":"_minGasLimit","type":"uint32"},{"name":"_extraData","type":"bytes"}],"name":"depositETH","outputs":[],"stateMutability":"payable","type":"function"},
+{"inputs":[{"name":"_l1Token","type":"address"},{"name":"_l2Token","type":"address"},{"name":"_amount","type":"uint256"},{"name":"_minGasLimit","type":"uint32"},{"name":"_extraData","type":"bytes"}],"name":"depositERC20","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"_l2Token","type":"address"},{"name":"_amount","type":"uint256"},{"name":"_minGasLimit","type":"uint32"},{"name":"_extraData","type":"bytes"}],"name":"withdraw","outputs":[],"stateMutability":"payable","type":"function"}
+]`
+
+// standardBridge is a standard bridge from one chain to another.
+type standardBridge struct {
+	address common.Address
+	// deposit is true for bridges from layer 1 to layer 2
+	deposit bool
+	// duration is a number of seconds before bridged funds can be used on the destination chain
+	duration uint64
+}
+
+var (
+	// l2StandardBridge is a predeployed bridge on Optimism networks.
+	l2StandardBridge = common.HexToAddress("0x4200000000000000000000000000000000000010")
+	// l2ETHToken represents ETH in withdrawals of layer 2 standard bridges.
+	l2ETHToken = common.HexToAddress("0xDeadDeAddeAddEAddeadDEaDDEAdDeaDDeAD0000")
+
+	// routeBridges are standard bridges between layer 1 and Optimism networks indexed by source and destination chains.
+	routeBridges = map[[2]uint64]standardBridge{
+		{1, 10}:  {address: common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1"), deposit: true, duration: 3 * 60},
+		{10, 1}:  {address: l2StandardBridge, duration: 7 * 24 * 60 * 60},
+		{5, 420}: {address: common.HexToAddress("0x636Af16bf2f682dD3109e60102b8E1A089FedAa8"), deposit: true, duration: 3 * 60},
+		{420, 5}: {address: l2StandardBridge, duration: 7 * 24 * 60 * 60},
+	}
+
+	routeBridgeContract abi.ABI
+	erc20Contract       abi.ABI
+
+	errRouteNotViable = errors.New("route is not viable")
+)
+
+func init() {
+	var err error
+	for definition, contract := range map[string]*abi.ABI{
+		routeBridgeABI:   &routeBridgeContract,
+		ierc20.IERC20ABI: &erc20Contract,
+	} {
+		*contract, err = abi.JSON(strings.NewReader(definition))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RouteRequest describes a desired transfer.
+type RouteRequest struct {
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Amount *hexutil.Big   `json:"amount"`
+	// ToChain is the chain on which the recipient receives the amount.
+	ToChain uint64 `json:"toChain"`
+	// FromChains are chains which balances can be used, all registered chains if empty.
+	FromChains []uint64 `json:"fromChains,omitempty"`
+	// Tokens are addresses of the sent token on every chain, ETH is sent if empty.
+	Tokens map[uint64]common.Address `json:"tokens,omitempty"`
+}
+
+// RouteStep is a single transaction of a route.
+type RouteStep struct {
+	// Type is "approve", "bridge" or "send".
+	Type     string         `json:"type"`
+	ChainID  uint64         `json:"chainId"`
+	Tx       CallArgs       `json:"tx"`
+	Gas      hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	Fee      *hexutil.Big   `json:"fee"`
+}
+
+// Route is a suggested way to make a transfer.
+type Route struct {
+	// Type is "direct" for a send on the destination chain or "bridge" for a bridge followed by a send.
+	Type      string      `json:"type"`
+	FromChain uint64      `json:"fromChain"`
+	ToChain   uint64      `json:"toChain"`
+	Steps     []RouteStep `json:"steps"`
+	// Fee is a total fee of all steps in wei.
+	Fee *hexutil.Big `json:"fee"`
+	// EstimatedTime is a number of seconds before the recipient receives the amount, not counting block times.
+	EstimatedTime uint64 `json:"estimatedTime"`
+}
+
+// transferRouter suggests routes of transfers using balances on all registered chains.
+type transferRouter struct {
+	mu     sync.RWMutex
+	chains map[uint64]RPCClient
+	abis   *abiRegistry
+}
+
+func newTransferRouter(abis *abiRegistry) *transferRouter {
+	return &transferRouter{chains: map[uint64]RPCClient{}, abis: abis}
+}
+
+// AddChain registers a client of the chain, an existing client is replaced.
+func (r *transferRouter) AddChain(chain uint64, client RPCClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chains[chain] = client
+}
+
+func (r *transferRouter) client(chain uint64) (RPCClient, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, exist := r.chains[chain]
+	return client, exist
+}
+
+func (r *transferRouter) sourceChains(req RouteRequest) []uint64 {
+	if len(req.FromChains) > 0 {
+		return req.FromChains
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chains := make([]uint64, 0, len(r.chains))
+	for chain := range r.chains {
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// SuggestRoutes returns viable routes of the transfer ordered by fee. Chains that can't
+// cover the amount and fees, or which transactions are reverted in a simulation, are skipped.
+func (r *transferRouter) SuggestRoutes(ctx context.Context, req RouteRequest) ([]Route, error) {
+	if req.Amount == nil || req.Amount.ToInt().Sign() <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if _, exist := r.client(req.ToChain); !exist {
+		return nil, errors.New("destination chain is not registered")
+	}
+	rst := []Route{}
+	for _, chain := range r.sourceChains(req) {
+		var (
+			route *Route
+			err   error
+		)
+		if chain == req.ToChain {
+			route, err = r.directRoute(ctx, req)
+		} else if bridge, exist := routeBridges[[2]uint64{chain, req.ToChain}]; exist {
+			route, err = r.bridgeRoute(ctx, req, chain, bridge)
+		} else {
+			continue
+		}
+		if err == errRouteNotViable {
+			continue
+		}
+		if err != nil {
+			log.Warn("failed to evaluate route", "from", chain, "to", req.ToChain, "error", err)
+			continue
+		}
+		rst = append(rst, *route)
+	}
+	sort.SliceStable(rst, func(i, j int) bool {
+		if c := rst[i].Fee.ToInt().Cmp(rst[j].Fee.ToInt()); c != 0 {
+			return c < 0
+		}
+		return rst[i].EstimatedTime < rst[j].EstimatedTime
+	})
+	return rst, nil
+}
+
+// token returns the address of the sent token on the chain, nil for ETH.
+func (req RouteRequest) token(chain uint64) (*common.Address, bool) {
+	if len(req.Tokens) == 0 {
+		return nil, true
+	}
+	token, exist := req.Tokens[chain]
+	return &token, exist
+}
+
+func (r *transferRouter) directRoute(ctx context.Context, req RouteRequest) (*Route, error) {
+	token, exist := req.token(req.ToChain)
+	if !exist {
+		return nil, errRouteNotViable
+	}
+	client, _ := r.client(req.ToChain)
+	send, err := sendStep(req.ToChain, req.From, req.To, token, req.Amount.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	steps, err := r.estimateSteps(ctx, client, []RouteStep{send})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBalances(ctx, client, req.From, token, req.Amount.ToInt(), steps); err != nil {
+		return nil, err
+	}
+	return newRoute(routeDirect, req.ToChain, req.ToChain, steps, 0), nil
+}
+
+func (r *transferRouter) bridgeRoute(ctx context.Context, req RouteRequest, chain uint64, bridge standardBridge) (*Route, error) {
+	source, exist := r.client(chain)
+	if !exist {
+		return nil, errRouteNotViable
+	}
+	destination, _ := r.client(req.ToChain)
+	sourceToken, exist := req.token(chain)
+	if !exist {
+		return nil, errRouteNotViable
+	}
+	destinationToken, exist := req.token(req.ToChain)
+	if !exist {
+		return nil, errRouteNotViable
+	}
+
+	amount := req.Amount.ToInt()
+	send, err := sendStep(req.ToChain, req.From, req.To, destinationToken, amount)
+	if err != nil {
+		return nil, err
+	}
+	sendSteps, err := r.estimateSteps(ctx, destination, []RouteStep{send})
+	if err != nil {
+		return nil, err
+	}
+	// the fee of the send is bridged with the amount if ETH is sent,
+	// otherwise it must be paid from the balance on the destination chain
+	bridged := amount
+	if destinationToken == nil {
+		bridged = new(big.Int).Add(amount, sendSteps[0].Fee.ToInt())
+	} else if err := checkBalances(ctx, destination, req.From, nil, nil, sendSteps); err != nil {
+		return nil, err
+	}
+
+	var steps []RouteStep
+	if sourceToken != nil && bridge.deposit {
+		var allowance hexutil.Bytes
+		data, err := erc20Contract.Pack("allowance", req.From, bridge.address)
+		if err != nil {
+			return nil, err
+		}
+		err = source.CallContext(ctx, &allowance, "eth_call", CallArgs{From: req.From, To: sourceToken, Data: data}, "latest")
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).SetBytes(allowance).Cmp(bridged) < 0 {
+			approve, err := approveStep(chain, req.From, *sourceToken, bridge.address, bridged)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, approve)
+		}
+	}
+	bridgeTx, err := bridgeStep(chain, req.From, bridge, sourceToken, destinationToken, bridged)
+	if err != nil {
+		return nil, err
+	}
+	steps, err = r.estimateSteps(ctx, source, append(steps, bridgeTx))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBalances(ctx, source, req.From, sourceToken, bridged, steps); err != nil {
+		return nil, err
+	}
+	return newRoute(routeBridge, chain, req.ToChain, append(steps, sendSteps...), bridge.duration), nil
+}
+
+func newRoute(typ string, from, to uint64, steps []RouteStep, duration uint64) *Route {
+	fee := new(big.Int)
+	for _, step := range steps {
+		fee.Add(fee, step.Fee.ToInt())
+	}
+	return &Route{
+		Type:          typ,
+		FromChain:     from,
+		ToChain:       to,
+		Steps:         steps,
+		Fee:           (*hexutil.Big)(fee),
+		EstimatedTime: duration,
+	}
+}
+
+func sendStep(chain uint64, from, to common.Address, token *common.Address, amount *big.Int) (RouteStep, error) {
+	if token == nil {
+		return RouteStep{Type: stepSend, ChainID: chain, Tx: CallArgs{From: from, To: &to, Value: (*hexutil.Big)(amount)}}, nil
+	}
+	data, err := erc20Contract.Pack("transfer", to, amount)
+	if err != nil {
+		return RouteStep{}, err
+	}
+	return RouteStep{Type: stepSend, ChainID: chain, Tx: CallArgs{From: from, To: token, Data: data}}, nil
+}
+
+func approveStep(chain uint64, from, token, spender common.Address, amount *big.Int) (RouteStep, error) {
+	data, err := erc20Contract.Pack("approve", spender, amount)
+	if err != nil {
+		return RouteStep{}, err
+	}
+	return RouteStep{Type: stepApprove, ChainID: chain, Tx: CallArgs{From: from, To: &token, Data: data}}, nil
+}
+
+func bridgeStep(chain uint64, from common.Address, bridge standardBridge, sourceToken, destinationToken *common.Address, amount *big.Int) (RouteStep, error) {
+	var (
+		data  []byte
+		value *hexutil.Big
+		err   error
+	)
+	switch {
+	case bridge.deposit && sourceToken == nil:
+		data, err = routeBridgeContract.Pack("depositETH", uint32(depositMinGasLimit), []byte{})
+		value = (*hexutil.Big)(amount)
+	case bridge.deposit:
+		data, err = routeBridgeContract.Pack("depositERC20", *sourceToken, *destinationToken, amount, uint32(depositMinGasLimit), []byte{})
+	case sourceToken == nil:
+		data, err = routeBridgeContract.Pack("withdraw", l2ETHToken, amount, uint32(0), []byte{})
+		value = (*hexutil.Big)(amount)
+	default:
+		data, err = routeBridgeContract.Pack("withdraw", *sourceToken, amount, uint32(0), []byte{})
+	}
+	if err != nil {
+		return RouteStep{}, err
+	}
+	to := bridge.address
+	return RouteStep{Type: stepBridge, ChainID: chain, Tx: CallArgs{From: from, To: &to, Value: value, Data: data}}, nil
+}
+
+// estimateSteps sets gas and fees of steps on a single chain. Only the first step is simulated,
+// later steps depend on it and use typical gas. A route with a reverted step is not viable.
+func (r *transferRouter) estimateSteps(ctx context.Context, client RPCClient, steps []RouteStep) ([]RouteStep, error) {
+	var gasPrice hexutil.Big
+	if err := client.CallContext(ctx, &gasPrice, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	for i := range steps {
+		var gas uint64
+		switch {
+		case i == 0:
+			estimate, err := EstimateTransaction(ctx, client, r.abis, steps[i].Tx)
+			if err != nil {
+				return nil, err
+			}
+			if estimate.Reverted {
+				log.Debug("route step reverted", "chain", steps[i].ChainID, "type", steps[i].Type, "reason", estimate.RevertReason)
+				return nil, errRouteNotViable
+			}
+			gas = uint64(estimate.Gas)
+		case steps[i].Type == stepBridge:
+			gas = bridgeGas
+		case steps[i].Type == stepApprove:
+			gas = approveGas
+		case steps[i].Tx.Value != nil:
+			gas = sendETHGas
+		default:
+			gas = sendTokenGas
+		}
+		price := new(big.Int).Set(gasPrice.ToInt())
+		steps[i].Gas = hexutil.Uint64(gas)
+		steps[i].GasPrice = (*hexutil.Big)(price)
+		steps[i].Fee = (*hexutil.Big)(new(big.Int).Mul(price, new(big.Int).SetUint64(gas)))
+	}
+	return steps, nil
+}
+
+// checkBalances returns errRouteNotViable if the account can't pay the amount of the token and fees of steps.
+func checkBalances(ctx context.Context, client RPCClient, account common.Address, token *common.Address, amount *big.Int, steps []RouteStep) error {
+	required := new(big.Int)
+	for _, step := range steps {
+		required.Add(required, step.Fee.ToInt())
+	}
+	if token == nil && amount != nil {
+		required.Add(required, amount)
+	}
+	var balance hexutil.Big
+	if err := client.CallContext(ctx, &balance, "eth_getBalance", account, "latest"); err != nil {
+		return err
+	}
+	if balance.ToInt().Cmp(required) < 0 {
+		return errRouteNotViable
+	}
+	if token == nil || amount == nil {
+		return nil
+	}
+	data, err := erc20Contract.Pack("balanceOf", account)
+	if err != nil {
+		return err
+	}
+	var tokenBalance hexutil.Bytes
+	if err := client.CallContext(ctx, &tokenBalance, "eth_call", CallArgs{From: account, To: token, Data: data}, "latest"); err != nil {
+		return err
+	}
+	if new(big.Int).SetBytes(tokenBalance).Cmp(amount) < 0 {
+		return errRouteNotViable
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func routerChainClient(balance *big.Int, gasPrice int64, gas uint64) *fakeRPCClient {
+	return &fakeRPCClient{results: map[string]interface{}{
+		"eth_getBalance":  (*hexutil.Big)(balance),
+		"eth_gasPrice":    (*hexutil.Big)(big.NewInt(gasPrice)),
+		"eth_call":        hexutil.Bytes{},
+		"eth_estimateGas": hexutil.Uint64(gas),
+	}}
+}
+
+func TestSuggestRoutesBridgeWhenDestinationBalanceIsLow(t *testing.T) {
+	router := newTransferRouter(newABIRegistry())
+	router.AddChain(1, routerChainClient(big.NewInt(5e18), 20e9, 100000))
+	router.AddChain(10, routerChainClient(big.NewInt(1e17), 1e6, 21000))
+
+	from, to := common.Address{1}, common.Address{2}
+	routes, err := router.SuggestRoutes(context.Background(), RouteRequest{
+		From:    from,
+		To:      to,
+		Amount:  (*hexutil.Big)(big.NewInt(1e18)),
+		ToChain: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	route := routes[0]
+	require.Equal(t, routeBridge, route.Type)
+	require.Equal(t, uint64(1), route.FromChain)
+	require.Equal(t, uint64(180), route.EstimatedTime)
+	require.Len(t, route.Steps, 2)
+
+	bridge, send := route.Steps[0], route.Steps[1]
+	require.Equal(t, stepBridge, bridge.Type)
+	require.Equal(t, uint64(1), bridge.ChainID)
+	require.Equal(t, common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1"), *bridge.Tx.To)
+	require.Equal(t, hexutil.Uint64(100000), bridge.Gas)
+	require.Equal(t, big.NewInt(2e15), bridge.Fee.ToInt())
+	// the fee of the send on the destination chain is bridged with the amount
+	require.Equal(t, big.NewInt(1e18+21e9), bridge.Tx.Value.ToInt())
+
+	require.Equal(t, stepSend, send.Type)
+	require.Equal(t, uint64(10), send.ChainID)
+	require.Equal(t, to, *send.Tx.To)
+	require.Equal(t, big.NewInt(1e18), send.Tx.Value.ToInt())
+	require.Equal(t, big.NewInt(21e9), send.Fee.ToInt())
+	require.Equal(t, big.NewInt(2e15+21e9), route.Fee.ToInt())
+}
+
+func TestSuggestRoutesOrderedByFee(t *testing.T) {
+	router := newTransferRouter(newABIRegistry())
+	router.AddChain(1, routerChainClient(big.NewInt(5e18), 20e9, 100000))
+	router.AddChain(10, routerChainClient(big.NewInt(5e18), 1e6, 21000))
+	// no bridge to the destination chain
+	router.AddChain(100, routerChainClient(big.NewInt(5e18), 1e9, 21000))
+
+	routes, err := router.SuggestRoutes(context.Background(), RouteRequest{
+		From:    common.Address{1},
+		To:      common.Address{2},
+		Amount:  (*hexutil.Big)(big.NewInt(1e18)),
+		ToChain: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	require.Equal(t, routeDirect, routes[0].Type)
+	require.Len(t, routes[0].Steps, 1)
+	require.Equal(t, routeBridge, routes[1].Type)
+
+	routes, err = router.SuggestRoutes(context.Background(), RouteRequest{
+		From:       common.Address{1},
+		To:         common.Address{2},
+		Amount:     (*hexutil.Big)(big.NewInt(1e18)),
+		ToChain:    10,
+		FromChains: []uint64{1},
+	})
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, routeBridge, routes[0].Type)
+}
+
+func TestSuggestRoutesSkipsRevertedAndUnfunded(t *testing.T) {
+	router := newTransferRouter(newABIRegistry())
+	source := routerChainClient(big.NewInt(5e18), 20e9, 100000)
+	source.results["eth_call"] = encodeRevert(t, "paused")
+	router.AddChain(1, source)
+	router.AddChain(10, routerChainClient(big.NewInt(1e17), 1e6, 21000))
+
+	req := RouteRequest{
+		From:    common.Address{1},
+		To:      common.Address{2},
+		Amount:  (*hexutil.Big)(big.NewInt(1e18)),
+		ToChain: 10,
+	}
+	routes, err := router.SuggestRoutes(context.Background(), req)
+	require.NoError(t, err)
+	require.Empty(t, routes)
+
+	req.ToChain = 5
+	_, err = router.SuggestRoutes(context.Background(), req)
+	require.Error(t, err)
+}
@@ -12,16 +12,60 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/status-im/status-go/multiaccounts/accounts"
+	"github.com/status-im/status-go/params"
 )
 
-// NewService initializes service instance.
-func NewService(db *Database, accountsFeed *event.Feed) *Service {
+// NewService initializes service instance. Transactor is used to send transactions signed by hardware wallets.
+// Accounts database and generator are used to derive accounts from the master key.
+func NewService(db *Database, accountsFeed *event.Feed, config params.WalletConfig, transactor Transactor, accountsDB *accounts.Database, generator AccountsGenerator, keys AccountKeys) *Service {
 	feed := &event.Feed{}
+	abis := newABIRegistry()
+	var indexer HistoryIndexer
+	if config.IndexerURL != "" {
+		indexer = NewEtherscanIndexer(config.IndexerURL, config.IndexerAPIKey)
+	}
+	var prices PriceSource
+	if config.PriceSourceURL != "" {
+		prices = NewCryptoCompareSource(config.PriceSourceURL, config.PriceSourceAPIKey)
+	}
+	var onRampSource OnRampSource
+	if config.OnRampProvidersURL != "" {
+		onRampSource = NewHTTPOnRampSource(config.OnRampProvidersURL)
+	}
+	var checkpointsSource CheckpointsSource
+	if config.CheckpointsURL != "" {
+		checkpointsSource = NewHTTPCheckpointsSource(config.CheckpointsURL)
+	}
+	checkpoints := newCheckpoints(checkpointsSource)
+	if config.CheckpointsFile != "" {
+		bundles, err := LoadCheckpointBundles(config.CheckpointsFile)
+		if err != nil {
+			log.Error("failed to load checkpoint bundles", "path", config.CheckpointsFile, "error", err)
+		}
+		checkpoints.Add(bundles)
+	}
 	return &Service{
 		db:           db,
 		feed:         feed,
 		signals:      &SignalsTransmitter{publisher: feed},
 		accountsFeed: accountsFeed,
+		indexer:      indexer,
+		prices:       prices,
+		abis:         abis,
+		transactor:   transactor,
+		keys:         keys,
+		hardware:     newHardwareSigner(db, feed, config.HardwareWalletConfirmationTimeout),
+		keycard:      newKeycardSigner(db, config.HardwareWalletConfirmationTimeout),
+		spending:     newSpendingMonitor(db),
+		addressBook:  newAddressBook(db),
+		ownedTokens:  newTokenDiscovery(db),
+		derived:      newDerivedAccounts(accountsDB, generator, accountsFeed),
+		onRamps:      newCryptoOnRamps(db, onRampSource),
+		fees:         newFeeHistory(),
+		checkpoints:  checkpoints,
+		shares:       newShareConverters(),
+		router:       newTransferRouter(abis),
+		nftMetadata:  newNFTMetadataCache(db, newNFTMetadataFetcher(config.IPFSGateways, config.ArweaveGateways)),
 	}
 }
 
@@ -32,9 +76,63 @@ type Service struct {
 	reactor *Reactor
 	signals *SignalsTransmitter
 	client  *ethclient.Client
+	rpc     RPCClient
 
 	group        *Group
 	accountsFeed *event.Feed
+	indexer      HistoryIndexer
+	prices       PriceSource
+	abis         *abiRegistry
+	transactor   Transactor
+	keys         AccountKeys
+	hardware     *hardwareSigner
+	keycard      *keycardSigner
+	spending     *spendingMonitor
+	addressBook  *addressBook
+	ownedTokens  *tokenDiscovery
+	derived      *derivedAccounts
+	onRamps      *cryptoOnRamps
+	fees         *feeHistory
+	checkpoints  *checkpoints
+	shares       *shareConverters
+	router       *transferRouter
+	nftMetadata  *nftMetadataCache
+}
+
+// RegisterShareConverter adds a converter for interest-bearing tokens of the kind.
+// Tokens are assigned to kinds with wallet_registerShareToken.
+func (s *Service) RegisterShareConverter(kind string, converter ShareConverter) {
+	s.shares.RegisterKind(kind, converter)
+}
+
+// RegisterChain adds a client of a network, balances on the network are used to suggest routes of transfers.
+// The network of the reactor is registered when the reactor is started.
+func (s *Service) RegisterChain(chainID uint64, client RPCClient) {
+	s.router.AddChain(chainID, client)
+}
+
+// RegisterHardwareWallet adds a connected hardware wallet under the name, e.g. ledger or trezor.
+// Transactions of accounts added from the wallet are signed on the device.
+// It is available only to Go code embedding the service, there is no RPC or mobile binding.
+func (s *Service) RegisterHardwareWallet(name string, wallet HardwareWallet) {
+	s.hardware.Register(name, wallet)
+}
+
+// UnregisterHardwareWallet removes a disconnected hardware wallet.
+func (s *Service) UnregisterHardwareWallet(name string) {
+	s.hardware.Unregister(name)
+}
+
+// ConnectKeycard is called by the client when a keycard is tapped to the reader.
+// Operations waiting for the card continue once it is connected.
+// It is available only to Go code embedding the service, there is no RPC or mobile binding.
+func (s *Service) ConnectKeycard(card Keycard) {
+	s.keycard.Connect(card)
+}
+
+// DisconnectKeycard is called by the client when the keycard is removed from the reader.
+func (s *Service) DisconnectKeycard() {
+	s.keycard.Disconnect()
 }
 
 // Start signals transmitter.
@@ -44,17 +142,43 @@ func (s *Service) Start(*p2p.Server) error {
 }
 
 // StartReactor separately because it requires known ethereum address, which will become available only after login.
-func (s *Service) StartReactor(client *ethclient.Client, accounts []common.Address, chain *big.Int) error {
+func (s *Service) StartReactor(client *ethclient.Client, rpcClient RPCClient, accounts []common.Address, chain *big.Int) error {
 	reactor := NewReactor(s.db, s.feed, client, chain)
+	reactor.indexer = s.indexer
+	reactor.checkpoints = s.checkpoints
+	if isL2Network(chain) {
+		reactor.l2 = rpcClient
+	}
+	if hasWithdrawals(chain) {
+		reactor.withdrawals = rpcClient
+	}
 	err := reactor.Start(accounts)
 	if err != nil {
 		return err
 	}
 	s.reactor = reactor
 	s.client = client
+	s.rpc = rpcClient
+	if chain != nil && chain.IsUint64() {
+		s.router.AddChain(chain.Uint64(), rpcClient)
+	}
 	s.group.Add(func(ctx context.Context) error {
 		return WatchAccountsChanges(ctx, s.accountsFeed, accounts, reactor)
 	})
+	s.group.Add(func(ctx context.Context) error {
+		return s.spending.Watch(ctx, s.feed)
+	})
+	s.addressBook.SetResolver(chainENSResolver{backend: client})
+	s.group.Add(func(ctx context.Context) error {
+		return s.addressBook.Watch(ctx, addressBookResolvePeriod)
+	})
+	s.ownedTokens.SetReader(chainTokenBalanceReader{backend: client})
+	for _, address := range accounts {
+		s.ownedTokens.Add(address)
+	}
+	s.group.Add(func(ctx context.Context) error {
+		return s.ownedTokens.Watch(ctx, ownedTokensCheckPeriod)
+	})
 	return nil
 }
 
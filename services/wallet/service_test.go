@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -108,3 +109,17 @@ func (s *ReactorChangesSuite) TestWatchNewAccounts() {
 		return nil
 	}, 5*time.Second, 500*time.Millisecond))
 }
+
+func (s *ReactorChangesSuite) TestRestartResetsStoppedReactor() {
+	s.Require().NoError(s.reactor.Start([]common.Address{s.first}))
+	defer s.reactor.Stop()
+
+	err := s.reactor.Restart(func() error {
+		s.Require().Nil(s.reactor.group)
+		return errors.New("reset failed")
+	})
+	s.Require().EqualError(err, "reset failed")
+	// reactor is started again even if reset failed
+	s.Require().NotNil(s.reactor.group)
+	s.Require().True(s.reactor.watching(s.first))
+}
@@ -0,0 +1,254 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// shareTokensABI describes methods of interest-bearing tokens that expose their underlying assets and rates.
+const shareTokensABI = `[
+{"constant":true,"inputs":[],"name":"underlying","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"exchangeRateStored","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"UNDERLYING_ASSET_ADDRESS","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"stETH","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"stEthPerToken","outputs":[{"name":"","type":"uint256"}],"type":"function"}
+]`
+
+// shareRateTTL is how long a rate fetched from a contract is used.
+const shareRateTTL = 10 * time.Minute
+
+var shareTokens abi.ABI
+
+func init() {
+	var err error
+	shareTokens, err = abi.JSON(strings.NewReader(shareTokensABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ErrUnknownShareConverter is returned if a token is registered with a kind that has no converter.
+var ErrUnknownShareConverter = errors.New("unknown share converter")
+
+// ShareRate converts amounts of an interest-bearing token to amounts of its underlying asset.
+type ShareRate struct {
+	Underlying common.Address
+	// Rate is the amount of the underlying asset for Scale amount of shares, both in the smallest units.
+	Rate  *big.Int
+	Scale *big.Int
+}
+
+// Convert returns the amount of the underlying asset for the shares.
+func (r ShareRate) Convert(shares *big.Int) *big.Int {
+	rst := new(big.Int).Mul(shares, r.Rate)
+	return rst.Div(rst, r.Scale)
+}
+
+// ShareConverter reads the underlying asset and the current rate of an interest-bearing token.
+type ShareConverter interface {
+	ShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error)
+}
+
+// ShareConverterFunc is an adapter to use functions as share converters.
+type ShareConverterFunc func(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error)
+
+// ShareRate calls f.
+func (f ShareConverterFunc) ShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error) {
+	return f(ctx, client, token)
+}
+
+var rateScale = big.NewInt(1e18)
+
+// compoundShareRate reads a rate of a compound cToken. The exchange rate is scaled by 1e18 and
+// already accounts for the difference in decimals of cTokens and underlying assets.
+func compoundShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error) {
+	return contractShareRate(ctx, client, token, "underlying", "exchangeRateStored")
+}
+
+// aaveShareRate reads an underlying asset of an aave aToken. Interest is accrued by increasing
+// balances, so aTokens are redeemable 1:1.
+func aaveShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error) {
+	var underlying common.Address
+	if err := callShareToken(ctx, client, token, "UNDERLYING_ASSET_ADDRESS", &underlying); err != nil {
+		return ShareRate{}, err
+	}
+	return ShareRate{Underlying: underlying, Rate: rateScale, Scale: rateScale}, nil
+}
+
+// wstETHShareRate reads a rate of a wrapped staked ether, stETH per token is scaled by 1e18.
+func wstETHShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, error) {
+	return contractShareRate(ctx, client, token, "stETH", "stEthPerToken")
+}
+
+func contractShareRate(ctx context.Context, client ethereum.ContractCaller, token common.Address, underlyingMethod, rateMethod string) (ShareRate, error) {
+	var underlying common.Address
+	if err := callShareToken(ctx, client, token, underlyingMethod, &underlying); err != nil {
+		return ShareRate{}, err
+	}
+	rate := new(big.Int)
+	if err := callShareToken(ctx, client, token, rateMethod, &rate); err != nil {
+		return ShareRate{}, err
+	}
+	return ShareRate{Underlying: underlying, Rate: rate, Scale: rateScale}, nil
+}
+
+func callShareToken(ctx context.Context, client ethereum.ContractCaller, token common.Address, method string, result interface{}) error {
+	input, err := shareTokens.Pack(method)
+	if err != nil {
+		return err
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: input}, nil)
+	if err != nil {
+		return err
+	}
+	return shareTokens.Unpack(result, method, output)
+}
+
+type cachedShareRate struct {
+	rate      ShareRate
+	fetchedAt time.Time
+}
+
+// shareConverters maps interest-bearing tokens to converters and caches their rates.
+// Converters are registered by kind, new kinds can be added with RegisterKind.
+type shareConverters struct {
+	mu     sync.Mutex
+	kinds  map[string]ShareConverter
+	tokens map[common.Address]string
+	rates  map[common.Address]cachedShareRate
+	now    func() time.Time
+}
+
+func newShareConverters() *shareConverters {
+	return &shareConverters{
+		kinds: map[string]ShareConverter{
+			"compound": ShareConverterFunc(compoundShareRate),
+			"aave":     ShareConverterFunc(aaveShareRate),
+			"wsteth":   ShareConverterFunc(wstETHShareRate),
+		},
+		tokens: map[common.Address]string{},
+		rates:  map[common.Address]cachedShareRate{},
+		now:    time.Now,
+	}
+}
+
+// RegisterKind adds a converter that can be used for tokens of the kind.
+func (c *shareConverters) RegisterKind(kind string, converter ShareConverter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kinds[kind] = converter
+}
+
+// Register sets a converter of the kind for the token.
+func (c *shareConverters) Register(token common.Address, kind string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exist := c.kinds[kind]; !exist {
+		return ErrUnknownShareConverter
+	}
+	if c.tokens[token] != kind {
+		c.tokens[token] = kind
+		delete(c.rates, token)
+	}
+	return nil
+}
+
+// RegisterTokens sets converters for tokens stored in the database. Tokens of kinds
+// without converters are skipped.
+func (c *shareConverters) RegisterTokens(tokens map[common.Address]string) {
+	for token, kind := range tokens {
+		if err := c.Register(token, kind); err != nil {
+			log.Warn("can't register share token", "token", token, "kind", kind, "error", err)
+		}
+	}
+}
+
+// Rate returns a cached rate of the token or fetches it from the contract.
+// False is returned if the token has no converter.
+func (c *shareConverters) Rate(ctx context.Context, client ethereum.ContractCaller, token common.Address) (ShareRate, bool, error) {
+	c.mu.Lock()
+	converter, exist := c.kinds[c.tokens[token]]
+	cached, cachedExist := c.rates[token]
+	c.mu.Unlock()
+	if !exist {
+		return ShareRate{}, false, nil
+	}
+	if cachedExist && c.now().Sub(cached.fetchedAt) < shareRateTTL {
+		return cached.rate, true, nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	rate, err := converter.ShareRate(callCtx, client, token)
+	cancel()
+	if err != nil {
+		return ShareRate{}, false, err
+	}
+	c.mu.Lock()
+	c.rates[token] = cachedShareRate{rate: rate, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return rate, true, nil
+}
+
+// UnderlyingBalance is a balance of a token in terms of its underlying asset. For tokens
+// without a converter the underlying asset is the token itself.
+type UnderlyingBalance struct {
+	Underlying common.Address `json:"underlying"`
+	Shares     *hexutil.Big   `json:"shares"`
+	Amount     *hexutil.Big   `json:"amount"`
+}
+
+// UnderlyingValue is a value of a transfer of an interest-bearing token in its underlying asset
+// at the current rate.
+type UnderlyingValue struct {
+	Token common.Address `json:"token"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+// GetUnderlyingBalances converts balances of tokens to their underlying assets.
+func GetUnderlyingBalances(ctx context.Context, converters *shareConverters, client ethereum.ContractCaller, balances map[common.Address]map[common.Address]*big.Int) (map[common.Address]map[common.Address]*UnderlyingBalance, error) {
+	rst := map[common.Address]map[common.Address]*UnderlyingBalance{}
+	for account, tokens := range balances {
+		rst[account] = map[common.Address]*UnderlyingBalance{}
+		for token, shares := range tokens {
+			rate, exist, err := converters.Rate(ctx, client, token)
+			if err != nil {
+				return nil, err
+			}
+			balance := &UnderlyingBalance{Underlying: token, Shares: (*hexutil.Big)(shares), Amount: (*hexutil.Big)(shares)}
+			if exist {
+				balance.Underlying = rate.Underlying
+				balance.Amount = (*hexutil.Big)(rate.Convert(shares))
+			}
+			rst[account][token] = balance
+		}
+	}
+	return rst, nil
+}
+
+// setUnderlyingValues sets values in underlying assets for transfers of interest-bearing tokens.
+func setUnderlyingValues(ctx context.Context, converters *shareConverters, client ethereum.ContractCaller, views []TransferView) error {
+	for i := range views {
+		view := &views[i]
+		if view.Type != erc20Transfer || view.Value == nil {
+			continue
+		}
+		rate, exist, err := converters.Rate(ctx, client, view.Contract)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		view.Underlying = &UnderlyingValue{Token: rate.Underlying, Value: (*hexutil.Big)(rate.Convert(view.Value.ToInt()))}
+	}
+	return nil
+}
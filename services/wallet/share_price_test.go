@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type shareTestClient struct {
+	// outputs of contract calls indexed by contract and method name
+	outputs map[common.Address]map[string][]byte
+	calls   int
+}
+
+func (c *shareTestClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	method, err := shareTokens.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	output, exist := c.outputs[*msg.To][method.Name]
+	if !exist {
+		return nil, errors.New("execution reverted")
+	}
+	return output, nil
+}
+
+func packShareOutput(t *testing.T, method string, value interface{}) []byte {
+	output, err := shareTokens.Methods[method].Outputs.Pack(value)
+	require.NoError(t, err)
+	return output
+}
+
+func setupShareTestClient(t *testing.T, ctoken, atoken, wsteth, dai, steth common.Address) *shareTestClient {
+	return &shareTestClient{outputs: map[common.Address]map[string][]byte{
+		ctoken: {
+			"underlying":         packShareOutput(t, "underlying", dai),
+			"exchangeRateStored": packShareOutput(t, "exchangeRateStored", big.NewInt(2e16)),
+		},
+		atoken: {
+			"UNDERLYING_ASSET_ADDRESS": packShareOutput(t, "UNDERLYING_ASSET_ADDRESS", dai),
+		},
+		wsteth: {
+			"stETH":         packShareOutput(t, "stETH", steth),
+			"stEthPerToken": packShareOutput(t, "stEthPerToken", big.NewInt(1.5e18)),
+		},
+	}}
+}
+
+func TestShareConvertersRates(t *testing.T) {
+	ctoken, atoken, wsteth, dai, steth := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}, common.Address{5}
+	client := setupShareTestClient(t, ctoken, atoken, wsteth, dai, steth)
+	now := time.Unix(1590000000, 0)
+	converters := newShareConverters()
+	converters.now = func() time.Time { return now }
+
+	require.Equal(t, ErrUnknownShareConverter, converters.Register(ctoken, "unknown"))
+	require.NoError(t, converters.Register(ctoken, "compound"))
+	require.NoError(t, converters.Register(atoken, "aave"))
+	require.NoError(t, converters.Register(wsteth, "wsteth"))
+
+	shares := big.NewInt(5e8)
+	rate, exist, err := converters.Rate(context.Background(), client, ctoken)
+	require.NoError(t, err)
+	require.True(t, exist)
+	require.Equal(t, dai, rate.Underlying)
+	require.Equal(t, big.NewInt(1e7), rate.Convert(shares))
+
+	rate, exist, err = converters.Rate(context.Background(), client, atoken)
+	require.NoError(t, err)
+	require.True(t, exist)
+	require.Equal(t, dai, rate.Underlying)
+	require.Equal(t, shares, rate.Convert(shares))
+
+	rate, exist, err = converters.Rate(context.Background(), client, wsteth)
+	require.NoError(t, err)
+	require.True(t, exist)
+	require.Equal(t, steth, rate.Underlying)
+	require.Equal(t, big.NewInt(7.5e8), rate.Convert(shares))
+
+	_, exist, err = converters.Rate(context.Background(), client, dai)
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	// rates are cached, registering the same kind again keeps the cache
+	calls := client.calls
+	converters.RegisterTokens(map[common.Address]string{ctoken: "compound", dai: "unknown"})
+	_, _, err = converters.Rate(context.Background(), client, ctoken)
+	require.NoError(t, err)
+	require.Equal(t, calls, client.calls)
+
+	now = now.Add(shareRateTTL)
+	_, _, err = converters.Rate(context.Background(), client, ctoken)
+	require.NoError(t, err)
+	require.Equal(t, calls+2, client.calls)
+
+	// new kinds can be plugged in
+	converters.RegisterKind("fixed", ShareConverterFunc(func(context.Context, ethereum.ContractCaller, common.Address) (ShareRate, error) {
+		return ShareRate{Underlying: dai, Rate: big.NewInt(3), Scale: big.NewInt(1)}, nil
+	}))
+	require.NoError(t, converters.Register(ctoken, "fixed"))
+	rate, _, err = converters.Rate(context.Background(), client, ctoken)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(15e8), rate.Convert(shares))
+}
+
+func TestUnderlyingBalancesAndValues(t *testing.T) {
+	ctoken, atoken, wsteth, dai, steth := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}, common.Address{5}
+	client := setupShareTestClient(t, ctoken, atoken, wsteth, dai, steth)
+	converters := newShareConverters()
+	require.NoError(t, converters.Register(ctoken, "compound"))
+
+	account := common.Address{0xaa}
+	balances, err := GetUnderlyingBalances(context.Background(), converters, client, map[common.Address]map[common.Address]*big.Int{
+		account: {ctoken: big.NewInt(5e8), dai: big.NewInt(10)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]*UnderlyingBalance{
+		ctoken: {Underlying: dai, Shares: (*hexutil.Big)(big.NewInt(5e8)), Amount: (*hexutil.Big)(big.NewInt(1e7))},
+		dai:    {Underlying: dai, Shares: (*hexutil.Big)(big.NewInt(10)), Amount: (*hexutil.Big)(big.NewInt(10))},
+	}, balances[account])
+
+	views := []TransferView{
+		{Type: erc20Transfer, Contract: ctoken, Value: (*hexutil.Big)(big.NewInt(100))},
+		{Type: erc20Transfer, Contract: dai, Value: (*hexutil.Big)(big.NewInt(100))},
+		{Type: ethTransfer, Value: (*hexutil.Big)(big.NewInt(100))},
+	}
+	require.NoError(t, setUnderlyingValues(context.Background(), converters, client, views))
+	require.Equal(t, &UnderlyingValue{Token: dai, Value: (*hexutil.Big)(big.NewInt(2))}, views[0].Underlying)
+	require.Nil(t, views[1].Underlying)
+	require.Nil(t, views[2].Underlying)
+}
+
+func TestShareTokens(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	tokens, err := db.GetShareTokens()
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+
+	require.NoError(t, db.SaveShareToken(common.Address{1}, "aave"))
+	require.NoError(t, db.SaveShareToken(common.Address{1}, "compound"))
+	require.NoError(t, db.SaveShareToken(common.Address{2}, "wsteth"))
+	tokens, err = db.GetShareTokens()
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{{1}: "compound", {2}: "wsteth"}, tokens)
+}
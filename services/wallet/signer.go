@@ -0,0 +1,234 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/transactions"
+)
+
+const defaultConfirmationTimeout = 2 * time.Minute
+
+var (
+	// ErrNotHardwareAccount returned when a transaction is sent from an account that wasn't added from a hardware wallet.
+	ErrNotHardwareAccount = errors.New("account is not on a hardware wallet")
+	// ErrHardwareWalletNotConnected returned when a hardware wallet of the account is not connected.
+	ErrHardwareWalletNotConnected = errors.New("hardware wallet is not connected")
+	// ErrConfirmationTimeout returned when a user didn't confirm a transaction on a hardware wallet in time.
+	ErrConfirmationTimeout = errors.New("transaction wasn't confirmed on a hardware wallet")
+	// ErrInvalidHardwareSignature returned when a transaction signed by a hardware wallet doesn't match the request.
+	ErrInvalidHardwareSignature = errors.New("hardware wallet returned invalid signature")
+)
+
+// Signer signs transactions sent with wallet_sendTransaction.
+type Signer interface {
+	SignTx(ctx context.Context, account common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Transactor prepares transactions and sends them with a signature.
+// Implemented by transactions.Transactor.
+type Transactor interface {
+	HashTransaction(args transactions.SendTxArgs) (transactions.SendTxArgs, statustypes.Hash, error)
+	SendTransactionWithSignature(args transactions.SendTxArgs, sig []byte) (statustypes.Hash, error)
+}
+
+// HardwareWallet is a transport to a connected hardware wallet, such as Ledger or Trezor.
+// No implementation is provided, wallets are registered by an application embedding
+// status-go with Service.RegisterHardwareWallet.
+type HardwareWallet interface {
+	// Derive returns an address of the account at the derivation path.
+	Derive(ctx context.Context, path accounts.DerivationPath) (common.Address, error)
+	// SignTx requests the user to confirm a transaction on the device and returns
+	// the transaction with an EIP155 signature. It must return once ctx is done.
+	SignTx(ctx context.Context, path accounts.DerivationPath, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// HardwareAccount is an account on a hardware wallet.
+type HardwareAccount struct {
+	Address common.Address `json:"address"`
+	// Wallet is a name the hardware wallet was registered with, e.g. ledger or trezor.
+	Wallet string `json:"wallet"`
+	Path   string `json:"path"`
+}
+
+// hardwareSigner signs transactions of accounts added from hardware wallets.
+type hardwareSigner struct {
+	db      *Database
+	feed    *event.Feed
+	timeout time.Duration
+
+	mu      sync.RWMutex
+	wallets map[string]HardwareWallet
+}
+
+func newHardwareSigner(db *Database, feed *event.Feed, timeout time.Duration) *hardwareSigner {
+	if timeout == 0 {
+		timeout = defaultConfirmationTimeout
+	}
+	return &hardwareSigner{
+		db:      db,
+		feed:    feed,
+		timeout: timeout,
+		wallets: map[string]HardwareWallet{},
+	}
+}
+
+// Register adds a connected hardware wallet. A previously registered wallet with the same name is replaced.
+func (s *hardwareSigner) Register(name string, wallet HardwareWallet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wallets[name] = wallet
+}
+
+// Unregister removes a disconnected hardware wallet.
+func (s *hardwareSigner) Unregister(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.wallets, name)
+}
+
+func (s *hardwareSigner) wallet(name string) (HardwareWallet, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	wallet, exist := s.wallets[name]
+	if !exist {
+		return nil, ErrHardwareWalletNotConnected
+	}
+	return wallet, nil
+}
+
+// AddAccount derives an account from the hardware wallet and stores its derivation path.
+func (s *hardwareSigner) AddAccount(ctx context.Context, name, path string) (HardwareAccount, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return HardwareAccount{}, err
+	}
+	wallet, err := s.wallet(name)
+	if err != nil {
+		return HardwareAccount{}, err
+	}
+	address, err := wallet.Derive(ctx, derivationPath)
+	if err != nil {
+		return HardwareAccount{}, err
+	}
+	account := HardwareAccount{Address: address, Wallet: name, Path: derivationPath.String()}
+	return account, s.db.SaveHardwareAccount(account)
+}
+
+// SignTx implements Signer. The user has to confirm the transaction on the device within the timeout.
+func (s *hardwareSigner) SignTx(ctx context.Context, address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	account, err := s.db.GetHardwareAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrNotHardwareAccount
+	}
+	path, err := accounts.ParseDerivationPath(account.Path)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := s.wallet(account.Wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	type result struct {
+		tx  *types.Transaction
+		err error
+	}
+	results := make(chan result, 1)
+	s.feed.Send(Event{Type: EventHardwareWalletConfirmationRequired, Accounts: []common.Address{address}})
+	go func() {
+		signed, err := wallet.SignTx(ctx, path, tx, chainID)
+		results <- result{signed, err}
+	}()
+
+	var signed *types.Transaction
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			log.Info("hardware wallet confirmation timed out", "account", address, "wallet", account.Wallet)
+			s.feed.Send(Event{Type: EventHardwareWalletConfirmationTimeout, Accounts: []common.Address{address}})
+			return nil, ErrConfirmationTimeout
+		}
+		return nil, ctx.Err()
+	case rst := <-results:
+		if rst.err != nil {
+			return nil, rst.err
+		}
+		signed = rst.tx
+	}
+
+	// make sure that the device signed the same transaction with the expected account
+	signer := types.NewEIP155Signer(chainID)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, ErrInvalidHardwareSignature
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, err
+	}
+	if sender != address {
+		return nil, ErrInvalidHardwareSignature
+	}
+	return signed, nil
+}
+
+// buildTransaction creates a transaction from arguments validated by the transactor.
+func buildTransaction(args transactions.SendTxArgs) *types.Transaction {
+	var (
+		nonce    = uint64(*args.Nonce)
+		value    = (*big.Int)(args.Value)
+		gas      = uint64(*args.Gas)
+		gasPrice = (*big.Int)(args.GasPrice)
+		input    = args.GetInput()
+	)
+	if value == nil {
+		value = new(big.Int)
+	}
+	if args.To == nil {
+		return types.NewContractCreation(nonce, value, gas, gasPrice, input)
+	}
+	return types.NewTransaction(nonce, common.Address(*args.To), value, gas, gasPrice, input)
+}
+
+// signatureValues returns a signature of the transaction in the [R || S || V] format
+// with V equal to 0 or 1, as expected by the transactor.
+func signatureValues(tx *types.Transaction, chainID *big.Int) []byte {
+	v, r, s := tx.RawSignatureValues()
+	recovery := new(big.Int).Sub(v, new(big.Int).Add(new(big.Int).Mul(chainID, big.NewInt(2)), big.NewInt(35)))
+	sig := make([]byte, 65)
+	copy(sig[32-len(r.Bytes()):32], r.Bytes())
+	copy(sig[64-len(s.Bytes()):64], s.Bytes())
+	sig[64] = byte(recovery.Uint64())
+	return sig
+}
+
+// SendTransaction fills missing fields of the transaction, signs it with the signer
+// and sends it to the network.
+func SendTransaction(ctx context.Context, transactor Transactor, signer Signer, chainID *big.Int, args transactions.SendTxArgs) (common.Hash, error) {
+	validatedArgs, _, err := transactor.HashTransaction(args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := signer.SignTx(ctx, common.Address(args.From), buildTransaction(validatedArgs), chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := transactor.SendTransactionWithSignature(validatedArgs, signatureValues(signed, chainID))
+	return common.Hash(hash), err
+}
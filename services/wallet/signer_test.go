@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+
+	statustypes "github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/transactions"
+)
+
+type hardwareTestWallet struct {
+	keys map[string]*ecdsa.PrivateKey
+	// confirm blocks signing until it is closed.
+	confirm chan struct{}
+}
+
+func newHardwareTestWallet(t *testing.T, paths ...string) *hardwareTestWallet {
+	w := &hardwareTestWallet{keys: map[string]*ecdsa.PrivateKey{}}
+	for _, path := range paths {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		w.keys[path] = key
+	}
+	return w
+}
+
+func (w *hardwareTestWallet) Derive(ctx context.Context, path accounts.DerivationPath) (common.Address, error) {
+	key, exist := w.keys[path.String()]
+	if !exist {
+		return common.Address{}, errors.New("unknown path")
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+func (w *hardwareTestWallet) SignTx(ctx context.Context, path accounts.DerivationPath, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if w.confirm != nil {
+		select {
+		case <-w.confirm:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), w.keys[path.String()])
+}
+
+type signerTestTransactor struct {
+	chainID *big.Int
+	sent    *types.Transaction
+}
+
+func (t *signerTestTransactor) HashTransaction(args transactions.SendTxArgs) (transactions.SendTxArgs, statustypes.Hash, error) {
+	nonce := hexutil.Uint64(1)
+	gas := hexutil.Uint64(21000)
+	args.Nonce = &nonce
+	args.Gas = &gas
+	args.GasPrice = (*hexutil.Big)(big.NewInt(10))
+	return args, statustypes.Hash{}, nil
+}
+
+func (t *signerTestTransactor) SendTransactionWithSignature(args transactions.SendTxArgs, sig []byte) (statustypes.Hash, error) {
+	tx, err := buildTransaction(args).WithSignature(types.NewEIP155Signer(t.chainID), sig)
+	if err != nil {
+		return statustypes.Hash{}, err
+	}
+	t.sent = tx
+	return statustypes.Hash(tx.Hash()), nil
+}
+
+func TestHardwareSignerSendTransaction(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	const path = "m/44'/60'/0'/0/1"
+	signer := newHardwareSigner(db, &event.Feed{}, time.Minute)
+	wallet := newHardwareTestWallet(t, path)
+
+	_, err := signer.AddAccount(context.Background(), "ledger", path)
+	require.Equal(t, ErrHardwareWalletNotConnected, err)
+	signer.Register("ledger", wallet)
+	_, err = signer.AddAccount(context.Background(), "ledger", "m/invalid")
+	require.Error(t, err)
+
+	account, err := signer.AddAccount(context.Background(), "ledger", path)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(wallet.keys[path].PublicKey), account.Address)
+	accounts, err := db.GetHardwareAccounts()
+	require.NoError(t, err)
+	require.Equal(t, []HardwareAccount{account}, accounts)
+
+	chainID := big.NewInt(1777)
+	transactor := &signerTestTransactor{chainID: chainID}
+	to := statustypes.Address{1}
+	args := transactions.SendTxArgs{
+		From:  statustypes.Address(account.Address),
+		To:    &to,
+		Value: (*hexutil.Big)(big.NewInt(100)),
+	}
+	hash, err := SendTransaction(context.Background(), transactor, signer, chainID, args)
+	require.NoError(t, err)
+	require.Equal(t, transactor.sent.Hash(), hash)
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), transactor.sent)
+	require.NoError(t, err)
+	require.Equal(t, account.Address, sender)
+
+	_, err = SendTransaction(context.Background(), transactor, signer, chainID, transactions.SendTxArgs{From: statustypes.Address{2}, To: &to})
+	require.Equal(t, ErrNotHardwareAccount, err)
+
+	signer.Unregister("ledger")
+	_, err = SendTransaction(context.Background(), transactor, signer, chainID, args)
+	require.Equal(t, ErrHardwareWalletNotConnected, err)
+}
+
+func TestHardwareSignerConfirmationTimeout(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	feed := &event.Feed{}
+	events := make(chan Event, 2)
+	sub := feed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	signer := newHardwareSigner(db, feed, 10*time.Millisecond)
+	wallet := newHardwareTestWallet(t, path)
+	wallet.confirm = make(chan struct{})
+	signer.Register("trezor", wallet)
+	account, err := signer.AddAccount(context.Background(), "trezor", path)
+	require.NoError(t, err)
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	_, err = signer.SignTx(context.Background(), account.Address, tx, big.NewInt(1))
+	require.Equal(t, ErrConfirmationTimeout, err)
+	require.Equal(t, Event{Type: EventHardwareWalletConfirmationRequired, Accounts: []common.Address{account.Address}}, <-events)
+	require.Equal(t, Event{Type: EventHardwareWalletConfirmationTimeout, Accounts: []common.Address{account.Address}}, <-events)
+}
+
+func TestHardwareSignerRejectsOtherAccount(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	const path = "m/44'/60'/0'/0/0"
+	signer := newHardwareSigner(db, &event.Feed{}, time.Minute)
+	signer.Register("ledger", newHardwareTestWallet(t, path))
+	// the device derives a different account at the path
+	require.NoError(t, db.SaveHardwareAccount(HardwareAccount{Address: common.Address{1}, Wallet: "ledger", Path: path}))
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	_, err := signer.SignTx(context.Background(), common.Address{1}, tx, big.NewInt(1))
+	require.Equal(t, ErrInvalidHardwareSignature, err)
+}
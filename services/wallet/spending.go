@@ -0,0 +1,226 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/signal"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+
+	dailyPeriod  = "daily"
+	weeklyPeriod = "weekly"
+)
+
+// Spent is a value sent from an address in ether and tokens.
+type Spent struct {
+	Eth    *hexutil.Big                    `json:"eth"`
+	Tokens map[common.Address]*hexutil.Big `json:"tokens"`
+}
+
+func newSpent() Spent {
+	return Spent{Eth: (*hexutil.Big)(new(big.Int)), Tokens: map[common.Address]*hexutil.Big{}}
+}
+
+// Value returns spent ether for the zero address and spent tokens otherwise.
+func (s Spent) Value(token common.Address) *big.Int {
+	if token == (common.Address{}) {
+		return s.Eth.ToInt()
+	}
+	if value, exist := s.Tokens[token]; exist {
+		return value.ToInt()
+	}
+	return new(big.Int)
+}
+
+func (s Spent) addToken(token common.Address, amount *big.Int) {
+	value, exist := s.Tokens[token]
+	if !exist {
+		value = (*hexutil.Big)(new(big.Int))
+		s.Tokens[token] = value
+	}
+	value.ToInt().Add(value.ToInt(), amount)
+}
+
+// SpendingReport is an outgoing value of an address in the last 24 hours and 7 days.
+type SpendingReport struct {
+	Address common.Address `json:"address"`
+	Daily   Spent          `json:"daily"`
+	Weekly  Spent          `json:"weekly"`
+}
+
+// SpendingLimit is a soft limit of an outgoing value. Exceeding the limit doesn't prevent
+// sending transactions, clients are notified with the wallet.limitReached signal.
+type SpendingLimit struct {
+	// Token is an address of the token contract, zero address for ether.
+	Token  common.Address `json:"token"`
+	Daily  *hexutil.Big   `json:"daily"`
+	Weekly *hexutil.Big   `json:"weekly"`
+}
+
+// LimitReached is sent when a value spent within a period exceeds the limit.
+type LimitReached struct {
+	Address common.Address `json:"address"`
+	Token   common.Address `json:"token"`
+	// Period is either daily or weekly.
+	Period string       `json:"period"`
+	Limit  *hexutil.Big `json:"limit"`
+	Spent  *hexutil.Big `json:"spent"`
+}
+
+// GetSpending returns ether and tokens sent from the address within the last day and week.
+// Values are computed from stored transfers, failed transactions and fees are not included.
+func GetSpending(db *Database, address common.Address, now time.Time) (*SpendingReport, error) {
+	transfers, err := db.GetTransfersSince(address, uint64(now.Add(-week).Unix()))
+	if err != nil {
+		return nil, err
+	}
+	rst := &SpendingReport{Address: address, Daily: newSpent(), Weekly: newSpent()}
+	dayStart := uint64(now.Add(-day).Unix())
+	for i := range transfers {
+		token, amount := outgoingValue(address, &transfers[i])
+		if amount == nil {
+			continue
+		}
+		periods := []Spent{rst.Weekly}
+		if transfers[i].Timestamp >= dayStart {
+			periods = append(periods, rst.Daily)
+		}
+		for _, spent := range periods {
+			if token == (common.Address{}) {
+				spent.Eth.ToInt().Add(spent.Eth.ToInt(), amount)
+			} else {
+				spent.addToken(token, amount)
+			}
+		}
+	}
+	return rst, nil
+}
+
+// outgoingValue returns a token address (zero for ether) and a value sent by the address
+// in the transfer. Nil value is returned for incoming transfers.
+func outgoingValue(address common.Address, transfer *Transfer) (common.Address, *big.Int) {
+	switch transfer.Type {
+	case ethTransfer, depositTransfer:
+		tx := transfer.Transaction
+		if tx == nil || transfer.From != address || (tx.To() != nil && *tx.To() == address) {
+			return common.Address{}, nil
+		}
+		if transfer.Receipt != nil && transfer.Receipt.Status != 1 {
+			return common.Address{}, nil
+		}
+		return common.Address{}, tx.Value()
+	case erc20Transfer:
+		l := transfer.Log
+		if l == nil || len(l.Topics) != 3 || l.Topics[0] != crypto.Keccak256Hash([]byte(erc20TransferEventSignature)) {
+			return common.Address{}, nil
+		}
+		from := common.BytesToAddress(l.Topics[1].Bytes())
+		to := common.BytesToAddress(l.Topics[2].Bytes())
+		if from != address || to == address {
+			return common.Address{}, nil
+		}
+		return l.Address, new(big.Int).SetBytes(l.Data)
+	}
+	return common.Address{}, nil
+}
+
+type limitKey struct {
+	address common.Address
+	token   common.Address
+	period  string
+}
+
+// spendingMonitor checks spending limits of accounts with new transfers.
+// A limit is reported once until spending drops below it.
+type spendingMonitor struct {
+	db     *Database
+	now    func() time.Time
+	notify func(LimitReached)
+
+	mu      sync.Mutex
+	reached map[limitKey]bool
+}
+
+func newSpendingMonitor(db *Database) *spendingMonitor {
+	return &spendingMonitor{
+		db:  db,
+		now: time.Now,
+		notify: func(event LimitReached) {
+			signal.SendWalletLimitReached(event)
+		},
+		reached: map[limitKey]bool{},
+	}
+}
+
+// Watch checks limits of accounts in wallet events until the context is canceled.
+func (m *spendingMonitor) Watch(ctx context.Context, feed *event.Feed) error {
+	events := make(chan Event, 10)
+	sub := feed.Subscribe(events)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err != nil {
+				log.Error("spending monitor subscription failed", "error", err)
+			}
+			return err
+		case event := <-events:
+			if event.Type != EventNewBlock && event.Type != EventRecentHistoryReady {
+				continue
+			}
+			for _, address := range event.Accounts {
+				if err := m.Check(address); err != nil {
+					log.Error("failed to check spending limits", "address", address, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// Check compares spent values of the address with its limits.
+func (m *spendingMonitor) Check(address common.Address) error {
+	limits, err := m.db.GetSpendingLimits(address)
+	if err != nil || len(limits) == 0 {
+		return err
+	}
+	report, err := GetSpending(m.db, address, m.now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, limit := range limits {
+		m.check(limitKey{address, limit.Token, dailyPeriod}, limit.Daily, report.Daily.Value(limit.Token))
+		m.check(limitKey{address, limit.Token, weeklyPeriod}, limit.Weekly, report.Weekly.Value(limit.Token))
+	}
+	return nil
+}
+
+func (m *spendingMonitor) check(key limitKey, limit *hexutil.Big, spent *big.Int) {
+	reached := limit != nil && spent.Cmp(limit.ToInt()) >= 0
+	if reached && !m.reached[key] {
+		m.notify(LimitReached{
+			Address: key.address,
+			Token:   key.token,
+			Period:  key.period,
+			Limit:   limit,
+			Spent:   (*hexutil.Big)(spent),
+		})
+	}
+	m.reached[key] = reached
+}
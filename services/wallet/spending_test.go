@@ -0,0 +1,155 @@
+package wallet
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func ethTransferAt(nonce uint64, from, to common.Address, value int64, timestamp time.Time, failed bool) Transfer {
+	tx := types.NewTransaction(nonce, to, big.NewInt(value), 21000, big.NewInt(1), nil)
+	receipt := types.NewReceipt(nil, failed, 21000)
+	receipt.Logs = []*types.Log{}
+	return Transfer{
+		ID:          tx.Hash(),
+		Type:        ethTransfer,
+		BlockNumber: big.NewInt(int64(nonce)),
+		BlockHash:   common.Hash{byte(nonce)},
+		Timestamp:   uint64(timestamp.Unix()),
+		Transaction: tx,
+		Receipt:     receipt,
+		Address:     from,
+		From:        from,
+	}
+}
+
+func erc20TransferAt(nonce uint64, token, from, to common.Address, value int64, timestamp time.Time) Transfer {
+	tx := types.NewTransaction(nonce, token, nil, 60000, big.NewInt(1), nil)
+	receipt := types.NewReceipt(nil, false, 60000)
+	l := &types.Log{
+		Address: token,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte(erc20TransferEventSignature)),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:   common.BigToHash(big.NewInt(value)).Bytes(),
+		TxHash: tx.Hash(),
+	}
+	receipt.Logs = []*types.Log{l}
+	return Transfer{
+		ID:          common.Hash{0xee, byte(nonce)},
+		Type:        erc20Transfer,
+		BlockNumber: big.NewInt(int64(nonce)),
+		BlockHash:   common.Hash{byte(nonce)},
+		Timestamp:   uint64(timestamp.Unix()),
+		Transaction: tx,
+		Receipt:     receipt,
+		Log:         l,
+		Address:     from,
+		From:        from,
+	}
+}
+
+func saveTransfers(t *testing.T, db *Database, transfers ...Transfer) {
+	for _, transfer := range transfers {
+		header := &DBHeader{Number: transfer.BlockNumber, Hash: transfer.BlockHash, Address: transfer.Address}
+		require.NoError(t, db.ProcessBlocks(transfer.Address, header.Number, header.Number, []*DBHeader{header}))
+	}
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+}
+
+func TestGetSpending(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	now := time.Now()
+	address := common.Address{1}
+	other := common.Address{2}
+	token := common.Address{0xaa}
+	saveTransfers(t, db,
+		ethTransferAt(1, address, other, 10, now.Add(-time.Hour), false),
+		ethTransferAt(2, address, other, 20, now.Add(-2*day), false),
+		ethTransferAt(3, address, other, 40, now.Add(-8*day), false),
+		// failed transactions and transfers to itself are not spendings
+		ethTransferAt(4, address, other, 80, now.Add(-time.Hour), true),
+		ethTransferAt(5, address, address, 160, now.Add(-time.Hour), false),
+		erc20TransferAt(6, token, address, other, 5, now.Add(-time.Minute)),
+		erc20TransferAt(7, token, address, other, 7, now.Add(-3*day)),
+		erc20TransferAt(8, token, other, address, 100, now.Add(-time.Minute)),
+	)
+
+	report, err := GetSpending(db, address, now)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), report.Daily.Eth.ToInt())
+	require.Equal(t, big.NewInt(30), report.Weekly.Eth.ToInt())
+	require.Equal(t, big.NewInt(5), report.Daily.Value(token))
+	require.Equal(t, big.NewInt(12), report.Weekly.Value(token))
+	require.Equal(t, big.NewInt(0), report.Daily.Value(common.Address{0xbb}))
+}
+
+func TestSpendingLimits(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	address := common.Address{1}
+	token := common.Address{0xaa}
+	limits := []SpendingLimit{
+		{Daily: (*hexutil.Big)(big.NewInt(100))},
+		{Token: token, Weekly: (*hexutil.Big)(big.NewInt(1000))},
+	}
+	for _, limit := range limits {
+		require.NoError(t, db.SaveSpendingLimit(address, limit))
+	}
+	rst, err := db.GetSpendingLimits(address)
+	require.NoError(t, err)
+	require.Equal(t, limits, rst)
+
+	require.NoError(t, db.SaveSpendingLimit(address, SpendingLimit{Token: token}))
+	rst, err = db.GetSpendingLimits(address)
+	require.NoError(t, err)
+	require.Equal(t, limits[:1], rst)
+}
+
+func TestSpendingMonitorNotifiesOnce(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	now := time.Now()
+	address := common.Address{1}
+	other := common.Address{2}
+	require.NoError(t, db.SaveSpendingLimit(address, SpendingLimit{
+		Daily:  (*hexutil.Big)(big.NewInt(15)),
+		Weekly: (*hexutil.Big)(big.NewInt(100)),
+	}))
+
+	var events []LimitReached
+	monitor := newSpendingMonitor(db)
+	monitor.now = func() time.Time { return now }
+	monitor.notify = func(event LimitReached) { events = append(events, event) }
+
+	saveTransfers(t, db, ethTransferAt(1, address, other, 10, now.Add(-time.Hour), false))
+	require.NoError(t, monitor.Check(address))
+	require.Empty(t, events)
+
+	saveTransfers(t, db, ethTransferAt(2, address, other, 10, now.Add(-time.Minute), false))
+	require.NoError(t, monitor.Check(address))
+	require.NoError(t, monitor.Check(address))
+	require.Len(t, events, 1)
+	require.Equal(t, dailyPeriod, events[0].Period)
+	require.Equal(t, big.NewInt(20), events[0].Spent.ToInt())
+
+	// the limit is reported again after spending drops below it
+	monitor.now = func() time.Time { return now.Add(day) }
+	require.NoError(t, monitor.Check(address))
+	monitor.now = func() time.Time { return now }
+	require.NoError(t, monitor.Check(address))
+	require.Len(t, events, 2)
+}
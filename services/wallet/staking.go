@@ -0,0 +1,184 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// depositContractABI describes the event emitted by the beacon chain deposit contract for every deposit.
+// The amount and the index are little endian encoded uint64, the amount is in gwei.
+const depositContractABI = `[
+{"anonymous":false,"inputs":[{"indexed":false,"name":"pubkey","type":"bytes"},{"indexed":false,"name":"withdrawal_credentials","type":"bytes"},{"indexed":false,"name":"amount","type":"bytes"},{"indexed":false,"name":"signature","type":"bytes"},{"indexed":false,"name":"index","type":"bytes"}],"name":"DepositEvent","type":"event"}
+]`
+
+var (
+	// depositContracts are addresses of beacon chain deposit contracts on mainnet, goerli, sepolia and holesky.
+	depositContracts = map[common.Address]bool{
+		common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa"): true,
+		common.HexToAddress("0xff50ed3d0ec03aC01D4C79aAd74928BFF48a7b2b"): true,
+		common.HexToAddress("0x7f02C3E3c98b133055B8B348B2Ac625669Ed295D"): true,
+		common.HexToAddress("0x4242424242424242424242424242424242424242"): true,
+	}
+	// withdrawalNetworks are chain IDs of networks where validator withdrawals are credited to accounts.
+	withdrawalNetworks = map[uint64]bool{1: true, 5: true, 11155111: true, 17000: true}
+
+	gweiInWei = big.NewInt(1e9)
+
+	depositContract abi.ABI
+	depositEvent    common.Hash
+)
+
+func init() {
+	var err error
+	depositContract, err = abi.JSON(strings.NewReader(depositContractABI))
+	if err != nil {
+		panic(err)
+	}
+	depositEvent = depositContract.Events["DepositEvent"].ID()
+}
+
+// hasWithdrawals returns true if validator withdrawals are credited to accounts on the chain.
+func hasWithdrawals(chain *big.Int) bool {
+	if chain == nil || !chain.IsUint64() {
+		return false
+	}
+	return withdrawalNetworks[chain.Uint64()]
+}
+
+// Withdrawal is a validator withdrawal included in a block, the amount is in gwei.
+type Withdrawal struct {
+	Index          hexutil.Uint64 `json:"index"`
+	ValidatorIndex hexutil.Uint64 `json:"validatorIndex"`
+	Address        common.Address `json:"address"`
+	Amount         hexutil.Uint64 `json:"amount"`
+}
+
+// Value returns the amount of the withdrawal in wei.
+func (w *Withdrawal) Value() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(uint64(w.Amount)), gweiInWei)
+}
+
+// StakingDeposit is a deposit to a validator made through the beacon chain deposit contract.
+type StakingDeposit struct {
+	// Pubkey is the public key of the validator.
+	Pubkey                hexutil.Bytes `json:"pubkey"`
+	WithdrawalCredentials hexutil.Bytes `json:"withdrawalCredentials"`
+	// Amount is the deposited amount in wei.
+	Amount *hexutil.Big `json:"amount"`
+	// Index is the index of the deposit in the deposit contract.
+	Index hexutil.Uint64 `json:"index"`
+}
+
+// StakingTransfer describes staking activity of a deposit or a withdrawal transfer.
+type StakingTransfer struct {
+	// Deposits are set for deposit transfers, a single transaction can deposit to many validators.
+	Deposits []StakingDeposit `json:"deposits,omitempty"`
+	// ValidatorIndex is set for withdrawal transfers.
+	ValidatorIndex *hexutil.Uint64 `json:"validatorIndex,omitempty"`
+}
+
+// isStakingDeposit returns true if the transaction made deposits to the beacon chain deposit contract.
+func isStakingDeposit(receipt *types.Receipt) bool {
+	if receipt == nil {
+		return false
+	}
+	for _, l := range receipt.Logs {
+		if depositContracts[l.Address] && len(l.Topics) > 0 && l.Topics[0] == depositEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStakingDeposits returns deposits made by the transaction, batch deposit
+// contracts emit many deposit events in a single transaction.
+func parseStakingDeposits(receipt *types.Receipt) []StakingDeposit {
+	if receipt == nil {
+		return nil
+	}
+	var rst []StakingDeposit
+	for _, l := range receipt.Logs {
+		if !depositContracts[l.Address] || len(l.Topics) == 0 || l.Topics[0] != depositEvent {
+			continue
+		}
+		var event struct {
+			Pubkey                []byte
+			WithdrawalCredentials []byte
+			Amount                []byte
+			Signature             []byte
+			Index                 []byte
+		}
+		if err := depositContract.Unpack(&event, "DepositEvent", l.Data); err != nil {
+			log.Warn("can't unpack deposit event", "tx", l.TxHash, "error", err)
+			continue
+		}
+		if len(event.Amount) != 8 || len(event.Index) != 8 {
+			log.Warn("unexpected length of deposit amount or index", "tx", l.TxHash)
+			continue
+		}
+		amount := new(big.Int).SetUint64(binary.LittleEndian.Uint64(event.Amount))
+		rst = append(rst, StakingDeposit{
+			Pubkey:                event.Pubkey,
+			WithdrawalCredentials: event.WithdrawalCredentials,
+			Amount:                (*hexutil.Big)(amount.Mul(amount, gweiInWei)),
+			Index:                 hexutil.Uint64(binary.LittleEndian.Uint64(event.Index)),
+		})
+	}
+	return rst
+}
+
+// parseStakingTransfer returns staking details of deposit and withdrawal transfers, nil for other transfers.
+func parseStakingTransfer(t *Transfer) *StakingTransfer {
+	switch t.Type {
+	case depositTransfer:
+		return &StakingTransfer{Deposits: parseStakingDeposits(t.Receipt)}
+	case withdrawalTransfer:
+		if t.Withdrawal == nil {
+			return nil
+		}
+		index := t.Withdrawal.ValidatorIndex
+		return &StakingTransfer{ValidatorIndex: &index}
+	}
+	return nil
+}
+
+// getWithdrawalTransfers returns withdrawals of the block credited to the accounts.
+// Blocks are requested without the client because it doesn't decode withdrawals.
+func getWithdrawalTransfers(ctx context.Context, client RPCClient, blk *types.Block, accounts []common.Address) ([]Transfer, error) {
+	var body struct {
+		Withdrawals []Withdrawal `json:"withdrawals"`
+	}
+	if err := client.CallContext(ctx, &body, "eth_getBlockByHash", blk.Hash(), false); err != nil {
+		return nil, err
+	}
+	var rst []Transfer
+	for i := range body.Withdrawals {
+		withdrawal := body.Withdrawals[i]
+		for _, address := range accounts {
+			if withdrawal.Address != address {
+				continue
+			}
+			index := [8]byte{}
+			binary.BigEndian.PutUint64(index[:], uint64(withdrawal.Index))
+			rst = append(rst, Transfer{
+				Type:        withdrawalTransfer,
+				ID:          crypto.Keccak256Hash(blk.Hash().Bytes(), index[:]),
+				Address:     address,
+				BlockNumber: blk.Number(),
+				BlockHash:   blk.Hash(),
+				Timestamp:   blk.Time(),
+				Withdrawal:  &withdrawal,
+			})
+		}
+	}
+	return rst, nil
+}
@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func depositLog(t *testing.T, contract common.Address, pubkey []byte, gwei, index uint64) *types.Log {
+	amount, idx := make([]byte, 8), make([]byte, 8)
+	binary.LittleEndian.PutUint64(amount, gwei)
+	binary.LittleEndian.PutUint64(idx, index)
+	data, err := depositContract.Events["DepositEvent"].Inputs.Pack(pubkey, []byte{0x01}, amount, []byte{0x02}, idx)
+	require.NoError(t, err)
+	return &types.Log{Address: contract, Topics: []common.Hash{depositEvent}, Data: data}
+}
+
+func TestParseStakingDeposits(t *testing.T) {
+	mainnet := common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+	receipt := &types.Receipt{Logs: []*types.Log{
+		depositLog(t, mainnet, []byte{0xaa}, 2e9, 7),
+		depositLog(t, mainnet, []byte{0xbb}, 1e9, 8),
+		// same event emitted by an unknown contract is ignored
+		depositLog(t, common.Address{1}, []byte{0xcc}, 1e9, 9),
+	}}
+	require.True(t, isStakingDeposit(receipt))
+	require.False(t, isStakingDeposit(&types.Receipt{Logs: receipt.Logs[2:]}))
+
+	deposits := parseStakingDeposits(receipt)
+	require.Equal(t, []StakingDeposit{
+		{Pubkey: []byte{0xaa}, WithdrawalCredentials: []byte{0x01}, Amount: (*hexutil.Big)(big.NewInt(2e18)), Index: 7},
+		{Pubkey: []byte{0xbb}, WithdrawalCredentials: []byte{0x01}, Amount: (*hexutil.Big)(big.NewInt(1e18)), Index: 8},
+	}, deposits)
+
+	tx := types.NewTransaction(1, mainnet, big.NewInt(3e18), 100000, big.NewInt(1), nil)
+	view := castToTransferView(Transfer{Type: depositTransfer, Transaction: tx, Receipt: receipt, From: common.Address{2}})
+	require.Equal(t, mainnet, view.To)
+	require.Equal(t, deposits, view.Staking.Deposits)
+}
+
+func TestWithdrawalTransfers(t *testing.T) {
+	account := common.Address{0xaa}
+	header := &types.Header{Number: big.NewInt(10), Time: 100}
+	blk := types.NewBlockWithHeader(header)
+	client := &fakeRPCClient{results: map[string]interface{}{
+		"eth_getBlockByHash": map[string]interface{}{"withdrawals": []Withdrawal{
+			{Index: 1, ValidatorIndex: 5, Address: account, Amount: 2e9},
+			{Index: 2, ValidatorIndex: 6, Address: common.Address{0xbb}, Amount: 3e9},
+		}},
+	}}
+
+	transfers, err := getWithdrawalTransfers(context.Background(), client, blk, []common.Address{account})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, withdrawalTransfer, transfers[0].Type)
+	require.Equal(t, blk.Hash(), client.calls["eth_getBlockByHash"][0])
+	require.Equal(t, big.NewInt(2e18), transfers[0].Withdrawal.Value())
+
+	_, in, out := chartValues(&transfers[0])
+	require.Equal(t, 2e18, in)
+	require.Zero(t, out)
+	require.Equal(t, big.NewInt(2e18), ethBalanceChange(account, transfers))
+
+	db, stop := setupTestDB(t)
+	defer stop()
+	dbHeader := &DBHeader{Number: blk.Number(), Hash: blk.Hash(), Address: account}
+	require.NoError(t, db.ProcessBlocks(account, blk.Number(), blk.Number(), []*DBHeader{dbHeader}))
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+
+	loaded, err := db.GetTransfersByAddress(account, big.NewInt(10), 10)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.Nil(t, loaded[0].Transaction)
+	require.Equal(t, transfers[0].Withdrawal, loaded[0].Withdrawal)
+
+	view := castToTransferView(loaded[0])
+	require.Equal(t, account, view.To)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(2e18)), view.Value)
+	require.Equal(t, hexutil.Uint64(5), *view.Staking.ValidatorIndex)
+}
+
+func TestHasWithdrawals(t *testing.T) {
+	require.True(t, hasWithdrawals(big.NewInt(1)))
+	require.False(t, hasWithdrawals(big.NewInt(10)))
+	require.False(t, hasWithdrawals(nil))
+}
@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BlockRange is an inclusive range of blocks.
+type BlockRange struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+// SyncState describes the progress of transfers download for a single address.
+type SyncState struct {
+	Address common.Address `json:"address"`
+	// FirstScannedBlock is the lowest checked block, nil if nothing was checked yet.
+	FirstScannedBlock *hexutil.Big `json:"firstScannedBlock"`
+	// LastScannedBlock is the highest checked block, nil if nothing was checked yet.
+	LastScannedBlock *hexutil.Big `json:"lastScannedBlock"`
+	// InProgress are ranges of blocks that are being checked.
+	InProgress []BlockRange `json:"inProgress"`
+	// Error is the last error of the downloader, it is reset after successful sync.
+	Error string `json:"error,omitempty"`
+}
+
+// syncTracker keeps in-memory state of running downloads. All methods are safe
+// to call on a nil tracker.
+type syncTracker struct {
+	mu       sync.Mutex
+	progress map[common.Address][]*BlockRange
+	errors   map[common.Address]error
+}
+
+func newSyncTracker() *syncTracker {
+	return &syncTracker{
+		progress: map[common.Address][]*BlockRange{},
+		errors:   map[common.Address]error{},
+	}
+}
+
+// start marks a range as in progress. Returned function must be called once the range is processed.
+func (t *syncTracker) start(address common.Address, from, to *big.Int) func() {
+	if t == nil {
+		return func() {}
+	}
+	r := &BlockRange{From: (*hexutil.Big)(from), To: (*hexutil.Big)(to)}
+	t.mu.Lock()
+	t.progress[address] = append(t.progress[address], r)
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		ranges := t.progress[address]
+		for i := range ranges {
+			if ranges[i] == r {
+				t.progress[address] = append(ranges[:i], ranges[i+1:]...)
+				break
+			}
+		}
+		if len(t.progress[address]) == 0 {
+			delete(t.progress, address)
+		}
+	}
+}
+
+// setError records the last error for every address. Nil error resets it.
+func (t *syncTracker) setError(addresses []common.Address, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, address := range addresses {
+		if err == nil {
+			delete(t.errors, address)
+		} else {
+			t.errors[address] = err
+		}
+	}
+}
+
+func (t *syncTracker) fill(state *SyncState) {
+	state.InProgress = []BlockRange{}
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.progress[state.Address] {
+		state.InProgress = append(state.InProgress, *r)
+	}
+	if err := t.errors[state.Address]; err != nil {
+		state.Error = err.Error()
+	}
+}
+
+// GetSyncState combines checked ranges stored in the database with the state of running downloads.
+func GetSyncState(db *Database, tracker *syncTracker, address common.Address) (*SyncState, error) {
+	first, err := db.GetFirstKnownBlock(address)
+	if err != nil {
+		return nil, err
+	}
+	last, err := db.GetLastKnownBlockByAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	state := &SyncState{
+		Address:           address,
+		FirstScannedBlock: (*hexutil.Big)(first),
+		LastScannedBlock:  (*hexutil.Big)(last),
+	}
+	tracker.fill(state)
+	return state, nil
+}
@@ -0,0 +1,43 @@
+package wallet
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestGetSyncState(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+	address := common.Address{1}
+	tracker := newSyncTracker()
+
+	state, err := GetSyncState(db, tracker, address)
+	require.NoError(t, err)
+	require.Nil(t, state.FirstScannedBlock)
+	require.Nil(t, state.LastScannedBlock)
+	require.Empty(t, state.InProgress)
+
+	require.NoError(t, db.ProcessBlocks(address, big.NewInt(0), big.NewInt(10), nil))
+	done := tracker.start(address, big.NewInt(11), big.NewInt(20))
+	tracker.setError([]common.Address{address}, errors.New("test"))
+
+	state, err = GetSyncState(db, tracker, address)
+	require.NoError(t, err)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(0)), state.FirstScannedBlock)
+	require.Equal(t, (*hexutil.Big)(big.NewInt(10)), state.LastScannedBlock)
+	require.Equal(t, []BlockRange{{From: (*hexutil.Big)(big.NewInt(11)), To: (*hexutil.Big)(big.NewInt(20))}}, state.InProgress)
+	require.Equal(t, "test", state.Error)
+
+	done()
+	tracker.setError([]common.Address{address}, nil)
+	state, err = GetSyncState(db, tracker, address)
+	require.NoError(t, err)
+	require.Empty(t, state.InProgress)
+	require.Empty(t, state.Error)
+}
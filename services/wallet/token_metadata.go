@@ -0,0 +1,126 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// erc20MetadataABI describes optional erc20 methods that are not part of ierc20.
+const erc20MetadataABI = `[
+{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"}
+]`
+
+var erc20Metadata abi.ABI
+
+func init() {
+	var err error
+	erc20Metadata, err = abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FetchTokenMetadata reads name, symbol and decimals of an erc20 contract using eth_call.
+func FetchTokenMetadata(ctx context.Context, client ethereum.ContractCaller, contract common.Address) (*Token, error) {
+	name, err := callStringMethod(ctx, client, contract, "name")
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := callStringMethod(ctx, client, contract, "symbol")
+	if err != nil {
+		return nil, err
+	}
+	output, err := callMethod(ctx, client, contract, "decimals")
+	if err != nil {
+		return nil, err
+	}
+	var decimals uint8
+	if err := erc20Metadata.Unpack(&decimals, "decimals", output); err != nil {
+		return nil, err
+	}
+	return &Token{
+		Address:  contract,
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: uint(decimals),
+	}, nil
+}
+
+func callMethod(ctx context.Context, client ethereum.ContractCaller, contract common.Address, method string) ([]byte, error) {
+	input, err := erc20Metadata.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	return client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: input}, nil)
+}
+
+// callStringMethod calls a method returning a string. Some early tokens return bytes32
+// instead of string, such values are trimmed from trailing zeros.
+func callStringMethod(ctx context.Context, client ethereum.ContractCaller, contract common.Address, method string) (string, error) {
+	output, err := callMethod(ctx, client, contract, method)
+	if err != nil {
+		return "", err
+	}
+	var rst string
+	if err := erc20Metadata.Unpack(&rst, method, output); err != nil {
+		if len(output) != common.HashLength {
+			return "", err
+		}
+		return string(bytes.TrimRight(output, "\x00")), nil
+	}
+	return rst, nil
+}
+
+// GetTokensMetadata returns metadata for given contracts. Metadata missing in the database
+// is fetched from contracts and persisted. Contracts that failed to respond are omitted.
+func GetTokensMetadata(ctx context.Context, db *Database, client ethereum.ContractCaller, contracts []common.Address) (map[common.Address]*Token, error) {
+	tokens, err := db.GetTokensMetadata(contracts)
+	if err != nil {
+		return nil, err
+	}
+	for _, contract := range contracts {
+		if _, exist := tokens[contract]; exist {
+			continue
+		}
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		token, err := FetchTokenMetadata(callCtx, client, contract)
+		cancel()
+		if err != nil {
+			log.Warn("failed to fetch token metadata", "contract", contract, "error", err)
+			continue
+		}
+		if err := db.SaveTokenMetadata(*token); err != nil {
+			return nil, err
+		}
+		tokens[contract] = token
+	}
+	return tokens, nil
+}
+
+// tokenContracts returns unique contracts of erc20 transfers.
+func tokenContracts(transfers []Transfer) []common.Address {
+	var (
+		rst  []common.Address
+		seen = map[common.Address]bool{}
+	)
+	for i := range transfers {
+		if transfers[i].Type != erc20Transfer || transfers[i].Log == nil {
+			continue
+		}
+		contract := transfers[i].Log.Address
+		if !seen[contract] {
+			seen[contract] = true
+			rst = append(rst, contract)
+		}
+	}
+	return rst
+}
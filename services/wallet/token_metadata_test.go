@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type tokenTestClient struct {
+	// outputs of contract calls indexed by contract and method name
+	outputs map[common.Address]map[string][]byte
+	calls   int
+}
+
+func (c *tokenTestClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	method, err := erc20Metadata.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	output, exist := c.outputs[*msg.To][method.Name]
+	if !exist {
+		return nil, errors.New("execution reverted")
+	}
+	return output, nil
+}
+
+func packOutput(t *testing.T, method string, value interface{}) []byte {
+	output, err := erc20Metadata.Methods[method].Outputs.Pack(value)
+	require.NoError(t, err)
+	return output
+}
+
+func TestFetchTokenMetadata(t *testing.T) {
+	token := common.Address{1}
+	legacy := common.Address{2}
+	var symbol common.Hash
+	copy(symbol[:], "MKR")
+	client := &tokenTestClient{outputs: map[common.Address]map[string][]byte{
+		token: {
+			"name":     packOutput(t, "name", "Status Network Token"),
+			"symbol":   packOutput(t, "symbol", "SNT"),
+			"decimals": packOutput(t, "decimals", uint8(18)),
+		},
+		legacy: {
+			"name":     symbol[:],
+			"symbol":   symbol[:],
+			"decimals": packOutput(t, "decimals", uint8(18)),
+		},
+	}}
+
+	rst, err := FetchTokenMetadata(context.Background(), client, token)
+	require.NoError(t, err)
+	require.Equal(t, &Token{Address: token, Name: "Status Network Token", Symbol: "SNT", Decimals: 18}, rst)
+
+	rst, err = FetchTokenMetadata(context.Background(), client, legacy)
+	require.NoError(t, err)
+	require.Equal(t, "MKR", rst.Symbol)
+
+	_, err = FetchTokenMetadata(context.Background(), client, common.Address{3})
+	require.Error(t, err)
+}
+
+func TestGetTokensMetadata(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	token := common.Address{1}
+	unknown := common.Address{2}
+	client := &tokenTestClient{outputs: map[common.Address]map[string][]byte{
+		token: {
+			"name":     packOutput(t, "name", "Status Network Token"),
+			"symbol":   packOutput(t, "symbol", "SNT"),
+			"decimals": packOutput(t, "decimals", uint8(18)),
+		},
+	}}
+
+	tokens, err := GetTokensMetadata(context.Background(), db, client, []common.Address{token, unknown})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "SNT", tokens[token].Symbol)
+
+	// metadata is persisted and not fetched again
+	client.calls = 0
+	tokens, err = GetTokensMetadata(context.Background(), db, client, []common.Address{token})
+	require.NoError(t, err)
+	require.Equal(t, "SNT", tokens[token].Symbol)
+	require.Equal(t, 0, client.calls)
+}
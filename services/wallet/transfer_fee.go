@@ -0,0 +1,38 @@
+package wallet
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TransferFee is the gas cost of the transaction that made an erc20 transfer. Transfers made
+// by the same transaction share it.
+type TransferFee struct {
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	// Paid is the fee paid by the address, including the layer 1 fee on Optimism.
+	// It is omitted if the transaction was sent by another address.
+	Paid *hexutil.Big `json:"paid,omitempty"`
+}
+
+// transferFee returns the fee of the transaction from its stored receipt, nil if the transfer
+// was stored without the transaction or the receipt.
+func transferFee(t Transfer) *TransferFee {
+	if t.Transaction == nil || t.Receipt == nil {
+		return nil
+	}
+	gasPrice := t.Transaction.GasPrice()
+	fee := &TransferFee{
+		GasUsed:  hexutil.Uint64(t.Receipt.GasUsed),
+		GasPrice: (*hexutil.Big)(gasPrice),
+	}
+	if t.From == t.Address {
+		paid := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(t.Receipt.GasUsed))
+		if t.L2Fee != nil && t.L2Fee.L1Fee != nil {
+			paid.Add(paid, t.L2Fee.L1Fee.ToInt())
+		}
+		fee.Paid = (*hexutil.Big)(paid)
+	}
+	return fee
+}
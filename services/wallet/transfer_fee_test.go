@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestTransferFeeFromStoredReceipt(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	address := common.Address{1}
+	header := &DBHeader{Number: big.NewInt(1), Hash: common.Hash{1}, Address: address}
+	tx := types.NewTransaction(1, common.Address{2}, nil, 100000, big.NewInt(10), nil)
+	receipt := types.NewReceipt(nil, false, 60000)
+	receipt.GasUsed = 50000
+	receipt.Logs = []*types.Log{}
+	transferLog := &types.Log{
+		Address: common.Address{3},
+		Topics:  []common.Hash{{}, common.BytesToHash(address.Bytes()), common.BytesToHash(common.Address{4}.Bytes())},
+		Data:    common.BigToHash(big.NewInt(5)).Bytes(),
+	}
+	transfers := []Transfer{{
+		ID:          common.Hash{1},
+		Type:        erc20Transfer,
+		BlockHash:   header.Hash,
+		BlockNumber: header.Number,
+		Transaction: tx,
+		Receipt:     receipt,
+		Log:         transferLog,
+		Address:     address,
+		From:        address,
+		L2Fee:       &L2Fee{L1Fee: (*hexutil.Big)(big.NewInt(7))},
+	}}
+	require.NoError(t, db.ProcessBlocks(address, big.NewInt(1), big.NewInt(1), []*DBHeader{header}))
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+
+	stored, err := db.GetTransfersByAddress(address, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	view := castToTransferView(stored[0])
+	require.Equal(t, &TransferFee{
+		GasUsed:  50000,
+		GasPrice: (*hexutil.Big)(big.NewInt(10)),
+		Paid:     (*hexutil.Big)(big.NewInt(500007)),
+	}, view.Fee)
+}
+
+func TestTransferFeeOfReceivedTransfer(t *testing.T) {
+	tx := types.NewTransaction(1, common.Address{2}, nil, 100000, big.NewInt(10), nil)
+	receipt := types.NewReceipt(nil, false, 50000)
+	receipt.GasUsed = 50000
+	fee := transferFee(Transfer{Transaction: tx, Receipt: receipt, Address: common.Address{1}, From: common.Address{2}})
+	require.Equal(t, hexutil.Uint64(50000), fee.GasUsed)
+	require.Equal(t, big.NewInt(10), fee.GasPrice.ToInt())
+	require.Nil(t, fee.Paid)
+
+	require.Nil(t, transferFee(Transfer{Transaction: tx}))
+}
@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrTransferNotFound returned when the transaction doesn't exist or doesn't transfer assets of the address.
+	ErrTransferNotFound = errors.New("transfer not found")
+	// ErrTransactionPending returned when the transaction is not mined yet.
+	ErrTransactionPending = errors.New("transaction is pending")
+)
+
+// TransactionReader interface for reading transactions and their receipts.
+type TransactionReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+type transferClient interface {
+	HeaderReader
+	TransactionReader
+}
+
+// GetTransferByHash returns a transfer of the address made by the transaction with a given hash.
+// If the transfer is not in the database it is fetched from the chain and persisted.
+func GetTransferByHash(ctx context.Context, db *Database, client transferClient, signer types.Signer, address common.Address, txHash common.Hash) (*Transfer, error) {
+	transfer, err := db.GetTransferByHash(address, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if transfer != nil {
+		return transfer, nil
+	}
+
+	transfer, err = fetchTransfer(ctx, client, signer, address, txHash)
+	if err != nil {
+		return nil, err
+	}
+	err = db.SaveTransferWithBlock(*transfer)
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// fetchTransfer downloads the transaction, its receipt and block header and builds
+// a transfer the same way as downloaders do it.
+func fetchTransfer(ctx context.Context, client transferClient, signer types.Signer, address common.Address, txHash common.Hash) (*Transfer, error) {
+	tx, pending, err := client.TransactionByHash(ctx, txHash)
+	if err == ethereum.NotFound {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrTransactionPending
+	}
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	header, err := client.HeaderByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		Address:     address,
+		BlockNumber: new(big.Int).Set(receipt.BlockNumber),
+		BlockHash:   receipt.BlockHash,
+		Timestamp:   header.Time,
+		Transaction: tx,
+		From:        from,
+		Receipt:     receipt,
+		Loaded:      true,
+	}
+
+	if tokenLog := getAddressTokenLog(receipt.Logs, address); tokenLog != nil {
+		index := [4]byte{}
+		binary.BigEndian.PutUint32(index[:], uint32(tokenLog.Index))
+		transfer.ID = crypto.Keccak256Hash(tokenLog.TxHash.Bytes(), index[:])
+		transfer.Type = erc20Transfer
+		transfer.Log = tokenLog
+		return transfer, nil
+	}
+
+	// Same as ETHTransferDownloader, transactions with token transfers are not eth transfers
+	if getTokenLog(receipt.Logs) == nil && (from == address || (tx.To() != nil && *tx.To() == address)) {
+		transfer.ID = tx.Hash()
+		transfer.Type = ethTransfer
+		if from == address && isStakingDeposit(receipt) {
+			transfer.Type = depositTransfer
+		}
+		return transfer, nil
+	}
+
+	return nil, ErrTransferNotFound
+}
+
+// getAddressTokenLog returns the first erc20 Transfer log that moves tokens from or to the address.
+func getAddressTokenLog(logs []*types.Log, address common.Address) *types.Log {
+	signature := crypto.Keccak256Hash([]byte(erc20TransferEventSignature))
+	padded := common.BytesToHash(address.Bytes())
+	for _, l := range logs {
+		if len(l.Topics) == 3 && l.Topics[0] == signature && (l.Topics[1] == padded || l.Topics[2] == padded) {
+			return l
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type transferTestClient struct {
+	headers      map[common.Hash]*types.Header
+	transactions map[common.Hash]*types.Transaction
+	receipts     map[common.Hash]*types.Receipt
+	calls        int
+}
+
+func (c *transferTestClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return c.headers[hash], nil
+}
+
+func (c *transferTestClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return nil, ethereum.NotFound
+}
+
+func (c *transferTestClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.calls++
+	tx, exist := c.transactions[hash]
+	if !exist {
+		return nil, false, ethereum.NotFound
+	}
+	return tx, false, nil
+}
+
+func (c *transferTestClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return c.receipts[hash], nil
+}
+
+func (c *transferTestClient) addTransaction(tx *types.Transaction, logs []*types.Log) {
+	header := &types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(1), Time: 100}
+	c.headers[header.Hash()] = header
+	c.transactions[tx.Hash()] = tx
+	for _, l := range logs {
+		l.TxHash = tx.Hash()
+	}
+	c.receipts[tx.Hash()] = &types.Receipt{
+		TxHash:      tx.Hash(),
+		BlockHash:   header.Hash(),
+		BlockNumber: header.Number,
+		Logs:        logs,
+	}
+}
+
+func TestGetTransferByHash(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.Address{1}
+	signer := types.NewEIP155Signer(big.NewInt(1777))
+
+	tx, err := types.SignTx(types.NewTransaction(0, recipient, big.NewInt(100), 21000, big.NewInt(1), nil), signer, key)
+	require.NoError(t, err)
+
+	client := &transferTestClient{
+		headers:      map[common.Hash]*types.Header{},
+		transactions: map[common.Hash]*types.Transaction{},
+		receipts:     map[common.Hash]*types.Receipt{},
+	}
+	client.addTransaction(tx, []*types.Log{})
+
+	transfer, err := GetTransferByHash(context.Background(), db, client, signer, recipient, tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, ethTransfer, transfer.Type)
+	require.Equal(t, tx.Hash(), transfer.ID)
+	require.Equal(t, sender, transfer.From)
+	require.Equal(t, uint64(100), transfer.Timestamp)
+	require.Equal(t, 1, client.calls)
+
+	// second lookup is served from the database
+	transfer, err = GetTransferByHash(context.Background(), db, client, signer, recipient, tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), transfer.ID)
+	require.Equal(t, 1, client.calls)
+
+	transfers, err := db.GetTransfersByAddress(recipient, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+
+	_, err = GetTransferByHash(context.Background(), db, client, signer, common.Address{2}, tx.Hash())
+	require.Equal(t, ErrTransferNotFound, err)
+
+	_, err = GetTransferByHash(context.Background(), db, client, signer, recipient, common.Hash{1})
+	require.Equal(t, ErrTransferNotFound, err)
+}
+
+func TestGetERC20TransferByHash(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	token := common.Address{5}
+	recipient := common.Address{1}
+	signer := types.NewEIP155Signer(big.NewInt(1777))
+
+	tx, err := types.SignTx(types.NewTransaction(0, token, big.NewInt(0), 100000, big.NewInt(1), nil), signer, key)
+	require.NoError(t, err)
+
+	client := &transferTestClient{
+		headers:      map[common.Hash]*types.Header{},
+		transactions: map[common.Hash]*types.Transaction{},
+		receipts:     map[common.Hash]*types.Receipt{},
+	}
+	client.addTransaction(tx, []*types.Log{{
+		Address: token,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte(erc20TransferEventSignature)),
+			common.BytesToHash(crypto.PubkeyToAddress(key.PublicKey).Bytes()),
+			common.BytesToHash(recipient.Bytes()),
+		},
+		Index: 3,
+	}})
+
+	transfer, err := GetTransferByHash(context.Background(), db, client, signer, recipient, tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, erc20Transfer, transfer.Type)
+	require.Equal(t, uint(3), transfer.Log.Index)
+
+	stored, err := db.GetTransferByHash(recipient, tx.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.Equal(t, transfer.ID, stored.ID)
+}
@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// maxTransferNoteLength is the maximum length of a note in bytes.
+	maxTransferNoteLength = 1000
+	// maxTransferTags is the maximum number of tags of a transfer.
+	maxTransferTags = 20
+	// maxTransferTagLength is the maximum length of a tag in bytes.
+	maxTransferTagLength = 64
+)
+
+var (
+	// ErrTransferNoteTooLong is returned when a note exceeds maxTransferNoteLength.
+	ErrTransferNoteTooLong = errors.New("transfer note is too long")
+	// ErrInvalidTransferTags is returned when there are too many tags or a tag is too long.
+	ErrInvalidTransferTags = errors.New("too many transfer tags or a tag is too long")
+)
+
+// TransferNote is a note and tags attached to a transfer by the user. They are stored
+// only locally.
+type TransferNote struct {
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+// TransfersOptions are optional filters of transfers.
+type TransfersOptions struct {
+	// Tags select transfers with at least one of the tags.
+	Tags []string `json:"tags"`
+}
+
+// normalizeTransferNote trims the note and tags, tags are lower-cased, deduplicated and sorted.
+func normalizeTransferNote(note string, tags []string) (TransferNote, error) {
+	rst := TransferNote{Note: strings.TrimSpace(note), Tags: normalizeTags(tags)}
+	if len(rst.Note) > maxTransferNoteLength {
+		return rst, ErrTransferNoteTooLong
+	}
+	if len(rst.Tags) > maxTransferTags {
+		return rst, ErrInvalidTransferTags
+	}
+	for _, tag := range rst.Tags {
+		if len(tag) > maxTransferTagLength {
+			return rst, ErrInvalidTransferTags
+		}
+	}
+	return rst, nil
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	rst := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		rst = append(rst, tag)
+	}
+	sort.Strings(rst)
+	return rst
+}
+
+// setTransferNotes attaches notes and tags to transfer views.
+func setTransferNotes(views []TransferView, notes map[common.Hash]TransferNote) {
+	for i := range views {
+		if note, ok := notes[views[i].ID]; ok {
+			views[i].Note = note.Note
+			views[i].Tags = note.Tags
+		}
+	}
+}
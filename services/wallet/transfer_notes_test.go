@@ -0,0 +1,82 @@
+package wallet
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestNormalizeTransferNote(t *testing.T) {
+	note, err := normalizeTransferNote("  rent  ", []string{"Home", " bills", "home", ""})
+	require.NoError(t, err)
+	require.Equal(t, TransferNote{Note: "rent", Tags: []string{"bills", "home"}}, note)
+
+	_, err = normalizeTransferNote(strings.Repeat("a", maxTransferNoteLength+1), nil)
+	require.Equal(t, ErrTransferNoteTooLong, err)
+	_, err = normalizeTransferNote("", []string{strings.Repeat("a", maxTransferTagLength+1)})
+	require.Equal(t, ErrInvalidTransferTags, err)
+}
+
+func TestTransferNotes(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	address := common.Address{1}
+	header := &DBHeader{Number: big.NewInt(1), Hash: common.Hash{1}, Address: address}
+	var transfers []Transfer
+	for i := byte(1); i <= 3; i++ {
+		receipt := types.NewReceipt(nil, false, 21000)
+		receipt.Logs = []*types.Log{}
+		transfers = append(transfers, Transfer{
+			ID:          common.Hash{i},
+			Type:        ethTransfer,
+			BlockHash:   header.Hash,
+			BlockNumber: header.Number,
+			Transaction: types.NewTransaction(uint64(i), common.Address{2}, big.NewInt(1), 21000, big.NewInt(10), nil),
+			Receipt:     receipt,
+			Address:     address,
+		})
+	}
+	require.NoError(t, db.ProcessBlocks(address, big.NewInt(1), big.NewInt(1), []*DBHeader{header}))
+	require.NoError(t, db.ProcessTranfers(transfers, []*DBHeader{}))
+
+	require.NoError(t, db.SaveTransferNote(common.Hash{1}, TransferNote{Note: "rent", Tags: []string{"bills", "home"}}))
+	require.NoError(t, db.SaveTransferNote(common.Hash{2}, TransferNote{Tags: []string{"food"}}))
+
+	notes, err := db.GetTransferNotes([]common.Hash{{1}, {2}, {3}})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]TransferNote{
+		{1}: {Note: "rent", Tags: []string{"bills", "home"}},
+		{2}: {Tags: []string{"food"}},
+	}, notes)
+
+	views := castToTransferViews(transfers)
+	setTransferNotes(views, notes)
+	require.Equal(t, "rent", views[0].Note)
+	require.Equal(t, []string{"food"}, views[1].Tags)
+	require.Empty(t, views[2].Tags)
+
+	// transfers with any of the tags are selected
+	tagged, err := db.GetTaggedTransfersByAddress(address, nil, 10, []string{"home", "food"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 2)
+	tagged, err = db.GetTaggedTransfersByAddress(address, nil, 10, []string{"bills"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	require.Equal(t, common.Hash{1}, tagged[0].ID)
+
+	// a note is replaced and removed with an empty note without tags
+	require.NoError(t, db.SaveTransferNote(common.Hash{1}, TransferNote{Note: "rent for May"}))
+	require.NoError(t, db.SaveTransferNote(common.Hash{2}, TransferNote{}))
+	notes, err = db.GetTransferNotes([]common.Hash{{1}, {2}})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Hash]TransferNote{{1}: {Note: "rent for May", Tags: []string{}}}, notes)
+	tagged, err = db.GetTaggedTransfersByAddress(address, nil, 10, []string{"home", "food"})
+	require.NoError(t, err)
+	require.Empty(t, tagged)
+}
@@ -25,6 +25,17 @@ func castToTransferView(t Transfer) TransferView {
 	view.BlockNumber = (*hexutil.Big)(t.BlockNumber)
 	view.BlockHash = t.BlockHash
 	view.Timestamp = hexutil.Uint64(t.Timestamp)
+	view.L2Fee = t.L2Fee
+	view.Staking = parseStakingTransfer(&t)
+	if t.Type == withdrawalTransfer {
+		// withdrawals don't have transactions, they are credited by the consensus layer
+		view.TxStatus = hexutil.Uint64(types.ReceiptStatusSuccessful)
+		view.To = t.Address
+		if t.Withdrawal != nil {
+			view.Value = (*hexutil.Big)(t.Withdrawal.Value())
+		}
+		return view
+	}
 	view.GasPrice = (*hexutil.Big)(t.Transaction.GasPrice())
 	view.GasLimit = hexutil.Uint64(t.Transaction.Gas())
 	view.GasUsed = hexutil.Uint64(t.Receipt.GasUsed)
@@ -32,8 +43,9 @@ func castToTransferView(t Transfer) TransferView {
 	view.TxStatus = hexutil.Uint64(t.Receipt.Status)
 	view.Input = hexutil.Bytes(t.Transaction.Data())
 	view.TxHash = t.Transaction.Hash()
+	view.Bridge = parseBridgeTransfer(t.Receipt)
 	switch t.Type {
-	case ethTransfer:
+	case ethTransfer, depositTransfer:
 		view.From = t.From
 		if t.Transaction.To() != nil {
 			view.To = *t.Transaction.To()
@@ -44,10 +56,20 @@ func castToTransferView(t Transfer) TransferView {
 		view.Contract = t.Log.Address
 		from, to, amount := parseLog(t.Log)
 		view.From, view.To, view.Value = from, to, (*hexutil.Big)(amount)
+		view.Fee = transferFee(t)
 	}
 	return view
 }
 
+// setTokensMetadata attaches metadata of contracts to erc20 transfer views.
+func setTokensMetadata(views []TransferView, tokens map[common.Address]*Token) {
+	for i := range views {
+		if views[i].Type == erc20Transfer {
+			views[i].Token = tokens[views[i].Contract]
+		}
+	}
+}
+
 func parseLog(ethlog *types.Log) (from, to common.Address, amount *big.Int) {
 	if len(ethlog.Topics) < 3 {
 		log.Warn("not enough topics for erc20 transfer", "topics", ethlog.Topics)
@@ -91,4 +113,28 @@ type TransferView struct {
 	From        common.Address `json:"from"`
 	To          common.Address `json:"to"`
 	Contract    common.Address `json:"contract"`
+	// Token is metadata of the erc20 contract, it is nil for eth transfers
+	// and for contracts that don't expose metadata.
+	Token *Token `json:"token,omitempty"`
+	// FiatValue is the value of the transfer in the requested currency at the day of the transfer.
+	// It is omitted if the currency wasn't requested or the price is not known.
+	FiatValue *float64 `json:"fiatValue,omitempty"`
+	// Counterparty is a known contact from the address book that sent or received the transfer.
+	Counterparty *ContactAddress `json:"counterparty,omitempty"`
+	// L2Fee is the layer 1 part of the fee of a transfer on a layer 2 network.
+	L2Fee *L2Fee `json:"l2Fee,omitempty"`
+	// Fee is the fee of the transaction of an erc20 transfer, it is nil for eth transfers.
+	Fee *TransferFee `json:"fee,omitempty"`
+	// Bridge is set if the transfer went through a bridge between layer 1 and layer 2.
+	Bridge *BridgeTransfer `json:"bridge,omitempty"`
+	// Call is the decoded contract call made by the transaction, it is nil for plain eth transfers
+	// and calls of unknown methods.
+	Call *DecodedCall `json:"call,omitempty"`
+	// Underlying is the value in the underlying asset if the token is a registered interest-bearing token.
+	Underlying *UnderlyingValue `json:"underlying,omitempty"`
+	// Staking is set for deposits to the beacon chain and validator withdrawals.
+	Staking *StakingTransfer `json:"staking,omitempty"`
+	// Note and Tags are attached to the transfer by the user with SetTransferNote.
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
 }
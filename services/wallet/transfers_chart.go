@@ -0,0 +1,152 @@
+package wallet
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const maxTransfersChartBuckets = 1000
+
+var (
+	// ErrInvalidTransfersChartRange is returned if the range of the chart is empty.
+	ErrInvalidTransfersChartRange = errors.New("from must be before to")
+	// ErrInvalidTransfersChartBucket is returned if the bucket size is zero or the range has too many buckets.
+	ErrInvalidTransfersChartBucket = errors.New("bucket must be positive and the range must have at most 1000 buckets")
+)
+
+// TransfersChartPoint aggregates transfers of an address in a token within a time bucket.
+// Sums are computed as floating point numbers, which is precise enough for rendering a chart.
+type TransfersChartPoint struct {
+	Address common.Address `json:"address"`
+	// Token is an address of the token contract, zero address for ether.
+	Token common.Address `json:"token"`
+	// Timestamp is the start of the bucket.
+	Timestamp uint64 `json:"timestamp"`
+	// SumIn and SumOut are received and sent values in the smallest units of the token.
+	SumIn  float64 `json:"sumIn"`
+	SumOut float64 `json:"sumOut"`
+	Count  uint64  `json:"count"`
+}
+
+// chartValues returns a token address (zero for ether) and values received and sent by the address
+// of the transfer. Values of failed transactions are zero.
+func chartValues(transfer *Transfer) (token common.Address, in, out float64) {
+	var (
+		from, to common.Address
+		amount   *big.Int
+	)
+	switch transfer.Type {
+	case ethTransfer, depositTransfer:
+		tx := transfer.Transaction
+		if tx == nil || (transfer.Receipt != nil && transfer.Receipt.Status != 1) {
+			return
+		}
+		from, amount = transfer.From, tx.Value()
+		if tx.To() != nil {
+			to = *tx.To()
+		}
+	case erc20Transfer:
+		l := transfer.Log
+		if l == nil || len(l.Topics) != 3 || l.Topics[0] != crypto.Keccak256Hash([]byte(erc20TransferEventSignature)) {
+			return
+		}
+		token = l.Address
+		from, to = common.BytesToAddress(l.Topics[1].Bytes()), common.BytesToAddress(l.Topics[2].Bytes())
+		amount = new(big.Int).SetBytes(l.Data)
+	case withdrawalTransfer:
+		if transfer.Withdrawal == nil {
+			return
+		}
+		to, amount = transfer.Address, transfer.Withdrawal.Value()
+	default:
+		return
+	}
+	value, _ := new(big.Float).SetInt(amount).Float64()
+	if to == transfer.Address {
+		in = value
+	}
+	if from == transfer.Address {
+		out = value
+	}
+	return
+}
+
+// fillTransfersChartValues computes chart values of transfers stored before the values were added.
+func (db *Database) fillTransfersChartValues(addresses []common.Address) (err error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+	update, err := tx.Prepare("UPDATE transfers SET token = ?, amount_in = ?, amount_out = ? WHERE network_id = ? AND address = ? AND hash = ?")
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		query := newTransfersQuery().FilterNetwork(db.network).FilterAddress(address).FilterLoaded(1).FilterChartValuesMissing()
+		rows, err := tx.Query(query.String(), query.Args()...)
+		if err != nil {
+			return err
+		}
+		transfers, err := query.Scan(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		for i := range transfers {
+			token, in, out := chartValues(&transfers[i])
+			if _, err := update.Exec(token, in, out, db.network, address, transfers[i].ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetTransfersChart aggregates transfers of the addresses with timestamps in the [from, to) range
+// in buckets of the given size in seconds. Buckets without transfers are omitted.
+func (db *Database) GetTransfersChart(addresses []common.Address, from, to, bucket uint64) ([]TransfersChartPoint, error) {
+	if from >= to {
+		return nil, ErrInvalidTransfersChartRange
+	}
+	if bucket == 0 || (to-from-1)/bucket+1 > maxTransfersChartBuckets {
+		return nil, ErrInvalidTransfersChartBucket
+	}
+	if err := db.fillTransfersChartValues(addresses); err != nil {
+		return nil, err
+	}
+	rst := []TransfersChartPoint{}
+	for _, address := range addresses {
+		rows, err := db.db.Query(`SELECT ? + (timestamp - ?) / ? * ? AS bucket, token, SUM(amount_in), SUM(amount_out), COUNT(*)
+		FROM transfers
+		WHERE network_id = ? AND address = ? AND loaded = 1 AND timestamp >= ? AND timestamp < ?
+		GROUP BY bucket, token
+		ORDER BY bucket, token`, from, from, bucket, bucket, db.network, address, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			point := TransfersChartPoint{Address: address}
+			if err := rows.Scan(&point.Timestamp, &point.Token, &point.SumIn, &point.SumOut, &point.Count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			rst = append(rst, point)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rst, nil
+}
@@ -0,0 +1,53 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetTransfersChart(t *testing.T) {
+	db, stop := setupTestDB(t)
+	defer stop()
+
+	start := time.Unix(1588000000, 0)
+	address := common.Address{1}
+	other := common.Address{2}
+	token := common.Address{0xaa}
+	incoming := erc20TransferAt(5, token, other, address, 100, start.Add(90*time.Minute))
+	incoming.Address = address
+	saveTransfers(t, db,
+		ethTransferAt(1, address, other, 10, start, false),
+		ethTransferAt(2, address, other, 20, start.Add(30*time.Minute), false),
+		// failed transactions are counted without values
+		ethTransferAt(3, address, other, 40, start.Add(40*time.Minute), true),
+		ethTransferAt(4, address, address, 80, start.Add(70*time.Minute), false),
+		incoming,
+		erc20TransferAt(6, token, address, other, 7, start.Add(100*time.Minute)),
+		ethTransferAt(7, address, other, 1000, start.Add(3*time.Hour), false),
+		ethTransferAt(8, other, address, 1000, start.Add(30*time.Minute), false),
+	)
+	// transfers stored before chart values were added
+	_, err := db.db.Exec("UPDATE transfers SET token = NULL, amount_in = NULL, amount_out = NULL WHERE timestamp < ?", start.Add(time.Hour).Unix())
+	require.NoError(t, err)
+
+	from := uint64(start.Unix())
+	points, err := db.GetTransfersChart([]common.Address{address, other}, from, from+2*3600, 3600)
+	require.NoError(t, err)
+	require.Equal(t, []TransfersChartPoint{
+		{Address: address, Timestamp: from, SumOut: 30, Count: 3},
+		{Address: address, Timestamp: from + 3600, SumIn: 80, SumOut: 80, Count: 1},
+		{Address: address, Token: token, Timestamp: from + 3600, SumIn: 100, SumOut: 7, Count: 2},
+		{Address: other, Timestamp: from, SumOut: 1000, Count: 1},
+	}, points)
+
+	_, err = db.GetTransfersChart([]common.Address{address}, from, from, 3600)
+	require.Equal(t, ErrInvalidTransfersChartRange, err)
+	_, err = db.GetTransfersChart([]common.Address{address}, from, from+3600, 0)
+	require.Equal(t, ErrInvalidTransfersChartBucket, err)
+	_, err = db.GetTransfersChart([]common.Address{address}, from, from+1001, 1)
+	require.Equal(t, ErrInvalidTransfersChartBucket, err)
+}
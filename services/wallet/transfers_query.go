@@ -9,7 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-const baseTransfersQuery = "SELECT hash, type, blk_hash, blk_number, timestamp, address, tx, sender, receipt, log FROM transfers"
+const baseTransfersQuery = "SELECT hash, type, blk_hash, blk_number, timestamp, address, tx, sender, receipt, log, l2_fee, withdrawal FROM transfers"
 
 func newTransfersQuery() *transfersQuery {
 	buf := bytes.NewBuffer(nil)
@@ -84,6 +84,49 @@ func (q *transfersQuery) FilterBlockHash(blockHash common.Hash) *transfersQuery
 	return q
 }
 
+func (q *transfersQuery) FilterTransactionHash(txHash common.Hash) *transfersQuery {
+	q.andOrWhere()
+	q.added = true
+	q.buf.WriteString(" tx_hash = ?")
+	q.args = append(q.args, txHash)
+	return q
+}
+
+func (q *transfersQuery) FilterTimestampFrom(timestamp uint64) *transfersQuery {
+	q.andOrWhere()
+	q.added = true
+	q.buf.WriteString(" timestamp >= ?")
+	q.args = append(q.args, timestamp)
+	return q
+}
+
+// FilterTags selects transfers with at least one of the tags, it is a no-op for empty tags.
+func (q *transfersQuery) FilterTags(network uint64, tags []string) *transfersQuery {
+	if len(tags) == 0 {
+		return q
+	}
+	q.andOrWhere()
+	q.added = true
+	q.buf.WriteString(" hash IN (SELECT hash FROM transfer_tags WHERE network_id = ? AND tag IN (")
+	q.args = append(q.args, network)
+	for i, tag := range tags {
+		if i > 0 {
+			q.buf.WriteString(", ")
+		}
+		q.buf.WriteString("?")
+		q.args = append(q.args, tag)
+	}
+	q.buf.WriteString("))")
+	return q
+}
+
+func (q *transfersQuery) FilterChartValuesMissing() *transfersQuery {
+	q.andOrWhere()
+	q.added = true
+	q.buf.WriteString(" amount_in IS NULL")
+	return q
+}
+
 func (q *transfersQuery) Limit(pageSize int64) *transfersQuery {
 	q.buf.WriteString(" ORDER BY blk_number DESC, hash ASC ")
 	q.buf.WriteString(" LIMIT ?")
@@ -110,10 +153,15 @@ func (q *transfersQuery) Scan(rows *sql.Rows) (rst []Transfer, err error) {
 		err = rows.Scan(
 			&transfer.ID, &transfer.Type, &transfer.BlockHash,
 			(*SQLBigInt)(transfer.BlockNumber), &transfer.Timestamp, &transfer.Address,
-			&JSONBlob{transfer.Transaction}, &transfer.From, &JSONBlob{transfer.Receipt}, &JSONBlob{transfer.Log})
+			&JSONBlob{transfer.Transaction}, &transfer.From, &JSONBlob{transfer.Receipt}, &JSONBlob{transfer.Log},
+			&JSONBlob{&transfer.L2Fee}, &JSONBlob{&transfer.Withdrawal})
 		if err != nil {
 			return nil, err
 		}
+		if transfer.Type == withdrawalTransfer {
+			// withdrawals are credited without transactions
+			transfer.Transaction, transfer.Receipt = nil, nil
+		}
 		rst = append(rst, transfer)
 	}
 	return rst, nil
@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/status-im/status-go/services/typeddata"
+)
+
+// AccountKeys unlocks private keys of wallet accounts stored in the keystore.
+type AccountKeys interface {
+	// WalletAccountKey returns the key of a wallet account if the password is valid.
+	WalletAccountKey(address common.Address, password string) (*ecdsa.PrivateKey, error)
+}
+
+// TypedDataHash is a hash of EIP-712 typed data with a summary to be confirmed by the user.
+type TypedDataHash struct {
+	Hash    common.Hash       `json:"hash"`
+	Summary typeddata.Summary `json:"summary"`
+}
+
+// HashTypedData validates typed data for the chain and returns its hash and summary.
+func HashTypedData(typed typeddata.TypedData, chainID *big.Int) (*TypedDataHash, error) {
+	hash, err := typeddata.ValidateAndHash(typed, chainID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := typeddata.Summarize(typed)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDataHash{Hash: hash, Summary: summary}, nil
+}
+
+// SignTypedData validates typed data for the chain and signs it with the key of a wallet account.
+func SignTypedData(keys AccountKeys, typed typeddata.TypedData, chainID *big.Int, address common.Address, password string) ([]byte, error) {
+	key, err := keys.WalletAccountKey(address, password)
+	if err != nil {
+		return nil, err
+	}
+	return typeddata.Sign(typed, key, chainID)
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/status-im/status-go/services/typeddata"
+)
+
+type testAccountKeys struct {
+	key      *ecdsa.PrivateKey
+	password string
+}
+
+func (k testAccountKeys) WalletAccountKey(address common.Address, password string) (*ecdsa.PrivateKey, error) {
+	if address != crypto.PubkeyToAddress(k.key.PublicKey) || password != k.password {
+		return nil, errors.New("invalid account")
+	}
+	return k.key, nil
+}
+
+func testTypedData() typeddata.TypedData {
+	return typeddata.TypedData{
+		Types: typeddata.Types{
+			"EIP712Domain": []typeddata.Field{
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Mail": []typeddata.Field{
+				{Name: "from", Type: "address"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: map[string]json.RawMessage{
+			"name":    json.RawMessage(`"Ether Mail"`),
+			"chainId": json.RawMessage(`1`),
+		},
+		Message: map[string]json.RawMessage{
+			"from":     json.RawMessage(`"0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826"`),
+			"contents": json.RawMessage(`"Hello"`),
+		},
+	}
+}
+
+func TestHashTypedData(t *testing.T) {
+	typed := testTypedData()
+	rst, err := HashTypedData(typed, big.NewInt(1))
+	require.NoError(t, err)
+	expected, err := typeddata.ValidateAndHash(typed, big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, expected, rst.Hash)
+	require.Equal(t, "Mail", rst.Summary.PrimaryType)
+	require.Len(t, rst.Summary.Message, 2)
+
+	_, err = HashTypedData(typed, big.NewInt(3))
+	require.Error(t, err)
+}
+
+func TestSignTypedData(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keys := testAccountKeys{key: key, password: "password"}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	typed := testTypedData()
+
+	sig, err := SignTypedData(keys, typed, big.NewInt(1), address, "password")
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+	hash, err := typeddata.ValidateAndHash(typed, big.NewInt(1))
+	require.NoError(t, err)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash[:], sig)
+	require.NoError(t, err)
+	require.Equal(t, address, crypto.PubkeyToAddress(*pub))
+
+	_, err = SignTypedData(keys, typed, big.NewInt(1), address, "wrong")
+	require.Error(t, err)
+}
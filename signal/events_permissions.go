@@ -0,0 +1,19 @@
+package signal
+
+const (
+	// EventDappPermissionRequested is triggered when a dapp calls a method it was not granted a permission for
+	EventDappPermissionRequested = "dapp.permission.requested"
+)
+
+// DappPermissionRequestEvent is a signal sent when a permission of a dapp is missing,
+// so a client can prompt a user to grant it.
+type DappPermissionRequestEvent struct {
+	Dapp       string `json:"dapp"`
+	Permission string `json:"permission"`
+	Method     string `json:"method"`
+}
+
+// SendDappPermissionRequested sends a signal when a permission of a dapp is missing.
+func SendDappPermissionRequested(event DappPermissionRequestEvent) {
+	send(EventDappPermissionRequested, event)
+}
@@ -0,0 +1,11 @@
+package signal
+
+const (
+	// EventLocalSettingChanged is triggered when a local setting is set to a new value or deleted.
+	EventLocalSettingChanged = "localsettings.changed"
+)
+
+// SendLocalSettingChanged sends a signal when a local setting changes.
+func SendLocalSettingChanged(event interface{}) {
+	send(EventLocalSettingChanged, event)
+}
@@ -18,12 +18,22 @@ const (
 	// to any peer
 	EventEnvelopeExpired = "envelope.expired"
 
+	// EventOutboxMessageSent is triggered when a postponed message was sent from the outbox.
+	EventOutboxMessageSent = "outbox.message.sent"
+
+	// EventOutboxMessageFailed is triggered when a postponed message was dropped from the outbox
+	// without being sent.
+	EventOutboxMessageFailed = "outbox.message.failed"
+
 	// EventMailServerRequestCompleted is triggered when whisper receives a message ack from the mailserver
 	EventMailServerRequestCompleted = "mailserver.request.completed"
 
 	// EventMailServerRequestExpired is triggered when request TTL ends
 	EventMailServerRequestExpired = "mailserver.request.expired"
 
+	// EventMailServerChanged is triggered when the active mail server is selected or replaced
+	EventMailServerChanged = "mailserver.changed"
+
 	// EventEnodeDiscovered is tiggered when enode has been discovered.
 	EventEnodeDiscovered = "enode.discovered"
 
@@ -38,8 +48,37 @@ const (
 
 	// EventNewMessages is triggered when we receive new messages
 	EventNewMessages = "messages.new"
+
+	// EventInboxSummary is triggered when history requests to mail servers are completed
+	EventInboxSummary = "messages.inbox.summary"
+
+	// EventMessagesSubscription is triggered when received messages match a messages subscription
+	EventMessagesSubscription = "messages.subscription"
+
+	// EventMentions is triggered when received messages of public chats mention the user
+	EventMentions = "messages.mentions"
+
+	// EventChatIndicator is triggered when we receive a typing or presence indicator
+	EventChatIndicator = "messages.indicator"
+
+	// EventPushNotificationRegistration is triggered when the push notification registration changes
+	EventPushNotificationRegistration = "messages.pushNotificationRegistration"
+
+	// EventFilterSilent is triggered when a filter received nothing for a configured period
+	EventFilterSilent = "filter.silent"
 )
 
+// MessagesSubscriptionSignal includes messages that match the filter of a subscription.
+type MessagesSubscriptionSignal struct {
+	SubscriptionID string                 `json:"subscriptionId"`
+	Messages       []*statusproto.Message `json:"messages"`
+}
+
+// MentionsSignal includes received messages mentioning the user.
+type MentionsSignal struct {
+	Mentions []*statusproto.Mention `json:"mentions"`
+}
+
 // EnvelopeSignal includes hash of the envelope.
 type EnvelopeSignal struct {
 	IDs     []hexutil.Bytes `json:"ids"`
@@ -55,6 +94,21 @@ type MailServerResponseSignal struct {
 	ErrorMsg         string     `json:"errorMessage"`
 }
 
+// ChatInboxSummary counts messages of a chat received during a history sync.
+type ChatInboxSummary struct {
+	ChatID      string `json:"chatId"`
+	NewMessages int    `json:"newMessages"`
+	Mentions    int    `json:"mentions"`
+	// Earliest and Latest are timestamps of the received messages in milliseconds.
+	Earliest uint64 `json:"earliest"`
+	Latest   uint64 `json:"latest"`
+}
+
+// InboxSummarySignal summarizes messages received during a history sync, chats are sorted by ID.
+type InboxSummarySignal struct {
+	Chats []ChatInboxSummary `json:"chats"`
+}
+
 // DecryptMessageFailedSignal holds the sender of the message that could not be decrypted
 type DecryptMessageFailedSignal struct {
 	Sender string `json:"sender"`
@@ -111,6 +165,30 @@ func SendEnvelopeExpired(identifiers [][]byte, err error) {
 	send(EventEnvelopeExpired, EnvelopeSignal{IDs: hexIdentifiers, Message: message})
 }
 
+// SendOutboxMessageSent triggered when a postponed message was sent.
+func SendOutboxMessageSent(identifiers [][]byte) {
+	var hexIdentifiers []hexutil.Bytes
+	for _, i := range identifiers {
+		hexIdentifiers = append(hexIdentifiers, i)
+	}
+
+	send(EventOutboxMessageSent, EnvelopeSignal{IDs: hexIdentifiers})
+}
+
+// SendOutboxMessageFailed triggered when a postponed message was dropped.
+func SendOutboxMessageFailed(identifiers [][]byte, err error) {
+	var message string
+	if err != nil {
+		message = err.Error()
+	}
+	var hexIdentifiers []hexutil.Bytes
+	for _, i := range identifiers {
+		hexIdentifiers = append(hexIdentifiers, i)
+	}
+
+	send(EventOutboxMessageFailed, EnvelopeSignal{IDs: hexIdentifiers, Message: message})
+}
+
 // SendMailServerRequestCompleted triggered when mail server response has been received
 func SendMailServerRequestCompleted(requestID types.Hash, lastEnvelopeHash types.Hash, cursor []byte, err error) {
 	errorMsg := ""
@@ -131,6 +209,18 @@ func SendMailServerRequestExpired(hash types.Hash) {
 	send(EventMailServerRequestExpired, EnvelopeSignal{Hash: hash})
 }
 
+// MailServerChangedSignal includes enode address of the active mail server.
+// The address is empty if there are no mail servers.
+type MailServerChangedSignal struct {
+	Enode  string `json:"enode"`
+	Pinned bool   `json:"pinned"`
+}
+
+// SendMailServerChanged triggered when the active mail server changes.
+func SendMailServerChanged(enode string, pinned bool) {
+	send(EventMailServerChanged, MailServerChangedSignal{Enode: enode, Pinned: pinned})
+}
+
 // EnodeDiscoveredSignal includes enode address and topic
 type EnodeDiscoveredSignal struct {
 	Enode string `json:"enode"`
@@ -161,3 +251,28 @@ func SendWhisperFilterAdded(filters []*Filter) {
 func SendNewMessages(response *statusproto.MessengerResponse) {
 	send(EventNewMessages, response)
 }
+
+func SendMessagesSubscription(subscriptionID string, messages []*statusproto.Message) {
+	send(EventMessagesSubscription, MessagesSubscriptionSignal{SubscriptionID: subscriptionID, Messages: messages})
+}
+
+func SendMentions(mentions []*statusproto.Mention) {
+	send(EventMentions, MentionsSignal{Mentions: mentions})
+}
+
+func SendChatIndicator(indicator statusproto.ChatIndicator) {
+	send(EventChatIndicator, indicator)
+}
+
+func SendPushNotificationRegistration(registration statusproto.PushNotificationRegistration) {
+	send(EventPushNotificationRegistration, registration)
+}
+
+func SendFilterSilent(stats statusproto.FilterStats) {
+	send(EventFilterSilent, stats)
+}
+
+// SendInboxSummary triggered when a history sync is completed.
+func SendInboxSummary(chats []ChatInboxSummary) {
+	send(EventInboxSummary, InboxSummarySignal{Chats: chats})
+}
@@ -1,10 +1,29 @@
 package signal
 
 const (
-	walletEvent = "wallet"
+	walletEvent             = "wallet"
+	walletLimitReachedEvent = "wallet.limitReached"
+	walletENSChangedEvent   = "wallet.ensAddressChanged"
+	walletKeycardEvent      = "wallet.keycard"
 )
 
 // SendWalletEvent sends event from services/wallet/events.
 func SendWalletEvent(event interface{}) {
 	send(walletEvent, event)
 }
+
+// SendWalletLimitReached sends an event when a spending limit of an account is reached.
+func SendWalletLimitReached(event interface{}) {
+	send(walletLimitReachedEvent, event)
+}
+
+// SendWalletENSAddressChanged sends an event when an ENS name of a contact in the address book
+// starts pointing to a different address.
+func SendWalletENSAddressChanged(event interface{}) {
+	send(walletENSChangedEvent, event)
+}
+
+// SendWalletKeycardEvent sends an event about a step of an interaction with a keycard.
+func SendWalletKeycardEvent(event interface{}) {
+	send(walletKeycardEvent, event)
+}
@@ -0,0 +1 @@
+CREATE TABLE topic_stats (bucket BIGINT NOT NULL, topic BYTEA NOT NULL, envelopes BIGINT NOT NULL DEFAULT 0, size BIGINT NOT NULL DEFAULT 0, PRIMARY KEY (bucket, topic));
@@ -0,0 +1 @@
+CREATE TABLE envelope_sources (id BYTEA NOT NULL PRIMARY KEY, peer BYTEA NOT NULL, size BIGINT NOT NULL);
@@ -0,0 +1,2 @@
+DROP INDEX envelopes_deleted_at;
+ALTER TABLE envelopes DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+ALTER TABLE envelopes ADD COLUMN deleted_at BIGINT;
+CREATE INDEX envelopes_deleted_at ON envelopes (deleted_at) WHERE deleted_at IS NOT NULL;
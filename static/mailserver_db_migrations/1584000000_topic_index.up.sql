@@ -0,0 +1 @@
+CREATE TABLE topic_index (bucket BIGINT NOT NULL, topic BYTEA NOT NULL, PRIMARY KEY (bucket, topic));
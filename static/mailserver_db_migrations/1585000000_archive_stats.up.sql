@@ -0,0 +1,10 @@
+CREATE TABLE archive_stats (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  envelopes BIGINT NOT NULL,
+  size BIGINT NOT NULL,
+  oldest BYTEA,
+  newest BYTEA
+);
+
+INSERT INTO archive_stats (envelopes, size, oldest, newest)
+SELECT COUNT(*), COALESCE(SUM(OCTET_LENGTH(data)), 0), MIN(id), MAX(id) FROM envelopes WHERE deleted_at IS NULL;
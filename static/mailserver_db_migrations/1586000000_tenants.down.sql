@@ -0,0 +1,12 @@
+DELETE FROM envelopes WHERE tenant <> '';
+DROP INDEX id_bloom_idx;
+DROP INDEX id_topic_idx;
+ALTER TABLE envelopes DROP CONSTRAINT envelopes_tenant_id_key;
+ALTER TABLE envelopes DROP COLUMN tenant;
+ALTER TABLE envelopes ADD CONSTRAINT envelopes_id_key UNIQUE (id);
+CREATE INDEX id_bloom_idx ON envelopes (id DESC, bloom);
+CREATE INDEX id_topic_idx ON envelopes (id DESC, topic);
+
+DELETE FROM archive_stats WHERE tenant <> '';
+ALTER TABLE archive_stats DROP COLUMN tenant;
+ALTER TABLE archive_stats ADD COLUMN id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id);
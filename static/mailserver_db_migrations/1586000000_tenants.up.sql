@@ -0,0 +1,11 @@
+ALTER TABLE envelopes ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE envelopes DROP CONSTRAINT envelopes_id_key;
+ALTER TABLE envelopes ADD CONSTRAINT envelopes_tenant_id_key UNIQUE (tenant, id);
+
+DROP INDEX id_bloom_idx;
+DROP INDEX id_topic_idx;
+CREATE INDEX id_bloom_idx ON envelopes (tenant, id DESC, bloom);
+CREATE INDEX id_topic_idx ON envelopes (tenant, id DESC, topic);
+
+ALTER TABLE archive_stats DROP COLUMN id;
+ALTER TABLE archive_stats ADD COLUMN tenant TEXT NOT NULL DEFAULT '' PRIMARY KEY;
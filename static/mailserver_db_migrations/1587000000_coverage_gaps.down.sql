@@ -0,0 +1,2 @@
+DROP TABLE coverage_heartbeats;
+DROP TABLE coverage_gaps;
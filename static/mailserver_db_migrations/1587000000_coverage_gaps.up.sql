@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS coverage_gaps (
+  tenant TEXT NOT NULL DEFAULT '',
+  gap_from BIGINT NOT NULL,
+  gap_to BIGINT NOT NULL,
+  reason TEXT NOT NULL,
+  PRIMARY KEY (tenant, gap_from)
+);
+
+CREATE TABLE IF NOT EXISTS coverage_heartbeats (
+  tenant TEXT NOT NULL DEFAULT '' PRIMARY KEY,
+  at BIGINT NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE coverage_gaps DROP COLUMN healed_at;
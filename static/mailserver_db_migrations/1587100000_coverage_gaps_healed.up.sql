@@ -0,0 +1 @@
+ALTER TABLE coverage_gaps ADD COLUMN healed_at BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE bloom_index;
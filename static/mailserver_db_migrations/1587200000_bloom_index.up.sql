@@ -0,0 +1 @@
+CREATE TABLE bloom_index (bucket BIGINT PRIMARY KEY, bloom BIT(512) NOT NULL);
\ No newline at end of file
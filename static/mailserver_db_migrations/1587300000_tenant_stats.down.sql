@@ -0,0 +1,14 @@
+DELETE FROM topic_stats WHERE tenant <> '';
+ALTER TABLE topic_stats DROP CONSTRAINT topic_stats_pkey;
+ALTER TABLE topic_stats DROP COLUMN tenant;
+ALTER TABLE topic_stats ADD PRIMARY KEY (bucket, topic);
+
+DELETE FROM topic_index WHERE tenant <> '';
+ALTER TABLE topic_index DROP CONSTRAINT topic_index_pkey;
+ALTER TABLE topic_index DROP COLUMN tenant;
+ALTER TABLE topic_index ADD PRIMARY KEY (bucket, topic);
+
+DELETE FROM envelope_sources WHERE tenant <> '';
+ALTER TABLE envelope_sources DROP CONSTRAINT envelope_sources_pkey;
+ALTER TABLE envelope_sources DROP COLUMN tenant;
+ALTER TABLE envelope_sources ADD PRIMARY KEY (id);
@@ -0,0 +1,11 @@
+ALTER TABLE topic_stats ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE topic_stats DROP CONSTRAINT topic_stats_pkey;
+ALTER TABLE topic_stats ADD PRIMARY KEY (tenant, bucket, topic);
+
+ALTER TABLE topic_index ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE topic_index DROP CONSTRAINT topic_index_pkey;
+ALTER TABLE topic_index ADD PRIMARY KEY (tenant, bucket, topic);
+
+ALTER TABLE envelope_sources ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE envelope_sources DROP CONSTRAINT envelope_sources_pkey;
+ALTER TABLE envelope_sources ADD PRIMARY KEY (tenant, id);
@@ -0,0 +1,4 @@
+DELETE FROM bloom_index WHERE tenant <> '';
+ALTER TABLE bloom_index DROP CONSTRAINT bloom_index_pkey;
+ALTER TABLE bloom_index DROP COLUMN tenant;
+ALTER TABLE bloom_index ADD PRIMARY KEY (bucket);
@@ -0,0 +1,3 @@
+ALTER TABLE bloom_index ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+ALTER TABLE bloom_index DROP CONSTRAINT bloom_index_pkey;
+ALTER TABLE bloom_index ADD PRIMARY KEY (tenant, bucket);
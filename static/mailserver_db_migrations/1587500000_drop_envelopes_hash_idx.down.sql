@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS envelopes_hash_idx ON envelopes (tenant, substring(id from 5 for 32));
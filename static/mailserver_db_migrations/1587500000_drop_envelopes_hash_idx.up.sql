@@ -0,0 +1 @@
+DROP INDEX IF EXISTS envelopes_hash_idx;
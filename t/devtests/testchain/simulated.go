@@ -0,0 +1,135 @@
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/status-im/status-go/services/wallet/erc20"
+)
+
+const simulatedGasLimit = 10000000
+
+// SimulatedBackend is a chain of backends.SimulatedBackend served to an ethclient over an
+// in-process RPC server. Unlike Backend it doesn't run a node, blocks are mined only when
+// Commit is called, so tests using it are fast and deterministic.
+type SimulatedBackend struct {
+	*backends.SimulatedBackend
+
+	// RPC is a client for components that make raw calls, Client wraps it.
+	RPC    *rpc.Client
+	Client *ethclient.Client
+	Faucet *ecdsa.PrivateKey
+	Signer types.Signer
+
+	db     ethdb.Database
+	config *params.ChainConfig
+	server *rpc.Server
+}
+
+// NewSimulatedBackend creates a chain with a funded faucet account in the genesis block.
+func NewSimulatedBackend() (*SimulatedBackend, error) {
+	faucet, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	db := rawdb.NewMemoryDatabase()
+	alloc := core.GenesisAlloc{crypto.PubkeyToAddress(faucet.PublicKey): {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))}}
+	b := &SimulatedBackend{
+		SimulatedBackend: backends.NewSimulatedBackendWithDatabase(db, alloc, simulatedGasLimit),
+		Faucet:           faucet,
+		Signer:           types.NewEIP155Signer(params.AllEthashProtocolChanges.ChainID),
+		db:               db,
+		config:           params.AllEthashProtocolChanges,
+		server:           rpc.NewServer(),
+	}
+	if err := b.server.RegisterName("eth", &simulatedAPI{backend: b}); err != nil {
+		return nil, err
+	}
+	b.RPC = rpc.DialInProc(b.server)
+	b.Client = ethclient.NewClient(b.RPC)
+	return b, nil
+}
+
+// GenerateBlocks generates n blocks on top of the block with the given number.
+// Blocks are not inserted, use InsertBlocks.
+func (b *SimulatedBackend) GenerateBlocks(n int, start uint64, gen func(int, *core.BlockGen)) []*types.Block {
+	block := b.Blockchain().GetBlockByNumber(start)
+	blocks, _ := core.GenerateChain(b.config, block, ethash.NewFaker(), b.db, n, gen)
+	return blocks
+}
+
+// InsertBlocks inserts generated blocks and discards pending transactions.
+// Blocks on a fork become canonical if the fork is longer than the current chain.
+func (b *SimulatedBackend) InsertBlocks(blocks []*types.Block) error {
+	if _, err := b.Blockchain().InsertChain(blocks); err != nil {
+		return err
+	}
+	// pending block must be built on top of the new head
+	b.Rollback()
+	return nil
+}
+
+// Reorg replaces the last depth blocks with depth+1 blocks created by gen.
+func (b *SimulatedBackend) Reorg(depth int, gen func(int, *core.BlockGen)) error {
+	head := b.Blockchain().CurrentBlock().NumberU64()
+	if uint64(depth) > head {
+		return errors.New("reorg is deeper than the chain")
+	}
+	return b.InsertBlocks(b.GenerateBlocks(depth+1, head-uint64(depth), func(i int, block *core.BlockGen) {
+		// blocks must differ from the replaced ones even without transactions
+		block.SetExtra([]byte("reorg"))
+		if gen != nil {
+			gen(i, block)
+		}
+	}))
+}
+
+// SendETH sends a value from the key owner to an address and mines a block with the transaction.
+func (b *SimulatedBackend) SendETH(from *ecdsa.PrivateKey, to common.Address, value *big.Int) (*types.Transaction, error) {
+	nonce, err := b.PendingNonceAt(context.Background(), crypto.PubkeyToAddress(from.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	tx, err := types.SignTx(types.NewTransaction(nonce, to, value, 21000, big.NewInt(1), nil), b.Signer, from)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.SendTransaction(context.Background(), tx); err != nil {
+		return nil, err
+	}
+	b.Commit()
+	return tx, nil
+}
+
+// DeployERC20 deploys a token contract that allows anyone to transfer any amount of tokens
+// and mines a block with the deployment.
+func (b *SimulatedBackend) DeployERC20(owner *ecdsa.PrivateKey) (common.Address, *erc20.ERC20Transfer, error) {
+	address, _, contract, err := erc20.DeployERC20Transfer(bind.NewKeyedTransactor(owner), b)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	b.Commit()
+	return address, contract, nil
+}
+
+// Stop closes the RPC client and the chain.
+func (b *SimulatedBackend) Stop() error {
+	b.RPC.Close()
+	b.server.Stop()
+	return b.Close()
+}
@@ -0,0 +1,259 @@
+package testchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errUnknownBlock         = errors.New("unknown block")
+	errOverridesUnsupported = errors.New("state overrides are not supported")
+)
+
+// simulatedAPI implements a subset of the eth namespace used by ethclient.
+type simulatedAPI struct {
+	backend *SimulatedBackend
+}
+
+// CallArgs are arguments of eth_call and eth_estimateGas. Types of arguments
+// must be exported to be accepted by the RPC server.
+type CallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+}
+
+func (args CallArgs) toCallMsg() ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		From: args.From,
+		To:   args.To,
+		Data: args.Data,
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.GasPrice != nil {
+		msg.GasPrice = args.GasPrice.ToInt()
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	return msg
+}
+
+// FilterArgs are arguments of eth_getLogs.
+type FilterArgs struct {
+	BlockHash *common.Hash     `json:"blockHash"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+func (api *simulatedAPI) ChainId() *hexutil.Big { // nolint: golint
+	return (*hexutil.Big)(api.backend.config.ChainID)
+}
+
+func (api *simulatedAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.backend.Blockchain().CurrentBlock().NumberU64())
+}
+
+func (api *simulatedAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.backend.SuggestGasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+func (api *simulatedAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	block := api.blockByNumber(number)
+	if block == nil {
+		return nil, nil
+	}
+	return api.marshalBlock(block, fullTx)
+}
+
+func (api *simulatedAPI) GetBlockByHash(hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block := api.backend.Blockchain().GetBlockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+	return api.marshalBlock(block, fullTx)
+}
+
+func (api *simulatedAPI) GetBalance(address common.Address, number rpc.BlockNumber) (*hexutil.Big, error) {
+	statedb, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(statedb.GetBalance(address)), nil
+}
+
+func (api *simulatedAPI) GetTransactionCount(ctx context.Context, address common.Address, number rpc.BlockNumber) (hexutil.Uint64, error) {
+	if number == rpc.PendingBlockNumber {
+		nonce, err := api.backend.PendingNonceAt(ctx, address)
+		return hexutil.Uint64(nonce), err
+	}
+	statedb, err := api.stateAt(number)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(statedb.GetNonce(address)), nil
+}
+
+func (api *simulatedAPI) GetCode(address common.Address, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	statedb, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(address), nil
+}
+
+// Call executes a call on top of the latest block, historical state is not supported by the simulated backend.
+func (api *simulatedAPI) Call(ctx context.Context, args CallArgs, number rpc.BlockNumber, overrides *map[common.Address]json.RawMessage) (hexutil.Bytes, error) {
+	if overrides != nil && len(*overrides) > 0 {
+		return nil, errOverridesUnsupported
+	}
+	if number == rpc.PendingBlockNumber {
+		return api.backend.PendingCallContract(ctx, args.toCallMsg())
+	}
+	return api.backend.CallContract(ctx, args.toCallMsg(), api.blockNumber(number))
+}
+
+func (api *simulatedAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+	gas, err := api.backend.SimulatedBackend.EstimateGas(ctx, args.toCallMsg())
+	return hexutil.Uint64(gas), err
+}
+
+// SendRawTransaction adds a transaction to the pending block, it is mined with Commit.
+func (api *simulatedAPI) SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encoded, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), api.backend.SendTransaction(ctx, tx)
+}
+
+func (api *simulatedAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, blockHash, number, index := rawdb.ReadTransaction(api.backend.db, hash)
+	if tx != nil {
+		return api.marshalTransaction(tx, blockHash, number, index)
+	}
+	tx, pending, err := api.backend.TransactionByHash(ctx, hash)
+	if err == ethereum.NotFound {
+		return nil, nil
+	} else if err != nil || !pending {
+		return nil, err
+	}
+	return marshalJSON(tx)
+}
+
+func (api *simulatedAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return api.backend.TransactionReceipt(ctx, hash)
+}
+
+func (api *simulatedAPI) GetLogs(ctx context.Context, args FilterArgs) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		BlockHash: args.BlockHash,
+		Addresses: args.Addresses,
+		Topics:    args.Topics,
+	}
+	if args.FromBlock != nil {
+		query.FromBlock = api.blockNumber(*args.FromBlock)
+	}
+	if args.ToBlock != nil {
+		query.ToBlock = api.blockNumber(*args.ToBlock)
+	}
+	logs, err := api.backend.FilterLogs(ctx, query)
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	return logs, err
+}
+
+// blockNumber returns nil for the latest and the pending block.
+func (api *simulatedAPI) blockNumber(number rpc.BlockNumber) *big.Int {
+	if number < 0 {
+		return nil
+	}
+	return big.NewInt(number.Int64())
+}
+
+func (api *simulatedAPI) blockByNumber(number rpc.BlockNumber) *types.Block {
+	if number < 0 {
+		return api.backend.Blockchain().CurrentBlock()
+	}
+	return api.backend.Blockchain().GetBlockByNumber(uint64(number))
+}
+
+func (api *simulatedAPI) stateAt(number rpc.BlockNumber) (*state.StateDB, error) {
+	block := api.blockByNumber(number)
+	if block == nil {
+		return nil, errUnknownBlock
+	}
+	return api.backend.Blockchain().StateAt(block.Root())
+}
+
+func (api *simulatedAPI) marshalBlock(block *types.Block, fullTx bool) (map[string]interface{}, error) {
+	fields, err := marshalJSON(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !fullTx {
+			txs[i] = tx.Hash()
+			continue
+		}
+		txs[i], err = api.marshalTransaction(tx, block.Hash(), block.NumberU64(), uint64(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	uncles := make([]common.Hash, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+	fields["size"] = hexutil.Uint64(block.Size())
+	fields["transactions"] = txs
+	fields["uncles"] = uncles
+	return fields, nil
+}
+
+func (api *simulatedAPI) marshalTransaction(tx *types.Transaction, blockHash common.Hash, number, index uint64) (map[string]interface{}, error) {
+	fields, err := marshalJSON(tx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(types.MakeSigner(api.backend.config, new(big.Int).SetUint64(number)), tx)
+	if err != nil {
+		return nil, err
+	}
+	fields["blockHash"] = blockHash
+	fields["blockNumber"] = (*hexutil.Big)(new(big.Int).SetUint64(number))
+	fields["transactionIndex"] = hexutil.Uint64(index)
+	fields["from"] = from
+	return fields, nil
+}
+
+// marshalJSON converts a value with a custom JSON encoding into fields that can be extended.
+func marshalJSON(v interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	return fields, json.Unmarshal(encoded, &fields)
+}
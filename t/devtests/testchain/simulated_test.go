@@ -0,0 +1,69 @@
+package testchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSimulatedBackendClient(t *testing.T) {
+	backend, err := NewSimulatedBackend()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, backend.Stop()) }()
+	ctx := context.Background()
+	faucet := crypto.PubkeyToAddress(backend.Faucet.PublicKey)
+
+	tx, err := backend.SendETH(backend.Faucet, common.Address{1}, big.NewInt(100))
+	require.NoError(t, err)
+	block, err := backend.Client.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), block.NumberU64())
+	require.Len(t, block.Transactions(), 1)
+	sender, err := backend.Client.TransactionSender(ctx, block.Transactions()[0], block.Hash(), 0)
+	require.NoError(t, err)
+	require.Equal(t, faucet, sender)
+
+	received, pending, err := backend.Client.TransactionByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, tx.Hash(), received.Hash())
+	receipt, err := backend.Client.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), receipt.BlockHash)
+
+	// balances are available at past blocks
+	balance, err := backend.Client.BalanceAt(ctx, common.Address{1}, big.NewInt(0))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), balance.Int64())
+	balance, err = backend.Client.BalanceAt(ctx, common.Address{1}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), balance.Int64())
+
+	token, contract, err := backend.DeployERC20(backend.Faucet)
+	require.NoError(t, err)
+	_, err = contract.Transfer(bind.NewKeyedTransactor(backend.Faucet), common.Address{2}, big.NewInt(10))
+	require.NoError(t, err)
+	backend.Commit()
+	logs, err := backend.Client.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{token}})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, uint64(3), logs[0].BlockNumber)
+
+	// the transfer is removed from the canonical chain
+	require.NoError(t, backend.Reorg(1, nil))
+	logs, err = backend.Client.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{token}})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+	head, err := backend.Client.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(4), head.Number)
+	_, err = backend.Client.TransactionReceipt(ctx, tx.Hash())
+	require.NoError(t, err)
+}
@@ -0,0 +1,121 @@
+package transactions
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// replacementGasPriceBump is the minimal increase of the gas price in percents
+// required by nodes to replace a pending transaction.
+const replacementGasPriceBump = 10
+
+// MinReplacementGasPrice returns the lowest gas price of a transaction that replaces
+// a pending transaction with the given gas price.
+func MinReplacementGasPrice(gasPrice *big.Int) *big.Int {
+	// rounded up, nodes reject replacements below the bump
+	bumped := new(big.Int).Mul(gasPrice, big.NewInt(100+replacementGasPriceBump))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// PendingTransaction is a transaction sent by this node that wasn't confirmed yet.
+type PendingTransaction struct {
+	Hash     types.Hash     `json:"hash"`
+	From     types.Address  `json:"from"`
+	To       *types.Address `json:"to"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Value    *hexutil.Big   `json:"value"`
+	Gas      hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	Data     hexutil.Bytes  `json:"data"`
+	// SentAt is a unix timestamp of the time the transaction was sent.
+	SentAt int64 `json:"sentAt"`
+}
+
+// pendingTransactions keeps transactions sent by the transactor by sender and nonce,
+// a transaction sent with the nonce of a pending one replaces it.
+type pendingTransactions struct {
+	mu  sync.Mutex
+	txs map[types.Address]map[uint64]*PendingTransaction
+}
+
+func newPendingTransactions() *pendingTransactions {
+	return &pendingTransactions{txs: make(map[types.Address]map[uint64]*PendingTransaction)}
+}
+
+func (p *pendingTransactions) add(from types.Address, tx *gethtypes.Transaction, sentAt time.Time) {
+	pending := &PendingTransaction{
+		Hash:     types.Hash(tx.Hash()),
+		From:     from,
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Value:    (*hexutil.Big)(tx.Value()),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Data:     tx.Data(),
+		SentAt:   sentAt.Unix(),
+	}
+	if tx.To() != nil {
+		to := types.Address(*tx.To())
+		pending.To = &to
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.txs[from] == nil {
+		p.txs[from] = make(map[uint64]*PendingTransaction)
+	}
+	p.txs[from][tx.Nonce()] = pending
+}
+
+// isReplacement returns true if the arguments set the nonce of a pending transaction
+// and a gas price that is high enough to replace it.
+func (p *pendingTransactions) isReplacement(args SendTxArgs) bool {
+	if args.Nonce == nil || args.GasPrice == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending, exist := p.txs[args.From][uint64(*args.Nonce)]
+	return exist && args.GasPrice.ToInt().Cmp(MinReplacementGasPrice(pending.GasPrice.ToInt())) >= 0
+}
+
+// confirm removes transactions with nonces lower than the nonce of the sender's next confirmed
+// transaction and returns the rest ordered by nonce.
+func (p *pendingTransactions) confirm(from types.Address, confirmedNonce uint64) []PendingTransaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rst := []PendingTransaction{}
+	for nonce, tx := range p.txs[from] {
+		if nonce < confirmedNonce {
+			delete(p.txs[from], nonce)
+			continue
+		}
+		rst = append(rst, *tx)
+	}
+	if len(p.txs[from]) == 0 {
+		delete(p.txs, from)
+	}
+	sort.Slice(rst, func(i, j int) bool { return rst[i].Nonce < rst[j].Nonce })
+	return rst
+}
+
+// NextNonce returns the nonce of the next transaction of the sender, accounting for
+// transactions sent by this node that are not known to the upstream node yet.
+func (t *Transactor) NextNonce(from types.Address) (uint64, error) {
+	t.addrLock.LockAddr(from)
+	defer t.addrLock.UnlockAddr(from)
+	return t.getTransactionNonce(SendTxArgs{From: from})
+}
+
+// PendingTransactions returns transactions of the sender sent by this node since it was started,
+// whose nonces are not lower than confirmedNonce. Transactions with lower nonces were mined
+// or replaced and are forgotten.
+func (t *Transactor) PendingTransactions(from types.Address, confirmedNonce uint64) []PendingTransaction {
+	return t.pending.confirm(from, confirmedNonce)
+}
@@ -54,6 +54,7 @@ type Transactor struct {
 
 	addrLock   *AddrLocker
 	localNonce sync.Map
+	pending    *pendingTransactions
 	log        log.Logger
 }
 
@@ -63,6 +64,7 @@ func NewTransactor() *Transactor {
 		addrLock:      &AddrLocker{},
 		sendTxTimeout: sendTxTimeout,
 		localNonce:    sync.Map{},
+		pending:       newPendingTransactions(),
 		log:           log.New("package", "status-go/transactions.Manager"),
 	}
 }
@@ -104,10 +106,12 @@ func (t *Transactor) SendTransactionWithSignature(args SendTxArgs, sig []byte) (
 
 	tx := t.buildTransaction(args)
 	t.addrLock.LockAddr(args.From)
+	// a transaction with the nonce of a pending one replaces it
+	replacement := t.pending.isReplacement(args)
 	defer func() {
 		// nonce should be incremented only if tx completed without error
 		// and if no other transactions have been sent while signing the current one.
-		if err == nil {
+		if err == nil && !replacement {
 			t.localNonce.Store(args.From, uint64(*args.Nonce)+1)
 		}
 		t.addrLock.UnlockAddr(args.From)
@@ -118,7 +122,7 @@ func (t *Transactor) SendTransactionWithSignature(args SendTxArgs, sig []byte) (
 		return hash, err
 	}
 
-	if tx.Nonce() != expectedNonce {
+	if tx.Nonce() != expectedNonce && !replacement {
 		return hash, &ErrBadNonce{tx.Nonce(), expectedNonce}
 	}
 
@@ -133,6 +137,7 @@ func (t *Transactor) SendTransactionWithSignature(args SendTxArgs, sig []byte) (
 	if err := t.sender.SendTransaction(ctx, signedTx); err != nil {
 		return hash, err
 	}
+	t.pending.add(args.From, signedTx, time.Now())
 
 	return types.Hash(signedTx.Hash()), nil
 }
@@ -153,6 +158,9 @@ func (t *Transactor) HashTransaction(args SendTxArgs) (validatedArgs SendTxArgs,
 	if err != nil {
 		return validatedArgs, hash, err
 	}
+	if t.pending.isReplacement(args) {
+		nonce = uint64(*args.Nonce)
+	}
 
 	gasPrice := (*big.Int)(args.GasPrice)
 	if args.GasPrice == nil {
@@ -237,10 +245,11 @@ func (t *Transactor) validateAndPropagate(selectedAccount *account.SelectedExtKe
 		localNonce = val.(uint64)
 	}
 	var nonce uint64
+	replacement := t.pending.isReplacement(args)
 	defer func() {
 		// nonce should be incremented only if tx completed without error
 		// if upstream node returned nonce higher than ours we will stick to it
-		if err == nil {
+		if err == nil && !replacement {
 			t.localNonce.Store(args.From, nonce+1)
 		}
 		t.addrLock.UnlockAddr(args.From)
@@ -257,6 +266,10 @@ func (t *Transactor) validateAndPropagate(selectedAccount *account.SelectedExtKe
 	if localNonce > nonce {
 		nonce = localNonce
 	}
+	// a transaction with the nonce of a pending one replaces it
+	if replacement {
+		nonce = uint64(*args.Nonce)
+	}
 	gasPrice := (*big.Int)(args.GasPrice)
 	if args.GasPrice == nil {
 		ctx, cancel = context.WithTimeout(context.Background(), t.rpcCallTimeout)
@@ -313,6 +326,7 @@ func (t *Transactor) validateAndPropagate(selectedAccount *account.SelectedExtKe
 	if err := t.sender.SendTransaction(ctx, signedTx); err != nil {
 		return hash, err
 	}
+	t.pending.add(args.From, signedTx, time.Now())
 	return types.Hash(signedTx.Hash()), nil
 }
 
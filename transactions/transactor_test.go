@@ -377,6 +377,63 @@ func (s *TransactorSuite) TestSendTransactionWithSignature() {
 	}
 }
 
+func (s *TransactorSuite) TestSendTransactionWithSignature_Replacement() {
+	privKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	address := crypto.PubkeyToAddress(privKey.PublicKey)
+	chainID := big.NewInt(int64(s.nodeConfig.NetworkID))
+	signer := gethtypes.NewEIP155Signer(chainID)
+
+	send := func(gasPrice int64, remoteNonce hexutil.Uint64) (types.Hash, error) {
+		nonce := hexutil.Uint64(0)
+		to := address
+		gas := hexutil.Uint64(21000)
+		args := SendTxArgs{
+			From:     address,
+			To:       &to,
+			Gas:      &gas,
+			GasPrice: (*hexutil.Big)(big.NewInt(gasPrice)),
+			Value:    (*hexutil.Big)(big.NewInt(10)),
+			Nonce:    &nonce,
+		}
+		tx := gethtypes.NewTransaction(0, common.Address(to), big.NewInt(10), uint64(gas), big.NewInt(gasPrice), []byte{})
+		hash := signer.Hash(tx)
+		sig, err := gethcrypto.Sign(hash[:], privKey)
+		s.Require().NoError(err)
+
+		s.txServiceMock.EXPECT().
+			GetTransactionCount(gomock.Any(), common.Address(address), gethrpc.PendingBlockNumber).
+			Return(&remoteNonce, nil)
+		s.txServiceMock.EXPECT().
+			SendRawTransaction(gomock.Any(), gomock.Any()).
+			Return(common.Hash{}, nil).AnyTimes()
+		return s.manager.SendTransactionWithSignature(args, sig)
+	}
+
+	first, err := send(100, 0)
+	s.Require().NoError(err)
+	pending := s.manager.PendingTransactions(address, 0)
+	s.Require().Len(pending, 1)
+	s.Require().Equal(first, pending[0].Hash)
+
+	// the gas price is not high enough to replace the pending transaction
+	_, err = send(109, 1)
+	s.Require().Error(err)
+
+	replacement, err := send(110, 1)
+	s.Require().NoError(err)
+	pending = s.manager.PendingTransactions(address, 0)
+	s.Require().Len(pending, 1)
+	s.Require().Equal(replacement, pending[0].Hash)
+	// a replacement doesn't change the local nonce
+	localNonce, _ := s.manager.localNonce.Load(address)
+	s.Require().Equal(uint64(1), localNonce.(uint64))
+
+	// mined transactions are forgotten
+	s.Require().Empty(s.manager.PendingTransactions(address, 1))
+	s.Require().Empty(s.manager.PendingTransactions(address, 0))
+}
+
 func (s *TransactorSuite) TestSendTransactionWithSignature_InvalidSignature() {
 	args := SendTxArgs{}
 	_, err := s.manager.SendTransactionWithSignature(args, []byte{})
@@ -0,0 +1,47 @@
+package waku
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CompressedEnvelopes is a batch of envelopes sent by a mail server with p2pMessageCode
+// to a peer that accepts compressed responses. Gzip is a gzip compressed RLP list of envelopes.
+// As any other payload of p2pMessageCode, batches are sent in a list.
+type CompressedEnvelopes struct {
+	Gzip []byte
+}
+
+// decodeCompressedEnvelopes decompresses a list of batches of envelopes. Batches larger
+// than maxSize after decompression are rejected.
+func decodeCompressedEnvelopes(data []byte, maxSize uint32) ([]*Envelope, error) {
+	var batches []CompressedEnvelopes
+	if err := rlp.DecodeBytes(data, &batches); err != nil {
+		return nil, err
+	}
+	var envelopes []*Envelope
+	for _, batch := range batches {
+		r, err := gzip.NewReader(bytes.NewReader(batch.Gzip))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) > int(maxSize) {
+			return nil, fmt.Errorf("decompressed envelopes exceed %d bytes", maxSize)
+		}
+		var decoded []*Envelope
+		if err := rlp.DecodeBytes(raw, &decoded); err != nil {
+			return nil, err
+		}
+		envelopes = append(envelopes, decoded...)
+	}
+	return envelopes, nil
+}
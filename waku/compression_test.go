@@ -0,0 +1,47 @@
+package waku
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func compressTestEnvelopes(t *testing.T, envelopes []*Envelope) []byte {
+	raw, err := rlp.EncodeToBytes(envelopes)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	data, err := rlp.EncodeToBytes([]CompressedEnvelopes{{Gzip: buf.Bytes()}})
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeCompressedEnvelopes(t *testing.T) {
+	envelopes := []*Envelope{
+		{Expiry: 1, TTL: 10, Data: make([]byte, 100)},
+		{Expiry: 2, TTL: 10, Data: make([]byte, 100)},
+	}
+	data := compressTestEnvelopes(t, envelopes)
+
+	decoded, err := decodeCompressedEnvelopes(data, 1024)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	require.Equal(t, envelopes[0].Hash(), decoded[0].Hash())
+	require.Equal(t, envelopes[1].Hash(), decoded[1].Hash())
+
+	_, err = decodeCompressedEnvelopes(data, 100)
+	require.Error(t, err)
+
+	// uncompressed envelopes are not decoded as compressed batches
+	raw, err := rlp.EncodeToBytes(envelopes)
+	require.NoError(t, err)
+	_, err = decodeCompressedEnvelopes(raw, 1024)
+	require.Error(t, err)
+}
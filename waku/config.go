@@ -26,6 +26,8 @@ type Config struct {
 	FullNode                 bool    `toml:",omitempty"` // when true, it forwards all messages
 	RestrictLightClientsConn bool    `toml:",omitempty"` // when true, do not accept light client as peers if it is a light client itself
 	EnableConfirmations      bool    `toml:",omitempty"` // when true, sends message confirmations
+	// MinimumPoWByTopic overrides MinimumAcceptedPoW for envelopes with given topics
+	MinimumPoWByTopic map[TopicType]float64 `toml:",omitempty"`
 }
 
 var DefaultConfig = Config{
@@ -202,6 +202,8 @@ type MailServerResponse struct {
 	LastEnvelopeHash common.Hash
 	Cursor           []byte
 	Error            error
+	// Digest contains keys of envelopes returned for a digest request.
+	Digest [][]byte
 }
 
 // RateLimits contains information about rate limit settings.
@@ -49,6 +49,9 @@ type Peer struct {
 	confirmationsEnabled bool
 	rateLimitsMu         sync.Mutex
 	rateLimits           RateLimits
+	// ackRequests are history requests that asked the peer for acknowledged batches
+	ackRequestsMu sync.Mutex
+	ackRequests   map[common.Hash]struct{}
 
 	known mapset.Set // Messages already known by the peer to avoid wasting bandwidth
 
@@ -69,6 +72,7 @@ func newPeer(host *Waku, remote *p2p.Peer, rw p2p.MsgReadWriter, logger *zap.Log
 		trusted:        false,
 		powRequirement: 0.0,
 		known:          mapset.NewSet(),
+		ackRequests:    make(map[common.Hash]struct{}),
 		quit:           make(chan struct{}),
 		bloomFilter:    MakeFullNodeBloom(),
 		fullNode:       true,
@@ -316,3 +320,25 @@ func sendBundle(rw p2p.MsgWriter, bundle []*Envelope) (rst common.Hash, err erro
 	}
 	return crypto.Keccak256Hash(data), nil
 }
+
+// expectAcknowledgements records a history request that asked the peer to deliver
+// batches of envelopes acknowledged by us.
+func (peer *Peer) expectAcknowledgements(requestID common.Hash) {
+	peer.ackRequestsMu.Lock()
+	defer peer.ackRequestsMu.Unlock()
+	peer.ackRequests[requestID] = struct{}{}
+}
+
+// requestFinished forgets a history request that was completed or expired.
+func (peer *Peer) requestFinished(requestID common.Hash) {
+	peer.ackRequestsMu.Lock()
+	defer peer.ackRequestsMu.Unlock()
+	delete(peer.ackRequests, requestID)
+}
+
+// acknowledgesBatches returns true if direct messages of the peer have to be acknowledged.
+func (peer *Peer) acknowledgesBatches() bool {
+	peer.ackRequestsMu.Lock()
+	defer peer.ackRequestsMu.Unlock()
+	return len(peer.ackRequests) > 0
+}
@@ -27,6 +27,7 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"net"
 	"runtime"
 	"sync"
 	"time"
@@ -56,15 +57,16 @@ type Bridge interface {
 }
 
 type settings struct {
-	MaxMsgSize               uint32  // Maximal message length allowed by the waku node
-	EnableConfirmations      bool    // Enable sending message confirmations
-	MinPow                   float64 // Minimal PoW required by the waku node
-	MinPowTolerance          float64 // Minimal PoW tolerated by the waku node for a limited time
-	BloomFilter              []byte  // Bloom filter for topics of interest for this node
-	BloomFilterTolerance     []byte  // Bloom filter tolerated by the waku node for a limited time
-	LightClient              bool    // Light client mode enabled does not forward messages
-	RestrictLightClientsConn bool    // Restrict connection between two light clients
-	SyncAllowance            int     // Maximum time in seconds allowed to process the waku-related messages
+	MaxMsgSize               uint32                // Maximal message length allowed by the waku node
+	EnableConfirmations      bool                  // Enable sending message confirmations
+	MinPow                   float64               // Minimal PoW required by the waku node
+	MinPowTolerance          float64               // Minimal PoW tolerated by the waku node for a limited time
+	BloomFilter              []byte                // Bloom filter for topics of interest for this node
+	BloomFilterTolerance     []byte                // Bloom filter tolerated by the waku node for a limited time
+	LightClient              bool                  // Light client mode enabled does not forward messages
+	RestrictLightClientsConn bool                  // Restrict connection between two light clients
+	SyncAllowance            int                   // Maximum time in seconds allowed to process the waku-related messages
+	TopicMinPow              map[TopicType]float64 // Minimal PoW required by the waku node for specific topics
 }
 
 // Waku represents a dark communication interface through the Ethereum
@@ -138,6 +140,10 @@ func New(cfg *Config, logger *zap.Logger) *Waku {
 		LightClient:              cfg.LightClient,
 		RestrictLightClientsConn: cfg.RestrictLightClientsConn,
 		SyncAllowance:            DefaultSyncAllowance,
+		TopicMinPow:              make(map[TopicType]float64, len(cfg.MinimumPoWByTopic)),
+	}
+	for topic, pow := range cfg.MinimumPoWByTopic {
+		waku.settings.TopicMinPow[topic] = pow
 	}
 
 	if cfg.FullNode {
@@ -202,6 +208,34 @@ func (w *Waku) SetMinimumPoW(val float64, tolerate bool) error {
 	return nil
 }
 
+// TopicMinPow returns the PoW value required by this node for envelopes with a given topic.
+// False is returned if the topic has no specific requirement and MinPow() applies.
+func (w *Waku) TopicMinPow(topic TopicType) (float64, bool) {
+	w.settingsMu.RLock()
+	defer w.settingsMu.RUnlock()
+	pow, ok := w.settings.TopicMinPow[topic]
+	return pow, ok
+}
+
+// SetTopicMinimumPoW sets the minimal PoW required by this node for envelopes with a given topic.
+// Peers are not notified as the requirement is advertised only for all topics, envelopes
+// with a lower PoW are dropped without penalizing the peer.
+func (w *Waku) SetTopicMinimumPoW(topic TopicType, val float64) error {
+	if val < 0.0 {
+		return fmt.Errorf("invalid PoW: %f", val)
+	}
+
+	w.settingsMu.Lock()
+	defer w.settingsMu.Unlock()
+	topics := make(map[TopicType]float64, len(w.settings.TopicMinPow)+1)
+	for t, pow := range w.settings.TopicMinPow {
+		topics[t] = pow
+	}
+	topics[topic] = val
+	w.settings.TopicMinPow = topics
+	return nil
+}
+
 // MinPowTolerance returns the value of minimum PoW which is tolerated for a limited
 // time after PoW was changed. If sufficient time have elapsed or no change of PoW
 // have ever occurred, the return value will be the same as return value of MinPow().
@@ -451,6 +485,15 @@ func (w *Waku) getPeer(peerID []byte) (*Peer, error) {
 	return nil, fmt.Errorf("could not find peer with ID: %x", peerID)
 }
 
+// PeerLocalAddr returns the local address of the connection with a peer.
+func (w *Waku) PeerLocalAddr(peerID []byte) (net.Addr, error) {
+	p, err := w.getPeer(peerID)
+	if err != nil {
+		return nil, err
+	}
+	return p.peer.LocalAddr(), nil
+}
+
 // AllowP2PMessagesFromPeer marks specific peer trusted,
 // which will allow it to send historic (expired) messages.
 func (w *Waku) AllowP2PMessagesFromPeer(peerID []byte) error {
@@ -494,6 +537,22 @@ func (w *Waku) RequestHistoricMessagesWithTimeout(peerID []byte, envelope *Envel
 	return err
 }
 
+// RequestAcknowledgedHistoricMessagesWithTimeout sends a request that asks the mail server
+// to deliver batches of envelopes acknowledged by us. Direct messages of the peer are
+// acknowledged until the request is completed or expired.
+func (w *Waku) RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope *Envelope, timeout time.Duration) error {
+	p, err := w.getPeer(peerID)
+	if err != nil {
+		return err
+	}
+	p.expectAcknowledgements(envelope.Hash())
+	if err := w.RequestHistoricMessagesWithTimeout(peerID, envelope, timeout); err != nil {
+		p.requestFinished(envelope.Hash())
+		return err
+	}
+	return nil
+}
+
 func (w *Waku) SendMessagesRequest(peerID []byte, request MessagesRequest) error {
 	if err := request.Validate(); err != nil {
 		return err
@@ -521,6 +580,9 @@ func (w *Waku) expireRequestHistoricMessages(peer enode.ID, hash common.Hash, ti
 	case <-w.quit:
 		return
 	case <-timer.C:
+		if p, err := w.getPeer(peer[:]); err == nil {
+			p.requestFinished(hash)
+		}
 		w.envelopeFeed.Send(EnvelopeEvent{
 			Peer:  peer,
 			Hash:  hash,
@@ -968,7 +1030,7 @@ func (w *Waku) runMessageLoop(p *Peer, rw p2p.MsgReadWriter) error {
 				return err
 			}
 		case p2pMessageCode:
-			if err := w.handleP2PMessageCode(p, packet, logger); err != nil {
+			if err := w.handleP2PMessageCode(p, rw, packet, logger); err != nil {
 				logger.Warn("failed to decode direct message, peer will be disconnected", zap.Binary("peer", peerID[:]), zap.Error(err))
 				return err
 			}
@@ -1073,7 +1135,7 @@ func (w *Waku) handleBloomFilterExCode(p *Peer, packet p2p.Msg, logger *zap.Logg
 	return nil
 }
 
-func (w *Waku) handleP2PMessageCode(p *Peer, packet p2p.Msg, logger *zap.Logger) error {
+func (w *Waku) handleP2PMessageCode(p *Peer, rw p2p.MsgReadWriter, packet p2p.Msg, logger *zap.Logger) error {
 	// peer-to-peer message, sent directly to peer bypassing PoW checks, etc.
 	// this message is not supposed to be forwarded to other peers, and
 	// therefore might not satisfy the PoW, expiry and other requirements.
@@ -1082,18 +1144,30 @@ func (w *Waku) handleP2PMessageCode(p *Peer, packet p2p.Msg, logger *zap.Logger)
 		return nil
 	}
 
-	var (
-		envelopes []*Envelope
-		err       error
-	)
-
-	if err = packet.Decode(&envelopes); err != nil {
+	data, err := ioutil.ReadAll(packet.Payload)
+	if err != nil {
 		return fmt.Errorf("invalid direct message payload: %w", err)
 	}
 
+	var envelopes []*Envelope
+	if err = rlp.DecodeBytes(data, &envelopes); err != nil {
+		// peers that requested compressed responses receive compressed batches
+		if envelopes, err = decodeCompressedEnvelopes(data, w.MaxMessageSize()); err != nil {
+			return fmt.Errorf("invalid direct message payload: %w", err)
+		}
+	}
+
 	for _, envelope := range envelopes {
 		w.postP2P(envelope)
 	}
+	// mail servers delivering history in acknowledged batches wait for it before sending the next batch
+	if !p.acknowledgesBatches() {
+		return nil
+	}
+	if err := p2p.Send(rw, batchAcknowledgedCode, crypto.Keccak256Hash(data)); err != nil {
+		peerID := p.peer.ID()
+		logger.Warn("failed to acknowledge direct messages", zap.Binary("peer", peerID[:]), zap.Error(err))
+	}
 	return nil
 }
 
@@ -1155,6 +1229,7 @@ func (w *Waku) handleP2PRequestCompleteCode(p *Peer, packet p2p.Msg, logger *zap
 		return fmt.Errorf("invalid p2p request complete payload: %w", err)
 	}
 
+	p.requestFinished(event.Hash)
 	w.postP2P(*event)
 	return nil
 }
@@ -1238,7 +1313,14 @@ func (w *Waku) addAndBridge(envelope *Envelope, isP2P bool, bridged bool) (bool,
 		return false, fmt.Errorf("huge messages are not allowed [%x]", envelope.Hash())
 	}
 
-	if envelope.PoW() < w.MinPow() {
+	if pow, ok := w.TopicMinPow(envelope.Topic); ok {
+		if envelope.PoW() < pow {
+			// peers are not aware of requirements for specific topics, so they are not penalized
+			envelopesCacheFailedCounter.WithLabelValues("low_topic_pow").Inc()
+			log.Debug("envelope with low PoW for its topic dropped", "hash", envelope.Hash().Hex(), "topic", envelope.Topic, "pow", envelope.PoW())
+			return false, nil
+		}
+	} else if envelope.PoW() < w.MinPow() {
 		// maybe the value was recently changed, and the peers did not adjust yet.
 		// in this case the previous value is retrieved by MinPowTolerance()
 		// for a short period of peer synchronization.
@@ -620,6 +620,48 @@ func TestCustomization(t *testing.T) {
 	}
 }
 
+func TestTopicMinimumPoW(t *testing.T) {
+	InitSingleTest()
+
+	const smallPoW = 0.00001
+	restricted := TopicType{1, 2, 3, 4}
+	w := New(&Config{
+		MaxMessageSize:     DefaultMaxMessageSize,
+		MinimumAcceptedPoW: smallPoW / 2,
+		MinimumPoWByTopic:  map[TopicType]float64{restricted: 1000},
+	}, nil)
+
+	pow, ok := w.TopicMinPow(restricted)
+	require.True(t, ok)
+	require.Equal(t, float64(1000), pow)
+	_, ok = w.TopicMinPow(TopicType{4, 3, 2, 1})
+	require.False(t, ok)
+
+	send := func(topic TopicType) error {
+		params, err := generateMessageParams()
+		require.NoError(t, err)
+		params.Topic = topic
+		params.PoW = smallPoW
+		params.TTL = 3600
+		msg, err := NewSentMessage(params)
+		require.NoError(t, err)
+		env, err := msg.Wrap(params, time.Now())
+		require.NoError(t, err)
+		return w.Send(env)
+	}
+
+	require.Error(t, send(restricted))
+	require.NoError(t, send(TopicType{4, 3, 2, 1}))
+
+	require.NoError(t, w.SetTopicMinimumPoW(restricted, smallPoW/2))
+	require.NoError(t, send(restricted))
+
+	// requirement for a topic takes precedence over the global one
+	require.NoError(t, w.SetMinimumPoW(1000, false))
+	require.NoError(t, send(restricted))
+	require.Error(t, w.SetTopicMinimumPoW(restricted, -1))
+}
+
 func TestSymmetricSendCycle(t *testing.T) {
 	InitSingleTest()
 
@@ -1581,13 +1623,33 @@ func TestMailserverCompletionEvent(t *testing.T) {
 	sub := w.SubscribeEnvelopeEvents(events)
 	defer sub.Unsubscribe()
 
+	// batches of envelopes are acknowledged only for requests that asked for it
+	unexpected := make(chan uint64, 1)
+	peer.expectAcknowledgements(common.Hash{})
 	envelopes := []*Envelope{{Data: []byte{1}}, {Data: []byte{2}}}
 	go func() {
 		require.NoError(t, p2p.Send(rw2, p2pMessageCode, envelopes))
+		ack, err := rw2.ReadMsg()
+		require.NoError(t, err)
+		require.Equal(t, uint64(batchAcknowledgedCode), ack.Code)
+		require.NoError(t, ack.Discard())
+		// the request is completed, following batches are not acknowledged
 		require.NoError(t, p2p.Send(rw2, p2pRequestCompleteCode, [100]byte{})) // 2 hashes + cursor size
+		require.NoError(t, p2p.Send(rw2, p2pMessageCode, envelopes))
+		go func() {
+			if msg, err := rw2.ReadMsg(); err == nil {
+				unexpected <- msg.Code
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
 		rw2.Close()
 	}()
 	require.EqualError(t, w.runMessageLoop(peer, rw1), "p2p: read or write on closed message pipe")
+	select {
+	case code := <-unexpected:
+		require.FailNow(t, "unexpected message", "code %d", code)
+	default:
+	}
 
 	after := time.After(2 * time.Second)
 	count := 0
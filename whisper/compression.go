@@ -0,0 +1,39 @@
+package whisper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CompressedEnvelopes is a batch of envelopes sent by a mail server with p2pMessageCode
+// to a peer that accepts compressed responses. Gzip is a gzip compressed RLP list of envelopes.
+type CompressedEnvelopes struct {
+	Gzip []byte
+}
+
+// decodeCompressedEnvelopes decompresses a batch of envelopes. Batches larger than maxSize
+// after decompression are rejected.
+func decodeCompressedEnvelopes(data []byte, maxSize uint32) ([]*Envelope, error) {
+	var compressed CompressedEnvelopes
+	if err := rlp.DecodeBytes(data, &compressed); err != nil {
+		return nil, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed.Gzip))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > int(maxSize) {
+		return nil, fmt.Errorf("decompressed envelopes exceed %d bytes", maxSize)
+	}
+	var envelopes []*Envelope
+	return envelopes, rlp.DecodeBytes(raw, &envelopes)
+}
@@ -22,6 +22,8 @@ type Config struct {
 	MinimumAcceptedPOW                    float64 `toml:",omitempty"`
 	RestrictConnectionBetweenLightClients bool    `toml:",omitempty"`
 	DisableConfirmations                  bool    `toml:",omitempty"`
+	// MinimumPoWByTopic overrides MinimumAcceptedPOW for envelopes with given topics
+	MinimumPoWByTopic map[TopicType]float64 `toml:",omitempty"`
 }
 
 // DefaultConfig represents (shocker!) the default configuration.
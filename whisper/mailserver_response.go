@@ -11,7 +11,11 @@ import (
 
 const (
 	mailServerFailedPayloadPrefix = "ERROR="
+	mailServerDigestPayloadPrefix = "DIGEST="
 	cursorSize                    = 36
+	// digestKeySize is a size of the envelope key in a digest,
+	// 4 for the timestamp + 32 for the envelope hash + 4 for the topic.
+	digestKeySize = cursorSize + TopicLength
 )
 
 func invalidResponseSizeError(size int) error {
@@ -37,10 +41,25 @@ func CreateMailServerRequestFailedPayload(requestID common.Hash, err error) []by
 	return payload
 }
 
+// CreateMailServerRequestDigestPayload creates a payload representing
+// a successful digest request to a mailserver. Instead of envelopes,
+// the mailserver returns keys of envelopes matching the request.
+func CreateMailServerRequestDigestPayload(requestID common.Hash, cursor []byte, keys [][]byte) []byte {
+	payload := []byte(mailServerDigestPayloadPrefix)
+	payload = append(payload, requestID[:]...)
+	payload = append(payload, byte(len(cursor)))
+	payload = append(payload, cursor...)
+	for _, key := range keys {
+		payload = append(payload, key...)
+	}
+	return payload
+}
+
 // CreateMailServerEvent returns EnvelopeEvent with correct data
 // if payload corresponds to any of the know mailserver events:
 // * request completed successfully
 // * request failed
+// * digest request completed successfully
 // If the payload is unknown/unparseable, it returns `nil`
 func CreateMailServerEvent(nodeID enode.ID, payload []byte) (*EnvelopeEvent, error) {
 
@@ -54,6 +73,12 @@ func CreateMailServerEvent(nodeID enode.ID, payload []byte) (*EnvelopeEvent, err
 		return event, err
 	}
 
+	event, err = tryCreateMailServerRequestDigestEvent(nodeID, payload)
+
+	if err != nil || event != nil {
+		return event, err
+	}
+
 	return tryCreateMailServerRequestCompletedEvent(nodeID, payload)
 }
 
@@ -89,6 +114,57 @@ func tryCreateMailServerRequestFailedEvent(nodeID enode.ID, payload []byte) (*En
 
 }
 
+func tryCreateMailServerRequestDigestEvent(nodeID enode.ID, payload []byte) (*EnvelopeEvent, error) {
+	if len(payload) < len(mailServerDigestPayloadPrefix)+common.HashLength+1 {
+		return nil, nil
+	}
+
+	prefix, remainder := extractPrefix(payload, len(mailServerDigestPayloadPrefix))
+
+	if !bytes.Equal(prefix, []byte(mailServerDigestPayloadPrefix)) {
+		return nil, nil
+	}
+
+	var (
+		requestID common.Hash
+		cursor    []byte
+		keys      [][]byte
+	)
+
+	requestID, remainder = extractHash(remainder)
+	// payload is
+	// - requestID + cursor length + cursor + keys
+	// cursor length is either 0 or cursorSize.
+	cursorLength := int(remainder[0])
+	remainder = remainder[1:]
+	if (cursorLength != 0 && cursorLength != cursorSize) || len(remainder) < cursorLength {
+		return nil, invalidResponseSizeError(len(payload))
+	}
+	if cursorLength > 0 {
+		cursor, remainder = extractPrefix(remainder, cursorLength)
+	}
+	if len(remainder)%digestKeySize != 0 {
+		return nil, invalidResponseSizeError(len(payload))
+	}
+	for len(remainder) > 0 {
+		var key []byte
+		key, remainder = extractPrefix(remainder, digestKeySize)
+		keys = append(keys, key)
+	}
+
+	event := EnvelopeEvent{
+		Peer:  nodeID,
+		Hash:  requestID,
+		Event: EventMailServerRequestCompleted,
+		Data: &MailServerResponse{
+			Cursor: cursor,
+			Digest: keys,
+		},
+	}
+
+	return &event, nil
+}
+
 func tryCreateMailServerRequestCompletedEvent(nodeID enode.ID, payload []byte) (*EnvelopeEvent, error) {
 	// check if payload is
 	// - requestID or
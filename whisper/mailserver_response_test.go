@@ -78,3 +78,30 @@ func TestCreateMailServerEvent(t *testing.T) {
 	_, err = CreateMailServerEvent(enode.ID{}, payloadTooBig)
 	require.Error(t, err)
 }
+
+func TestCreateMailServerDigestEvent(t *testing.T) {
+	requestID := common.Hash{0x01}
+	cursor := make([]byte, cursorSize)
+	cursor[0] = 0x02
+	keys := [][]byte{make([]byte, digestKeySize), make([]byte, digestKeySize)}
+	keys[1][0] = 0x03
+
+	event, err := CreateMailServerEvent(enode.ID{1}, CreateMailServerRequestDigestPayload(requestID, cursor, keys))
+	require.NoError(t, err)
+	require.Equal(t, requestID, event.Hash)
+	eventData := event.Data.(*MailServerResponse)
+	require.NoError(t, eventData.Error)
+	require.Equal(t, cursor, eventData.Cursor)
+	require.Equal(t, keys, eventData.Digest)
+
+	event, err = CreateMailServerEvent(enode.ID{1}, CreateMailServerRequestDigestPayload(requestID, nil, nil))
+	require.NoError(t, err)
+	eventData = event.Data.(*MailServerResponse)
+	require.Nil(t, eventData.Cursor)
+	require.Empty(t, eventData.Digest)
+
+	// truncated key
+	payload := CreateMailServerRequestDigestPayload(requestID, nil, keys)
+	_, err = CreateMailServerEvent(enode.ID{1}, payload[:len(payload)-1])
+	require.Error(t, err)
+}
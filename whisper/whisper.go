@@ -25,6 +25,7 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"net"
 	"runtime"
 	"sync"
 	"time"
@@ -69,6 +70,7 @@ const (
 	bloomFilterToleranceIdx                         // Bloom filter tolerated by the whisper node for a limited time
 	lightClientModeIdx                              // Light client mode. (does not forward any messages)
 	restrictConnectionBetweenLightClientsIdx        // Restrict connection between two light clients
+	topicMinPowIdx                                  // Minimal PoW required by the whisper node for specific topics
 )
 
 // MailServerResponse is the response payload sent by the mailserver
@@ -76,6 +78,8 @@ type MailServerResponse struct {
 	LastEnvelopeHash common.Hash
 	Cursor           []byte
 	Error            error
+	// Digest contains keys of envelopes returned for a digest request.
+	Digest [][]byte
 }
 
 // Whisper represents a dark communication interface through the Ethereum
@@ -101,6 +105,8 @@ type Whisper struct {
 
 	settings syncmap.Map // holds configuration settings that can be dynamically changed
 
+	topicMinPowMu sync.Mutex // serializes updates of PoW requirements for specific topics
+
 	disableConfirmations bool // do not reply with confirmations
 
 	syncAllowance int // maximum time in seconds allowed to process the whisper-related messages
@@ -149,6 +155,11 @@ func New(cfg *Config) *Whisper {
 	whisper.settings.Store(maxMsgSizeIdx, cfg.MaxMessageSize)
 	whisper.settings.Store(overflowIdx, false)
 	whisper.settings.Store(restrictConnectionBetweenLightClientsIdx, cfg.RestrictConnectionBetweenLightClients)
+	topicMinPow := make(map[TopicType]float64, len(cfg.MinimumPoWByTopic))
+	for topic, pow := range cfg.MinimumPoWByTopic {
+		topicMinPow[topic] = pow
+	}
+	whisper.settings.Store(topicMinPowIdx, topicMinPow)
 
 	// p2p whisper sub protocol handler
 	whisper.protocol = p2p.Protocol{
@@ -206,6 +217,38 @@ func (whisper *Whisper) MinPow() float64 {
 	return v
 }
 
+// TopicMinPow returns the PoW value required by this node for envelopes with a given topic.
+// False is returned if the topic has no specific requirement and MinPow() applies.
+func (whisper *Whisper) TopicMinPow(topic TopicType) (float64, bool) {
+	val, exist := whisper.settings.Load(topicMinPowIdx)
+	if !exist || val == nil {
+		return 0, false
+	}
+	pow, ok := val.(map[TopicType]float64)[topic]
+	return pow, ok
+}
+
+// SetTopicMinimumPoW sets the minimal PoW required by this node for envelopes with a given topic.
+// Peers are not notified as the requirement is advertised only for all topics, envelopes
+// with a lower PoW are dropped without penalizing the peer.
+func (whisper *Whisper) SetTopicMinimumPoW(topic TopicType, val float64) error {
+	if val < 0.0 {
+		return fmt.Errorf("invalid PoW: %f", val)
+	}
+
+	whisper.topicMinPowMu.Lock()
+	defer whisper.topicMinPowMu.Unlock()
+	topics := map[TopicType]float64{}
+	if current, exist := whisper.settings.Load(topicMinPowIdx); exist && current != nil {
+		for t, pow := range current.(map[TopicType]float64) {
+			topics[t] = pow
+		}
+	}
+	topics[topic] = val
+	whisper.settings.Store(topicMinPowIdx, topics)
+	return nil
+}
+
 // MinPowTolerance returns the value of minimum PoW which is tolerated for a limited
 // time after PoW was changed. If sufficient time have elapsed or no change of PoW
 // have ever occurred, the return value will be the same as return value of MinPow().
@@ -481,6 +524,15 @@ func (whisper *Whisper) getPeer(peerID []byte) (*Peer, error) {
 	return nil, fmt.Errorf("Could not find peer with ID: %x", peerID)
 }
 
+// PeerLocalAddr returns the local address of the connection with a peer.
+func (whisper *Whisper) PeerLocalAddr(peerID []byte) (net.Addr, error) {
+	p, err := whisper.getPeer(peerID)
+	if err != nil {
+		return nil, err
+	}
+	return p.peer.LocalAddr(), nil
+}
+
 // AllowP2PMessagesFromPeer marks specific peer trusted,
 // which will allow it to send historic (expired) messages.
 func (whisper *Whisper) AllowP2PMessagesFromPeer(peerID []byte) error {
@@ -520,6 +572,22 @@ func (whisper *Whisper) RequestHistoricMessagesWithTimeout(peerID []byte, envelo
 	return err
 }
 
+// RequestAcknowledgedHistoricMessagesWithTimeout sends a request that asks the mail server
+// to deliver batches of envelopes acknowledged by us. Direct messages of the peer are
+// acknowledged until the request is completed or expired.
+func (whisper *Whisper) RequestAcknowledgedHistoricMessagesWithTimeout(peerID []byte, envelope *Envelope, timeout time.Duration) error {
+	p, err := whisper.getPeer(peerID)
+	if err != nil {
+		return err
+	}
+	p.expectAcknowledgements(envelope.Hash())
+	if err := whisper.RequestHistoricMessagesWithTimeout(peerID, envelope, timeout); err != nil {
+		p.requestFinished(envelope.Hash())
+		return err
+	}
+	return nil
+}
+
 func (whisper *Whisper) SendMessagesRequest(peerID []byte, request MessagesRequest) error {
 	if err := request.Validate(); err != nil {
 		return err
@@ -547,6 +615,9 @@ func (whisper *Whisper) expireRequestHistoricMessages(peer enode.ID, hash common
 	case <-whisper.quit:
 		return
 	case <-timer.C:
+		if p, err := whisper.getPeer(peer[:]); err == nil {
+			p.requestFinished(hash)
+		}
 		whisper.envelopeFeed.Send(EnvelopeEvent{
 			Peer:  peer,
 			Hash:  hash,
@@ -981,6 +1052,15 @@ func (whisper *Whisper) sendConfirmation(peer enode.ID, rw p2p.MsgReadWriter, da
 	}
 }
 
+// sendP2PAcknowledgement acknowledges a batch of direct messages, so that a mail server
+// delivering history in acknowledged batches can send the next one.
+func (whisper *Whisper) sendP2PAcknowledgement(peer enode.ID, rw p2p.MsgReadWriter, data []byte) {
+	batchHash := crypto.Keccak256Hash(data)
+	if err := p2p.Send(rw, batchAcknowledgedCode, batchHash); err != nil {
+		log.Warn("failed to acknowledge direct messages", "hash", batchHash, "peer", peer, "error", err)
+	}
+}
+
 // runMessageLoop reads and processes inbound messages directly to merge into client-global state.
 func (whisper *Whisper) runMessageLoop(p *Peer, rw p2p.MsgReadWriter) error {
 	for {
@@ -1139,6 +1219,9 @@ func (whisper *Whisper) runMessageLoop(p *Peer, rw p2p.MsgReadWriter) error {
 					for _, envelope := range envelopes {
 						whisper.postP2P(envelope)
 					}
+					if p.acknowledgesBatches() {
+						whisper.sendP2PAcknowledgement(p.peer.ID(), rw, data)
+					}
 					continue
 				}
 
@@ -1152,6 +1235,19 @@ func (whisper *Whisper) runMessageLoop(p *Peer, rw p2p.MsgReadWriter) error {
 
 				if err = packet.Decode(&envelope); err == nil {
 					whisper.postP2P(envelope)
+					if p.acknowledgesBatches() {
+						whisper.sendP2PAcknowledgement(p.peer.ID(), rw, data)
+					}
+					continue
+				}
+
+				if envelopes, err = decodeCompressedEnvelopes(data, whisper.MaxMessageSize()); err == nil {
+					for _, envelope := range envelopes {
+						whisper.postP2P(envelope)
+					}
+					if p.acknowledgesBatches() {
+						whisper.sendP2PAcknowledgement(p.peer.ID(), rw, data)
+					}
 					continue
 				}
 
@@ -1266,6 +1362,7 @@ func (whisper *Whisper) runMessageLoop(p *Peer, rw p2p.MsgReadWriter) error {
 					return err
 				}
 				if event != nil {
+					p.requestFinished(event.Hash)
 					whisper.postP2P(*event)
 				}
 			}
@@ -1317,7 +1414,14 @@ func (whisper *Whisper) addAndBridge(envelope *Envelope, isP2P bool, bridged boo
 		return false, fmt.Errorf("huge messages are not allowed [%x]", envelope.Hash())
 	}
 
-	if envelope.PoW() < whisper.MinPow() {
+	if pow, ok := whisper.TopicMinPow(envelope.Topic); ok {
+		if envelope.PoW() < pow {
+			// peers are not aware of requirements for specific topics, so they are not penalized
+			envelopesCacheFailedCounter.WithLabelValues("low_topic_pow").Inc()
+			log.Debug("envelope with low PoW for its topic dropped", "hash", envelope.Hash().Hex(), "topic", envelope.Topic, "pow", envelope.PoW())
+			return false, nil
+		}
+	} else if envelope.PoW() < whisper.MinPow() {
 		// maybe the value was recently changed, and the peers did not adjust yet.
 		// in this case the previous value is retrieved by MinPowTolerance()
 		// for a short period of peer synchronization.
@@ -946,7 +946,7 @@ func TestSendP2PDirect(t *testing.T) {
 	rwStub.messages = nil
 
 	// send a batch of envelopes
-	err = w.SendP2PDirect(peerW, env, env, env)
+	err = w.SendP2PDirect(peerW.ID(), env, env, env)
 	if err != nil {
 		t.Fatalf("failed to send envelope with seed %d: %s.", seed, err)
 	}
@@ -1065,7 +1065,7 @@ func testConfirmationsHandshake(t *testing.T, expectConfirmations bool) {
 	time.AfterFunc(5*time.Second, func() {
 		rw1.Close()
 	})
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, expectConfirmations}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, expectConfirmations, w.RateLimits()}))
 }
 
 func TestConfirmationHadnshakeExtension(t *testing.T) {
@@ -1092,7 +1092,7 @@ func TestConfirmationReceived(t *testing.T) {
 	time.AfterFunc(5*time.Second, func() {
 		rw1.Close()
 	})
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true, w.RateLimits()}))
 	require.NoError(t, p2p.SendItems(rw1, statusCode, ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), true, true))
 
 	e := Envelope{
@@ -1127,7 +1127,7 @@ func TestMessagesResponseWithError(t *testing.T) {
 		err := w.HandlePeer(p, rw2)
 		errorc <- err
 	}()
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true, w.RateLimits()}))
 	require.NoError(t, p2p.SendItems(rw1, statusCode, ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), true, true))
 
 	failed := Envelope{
@@ -1175,7 +1175,7 @@ func testConfirmationEvents(t *testing.T, envelope Envelope, envelopeErrors []En
 	time.AfterFunc(5*time.Second, func() {
 		rw1.Close()
 	})
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true, w.RateLimits()}))
 	require.NoError(t, p2p.SendItems(rw1, statusCode, ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), true, true))
 
 	require.NoError(t, w.Send(&envelope))
@@ -1251,7 +1251,7 @@ func TestEventsWithoutConfirmation(t *testing.T) {
 	time.AfterFunc(5*time.Second, func() {
 		rw1.Close()
 	})
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true, w.RateLimits()}))
 	require.NoError(t, p2p.SendItems(rw1, statusCode, ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), true, false))
 
 	e := Envelope{
@@ -1401,14 +1401,14 @@ func TestSyncMessages(t *testing.T) {
 
 	t.Run("WithoutPeer", func(t *testing.T) {
 		w := New(nil)
-		w.RegisterServer(&stubMailServer{})
+		w.RegisterMailServer(&stubMailServer{})
 		err := w.SyncMessages([]byte{0x01, 0x02}, SyncMailRequest{})
 		require.EqualError(t, err, "Could not find peer with ID: 0102")
 	})
 
 	t.Run("WithInvalidRequest", func(t *testing.T) {
 		w := New(nil)
-		w.RegisterServer(&stubMailServer{})
+		w.RegisterMailServer(&stubMailServer{})
 
 		p := p2p.NewPeer(enode.ID{0x01}, "peer01", nil)
 		rw1, _ := p2p.MsgPipe()
@@ -1421,7 +1421,7 @@ func TestSyncMessages(t *testing.T) {
 
 	t.Run("AllGood", func(t *testing.T) {
 		w := New(nil)
-		w.RegisterServer(&stubMailServer{})
+		w.RegisterMailServer(&stubMailServer{})
 
 		p := p2p.NewPeer(enode.ID{0x01}, "peer01", nil)
 		rw1, rw2 := p2p.MsgPipe()
@@ -1442,9 +1442,10 @@ func TestSendSyncResponse(t *testing.T) {
 	p := p2p.NewPeer(enode.ID{0x01}, "peer01", nil)
 	rw1, rw2 := p2p.MsgPipe()
 	whisperPeer := newPeer(w, p, rw1)
+	w.peers[whisperPeer] = struct{}{}
 
 	go func() {
-		err := w.SendSyncResponse(whisperPeer, SyncResponse{})
+		err := w.SendSyncResponse(whisperPeer.ID(), SyncResponse{})
 		require.NoError(t, err)
 	}()
 
@@ -1456,10 +1457,10 @@ func TestHandleP2PSyncRequestCode(t *testing.T) {
 	peer := newPeer(nil, p2p.NewPeer(enode.ID{}, "test", nil), nil)
 
 	mailMock := &mockMailServer{}
-	mailMock.On("SyncMail", peer, mock.Anything).Return(nil)
+	mailMock.On("SyncMail", peer.ID(), mock.Anything).Return(nil)
 
 	w := New(nil)
-	w.RegisterServer(mailMock)
+	w.RegisterMailServer(mailMock)
 
 	go func() {
 		err := p2p.Send(rw1, p2pSyncRequestCode, SyncMailRequest{Limit: 10})
@@ -1480,10 +1481,10 @@ func TestHandleP2PSyncRequestCodeWithInvalidRequest(t *testing.T) {
 	peer := newPeer(nil, p2p.NewPeer(enode.ID{}, "test", nil), nil)
 
 	mailMock := &mockMailServer{}
-	mailMock.On("SyncMail", peer, mock.Anything).Return(nil)
+	mailMock.On("SyncMail", peer.ID(), mock.Anything).Return(nil)
 
 	w := New(nil)
-	w.RegisterServer(mailMock)
+	w.RegisterMailServer(mailMock)
 
 	// create an invalid request
 	req := SyncMailRequest{Limit: 10, Lower: 10, Upper: 5}
@@ -1510,7 +1511,7 @@ func TestHandleP2PSyncResponseCode(t *testing.T) {
 	mailMock.On("Archive", mock.Anything)
 
 	w := New(nil)
-	w.RegisterServer(mailMock)
+	w.RegisterMailServer(mailMock)
 
 	envelopesCount := 3
 
@@ -1560,7 +1561,7 @@ func TestRateLimiterIntegration(t *testing.T) {
 		err := w.HandlePeer(p, rw2)
 		errorc <- err
 	}()
-	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true}))
+	require.NoError(t, p2p.ExpectMsg(rw1, statusCode, []interface{}{ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), false, true, w.RateLimits()}))
 	require.NoError(t, p2p.SendItems(rw1, statusCode, ProtocolVersion, math.Float64bits(w.MinPow()), w.BloomFilter(), true, true))
 
 	envelope := Envelope{
@@ -1580,10 +1581,10 @@ func TestRateLimiterIntegration(t *testing.T) {
 
 type stubMailServer struct{}
 
-func (stubMailServer) Archive(*Envelope)                     {}
-func (stubMailServer) DeliverMail(*Peer, *Envelope)          {}
-func (stubMailServer) Deliver(*Peer, MessagesRequest)        {}
-func (stubMailServer) SyncMail(*Peer, SyncMailRequest) error { return nil }
+func (stubMailServer) Archive(*Envelope)                      {}
+func (stubMailServer) DeliverMail([]byte, *Envelope)          {}
+func (stubMailServer) Deliver([]byte, MessagesRequest)        {}
+func (stubMailServer) SyncMail([]byte, SyncMailRequest) error { return nil }
 
 type mockMailServer struct {
 	mock.Mock
@@ -1593,15 +1594,15 @@ func (m *mockMailServer) Archive(env *Envelope) {
 	m.Called(env)
 }
 
-func (m *mockMailServer) DeliverMail(p *Peer, env *Envelope) {
+func (m *mockMailServer) DeliverMail(p []byte, env *Envelope) {
 	m.Called(p, env)
 }
 
-func (m *mockMailServer) Deliver(p *Peer, r MessagesRequest) {
+func (m *mockMailServer) Deliver(p []byte, r MessagesRequest) {
 	m.Called(p, r)
 }
 
-func (m *mockMailServer) SyncMail(p *Peer, r SyncMailRequest) error {
+func (m *mockMailServer) SyncMail(p []byte, r SyncMailRequest) error {
 	args := m.Called(p, r)
 	return args.Error(0)
 }
@@ -1620,13 +1621,33 @@ func TestMailserverCompletionEvent(t *testing.T) {
 	sub := w.SubscribeEnvelopeEvents(events)
 	defer sub.Unsubscribe()
 
+	// batches of envelopes are acknowledged only for requests that asked for it
+	unexpected := make(chan uint64, 1)
+	peer.expectAcknowledgements(common.Hash{})
 	envelopes := []*Envelope{{Data: []byte{1}}, {Data: []byte{2}}}
 	go func() {
 		require.NoError(t, p2p.Send(rw2, p2pMessageCode, envelopes))
+		ack, err := rw2.ReadMsg()
+		require.NoError(t, err)
+		require.Equal(t, uint64(batchAcknowledgedCode), ack.Code)
+		require.NoError(t, ack.Discard())
+		// the request is completed, following batches are not acknowledged
 		require.NoError(t, p2p.Send(rw2, p2pRequestCompleteCode, [100]byte{})) // 2 hashes + cursor size
+		require.NoError(t, p2p.Send(rw2, p2pMessageCode, envelopes))
+		go func() {
+			if msg, err := rw2.ReadMsg(); err == nil {
+				unexpected <- msg.Code
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
 		rw2.Close()
 	}()
 	require.EqualError(t, w.runMessageLoop(peer, rw1), "p2p: read or write on closed message pipe")
+	select {
+	case code := <-unexpected:
+		require.FailNow(t, "unexpected message", "code %d", code)
+	default:
+	}
 
 	after := time.After(2 * time.Second)
 	count := 0